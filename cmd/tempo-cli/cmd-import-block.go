@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/grafana/tempo/tempodb"
+)
+
+type importBlockCmd struct {
+	TenantID string `arg:"" help:"tenant-id the block is imported into"`
+	Path     string `arg:"" help:"local folder of the block files, meta.json included"`
+	backendOptions
+}
+
+func (cmd *importBlockCmd) Run(opts *globalOptions) error {
+	_, w, _, err := loadBackend(&cmd.backendOptions, opts)
+	if err != nil {
+		return err
+	}
+
+	meta, err := tempodb.ImportBlock(context.Background(), cmd.TenantID, cmd.Path, w)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("imported block %s of tenant %s: %d objects, %s, %s - %s\n", meta.BlockID.String(), cmd.TenantID,
+		meta.TotalObjects, humanize.Bytes(meta.Size_), meta.StartTime, meta.EndTime)
+	return nil
+}
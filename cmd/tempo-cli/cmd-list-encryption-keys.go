@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type listEncryptionKeysCmd struct {
+	TenantID   string `arg:"" help:"tenant-id within the bucket"`
+	KeyVersion uint32 `help:"current key version of the tenant, optional, overrides the version of the key of the tenant in config file"`
+	backendOptions
+}
+
+func (l *listEncryptionKeysCmd) Run(ctx *globalOptions) error {
+	current := l.KeyVersion
+	if current == 0 {
+		cfg, err := loadConfig(ctx)
+		if err != nil {
+			return err
+		}
+		secret, ok := cfg.StorageConfig.Trace.Encryption.TenantKeys[l.TenantID]
+		if !ok {
+			return errors.New("the tenant has no encryption key in config file, pass its key version instead")
+		}
+		key, err := base64.StdEncoding.DecodeString(secret.String())
+		if err != nil {
+			return fmt.Errorf("encryption key of tenant %s is invalid: %w", l.TenantID, err)
+		}
+		current = backend.StaticKeyVersion(key)
+	}
+
+	r, _, c, err := loadBackend(&l.backendOptions, ctx)
+	if err != nil {
+		return err
+	}
+
+	// compacted blocks are deleted with the data keys in their meta
+	blocks, err := loadBucket(r, c, l.TenantID, time.Hour, false)
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+
+	versions := map[uint32]int{}
+	out := make([][]string, 0)
+	for _, b := range blocks {
+		if len(b.EncryptionKey) == 0 {
+			continue
+		}
+		versions[b.EncryptionKeyVersion]++
+		if b.EncryptionKeyVersion == current {
+			continue
+		}
+
+		out = append(out, []string{
+			b.BlockID.String(),
+			strconv.FormatUint(uint64(b.EncryptionKeyVersion), 10),
+			b.EndTime.Format(time.RFC3339),
+			time.Since(b.EndTime).Round(time.Second).String(),
+		})
+	}
+
+	fmt.Println("blocks on old keys:")
+	w := tablewriter.NewWriter(os.Stdout)
+	w.SetHeader([]string{"id", "key version", "end", "age"})
+	w.AppendBulk(out)
+	w.Render()
+
+	ordered := make([]uint32, 0, len(versions))
+	for v := range versions {
+		ordered = append(ordered, v)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i] < ordered[j] })
+
+	summary := make([][]string, 0, len(ordered))
+	for _, v := range ordered {
+		summary = append(summary, []string{
+			strconv.FormatUint(uint64(v), 10),
+			strconv.FormatBool(v == current),
+			strconv.Itoa(versions[v]),
+		})
+	}
+
+	fmt.Println("encrypted blocks by key version:")
+	w = tablewriter.NewWriter(os.Stdout)
+	w.SetHeader([]string{"key version", "current", "blocks"})
+	w.AppendBulk(summary)
+	w.Render()
+
+	return nil
+}
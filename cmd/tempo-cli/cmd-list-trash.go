@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type listTrashCmd struct {
+	TenantID string `arg:"" help:"tenant-id within the bucket"`
+	backendOptions
+}
+
+func (l *listTrashCmd) Run(ctx *globalOptions) error {
+	r, _, _, err := loadRawBackend(&l.backendOptions, ctx)
+	if err != nil {
+		return err
+	}
+
+	trashed, err := backend.TrashedBlocks(context.Background(), r, l.TenantID)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(trashed, func(i, j int) bool {
+		return trashed[i].TrashedTime.Before(trashed[j].TrashedTime)
+	})
+
+	out := make([][]string, 0, len(trashed))
+	for _, b := range trashed {
+		out = append(out, []string{
+			b.BlockID.String(),
+			b.TrashedTime.Format(time.RFC3339),
+			time.Since(b.TrashedTime).Round(time.Second).String(),
+		})
+	}
+
+	w := tablewriter.NewWriter(os.Stdout)
+	w.SetHeader([]string{"id", "trashed", "age"})
+	w.AppendBulk(out)
+	w.Render()
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/dustin/go-humanize"
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+)
+
+type migrateMergeTenantCmd struct {
+	backendOptions
+
+	SourceTenantID    string `arg:"" help:"tenant-id to merge, usually an alias of the canonical tenant"`
+	CanonicalTenantID string `arg:"" help:"tenant-id the blocks are merged into"`
+
+	MarkSourceCompacted bool `help:"mark the blocks of the source tenant compacted once they are copied, so retention deletes them" default:"false"`
+	DryRun              bool `help:"only print the blocks that would be merged" default:"false"`
+}
+
+func (cmd *migrateMergeTenantCmd) Run(opts *globalOptions) error {
+	ctx := context.Background()
+
+	if cmd.SourceTenantID == cmd.CanonicalTenantID {
+		return errors.New("source and canonical tenant are the same")
+	}
+
+	r, w, c, err := loadBackend(&cmd.backendOptions, opts)
+	if err != nil {
+		return err
+	}
+
+	kms, err := loadKMS(opts)
+	if err != nil {
+		return err
+	}
+
+	sourceBlocks, _, err := r.Blocks(ctx, cmd.SourceTenantID)
+	if err != nil {
+		return fmt.Errorf("listing source blocks: %w", err)
+	}
+
+	tenants, err := r.Tenants(ctx)
+	if err != nil {
+		return fmt.Errorf("listing tenants: %w", err)
+	}
+
+	// the canonical tenant doesn't exist yet if it's a new name
+	var canonicalBlocks []uuid.UUID
+	if slices.Contains(tenants, cmd.CanonicalTenantID) {
+		canonicalBlocks, _, err = r.Blocks(ctx, cmd.CanonicalTenantID)
+		if err != nil {
+			return fmt.Errorf("listing canonical blocks: %w", err)
+		}
+	}
+	fmt.Printf("Blocks in %s: %d, in %s: %d\n", cmd.SourceTenantID, len(sourceBlocks), cmd.CanonicalTenantID, len(canonicalBlocks))
+
+	existing := make(map[uuid.UUID]struct{}, len(canonicalBlocks))
+	for _, id := range canonicalBlocks {
+		existing[id] = struct{}{}
+	}
+
+	var mergedBlocks, mergedSize uint64
+
+	for _, id := range sourceBlocks {
+		// a block already in the canonical tenant was merged by a previous run
+		if _, ok := existing[id]; ok {
+			fmt.Printf("block %s exists in the canonical tenant, skipping\n", id)
+			continue
+		}
+
+		sourceMeta, err := r.BlockMeta(ctx, id, cmd.SourceTenantID)
+		if err != nil {
+			return fmt.Errorf("reading meta of block %s: %w", id, err)
+		}
+
+		if cmd.DryRun {
+			fmt.Printf("would merge block %s, %s\n", id, humanize.Bytes(sourceMeta.Size_))
+			mergedBlocks++
+			mergedSize += sourceMeta.Size_
+			continue
+		}
+
+		canonicalMeta := *sourceMeta
+		canonicalMeta.TenantID = cmd.CanonicalTenantID
+
+		// the data key of an encrypted block is wrapped with the key of its tenant
+		if len(sourceMeta.EncryptionKey) > 0 {
+			if kms == nil {
+				return fmt.Errorf("block %s is encrypted, the config file must have the encryption keys of both tenants", id)
+			}
+			canonicalMeta.EncryptionKey, canonicalMeta.EncryptionKeyVersion, err = backend.RewrapDataKeyForTenant(ctx, kms, sourceMeta, cmd.CanonicalTenantID)
+			if err != nil {
+				return err
+			}
+		}
+
+		encoder, err := encoding.FromVersion(sourceMeta.Version)
+		if err != nil {
+			return fmt.Errorf("creating encoder from version: %w", err)
+		}
+
+		err = encoder.MigrateBlock(ctx, sourceMeta, &canonicalMeta, r, w)
+		if err != nil {
+			return fmt.Errorf("copying block %s: %w", id, err)
+		}
+
+		if cmd.MarkSourceCompacted {
+			err = c.MarkBlockCompacted(id, cmd.SourceTenantID)
+			if err != nil {
+				return fmt.Errorf("marking block %s compacted: %w", id, err)
+			}
+		}
+
+		mergedBlocks++
+		mergedSize += sourceMeta.Size_
+	}
+
+	if cmd.DryRun {
+		fmt.Printf("Would merge %d blocks, %s\n", mergedBlocks, humanize.Bytes(mergedSize))
+		return nil
+	}
+
+	fmt.Printf("Finished merging %s into %s. Copied %d blocks, %s\n", cmd.SourceTenantID, cmd.CanonicalTenantID, mergedBlocks, humanize.Bytes(mergedSize))
+	return nil
+}
+
+// loadKMS returns the KMS of the encryption config in the config file or nil if encryption is disabled.
+func loadKMS(opts *globalOptions) (backend.KMS, error) {
+	cfg, err := loadConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.StorageConfig.Trace.Encryption.Enabled() {
+		return nil, nil
+	}
+	return tempodb.NewKMS(&cfg.StorageConfig.Trace.Encryption)
+}
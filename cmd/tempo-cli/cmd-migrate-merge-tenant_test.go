@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
+)
+
+func TestMigrateMergeTenantCmdEncryptedBlocks(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	newKey := func() []byte {
+		key := make([]byte, backend.DataKeySize)
+		_, err := rand.Read(key)
+		require.NoError(t, err)
+		return key
+	}
+	sourceKey, canonicalKey := newKey(), newKey()
+	kms, err := backend.NewStaticKMS(map[string][][]byte{"source": {sourceKey}, "canonical": {canonicalKey}})
+	require.NoError(t, err)
+
+	rawR, rawW, _, err := local.New(&local.Config{Path: dir})
+	require.NoError(t, err)
+	newReaderWriter := func() (backend.Reader, backend.Writer) {
+		return backend.NewEncryptedReaderWriter(backend.NewReader(rawR), backend.NewWriter(rawW), kms)
+	}
+
+	// an encrypted block of the source tenant
+	r, w := newReaderWriter()
+	iter := &testIterator{traces: newTestTraces(5)}
+	meta := backend.NewBlockMeta("source", uuid.New(), vparquet4.VersionString, backend.EncNone, "")
+	meta.TotalObjects = int64(len(iter.traces))
+	meta, err = vparquet4.CreateBlock(ctx, &common.BlockConfig{BloomFP: 0.01, BloomShardSizeBytes: 100 * 1024}, meta, iter, r, w)
+	require.NoError(t, err)
+	blockID := (uuid.UUID)(meta.BlockID)
+
+	writeConfig := func(tenantKeys map[string][]byte) *globalOptions {
+		config := "storage:\n  trace:\n    encryption:\n      tenant_keys:\n"
+		for tenantID, key := range tenantKeys {
+			config += fmt.Sprintf("        %s: %s\n", tenantID, base64.StdEncoding.EncodeToString(key))
+		}
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+		return &globalOptions{ConfigFile: path}
+	}
+	cmd := migrateMergeTenantCmd{
+		backendOptions:    backendOptions{Backend: backend.Local, Bucket: dir},
+		SourceTenantID:    "source",
+		CanonicalTenantID: "canonical",
+	}
+
+	// encrypted blocks aren't merged without the keys of both tenants
+	require.Error(t, cmd.Run(&globalOptions{}))
+	require.Error(t, cmd.Run(writeConfig(map[string][]byte{"source": sourceKey})))
+	_, err = backend.NewReader(rawR).BlockMeta(ctx, blockID, "canonical")
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+
+	// the data key of the merged block is wrapped with the key of the canonical tenant
+	require.NoError(t, cmd.Run(writeConfig(map[string][]byte{"source": sourceKey, "canonical": canonicalKey})))
+
+	r, _ = newReaderWriter()
+	merged, err := r.BlockMeta(ctx, blockID, "canonical")
+	require.NoError(t, err)
+	require.Equal(t, backend.StaticKeyVersion(canonicalKey), merged.EncryptionKeyVersion)
+
+	source, err := r.Read(ctx, vparquet4.DataFileName, blockID, "source", nil)
+	require.NoError(t, err)
+	canonical, err := r.Read(ctx, vparquet4.DataFileName, blockID, "canonical", nil)
+	require.NoError(t, err)
+	require.Equal(t, source, canonical)
+}
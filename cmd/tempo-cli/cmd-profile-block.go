@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// defaultProfileQueries exercise one TraceQL operator each
+var defaultProfileQueries = []string{
+	`{ name = "GET" }`,
+	`{ resource.service.name != "" }`,
+	`{ name =~ "GET.*" }`,
+	`{ span.http.status_code >= 500 }`,
+	`{ duration > 1s }`,
+	`{ status = error }`,
+	`{ kind = server && span.http.method = "GET" }`,
+	`{ status = error || span.http.status_code = 500 }`,
+	`{ } | count() > 10`,
+	`{ } >> { status = error }`,
+	`{ status = error } | select(span.http.url)`,
+}
+
+type profileBlockCmd struct {
+	backendOptions
+
+	TenantID   string   `arg:"" help:"tenant-id within the bucket"`
+	BlockID    string   `arg:"" help:"block ID to profile"`
+	Queries    []string `name:"query" help:"TraceQL query to profile, can be repeated. Defaults to one query per operator"`
+	Iterations int      `default:"5" help:"number of times each query and predicate is run"`
+	Limit      uint32   `default:"20" help:"limit of the search requests. Predicates are always read to the end of the block"`
+	CPUProfile string   `name:"cpu-profile" help:"write a CPU profile to this file. Samples are labeled with the query and predicate"`
+	MemProfile string   `name:"mem-profile" help:"write an allocation profile to this file"`
+}
+
+type profileResult struct {
+	name         string
+	duration     time.Duration
+	allocBytes   uint64
+	allocs       uint64
+	spansets     int
+	inspectedKiB uint64
+}
+
+func (cmd *profileBlockCmd) Run(ctx *globalOptions) error {
+	r, _, _, err := loadBackend(&cmd.backendOptions, ctx)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Iterations <= 0 {
+		return errors.New("iterations must be greater than 0")
+	}
+
+	id, err := uuid.Parse(cmd.BlockID)
+	if err != nil {
+		return err
+	}
+
+	meta, err := r.BlockMeta(context.Background(), id, cmd.TenantID)
+	if err != nil {
+		return err
+	}
+
+	block, err := encoding.OpenBlock(meta, r)
+	if err != nil {
+		return err
+	}
+
+	queries := cmd.Queries
+	if len(queries) == 0 {
+		queries = defaultProfileQueries
+	}
+
+	if cmd.CPUProfile != "" {
+		f, err := os.Create(cmd.CPUProfile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return err
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	fmt.Printf("Profiling block %s (%s, %d traces, %d bytes) with %d iterations\n", meta.BlockID, meta.Version, meta.TotalObjects, meta.Size_, cmd.Iterations)
+
+	opts := common.DefaultSearchOptions()
+	results := make([]profileResult, 0, len(queries))
+	for _, q := range queries {
+		_, _, _, _, req, err := traceql.Compile(q)
+		if err != nil {
+			return fmt.Errorf("failed to compile query %s: %w", q, err)
+		}
+
+		res, err := cmd.profile(q, "", func(ctx context.Context) (int, uint64, error) {
+			return searchBlock(ctx, block, q, cmd.Limit, opts)
+		})
+		if err != nil {
+			return err
+		}
+		results = append(results, res)
+
+		// each predicate is fetched on its own to measure the cost of its column
+		for _, c := range req.Conditions {
+			if isStructural(c.Attribute) {
+				// structural operators don't read a column of their own
+				continue
+			}
+
+			name := conditionString(c)
+			res, err := cmd.profile(q, name, func(ctx context.Context) (int, uint64, error) {
+				return fetchCondition(ctx, block, c, opts)
+			})
+			if err != nil {
+				return err
+			}
+			results = append(results, res)
+		}
+	}
+
+	if cmd.MemProfile != "" {
+		f, err := os.Create(cmd.MemProfile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		if err := pprof.Lookup("allocs").WriteTo(f, 0); err != nil {
+			return err
+		}
+	}
+
+	out := make([][]string, 0, len(results))
+	for _, res := range results {
+		out = append(out, []string{
+			res.name,
+			res.duration.String(),
+			strconv.FormatUint(res.allocBytes, 10),
+			strconv.FormatUint(res.allocs, 10),
+			strconv.Itoa(res.spansets),
+			strconv.FormatUint(res.inspectedKiB, 10),
+		})
+	}
+
+	w := tablewriter.NewWriter(os.Stdout)
+	w.SetHeader([]string{"query / predicate", "time/op", "B/op", "allocs/op", "spansets", "KiB read/op"})
+	w.SetAutoWrapText(false)
+	w.AppendBulk(out)
+	w.Render()
+
+	return nil
+}
+
+// profile runs fn for the configured number of iterations and returns the average duration and allocations of a
+// run. Runs are labeled with the query and predicate in the CPU profile.
+func (cmd *profileBlockCmd) profile(query, predicate string, fn func(ctx context.Context) (int, uint64, error)) (profileResult, error) {
+	res := profileResult{name: query}
+	if predicate != "" {
+		res.name = "  " + predicate
+	}
+
+	var (
+		before, after runtime.MemStats
+		spansets      int
+		inspected     uint64
+		runErr        error
+	)
+
+	labels := pprof.Labels("query", query, "predicate", predicate)
+	pprof.Do(context.Background(), labels, func(ctx context.Context) {
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+
+		for i := 0; i < cmd.Iterations; i++ {
+			n, bytes, err := fn(ctx)
+			if err != nil {
+				runErr = err
+				return
+			}
+			spansets = n
+			inspected += bytes
+		}
+
+		res.duration = time.Since(start) / time.Duration(cmd.Iterations)
+		runtime.ReadMemStats(&after)
+	})
+	if runErr != nil {
+		return res, fmt.Errorf("failed to profile %s: %w", res.name, runErr)
+	}
+
+	iterations := uint64(cmd.Iterations)
+	res.allocBytes = (after.TotalAlloc - before.TotalAlloc) / iterations
+	res.allocs = (after.Mallocs - before.Mallocs) / iterations
+	res.spansets = spansets
+	res.inspectedKiB = inspected / iterations / 1024
+
+	return res, nil
+}
+
+func searchBlock(ctx context.Context, block common.BackendBlock, query string, limit uint32, opts common.SearchOptions) (int, uint64, error) {
+	fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return block.Fetch(ctx, req, opts)
+	})
+
+	resp, err := traceql.NewEngine().ExecuteSearch(ctx, &tempopb.SearchRequest{Query: query, Limit: limit}, fetcher)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return len(resp.Traces), resp.Metrics.InspectedBytes, nil
+}
+
+func fetchCondition(ctx context.Context, block common.BackendBlock, c traceql.Condition, opts common.SearchOptions) (int, uint64, error) {
+	resp, err := block.Fetch(ctx, traceql.FetchSpansRequest{
+		Conditions:    []traceql.Condition{c},
+		AllConditions: true,
+	}, opts)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Results.Close()
+
+	spansets := 0
+	for {
+		ss, err := resp.Results.Next(ctx)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return 0, 0, err
+		}
+		if ss == nil {
+			break
+		}
+		spansets++
+	}
+
+	var inspected uint64
+	if resp.Bytes != nil {
+		inspected = resp.Bytes()
+	}
+
+	return spansets, inspected, nil
+}
+
+func isStructural(a traceql.Attribute) bool {
+	switch a.Intrinsic {
+	case traceql.IntrinsicStructuralDescendant, traceql.IntrinsicStructuralChild, traceql.IntrinsicStructuralSibling:
+		return true
+	}
+	return false
+}
+
+func conditionString(c traceql.Condition) string {
+	if c.Op == traceql.OpNone {
+		return c.Attribute.String()
+	}
+
+	operands := make([]string, 0, len(c.Operands))
+	for _, o := range c.Operands {
+		operands = append(operands, o.String())
+	}
+
+	return fmt.Sprintf("%s %s %s", c.Attribute.String(), c.Op.String(), strings.Join(operands, ", "))
+}
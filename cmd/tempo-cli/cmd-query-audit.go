@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/grafana/tempo/modules/frontend/queryaudit"
+)
+
+type queryAuditCmd struct {
+	backendOptions
+
+	TenantID string `arg:"" help:"tenant ID to search"`
+	Start    string `help:"only return queries received after this time in RFC3339 format"`
+	End      string `help:"only return queries received before this time in RFC3339 format"`
+	User     string `help:"only return queries of this user"`
+	Contains string `help:"only return queries that contain this text"`
+}
+
+func (cmd *queryAuditCmd) Run(ctx *globalOptions) error {
+	r, _, _, err := loadRawBackend(&cmd.backendOptions, ctx)
+	if err != nil {
+		return err
+	}
+
+	opts := queryaudit.SearchOptions{
+		User:     cmd.User,
+		Contains: cmd.Contains,
+	}
+	if cmd.Start != "" {
+		if opts.Start, err = time.Parse(time.RFC3339, cmd.Start); err != nil {
+			return err
+		}
+	}
+	if cmd.End != "" {
+		if opts.End, err = time.Parse(time.RFC3339, cmd.End); err != nil {
+			return err
+		}
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	return queryaudit.Search(context.Background(), r, cmd.TenantID, opts, func(rec *queryaudit.Record) error {
+		return enc.Encode(rec)
+	})
+}
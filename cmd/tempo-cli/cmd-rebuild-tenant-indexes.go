@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/pkg/boundedwaitgroup"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type rebuildTenantIndexesCmd struct {
+	backendOptions
+
+	TenantIDs        []string `arg:"" optional:"" help:"tenant-ids to rebuild the index of, all tenants in the bucket if none are given"`
+	Concurrency      uint     `help:"number of tenants rebuilt in parallel" default:"4"`
+	BlockConcurrency uint     `help:"number of block metas read in parallel for each tenant" default:"20"`
+	DryRun           bool     `help:"only poll the tenants and print the indexes that would be written" default:"false"`
+}
+
+func (cmd *rebuildTenantIndexesCmd) Run(opts *globalOptions) error {
+	ctx := context.Background()
+
+	if cmd.Concurrency == 0 || cmd.BlockConcurrency == 0 {
+		return errors.New("concurrency and block-concurrency must be greater than 0")
+	}
+
+	rawR, rawW, c, err := loadRawBackend(&cmd.backendOptions, opts)
+	if err != nil {
+		return err
+	}
+
+	versioned, ok := rawR.(backend.VersionedReaderWriter)
+	if !ok {
+		fmt.Println("The backend doesn't support conditional writes, tenant indexes written by Tempo while rebuilding are overwritten")
+		versioned = backend.NewFakeVersionedReaderWriter(rawR, rawW)
+	}
+
+	rebuilder := &tenantIndexRebuilder{
+		r:                backend.NewReader(rawR),
+		w:                rawW,
+		c:                c,
+		versioned:        versioned,
+		blockConcurrency: cmd.BlockConcurrency,
+		dryRun:           cmd.DryRun,
+	}
+
+	tenants := cmd.TenantIDs
+	if len(tenants) == 0 {
+		tenants, err = rebuilder.r.Tenants(ctx)
+		if err != nil {
+			return fmt.Errorf("listing tenants: %w", err)
+		}
+		sort.Strings(tenants)
+	}
+	fmt.Printf("Rebuilding the tenant index of %d tenants\n", len(tenants))
+
+	var (
+		wg                        = boundedwaitgroup.New(cmd.Concurrency)
+		mtx                       sync.Mutex
+		done, conflicts, failures int
+	)
+	for _, tenantID := range tenants {
+		wg.Add(1)
+		go func(tenantID string) {
+			defer wg.Done()
+
+			start := time.Now()
+			metas, compactedMetas, err := rebuilder.rebuild(ctx, tenantID)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			done++
+			progress := fmt.Sprintf("[%d/%d] %s:", done, len(tenants), tenantID)
+			switch {
+			case errors.Is(err, backend.ErrVersionDoesNotMatch):
+				conflicts++
+				fmt.Println(progress, "the index was written by Tempo while rebuilding, skipped")
+			case err != nil:
+				failures++
+				fmt.Println(progress, "failed:", err)
+			case cmd.DryRun:
+				fmt.Println(progress, "would write", len(metas), "blocks and", len(compactedMetas), "compacted blocks")
+			default:
+				fmt.Println(progress, "wrote", len(metas), "blocks and", len(compactedMetas), "compacted blocks in", time.Since(start).Round(time.Millisecond))
+			}
+		}(tenantID)
+	}
+	wg.Wait()
+
+	fmt.Printf("Finished rebuilding %d tenant indexes, %d conflicts, %d failures\n", len(tenants)-conflicts-failures, conflicts, failures)
+	if failures > 0 {
+		return fmt.Errorf("failed to rebuild the index of %d tenants", failures)
+	}
+	return nil
+}
+
+// tenantIndexRebuilder polls the blocks of a tenant and writes its index, the same way the compactors building the
+// tenant index do.
+type tenantIndexRebuilder struct {
+	r                backend.Reader
+	w                backend.RawWriter
+	c                backend.Compactor
+	versioned        backend.VersionedReaderWriter
+	blockConcurrency uint
+	dryRun           bool
+}
+
+// rebuild rebuilds the index of the tenant. The index is only written if it wasn't written since the blocks were
+// listed, backend.ErrVersionDoesNotMatch is returned otherwise: the index written in the meantime is more recent.
+func (b *tenantIndexRebuilder) rebuild(ctx context.Context, tenantID string) ([]*backend.BlockMeta, []*backend.CompactedBlockMeta, error) {
+	version, err := backend.ReadTenantIndexVersion(ctx, b.versioned, tenantID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading tenant index version: %w", err)
+	}
+
+	blockIDs, compactedBlockIDs, err := b.r.Blocks(ctx, tenantID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing blocks: %w", err)
+	}
+
+	metas, compactedMetas, err := b.pollBlocks(ctx, tenantID, blockIDs, compactedBlockIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if b.dryRun {
+		return metas, compactedMetas, nil
+	}
+
+	_, err = backend.WriteTenantIndexVersioned(ctx, b.versioned, b.w, tenantID, metas, compactedMetas, version)
+	if err != nil {
+		return nil, nil, fmt.Errorf("writing tenant index: %w", err)
+	}
+
+	return metas, compactedMetas, nil
+}
+
+func (b *tenantIndexRebuilder) pollBlocks(ctx context.Context, tenantID string, blockIDs, compactedBlockIDs []uuid.UUID) ([]*backend.BlockMeta, []*backend.CompactedBlockMeta, error) {
+	var (
+		wg             = boundedwaitgroup.New(b.blockConcurrency)
+		mtx            sync.Mutex
+		metas          = make([]*backend.BlockMeta, 0, len(blockIDs))
+		compactedMetas = make([]*backend.CompactedBlockMeta, 0, len(compactedBlockIDs))
+		errs           []error
+	)
+
+	poll := func(blockID uuid.UUID, compacted bool) {
+		defer wg.Done()
+
+		meta, compactedMeta, err := b.pollBlock(ctx, tenantID, blockID, compacted)
+
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("polling block %s: %w", blockID, err))
+		case meta != nil:
+			metas = append(metas, meta)
+		case compactedMeta != nil:
+			compactedMetas = append(compactedMetas, compactedMeta)
+		}
+	}
+
+	for _, id := range blockIDs {
+		wg.Add(1)
+		go poll(id, false)
+	}
+	for _, id := range compactedBlockIDs {
+		wg.Add(1)
+		go poll(id, true)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	slices.SortFunc(metas, func(a, b *backend.BlockMeta) int {
+		return strings.Compare(a.BlockID.String(), b.BlockID.String())
+	})
+	slices.SortFunc(compactedMetas, func(a, b *backend.CompactedBlockMeta) int {
+		return strings.Compare(a.BlockID.String(), b.BlockID.String())
+	})
+
+	return metas, compactedMetas, nil
+}
+
+// pollBlock returns the meta or the compacted meta of a block, nil for blocks in intermediate states without a meta.
+func (b *tenantIndexRebuilder) pollBlock(ctx context.Context, tenantID string, blockID uuid.UUID, compacted bool) (*backend.BlockMeta, *backend.CompactedBlockMeta, error) {
+	if !compacted {
+		meta, err := b.r.BlockMeta(ctx, blockID, tenantID)
+		if err == nil {
+			return meta, nil, nil
+		}
+		if !errors.Is(err, backend.ErrDoesNotExist) {
+			return nil, nil, err
+		}
+	}
+
+	// if the normal meta doesn't exist maybe it's compacted
+	compactedMeta, err := b.c.CompactedBlockMeta(blockID, tenantID)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nil, compactedMeta, nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+)
+
+func TestRebuildTenantIndexesCmd(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	rawR, rawW, c, err := local.New(&local.Config{Path: dir})
+	require.NoError(t, err)
+	r, w := backend.NewReader(rawR), backend.NewWriter(rawW)
+
+	writeBlocks := func(tenantID string, count int) []uuid.UUID {
+		ids := make([]uuid.UUID, 0, count)
+		for i := 0; i < count; i++ {
+			id := uuid.New()
+			require.NoError(t, w.WriteBlockMeta(ctx, backend.NewBlockMeta(tenantID, id, "v1", backend.EncNone, "")))
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	blocksA := writeBlocks("a", 3)
+	require.NoError(t, c.MarkBlockCompacted(blocksA[0], "a"))
+	writeBlocks("b", 2)
+
+	// the stale index of tenant a only has one block
+	require.NoError(t, w.WriteTenantIndex(ctx, "a", []*backend.BlockMeta{backend.NewBlockMeta("a", uuid.New(), "v1", backend.EncNone, "")}, nil))
+
+	cmd := rebuildTenantIndexesCmd{
+		backendOptions:   backendOptions{Backend: "local", Bucket: dir},
+		Concurrency:      2,
+		BlockConcurrency: 2,
+	}
+
+	// a dry run doesn't write the indexes
+	cmd.DryRun = true
+	require.NoError(t, cmd.Run(&globalOptions{}))
+	_, err = r.TenantIndex(ctx, "b")
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+
+	cmd.DryRun = false
+	require.NoError(t, cmd.Run(&globalOptions{}))
+
+	idx, err := r.TenantIndex(ctx, "a")
+	require.NoError(t, err)
+	require.Len(t, idx.Meta, 2)
+	require.Len(t, idx.CompactedMeta, 1)
+	require.Equal(t, blocksA[0], (uuid.UUID)(idx.CompactedMeta[0].BlockID))
+
+	idx, err = r.TenantIndex(ctx, "b")
+	require.NoError(t, err)
+	require.Len(t, idx.Meta, 2)
+	require.Empty(t, idx.CompactedMeta)
+
+	// only the given tenants are rebuilt
+	writeBlocks("a", 1)
+	writeBlocks("b", 1)
+	cmd.TenantIDs = []string{"b"}
+	require.NoError(t, cmd.Run(&globalOptions{}))
+
+	idx, err = r.TenantIndex(ctx, "a")
+	require.NoError(t, err)
+	require.Len(t, idx.Meta, 2)
+
+	idx, err = r.TenantIndex(ctx, "b")
+	require.NoError(t, err)
+	require.Len(t, idx.Meta, 3)
+}
+
+func TestTenantIndexRebuilderConflict(t *testing.T) {
+	ctx := context.Background()
+
+	rawR, rawW, c, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+	require.NoError(t, backend.NewWriter(rawW).WriteBlockMeta(ctx, backend.NewBlockMeta("a", uuid.New(), "v1", backend.EncNone, "")))
+
+	rebuilder := &tenantIndexRebuilder{
+		r:                backend.NewReader(rawR),
+		w:                rawW,
+		c:                c,
+		versioned:        &conflictingVersionedReaderWriter{backend.NewFakeVersionedReaderWriter(rawR, rawW)},
+		blockConcurrency: 1,
+	}
+
+	_, _, err = rebuilder.rebuild(ctx, "a")
+	require.ErrorIs(t, err, backend.ErrVersionDoesNotMatch)
+
+	// the index isn't written
+	_, err = backend.NewReader(rawR).TenantIndex(ctx, "a")
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+}
+
+// conflictingVersionedReaderWriter fails all versioned writes as if the object was written in the meantime.
+type conflictingVersionedReaderWriter struct {
+	*backend.FakeVersionedReaderWriter
+}
+
+func (conflictingVersionedReaderWriter) WriteVersioned(context.Context, string, backend.KeyPath, io.Reader, int64, backend.Version) (backend.Version, error) {
+	return "", backend.ErrVersionDoesNotMatch
+}
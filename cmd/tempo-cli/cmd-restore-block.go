@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type restoreBlockCmd struct {
+	TenantID string `arg:"" help:"tenant-id within the bucket"`
+	BlockID  string `arg:"" help:"block ID to restore from the trash"`
+	backendOptions
+}
+
+func (cmd *restoreBlockCmd) Run(ctx *globalOptions) error {
+	blockID, err := uuid.Parse(cmd.BlockID)
+	if err != nil {
+		return err
+	}
+
+	r, w, _, err := loadRawBackend(&cmd.backendOptions, ctx)
+	if err != nil {
+		return err
+	}
+
+	err = backend.RestoreTrashedBlock(context.Background(), r, w, blockID, cmd.TenantID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("restored block", blockID, "of tenant", cmd.TenantID)
+	return nil
+}
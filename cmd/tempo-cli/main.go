@@ -48,6 +48,8 @@ var cli struct {
 		CacheSummary      listCacheSummaryCmd      `cmd:"" help:"List summary of bloom sizes per day per compaction level"`
 		Index             listIndexCmd             `cmd:"" help:"List information about a block index"`
 		Column            listColumnCmd            `cmd:"" help:"List values in a given column"`
+		Trash             listTrashCmd             `cmd:"" help:"List blocks in the trash of a tenant"`
+		EncryptionKeys    listEncryptionKeysCmd    `cmd:"" help:"List the encrypted blocks of a tenant whose data key isn't wrapped with its current key"`
 	} `cmd:""`
 
 	Analyse struct {
@@ -77,6 +79,7 @@ var cli struct {
 		TraceID      queryBlocksCmd       `cmd:"" help:"query for a traceid directly from backend blocks"`
 		TraceSummary queryTraceSummaryCmd `cmd:"" help:"query summary for a traceid directly from backend blocks"`
 		Search       searchBlocksCmd      `cmd:"" help:"search for a traceid directly from backend blocks"`
+		Audit        queryAuditCmd        `cmd:"" help:"search the query audit log of a tenant"`
 	} `cmd:""`
 
 	RewriteBlocks struct {
@@ -88,9 +91,26 @@ var cli struct {
 		Convert3to4 convertParquet3to4 `cmd:"" help:"convert an existing vParquet3 file to vParquet4 block"`
 	} `cmd:""`
 
+	Profile struct {
+		Block profileBlockCmd `cmd:"" help:"profile the CPU and allocations of TraceQL queries and their predicates against a block"`
+	} `cmd:""`
+
+	Restore struct {
+		Block restoreBlockCmd `cmd:"" help:"restore a block from the trash of a tenant"`
+	} `cmd:""`
+
+	Import struct {
+		Block importBlockCmd `cmd:"" help:"import an externally generated block into a tenant under a new block ID"`
+	} `cmd:""`
+
+	Rebuild struct {
+		TenantIndexes rebuildTenantIndexesCmd `cmd:"" help:"rebuild the tenant indexes of all or the given tenants directly from the backend"`
+	} `cmd:""`
+
 	Migrate struct {
 		Tenant          migrateTenantCmd          `cmd:"" help:"migrate tenant between two backends"`
 		OverridesConfig migrateOverridesConfigCmd `cmd:"" help:"migrate overrides config"`
+		MergeTenant     migrateMergeTenantCmd     `cmd:"" help:"merge the blocks of a tenant into another tenant of the same backend"`
 	} `cmd:""`
 }
 
@@ -106,21 +126,18 @@ func main() {
 }
 
 func loadBackend(b *backendOptions, g *globalOptions) (backend.Reader, backend.Writer, backend.Compactor, error) {
-	// Defaults
-	cfg := app.Config{}
-	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+	r, w, c, err := loadRawBackend(b, g)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-	// Existing config
-	if g.ConfigFile != "" {
-		buff, err := os.ReadFile(g.ConfigFile)
-		if err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to read configFile %s: %w", g.ConfigFile, err)
-		}
+	return backend.NewReader(r), backend.NewWriter(w), c, nil
+}
 
-		err = yaml.UnmarshalStrict(buff, &cfg)
-		if err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to parse configFile %s: %w", g.ConfigFile, err)
-		}
+func loadRawBackend(b *backendOptions, g *globalOptions) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
+	cfg, err := loadConfig(g)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
 	// cli overrides
@@ -150,7 +167,6 @@ func loadBackend(b *backendOptions, g *globalOptions) (backend.Reader, backend.W
 		cfg.StorageConfig.Trace.S3.Endpoint = b.S3Endpoint
 	}
 
-	var err error
 	var r backend.RawReader
 	var w backend.RawWriter
 	var c backend.Compactor
@@ -172,5 +188,25 @@ func loadBackend(b *backendOptions, g *globalOptions) (backend.Reader, backend.W
 		return nil, nil, nil, err
 	}
 
-	return backend.NewReader(r), backend.NewWriter(w), c, nil
+	return r, w, c, nil
+}
+
+// loadConfig returns the defaults overridden by the config file, if any.
+func loadConfig(g *globalOptions) (*app.Config, error) {
+	cfg := &app.Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+
+	if g.ConfigFile != "" {
+		buff, err := os.ReadFile(g.ConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read configFile %s: %w", g.ConfigFile, err)
+		}
+
+		err = yaml.UnmarshalStrict(buff, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse configFile %s: %w", g.ConfigFile, err)
+		}
+	}
+
+	return cfg, nil
 }
@@ -35,6 +35,7 @@ import (
 	"github.com/grafana/tempo/modules/compactor"
 	"github.com/grafana/tempo/modules/distributor"
 	"github.com/grafana/tempo/modules/distributor/receiver"
+	"github.com/grafana/tempo/modules/frontend/queryaudit"
 	frontend_v1 "github.com/grafana/tempo/modules/frontend/v1"
 	"github.com/grafana/tempo/modules/generator"
 	"github.com/grafana/tempo/modules/ingester"
@@ -74,6 +75,7 @@ type App struct {
 	distributor          *distributor.Distributor
 	querier              *querier.Querier
 	frontend             *frontend_v1.Frontend
+	queryAudit           *queryaudit.Auditor
 	compactor            *compactor.Compactor
 	ingester             *ingester.Ingester
 	generator            *generator.Generator
@@ -96,6 +98,16 @@ type App struct {
 
 // New makes a new app.
 func New(cfg Config) (*App, error) {
+	if err := cfg.TenantAliases.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid tenant_aliases: %w", err)
+	}
+	if err := cfg.ReceiverCertificateTenants.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid receiver_certificate_tenants: %w", err)
+	}
+	if err := cfg.validateQueryRelevantIngesters(); err != nil {
+		return nil, err
+	}
+
 	app := &App{
 		cfg:       cfg,
 		readRings: map[string]*ring.Ring{},
@@ -155,6 +167,13 @@ func (t *App) setupAuthMiddleware() {
 		}
 		t.HTTPAuthMiddleware = middleware.AuthenticateUser
 		t.TracesConsumerMiddleware = receiver.MultiTenancyMiddleware()
+		if len(t.cfg.ReceiverCertificateTenants) > 0 {
+			t.TracesConsumerMiddleware = receiver.CertificateTenantMiddleware(t.cfg.ReceiverCertificateTenants)
+		}
+
+		if len(t.cfg.TenantAliases) > 0 {
+			t.setupTenantAliases()
+		}
 	} else {
 		t.cfg.Server.GRPCMiddleware = []grpc.UnaryServerInterceptor{
 			fakeGRPCAuthUniaryMiddleware,
@@ -199,6 +218,7 @@ func (t *App) Run() error {
 	t.Server.HTTPRouter().Path(addHTTPAPIPrefix(&t.cfg, api.PathBuildInfo)).Handler(t.buildinfoHandler()).Methods("GET")
 
 	t.Server.HTTPRouter().Path("/ready").Handler(t.readyHandler(sm, shutdownRequested))
+	t.Server.HTTPRouter().Path("/status/config/verify").Handler(t.configVerifyHandler()).Methods("GET", "POST")
 	t.Server.HTTPRouter().Path("/status").Handler(t.statusHandler()).Methods("GET")
 	t.Server.HTTPRouter().Path("/status/{endpoint}").Handler(t.statusHandler()).Methods("GET")
 	grpc_health_v1.RegisterHealthServer(t.Server.GRPC(),
@@ -58,3 +58,16 @@ func TestApp_RunStop(t *testing.T) {
 		return httpErr != nil
 	}, 30*time.Second, 1*time.Second)
 }
+
+func TestNewRejectsQueryRelevantIngestersWithKafkaConsumption(t *testing.T) {
+	config := NewDefaultConfig()
+	config.Querier.QueryRelevantIngesters = true
+	config.Ingest.Enabled = true
+	config.Ingest.IngestersConsume = true
+
+	_, err := New(*config)
+	require.ErrorContains(t, err, "querier.query_relevant_ingesters can't be enabled when ingest.ingesters_consume is")
+
+	config.Ingest.IngestersConsume = false
+	require.NoError(t, config.validateQueryRelevantIngesters())
+}
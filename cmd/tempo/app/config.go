@@ -1,8 +1,10 @@
 package app
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/grafana/dskit/flagext"
@@ -15,6 +17,7 @@ import (
 	"github.com/grafana/tempo/modules/cache"
 	"github.com/grafana/tempo/modules/compactor"
 	"github.com/grafana/tempo/modules/distributor"
+	"github.com/grafana/tempo/modules/distributor/receiver"
 	"github.com/grafana/tempo/modules/frontend"
 	"github.com/grafana/tempo/modules/generator"
 	generator_client "github.com/grafana/tempo/modules/generator/client"
@@ -41,6 +44,10 @@ type Config struct {
 	StreamOverHTTPEnabled  bool          `yaml:"stream_over_http_enabled,omitempty"`
 	HTTPAPIPrefix          string        `yaml:"http_api_prefix"`
 	EnableGoRuntimeMetrics bool          `yaml:"enable_go_runtime_metrics,omitempty"`
+	// TenantAliases maps alias tenant IDs to the canonical tenant they are ingested and queried as.
+	TenantAliases util.TenantAliases `yaml:"tenant_aliases,omitempty"`
+	// ReceiverCertificateTenants maps the client certificates of the receivers to the tenant their traces are ingested as.
+	ReceiverCertificateTenants receiver.CertificateTenants `yaml:"receiver_certificate_tenants,omitempty"`
 
 	Server                server.Config                  `yaml:"server,omitempty"`
 	InternalServer        internalserver.Config          `yaml:"internal_server,omitempty"`
@@ -151,9 +158,29 @@ func (c *Config) MultitenancyIsEnabled() bool {
 	return c.MultitenancyEnabled || c.AuthEnabled
 }
 
+// ApplySingleBinarySettings forces the settings that are the only ones that make sense in single binary mode.
+func (c *Config) ApplySingleBinarySettings() {
+	if c.Target != SingleBinary {
+		return
+	}
+
+	c.Ingester.LifecyclerConfig.RingConfig.KVStore.Store = "inmemory"
+	c.Ingester.LifecyclerConfig.RingConfig.ReplicationFactor = 1
+	c.Ingester.LifecyclerConfig.Addr = "127.0.0.1"
+
+	// Generator's ring
+	c.Generator.Ring.KVStore.Store = "inmemory"
+	c.Generator.Ring.InstanceAddr = "127.0.0.1"
+}
+
 // CheckConfig checks if config values are suspect and returns a bundled list of warnings and explanation.
 func (c *Config) CheckConfig() []ConfigWarning {
-	var warnings []ConfigWarning
+	invalid, warnings := c.checkConfig()
+	return append(invalid, warnings...)
+}
+
+// checkConfig returns the settings Tempo will not start with apart from the suspect ones.
+func (c *Config) checkConfig() (invalid, warnings []ConfigWarning) {
 	if c.Ingester.CompleteBlockTimeout < c.StorageConfig.Trace.BlocklistPoll {
 		warnings = append(warnings, warnCompleteBlockTimeout)
 	}
@@ -190,10 +217,43 @@ func (c *Config) CheckConfig() []ConfigWarning {
 		warnings = append(warnings, warnMCPServerEnabled)
 	}
 
+	if err := c.TenantAliases.Validate(); err != nil {
+		invalid = append(invalid, ConfigWarning{
+			Path:    "tenant_aliases",
+			Message: "tenant_aliases: " + err.Error(),
+			Explain: "Tempo will not start with invalid tenant aliases",
+		})
+	}
+
+	if len(c.TenantAliases) > 0 && !c.MultitenancyIsEnabled() {
+		warnings = append(warnings, warnTenantAliasesWithoutMultitenancy)
+	}
+
+	if err := c.ReceiverCertificateTenants.Validate(); err != nil {
+		invalid = append(invalid, ConfigWarning{
+			Path:    "receiver_certificate_tenants",
+			Message: "receiver_certificate_tenants: " + err.Error(),
+			Explain: "Tempo will not start with invalid receiver certificate tenants",
+		})
+	}
+
+	if len(c.ReceiverCertificateTenants) > 0 && !c.MultitenancyIsEnabled() {
+		warnings = append(warnings, warnReceiverCertificateTenantsWithoutMultitenancy)
+	}
+
+	if err := c.validateQueryRelevantIngesters(); err != nil {
+		invalid = append(invalid, ConfigWarning{
+			Path:    "querier.query_relevant_ingesters",
+			Message: err.Error(),
+			Explain: "Tempo will not start because trace by id queries would miss the traces of other ingesters",
+		})
+	}
+
 	for _, dc := range c.StorageConfig.Trace.Block.DedicatedColumns {
 		err := dc.Validate()
 		if err != nil {
-			warnings = append(warnings, ConfigWarning{
+			invalid = append(invalid, ConfigWarning{
+				Path:    "storage.trace.block.parquet_dedicated_columns",
 				Message: err.Error(),
 				Explain: "Tempo will not start with an invalid dedicated attribute column configuration",
 			})
@@ -221,6 +281,10 @@ func (c *Config) CheckConfig() []ConfigWarning {
 		warnings = append(warnings, newV2Warning("v2_prefetch_traces_count"))
 	}
 
+	if c.StorageConfig.Trace.Block.Version != "v2" && c.Compactor.Compactor.ReadAheadChunks != 0 {
+		warnings = append(warnings, newV2Warning("v2_read_ahead_chunks"))
+	}
+
 	if c.tracesAndOverridesStorageConflict() {
 		warnings = append(warnings, warnTracesAndUserConfigurableOverridesStorageConflict)
 	}
@@ -249,86 +313,136 @@ func (c *Config) CheckConfig() []ConfigWarning {
 		warnings = append(warnings, warnBackendSchedulerPruneAgeLessThanBlocklistPoll)
 	}
 
-	return warnings
+	return invalid, warnings
+}
+
+// validateQueryRelevantIngesters rejects querying the owners of a trace in the ingester ring when the ingesters
+// consume Kafka. The trace is then in the ingester of the partition the distributor picked with the partition ring.
+func (c *Config) validateQueryRelevantIngesters() error {
+	if c.Querier.QueryRelevantIngesters && c.Ingest.Enabled && c.Ingest.IngestersConsume {
+		return errors.New("querier.query_relevant_ingesters can't be enabled when ingest.ingesters_consume is, traces are owned by the ingesters of their kafka partition")
+	}
+	return nil
 }
 
-// ConfigWarning bundles message and explanation strings in one structure.
+// ConfigWarning bundles message and explanation strings in one structure. Path is the YAML path of the setting the
+// warning is about, if any.
 type ConfigWarning struct {
-	Message string
-	Explain string
+	Path       string
+	Message    string
+	Explain    string
+	Deprecated bool
 }
 
 var (
 	warnCompleteBlockTimeout = ConfigWarning{
+		Path:    "ingester.complete_block_timeout",
 		Message: "ingester.complete_block_timeout < storage.trace.blocklist_poll",
 		Explain: "You may receive 404s between the time the ingesters have flushed a trace and the querier is aware of the new block",
 	}
 	warnBlockRetention = ConfigWarning{
-		Message: "compactor.compaction.compacted_block_timeout < storage.trace.blocklist_poll",
+		Path:    "compactor.compaction.block_retention",
+		Message: "compactor.compaction.block_retention < storage.trace.blocklist_poll",
 		Explain: "Queriers and Compactors may attempt to read a block that no longer exists",
 	}
 	warnRetentionConcurrency = ConfigWarning{
+		Path:    "compactor.compaction.retention_concurrency",
 		Message: "c.Compactor.Compactor.RetentionConcurrency must be greater than zero. Using default.",
 		Explain: fmt.Sprintf("default=%d", tempodb.DefaultRetentionConcurrency),
 	}
 	warnStorageTraceBackendS3 = ConfigWarning{
+		Path:    "compactor.compaction.v2_out_buffer_bytes",
 		Message: "c.Compactor.Compactor.FlushSizeBytes < 5242880",
 		Explain: "Compaction flush size should be 5MB or higher for S3 backend",
 	}
 	warnBlocklistPollConcurrency = ConfigWarning{
+		Path:    "storage.trace.blocklist_poll_concurrency",
 		Message: "c.StorageConfig.Trace.BlocklistPollConcurrency must be greater than zero. Using default.",
 		Explain: fmt.Sprintf("default=%d", tempodb.DefaultBlocklistPollConcurrency),
 	}
 	warnLogReceivedTraces = ConfigWarning{
+		Path:    "distributor.log_received_spans.enabled",
 		Message: "Span logging is enabled. This is for debugging only and not recommended for production deployments.",
 	}
 	warnLogDiscardedTraces = ConfigWarning{
+		Path:    "distributor.log_discarded_spans.enabled",
 		Message: "Span logging for discarded traces is enabled. This is for debugging only and not recommended for production deployments.",
 	}
 	warnStorageTraceBackendLocal = ConfigWarning{
+		Path:    "storage.trace.backend",
 		Message: "Local backend will not correctly retrieve traces with a distributed deployment unless all components have access to the same disk. You should probably be using object storage as a backend.",
 	}
 	warnLegacyOverridesConfig = ConfigWarning{
-		Message: "Inline, unscoped overrides are deprecated. Please use the new overrides config format.",
+		Path:       "overrides",
+		Message:    "Inline, unscoped overrides are deprecated. Please use the new overrides config format.",
+		Deprecated: true,
 	}
 
 	warnTracesAndUserConfigurableOverridesStorageConflict = ConfigWarning{
+		Path:    "overrides.user_configurable_overrides.client",
 		Message: "Trace storage conflicts with user-configurable overrides storage",
 	}
 
 	warnNativeAWSAuthEnabled = ConfigWarning{
-		Message: "c.StorageConfig.Trace.S3.NativeAWSAuthEnabled is deprecated and will be removed in a future release.",
-		Explain: "This setting is no longer necessary and will be ignored.",
+		Path:       "storage.trace.s3.native_aws_auth_enabled",
+		Message:    "c.StorageConfig.Trace.S3.NativeAWSAuthEnabled is deprecated and will be removed in a future release.",
+		Explain:    "This setting is no longer necessary and will be ignored.",
+		Deprecated: true,
 	}
 
 	warnConfiguredLegacyCache = ConfigWarning{
-		Message: "c.StorageConfig.Trace.Cache is deprecated and will be removed in a future release.",
-		Explain: "Please migrate to the top level cache settings config.",
+		Path:       "storage.trace.cache",
+		Message:    "c.StorageConfig.Trace.Cache is deprecated and will be removed in a future release.",
+		Explain:    "Please migrate to the top level cache settings config.",
+		Deprecated: true,
 	}
 
 	warnTraceByIDConcurrentShards = ConfigWarning{
+		Path:    "query_frontend.trace_by_id.concurrent_shards",
 		Message: "c.Frontend.TraceByID.ConcurrentShards greater than query_shards is invalid. concurrent_shards will be set to query_shards",
 		Explain: "Please remove ConcurrentShards or set it to a value less than or equal to QueryShards",
 	}
 
 	warnBlockAndWALVersionMismatch = ConfigWarning{
+		Path:    "block_builder.wal.version",
 		Message: "c.BlockConfig.BlockCfg.Version != c.WAL.Version",
 		Explain: "Block version and WAL version must match. WAL version will be set to block version",
 	}
 
 	warnMCPServerEnabled = ConfigWarning{
+		Path:    "query_frontend.mcp_server.enabled",
 		Message: "c.Frontend.MCPServer.Enabled is enabled.",
 		Explain: "Querying Tempo with an LLM will result in tracing data being sent to the LLM. Review your LLM provider's documentation and confirm you are comfortable with this.",
 	}
 
+	warnTenantAliasesWithoutMultitenancy = ConfigWarning{
+		Path:    "tenant_aliases",
+		Message: "tenant_aliases is set but multitenancy is disabled",
+		Explain: "Tenant aliases are only resolved when multitenancy is enabled",
+	}
+
+	warnReceiverCertificateTenantsWithoutMultitenancy = ConfigWarning{
+		Path:    "receiver_certificate_tenants",
+		Message: "receiver_certificate_tenants is set but multitenancy is disabled",
+		Explain: "Client certificates of the receivers are only mapped to tenants when multitenancy is enabled",
+	}
+
 	warnBackendSchedulerPruneAgeLessThanBlocklistPoll = ConfigWarning{
+		Path:    "backend_scheduler.work.prune_age",
 		Message: "c.BackendScheduler.Work.PruneAge must be greater than 2x the storage.trace.blocklist_poll duration",
 		Explain: "The backend scheduler needs not to prune work faster than the block list poll duration to avoid losing track of blocks which may have been have been compacted, but whose status has not been rediscovered during polling.",
 	}
 )
 
 func newV2Warning(setting string) ConfigWarning {
+	// the index settings are block settings, the others compaction settings
+	path := "compactor.compaction." + setting
+	if strings.HasPrefix(setting, "v2_index_") {
+		path = "storage.trace.block." + setting
+	}
+
 	return ConfigWarning{
+		Path:    path,
 		Message: "c.StorageConfig.Trace.Block.Version != \"v2\" but " + setting + " is set",
 		Explain: "This setting is only used in v2 blocks",
 	}
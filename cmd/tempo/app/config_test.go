@@ -9,7 +9,9 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/grafana/tempo/modules/distributor"
+	"github.com/grafana/tempo/modules/distributor/receiver"
 	"github.com/grafana/tempo/modules/storage"
+	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/encoding/common"
@@ -166,6 +168,65 @@ func TestConfig_CheckConfig(t *testing.T) {
 			}(),
 			expect: nil,
 		},
+		{
+			name: "tenant aliases",
+			config: func() *Config {
+				cfg := NewDefaultConfig()
+				cfg.MultitenancyEnabled = true
+				cfg.TenantAliases = util.TenantAliases{"old-team": "new-team"}
+				return cfg
+			}(),
+			expect: nil,
+		},
+		{
+			name: "invalid tenant aliases without multitenancy",
+			config: func() *Config {
+				cfg := NewDefaultConfig()
+				cfg.TenantAliases = util.TenantAliases{"old-team": "old-team"}
+				return cfg
+			}(),
+			expect: []ConfigWarning{
+				{
+					Path:    "tenant_aliases",
+					Message: `tenant_aliases: alias "old-team" is its own canonical tenant`,
+					Explain: "Tempo will not start with invalid tenant aliases",
+				},
+				warnTenantAliasesWithoutMultitenancy,
+			},
+		},
+		{
+			name: "invalid receiver certificate tenants without multitenancy",
+			config: func() *Config {
+				cfg := NewDefaultConfig()
+				cfg.ReceiverCertificateTenants = receiver.CertificateTenants{{Tenant: "team-a"}}
+				return cfg
+			}(),
+			expect: []ConfigWarning{
+				{
+					Path:    "receiver_certificate_tenants",
+					Message: "receiver_certificate_tenants: mapping 0 must have a san or an ou",
+					Explain: "Tempo will not start with invalid receiver certificate tenants",
+				},
+				warnReceiverCertificateTenantsWithoutMultitenancy,
+			},
+		},
+		{
+			name: "query relevant ingesters with ingesters consuming kafka",
+			config: func() *Config {
+				cfg := NewDefaultConfig()
+				cfg.Querier.QueryRelevantIngesters = true
+				cfg.Ingest.Enabled = true
+				cfg.Ingest.IngestersConsume = true
+				return cfg
+			}(),
+			expect: []ConfigWarning{
+				{
+					Path:    "querier.query_relevant_ingesters",
+					Message: "querier.query_relevant_ingesters can't be enabled when ingest.ingesters_consume is, traces are owned by the ingesters of their kafka partition",
+					Explain: "Tempo will not start because trace by id queries would miss the traces of other ingesters",
+				},
+			},
+		},
 	}
 
 	for _, tc := range tt {
@@ -0,0 +1,201 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/drone/envsubst"
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/tempo/modules/overrides"
+)
+
+// ConfigReport is the machine-readable result of the verification of a config. The config is valid if it has no
+// errors, Tempo will start with a config with warnings.
+type ConfigReport struct {
+	Valid    bool          `json:"valid"`
+	Errors   []ConfigIssue `json:"errors"`
+	Warnings []ConfigIssue `json:"warnings"`
+}
+
+// ConfigIssue is an error or a warning of a config. Path is the YAML path of the setting, or of the tenant for
+// per-tenant overrides, empty if the issue isn't about a single setting.
+type ConfigIssue struct {
+	Path       string `json:"path"`
+	Message    string `json:"message"`
+	Explain    string `json:"explain,omitempty"`
+	Deprecated bool   `json:"deprecated,omitempty"`
+}
+
+// NewConfigParseErrorReport returns the report of a config that couldn't be parsed.
+func NewConfigParseErrorReport(err error) *ConfigReport {
+	r := &ConfigReport{}
+	r.addError("", err)
+	return r.finish()
+}
+
+// Verify validates the config the way Tempo does when starting, the per-tenant overrides file included, and returns
+// the errors and warnings of the config. Unlike CheckConfig, the settings Tempo will not start with are errors and
+// not warnings.
+func (c *Config) Verify() *ConfigReport {
+	return c.verify(true)
+}
+
+// verify returns the report of the config. The per-tenant overrides file is only read if readOverrides is set.
+func (c *Config) verify(readOverrides bool) *ConfigReport {
+	r := &ConfigReport{}
+
+	invalid, warnings := c.checkConfig()
+	for _, w := range invalid {
+		r.Errors = append(r.Errors, ConfigIssue(w))
+	}
+	for _, w := range warnings {
+		r.Warnings = append(r.Warnings, ConfigIssue(w))
+	}
+
+	r.addError("distributor", c.Distributor.Validate())
+	r.addError("metrics_generator", c.Generator.Validate())
+	r.addError("ingest", c.Ingest.Validate())
+	r.addError("block_builder", c.BlockBuilder.Validate())
+	r.addError("cache", c.CacheProvider.Validate())
+
+	c.verifyOverrides(r, readOverrides)
+
+	return r.finish()
+}
+
+func (c *Config) verifyOverrides(r *ConfigReport, readOverrides bool) {
+	validator := newRuntimeConfigValidator(c)
+	r.addError("overrides.defaults", validator.Validate(&c.Overrides.Defaults))
+
+	path := c.Overrides.PerTenantOverrideConfig
+	if path == "" {
+		return
+	}
+	if !readOverrides {
+		r.Warnings = append(r.Warnings, warnDryRunOverrides)
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		r.addError("overrides.per_tenant_override_config", err)
+		return
+	}
+	defer f.Close()
+
+	tenantErrs, typ, err := overrides.ValidatePerTenantOverrides(f, validator, c.Overrides.ExpandEnv)
+	if err != nil {
+		r.addError("overrides.per_tenant_override_config", fmt.Errorf("failed to parse %s: %w", path, err))
+		return
+	}
+
+	if typ != c.Overrides.ConfigType {
+		r.Warnings = append(r.Warnings, ConfigIssue{
+			Path:    "overrides.per_tenant_override_config",
+			Message: "per-tenant overrides config type does not match static overrides config type",
+			Explain: fmt.Sprintf("per-tenant overrides in %s are %s while the static overrides are %s", path, typ, c.Overrides.ConfigType),
+		})
+	}
+	if typ == overrides.ConfigTypeLegacy {
+		r.Warnings = append(r.Warnings, ConfigIssue{
+			Path:       "overrides.per_tenant_override_config",
+			Message:    "Per-tenant overrides in the legacy format are deprecated. Please use the new overrides config format.",
+			Deprecated: true,
+		})
+	}
+
+	tenants := make([]string, 0, len(tenantErrs))
+	for tenant := range tenantErrs {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	for _, tenant := range tenants {
+		r.addError("overrides.per_tenant_override_config.overrides."+tenant, tenantErrs[tenant])
+	}
+}
+
+func (r *ConfigReport) addError(path string, err error) {
+	if err == nil {
+		return
+	}
+	r.Errors = append(r.Errors, ConfigIssue{Path: path, Message: err.Error()})
+}
+
+func (r *ConfigReport) finish() *ConfigReport {
+	r.Valid = len(r.Errors) == 0
+	// empty lists in the output instead of null
+	if r.Errors == nil {
+		r.Errors = []ConfigIssue{}
+	}
+	if r.Warnings == nil {
+		r.Warnings = []ConfigIssue{}
+	}
+	return r
+}
+
+// warnDryRunOverrides is the warning of a dry run of a config with a per-tenant overrides file. Files aren't read
+// in dry runs, the API would otherwise read any file on the host of Tempo.
+var warnDryRunOverrides = ConfigIssue{
+	Path:    "overrides.per_tenant_override_config",
+	Message: "the per-tenant overrides file is not verified in a dry run",
+	Explain: "verify the config with -config.verify to verify the per-tenant overrides file as well",
+}
+
+// configVerifyHandler returns the verification report of the running config. A config POSTed as YAML is verified
+// instead, without applying it. The POSTed config is parsed with the -config.expand-env setting of the running
+// config, but its per-tenant overrides file isn't read.
+func (t *App) configVerifyHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var report *ConfigReport
+		switch r.Method {
+		case http.MethodPost:
+			cfg, err := dryRunConfig(r.Body, t.cfg.Overrides.ExpandEnv)
+			if err != nil {
+				report = NewConfigParseErrorReport(err)
+			} else {
+				report = cfg.verify(false)
+			}
+		default:
+			report = t.cfg.Verify()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// dryRunConfig parses a config the same way as the config file is parsed at startup, expanding environment variables
+// if expandEnv is set.
+func dryRunConfig(r io.Reader, expandEnv bool) (*Config, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) == 0 {
+		return nil, errors.New("empty config")
+	}
+
+	if expandEnv {
+		s, err := envsubst.EvalEnv(string(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand env vars from config: %w", err)
+		}
+		body = []byte(s)
+	}
+
+	cfg := NewDefaultConfig()
+	if err := yaml.UnmarshalStrict(body, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	cfg.ApplySingleBinarySettings()
+	cfg.Overrides.ExpandEnv = expandEnv
+	return cfg, nil
+}
@@ -0,0 +1,207 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+func TestConfig_Verify(t *testing.T) {
+	writeOverrides := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "overrides.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	tt := []struct {
+		name   string
+		config func(t *testing.T) *Config
+		expect *ConfigReport
+	}{
+		{
+			name:   "default config",
+			config: func(*testing.T) *Config { return NewDefaultConfig() },
+			expect: &ConfigReport{Valid: true, Errors: []ConfigIssue{}, Warnings: []ConfigIssue{}},
+		},
+		{
+			name: "invalid settings are errors",
+			config: func(*testing.T) *Config {
+				cfg := NewDefaultConfig()
+				cfg.MultitenancyEnabled = true
+				cfg.TenantAliases = util.TenantAliases{"old-team": "old-team"}
+				cfg.Overrides.Defaults.Ingestion.TraceIDHashScheme = "bogus"
+				cfg.StorageConfig.Trace.Cache = "memcached"
+				return cfg
+			},
+			expect: &ConfigReport{
+				Valid: false,
+				Errors: []ConfigIssue{
+					{
+						Path:    "tenant_aliases",
+						Message: `tenant_aliases: alias "old-team" is its own canonical tenant`,
+						Explain: "Tempo will not start with invalid tenant aliases",
+					},
+					{
+						Path:    "overrides.defaults",
+						Message: `ingestion.trace_id_hash_scheme "bogus" is not a valid value, valid values: fnv32, xxhash64, fnv-128-fold`,
+					},
+				},
+				Warnings: []ConfigIssue{ConfigIssue(warnConfiguredLegacyCache)},
+			},
+		},
+		{
+			name: "per-tenant overrides",
+			config: func(t *testing.T) *Config {
+				cfg := NewDefaultConfig()
+				cfg.Overrides.ConfigType = overrides.ConfigTypeNew
+				cfg.Overrides.PerTenantOverrideConfig = writeOverrides(t, `
+overrides:
+  valid:
+    ingestion:
+      rate_limit_bytes: 100
+  invalid:
+    ingestion:
+      previous_trace_id_hash_scheme: bogus
+`)
+				return cfg
+			},
+			expect: &ConfigReport{
+				Valid: false,
+				Errors: []ConfigIssue{
+					{
+						Path:    "overrides.per_tenant_override_config.overrides.invalid",
+						Message: `ingestion.previous_trace_id_hash_scheme "bogus" is not a valid value, valid values: fnv32, xxhash64, fnv-128-fold`,
+					},
+				},
+				Warnings: []ConfigIssue{},
+			},
+		},
+		{
+			name: "legacy per-tenant overrides",
+			config: func(t *testing.T) *Config {
+				cfg := NewDefaultConfig()
+				cfg.Overrides.ConfigType = overrides.ConfigTypeNew
+				cfg.Overrides.PerTenantOverrideConfig = writeOverrides(t, `
+overrides:
+  tenant:
+    ingestion_rate_limit_bytes: 100
+`)
+				return cfg
+			},
+			expect: &ConfigReport{
+				Valid:  true,
+				Errors: []ConfigIssue{},
+				Warnings: []ConfigIssue{
+					{
+						Path:    "overrides.per_tenant_override_config",
+						Message: "per-tenant overrides config type does not match static overrides config type",
+					},
+					{
+						Path:       "overrides.per_tenant_override_config",
+						Message:    "Per-tenant overrides in the legacy format are deprecated. Please use the new overrides config format.",
+						Deprecated: true,
+					},
+				},
+			},
+		},
+		{
+			name: "unparsable per-tenant overrides",
+			config: func(t *testing.T) *Config {
+				cfg := NewDefaultConfig()
+				cfg.Overrides.ConfigType = overrides.ConfigTypeNew
+				cfg.Overrides.PerTenantOverrideConfig = writeOverrides(t, "overrides:\n  tenant:\n    unknown: 1\n")
+				return cfg
+			},
+			expect: &ConfigReport{
+				Valid:    false,
+				Errors:   []ConfigIssue{{Path: "overrides.per_tenant_override_config"}},
+				Warnings: []ConfigIssue{},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			report := tc.config(t).Verify()
+
+			// explanations and parse errors mention the temporary file
+			for i := range report.Warnings {
+				if report.Warnings[i].Path == "overrides.per_tenant_override_config" {
+					report.Warnings[i].Explain = ""
+				}
+			}
+			for i := range report.Errors {
+				if strings.HasPrefix(report.Errors[i].Message, "failed to parse") {
+					report.Errors[i].Message = ""
+				}
+			}
+
+			assert.Equal(t, tc.expect, report)
+		})
+	}
+}
+
+func TestConfigVerifyHandler(t *testing.T) {
+	app := &App{cfg: *NewDefaultConfig()}
+	app.cfg.StorageConfig.Trace.Cache = "memcached"
+
+	verify := func(method, body string) *ConfigReport {
+		req := httptest.NewRequest(method, "/status/config/verify", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		app.configVerifyHandler()(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		report := &ConfigReport{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), report))
+		return report
+	}
+
+	// the running config
+	report := verify(http.MethodGet, "")
+	assert.True(t, report.Valid)
+	assert.Equal(t, []ConfigIssue{ConfigIssue(warnConfiguredLegacyCache)}, report.Warnings)
+
+	// a dry run of a config with an invalid setting
+	report = verify(http.MethodPost, "overrides:\n  defaults:\n    ingestion:\n      trace_id_hash_scheme: bogus\n")
+	assert.False(t, report.Valid)
+	require.Len(t, report.Errors, 1)
+	assert.Equal(t, "overrides.defaults", report.Errors[0].Path)
+	assert.Empty(t, report.Warnings)
+
+	// the per-tenant overrides file of a dry run isn't read
+	report = verify(http.MethodPost, "overrides:\n  per_tenant_override_config: /etc/passwd\n")
+	assert.True(t, report.Valid)
+	assert.Equal(t, []ConfigIssue{warnDryRunOverrides}, report.Warnings)
+
+	// environment variables are expanded if the running config expands them
+	t.Setenv("TEMPO_TEST_HASH_SCHEME", "bogus")
+	body := "overrides:\n  defaults:\n    ingestion:\n      trace_id_hash_scheme: ${TEMPO_TEST_HASH_SCHEME}\n"
+	report = verify(http.MethodPost, body)
+	assert.False(t, report.Valid)
+	assert.Contains(t, report.Errors[0].Message, "${TEMPO_TEST_HASH_SCHEME}")
+
+	app.cfg.Overrides.ExpandEnv = true
+	report = verify(http.MethodPost, body)
+	assert.False(t, report.Valid)
+	require.Len(t, report.Errors, 1)
+	assert.Contains(t, report.Errors[0].Message, "bogus")
+	assert.NotContains(t, report.Errors[0].Message, "${TEMPO_TEST_HASH_SCHEME}")
+	app.cfg.Overrides.ExpandEnv = false
+
+	// a dry run of a config that can't be parsed
+	report = verify(http.MethodPost, "unknown_setting: true\n")
+	assert.False(t, report.Valid)
+	require.Len(t, report.Errors, 1)
+	assert.Contains(t, report.Errors[0].Message, "failed to parse config")
+}
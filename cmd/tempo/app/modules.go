@@ -31,6 +31,7 @@ import (
 	"github.com/grafana/tempo/modules/distributor"
 	"github.com/grafana/tempo/modules/frontend"
 	"github.com/grafana/tempo/modules/frontend/interceptor"
+	"github.com/grafana/tempo/modules/frontend/queryaudit"
 	frontend_v1pb "github.com/grafana/tempo/modules/frontend/v1/frontendv1pb"
 	"github.com/grafana/tempo/modules/generator"
 	"github.com/grafana/tempo/modules/ingester"
@@ -63,6 +64,7 @@ const (
 	Overrides      string = "overrides"
 	OverridesAPI   string = "overrides-api"
 	CacheProvider  string = "cache-provider"
+	QueryAudit     string = "query-audit"
 
 	// rings
 	IngesterRing          string = "ring"
@@ -253,6 +255,15 @@ func (t *App) initOverridesAPI() (services.Service, error) {
 func (t *App) initDistributor() (services.Service, error) {
 	t.cfg.Distributor.KafkaConfig = t.cfg.Ingest.Kafka
 	t.cfg.Distributor.KafkaWritePathEnabled = t.cfg.Ingest.Enabled // TODO: Don't mix config params
+	t.cfg.Distributor.KafkaOnlyWritePath = t.cfg.Ingest.Enabled && t.cfg.Ingest.IngestersConsume
+
+	if t.cfg.Distributor.OffboardingPollInterval > 0 {
+		reader, _, err := t.newRawBackend()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize distributor offboarding reader: %w", err)
+		}
+		t.cfg.Distributor.OffboardingReader = reader
+	}
 
 	// todo: make ingester client a module instead of passing the config everywhere
 	distributor, err := distributor.New(t.cfg.Distributor,
@@ -273,6 +284,8 @@ func (t *App) initDistributor() (services.Service, error) {
 		t.Server.HTTPRouter().Handle("/distributor/ring", distributor.DistributorRing)
 	}
 
+	t.Server.HTTPRouter().Handle("/distributor/rate_limits", http.HandlerFunc(distributor.RateLimitsHandler))
+
 	if usageHandler := distributor.UsageTrackerHandler(); usageHandler != nil {
 		t.Server.HTTPRouter().Handle("/usage_metrics", usageHandler)
 	}
@@ -302,6 +315,9 @@ func (t *App) initIngester() (services.Service, error) {
 	t.Server.HTTPRouter().Methods(http.MethodGet, http.MethodPost, http.MethodDelete).
 		Path("/ingester/prepare-partition-downscale").
 		Handler(http.HandlerFunc(t.ingester.PreparePartitionDownscaleHandler))
+	t.Server.HTTPRouter().Methods(http.MethodGet).
+		Path("/ingester/attribute-cardinality").
+		Handler(http.HandlerFunc(t.ingester.AttributeCardinalityHandler))
 	return t.ingester, nil
 }
 
@@ -459,6 +475,9 @@ func (t *App) initQuerier() (services.Service, error) {
 	tracesHandlerV2 := middleware.Wrap(http.HandlerFunc(t.querier.TraceByIDHandlerV2))
 	t.Server.HTTPRouter().Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathTracesV2)), tracesHandlerV2)
 
+	tracesByIDsHandler := middleware.Wrap(http.HandlerFunc(t.querier.TraceByIDsHandler))
+	t.Server.HTTPRouter().Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathTracesByIDs)), tracesByIDsHandler)
+
 	searchHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.SearchHandler))
 	t.Server.HTTPRouter().Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathSearch)), searchHandler)
 
@@ -480,6 +499,10 @@ func (t *App) initQuerier() (services.Service, error) {
 	queryRangeHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.QueryRangeHandler))
 	t.Server.HTTPRouter().Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathMetricsQueryRange)), queryRangeHandler)
 
+	// live tails are streamed by the querier itself, the query frontend doesn't proxy them
+	tailHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.TailHandler))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTail), tailHandler)
+
 	return t.querier, t.querier.CreateAndRegisterWorker(t.Server.HTTPHandler())
 }
 
@@ -493,6 +516,7 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 	t.frontend = v1
 
 	// create query frontend
+	t.cfg.Frontend.QueryAuditor = t.queryAudit
 	queryFrontend, err := frontend.New(t.cfg.Frontend, cortexTripper, t.Overrides, t.store, t.cacheProvider, t.cfg.HTTPAPIPrefix, t.HTTPAuthMiddleware, log.Logger, prometheus.DefaultRegisterer)
 	if err != nil {
 		return nil, err
@@ -521,6 +545,7 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 	// http trace by id endpoint
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTraces), base.Wrap(queryFrontend.TraceByIDHandler))
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTracesV2), base.Wrap(queryFrontend.TraceByIDHandlerV2))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTracesByIDs), base.Wrap(queryFrontend.TraceByIDsHandler))
 
 	// http search endpoints
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearch), base.Wrap(queryFrontend.SearchHandler))
@@ -533,6 +558,14 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSpanMetricsSummary), base.Wrap(queryFrontend.MetricsSummaryHandler))
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathMetricsQueryInstant), base.Wrap(queryFrontend.MetricsQueryInstantHandler))
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathMetricsQueryRange), base.Wrap(queryFrontend.MetricsQueryRangeHandler))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathMetricsRemoteRead), base.Wrap(queryFrontend.MetricsRemoteReadHandler))
+
+	// http traceql lint endpoints
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTraceQLParse), base.Wrap(queryFrontend.TraceQLParseHandler))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTraceQLValidate), base.Wrap(queryFrontend.TraceQLValidateHandler))
+
+	// http trace ui endpoint
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTraceUI), base.Wrap(queryFrontend.TraceUIHandler))
 
 	// http mcp endpoint
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathMCP), base.Wrap(queryFrontend.MCPHandler))
@@ -552,6 +585,17 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 	return t.frontend, nil
 }
 
+func (t *App) initQueryAudit() (services.Service, error) {
+	reader, writer, err := t.newRawBackend()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize query audit backend: %w", err)
+	}
+
+	t.queryAudit = queryaudit.New(t.cfg.Frontend.QueryAudit, reader, writer, t.Overrides, log.Logger)
+
+	return t.queryAudit, nil
+}
+
 //go:embed static
 var staticFiles embed.FS
 
@@ -597,6 +641,8 @@ func (t *App) initStore() (services.Service, error) {
 	}
 	t.store = store
 
+	t.Server.HTTPRouter().Path("/status/tenant-ownership").HandlerFunc(tempo_storage.TenantOwnershipHandler(t.store)).Methods("GET")
+
 	return t.store, nil
 }
 
@@ -692,6 +738,32 @@ func (t *App) initBackendScheduler() (services.Service, error) {
 		t.cfg.BackendScheduler.Poll = true
 	}
 
+	reader, writer, err := t.newRawBackend()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize backendscheduler reader/writer: %w", err)
+	}
+
+	scheduler, err := backendscheduler.New(t.cfg.BackendScheduler, t.store, t.Overrides, reader, writer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backend scheduler: %w", err)
+	}
+
+	// Register the GRPC service
+	tempopb.RegisterBackendSchedulerServer(t.Server.GRPC(), scheduler)
+
+	t.Server.HTTPRouter().Path("/status/backendscheduler").HandlerFunc(scheduler.StatusHandler)
+	t.Server.HTTPRouter().Path("/backendscheduler/offboarding/{tenant}").HandlerFunc(scheduler.OffboardingHandler).Methods("GET", "POST", "DELETE")
+	t.Server.HTTPRouter().Path("/backendscheduler/redactions/{tenant}").HandlerFunc(scheduler.RedactionHandler).Methods("GET", "POST")
+	t.Server.HTTPRouter().Path("/backendscheduler/redactions/{tenant}/{id}").HandlerFunc(scheduler.RedactionHandler).Methods("DELETE")
+	t.Server.HTTPRouter().Path("/backendscheduler/dedicated-columns/{tenant}").HandlerFunc(scheduler.DedicatedColumnsHandler).Methods("GET", "POST")
+
+	t.backendScheduler = scheduler
+
+	return scheduler, nil
+}
+
+// newRawBackend creates a raw reader and writer for the configured trace storage backend.
+func (t *App) newRawBackend() (backend.RawReader, backend.RawWriter, error) {
 	var (
 		err    error
 		reader backend.RawReader
@@ -711,23 +783,7 @@ func (t *App) initBackendScheduler() (services.Service, error) {
 		err = fmt.Errorf("unknown backend %s", t.cfg.StorageConfig.Trace.Backend)
 	}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize backendscheduler reader/writer: %w", err)
-	}
-
-	scheduler, err := backendscheduler.New(t.cfg.BackendScheduler, t.store, t.Overrides, reader, writer)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create backend scheduler: %w", err)
-	}
-
-	// Register the GRPC service
-	tempopb.RegisterBackendSchedulerServer(t.Server.GRPC(), scheduler)
-
-	t.Server.HTTPRouter().Path("/status/backendscheduler").HandlerFunc(scheduler.StatusHandler)
-
-	t.backendScheduler = scheduler
-
-	return scheduler, nil
+	return reader, writer, err
 }
 
 func (t *App) initBackendWorker() (services.Service, error) {
@@ -763,6 +819,7 @@ func (t *App) setupModuleManager() error {
 	mm.RegisterModule(OverridesAPI, t.initOverridesAPI)
 	mm.RegisterModule(UsageReport, t.initUsageReport)
 	mm.RegisterModule(CacheProvider, t.initCacheProvider, modules.UserInvisibleModule)
+	mm.RegisterModule(QueryAudit, t.initQueryAudit, modules.UserInvisibleModule)
 	mm.RegisterModule(IngesterRing, t.initIngesterRing, modules.UserInvisibleModule)
 	mm.RegisterModule(MetricsGeneratorRing, t.initGeneratorRing, modules.UserInvisibleModule)
 	mm.RegisterModule(GeneratorRingWatcher, t.initGeneratorRingWatcher, modules.UserInvisibleModule)
@@ -799,11 +856,12 @@ func (t *App) setupModuleManager() error {
 		MetricsGeneratorRing:  {Server, MemberlistKV},
 		PartitionRing:         {MemberlistKV, Server, IngesterRing},
 		GeneratorRingWatcher:  {MemberlistKV},
+		QueryAudit:            {Server, Overrides},
 
 		Common: {UsageReport, Server, Overrides},
 
 		// individual targets
-		QueryFrontend:                 {Common, Store, OverridesAPI},
+		QueryFrontend:                 {Common, Store, OverridesAPI, QueryAudit},
 		Distributor:                   {Common, IngesterRing, MetricsGeneratorRing, PartitionRing},
 		Ingester:                      {Common, Store, MemberlistKV, PartitionRing},
 		MetricsGenerator:              {Common, OptionalStore, MemberlistKV, PartitionRing},
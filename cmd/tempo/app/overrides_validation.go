@@ -5,12 +5,16 @@ import (
 	"slices"
 	"time"
 
+	"github.com/grafana/tempo/modules/distributor"
 	"github.com/grafana/tempo/modules/generator"
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/modules/overrides/userconfigurable/api"
 	"github.com/grafana/tempo/modules/overrides/userconfigurable/client"
 	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
 )
 
 type runtimeConfigValidator struct {
@@ -33,12 +37,50 @@ func (r *runtimeConfigValidator) Validate(config *overrides.Overrides) error {
 		}
 	}
 
+	for _, s := range []struct{ name, scheme string }{
+		{"ingestion.trace_id_hash_scheme", config.Ingestion.TraceIDHashScheme},
+		{"ingestion.previous_trace_id_hash_scheme", config.Ingestion.PreviousTraceIDHashScheme},
+	} {
+		if !util.ValidTraceIDHashScheme(s.scheme) {
+			return fmt.Errorf("%s \"%s\" is not a valid value, valid values: %s, %s, %s", s.name, s.scheme, util.TraceIDHashSchemeFNV32, util.TraceIDHashSchemeXXHash64, util.TraceIDHashSchemeFNV128Fold)
+		}
+	}
+
+	switch mode := config.Ingestion.AttributeDenylistMode; mode {
+	case "", distributor.AttributeDenylistModeDrop, distributor.AttributeDenylistModeHash:
+	default:
+		return fmt.Errorf("ingestion.attribute_denylist_mode \"%s\" is not a valid value, valid values: %s, %s", mode, distributor.AttributeDenylistModeDrop, distributor.AttributeDenylistModeHash)
+	}
+
 	if _, ok := registry.HistogramModeToValue[string(config.MetricsGenerator.GenerateNativeHistograms)]; !ok {
 		if config.MetricsGenerator.GenerateNativeHistograms != "" {
 			return fmt.Errorf("metrics_generator.generate_native_histograms \"%s\" is not a valid value, valid values: classic, native, both", config.MetricsGenerator.GenerateNativeHistograms)
 		}
 	}
 
+	if err := config.Storage.BlockEncoding.Validate(); err != nil {
+		return fmt.Errorf("storage.block_encoding: %w", err)
+	}
+
+	blockEncoding := config.Storage.BlockEncoding
+	if version := blockEncoding.Version; version != "" {
+		if _, err := encoding.FromVersion(version); err != nil {
+			return fmt.Errorf("storage.block_encoding.version: %w", err)
+		}
+	}
+
+	// the compression and dictionary encoding of the columns can only be changed for vParquet4 blocks
+	version := blockEncoding.Version
+	if version == "" && r.cfg.StorageConfig.Trace.Block != nil {
+		version = r.cfg.StorageConfig.Trace.Block.Version
+	}
+	if version == "" {
+		version = encoding.DefaultEncoding().Version()
+	}
+	if version != vparquet4.VersionString && (blockEncoding.Compression != "" || blockEncoding.ZstdLevel != 0 || blockEncoding.DisableDictionary) {
+		return fmt.Errorf("storage.block_encoding: parquet_compression, parquet_zstd_level and parquet_disable_dictionary are only supported by %s blocks, not %s", vparquet4.VersionString, version)
+	}
+
 	return nil
 }
 
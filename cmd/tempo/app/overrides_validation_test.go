@@ -14,7 +14,10 @@ import (
 	"github.com/grafana/tempo/modules/ingester"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/modules/overrides/userconfigurable/client"
+	"github.com/grafana/tempo/modules/storage"
 	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 func Test_runtimeOverridesValidator(t *testing.T) {
@@ -51,6 +54,31 @@ func Test_runtimeOverridesValidator(t *testing.T) {
 			},
 			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{TenantShardSize: 3}},
 		},
+		{
+			name:      "ingestion.trace_id_hash_scheme invalid",
+			cfg:       Config{},
+			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{TraceIDHashScheme: "md5"}},
+			expErr:    "ingestion.trace_id_hash_scheme \"md5\" is not a valid value, valid values: fnv32, xxhash64, fnv-128-fold",
+		},
+		{
+			name: "ingestion.trace_id_hash_scheme migration",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{
+				TraceIDHashScheme:         "xxhash64",
+				PreviousTraceIDHashScheme: "fnv32",
+			}},
+		},
+		{
+			name:      "ingestion.attribute_denylist_mode invalid",
+			cfg:       Config{},
+			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{AttributeDenylistMode: "redact"}},
+			expErr:    "ingestion.attribute_denylist_mode \"redact\" is not a valid value, valid values: drop, hash",
+		},
+		{
+			name:      "ingestion.attribute_denylist_mode hash",
+			cfg:       Config{},
+			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{AttributeDenylistMode: "hash"}},
+		},
 		{
 			name: "metrics_generator.generate_native_histograms invalid",
 			cfg:  Config{},
@@ -80,6 +108,59 @@ func Test_runtimeOverridesValidator(t *testing.T) {
 				GenerateNativeHistograms: "both",
 			}},
 		},
+		{
+			name: "storage.block_encoding valid",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Storage: overrides.StorageOverrides{BlockEncoding: common.BlockEncoding{
+				RowGroupSizeBytes: 50_000_000,
+				Compression:       common.ParquetCompressionZstd,
+				ZstdLevel:         9,
+			}}},
+		},
+		{
+			name:      "storage.block_encoding invalid compression",
+			cfg:       Config{},
+			overrides: overrides.Overrides{Storage: overrides.StorageOverrides{BlockEncoding: common.BlockEncoding{Compression: "brotli"}}},
+			expErr:    "storage.block_encoding: parquet_compression \"brotli\" is not a valid value, valid values: snappy, zstd, none",
+		},
+		{
+			name:      "storage.block_encoding invalid zstd level",
+			cfg:       Config{},
+			overrides: overrides.Overrides{Storage: overrides.StorageOverrides{BlockEncoding: common.BlockEncoding{ZstdLevel: 23}}},
+			expErr:    "storage.block_encoding: parquet_zstd_level must be between 1 and 22",
+		},
+		{
+			name:      "storage.block_encoding version",
+			cfg:       Config{},
+			overrides: overrides.Overrides{Storage: overrides.StorageOverrides{BlockEncoding: common.BlockEncoding{Version: "vParquet3"}}},
+		},
+		{
+			name:      "storage.block_encoding invalid version",
+			cfg:       Config{},
+			overrides: overrides.Overrides{Storage: overrides.StorageOverrides{BlockEncoding: common.BlockEncoding{Version: "vParquet9"}}},
+			expErr:    "storage.block_encoding.version: vParquet9 is not a valid block version: unsupported block version",
+		},
+		{
+			name:      "storage.block_encoding compression of a vParquet3 version",
+			cfg:       Config{},
+			overrides: overrides.Overrides{Storage: overrides.StorageOverrides{BlockEncoding: common.BlockEncoding{Version: "vParquet3", Compression: common.ParquetCompressionZstd}}},
+			expErr:    "storage.block_encoding: parquet_compression, parquet_zstd_level and parquet_disable_dictionary are only supported by vParquet4 blocks, not vParquet3",
+		},
+		{
+			name: "storage.block_encoding dictionary of the configured vParquet2 version",
+			cfg: Config{StorageConfig: storage.Config{Trace: tempodb.Config{Block: &common.BlockConfig{
+				Version: "vParquet2",
+			}}}},
+			overrides: overrides.Overrides{Storage: overrides.StorageOverrides{BlockEncoding: common.BlockEncoding{DisableDictionary: true}}},
+			expErr:    "storage.block_encoding: parquet_compression, parquet_zstd_level and parquet_disable_dictionary are only supported by vParquet4 blocks, not vParquet2",
+		},
+		{
+			name: "storage.block_encoding compression of a vParquet4 version",
+			cfg: Config{StorageConfig: storage.Config{Trace: tempodb.Config{Block: &common.BlockConfig{
+				Version: "vParquet3",
+			}}}},
+			overrides: overrides.Overrides{Storage: overrides.StorageOverrides{BlockEncoding: common.BlockEncoding{Version: "vParquet4", Compression: common.ParquetCompressionNone}}},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grafana/dskit/middleware"
+	"google.golang.org/grpc"
+
+	"github.com/grafana/tempo/modules/distributor/receiver"
+)
+
+// setupTenantAliases resolves the tenant aliases of the org ID injected by the auth middlewares, so requests of an
+// alias are ingested and queried as the canonical tenant.
+func (t *App) setupTenantAliases() {
+	aliases := t.cfg.TenantAliases
+
+	t.cfg.Server.GRPCMiddleware = append(t.cfg.Server.GRPCMiddleware,
+		func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			return handler(aliases.ResolveContext(ctx), req)
+		},
+	)
+	t.cfg.Server.GRPCStreamMiddleware = append(t.cfg.Server.GRPCStreamMiddleware,
+		func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return handler(srv, serverStream{
+				ctx:          aliases.ResolveContext(ss.Context()),
+				ServerStream: ss,
+			})
+		},
+	)
+
+	t.HTTPAuthMiddleware = middleware.Merge(t.HTTPAuthMiddleware, middleware.Func(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(aliases.ResolveContext(r.Context())))
+		})
+	}))
+	t.TracesConsumerMiddleware = receiver.TenantAliasMiddleware(t.TracesConsumerMiddleware, aliases)
+}
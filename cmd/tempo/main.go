@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
@@ -61,8 +62,12 @@ func main() {
 	mutexProfileFraction := flag.Int("mutex-profile-fraction", 0, "Override default mutex profiling fraction.")
 	blockProfileThreshold := flag.Int("block-profile-threshold", 0, "Override default block profiling threshold.")
 
-	config, configVerify, err := loadConfig()
+	config, configVerify, configVerifyFormat, err := loadConfig()
 	if err != nil {
+		if configVerify && configVerifyFormat == configVerifyFormatJSON {
+			writeConfigReport(app.NewConfigParseErrorReport(err))
+			os.Exit(1)
+		}
 		fmt.Fprintf(os.Stderr, "failed parsing config: %v\n", err)
 		os.Exit(1)
 	}
@@ -78,17 +83,17 @@ func main() {
 	}
 	log.InitLogger(&config.Server)
 
-	// Verifying the config's validity and log warnings now that the logger is initialized
-	isValid := configIsValid(config)
-
 	// Exit if config.verify flag is true
 	if configVerify {
-		if !isValid {
+		if !configIsVerified(config, configVerifyFormat) {
 			os.Exit(1)
 		}
 		os.Exit(0)
 	}
 
+	// Verifying the config's validity and log warnings now that the logger is initialized
+	configIsValid(config)
+
 	// Init tracer if OTEL_TRACES_EXPORTER, OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is set
 	if os.Getenv("OTEL_TRACES_EXPORTER") != "" || os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" {
 		shutdownTracer, err := installOpenTelemetryTracer(config)
@@ -140,17 +145,69 @@ func configIsValid(config *app.Config) bool {
 	return true
 }
 
-func loadConfig() (*app.Config, bool, error) {
+// configIsVerified verifies the config, the per-tenant overrides included, and reports its errors and warnings in
+// the given format. A config with warnings isn't verified.
+func configIsVerified(config *app.Config, format string) bool {
+	report := config.Verify()
+
+	if format == configVerifyFormatJSON {
+		writeConfigReport(report)
+		return report.Valid && len(report.Warnings) == 0
+	}
+
+	if len(report.Errors) != 0 {
+		level.Error(log.Logger).Log("msg", "-- CONFIGURATION ERRORS --")
+		for _, e := range report.Errors {
+			level.Error(log.Logger).Log(configIssueOutput(e)...)
+		}
+	}
+	if len(report.Warnings) != 0 {
+		level.Warn(log.Logger).Log("msg", "-- CONFIGURATION WARNINGS --")
+		for _, w := range report.Warnings {
+			level.Warn(log.Logger).Log(configIssueOutput(w)...)
+		}
+	}
+	return report.Valid && len(report.Warnings) == 0
+}
+
+func configIssueOutput(issue app.ConfigIssue) []any {
+	output := []any{"msg", issue.Message}
+	if issue.Path != "" {
+		output = append(output, "path", issue.Path)
+	}
+	if issue.Explain != "" {
+		output = append(output, "explain", issue.Explain)
+	}
+	if issue.Deprecated {
+		output = append(output, "deprecated", true)
+	}
+	return output
+}
+
+func writeConfigReport(report *app.ConfigReport) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(report)
+}
+
+const (
+	configVerifyFormatLogfmt = "logfmt"
+	configVerifyFormatJSON   = "json"
+)
+
+func loadConfig() (*app.Config, bool, string, error) {
 	const (
-		configFileOption      = "config.file"
-		configExpandEnvOption = "config.expand-env"
-		configVerifyOption    = "config.verify"
+		configFileOption         = "config.file"
+		configExpandEnvOption    = "config.expand-env"
+		configVerifyOption       = "config.verify"
+		configVerifyFormatOption = "config.verify-format"
 	)
 
 	var (
-		configFile      string
-		configExpandEnv bool
-		configVerify    bool
+		configFile         string
+		configExpandEnv    bool
+		configVerify       bool
+		configVerifyFormat string
 	)
 
 	args := os.Args[1:]
@@ -163,6 +220,7 @@ func loadConfig() (*app.Config, bool, error) {
 	fs.StringVar(&configFile, configFileOption, "", "")
 	fs.BoolVar(&configExpandEnv, configExpandEnvOption, false, "")
 	fs.BoolVar(&configVerify, configVerifyOption, false, "")
+	fs.StringVar(&configVerifyFormat, configVerifyFormatOption, configVerifyFormatLogfmt, "")
 
 	// Try to find -config.file & -config.expand-env flags. As Parsing stops on the first error, eg. unknown flag,
 	// we simply try remaining parameters until we find config flag, or there are no params left.
@@ -179,20 +237,20 @@ func loadConfig() (*app.Config, bool, error) {
 	if configFile != "" {
 		buff, err := os.ReadFile(configFile)
 		if err != nil {
-			return nil, false, fmt.Errorf("failed to read configFile %s: %w", configFile, err)
+			return nil, configVerify, configVerifyFormat, fmt.Errorf("failed to read configFile %s: %w", configFile, err)
 		}
 
 		if configExpandEnv {
 			s, err := envsubst.EvalEnv(string(buff))
 			if err != nil {
-				return nil, false, fmt.Errorf("failed to expand env vars from configFile %s: %w", configFile, err)
+				return nil, configVerify, configVerifyFormat, fmt.Errorf("failed to expand env vars from configFile %s: %w", configFile, err)
 			}
 			buff = []byte(s)
 		}
 
 		err = yaml.UnmarshalStrict(buff, config)
 		if err != nil {
-			return nil, false, fmt.Errorf("failed to parse configFile %s: %w", configFile, err)
+			return nil, configVerify, configVerifyFormat, fmt.Errorf("failed to parse configFile %s: %w", configFile, err)
 		}
 
 	}
@@ -204,21 +262,17 @@ func loadConfig() (*app.Config, bool, error) {
 	flagext.IgnoredFlag(flag.CommandLine, configFileOption, "Configuration file to load")
 	flagext.IgnoredFlag(flag.CommandLine, configExpandEnvOption, "Whether to expand environment variables in config file")
 	flagext.IgnoredFlag(flag.CommandLine, configVerifyOption, "Verify configuration and exit")
+	flagext.IgnoredFlag(flag.CommandLine, configVerifyFormatOption, "Format of the configuration verification report, logfmt or json")
 	flag.Parse()
 
 	// after loading config, let's force some values if in single binary mode
-	// if we're in single binary mode we're going to force some settings b/c nothing else makes sense
-	if config.Target == app.SingleBinary {
-		config.Ingester.LifecyclerConfig.RingConfig.KVStore.Store = "inmemory"
-		config.Ingester.LifecyclerConfig.RingConfig.ReplicationFactor = 1
-		config.Ingester.LifecyclerConfig.Addr = "127.0.0.1"
-
-		// Generator's ring
-		config.Generator.Ring.KVStore.Store = "inmemory"
-		config.Generator.Ring.InstanceAddr = "127.0.0.1"
+	config.ApplySingleBinarySettings()
+
+	if configVerifyFormat != configVerifyFormatLogfmt && configVerifyFormat != configVerifyFormatJSON {
+		return nil, configVerify, configVerifyFormatLogfmt, fmt.Errorf("unknown value for %s: %s", configVerifyFormatOption, configVerifyFormat)
 	}
 
-	return config, configVerify, nil
+	return config, configVerify, configVerifyFormat, nil
 }
 
 func installOpenTelemetryTracer(config *app.Config) (func(), error) {
@@ -51,6 +51,12 @@ type BackendScheduler struct {
 	}
 
 	mergedJobs chan *work.Job
+
+	offboardingMtx sync.Mutex
+	offboarding    map[string]*backend.TenantOffboarding
+
+	redactionsMtx sync.Mutex
+	redacting     map[string]struct{} // tenants with running redactions
 }
 
 // ListJobs returns all jobs in the work cache
@@ -66,13 +72,15 @@ func New(cfg Config, store storage.Store, overrides overrides.Interface, reader
 	}
 
 	s := &BackendScheduler{
-		cfg:        cfg,
-		store:      store,
-		overrides:  overrides,
-		work:       work.New(cfg.Work),
-		reader:     reader,
-		writer:     writer,
-		mergedJobs: make(chan *work.Job, 1),
+		cfg:         cfg,
+		store:       store,
+		overrides:   overrides,
+		work:        work.New(cfg.Work),
+		reader:      reader,
+		writer:      writer,
+		mergedJobs:  make(chan *work.Job, 1),
+		offboarding: make(map[string]*backend.TenantOffboarding),
+		redacting:   make(map[string]struct{}),
 	}
 
 	// Initialize providers
@@ -85,7 +93,7 @@ func New(cfg Config, store storage.Store, overrides overrides.Interface, reader
 				s.cfg.ProviderConfig.Compaction,
 				log.Logger,
 				s.store,
-				s.overrides,
+				&offboardingOverrides{Interface: s.overrides, s: s},
 				s.work,
 			),
 			jobs: nil, // Will be set in running
@@ -116,6 +124,16 @@ func (s *BackendScheduler) starting(ctx context.Context) error {
 		return fmt.Errorf("failed to load work cache: %w", err)
 	}
 
+	err = s.loadOffboarding(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load tenant offboarding: %w", err)
+	}
+
+	err = s.loadRedactions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load attribute redactions: %w", err)
+	}
+
 	wg := sync.WaitGroup{}
 
 	for i := range s.providers {
@@ -174,6 +192,8 @@ func (s *BackendScheduler) running(ctx context.Context) error {
 			return nil
 		case <-maintenanceTicker.C:
 			s.work.Prune(ctx)
+			s.processOffboarding(ctx)
+			s.processRedactions(ctx)
 		case <-backendFlushTicker.C:
 			err = s.flushWorkCacheToBackend(ctx)
 			metricWorkFlushes.Inc()
@@ -20,6 +20,8 @@ type Config struct {
 	ProviderConfig provider.Config `yaml:"provider"`
 	JobTimeout     time.Duration   `yaml:"job_timeout"`
 	LocalWorkPath  string          `yaml:"local_work_path,omitempty"` // Path to store local work cache
+
+	Offboarding OffboardingConfig `yaml:"offboarding"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
@@ -32,6 +34,8 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	cfg.Work.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "work"), f)
 
 	cfg.ProviderConfig.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "provider"), f)
+
+	cfg.Offboarding.RegisterFlagsAndApplyDefaults(prefix, f)
 }
 
 func ValidateConfig(cfg *Config) error {
@@ -0,0 +1,76 @@
+package backendscheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-kit/log/level"
+	"github.com/gorilla/mux"
+	"github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// defaultRecommendationBlocks is the number of recent blocks analysed when the request doesn't set it.
+const defaultRecommendationBlocks = 10
+
+// DedicatedColumnsHandler serves the dedicated columns recommendation API.
+//
+//	GET  returns the last recommendation of the tenant
+//	POST analyses the most recent blocks of the tenant and stores the new recommendation, the number of blocks is
+//	     set with the blocks parameter
+//
+// The recommended columns are applied by the next compactions of the tenant if the dedicated_columns_auto_apply
+// compaction override is enabled.
+func (s *BackendScheduler) DedicatedColumnsHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID := mux.Vars(r)[muxVarTenant]
+	if tenantID == "" {
+		http.Error(w, "tenant is required", http.StatusBadRequest)
+		return
+	}
+
+	var (
+		rec *backend.DedicatedColumnsRecommendation
+		err error
+	)
+
+	switch r.Method {
+	case http.MethodGet:
+		rec, err = backend.ReadDedicatedColumnsRecommendation(r.Context(), s.reader, tenantID)
+	case http.MethodPost:
+		blocks := defaultRecommendationBlocks
+		if v := r.URL.Query().Get("blocks"); v != "" {
+			blocks, err = strconv.Atoi(v)
+			if err != nil || blocks <= 0 {
+				http.Error(w, fmt.Sprintf("invalid blocks %q", v), http.StatusBadRequest)
+				return
+			}
+		}
+
+		rec, err = s.store.RecommendDedicatedColumns(r.Context(), tenantID, blocks)
+		if err == nil {
+			err = backend.WriteDedicatedColumnsRecommendation(r.Context(), s.writer, rec)
+		}
+		if err == nil {
+			level.Info(log.Logger).Log("msg", "recommended dedicated columns", "tenantID", tenantID, "blocks", len(rec.Blocks), "columns", len(rec.Columns))
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case errors.Is(err, backend.ErrDoesNotExist):
+		http.Error(w, fmt.Sprintf("no dedicated columns recommendation for tenant %s", tenantID), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(rec)
+}
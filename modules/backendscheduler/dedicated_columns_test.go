@@ -0,0 +1,97 @@
+package backendscheduler
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestDedicatedColumnsRecommendation(t *testing.T) {
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+	cfg.LocalWorkPath = t.TempDir()
+
+	var (
+		ctx, cancel   = context.WithCancel(context.Background())
+		store, rr, ww = newStore(ctx, t, t.TempDir())
+	)
+	defer func() {
+		cancel()
+		store.Shutdown()
+	}()
+
+	limits, err := overrides.NewOverrides(overrides.Config{Defaults: overrides.Overrides{}}, nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	s, err := New(cfg, store, limits, rr, ww)
+	require.NoError(t, err)
+
+	recommend := func(method, query string) (int, *backend.DedicatedColumnsRecommendation) {
+		req := httptest.NewRequest(method, "/backendscheduler/dedicated-columns/"+tenant+query, nil)
+		req = mux.SetURLVars(req, map[string]string{muxVarTenant: tenant})
+		w := httptest.NewRecorder()
+		s.DedicatedColumnsHandler(w, req)
+		if w.Code != http.StatusOK {
+			return w.Code, nil
+		}
+		rec := &backend.DedicatedColumnsRecommendation{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), rec))
+		return w.Code, rec
+	}
+
+	// nothing to recommend from yet
+	code, _ := recommend(http.MethodGet, "")
+	require.Equal(t, http.StatusNotFound, code)
+	code, _ = recommend(http.MethodPost, "")
+	require.Equal(t, http.StatusNotFound, code)
+	code, _ = recommend(http.MethodPost, "?blocks=0")
+	require.Equal(t, http.StatusBadRequest, code)
+
+	// a block with traces holding the dedicated attributes
+	dec := model.MustNewSegmentDecoder(model.CurrentEncoding)
+	block, err := store.WAL().NewBlock(&backend.BlockMeta{BlockID: backend.NewUUID(), TenantID: tenant}, model.CurrentEncoding)
+	require.NoError(t, err)
+	now := uint32(time.Now().Add(-time.Minute).Unix())
+	for range 10 {
+		id := test.ValidTraceID(nil)
+		b, err := dec.PrepareForWrite(test.AddDedicatedAttributes(test.MakeTrace(10, id)), now, now)
+		require.NoError(t, err)
+		obj, err := dec.ToObject([][]byte{b})
+		require.NoError(t, err)
+		require.NoError(t, block.Append(id, obj, now, now, true))
+	}
+	require.NoError(t, block.Flush())
+	complete, err := store.CompleteBlock(ctx, block)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(store.BlockMetas(tenant)) == 1
+	}, 5*time.Second, 100*time.Millisecond, "wait for the blocklist to be polled")
+
+	code, rec := recommend(http.MethodPost, "?blocks=5")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, tenant, rec.TenantID)
+	require.Equal(t, []backend.UUID{complete.BlockMeta().BlockID}, rec.Blocks)
+	require.Contains(t, rec.Columns, backend.DedicatedColumn{Scope: backend.DedicatedColumnScopeSpan, Name: "dedicated.span.1", Type: backend.DedicatedColumnTypeString})
+	require.Contains(t, rec.Columns, backend.DedicatedColumn{Scope: backend.DedicatedColumnScopeResource, Name: "dedicated.resource.1", Type: backend.DedicatedColumnTypeString})
+	require.NotEmpty(t, rec.Attributes)
+
+	// the recommendation is stored
+	code, stored := recommend(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, rec.Columns, stored.Columns)
+	require.Equal(t, rec.Blocks, stored.Blocks)
+}
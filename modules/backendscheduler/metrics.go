@@ -62,4 +62,34 @@ var (
 		NativeHistogramMaxBucketNumber:  100,
 		NativeHistogramMinResetDuration: 1 * time.Hour,
 	})
+	metricOffboardingDeletedObjects = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "backend_scheduler_offboarding_deleted_objects_total",
+		Help:      "The number of objects deleted while offboarding tenants",
+	})
+	metricOffboardingDeletedBytes = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "backend_scheduler_offboarding_deleted_bytes_total",
+		Help:      "The number of block bytes deleted while offboarding tenants",
+	})
+	metricOffboardingFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "backend_scheduler_offboarding_failures_total",
+		Help:      "The number of failed tenant offboarding passes",
+	})
+	metricRedactedValues = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "backend_scheduler_redacted_values_total",
+		Help:      "The number of attribute values replaced by attribute redactions",
+	})
+	metricRedactionRewrittenBlocks = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "backend_scheduler_redaction_rewritten_blocks_total",
+		Help:      "The number of blocks rewritten by attribute redactions",
+	})
+	metricRedactionFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "backend_scheduler_redaction_failures_total",
+		Help:      "The number of blocks and passes of attribute redactions that failed",
+	})
 )
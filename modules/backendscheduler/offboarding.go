@@ -0,0 +1,327 @@
+package backendscheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/grafana/tempo/modules/overrides"
+	tempo_io "github.com/grafana/tempo/pkg/io"
+	"github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const muxVarTenant = "tenant"
+
+type OffboardingConfig struct {
+	ConfirmationWindow time.Duration `yaml:"confirmation_window"`
+}
+
+func (cfg *OffboardingConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.DurationVar(&cfg.ConfirmationWindow, prefix+"backend-scheduler.offboarding.confirmation-window", 24*time.Hour, "Time between a tenant offboarding request and the deletion of its data. The offboarding can be cancelled during this window.")
+}
+
+// OffboardingHandler serves the tenant offboarding API.
+//
+//	GET    returns the offboarding record for the tenant, including the final report once complete
+//	POST   starts offboarding the tenant. Writes are rejected immediately and data is deleted after the confirmation window
+//	DELETE cancels an offboarding that is still in its confirmation window
+func (s *BackendScheduler) OffboardingHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID := mux.Vars(r)[muxVarTenant]
+	if tenantID == "" {
+		http.Error(w, "tenant is required", http.StatusBadRequest)
+		return
+	}
+
+	var (
+		o   *backend.TenantOffboarding
+		err error
+	)
+
+	switch r.Method {
+	case http.MethodGet:
+		o, err = backend.ReadTenantOffboarding(r.Context(), s.reader, tenantID)
+	case http.MethodPost:
+		o, err = s.startOffboarding(r.Context(), tenantID)
+	case http.MethodDelete:
+		o, err = s.cancelOffboarding(r.Context(), tenantID)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case errors.Is(err, backend.ErrDoesNotExist):
+		http.Error(w, fmt.Sprintf("tenant %s is not being offboarded", tenantID), http.StatusNotFound)
+		return
+	case errors.Is(err, errOffboardingConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(o)
+}
+
+var errOffboardingConflict = errors.New("offboarding conflict")
+
+func (s *BackendScheduler) startOffboarding(ctx context.Context, tenantID string) (*backend.TenantOffboarding, error) {
+	s.offboardingMtx.Lock()
+	defer s.offboardingMtx.Unlock()
+
+	existing, err := backend.ReadTenantOffboarding(ctx, s.reader, tenantID)
+	if err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
+		return nil, err
+	}
+	if !existing.AcceptsWrites() {
+		return nil, fmt.Errorf("%w: tenant %s is already %s", errOffboardingConflict, tenantID, existing.State)
+	}
+
+	now := time.Now()
+	o := &backend.TenantOffboarding{
+		TenantID:    tenantID,
+		State:       backend.OffboardingStatePending,
+		RequestedAt: now,
+		DeleteAfter: now.Add(s.cfg.Offboarding.ConfirmationWindow),
+	}
+
+	err = backend.WriteTenantOffboarding(ctx, s.writer, o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write offboarding record: %w", err)
+	}
+
+	s.offboarding[tenantID] = o
+	level.Info(log.Logger).Log("msg", "tenant offboarding started", "tenant", tenantID, "delete_after", o.DeleteAfter)
+
+	return o, nil
+}
+
+func (s *BackendScheduler) cancelOffboarding(ctx context.Context, tenantID string) (*backend.TenantOffboarding, error) {
+	s.offboardingMtx.Lock()
+	defer s.offboardingMtx.Unlock()
+
+	o, err := backend.ReadTenantOffboarding(ctx, s.reader, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if o.State != backend.OffboardingStatePending {
+		return nil, fmt.Errorf("%w: tenant %s is %s and can no longer be cancelled", errOffboardingConflict, tenantID, o.State)
+	}
+
+	o.State = backend.OffboardingStateCancelled
+	o.CompletedAt = time.Now()
+
+	err = backend.WriteTenantOffboarding(ctx, s.writer, o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write offboarding record: %w", err)
+	}
+
+	delete(s.offboarding, tenantID)
+	level.Info(log.Logger).Log("msg", "tenant offboarding cancelled", "tenant", tenantID)
+
+	return o, nil
+}
+
+// loadOffboarding restores the in progress offboardings from the backend.
+func (s *BackendScheduler) loadOffboarding(ctx context.Context) error {
+	tenants, err := backend.NewReader(s.reader).Tenants(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.offboardingMtx.Lock()
+	defer s.offboardingMtx.Unlock()
+
+	for _, tenantID := range tenants {
+		o, err := backend.ReadTenantOffboarding(ctx, s.reader, tenantID)
+		if errors.Is(err, backend.ErrDoesNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read offboarding record for tenant %s: %w", tenantID, err)
+		}
+
+		switch o.State {
+		case backend.OffboardingStatePending, backend.OffboardingStateDeleting:
+			s.offboarding[tenantID] = o
+		}
+	}
+
+	return nil
+}
+
+func (s *BackendScheduler) isOffboarding(tenantID string) bool {
+	s.offboardingMtx.Lock()
+	defer s.offboardingMtx.Unlock()
+
+	_, ok := s.offboarding[tenantID]
+	return ok
+}
+
+// processOffboarding deletes the data of all tenants whose confirmation window has passed. The lock is only
+// held while moving offboardings out of the pending state so the deletion itself does not block the API.
+func (s *BackendScheduler) processOffboarding(ctx context.Context) {
+	var due []*backend.TenantOffboarding
+
+	s.offboardingMtx.Lock()
+	for tenantID, o := range s.offboarding {
+		if o.State == backend.OffboardingStatePending {
+			if time.Now().Before(o.DeleteAfter) {
+				continue
+			}
+
+			o.State = backend.OffboardingStateDeleting
+			if err := backend.WriteTenantOffboarding(ctx, s.writer, o); err != nil {
+				o.State = backend.OffboardingStatePending
+				metricOffboardingFailures.Inc()
+				level.Error(log.Logger).Log("msg", "failed to write offboarding record", "tenant", tenantID, "err", err)
+				continue
+			}
+			level.Info(log.Logger).Log("msg", "tenant offboarding deleting data", "tenant", tenantID)
+		}
+		due = append(due, o)
+	}
+	s.offboardingMtx.Unlock()
+
+	for _, o := range due {
+		err := s.offboardTenant(ctx, o)
+		if err != nil {
+			metricOffboardingFailures.Inc()
+			level.Error(log.Logger).Log("msg", "failed to offboard tenant", "tenant", o.TenantID, "err", err)
+			continue
+		}
+
+		if o.State == backend.OffboardingStateComplete {
+			s.offboardingMtx.Lock()
+			delete(s.offboarding, o.TenantID)
+			s.offboardingMtx.Unlock()
+		}
+	}
+}
+
+// offboardTenant makes one deletion pass over a tenant in the deleting state. All blocks are marked
+// compacted so they are no longer queried, then every object in the tenant path is deleted. The offboarding
+// is complete once a pass finds nothing left to delete, which also catches blocks that were flushed or
+// compacted after the first pass.
+func (s *BackendScheduler) offboardTenant(ctx context.Context, o *backend.TenantOffboarding) error {
+	tenantID := o.TenantID
+
+	metas := s.store.BlockMetas(tenantID)
+	for _, m := range metas {
+		if err := s.store.MarkBlockCompacted(tenantID, m.BlockID); err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
+			return fmt.Errorf("failed to mark block %s compacted: %w", m.BlockID, err)
+		}
+		o.Report.MarkedBlocks++
+	}
+	if err := s.store.MarkBlocklistCompacted(tenantID, metas, nil); err != nil {
+		return fmt.Errorf("failed to mark blocklist compacted: %w", err)
+	}
+
+	report, err := s.deleteTenantObjects(ctx, tenantID)
+	o.Report.DeletedBlocks += report.DeletedBlocks
+	o.Report.DeletedObjects += report.DeletedObjects
+	o.Report.DeletedBytes += report.DeletedBytes
+	metricOffboardingDeletedObjects.Add(float64(report.DeletedObjects))
+	metricOffboardingDeletedBytes.Add(float64(report.DeletedBytes))
+	if err != nil {
+		return err
+	}
+
+	if report.DeletedObjects == 0 {
+		o.State = backend.OffboardingStateComplete
+		o.CompletedAt = time.Now()
+		level.Info(log.Logger).Log(
+			"msg", "tenant offboarding complete",
+			"tenant", tenantID,
+			"requested_at", o.RequestedAt,
+			"marked_blocks", o.Report.MarkedBlocks,
+			"deleted_blocks", o.Report.DeletedBlocks,
+			"deleted_objects", o.Report.DeletedObjects,
+			"deleted_bytes", o.Report.DeletedBytes)
+	}
+
+	return backend.WriteTenantOffboarding(ctx, s.writer, o)
+}
+
+// deleteTenantObjects deletes every object in the tenant path except the offboarding record. Sizes are taken
+// from the block metas before they are deleted.
+func (s *BackendScheduler) deleteTenantObjects(ctx context.Context, tenantID string) (backend.OffboardingReport, error) {
+	var report backend.OffboardingReport
+
+	blockIDs, compactedBlockIDs, err := backend.NewReader(s.reader).Blocks(ctx, tenantID)
+	if err != nil {
+		return report, fmt.Errorf("failed to list blocks: %w", err)
+	}
+	for _, id := range blockIDs {
+		report.DeletedBytes += s.blockSize(ctx, id, tenantID, backend.MetaName)
+	}
+	for _, id := range compactedBlockIDs {
+		report.DeletedBytes += s.blockSize(ctx, id, tenantID, backend.CompactedMetaName)
+	}
+	report.DeletedBlocks = len(blockIDs) + len(compactedBlockIDs)
+
+	var objects []string
+	err = s.reader.Find(ctx, backend.KeyPath{tenantID}, func(m backend.FindMatch) {
+		if path.Base(m.Key) != backend.OffboardingFileName {
+			objects = append(objects, m.Key)
+		}
+	})
+	if err != nil {
+		return report, fmt.Errorf("failed to find tenant objects: %w", err)
+	}
+
+	for _, object := range objects {
+		dir, name := path.Split(object)
+		err = s.writer.Delete(ctx, name, backend.KeyPath{dir}, nil)
+		if err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
+			return report, fmt.Errorf("failed to delete %s: %w", object, err)
+		}
+		report.DeletedObjects++
+	}
+
+	return report, nil
+}
+
+// blockSize returns the size recorded in the block meta or 0 if it can't be read.
+func (s *BackendScheduler) blockSize(ctx context.Context, blockID uuid.UUID, tenantID, metaName string) uint64 {
+	reader, size, err := s.reader.Read(ctx, metaName, backend.KeyPathForBlock(blockID, tenantID), nil)
+	if err != nil {
+		return 0
+	}
+	defer reader.Close()
+
+	b, err := tempo_io.ReadAllWithEstimate(reader, size)
+	if err != nil {
+		return 0
+	}
+
+	meta := &backend.BlockMeta{}
+	if err := json.Unmarshal(b, meta); err != nil {
+		return 0
+	}
+
+	return meta.Size_
+}
+
+// offboardingOverrides disables compaction for tenants that are being offboarded or redacted. Redacted blocks
+// would otherwise be compacted with unredacted ones while the redaction is running.
+type offboardingOverrides struct {
+	overrides.Interface
+	s *BackendScheduler
+}
+
+func (o *offboardingOverrides) CompactionDisabled(tenantID string) bool {
+	return o.s.isOffboarding(tenantID) || o.s.isRedacting(tenantID) || o.Interface.CompactionDisabled(tenantID)
+}
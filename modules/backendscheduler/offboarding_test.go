@@ -0,0 +1,128 @@
+package backendscheduler
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestTenantOffboarding(t *testing.T) {
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+	cfg.LocalWorkPath = t.TempDir()
+
+	var (
+		ctx, cancel   = context.WithCancel(context.Background())
+		store, rr, ww = newStore(ctx, t, t.TempDir())
+	)
+	defer func() {
+		cancel()
+		store.Shutdown()
+	}()
+
+	limits, err := overrides.NewOverrides(overrides.Config{Defaults: overrides.Overrides{}}, nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	writeTenantBlocks(ctx, t, backend.NewWriter(ww), tenant, 5)
+	otherTenant := tenant + "-other"
+	writeTenantBlocks(ctx, t, backend.NewWriter(ww), otherTenant, 2)
+
+	time.Sleep(500 * time.Millisecond) // wait for the blocklist to be polled
+
+	s, err := New(cfg, store, limits, rr, ww)
+	require.NoError(t, err)
+
+	offboard := func(method string) (int, *backend.TenantOffboarding) {
+		req := httptest.NewRequest(method, "/backendscheduler/offboarding/"+tenant, nil)
+		req = mux.SetURLVars(req, map[string]string{muxVarTenant: tenant})
+		w := httptest.NewRecorder()
+		s.OffboardingHandler(w, req)
+
+		if w.Code != http.StatusOK {
+			return w.Code, nil
+		}
+
+		o := &backend.TenantOffboarding{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), o))
+		return w.Code, o
+	}
+
+	code, _ := offboard(http.MethodGet)
+	require.Equal(t, http.StatusNotFound, code)
+
+	// start and cancel within the confirmation window
+	code, o := offboard(http.MethodPost)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, backend.OffboardingStatePending, o.State)
+	require.True(t, s.offboardingOverrides().CompactionDisabled(tenant))
+
+	code, _ = offboard(http.MethodPost)
+	require.Equal(t, http.StatusConflict, code)
+
+	s.processOffboarding(ctx)
+	require.Len(t, store.BlockMetas(tenant), 5, "data must be kept during the confirmation window")
+
+	code, o = offboard(http.MethodDelete)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, backend.OffboardingStateCancelled, o.State)
+	require.False(t, s.offboardingOverrides().CompactionDisabled(tenant))
+
+	// start again without a confirmation window
+	s.cfg.Offboarding.ConfirmationWindow = 0
+	code, _ = offboard(http.MethodPost)
+	require.Equal(t, http.StatusOK, code)
+
+	// a restarted scheduler picks up the in progress offboarding
+	s2, err := New(cfg, store, limits, rr, ww)
+	require.NoError(t, err)
+	require.NoError(t, s2.loadOffboarding(ctx))
+	require.True(t, s2.isOffboarding(tenant))
+
+	s.processOffboarding(ctx)
+	code, o = offboard(http.MethodGet)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, backend.OffboardingStateDeleting, o.State)
+	require.Equal(t, 5, o.Report.MarkedBlocks)
+	require.Equal(t, 5, o.Report.DeletedBlocks)
+	require.Equal(t, 7, o.Report.DeletedObjects) // one meta per block and both tenant index formats
+	require.Empty(t, store.BlockMetas(tenant))
+
+	code, _ = offboard(http.MethodDelete)
+	require.Equal(t, http.StatusConflict, code)
+
+	// a later pass finds nothing left and completes. the polling store may still rewrite the tenant
+	// index right after the first pass, in which case that is deleted as well.
+	require.Eventually(t, func() bool {
+		s.processOffboarding(ctx)
+		_, o = offboard(http.MethodGet)
+		return o.State == backend.OffboardingStateComplete
+	}, 5*time.Second, 200*time.Millisecond)
+	require.GreaterOrEqual(t, o.Report.DeletedObjects, 7)
+	require.False(t, o.CompletedAt.IsZero())
+	require.False(t, s.isOffboarding(tenant))
+
+	var remaining []string
+	require.NoError(t, rr.Find(ctx, backend.KeyPath{tenant}, func(m backend.FindMatch) {
+		remaining = append(remaining, m.Key)
+	}))
+	require.Len(t, remaining, 1, "only the offboarding record is kept")
+
+	blocks, _, err := backend.NewReader(rr).Blocks(ctx, otherTenant)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2, "other tenants are untouched")
+}
+
+func (s *BackendScheduler) offboardingOverrides() *offboardingOverrides {
+	return &offboardingOverrides{Interface: s.overrides, s: s}
+}
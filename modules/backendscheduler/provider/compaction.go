@@ -19,6 +19,7 @@ import (
 	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/blockselector"
+	v2 "github.com/grafana/tempo/tempodb/encoding/v2"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -280,6 +281,11 @@ func (p *CompactionProvider) getNextBlockIDs(_ context.Context) ([]string, bool)
 		ids = append(ids, b.BlockID.String())
 	}
 
+	// a single v2 block is a conversion job
+	if len(toBeCompacted) == 1 && toBeCompacted[0].Version == v2.VersionString && p.overrides.CompactionConvertV2Blocks(p.curTenant.Value()) {
+		return ids, true
+	}
+
 	return ids, len(ids) >= p.cfg.MinInputBlocks
 }
 
@@ -415,7 +421,14 @@ func (p *CompactionProvider) newBlockSelector(tenantID string) (blockselector.Co
 	}
 	p.outstandingJobsMtx.Unlock()
 
+	// archived blocks can't be read so they aren't compacted
+	archiveAfter, _ := p.overrides.BlockArchive(tenantID)
+	now := time.Now()
+
 	for _, block := range fullBlocklist {
+		if backend.IsBlockArchived(block, archiveAfter, now) {
+			continue
+		}
 		if _, ok := inProgressBlockIDs[block.BlockID]; !ok {
 			// Include blocks that are not already in the input list from another job or recent jobs
 			blocklist = append(blocklist, block)
@@ -426,14 +439,29 @@ func (p *CompactionProvider) newBlockSelector(tenantID string) (blockselector.Co
 		window = p.cfg.Compactor.MaxCompactionRange
 	}
 
-	return blockselector.NewTimeWindowBlockSelector(
-		blocklist,
-		window,
-		p.cfg.Compactor.MaxCompactionObjects,
-		p.cfg.Compactor.MaxBlockBytes,
-		p.cfg.MinInputBlocks,
-		p.cfg.MaxInputBlocks,
-	), len(blocklist)
+	planner, err := blockselector.Planner(p.cfg.Compactor.CompactionPlanner)
+	if err != nil {
+		level.Error(p.logger).Log("msg", "failed to get compaction planner, using the default", "err", err)
+		planner, _ = blockselector.Planner(blockselector.PlannerTimeWindow)
+	}
+
+	opts := blockselector.PlannerOptions{
+		MaxCompactionRange:   window,
+		MaxCompactionObjects: p.cfg.Compactor.MaxCompactionObjects,
+		MaxBlockBytes:        p.cfg.Compactor.MaxBlockBytes,
+		MinInputBlocks:       p.cfg.MinInputBlocks,
+		MaxInputBlocks:       p.cfg.MaxInputBlocks,
+		MaxCompactionLevel:   p.cfg.Compactor.MaxCompactionLevel,
+	}
+
+	if p.overrides.CompactionConvertV2Blocks(tenantID) {
+		// v2 blocks are sent to the workers one at a time, which convert them to their configured block version
+		return blockselector.NewConversionBlockSelector(blocklist, v2.VersionString, func(rest []*backend.BlockMeta) blockselector.CompactionBlockSelector {
+			return planner.NewSelector(rest, opts)
+		}), len(blocklist)
+	}
+
+	return planner.NewSelector(blocklist, opts), len(blocklist)
 }
 
 // addToRecentJobs adds a job to the recent jobs cache
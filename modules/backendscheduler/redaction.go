@@ -0,0 +1,392 @@
+package backendscheduler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+const muxVarRedaction = "id"
+
+var (
+	errInvalidRedaction    = errors.New("invalid redaction")
+	errRedactionConflict   = errors.New("redaction conflict")
+	errRedactionNotRunning = errors.New("redaction is not running")
+)
+
+// redactionRequest is the body of a request starting a redaction.
+type redactionRequest struct {
+	Key         string    `json:"key"`
+	Value       string    `json:"value"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Reason      string    `json:"reason"`
+	RequestedBy string    `json:"requested_by"`
+}
+
+// RedactionHandler serves the attribute redaction API. Redacted values are never returned.
+//
+//	GET    returns the redaction records of the tenant
+//	POST   starts replacing a value of an attribute key with a redaction marker in the blocks of a time range
+//	DELETE cancels a running redaction, the blocks already rewritten stay redacted
+func (s *BackendScheduler) RedactionHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tenantID := vars[muxVarTenant]
+	if tenantID == "" {
+		http.Error(w, "tenant is required", http.StatusBadRequest)
+		return
+	}
+
+	var (
+		resp any
+		err  error
+	)
+
+	switch r.Method {
+	case http.MethodGet:
+		var redactions []*backend.Redaction
+		redactions, err = s.readRedactions(r.Context(), tenantID)
+		list := make([]*backend.Redaction, 0, len(redactions))
+		for _, rd := range redactions {
+			list = append(list, withoutValue(rd))
+		}
+		resp = list
+	case http.MethodPost:
+		req := redactionRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid redaction request: %s", err), http.StatusBadRequest)
+			return
+		}
+		resp, err = s.startRedaction(r.Context(), tenantID, req)
+	case http.MethodDelete:
+		resp, err = s.cancelRedaction(r.Context(), tenantID, vars[muxVarRedaction])
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case errors.Is(err, errInvalidRedaction):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, backend.ErrDoesNotExist):
+		http.Error(w, fmt.Sprintf("redaction %s of tenant %s not found", vars[muxVarRedaction], tenantID), http.StatusNotFound)
+		return
+	case errors.Is(err, errRedactionConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *BackendScheduler) startRedaction(ctx context.Context, tenantID string, req redactionRequest) (*backend.Redaction, error) {
+	switch {
+	case req.Key == "" || req.Value == "":
+		return nil, fmt.Errorf("%w: key and value are required", errInvalidRedaction)
+	case !req.Start.Before(req.End):
+		return nil, fmt.Errorf("%w: start must be before end", errInvalidRedaction)
+	case req.End.After(time.Now()):
+		return nil, fmt.Errorf("%w: end must not be in the future", errInvalidRedaction)
+	}
+
+	s.redactionsMtx.Lock()
+	defer s.redactionsMtx.Unlock()
+
+	redactions, err := s.readRedactions(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256([]byte(req.Value))
+	rd := &backend.Redaction{
+		ID:          uuid.New().String(),
+		Key:         req.Key,
+		Value:       req.Value,
+		ValueSHA256: hex.EncodeToString(hash[:]),
+		Start:       req.Start,
+		End:         req.End,
+		Reason:      req.Reason,
+		RequestedBy: req.RequestedBy,
+		State:       backend.RedactionStateRunning,
+		RequestedAt: time.Now(),
+	}
+	for _, existing := range redactions {
+		if existing.State == backend.RedactionStateRunning && existing.Key == rd.Key && existing.ValueSHA256 == rd.ValueSHA256 {
+			return nil, fmt.Errorf("%w: redaction %s of the value is already running", errRedactionConflict, existing.ID)
+		}
+	}
+
+	err = backend.WriteTenantRedactions(ctx, s.writer, tenantID, append(redactions, rd))
+	if err != nil {
+		return nil, fmt.Errorf("failed to write redaction records: %w", err)
+	}
+
+	s.redacting[tenantID] = struct{}{}
+	level.Info(log.Logger).Log(
+		"msg", "attribute redaction started",
+		"tenant", tenantID,
+		"id", rd.ID,
+		"key", rd.Key,
+		"value_sha256", rd.ValueSHA256,
+		"start", rd.Start,
+		"end", rd.End,
+		"reason", rd.Reason,
+		"requested_by", rd.RequestedBy)
+
+	return withoutValue(rd), nil
+}
+
+func (s *BackendScheduler) cancelRedaction(ctx context.Context, tenantID, id string) (*backend.Redaction, error) {
+	rd, err := s.updateRedaction(ctx, tenantID, id, func(rd *backend.Redaction) {
+		rd.State = backend.RedactionStateCancelled
+		rd.Value = ""
+		rd.CompletedAt = time.Now()
+	})
+	if errors.Is(err, errRedactionNotRunning) {
+		return nil, fmt.Errorf("%w: redaction %s is %s and can no longer be cancelled", errRedactionConflict, id, rd.State)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	level.Info(log.Logger).Log("msg", "attribute redaction cancelled", "tenant", tenantID, "id", id)
+	return withoutValue(rd), nil
+}
+
+// updateRedaction applies update to a running redaction of the tenant and writes the records. The redaction is
+// returned with errRedactionNotRunning without being updated if it's no longer running.
+func (s *BackendScheduler) updateRedaction(ctx context.Context, tenantID, id string, update func(*backend.Redaction)) (*backend.Redaction, error) {
+	s.redactionsMtx.Lock()
+	defer s.redactionsMtx.Unlock()
+
+	redactions, err := s.readRedactions(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rd *backend.Redaction
+	for _, existing := range redactions {
+		if existing.ID == id {
+			rd = existing
+		}
+	}
+	if rd == nil {
+		return nil, backend.ErrDoesNotExist
+	}
+	if rd.State != backend.RedactionStateRunning {
+		return rd, errRedactionNotRunning
+	}
+
+	update(rd)
+	err = backend.WriteTenantRedactions(ctx, s.writer, tenantID, redactions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write redaction records: %w", err)
+	}
+
+	if !hasRunningRedaction(redactions) {
+		delete(s.redacting, tenantID)
+	}
+
+	return rd, nil
+}
+
+// readRedactions returns the redaction records of the tenant, none if it has never been redacted.
+func (s *BackendScheduler) readRedactions(ctx context.Context, tenantID string) ([]*backend.Redaction, error) {
+	redactions, err := backend.ReadTenantRedactions(ctx, s.reader, tenantID)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return nil, nil
+	}
+	return redactions, err
+}
+
+// loadRedactions restores the tenants with running redactions from the backend.
+func (s *BackendScheduler) loadRedactions(ctx context.Context) error {
+	tenants, err := backend.NewReader(s.reader).Tenants(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.redactionsMtx.Lock()
+	defer s.redactionsMtx.Unlock()
+
+	for _, tenantID := range tenants {
+		redactions, err := s.readRedactions(ctx, tenantID)
+		if err != nil {
+			return fmt.Errorf("failed to read redaction records for tenant %s: %w", tenantID, err)
+		}
+		if hasRunningRedaction(redactions) {
+			s.redacting[tenantID] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+func (s *BackendScheduler) isRedacting(tenantID string) bool {
+	s.redactionsMtx.Lock()
+	defer s.redactionsMtx.Unlock()
+
+	_, ok := s.redacting[tenantID]
+	return ok
+}
+
+// processRedactions makes one pass over the running redactions of all tenants. The lock is only held while
+// reading and writing the records so rewriting the blocks does not block the API.
+func (s *BackendScheduler) processRedactions(ctx context.Context) {
+	s.redactionsMtx.Lock()
+	tenants := make([]string, 0, len(s.redacting))
+	for tenantID := range s.redacting {
+		tenants = append(tenants, tenantID)
+	}
+	s.redactionsMtx.Unlock()
+
+	for _, tenantID := range tenants {
+		redactions, err := backend.ReadTenantRedactions(ctx, s.reader, tenantID)
+		if err != nil {
+			metricRedactionFailures.Inc()
+			level.Error(log.Logger).Log("msg", "failed to read redaction records", "tenant", tenantID, "err", err)
+			continue
+		}
+
+		for _, rd := range redactions {
+			if rd.State != backend.RedactionStateRunning {
+				continue
+			}
+			if err := s.redactBlocks(ctx, tenantID, rd); err != nil {
+				metricRedactionFailures.Inc()
+				level.Error(log.Logger).Log("msg", "failed to redact blocks", "tenant", tenantID, "id", rd.ID, "err", err)
+			}
+		}
+	}
+}
+
+// redactBlocks makes one pass over the blocks of the time range of a running redaction. Every block that wasn't
+// written or skipped by the redaction is rewritten, which also catches the blocks flushed or compacted from
+// unredacted blocks after the previous pass. The redaction is complete once a pass finds no block left. Blocks
+// that fail to be rewritten are retried in the next pass.
+func (s *BackendScheduler) redactBlocks(ctx context.Context, tenantID string, rd *backend.Redaction) error {
+	done := make(map[backend.UUID]struct{}, len(rd.Blocks))
+	for _, id := range rd.Blocks {
+		done[id] = struct{}{}
+	}
+
+	var pending []*backend.BlockMeta
+	for _, m := range s.store.BlockMetasInRange(tenantID, rd.Start, rd.End) {
+		if _, ok := done[m.BlockID]; !ok {
+			pending = append(pending, m)
+		}
+	}
+
+	if len(pending) == 0 {
+		rd, err := s.updateRedaction(ctx, tenantID, rd.ID, func(rd *backend.Redaction) {
+			rd.State = backend.RedactionStateComplete
+			rd.Value = ""
+			rd.CompletedAt = time.Now()
+			rd.Progress.PendingBlocks = 0
+		})
+		if errors.Is(err, errRedactionNotRunning) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		level.Info(log.Logger).Log(
+			"msg", "attribute redaction complete",
+			"tenant", tenantID,
+			"id", rd.ID,
+			"key", rd.Key,
+			"value_sha256", rd.ValueSHA256,
+			"requested_at", rd.RequestedAt,
+			"rewritten_blocks", rd.Progress.RewrittenBlocks,
+			"skipped_blocks", rd.Progress.SkippedBlocks,
+			"redacted_values", rd.Progress.RedactedValues)
+		return nil
+	}
+
+	_, err := s.updateRedaction(ctx, tenantID, rd.ID, func(rd *backend.Redaction) {
+		rd.Progress.PendingBlocks = len(pending)
+	})
+	if errors.Is(err, errRedactionNotRunning) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	redaction := &common.AttributeRedaction{Key: rd.Key, Value: rd.Value}
+	for i, m := range pending {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		newMeta, redacted, err := s.store.RedactBlock(ctx, m, redaction)
+		skipped := errors.Is(err, common.ErrUnsupported)
+		if err != nil && !skipped {
+			metricRedactionFailures.Inc()
+			level.Error(log.Logger).Log("msg", "failed to redact block", "tenant", tenantID, "id", rd.ID, "blockID", m.BlockID, "err", err)
+			continue
+		}
+
+		if skipped {
+			level.Warn(log.Logger).Log("msg", "skipping block of unsupported version", "tenant", tenantID, "id", rd.ID, "blockID", m.BlockID, "version", m.Version)
+		} else {
+			metricRedactionRewrittenBlocks.Inc()
+			metricRedactedValues.Add(float64(redacted))
+		}
+
+		_, err = s.updateRedaction(ctx, tenantID, rd.ID, func(rd *backend.Redaction) {
+			rd.Progress.PendingBlocks = len(pending) - i - 1
+			if skipped {
+				rd.Progress.SkippedBlocks++
+				rd.Blocks = append(rd.Blocks, m.BlockID)
+				return
+			}
+			rd.Progress.RewrittenBlocks++
+			rd.Progress.RedactedValues += redacted
+			rd.Blocks = append(rd.Blocks, newMeta.BlockID)
+		})
+		if errors.Is(err, errRedactionNotRunning) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func hasRunningRedaction(redactions []*backend.Redaction) bool {
+	for _, rd := range redactions {
+		if rd.State == backend.RedactionStateRunning {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutValue returns a copy of the redaction without the redacted value.
+func withoutValue(rd *backend.Redaction) *backend.Redaction {
+	out := *rd
+	out.Value = ""
+	return &out
+}
@@ -0,0 +1,163 @@
+package backendscheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestAttributeRedaction(t *testing.T) {
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+	cfg.LocalWorkPath = t.TempDir()
+
+	var (
+		ctx, cancel   = context.WithCancel(context.Background())
+		store, rr, ww = newStore(ctx, t, t.TempDir())
+	)
+	defer func() {
+		cancel()
+		store.Shutdown()
+	}()
+
+	limits, err := overrides.NewOverrides(overrides.Config{Defaults: overrides.Overrides{}}, nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	// a block with traces holding the span attribute key=value
+	dec := model.MustNewSegmentDecoder(model.CurrentEncoding)
+	block, err := store.WAL().NewBlock(&backend.BlockMeta{BlockID: backend.NewUUID(), TenantID: tenant}, model.CurrentEncoding)
+	require.NoError(t, err)
+	now := uint32(time.Now().Add(-time.Minute).Unix())
+	for range 10 {
+		id := test.ValidTraceID(nil)
+		b, err := dec.PrepareForWrite(test.MakeTrace(10, id), now, now)
+		require.NoError(t, err)
+		obj, err := dec.ToObject([][]byte{b})
+		require.NoError(t, err)
+		require.NoError(t, block.Append(id, obj, now, now, true))
+	}
+	require.NoError(t, block.Flush())
+	complete, err := store.CompleteBlock(ctx, block)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(store.BlockMetas(tenant)) == 1
+	}, 5*time.Second, 100*time.Millisecond, "wait for the blocklist to be polled")
+
+	s, err := New(cfg, store, limits, rr, ww)
+	require.NoError(t, err)
+
+	redact := func(method, id string, body any) (int, []byte) {
+		var reqBody io.Reader
+		if body != nil {
+			b, err := json.Marshal(body)
+			require.NoError(t, err)
+			reqBody = bytes.NewReader(b)
+		}
+		req := httptest.NewRequest(method, "/backendscheduler/redactions/"+tenant, reqBody)
+		req = mux.SetURLVars(req, map[string]string{muxVarTenant: tenant, muxVarRedaction: id})
+		w := httptest.NewRecorder()
+		s.RedactionHandler(w, req)
+		return w.Code, w.Body.Bytes()
+	}
+	list := func() []*backend.Redaction {
+		code, body := redact(http.MethodGet, "", nil)
+		require.Equal(t, http.StatusOK, code)
+		var redactions []*backend.Redaction
+		require.NoError(t, json.Unmarshal(body, &redactions))
+		return redactions
+	}
+
+	require.Empty(t, list())
+
+	req := redactionRequest{
+		Key:         "key",
+		Value:       "value",
+		Start:       time.Now().Add(-time.Hour),
+		End:         time.Now(),
+		Reason:      "credential logged by accident",
+		RequestedBy: "admin",
+	}
+
+	invalid := req
+	invalid.Value = ""
+	code, _ := redact(http.MethodPost, "", invalid)
+	require.Equal(t, http.StatusBadRequest, code)
+	invalid = req
+	invalid.End = time.Now().Add(time.Hour)
+	code, _ = redact(http.MethodPost, "", invalid)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, body := redact(http.MethodPost, "", req)
+	require.Equal(t, http.StatusOK, code)
+	rd := &backend.Redaction{}
+	require.NoError(t, json.Unmarshal(body, rd))
+	require.Equal(t, backend.RedactionStateRunning, rd.State)
+	require.Empty(t, rd.Value, "the value is never returned")
+	require.NotEmpty(t, rd.ValueSHA256)
+	require.True(t, s.offboardingOverrides().CompactionDisabled(tenant))
+
+	code, _ = redact(http.MethodPost, "", req)
+	require.Equal(t, http.StatusConflict, code)
+
+	// the first pass rewrites the block, the second one finds nothing left and completes
+	s.processRedactions(ctx)
+	redactions := list()
+	require.Len(t, redactions, 1)
+	require.Equal(t, backend.RedactionStateRunning, redactions[0].State)
+	require.Equal(t, 1, redactions[0].Progress.RewrittenBlocks)
+	require.Greater(t, redactions[0].Progress.RedactedValues, 0)
+
+	metas := store.BlockMetas(tenant)
+	require.Len(t, metas, 1)
+	require.NotEqual(t, complete.BlockMeta().BlockID, metas[0].BlockID)
+	require.Equal(t, []backend.UUID{metas[0].BlockID}, redactions[0].Blocks)
+
+	s.processRedactions(ctx)
+	redactions = list()
+	require.Equal(t, backend.RedactionStateComplete, redactions[0].State)
+	require.False(t, redactions[0].CompletedAt.IsZero())
+	require.False(t, s.offboardingOverrides().CompactionDisabled(tenant))
+
+	stored, err := backend.ReadTenantRedactions(ctx, rr, tenant)
+	require.NoError(t, err)
+	require.Empty(t, stored[0].Value, "the value isn't kept once complete")
+
+	code, _ = redact(http.MethodDelete, rd.ID, nil)
+	require.Equal(t, http.StatusConflict, code)
+	code, _ = redact(http.MethodDelete, "unknown", nil)
+	require.Equal(t, http.StatusNotFound, code)
+
+	// a restarted scheduler picks up the running redaction, which can be cancelled
+	req.Value = "other"
+	code, body = redact(http.MethodPost, "", req)
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, json.Unmarshal(body, rd))
+
+	s2, err := New(cfg, store, limits, rr, ww)
+	require.NoError(t, err)
+	require.NoError(t, s2.loadRedactions(ctx))
+	require.True(t, s2.isRedacting(tenant))
+
+	code, body = redact(http.MethodDelete, rd.ID, nil)
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, json.Unmarshal(body, rd))
+	require.Equal(t, backend.RedactionStateCancelled, rd.State)
+	require.False(t, s.isRedacting(tenant))
+	require.Len(t, list(), 2)
+}
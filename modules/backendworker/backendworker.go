@@ -23,6 +23,7 @@ import (
 	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc/codes"
 )
@@ -59,6 +60,9 @@ type BackendWorker struct {
 
 	subservices        *services.Manager
 	subservicesWatcher *services.FailureWatcher
+
+	// webhook posts compaction and retention events, nil if disabled
+	webhook *tempodb.CompactionWebhook
 }
 
 // var tracer = otel.Tracer("modules/backendworker")
@@ -178,7 +182,13 @@ func (w *BackendWorker) starting(ctx context.Context) (err error) {
 		}
 	}
 
+	if w.cfg.Compactor.Webhook.Enabled() {
+		w.webhook = tempodb.NewCompactionWebhook(w.cfg.Compactor.Webhook, log.Logger)
+		w.store.AddCompactionListener(w.webhook)
+	}
+
 	if w.cfg.Poll {
+		w.store.AddTenantLifecycleListener(w)
 		w.store.EnablePolling(ctx, w, false)
 	}
 
@@ -339,6 +349,10 @@ func (w *BackendWorker) processRetentionJob(ctx context.Context, resp *tempopb.N
 }
 
 func (w *BackendWorker) stopping(_ error) error {
+	if w.webhook != nil {
+		w.webhook.Stop()
+	}
+
 	if w.subservices != nil {
 		return services.StopManagerAndAwaitStopped(context.Background(), w.subservices)
 	}
@@ -435,26 +449,40 @@ func (w *BackendWorker) Owns(hash string) bool {
 
 	level.Debug(log.Logger).Log("msg", "checking hash", "hash", hash)
 
+	owner, err := w.Owner(hash)
+	if err != nil {
+		level.Error(log.Logger).Log("msg", "failed to get owner", "err", err)
+		return false
+	}
+
+	ringAddr := w.ringLifecycler.GetInstanceAddr()
+
+	level.Debug(log.Logger).Log("msg", "checking addresses", "owning_addr", owner, "this_addr", ringAddr)
+
+	return owner == ringAddr
+}
+
+// Owner implements blocklist.JobOwner. It returns the address of the instance that owns the hash, or an empty string
+// if the ring isn't sharded.
+func (w *BackendWorker) Owner(hash string) (string, error) {
+	if !w.isSharded() {
+		return "", nil
+	}
+
 	hasher := fnv.New32a()
 	_, _ = hasher.Write([]byte(hash))
 	hash32 := hasher.Sum32()
 
 	rs, err := w.Ring.Get(hash32, ringOp, []ring.InstanceDesc{}, nil, nil)
 	if err != nil {
-		level.Error(log.Logger).Log("msg", "failed to get ring", "err", err)
-		return false
+		return "", fmt.Errorf("failed to get ring: %w", err)
 	}
 
 	if len(rs.Instances) != 1 {
-		level.Error(log.Logger).Log("msg", "unexpected number of compactors in the shard (expected 1, got %d)", len(rs.Instances))
-		return false
+		return "", fmt.Errorf("unexpected number of compactors in the shard (expected 1, got %d)", len(rs.Instances))
 	}
 
-	ringAddr := w.ringLifecycler.GetInstanceAddr()
-
-	level.Debug(log.Logger).Log("msg", "checking addresses", "owning_addr", rs.Instances[0].Addr, "this_addr", ringAddr)
-
-	return rs.Instances[0].Addr == ringAddr
+	return rs.Instances[0].Addr, nil
 }
 
 func (w *BackendWorker) RecordDiscardedSpans(count int, tenantID string, traceID string, rootSpanName string, rootServiceName string) {
@@ -463,6 +491,14 @@ func (w *BackendWorker) RecordDiscardedSpans(count int, tenantID string, traceID
 	overrides.RecordDiscardedSpans(count, reasonCompactorDiscardedSpans, tenantID)
 }
 
+// BeforeTenantDeletion implements blocklist.TenantLifecycleListener
+func (*BackendWorker) BeforeTenantDeletion(context.Context, string) {}
+
+// AfterTenantDeletion implements blocklist.TenantLifecycleListener
+func (*BackendWorker) AfterTenantDeletion(_ context.Context, tenantID string, _ int, _ int64) {
+	overrides.DeleteDiscardedSpans(tenantID)
+}
+
 // BlockRetentionForTenant implements CompactorOverrides
 func (w *BackendWorker) BlockRetentionForTenant(tenantID string) time.Duration {
 	return w.overrides.BlockRetention(tenantID)
@@ -481,6 +517,34 @@ func (w *BackendWorker) MaxCompactionRangeForTenant(tenantID string) time.Durati
 	return w.overrides.MaxCompactionRange(tenantID)
 }
 
+func (w *BackendWorker) StorageAttributePolicyForTenant(tenantID string) common.AttributePolicy {
+	return w.overrides.StorageAttributePolicy(tenantID)
+}
+
+func (w *BackendWorker) ConvertV2BlocksForTenant(tenantID string) bool {
+	return w.overrides.CompactionConvertV2Blocks(tenantID)
+}
+
+func (w *BackendWorker) DedicatedColumnsForTenant(tenantID string) backend.DedicatedColumns {
+	return w.overrides.DedicatedColumns(tenantID)
+}
+
+func (w *BackendWorker) BlockRetentionClassesForTenant(tenantID string) (string, map[string]time.Duration) {
+	return w.overrides.BlockRetentionClasses(tenantID)
+}
+
+func (w *BackendWorker) BlockEncodingForTenant(tenantID string) common.BlockEncoding {
+	return w.overrides.StorageBlockEncoding(tenantID)
+}
+
+func (w *BackendWorker) BlockArchiveForTenant(tenantID string) (time.Duration, string) {
+	return w.overrides.BlockArchive(tenantID)
+}
+
+func (w *BackendWorker) DedicatedColumnsAutoApplyForTenant(tenantID string) bool {
+	return w.overrides.CompactionDedicatedColumnsAutoApply(tenantID)
+}
+
 func (w *BackendWorker) callSchedulerWithBackoff(ctx context.Context, f func(context.Context) error) error {
 	var (
 		b   = backoff.New(ctx, w.cfg.Backoff)
@@ -870,11 +870,22 @@ func (m *mockPartitionRingReader) PartitionRing() *ring.PartitionRing {
 var _ Overrides = (*mockOverrides)(nil)
 
 type mockOverrides struct {
-	dc backend.DedicatedColumns
+	dc                 backend.DedicatedColumns
+	retentionAttribute string
+	retentionClasses   map[string]time.Duration
+	blockEncoding      common.BlockEncoding
 }
 
 func (m *mockOverrides) MaxBytesPerTrace(_ string) int                      { return 0 }
 func (m *mockOverrides) DedicatedColumns(_ string) backend.DedicatedColumns { return m.dc }
+func (m *mockOverrides) BlockRetention(_ string) time.Duration              { return 0 }
+func (m *mockOverrides) BlockRetentionClasses(_ string) (string, map[string]time.Duration) {
+	return m.retentionAttribute, m.retentionClasses
+}
+
+func (m *mockOverrides) StorageBlockEncoding(_ string) common.BlockEncoding {
+	return m.blockEncoding
+}
 
 func newKafkaClient(t testing.TB, config ingest.KafkaConfig) *kgo.Client {
 	writeClient, err := kgo.NewClient(
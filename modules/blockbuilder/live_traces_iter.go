@@ -8,6 +8,7 @@ import (
 
 	"github.com/grafana/tempo/pkg/livetraces"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
@@ -34,15 +35,17 @@ type liveTracesIter struct {
 	chBuf      []chEntry
 	cancel     func()
 	start, end uint64
+	retention  *tempodb.RetentionClassifier
 }
 
-func newLiveTracesIter(liveTraces *livetraces.LiveTraces[[]byte]) *liveTracesIter {
+func newLiveTracesIter(liveTraces *livetraces.LiveTraces[[]byte], retention *tempodb.RetentionClassifier) *liveTracesIter {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	l := &liveTracesIter{
 		liveTraces: liveTraces,
 		ch:         make(chan []chEntry, 1),
 		cancel:     cancel,
+		retention:  retention,
 	}
 
 	go l.iter(ctx)
@@ -123,6 +126,10 @@ func (i *liveTracesIter) iter(ctx context.Context) {
 				}
 			}
 
+			if i.retention != nil {
+				i.retention.Observe(tr)
+			}
+
 			tempopb.ReuseByteSlices(entry.Batches)
 			delete(i.liveTraces.Traces, e.hash)
 
@@ -151,6 +158,15 @@ func (i *liveTracesIter) MinMaxTimestamps() (uint64, uint64) {
 	return i.start, i.end
 }
 
+// RetentionClass returns the retention class of the traces. The iterator must be exhausted before this can be
+// accessed.
+func (i *liveTracesIter) RetentionClass() string {
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+
+	return i.retention.Class()
+}
+
 func (i *liveTracesIter) Close() {
 	i.cancel()
 }
@@ -2,6 +2,7 @@ package blockbuilder
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/go-kit/log"
@@ -35,17 +36,18 @@ const (
 )
 
 type tenantStore struct {
-	tenantID         string
-	idGenerator      util.IDGenerator
-	cfg              BlockConfig
-	startTime        time.Time
-	cycleDuration    time.Duration
-	slackDuration    time.Duration
-	logger           log.Logger
-	overrides        Overrides
-	enc              encoding.VersionedEncoding
-	wal              *wal.WAL
-	noCompactBlockID *backend.UUID
+	tenantID      string
+	idGenerator   util.IDGenerator
+	cfg           BlockConfig
+	startTime     time.Time
+	cycleDuration time.Duration
+	slackDuration time.Duration
+	logger        log.Logger
+	overrides     Overrides
+	enc           encoding.VersionedEncoding
+	wal           *wal.WAL
+	// noCompactBlockIDs are the blocks written by the last flush, one per retention class
+	noCompactBlockIDs []backend.UUID
 
 	liveTraces *livetraces.LiveTraces[[]byte]
 }
@@ -104,6 +106,37 @@ func (s *tenantStore) Flush(ctx context.Context, r tempodb.Reader, w tempodb.Wri
 		return nil
 	}
 
+	// traces of different retention classes are written to separate blocks so each block is removed after the
+	// retention of its own traces
+	classes := s.retentionClasses()
+	if !classes.Enabled() {
+		return s.flushBlock(ctx, r, w, c, s.liveTraces, classes)
+	}
+
+	byClass, err := splitLiveTracesByClass(s.liveTraces, classes)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(byClass))
+	for class := range byClass {
+		names = append(names, class)
+	}
+	// block IDs are generated in order, so the classes are flushed in the same order on every attempt
+	sort.Strings(names)
+
+	for _, class := range names {
+		if err := s.flushBlock(ctx, r, w, c, byClass[class], classes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *tenantStore) flushBlock(ctx context.Context, r tempodb.Reader, w tempodb.Writer, c tempodb.Compactor, liveTraces *livetraces.LiveTraces[[]byte], classes *tempodb.RetentionClasses) error {
+	span := trace.SpanFromContext(ctx)
+
 	blockID, existingBlocksToBeCompacted, err := s.determineBlockIDs(ctx, r)
 	if err != nil {
 		return err
@@ -125,18 +158,28 @@ func (s *tenantStore) Flush(ctx context.Context, r tempodb.Reader, w tempodb.Wri
 		}
 	}
 
+	// the tenant can be pinned to another block version
+	blockCfg := s.overrides.StorageBlockEncoding(s.tenantID).ApplyTo(s.cfg.BlockCfg)
+	enc := s.enc
+	if blockCfg.Version != "" && blockCfg.Version != enc.Version() {
+		enc, err = encoding.FromVersion(blockCfg.Version)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Initial meta for creating the block
-	meta := backend.NewBlockMeta(s.tenantID, (uuid.UUID)(blockID), s.enc.Version(), backend.EncNone, "")
+	meta := backend.NewBlockMeta(s.tenantID, (uuid.UUID)(blockID), enc.Version(), backend.EncNone, "")
 	meta.DedicatedColumns = s.overrides.DedicatedColumns(s.tenantID)
 	meta.ReplicationFactor = 1
-	meta.TotalObjects = int64(s.liveTraces.Len())
+	meta.TotalObjects = int64(liveTraces.Len())
 
 	var (
 		st     = time.Now()
 		l      = s.wal.LocalBackend()
 		reader = backend.NewReader(l)
 		writer = backend.NewWriter(l)
-		iter   = newLiveTracesIter(s.liveTraces)
+		iter   = newLiveTracesIter(liveTraces, tempodb.NewRetentionClassifier(classes))
 	)
 
 	level.Info(s.logger).Log(
@@ -146,7 +189,7 @@ func (s *tenantStore) Flush(ctx context.Context, r tempodb.Reader, w tempodb.Wri
 		"meta", meta,
 	)
 
-	newMeta, err := s.enc.CreateBlock(ctx, &s.cfg.BlockCfg, meta, iter, reader, writer)
+	newMeta, err := enc.CreateBlock(ctx, &blockCfg, meta, iter, reader, writer)
 	if err != nil {
 		return err
 	}
@@ -155,8 +198,9 @@ func (s *tenantStore) Flush(ctx context.Context, r tempodb.Reader, w tempodb.Wri
 	// all of the traces.
 	start, end := iter.MinMaxTimestamps()
 	newMeta.StartTime, newMeta.EndTime = s.adjustTimeRangeForSlack(time.Unix(0, int64(start)), time.Unix(0, int64(end)))
+	newMeta.RetentionClass = iter.RetentionClass()
 
-	newBlock, err := s.enc.OpenBlock(newMeta, reader)
+	newBlock, err := enc.OpenBlock(newMeta, reader)
 	if err != nil {
 		return err
 	}
@@ -165,7 +209,7 @@ func (s *tenantStore) Flush(ctx context.Context, r tempodb.Reader, w tempodb.Wri
 		span.RecordError(err)
 		return err
 	}
-	s.noCompactBlockID = &newMeta.BlockID
+	s.noCompactBlockIDs = append(s.noCompactBlockIDs, newMeta.BlockID)
 	span.AddEvent("wrote block to backend", trace.WithAttributes(attribute.String("block_id", newMeta.BlockID.String())))
 
 	metricBlockBuilderFlushedBlocks.WithLabelValues(s.tenantID).Inc()
@@ -186,14 +230,49 @@ func (s *tenantStore) Flush(ctx context.Context, r tempodb.Reader, w tempodb.Wri
 	return nil
 }
 
-func (s *tenantStore) AllowCompaction(ctx context.Context, w tempodb.Writer) error {
-	if s.noCompactBlockID == nil {
-		return nil // no block to allow compaction for
+func (s *tenantStore) retentionClasses() *tempodb.RetentionClasses {
+	attribute, classes := s.overrides.BlockRetentionClasses(s.tenantID)
+	return &tempodb.RetentionClasses{
+		Attribute: attribute,
+		Retention: classes,
+		Default:   s.overrides.BlockRetention(s.tenantID),
 	}
-	if err := w.DeleteNoCompactFlag(ctx, s.tenantID, *s.noCompactBlockID); err != nil {
-		return err
+}
+
+// splitLiveTracesByClass moves the traces into one set per retention class.
+func splitLiveTracesByClass(liveTraces *livetraces.LiveTraces[[]byte], classes *tempodb.RetentionClasses) (map[string]*livetraces.LiveTraces[[]byte], error) {
+	byClass := map[string]*livetraces.LiveTraces[[]byte]{}
+
+	tr := new(tempopb.Trace)
+	for hash, entry := range liveTraces.Traces {
+		tr.Reset()
+		for _, b := range entry.Batches {
+			// This unmarshal appends the batches onto the existing tempopb.Trace
+			if err := tr.Unmarshal(b); err != nil {
+				return nil, err
+			}
+		}
+
+		class := classes.TraceClass(tr)
+		set, ok := byClass[class]
+		if !ok {
+			set = livetraces.New(func(b []byte) uint64 { return uint64(len(b)) }, 0, 0)
+			byClass[class] = set
+		}
+		set.Traces[hash] = entry
+		delete(liveTraces.Traces, hash)
+	}
+
+	return byClass, nil
+}
+
+func (s *tenantStore) AllowCompaction(ctx context.Context, w tempodb.Writer) error {
+	for len(s.noCompactBlockIDs) > 0 {
+		if err := w.DeleteNoCompactFlag(ctx, s.tenantID, s.noCompactBlockIDs[0]); err != nil {
+			return err
+		}
+		s.noCompactBlockIDs = s.noCompactBlockIDs[1:]
 	}
-	s.noCompactBlockID = nil
 
 	return nil
 }
@@ -235,3 +235,54 @@ func TestTenantStoreNoCompactFlag(t *testing.T) {
 	require.EqualValues(t, 1, actualMeta.TotalRecords)
 	require.Greater(t, actualMeta.Size_, uint64(0))
 }
+
+func TestTenantStoreRetentionClasses(t *testing.T) {
+	var (
+		ctx       = t.Context()
+		startTime = time.Now().Add(-24 * time.Hour)
+		store     = newStoreWithLogger(ctx, t, log.NewNopLogger(), true)
+	)
+
+	ts, err := getTenantStore(t, startTime, 5*time.Minute, 5*time.Minute)
+	require.NoError(t, err)
+	ts.overrides = &mockOverrides{
+		retentionAttribute: "env",
+		retentionClasses: map[string]time.Duration{
+			"dev":  48 * time.Hour,
+			"prod": 720 * time.Hour,
+		},
+	}
+
+	// 2 dev, 3 prod and 1 trace without a class
+	envs := []string{"dev", "prod", "dev", "prod", "prod", ""}
+	expected := map[string]int64{}
+	for _, env := range envs {
+		id := test.ValidTraceID(nil)
+		tr := test.MakeTrace(2, id)
+		if env != "" {
+			for _, rs := range tr.ResourceSpans {
+				rs.Resource.Attributes = append(rs.Resource.Attributes, test.MakeAttribute("env", env))
+			}
+		}
+		b, err := tr.Marshal()
+		require.NoError(t, err)
+
+		require.NoError(t, ts.AppendTrace(id, b, startTime))
+		expected[env]++
+	}
+
+	err = ts.Flush(ctx, store, store, store)
+	require.NoError(t, err)
+	err = ts.AllowCompaction(ctx, store)
+	require.NoError(t, err)
+
+	store.PollNow(ctx)
+	metas := store.BlockMetas(ts.tenantID)
+	require.Len(t, metas, len(expected))
+
+	actual := map[string]int64{}
+	for _, m := range metas {
+		actual[m.RetentionClass] = m.TotalObjects
+	}
+	require.Equal(t, expected, actual)
+}
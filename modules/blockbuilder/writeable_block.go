@@ -17,6 +17,9 @@ import (
 type Overrides interface {
 	MaxBytesPerTrace(string) int
 	DedicatedColumns(string) backend.DedicatedColumns
+	BlockRetention(string) time.Duration
+	BlockRetentionClasses(string) (string, map[string]time.Duration)
+	StorageBlockEncoding(string) common.BlockEncoding
 }
 
 const nameFlushed = "flushed"
@@ -17,6 +17,9 @@ import (
 	"github.com/grafana/tempo/pkg/model"
 	tempoUtil "github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 const (
@@ -48,6 +51,9 @@ type Compactor struct {
 
 	subservices        *services.Manager
 	subservicesWatcher *services.FailureWatcher
+
+	// webhook posts compaction and retention events, nil if disabled
+	webhook *tempodb.CompactionWebhook
 }
 
 // New makes a new Compactor.
@@ -148,7 +154,13 @@ func (c *Compactor) starting(ctx context.Context) (err error) {
 		}
 	}
 
+	if c.cfg.Compactor.Webhook.Enabled() {
+		c.webhook = tempodb.NewCompactionWebhook(c.cfg.Compactor.Webhook, log.Logger)
+		c.store.AddCompactionListener(c.webhook)
+	}
+
 	// this will block until one poll cycle is complete
+	c.store.AddTenantLifecycleListener(c)
 	c.store.EnablePolling(ctx, c, true)
 
 	return nil
@@ -179,6 +191,10 @@ func (c *Compactor) running(ctx context.Context) error {
 
 // Called after compactor is asked to stop via StopAsync.
 func (c *Compactor) stopping(_ error) error {
+	if c.webhook != nil {
+		c.webhook.Stop()
+	}
+
 	if c.subservices != nil {
 		return services.StopManagerAndAwaitStopped(context.Background(), c.subservices)
 	}
@@ -194,26 +210,40 @@ func (c *Compactor) Owns(hash string) bool {
 
 	level.Debug(log.Logger).Log("msg", "checking hash", "hash", hash)
 
+	owner, err := c.Owner(hash)
+	if err != nil {
+		level.Error(log.Logger).Log("msg", "failed to get owner", "err", err)
+		return false
+	}
+
+	ringAddr := c.ringLifecycler.GetInstanceAddr()
+
+	level.Debug(log.Logger).Log("msg", "checking addresses", "owning_addr", owner, "this_addr", ringAddr)
+
+	return owner == ringAddr
+}
+
+// Owner implements blocklist.JobOwner. It returns the address of the instance that owns the hash, or an empty string
+// if the ring isn't sharded.
+func (c *Compactor) Owner(hash string) (string, error) {
+	if !c.isSharded() {
+		return "", nil
+	}
+
 	hasher := fnv.New32a()
 	_, _ = hasher.Write([]byte(hash))
 	hash32 := hasher.Sum32()
 
 	rs, err := c.Ring.Get(hash32, ringOp, []ring.InstanceDesc{}, nil, nil)
 	if err != nil {
-		level.Error(log.Logger).Log("msg", "failed to get ring", "err", err)
-		return false
+		return "", fmt.Errorf("failed to get ring: %w", err)
 	}
 
 	if len(rs.Instances) != 1 {
-		level.Error(log.Logger).Log("msg", "unexpected number of compactors in the shard (expected 1, got %d)", len(rs.Instances))
-		return false
+		return "", fmt.Errorf("unexpected number of compactors in the shard (expected 1, got %d)", len(rs.Instances))
 	}
 
-	ringAddr := c.ringLifecycler.GetInstanceAddr()
-
-	level.Debug(log.Logger).Log("msg", "checking addresses", "owning_addr", rs.Instances[0].Addr, "this_addr", ringAddr)
-
-	return rs.Instances[0].Addr == ringAddr
+	return rs.Instances[0].Addr, nil
 }
 
 // Combine implements tempodb.CompactorSharder
@@ -249,6 +279,14 @@ func (c *Compactor) RecordDiscardedSpans(count int, tenantID string, traceID str
 	overrides.RecordDiscardedSpans(count, reasonCompactorDiscardedSpans, tenantID)
 }
 
+// BeforeTenantDeletion implements blocklist.TenantLifecycleListener
+func (*Compactor) BeforeTenantDeletion(context.Context, string) {}
+
+// AfterTenantDeletion implements blocklist.TenantLifecycleListener
+func (*Compactor) AfterTenantDeletion(_ context.Context, tenantID string, _ int, _ int64) {
+	overrides.DeleteDiscardedSpans(tenantID)
+}
+
 // BlockRetentionForTenant implements CompactorOverrides
 func (c *Compactor) BlockRetentionForTenant(tenantID string) time.Duration {
 	return c.overrides.BlockRetention(tenantID)
@@ -267,6 +305,34 @@ func (c *Compactor) MaxCompactionRangeForTenant(tenantID string) time.Duration {
 	return c.overrides.MaxCompactionRange(tenantID)
 }
 
+func (c *Compactor) StorageAttributePolicyForTenant(tenantID string) common.AttributePolicy {
+	return c.overrides.StorageAttributePolicy(tenantID)
+}
+
+func (c *Compactor) ConvertV2BlocksForTenant(tenantID string) bool {
+	return c.overrides.CompactionConvertV2Blocks(tenantID)
+}
+
+func (c *Compactor) DedicatedColumnsForTenant(tenantID string) backend.DedicatedColumns {
+	return c.overrides.DedicatedColumns(tenantID)
+}
+
+func (c *Compactor) BlockRetentionClassesForTenant(tenantID string) (string, map[string]time.Duration) {
+	return c.overrides.BlockRetentionClasses(tenantID)
+}
+
+func (c *Compactor) BlockEncodingForTenant(tenantID string) common.BlockEncoding {
+	return c.overrides.StorageBlockEncoding(tenantID)
+}
+
+func (c *Compactor) BlockArchiveForTenant(tenantID string) (time.Duration, string) {
+	return c.overrides.BlockArchive(tenantID)
+}
+
+func (c *Compactor) DedicatedColumnsAutoApplyForTenant(tenantID string) bool {
+	return c.overrides.CompactionDedicatedColumnsAutoApply(tenantID)
+}
+
 func (c *Compactor) isSharded() bool {
 	return c.cfg.ShardingRing.KVStore.Store != ""
 }
@@ -0,0 +1,89 @@
+package distributor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+const (
+	// AttributeDenylistModeDrop removes denied attributes. It's the default.
+	AttributeDenylistModeDrop = "drop"
+	// AttributeDenylistModeHash replaces the value of denied attributes with the hex encoded SHA-256 of the value.
+	AttributeDenylistModeHash = "hash"
+)
+
+// denyAttributes removes the attributes of the batches whose key is in the denylist, or hashes their value if mode is
+// AttributeDenylistModeHash. It returns the number of attributes removed or hashed.
+func denyAttributes(batches []*v1.ResourceSpans, denylist []string, mode string) int {
+	if len(denylist) == 0 {
+		return 0
+	}
+
+	hash := mode == AttributeDenylistModeHash
+	count := 0
+	for _, b := range batches {
+		if b.Resource != nil {
+			b.Resource.Attributes, count = denyKeyValues(b.Resource.Attributes, denylist, hash, count)
+		}
+
+		for _, ils := range b.ScopeSpans {
+			if ils.Scope != nil {
+				ils.Scope.Attributes, count = denyKeyValues(ils.Scope.Attributes, denylist, hash, count)
+			}
+
+			for _, span := range ils.Spans {
+				span.Attributes, count = denyKeyValues(span.Attributes, denylist, hash, count)
+
+				for _, event := range span.Events {
+					event.Attributes, count = denyKeyValues(event.Attributes, denylist, hash, count)
+				}
+
+				for _, link := range span.Links {
+					link.Attributes, count = denyKeyValues(link.Attributes, denylist, hash, count)
+				}
+			}
+		}
+	}
+
+	return count
+}
+
+func denyKeyValues(attributes []*v1_common.KeyValue, denylist []string, hash bool, count int) ([]*v1_common.KeyValue, int) {
+	if hash {
+		for _, attr := range attributes {
+			if slices.Contains(denylist, attr.Key) {
+				attr.Value = hashValue(attr.Value)
+				count++
+			}
+		}
+		return attributes, count
+	}
+
+	kept := attributes[:0]
+	for _, attr := range attributes {
+		if slices.Contains(denylist, attr.Key) {
+			count++
+			continue
+		}
+		kept = append(kept, attr)
+	}
+	return kept, count
+}
+
+// hashValue returns the SHA-256 of a value as a string value. Strings are hashed as is so that a value can be found
+// by hashing it, other types are hashed in their protobuf encoding.
+func hashValue(value *v1_common.AnyValue) *v1_common.AnyValue {
+	var b []byte
+	if s, ok := value.GetValue().(*v1_common.AnyValue_StringValue); ok {
+		b = []byte(s.StringValue)
+	} else if value != nil {
+		b, _ = value.Marshal()
+	}
+
+	sum := sha256.Sum256(b)
+	return &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: hex.EncodeToString(sum[:])}}
+}
@@ -0,0 +1,87 @@
+package distributor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+const authorizationKey = "http.request.header.authorization"
+
+func makeDenylistBatch() *v1.ResourceSpans {
+	return &v1.ResourceSpans{
+		Resource: &v1_resource.Resource{Attributes: []*v1_common.KeyValue{
+			test.MakeAttribute("service.name", "svc"),
+			test.MakeAttribute(authorizationKey, "resource-secret"),
+		}},
+		ScopeSpans: []*v1.ScopeSpans{{
+			Scope: &v1_common.InstrumentationScope{Attributes: []*v1_common.KeyValue{
+				test.MakeAttribute(authorizationKey, "scope-secret"),
+			}},
+			Spans: []*v1.Span{{
+				Attributes: []*v1_common.KeyValue{
+					test.MakeAttribute(authorizationKey, "span-secret"),
+					test.MakeAttribute("http.method", "GET"),
+					{Key: "user.id", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_IntValue{IntValue: 42}}},
+				},
+				Events: []*v1.Span_Event{{Attributes: []*v1_common.KeyValue{test.MakeAttribute(authorizationKey, "event-secret")}}},
+				Links:  []*v1.Span_Link{{Attributes: []*v1_common.KeyValue{test.MakeAttribute(authorizationKey, "link-secret")}}},
+			}},
+		}},
+	}
+}
+
+func TestDenyAttributesDrop(t *testing.T) {
+	b := makeDenylistBatch()
+
+	// drop is the default mode
+	count := denyAttributes([]*v1.ResourceSpans{b}, []string{authorizationKey, "user.id"}, "")
+	require.Equal(t, 6, count)
+
+	span := b.ScopeSpans[0].Spans[0]
+	require.Equal(t, []*v1_common.KeyValue{test.MakeAttribute("service.name", "svc")}, b.Resource.Attributes)
+	require.Empty(t, b.ScopeSpans[0].Scope.Attributes)
+	require.Equal(t, []*v1_common.KeyValue{test.MakeAttribute("http.method", "GET")}, span.Attributes)
+	require.Empty(t, span.Events[0].Attributes)
+	require.Empty(t, span.Links[0].Attributes)
+}
+
+func TestDenyAttributesHash(t *testing.T) {
+	b := makeDenylistBatch()
+
+	count := denyAttributes([]*v1.ResourceSpans{b}, []string{authorizationKey, "user.id"}, AttributeDenylistModeHash)
+	require.Equal(t, 6, count)
+
+	hashed := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+
+	span := b.ScopeSpans[0].Spans[0]
+	require.Equal(t, test.MakeAttribute(authorizationKey, hashed("resource-secret")), b.Resource.Attributes[1])
+	require.Equal(t, test.MakeAttribute(authorizationKey, hashed("scope-secret")), b.ScopeSpans[0].Scope.Attributes[0])
+	require.Equal(t, test.MakeAttribute(authorizationKey, hashed("span-secret")), span.Attributes[0])
+	require.Equal(t, test.MakeAttribute(authorizationKey, hashed("event-secret")), span.Events[0].Attributes[0])
+	require.Equal(t, test.MakeAttribute(authorizationKey, hashed("link-secret")), span.Links[0].Attributes[0])
+
+	// non-string values are hashed too
+	require.Equal(t, "user.id", span.Attributes[2].Key)
+	require.Len(t, span.Attributes[2].Value.GetStringValue(), 64)
+
+	// other attributes are untouched
+	require.Equal(t, test.MakeAttribute("service.name", "svc"), b.Resource.Attributes[0])
+	require.Equal(t, test.MakeAttribute("http.method", "GET"), span.Attributes[1])
+}
+
+func TestDenyAttributesEmptyDenylist(t *testing.T) {
+	b := makeDenylistBatch()
+	require.Equal(t, 0, denyAttributes([]*v1.ResourceSpans{b}, nil, AttributeDenylistModeDrop))
+	require.Equal(t, makeDenylistBatch(), b)
+}
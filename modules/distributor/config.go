@@ -11,6 +11,7 @@ import (
 	"github.com/grafana/tempo/modules/distributor/forwarder"
 	"github.com/grafana/tempo/modules/distributor/usage"
 	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
 )
 
 var defaultReceivers = map[string]interface{}{
@@ -45,6 +46,8 @@ type Config struct {
 	// Kafka
 	KafkaWritePathEnabled bool               `yaml:"kafka_write_path_enabled"`
 	KafkaConfig           ingest.KafkaConfig `yaml:"kafka_config"`
+	// KafkaOnlyWritePath skips the writes to the ingesters, which consume the traces from Kafka.
+	KafkaOnlyWritePath bool `yaml:"-"`
 
 	// disables write extension with inactive ingesters. Use this along with ingester.lifecycler.unregister_on_shutdown = true
 	//  note that setting these two config values reduces tolerance to failures on rollout b/c there is always one guaranteed to be failing replica
@@ -61,6 +64,11 @@ type Config struct {
 
 	// ArtificialDelay is an optional duration to introduce a delay for artificial processing in the distributor.
 	ArtificialDelay time.Duration `yaml:"artificial_delay,omitempty"`
+
+	// Interval at which tenant offboarding records are read from the backend. Writes for tenants that are
+	// being offboarded are rejected. 0 disables the check.
+	OffboardingPollInterval time.Duration     `yaml:"offboarding_poll_interval"`
+	OffboardingReader       backend.RawReader `yaml:"-"`
 }
 
 type LogSpansConfig struct {
@@ -0,0 +1,231 @@
+package distributor
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+// idSlabSize is the size of the slabs the trace and span IDs of a push are allocated from.
+const idSlabSize = 16 * 1024
+
+// traceConverter converts the OTLP traces of a push to a tempopb.Trace without marshalling them to bytes and back.
+// The result is equal to unmarshalling the OTLP proto bytes of the traces in a tempopb.Trace, which is wire-compatible:
+// strings are immutable and shared with the pdata traces, IDs and the structs of the spans are allocated in slabs
+// per push. Nothing is pooled across pushes: the spans are kept by the generator forwarder after the push returns.
+type traceConverter struct {
+	ids      []byte
+	spans    []v1.Span
+	statuses []v1.Status
+}
+
+// tracesToTempopb converts the OTLP traces to a tempopb.Trace. spanCount is the number of spans of the traces.
+func tracesToTempopb(traces ptrace.Traces, spanCount int) *tempopb.Trace {
+	c := traceConverter{
+		spans:    make([]v1.Span, spanCount),
+		statuses: make([]v1.Status, spanCount),
+	}
+
+	rss := traces.ResourceSpans()
+	trace := &tempopb.Trace{}
+	if rss.Len() > 0 {
+		trace.ResourceSpans = make([]*v1.ResourceSpans, 0, rss.Len())
+	}
+	for i := 0; i < rss.Len(); i++ {
+		trace.ResourceSpans = append(trace.ResourceSpans, c.resourceSpans(rss.At(i)))
+	}
+	return trace
+}
+
+func (c *traceConverter) resourceSpans(rs ptrace.ResourceSpans) *v1.ResourceSpans {
+	res := rs.Resource()
+	out := &v1.ResourceSpans{
+		Resource: &v1_resource.Resource{
+			Attributes:             c.attributes(res.Attributes()),
+			DroppedAttributesCount: res.DroppedAttributesCount(),
+		},
+		SchemaUrl: rs.SchemaUrl(),
+	}
+
+	sss := rs.ScopeSpans()
+	if sss.Len() > 0 {
+		out.ScopeSpans = make([]*v1.ScopeSpans, 0, sss.Len())
+	}
+	for i := 0; i < sss.Len(); i++ {
+		out.ScopeSpans = append(out.ScopeSpans, c.scopeSpans(sss.At(i)))
+	}
+	return out
+}
+
+func (c *traceConverter) scopeSpans(ss ptrace.ScopeSpans) *v1.ScopeSpans {
+	scope := ss.Scope()
+	out := &v1.ScopeSpans{
+		Scope: &v1_common.InstrumentationScope{
+			Name:                   scope.Name(),
+			Version:                scope.Version(),
+			Attributes:             c.attributes(scope.Attributes()),
+			DroppedAttributesCount: scope.DroppedAttributesCount(),
+		},
+		SchemaUrl: ss.SchemaUrl(),
+	}
+
+	spans := ss.Spans()
+	if spans.Len() > 0 {
+		out.Spans = make([]*v1.Span, 0, spans.Len())
+	}
+	for i := 0; i < spans.Len(); i++ {
+		out.Spans = append(out.Spans, c.span(spans.At(i)))
+	}
+	return out
+}
+
+func (c *traceConverter) span(s ptrace.Span) *v1.Span {
+	out := c.allocSpan()
+	out.TraceId = c.traceID(s.TraceID())
+	out.SpanId = c.spanID(s.SpanID())
+	out.TraceState = s.TraceState().AsRaw()
+	out.ParentSpanId = c.spanID(s.ParentSpanID())
+	out.Flags = s.Flags()
+	out.Name = s.Name()
+	out.Kind = v1.Span_SpanKind(s.Kind())
+	out.StartTimeUnixNano = uint64(s.StartTimestamp())
+	out.EndTimeUnixNano = uint64(s.EndTimestamp())
+	out.Attributes = c.attributes(s.Attributes())
+	out.DroppedAttributesCount = s.DroppedAttributesCount()
+	out.DroppedEventsCount = s.DroppedEventsCount()
+	out.DroppedLinksCount = s.DroppedLinksCount()
+
+	if events := s.Events(); events.Len() > 0 {
+		out.Events = make([]*v1.Span_Event, 0, events.Len())
+		for i := 0; i < events.Len(); i++ {
+			e := events.At(i)
+			out.Events = append(out.Events, &v1.Span_Event{
+				TimeUnixNano:           uint64(e.Timestamp()),
+				Name:                   e.Name(),
+				Attributes:             c.attributes(e.Attributes()),
+				DroppedAttributesCount: e.DroppedAttributesCount(),
+			})
+		}
+	}
+
+	if links := s.Links(); links.Len() > 0 {
+		out.Links = make([]*v1.Span_Link, 0, links.Len())
+		for i := 0; i < links.Len(); i++ {
+			l := links.At(i)
+			out.Links = append(out.Links, &v1.Span_Link{
+				TraceId:                c.traceID(l.TraceID()),
+				SpanId:                 c.spanID(l.SpanID()),
+				TraceState:             l.TraceState().AsRaw(),
+				Attributes:             c.attributes(l.Attributes()),
+				DroppedAttributesCount: l.DroppedAttributesCount(),
+			})
+		}
+	}
+
+	status := c.allocStatus()
+	status.Message = s.Status().Message()
+	status.Code = v1.Status_StatusCode(s.Status().Code())
+	out.Status = status
+
+	return out
+}
+
+func (c *traceConverter) attributes(m pcommon.Map) []*v1_common.KeyValue {
+	if m.Len() == 0 {
+		return nil
+	}
+
+	kvs := make([]v1_common.KeyValue, m.Len())
+	out := make([]*v1_common.KeyValue, 0, m.Len())
+	m.Range(func(k string, v pcommon.Value) bool {
+		kv := &kvs[len(out)]
+		kv.Key = k
+		kv.Value = c.value(v)
+		out = append(out, kv)
+		return true
+	})
+	return out
+}
+
+func (c *traceConverter) value(v pcommon.Value) *v1_common.AnyValue {
+	out := &v1_common.AnyValue{}
+	switch v.Type() {
+	case pcommon.ValueTypeStr:
+		out.Value = &v1_common.AnyValue_StringValue{StringValue: v.Str()}
+	case pcommon.ValueTypeBool:
+		out.Value = &v1_common.AnyValue_BoolValue{BoolValue: v.Bool()}
+	case pcommon.ValueTypeInt:
+		out.Value = &v1_common.AnyValue_IntValue{IntValue: v.Int()}
+	case pcommon.ValueTypeDouble:
+		out.Value = &v1_common.AnyValue_DoubleValue{DoubleValue: v.Double()}
+	case pcommon.ValueTypeBytes:
+		// nil bytes aren't marshalled by the otel-collector, the value is empty
+		if b := v.Bytes().AsRaw(); b != nil {
+			out.Value = &v1_common.AnyValue_BytesValue{BytesValue: b}
+		}
+	case pcommon.ValueTypeSlice:
+		s := v.Slice()
+		arr := &v1_common.ArrayValue{}
+		if s.Len() > 0 {
+			arr.Values = make([]*v1_common.AnyValue, 0, s.Len())
+		}
+		for i := 0; i < s.Len(); i++ {
+			arr.Values = append(arr.Values, c.value(s.At(i)))
+		}
+		out.Value = &v1_common.AnyValue_ArrayValue{ArrayValue: arr}
+	case pcommon.ValueTypeMap:
+		out.Value = &v1_common.AnyValue_KvlistValue{KvlistValue: &v1_common.KeyValueList{Values: c.attributes(v.Map())}}
+	}
+	return out
+}
+
+// traceID returns the trace ID as a slice. Empty IDs are marshalled by the otel-collector, so they are empty slices
+// instead of nil.
+func (c *traceConverter) traceID(id pcommon.TraceID) []byte {
+	if id.IsEmpty() {
+		return []byte{}
+	}
+	return c.allocID(id[:])
+}
+
+func (c *traceConverter) spanID(id pcommon.SpanID) []byte {
+	if id.IsEmpty() {
+		return []byte{}
+	}
+	return c.allocID(id[:])
+}
+
+// allocID copies the ID to the current slab. The capacity of the returned slice is its length, so appending to it
+// can't overwrite the next ID.
+func (c *traceConverter) allocID(id []byte) []byte {
+	if cap(c.ids)-len(c.ids) < len(id) {
+		c.ids = make([]byte, 0, idSlabSize)
+	}
+	start := len(c.ids)
+	c.ids = append(c.ids, id...)
+	return c.ids[start:len(c.ids):len(c.ids)]
+}
+
+// allocSpan returns the next span of the slab, spanCount may be wrong if the traces were modified after it was
+// counted, so spans past the end of the slab are allocated one by one.
+func (c *traceConverter) allocSpan() *v1.Span {
+	if len(c.spans) == 0 {
+		return &v1.Span{}
+	}
+	s := &c.spans[0]
+	c.spans = c.spans[1:]
+	return s
+}
+
+func (c *traceConverter) allocStatus() *v1.Status {
+	if len(c.statuses) == 0 {
+		return &v1.Status{}
+	}
+	s := &c.statuses[0]
+	c.statuses = c.statuses[1:]
+	return s
+}
@@ -0,0 +1,122 @@
+package distributor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+// tracesToTempopbByBytes is the conversion by marshalling to bytes and back that tracesToTempopb must be equal to
+func tracesToTempopbByBytes(t testing.TB, traces ptrace.Traces) *tempopb.Trace {
+	b, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(traces)
+	require.NoError(t, err)
+
+	trace := &tempopb.Trace{}
+	require.NoError(t, trace.Unmarshal(b))
+	return trace
+}
+
+func TestTracesToTempopb(t *testing.T) {
+	traces := ptrace.NewTraces()
+
+	// empty resource and scope
+	traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty()
+
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.SetSchemaUrl("resource-schema")
+	rs.Resource().Attributes().PutStr("service.name", "svc")
+	rs.Resource().SetDroppedAttributesCount(1)
+
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.SetSchemaUrl("scope-schema")
+	ss.Scope().SetName("scope")
+	ss.Scope().SetVersion("1.0")
+	ss.Scope().Attributes().PutBool("bool", true)
+
+	span := ss.Spans().AppendEmpty()
+	span.SetTraceID(pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	span.SetSpanID(pcommon.SpanID{1, 2, 3, 4, 5, 6, 7, 8})
+	span.SetParentSpanID(pcommon.SpanID{8, 7, 6, 5, 4, 3, 2, 1})
+	span.TraceState().FromRaw("k=v")
+	span.SetFlags(1)
+	span.SetName("span")
+	span.SetKind(ptrace.SpanKindServer)
+	span.SetStartTimestamp(10)
+	span.SetEndTimestamp(20)
+	span.SetDroppedAttributesCount(2)
+	span.SetDroppedEventsCount(3)
+	span.SetDroppedLinksCount(4)
+	span.Status().SetCode(ptrace.StatusCodeError)
+	span.Status().SetMessage("error")
+
+	attrs := span.Attributes()
+	attrs.PutStr("str", "value")
+	attrs.PutInt("int", 1)
+	attrs.PutDouble("double", 1.5)
+	attrs.PutEmptyBytes("bytes").FromRaw([]byte{1, 2})
+	attrs.PutEmptyBytes("empty-bytes")
+	attrs.PutEmpty("empty")
+	attrs.PutEmptySlice("empty-slice")
+	slice := attrs.PutEmptySlice("slice")
+	slice.AppendEmpty().SetStr("a")
+	slice.AppendEmpty().SetInt(2)
+	attrs.PutEmptyMap("empty-map")
+	attrs.PutEmptyMap("map").PutStr("nested", "value")
+
+	event := span.Events().AppendEmpty()
+	event.SetName("event")
+	event.SetTimestamp(15)
+	event.Attributes().PutStr("event", "value")
+	event.SetDroppedAttributesCount(5)
+
+	link := span.Links().AppendEmpty()
+	link.SetTraceID(pcommon.TraceID{16})
+	link.SetSpanID(pcommon.SpanID{8})
+	link.TraceState().FromRaw("l=v")
+	link.Attributes().PutStr("link", "value")
+	link.SetDroppedAttributesCount(6)
+
+	// span without IDs
+	ss.Spans().AppendEmpty()
+
+	require.Equal(t, tracesToTempopbByBytes(t, traces), tracesToTempopb(traces, traces.SpanCount()))
+	// the span count is only used to size the slabs
+	require.Equal(t, tracesToTempopbByBytes(t, traces), tracesToTempopb(traces, 0))
+}
+
+func TestTracesToTempopbRandom(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		b, err := test.MakeTrace(10, nil).Marshal()
+		require.NoError(t, err)
+		traces, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(b)
+		require.NoError(t, err)
+
+		require.Equal(t, tracesToTempopbByBytes(t, traces), tracesToTempopb(traces, traces.SpanCount()))
+	}
+}
+
+func BenchmarkTracesToTempopb(b *testing.B) {
+	buff, err := test.MakeTrace(100, nil).Marshal()
+	require.NoError(b, err)
+	traces, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(buff)
+	require.NoError(b, err)
+	spanCount := traces.SpanCount()
+
+	b.Run("bytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = tracesToTempopbByBytes(b, traces)
+		}
+	})
+	b.Run("fields", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = tracesToTempopb(traces, spanCount)
+		}
+	})
+}
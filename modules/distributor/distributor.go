@@ -14,7 +14,6 @@ import (
 	"github.com/go-kit/log/level"
 	"github.com/gogo/protobuf/proto"
 	"github.com/gogo/status"
-	"github.com/grafana/dskit/limiter"
 	dslog "github.com/grafana/dskit/log"
 	"github.com/grafana/dskit/ring"
 	ring_client "github.com/grafana/dskit/ring/client"
@@ -26,8 +25,11 @@ import (
 	"github.com/segmentio/fasthash/fnv1a"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/otel"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/health/grpc_health_v1"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"github.com/grafana/tempo/modules/distributor/forwarder"
 	"github.com/grafana/tempo/modules/distributor/receiver"
@@ -54,6 +56,8 @@ const (
 	reasonTraceTooLarge = "trace_too_large"
 	// reasonLiveTracesExceeded indicates that tempo is already tracking too many live traces in the ingesters for this user
 	reasonLiveTracesExceeded = "live_traces_exceeded"
+	// reasonTenantOffboarded indicates that the tenant is being offboarded and no longer accepts writes
+	reasonTenantOffboarded = "tenant_offboarded"
 	// reasonUnknown indicates a pushByte error at the ingester level not related to GRPC
 	reasonUnknown = "unknown_error"
 
@@ -120,6 +124,11 @@ var (
 		Name:      "distributor_attributes_truncated_total",
 		Help:      "The total number of attribute keys or values truncated per tenant",
 	}, []string{"tenant"})
+	metricAttributesDenied = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_attributes_denied_total",
+		Help:      "The total number of attributes removed or hashed by the attribute denylist per tenant",
+	}, []string{"tenant"})
 	metricKafkaRecordsPerRequest = promauto.NewHistogram(prometheus.HistogramOpts{
 		Namespace: "tempo",
 		Subsystem: "distributor",
@@ -186,7 +195,7 @@ type Distributor struct {
 	partitionRing ring.PartitionRingReader
 
 	// Per-user rate limiter.
-	ingestionRateLimiter *limiter.RateLimiter
+	ingestionRateLimiter *ingestionRateLimiter
 
 	// Manager for subservices
 	subservices        *services.Manager
@@ -194,6 +203,8 @@ type Distributor struct {
 
 	usage *usage.Tracker
 
+	offboarding *tenantOffboarding
+
 	logger log.Logger
 
 	// For testing functionality that relies on timing without having to sleep in unit tests.
@@ -229,7 +240,7 @@ func New(
 	subservices := []services.Service(nil)
 
 	// Create the configured ingestion rate limit strategy (local or global).
-	var ingestionRateStrategy limiter.RateLimiterStrategy
+	var ingestionRateStrategy ingestionRateStrategy
 	var distributorRing *ring.Ring
 
 	if o.IngestionRateStrategy() == overrides.GlobalIngestionRateStrategy {
@@ -266,7 +277,7 @@ func New(
 		ingestersRing:        ingestersRing,
 		pool:                 pool,
 		DistributorRing:      distributorRing,
-		ingestionRateLimiter: limiter.NewRateLimiter(ingestionRateStrategy, 10*time.Second),
+		ingestionRateLimiter: newIngestionRateLimiter(ingestionRateStrategy, 10*time.Second),
 		generatorClientCfg:   generatorClientCfg,
 		generatorsRing:       generatorsRing,
 		partitionRing:        partitionRing,
@@ -321,6 +332,11 @@ func New(
 	}
 	subservices = append(subservices, receivers)
 
+	if cfg.OffboardingPollInterval > 0 && cfg.OffboardingReader != nil {
+		d.offboarding = newTenantOffboarding(cfg.OffboardingReader, cfg.OffboardingPollInterval, logger)
+		subservices = append(subservices, d.offboarding)
+	}
+
 	if cfg.KafkaWritePathEnabled {
 		client, err := ingest.NewWriterClient(cfg.KafkaConfig, 10, logger, prometheus.WrapRegistererWithPrefix("tempo_distributor_", reg))
 		if err != nil {
@@ -375,12 +391,12 @@ func (d *Distributor) stopping(_ error) error {
 // example: if the ingestion rate limit is 10MB/s and the burst size is 20MB, and there are 5 healthy distributors,
 // then each distributor will allow 2MB/s with a burst of 20MB.
 func (d *Distributor) checkForRateLimits(tracesSize, spanCount int, userID string) error {
-	now := time.Now()
-	if !d.ingestionRateLimiter.AllowN(now, userID, tracesSize) {
+	denial := d.ingestionRateLimiter.allowN(time.Now(), userID, tracesSize)
+	if denial != nil {
 		overrides.RecordDiscardedSpans(spanCount, reasonRateLimited, userID)
-		// limit: number of bytes per second allowed for the user, as per ingestion rate strategy
-		limit := int(d.ingestionRateLimiter.Limit(now, userID))
-		burst := d.ingestionRateLimiter.Burst(now, userID)
+		// limit: number of bytes per second allowed for the user by the class that denied the push, as per ingestion rate strategy
+		limit := int(denial.limit)
+		burst := denial.burst
 
 		// globalLimit will be 0 when using local ingestion rate strategy
 		var globalLimit int
@@ -390,21 +406,42 @@ func (d *Distributor) checkForRateLimits(tracesSize, spanCount int, userID strin
 			globalLimit = limit * d.DistributorRing.InstancesCount()
 		}
 
+		// the class is only named if the tenant has a burst class
+		var class string
+		if denial.classes > 1 {
+			class = denial.class + " "
+		}
+
 		// batch size is too big if it's more than the limit and burst both
 		if tracesSize > limit && tracesSize > burst {
-			return status.Errorf(codes.ResourceExhausted,
-				"%s: batch size (%d bytes) exceeds ingestion limit (local: %d bytes/s, global: %d bytes/s, burst: %d bytes) while adding %d bytes for user %s. consider reducing batch size or increasing rate limit.",
-				overrides.ErrorPrefixRateLimited, tracesSize, limit, globalLimit, burst, tracesSize, userID)
+			return rateLimitedError(denial.retryAfter,
+				"%s: batch size (%d bytes) exceeds %singestion limit (local: %d bytes/s, global: %d bytes/s, burst: %d bytes) while adding %d bytes for user %s. consider reducing batch size or increasing rate limit.",
+				overrides.ErrorPrefixRateLimited, tracesSize, class, limit, globalLimit, burst, tracesSize, userID)
 		}
 
-		return status.Errorf(codes.ResourceExhausted,
-			"%s: ingestion rate limit (local: %d bytes/s, global: %d bytes/s, burst: %d bytes) exceeded while adding %d bytes for user %s. consider increasing the limit or reducing ingestion rate.",
-			overrides.ErrorPrefixRateLimited, limit, globalLimit, burst, tracesSize, userID)
+		return rateLimitedError(denial.retryAfter,
+			"%s: %singestion rate limit (local: %d bytes/s, global: %d bytes/s, burst: %d bytes) exceeded while adding %d bytes for user %s. consider increasing the limit or reducing ingestion rate.",
+			overrides.ErrorPrefixRateLimited, class, limit, globalLimit, burst, tracesSize, userID)
 	}
 
 	return nil
 }
 
+// rateLimitedError returns a ResourceExhausted error. If the push will fit in the rate limits after retryAfter, the
+// error has a RetryInfo with that delay so clients can back off precisely.
+func rateLimitedError(retryAfter time.Duration, format string, args ...any) error {
+	st := grpcstatus.Newf(codes.ResourceExhausted, format, args...)
+	if retryAfter <= 0 {
+		return st.Err()
+	}
+
+	withRetry, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	if err != nil {
+		return st.Err()
+	}
+	return withRetry.Err()
+}
+
 func (d *Distributor) extractBasicInfo(ctx context.Context, traces ptrace.Traces) (userID string, spanCount, tracesSize int, err error) {
 	user, e := user.ExtractOrgID(ctx)
 	if e != nil {
@@ -432,6 +469,11 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 		return &tempopb.PushResponse{}, nil
 	}
 
+	if d.offboarding != nil && !d.offboarding.acceptsWrites(userID) {
+		overrides.RecordDiscardedSpans(spanCount, reasonTenantOffboarded, userID)
+		return nil, status.Errorf(codes.PermissionDenied, "tenant %s is being offboarded and no longer accepts writes", userID)
+	}
+
 	// check limits
 	// todo - usage tracker include discarded bytes?
 	err = d.checkForRateLimits(size, spanCount, userID)
@@ -439,23 +481,15 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 		return nil, err
 	}
 
-	// Convert to bytes and back. This is unfortunate for efficiency, but it works
-	// around the otel-collector internalization of otel-proto which Tempo also uses.
-	convert, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(traces)
-	if err != nil {
-		return nil, err
-	}
+	// Convert to the tempopb model, which is wire-compatible with the otel-proto internalized by the otel-collector.
+	// The conversion is field by field instead of marshalling to bytes and back.
+	batches := tracesToTempopb(traces, spanCount).ResourceSpans
 
-	// tempopb.Trace is wire-compatible with ExportTraceServiceRequest
-	// used by ToOtlpProtoBytes
-	trace := tempopb.Trace{}
-	err = trace.Unmarshal(convert)
-	if err != nil {
-		return nil, err
+	// deny attributes first so they aren't logged either
+	if denied := denyAttributes(batches, d.overrides.IngestionAttributeDenylist(userID), d.overrides.IngestionAttributeDenylistMode(userID)); denied > 0 {
+		metricAttributesDenied.WithLabelValues(userID).Add(float64(denied))
 	}
 
-	batches := trace.ResourceSpans
-
 	logReceivedSpans(batches, &d.cfg.LogReceivedSpans, d.logger)
 	if d.cfg.MetricReceivedSpans.Enabled {
 		metricSpans(batches, userID, &d.cfg.MetricReceivedSpans)
@@ -474,7 +508,7 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 
 	maxAttributeBytes := d.getMaxAttributeBytes(userID)
 
-	ringTokens, rebatchedTraces, truncatedAttributeCount, err := requestsByTraceID(batches, userID, spanCount, maxAttributeBytes)
+	ringTokens, rebatchedTraces, truncatedAttributeCount, err := requestsByTraceID(batches, userID, spanCount, maxAttributeBytes, d.overrides.IngestionTraceIDHashScheme(userID))
 	if err != nil {
 		logDiscardedResourceSpans(batches, userID, &d.cfg.LogDiscardedSpans, d.logger)
 		return nil, err
@@ -484,9 +518,11 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 		metricAttributesTruncated.WithLabelValues(userID).Add(float64(truncatedAttributeCount))
 	}
 
-	err = d.sendToIngestersViaBytes(ctx, userID, rebatchedTraces, ringTokens)
-	if err != nil {
-		return nil, err
+	if !d.cfg.KafkaOnlyWritePath {
+		err = d.sendToIngestersViaBytes(ctx, userID, rebatchedTraces, ringTokens)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if err := d.forwardersManager.ForTenant(userID).ForwardTraces(ctx, traces); err != nil {
@@ -701,7 +737,7 @@ func (d *Distributor) sendToKafka(ctx context.Context, userID string, keys []uin
 
 // requestsByTraceID takes an incoming tempodb.PushRequest and creates a set of keys for the hash ring
 // and traces to pass onto the ingesters.
-func requestsByTraceID(batches []*v1.ResourceSpans, userID string, spanCount, maxSpanAttrSize int) ([]uint32, []*rebatchedTrace, int, error) {
+func requestsByTraceID(batches []*v1.ResourceSpans, userID string, spanCount, maxSpanAttrSize int, hashScheme string) ([]uint32, []*rebatchedTrace, int, error) {
 	const tracesPerBatch = 20 // p50 of internal env
 	tracesByID := make(map[uint64]*rebatchedTrace, tracesPerBatch)
 	truncatedAttributeCount := 0
@@ -810,7 +846,7 @@ func requestsByTraceID(batches []*v1.ResourceSpans, userID string, spanCount, ma
 	traces := make([]*rebatchedTrace, 0, len(tracesByID))
 
 	for _, tr := range tracesByID {
-		ringTokens = append(ringTokens, util.TokenFor(userID, tr.id))
+		ringTokens = append(ringTokens, util.TokenForScheme(hashScheme, userID, tr.id))
 		traces = append(traces, tr)
 	}
 
@@ -27,15 +27,18 @@ import (
 	"github.com/grafana/tempo/modules/generator"
 	"github.com/grafana/tempo/pkg/ingest"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/twmb/franz-go/pkg/kfake"
 	"github.com/twmb/franz-go/pkg/kgo"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
+	grpcstatus "google.golang.org/grpc/status"
 
 	"github.com/grafana/tempo/modules/distributor/receiver"
 	generator_client "github.com/grafana/tempo/modules/generator/client"
@@ -899,7 +902,7 @@ func TestRequestsByTraceID(t *testing.T) {
 			if tt.emptyTenant {
 				tenant = ""
 			}
-			ringTokens, rebatchedTraces, _, err := requestsByTraceID(tt.batches, tenant, 1, 1000)
+			ringTokens, rebatchedTraces, _, err := requestsByTraceID(tt.batches, tenant, 1, 1000, "")
 			require.Equal(t, len(ringTokens), len(rebatchedTraces))
 
 			for i, expectedID := range tt.expectedIDs {
@@ -924,6 +927,17 @@ func TestRequestsByTraceID(t *testing.T) {
 	}
 }
 
+func TestRequestsByTraceIDHashScheme(t *testing.T) {
+	traceID := test.ValidTraceID(nil)
+	batches := []*v1.ResourceSpans{test.MakeBatch(1, traceID)}
+
+	for _, scheme := range []string{"", util.TraceIDHashSchemeXXHash64, util.TraceIDHashSchemeFNV128Fold} {
+		ringTokens, _, _, err := requestsByTraceID(batches, util.FakeTenantID, 1, 1000, scheme)
+		require.NoError(t, err)
+		require.Equal(t, []uint32{util.TokenForScheme(scheme, util.FakeTenantID, traceID)}, ringTokens, scheme)
+	}
+}
+
 func TestProcessAttributes(t *testing.T) {
 	spanCount := 10
 	batchCount := 3
@@ -981,7 +995,7 @@ func TestProcessAttributes(t *testing.T) {
 		},
 	}
 
-	_, rebatchedTrace, truncatedCount, _ := requestsByTraceID(trace.ResourceSpans, "test", spanCount*batchCount, maxAttrByte)
+	_, rebatchedTrace, truncatedCount, _ := requestsByTraceID(trace.ResourceSpans, "test", spanCount*batchCount, maxAttrByte, "")
 	// 2 at resource level, 2 at span level, 2 at event level, 2 at link level, 2 at scope level
 	assert.Equal(t, 10, truncatedCount)
 	for _, rT := range rebatchedTrace {
@@ -1071,7 +1085,7 @@ func BenchmarkTestsByRequestID(b *testing.B) {
 				{
 					ScopeSpans: blerg,
 				},
-			}, "test", spansPer*len(traces), 5)
+			}, "test", spansPer*len(traces), 5, "")
 			require.NoError(b, err)
 		}
 	}
@@ -2351,3 +2365,38 @@ func TestCheckForRateLimits(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckForRateLimitsRetryInfo(t *testing.T) {
+	overridesConfig := overrides.Config{
+		Defaults: overrides.Overrides{
+			Ingestion: overrides.IngestionOverrides{
+				RateStrategy:        overrides.LocalIngestionRateStrategy,
+				RateLimitBytes:      100,
+				BurstSizeBytes:      200,
+				BurstRateLimitBytes: 400,
+				BurstDuration:       model.Duration(time.Minute),
+			},
+		},
+	}
+	d, _ := prepare(t, overridesConfig, kitlog.NewNopLogger())
+
+	require.NoError(t, d.checkForRateLimits(200, 100, "test-user"))
+
+	// the burst class is empty, the push fits once it refilled
+	err := d.checkForRateLimits(200, 100, "test-user")
+	s, ok := grpcstatus.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.ResourceExhausted, s.Code())
+	require.Equal(t, "RATE_LIMITED: burst ingestion rate limit (local: 400 bytes/s, global: 0 bytes/s, burst: 200 bytes) exceeded while adding 200 bytes for user test-user. consider increasing the limit or reducing ingestion rate.", s.Message())
+	require.Len(t, s.Details(), 1)
+	retryInfo, ok := s.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	require.InDelta(t, 500*time.Millisecond, retryInfo.RetryDelay.AsDuration(), float64(10*time.Millisecond))
+
+	// the push never fits, there's no point in retrying
+	err = d.checkForRateLimits(300, 100, "test-user")
+	s, ok = grpcstatus.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.ResourceExhausted, s.Code())
+	require.Empty(t, s.Details())
+}
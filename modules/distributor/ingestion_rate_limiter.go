@@ -0,0 +1,212 @@
+package distributor
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	rateClassSustained = "sustained"
+	rateClassBurst     = "burst"
+)
+
+// ingestionRateLimiter limits the ingestion of each tenant with a token bucket per rate class, a push must fit in the
+// buckets of all classes. Without a burst class a tenant has a single sustained bucket refilled at rate_limit_bytes
+// and holding burst_size_bytes. With a burst class, the burst bucket is refilled at burst_rate_limit_bytes and holds
+// burst_size_bytes, and the sustained bucket also holds the bytes ingested above rate_limit_bytes during
+// burst_duration: tenants can ingest at the burst rate for burst_duration before falling back to the sustained rate.
+type ingestionRateLimiter struct {
+	strategy      ingestionRateStrategy
+	recheckPeriod time.Duration
+
+	mtx     sync.Mutex
+	tenants map[string]*tenantRateLimiter
+}
+
+type tenantRateLimiter struct {
+	classes   []*rateClass
+	recheckAt time.Time
+}
+
+type rateClass struct {
+	name    string
+	limiter *rate.Limiter
+}
+
+// rateLimitDenial describes the rate class that denied a push
+type rateLimitDenial struct {
+	class string
+	limit float64
+	burst int
+	// retryAfter is when the push would fit in all classes, 0 if it never fits
+	retryAfter time.Duration
+	// classes is the number of rate classes of the tenant
+	classes int
+}
+
+func newIngestionRateLimiter(strategy ingestionRateStrategy, recheckPeriod time.Duration) *ingestionRateLimiter {
+	return &ingestionRateLimiter{
+		strategy:      strategy,
+		recheckPeriod: recheckPeriod,
+		tenants:       map[string]*tenantRateLimiter{},
+	}
+}
+
+// allowN consumes n tokens of all rate classes of the tenant. If a class doesn't have enough tokens, no tokens are
+// consumed and the class that denied the push is returned.
+func (l *ingestionRateLimiter) allowN(now time.Time, userID string, n int) *rateLimitDenial {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	t := l.tenantLimiter(now, userID)
+
+	var (
+		denial       *rateLimitDenial
+		reservations = make([]*rate.Reservation, 0, len(t.classes))
+	)
+	for _, c := range t.classes {
+		r := c.limiter.ReserveN(now, n)
+		reservations = append(reservations, r)
+
+		if !r.OK() {
+			// the push never fits in this class
+			denial = &rateLimitDenial{class: c.name, limit: float64(c.limiter.Limit()), burst: c.limiter.Burst()}
+			break
+		}
+		if delay := r.DelayFrom(now); delay > 0 && (denial == nil || delay > denial.retryAfter) {
+			denial = &rateLimitDenial{class: c.name, limit: float64(c.limiter.Limit()), burst: c.limiter.Burst(), retryAfter: delay}
+		}
+	}
+
+	if denial == nil {
+		return nil
+	}
+
+	for _, r := range reservations {
+		r.CancelAt(now)
+	}
+	if denial.burst < n {
+		// a class the push never fits in overrides the delay of the others
+		denial.retryAfter = 0
+	}
+	denial.classes = len(t.classes)
+	return denial
+}
+
+func (l *ingestionRateLimiter) tenantLimiter(now time.Time, userID string) *tenantRateLimiter {
+	t, ok := l.tenants[userID]
+	if ok && now.Before(t.recheckAt) {
+		return t
+	}
+
+	classes := l.rateClasses(userID)
+	if !ok {
+		t = &tenantRateLimiter{}
+		for _, c := range classes {
+			t.classes = append(t.classes, &rateClass{name: c.name, limiter: rate.NewLimiter(rate.Limit(c.limit), c.burst)})
+		}
+		l.tenants[userID] = t
+	} else {
+		// keep the tokens of the classes that still exist
+		existing := t.classes
+		t.classes = nil
+		for _, c := range classes {
+			i := slices.IndexFunc(existing, func(e *rateClass) bool { return e.name == c.name })
+			if i < 0 {
+				t.classes = append(t.classes, &rateClass{name: c.name, limiter: rate.NewLimiter(rate.Limit(c.limit), c.burst)})
+				continue
+			}
+
+			lim := existing[i].limiter
+			if float64(lim.Limit()) != c.limit {
+				lim.SetLimitAt(now, rate.Limit(c.limit))
+			}
+			if lim.Burst() != c.burst {
+				lim.SetBurstAt(now, c.burst)
+			}
+			t.classes = append(t.classes, existing[i])
+		}
+	}
+
+	t.recheckAt = now.Add(l.recheckPeriod)
+	return t
+}
+
+type rateClassLimits struct {
+	name  string
+	limit float64
+	burst int
+}
+
+// rateClasses returns the rate classes of the tenant from the strategy
+func (l *ingestionRateLimiter) rateClasses(userID string) []rateClassLimits {
+	limit, burst := l.strategy.Limit(userID), l.strategy.Burst(userID)
+
+	burstLimit, burstDuration := l.strategy.BurstLimit(userID), l.strategy.BurstDuration(userID)
+	if burstLimit <= limit || burstDuration <= 0 {
+		return []rateClassLimits{{name: rateClassSustained, limit: limit, burst: burst}}
+	}
+
+	return []rateClassLimits{
+		{name: rateClassSustained, limit: limit, burst: burst + int((burstLimit-limit)*burstDuration.Seconds())},
+		{name: rateClassBurst, limit: burstLimit, burst: burst},
+	}
+}
+
+type rateLimiterState struct {
+	Tenant  string           `json:"tenant"`
+	Classes []rateClassState `json:"classes"`
+}
+
+type rateClassState struct {
+	Class string  `json:"class"`
+	Limit float64 `json:"limit_bytes_per_second"`
+	Burst int     `json:"burst_bytes"`
+	// Tokens is the number of bytes that can be pushed right now
+	Tokens float64 `json:"tokens_bytes"`
+}
+
+// state returns the state of the rate classes of the tenants that pushed to this distributor, of a single tenant if
+// userID isn't empty.
+func (l *ingestionRateLimiter) state(now time.Time, userID string) []rateLimiterState {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	states := []rateLimiterState{}
+	for tenant, t := range l.tenants {
+		if userID != "" && tenant != userID {
+			continue
+		}
+
+		s := rateLimiterState{Tenant: tenant}
+		for _, c := range t.classes {
+			s.Classes = append(s.Classes, rateClassState{
+				Class:  c.name,
+				Limit:  float64(c.limiter.Limit()),
+				Burst:  c.limiter.Burst(),
+				Tokens: c.limiter.TokensAt(now),
+			})
+		}
+		states = append(states, s)
+	}
+
+	slices.SortFunc(states, func(a, b rateLimiterState) int { return strings.Compare(a.Tenant, b.Tenant) })
+	return states
+}
+
+// RateLimitsHandler returns the state of the ingestion rate limiter of each tenant that pushed to this distributor,
+// or of the tenant of the tenant query parameter.
+func (d *Distributor) RateLimitsHandler(w http.ResponseWriter, r *http.Request) {
+	states := d.ingestionRateLimiter.state(time.Now(), r.URL.Query().Get("tenant"))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(states); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
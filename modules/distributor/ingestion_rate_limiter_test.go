@@ -0,0 +1,173 @@
+package distributor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides"
+)
+
+func newTestIngestionRateLimiter(t *testing.T, ingestion overrides.IngestionOverrides) *ingestionRateLimiter {
+	o, err := overrides.NewOverrides(overrides.Config{
+		Defaults: overrides.Overrides{Ingestion: ingestion},
+	}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	return newIngestionRateLimiter(newLocalIngestionRateStrategy(o), time.Minute)
+}
+
+func TestIngestionRateLimiter_Sustained(t *testing.T) {
+	l := newTestIngestionRateLimiter(t, overrides.IngestionOverrides{
+		RateStrategy:   overrides.LocalIngestionRateStrategy,
+		RateLimitBytes: 10,
+		BurstSizeBytes: 20,
+	})
+
+	now := time.Now()
+	require.Nil(t, l.allowN(now, "test", 20))
+
+	denial := l.allowN(now, "test", 5)
+	require.NotNil(t, denial)
+	assert.Equal(t, &rateLimitDenial{class: rateClassSustained, limit: 10, burst: 20, retryAfter: 500 * time.Millisecond, classes: 1}, denial)
+
+	// a push bigger than the burst never fits
+	denial = l.allowN(now, "test", 21)
+	require.NotNil(t, denial)
+	assert.Equal(t, time.Duration(0), denial.retryAfter)
+
+	// denied pushes don't consume tokens
+	require.Nil(t, l.allowN(now.Add(500*time.Millisecond), "test", 5))
+}
+
+func TestIngestionRateLimiter_BurstClass(t *testing.T) {
+	// 10 bytes/s sustained, 30 bytes/s during 2s
+	l := newTestIngestionRateLimiter(t, overrides.IngestionOverrides{
+		RateStrategy:        overrides.LocalIngestionRateStrategy,
+		RateLimitBytes:      10,
+		BurstSizeBytes:      20,
+		BurstRateLimitBytes: 30,
+		BurstDuration:       model.Duration(2 * time.Second),
+	})
+
+	now := time.Now()
+	require.Nil(t, l.allowN(now, "test", 20))
+
+	// the burst class is empty, it refills at 30 bytes/s
+	denial := l.allowN(now, "test", 3)
+	require.NotNil(t, denial)
+	assert.Equal(t, &rateLimitDenial{class: rateClassBurst, limit: 30, burst: 20, retryAfter: 100 * time.Millisecond, classes: 2}, denial)
+
+	// a push bigger than the burst class never fits
+	denial = l.allowN(now, "test", 25)
+	require.NotNil(t, denial)
+	assert.Equal(t, rateClassBurst, denial.class)
+	assert.Equal(t, time.Duration(0), denial.retryAfter)
+
+	// 20 bytes/s are above the sustained rate, the sustained class is empty after 4s
+	for i := 1; i <= 4; i++ {
+		require.Nil(t, l.allowN(now.Add(time.Duration(i)*time.Second), "test", 20), "push %d", i)
+	}
+
+	denial = l.allowN(now.Add(5*time.Second), "test", 20)
+	require.NotNil(t, denial)
+	assert.Equal(t, &rateLimitDenial{class: rateClassSustained, limit: 10, burst: 60, retryAfter: time.Second, classes: 2}, denial)
+}
+
+func TestIngestionRateLimiter_State(t *testing.T) {
+	l := newTestIngestionRateLimiter(t, overrides.IngestionOverrides{
+		RateStrategy:        overrides.LocalIngestionRateStrategy,
+		RateLimitBytes:      10,
+		BurstSizeBytes:      20,
+		BurstRateLimitBytes: 30,
+		BurstDuration:       model.Duration(2 * time.Second),
+	})
+
+	now := time.Now()
+	require.Nil(t, l.allowN(now, "b", 5))
+	require.Nil(t, l.allowN(now, "a", 20))
+
+	assert.Equal(t, []rateLimiterState{
+		{Tenant: "a", Classes: []rateClassState{
+			{Class: rateClassSustained, Limit: 10, Burst: 60, Tokens: 40},
+			{Class: rateClassBurst, Limit: 30, Burst: 20, Tokens: 0},
+		}},
+		{Tenant: "b", Classes: []rateClassState{
+			{Class: rateClassSustained, Limit: 10, Burst: 60, Tokens: 55},
+			{Class: rateClassBurst, Limit: 30, Burst: 20, Tokens: 15},
+		}},
+	}, l.state(now, ""))
+
+	states := l.state(now, "b")
+	require.Len(t, states, 1)
+	assert.Equal(t, "b", states[0].Tenant)
+
+	assert.Empty(t, l.state(now, "c"))
+}
+
+func TestRateLimitsHandler(t *testing.T) {
+	d := &Distributor{ingestionRateLimiter: newTestIngestionRateLimiter(t, overrides.IngestionOverrides{
+		RateStrategy:   overrides.LocalIngestionRateStrategy,
+		RateLimitBytes: 10,
+		BurstSizeBytes: 20,
+	})}
+	require.Nil(t, d.ingestionRateLimiter.allowN(time.Now(), "test", 20))
+
+	rec := httptest.NewRecorder()
+	d.RateLimitsHandler(rec, httptest.NewRequest(http.MethodGet, "/distributor/rate_limits?tenant=test", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var states []rateLimiterState
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &states))
+	require.Len(t, states, 1)
+	assert.Equal(t, "test", states[0].Tenant)
+	require.Len(t, states[0].Classes, 1)
+	assert.Equal(t, rateClassSustained, states[0].Classes[0].Class)
+	assert.InDelta(t, 0, states[0].Classes[0].Tokens, 1)
+}
+
+func TestIngestionRateLimiter_LimitsChange(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Config{
+		Defaults: overrides.Overrides{Ingestion: overrides.IngestionOverrides{
+			RateStrategy:   overrides.LocalIngestionRateStrategy,
+			RateLimitBytes: 10,
+			BurstSizeBytes: 20,
+		}},
+	}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	strategy := &burstStrategy{ingestionRateStrategy: newLocalIngestionRateStrategy(o)}
+	l := newIngestionRateLimiter(strategy, time.Second)
+
+	now := time.Now()
+	require.Nil(t, l.allowN(now, "test", 15))
+
+	// the burst class is added on the next recheck, the sustained class keeps its tokens
+	strategy.burstLimit, strategy.burstDuration = 30, 2*time.Second
+	states := l.state(now, "test")
+	require.Len(t, states[0].Classes, 1)
+
+	require.Nil(t, l.allowN(now.Add(time.Second), "test", 1))
+	states = l.state(now.Add(time.Second), "test")
+	require.Len(t, states[0].Classes, 2)
+	assert.Equal(t, rateClassState{Class: rateClassSustained, Limit: 10, Burst: 60, Tokens: 14}, states[0].Classes[0])
+	assert.Equal(t, rateClassState{Class: rateClassBurst, Limit: 30, Burst: 20, Tokens: 19}, states[0].Classes[1])
+}
+
+type burstStrategy struct {
+	ingestionRateStrategy
+	burstLimit    float64
+	burstDuration time.Duration
+}
+
+func (s *burstStrategy) BurstLimit(string) float64 { return s.burstLimit }
+
+func (s *burstStrategy) BurstDuration(string) time.Duration { return s.burstDuration }
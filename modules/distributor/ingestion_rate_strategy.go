@@ -1,6 +1,8 @@
 package distributor
 
 import (
+	"time"
+
 	"github.com/grafana/dskit/limiter"
 
 	"github.com/grafana/tempo/modules/overrides"
@@ -11,11 +13,21 @@ type ReadLifecycler interface {
 	HealthyInstancesCount() int
 }
 
+// ingestionRateStrategy returns the ingestion rate limits of a tenant in this distributor, the burst class included.
+type ingestionRateStrategy interface {
+	limiter.RateLimiterStrategy
+
+	// BurstLimit returns the rate of the burst class, 0 if the tenant has no burst class.
+	BurstLimit(userID string) float64
+	// BurstDuration returns how long the tenant can ingest at the rate of the burst class.
+	BurstDuration(userID string) time.Duration
+}
+
 type localStrategy struct {
 	limits overrides.Interface
 }
 
-func newLocalIngestionRateStrategy(limits overrides.Interface) limiter.RateLimiterStrategy {
+func newLocalIngestionRateStrategy(limits overrides.Interface) ingestionRateStrategy {
 	return &localStrategy{
 		limits: limits,
 	}
@@ -29,12 +41,20 @@ func (s *localStrategy) Burst(userID string) int {
 	return s.limits.IngestionBurstSizeBytes(userID)
 }
 
+func (s *localStrategy) BurstLimit(userID string) float64 {
+	return s.limits.IngestionBurstRateLimitBytes(userID)
+}
+
+func (s *localStrategy) BurstDuration(userID string) time.Duration {
+	return s.limits.IngestionBurstDuration(userID)
+}
+
 type globalStrategy struct {
 	limits overrides.Interface
 	ring   ReadLifecycler
 }
 
-func newGlobalIngestionRateStrategy(limits overrides.Interface, ring ReadLifecycler) limiter.RateLimiterStrategy {
+func newGlobalIngestionRateStrategy(limits overrides.Interface, ring ReadLifecycler) ingestionRateStrategy {
 	return &globalStrategy{
 		limits: limits,
 		ring:   ring,
@@ -56,3 +76,17 @@ func (s *globalStrategy) Burst(userID string) int {
 	// to keep it easier to understand for users / operators.
 	return s.limits.IngestionBurstSizeBytes(userID)
 }
+
+func (s *globalStrategy) BurstLimit(userID string) float64 {
+	numDistributors := s.ring.HealthyInstancesCount()
+
+	if numDistributors == 0 {
+		return s.limits.IngestionBurstRateLimitBytes(userID)
+	}
+
+	return s.limits.IngestionBurstRateLimitBytes(userID) / float64(numDistributors)
+}
+
+func (s *globalStrategy) BurstDuration(userID string) time.Duration {
+	return s.limits.IngestionBurstDuration(userID)
+}
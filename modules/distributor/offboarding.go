@@ -0,0 +1,80 @@
+package distributor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// tenantOffboarding tracks whether the tenants that push to this distributor are being offboarded. Tenants
+// are checked against their offboarding record in the backend on every poll. A tenant seen for the first time
+// is accepted until the next poll so the backend is never read on the write path.
+type tenantOffboarding struct {
+	services.Service
+
+	reader backend.RawReader
+	logger log.Logger
+
+	mtx     sync.RWMutex
+	tenants map[string]bool // tenant -> accepts writes
+}
+
+func newTenantOffboarding(reader backend.RawReader, interval time.Duration, logger log.Logger) *tenantOffboarding {
+	t := &tenantOffboarding{
+		reader:  reader,
+		logger:  logger,
+		tenants: make(map[string]bool),
+	}
+	t.Service = services.NewTimerService(interval, nil, t.poll, nil).WithName("tenant offboarding")
+
+	return t
+}
+
+func (t *tenantOffboarding) acceptsWrites(tenantID string) bool {
+	t.mtx.RLock()
+	accepts, ok := t.tenants[tenantID]
+	t.mtx.RUnlock()
+
+	if ok {
+		return accepts
+	}
+
+	t.mtx.Lock()
+	if _, ok := t.tenants[tenantID]; !ok {
+		t.tenants[tenantID] = true
+	}
+	t.mtx.Unlock()
+
+	return true
+}
+
+func (t *tenantOffboarding) poll(ctx context.Context) error {
+	t.mtx.RLock()
+	tenants := make([]string, 0, len(t.tenants))
+	for tenantID := range t.tenants {
+		tenants = append(tenants, tenantID)
+	}
+	t.mtx.RUnlock()
+
+	for _, tenantID := range tenants {
+		o, err := backend.ReadTenantOffboarding(ctx, t.reader, tenantID)
+		if err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
+			// keep the previous state, a failed read must not reopen writes for an offboarded tenant
+			level.Warn(t.logger).Log("msg", "failed to read tenant offboarding record", "tenant", tenantID, "err", err)
+			continue
+		}
+
+		t.mtx.Lock()
+		t.tenants[tenantID] = o.AcceptsWrites()
+		t.mtx.Unlock()
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package distributor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+)
+
+func TestTenantOffboardingAcceptsWrites(t *testing.T) {
+	rr, ww, _, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	o := newTenantOffboarding(rr, time.Hour, log.NewNopLogger())
+
+	// unknown tenants are accepted until polled
+	require.True(t, o.acceptsWrites("offboarded"))
+	require.True(t, o.acceptsWrites("active"))
+
+	require.NoError(t, backend.WriteTenantOffboarding(ctx, ww, &backend.TenantOffboarding{
+		TenantID: "offboarded",
+		State:    backend.OffboardingStatePending,
+	}))
+	require.NoError(t, o.poll(ctx))
+
+	require.False(t, o.acceptsWrites("offboarded"))
+	require.True(t, o.acceptsWrites("active"))
+
+	// cancelling reopens writes
+	require.NoError(t, backend.WriteTenantOffboarding(ctx, ww, &backend.TenantOffboarding{
+		TenantID: "offboarded",
+		State:    backend.OffboardingStateCancelled,
+	}))
+	require.NoError(t, o.poll(ctx))
+
+	require.True(t, o.acceptsWrites("offboarded"))
+}
@@ -0,0 +1,140 @@
+package receiver
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/grafana/dskit/tenant"
+	"github.com/grafana/dskit/user"
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/tempo/pkg/util/log"
+)
+
+// CertificateTenant maps the client certificates with a subject alternative name and/or an organizational unit to
+// a tenant.
+type CertificateTenant struct {
+	SAN    string `yaml:"san,omitempty"`
+	OU     string `yaml:"ou,omitempty"`
+	Tenant string `yaml:"tenant"`
+}
+
+// CertificateTenants maps client certificate identities to tenants. The first mapping matching the certificate wins.
+type CertificateTenants []CertificateTenant
+
+// Validate returns an error if a mapping matches every certificate or maps to an invalid tenant ID.
+func (c CertificateTenants) Validate() error {
+	for i, m := range c {
+		if m.SAN == "" && m.OU == "" {
+			return fmt.Errorf("mapping %d must have a san or an ou", i)
+		}
+		if err := tenant.ValidTenantID(m.Tenant); err != nil {
+			return fmt.Errorf("tenant %q of mapping %d: %w", m.Tenant, i, err)
+		}
+	}
+	return nil
+}
+
+// Tenant returns the tenant of the first mapping matching the certificate. A mapping matches if the certificate has
+// its SAN as a DNS name, email address or URI and its OU as organizational unit.
+func (c CertificateTenants) Tenant(cert *x509.Certificate) (string, bool) {
+	for _, m := range c {
+		if m.SAN != "" && !hasSAN(cert, m.SAN) {
+			continue
+		}
+		if m.OU != "" && !slices.Contains(cert.Subject.OrganizationalUnit, m.OU) {
+			continue
+		}
+		return m.Tenant, true
+	}
+	return "", false
+}
+
+func hasSAN(cert *x509.Certificate, san string) bool {
+	if slices.Contains(cert.DNSNames, san) || slices.Contains(cert.EmailAddresses, san) {
+		return true
+	}
+	for _, uri := range cert.URIs {
+		if uri.String() == san {
+			return true
+		}
+	}
+	return false
+}
+
+type certificateTenantMiddleware struct {
+	tenants CertificateTenants
+}
+
+// CertificateTenantMiddleware injects the tenant the verified client certificate of the request maps to as org ID.
+// Requests without a verified client certificate, with a certificate that isn't mapped or with an org ID header of
+// another tenant are refused. Client certificates are only available to gRPC receivers.
+func CertificateTenantMiddleware(tenants CertificateTenants) Middleware {
+	return &certificateTenantMiddleware{tenants: tenants}
+}
+
+func (m *certificateTenantMiddleware) Wrap(next consumer.Traces) consumer.Traces {
+	return ConsumeTracesFunc(func(ctx context.Context, td ptrace.Traces) error {
+		clientAddr := "unknown"
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			clientAddr = p.Addr.String()
+		}
+
+		cert, err := verifiedClientCertificate(ctx)
+		if err != nil {
+			log.Logger.Log("msg", "failed to map client certificate to tenant", "err", err, "client", clientAddr)
+			return status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		tenantID, ok := m.tenants.Tenant(cert)
+		if !ok {
+			log.Logger.Log("msg", "client certificate isn't mapped to a tenant", "subject", cert.Subject.String(), "client", clientAddr)
+			return status.Error(codes.PermissionDenied, "client certificate isn't mapped to a tenant")
+		}
+
+		for _, orgID := range requestOrgIDs(ctx) {
+			if orgID != tenantID {
+				log.Logger.Log("msg", "org id doesn't match the tenant of the client certificate", "orgID", orgID, "tenant", tenantID, "client", clientAddr)
+				return status.Errorf(codes.PermissionDenied, "org id %q doesn't match the tenant of the client certificate", orgID)
+			}
+		}
+
+		return next.ConsumeTraces(user.InjectOrgID(ctx, tenantID), td)
+	})
+}
+
+// verifiedClientCertificate returns the leaf of the first verified chain of the client certificate of a gRPC request.
+// Certificates are only verified if the receiver requires client certificates signed by its client CA.
+func verifiedClientCertificate(ctx context.Context) (*x509.Certificate, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, errors.New("no peer in the request context, client certificates are only available to gRPC receivers")
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil, errors.New("request isn't using TLS")
+	}
+	if len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil, errors.New("request has no verified client certificate")
+	}
+	return info.State.VerifiedChains[0][0], nil
+}
+
+// requestOrgIDs returns the org IDs set in the gRPC metadata or HTTP headers of the request.
+func requestOrgIDs(ctx context.Context) []string {
+	var orgIDs []string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		orgIDs = append(orgIDs, md.Get(user.OrgIDHeaderName)...)
+	}
+	return append(orgIDs, client.FromContext(ctx).Metadata.Get(user.OrgIDHeaderName)...)
+}
@@ -83,3 +83,19 @@ func (m *multiTenancyMiddleware) Wrap(next consumer.Traces) consumer.Traces {
 		return next.ConsumeTraces(ctx, td)
 	})
 }
+
+type tenantAliasMiddleware struct {
+	next    Middleware
+	aliases util.TenantAliases
+}
+
+// TenantAliasMiddleware replaces the aliases in the org ID injected by next with their canonical tenant.
+func TenantAliasMiddleware(next Middleware, aliases util.TenantAliases) Middleware {
+	return &tenantAliasMiddleware{next: next, aliases: aliases}
+}
+
+func (m *tenantAliasMiddleware) Wrap(next consumer.Traces) consumer.Traces {
+	return m.next.Wrap(ConsumeTracesFunc(func(ctx context.Context, td ptrace.Traces) error {
+		return next.ConsumeTraces(m.aliases.ResolveContext(ctx), td)
+	}))
+}
@@ -2,6 +2,10 @@ package receiver
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
 	"testing"
 
 	"github.com/grafana/dskit/user"
@@ -9,7 +13,11 @@ import (
 	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 
 	"github.com/grafana/tempo/pkg/util"
 )
@@ -97,3 +105,118 @@ func TestMultiTenancyMiddleware(t *testing.T) {
 		require.EqualError(t, m.Wrap(consumer).ConsumeTraces(ctx, ptrace.Traces{}), "no org id")
 	})
 }
+
+func TestTenantAliasMiddleware(t *testing.T) {
+	m := TenantAliasMiddleware(MultiTenancyMiddleware(), util.TenantAliases{"old-team": "new-team"})
+
+	for tenantID, expected := range map[string]string{"old-team": "new-team", "new-team": "new-team", "other": "other"} {
+		t.Run(tenantID, func(t *testing.T) {
+			consumer := newAssertingConsumer(t, func(t *testing.T, ctx context.Context) {
+				orgID, err := user.ExtractOrgID(ctx)
+				require.NoError(t, err)
+				require.Equal(t, expected, orgID)
+			})
+
+			ctx := metadata.NewIncomingContext(
+				context.Background(),
+				metadata.Pairs("X-Scope-OrgID", tenantID),
+			)
+			require.NoError(t, m.Wrap(consumer).ConsumeTraces(ctx, ptrace.Traces{}))
+		})
+	}
+}
+
+func TestCertificateTenantMiddleware(t *testing.T) {
+	tenants := CertificateTenants{
+		{SAN: "spiffe://cluster/ns/team-a/sa/collector", Tenant: "team-a"},
+		{SAN: "collector.team-b.svc", OU: "team-b", Tenant: "team-b"},
+		{OU: "team-c", Tenant: "team-c"},
+	}
+	require.NoError(t, tenants.Validate())
+	require.Error(t, CertificateTenants{{Tenant: "team-a"}}.Validate())
+	require.Error(t, CertificateTenants{{OU: "team-a", Tenant: "../team-a"}}.Validate())
+
+	m := CertificateTenantMiddleware(tenants)
+
+	withCert := func(cert *x509.Certificate, verified bool) context.Context {
+		state := tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+		if verified {
+			state.VerifiedChains = [][]*x509.Certificate{{cert}}
+		}
+		return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+	}
+	spiffe, err := url.Parse("spiffe://cluster/ns/team-a/sa/collector")
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+		code     codes.Code
+	}{
+		{
+			name:     "san uri",
+			ctx:      withCert(&x509.Certificate{URIs: []*url.URL{spiffe}}, true),
+			expected: "team-a",
+		},
+		{
+			name:     "san and ou",
+			ctx:      withCert(&x509.Certificate{DNSNames: []string{"collector.team-b.svc"}, Subject: pkix.Name{OrganizationalUnit: []string{"team-b"}}}, true),
+			expected: "team-b",
+		},
+		{
+			name:     "ou",
+			ctx:      withCert(&x509.Certificate{DNSNames: []string{"collector.team-b.svc"}, Subject: pkix.Name{OrganizationalUnit: []string{"team-c"}}}, true),
+			expected: "team-c",
+		},
+		{
+			name:     "matching org id",
+			ctx:      metadata.NewIncomingContext(withCert(&x509.Certificate{URIs: []*url.URL{spiffe}}, true), metadata.Pairs("X-Scope-OrgID", "team-a")),
+			expected: "team-a",
+		},
+		{
+			name: "spoofed org id",
+			ctx:  metadata.NewIncomingContext(withCert(&x509.Certificate{URIs: []*url.URL{spiffe}}, true), metadata.Pairs("X-Scope-OrgID", "team-b")),
+			code: codes.PermissionDenied,
+		},
+		{
+			name: "spoofed org id http",
+			ctx: client.NewContext(withCert(&x509.Certificate{URIs: []*url.URL{spiffe}}, true), client.Info{
+				Metadata: client.NewMetadata(map[string][]string{"X-Scope-OrgID": {"team-b"}}),
+			}),
+			code: codes.PermissionDenied,
+		},
+		{
+			name: "unmapped certificate",
+			ctx:  withCert(&x509.Certificate{DNSNames: []string{"collector.team-b.svc"}}, true),
+			code: codes.PermissionDenied,
+		},
+		{
+			name: "unverified certificate",
+			ctx:  withCert(&x509.Certificate{URIs: []*url.URL{spiffe}}, false),
+			code: codes.Unauthenticated,
+		},
+		{
+			name: "no certificate",
+			ctx:  metadata.NewIncomingContext(context.Background(), metadata.Pairs("X-Scope-OrgID", "team-a")),
+			code: codes.Unauthenticated,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			consumer := newAssertingConsumer(t, func(t *testing.T, ctx context.Context) {
+				orgID, err := user.ExtractOrgID(ctx)
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, orgID)
+			})
+
+			err := m.Wrap(consumer).ConsumeTraces(tc.ctx, ptrace.Traces{})
+			if tc.code == codes.OK {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, tc.code, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package otlpfilereceiver
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+)
+
+// fingerprintSize is the number of bytes at the start of a file used to recognize it after it was renamed, e.g.
+// by the rotation of the file exporter.
+const fingerprintSize = 1024
+
+// fileCheckpoint is the read position of a single file.
+type fileCheckpoint struct {
+	Path           string `json:"path"`
+	Offset         int64  `json:"offset"`
+	FingerprintLen int    `json:"fingerprint_len"`
+	Fingerprint    string `json:"fingerprint"`
+}
+
+// matches returns true if head, the first bytes of a file, starts with the fingerprinted bytes.
+func (c *fileCheckpoint) matches(head []byte) bool {
+	if len(head) < c.FingerprintLen {
+		return false
+	}
+	return fingerprint(head[:c.FingerprintLen]) == c.Fingerprint
+}
+
+// setFingerprint updates the fingerprint to cover the bytes that have been read so far.
+func (c *fileCheckpoint) setFingerprint(head []byte) {
+	n := int(min(c.Offset, int64(len(head))))
+	c.FingerprintLen = n
+	c.Fingerprint = fingerprint(head[:n])
+}
+
+func fingerprint(b []byte) string {
+	h := fnv.New64a()
+	_, _ = h.Write(b)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type checkpoints struct {
+	Files []*fileCheckpoint `json:"files"`
+}
+
+// find returns the checkpoint of the file at path with the given head. A checkpoint of the same path is preferred,
+// otherwise the file may have been renamed and any unclaimed checkpoint with a matching fingerprint is used. A new
+// checkpoint is returned if none match.
+func (c *checkpoints) find(path string, head []byte, claimed map[*fileCheckpoint]struct{}) *fileCheckpoint {
+	for _, f := range c.Files {
+		if _, ok := claimed[f]; !ok && f.Path == path && f.matches(head) {
+			return f
+		}
+	}
+	for _, f := range c.Files {
+		if _, ok := claimed[f]; !ok && f.matches(head) {
+			f.Path = path
+			return f
+		}
+	}
+	return &fileCheckpoint{Path: path}
+}
+
+func loadCheckpoints(path string) (*checkpoints, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &checkpoints{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint %s: %w", path, err)
+	}
+
+	c := &checkpoints{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checkpoint %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// save writes the checkpoints to a temporary file and renames it over path so a crash never leaves a partial file.
+func (c *checkpoints) save(path string) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint %s: %w", tmp, err)
+	}
+	return os.Rename(tmp, path)
+}
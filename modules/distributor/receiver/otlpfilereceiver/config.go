@@ -0,0 +1,111 @@
+package otlpfilereceiver
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// Supported file formats. They match the formats of the OTel collector file exporter.
+const (
+	// FormatJSON is one OTLP/JSON encoded request per line.
+	FormatJSON = "json"
+	// FormatProto is a sequence of OTLP protobuf encoded requests, each prefixed with its length as a 4 byte
+	// big endian integer.
+	FormatProto = "proto"
+)
+
+const (
+	defaultCheckpointName = ".otlpfile.checkpoint"
+	// defaultMaxRecordSize matches the default max message size of gRPC servers
+	defaultMaxRecordSize = 4 << 20
+)
+
+// Config configures the otlpfile receiver.
+type Config struct {
+	// Directory is the directory that is tailed.
+	Directory string `mapstructure:"directory"`
+	// Include are the glob patterns of the files in Directory that are read. Defaults to *.json and *.jsonl for
+	// the json format and *.pb and *.binpb for the proto format.
+	Include []string `mapstructure:"include"`
+	// Format is one of FormatJSON or FormatProto.
+	Format string `mapstructure:"format"`
+	// PollInterval is the time between scans of the directory.
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// CheckpointPath is the file that stores how far each file has been read. Defaults to a file in Directory.
+	CheckpointPath string `mapstructure:"checkpoint_path"`
+	// Tenant is the tenant the traces are ingested for when multitenancy is enabled.
+	Tenant string `mapstructure:"tenant"`
+	// DeleteAfter deletes files that were read completely and were not modified for this long. 0 keeps all files.
+	DeleteAfter time.Duration `mapstructure:"delete_after"`
+	// MaxRecordSize is the size in bytes of the largest record that is read. Larger records are skipped as invalid,
+	// for the proto format with the rest of the file. Defaults to 4 MiB.
+	MaxRecordSize int `mapstructure:"max_record_size"`
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		Format:       FormatJSON,
+		PollInterval: time.Second,
+	}
+}
+
+// Validate implements xconfmap.Validator
+func (c *Config) Validate() error {
+	if c.Directory == "" {
+		return errors.New("directory is required")
+	}
+
+	switch c.Format {
+	case FormatJSON, FormatProto:
+	default:
+		return fmt.Errorf("unknown format %q, must be one of %s or %s", c.Format, FormatJSON, FormatProto)
+	}
+
+	if c.PollInterval <= 0 {
+		return errors.New("poll_interval must be greater than 0")
+	}
+
+	for _, p := range c.Include {
+		if _, err := filepath.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid include pattern %q: %w", p, err)
+		}
+	}
+
+	if c.DeleteAfter < 0 {
+		return errors.New("delete_after must not be negative")
+	}
+
+	if c.MaxRecordSize < 0 {
+		return errors.New("max_record_size must not be negative")
+	}
+
+	return nil
+}
+
+func (c *Config) patterns() []string {
+	if len(c.Include) > 0 {
+		return c.Include
+	}
+	if c.Format == FormatProto {
+		return []string{"*.pb", "*.binpb"}
+	}
+	return []string{"*.json", "*.jsonl"}
+}
+
+func (c *Config) maxRecordSize() int64 {
+	if c.MaxRecordSize > 0 {
+		return int64(c.MaxRecordSize)
+	}
+	return defaultMaxRecordSize
+}
+
+func (c *Config) checkpointPath() string {
+	if c.CheckpointPath != "" {
+		return c.CheckpointPath
+	}
+	return filepath.Join(c.Directory, defaultCheckpointName)
+}
@@ -0,0 +1,26 @@
+// Package otlpfilereceiver tails a directory of OTLP files, as written by the OTel collector file exporter, and
+// ingests them. The read position of each file is checkpointed so files are only ingested once across restarts.
+package otlpfilereceiver
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+)
+
+const typeStr = "otlpfile"
+
+// NewFactory creates a factory for the otlpfile receiver.
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		component.MustNewType(typeStr),
+		createDefaultConfig,
+		receiver.WithTraces(createTraces, component.StabilityLevelAlpha),
+	)
+}
+
+func createTraces(_ context.Context, set receiver.Settings, cfg component.Config, next consumer.Traces) (receiver.Traces, error) {
+	return newFileReceiver(cfg.(*Config), next, set.Logger), nil
+}
@@ -0,0 +1,372 @@
+package otlpfilereceiver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	resultIngested = "ingested"
+	resultInvalid  = "invalid"
+	resultRejected = "rejected"
+)
+
+var metricRecords = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "receiver_otlp_file_records_total",
+	Help:      "Records read by the otlpfile receiver by result.",
+}, []string{"result"})
+
+var (
+	// errIncomplete is returned when the rest of the file does not hold a complete record. It is read again on the
+	// next poll once the writer has finished it.
+	errIncomplete = errors.New("incomplete record")
+	// errRecordTooLarge is returned for records larger than the max record size.
+	errRecordTooLarge = errors.New("record larger than the max record size")
+)
+
+type fileReceiver struct {
+	cfg    *Config
+	next   consumer.Traces
+	logger *zap.Logger
+
+	checkpoints *checkpoints
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+func newFileReceiver(cfg *Config, next consumer.Traces, logger *zap.Logger) *fileReceiver {
+	return &fileReceiver{
+		cfg:    cfg,
+		next:   next,
+		logger: logger,
+	}
+}
+
+// Start implements component.Component
+func (r *fileReceiver) Start(_ context.Context, _ component.Host) error {
+	cp, err := loadCheckpoints(r.cfg.checkpointPath())
+	if err != nil {
+		return err
+	}
+	r.checkpoints = cp
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go r.run(ctx)
+
+	return nil
+}
+
+// Shutdown implements component.Component
+func (r *fileReceiver) Shutdown(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *fileReceiver) run(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll reads all matching files from their checkpoint. Checkpoints of files that no longer exist are dropped.
+func (r *fileReceiver) poll(ctx context.Context) {
+	paths, err := r.files()
+	if err != nil {
+		r.logger.Error("failed to list files", zap.String("directory", r.cfg.Directory), zap.Error(err))
+		return
+	}
+
+	claimed := make(map[*fileCheckpoint]struct{}, len(paths))
+	kept := make([]*fileCheckpoint, 0, len(paths))
+
+	for _, path := range paths {
+		cp, err := r.readFile(ctx, path, claimed)
+		if cp != nil {
+			claimed[cp] = struct{}{}
+			kept = append(kept, cp)
+		}
+		if err != nil {
+			r.logger.Error("failed to read file", zap.String("path", path), zap.Error(err))
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	// files that were not visited because of shutdown keep their checkpoints
+	if ctx.Err() != nil {
+		for _, cp := range r.checkpoints.Files {
+			if _, ok := claimed[cp]; !ok {
+				kept = append(kept, cp)
+			}
+		}
+	}
+
+	r.checkpoints.Files = kept
+	if err := r.checkpoints.save(r.cfg.checkpointPath()); err != nil {
+		r.logger.Error("failed to save checkpoint", zap.Error(err))
+	}
+}
+
+// files returns the files in the directory that match the include patterns, sorted by name so rotated files are
+// read in order.
+func (r *fileReceiver) files() ([]string, error) {
+	checkpoint, err := filepath.Abs(r.cfg.checkpointPath())
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]struct{}{}
+	var paths []string
+	for _, pattern := range r.cfg.patterns() {
+		matches, err := filepath.Glob(filepath.Join(r.cfg.Directory, pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			if abs, err := filepath.Abs(m); err == nil && abs == checkpoint {
+				continue
+			}
+			if _, ok := seen[m]; ok {
+				continue
+			}
+			seen[m] = struct{}{}
+			paths = append(paths, m)
+		}
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// readFile consumes all complete records of the file after its checkpoint and returns the updated checkpoint. A
+// nil checkpoint is returned if the file was deleted. Records that are rejected with a retryable error are read
+// again on the next poll.
+func (r *fileReceiver) readFile(ctx context.Context, path string, claimed map[*fileCheckpoint]struct{}) (*fileCheckpoint, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		return nil, nil
+	}
+	size := fi.Size()
+
+	head := make([]byte, min(size, fingerprintSize))
+	if _, err := io.ReadFull(f, head); err != nil {
+		return nil, err
+	}
+
+	cp := r.checkpoints.find(path, head, claimed)
+	if cp.Offset > size {
+		// the file was truncated and rewritten with the same start
+		cp.Offset = 0
+	}
+	defer cp.setFingerprint(head)
+
+	if cp.Offset < size {
+		if _, err := f.Seek(cp.Offset, io.SeekStart); err != nil {
+			return cp, err
+		}
+		if err := r.readRecords(ctx, bufio.NewReader(io.LimitReader(f, size-cp.Offset)), cp, size); err != nil {
+			return cp, err
+		}
+	}
+
+	if r.cfg.DeleteAfter > 0 && cp.Offset == size && time.Since(fi.ModTime()) > r.cfg.DeleteAfter {
+		if err := os.Remove(path); err != nil {
+			return cp, err
+		}
+		r.logger.Info("deleted file that was read completely", zap.String("path", path))
+		return nil, nil
+	}
+
+	return cp, nil
+}
+
+// readRecords consumes the records of the file of the given size from the checkpoint.
+func (r *fileReceiver) readRecords(ctx context.Context, br *bufio.Reader, cp *fileCheckpoint, size int64) error {
+	for ctx.Err() == nil {
+		record, n, err := r.nextRecord(br)
+		if errors.Is(err, errIncomplete) {
+			return nil
+		}
+		if errors.Is(err, errRecordTooLarge) {
+			metricRecords.WithLabelValues(resultInvalid).Inc()
+			if n < 0 {
+				// the end of the record is unknown, so are the records after it
+				r.logger.Warn("skipping the rest of the file after a record larger than the max record size", zap.String("path", cp.Path), zap.Int64("offset", cp.Offset))
+				cp.Offset = size
+				return nil
+			}
+			r.logger.Warn("skipping record larger than the max record size", zap.String("path", cp.Path), zap.Int64("offset", cp.Offset), zap.Int64("size", n))
+			cp.Offset += n
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if len(record) == 0 {
+			cp.Offset += n
+			continue
+		}
+
+		td, err := r.unmarshal(record)
+		if err != nil {
+			metricRecords.WithLabelValues(resultInvalid).Inc()
+			r.logger.Warn("skipping invalid record", zap.String("path", cp.Path), zap.Int64("offset", cp.Offset), zap.Error(err))
+			cp.Offset += n
+			continue
+		}
+
+		err = r.next.ConsumeTraces(r.context(ctx), td)
+		if err != nil && (ctx.Err() != nil || retryable(err)) {
+			return fmt.Errorf("record at offset %d will be retried: %w", cp.Offset, err)
+		}
+		if err != nil {
+			metricRecords.WithLabelValues(resultRejected).Inc()
+			r.logger.Warn("dropping rejected record", zap.String("path", cp.Path), zap.Int64("offset", cp.Offset), zap.Error(err))
+		} else {
+			metricRecords.WithLabelValues(resultIngested).Inc()
+		}
+		cp.Offset += n
+	}
+	return nil
+}
+
+// nextRecord returns the next encoded record and the number of bytes it took in the file. Records larger than the
+// max record size return errRecordTooLarge with their size in the file, or -1 if it's unknown.
+func (r *fileReceiver) nextRecord(br *bufio.Reader) ([]byte, int64, error) {
+	maxSize := r.cfg.maxRecordSize()
+
+	if r.cfg.Format == FormatProto {
+		var size [4]byte
+		if _, err := io.ReadFull(br, size[:]); err != nil {
+			return nil, 0, incomplete(err)
+		}
+		recordSize := int64(binary.BigEndian.Uint32(size[:]))
+		if recordSize > maxSize {
+			return nil, -1, errRecordTooLarge
+		}
+		record := make([]byte, recordSize)
+		if _, err := io.ReadFull(br, record); err != nil {
+			return nil, 0, incomplete(err)
+		}
+		return record, int64(len(size) + len(record)), nil
+	}
+
+	// lines are read in fragments so a line larger than the max record size isn't buffered
+	var (
+		line []byte
+		n    int64
+	)
+	for {
+		frag, err := br.ReadSlice('\n')
+		n += int64(len(frag))
+		if n <= maxSize {
+			line = append(line, frag...)
+		}
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return nil, 0, incomplete(err)
+		}
+	}
+	if n > maxSize {
+		return nil, n, errRecordTooLarge
+	}
+	return bytes.TrimSpace(line), n, nil
+}
+
+func incomplete(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return errIncomplete
+	}
+	return err
+}
+
+func (r *fileReceiver) unmarshal(record []byte) (ptrace.Traces, error) {
+	if r.cfg.Format == FormatProto {
+		return (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(record)
+	}
+	return (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(record)
+}
+
+// context adds the configured tenant the same way as the org id header of an HTTP request.
+func (r *fileReceiver) context(ctx context.Context) context.Context {
+	if r.cfg.Tenant == "" {
+		return ctx
+	}
+	return client.NewContext(ctx, client.Info{
+		Metadata: client.NewMetadata(map[string][]string{user.OrgIDHeaderName: {r.cfg.Tenant}}),
+	})
+}
+
+// retryable returns true if the error is a gRPC status of a transient failure, e.g. a rate limited push. Other
+// errors will not change on retry and would block the rest of the file.
+func retryable(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+
+	switch s.Code() {
+	case codes.ResourceExhausted, codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
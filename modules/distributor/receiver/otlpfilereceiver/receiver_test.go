@@ -0,0 +1,269 @@
+package otlpfilereceiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFileReceiverJSON(t *testing.T) {
+	dir := t.TempDir()
+	next := &capturingConsumer{}
+	r := newTestReceiver(t, &Config{Directory: dir, Format: FormatJSON, PollInterval: time.Hour}, next)
+
+	path := filepath.Join(dir, "traces.json")
+	appendFile(t, path, jsonRecord(t, "a"), jsonRecord(t, "b"))
+	// a partial line is not read until it is complete
+	partial := jsonRecord(t, "c")
+	appendFile(t, path, partial[:10])
+
+	r.poll(context.Background())
+	require.Equal(t, []string{"a", "b"}, next.take())
+
+	appendFile(t, path, partial[10:], []byte("not json\n"), jsonRecord(t, "d"))
+	r.poll(context.Background())
+	require.Equal(t, []string{"c", "d"}, next.take())
+
+	// a restarted receiver continues from the checkpoint
+	r = newTestReceiver(t, r.cfg, next)
+	appendFile(t, path, jsonRecord(t, "e"))
+	r.poll(context.Background())
+	require.Equal(t, []string{"e"}, next.take())
+}
+
+func TestFileReceiverRotation(t *testing.T) {
+	dir := t.TempDir()
+	next := &capturingConsumer{}
+	r := newTestReceiver(t, &Config{Directory: dir, Format: FormatJSON, PollInterval: time.Hour}, next)
+
+	path := filepath.Join(dir, "traces.json")
+	appendFile(t, path, jsonRecord(t, "a"))
+	r.poll(context.Background())
+	require.Equal(t, []string{"a"}, next.take())
+
+	// the file exporter renames the current file and starts a new one
+	appendFile(t, path, jsonRecord(t, "b"))
+	require.NoError(t, os.Rename(path, filepath.Join(dir, "traces-2024-01-01T00-00-00.000.json")))
+	appendFile(t, path, jsonRecord(t, "c"))
+
+	r.poll(context.Background())
+	require.ElementsMatch(t, []string{"b", "c"}, next.take())
+	require.Len(t, r.checkpoints.Files, 2)
+
+	r.poll(context.Background())
+	require.Empty(t, next.take())
+}
+
+func TestFileReceiverProto(t *testing.T) {
+	dir := t.TempDir()
+	next := &capturingConsumer{}
+	r := newTestReceiver(t, &Config{Directory: dir, Format: FormatProto, PollInterval: time.Hour}, next)
+
+	path := filepath.Join(dir, "traces.pb")
+	partial := protoRecord(t, "b")
+	appendFile(t, path, protoRecord(t, "a"), partial[:6])
+
+	r.poll(context.Background())
+	require.Equal(t, []string{"a"}, next.take())
+
+	appendFile(t, path, partial[6:])
+	r.poll(context.Background())
+	require.Equal(t, []string{"b"}, next.take())
+}
+
+func TestFileReceiverConsumeErrors(t *testing.T) {
+	dir := t.TempDir()
+	next := &capturingConsumer{}
+	r := newTestReceiver(t, &Config{Directory: dir, Format: FormatJSON, PollInterval: time.Hour, Tenant: "tenant"}, next)
+
+	path := filepath.Join(dir, "traces.json")
+	appendFile(t, path, jsonRecord(t, "a"), jsonRecord(t, "b"))
+
+	// retryable errors are read again on the next poll
+	next.err = status.Error(codes.ResourceExhausted, "rate limited")
+	r.poll(context.Background())
+	require.Equal(t, []string{"a"}, next.take())
+
+	next.err = nil
+	r.poll(context.Background())
+	require.Equal(t, []string{"a", "b"}, next.take())
+	require.Equal(t, []string{"tenant", "tenant"}, next.tenants)
+
+	// other errors drop the record
+	for _, err := range []error{
+		status.Error(codes.InvalidArgument, "trace too large"),
+		status.Error(codes.Internal, "internal error"),
+		errors.New("not a status"),
+	} {
+		appendFile(t, path, jsonRecord(t, "c"), jsonRecord(t, "d"))
+		next.err = err
+		r.poll(context.Background())
+		require.Equal(t, []string{"c", "d"}, next.take())
+
+		next.err = nil
+		r.poll(context.Background())
+		require.Empty(t, next.take())
+	}
+}
+
+func TestFileReceiverMaxRecordSize(t *testing.T) {
+	dir := t.TempDir()
+	next := &capturingConsumer{}
+
+	// records larger than the max record size are skipped
+	r := newTestReceiver(t, &Config{Directory: dir, Format: FormatJSON, PollInterval: time.Hour, MaxRecordSize: 1024}, next)
+	large := append(bytes.Repeat([]byte("x"), 8192), '\n')
+	path := filepath.Join(dir, "traces.json")
+	appendFile(t, path, jsonRecord(t, "a"), large, jsonRecord(t, "b"))
+
+	r.poll(context.Background())
+	require.Equal(t, []string{"a", "b"}, next.take())
+
+	// a length prefix larger than the max record size skips the rest of the file
+	r = newTestReceiver(t, &Config{Directory: dir, Format: FormatProto, PollInterval: time.Hour, CheckpointPath: filepath.Join(dir, "proto.checkpoint")}, next)
+	path = filepath.Join(dir, "traces.pb")
+	appendFile(t, path, protoRecord(t, "c"), binary.BigEndian.AppendUint32(nil, defaultMaxRecordSize+1), protoRecord(t, "d"))
+
+	r.poll(context.Background())
+	require.Equal(t, []string{"c"}, next.take())
+
+	// records appended to the file are read after it
+	appendFile(t, path, protoRecord(t, "e"))
+	r.poll(context.Background())
+	require.Equal(t, []string{"e"}, next.take())
+}
+
+func TestFileReceiverDeleteAfter(t *testing.T) {
+	dir := t.TempDir()
+	next := &capturingConsumer{}
+	r := newTestReceiver(t, &Config{Directory: dir, Format: FormatJSON, PollInterval: time.Hour, DeleteAfter: time.Minute}, next)
+
+	complete := filepath.Join(dir, "complete.json")
+	appendFile(t, complete, jsonRecord(t, "a"))
+	partial := filepath.Join(dir, "partial.json")
+	appendFile(t, partial, jsonRecord(t, "b")[:10])
+	recent := filepath.Join(dir, "recent.json")
+	appendFile(t, recent, jsonRecord(t, "c"))
+
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(complete, old, old))
+	require.NoError(t, os.Chtimes(partial, old, old))
+
+	r.poll(context.Background())
+	require.Equal(t, []string{"a", "c"}, next.take())
+
+	require.NoFileExists(t, complete)
+	require.FileExists(t, partial)
+	require.FileExists(t, recent)
+	require.FileExists(t, r.cfg.checkpointPath())
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	require.EqualError(t, cfg.Validate(), "directory is required")
+
+	cfg.Directory = t.TempDir()
+	require.NoError(t, cfg.Validate())
+
+	cfg.Format = "csv"
+	require.EqualError(t, cfg.Validate(), `unknown format "csv", must be one of json or proto`)
+
+	cfg.Format = FormatProto
+	cfg.Include = []string{"["}
+	require.ErrorContains(t, cfg.Validate(), `invalid include pattern "["`)
+
+	cfg.Include = nil
+	cfg.MaxRecordSize = -1
+	require.EqualError(t, cfg.Validate(), "max_record_size must not be negative")
+}
+
+func newTestReceiver(t *testing.T, cfg *Config, next *capturingConsumer) *fileReceiver {
+	r := newFileReceiver(cfg, next, zap.NewNop())
+
+	cp, err := loadCheckpoints(cfg.checkpointPath())
+	require.NoError(t, err)
+	r.checkpoints = cp
+
+	return r
+}
+
+func appendFile(t *testing.T, path string, records ...[]byte) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	defer f.Close()
+
+	for _, r := range records {
+		_, err = f.Write(r)
+		require.NoError(t, err)
+	}
+}
+
+func testTraces(name string) ptrace.Traces {
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "test")
+	s := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	s.SetName(name)
+	s.SetTraceID([16]byte{1})
+	s.SetSpanID([8]byte{1})
+	return td
+}
+
+func jsonRecord(t *testing.T, name string) []byte {
+	b, err := (&ptrace.JSONMarshaler{}).MarshalTraces(testTraces(name))
+	require.NoError(t, err)
+	return append(b, '\n')
+}
+
+func protoRecord(t *testing.T, name string) []byte {
+	b, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(testTraces(name))
+	require.NoError(t, err)
+	return append(binary.BigEndian.AppendUint32(nil, uint32(len(b))), b...)
+}
+
+// capturingConsumer records the name of the first span of every request it receives, including failed ones.
+type capturingConsumer struct {
+	mtx     sync.Mutex
+	names   []string
+	tenants []string
+	err     error
+}
+
+func (c *capturingConsumer) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.names = append(c.names, td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+	if orgIDs := client.FromContext(ctx).Metadata.Get(user.OrgIDHeaderName); len(orgIDs) > 0 && c.err == nil {
+		c.tenants = append(c.tenants, orgIDs...)
+	}
+	return c.err
+}
+
+func (c *capturingConsumer) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+func (c *capturingConsumer) take() []string {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	names := c.names
+	c.names = nil
+	return names
+}
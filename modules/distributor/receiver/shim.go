@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-kit/log/level"
 	dslog "github.com/grafana/dskit/log"
 	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
 	zaplogfmt "github.com/jsternberg/zap-logfmt"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/jaegerreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
@@ -18,6 +21,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter"
@@ -37,6 +41,7 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/durationpb"
 
+	"github.com/grafana/tempo/modules/distributor/receiver/otlpfilereceiver"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/usagestats"
 	"github.com/grafana/tempo/pkg/util/log"
@@ -62,6 +67,7 @@ var (
 	statReceiverZipkin     = usagestats.NewInt("receiver_enabled_zipkin")
 	statReceiverOpencensus = usagestats.NewInt("receiver_enabled_opencensus")
 	statReceiverKafka      = usagestats.NewInt("receiver_enabled_kafka")
+	statReceiverOtlpFile   = usagestats.NewInt("receiver_enabled_otlpfile")
 )
 
 var tracer = otel.Tracer("modules/distributor/receiver")
@@ -82,7 +88,8 @@ func (r *RetryableError) Error() string {
 // wrapErrorIfRetryable wraps the passed in error to meet expectations of the otel collector exporter code:
 // https://github.com/open-telemetry/opentelemetry-collector/blob/d7b49df5d9e922df6ce56ad4b64ee1c79f9dbdbe/exporter/otlpexporter/otlp.go#L172
 // The otel collector considers some errors retryable and other not. "ResourceExhausted" is special in that it requires a
-// RetryInfo detail along with the error code
+// RetryInfo detail along with the error code. The RetryInfo of an error that already has one, like the errors of the
+// distributor rate limiter, is kept as it's more precise than the configured duration.
 func wrapErrorIfRetryable(err error, dur *durationpb.Duration) error {
 	if dur == nil {
 		return err
@@ -97,6 +104,12 @@ func wrapErrorIfRetryable(err error, dur *durationpb.Duration) error {
 		return err
 	}
 
+	for _, detail := range s.Details() {
+		if _, ok := detail.(*errdetails.RetryInfo); ok {
+			return err
+		}
+	}
+
 	// ignore error. code only errors if Code() == ok
 	s, _ = s.WithDetails(&errdetails.RetryInfo{
 		RetryDelay: dur,
@@ -169,6 +182,7 @@ func New(receiverCfg map[string]interface{}, pusher TracesPusher, middleware Mid
 		opencensusreceiver.NewFactory(),
 		otlpreceiver.NewFactory(),
 		kafkareceiver.NewFactory(),
+		otlpfilereceiver.NewFactory(),
 	)
 	if err != nil {
 		return nil, err
@@ -186,6 +200,8 @@ func New(receiverCfg map[string]interface{}, pusher TracesPusher, middleware Mid
 			statReceiverOpencensus.Set(1)
 		case "kafka":
 			statReceiverKafka.Set(1)
+		case "otlpfile":
+			statReceiverOtlpFile.Set(1)
 		}
 	}
 
@@ -257,6 +273,9 @@ func New(receiverCfg map[string]interface{}, pusher TracesPusher, middleware Mid
 
 			if otlpRecvCfg.HTTP.HasValue() {
 				otlpRecvCfg.HTTP.Get().ServerConfig.IncludeMetadata = true
+				if cors := &otlpRecvCfg.HTTP.Get().ServerConfig.CORS; cors.HasValue() {
+					allowCORSHeaders(cors.Get())
+				}
 				cfg = otlpRecvCfg
 			}
 
@@ -306,6 +325,27 @@ func (r *receiversShim) running(ctx context.Context) error {
 	}
 }
 
+// browserHeaders are the request headers a browser sends to push OTLP/JSON traces: the content type, the tenant and,
+// for compressed payloads, the content encoding. They aren't CORS safelisted, so they must be allowed for preflight
+// requests to pass.
+var browserHeaders = []string{"Content-Type", user.OrgIDHeaderName, "Content-Encoding"}
+
+// allowCORSHeaders adds the browser headers to the allowed headers of a CORS config with allowed origins. The CORS
+// handler only allows its default headers if none are configured, so they are kept.
+func allowCORSHeaders(cors *confighttp.CORSConfig) {
+	if len(cors.AllowedOrigins) == 0 || slices.Contains(cors.AllowedHeaders, "*") {
+		return
+	}
+	if len(cors.AllowedHeaders) == 0 {
+		cors.AllowedHeaders = []string{"Accept", "X-Requested-With"}
+	}
+	for _, h := range browserHeaders {
+		if !slices.ContainsFunc(cors.AllowedHeaders, func(allowed string) bool { return strings.EqualFold(allowed, h) }) {
+			cors.AllowedHeaders = append(cors.AllowedHeaders, h)
+		}
+	}
+}
+
 func (r *receiversShim) starting(ctx context.Context) error {
 	for _, receiver := range r.receivers {
 		err := receiver.Start(ctx, r)
@@ -1,14 +1,21 @@
 package receiver
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	dslog "github.com/grafana/dskit/log"
 	"github.com/grafana/dskit/services"
+	"github.com/klauspost/compress/zstd"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
@@ -147,6 +154,131 @@ func TestShim_integration(t *testing.T) {
 	}
 }
 
+// TestShim_otlpJSONOverHTTP pushes OTLP/JSON the way a browser or serverless client does, without an exporter
+func TestShim_otlpJSONOverHTTP(t *testing.T) {
+	randomTraces := testdata.GenerateTraces(5)
+	payload, err := (&ptrace.JSONMarshaler{}).MarshalTraces(randomTraces)
+	require.NoError(t, err)
+
+	receiverCfg := map[string]interface{}{
+		"otlp": map[string]interface{}{
+			"protocols": map[string]interface{}{
+				"http": map[string]interface{}{
+					"cors": map[string]interface{}{
+						"allowed_origins": []string{"https://app.example.com"},
+					},
+				},
+			},
+		},
+	}
+
+	pusher := &capturingPusher{t: t}
+	stopShim := runReceiverShim(t, receiverCfg, pusher, prometheus.NewPedanticRegistry())
+	defer stopShim()
+
+	compress := map[string]func([]byte) []byte{
+		"": func(b []byte) []byte { return b },
+		"gzip": func(b []byte) []byte {
+			buf := &bytes.Buffer{}
+			w := gzip.NewWriter(buf)
+			_, err := w.Write(b)
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+			return buf.Bytes()
+		},
+		"zstd": func(b []byte) []byte {
+			w, err := zstd.NewWriter(nil)
+			require.NoError(t, err)
+			defer w.Close()
+			return w.EncodeAll(b, nil)
+		},
+	}
+
+	for encoding, fn := range compress {
+		t.Run("content-encoding "+encoding, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:4318/v1/traces", bytes.NewReader(fn(payload)))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(generator.NoGenerateMetricsContextKey, "true")
+			if encoding != "" {
+				req.Header.Set("Content-Encoding", encoding)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+			receivedTraces := pusher.GetAndClearTraces()
+			require.Len(t, receivedTraces, 1)
+			assert.Equal(t, randomTraces, receivedTraces[0])
+		})
+	}
+
+	t.Run("cors preflight", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodOptions, "http://127.0.0.1:4318/v1/traces", nil)
+		require.NoError(t, err)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "content-encoding,content-type,x-scope-orgid")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		require.Equal(t, "https://app.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+		require.Equal(t, "content-encoding,content-type,x-scope-orgid", resp.Header.Get("Access-Control-Allow-Headers"))
+	})
+}
+
+func TestAllowCORSHeaders(t *testing.T) {
+	cors := &confighttp.CORSConfig{AllowedHeaders: []string{"x-scope-orgid"}}
+	allowCORSHeaders(cors)
+	require.Equal(t, []string{"x-scope-orgid"}, cors.AllowedHeaders)
+
+	cors.AllowedOrigins = []string{"*"}
+	allowCORSHeaders(cors)
+	require.Equal(t, []string{"x-scope-orgid", "Content-Type", "Content-Encoding"}, cors.AllowedHeaders)
+
+	cors = &confighttp.CORSConfig{AllowedOrigins: []string{"*"}}
+	allowCORSHeaders(cors)
+	require.Equal(t, []string{"Accept", "X-Requested-With", "Content-Type", "X-Scope-OrgID", "Content-Encoding"}, cors.AllowedHeaders)
+
+	cors = &confighttp.CORSConfig{AllowedOrigins: []string{"*"}, AllowedHeaders: []string{"*"}}
+	allowCORSHeaders(cors)
+	require.Equal(t, []string{"*"}, cors.AllowedHeaders)
+}
+
+func TestShim_otlpFile(t *testing.T) {
+	dir := t.TempDir()
+
+	td := testdata.GenerateTraces(5)
+	b, err := (&ptrace.JSONMarshaler{}).MarshalTraces(td)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "traces.json"), append(b, '\n'), 0o644))
+
+	pusher := &countingPusher{}
+	stopShim := runReceiverShim(t, map[string]interface{}{
+		"otlpfile": map[string]interface{}{
+			"directory":     dir,
+			"poll_interval": "10ms",
+		},
+	}, pusher, prometheus.NewPedanticRegistry())
+	defer stopShim()
+
+	require.Eventually(t, func() bool { return pusher.spans.Load() == 5 }, 5*time.Second, 10*time.Millisecond)
+}
+
+type countingPusher struct {
+	spans atomic.Int64
+}
+
+func (p *countingPusher) PushTraces(_ context.Context, t ptrace.Traces) (*tempopb.PushResponse, error) {
+	p.spans.Add(int64(t.SpanCount()))
+	return &tempopb.PushResponse{}, nil
+}
+
 func runReceiverShim(t *testing.T, receiverCfg map[string]interface{}, pusher TracesPusher, reg prometheus.Registerer) func() {
 	level := dslog.Level{}
 	_ = level.Set("info")
@@ -247,6 +379,14 @@ func TestWrapRetryableError(t *testing.T) {
 	wrapped = wrapErrorIfRetryable(err, durationpb.New(time.Second))
 	require.NotEqual(t, err, wrapped)
 	require.True(t, isRetryable(wrapped))
+
+	// no wrapping b/c the error already has a retry info
+	st, err := status.New(codes.ResourceExhausted, "res exhausted").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Millisecond)})
+	require.NoError(t, err)
+	err = st.Err()
+	wrapped = wrapErrorIfRetryable(err, durationpb.New(time.Second))
+	require.Equal(t, err, wrapped)
+	require.True(t, isRetryable(wrapped))
 }
 
 func isRetryable(err error) bool {
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/segmentio/fasthash/fnv1a"
+
 	"github.com/grafana/tempo/tempodb/backend"
 )
 
@@ -15,8 +17,39 @@ const (
 	cacheKeyPrefixQueryRange      = "qr:"
 )
 
+// searchJobCacheKey returns the cache key of a backend search job. backend blocks are immutable so the results of a
+// job only depend on the query, the pages of the block searched and the part of the search range overlapping the
+// block. unlike other jobs a search job is cached when the search range only partially covers the block, the key then
+// includes the search start and/or end inside the block so repeated searches over different ranges share the jobs of
+// blocks they overlap the same way.
 func searchJobCacheKey(tenant string, queryHash uint64, start, end time.Time, meta *backend.BlockMeta, startPage, pagesToSearch int) string {
-	return cacheKey(cacheKeyPrefixSearchJob, tenant, queryHash, start, end, meta, startPage, pagesToSearch)
+	// if the search range doesn't overlap the block there is nothing to cache
+	if !start.Before(meta.EndTime) || !end.After(meta.StartTime) {
+		return ""
+	}
+
+	key := blockCacheKey(cacheKeyPrefixSearchJob, tenant, queryHash, meta, startPage, pagesToSearch)
+	if key == "" {
+		return ""
+	}
+
+	// the traces of the block are all inside the block range so a search start before the block or a search end after it
+	// matches the same traces as the block start or end and is left out of the key
+	startInBlock := !start.Before(meta.StartTime)
+	endInBlock := !end.After(meta.EndTime)
+	if startInBlock || endInBlock {
+		key += ":" + unixInBlock(start, startInBlock) + "-" + unixInBlock(end, endInBlock)
+	}
+
+	return key
+}
+
+// unixInBlock returns the unix seconds of a search bound inside a block or an empty string if it's outside the block.
+func unixInBlock(t time.Time, inBlock bool) string {
+	if !inBlock {
+		return ""
+	}
+	return strconv.FormatInt(t.Unix(), 10)
 }
 
 func queryRangeCacheKey(tenant string, queryHash uint64, start, end time.Time, meta *backend.BlockMeta, startPage, pagesToSearch int) string {
@@ -24,13 +57,9 @@ func queryRangeCacheKey(tenant string, queryHash uint64, start, end time.Time, m
 }
 
 // cacheKey returns a string that can be used as a cache key for a backend search job. if a valid key cannot be calculated
-// it returns an empty string.
+// it returns an empty string. the key includes a hash of the block meta so the cached results of a block are invalidated
+// if the blocklist returns a different meta for the same block id.
 func cacheKey(prefix string, tenant string, queryHash uint64, start, end time.Time, meta *backend.BlockMeta, startPage, pagesToSearch int) string {
-	// if the query hash is 0 we can't cache. this may occur if the user is using the old search api
-	if queryHash == 0 {
-		return ""
-	}
-
 	// unless the search range completely encapsulates the block range we can't cache. this is b/c different search ranges will return different results
 	// for a given block unless the search range covers the entire block
 	if !(start.Before(meta.StartTime) && // search start is before block start
@@ -38,6 +67,16 @@ func cacheKey(prefix string, tenant string, queryHash uint64, start, end time.Ti
 		return ""
 	}
 
+	return blockCacheKey(prefix, tenant, queryHash, meta, startPage, pagesToSearch)
+}
+
+// blockCacheKey returns the cache key of a job searching the given pages of a block regardless of the search range.
+func blockCacheKey(prefix string, tenant string, queryHash uint64, meta *backend.BlockMeta, startPage, pagesToSearch int) string {
+	// if the query hash is 0 we can't cache. this may occur if the user is using the old search api
+	if queryHash == 0 {
+		return ""
+	}
+
 	sb := strings.Builder{}
 	sb.Grow(len(prefix) +
 		len(tenant) +
@@ -46,6 +85,8 @@ func cacheKey(prefix string, tenant string, queryHash uint64, start, end time.Ti
 		1 + // :
 		36 + // block id
 		1 + // :
+		16 + // block meta hash
+		1 + // :
 		3 + // start page
 		1 + // :
 		2) // 2 for pages to search
@@ -56,9 +97,25 @@ func cacheKey(prefix string, tenant string, queryHash uint64, start, end time.Ti
 	sb.WriteString(":")
 	sb.WriteString(meta.BlockID.String())
 	sb.WriteString(":")
+	sb.WriteString(strconv.FormatUint(hashForBlockMeta(meta), 16))
+	sb.WriteString(":")
 	sb.WriteString(strconv.Itoa(startPage))
 	sb.WriteString(":")
 	sb.WriteString(strconv.Itoa(pagesToSearch))
 
 	return sb.String()
 }
+
+// hashForBlockMeta hashes the fields of the block meta that change if the block is rewritten
+func hashForBlockMeta(meta *backend.BlockMeta) uint64 {
+	hash := fnv1a.HashString64(meta.Version)
+	hash = fnv1a.AddString64(hash, meta.DataEncoding)
+	hash = fnv1a.AddUint64(hash, meta.Size_)
+	hash = fnv1a.AddUint64(hash, uint64(meta.TotalObjects))
+	hash = fnv1a.AddUint64(hash, uint64(meta.TotalRecords))
+	hash = fnv1a.AddUint64(hash, uint64(meta.FooterSize))
+	hash = fnv1a.AddUint64(hash, uint64(meta.StartTime.UnixNano()))
+	hash = fnv1a.AddUint64(hash, uint64(meta.EndTime.UnixNano()))
+
+	return hash
+}
@@ -36,7 +36,7 @@ func TestCacheKeyForJob(t *testing.T) {
 			},
 			searchPage:    1,
 			pagesToSearch: 2,
-			expected:      "sj:foo:42:00000000-0000-0000-0000-000000000123:1:2",
+			expected:      "sj:foo:42:00000000-0000-0000-0000-000000000123:467b1a00011ea443:1:2",
 		},
 		{
 			name:      "no query hash means no query cache",
@@ -72,6 +72,7 @@ func TestCacheKeyForJob(t *testing.T) {
 		},
 		{
 			name:      "meta overlaps search start",
+			tenant:    "foo",
 			queryHash: 42,
 			req: &tempopb.SearchRequest{
 				Start: 10,
@@ -84,10 +85,11 @@ func TestCacheKeyForJob(t *testing.T) {
 			},
 			searchPage:    1,
 			pagesToSearch: 2,
-			expected:      "",
+			expected:      "sj:foo:42:00000000-0000-0000-0000-000000000123:9f3a5687f9ef5d23:1:2:10-",
 		},
 		{
 			name:      "meta overlaps search end",
+			tenant:    "foo",
 			queryHash: 42,
 			req: &tempopb.SearchRequest{
 				Start: 10,
@@ -100,7 +102,7 @@ func TestCacheKeyForJob(t *testing.T) {
 			},
 			searchPage:    1,
 			pagesToSearch: 2,
-			expected:      "",
+			expected:      "sj:foo:42:00000000-0000-0000-0000-000000000123:6b4591de166ce48f:1:2:-20",
 		},
 		{
 			name:      "meta after search range",
@@ -120,6 +122,7 @@ func TestCacheKeyForJob(t *testing.T) {
 		},
 		{
 			name:      "meta encapsulates search range",
+			tenant:    "foo",
 			queryHash: 42,
 			req: &tempopb.SearchRequest{
 				Start: 10,
@@ -132,7 +135,7 @@ func TestCacheKeyForJob(t *testing.T) {
 			},
 			searchPage:    1,
 			pagesToSearch: 2,
-			expected:      "",
+			expected:      "sj:foo:42:00000000-0000-0000-0000-000000000123:af3ff69f473a20ce:1:2:10-20",
 		},
 	}
 
@@ -147,6 +150,51 @@ func TestCacheKeyForJob(t *testing.T) {
 	}
 }
 
+func TestCacheKeyForJobChangesWithBlockMeta(t *testing.T) {
+	meta := &backend.BlockMeta{
+		BlockID:      backend.MustParse("00000000-0000-0000-0000-000000000123"),
+		StartTime:    time.Unix(15, 0),
+		EndTime:      time.Unix(16, 0),
+		Size_:        1000,
+		TotalRecords: 10,
+	}
+	startTime, endTime := time.Unix(10, 0), time.Unix(20, 0)
+
+	key := searchJobCacheKey("foo", 42, startTime, endTime, meta, 1, 2)
+	require.NotEmpty(t, key)
+	require.Equal(t, key, searchJobCacheKey("foo", 42, startTime, endTime, meta, 1, 2))
+
+	// the same block id with a rewritten block gets a new key
+	rewritten := *meta
+	rewritten.Size_ = 2000
+	require.NotEqual(t, key, searchJobCacheKey("foo", 42, startTime, endTime, &rewritten, 1, 2))
+
+	rewritten = *meta
+	rewritten.Version = "vParquet4"
+	require.NotEqual(t, key, searchJobCacheKey("foo", 42, startTime, endTime, &rewritten, 1, 2))
+}
+
+func TestCacheKeyForJobSharedAcrossRanges(t *testing.T) {
+	meta := &backend.BlockMeta{
+		BlockID:   backend.MustParse("00000000-0000-0000-0000-000000000123"),
+		StartTime: time.Unix(15, 0),
+		EndTime:   time.Unix(25, 0),
+	}
+
+	// searches starting before the block share the key of the block start
+	key := searchJobCacheKey("foo", 42, time.Unix(10, 0), time.Unix(20, 0), meta, 1, 2)
+	require.NotEmpty(t, key)
+	require.Equal(t, key, searchJobCacheKey("foo", 42, time.Unix(5, 0), time.Unix(20, 0), meta, 1, 2))
+
+	// but not searches ending elsewhere in the block
+	require.NotEqual(t, key, searchJobCacheKey("foo", 42, time.Unix(10, 0), time.Unix(21, 0), meta, 1, 2))
+
+	// searches covering the block share its key regardless of their range
+	require.Equal(t,
+		searchJobCacheKey("foo", 42, time.Unix(10, 0), time.Unix(30, 0), meta, 1, 2),
+		searchJobCacheKey("foo", 42, time.Unix(0, 0), time.Unix(100, 0), meta, 1, 2))
+}
+
 func BenchmarkCacheKeyForJob(b *testing.B) {
 	req := &tempopb.SearchRequest{
 		Start: 10,
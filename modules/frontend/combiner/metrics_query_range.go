@@ -28,7 +28,9 @@ func NewQueryRange(req *tempopb.QueryRangeRequest, maxSeriesLimit int) (Combiner
 	}
 
 	var prevResp *tempopb.QueryRangeResponse
+	var warnings []*tempopb.QueryWarning
 	maxSeriesReachedErrorMsg := fmt.Sprintf("Response exceeds maximum series limit of %d, a partial response is returned. Warning: the accuracy of each individual value is not guaranteed.", maxSeries)
+	maxSeriesReachedWarning := tempopb.NewQueryWarning(tempopb.WarningResultsTruncated, fmt.Sprintf("Response exceeds maximum series limit of %d", maxSeries))
 
 	metricsCombiner := NewQueryRangeMetricsCombiner()
 	c := &genericCombiner[*tempopb.QueryRangeResponse]{
@@ -38,6 +40,7 @@ func NewQueryRange(req *tempopb.QueryRangeRequest, maxSeriesLimit int) (Combiner
 		combine: func(partial *tempopb.QueryRangeResponse, _ *tempopb.QueryRangeResponse, resp PipelineResponse) error {
 			combiner.Combine(partial)
 			metricsCombiner.Combine(partial.Metrics, resp)
+			warnings = tempopb.AppendWarnings(warnings, partial.Warnings...)
 			return nil
 		},
 		finalize: func(_ *tempopb.QueryRangeResponse) (*tempopb.QueryRangeResponse, error) {
@@ -47,12 +50,14 @@ func NewQueryRange(req *tempopb.QueryRangeRequest, maxSeriesLimit int) (Combiner
 			}
 
 			sortResponse(resp)
+			resp.Warnings = tempopb.WithoutWarning(warnings, tempopb.WarningArchivePending)
 			if combiner.MaxSeriesReached() {
 				// Truncating the final response because even if we bail as soon as len(resp.Series) >= maxSeries
 				// it's possible that the last response pushed us over the max series limit.
 				resp.Series = resp.Series[:maxSeries]
 				resp.Status = tempopb.PartialStatus_PARTIAL
 				resp.Message = maxSeriesReachedErrorMsg
+				resp.Warnings = tempopb.AppendWarnings(resp.Warnings, maxSeriesReachedWarning)
 			}
 			attachExemplars(req, resp)
 			resp.Metrics = metricsCombiner.Metrics
@@ -77,9 +82,16 @@ func NewQueryRange(req *tempopb.QueryRangeRequest, maxSeriesLimit int) (Combiner
 			// store resp for next diff
 			prevResp = resp
 
+			// warnings are copied, like the metrics they aren't diffed
+			diff.Warnings = tempopb.AppendWarnings(nil, warnings...)
+			// the results are preliminary until the jobs of the archive tier completed
+			if metricsCombiner.Metrics.CompletedJobs >= metricsCombiner.Metrics.TotalJobs {
+				diff.Warnings = tempopb.WithoutWarning(diff.Warnings, tempopb.WarningArchivePending)
+			}
 			if combiner.MaxSeriesReached() {
 				diff.Status = tempopb.PartialStatus_PARTIAL
 				diff.Message = maxSeriesReachedErrorMsg
+				diff.Warnings = tempopb.AppendWarnings(diff.Warnings, maxSeriesReachedWarning)
 			}
 			diff.Metrics = metricsCombiner.Metrics
 			return diff, nil
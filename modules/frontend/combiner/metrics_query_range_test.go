@@ -617,6 +617,109 @@ func TestQueryRangemaxSeriesShouldQuit(t *testing.T) {
 	err = queryRangeCombiner.AddResponse(toHTTPResponse(t, secondResp, 200))
 	require.NoError(t, err)
 	require.True(t, queryRangeCombiner.ShouldQuit())
+
+	// the truncated series are a warning of the final response
+	final, err := queryRangeCombiner.HTTPFinal()
+	require.NoError(t, err)
+	actual := &tempopb.QueryRangeResponse{}
+	fromHTTPResponse(t, final, actual)
+	require.Equal(t, tempopb.PartialStatus_PARTIAL, actual.Status)
+	require.Len(t, actual.Warnings, 1)
+	require.Equal(t, tempopb.WarningResultsTruncated, actual.Warnings[0].Code)
+}
+
+func TestQueryRangeCombinesWarnings(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Query: "{} | rate()",
+		Start: uint64(1100 * time.Second),
+		End:   uint64(1300 * time.Second),
+		Step:  uint64(10 * time.Second),
+	}
+
+	c, err := NewTypedQueryRange(req, 0)
+	require.NoError(t, err)
+
+	skipped := tempopb.NewQueryWarning(tempopb.WarningBlocksSkipped, "skipped")
+	stale := tempopb.NewQueryWarning(tempopb.WarningStaleBlocklist, "stale")
+	for _, warnings := range [][]*tempopb.QueryWarning{{skipped}, {skipped, stale}, nil} {
+		err = c.AddResponse(toHTTPResponse(t, &tempopb.QueryRangeResponse{Metrics: &tempopb.SearchMetrics{}, Warnings: warnings}, 200))
+		require.NoError(t, err)
+	}
+
+	diff, err := c.GRPCDiff()
+	require.NoError(t, err)
+	require.Equal(t, []*tempopb.QueryWarning{skipped, stale}, diff.Warnings)
+
+	final, err := c.GRPCFinal()
+	require.NoError(t, err)
+	require.Equal(t, []*tempopb.QueryWarning{skipped, stale}, final.Warnings)
+}
+
+func TestQueryRangeArchivePending(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Query: "{} | rate()",
+		Start: uint64(1100 * time.Second),
+		End:   uint64(1300 * time.Second),
+		Step:  uint64(10 * time.Second),
+	}
+
+	c, err := NewTypedQueryRange(req, 0)
+	require.NoError(t, err)
+
+	pending := tempopb.NewQueryWarning(tempopb.WarningArchivePending, "pending")
+	require.NoError(t, c.AddResponse(toHTTPResponse(t, &tempopb.QueryRangeResponse{Metrics: &tempopb.SearchMetrics{TotalJobs: 2}, Warnings: []*tempopb.QueryWarning{pending}}, 200)))
+	require.NoError(t, c.AddResponse(toHTTPResponse(t, &tempopb.QueryRangeResponse{Metrics: &tempopb.SearchMetrics{}}, 200)))
+
+	diff, err := c.GRPCDiff()
+	require.NoError(t, err)
+	require.Equal(t, []*tempopb.QueryWarning{pending}, diff.Warnings)
+
+	require.NoError(t, c.AddResponse(toHTTPResponse(t, &tempopb.QueryRangeResponse{Metrics: &tempopb.SearchMetrics{}}, 200)))
+
+	diff, err = c.GRPCDiff()
+	require.NoError(t, err)
+	require.Empty(t, diff.Warnings)
+
+	final, err := c.GRPCFinal()
+	require.NoError(t, err)
+	require.Empty(t, final.Warnings)
+}
+
+func TestQueryRangeMergesExemplarsOfSpan(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Query:     "{} | rate()",
+		Start:     uint64(1100 * time.Second),
+		End:       uint64(1300 * time.Second),
+		Step:      uint64(10 * time.Second),
+		Exemplars: 10,
+	}
+
+	c, err := NewTypedQueryRange(req, 0)
+	require.NoError(t, err)
+
+	exemplar := tempopb.Exemplar{
+		Labels:                []v1.KeyValue{{Key: "trace:id", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "1234"}}}},
+		Value:                 1,
+		TimestampMs:           1200_000,
+		SpanStartTimeUnixNano: uint64(1200 * time.Second),
+		SpanDurationNanos:     uint64(time.Second),
+	}
+
+	// the same span found by two jobs
+	for range 2 {
+		resp := &tempopb.QueryRangeResponse{
+			Metrics: &tempopb.SearchMetrics{},
+			Series:  []*tempopb.TimeSeries{ts([]tempopb.Sample{{TimestampMs: 1200_000, Value: 1}}, []tempopb.Exemplar{exemplar}, "foo", "bar")},
+		}
+		require.NoError(t, c.AddResponse(toHTTPResponse(t, resp, 200)))
+	}
+
+	final, err := c.GRPCFinal()
+	require.NoError(t, err)
+	require.Len(t, final.Series, 1)
+	require.Len(t, final.Series[0].Exemplars, 1)
+	require.Equal(t, exemplar.SpanStartTimeUnixNano, final.Series[0].Exemplars[0].SpanStartTimeUnixNano)
+	require.Equal(t, exemplar.SpanDurationNanos, final.Series[0].Exemplars[0].SpanDurationNanos)
 }
 
 func BenchmarkDiffSeriesAndMarshal(b *testing.B) {
@@ -19,9 +19,11 @@ func NewSearchMetricsCombiner() *SearchMetricsCombiner {
 func (mc *SearchMetricsCombiner) Combine(newMetrics *tempopb.SearchMetrics, resp PipelineResponse) {
 	if newMetrics != nil {
 		mc.Metrics.CompletedJobs++
+		mc.Metrics.Estimated = mc.Metrics.Estimated || newMetrics.Estimated
 		if !IsCacheHit(resp.HTTPResponse()) {
 			mc.Metrics.InspectedTraces += newMetrics.InspectedTraces
 			mc.Metrics.InspectedBytes += newMetrics.InspectedBytes
+			addInspectedBytesByRole(mc.Metrics, newMetrics)
 		}
 	}
 }
@@ -88,6 +90,7 @@ func (mc *QueryRangeMetricsCombiner) Combine(newMetrics *tempopb.SearchMetrics,
 			newMetrics.CompletedJobs = 1
 			mc.Metrics.CompletedJobs += newMetrics.CompletedJobs
 		}
+		mc.Metrics.Estimated = mc.Metrics.Estimated || newMetrics.Estimated
 		if !IsCacheHit(resp.HTTPResponse()) {
 			mc.Metrics.TotalJobs += newMetrics.TotalJobs
 			mc.Metrics.TotalBlocks += newMetrics.TotalBlocks
@@ -95,6 +98,14 @@ func (mc *QueryRangeMetricsCombiner) Combine(newMetrics *tempopb.SearchMetrics,
 			mc.Metrics.InspectedBytes += newMetrics.InspectedBytes
 			mc.Metrics.InspectedTraces += newMetrics.InspectedTraces
 			mc.Metrics.InspectedSpans += newMetrics.InspectedSpans
+			addInspectedBytesByRole(mc.Metrics, newMetrics)
 		}
 	}
 }
+
+// addInspectedBytesByRole accumulates the inspected bytes broken down by the role of the objects read
+func addInspectedBytesByRole(metrics, newMetrics *tempopb.SearchMetrics) {
+	metrics.InspectedBloomBytes += newMetrics.InspectedBloomBytes
+	metrics.InspectedIndexBytes += newMetrics.InspectedIndexBytes
+	metrics.InspectedColumnBytes += newMetrics.InspectedColumnBytes
+}
@@ -21,6 +21,8 @@ type SearchJobResponse struct {
 	TotalJobs   int
 	TotalBytes  uint64
 	Shards      []SearchShards
+	// Warnings are the caveats of the search known while sharding it, like a stale blocklist
+	Warnings []*tempopb.QueryWarning
 }
 
 func (s *SearchJobResponse) HTTPResponse() *http.Response {
@@ -39,7 +41,16 @@ var _ GRPCCombiner[*tempopb.SearchResponse] = (*genericCombiner[*tempopb.SearchR
 
 // NewSearch returns a search combiner
 func NewSearch(limit int, keepMostRecent bool) Combiner {
-	metadataCombiner := traceql.NewMetadataCombiner(limit, keepMostRecent)
+	return NewOrderedSearch(limit, nil, keepMostRecent)
+}
+
+// NewOrderedSearch returns a search combiner that keeps the first limit traces in the given order. Ordered
+// results are only known once all jobs are complete and they aren't tracked by shard like the most recent ones.
+func NewOrderedSearch(limit int, order *traceql.SearchOrder, keepMostRecent bool) Combiner {
+	if order != nil {
+		keepMostRecent = false
+	}
+	metadataCombiner := traceql.NewOrderedMetadataCombiner(limit, order, keepMostRecent)
 	diffTraces := map[string]struct{}{}
 	completedThroughTracker := &ShardCompletionTracker{}
 	metricsCombiner := NewSearchMetricsCombiner()
@@ -62,6 +73,7 @@ func NewSearch(limit int, keepMostRecent bool) Combiner {
 			}
 
 			metricsCombiner.Combine(partial.Metrics, resp)
+			final.Warnings = tempopb.AppendWarnings(final.Warnings, partial.Warnings...)
 
 			return nil
 		},
@@ -73,6 +85,7 @@ func NewSearch(limit int, keepMostRecent bool) Combiner {
 					TotalBlockBytes: sj.TotalBytes,
 				}
 				metricsCombiner.CombineMetadata(sjMetrics, resp)
+				final.Warnings = tempopb.AppendWarnings(final.Warnings, sj.Warnings...)
 
 				if keepMostRecent {
 					completedThroughTracker.addShards(sj.Shards)
@@ -85,14 +98,20 @@ func NewSearch(limit int, keepMostRecent bool) Combiner {
 			// metrics are already combined on the passed in final
 			final.Traces = metadataCombiner.Metadata()
 			final.Metrics = metricsCombiner.Metrics
+			final.Warnings = tempopb.WithoutWarning(final.Warnings, tempopb.WarningArchivePending)
 			addRootSpanNotReceivedText(final.Traces)
 			return final, nil
 		},
 		diff: func(current *tempopb.SearchResponse) (*tempopb.SearchResponse, error) {
 			// wipe out any existing traces and recreate from the map
 			diff := &tempopb.SearchResponse{
-				Traces:  make([]*tempopb.TraceSearchMetadata, 0, len(diffTraces)),
-				Metrics: metricsCombiner.Metrics,
+				Traces:   make([]*tempopb.TraceSearchMetadata, 0, len(diffTraces)),
+				Metrics:  metricsCombiner.Metrics,
+				Warnings: current.Warnings,
+			}
+			// the results are preliminary until the jobs of the archive tier completed
+			if diff.Metrics.CompletedJobs == diff.Metrics.TotalJobs {
+				diff.Warnings = tempopb.WithoutWarning(diff.Warnings, tempopb.WarningArchivePending)
 			}
 			metadataFn := metadataCombiner.Metadata
 			if keepMostRecent {
@@ -152,6 +171,10 @@ func NewTypedSearch(limit int, keepMostRecent bool) GRPCCombiner[*tempopb.Search
 	return NewSearch(limit, keepMostRecent).(GRPCCombiner[*tempopb.SearchResponse])
 }
 
+func NewTypedOrderedSearch(limit int, order *traceql.SearchOrder, keepMostRecent bool) GRPCCombiner[*tempopb.SearchResponse] {
+	return NewOrderedSearch(limit, order, keepMostRecent).(GRPCCombiner[*tempopb.SearchResponse])
+}
+
 // ShardCompletionTracker
 type ShardCompletionTracker struct {
 	shards         []SearchShards
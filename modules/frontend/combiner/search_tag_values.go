@@ -11,6 +11,12 @@ var (
 	_ GRPCCombiner[*tempopb.SearchTagValuesV2Response] = (*genericCombiner[*tempopb.SearchTagValuesV2Response])(nil)
 )
 
+// tagValuesTruncatedWarning is the warning of tag values cut at the size or count limits
+var (
+	tagValuesTruncatedWarning = tempopb.NewQueryWarning(tempopb.WarningResultsTruncated, "response exceeds the maximum size or number of tag values, the tag values are truncated")
+	tagValuesPagedWarning     = tempopb.NewQueryWarning(tempopb.WarningResultsPaged, "response is limited to a page of tag values, pass nextToken as after to request the next page")
+)
+
 func NewSearchTagValues(maxDataBytes int, maxTagsValues uint32, staleValueThreshold uint32) Combiner {
 	// Distinct collector with no limit
 	d := collector.NewDistinctStringWithDiff(maxDataBytes, maxTagsValues, staleValueThreshold)
@@ -56,13 +62,57 @@ func NewTypedSearchTagValues(maxDataBytes int, maxTagsValues uint32, staleValueT
 func NewSearchTagValuesV2(maxDataBytes int, maxTagsValues uint32, staleValueThreshold uint32) Combiner {
 	// Distinct collector with no limit and diff enabled
 	d := collector.NewDistinctValueWithDiff(maxDataBytes, maxTagsValues, staleValueThreshold, func(tv tempopb.TagValue) int { return len(tv.Type) + len(tv.Value) })
+	return newSearchTagValuesV2(d, false)
+}
+
+// NewPagedSearchTagValuesV2 combines the page of pageSize tag values that follows the after token. If there are more
+// values the response includes the token of the next page and a warning.
+func NewPagedSearchTagValuesV2(maxDataBytes int, pageSize uint32, after string) (Combiner, error) {
+	d, err := collector.NewPagedTagValues(maxDataBytes, pageSize, after)
+	if err != nil {
+		return nil, err
+	}
+	return newSearchTagValuesV2(d, true), nil
+}
+
+func newSearchTagValuesV2(d *collector.DistinctValue[tempopb.TagValue], paged bool) Combiner {
 	metricsCombiner := NewMetadataMetricsCombiner()
 
+	// warn adds the warning of truncated values. a full page isn't truncated, the rest is in the next pages
+	warn := func(response *tempopb.SearchTagValuesV2Response) {
+		if !paged && d.Exceeded() {
+			response.Warnings = tempopb.AppendWarnings(response.Warnings, tagValuesTruncatedWarning)
+		}
+	}
+
+	// page fills the response with the values collected so far and the token of the next page
+	page := func(response *tempopb.SearchTagValuesV2Response) *tempopb.SearchTagValuesV2Response {
+		values := d.Values()
+		response.TagValues = make([]*tempopb.TagValue, 0, len(values))
+		for _, v := range values {
+			v2 := v
+			response.TagValues = append(response.TagValues, &v2)
+		}
+
+		response.NextToken = collector.NextTagValuesToken(d)
+		if response.NextToken != "" {
+			response.Warnings = tempopb.AppendWarnings(response.Warnings, tagValuesPagedWarning)
+		}
+		response.Metrics = metricsCombiner.Metrics
+
+		return response
+	}
+
 	c := &genericCombiner[*tempopb.SearchTagValuesV2Response]{
 		httpStatusCode: 200,
 		current:        &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{}},
 		new:            func() *tempopb.SearchTagValuesV2Response { return &tempopb.SearchTagValuesV2Response{} },
-		combine: func(partial, _ *tempopb.SearchTagValuesV2Response, pipelineResp PipelineResponse) error {
+		combine: func(partial, final *tempopb.SearchTagValuesV2Response, pipelineResp PipelineResponse) error {
+			final.Warnings = tempopb.AppendWarnings(final.Warnings, partial.Warnings...)
+			// a job that truncated its page has more values than fit in ours
+			if partial.NextToken != "" {
+				d.MarkTruncated()
+			}
 			for _, v := range partial.TagValues {
 				d.Collect(*v)
 			}
@@ -70,21 +120,18 @@ func NewSearchTagValuesV2(maxDataBytes int, maxTagsValues uint32, staleValueThre
 			return nil
 		},
 		finalize: func(final *tempopb.SearchTagValuesV2Response) (*tempopb.SearchTagValuesV2Response, error) {
-			values := d.Values()
-			final.TagValues = make([]*tempopb.TagValue, 0, len(values))
-			for _, v := range values {
-				v2 := v
-				final.TagValues = append(final.TagValues, &v2)
-			}
-
-			final.Metrics = metricsCombiner.Metrics
-
-			return final, nil
+			warn(final)
+			return page(final), nil
 		},
 		quit: func(_ *tempopb.SearchTagValuesV2Response) bool {
 			return d.Exceeded()
 		},
 		diff: func(response *tempopb.SearchTagValuesV2Response) (*tempopb.SearchTagValuesV2Response, error) {
+			// values can leave a page when smaller ones are found so every streamed response holds the whole page
+			if paged {
+				return page(response), nil
+			}
+
 			diff, err := d.Diff()
 			if err != nil {
 				return nil, err
@@ -96,6 +143,7 @@ func NewSearchTagValuesV2(maxDataBytes int, maxTagsValues uint32, staleValueThre
 			}
 
 			response.Metrics = metricsCombiner.Metrics
+			warn(response)
 
 			return response, nil
 		},
@@ -107,3 +155,11 @@ func NewSearchTagValuesV2(maxDataBytes int, maxTagsValues uint32, staleValueThre
 func NewTypedSearchTagValuesV2(maxDataBytes int, maxTagsValues uint32, staleValueThreshold uint32) GRPCCombiner[*tempopb.SearchTagValuesV2Response] {
 	return NewSearchTagValuesV2(maxDataBytes, maxTagsValues, staleValueThreshold).(GRPCCombiner[*tempopb.SearchTagValuesV2Response])
 }
+
+func NewTypedPagedSearchTagValuesV2(maxDataBytes int, pageSize uint32, after string) (GRPCCombiner[*tempopb.SearchTagValuesV2Response], error) {
+	c, err := NewPagedSearchTagValuesV2(maxDataBytes, pageSize, after)
+	if err != nil {
+		return nil, err
+	}
+	return c.(GRPCCombiner[*tempopb.SearchTagValuesV2Response]), nil
+}
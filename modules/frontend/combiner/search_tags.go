@@ -11,6 +11,9 @@ var (
 	_ GRPCCombiner[*tempopb.SearchTagsV2Response] = (*genericCombiner[*tempopb.SearchTagsV2Response])(nil)
 )
 
+// tagsTruncatedWarning is the warning of tag names cut at the size or count limits
+var tagsTruncatedWarning = tempopb.NewQueryWarning(tempopb.WarningResultsTruncated, "response exceeds the maximum size or number of tags, the tags are truncated")
+
 func NewSearchTags(maxDataBytes int, maxTagsPerScope uint32, staleValueThreshold uint32) Combiner {
 	d := collector.NewDistinctStringWithDiff(maxDataBytes, maxTagsPerScope, staleValueThreshold)
 	metricsCombiner := NewMetadataMetricsCombiner()
@@ -60,8 +63,9 @@ func NewSearchTagsV2(maxDataBytes int, maxTagsPerScope uint32, staleValueThresho
 		httpStatusCode: 200,
 		new:            func() *tempopb.SearchTagsV2Response { return &tempopb.SearchTagsV2Response{} },
 		current:        &tempopb.SearchTagsV2Response{Scopes: make([]*tempopb.SearchTagsV2Scope, 0)},
-		combine: func(partial, _ *tempopb.SearchTagsV2Response, pipelineResp PipelineResponse) error {
+		combine: func(partial, final *tempopb.SearchTagsV2Response, pipelineResp PipelineResponse) error {
 			metricsCombiner.Combine(partial.Metrics, pipelineResp)
+			final.Warnings = tempopb.AppendWarnings(final.Warnings, partial.Warnings...)
 
 			for _, res := range partial.GetScopes() {
 				for _, tag := range res.Tags {
@@ -82,6 +86,9 @@ func NewSearchTagsV2(maxDataBytes int, maxTagsPerScope uint32, staleValueThresho
 			}
 
 			final.Metrics = metricsCombiner.Metrics
+			if distinctValues.Exceeded() {
+				final.Warnings = tempopb.AppendWarnings(final.Warnings, tagsTruncatedWarning)
+			}
 			return final, nil
 		},
 		quit: func(_ *tempopb.SearchTagsV2Response) bool {
@@ -102,6 +109,9 @@ func NewSearchTagsV2(maxDataBytes int, maxTagsPerScope uint32, staleValueThresho
 			}
 
 			response.Metrics = metricsCombiner.Metrics
+			if distinctValues.Exceeded() {
+				response.Warnings = tempopb.AppendWarnings(response.Warnings, tagsTruncatedWarning)
+			}
 			return response, nil
 		},
 	}
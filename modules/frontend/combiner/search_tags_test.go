@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/grafana/tempo/pkg/collector"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -94,7 +95,7 @@ func TestTagsCombiner(t *testing.T) {
 			factory:        NewSearchTagsV2,
 			result1:        &tempopb.SearchTagsV2Response{Scopes: []*tempopb.SearchTagsV2Scope{{Name: "scope1", Tags: []string{"v1"}}}},
 			result2:        &tempopb.SearchTagsV2Response{Scopes: []*tempopb.SearchTagsV2Scope{{Name: "scope1", Tags: []string{"v2", "v1"}}}},
-			expectedResult: &tempopb.SearchTagsV2Response{Scopes: []*tempopb.SearchTagsV2Scope{{Name: "scope1", Tags: []string{"v1"}}}, Metrics: &tempopb.MetadataMetrics{}},
+			expectedResult: &tempopb.SearchTagsV2Response{Scopes: []*tempopb.SearchTagsV2Scope{{Name: "scope1", Tags: []string{"v1"}}}, Metrics: &tempopb.MetadataMetrics{}, Warnings: []*tempopb.QueryWarning{tagsTruncatedWarning}},
 			actualResult:   &tempopb.SearchTagsV2Response{},
 			sort: func(m proto.Message) {
 				scopes := m.(*tempopb.SearchTagsV2Response).Scopes
@@ -124,7 +125,7 @@ func TestTagsCombiner(t *testing.T) {
 			factory:        NewSearchTagValuesV2,
 			result1:        &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Value: "v1", Type: "string"}}},
 			result2:        &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Value: "v2", Type: "string"}, {Value: "v3", Type: "string"}}},
-			expectedResult: &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Value: "v1", Type: "string"}}, Metrics: &tempopb.MetadataMetrics{}},
+			expectedResult: &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Value: "v1", Type: "string"}}, Metrics: &tempopb.MetadataMetrics{}, Warnings: []*tempopb.QueryWarning{tagValuesTruncatedWarning}},
 			actualResult:   &tempopb.SearchTagValuesV2Response{},
 			sort: func(m proto.Message) {
 				sort.Slice(m.(*tempopb.SearchTagValuesV2Response).TagValues, func(i, j int) bool {
@@ -139,7 +140,7 @@ func TestTagsCombiner(t *testing.T) {
 			factory:        NewSearchTagValuesV2,
 			result1:        &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Value: "v1", Type: "string"}}},
 			result2:        &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Value: "v2", Type: "string"}, {Value: "v3", Type: "string"}}},
-			expectedResult: &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Value: "v1", Type: "string"}}, Metrics: &tempopb.MetadataMetrics{}},
+			expectedResult: &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Value: "v1", Type: "string"}}, Metrics: &tempopb.MetadataMetrics{}, Warnings: []*tempopb.QueryWarning{tagValuesTruncatedWarning}},
 			actualResult:   &tempopb.SearchTagValuesV2Response{},
 			sort: func(m proto.Message) {
 				sort.Slice(m.(*tempopb.SearchTagValuesV2Response).TagValues, func(i, j int) bool {
@@ -295,6 +296,26 @@ func TestTagValuesV2GRPCCombiner(t *testing.T) {
 	})
 }
 
+func TestPagedTagValuesV2GRPCCombiner(t *testing.T) {
+	c, err := NewTypedPagedSearchTagValuesV2(0, 2, "")
+	require.NoError(t, err)
+
+	nextToken := collector.TagValuesToken(tempopb.TagValue{Value: "v2", Type: "string"})
+	warnings := []*tempopb.QueryWarning{tempopb.NewQueryWarning(tempopb.WarningResultsPaged, "response is limited to a page of tag values, pass nextToken as after to request the next page")}
+
+	res1 := &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Value: "v3", Type: "string"}, {Value: "v1", Type: "string"}}, Metrics: &tempopb.MetadataMetrics{InspectedBytes: 1}}
+	// the second job truncated its page so there are more values even if ours isn't truncated
+	res2 := &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Value: "v2", Type: "string"}}, NextToken: "more", Metrics: &tempopb.MetadataMetrics{InspectedBytes: 1}}
+	// diffs hold the whole page because v3 leaves it when v2 is found
+	diff1 := &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Value: "v1", Type: "string"}, {Value: "v3", Type: "string"}}, Metrics: &tempopb.MetadataMetrics{InspectedBytes: 1}}
+	diff2 := &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Value: "v1", Type: "string"}, {Value: "v2", Type: "string"}}, NextToken: nextToken, Warnings: warnings, Metrics: &tempopb.MetadataMetrics{InspectedBytes: 2}}
+	expectedFinal := &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{{Value: "v1", Type: "string"}, {Value: "v2", Type: "string"}}, NextToken: nextToken, Warnings: warnings, Metrics: &tempopb.MetadataMetrics{InspectedBytes: 2}}
+	testGRPCCombiner(t, c, res1, res2, diff1, diff2, expectedFinal, func(*tempopb.SearchTagValuesV2Response) {})
+
+	_, err = NewTypedPagedSearchTagValuesV2(0, 2, "not a token")
+	require.Error(t, err)
+}
+
 func testGRPCCombiner[T proto.Message](t *testing.T, combiner GRPCCombiner[T], result1 T, result2 T, diff1 T, diff2 T, expectedFinal T, sort func(T)) {
 	err := combiner.AddResponse(toHTTPResponse(t, result1, 200))
 	require.NoError(t, err)
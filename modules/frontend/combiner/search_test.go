@@ -220,6 +220,81 @@ func TestSearchCombinesResults(t *testing.T) {
 	}
 }
 
+func TestSearchCombinesWarnings(t *testing.T) {
+	stale := tempopb.NewQueryWarning(tempopb.WarningStaleBlocklist, "stale")
+	skipped := tempopb.NewQueryWarning(tempopb.WarningBlocksSkipped, "skipped")
+
+	c := NewTypedSearch(10, false)
+	require.NoError(t, c.AddResponse(&SearchJobResponse{TotalJobs: 2, Warnings: []*tempopb.QueryWarning{stale}}))
+	for range 2 {
+		require.NoError(t, c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{
+			Metrics:  &tempopb.SearchMetrics{},
+			Warnings: []*tempopb.QueryWarning{skipped},
+		}, 200)))
+	}
+
+	diff, err := c.GRPCDiff()
+	require.NoError(t, err)
+	require.Equal(t, []*tempopb.QueryWarning{stale, skipped}, diff.Warnings)
+
+	final, err := c.GRPCFinal()
+	require.NoError(t, err)
+	require.Equal(t, []*tempopb.QueryWarning{stale, skipped}, final.Warnings)
+}
+
+func TestSearchArchivePending(t *testing.T) {
+	pending := tempopb.NewQueryWarning(tempopb.WarningArchivePending, "pending")
+
+	c := NewTypedSearch(10, false)
+	require.NoError(t, c.AddResponse(&SearchJobResponse{TotalJobs: 2, Warnings: []*tempopb.QueryWarning{pending}}))
+	require.NoError(t, c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{
+		Traces:  []*tempopb.TraceSearchMetadata{{TraceID: "1", RootServiceName: "a"}},
+		Metrics: &tempopb.SearchMetrics{},
+	}, 200)))
+
+	// the results of the fast tier are streamed as preliminary
+	diff, err := c.GRPCDiff()
+	require.NoError(t, err)
+	require.Len(t, diff.Traces, 1)
+	require.Equal(t, []*tempopb.QueryWarning{pending}, diff.Warnings)
+
+	require.NoError(t, c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{
+		Traces:  []*tempopb.TraceSearchMetadata{{TraceID: "2", RootServiceName: "b"}},
+		Metrics: &tempopb.SearchMetrics{},
+	}, 200)))
+
+	diff, err = c.GRPCDiff()
+	require.NoError(t, err)
+	require.Len(t, diff.Traces, 1)
+	require.Empty(t, diff.Warnings)
+
+	final, err := c.GRPCFinal()
+	require.NoError(t, err)
+	require.Len(t, final.Traces, 2)
+	require.Empty(t, final.Warnings)
+}
+
+func TestSearchCombinesInspectedBytesByRole(t *testing.T) {
+	c := NewTypedSearch(10, false)
+	for range 2 {
+		require.NoError(t, c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{
+			Metrics: &tempopb.SearchMetrics{
+				InspectedBytes:       6,
+				InspectedBloomBytes:  1,
+				InspectedIndexBytes:  2,
+				InspectedColumnBytes: 3,
+			},
+		}, 200)))
+	}
+
+	final, err := c.GRPCFinal()
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), final.Metrics.InspectedBytes)
+	require.Equal(t, uint64(2), final.Metrics.InspectedBloomBytes)
+	require.Equal(t, uint64(4), final.Metrics.InspectedIndexBytes)
+	require.Equal(t, uint64(6), final.Metrics.InspectedColumnBytes)
+}
+
 func TestSearchResponseCombiner(t *testing.T) {
 	for _, keepMostRecent := range []bool{true, false} {
 		tests := []struct {
@@ -12,8 +12,19 @@ func NewTypedTraceByIDV2(maxBytes int, marshalingFormat string) GRPCCombiner[*te
 }
 
 func NewTraceByIDV2(maxBytes int, marshalingFormat string) Combiner {
+	return NewTraceByIDV2WithCollisions(maxBytes, marshalingFormat, trace.CollisionConfig{})
+}
+
+func NewTypedTraceByIDV2WithCollisions(maxBytes int, marshalingFormat string, collisions trace.CollisionConfig) GRPCCombiner[*tempopb.TraceByIDResponse] {
+	return NewTraceByIDV2WithCollisions(maxBytes, marshalingFormat, collisions).(GRPCCombiner[*tempopb.TraceByIDResponse])
+}
+
+// NewTraceByIDV2WithCollisions returns a trace by id combiner that detects distinct traces sharing the trace ID in
+// the final response. Collisions are only detected in the final response, streamed diffs are never split.
+func NewTraceByIDV2WithCollisions(maxBytes int, marshalingFormat string, collisions trace.CollisionConfig) Combiner {
 	combiner := trace.NewCombiner(maxBytes, true)
 	var partialTrace bool
+	var warnings []*tempopb.QueryWarning
 	metricsCombiner := NewTraceByIDMetricsCombiner()
 	gc := &genericCombiner[*tempopb.TraceByIDResponse]{
 		combine: func(partial *tempopb.TraceByIDResponse, _ *tempopb.TraceByIDResponse, pipelineResp PipelineResponse) error {
@@ -22,6 +33,7 @@ func NewTraceByIDV2(maxBytes int, marshalingFormat string) Combiner {
 			}
 
 			metricsCombiner.Combine(partial.Metrics, pipelineResp)
+			warnings = tempopb.AppendWarnings(warnings, partial.Warnings...)
 
 			_, err := combiner.Consume(partial.Trace)
 			return err
@@ -37,10 +49,43 @@ func NewTraceByIDV2(maxBytes int, marshalingFormat string) Combiner {
 			traceResult = deduper.dedupe(traceResult)
 			resp.Trace = traceResult
 			resp.Metrics = metricsCombiner.Metrics
+			resp.Warnings = tempopb.AppendWarnings(nil, warnings...)
+
+			if partialTrace || combiner.IsPartialTrace() {
+				resp.Status = tempopb.PartialStatus_PARTIAL
+				resp.Message = fmt.Sprintf("Trace exceeds maximum size of %d bytes, a partial trace is returned", maxBytes)
+				resp.Warnings = tempopb.AppendWarnings(resp.Warnings, tempopb.NewQueryWarning(tempopb.WarningResultsTruncated, resp.Message))
+			}
+
+			if collisions.Enabled {
+				traces := trace.SplitCollisions(traceResult, collisions.MinGap)
+				if len(traces) > 1 {
+					resp.Warnings = tempopb.AppendWarnings(resp.Warnings, tempopb.NewQueryWarning(tempopb.WarningTraceIDCollision,
+						fmt.Sprintf("the trace ID is shared by %d distinct traces", len(traces))))
+					if collisions.Split {
+						resp.Trace = traces[0]
+						resp.CollidingTraces = traces[1:]
+					}
+				}
+			}
+
+			return resp, nil
+		},
+		// diff returns the resource spans combined since the last diff. zipkin span ids are not
+		// deduped when streaming because the full trace is never assembled.
+		diff: func(_ *tempopb.TraceByIDResponse) (*tempopb.TraceByIDResponse, error) {
+			resp := &tempopb.TraceByIDResponse{
+				Trace: &tempopb.Trace{
+					ResourceSpans: combiner.NewResourceSpans(),
+				},
+				Metrics:  metricsCombiner.Metrics,
+				Warnings: tempopb.AppendWarnings(nil, warnings...),
+			}
 
 			if partialTrace || combiner.IsPartialTrace() {
 				resp.Status = tempopb.PartialStatus_PARTIAL
 				resp.Message = fmt.Sprintf("Trace exceeds maximum size of %d bytes, a partial trace is returned", maxBytes)
+				resp.Warnings = tempopb.AppendWarnings(resp.Warnings, tempopb.NewQueryWarning(tempopb.WarningResultsTruncated, resp.Message))
 			}
 
 			return resp, nil
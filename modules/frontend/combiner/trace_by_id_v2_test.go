@@ -5,11 +5,16 @@ import (
 	"io"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/proto"
 	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -56,6 +61,64 @@ func TestNewTraceByIdV2ReturnsAPartialTrace(t *testing.T) {
 	err = new(jsonpb.Unmarshaler).Unmarshal(res.Body, actualResp)
 	require.NoError(t, err)
 	assert.Equal(t, actualResp.Status, tempopb.PartialStatus_PARTIAL)
+	require.Len(t, actualResp.Warnings, 1)
+	assert.Equal(t, tempopb.WarningResultsTruncated, actualResp.Warnings[0].Code)
+}
+
+func TestNewTraceByIdV2CombinesWarnings(t *testing.T) {
+	tier := tempopb.NewQueryWarning(tempopb.WarningTierPending, "archived")
+	combiner := NewTypedTraceByIDV2(0, api.HeaderAcceptJSON)
+	for range 2 {
+		err := combiner.AddResponse(toHTTPResponse(t, &tempopb.TraceByIDResponse{
+			Trace:    test.MakeTrace(2, []byte{0x01, 0x02}),
+			Metrics:  &tempopb.TraceByIDMetrics{},
+			Warnings: []*tempopb.QueryWarning{tier},
+		}, 200))
+		require.NoError(t, err)
+	}
+
+	res, err := combiner.GRPCFinal()
+	require.NoError(t, err)
+	assert.Equal(t, []*tempopb.QueryWarning{tier}, res.Warnings)
+}
+
+func TestNewTraceByIdV2Collisions(t *testing.T) {
+	rootSpan := func(service string, id byte, start uint64) *tempopb.Trace {
+		return &tempopb.Trace{ResourceSpans: []*v1.ResourceSpans{{
+			Resource: &v1_resource.Resource{Attributes: []*v1_common.KeyValue{
+				{Key: "service.name", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: service}}},
+			}},
+			ScopeSpans: []*v1.ScopeSpans{{Spans: []*v1.Span{{
+				SpanId:            []byte{0, 0, 0, 0, 0, 0, 0, id},
+				StartTimeUnixNano: start,
+				EndTimeUnixNano:   start + 1,
+			}}}},
+		}}}
+	}
+	day := uint64(24 * time.Hour)
+
+	for _, split := range []bool{false, true} {
+		combiner := NewTypedTraceByIDV2WithCollisions(0, api.HeaderAcceptJSON, trace.CollisionConfig{Enabled: true, Split: split, MinGap: time.Hour})
+		for _, tr := range []*tempopb.Trace{rootSpan("b", 2, day), rootSpan("a", 1, 0)} {
+			err := combiner.AddResponse(toHTTPResponse(t, &tempopb.TraceByIDResponse{Trace: tr, Metrics: &tempopb.TraceByIDMetrics{}}, 200))
+			require.NoError(t, err)
+		}
+
+		res, err := combiner.GRPCFinal()
+		require.NoError(t, err)
+		require.Len(t, res.Warnings, 1)
+		assert.Equal(t, tempopb.WarningTraceIDCollision, res.Warnings[0].Code)
+
+		if !split {
+			assert.Len(t, res.Trace.ResourceSpans, 2)
+			assert.Empty(t, res.CollidingTraces)
+			continue
+		}
+		require.Len(t, res.Trace.ResourceSpans, 1)
+		assert.Equal(t, uint64(0), res.Trace.ResourceSpans[0].ScopeSpans[0].Spans[0].StartTimeUnixNano)
+		require.Len(t, res.CollidingTraces, 1)
+		assert.Equal(t, day, res.CollidingTraces[0].ResourceSpans[0].ScopeSpans[0].Spans[0].StartTimeUnixNano)
+	}
 }
 
 func TestNewTraceByIdV2ReturnsAPartialTraceOnPartialTraceReturnedByQuerier(t *testing.T) {
@@ -124,3 +187,44 @@ func TestNewTraceByIDV2(t *testing.T) {
 		require.NotNil(t, res)
 	})
 }
+
+func TestNewTraceByIdV2Diff(t *testing.T) {
+	toResponse := func(tr *tempopb.Trace) MockResponse {
+		resBytes, err := proto.Marshal(&tempopb.TraceByIDResponse{
+			Trace:   tr,
+			Metrics: &tempopb.TraceByIDMetrics{InspectedBytes: 1},
+		})
+		require.NoError(t, err)
+		return MockResponse{&http.Response{
+			StatusCode: 200,
+			Header: map[string][]string{
+				"Content-Type": {"application/protobuf"},
+			},
+			Body: io.NopCloser(bytes.NewReader(resBytes)),
+		}}
+	}
+
+	traceA := test.MakeTrace(2, []byte{0x01, 0x02})
+	traceB := test.MakeTrace(3, []byte{0x01, 0x02})
+
+	combiner := NewTypedTraceByIDV2(0, api.HeaderAcceptProtobuf)
+
+	require.NoError(t, combiner.AddResponse(toResponse(traceA)))
+	diff, err := combiner.GRPCDiff()
+	require.NoError(t, err)
+	require.Len(t, diff.Trace.ResourceSpans, 2)
+	require.Equal(t, uint64(1), diff.Metrics.InspectedBytes)
+
+	// the same trace again adds nothing
+	require.NoError(t, combiner.AddResponse(toResponse(traceA)))
+	diff, err = combiner.GRPCDiff()
+	require.NoError(t, err)
+	require.Empty(t, diff.Trace.ResourceSpans)
+	require.Equal(t, uint64(2), diff.Metrics.InspectedBytes)
+
+	require.NoError(t, combiner.AddResponse(toResponse(traceB)))
+	diff, err = combiner.GRPCDiff()
+	require.NoError(t, err)
+	require.Len(t, diff.Trace.ResourceSpans, 3)
+	require.Equal(t, tempopb.PartialStatus_COMPLETE, diff.Status)
+}
@@ -0,0 +1,67 @@
+package combiner
+
+import (
+	"fmt"
+
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func NewTypedTraceByIDs(maxBytes int, traceIDs int, marshalingFormat string) GRPCCombiner[*tempopb.TraceByIDsResponse] {
+	return NewTraceByIDs(maxBytes, traceIDs, marshalingFormat).(GRPCCombiner[*tempopb.TraceByIDsResponse])
+}
+
+// NewTraceByIDs returns a combiner of the responses of a trace by ids request. The traces of the responses are
+// combined by their index, which is the index of their trace id in the request.
+func NewTraceByIDs(maxBytes int, traceIDs int, marshalingFormat string) Combiner {
+	combiners := make([]*trace.Combiner, traceIDs)
+	for i := range combiners {
+		combiners[i] = trace.NewCombiner(maxBytes, true)
+	}
+	var warnings []*tempopb.QueryWarning
+	metricsCombiner := NewTraceByIDMetricsCombiner()
+	gc := &genericCombiner[*tempopb.TraceByIDsResponse]{
+		combine: func(partial *tempopb.TraceByIDsResponse, _ *tempopb.TraceByIDsResponse, pipelineResp PipelineResponse) error {
+			if len(partial.Traces) > len(combiners) {
+				return fmt.Errorf("received %d traces for %d trace ids", len(partial.Traces), len(combiners))
+			}
+
+			metricsCombiner.Combine(partial.Metrics, pipelineResp)
+			warnings = tempopb.AppendWarnings(warnings, partial.Warnings...)
+
+			for i, tr := range partial.Traces {
+				if _, err := combiners[i].Consume(tr); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		finalize: func(resp *tempopb.TraceByIDsResponse) (*tempopb.TraceByIDsResponse, error) {
+			resp.Traces = make([]*tempopb.Trace, 0, len(combiners))
+			resp.Metrics = metricsCombiner.Metrics
+			resp.Warnings = tempopb.AppendWarnings(nil, warnings...)
+
+			partial := false
+			for _, c := range combiners {
+				traceResult, _ := c.Result()
+				if traceResult == nil {
+					traceResult = &tempopb.Trace{}
+				}
+				// dedupe duplicate span ids
+				resp.Traces = append(resp.Traces, newDeduper().dedupe(traceResult))
+				partial = partial || c.IsPartialTrace()
+			}
+
+			if partial {
+				resp.Warnings = tempopb.AppendWarnings(resp.Warnings, tempopb.NewQueryWarning(tempopb.WarningResultsTruncated,
+					fmt.Sprintf("Traces exceed maximum size of %d bytes, partial traces are returned", maxBytes)))
+			}
+
+			return resp, nil
+		},
+		new:     func() *tempopb.TraceByIDsResponse { return &tempopb.TraceByIDsResponse{} },
+		current: &tempopb.TraceByIDsResponse{},
+	}
+	initHTTPCombiner(gc, marshalingFormat)
+	return gc
+}
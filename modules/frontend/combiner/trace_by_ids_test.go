@@ -0,0 +1,55 @@
+package combiner
+
+import (
+	"testing"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceByIDsCombinesByIndex(t *testing.T) {
+	first := test.MakeTrace(2, []byte{0x01})
+	second := test.MakeTrace(3, []byte{0x02})
+
+	combiner := NewTypedTraceByIDs(0, 3, api.HeaderAcceptJSON)
+	// the ingesters found the first trace, the blocks the second
+	err := combiner.AddResponse(toHTTPResponse(t, &tempopb.TraceByIDsResponse{
+		Traces:  []*tempopb.Trace{first, {}, {}},
+		Metrics: &tempopb.TraceByIDMetrics{InspectedBytes: 1},
+	}, 200))
+	require.NoError(t, err)
+	err = combiner.AddResponse(toHTTPResponse(t, &tempopb.TraceByIDsResponse{
+		Traces:  []*tempopb.Trace{{}, second, {}},
+		Metrics: &tempopb.TraceByIDMetrics{InspectedBytes: 2},
+	}, 200))
+	require.NoError(t, err)
+
+	res, err := combiner.GRPCFinal()
+	require.NoError(t, err)
+	require.Len(t, res.Traces, 3)
+	assert.Equal(t, spanCount(first), spanCount(res.Traces[0]))
+	assert.Equal(t, spanCount(second), spanCount(res.Traces[1]))
+	assert.Equal(t, 0, spanCount(res.Traces[2]))
+	assert.Equal(t, uint64(3), res.Metrics.InspectedBytes)
+}
+
+func TestTraceByIDsRejectsTooManyTraces(t *testing.T) {
+	combiner := NewTypedTraceByIDs(0, 1, api.HeaderAcceptJSON)
+	err := combiner.AddResponse(toHTTPResponse(t, &tempopb.TraceByIDsResponse{
+		Traces: []*tempopb.Trace{{}, {}},
+	}, 200))
+	require.Error(t, err)
+}
+
+func spanCount(tr *tempopb.Trace) int {
+	count := 0
+	for _, rs := range tr.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			count += len(ss.Spans)
+		}
+	}
+	return count
+}
@@ -8,7 +8,9 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/modules/frontend/queryaudit"
 	v1 "github.com/grafana/tempo/modules/frontend/v1"
+	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/usagestats"
 )
 
@@ -24,6 +26,7 @@ type Config struct {
 	ResponseConsumers         int                    `yaml:"response_consumers"`
 	Weights                   pipeline.WeightsConfig `yaml:"weights"`
 	MCPServer                 MCPServerConfig        `yaml:"mcp_server"`
+	QueryAudit                queryaudit.Config      `yaml:"query_audit"`
 
 	// the maximum time limit that tempo will work on an api request. this includes both
 	// grpc and http requests and applies to all "api" frontend query endpoints such as
@@ -40,6 +43,9 @@ type Config struct {
 	// A list of headers allowed through the HTTP pipeline. Everything else will be stripped.
 	AllowedHeaders []string `yaml:"-"`
 
+	// QueryAuditor records the queries of the tenants that have the query audit log enabled. It is injected by the app.
+	QueryAuditor *queryaudit.Auditor `yaml:"-"`
+
 	// RF1After specifies the time after which RF1 logic is applied.
 	RF1After time.Time `yaml:"rf1_after" category:"advanced"`
 }
@@ -60,6 +66,15 @@ type TraceByIDConfig struct {
 	ConcurrentShards int       `yaml:"concurrent_shards,omitempty"`
 	SLO              SLOConfig `yaml:",inline"`
 
+	// StreamChunkSizeBytes is the maximum size of a single message sent by the streaming gRPC FindTraceByID endpoint.
+	StreamChunkSizeBytes int `yaml:"stream_chunk_size_bytes,omitempty"`
+
+	// Collisions detects distinct traces that share a trace ID in the responses of the v2 trace by id endpoint.
+	Collisions trace.CollisionConfig `yaml:"trace_id_collisions"`
+
+	// TraceIDSummaryPruning skips the block shards without a block whose trace ID summary matches the trace ID.
+	TraceIDSummaryPruning bool `yaml:"trace_id_summary_pruning,omitempty"`
+
 	// RF1After specifies the time after which RF1 logic is applied, injected by the configuration
 	// or determined at runtime based on search request parameters.
 	RF1After time.Time `yaml:"-"`
@@ -101,8 +116,12 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 		SLO: slo,
 	}
 	cfg.TraceByID = TraceByIDConfig{
-		QueryShards: 50,
-		SLO:         slo,
+		QueryShards:          50,
+		StreamChunkSizeBytes: 1024 * 1024, // 1MiB
+		SLO:                  slo,
+		Collisions: trace.CollisionConfig{
+			MinGap: trace.DefaultCollisionMinGap,
+		},
 	}
 	cfg.Metrics = MetricsConfig{
 		Sharder: QueryRangeSharderConfig{
@@ -129,6 +148,8 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 		Enabled: false,
 	}
 
+	cfg.QueryAudit.RegisterFlagsAndApplyDefaults()
+
 	// set default max query size to 128 KiB, queries larger than this will be rejected
 	cfg.MaxQueryExpressionSizeBytes = 128 * 1024
 	// enable multi tenant queries by default
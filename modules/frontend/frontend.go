@@ -19,6 +19,7 @@ import (
 
 	"github.com/grafana/tempo/modules/frontend/combiner"
 	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/modules/frontend/queryaudit"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/cache"
@@ -44,13 +45,15 @@ type (
 	streamingTagValuesV2Handler  func(req *tempopb.SearchTagValuesRequest, srv tempopb.StreamingQuerier_SearchTagValuesV2Server) error
 	streamingQueryRangeHandler   func(req *tempopb.QueryRangeRequest, srv tempopb.StreamingQuerier_MetricsQueryRangeServer) error
 	streamingQueryInstantHandler func(req *tempopb.QueryInstantRequest, srv tempopb.StreamingQuerier_MetricsQueryInstantServer) error
+	streamingTraceByIDHandler    func(req *tempopb.TraceByIDRequest, srv tempopb.StreamingQuerier_FindTraceByIDServer) error
 )
 
 type QueryFrontend struct {
 	TraceByIDHandler, TraceByIDHandlerV2, SearchHandler, MetricsSummaryHandler                 http.Handler
 	SearchTagsHandler, SearchTagsV2Handler, SearchTagsValuesHandler, SearchTagsValuesV2Handler http.Handler
-	MetricsQueryInstantHandler, MetricsQueryRangeHandler                                       http.Handler
-	MCPHandler                                                                                 http.Handler
+	MetricsQueryInstantHandler, MetricsQueryRangeHandler, MetricsRemoteReadHandler             http.Handler
+	TraceByIDsHandler                                                                          http.Handler
+	MCPHandler, TraceUIHandler, TraceQLParseHandler, TraceQLValidateHandler                    http.Handler
 	cacheProvider                                                                              cache.Provider
 	streamingSearch                                                                            streamingSearchHandler
 	streamingTags                                                                              streamingTagsHandler
@@ -59,6 +62,8 @@ type QueryFrontend struct {
 	streamingTagValuesV2                                                                       streamingTagValuesV2Handler
 	streamingQueryRange                                                                        streamingQueryRangeHandler
 	streamingQueryInstant                                                                      streamingQueryInstantHandler
+	streamingTraceByID                                                                         streamingTraceByIDHandler
+	auditor                                                                                    *queryaudit.Auditor
 	logger                                                                                     log.Logger
 }
 
@@ -72,6 +77,10 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 		return nil, fmt.Errorf("frontend query shards should be between %d and %d (both inclusive)", minQueryShards, maxQueryShards)
 	}
 
+	if cfg.TraceByID.Collisions.MinGap < 0 {
+		return nil, fmt.Errorf("frontend trace by id collisions min gap should be greater than or equal to 0")
+	}
+
 	if cfg.Search.Sharder.ConcurrentRequests <= 0 {
 		return nil, fmt.Errorf("frontend search concurrent requests should be greater than 0")
 	}
@@ -118,7 +127,7 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 			urlDenyListWare,
 			pipeline.NewWeightRequestWare(pipeline.TraceByID, cfg.Weights),
 			multiTenantMiddleware(cfg, logger),
-			newAsyncTraceIDSharder(&cfg.TraceByID, logger),
+			newAsyncTraceIDSharder(reader, &cfg.TraceByID, logger),
 		},
 		[]pipeline.Middleware{traceIDStatusCodeWare, retryWare},
 		next)
@@ -194,7 +203,10 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 		next)
 
 	traces := newTraceIDHandler(cfg, tracePipeline, o, combiner.NewTypedTraceByID, logger)
-	tracesV2 := newTraceIDV2Handler(cfg, tracePipeline, o, combiner.NewTypedTraceByIDV2, logger)
+	tracesV2 := newTraceIDV2Handler(cfg, tracePipeline, o, func(maxBytes int, marshalingFormat string) combiner.GRPCCombiner[*tempopb.TraceByIDResponse] {
+		return combiner.NewTypedTraceByIDV2WithCollisions(maxBytes, marshalingFormat, cfg.TraceByID.Collisions)
+	}, logger)
+	tracesByIDs := newTraceIDsHandler(cfg, tracePipeline, o, logger)
 	search := newSearchHTTPHandler(cfg, searchPipeline, logger)
 	searchTags := newTagsHTTPHandler(cfg, searchTagsPipeline, o, logger)
 	searchTagsV2 := newTagsV2HTTPHandler(cfg, searchTagsPipeline, o, logger)
@@ -203,19 +215,22 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 	metrics := newMetricsSummaryHandler(metricsPipeline, logger)
 	queryInstant := newMetricsQueryInstantHTTPHandler(cfg, queryInstantPipeline, logger) // Reuses the same pipeline
 	queryRange := newMetricsQueryRangeHTTPHandler(cfg, queryRangePipeline, logger)
+	remoteRead := newMetricsRemoteReadHTTPHandler(cfg, queryRangePipeline, logger) // Reuses the same pipeline
 
 	f := &QueryFrontend{
 		// http/discrete
-		TraceByIDHandler:           newHandler(cfg.Config.LogQueryRequestHeaders, traces, logger),
-		TraceByIDHandlerV2:         newHandler(cfg.Config.LogQueryRequestHeaders, tracesV2, logger),
-		SearchHandler:              newHandler(cfg.Config.LogQueryRequestHeaders, search, logger),
-		SearchTagsHandler:          newHandler(cfg.Config.LogQueryRequestHeaders, searchTags, logger),
-		SearchTagsV2Handler:        newHandler(cfg.Config.LogQueryRequestHeaders, searchTagsV2, logger),
-		SearchTagsValuesHandler:    newHandler(cfg.Config.LogQueryRequestHeaders, searchTagValues, logger),
-		SearchTagsValuesV2Handler:  newHandler(cfg.Config.LogQueryRequestHeaders, searchTagValuesV2, logger),
-		MetricsSummaryHandler:      newHandler(cfg.Config.LogQueryRequestHeaders, metrics, logger),
-		MetricsQueryInstantHandler: newHandler(cfg.Config.LogQueryRequestHeaders, queryInstant, logger),
-		MetricsQueryRangeHandler:   newHandler(cfg.Config.LogQueryRequestHeaders, queryRange, logger),
+		TraceByIDHandler:           newHandler(cfg.Config.LogQueryRequestHeaders, cfg.QueryAuditor, traces, logger),
+		TraceByIDHandlerV2:         newHandler(cfg.Config.LogQueryRequestHeaders, cfg.QueryAuditor, tracesV2, logger),
+		TraceByIDsHandler:          newHandler(cfg.Config.LogQueryRequestHeaders, cfg.QueryAuditor, tracesByIDs, logger),
+		SearchHandler:              newHandler(cfg.Config.LogQueryRequestHeaders, cfg.QueryAuditor, search, logger),
+		SearchTagsHandler:          newHandler(cfg.Config.LogQueryRequestHeaders, cfg.QueryAuditor, searchTags, logger),
+		SearchTagsV2Handler:        newHandler(cfg.Config.LogQueryRequestHeaders, cfg.QueryAuditor, searchTagsV2, logger),
+		SearchTagsValuesHandler:    newHandler(cfg.Config.LogQueryRequestHeaders, cfg.QueryAuditor, searchTagValues, logger),
+		SearchTagsValuesV2Handler:  newHandler(cfg.Config.LogQueryRequestHeaders, cfg.QueryAuditor, searchTagValuesV2, logger),
+		MetricsSummaryHandler:      newHandler(cfg.Config.LogQueryRequestHeaders, cfg.QueryAuditor, metrics, logger),
+		MetricsQueryInstantHandler: newHandler(cfg.Config.LogQueryRequestHeaders, cfg.QueryAuditor, queryInstant, logger),
+		MetricsQueryRangeHandler:   newHandler(cfg.Config.LogQueryRequestHeaders, cfg.QueryAuditor, queryRange, logger),
+		MetricsRemoteReadHandler:   newHandler(cfg.Config.LogQueryRequestHeaders, cfg.QueryAuditor, remoteRead, logger),
 
 		// grpc/streaming
 		streamingSearch:       newSearchStreamingGRPCHandler(cfg, searchPipeline, apiPrefix, logger),
@@ -225,11 +240,20 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 		streamingTagValuesV2:  newTagValuesV2StreamingGRPCHandler(cfg, searchTagValuesPipeline, apiPrefix, o, logger),
 		streamingQueryRange:   newQueryRangeStreamingGRPCHandler(cfg, queryRangePipeline, apiPrefix, logger),
 		streamingQueryInstant: newQueryInstantStreamingGRPCHandler(cfg, queryRangePipeline, apiPrefix, logger), // Reuses the same pipeline
+		streamingTraceByID:    newTraceIDStreamingGRPCHandler(cfg, tracePipeline, apiPrefix, o, logger),
 
 		cacheProvider: cacheProvider,
+		auditor:       cfg.QueryAuditor,
 		logger:        logger,
 	}
 
+	// the trace ui renders the response of the trace by id v2 handler
+	f.TraceUIHandler = newTraceUIHandler(f.TraceByIDHandlerV2, logger)
+
+	// the traceql lint handlers don't execute queries
+	f.TraceQLParseHandler = newTraceQLLintHandler(true)
+	f.TraceQLValidateHandler = newTraceQLLintHandler(false)
+
 	if cfg.MCPServer.Enabled {
 		// Initialize MCP server
 		mcpServer := NewMCPServer(f, apiPrefix, logger, authMiddleware)
@@ -245,31 +269,44 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 
 // Search implements StreamingQuerierServer interface for streaming search
 func (q *QueryFrontend) Search(req *tempopb.SearchRequest, srv tempopb.StreamingQuerier_SearchServer) error {
-	return q.streamingSearch(req, srv)
+	stream := newAuditedStream[*tempopb.SearchResponse](q.auditor, srv, api.PathSearch, req.Query, unixSeconds(req.Start), unixSeconds(req.End))
+	return stream.done(q.streamingSearch(req, stream))
 }
 
 func (q *QueryFrontend) SearchTags(req *tempopb.SearchTagsRequest, srv tempopb.StreamingQuerier_SearchTagsServer) error {
-	return q.streamingTags(req, srv)
+	stream := newAuditedStream[*tempopb.SearchTagsResponse](q.auditor, srv, api.PathSearchTags, req.Query, unixSeconds(req.Start), unixSeconds(req.End))
+	return stream.done(q.streamingTags(req, stream))
 }
 
 func (q *QueryFrontend) SearchTagsV2(req *tempopb.SearchTagsRequest, srv tempopb.StreamingQuerier_SearchTagsV2Server) error {
-	return q.streamingTagsV2(req, srv)
+	stream := newAuditedStream[*tempopb.SearchTagsV2Response](q.auditor, srv, api.PathSearchTagsV2, req.Query, unixSeconds(req.Start), unixSeconds(req.End))
+	return stream.done(q.streamingTagsV2(req, stream))
 }
 
 func (q *QueryFrontend) SearchTagValues(req *tempopb.SearchTagValuesRequest, srv tempopb.StreamingQuerier_SearchTagValuesServer) error {
-	return q.streamingTagValues(req, srv)
+	stream := newAuditedStream[*tempopb.SearchTagValuesResponse](q.auditor, srv, tagValuesEndpoint(api.PathSearchTagValues, req.TagName), req.Query, unixSeconds(req.Start), unixSeconds(req.End))
+	return stream.done(q.streamingTagValues(req, stream))
 }
 
 func (q *QueryFrontend) SearchTagValuesV2(req *tempopb.SearchTagValuesRequest, srv tempopb.StreamingQuerier_SearchTagValuesV2Server) error {
-	return q.streamingTagValuesV2(req, srv)
+	stream := newAuditedStream[*tempopb.SearchTagValuesV2Response](q.auditor, srv, tagValuesEndpoint(api.PathSearchTagValuesV2, req.TagName), req.Query, unixSeconds(req.Start), unixSeconds(req.End))
+	return stream.done(q.streamingTagValuesV2(req, stream))
 }
 
 func (q *QueryFrontend) MetricsQueryRange(req *tempopb.QueryRangeRequest, srv tempopb.StreamingQuerier_MetricsQueryRangeServer) error {
-	return q.streamingQueryRange(req, srv)
+	stream := newAuditedStream[*tempopb.QueryRangeResponse](q.auditor, srv, api.PathMetricsQueryRange, req.Query, unixNanos(req.Start), unixNanos(req.End))
+	return stream.done(q.streamingQueryRange(req, stream))
 }
 
 func (q *QueryFrontend) MetricsQueryInstant(req *tempopb.QueryInstantRequest, srv tempopb.StreamingQuerier_MetricsQueryInstantServer) error {
-	return q.streamingQueryInstant(req, srv)
+	stream := newAuditedStream[*tempopb.QueryInstantResponse](q.auditor, srv, api.PathMetricsQueryInstant, req.Query, unixNanos(req.Start), unixNanos(req.End))
+	return stream.done(q.streamingQueryInstant(req, stream))
+}
+
+// FindTraceByID streams the trace with the given id as it is combined from all queriers
+func (q *QueryFrontend) FindTraceByID(req *tempopb.TraceByIDRequest, srv tempopb.StreamingQuerier_FindTraceByIDServer) error {
+	stream := newAuditedStream[*tempopb.TraceByIDResponse](q.auditor, srv, traceByIDEndpoint(req.TraceID), "", time.Time{}, time.Time{})
+	return stream.done(q.streamingTraceByID(req, stream))
 }
 
 // newSpanMetricsMiddleware creates a new frontend middleware to handle metrics-generator requests.
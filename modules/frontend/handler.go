@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/grafana/dskit/flagext"
 	"github.com/grafana/dskit/grpcutil"
+	"github.com/grafana/tempo/modules/frontend/queryaudit"
+	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/util"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -40,14 +43,16 @@ type handler struct {
 	roundTripper           http.RoundTripper
 	logger                 log.Logger
 	logQueryRequestHeaders flagext.StringSliceCSV
+	auditor                *queryaudit.Auditor
 }
 
 // newHandler creates a handler
-func newHandler(LogQueryRequestHeaders flagext.StringSliceCSV, rt http.RoundTripper, logger log.Logger) http.Handler {
+func newHandler(LogQueryRequestHeaders flagext.StringSliceCSV, auditor *queryaudit.Auditor, rt http.RoundTripper, logger log.Logger) http.Handler {
 	return &handler{
 		logQueryRequestHeaders: LogQueryRequestHeaders,
 		roundTripper:           rt,
 		logger:                 logger,
+		auditor:                auditor,
 	}
 }
 
@@ -93,6 +98,7 @@ func (f *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			"response_size", 0,
 		)
 		level.Info(f.logger).Log(logMessage...)
+		f.audit(r, orgID, start, statusCode, 0)
 		return
 	}
 
@@ -106,14 +112,16 @@ func (f *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			"response_size", 0,
 		)
 		level.Info(f.logger).Log(logMessage...)
+		f.audit(r, orgID, start, statusCode, 0)
 		return
 	}
 
 	// write headers, status code and body
 	copyHeader(w.Header(), resp.Header)
 	w.WriteHeader(resp.StatusCode)
+	var written int64
 	if resp.Body != nil {
-		_, _ = io.Copy(w, resp.Body)
+		written, _ = io.Copy(w, resp.Body)
 	}
 
 	// request/response logging
@@ -133,6 +141,26 @@ func (f *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		"status", statusCode,
 	)
 	level.Info(f.logger).Log(logMessage...)
+	f.audit(r, orgID, start, statusCode, written)
+}
+
+// audit records the request in the query audit log of the tenant.
+func (f *handler) audit(r *http.Request, orgID string, received time.Time, statusCode int, responseBytes int64) {
+	if f.auditor == nil {
+		return
+	}
+
+	query, start, end := api.ParseQueryTextAndRange(r)
+	f.auditor.Record(orgID, queryaudit.Record{
+		Time:          received,
+		User:          r.Header.Get(f.auditor.UserHeader()),
+		Endpoint:      r.URL.Path,
+		Query:         query,
+		Start:         start,
+		End:           end,
+		ResponseBytes: responseBytes,
+		Status:        strconv.Itoa(statusCode),
+	})
 }
 
 func formatRequestHeaders(h *http.Header, headersToLog []string) (fields []interface{}) {
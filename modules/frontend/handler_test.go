@@ -1,10 +1,25 @@
 package frontend
 
 import (
+	"context"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/grafana/tempo/modules/frontend/queryaudit"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
 )
 
 func TestFormatRequestHeaders(t *testing.T) {
@@ -21,3 +36,101 @@ func TestFormatRequestHeaders(t *testing.T) {
 
 	require.Equal(t, expected, fields)
 }
+
+type mockQueryAuditOverrides struct{}
+
+func (mockQueryAuditOverrides) QueryAuditEnabled(userID string) bool { return userID == "audited" }
+
+func (mockQueryAuditOverrides) QueryAuditRetention(string) time.Duration { return 0 }
+
+func newTestQueryAuditor(t *testing.T) (*queryaudit.Auditor, backend.RawReader) {
+	r, w, _, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+
+	cfg := queryaudit.Config{}
+	cfg.RegisterFlagsAndApplyDefaults()
+	a := queryaudit.New(cfg, r, w, mockQueryAuditOverrides{}, log.NewNopLogger())
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), a))
+
+	return a, r
+}
+
+// auditRecords stops the auditor to flush its records and returns the records of the tenant.
+func auditRecords(t *testing.T, a *queryaudit.Auditor, r backend.RawReader, tenantID string) []*queryaudit.Record {
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), a))
+
+	var records []*queryaudit.Record
+	err := queryaudit.Search(context.Background(), r, tenantID, queryaudit.SearchOptions{}, func(rec *queryaudit.Record) error {
+		records = append(records, rec)
+		return nil
+	})
+	require.NoError(t, err)
+	return records
+}
+
+func TestHandlerQueryAudit(t *testing.T) {
+	a, r := newTestQueryAuditor(t)
+
+	h := newHandler(nil, a, RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("response")),
+		}, nil
+	}), log.NewNopLogger())
+
+	for _, tenantID := range []string{"audited", "not-audited"} {
+		req := httptest.NewRequest("GET", "/api/search?q=%7B%7D&start=1700000000&end=1700003600", nil)
+		req.Header.Set("X-Grafana-User", "alice")
+		req = req.WithContext(user.InjectOrgID(req.Context(), tenantID))
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	records := auditRecords(t, a, r, "audited")
+	require.Len(t, records, 1)
+	require.Equal(t, "alice", records[0].User)
+	require.Equal(t, "/api/search", records[0].Endpoint)
+	require.Equal(t, "{}", records[0].Query)
+	require.Equal(t, time.Unix(1700000000, 0).UTC(), records[0].Start.UTC())
+	require.Equal(t, time.Unix(1700003600, 0).UTC(), records[0].End.UTC())
+	require.Equal(t, int64(len("response")), records[0].ResponseBytes)
+	require.Equal(t, "200", records[0].Status)
+
+	require.Empty(t, auditRecords(t, a, r, "not-audited"))
+}
+
+type mockSearchServer struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent int
+}
+
+func (m *mockSearchServer) Context() context.Context { return m.ctx }
+
+func (m *mockSearchServer) Send(*tempopb.SearchResponse) error {
+	m.sent++
+	return nil
+}
+
+func TestAuditedStream(t *testing.T) {
+	a, r := newTestQueryAuditor(t)
+
+	ctx := metadata.NewIncomingContext(user.InjectOrgID(context.Background(), "audited"), metadata.Pairs("X-Grafana-User", "bob"))
+	srv := &mockSearchServer{ctx: ctx}
+	resp := &tempopb.SearchResponse{Traces: []*tempopb.TraceSearchMetadata{{TraceID: "1234", RootServiceName: "svc"}}}
+
+	stream := newAuditedStream[*tempopb.SearchResponse](a, srv, "/api/search", "{}", unixSeconds(1700000000), unixSeconds(0))
+	require.NoError(t, stream.Send(resp))
+	require.NoError(t, stream.Send(resp))
+	require.NoError(t, stream.done(nil))
+	require.Equal(t, 2, srv.sent)
+
+	records := auditRecords(t, a, r, "audited")
+	require.Len(t, records, 1)
+	require.Equal(t, "bob", records[0].User)
+	require.Equal(t, "/api/search", records[0].Endpoint)
+	require.Equal(t, "{}", records[0].Query)
+	require.Equal(t, time.Unix(1700000000, 0).UTC(), records[0].Start.UTC())
+	require.True(t, records[0].End.IsZero())
+	require.Equal(t, int64(2*resp.Size()), records[0].ResponseBytes)
+	require.Equal(t, "OK", records[0].Status)
+}
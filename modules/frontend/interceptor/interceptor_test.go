@@ -119,3 +119,7 @@ func (s *mockService) MetricsQueryRange(*tempopb.QueryRangeRequest, tempopb.Stre
 func (s *mockService) MetricsQueryInstant(*tempopb.QueryInstantRequest, tempopb.StreamingQuerier_MetricsQueryInstantServer) error {
 	return nil
 }
+
+func (s *mockService) FindTraceByID(*tempopb.TraceByIDRequest, tempopb.StreamingQuerier_FindTraceByIDServer) error {
+	return nil
+}
@@ -173,9 +173,10 @@ func newMetricsQueryInstantHTTPHandler(cfg Config, next pipeline.AsyncRoundTripp
 
 func translateQueryRangeToInstant(input tempopb.QueryRangeResponse) tempopb.QueryInstantResponse {
 	output := tempopb.QueryInstantResponse{
-		Metrics: input.Metrics,
-		Status:  input.Status,
-		Message: input.Message,
+		Metrics:  input.Metrics,
+		Status:   input.Status,
+		Message:  input.Message,
+		Warnings: input.Warnings,
 	}
 	for _, series := range input.Series {
 		if len(series.Samples) == 0 {
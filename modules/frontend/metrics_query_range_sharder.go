@@ -139,7 +139,7 @@ func (s queryRangeSharder) RoundTrip(pipelineRequest pipeline.Request) (pipeline
 		reqCh <- generatorReq
 	}
 
-	totalJobs, totalBlocks, totalBlockBytes := s.backendRequests(ctx, tenantID, pipelineRequest, *req, cutoff, targetBytesPerRequest, reqCh)
+	totalJobs, totalBlocks, totalBlockBytes, warnings := s.backendRequests(ctx, tenantID, pipelineRequest, *req, cutoff, targetBytesPerRequest, expr.RequiresErrorStatus(), reqCh)
 
 	span.SetAttributes(attribute.Int64("totalJobs", int64(totalJobs)))
 	span.SetAttributes(attribute.Int64("totalBlocks", int64(totalBlocks)))
@@ -154,6 +154,7 @@ func (s queryRangeSharder) RoundTrip(pipelineRequest pipeline.Request) (pipeline
 				TotalBlocks:     totalBlocks,
 				TotalBlockBytes: totalBlockBytes,
 			},
+			Warnings: append(staleBlocklistWarnings(s.reader, tenantID, metricsOp), warnings...),
 		}
 
 		m := jsonpb.Marshaler{}
@@ -234,7 +235,7 @@ func (s *queryRangeSharder) exemplarsCutoff(req tempopb.QueryRangeRequest, cutof
 	return limit - shareAfterCutoffCeil, shareAfterCutoffCeil
 }
 
-func (s *queryRangeSharder) backendRequests(ctx context.Context, tenantID string, parent pipeline.Request, searchReq tempopb.QueryRangeRequest, cutoff time.Time, targetBytesPerRequest int, reqCh chan pipeline.Request) (totalJobs, totalBlocks uint32, totalBlockBytes uint64) {
+func (s *queryRangeSharder) backendRequests(ctx context.Context, tenantID string, parent pipeline.Request, searchReq tempopb.QueryRangeRequest, cutoff time.Time, targetBytesPerRequest int, errorsOnly bool, reqCh chan pipeline.Request) (totalJobs, totalBlocks uint32, totalBlockBytes uint64, warnings []*tempopb.QueryWarning) {
 	// request without start or end, search only in generator
 	if searchReq.Start == 0 || searchReq.End == 0 {
 		close(reqCh)
@@ -256,9 +257,14 @@ func (s *queryRangeSharder) backendRequests(ctx context.Context, tenantID string
 	// range is checked for each window.
 	start := time.Unix(0, int64(backendReq.Start))
 	end := time.Unix(0, int64(backendReq.End))
-	blocks := blockMetasForSearch(s.reader.BlockMetas(tenantID), start, end, func(m *backend.BlockMeta) bool {
+	filterFn := func(m *backend.BlockMeta) bool {
 		return m.ReplicationFactor == backend.MetricsGeneratorReplicationFactor
-	})
+	}
+	if errorsOnly {
+		filterFn = errorTimesFilterFn(start, end, filterFn)
+	}
+
+	blocks := blockMetasForSearch(s.reader.BlockMetasInRange(tenantID, start, end), start, end, filterFn)
 	if len(blocks) == 0 {
 		// no need to search backend
 		close(reqCh)
@@ -276,6 +282,7 @@ func (s *queryRangeSharder) backendRequests(ctx context.Context, tenantID string
 		}
 		totalBlockBytes += b.Size_
 	}
+	warnings = archivePendingWarnings(s.overrides, tenantID, blocks)
 
 	go func() {
 		s.buildBackendRequests(ctx, tenantID, parent, backendReq, blocks, targetBytesPerRequest, reqCh)
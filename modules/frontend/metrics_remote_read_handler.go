@@ -0,0 +1,224 @@
+package frontend
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/storage/remote"
+	"github.com/prometheus/prometheus/util/strutil"
+
+	"github.com/grafana/tempo/modules/frontend/combiner"
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+)
+
+// RemoteReadQueryLabel is the label matcher that carries the TraceQL metrics query in a remote read request.
+// e.g. {__traceql__="{ status = error } | rate() by (resource.service.name)"}
+const RemoteReadQueryLabel = "__traceql__"
+
+// newMetricsRemoteReadHTTPHandler serves the Prometheus remote read protocol on top of TraceQL metrics. Every query
+// in the read request must have an equality matcher on RemoteReadQueryLabel with the TraceQL query to run. An
+// equality matcher on __name__ names the returned series and all other matchers filter them. The query is rewritten
+// into a query_range request to make use of the existing pipeline.
+func newMetricsRemoteReadHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], logger log.Logger) http.RoundTripper {
+	postSLOHook := metricsSLOPostHook(cfg.Metrics.SLO)
+
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		tenant, _ := user.ExtractOrgID(req.Context())
+		start := time.Now()
+
+		readReq, err := remote.DecodeReadRequest(req)
+		if err != nil {
+			level.Error(logger).Log("msg", "remote read: decode request failed", "err", err)
+			return remoteReadBadRequest(err), nil
+		}
+
+		readResp := &prompb.ReadResponse{
+			Results: make([]*prompb.QueryResult, 0, len(readReq.Queries)),
+		}
+
+		var bytesProcessed uint64
+		for _, q := range readReq.Queries {
+			qr, name, matchers, err := parseRemoteReadQuery(q)
+			if err != nil {
+				level.Error(logger).Log("msg", "remote read: parse query failed", "err", err)
+				return remoteReadBadRequest(err), nil
+			}
+
+			logQueryRangeRequest(logger, tenant, qr)
+
+			c, err := combiner.NewTypedQueryRange(qr, cfg.Metrics.Sharder.MaxResponseSeries)
+			if err != nil {
+				level.Error(logger).Log("msg", "remote read: query range combiner failed", "err", err)
+				return remoteReadBadRequest(err), nil
+			}
+			rt := pipeline.NewHTTPCollector(next, cfg.ResponseConsumers, c)
+
+			httpReq := api.BuildQueryRangeRequest(&http.Request{
+				Method: http.MethodGet,
+				URL:    &url.URL{Path: strings.ReplaceAll(req.URL.Path, api.PathMetricsRemoteRead, api.PathMetricsQueryRange)},
+				Header: http.Header{},
+				Body:   io.NopCloser(bytes.NewReader([]byte{})),
+			}, qr, "") // dedicated cols are never passed from the caller
+			httpReq = httpReq.WithContext(req.Context())
+
+			// roundtrip the request and look for intermediate failures
+			innerResp, err := rt.RoundTrip(httpReq)
+			if err != nil {
+				return nil, err
+			}
+			if innerResp != nil && innerResp.StatusCode != http.StatusOK {
+				return innerResp, nil
+			}
+
+			qrResp, err := c.GRPCFinal()
+			if err != nil {
+				return nil, err
+			}
+			if qrResp.Metrics != nil {
+				bytesProcessed += qrResp.Metrics.InspectedBytes
+			}
+			logQueryRangeResult(logger, tenant, time.Since(start).Seconds(), qr, qrResp, nil)
+
+			readResp.Results = append(readResp.Results, &prompb.QueryResult{
+				Timeseries: toRemoteReadSeries(qrResp.Series, name, matchers),
+			})
+		}
+
+		data, err := proto.Marshal(readResp)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling response body: %w", err)
+		}
+		data = snappy.Encode(nil, data)
+
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				api.HeaderContentType: {"application/x-protobuf"},
+				"Content-Encoding":    {"snappy"},
+			},
+			Body:          io.NopCloser(bytes.NewReader(data)),
+			ContentLength: int64(len(data)),
+		}
+
+		postSLOHook(resp, tenant, bytesProcessed, time.Since(start), nil)
+		return resp, nil
+	})
+}
+
+// parseRemoteReadQuery returns the query range request, the series name and the label matchers that filter the
+// resulting series for a single remote read query.
+func parseRemoteReadQuery(q *prompb.Query) (*tempopb.QueryRangeRequest, string, []*labels.Matcher, error) {
+	matchers, err := remote.FromLabelMatchers(q.Matchers)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	var (
+		query   string
+		name    string
+		filters = make([]*labels.Matcher, 0, len(matchers))
+	)
+	for _, m := range matchers {
+		switch {
+		case m.Name == RemoteReadQueryLabel && m.Type == labels.MatchEqual:
+			query = m.Value
+		case m.Name == labels.MetricName && m.Type == labels.MatchEqual:
+			name = m.Value
+		default:
+			filters = append(filters, m)
+		}
+	}
+	if query == "" {
+		return nil, "", nil, fmt.Errorf("remote read query requires an equality matcher on %s with the TraceQL query", RemoteReadQueryLabel)
+	}
+
+	qr := &tempopb.QueryRangeRequest{
+		Query: query,
+		Start: uint64(time.UnixMilli(q.StartTimestampMs).UnixNano()),
+		End:   uint64(time.UnixMilli(q.EndTimestampMs).UnixNano()),
+	}
+	if qr.End <= qr.Start {
+		return nil, "", nil, fmt.Errorf("remote read query end must be after start. received start=%d end=%d", q.StartTimestampMs, q.EndTimestampMs)
+	}
+
+	if q.Hints != nil && q.Hints.StepMs > 0 {
+		qr.Step = uint64(time.Duration(q.Hints.StepMs) * time.Millisecond)
+	} else {
+		qr.Step = traceql.DefaultQueryRangeStep(qr.Start, qr.End)
+	}
+
+	return qr, name, filters, nil
+}
+
+// toRemoteReadSeries converts TraceQL metrics series into Prometheus series. Attribute names are sanitized into
+// valid Prometheus label names, e.g. resource.service.name becomes resource_service_name. Series that don't match
+// all matchers are dropped.
+func toRemoteReadSeries(series []*tempopb.TimeSeries, name string, matchers []*labels.Matcher) []*prompb.TimeSeries {
+	result := make([]*prompb.TimeSeries, 0, len(series))
+
+	for _, s := range series {
+		b := labels.NewScratchBuilder(len(s.Labels) + 1)
+		if name != "" {
+			b.Add(labels.MetricName, name)
+		}
+		for _, l := range s.Labels {
+			b.Add(strutil.SanitizeFullLabelName(l.Key), traceql.StaticFromAnyValue(l.Value).EncodeToString(false))
+		}
+		b.Sort()
+		lbls := b.Labels()
+
+		if !matchesAll(lbls, matchers) {
+			continue
+		}
+
+		ts := &prompb.TimeSeries{
+			Labels:  make([]prompb.Label, 0, lbls.Len()),
+			Samples: make([]prompb.Sample, 0, len(s.Samples)),
+		}
+		lbls.Range(func(l labels.Label) {
+			ts.Labels = append(ts.Labels, prompb.Label{Name: l.Name, Value: l.Value})
+		})
+		for _, sample := range s.Samples {
+			ts.Samples = append(ts.Samples, prompb.Sample{Timestamp: sample.TimestampMs, Value: sample.Value})
+		}
+		// remote read expects samples in timestamp order
+		sort.Slice(ts.Samples, func(i, j int) bool { return ts.Samples[i].Timestamp < ts.Samples[j].Timestamp })
+
+		result = append(result, ts)
+	}
+
+	return result
+}
+
+func matchesAll(lbls labels.Labels, matchers []*labels.Matcher) bool {
+	for _, m := range matchers {
+		if !m.Matches(lbls.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+func remoteReadBadRequest(err error) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Status:     http.StatusText(http.StatusBadRequest),
+		Body:       io.NopCloser(strings.NewReader(err.Error())),
+	}
+}
@@ -0,0 +1,148 @@
+package frontend
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/dskit/user"
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteReadHandlerSucceeds(t *testing.T) {
+	resp := &tempopb.QueryRangeResponse{
+		Metrics: &tempopb.SearchMetrics{
+			InspectedTraces: 1,
+			InspectedBytes:  1,
+		},
+		Series: []*tempopb.TimeSeries{
+			{
+				PromLabels: "foo",
+				Labels: []v1.KeyValue{
+					{Key: "resource.service.name", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "bar"}}},
+				},
+				Samples: []tempopb.Sample{
+					{
+						TimestampMs: 1200_000,
+						Value:       2,
+					},
+					{
+						TimestampMs: 1100_000,
+						Value:       1,
+					},
+				},
+			},
+		},
+	}
+
+	f := frontendWithSettings(t, &mockRoundTripper{
+		responseFn: func() proto.Message {
+			return resp
+		},
+	}, nil, nil, nil, func(c *Config, _ *overrides.Config) {
+		c.Metrics.Sharder.Interval = time.Hour
+	})
+
+	query := func(serviceName string) *prompb.Query {
+		return &prompb.Query{
+			StartTimestampMs: 1100_000,
+			EndTimestampMs:   1300_000,
+			Matchers: []*prompb.LabelMatcher{
+				{Type: prompb.LabelMatcher_EQ, Name: RemoteReadQueryLabel, Value: "{} | rate() by (resource.service.name)"},
+				{Type: prompb.LabelMatcher_EQ, Name: "__name__", Value: "traces_rate"},
+				{Type: prompb.LabelMatcher_EQ, Name: "resource_service_name", Value: serviceName},
+			},
+			Hints: &prompb.ReadHints{StepMs: 100_000},
+		}
+	}
+
+	httpResp := remoteRead(t, f, &prompb.ReadRequest{
+		Queries: []*prompb.Query{query("bar"), query("baz")},
+	})
+	require.Equal(t, 200, httpResp.Code)
+
+	b, err := snappy.Decode(nil, httpResp.Body.Bytes())
+	require.NoError(t, err)
+	actualResp := &prompb.ReadResponse{}
+	require.NoError(t, proto.Unmarshal(b, actualResp))
+
+	expectedResp := &prompb.ReadResponse{
+		Results: []*prompb.QueryResult{
+			{
+				Timeseries: []*prompb.TimeSeries{
+					{
+						Labels: []prompb.Label{
+							{Name: "__name__", Value: "traces_rate"},
+							{Name: "resource_service_name", Value: "bar"},
+						},
+						Samples: []prompb.Sample{
+							{Timestamp: 1100_000, Value: 4},
+							{Timestamp: 1200_000, Value: 8},
+							{Timestamp: 1300_000, Value: 0},
+						},
+					},
+				},
+			},
+			{}, // no series match the filter
+		},
+	}
+	require.Equal(t, expectedResp, actualResp)
+}
+
+func TestRemoteReadHandlerBadRequest(t *testing.T) {
+	f := frontendWithSettings(t, nil, nil, nil, nil)
+
+	tcs := []struct {
+		name  string
+		query *prompb.Query
+	}{
+		{
+			name: "no traceql query",
+			query: &prompb.Query{
+				StartTimestampMs: 1100_000,
+				EndTimestampMs:   1300_000,
+				Matchers: []*prompb.LabelMatcher{
+					{Type: prompb.LabelMatcher_EQ, Name: "__name__", Value: "traces_rate"},
+				},
+			},
+		},
+		{
+			name: "end before start",
+			query: &prompb.Query{
+				StartTimestampMs: 1300_000,
+				EndTimestampMs:   1100_000,
+				Matchers: []*prompb.LabelMatcher{
+					{Type: prompb.LabelMatcher_EQ, Name: RemoteReadQueryLabel, Value: "{} | rate()"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			httpResp := remoteRead(t, f, &prompb.ReadRequest{Queries: []*prompb.Query{tc.query}})
+			require.Equal(t, 400, httpResp.Code)
+		})
+	}
+}
+
+func remoteRead(t *testing.T, f *QueryFrontend, readReq *prompb.ReadRequest) *httptest.ResponseRecorder {
+	b, err := proto.Marshal(readReq)
+	require.NoError(t, err)
+
+	httpReq := httptest.NewRequest("POST", api.PathMetricsRemoteRead, io.NopCloser(bytes.NewReader(snappy.Encode(nil, b))))
+	httpReq = httpReq.WithContext(user.InjectOrgID(httpReq.Context(), "foo"))
+
+	httpResp := httptest.NewRecorder()
+	f.MetricsRemoteReadHandler.ServeHTTP(httpResp, httpReq)
+	return httpResp
+}
@@ -0,0 +1,101 @@
+package frontend
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gogo/status"
+	"github.com/grafana/dskit/user"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/grafana/tempo/modules/frontend/queryaudit"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+type streamingServer[T any] interface {
+	Send(T) error
+	grpc.ServerStream
+}
+
+type sizer interface {
+	Size() int
+}
+
+// auditedStream records a streaming gRPC query in the query audit log. Queries are recorded with the path of the
+// equivalent HTTP endpoint and the gRPC status code.
+type auditedStream[T sizer] struct {
+	streamingServer[T]
+
+	auditor *queryaudit.Auditor
+	orgID   string
+	record  queryaudit.Record
+}
+
+func newAuditedStream[T sizer](auditor *queryaudit.Auditor, srv streamingServer[T], endpoint, query string, start, end time.Time) *auditedStream[T] {
+	s := &auditedStream[T]{
+		streamingServer: srv,
+		auditor:         auditor,
+	}
+	if auditor == nil {
+		return s
+	}
+
+	ctx := srv.Context()
+	s.orgID, _ = user.ExtractOrgID(ctx)
+	s.record = queryaudit.Record{
+		Time:     time.Now(),
+		Endpoint: endpoint,
+		Query:    query,
+		Start:    start,
+		End:      end,
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(auditor.UserHeader()); len(v) > 0 {
+			s.record.User = v[0]
+		}
+	}
+
+	return s
+}
+
+func (s *auditedStream[T]) Send(m T) error {
+	if s.auditor != nil {
+		s.record.ResponseBytes += int64(m.Size())
+	}
+	return s.streamingServer.Send(m)
+}
+
+// done records the query with the error the stream ended with and returns the error.
+func (s *auditedStream[T]) done(err error) error {
+	if s.auditor != nil {
+		s.record.Status = status.Code(err).String()
+		s.auditor.Record(s.orgID, s.record)
+	}
+	return err
+}
+
+func tagValuesEndpoint(p, tagName string) string {
+	return strings.Replace(p, "{"+api.MuxVarTagName+"}", tagName, 1)
+}
+
+func traceByIDEndpoint(traceID []byte) string {
+	return strings.Replace(api.PathTracesV2, "{traceID}", util.TraceIDToHexString(traceID), 1)
+}
+
+// unixSeconds returns the time of a unix timestamp in seconds or a zero time if it's 0.
+func unixSeconds(s uint32) time.Time {
+	if s == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(s), 0)
+}
+
+// unixNanos returns the time of a unix timestamp in nanoseconds or a zero time if it's 0.
+func unixNanos(ns uint64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(ns))
+}
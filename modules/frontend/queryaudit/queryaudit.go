@@ -0,0 +1,532 @@
+package queryaudit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const (
+	// KeyPath is the path of the backend under which audit records are stored, outside of the tenant paths of the
+	// blocks. Objects are grouped by tenant and by the day they were written:
+	// tempo_query_audit/<tenant>/<yyyy-mm-dd>/<unix nanos>-<uuid>.json.gz
+	KeyPath = backend.QueryAuditDirName
+
+	// retentionLeaseName is the object of the frontend that applies the retention, in the KeyPath.
+	retentionLeaseName = "retention.json"
+
+	dayLayout    = "2006-01-02"
+	objectSuffix = ".json.gz"
+
+	// maxPendingRecords is the number of records kept per tenant while they can't be written to the backend.
+	maxPendingRecords = 100_000
+	// retentionInterval is how often records past the retention of their tenant are deleted.
+	retentionInterval = time.Hour
+	// retentionLeaseTimeout is how long the retention lease of a frontend is kept after it last applied the
+	// retention, after which another frontend takes over.
+	retentionLeaseTimeout = 3 * retentionInterval
+)
+
+var (
+	metricRecords = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "query_frontend_audit_records_total",
+		Help:      "Total number of queries recorded in the query audit log.",
+	}, []string{"tenant"})
+	metricDroppedRecords = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "query_frontend_audit_records_dropped_total",
+		Help:      "Total number of query audit records dropped because they could not be written to the backend.",
+	}, []string{"tenant"})
+	metricFlushFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "query_frontend_audit_flush_failed_total",
+		Help:      "Total number of failed writes of query audit records to the backend.",
+	}, []string{"tenant"})
+	metricDeletedObjects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "query_frontend_audit_deleted_objects_total",
+		Help:      "Total number of query audit objects deleted by retention.",
+	}, []string{"tenant"})
+)
+
+type Config struct {
+	// FlushPeriod is how often records are written to the backend.
+	FlushPeriod time.Duration `yaml:"flush_period"`
+	// UserHeader is the request header that identifies the user that sent the query.
+	UserHeader string `yaml:"user_header"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults() {
+	cfg.FlushPeriod = time.Minute
+	cfg.UserHeader = "X-Grafana-User"
+}
+
+// Overrides are the per tenant settings of the query audit log.
+type Overrides interface {
+	QueryAuditEnabled(userID string) bool
+	QueryAuditRetention(userID string) time.Duration
+}
+
+// Record is a query received by the frontend.
+type Record struct {
+	Time     time.Time `json:"time"`
+	Tenant   string    `json:"tenant"`
+	User     string    `json:"user,omitempty"`
+	Endpoint string    `json:"endpoint"`
+	Query    string    `json:"query,omitempty"`
+	// Start and End are the time range of the query. They're zero if the query has none.
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+	ResponseBytes int64     `json:"response_bytes"`
+	Status        string    `json:"status"`
+}
+
+// Auditor buffers the records of the tenants that have the query audit log enabled and writes them to the backend.
+type Auditor struct {
+	services.Service
+
+	cfg       Config
+	id        string
+	reader    backend.RawReader
+	writer    backend.RawWriter
+	versioned backend.VersionedReaderWriter
+	overrides Overrides
+	logger    log.Logger
+
+	mtx     sync.Mutex
+	pending map[string][]*Record
+
+	lastRetention time.Time
+}
+
+// New returns an auditor that stores the records in the backend.
+func New(cfg Config, reader backend.RawReader, writer backend.RawWriter, o Overrides, logger log.Logger) *Auditor {
+	// the retention lease is written with conditional writes when the backend supports them
+	versioned, ok := reader.(backend.VersionedReaderWriter)
+	if !ok {
+		versioned = backend.NewFakeVersionedReaderWriter(reader, writer)
+	}
+
+	id, err := os.Hostname()
+	if err != nil || id == "" {
+		id = uuid.NewString()
+	}
+
+	a := &Auditor{
+		cfg:       cfg,
+		id:        id,
+		reader:    reader,
+		writer:    writer,
+		versioned: versioned,
+		overrides: o,
+		logger:    logger,
+		pending:   make(map[string][]*Record),
+	}
+	a.Service = services.NewTimerService(cfg.FlushPeriod, nil, a.iteration, a.stopping).WithName("query audit")
+
+	return a
+}
+
+// Enabled returns true if the queries of the tenant are recorded.
+func (a *Auditor) Enabled(tenantID string) bool {
+	return a != nil && a.overrides.QueryAuditEnabled(tenantID)
+}
+
+// UserHeader returns the request header that identifies the user.
+func (a *Auditor) UserHeader() string {
+	if a == nil {
+		return ""
+	}
+	return a.cfg.UserHeader
+}
+
+// Record queues the record for each tenant of the org id that has the query audit log enabled.
+func (a *Auditor) Record(orgID string, rec Record) {
+	if a == nil {
+		return
+	}
+
+	tenants, err := tenant.TenantIDsFromOrgID(orgID)
+	if err != nil {
+		return
+	}
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	for _, tenantID := range tenants {
+		if !a.overrides.QueryAuditEnabled(tenantID) {
+			continue
+		}
+
+		r := rec
+		r.Tenant = tenantID
+		a.pending[tenantID] = append(a.pending[tenantID], &r)
+		a.trimPending(tenantID)
+		metricRecords.WithLabelValues(tenantID).Inc()
+	}
+}
+
+// trimPending drops the oldest pending records of the tenant above the limit. It must be called with the lock held.
+func (a *Auditor) trimPending(tenantID string) {
+	if dropped := len(a.pending[tenantID]) - maxPendingRecords; dropped > 0 {
+		a.pending[tenantID] = a.pending[tenantID][dropped:]
+		metricDroppedRecords.WithLabelValues(tenantID).Add(float64(dropped))
+	}
+}
+
+func (a *Auditor) iteration(ctx context.Context) error {
+	a.flush(ctx)
+
+	if time.Since(a.lastRetention) >= retentionInterval {
+		a.applyRetention(ctx)
+		a.lastRetention = time.Now()
+	}
+
+	return nil
+}
+
+func (a *Auditor) stopping(_ error) error {
+	a.flush(context.Background())
+	return nil
+}
+
+// flush writes the pending records of each tenant as one object. Records that fail to be written are kept
+// and retried on the next flush.
+func (a *Auditor) flush(ctx context.Context) {
+	a.mtx.Lock()
+	pending := a.pending
+	a.pending = make(map[string][]*Record, len(pending))
+	a.mtx.Unlock()
+
+	now := time.Now()
+	for tenantID, records := range pending {
+		if len(records) == 0 {
+			continue
+		}
+
+		err := writeRecords(ctx, a.writer, tenantID, records, now)
+		if err == nil {
+			continue
+		}
+
+		level.Error(a.logger).Log("msg", "failed to write query audit records", "tenant", tenantID, "records", len(records), "err", err)
+		metricFlushFailed.WithLabelValues(tenantID).Inc()
+
+		a.mtx.Lock()
+		a.pending[tenantID] = append(records, a.pending[tenantID]...)
+		a.trimPending(tenantID)
+		a.mtx.Unlock()
+	}
+}
+
+// retentionLease is held by the frontend that applies the retention of the query audit log.
+type retentionLease struct {
+	Owner string    `json:"owner"`
+	Time  time.Time `json:"time"`
+}
+
+// acquireRetentionLease returns true if this frontend holds the retention lease. The lease is taken over if its
+// owner hasn't renewed it within the lease timeout.
+func (a *Auditor) acquireRetentionLease(ctx context.Context) (bool, error) {
+	version := backend.VersionNew
+
+	rc, v, err := a.versioned.ReadVersioned(ctx, retentionLeaseName, backend.KeyPath{KeyPath})
+	switch {
+	case errors.Is(err, backend.ErrDoesNotExist):
+	case err != nil:
+		return false, err
+	default:
+		lease := &retentionLease{}
+		err = json.NewDecoder(rc).Decode(lease)
+		rc.Close()
+		if err == nil && lease.Owner != a.id && time.Since(lease.Time) < retentionLeaseTimeout {
+			return false, nil
+		}
+		version = v
+	}
+
+	b, err := json.Marshal(&retentionLease{Owner: a.id, Time: time.Now()})
+	if err != nil {
+		return false, err
+	}
+	_, err = a.versioned.WriteVersioned(ctx, retentionLeaseName, backend.KeyPath{KeyPath}, bytes.NewReader(b), int64(len(b)), version)
+	if errors.Is(err, backend.ErrVersionDoesNotMatch) {
+		// another frontend took the lease first
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// applyRetention deletes the objects of every tenant with a retention that were written before it. Only the
+// frontend holding the retention lease applies it.
+func (a *Auditor) applyRetention(ctx context.Context) {
+	owner, err := a.acquireRetentionLease(ctx)
+	if err != nil {
+		level.Error(a.logger).Log("msg", "failed to acquire the query audit retention lease", "err", err)
+		return
+	}
+	if !owner {
+		return
+	}
+
+	tenants, err := a.reader.List(ctx, backend.KeyPath{KeyPath})
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, backend.ErrDoesNotExist) {
+		return
+	}
+	if err != nil {
+		level.Error(a.logger).Log("msg", "failed to list tenants for query audit retention", "err", err)
+		return
+	}
+
+	now := time.Now()
+	for _, tenantID := range tenants {
+		retention := a.overrides.QueryAuditRetention(tenantID)
+		if retention <= 0 {
+			continue
+		}
+
+		deleted, err := deleteRecordsBefore(ctx, a.reader, a.writer, tenantID, now.Add(-retention))
+		if err != nil {
+			level.Error(a.logger).Log("msg", "failed to apply query audit retention", "tenant", tenantID, "err", err)
+		}
+		if deleted > 0 {
+			level.Info(a.logger).Log("msg", "deleted query audit objects", "tenant", tenantID, "objects", deleted)
+			metricDeletedObjects.WithLabelValues(tenantID).Add(float64(deleted))
+		}
+	}
+}
+
+// SearchOptions select the records returned by Search.
+type SearchOptions struct {
+	// Start and End bound the time the queries were received. Zero values are unbounded.
+	Start time.Time
+	End   time.Time
+	// User only returns the records of the user if set.
+	User string
+	// Contains only returns the records with a query that contains it if set.
+	Contains string
+}
+
+func (o SearchOptions) matches(r *Record) bool {
+	if !o.Start.IsZero() && r.Time.Before(o.Start) {
+		return false
+	}
+	if !o.End.IsZero() && r.Time.After(o.End) {
+		return false
+	}
+	if o.User != "" && r.User != o.User {
+		return false
+	}
+	return o.Contains == "" || strings.Contains(r.Query, o.Contains)
+}
+
+// Search calls f in the order they were written for each record of the tenant that matches the options.
+func Search(ctx context.Context, r backend.RawReader, tenantID string, opts SearchOptions, f func(*Record) error) error {
+	days, err := listDays(ctx, r, tenantID)
+	if err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		// objects are written after the records they hold so the day after the end of the range is included.
+		if !opts.Start.IsZero() && day.Before(truncateDay(opts.Start)) {
+			continue
+		}
+		if !opts.End.IsZero() && day.After(truncateDay(opts.End).AddDate(0, 0, 1)) {
+			continue
+		}
+
+		objects, err := listObjects(ctx, r, tenantID, day)
+		if err != nil {
+			return err
+		}
+
+		for _, o := range objects {
+			if !opts.Start.IsZero() && o.written.Before(opts.Start) {
+				continue
+			}
+
+			records, err := readRecords(ctx, r, tenantID, day, o.name)
+			if err != nil {
+				return fmt.Errorf("error reading query audit object %s: %w", o.name, err)
+			}
+
+			for _, rec := range records {
+				if !opts.matches(rec) {
+					continue
+				}
+				if err := f(rec); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func writeRecords(ctx context.Context, w backend.RawWriter, tenantID string, records []*Record, now time.Time) error {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	enc := json.NewEncoder(gz)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%s%s", now.UnixNano(), uuid.NewString(), objectSuffix)
+	return w.Write(ctx, name, dayKeyPath(tenantID, now), bytes.NewReader(buf.Bytes()), int64(buf.Len()), nil)
+}
+
+func readRecords(ctx context.Context, r backend.RawReader, tenantID string, day time.Time, name string) ([]*Record, error) {
+	reader, _, err := r.Read(ctx, name, dayKeyPath(tenantID, day), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	gz, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var records []*Record
+	dec := json.NewDecoder(gz)
+	for {
+		rec := &Record{}
+		err := dec.Decode(rec)
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+}
+
+func deleteRecordsBefore(ctx context.Context, r backend.RawReader, w backend.RawWriter, tenantID string, cutoff time.Time) (int, error) {
+	days, err := listDays(ctx, r, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, day := range days {
+		if day.After(cutoff) {
+			break
+		}
+
+		objects, err := listObjects(ctx, r, tenantID, day)
+		if err != nil {
+			return deleted, err
+		}
+
+		for _, o := range objects {
+			if !o.written.Before(cutoff) {
+				continue
+			}
+
+			err := w.Delete(ctx, o.name, dayKeyPath(tenantID, day), nil)
+			if err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
+				return deleted, err
+			}
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// listDays returns the days the tenant has objects for in ascending order.
+func listDays(ctx context.Context, r backend.RawReader, tenantID string) ([]time.Time, error) {
+	names, err := r.List(ctx, backend.KeyPath{KeyPath, tenantID})
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, backend.ErrDoesNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]time.Time, 0, len(names))
+	for _, name := range names {
+		day, err := time.Parse(dayLayout, name)
+		if err != nil {
+			continue
+		}
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	return days, nil
+}
+
+type object struct {
+	name    string
+	written time.Time
+}
+
+// listObjects returns the objects of the day in the order they were written.
+func listObjects(ctx context.Context, r backend.RawReader, tenantID string, day time.Time) ([]object, error) {
+	var objects []object
+	err := r.Find(ctx, dayKeyPath(tenantID, day), func(m backend.FindMatch) {
+		name := path.Base(m.Key)
+		if !strings.HasSuffix(name, objectSuffix) {
+			return
+		}
+
+		nanos, _, _ := strings.Cut(name, "-")
+		n, err := strconv.ParseInt(nanos, 10, 64)
+		if err != nil {
+			return
+		}
+		objects = append(objects, object{name: name, written: time.Unix(0, n)})
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].written.Before(objects[j].written) })
+
+	return objects, nil
+}
+
+func dayKeyPath(tenantID string, t time.Time) backend.KeyPath {
+	return backend.KeyPath{KeyPath, tenantID, t.UTC().Format(dayLayout)}
+}
+
+func truncateDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,166 @@
+package queryaudit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+)
+
+type mockOverrides struct {
+	enabled   map[string]bool
+	retention map[string]time.Duration
+}
+
+func (m *mockOverrides) QueryAuditEnabled(userID string) bool {
+	return m.enabled[userID]
+}
+
+func (m *mockOverrides) QueryAuditRetention(userID string) time.Duration {
+	return m.retention[userID]
+}
+
+type failingWriter struct {
+	backend.RawWriter
+}
+
+func (f *failingWriter) Write(context.Context, string, backend.KeyPath, io.Reader, int64, *backend.CacheInfo) error {
+	return errors.New("write failed")
+}
+
+func newTestAuditor(t *testing.T, o *mockOverrides) (*Auditor, backend.RawReader) {
+	r, w, _, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults()
+
+	return New(cfg, r, w, o, log.NewNopLogger()), r
+}
+
+func search(t *testing.T, r backend.RawReader, tenantID string, opts SearchOptions) []*Record {
+	var records []*Record
+	err := Search(context.Background(), r, tenantID, opts, func(rec *Record) error {
+		records = append(records, rec)
+		return nil
+	})
+	require.NoError(t, err)
+	return records
+}
+
+func TestAuditorRecordsEnabledTenants(t *testing.T) {
+	a, r := newTestAuditor(t, &mockOverrides{enabled: map[string]bool{"a": true, "b": true}})
+
+	now := time.Now().Truncate(time.Second)
+	a.Record("a", Record{Time: now, User: "alice", Endpoint: "/api/search", Query: "{ .foo = `bar` }", ResponseBytes: 10, Status: "200"})
+	a.Record("c", Record{Time: now, User: "carol", Endpoint: "/api/search", Status: "200"})
+	a.Record("b|c", Record{Time: now.Add(time.Second), User: "bob", Endpoint: "/api/metrics/query_range", Query: "{} | rate()", Status: "200"})
+	a.flush(context.Background())
+
+	records := search(t, r, "a", SearchOptions{})
+	require.Len(t, records, 1)
+	require.Equal(t, "a", records[0].Tenant)
+	require.Equal(t, "alice", records[0].User)
+	require.Equal(t, "{ .foo = `bar` }", records[0].Query)
+	require.Equal(t, int64(10), records[0].ResponseBytes)
+	require.True(t, now.Equal(records[0].Time))
+
+	records = search(t, r, "b", SearchOptions{})
+	require.Len(t, records, 1)
+	require.Equal(t, "b", records[0].Tenant)
+	require.Equal(t, "bob", records[0].User)
+
+	require.Empty(t, search(t, r, "c", SearchOptions{}))
+}
+
+func TestSearchOptions(t *testing.T) {
+	a, r := newTestAuditor(t, &mockOverrides{enabled: map[string]bool{"a": true}})
+
+	now := time.Now()
+	a.Record("a", Record{Time: now.Add(-2 * time.Hour), User: "alice", Query: "{ .foo = `bar` }"})
+	a.flush(context.Background())
+	a.Record("a", Record{Time: now.Add(-time.Hour), User: "bob", Query: "{ .foo = `baz` }"})
+	a.Record("a", Record{Time: now, User: "alice", Query: "{} | rate()"})
+	a.flush(context.Background())
+
+	queries := func(opts SearchOptions) []string {
+		var queries []string
+		for _, rec := range search(t, r, "a", opts) {
+			queries = append(queries, rec.Query)
+		}
+		return queries
+	}
+
+	require.Equal(t, []string{"{ .foo = `bar` }", "{ .foo = `baz` }", "{} | rate()"}, queries(SearchOptions{}))
+	require.Equal(t, []string{"{ .foo = `bar` }", "{} | rate()"}, queries(SearchOptions{User: "alice"}))
+	require.Equal(t, []string{"{ .foo = `bar` }", "{ .foo = `baz` }"}, queries(SearchOptions{Contains: ".foo"}))
+	require.Equal(t, []string{"{ .foo = `baz` }", "{} | rate()"}, queries(SearchOptions{Start: now.Add(-90 * time.Minute)}))
+	require.Equal(t, []string{"{ .foo = `bar` }"}, queries(SearchOptions{End: now.Add(-90 * time.Minute)}))
+	require.Empty(t, queries(SearchOptions{Start: now.Add(time.Minute)}))
+}
+
+func TestAuditorRetention(t *testing.T) {
+	o := &mockOverrides{
+		enabled:   map[string]bool{"a": true, "b": true},
+		retention: map[string]time.Duration{"a": 24 * time.Hour},
+	}
+	a, r := newTestAuditor(t, o)
+
+	now := time.Now()
+	for _, tenantID := range []string{"a", "b"} {
+		for _, written := range []time.Time{now.Add(-72 * time.Hour), now.Add(-25 * time.Hour), now.Add(-time.Hour)} {
+			err := writeRecords(context.Background(), a.writer, tenantID, []*Record{{Time: written, Tenant: tenantID}}, written)
+			require.NoError(t, err)
+		}
+	}
+
+	// another frontend holds the retention lease
+	other := New(a.cfg, a.reader, a.writer, o, log.NewNopLogger())
+	other.id = "other"
+	owner, err := other.acquireRetentionLease(context.Background())
+	require.NoError(t, err)
+	require.True(t, owner)
+
+	a.applyRetention(context.Background())
+	require.Len(t, search(t, r, "a", SearchOptions{}), 3)
+
+	// until it expires
+	expired, err := json.Marshal(&retentionLease{Owner: "other", Time: now.Add(-retentionLeaseTimeout)})
+	require.NoError(t, err)
+	require.NoError(t, a.writer.Write(context.Background(), retentionLeaseName, backend.KeyPath{KeyPath}, bytes.NewReader(expired), int64(len(expired)), nil))
+
+	a.applyRetention(context.Background())
+
+	// tenant a keeps the records of the last day, tenant b has no retention and keeps all of them
+	require.Len(t, search(t, r, "a", SearchOptions{}), 1)
+	require.Len(t, search(t, r, "b", SearchOptions{}), 3)
+
+	// the audit log is kept out of the tenants of the blocks
+	tenants, err := backend.NewReader(r).Tenants(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, tenants)
+}
+
+func TestAuditorKeepsRecordsOnFailedFlush(t *testing.T) {
+	a, r := newTestAuditor(t, &mockOverrides{enabled: map[string]bool{"a": true}})
+	w := a.writer
+	a.writer = &failingWriter{RawWriter: w}
+
+	a.Record("a", Record{Time: time.Now(), Query: "{}"})
+	a.flush(context.Background())
+	require.Len(t, a.pending["a"], 1)
+
+	a.writer = w
+	require.NoError(t, a.stopping(nil))
+	require.Empty(t, a.pending["a"])
+	require.Len(t, search(t, r, "a", SearchOptions{}), 1)
+}
@@ -143,7 +143,12 @@ func newCombiner(req *tempopb.SearchRequest, cfg SearchSharderConfig) (combiner.
 		}
 	}
 
-	return combiner.NewTypedSearch(int(limit), mostRecent), nil
+	order, err := traceql.ParseSearchOrder(req.OrderBy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order_by: %w", err)
+	}
+
+	return combiner.NewTypedOrderedSearch(int(limit), order, mostRecent), nil
 }
 
 // adjusts the limit based on provided config
@@ -9,6 +9,8 @@ import (
 
 	"github.com/go-kit/log" //nolint:all deprecated
 	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/segmentio/fasthash/fnv1a"
 
 	"github.com/grafana/tempo/modules/frontend/combiner"
@@ -27,6 +29,12 @@ const (
 	defaultMostRecentShards      = 200
 )
 
+var staleBlocklistQueries = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "query_frontend_stale_blocklist_queries_total",
+	Help:      "Total queries per tenant served with a stale blocklist, recent blocks may be missing from their results.",
+}, []string{"tenant", "op"})
+
 type SearchSharderConfig struct {
 	ConcurrentRequests    int           `yaml:"concurrent_jobs,omitempty"`
 	TargetBytesPerRequest int           `yaml:"target_bytes_per_job,omitempty"`
@@ -102,21 +110,39 @@ func (s asyncSearchSharder) RoundTrip(pipelineRequest pipeline.Request) (pipelin
 		return pipeline.NewBadRequest(fmt.Errorf("spans per span set exceeds %d. received %d", s.cfg.MaxSpansPerSpanSet, searchReq.SpansPerSpanSet)), nil
 	}
 
+	// the query mode allows callers to trade freshness for load on the write path by skipping the ingesters,
+	// or to only search recent data by skipping the backend
+	queryMode := pipelineRequest.HTTPRequest().URL.Query().Get(api.QueryModeKey)
+	switch queryMode {
+	case "", api.QueryModeAll, api.QueryModeIngesters, api.QueryModeBlocks:
+	default:
+		return pipeline.NewBadRequest(fmt.Errorf("invalid value for %s: %q, must be one of %s, %s or %s", api.QueryModeKey, queryMode, api.QueryModeAll, api.QueryModeIngesters, api.QueryModeBlocks)), nil
+	}
+
 	// buffer of shards+1 allows us to insert ingestReq and metrics
 	reqCh := make(chan pipeline.Request, s.cfg.IngesterShards+1)
 
 	// build request to search ingesters based on query_ingesters_until config and time range
 	// pass subCtx in requests so we can cancel and exit early
-	jobMetrics, err := s.ingesterRequests(tenantID, pipelineRequest, *searchReq, reqCh)
-	if err != nil {
-		return nil, err
+	jobMetrics := &combiner.SearchJobResponse{
+		Shards: make([]combiner.SearchShards, 0, s.cfg.MostRecentShards),
+	}
+	if queryMode != api.QueryModeBlocks {
+		jobMetrics, err = s.ingesterRequests(tenantID, pipelineRequest, *searchReq, reqCh)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// pass subCtx in requests so we can cancel and exit early
-	s.backendRequests(ctx, tenantID, pipelineRequest, searchReq, jobMetrics, reqCh, func(err error) {
-		// todo: actually find a way to return this error to the user
-		s.logger.Log("msg", "search: failed to build backend requests", "err", err)
-	})
+	if queryMode == api.QueryModeIngesters {
+		close(reqCh)
+	} else {
+		// pass subCtx in requests so we can cancel and exit early
+		s.backendRequests(ctx, tenantID, pipelineRequest, searchReq, jobMetrics, reqCh, func(err error) {
+			// todo: actually find a way to return this error to the user
+			s.logger.Log("msg", "search: failed to build backend requests", "err", err)
+		})
+	}
 
 	// execute requests
 	return pipeline.NewAsyncSharderChan(ctx, s.cfg.ConcurrentRequests, reqCh, pipeline.NewAsyncResponse(jobMetrics), s.next), nil
@@ -149,10 +175,17 @@ func (s *asyncSearchSharder) backendRequests(ctx context.Context, tenantID strin
 		rf1After = s.cfg.RF1After
 	}
 
-	blocks := blockMetasForSearch(s.reader.BlockMetas(tenantID), startT, endT, rf1FilterFn(rf1After))
+	filterFn := rf1FilterFn(rf1After)
+	if requiresErrorStatus(searchReq.Query) {
+		filterFn = errorTimesFilterFn(startT, endT, filterFn)
+	}
+
+	blocks := blockMetasForSearch(s.reader.BlockMetasInRange(tenantID, startT, endT), startT, endT, filterFn)
 
 	// calculate metrics to return to the caller
 	resp.TotalBlocks = len(blocks)
+	resp.Warnings = staleBlocklistWarnings(s.reader, tenantID, searchOp)
+	resp.Warnings = append(resp.Warnings, archivePendingWarnings(s.overrides, tenantID, blocks)...)
 
 	firstShardIdx := len(resp.Shards)
 	blockIter := backendJobsFunc(blocks, s.cfg.TargetBytesPerRequest, s.cfg.MostRecentShards, searchReq.End)
@@ -171,6 +204,35 @@ func (s *asyncSearchSharder) backendRequests(ctx context.Context, tenantID strin
 	}()
 }
 
+// staleBlocklistWarnings returns a warning if the blocklist of the tenant is stale, the blocks searched in the backend
+// may then miss recent ones. The query still searches the ingesters and the blocks of the stale blocklist.
+func staleBlocklistWarnings(reader tempodb.Reader, tenantID, op string) []*tempopb.QueryWarning {
+	if !reader.BlocklistStale(tenantID) {
+		return nil
+	}
+	staleBlocklistQueries.WithLabelValues(tenantID, op).Inc()
+	return []*tempopb.QueryWarning{tempopb.NewQueryWarning(tempopb.WarningStaleBlocklist, "the blocklist wasn't polled recently, recent blocks may be missing")}
+}
+
+// archivePendingWarnings returns a warning if some of the blocks are in the archive tier of the tenant. Blocks are
+// searched most recent first and archived blocks are the oldest ones, so the jobs of the fast tier are executed first
+// and their results are streamed while the archive jobs are running. The combiners flag the partial results with
+// the warning and drop it once all jobs completed.
+func archivePendingWarnings(o overrides.Interface, tenantID string, blocks []*backend.BlockMeta) []*tempopb.QueryWarning {
+	archiveAfter, _ := o.BlockArchive(tenantID)
+	if archiveAfter == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for _, b := range blocks {
+		if backend.IsBlockArchived(b, archiveAfter, now) {
+			return []*tempopb.QueryWarning{tempopb.NewQueryWarning(tempopb.WarningArchivePending, "some blocks are in the archive tier and are still being searched")}
+		}
+	}
+	return nil
+}
+
 // ingesterRequest returns a new start and end time range for the backend as well as an http request
 // that covers the ingesters. If nil is returned for the http.Request then there is no ingesters query.
 // since this function modifies searchReq.Start and End we are taking a value instead of a pointer to prevent it from
@@ -364,6 +426,10 @@ func hashForSearchRequest(searchRequest *tempopb.SearchRequest) uint64 {
 	hash := fnv1a.HashString64(query)
 	hash = fnv1a.AddUint64(hash, uint64(searchRequest.Limit))
 	hash = fnv1a.AddUint64(hash, uint64(searchRequest.SpansPerSpanSet))
+	// the order decides which results a job keeps within the limit
+	if searchRequest.OrderBy != "" {
+		hash = fnv1a.AddString64(hash, searchRequest.OrderBy)
+	}
 
 	return hash
 }
@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/grafana/dskit/user"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -41,6 +43,7 @@ var _ tempodb.Reader = (*mockReader)(nil)
 type mockReader struct {
 	metas   []*backend.BlockMeta
 	tenants []string
+	stale   bool
 }
 
 func (m *mockReader) SearchTags(context.Context, *backend.BlockMeta, *tempopb.SearchTagsBlockRequest, common.SearchOptions) (*tempopb.SearchTagsV2Response, error) {
@@ -63,6 +66,10 @@ func (m *mockReader) Find(context.Context, string, common.ID, string, string, in
 	return nil, nil, nil
 }
 
+func (m *mockReader) FindMany(context.Context, string, []common.ID, string, string, int64, int64, common.SearchOptions) ([][]*tempopb.Trace, *tempopb.TraceByIDMetrics, []error, error) {
+	return nil, nil, nil, nil
+}
+
 func (m *mockReader) BlockMeta(context.Context, string, backend.UUID) (*backend.BlockMeta, *backend.CompactedBlockMeta, error) {
 	return nil, nil, nil
 }
@@ -71,6 +78,14 @@ func (m *mockReader) BlockMetas(string) []*backend.BlockMeta {
 	return m.metas
 }
 
+func (m *mockReader) BlockMetasInRange(string, time.Time, time.Time) []*backend.BlockMeta {
+	return m.metas
+}
+
+func (m *mockReader) ValidateBlock(context.Context, string, backend.UUID) error {
+	return nil
+}
+
 func (m *mockReader) Tenants() []string {
 	return m.tenants
 }
@@ -89,10 +104,17 @@ func (m *mockReader) FetchTagNames(context.Context, *backend.BlockMeta, traceql.
 
 func (m *mockReader) EnablePolling(context.Context, blocklist.JobSharder, bool) {}
 func (m *mockReader) PollNow(context.Context)                                   {}
+func (m *mockReader) BlocklistStale(string) bool                                { return m.stale }
 func (m *mockReader) Shutdown()                                                 {}
 
 //nolint:all deprecated
 
+func newTestOverrides(t *testing.T) overrides.Interface {
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+	return o
+}
+
 func TestBuildBackendRequests(t *testing.T) {
 	tests := []struct {
 		targetBytesPerRequest int
@@ -392,7 +414,8 @@ func TestBackendRequests(t *testing.T) {
 		cfg: SearchSharderConfig{
 			MostRecentShards: defaultMostRecentShards,
 		},
-		reader: &mockReader{metas: []*backend.BlockMeta{bm}},
+		reader:    &mockReader{metas: []*backend.BlockMeta{bm}},
+		overrides: newTestOverrides(t),
 	}
 
 	tests := []struct {
@@ -855,6 +878,94 @@ func TestTotalJobsIncludesIngester(t *testing.T) {
 	assert.Equal(t, 3, totalJobs)
 }
 
+func TestSearchSharderQueryMode(t *testing.T) {
+	tests := []struct {
+		mode             string
+		expectedJobs     int
+		expectedRequests []string
+	}{
+		{mode: "", expectedJobs: 3, expectedRequests: []string{"ingester", "backend", "backend"}},
+		{mode: api.QueryModeAll, expectedJobs: 3, expectedRequests: []string{"ingester", "backend", "backend"}},
+		{mode: api.QueryModeBlocks, expectedJobs: 2, expectedRequests: []string{"backend", "backend"}},
+		{mode: api.QueryModeIngesters, expectedJobs: 1, expectedRequests: []string{"ingester"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.mode, func(t *testing.T) {
+			var (
+				mtx      sync.Mutex
+				requests []string
+			)
+			next := pipeline.AsyncRoundTripperFunc[combiner.PipelineResponse](func(r pipeline.Request) (pipeline.Responses[combiner.PipelineResponse], error) {
+				mtx.Lock()
+				if api.IsSearchBlock(r.HTTPRequest()) {
+					requests = append(requests, "backend")
+				} else {
+					requests = append(requests, "ingester")
+				}
+				mtx.Unlock()
+
+				resString, err := (&jsonpb.Marshaler{}).MarshalToString(&tempopb.SearchResponse{
+					Metrics: &tempopb.SearchMetrics{},
+				})
+				require.NoError(t, err)
+
+				return pipeline.NewHTTPToAsyncResponse(&http.Response{
+					Body:       io.NopCloser(strings.NewReader(resString)),
+					StatusCode: 200,
+				}), nil
+			})
+
+			o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.DefaultRegisterer)
+			require.NoError(t, err)
+
+			now := time.Now().Add(-10 * time.Minute).Unix()
+
+			sharder := newAsyncSearchSharder(&mockReader{
+				metas: []*backend.BlockMeta{ // one block with 2 records that are each the target bytes per request will force 2 sub queries
+					{
+						StartTime:    time.Unix(now, 0),
+						EndTime:      time.Unix(now, 0),
+						Size_:        defaultTargetBytesPerRequest * 2,
+						TotalRecords: 2,
+						BlockID:      backend.MustParse("00000000-0000-0000-0000-000000000000"),
+					},
+				},
+			}, o, SearchSharderConfig{
+				QueryIngestersUntil:   15 * time.Minute,
+				ConcurrentRequests:    1, // 1 concurrent request to force order
+				TargetBytesPerRequest: defaultTargetBytesPerRequest,
+				MostRecentShards:      defaultMostRecentShards,
+				IngesterShards:        1,
+			}, log.NewNopLogger())
+			testRT := sharder.Wrap(next)
+
+			path := fmt.Sprintf("/?start=%d&end=%d&mode=%s", now-1, now+1, tc.mode)
+			req := httptest.NewRequest("GET", path, nil)
+			req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+
+			resps, err := testRT.RoundTrip(pipeline.NewHTTPRequest(req))
+			require.NoError(t, err)
+
+			totalJobs := 0
+			for {
+				res, done, err := resps.Next(context.Background())
+				require.NoError(t, err)
+
+				if res != nil && res.IsMetadata() {
+					totalJobs += res.(*combiner.SearchJobResponse).TotalJobs
+				}
+				if done {
+					break
+				}
+			}
+
+			assert.Equal(t, tc.expectedJobs, totalJobs)
+			assert.Equal(t, tc.expectedRequests, requests)
+		})
+	}
+}
+
 func TestSearchSharderRoundTripBadRequest(t *testing.T) {
 	next := pipeline.AsyncRoundTripperFunc[combiner.PipelineResponse](func(_ pipeline.Request) (pipeline.Responses[combiner.PipelineResponse], error) {
 		return nil, nil
@@ -889,6 +1000,12 @@ func TestSearchSharderRoundTripBadRequest(t *testing.T) {
 	resp, err = testRT.RoundTrip(pipeline.NewHTTPRequest(req))
 	testBadRequestFromResponses(t, resp, err, "spans per span set exceeds 100. received 200")
 
+	// unknown query mode
+	req = httptest.NewRequest("GET", "/?mode=fresh", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = testRT.RoundTrip(pipeline.NewHTTPRequest(req))
+	testBadRequestFromResponses(t, resp, err, `invalid value for mode: "fresh", must be one of all, ingesters or blocks`)
+
 	// bad request
 	req = httptest.NewRequest("GET", "/?start=asdf&end=1500", nil)
 	resp, err = testRT.RoundTrip(pipeline.NewHTTPRequest(req))
@@ -1005,6 +1122,11 @@ func TestHashTraceQLQuery(t *testing.T) {
 	h2 = hashForSearchRequest(&tempopb.SearchRequest{Query: "{ span.foo = `baz` }"})
 	require.NotEqual(t, h1, h2)
 
+	// different orders should have different hashes
+	h1 = hashForSearchRequest(&tempopb.SearchRequest{Query: "{ span.foo = `bar` }", OrderBy: "duration desc"})
+	h2 = hashForSearchRequest(&tempopb.SearchRequest{Query: "{ span.foo = `bar` }"})
+	require.NotEqual(t, h1, h2)
+
 	// invalid queries should return 0
 	h1 = hashForSearchRequest(&tempopb.SearchRequest{Query: "{ span.foo = `bar` "})
 	require.Equal(t, uint64(0), h1)
@@ -1164,6 +1286,7 @@ func TestRF1After(t *testing.T) {
 		cfg: SearchSharderConfig{
 			MostRecentShards: defaultMostRecentShards,
 		},
+		overrides: newTestOverrides(t),
 	}
 	s.reader = &mockReader{metas: blockMetas}
 
@@ -1174,6 +1297,152 @@ func TestRF1After(t *testing.T) {
 	require.Equal(t, 2, searchJobResponse.TotalBlocks) // Verify the expected number of blocks after filtering
 }
 
+func TestBackendRequestsStaleBlocklist(t *testing.T) {
+	blockMetas := []*backend.BlockMeta{
+		{StartTime: time.Unix(100, 0), EndTime: time.Unix(200, 0), ReplicationFactor: backend.DefaultReplicationFactor},
+	}
+
+	for _, stale := range []bool{false, true} {
+		r := httptest.NewRequest("GET", "/?q={}&start=50&end=300", nil)
+		searchReq, err := api.ParseSearchRequest(r)
+		require.NoError(t, err)
+
+		ctx, cancelCause := context.WithCancelCause(context.Background())
+		s := &asyncSearchSharder{
+			cfg: SearchSharderConfig{
+				MostRecentShards: defaultMostRecentShards,
+			},
+			reader:    &mockReader{metas: blockMetas, stale: stale},
+			overrides: newTestOverrides(t),
+		}
+
+		staleQueries := testutil.ToFloat64(staleBlocklistQueries.WithLabelValues("test", searchOp))
+
+		searchJobResponse := &combiner.SearchJobResponse{}
+		s.backendRequests(ctx, "test", pipeline.NewHTTPRequest(r), searchReq, searchJobResponse, make(chan pipeline.Request), cancelCause)
+		cancelCause(nil)
+
+		// the blocks of a stale blocklist are still searched
+		require.Equal(t, 1, searchJobResponse.TotalBlocks)
+
+		if !stale {
+			require.Empty(t, searchJobResponse.Warnings)
+			require.Equal(t, staleQueries, testutil.ToFloat64(staleBlocklistQueries.WithLabelValues("test", searchOp)))
+			continue
+		}
+		require.Len(t, searchJobResponse.Warnings, 1)
+		require.Equal(t, tempopb.WarningStaleBlocklist, searchJobResponse.Warnings[0].Code)
+		require.Equal(t, staleQueries+1, testutil.ToFloat64(staleBlocklistQueries.WithLabelValues("test", searchOp)))
+	}
+}
+
+func TestBackendRequestsArchivePending(t *testing.T) {
+	now := time.Now()
+	fast := &backend.BlockMeta{BlockID: backend.NewUUID(), TotalRecords: 1, Size_: 100, StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour), ReplicationFactor: backend.DefaultReplicationFactor}
+	archived := &backend.BlockMeta{BlockID: backend.NewUUID(), TotalRecords: 1, Size_: 100, StartTime: now.Add(-4 * time.Hour), EndTime: now.Add(-3 * time.Hour), ReplicationFactor: backend.DefaultReplicationFactor}
+
+	o, err := overrides.NewOverrides(overrides.Config{
+		Defaults: overrides.Overrides{
+			Compaction: overrides.CompactionOverrides{
+				ArchiveAfter: model.Duration(2 * time.Hour),
+			},
+		},
+	}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		blocks  []*backend.BlockMeta
+		pending bool
+	}{
+		{blocks: []*backend.BlockMeta{fast}},
+		{blocks: []*backend.BlockMeta{archived, fast}, pending: true},
+	} {
+		r := httptest.NewRequest("GET", fmt.Sprintf("/?q={}&start=%d&end=%d", now.Add(-5*time.Hour).Unix(), now.Unix()), nil)
+		searchReq, err := api.ParseSearchRequest(r)
+		require.NoError(t, err)
+
+		ctx, cancelCause := context.WithCancelCause(context.Background())
+		s := &asyncSearchSharder{
+			cfg: SearchSharderConfig{
+				MostRecentShards:      defaultMostRecentShards,
+				TargetBytesPerRequest: 1000,
+			},
+			reader:    &mockReader{metas: tc.blocks},
+			overrides: o,
+		}
+
+		reqCh := make(chan pipeline.Request)
+		searchJobResponse := &combiner.SearchJobResponse{}
+		s.backendRequests(ctx, "test", pipeline.NewHTTPRequest(r), searchReq, searchJobResponse, reqCh, cancelCause)
+
+		// the jobs of the fast tier are executed first
+		var blockIDs []string
+		for req := range reqCh {
+			blockIDs = append(blockIDs, req.HTTPRequest().URL.Query().Get("blockID"))
+		}
+		cancelCause(nil)
+		require.Equal(t, fast.BlockID.String(), blockIDs[0])
+
+		if !tc.pending {
+			require.Empty(t, searchJobResponse.Warnings)
+			continue
+		}
+		require.Len(t, searchJobResponse.Warnings, 1)
+		require.Equal(t, tempopb.WarningArchivePending, searchJobResponse.Warnings[0].Code)
+	}
+}
+
+func TestBackendRequestsSkipsBlocksWithoutErrors(t *testing.T) {
+	errorTime := func(s int64) *time.Time {
+		t := time.Unix(s, 0)
+		return &t
+	}
+
+	blockMetas := []*backend.BlockMeta{
+		// error times not tracked
+		{StartTime: time.Unix(100, 0), EndTime: time.Unix(300, 0)},
+		// no errors
+		{StartTime: time.Unix(100, 0), EndTime: time.Unix(300, 0), ErrorTimesTracked: true},
+		// errors before the search range
+		{StartTime: time.Unix(100, 0), EndTime: time.Unix(300, 0), ErrorTimesTracked: true, ErrorStartTime: errorTime(100), ErrorEndTime: errorTime(150)},
+		// errors in the search range
+		{StartTime: time.Unix(100, 0), EndTime: time.Unix(300, 0), ErrorTimesTracked: true, ErrorStartTime: errorTime(150), ErrorEndTime: errorTime(250)},
+	}
+
+	s := &asyncSearchSharder{
+		cfg: SearchSharderConfig{
+			MostRecentShards: defaultMostRecentShards,
+		},
+		reader:    &mockReader{metas: blockMetas},
+		overrides: newTestOverrides(t),
+	}
+
+	tests := []struct {
+		query          string
+		expectedBlocks int
+	}{
+		{query: "{status = error}", expectedBlocks: 2},
+		{query: "{status = error && .foo = `bar`}", expectedBlocks: 2},
+		{query: "{status = error || .foo = `bar`}", expectedBlocks: 4},
+		{query: "{}", expectedBlocks: 4},
+	}
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/?q="+url.QueryEscape(tc.query)+"&start=200&end=300", nil)
+			searchReq, err := api.ParseSearchRequest(r)
+			require.NoError(t, err)
+
+			ctx, cancelCause := context.WithCancelCause(context.Background())
+			defer cancelCause(nil)
+
+			searchJobResponse := &combiner.SearchJobResponse{}
+			s.backendRequests(ctx, "test", pipeline.NewHTTPRequest(r), searchReq, searchJobResponse, make(chan pipeline.Request), cancelCause)
+
+			require.Equal(t, tc.expectedBlocks, searchJobResponse.TotalBlocks)
+		})
+	}
+}
+
 func TestSearchSharderReturnsConsistentShards(t *testing.T) {
 	now := time.Now()
 
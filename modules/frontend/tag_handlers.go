@@ -3,6 +3,8 @@ package frontend
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -64,6 +66,10 @@ func newTagsStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[com
 			if err != nil {
 				return err
 			}
+			err = sendGRPCDiff(comb, srv.Send)
+			if err != nil {
+				return err
+			}
 			// TODO: Exit early here, no need to issue more requests downstream, but some
 			//  work needed to ensure things are still logged/metriced correctly.
 		}
@@ -117,6 +123,10 @@ func newTagsV2StreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[c
 			if err != nil {
 				return err
 			}
+			err = sendGRPCDiff(comb, srv.Send)
+			if err != nil {
+				return err
+			}
 			// TODO: For intrinsic scope only, exit early here, no need to issue more requests downstream, but some
 			//  work needed to ensure things are still logged/metriced correctly.
 		}
@@ -189,7 +199,10 @@ func newTagValuesV2StreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTrip
 		}
 
 		var finalResponse *tempopb.SearchTagValuesV2Response
-		comb := combiner.NewTypedSearchTagValuesV2(o.MaxBytesPerTagValuesQuery(tenant), req.MaxTagValues, req.StaleValueThreshold)
+		comb, err := newTagValuesV2Combiner(httpReq, o, tenant, req.MaxTagValues, req.StaleValueThreshold)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
 		collector := pipeline.NewGRPCCollector(next, cfg.ResponseConsumers, comb, func(res *tempopb.SearchTagValuesV2Response) error {
 			finalResponse = res // to get the bytes processed for SLO calculations
 			return srv.Send(res)
@@ -211,6 +224,16 @@ func newTagValuesV2StreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTrip
 	}
 }
 
+// sendGRPCDiff streams what the combiner collected before the jobs are sent downstream, like the intrinsic tags, so
+// clients get it right away instead of with the results of the first jobs.
+func sendGRPCDiff[T combiner.TResponse](comb combiner.GRPCCombiner[T], send func(T) error) error {
+	diff, err := comb.GRPCDiff()
+	if err != nil {
+		return err
+	}
+	return send(diff)
+}
+
 // HTTP Handlers
 func newTagsHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, logger log.Logger) http.RoundTripper {
 	postSLOHook := metadataSLOPostHook(cfg.Search.MetadataSLO)
@@ -365,7 +388,14 @@ func newTagValuesV2HTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combi
 		tagName := extractTagName(req.URL.Path, tagNameRegexV2)
 
 		// build and use round tripper
-		comb := combiner.NewTypedSearchTagValuesV2(o.MaxBytesPerTagValuesQuery(tenant), maxTagsValues, staleValueThreshold)
+		comb, err := newTagValuesV2Combiner(req, o, tenant, maxTagsValues, staleValueThreshold)
+		if err != nil {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Status:     http.StatusText(http.StatusBadRequest),
+				Body:       io.NopCloser(strings.NewReader(err.Error())),
+			}, nil
+		}
 		rt := pipeline.NewHTTPCollector(next, cfg.ResponseConsumers, comb)
 		start := time.Now()
 		logTagValuesRequest(logger, tenant, "SearchTagValuesV2", tagName, query, rangeDur)
@@ -387,6 +417,41 @@ func newTagValuesV2HTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combi
 	})
 }
 
+// newTagValuesV2Combiner returns the combiner of a tag values request. Requests are paged when they have a page size
+// or when the tenant has a max tag values per query. The page size is the smallest of the page size of the request,
+// the max tag values per query and the limit, and is set on the request for the queriers.
+func newTagValuesV2Combiner(req *http.Request, o overrides.Interface, tenant string, limit uint32, staleValueThreshold uint32) (combiner.GRPCCombiner[*tempopb.SearchTagValuesV2Response], error) {
+	query := req.URL.Query()
+	after := query.Get(api.URLParamAfter)
+
+	var pageSize uint32
+	if s := query.Get(api.URLParamPageSize); s != "" {
+		size, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pageSize: %w", err)
+		}
+		pageSize = uint32(size)
+	}
+	if maxPerQuery := o.MaxTagValuesPerQuery(tenant); maxPerQuery > 0 && (pageSize == 0 || pageSize > uint32(maxPerQuery)) {
+		pageSize = uint32(maxPerQuery)
+	}
+	if pageSize > 0 && limit > 0 && limit < pageSize {
+		pageSize = limit
+	}
+
+	if pageSize == 0 {
+		if after != "" {
+			return nil, errors.New("after requires a pageSize")
+		}
+		return combiner.NewTypedSearchTagValuesV2(o.MaxBytesPerTagValuesQuery(tenant), limit, staleValueThreshold), nil
+	}
+
+	query.Set(api.URLParamPageSize, strconv.FormatUint(uint64(pageSize), 10))
+	req.URL.RawQuery = query.Encode()
+
+	return combiner.NewTypedPagedSearchTagValuesV2(o.MaxBytesPerTagValuesQuery(tenant), pageSize, after)
+}
+
 // helpers
 func extractTenantWithErrorResp(req *http.Request, logger log.Logger) (string, *http.Response, error) {
 	tenant, err := user.ExtractOrgID(req.Context())
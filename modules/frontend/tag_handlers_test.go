@@ -265,6 +265,51 @@ func TestSearchTagsV2Intrinsics(t *testing.T) {
 	}
 }
 
+func TestSearchTagsStreamingSendsIntrinsicsFirst(t *testing.T) {
+	next := &mockRoundTripper{
+		responseFn: func() proto.Message {
+			return &tempopb.SearchTagsV2Response{
+				Scopes: []*tempopb.SearchTagsV2Scope{{Name: "span", Tags: []string{"foo"}}},
+			}
+		},
+	}
+	f := frontendWithSettings(t, next, nil, nil, nil)
+
+	// the intrinsics are streamed before the results of the jobs
+	var responses []*tempopb.SearchTagsV2Response
+	srv := newMockStreamingServer("tenant", func(_ int, r *tempopb.SearchTagsV2Response) {
+		responses = append(responses, r)
+	})
+	require.NoError(t, f.streamingTagsV2(&tempopb.SearchTagsRequest{}, srv))
+	require.Greater(t, len(responses), 1)
+	require.Len(t, responses[0].Scopes, 1)
+	require.Equal(t, api.ParamScopeIntrinsic, responses[0].Scopes[0].Name)
+	require.ElementsMatch(t, search.GetVirtualIntrinsicValues(), responses[0].Scopes[0].Tags)
+
+	var spanTags []string
+	for _, r := range responses[1:] {
+		for _, scope := range r.Scopes {
+			require.NotEqual(t, api.ParamScopeIntrinsic, scope.Name)
+			spanTags = append(spanTags, scope.Tags...)
+		}
+	}
+	require.Equal(t, []string{"foo"}, spanTags)
+
+	// v1 tags only return intrinsics for the intrinsic scope
+	f = frontendWithSettings(t, &mockRoundTripper{
+		responseFn: func() proto.Message {
+			return &tempopb.SearchTagsResponse{TagNames: []string{"foo"}}
+		},
+	}, nil, nil, nil)
+	var v1Responses []*tempopb.SearchTagsResponse
+	v1Srv := newMockStreamingServer("tenant", func(_ int, r *tempopb.SearchTagsResponse) {
+		v1Responses = append(v1Responses, r)
+	})
+	require.NoError(t, f.streamingTags(&tempopb.SearchTagsRequest{Scope: api.ParamScopeIntrinsic}, v1Srv))
+	require.Greater(t, len(v1Responses), 1)
+	require.ElementsMatch(t, search.GetVirtualIntrinsicValues(), v1Responses[0].TagNames)
+}
+
 // todo: a lot of code is replicated between all of these "failure propagates from queriers" tests. we should refactor
 // to a framework that tests this against all endpoints
 func TestSearchTagsV2FailurePropagatesFromQueriers(t *testing.T) {
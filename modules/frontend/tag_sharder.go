@@ -89,6 +89,10 @@ func (r *tagValueSearchRequest) end() uint32 {
 func (r *tagValueSearchRequest) hash() uint64 {
 	hash := fnv1a.HashString64(r.request.TagName)
 	hash = fnv1a.AddString64(hash, traceql.ExtractMatchers(r.request.Query))
+	if r.request.PageSize > 0 {
+		hash = fnv1a.AddUint64(hash, uint64(r.request.PageSize))
+		hash = fnv1a.AddString64(hash, r.request.After)
+	}
 
 	return hash
 }
@@ -268,7 +272,7 @@ func (s searchTagSharder) backendRequests(ctx context.Context, tenantID string,
 	// get block metadata of blocks in start, end duration
 	startT := time.Unix(int64(start), 0)
 	endT := time.Unix(int64(end), 0)
-	blocks := blockMetasForSearch(s.reader.BlockMetas(tenantID), startT, endT, rf1FilterFn(rf1After))
+	blocks := blockMetasForSearch(s.reader.BlockMetasInRange(tenantID, startT, endT), startT, endT, rf1FilterFn(rf1After))
 
 	targetBytesPerRequest := s.cfg.TargetBytesPerRequest
 
@@ -0,0 +1,216 @@
+package frontend
+
+import (
+	_ "embed" // Used to embed html templates
+	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gorilla/mux"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+//go:embed trace_ui.gohtml
+var traceUIPageHTML string
+var traceUITemplate = template.Must(template.New("webpage").Parse(traceUIPageHTML))
+
+type traceUIPageContents struct {
+	TraceID  string
+	Error    string
+	Start    time.Time
+	Duration time.Duration
+	Services int
+	Spans    []traceUISpan
+}
+
+type traceUISpan struct {
+	Name       string
+	Service    string
+	SpanID     string
+	Kind       string
+	Depth      int
+	Start      time.Duration
+	Duration   time.Duration
+	Offset     string
+	Width      string
+	Error      bool
+	Attributes []traceUIAttribute
+}
+
+type traceUIAttribute struct {
+	Key   string
+	Value string
+}
+
+// newTraceUIHandler returns a handler that renders a waterfall view of a trace as a self-contained html page. The
+// trace is fetched from the trace by id v2 handler so the page is subject to the same limits and sharding as the api.
+func newTraceUIHandler(traceByID http.Handler, logger log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := traceUIPageContents{
+			TraceID: mux.Vars(r)["traceID"],
+		}
+
+		status := http.StatusOK
+		resp := fetchTraceForUI(traceByID, r)
+		switch {
+		case resp.status != http.StatusOK:
+			status, page.Error = resp.status, resp.body.String()
+		default:
+			tr := &tempopb.TraceByIDResponse{}
+			if err := tr.Unmarshal(resp.body.Bytes()); err != nil {
+				status, page.Error = http.StatusInternalServerError, fmt.Sprintf("error unmarshalling trace: %s", err)
+				break
+			}
+			buildTraceUIPage(&page, tr.Trace)
+			if tr.Status == tempopb.PartialStatus_PARTIAL {
+				page.Error = "The trace is incomplete: " + tr.Message
+			}
+		}
+
+		w.Header().Set(api.HeaderContentType, "text/html; charset=utf-8")
+		w.WriteHeader(status)
+		if err := traceUITemplate.Execute(w, page); err != nil {
+			level.Error(logger).Log("msg", "error rendering trace ui", "traceID", page.TraceID, "err", err)
+		}
+	})
+}
+
+func fetchTraceForUI(traceByID http.Handler, r *http.Request) *responseBuffer {
+	req := r.Clone(r.Context())
+	req.Header.Set(api.HeaderAccept, api.HeaderAcceptProtobuf)
+
+	rb := newResponseBuffer()
+	traceByID.ServeHTTP(rb, req)
+
+	return rb
+}
+
+// buildTraceUIPage flattens the spans of the trace into waterfall rows. Children follow their parent ordered by
+// start time. Spans whose parent isn't in the trace are shown as roots.
+func buildTraceUIPage(page *traceUIPageContents, tr *tempopb.Trace) {
+	if tr == nil {
+		page.Error = "trace not found"
+		return
+	}
+
+	type node struct {
+		span    *v1_trace.Span
+		service string
+	}
+
+	var (
+		nodes    = map[string]node{}
+		children = map[string][]node{}
+		services = map[string]struct{}{}
+		start    = uint64(0)
+		end      = uint64(0)
+	)
+
+	for _, rs := range tr.ResourceSpans {
+		service := ""
+		if rs.Resource != nil {
+			for _, a := range rs.Resource.Attributes {
+				if a.Key == "service.name" {
+					service = util.StringifyAnyValue(a.Value)
+				}
+			}
+		}
+		services[service] = struct{}{}
+
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				nodes[string(s.SpanId)] = node{span: s, service: service}
+				if start == 0 || s.StartTimeUnixNano < start {
+					start = s.StartTimeUnixNano
+				}
+				if s.EndTimeUnixNano > end {
+					end = s.EndTimeUnixNano
+				}
+			}
+		}
+	}
+
+	var roots []node
+	for _, n := range nodes {
+		if _, ok := nodes[string(n.span.ParentSpanId)]; ok && len(n.span.ParentSpanId) > 0 {
+			children[string(n.span.ParentSpanId)] = append(children[string(n.span.ParentSpanId)], n)
+			continue
+		}
+		roots = append(roots, n)
+	}
+
+	sortNodes := func(n []node) {
+		slices.SortFunc(n, func(a, b node) int {
+			if a.span.StartTimeUnixNano != b.span.StartTimeUnixNano {
+				if a.span.StartTimeUnixNano < b.span.StartTimeUnixNano {
+					return -1
+				}
+				return 1
+			}
+			return slices.Compare(a.span.SpanId, b.span.SpanId)
+		})
+	}
+
+	total := float64(end - start)
+	percent := func(d uint64) string {
+		if total <= 0 {
+			return "0"
+		}
+		return fmt.Sprintf("%.3f", float64(d)/total*100)
+	}
+
+	visited := map[string]struct{}{}
+	var walk func(n node, depth int)
+	walk = func(n node, depth int) {
+		if _, ok := visited[string(n.span.SpanId)]; ok {
+			return
+		}
+		visited[string(n.span.SpanId)] = struct{}{}
+
+		s := n.span
+		duration := uint64(0)
+		if s.EndTimeUnixNano > s.StartTimeUnixNano {
+			duration = s.EndTimeUnixNano - s.StartTimeUnixNano
+		}
+
+		row := traceUISpan{
+			Name:     s.Name,
+			Service:  n.service,
+			SpanID:   util.SpanIDToHexString(s.SpanId),
+			Kind:     s.Kind.String(),
+			Depth:    depth,
+			Start:    time.Duration(s.StartTimeUnixNano - start),
+			Duration: time.Duration(duration),
+			Offset:   percent(s.StartTimeUnixNano - start),
+			Width:    percent(duration),
+			Error:    s.Status != nil && s.Status.Code == v1_trace.Status_STATUS_CODE_ERROR,
+		}
+		for _, a := range s.Attributes {
+			row.Attributes = append(row.Attributes, traceUIAttribute{Key: a.Key, Value: util.StringifyAnyValue(a.Value)})
+		}
+		page.Spans = append(page.Spans, row)
+
+		c := children[string(s.SpanId)]
+		sortNodes(c)
+		for _, child := range c {
+			walk(child, depth+1)
+		}
+	}
+
+	sortNodes(roots)
+	for _, r := range roots {
+		walk(r, 0)
+	}
+
+	page.Start = time.Unix(0, int64(start)).UTC()
+	page.Duration = time.Duration(end - start)
+	page.Services = len(services)
+}
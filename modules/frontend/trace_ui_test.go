@@ -0,0 +1,169 @@
+package frontend
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestTraceUIHandler(t *testing.T) {
+	next := pipeline.RoundTripperFunc(func(_ pipeline.Request) (*http.Response, error) {
+		resBytes, err := proto.Marshal(&tempopb.TraceByIDResponse{
+			Trace:   testTraceUITrace(),
+			Metrics: &tempopb.TraceByIDMetrics{},
+		})
+		require.NoError(t, err)
+
+		return &http.Response{
+			Body:       io.NopCloser(bytes.NewReader(resBytes)),
+			StatusCode: 200,
+			Header: map[string][]string{
+				"Content-Type": {"application/protobuf"},
+			},
+		}, nil
+	})
+
+	f := frontendWithSettings(t, next, nil, config, nil)
+
+	httpResp := httptest.NewRecorder()
+	f.TraceUIHandler.ServeHTTP(httpResp, testTraceUIRequest())
+	resp := httpResp.Result()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	body := httpResp.Body.String()
+	assert.Contains(t, body, "Trace: 1234")
+	assert.Contains(t, body, "Services: 2 | Spans: 3")
+	assert.Contains(t, body, `<tr class="error">`)
+	assert.Contains(t, body, "user.id")
+
+	// children follow their parent
+	root, child, grandchild := strings.Index(body, "> root</summary>"), strings.Index(body, "> child</summary>"), strings.Index(body, "> grandchild</summary>")
+	assert.True(t, root >= 0 && root < child && child < grandchild, body)
+
+	// the page doesn't load any external assets
+	assert.NotContains(t, body, "<script")
+	assert.NotContains(t, body, "<link")
+}
+
+func TestTraceUIHandlerNotFound(t *testing.T) {
+	next := pipeline.RoundTripperFunc(func(_ pipeline.Request) (*http.Response, error) {
+		return &http.Response{
+			Body:       io.NopCloser(strings.NewReader("")),
+			StatusCode: 404,
+		}, nil
+	})
+
+	f := frontendWithSettings(t, next, nil, config, nil)
+
+	httpResp := httptest.NewRecorder()
+	f.TraceUIHandler.ServeHTTP(httpResp, testTraceUIRequest())
+
+	assert.Equal(t, http.StatusNotFound, httpResp.Code)
+	assert.Contains(t, httpResp.Body.String(), "Trace: 1234")
+}
+
+func TestBuildTraceUIPage(t *testing.T) {
+	page := traceUIPageContents{}
+	buildTraceUIPage(&page, testTraceUITrace())
+
+	require.Len(t, page.Spans, 3)
+	assert.Equal(t, 2, page.Services)
+	assert.Equal(t, "100ms", page.Duration.String())
+
+	expected := []struct {
+		name    string
+		service string
+		depth   int
+		offset  string
+		width   string
+		error   bool
+	}{
+		{name: "root", service: "frontend", depth: 0, offset: "0.000", width: "100.000"},
+		{name: "child", service: "backend", depth: 1, offset: "10.000", width: "50.000", error: true},
+		{name: "grandchild", service: "backend", depth: 2, offset: "20.000", width: "10.000"},
+	}
+	for i, e := range expected {
+		s := page.Spans[i]
+		assert.Equal(t, e.name, s.Name)
+		assert.Equal(t, e.service, s.Service)
+		assert.Equal(t, e.depth, s.Depth)
+		assert.Equal(t, e.offset, s.Offset)
+		assert.Equal(t, e.width, s.Width)
+		assert.Equal(t, e.error, s.Error)
+	}
+
+	// no trace
+	page = traceUIPageContents{}
+	buildTraceUIPage(&page, nil)
+	assert.Equal(t, "trace not found", page.Error)
+	assert.Empty(t, page.Spans)
+}
+
+func spanID(id byte) []byte {
+	return []byte{0, 0, 0, 0, 0, 0, 0, id}
+}
+
+func testTraceUIRequest() *http.Request {
+	req := httptest.NewRequest("GET", "/ui/trace/1234", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	return mux.SetURLVars(req, map[string]string{"traceID": "1234"})
+}
+
+func testTraceUITrace() *tempopb.Trace {
+	const ms = uint64(1_000_000)
+	start := uint64(1_700_000_000_000) * ms
+
+	resource := func(service string) *v1_resource.Resource {
+		return &v1_resource.Resource{Attributes: []*v1_common.KeyValue{
+			{Key: "service.name", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: service}}},
+		}}
+	}
+
+	return &tempopb.Trace{
+		ResourceSpans: []*v1.ResourceSpans{
+			{
+				Resource: resource("backend"),
+				ScopeSpans: []*v1.ScopeSpans{{Spans: []*v1.Span{
+					{
+						Name: "grandchild", SpanId: spanID(3), ParentSpanId: spanID(2),
+						StartTimeUnixNano: start + 20*ms, EndTimeUnixNano: start + 30*ms,
+					},
+					{
+						Name: "child", SpanId: spanID(2), ParentSpanId: spanID(1),
+						StartTimeUnixNano: start + 10*ms, EndTimeUnixNano: start + 60*ms,
+						Status: &v1.Status{Code: v1.Status_STATUS_CODE_ERROR},
+						Attributes: []*v1_common.KeyValue{
+							{Key: "user.id", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_IntValue{IntValue: 42}}},
+						},
+					},
+				}}},
+			},
+			{
+				Resource: resource("frontend"),
+				ScopeSpans: []*v1.ScopeSpans{{Spans: []*v1.Span{
+					{
+						Name: "root", SpanId: spanID(1),
+						StartTimeUnixNano: start, EndTimeUnixNano: start + 100*ms,
+					},
+				}}},
+			},
+		},
+	}
+}
@@ -1,8 +1,11 @@
 package frontend
 
 import (
+	"bytes"
 	"io"
 	"net/http"
+	"net/url"
+	"path"
 	"strings"
 	"time"
 
@@ -10,11 +13,15 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level" //nolint:all //deprecated
+	"github.com/gogo/status"
 	"github.com/grafana/dskit/user"
 	"github.com/grafana/tempo/modules/frontend/combiner"
 	"github.com/grafana/tempo/modules/frontend/pipeline"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/api"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util"
+	"google.golang.org/grpc/codes"
 )
 
 // newTraceIDHandler creates a http.handler for trace by id requests
@@ -168,3 +175,221 @@ func newTraceIDV2Handler(cfg Config, next pipeline.AsyncRoundTripper[combiner.Pi
 		return resp, err
 	})
 }
+
+// newTraceIDsHandler creates a http.handler for trace by ids requests
+func newTraceIDsHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, logger log.Logger) http.RoundTripper {
+	postSLOHook := traceByIDSLOPostHook(cfg.TraceByID.SLO)
+
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		tenant, err := user.ExtractOrgID(req.Context())
+		if err != nil {
+			level.Error(logger).Log("msg", "trace ids: failed to extract tenant id", "err", err)
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Status:     http.StatusText(http.StatusBadRequest),
+				Body:       io.NopCloser(strings.NewReader(err.Error())),
+			}, nil
+		}
+
+		// validate traceIDs
+		traceIDs, err := api.ParseTraceIDs(req)
+		if err != nil {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       io.NopCloser(strings.NewReader(err.Error())),
+				Header:     http.Header{},
+			}, nil
+		}
+
+		// validate start and end parameter
+		_, _, _, _, _, _, reqErr := api.ValidateAndSanitizeRequest(req)
+		if reqErr != nil {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       io.NopCloser(strings.NewReader(reqErr.Error())),
+				Header:     http.Header{},
+			}, nil
+		}
+
+		// check marshalling format
+		marshallingFormat := api.HeaderAcceptJSON
+		if req.Header.Get(api.HeaderAccept) == api.HeaderAcceptProtobuf {
+			marshallingFormat = api.HeaderAcceptProtobuf
+		}
+
+		// enforce all communication internal to Tempo to be in protobuf bytes
+		req.Header.Set(api.HeaderAccept, api.HeaderAcceptProtobuf)
+
+		level.Info(logger).Log(
+			"msg", "trace ids request",
+			"tenant", tenant,
+			"traceIDs", len(traceIDs))
+
+		comb := combiner.NewTypedTraceByIDs(o.MaxBytesPerTrace(tenant), len(traceIDs), marshallingFormat)
+		rt := pipeline.NewHTTPCollector(next, cfg.ResponseConsumers, comb)
+
+		start := time.Now()
+		resp, err := rt.RoundTrip(req)
+		elapsed := time.Since(start)
+
+		var bytesProcessed uint64
+		findResp, _ := comb.GRPCFinal()
+		if findResp != nil && findResp.Metrics != nil {
+			bytesProcessed = findResp.Metrics.InspectedBytes
+		}
+
+		postSLOHook(resp, tenant, bytesProcessed, elapsed, err)
+
+		level.Info(logger).Log(
+			"msg", "trace ids response",
+			"tenant", tenant,
+			"traceIDs", len(traceIDs),
+			"inspected_bytes", bytesProcessed,
+			"request_throughput", float64(bytesProcessed)/elapsed.Seconds(),
+			"duration_seconds", elapsed.Seconds(),
+			"err", err)
+
+		return resp, err
+	})
+}
+
+// newTraceIDStreamingGRPCHandler returns a handler that streams a trace by id as it is combined. Each
+// message contains only the resource spans that were not previously sent, split into chunks of at most
+// cfg.TraceByID.StreamChunkSizeBytes. Clients reassemble the trace by concatenating resource spans.
+func newTraceIDStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, o overrides.Interface, logger log.Logger) streamingTraceByIDHandler {
+	postSLOHook := traceByIDSLOPostHook(cfg.TraceByID.SLO)
+	downstreamPath := path.Join(apiPrefix, strings.TrimSuffix(api.PathTracesV2, "{traceID}"))
+
+	return func(req *tempopb.TraceByIDRequest, srv tempopb.StreamingQuerier_FindTraceByIDServer) error {
+		ctx := srv.Context()
+		tenant, err := user.ExtractOrgID(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(req.TraceID) == 0 {
+			return status.Error(codes.InvalidArgument, "please provide a traceID")
+		}
+		traceID := util.TraceIDToHexString(req.TraceID)
+
+		switch req.QueryMode {
+		case "", api.QueryModeAll, api.QueryModeIngesters, api.QueryModeBlocks:
+		default:
+			return status.Errorf(codes.InvalidArgument, "invalid value for %s: %q", api.QueryModeKey, req.QueryMode)
+		}
+
+		httpReq := &http.Request{
+			URL:    &url.URL{Path: path.Join(downstreamPath, traceID)},
+			Header: headersFromGrpcContext(ctx),
+			Body:   io.NopCloser(bytes.NewReader([]byte{})),
+		}
+		params := map[string]string{}
+		if !req.RF1After.IsZero() {
+			params[api.URLParamRF1After] = req.RF1After.Format(time.RFC3339)
+		}
+		if req.QueryMode != "" {
+			params[api.QueryModeKey] = req.QueryMode
+		}
+		httpReq = api.BuildQueryRequest(httpReq, params)
+		// enforce all communication internal to Tempo to be in protobuf bytes
+		httpReq.Header.Set(api.HeaderAccept, api.HeaderAcceptProtobuf)
+		httpReq = httpReq.WithContext(ctx)
+
+		level.Info(logger).Log(
+			"msg", "trace id streaming request",
+			"tenant", tenant,
+			"traceID", traceID)
+
+		// colliding traces are only split in the final response, so it's streamed once the trace is combined
+		collisions := cfg.TraceByID.Collisions
+		streamFinal := collisions.Enabled
+
+		var finalResponse *tempopb.TraceByIDResponse
+		comb := combiner.NewTypedTraceByIDV2WithCollisions(o.MaxBytesPerTrace(tenant), api.HeaderAcceptProtobuf, collisions)
+		collector := pipeline.NewGRPCCollector(next, cfg.ResponseConsumers, comb, func(resp *tempopb.TraceByIDResponse) error {
+			finalResponse = resp // save the last response for bytesProcessed for the SLO calculations
+			if streamFinal {
+				return nil
+			}
+			return sendTraceByIDChunks(srv, resp, cfg.TraceByID.StreamChunkSizeBytes)
+		})
+
+		start := time.Now()
+		err = collector.RoundTrip(httpReq)
+		elapsed := time.Since(start)
+
+		if err == nil && streamFinal {
+			finalResponse, err = comb.GRPCFinal()
+			if err == nil {
+				err = sendTraceByIDChunks(srv, finalResponse, cfg.TraceByID.StreamChunkSizeBytes)
+			}
+		}
+
+		var bytesProcessed uint64
+		if finalResponse != nil && finalResponse.Metrics != nil {
+			bytesProcessed = finalResponse.Metrics.InspectedBytes
+		}
+		postSLOHook(nil, tenant, bytesProcessed, elapsed, err)
+
+		level.Info(logger).Log(
+			"msg", "trace id streaming response",
+			"tenant", tenant,
+			"traceID", traceID,
+			"inspected_bytes", bytesProcessed,
+			"request_throughput", float64(bytesProcessed)/elapsed.Seconds(),
+			"duration_seconds", elapsed.Seconds(),
+			"err", err)
+
+		return err
+	}
+}
+
+// sendTraceByIDChunks sends the trace of the response in chunks of at most maxBytes. The colliding traces of the
+// response are sent with the last chunk.
+func sendTraceByIDChunks(srv tempopb.StreamingQuerier_FindTraceByIDServer, resp *tempopb.TraceByIDResponse, maxBytes int) error {
+	chunks := chunkResourceSpans(resp.Trace.GetResourceSpans(), maxBytes)
+	for i, chunk := range chunks {
+		msg := &tempopb.TraceByIDResponse{
+			Trace:    &tempopb.Trace{ResourceSpans: chunk},
+			Metrics:  resp.Metrics,
+			Status:   resp.Status,
+			Message:  resp.Message,
+			Warnings: resp.Warnings,
+		}
+		if i == len(chunks)-1 {
+			msg.CollidingTraces = resp.CollidingTraces
+		}
+
+		err := srv.Send(msg)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chunkResourceSpans splits resource spans into chunks of at most maxBytes. A single resource span
+// larger than maxBytes is returned in its own chunk. At least one, possibly empty, chunk is always
+// returned so metrics are sent even if there are no new spans.
+func chunkResourceSpans(rs []*v1.ResourceSpans, maxBytes int) [][]*v1.ResourceSpans {
+	if maxBytes <= 0 || len(rs) == 0 {
+		return [][]*v1.ResourceSpans{rs}
+	}
+
+	var (
+		chunks    [][]*v1.ResourceSpans
+		start     int
+		chunkSize int
+	)
+	for i, r := range rs {
+		size := r.Size()
+		if chunkSize > 0 && chunkSize+size > maxBytes {
+			chunks = append(chunks, rs[start:i])
+			start = i
+			chunkSize = 0
+		}
+		chunkSize += size
+	}
+
+	return append(chunks, rs[start:])
+}
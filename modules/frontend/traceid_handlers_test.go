@@ -18,6 +18,9 @@ import (
 	"github.com/grafana/tempo/modules/frontend/pipeline"
 	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -594,3 +597,165 @@ func TestTraceByIDHandlerV2CachedMetrics(t *testing.T) {
 	// Verify the backend was called again (callCount should be 4)
 	require.Equal(t, 4, callCount)
 }
+
+func TestTraceIDStreamingGRPC(t *testing.T) {
+	// create and split a splitTrace
+	splitTrace := test.MakeTrace(4, []byte{0x01, 0x02})
+	trace1 := &tempopb.Trace{}
+	trace2 := &tempopb.Trace{}
+
+	for i, b := range splitTrace.ResourceSpans {
+		if i%2 == 0 {
+			trace1.ResourceSpans = append(trace1.ResourceSpans, b)
+		} else {
+			trace2.ResourceSpans = append(trace2.ResourceSpans, b)
+		}
+	}
+
+	next := pipeline.RoundTripperFunc(func(r pipeline.Request) (*http.Response, error) {
+		testTrace := trace2
+		if strings.Contains(r.HTTPRequest().RequestURI, "mode=ingesters") {
+			testTrace = trace1
+		}
+
+		resBytes, err := proto.Marshal(&tempopb.TraceByIDResponse{
+			Trace:   testTrace,
+			Metrics: &tempopb.TraceByIDMetrics{InspectedBytes: 1},
+		})
+		require.NoError(t, err)
+
+		return &http.Response{
+			Body:       io.NopCloser(bytes.NewReader(resBytes)),
+			StatusCode: 200,
+			Header: map[string][]string{
+				"Content-Type": {"application/protobuf"},
+			},
+		}, nil
+	})
+
+	cfg := *config
+	cfg.TraceByID.StreamChunkSizeBytes = 1 // forces one resource span per message
+	f := frontendWithSettings(t, next, nil, &cfg, nil)
+
+	mtx := sync.Mutex{}
+	actualTrace := &tempopb.Trace{}
+	var lastMetrics *tempopb.TraceByIDMetrics
+	srv := newMockStreamingServer("blerg", func(_ int, resp *tempopb.TraceByIDResponse) {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		assert.LessOrEqual(t, len(resp.Trace.ResourceSpans), 1)
+		actualTrace.ResourceSpans = append(actualTrace.ResourceSpans, resp.Trace.ResourceSpans...)
+		lastMetrics = resp.Metrics
+	})
+
+	err := f.FindTraceByID(&tempopb.TraceByIDRequest{TraceID: []byte{0x01, 0x02}}, srv)
+	require.NoError(t, err)
+
+	trace.SortTrace(splitTrace)
+	trace.SortTrace(actualTrace)
+	assert.True(t, proto.Equal(splitTrace, actualTrace))
+	assert.Equal(t, uint64(2), lastMetrics.InspectedBytes)
+
+	// an empty trace id is rejected
+	err = f.FindTraceByID(&tempopb.TraceByIDRequest{}, srv)
+	require.Error(t, err)
+}
+
+func TestTraceIDStreamingGRPCCollisions(t *testing.T) {
+	rootSpan := func(service string, id byte, start uint64) *v1.ResourceSpans {
+		return &v1.ResourceSpans{
+			Resource: &v1_resource.Resource{Attributes: []*v1_common.KeyValue{
+				{Key: "service.name", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: service}}},
+			}},
+			ScopeSpans: []*v1.ScopeSpans{{Spans: []*v1.Span{{
+				SpanId:            []byte{0, 0, 0, 0, 0, 0, 0, id},
+				StartTimeUnixNano: start,
+				EndTimeUnixNano:   start + 1,
+			}}}},
+		}
+	}
+	day := uint64(24 * time.Hour)
+
+	next := pipeline.RoundTripperFunc(func(r pipeline.Request) (*http.Response, error) {
+		// the ingesters and the blocks return distinct traces with the same id
+		rs := rootSpan("b", 2, day)
+		if strings.Contains(r.HTTPRequest().RequestURI, "mode=ingesters") {
+			rs = rootSpan("a", 1, 0)
+		}
+
+		resBytes, err := proto.Marshal(&tempopb.TraceByIDResponse{
+			Trace:   &tempopb.Trace{ResourceSpans: []*v1.ResourceSpans{rs}},
+			Metrics: &tempopb.TraceByIDMetrics{InspectedBytes: 1},
+		})
+		require.NoError(t, err)
+
+		return &http.Response{
+			Body:       io.NopCloser(bytes.NewReader(resBytes)),
+			StatusCode: 200,
+			Header: map[string][]string{
+				"Content-Type": {"application/protobuf"},
+			},
+		}, nil
+	})
+
+	cfg := *config
+	cfg.TraceByID.Collisions = trace.CollisionConfig{Enabled: true, Split: true, MinGap: time.Hour}
+	f := frontendWithSettings(t, next, nil, &cfg, nil)
+
+	mtx := sync.Mutex{}
+	var responses []*tempopb.TraceByIDResponse
+	srv := newMockStreamingServer("blerg", func(_ int, resp *tempopb.TraceByIDResponse) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		responses = append(responses, resp)
+	})
+
+	err := f.FindTraceByID(&tempopb.TraceByIDRequest{TraceID: []byte{0x01, 0x02}}, srv)
+	require.NoError(t, err)
+
+	// the final response is streamed with the colliding traces
+	require.Len(t, responses, 1)
+	res := responses[0]
+	require.Len(t, res.Trace.ResourceSpans, 1)
+	assert.Equal(t, uint64(0), res.Trace.ResourceSpans[0].ScopeSpans[0].Spans[0].StartTimeUnixNano)
+	require.Len(t, res.CollidingTraces, 1)
+	assert.Equal(t, day, res.CollidingTraces[0].ResourceSpans[0].ScopeSpans[0].Spans[0].StartTimeUnixNano)
+	require.Len(t, res.Warnings, 1)
+	assert.Equal(t, tempopb.WarningTraceIDCollision, res.Warnings[0].Code)
+	assert.Equal(t, uint64(2), res.Metrics.InspectedBytes)
+}
+
+func TestChunkResourceSpans(t *testing.T) {
+	rs := test.MakeTrace(5, []byte{0x01}).ResourceSpans
+	total := 0
+	for _, r := range rs {
+		total += r.Size()
+	}
+
+	assert.Equal(t, [][]*v1.ResourceSpans{rs}, chunkResourceSpans(rs, 0))
+	assert.Equal(t, [][]*v1.ResourceSpans{nil}, chunkResourceSpans(nil, 1024))
+
+	chunks := chunkResourceSpans(rs, 1)
+	require.Len(t, chunks, len(rs))
+	for i, c := range chunks {
+		assert.Equal(t, []*v1.ResourceSpans{rs[i]}, c)
+	}
+
+	// every chunk stays below the limit, unless it holds a single larger resource span, and all
+	// resource spans are returned in order
+	limit := total / 2
+	chunks = chunkResourceSpans(rs, limit)
+	var actual []*v1.ResourceSpans
+	for _, c := range chunks {
+		chunkSize := 0
+		for _, r := range c {
+			chunkSize += r.Size()
+		}
+		if len(c) > 1 {
+			assert.LessOrEqual(t, chunkSize, limit)
+		}
+		actual = append(actual, c...)
+	}
+	assert.Equal(t, rs, actual)
+}
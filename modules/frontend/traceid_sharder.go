@@ -1,8 +1,10 @@
 package frontend
 
 import (
+	"bytes"
 	"encoding/hex"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/go-kit/log" //nolint:all //deprecated
@@ -12,6 +14,10 @@ import (
 	"github.com/grafana/tempo/modules/querier"
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/blockboundary"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -21,15 +27,17 @@ const (
 
 type asyncTraceSharder struct {
 	next            pipeline.AsyncRoundTripper[combiner.PipelineResponse]
+	reader          tempodb.Reader
 	cfg             *TraceByIDConfig
 	logger          log.Logger
 	blockBoundaries [][]byte
 }
 
-func newAsyncTraceIDSharder(cfg *TraceByIDConfig, logger log.Logger) pipeline.AsyncMiddleware[combiner.PipelineResponse] {
+func newAsyncTraceIDSharder(reader tempodb.Reader, cfg *TraceByIDConfig, logger log.Logger) pipeline.AsyncMiddleware[combiner.PipelineResponse] {
 	return pipeline.AsyncMiddlewareFunc[combiner.PipelineResponse](func(next pipeline.AsyncRoundTripper[combiner.PipelineResponse]) pipeline.AsyncRoundTripper[combiner.PipelineResponse] {
 		return asyncTraceSharder{
 			next:            next,
+			reader:          reader,
 			cfg:             cfg,
 			logger:          logger,
 			blockBoundaries: blockboundary.CreateBlockBoundaries(cfg.QueryShards - 1), // one shard will be used to query ingesters
@@ -69,22 +77,32 @@ func (s asyncTraceSharder) RoundTrip(pipelineRequest pipeline.Request) (pipeline
 }
 
 // buildShardedRequests returns a slice of requests sharded on the precalculated
-// block boundaries
+// block boundaries. The mode parameter of the parent request restricts the requests
+// to the ingesters or to the backend blocks.
 func (s *asyncTraceSharder) buildShardedRequests(parent pipeline.Request) ([]pipeline.Request, error) {
 	userID, err := user.ExtractOrgID(parent.Context())
 	if err != nil {
 		return nil, err
 	}
 
-	reqs := make([]pipeline.Request, s.cfg.QueryShards)
+	mode := parent.HTTPRequest().URL.Query().Get(querier.QueryModeKey)
+
+	reqs := make([]pipeline.Request, 0, s.cfg.QueryShards)
 	params := map[string]string{}
 
-	reqs[0], err = cloneRequestforQueriers(parent, userID, func(r *http.Request) (*http.Request, error) {
-		params[querier.QueryModeKey] = querier.QueryModeIngesters
-		return api.BuildQueryRequest(r, params), nil
-	})
-	if err != nil {
-		return nil, err
+	if mode != querier.QueryModeBlocks {
+		ingesterReq, err := cloneRequestforQueriers(parent, userID, func(r *http.Request) (*http.Request, error) {
+			params[querier.QueryModeKey] = querier.QueryModeIngesters
+			return api.BuildQueryRequest(withoutQueryMode(r), params), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, ingesterReq)
+	}
+
+	if mode == querier.QueryModeIngesters {
+		return reqs, nil
 	}
 
 	var rf1After string
@@ -94,8 +112,19 @@ func (s *asyncTraceSharder) buildShardedRequests(parent pipeline.Request) ([]pip
 		rf1After = s.cfg.RF1After.Format(time.RFC3339)
 	}
 
+	// the warning is added by the queriers, which search the blocks of the stale blocklist
+	if s.reader != nil && s.reader.BlocklistStale(userID) {
+		staleBlocklistQueries.WithLabelValues(userID, traceByIDOp).Inc()
+	}
+
+	shards := s.matchingShards(parent, userID)
+
 	// build sharded block queries
 	for i := 1; i < len(s.blockBoundaries); i++ {
+		if shards != nil && !shards[i-1] {
+			continue
+		}
+
 		i := i // save the loop variable locally to make sure the closure grabs the correct var.
 		pipelineR, _ := cloneRequestforQueriers(parent, userID, func(r *http.Request) (*http.Request, error) {
 			// block queries
@@ -104,11 +133,95 @@ func (s *asyncTraceSharder) buildShardedRequests(parent pipeline.Request) ([]pip
 			params[querier.QueryModeKey] = querier.QueryModeBlocks
 			params[api.URLParamRF1After] = rf1After
 
-			return api.BuildQueryRequest(r, params), nil
+			return api.BuildQueryRequest(withoutQueryMode(r), params), nil
 		})
 
-		reqs[i] = pipelineR
+		reqs = append(reqs, pipelineR)
 	}
 
 	return reqs, nil
 }
+
+// matchingShards returns which block shards contain a block that may contain the trace, according to the trace ID
+// ranges and summaries of the blocks in the tenant index. Blocks without a range or a summary may contain any trace. Returns nil if all
+// shards must be queried: pruning is disabled, or the blocklist is unknown or stale and may miss blocks.
+func (s *asyncTraceSharder) matchingShards(parent pipeline.Request, tenantID string) []bool {
+	if !s.cfg.TraceIDSummaryPruning || s.reader == nil || s.reader.BlocklistStale(tenantID) {
+		return nil
+	}
+
+	traceIDs, err := requestTraceIDs(parent.HTTPRequest())
+	if err != nil {
+		return nil
+	}
+	_, _, _, start, end, _, err := api.ValidateAndSanitizeRequest(parent.HTTPRequest())
+	if err != nil {
+		return nil
+	}
+
+	var metas []*backend.BlockMeta
+	if start != 0 && end != 0 {
+		// the time range is inclusive, exact checks are done by the queriers
+		metas = s.reader.BlockMetasInRange(tenantID, time.Unix(start, 0), time.Unix(end, 0))
+	} else {
+		metas = s.reader.BlockMetas(tenantID)
+	}
+	// an empty blocklist may not have been polled yet
+	if len(metas) == 0 {
+		return nil
+	}
+
+	shards := make([]bool, len(s.blockBoundaries)-1)
+	for _, m := range metas {
+		if !mayContainAnyTraceID(m, traceIDs) {
+			continue
+		}
+
+		// the shard of a block is the first shard whose end boundary is not lower than the block id, the boundaries
+		// are inclusive on both ends so a block on a boundary is queried by both shards
+		id, _ := m.BlockID.Marshal()
+		i := sort.Search(len(shards), func(i int) bool { return bytes.Compare(id, s.blockBoundaries[i+1]) <= 0 })
+		for ; i < len(shards) && bytes.Compare(id, s.blockBoundaries[i]) >= 0; i++ {
+			shards[i] = true
+		}
+	}
+
+	pruned := 0
+	for _, match := range shards {
+		if !match {
+			pruned++
+		}
+	}
+	trace.SpanFromContext(parent.Context()).SetAttributes(attribute.Int("prunedShards", pruned))
+
+	return shards
+}
+
+// requestTraceIDs returns the trace id of a trace by id request, or the trace ids of a trace by ids request
+func requestTraceIDs(r *http.Request) ([][]byte, error) {
+	if traceID, err := api.ParseTraceID(r); err == nil {
+		return [][]byte{traceID}, nil
+	}
+	return api.ParseTraceIDs(r)
+}
+
+func mayContainAnyTraceID(m *backend.BlockMeta, traceIDs [][]byte) bool {
+	for _, traceID := range traceIDs {
+		if m.MayContainTraceID(traceID) {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutQueryMode removes the mode requested by the caller so it can be replaced by the mode of the sharded request
+func withoutQueryMode(r *http.Request) *http.Request {
+	q := r.URL.Query()
+	if !q.Has(querier.QueryModeKey) {
+		return r
+	}
+
+	q.Del(querier.QueryModeKey)
+	r.URL.RawQuery = q.Encode()
+	return r
+}
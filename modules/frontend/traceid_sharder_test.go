@@ -2,14 +2,19 @@ package frontend
 
 import (
 	"context"
+	"encoding/hex"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 	"github.com/grafana/dskit/user"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/tempo/modules/frontend/pipeline"
 	"github.com/grafana/tempo/pkg/blockboundary"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
 )
 
 func TestBuildShardedRequests(t *testing.T) {
@@ -32,3 +37,95 @@ func TestBuildShardedRequests(t *testing.T) {
 	require.Equal(t, "/querier?mode=ingesters", shardedReqs[0].HTTPRequest().RequestURI)
 	urisEqual(t, []string{"/querier?blockEnd=ffffffffffffffffffffffffffffffff&blockStart=00000000000000000000000000000000&mode=blocks"}, []string{shardedReqs[1].HTTPRequest().RequestURI})
 }
+
+func TestBuildShardedRequestsQueryMode(t *testing.T) {
+	queryShards := 3
+
+	sharder := &asyncTraceSharder{
+		cfg: &TraceByIDConfig{
+			QueryShards: queryShards,
+		},
+		blockBoundaries: blockboundary.CreateBlockBoundaries(queryShards - 1),
+	}
+
+	ctx := user.InjectOrgID(context.Background(), "blerg")
+
+	// skip the ingesters
+	req := httptest.NewRequest("GET", "/?mode=blocks", nil).WithContext(ctx)
+	shardedReqs, err := sharder.buildShardedRequests(pipeline.NewHTTPRequest(req))
+	require.NoError(t, err)
+	require.Len(t, shardedReqs, queryShards-1)
+	for _, r := range shardedReqs {
+		require.Equal(t, "blocks", r.HTTPRequest().URL.Query().Get("mode"))
+	}
+
+	// skip the backend
+	req = httptest.NewRequest("GET", "/?mode=ingesters", nil).WithContext(ctx)
+	shardedReqs, err = sharder.buildShardedRequests(pipeline.NewHTTPRequest(req))
+	require.NoError(t, err)
+	require.Len(t, shardedReqs, 1)
+	require.Equal(t, "/querier?mode=ingesters", shardedReqs[0].HTTPRequest().RequestURI)
+}
+
+func TestBuildShardedRequestsTraceIDSummaryPruning(t *testing.T) {
+	queryShards := 5
+	boundaries := blockboundary.CreateBlockBoundaries(queryShards - 1)
+	traceID := test.ValidTraceID(nil)
+
+	// returns a block in the given block shard, that has the trace if summary is set
+	blockInShard := func(shard int, summary, hasTrace bool) *backend.BlockMeta {
+		id := append([]byte{}, boundaries[shard]...)
+		id[len(id)-1]++
+		m := &backend.BlockMeta{BlockID: backend.UUID(uuid.Must(uuid.FromBytes(id)))}
+		if summary {
+			m.TraceIDSummary = backend.NewTraceIDSummary(64)
+			if hasTrace {
+				m.TraceIDAdded(traceID)
+			} else {
+				m.TraceIDAdded(test.ValidTraceID(nil))
+			}
+		}
+		return m
+	}
+
+	reader := &mockReader{metas: []*backend.BlockMeta{
+		blockInShard(0, true, false),
+		blockInShard(1, true, true),
+		blockInShard(3, false, false),
+	}}
+	sharder := &asyncTraceSharder{
+		reader: reader,
+		cfg: &TraceByIDConfig{
+			QueryShards:           queryShards,
+			TraceIDSummaryPruning: true,
+		},
+		blockBoundaries: boundaries,
+	}
+
+	ctx := user.InjectOrgID(context.Background(), "blerg")
+	req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	req = mux.SetURLVars(req, map[string]string{"traceID": hex.EncodeToString(traceID)})
+
+	blockStarts := func() []string {
+		shardedReqs, err := sharder.buildShardedRequests(pipeline.NewHTTPRequest(req))
+		require.NoError(t, err)
+
+		var starts []string
+		for _, r := range shardedReqs[1:] {
+			starts = append(starts, r.HTTPRequest().URL.Query().Get("blockStart"))
+		}
+		return starts
+	}
+
+	// the shard without blocks and the shard whose block doesn't have the trace are pruned
+	require.Equal(t, []string{hex.EncodeToString(boundaries[1]), hex.EncodeToString(boundaries[3])}, blockStarts())
+
+	// all shards are queried if the blocklist is stale
+	reader.stale = true
+	require.Len(t, blockStarts(), queryShards-1)
+	reader.stale = false
+
+	// or if pruning is disabled
+	sharder.cfg.TraceIDSummaryPruning = false
+	require.Len(t, blockStarts(), queryShards-1)
+}
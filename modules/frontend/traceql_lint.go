@@ -0,0 +1,30 @@
+package frontend
+
+import (
+	"net/http"
+
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const traceQLLintParamQuery = "q"
+
+// newTraceQLLintHandler returns a handler that parses and, unless parseOnly is true, validates the TraceQL query in
+// the q parameter without executing it. Invalid queries are reported in the diagnostics of the response, not with
+// an error status, so that linters can show all of them.
+func newTraceQLLintHandler(parseOnly bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.FormValue(traceQLLintParamQuery)
+		if query == "" {
+			http.Error(w, "missing query parameter "+traceQLLintParamQuery, http.StatusBadRequest)
+			return
+		}
+
+		util.WriteJSONResponse(w, traceql.Lint(query, parseOnly))
+	})
+}
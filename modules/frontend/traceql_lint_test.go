@@ -0,0 +1,86 @@
+package frontend
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/traceql"
+)
+
+func TestTraceQLLintHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		parseOnly      bool
+		req            *http.Request
+		expectedStatus int
+		expectedValid  bool
+		expectedDiags  int
+	}{
+		{
+			name:           "valid",
+			req:            httptest.NewRequest(http.MethodGet, "/api/traceql/validate?q="+url.QueryEscape(`{ span.foo = "bar" }`), nil),
+			expectedStatus: http.StatusOK,
+			expectedValid:  true,
+		},
+		{
+			name:           "invalid",
+			req:            httptest.NewRequest(http.MethodGet, "/api/traceql/validate?q="+url.QueryEscape(`{ .foo + 1 }`), nil),
+			expectedStatus: http.StatusOK,
+			expectedDiags:  1,
+		},
+		{
+			name:           "parse only",
+			parseOnly:      true,
+			req:            httptest.NewRequest(http.MethodGet, "/api/traceql/parse?q="+url.QueryEscape(`{ .foo + 1 }`), nil),
+			expectedStatus: http.StatusOK,
+			expectedValid:  true,
+		},
+		{
+			name:           "post form",
+			req:            newFormRequest("/api/traceql/validate", url.Values{"q": {`{ span.foo = "bar" }`}}),
+			expectedStatus: http.StatusOK,
+			expectedValid:  true,
+		},
+		{
+			name:           "missing query",
+			req:            httptest.NewRequest(http.MethodGet, "/api/traceql/validate", nil),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "method not allowed",
+			req:            httptest.NewRequest(http.MethodDelete, "/api/traceql/validate?q=%7B%7D", nil),
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			newTraceQLLintHandler(tc.parseOnly).ServeHTTP(rec, tc.req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+			var res traceql.LintResult
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+			require.Equal(t, tc.expectedValid, res.Valid)
+			require.Len(t, res.Diagnostics, tc.expectedDiags)
+		})
+	}
+}
+
+func newFormRequest(target string, form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
@@ -3,6 +3,7 @@ package frontend
 import (
 	"time"
 
+	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/tempodb/backend"
 )
 
@@ -16,3 +17,17 @@ func rf1FilterFn(rf1After time.Time) func(m *backend.BlockMeta) bool {
 			(m.ReplicationFactor == backend.MetricsGeneratorReplicationFactor && m.StartTime.After(rf1After))
 	}
 }
+
+// errorTimesFilterFn wraps a block filter to also skip blocks without spans with an error status in the
+// given time range. It's used for queries that only match spans with an error status.
+func errorTimesFilterFn(start, end time.Time, next func(m *backend.BlockMeta) bool) func(m *backend.BlockMeta) bool {
+	return func(m *backend.BlockMeta) bool {
+		return m.MayContainErrorsInRange(start, end) && next(m)
+	}
+}
+
+// requiresErrorStatus returns true if the query only matches spans with an error status.
+func requiresErrorStatus(query string) bool {
+	expr, err := traceql.Parse(query)
+	return err == nil && expr.RequiresErrorStatus()
+}
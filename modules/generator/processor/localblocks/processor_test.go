@@ -67,7 +67,7 @@ func (m *mockWriter) CompleteBlock(context.Context, common.WALBlock) (common.Bac
 	return nil, nil
 }
 
-func (m *mockWriter) CompleteBlockWithBackend(context.Context, common.WALBlock, backend.Reader, backend.Writer) (common.BackendBlock, error) {
+func (m *mockWriter) CompleteBlockWithBackend(context.Context, common.WALBlock, common.BlockEncoding, backend.Reader, backend.Writer) (common.BackendBlock, error) {
 	return nil, nil
 }
 
@@ -408,6 +408,10 @@ func (m *mockBlock) FindTraceByID(context.Context, common.ID, common.SearchOptio
 	return nil, nil
 }
 
+func (m *mockBlock) FindTraceByIDs(context.Context, []common.ID, common.SearchOptions) ([]*tempopb.Trace, *tempopb.TraceByIDMetrics, error) {
+	return nil, nil, nil
+}
+
 func (m *mockBlock) Search(context.Context, *tempopb.SearchRequest, common.SearchOptions) (*tempopb.SearchResponse, error) {
 	return nil, nil
 }
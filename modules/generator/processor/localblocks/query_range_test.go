@@ -24,6 +24,27 @@ func (m *mockOverrides) BlockRetentionForTenant(_ string) time.Duration     { re
 func (m *mockOverrides) CompactionDisabledForTenant(_ string) bool          { return false }
 func (m *mockOverrides) MaxBytesPerTraceForTenant(_ string) int             { return 0 }
 func (m *mockOverrides) MaxCompactionRangeForTenant(_ string) time.Duration { return 0 }
+func (m *mockOverrides) StorageAttributePolicyForTenant(_ string) common.AttributePolicy {
+	return common.AttributePolicy{}
+}
+func (m *mockOverrides) ConvertV2BlocksForTenant(_ string) bool { return false }
+func (m *mockOverrides) DedicatedColumnsForTenant(_ string) backend.DedicatedColumns {
+	return nil
+}
+
+func (m *mockOverrides) BlockRetentionClassesForTenant(_ string) (string, map[string]time.Duration) {
+	return "", nil
+}
+
+func (m *mockOverrides) BlockArchiveForTenant(_ string) (time.Duration, string) {
+	return 0, ""
+}
+
+func (m *mockOverrides) BlockEncodingForTenant(_ string) common.BlockEncoding {
+	return common.BlockEncoding{}
+}
+
+func (m *mockOverrides) DedicatedColumnsAutoApplyForTenant(_ string) bool { return false }
 
 func TestProcessor(t *testing.T) {
 	// init configuration
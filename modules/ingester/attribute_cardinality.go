@@ -0,0 +1,41 @@
+package ingester
+
+import (
+	"net/http"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// AttributeCardinalityHandler lists the attribute keys of each tenant whose values exceeded the tenant's attribute
+// cardinality limit in the current or the previous head block. The tenant query parameter limits the response to
+// one tenant.
+func (i *Ingester) AttributeCardinalityHandler(w http.ResponseWriter, r *http.Request) {
+	queryParamInstance := "tenant"
+
+	instances := i.getInstances()
+	if r.URL.Query().Has(queryParamInstance) {
+		inst, ok := i.getInstanceByID(r.URL.Query().Get(queryParamInstance))
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		instances = []*instance{inst}
+	}
+
+	resp := make(map[string][]common.LimitedAttribute, len(instances))
+	for _, inst := range instances {
+		if limited := inst.limitedAttributes(); len(limited) > 0 {
+			resp[inst.instanceID] = limited
+		}
+	}
+
+	util.WriteJSONResponse(w, resp)
+}
+
+func (i *instance) limitedAttributes() []common.LimitedAttribute {
+	i.headBlockMtx.RLock()
+	defer i.headBlockMtx.RUnlock()
+
+	return i.attributeCardinality.Limited()
+}
@@ -32,6 +32,9 @@ type Config struct {
 	FlushAllOnShutdown   bool          `yaml:"flush_all_on_shutdown"`
 	FlushObjectStorage   bool          `yaml:"flush_object_storage"`
 
+	TraceIDConflicts TraceIDConflictsConfig `yaml:"trace_id_conflicts"`
+	TraceAssembly    TraceAssemblyConfig    `yaml:"trace_assembly"`
+
 	// This config is dynamically injected because defined outside the ingester config.
 	DedicatedColumns    backend.DedicatedColumns `yaml:"-"`
 	IngestStorageConfig ingest.Config            `yaml:"-"`
@@ -192,7 +192,11 @@ func (i *Ingester) cutOneInstanceToWal(instance *instance, immediate bool) {
 		return
 	}
 
+	blockIDs := instance.SplitTraceBlocks()
 	if blockID != uuid.Nil {
+		blockIDs = append(blockIDs, blockID)
+	}
+	for _, blockID := range blockIDs {
 		level.Info(log.Logger).Log("msg", "head block cut. enqueueing flush op", "tenant", instance.instanceID, "block", blockID)
 		// jitter to help when flushing many instances at the same time
 		// no jitter if immediate (initiated via /flush handler for example)
@@ -279,7 +283,7 @@ func (i *Ingester) handleComplete(ctx context.Context, op *flushOp) (retry bool,
 		return false, err
 	}
 
-	err = instance.CompleteBlock(ctx, op.blockID)
+	completedIDs, err := instance.CompleteBlock(ctx, op.blockID)
 	level.Info(log.Logger).Log("msg", "block completed", "tenant", op.userID, "blockID", op.blockID, "duration", time.Since(start))
 	if err != nil {
 		handleFailedOp(op, err)
@@ -301,14 +305,16 @@ func (i *Ingester) handleComplete(ctx context.Context, op *flushOp) (retry bool,
 		return false, fmt.Errorf("error clearing completing block: %w", err)
 	}
 
-	// add a flushOp for the block we just completed
+	// add a flushOp for the blocks we just completed
 	// No delay
 	if i.cfg.FlushObjectStorage {
-		i.enqueue(&flushOp{
-			kind:    opKindFlush,
-			userID:  instance.instanceID,
-			blockID: op.blockID,
-		}, false)
+		for _, id := range completedIDs {
+			i.enqueue(&flushOp{
+				kind:    opKindFlush,
+				userID:  instance.instanceID,
+				blockID: id,
+			}, false)
+		}
 	}
 
 	return false, nil
@@ -86,9 +86,13 @@ type Ingester struct {
 	// Used by ingest storage when enabled
 	ingestPartitionLifecycler *ring.PartitionInstanceLifecycler
 	ingestPartitionID         int32
+	kafkaConsumer             *kafkaConsumer
 
 	overrides ingesterOverrides
 
+	// conflictResolver resolves trace ID conflicts of all tenants, nil if they aren't detected
+	conflictResolver TraceConflictResolver
+
 	subservicesWatcher *services.FailureWatcher
 }
 
@@ -102,6 +106,8 @@ func New(cfg Config, store storage.Store, overrides overrides.Interface, reg pro
 		replayJitter: true,
 		overrides:    overrides,
 
+		conflictResolver: newTraceConflictResolver(cfg.TraceIDConflicts),
+
 		cutToWalStart: make(chan struct{}),
 		cutToWalStop:  make(chan struct{}),
 	}
@@ -143,6 +149,13 @@ func New(cfg Config, store storage.Store, overrides overrides.Interface, reg pro
 			partitionRingKV,
 			log.Logger,
 			prometheus.WrapRegistererWithPrefix("cortex_", reg))
+
+		if ingestCfg.IngestersConsume {
+			i.kafkaConsumer, err = newKafkaConsumer(i, reg)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// Now that the lifecycler has been created, we can create the limiter
@@ -190,6 +203,12 @@ func (i *Ingester) starting(ctx context.Context) error {
 		}
 	}
 
+	if i.kafkaConsumer != nil {
+		if err := i.kafkaConsumer.start(ctx); err != nil {
+			return err
+		}
+	}
+
 	// accept traces
 	i.pushErr.Store(nil)
 
@@ -212,6 +231,10 @@ func (i *Ingester) running(ctx context.Context) error {
 func (i *Ingester) stopping(_ error) error {
 	i.markUnavailable()
 
+	if i.kafkaConsumer != nil {
+		i.kafkaConsumer.shutdown()
+	}
+
 	// signal all cutting to wal to stop and wait for all goroutines to finish
 	close(i.cutToWalStop)
 	i.cutToWalWg.Wait()
@@ -224,6 +247,12 @@ func (i *Ingester) stopping(_ error) error {
 		i.cutAllInstancesToWal()
 	}
 
+	if i.kafkaConsumer != nil {
+		// all consumed traces are in the wal
+		i.kafkaConsumer.commit(context.Background(), time.Now())
+		i.kafkaConsumer.close()
+	}
+
 	if i.flushQueues != nil {
 		i.flushQueues.Stop()
 		i.flushQueuesDone.Wait()
@@ -380,6 +409,10 @@ func (i *Ingester) getOrCreateInstance(instanceID string) (*instance, error) {
 		if err != nil {
 			return nil, err
 		}
+		inst.conflictResolver = i.conflictResolver
+		if i.cfg.TraceAssembly.LateSpans {
+			inst.lateSpans = newLateSpanTracker()
+		}
 		i.instances[instanceID] = inst
 
 		i.cutToWalLoop(inst)
@@ -387,6 +420,12 @@ func (i *Ingester) getOrCreateInstance(instanceID string) (*instance, error) {
 	return inst, nil
 }
 
+// SetTraceConflictResolver replaces the resolver of trace ID conflicts configured by trace_id_conflicts. It must be
+// called before the ingester is started, and a nil resolver disables the detection.
+func (i *Ingester) SetTraceConflictResolver(r TraceConflictResolver) {
+	i.conflictResolver = r
+}
+
 func (i *Ingester) getInstanceByID(id string) (*instance, bool) {
 	i.instancesMtx.RLock()
 	defer i.instancesMtx.RUnlock()
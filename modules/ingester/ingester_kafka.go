@@ -0,0 +1,311 @@
+package ingester
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/backoff"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/ingest"
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/log"
+)
+
+var (
+	metricKafkaRecordsConsumed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_kafka_records_consumed_total",
+		Help:      "The total number of records consumed from the partition of the ingester.",
+	})
+	metricKafkaRecordsFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_kafka_records_failed_total",
+		Help:      "The total number of records consumed from the partition of the ingester that couldn't be decoded.",
+	})
+	metricKafkaCommittedOffset = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "ingester_kafka_committed_offset",
+		Help:      "The last offset of the partition of the ingester committed to Kafka.",
+	})
+)
+
+// Reasons of the traces consumed from Kafka that were refused by the instances, the same as the reasons of the
+// distributors when ingesters receive the traces.
+const (
+	reasonTraceTooLarge      = "trace_too_large"
+	reasonLiveTracesExceeded = "live_traces_exceeded"
+	reasonUnknown            = "unknown_error"
+)
+
+// kafkaConsumer consumes the partition of the ingester and pushes its traces to the instances. The records are
+// consumed at least once: consumed offsets are committed once their traces are in the WAL, and after a restart the
+// partition is replayed from the committed offset.
+type kafkaConsumer struct {
+	i         *Ingester
+	client    *kgo.Client
+	adm       *kadm.Client
+	topic     string
+	group     string
+	partition int32
+	decoder   *ingest.Decoder
+	encoder   model.SegmentDecoder
+
+	// dropLogger logs the refused traces, no client sees their errors
+	dropLogger *log.RateLimitedLogger
+
+	// checkpoints are the offsets consumed but not committed yet, in order, with the time they were consumed
+	checkpointsMtx sync.Mutex
+	checkpoints    []kafkaCheckpoint
+	committed      int64
+
+	stop func()
+	wg   sync.WaitGroup
+}
+
+type kafkaCheckpoint struct {
+	offset     int64
+	consumedAt time.Time
+}
+
+func newKafkaConsumer(i *Ingester, reg prometheus.Registerer) (*kafkaConsumer, error) {
+	cfg := i.cfg.IngestStorageConfig.Kafka
+
+	client, err := ingest.NewReaderClient(cfg, ingest.NewReaderClientMetrics("ingester", reg), log.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka reader client: %w", err)
+	}
+
+	return &kafkaConsumer{
+		i:          i,
+		client:     client,
+		adm:        kadm.NewClient(client),
+		topic:      cfg.Topic,
+		group:      cfg.GetConsumerGroup(i.cfg.LifecyclerConfig.ID, i.ingestPartitionID),
+		partition:  i.ingestPartitionID,
+		decoder:    ingest.NewDecoder(),
+		encoder:    model.MustNewSegmentDecoder(model.CurrentEncoding),
+		dropLogger: log.NewRateLimitedLogger(maxTraceLogLinesPerSecond, level.Warn(log.Logger)),
+		committed:  -1,
+	}, nil
+}
+
+// start resumes the consumption of the partition from the committed offset, or from the start if there is none
+func (c *kafkaConsumer) start(ctx context.Context) error {
+	boff := backoff.New(ctx, backoff.Config{
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: time.Minute, // If there is a network hiccup, we prefer to wait longer retrying, than fail the service.
+		MaxRetries: 10,
+	})
+
+	var (
+		offset = kgo.NewOffset().AtStart()
+		err    error
+	)
+	for boff.Ongoing() {
+		var commits kadm.OffsetResponses
+		commits, err = c.adm.FetchOffsets(ctx, c.group)
+		if err == nil {
+			err = commits.Error()
+		}
+		if err == nil {
+			if commit, ok := commits.Lookup(c.topic, c.partition); ok && commit.At >= 0 {
+				c.committed = commit.At
+				offset = kgo.NewOffset().At(commit.At)
+			}
+			break
+		}
+		ingest.HandleKafkaError(err, c.client.ForceMetadataRefresh)
+		level.Warn(log.Logger).Log("msg", "failed to fetch committed offset; will retry", "partition", c.partition, "err", err)
+		boff.Wait()
+	}
+	if err := boff.ErrCause(); err != nil {
+		return fmt.Errorf("failed to fetch committed offset of partition %d: %w", c.partition, err)
+	}
+
+	level.Info(log.Logger).Log("msg", "consuming partition", "partition", c.partition, "group", c.group, "commit_offset", c.committed)
+	metricKafkaCommittedOffset.Set(float64(c.committed))
+	c.client.AddConsumePartitions(map[string]map[int32]kgo.Offset{c.topic: {c.partition: offset}})
+
+	ctx, c.stop = context.WithCancel(context.Background())
+
+	c.wg.Add(2)
+	go c.consumeLoop(ctx)
+	go c.commitLoop(ctx)
+
+	return nil
+}
+
+// shutdown stops the consumption. The consumed offsets are committed once the traces are cut to the WAL by the
+// caller with commit.
+func (c *kafkaConsumer) shutdown() {
+	c.stop()
+	c.wg.Wait()
+}
+
+func (c *kafkaConsumer) close() {
+	c.client.Close()
+}
+
+func (c *kafkaConsumer) consumeLoop(ctx context.Context) {
+	defer c.wg.Done()
+
+	for ctx.Err() == nil {
+		fetches := c.client.PollFetches(ctx)
+		fetches.EachError(func(_ string, _ int32, err error) {
+			if !errors.Is(err, context.Canceled) {
+				level.Error(log.Logger).Log("msg", "failed to fetch records", "partition", c.partition, "err", err)
+				ingest.HandleKafkaError(err, c.client.ForceMetadataRefresh)
+			}
+		})
+
+		var last *kgo.Record
+		for iter := fetches.RecordIter(); !iter.Done(); {
+			last = iter.Next()
+			c.pushRecord(ctx, last)
+		}
+		if last == nil {
+			continue
+		}
+
+		ingest.SetPartitionLagSeconds(c.group, c.partition, time.Since(last.Timestamp))
+
+		c.checkpointsMtx.Lock()
+		c.checkpoints = append(c.checkpoints, kafkaCheckpoint{offset: last.Offset + 1, consumedAt: time.Now()})
+		c.checkpointsMtx.Unlock()
+	}
+}
+
+// pushRecord pushes the traces of a record. Traces refused by the instance are dropped, as they would be if they
+// were pushed by the distributors, and recorded as discarded spans.
+func (c *kafkaConsumer) pushRecord(ctx context.Context, r *kgo.Record) {
+	metricKafkaRecordsConsumed.Inc()
+
+	req, err := c.decoder.Decode(r.Value)
+	if err != nil {
+		metricKafkaRecordsFailed.Inc()
+		level.Error(log.Logger).Log("msg", "failed to decode record", "partition", r.Partition, "offset", r.Offset, "err", err)
+		return
+	}
+	defer c.decoder.Reset()
+
+	tenant := string(r.Key)
+	inst, err := c.i.getOrCreateInstance(tenant)
+	if err != nil {
+		level.Error(log.Logger).Log("msg", "failed to get instance", "tenant", tenant, "err", err)
+		return
+	}
+
+	for j := range req.Traces {
+		segment, spanCount, err := c.traceSegment(req.Traces[j].Slice)
+		if err != nil {
+			metricKafkaRecordsFailed.Inc()
+			level.Error(log.Logger).Log("msg", "failed to decode trace", "tenant", tenant, "partition", r.Partition, "offset", r.Offset, "err", err)
+			continue
+		}
+
+		if err := inst.PushBytes(ctx, req.Ids[j], segment); err != nil {
+			reason := discardReason(err)
+			overrides.RecordDiscardedSpans(spanCount, reason, tenant)
+			c.dropLogger.Log("msg", "dropped trace consumed from kafka", "tenant", tenant, "partition", r.Partition, "offset", r.Offset, "reason", reason, "spans", spanCount, "err", err)
+		}
+	}
+}
+
+// discardReason returns the reason of the spans of a trace refused by an instance
+func discardReason(err error) string {
+	switch {
+	case errors.Is(err, errTraceTooLarge):
+		return reasonTraceTooLarge
+	case errors.Is(err, errMaxLiveTraces):
+		return reasonLiveTracesExceeded
+	default:
+		return reasonUnknown
+	}
+}
+
+// traceSegment converts a trace of a record, marshalled by the distributor, to the segment format pushed to the
+// instances. It returns the segment and the number of spans of the trace.
+func (c *kafkaConsumer) traceSegment(b []byte) ([]byte, int, error) {
+	tr := &tempopb.Trace{}
+	if err := tr.Unmarshal(b); err != nil {
+		return nil, 0, err
+	}
+
+	start, end := uint32(math.MaxUint32), uint32(0)
+	spanCount := 0
+	for _, rs := range tr.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			spanCount += len(ss.Spans)
+			for _, s := range ss.Spans {
+				start = min(start, uint32(s.StartTimeUnixNano/uint64(time.Second)))
+				end = max(end, uint32(s.EndTimeUnixNano/uint64(time.Second)))
+			}
+		}
+	}
+	if start > end {
+		start = end
+	}
+
+	segment, err := c.encoder.PrepareForWrite(tr, start, end)
+	return segment, spanCount, err
+}
+
+// commitLoop commits the offsets whose traces were cut to the WAL. A trace is cut at the first cut to the WAL after
+// it's live for longer than the trace live period, and instances are cut every flush check period, so the records
+// consumed before that window are in the WAL.
+func (c *kafkaConsumer) commitLoop(ctx context.Context) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.i.cfg.IngestStorageConfig.Kafka.ConsumerGroupOffsetCommitInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.commit(ctx, time.Now().Add(-c.i.cfg.MaxTraceLive-2*c.i.cfg.FlushCheckPeriod))
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// commit commits the last offset consumed before the cutoff
+func (c *kafkaConsumer) commit(ctx context.Context, cutoff time.Time) {
+	c.checkpointsMtx.Lock()
+	n := 0
+	for n < len(c.checkpoints) && !c.checkpoints[n].consumedAt.After(cutoff) {
+		n++
+	}
+	if n == 0 {
+		c.checkpointsMtx.Unlock()
+		return
+	}
+	offset := c.checkpoints[n-1].offset
+	c.checkpointsMtx.Unlock()
+
+	offsets := make(kadm.Offsets)
+	offsets.Add(kadm.Offset{Topic: c.topic, Partition: c.partition, At: offset, LeaderEpoch: -1})
+	if err := c.adm.CommitAllOffsets(ctx, c.group, offsets); err != nil {
+		// retried with the next commit
+		ingest.HandleKafkaError(err, c.client.ForceMetadataRefresh)
+		level.Warn(log.Logger).Log("msg", "failed to commit offset", "partition", c.partition, "commit_offset", offset, "err", err)
+		return
+	}
+
+	c.checkpointsMtx.Lock()
+	c.checkpoints = c.checkpoints[n:]
+	c.committed = offset
+	c.checkpointsMtx.Unlock()
+	metricKafkaCommittedOffset.Set(float64(offset))
+}
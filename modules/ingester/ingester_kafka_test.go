@@ -0,0 +1,176 @@
+package ingester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/proto"
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/kv/consul"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/ingest"
+	"github.com/grafana/tempo/pkg/ingest/testkafka"
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/tempopb"
+	tempo_log "github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+const testKafkaTopic = "test-topic"
+
+func kafkaIngester(t *testing.T, tmpDir, address string) *Ingester {
+	cfg := defaultIngesterTestConfig()
+	cfg.MaxTraceLive = time.Hour
+
+	flagext.DefaultValues(&cfg.IngestStorageConfig.Kafka)
+	cfg.IngestStorageConfig.Enabled = true
+	cfg.IngestStorageConfig.IngestersConsume = true
+	cfg.IngestStorageConfig.Kafka.Address = address
+	cfg.IngestStorageConfig.Kafka.Topic = testKafkaTopic
+
+	partitionStore, _ := consul.NewInMemoryClient(ring.GetPartitionRingCodec(), log.NewNopLogger(), nil)
+	cfg.IngesterPartitionRing.KVStore.Mock = partitionStore
+
+	limits, err := overrides.NewOverrides(defaultOverridesConfig(), nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	ingester, err := New(cfg, defaultIngesterStore(t, tmpDir), limits, prometheus.NewPedanticRegistry(), true)
+	require.NoError(t, err)
+	ingester.replayJitter = false
+
+	require.NoError(t, ingester.starting(context.Background()))
+	return ingester
+}
+
+func produceTrace(t *testing.T, client *kgo.Client, tenant string) (*tempopb.Trace, []byte) {
+	id := test.ValidTraceID(nil)
+	tr := test.MakeTrace(5, id)
+	b, err := proto.Marshal(tr)
+	require.NoError(t, err)
+
+	records, err := ingest.Encode(0, tenant, &tempopb.PushBytesRequest{
+		Traces: []tempopb.PreallocBytes{{Slice: b}},
+		Ids:    [][]byte{id},
+	}, 1_000_000)
+	require.NoError(t, err)
+	require.NoError(t, client.ProduceSync(context.Background(), records...).FirstErr())
+
+	return tr, id
+}
+
+func requireTraceFound(t *testing.T, i *Ingester, tenant string, tr *tempopb.Trace, id []byte) {
+	ctx := user.InjectOrgID(context.Background(), tenant)
+	require.Eventually(t, func() bool {
+		resp, err := i.FindTraceByID(ctx, &tempopb.TraceByIDRequest{TraceID: id})
+		return err == nil && resp.Trace != nil && proto.Equal(tr, resp.Trace)
+	}, 10*time.Second, 50*time.Millisecond)
+}
+
+func TestIngesterConsumesKafka(t *testing.T) {
+	_, address := testkafka.CreateCluster(t, 1, testKafkaTopic)
+
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(address),
+		kgo.DefaultProduceTopic(testKafkaTopic),
+		kgo.RecordPartitioner(kgo.ManualPartitioner()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	tmpDir := t.TempDir()
+	ingester := kafkaIngester(t, tmpDir, address)
+
+	tr1, id1 := produceTrace(t, client, "test")
+	requireTraceFound(t, ingester, "test", tr1, id1)
+	consumed := testutil.ToFloat64(metricKafkaRecordsConsumed)
+
+	// the offset is committed on shutdown, once the traces are in the wal
+	require.NoError(t, ingester.stopping(nil))
+
+	offsets, err := kadm.NewClient(client).FetchOffsetsForTopics(context.Background(), ingester.kafkaConsumer.group, testKafkaTopic)
+	require.NoError(t, err)
+	offset, ok := offsets.Lookup(testKafkaTopic, 0)
+	require.True(t, ok)
+	require.Equal(t, int64(1), offset.At)
+
+	// the restarted ingester replays the wal and resumes from the committed offset
+	tr2, id2 := produceTrace(t, client, "test")
+	ingester = kafkaIngester(t, tmpDir, address)
+	t.Cleanup(func() { _ = ingester.stopping(nil) })
+
+	requireTraceFound(t, ingester, "test", tr2, id2)
+	requireTraceFound(t, ingester, "test", tr1, id1)
+	require.Equal(t, consumed+1, testutil.ToFloat64(metricKafkaRecordsConsumed))
+}
+
+func TestKafkaConsumerRecordsRefusedTraces(t *testing.T) {
+	o := defaultOverridesConfig()
+	o.Defaults.Global.MaxBytesPerTrace = 10
+	ingester := defaultIngesterWithOverrides(t, t.TempDir(), o)
+
+	c := &kafkaConsumer{
+		i:          ingester,
+		decoder:    ingest.NewDecoder(),
+		encoder:    model.MustNewSegmentDecoder(model.CurrentEncoding),
+		dropLogger: tempo_log.NewRateLimitedLogger(maxTraceLogLinesPerSecond, log.NewNopLogger()),
+	}
+
+	const tenant = "kafka-refused"
+	id := test.ValidTraceID(nil)
+	b, err := proto.Marshal(test.MakeTrace(5, id))
+	require.NoError(t, err)
+	records, err := ingest.Encode(0, tenant, &tempopb.PushBytesRequest{
+		Traces: []tempopb.PreallocBytes{{Slice: b}},
+		Ids:    [][]byte{id},
+	}, 1_000_000)
+	require.NoError(t, err)
+
+	before := discardedSpans(t, reasonTraceTooLarge, tenant)
+	c.pushRecord(context.Background(), records[0])
+	require.Equal(t, before+float64(spanCount(t, b)), discardedSpans(t, reasonTraceTooLarge, tenant))
+}
+
+func spanCount(t *testing.T, b []byte) int {
+	tr := &tempopb.Trace{}
+	require.NoError(t, proto.Unmarshal(b, tr))
+
+	count := 0
+	for _, rs := range tr.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			count += len(ss.Spans)
+		}
+	}
+	return count
+}
+
+// discardedSpans returns the spans discarded for the reason and tenant in the default registry
+func discardedSpans(t *testing.T, reason, tenant string) float64 {
+	mfs, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, mf := range mfs {
+		if mf.GetName() != "tempo_discarded_spans_total" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["reason"] == reason && labels["tenant"] == tenant {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -74,6 +75,21 @@ var (
 		Name:      "ingester_replay_errors_total",
 		Help:      "The total number of replay errors received per tenant.",
 	}, []string{"tenant"})
+	metricAttributePolicyDroppedBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_attribute_policy_dropped_bytes_total",
+		Help:      "The total number of attribute bytes dropped by the tenant's storage attribute policy when completing blocks.",
+	}, []string{"tenant"})
+	metricAttributeCardinalityLimitedValues = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_attribute_cardinality_limited_values_total",
+		Help:      "The total number of attribute values hashed or dropped by the tenant's attribute cardinality limit.",
+	}, []string{"tenant", "action"})
+	metricAttributeCardinalityLimitedKeys = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "ingester_attribute_cardinality_limited_keys",
+		Help:      "The number of attribute keys above the tenant's attribute cardinality limit.",
+	}, []string{"tenant"})
 )
 
 type instance struct {
@@ -81,6 +97,8 @@ type instance struct {
 	traces         map[uint64]*liveTrace
 	traceSizes     *tracesizes.Tracker
 	traceSizeBytes uint64
+	// splitTraces are the live traces cut by a trace ID conflict, written to the head block at the next cut
+	splitTraces []*liveTrace
 
 	headBlockMtx sync.RWMutex
 	headBlock    common.WALBlock
@@ -88,6 +106,8 @@ type instance struct {
 	blocksMtx        sync.RWMutex
 	completingBlocks []common.WALBlock
 	completeBlocks   []*LocalBlock
+	// splitTraceBlocks are the blocks cut for split traces that weren't handed out by SplitTraceBlocks yet
+	splitTraceBlocks []uuid.UUID
 
 	lastBlockCut time.Time
 
@@ -100,6 +120,18 @@ type instance struct {
 	dedicatedColumns backend.DedicatedColumns
 	overrides        ingesterOverrides
 
+	// headBlockRetention classifies the traces of the head block if the tenant has retention classes
+	headBlockRetention *tempodb.RetentionClassifier
+	// attributeCardinality limits the distinct values of attribute keys if the tenant has a cardinality limit
+	attributeCardinality *common.AttributeCardinalityLimiter
+	objectDecoder        model.ObjectDecoder
+	// conflictResolver resolves trace ID conflicts, nil if they aren't detected
+	conflictResolver TraceConflictResolver
+	// lateSpans tracks the traces of cut blocks, nil if late spans aren't counted
+	lateSpans *lateSpanTracker
+	// tails are the live tails of the tenant, matched against the traces written to the head block
+	tails liveTails
+
 	local       *local.Backend
 	localReader backend.Reader
 	localWriter backend.Writer
@@ -122,6 +154,7 @@ func newInstance(instanceID string, limiter Limiter, overrides ingesterOverrides
 
 		dedicatedColumns: dedicatedColumns,
 		overrides:        overrides,
+		objectDecoder:    model.MustNewObjectDecoder(model.CurrentEncoding),
 
 		local:       l,
 		localReader: backend.NewReader(l),
@@ -192,12 +225,35 @@ func (i *instance) PushBytes(ctx context.Context, id, traceBytes []byte) error {
 }
 
 func (i *instance) push(ctx context.Context, id, traceBytes []byte) error {
+	// segments are decoded before taking the lock so pushes of other traces aren't blocked by it
+	decoder := model.MustNewSegmentDecoder(model.CurrentEncoding)
+	var roots []traceRoot
+	if i.conflictResolver != nil {
+		roots = segmentRoots(decoder, traceBytes)
+	}
+
+	cutAt, err := i.pushLocked(ctx, id, traceBytes, roots)
+	if err != nil {
+		return err
+	}
+
+	if !cutAt.IsZero() {
+		metricLateSpansTotal.WithLabelValues(i.instanceID).Add(float64(segmentSpanCount(decoder, traceBytes)))
+		metricLateSpanLag.WithLabelValues(i.instanceID).Observe(time.Since(cutAt).Seconds())
+	}
+
+	return nil
+}
+
+// pushLocked pushes the segment with the roots to its live trace under tracesMtx. It returns when the trace was
+// cut to a block if the segment holds late spans.
+func (i *instance) pushLocked(ctx context.Context, id, traceBytes []byte, roots []traceRoot) (time.Time, error) {
 	i.tracesMtx.Lock()
 	defer i.tracesMtx.Unlock()
 
 	err := i.limiter.AssertMaxTracesPerUser(i.instanceID, len(i.traces))
 	if err != nil {
-		return errMaxLiveTraces
+		return time.Time{}, errMaxLiveTraces
 	}
 
 	maxBytes := i.limiter.Limits().MaxBytesPerTrace(i.instanceID)
@@ -205,20 +261,28 @@ func (i *instance) push(ctx context.Context, id, traceBytes []byte) error {
 
 	if maxBytes > 0 && !i.traceSizes.Allow(id, reqSize, maxBytes) {
 		i.maxTraceLogger.Log("msg", overrides.ErrorPrefixTraceTooLarge, "max", maxBytes, "size", reqSize, "trace", hex.EncodeToString(id))
-		return errTraceTooLarge
+		return time.Time{}, errTraceTooLarge
 	}
 
 	tkn := util.HashForTraceID(id)
-	trace := i.getOrCreateTrace(id, tkn)
+	trace, created := i.getOrCreateTrace(id, tkn)
+	if created && i.lateSpans != nil {
+		trace.cutAt, _ = i.lateSpans.CutAt(tkn)
+	}
+
+	if i.conflictResolver != nil && trace.conflicts(roots) {
+		trace = i.resolveTraceConflict(trace, tkn, roots)
+	}
 
 	err = trace.Push(ctx, i.instanceID, traceBytes)
 	if err != nil {
-		return err
+		return time.Time{}, err
 	}
+	trace.addRoots(roots)
 
 	i.traceSizeBytes += uint64(reqSize)
 
-	return nil
+	return trace.cutAt, nil
 }
 
 func (i *instance) measureReceivedBytes(traceBytes []byte) {
@@ -230,7 +294,70 @@ func (i *instance) measureReceivedBytes(traceBytes []byte) {
 
 // CutCompleteTraces moves any complete traces out of the map to complete traces.
 func (i *instance) CutCompleteTraces(idleCutoff time.Duration, liveCutoff time.Duration, immediate bool) error {
-	tracesToCut := i.tracesToCut(time.Now(), idleCutoff, liveCutoff, immediate)
+	err := i.cutSplitTraces()
+	if err != nil {
+		return err
+	}
+
+	return i.writeTracesToHeadBlock(i.tracesToCut(time.Now(), idleCutoff, liveCutoff, immediate))
+}
+
+// cutSplitTraces writes the traces split by trace ID conflicts to the head block. The traces of a block with the
+// same ID are combined when it's completed, so the head block is cut after each part of a split trace and every part
+// ends up in its own block. The live traces are written to the next head block.
+func (i *instance) cutSplitTraces() error {
+	i.tracesMtx.Lock()
+	splitTraces := i.splitTraces
+	i.splitTraces = nil
+	for _, t := range splitTraces {
+		i.traceSizeBytes -= t.Size()
+	}
+	i.tracesMtx.Unlock()
+
+	for len(splitTraces) > 0 {
+		var next, rest []*liveTrace
+		for _, t := range splitTraces {
+			if slices.ContainsFunc(next, func(n *liveTrace) bool { return bytes.Equal(n.traceID, t.traceID) }) {
+				rest = append(rest, t)
+				continue
+			}
+			next = append(next, t)
+		}
+
+		err := i.writeTracesToHeadBlock(next)
+		if err != nil {
+			return err
+		}
+
+		i.headBlockMtx.Lock()
+		blockID, err := i.cutHeadBlock()
+		i.headBlockMtx.Unlock()
+		if err != nil {
+			return fmt.Errorf("failed to cut head block of split traces: %w", err)
+		}
+
+		i.blocksMtx.Lock()
+		i.splitTraceBlocks = append(i.splitTraceBlocks, blockID)
+		i.blocksMtx.Unlock()
+
+		splitTraces = rest
+	}
+	return nil
+}
+
+// SplitTraceBlocks returns the IDs of the blocks cut for split traces since the last call. They're completed like
+// the blocks returned by CutBlockIfReady.
+func (i *instance) SplitTraceBlocks() []uuid.UUID {
+	i.blocksMtx.Lock()
+	defer i.blocksMtx.Unlock()
+
+	ids := i.splitTraceBlocks
+	i.splitTraceBlocks = nil
+	return ids
+}
+
+// writeTracesToHeadBlock writes the traces to the head block and flushes it.
+func (i *instance) writeTracesToHeadBlock(tracesToCut []*liveTrace) error {
 	segmentDecoder := model.MustNewSegmentDecoder(model.CurrentEncoding)
 
 	// Sort by ID
@@ -274,41 +401,50 @@ func (i *instance) CutBlockIfReady(maxBlockLifetime time.Duration, maxBlockBytes
 
 	now := time.Now()
 	if i.lastBlockCut.Add(maxBlockLifetime).Before(now) || i.headBlock.DataLength() >= maxBlockBytes || immediate {
-		// Reset trace sizes when cutting block
-		i.traceSizes.ClearIdle(i.lastBlockCut)
+		return i.cutHeadBlock()
+	}
 
-		// Final flush
-		err := i.headBlock.Flush()
-		if err != nil {
-			return uuid.Nil, fmt.Errorf("failed to flush head block: %w", err)
-		}
+	return uuid.Nil, nil
+}
 
-		completingBlock := i.headBlock
+// cutHeadBlock moves the head block to the completing blocks and returns its ID. Must be called under headBlockMtx.
+func (i *instance) cutHeadBlock() (uuid.UUID, error) {
+	// Reset trace sizes when cutting block
+	i.traceSizes.ClearIdle(i.lastBlockCut)
 
-		// Now that we are adding a new block take the blocks mutex.
-		// A warning about deadlocks!!  This area does a hard-acquire of both mutexes.
-		// To avoid deadlocks this function and all others must acquire them in
-		// the ** same_order ** or else!!! i.e. another function can't acquire blocksMtx
-		// then headblockMtx. Even if the likelihood is low it is a statistical certainly
-		// that eventually a deadlock will occur.
-		i.blocksMtx.Lock()
-		defer i.blocksMtx.Unlock()
+	// Final flush
+	err := i.headBlock.Flush()
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to flush head block: %w", err)
+	}
 
-		i.completingBlocks = append(i.completingBlocks, completingBlock)
+	completingBlock := i.headBlock
+	if i.lateSpans != nil {
+		i.lateSpans.BlockCut((uuid.UUID)(completingBlock.BlockMeta().BlockID))
+	}
 
-		err = i.resetHeadBlock()
-		if err != nil {
-			return uuid.Nil, fmt.Errorf("failed to resetHeadBlock: %w", err)
-		}
+	// Now that we are adding a new block take the blocks mutex.
+	// A warning about deadlocks!!  This area does a hard-acquire of both mutexes.
+	// To avoid deadlocks this function and all others must acquire them in
+	// the ** same_order ** or else!!! i.e. another function can't acquire blocksMtx
+	// then headblockMtx. Even if the likelihood is low it is a statistical certainly
+	// that eventually a deadlock will occur.
+	i.blocksMtx.Lock()
+	defer i.blocksMtx.Unlock()
+
+	i.completingBlocks = append(i.completingBlocks, completingBlock)
 
-		return (uuid.UUID)(completingBlock.BlockMeta().BlockID), nil
+	err = i.resetHeadBlock()
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to resetHeadBlock: %w", err)
 	}
 
-	return uuid.Nil, nil
+	return (uuid.UUID)(completingBlock.BlockMeta().BlockID), nil
 }
 
-// CompleteBlock moves a completingBlock to a completeBlock. The new completeBlock has the same ID.
-func (i *instance) CompleteBlock(ctx context.Context, blockID uuid.UUID) error {
+// CompleteBlock moves a completingBlock to a completeBlock. The new completeBlock has the same ID, unless the traces
+// of the tenant have retention classes: then there is a completeBlock per class and the IDs of all are returned.
+func (i *instance) CompleteBlock(ctx context.Context, blockID uuid.UUID) ([]uuid.UUID, error) {
 	i.blocksMtx.Lock()
 	var completingBlock common.WALBlock
 	for _, iterBlock := range i.completingBlocks {
@@ -320,21 +456,40 @@ func (i *instance) CompleteBlock(ctx context.Context, blockID uuid.UUID) error {
 	i.blocksMtx.Unlock()
 
 	if completingBlock == nil {
-		return fmt.Errorf("error finding completingBlock")
+		return nil, fmt.Errorf("error finding completingBlock")
 	}
 
-	backendBlock, err := i.writer.CompleteBlockWithBackend(ctx, completingBlock, i.localReader, i.localWriter)
+	blocks, err := splitByRetentionClass(ctx, completingBlock, i.getRetentionClasses())
 	if err != nil {
-		return fmt.Errorf("error completing wal block with local backend: %w", err)
-	}
+		return nil, fmt.Errorf("error splitting wal block by retention class: %w", err)
+	}
+
+	filter := common.NewAttributeFilter(i.overrides.StorageAttributePolicy(i.instanceID))
+	ingesterBlocks := make([]*LocalBlock, 0, len(blocks))
+	ids := make([]uuid.UUID, 0, len(blocks))
+	for _, b := range blocks {
+		if filter != nil {
+			b = &attributeFilterWALBlock{
+				WALBlock: b,
+				filter:   filter,
+				tenantID: i.instanceID,
+			}
+		}
+
+		backendBlock, err := i.writer.CompleteBlockWithBackend(ctx, b, i.overrides.StorageBlockEncoding(i.instanceID), i.localReader, i.localWriter)
+		if err != nil {
+			return nil, fmt.Errorf("error completing wal block with local backend: %w", err)
+		}
 
-	ingesterBlock := NewLocalBlock(ctx, backendBlock, i.local)
+		ingesterBlocks = append(ingesterBlocks, NewLocalBlock(ctx, backendBlock, i.local))
+		ids = append(ids, (uuid.UUID)(backendBlock.BlockMeta().BlockID))
+	}
 
 	i.blocksMtx.Lock()
-	i.completeBlocks = append(i.completeBlocks, ingesterBlock)
+	i.completeBlocks = append(i.completeBlocks, ingesterBlocks...)
 	i.blocksMtx.Unlock()
 
-	return nil
+	return ids, nil
 }
 
 func (i *instance) ClearCompletingBlock(blockID uuid.UUID) error {
@@ -394,6 +549,9 @@ func (i *instance) ClearOldBlocks(flushObjectStorage bool, completeBlockTimeout
 		if err != nil {
 			return err
 		}
+		if i.lateSpans != nil {
+			i.lateSpans.BlockCleared((uuid.UUID)(b.BlockMeta().BlockID))
+		}
 		i.completeBlocks = append(i.completeBlocks[:idx], i.completeBlocks[idx+1:]...)
 		idx--
 		numBlocks--
@@ -506,18 +664,61 @@ func (i *instance) AddCompletingBlock(b common.WALBlock) {
 	i.completingBlocks = append(i.completingBlocks, b)
 }
 
-// getOrCreateTrace will return a new trace object for the given request
+// attributeFilterWALBlock applies the tenant's storage attribute policy to every trace
+// iterated from the wrapped WAL block while it is being completed.
+type attributeFilterWALBlock struct {
+	common.WALBlock
+	filter   *common.AttributeFilter
+	tenantID string
+}
+
+func (b *attributeFilterWALBlock) Iterator() (common.Iterator, error) {
+	iter, err := b.WALBlock.Iterator()
+	if err != nil {
+		return nil, err
+	}
+
+	dropped := metricAttributePolicyDroppedBytes.WithLabelValues(b.tenantID)
+	return common.NewAttributeFilterIterator(iter, b.filter, func(bytes int) {
+		dropped.Add(float64(bytes))
+	}), nil
+}
+
+// getOrCreateTrace will return a new trace object for the given request and true if it was created
 //
 //	It must be called under the i.tracesMtx lock
-func (i *instance) getOrCreateTrace(traceID []byte, fp uint64) *liveTrace {
+func (i *instance) getOrCreateTrace(traceID []byte, fp uint64) (*liveTrace, bool) {
 	trace, ok := i.traces[fp]
 	if ok {
-		return trace
+		return trace, false
 	}
 
 	trace = newTrace(traceID)
 	i.traces[fp] = trace
 
+	return trace, true
+}
+
+// resolveTraceConflict asks the resolver what to do with a segment that conflicts with a live trace and returns the
+// live trace to push the segment to. Must be called under tracesMtx.
+func (i *instance) resolveTraceConflict(trace *liveTrace, fp uint64, roots []traceRoot) *liveTrace {
+	action := i.conflictResolver.Resolve(TraceConflict{
+		TenantID:            i.instanceID,
+		TraceID:             trace.traceID,
+		RootServices:        rootServices(trace.roots),
+		SegmentRootServices: rootServices(roots),
+	})
+	metricTraceIDConflicts.WithLabelValues(i.instanceID, action.String()).Inc()
+
+	if action != TraceConflictSplit {
+		return trace
+	}
+
+	i.splitTraces = append(i.splitTraces, trace)
+	cutAt := trace.cutAt
+	trace = newTrace(trace.traceID)
+	trace.cutAt = cutAt
+	i.traces[fp] = trace
 	return trace
 }
 
@@ -536,6 +737,8 @@ func (i *instance) resetHeadBlock() error {
 	}
 
 	i.headBlock = newHeadBlock
+	i.headBlockRetention = tempodb.NewRetentionClassifier(i.getRetentionClasses())
+	i.resetAttributeCardinality()
 	i.lastBlockCut = time.Now()
 
 	return nil
@@ -554,6 +757,33 @@ func (i *instance) getDedicatedColumns() backend.DedicatedColumns {
 	return i.dedicatedColumns
 }
 
+// resetAttributeCardinality starts counting attribute values for a new head block. The limiter is kept if the
+// tenant's policy didn't change so that the keys limited in the previous block are still reported.
+func (i *instance) resetAttributeCardinality() {
+	policy := i.overrides.StorageAttributeCardinality(i.instanceID)
+	if err := policy.Validate(); err != nil {
+		level.Error(i.logger).Log("msg", "Unable to apply overrides for attribute cardinality. Policy invalid.", "error", err)
+		policy = common.AttributeCardinalityPolicy{}
+	}
+
+	if i.attributeCardinality != nil && i.attributeCardinality.Policy() == policy {
+		i.attributeCardinality.Reset()
+	} else {
+		i.attributeCardinality = common.NewAttributeCardinalityLimiter(policy)
+	}
+
+	metricAttributeCardinalityLimitedKeys.WithLabelValues(i.instanceID).Set(float64(i.attributeCardinality.NumLimited()))
+}
+
+func (i *instance) getRetentionClasses() *tempodb.RetentionClasses {
+	attribute, classes := i.overrides.BlockRetentionClasses(i.instanceID)
+	return &tempodb.RetentionClasses{
+		Attribute: attribute,
+		Retention: classes,
+		Default:   i.overrides.BlockRetention(i.instanceID),
+	}
+}
+
 func (i *instance) tracesToCut(now time.Time, idleCutoff time.Duration, liveCutoff time.Duration, immediate bool) []*liveTrace {
 	i.tracesMtx.Lock()
 	defer i.tracesMtx.Unlock()
@@ -566,13 +796,26 @@ func (i *instance) tracesToCut(now time.Time, idleCutoff time.Duration, liveCuto
 	liveCutoffTime := now.Add(-liveCutoff)
 	tracesToCut := make([]*liveTrace, 0, len(i.traces))
 
-	for key, trace := range i.traces {
-		if idleCutoffTime.After(trace.lastAppend) ||
-			liveCutoffTime.After(trace.createdAt) ||
-			immediate {
+	lifetime := metricTraceLifetime.WithLabelValues(i.instanceID)
+	assemblyIdle := metricTraceAssemblyDuration.WithLabelValues(i.instanceID, traceCutReasonIdle)
+	assemblyLive := metricTraceAssemblyDuration.WithLabelValues(i.instanceID, traceCutReasonLive)
 
+	for key, trace := range i.traces {
+		idle := idleCutoffTime.After(trace.lastAppend)
+		live := liveCutoffTime.After(trace.createdAt)
+		if idle || live || immediate {
 			tracesToCut = append(tracesToCut, trace)
 
+			// traces cut immediately, e.g. on shutdown, aren't complete and aren't measured
+			if idle || live {
+				assembly := assemblyLive
+				if idle {
+					assembly = assemblyIdle
+				}
+				assembly.Observe(now.Sub(trace.createdAt).Seconds())
+				lifetime.Observe(trace.lastAppend.Sub(trace.createdAt).Seconds())
+			}
+
 			// decrease live trace bytes
 			i.traceSizeBytes -= trace.Size()
 
@@ -588,11 +831,44 @@ func (i *instance) writeTraceToHeadBlock(id common.ID, b []byte, start, end uint
 	defer i.headBlockMtx.Unlock()
 
 	i.tracesCreatedTotal.Inc()
-	err := i.headBlock.Append(id, b, start, end, true)
+	if i.lateSpans != nil {
+		i.lateSpans.Written(util.HashForTraceID(id), time.Now())
+	}
+
+	tailing := i.tails.active()
+	if i.headBlockRetention == nil && i.attributeCardinality == nil && !tailing {
+		return i.headBlock.Append(id, b, start, end, true)
+	}
+
+	// the trace is decoded here instead of in the block to find its retention class, limit its attributes and match
+	// it against the live tails
+	tr, err := i.objectDecoder.PrepareForRead(b)
+	if err != nil {
+		return fmt.Errorf("error preparing trace for read: %w", err)
+	}
+
+	if limited := i.attributeCardinality.LimitTrace(tr); limited > 0 {
+		action := i.attributeCardinality.Policy().Action
+		if action == "" {
+			action = common.AttributeCardinalityActionHash
+		}
+		metricAttributeCardinalityLimitedValues.WithLabelValues(i.instanceID, action).Add(float64(limited))
+		metricAttributeCardinalityLimitedKeys.WithLabelValues(i.instanceID).Set(float64(i.attributeCardinality.NumLimited()))
+	}
+
+	err = i.headBlock.AppendTrace(id, tr, start, end, true)
 	if err != nil {
 		return err
 	}
 
+	if i.headBlockRetention != nil {
+		i.headBlockRetention.Observe(tr)
+		i.headBlock.BlockMeta().RetentionClass = i.headBlockRetention.Class()
+	}
+	if tailing {
+		i.tails.match(i.instanceID, id, tr)
+	}
+
 	return nil
 }
 
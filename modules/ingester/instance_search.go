@@ -52,9 +52,14 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 		}
 	}
 
+	order, err := traceql.ParseSearchOrder(req.OrderBy)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing order: %w", err)
+	}
+
 	var (
 		resultsMtx = sync.Mutex{}
-		combiner   = traceql.NewMetadataCombiner(maxResults, mostRecent)
+		combiner   = traceql.NewOrderedMetadataCombiner(maxResults, order, mostRecent)
 		metrics    = &tempopb.SearchMetrics{}
 		opts       = common.DefaultSearchOptions()
 		anyErr     atomic.Error
@@ -387,7 +392,10 @@ func (i *instance) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTag
 	defer span.End()
 
 	limit := i.limiter.Limits().MaxBytesPerTagValuesQuery(userID)
-	valueCollector := collector.NewDistinctValue(limit, req.MaxTagValues, req.StaleValueThreshold, func(v tempopb.TagValue) int { return len(v.Type) + len(v.Value) })
+	valueCollector, err := collector.NewTagValuesV2(limit, req)
+	if err != nil {
+		return nil, err
+	}
 	mc := collector.NewMetricsCollector() // to collect bytesRead metric
 
 	engine := traceql.NewEngine()
@@ -488,6 +496,9 @@ func (i *instance) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTag
 			// we can remove this if this becomes an issue but leave it in for now to more accurate.
 			mc.Add(uint64(len(cacheData)))
 
+			if resp.NextToken != "" {
+				valueCollector.MarkTruncated()
+			}
 			for _, v := range resp.TagValues {
 				if valueCollector.Collect(*v) {
 					break // we have reached the limit, so stop
@@ -499,7 +510,10 @@ func (i *instance) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTag
 		// cache miss, search the block. We will cache the results if we find any.
 		span.SetAttributes(attribute.Bool("cached", false))
 		// using local collector to collect values from the block and cache them.
-		localCol := collector.NewDistinctValue[tempopb.TagValue](limit, req.MaxTagValues, req.StaleValueThreshold, func(v tempopb.TagValue) int { return len(v.Type) + len(v.Value) })
+		localCol, err := collector.NewTagValuesV2(limit, req)
+		if err != nil {
+			return err
+		}
 		localErr := performSearch(ctx, b, localCol)
 		if localErr != nil {
 			return localErr
@@ -507,7 +521,8 @@ func (i *instance) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTag
 
 		// marshal the values local collector and set the cache
 		values := localCol.Values()
-		v2RespProto, err := valuesToTagValuesV2RespProto(values)
+		nextToken := collector.NextTagValuesToken(localCol)
+		v2RespProto, err := valuesToTagValuesV2RespProto(values, nextToken)
 		if err == nil && len(v2RespProto) > 0 {
 			err2 := b.SetDiskCache(ctx, cacheKey, v2RespProto)
 			if err2 != nil {
@@ -516,6 +531,9 @@ func (i *instance) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTag
 		}
 
 		// now add values to the central collector to make sure they are included in the response.
+		if nextToken != "" {
+			valueCollector.MarkTruncated()
+		}
 		for _, v := range values {
 			if valueCollector.Collect(v) {
 				break // we have reached the limit, so stop
@@ -580,7 +598,8 @@ func (i *instance) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTag
 	}
 
 	resp := &tempopb.SearchTagValuesV2Response{
-		Metrics: &tempopb.MetadataMetrics{InspectedBytes: mc.TotalValue()}, // include metrics in response
+		Metrics:   &tempopb.MetadataMetrics{InspectedBytes: mc.TotalValue()}, // include metrics in response
+		NextToken: collector.NextTagValuesToken(valueCollector),
 	}
 
 	for _, v := range valueCollector.Values() {
@@ -624,15 +643,19 @@ func searchTagValuesV2CacheKey(req *tempopb.SearchTagValuesRequest, limit int, p
 	h := fnv1a.HashString64(req.TagName)
 	h = fnv1a.AddString64(h, query)
 	h = fnv1a.AddUint64(h, uint64(limit))
+	if req.PageSize > 0 {
+		h = fnv1a.AddUint64(h, uint64(req.PageSize))
+		h = fnv1a.AddString64(h, req.After)
+	}
 
 	return fmt.Sprintf("%s_%v.buf", prefix, h)
 }
 
 // valuesToTagValuesV2RespProto converts TagValues to a protobuf marshalled bytes
 // this is slightly modified version of valuesToV2Response from querier.go
-func valuesToTagValuesV2RespProto(tagValues []tempopb.TagValue) ([]byte, error) {
-	// NOTE: we only cache TagValues and don't Marshal Metrics
-	resp := &tempopb.SearchTagValuesV2Response{}
+func valuesToTagValuesV2RespProto(tagValues []tempopb.TagValue, nextToken string) ([]byte, error) {
+	// NOTE: we only cache TagValues and the next page token and don't Marshal Metrics
+	resp := &tempopb.SearchTagValuesV2Response{NextToken: nextToken}
 	resp.TagValues = make([]*tempopb.TagValue, 0, len(tagValues))
 
 	for _, v := range tagValues {
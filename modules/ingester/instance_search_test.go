@@ -64,7 +64,7 @@ func TestInstanceSearch(t *testing.T) {
 	checkEqual(t, ids, sr)
 
 	// Test after completing a block
-	err = i.CompleteBlock(context.Background(), blockID)
+	_, err = i.CompleteBlock(context.Background(), blockID)
 	require.NoError(t, err)
 
 	sr, err = i.Search(context.Background(), req)
@@ -131,7 +131,7 @@ func TestInstanceSearchTraceQL(t *testing.T) {
 			checkEqual(t, ids, sr)
 
 			// Test after completing a block
-			err = i.CompleteBlock(context.Background(), blockID)
+			_, err = i.CompleteBlock(context.Background(), blockID)
 			require.NoError(t, err)
 
 			sr, err = i.Search(context.Background(), req)
@@ -216,7 +216,7 @@ func TestInstanceSearchWithStartAndEnd(t *testing.T) {
 	searchAndAssert(req, uint32(100))
 
 	// Test after completing a block
-	err = i.CompleteBlock(context.Background(), blockID)
+	_, err = i.CompleteBlock(context.Background(), blockID)
 	require.NoError(t, err)
 	searchAndAssert(req, uint32(200))
 
@@ -261,7 +261,7 @@ func TestInstanceSearchTags(t *testing.T) {
 	testSearchTagsAndValues(t, userCtx, i, tagKey, expectedTagValues)
 
 	// Test after completing a block
-	err = i.CompleteBlock(context.Background(), blockID)
+	_, err = i.CompleteBlock(context.Background(), blockID)
 	require.NoError(t, err)
 
 	testSearchTagsAndValues(t, userCtx, i, tagKey, expectedTagValues)
@@ -330,7 +330,7 @@ func TestInstanceSearchTagAndValuesV2(t *testing.T) {
 	testSearchTagsAndValuesV2(t, userCtx, i, tagKey, queryThatMatches, expectedTagValues, expectedEventTagValues, expectedLinkTagValues)
 
 	// Test after completing a block
-	err = i.CompleteBlock(context.Background(), blockID)
+	_, err = i.CompleteBlock(context.Background(), blockID)
 	require.NoError(t, err)
 	require.NoError(t, i.ClearCompletingBlock(blockID)) // Clear the completing block
 
@@ -672,7 +672,7 @@ func TestInstanceSearchDoesNotRace(t *testing.T) {
 		// Cut wal, complete, delete wal, then flush
 		blockID, _ := i.CutBlockIfReady(0, 0, true)
 		if blockID != uuid.Nil {
-			err := i.CompleteBlock(context.Background(), blockID)
+			_, err := i.CompleteBlock(context.Background(), blockID)
 			require.NoError(t, err)
 			err = i.ClearCompletingBlock(blockID)
 			require.NoError(t, err)
@@ -821,7 +821,7 @@ func TestInstanceSearchMetrics(t *testing.T) {
 	require.Less(t, numBytes, m.InspectedBytes)
 
 	// Test after completing a block
-	err = i.CompleteBlock(context.Background(), blockID)
+	_, err = i.CompleteBlock(context.Background(), blockID)
 	require.NoError(t, err)
 	err = i.ClearCompletingBlock(blockID)
 	require.NoError(t, err)
@@ -1047,6 +1047,20 @@ func Test_searchTagValuesV2CacheKey(t *testing.T) {
 			prefix:           "my_amazing_prefix",
 			expectedCacheKey: "my_amazing_prefix_7849238702443650194.buf",
 		},
+		{
+			name:             "page size changes the cache key for same query",
+			req:              &tempopb.SearchTagValuesRequest{TagName: "span.foo", Query: "{}", PageSize: 10},
+			limit:            500,
+			prefix:           "my_amazing_prefix",
+			expectedCacheKey: "my_amazing_prefix_13180406112076727104.buf",
+		},
+		{
+			name:             "page token changes the cache key for same query",
+			req:              &tempopb.SearchTagValuesRequest{TagName: "span.foo", Query: "{}", PageSize: 10, After: "c3RyaW5nOmZvbw"},
+			limit:            500,
+			prefix:           "my_amazing_prefix",
+			expectedCacheKey: "my_amazing_prefix_10834168755532815275.buf",
+		},
 	}
 
 	for _, tt := range tests {
@@ -4,7 +4,9 @@ import (
 	"context"
 	crand "crypto/rand"
 	"encoding/binary"
+	"encoding/json"
 	"math/rand"
+	"net/http/httptest"
 	"sync"
 	"testing"
 	"time"
@@ -12,6 +14,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/grafana/dskit/user"
 	"github.com/prometheus/client_golang/prometheus"
+	prom_model "github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -19,9 +22,11 @@ import (
 	"github.com/grafana/tempo/pkg/model"
 	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
 	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 const testTenantID = "fake"
@@ -55,7 +60,7 @@ func TestInstance(t *testing.T) {
 	require.NoError(t, err, "unexpected error cutting block")
 	require.NotEqual(t, blockID, uuid.Nil)
 
-	err = i.CompleteBlock(context.Background(), blockID)
+	_, err = i.CompleteBlock(context.Background(), blockID)
 	require.NoError(t, err, "unexpected error completing block")
 
 	block := i.GetBlockToBeFlushed(blockID)
@@ -105,7 +110,7 @@ func TestInstanceFind(t *testing.T) {
 
 	queryAll(t, i, ids, traces)
 
-	err = i.CompleteBlock(context.Background(), blockID)
+	_, err = i.CompleteBlock(context.Background(), blockID)
 	require.NoError(t, err)
 
 	queryAll(t, i, ids, traces)
@@ -194,7 +199,7 @@ func TestInstanceDoesNotRace(t *testing.T) {
 	concurrent(func() {
 		blockID, _ := i.CutBlockIfReady(0, 0, false)
 		if blockID != uuid.Nil {
-			err := i.CompleteBlock(context.Background(), blockID)
+			_, err := i.CompleteBlock(context.Background(), blockID)
 			require.NoError(t, err, "unexpected error completing block")
 			block := i.GetBlockToBeFlushed(blockID)
 			require.NotNil(t, block)
@@ -527,7 +532,7 @@ func TestInstanceCutBlockIfReady(t *testing.T) {
 			blockID, err := instance.CutBlockIfReady(tc.maxBlockLifetime, tc.maxBlockBytes, tc.immediate)
 			require.NoError(t, err)
 
-			err = instance.CompleteBlock(context.Background(), blockID)
+			_, err = instance.CompleteBlock(context.Background(), blockID)
 			if tc.expectedToCutBlock {
 				require.NoError(t, err, "unexpected error completing block")
 			}
@@ -856,7 +861,7 @@ func TestInstanceClearOldBlocks(t *testing.T) {
 				require.NoError(t, err)
 				require.NotEqual(t, blockID, uuid.Nil)
 
-				err = instance.CompleteBlock(ctx, blockID)
+				_, err = instance.CompleteBlock(ctx, blockID)
 				require.NoError(t, err)
 			}
 
@@ -872,6 +877,128 @@ func TestInstanceClearOldBlocks(t *testing.T) {
 	}
 }
 
+func TestInstanceRetentionClass(t *testing.T) {
+	ctx := context.Background()
+
+	ingester, instance := testInstance(t, func(_ *Config, o *overrides.Config) {
+		o.Defaults.Compaction.BlockRetention = prom_model.Duration(14 * 24 * time.Hour)
+		o.Defaults.Compaction.RetentionClassAttribute = "deployment.environment"
+		o.Defaults.Compaction.RetentionClasses = map[string]prom_model.Duration{
+			"prod": prom_model.Duration(30 * 24 * time.Hour),
+			"dev":  prom_model.Duration(3 * 24 * time.Hour),
+		}
+	})
+	t.Cleanup(func() {
+		ingester.StopAsync()
+		require.NoError(t, ingester.AwaitTerminated(ctx))
+	})
+
+	push := func(env string) {
+		id := test.ValidTraceID(nil)
+		batch := test.MakeBatch(1, id)
+		batch.Resource.Attributes = append(batch.Resource.Attributes, &v1_common.KeyValue{
+			Key:   "deployment.environment",
+			Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: env}},
+		})
+		response := instance.PushBytesRequest(ctx, makePushBytesRequest(id, batch))
+		errored, _, _ := CheckPushBytesError(response)
+		require.False(t, errored)
+	}
+
+	cutBlock := func() []string {
+		require.NoError(t, instance.CutCompleteTraces(0, 0, true))
+
+		blockID, err := instance.CutBlockIfReady(0, 0, true)
+		require.NoError(t, err)
+		ids, err := instance.CompleteBlock(ctx, blockID)
+		require.NoError(t, err)
+
+		// the block of the class with the longest retention keeps the ID of the wal block
+		require.Equal(t, blockID, ids[0])
+
+		var classes []string
+		for _, id := range ids {
+			b := instance.GetBlockToBeFlushed(id)
+			classes = append(classes, b.BlockMeta().RetentionClass)
+			require.Equal(t, int64(1), b.BlockMeta().TotalObjects)
+		}
+		return classes
+	}
+
+	push("dev")
+	require.Equal(t, []string{"dev"}, cutBlock())
+
+	// traces of different classes are completed into separate blocks
+	push("dev")
+	push("prod")
+	require.Equal(t, []string{"prod", "dev"}, cutBlock())
+
+	// traces without a class use the tenant retention
+	push("dev")
+	push("staging")
+	require.Equal(t, []string{"", "dev"}, cutBlock())
+}
+
+func TestInstanceAttributeCardinality(t *testing.T) {
+	ctx := context.Background()
+
+	ingester, instance := testInstance(t, func(_ *Config, o *overrides.Config) {
+		o.Defaults.Storage.AttributeCardinality = common.AttributeCardinalityPolicy{
+			MaxValues: 1,
+			Action:    common.AttributeCardinalityActionDrop,
+		}
+	})
+	t.Cleanup(func() {
+		ingester.StopAsync()
+		require.NoError(t, ingester.AwaitTerminated(ctx))
+	})
+
+	push := func(userID string) []byte {
+		id := test.ValidTraceID(nil)
+		batch := test.MakeBatch(1, id)
+		batch.Resource.Attributes = append(batch.Resource.Attributes, &v1_common.KeyValue{
+			Key:   "user.id",
+			Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: userID}},
+		})
+		response := instance.PushBytesRequest(ctx, makePushBytesRequest(id, batch))
+		errored, _, _ := CheckPushBytesError(response)
+		require.False(t, errored)
+		return id
+	}
+
+	hasUserID := func(id []byte) bool {
+		resp, err := instance.FindTraceByID(ctx, id, false)
+		require.NoError(t, err)
+		require.NotNil(t, resp.Trace)
+		for _, a := range resp.Trace.ResourceSpans[0].Resource.Attributes {
+			if a.Key == "user.id" {
+				return true
+			}
+		}
+		return false
+	}
+
+	first := push("a")
+	require.NoError(t, instance.CutCompleteTraces(0, 0, true))
+	second := push("b")
+	require.NoError(t, instance.CutCompleteTraces(0, 0, true))
+
+	require.True(t, hasUserID(first))
+	require.False(t, hasUserID(second))
+
+	rec := httptest.NewRecorder()
+	ingester.AttributeCardinalityHandler(rec, httptest.NewRequest("GET", "/ingester/attribute-cardinality?tenant="+testTenantID, nil))
+	require.Equal(t, 200, rec.Code)
+
+	var limited map[string][]common.LimitedAttribute
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &limited))
+	require.Contains(t, limited[testTenantID], common.LimitedAttribute{Scope: "resource", Key: "user.id", LimitedValues: 1})
+
+	rec = httptest.NewRecorder()
+	ingester.AttributeCardinalityHandler(rec, httptest.NewRequest("GET", "/ingester/attribute-cardinality?tenant=unknown", nil))
+	require.Equal(t, 404, rec.Code)
+}
+
 func defaultInstance(t testing.TB) (*instance, *Ingester) {
 	instance, ingester, _ := defaultInstanceAndTmpDir(t)
 	return instance, ingester
@@ -958,7 +1085,7 @@ func BenchmarkInstanceFindTraceByIDFromCompleteBlock(b *testing.B) {
 	require.NoError(b, err)
 	id, err := instance.CutBlockIfReady(0, 0, true)
 	require.NoError(b, err)
-	err = instance.CompleteBlock(context.Background(), id)
+	_, err = instance.CompleteBlock(context.Background(), id)
 	require.NoError(b, err)
 
 	require.Equal(b, 1, len(instance.completeBlocks))
@@ -996,7 +1123,7 @@ func benchmarkInstanceSearch(b testing.TB, writes int, reads int) {
 	// force the traces to be in a complete block
 	id, err := instance.CutBlockIfReady(0, 0, true)
 	require.NoError(b, err)
-	err = instance.CompleteBlock(context.Background(), id)
+	_, err = instance.CompleteBlock(context.Background(), id)
 	require.NoError(b, err)
 
 	require.Equal(b, 1, len(instance.completeBlocks))
@@ -1102,7 +1229,7 @@ func BenchmarkInstanceContention(t *testing.B) {
 	go concurrent(func() {
 		blockID, _ := i.CutBlockIfReady(0, 0, false)
 		if blockID != uuid.Nil {
-			err := i.CompleteBlock(context.Background(), blockID)
+			_, err := i.CompleteBlock(context.Background(), blockID)
 			require.NoError(t, err, "unexpected error completing block")
 			err = i.ClearCompletingBlock(blockID)
 			require.NoError(t, err, "unexpected error clearing wal block")
@@ -60,6 +60,12 @@ func (c *LocalBlock) FindTraceByID(ctx context.Context, id common.ID, opts commo
 	return c.BackendBlock.FindTraceByID(ctx, id, opts)
 }
 
+func (c *LocalBlock) FindTraceByIDs(ctx context.Context, ids []common.ID, opts common.SearchOptions) ([]*tempopb.Trace, *tempopb.TraceByIDMetrics, error) {
+	ctx, span := tracer.Start(ctx, "LocalBlock.FindTraceByIDs")
+	defer span.End()
+	return c.BackendBlock.FindTraceByIDs(ctx, ids, opts)
+}
+
 func (c *LocalBlock) Search(ctx context.Context, req *tempopb.SearchRequest, opts common.SearchOptions) (*tempopb.SearchResponse, error) {
 	ctx, span := tracer.Start(ctx, "LocalBlock.Search")
 	defer span.End()
@@ -1,15 +1,24 @@
 package ingester
 
 import (
+	"time"
+
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 type ingesterOverrides interface {
 	registry.Overrides
 
 	DedicatedColumns(userID string) backend.DedicatedColumns
+	StorageAttributePolicy(userID string) common.AttributePolicy
+	StorageAttributeCardinality(userID string) common.AttributeCardinalityPolicy
+	BlockRetention(userID string) time.Duration
+	BlockRetentionClasses(userID string) (string, map[string]time.Duration)
+	StorageBlockEncoding(userID string) common.BlockEncoding
+	MaxConcurrentTailRequests(userID string) int
 }
 
 var _ ingesterOverrides = (overrides.Interface)(nil)
@@ -0,0 +1,116 @@
+package ingester
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// retentionClassWALBlock is the part of a WAL block with the traces of one retention class.
+type retentionClassWALBlock struct {
+	common.WALBlock
+	meta    *backend.BlockMeta
+	classes *tempodb.RetentionClasses
+}
+
+func (b *retentionClassWALBlock) BlockMeta() *backend.BlockMeta {
+	return b.meta
+}
+
+func (b *retentionClassWALBlock) Iterator() (common.Iterator, error) {
+	iter, err := b.WALBlock.Iterator()
+	if err != nil {
+		return nil, err
+	}
+
+	return &retentionClassIterator{Iterator: iter, classes: b.classes, class: b.meta.RetentionClass}, nil
+}
+
+// retentionClassIterator skips the traces of other retention classes.
+type retentionClassIterator struct {
+	common.Iterator
+	classes *tempodb.RetentionClasses
+	class   string
+}
+
+func (i *retentionClassIterator) Next(ctx context.Context) (common.ID, *tempopb.Trace, error) {
+	for {
+		id, tr, err := i.Iterator.Next(ctx)
+		if err != nil || tr == nil {
+			return id, tr, err
+		}
+
+		if i.classes.TraceClass(tr) == i.class {
+			return id, tr, nil
+		}
+	}
+}
+
+// splitByRetentionClass returns a block per retention class of the traces of the WAL block, so every completed
+// block is removed after the retention of its own traces. The block of the class with the longest retention keeps
+// the ID of the WAL block and is returned first, the IDs of the others are derived from it and the class so they are
+// the same if the completion is retried. The block is returned as is if all of its traces have the same class.
+func splitByRetentionClass(ctx context.Context, block common.WALBlock, classes *tempodb.RetentionClasses) ([]common.WALBlock, error) {
+	if !classes.Enabled() {
+		return []common.WALBlock{block}, nil
+	}
+
+	iter, err := block.Iterator()
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	seen := map[string]struct{}{}
+	for {
+		_, tr, err := iter.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if tr == nil {
+			break
+		}
+		seen[classes.TraceClass(tr)] = struct{}{}
+	}
+
+	if len(seen) <= 1 {
+		return []common.WALBlock{block}, nil
+	}
+
+	names := make([]string, 0, len(seen))
+	for class := range seen {
+		names = append(names, class)
+	}
+	sort.Strings(names)
+
+	// the block that keeps the ID of the wal block goes first
+	longest := classes.BlockClass(names...)
+	sort.SliceStable(names, func(i, j int) bool {
+		return names[i] == longest && names[j] != longest
+	})
+
+	walMeta := block.BlockMeta()
+
+	blocks := make([]common.WALBlock, 0, len(names))
+	for _, class := range names {
+		meta := *walMeta
+		meta.RetentionClass = class
+		if class != longest {
+			meta.BlockID = backend.UUID(uuid.NewSHA1((uuid.UUID)(walMeta.BlockID), []byte(class)))
+		}
+
+		blocks = append(blocks, &retentionClassWALBlock{
+			WALBlock: block,
+			meta:     &meta,
+			classes:  classes,
+		})
+	}
+
+	return blocks, nil
+}
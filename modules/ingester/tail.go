@@ -0,0 +1,155 @@
+package ingester
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gogo/status"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc/codes"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// tailBufferSize is the number of matching traces buffered for a live tail, matches are dropped while it's full.
+const tailBufferSize = 100
+
+var (
+	metricLiveTails = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "ingester_live_tails",
+		Help:      "The current number of live tails per tenant.",
+	}, []string{"tenant"})
+	metricLiveTailDroppedTraces = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_live_tail_dropped_traces_total",
+		Help:      "The total number of matching traces dropped because a live tail didn't receive them fast enough.",
+	}, []string{"tenant"})
+)
+
+type liveTail struct {
+	filter          *traceql.SpanFilter
+	spansPerSpanSet int
+	results         chan *tempopb.TraceSearchMetadata
+}
+
+// liveTails are the live tails of a tenant. The traces written to the head block are matched against their filters.
+// The zero value has no tails.
+type liveTails struct {
+	mtx   sync.RWMutex
+	tails map[*liveTail]struct{}
+	// count is read on each write to the head block to skip decoding traces when there are no tails
+	count atomic.Int32
+}
+
+func (t *liveTails) add(filter *traceql.SpanFilter, spansPerSpanSet, limit int) (*liveTail, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if len(t.tails) >= limit {
+		return nil, fmt.Errorf("max concurrent tail requests (%d) exceeded", limit)
+	}
+	if t.tails == nil {
+		t.tails = map[*liveTail]struct{}{}
+	}
+
+	tail := &liveTail{
+		filter:          filter,
+		spansPerSpanSet: spansPerSpanSet,
+		results:         make(chan *tempopb.TraceSearchMetadata, tailBufferSize),
+	}
+	t.tails[tail] = struct{}{}
+	t.count.Store(int32(len(t.tails)))
+	return tail, nil
+}
+
+func (t *liveTails) remove(tail *liveTail) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	delete(t.tails, tail)
+	t.count.Store(int32(len(t.tails)))
+}
+
+func (t *liveTails) active() bool {
+	return t.count.Load() > 0
+}
+
+// match sends the spans of the trace matching the filter of each tail. It never blocks, the matches of tails whose
+// buffer is full are dropped and counted.
+func (t *liveTails) match(tenant string, id common.ID, tr *tempopb.Trace) {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	for tail := range t.tails {
+		md, err := tail.filter.Match(id, tr, tail.spansPerSpanSet)
+		if err != nil || md == nil {
+			continue
+		}
+
+		select {
+		case tail.results <- md:
+		default:
+			metricLiveTailDroppedTraces.WithLabelValues(tenant).Inc()
+		}
+	}
+}
+
+// Tail streams the spans of the tenant matching the spanset filter of the query as they are written to the head block,
+// until the stream is closed. The number of tails of a tenant open at once is limited by max_concurrent_tail_requests.
+func (i *Ingester) Tail(req *tempopb.SearchRequest, srv tempopb.Querier_TailServer) error {
+	ctx := srv.Context()
+	instanceID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter, err := traceql.NewSpanFilter(req.Query)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// the instance is created if the tenant has no traces yet, to receive the first ones
+	inst, err := i.getOrCreateInstance(instanceID)
+	if err != nil {
+		return err
+	}
+
+	tail, err := inst.tails.add(filter, int(req.SpansPerSpanSet), i.overrides.MaxConcurrentTailRequests(instanceID))
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	metricLiveTails.WithLabelValues(instanceID).Inc()
+	defer func() {
+		inst.tails.remove(tail)
+		metricLiveTails.WithLabelValues(instanceID).Dec()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case md := <-tail.results:
+			// the buffered matches are sent at once
+			resp := &tempopb.SearchResponse{Traces: []*tempopb.TraceSearchMetadata{md}}
+		drain:
+			for len(resp.Traces) < tailBufferSize {
+				select {
+				case md := <-tail.results:
+					resp.Traces = append(resp.Traces, md)
+				default:
+					break drain
+				}
+			}
+
+			if err := srv.Send(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
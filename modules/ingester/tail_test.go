@@ -0,0 +1,104 @@
+package ingester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gogo/status"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+type mockTailServer struct {
+	grpc.ServerStream
+	ctx   context.Context
+	resps chan *tempopb.SearchResponse
+}
+
+func (s *mockTailServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *mockTailServer) Send(resp *tempopb.SearchResponse) error {
+	s.resps <- resp
+	return nil
+}
+
+func TestIngesterTail(t *testing.T) {
+	o := defaultOverridesConfig()
+	o.Defaults.Read.MaxConcurrentTailRequests = 1
+	ingester := defaultIngesterWithOverrides(t, t.TempDir(), o)
+
+	ctx, cancel := context.WithCancel(user.InjectOrgID(context.Background(), testTenantID))
+	defer cancel()
+
+	srv := &mockTailServer{ctx: ctx, resps: make(chan *tempopb.SearchResponse, 1)}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- ingester.Tail(&tempopb.SearchRequest{Query: `{ resource.service.name = "foo" }`}, srv)
+	}()
+
+	inst, err := ingester.getOrCreateInstance(testTenantID)
+	require.NoError(t, err)
+	require.Eventually(t, inst.tails.active, time.Second, 10*time.Millisecond)
+
+	// the tenant has a single tail
+	err = ingester.Tail(&tempopb.SearchRequest{Query: `{ }`}, &mockTailServer{ctx: ctx})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	err = ingester.Tail(&tempopb.SearchRequest{Query: `{ } | count() > 1`}, &mockTailServer{ctx: ctx})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// only the traces written to the head block are tailed
+	fooID := test.ValidTraceID(nil)
+	barID := test.ValidTraceID(nil)
+	requireNoPushErrors(t, inst, makePushBytesRequest(barID, makeRootBatch(barID, "bar", 1)))
+	requireNoPushErrors(t, inst, makePushBytesRequest(fooID, makeRootBatch(fooID, "foo", 1)))
+	require.Empty(t, srv.resps)
+	require.NoError(t, inst.CutCompleteTraces(0, 0, true))
+
+	select {
+	case resp := <-srv.resps:
+		require.Len(t, resp.Traces, 1)
+		require.Equal(t, util.TraceIDToHexString(fooID), resp.Traces[0].TraceID)
+		require.Equal(t, "foo", resp.Traces[0].RootServiceName)
+	case <-time.After(time.Second):
+		t.Fatal("no spans tailed")
+	}
+
+	cancel()
+	require.NoError(t, <-errs)
+	require.False(t, inst.tails.active())
+}
+
+func TestLiveTailsDropped(t *testing.T) {
+	filter, err := traceql.NewSpanFilter(`{ }`)
+	require.NoError(t, err)
+
+	var tails liveTails
+	tail, err := tails.add(filter, 0, 1)
+	require.NoError(t, err)
+
+	traceID := test.ValidTraceID(nil)
+	tr := test.MakeTrace(1, traceID)
+	dropped := testutil.ToFloat64(metricLiveTailDroppedTraces.WithLabelValues(testTenantID))
+
+	// matches are dropped instead of blocking the writes while the buffer is full
+	for range tailBufferSize + 2 {
+		tails.match(testTenantID, traceID, tr)
+	}
+	require.Len(t, tail.results, tailBufferSize)
+	require.Equal(t, dropped+2, testutil.ToFloat64(metricLiveTailDroppedTraces.WithLabelValues(testTenantID)))
+
+	tails.remove(tail)
+	require.False(t, tails.active())
+}
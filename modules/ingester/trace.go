@@ -16,6 +16,12 @@ type liveTrace struct {
 	decoder    model.SegmentDecoder
 	lastAppend time.Time
 	createdAt  time.Time
+	// cutAt is the last time the trace was written to a block that has been cut, zero if it isn't in one. Spans
+	// pushed to the trace are late.
+	cutAt time.Time
+
+	// roots are the root spans pushed so far, only tracked if trace ID conflicts are detected
+	roots []traceRoot
 }
 
 func newTrace(traceID []byte) *liveTrace {
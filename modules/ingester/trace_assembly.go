@@ -0,0 +1,120 @@
+package ingester
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/model"
+)
+
+const (
+	traceCutReasonIdle = "idle"
+	traceCutReasonLive = "live"
+)
+
+var (
+	metricTraceAssemblyDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tempo",
+		Name:      "ingester_trace_assembly_duration_seconds",
+		Help:      "The time from the first span of a live trace received to the trace cut, by the period that cut it.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"tenant", "reason"})
+	metricTraceLifetime = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tempo",
+		Name:      "ingester_trace_lifetime_seconds",
+		Help:      "The time between the first and the last span of a live trace received.",
+		Buckets:   prometheus.ExponentialBuckets(0.25, 2, 14),
+	}, []string{"tenant"})
+	metricLateSpansTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_late_spans_total",
+		Help:      "The total number of spans received for traces that are in a block cut from the head block.",
+	}, []string{"tenant"})
+	metricLateSpanLag = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tempo",
+		Name:      "ingester_late_span_lag_seconds",
+		Help:      "The time between a trace cut to the head block and segments of it received after the block was cut.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+	}, []string{"tenant"})
+)
+
+// TraceAssemblyConfig configures the metrics of how traces are assembled from the spans received.
+type TraceAssemblyConfig struct {
+	// LateSpans tracks the traces of the blocks cut from the head block to count the spans received for them.
+	LateSpans bool `yaml:"late_spans"`
+}
+
+// lateSpanTracker remembers the traces written to the head block and to the blocks cut from it that are still in
+// the ingester, to find the spans received for traces that are already in a cut block.
+type lateSpanTracker struct {
+	mtx sync.Mutex
+	// head maps the traces written to the head block to the time they were last written
+	head   map[uint64]time.Time
+	blocks map[uuid.UUID]map[uint64]time.Time
+}
+
+func newLateSpanTracker() *lateSpanTracker {
+	return &lateSpanTracker{
+		head:   map[uint64]time.Time{},
+		blocks: map[uuid.UUID]map[uint64]time.Time{},
+	}
+}
+
+// Written records a trace written to the head block.
+func (t *lateSpanTracker) Written(fp uint64, now time.Time) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.head[fp] = now
+}
+
+// BlockCut moves the traces of the head block to the block cut from it.
+func (t *lateSpanTracker) BlockCut(blockID uuid.UUID) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if len(t.head) > 0 {
+		t.blocks[blockID] = t.head
+		t.head = map[uint64]time.Time{}
+	}
+}
+
+// BlockCleared forgets the traces of a block cleared from the ingester.
+func (t *lateSpanTracker) BlockCleared(blockID uuid.UUID) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	delete(t.blocks, blockID)
+}
+
+// CutAt returns the last time a trace was written to a block that has been cut, and false if it isn't in any.
+func (t *lateSpanTracker) CutAt(fp uint64) (time.Time, bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	var last time.Time
+	for _, traces := range t.blocks {
+		if written, ok := traces[fp]; ok && written.After(last) {
+			last = written
+		}
+	}
+	return last, !last.IsZero()
+}
+
+// segmentSpanCount returns the number of spans of a segment. Errors are ignored, the segment is pushed and fails
+// later.
+func segmentSpanCount(decoder model.SegmentDecoder, segment []byte) int {
+	tr, err := decoder.PrepareForRead([][]byte{segment})
+	if err != nil {
+		return 0
+	}
+
+	count := 0
+	for _, rs := range tr.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			count += len(ss.Spans)
+		}
+	}
+	return count
+}
@@ -0,0 +1,82 @@
+package ingester
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestInstanceTraceAssemblyMetrics(t *testing.T) {
+	i, _ := defaultInstance(t)
+
+	lifetime := histogramCount(t, metricTraceLifetime.WithLabelValues(testTenantID))
+	idle := histogramCount(t, metricTraceAssemblyDuration.WithLabelValues(testTenantID, traceCutReasonIdle))
+	live := histogramCount(t, metricTraceAssemblyDuration.WithLabelValues(testTenantID, traceCutReasonLive))
+
+	// traces cut immediately aren't measured
+	traceID := test.ValidTraceID(nil)
+	requireNoPushErrors(t, i, makePushBytesRequest(traceID, makeRootBatch(traceID, "svc", 1)))
+	require.NoError(t, i.CutCompleteTraces(time.Hour, time.Hour, true))
+	require.Equal(t, lifetime, histogramCount(t, metricTraceLifetime.WithLabelValues(testTenantID)))
+
+	requireNoPushErrors(t, i, makePushBytesRequest(traceID, makeRootBatch(traceID, "svc", 1)))
+	require.NoError(t, i.CutCompleteTraces(0, time.Hour, false))
+	requireNoPushErrors(t, i, makePushBytesRequest(traceID, makeRootBatch(traceID, "svc", 1)))
+	require.NoError(t, i.CutCompleteTraces(time.Hour, 0, false))
+
+	require.Equal(t, lifetime+2, histogramCount(t, metricTraceLifetime.WithLabelValues(testTenantID)))
+	require.Equal(t, idle+1, histogramCount(t, metricTraceAssemblyDuration.WithLabelValues(testTenantID, traceCutReasonIdle)))
+	require.Equal(t, live+1, histogramCount(t, metricTraceAssemblyDuration.WithLabelValues(testTenantID, traceCutReasonLive)))
+}
+
+func TestInstanceLateSpans(t *testing.T) {
+	i, _ := defaultInstance(t)
+	i.lateSpans = newLateSpanTracker()
+
+	late := testutil.ToFloat64(metricLateSpansTotal.WithLabelValues(testTenantID))
+	lag := histogramCount(t, metricLateSpanLag.WithLabelValues(testTenantID))
+
+	traceID := test.ValidTraceID(nil)
+	batch := test.MakeBatch(3, traceID)
+	requireNoPushErrors(t, i, makePushBytesRequest(traceID, batch))
+	require.NoError(t, i.CutCompleteTraces(0, 0, true))
+
+	// spans of a trace cut to the head block aren't late until the block is cut
+	requireNoPushErrors(t, i, makePushBytesRequest(traceID, batch))
+	require.NoError(t, i.CutCompleteTraces(0, 0, true))
+	require.Equal(t, late, testutil.ToFloat64(metricLateSpansTotal.WithLabelValues(testTenantID)))
+
+	blockID, err := i.CutBlockIfReady(0, 0, true)
+	require.NoError(t, err)
+	require.NotEqual(t, uuid.Nil, blockID)
+
+	requireNoPushErrors(t, i, makePushBytesRequest(traceID, batch))
+	requireNoPushErrors(t, i, makePushBytesRequest(traceID, batch))
+	require.Equal(t, late+6, testutil.ToFloat64(metricLateSpansTotal.WithLabelValues(testTenantID)))
+	require.Equal(t, lag+2, histogramCount(t, metricLateSpanLag.WithLabelValues(testTenantID)))
+
+	// traces of other blocks and of cleared blocks aren't late
+	otherID := test.ValidTraceID(nil)
+	requireNoPushErrors(t, i, makePushBytesRequest(otherID, test.MakeBatch(1, otherID)))
+	require.Equal(t, late+6, testutil.ToFloat64(metricLateSpansTotal.WithLabelValues(testTenantID)))
+
+	_, ok := i.lateSpans.CutAt(util.HashForTraceID(traceID))
+	require.True(t, ok)
+	i.lateSpans.BlockCleared(blockID)
+	_, ok = i.lateSpans.CutAt(util.HashForTraceID(traceID))
+	require.False(t, ok)
+}
+
+func histogramCount(t *testing.T, o prometheus.Observer) uint64 {
+	m := &dto.Metric{}
+	require.NoError(t, o.(prometheus.Metric).Write(m))
+	return m.GetHistogram().GetSampleCount()
+}
@@ -0,0 +1,151 @@
+package ingester
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/model"
+)
+
+var metricTraceIDConflicts = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "ingester_trace_id_conflicts_total",
+	Help:      "The total number of segments pushed for a live trace that look like a distinct trace, by the action taken.",
+}, []string{"tenant", "action"})
+
+// TraceIDConflictsConfig configures the detection of distinct traces pushed with the same trace ID.
+type TraceIDConflictsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Split cuts the live trace when a conflicting segment is pushed instead of combining them.
+	Split bool `yaml:"split"`
+}
+
+// TraceConflict is a segment pushed for a live trace with a root span that isn't a root span of the live trace. Two
+// root spans usually mean that two distinct traces share the trace ID: an ID collision or a misbehaving SDK.
+type TraceConflict struct {
+	TenantID string
+	TraceID  []byte
+	// RootServices are the services of the root spans of the live trace, SegmentRootServices the ones of the
+	// root spans of the segment.
+	RootServices        []string
+	SegmentRootServices []string
+}
+
+// TraceConflictAction is what the ingester does with a trace ID conflict.
+type TraceConflictAction int
+
+const (
+	// TraceConflictCombine combines the segment with the live trace, as if there was no conflict.
+	TraceConflictCombine TraceConflictAction = iota
+	// TraceConflictSplit cuts the live trace and starts a new live trace with the segment, so they are written to
+	// separate WAL blocks.
+	TraceConflictSplit
+)
+
+func (a TraceConflictAction) String() string {
+	switch a {
+	case TraceConflictSplit:
+		return "split"
+	default:
+		return "combine"
+	}
+}
+
+// TraceConflictResolver decides what to do with trace ID conflicts. Resolve is called with the live traces of the
+// tenant locked and must be fast.
+type TraceConflictResolver interface {
+	Resolve(c TraceConflict) TraceConflictAction
+}
+
+// TraceConflictResolverFunc is a TraceConflictResolver function.
+type TraceConflictResolverFunc func(c TraceConflict) TraceConflictAction
+
+// Resolve implements TraceConflictResolver
+func (f TraceConflictResolverFunc) Resolve(c TraceConflict) TraceConflictAction {
+	return f(c)
+}
+
+// newTraceConflictResolver returns the resolver of the config, or nil if conflicts aren't detected.
+func newTraceConflictResolver(cfg TraceIDConflictsConfig) TraceConflictResolver {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	action := TraceConflictCombine
+	if cfg.Split {
+		action = TraceConflictSplit
+	}
+	return TraceConflictResolverFunc(func(TraceConflict) TraceConflictAction { return action })
+}
+
+// traceRoot is a root span of a live trace
+type traceRoot struct {
+	spanID  []byte
+	service string
+}
+
+// segmentRoots returns the root spans of a segment. Errors are ignored, the segment is pushed and fails later.
+func segmentRoots(decoder model.SegmentDecoder, segment []byte) []traceRoot {
+	tr, err := decoder.PrepareForRead([][]byte{segment})
+	if err != nil {
+		return nil
+	}
+
+	var roots []traceRoot
+	for _, rs := range tr.ResourceSpans {
+		service := ""
+		if rs.Resource != nil {
+			for _, attr := range rs.Resource.Attributes {
+				if attr.Key == "service.name" {
+					service = attr.Value.GetStringValue()
+					break
+				}
+			}
+		}
+
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				if len(s.ParentSpanId) == 0 {
+					roots = append(roots, traceRoot{spanID: s.SpanId, service: service})
+				}
+			}
+		}
+	}
+	return roots
+}
+
+// conflicts returns true if a segment with the roots has a root span the trace doesn't have, while the trace already
+// has root spans. The same root pushed again, for example by a retry, isn't a conflict.
+func (t *liveTrace) conflicts(roots []traceRoot) bool {
+	if len(t.roots) == 0 {
+		return false
+	}
+
+	for _, r := range roots {
+		if !slices.ContainsFunc(t.roots, func(tr traceRoot) bool { return bytes.Equal(tr.spanID, r.spanID) }) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *liveTrace) addRoots(roots []traceRoot) {
+	for _, r := range roots {
+		if !slices.ContainsFunc(t.roots, func(tr traceRoot) bool { return bytes.Equal(tr.spanID, r.spanID) }) {
+			t.roots = append(t.roots, r)
+		}
+	}
+}
+
+func rootServices(roots []traceRoot) []string {
+	services := make([]string, 0, len(roots))
+	for _, r := range roots {
+		if !slices.Contains(services, r.service) {
+			services = append(services, r.service)
+		}
+	}
+	return services
+}
@@ -0,0 +1,159 @@
+package ingester
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func makeRootBatch(traceID []byte, service string, spanID byte) *v1_trace.ResourceSpans {
+	return &v1_trace.ResourceSpans{
+		Resource: &v1_resource.Resource{
+			Attributes: []*v1_common.KeyValue{test.MakeAttribute("service.name", service)},
+		},
+		ScopeSpans: []*v1_trace.ScopeSpans{{
+			Spans: []*v1_trace.Span{{
+				TraceId:           traceID,
+				SpanId:            []byte{0, 0, 0, 0, 0, 0, 0, spanID},
+				Name:              "root",
+				StartTimeUnixNano: 1000,
+				EndTimeUnixNano:   2000,
+			}},
+		}},
+	}
+}
+
+func TestInstanceTraceIDConflicts(t *testing.T) {
+	tcs := []struct {
+		name            string
+		split           bool
+		expectedCreated float64
+	}{
+		{name: "combine", split: false, expectedCreated: 1},
+		{name: "split", split: true, expectedCreated: 2},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			i, _ := defaultInstance(t)
+
+			var conflicts []TraceConflict
+			action := newTraceConflictResolver(TraceIDConflictsConfig{Enabled: true, Split: tc.split})
+			i.conflictResolver = TraceConflictResolverFunc(func(c TraceConflict) TraceConflictAction {
+				conflicts = append(conflicts, c)
+				return action.Resolve(c)
+			})
+
+			traceID := test.ValidTraceID(nil)
+
+			// the same root pushed twice, as by a retry, isn't a conflict
+			requireNoPushErrors(t, i, makePushBytesRequest(traceID, makeRootBatch(traceID, "svc-a", 1)))
+			requireNoPushErrors(t, i, makePushBytesRequest(traceID, makeRootBatch(traceID, "svc-a", 1)))
+			require.Empty(t, conflicts)
+
+			requireNoPushErrors(t, i, makePushBytesRequest(traceID, makeRootBatch(traceID, "svc-b", 2)))
+			require.Len(t, conflicts, 1)
+			require.Equal(t, testTenantID, conflicts[0].TenantID)
+			require.Equal(t, []string{"svc-a"}, conflicts[0].RootServices)
+			require.Equal(t, []string{"svc-b"}, conflicts[0].SegmentRootServices)
+
+			require.Len(t, i.traces, 1)
+			if tc.split {
+				require.Len(t, i.splitTraces, 1)
+			} else {
+				require.Empty(t, i.splitTraces)
+			}
+
+			created := testutil.ToFloat64(i.tracesCreatedTotal)
+			require.NoError(t, i.CutCompleteTraces(0, 0, true))
+			require.Equal(t, tc.expectedCreated, testutil.ToFloat64(i.tracesCreatedTotal)-created)
+			require.Empty(t, i.splitTraces)
+			require.Zero(t, i.traceSizeBytes)
+
+			// the split trace is in a block of its own, so it isn't combined with the live trace on completion
+			if tc.split {
+				require.Len(t, i.SplitTraceBlocks(), 1)
+				require.Len(t, i.completingBlocks, 1)
+				requireBlockTraceBatches(t, i.completingBlocks[0], 1)
+			} else {
+				require.Empty(t, i.SplitTraceBlocks())
+				require.Empty(t, i.completingBlocks)
+			}
+
+			// the split objects are still found as a single trace
+			res, err := i.FindTraceByID(context.Background(), traceID, false)
+			require.NoError(t, err)
+			require.NotNil(t, res.Trace)
+			require.Len(t, res.Trace.ResourceSpans, 2)
+		})
+	}
+}
+
+func TestInstanceTraceIDConflictsMultipleSplits(t *testing.T) {
+	i, _ := defaultInstance(t)
+	i.conflictResolver = newTraceConflictResolver(TraceIDConflictsConfig{Enabled: true, Split: true})
+
+	traceID := test.ValidTraceID(nil)
+	for j := byte(1); j <= 3; j++ {
+		requireNoPushErrors(t, i, makePushBytesRequest(traceID, makeRootBatch(traceID, "svc", j)))
+	}
+	require.Len(t, i.splitTraces, 2)
+
+	// each split trace is written to a block of its own
+	created := testutil.ToFloat64(i.tracesCreatedTotal)
+	require.NoError(t, i.CutCompleteTraces(0, 0, true))
+	require.Equal(t, float64(3), testutil.ToFloat64(i.tracesCreatedTotal)-created)
+	require.Len(t, i.SplitTraceBlocks(), 2)
+	require.Len(t, i.completingBlocks, 2)
+	for _, b := range i.completingBlocks {
+		requireBlockTraceBatches(t, b, 1)
+	}
+
+	blockID, err := i.CutBlockIfReady(0, 0, true)
+	require.NoError(t, err)
+	require.NotEqual(t, uuid.Nil, blockID)
+	require.Len(t, i.completingBlocks, 3)
+	requireBlockTraceBatches(t, i.completingBlocks[2], 1)
+
+	res, err := i.FindTraceByID(context.Background(), traceID, false)
+	require.NoError(t, err)
+	require.NotNil(t, res.Trace)
+	require.Len(t, res.Trace.ResourceSpans, 3)
+}
+
+func TestNewTraceConflictResolver(t *testing.T) {
+	require.Nil(t, newTraceConflictResolver(TraceIDConflictsConfig{Split: true}))
+	require.Equal(t, TraceConflictCombine, newTraceConflictResolver(TraceIDConflictsConfig{Enabled: true}).Resolve(TraceConflict{}))
+	require.Equal(t, TraceConflictSplit, newTraceConflictResolver(TraceIDConflictsConfig{Enabled: true, Split: true}).Resolve(TraceConflict{}))
+}
+
+// requireBlockTraceBatches requires the block to hold a single trace with the number of batches once it's completed.
+func requireBlockTraceBatches(t *testing.T, b common.WALBlock, batches int) {
+	iter, err := b.Iterator()
+	require.NoError(t, err)
+	defer iter.Close()
+
+	_, tr, err := iter.Next(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, tr)
+	require.Len(t, tr.ResourceSpans, batches)
+
+	_, tr, err = iter.Next(context.Background())
+	require.NoError(t, err)
+	require.Nil(t, tr)
+}
+
+func requireNoPushErrors(t *testing.T, i *instance, req *tempopb.PushBytesRequest) {
+	errored, _, _ := CheckPushBytesError(i.PushBytesRequest(context.Background(), req))
+	require.False(t, errored)
+}
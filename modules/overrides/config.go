@@ -8,6 +8,7 @@ import (
 
 	"github.com/grafana/tempo/pkg/util/listtomap"
 	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -37,6 +38,9 @@ const (
 	// ErrorPrefixRateLimited is used to flag batches that have exceeded the spans/second of the tenant
 	ErrorPrefixRateLimited = "RATE_LIMITED"
 
+	// DefaultArchiveTier is the storage tier blocks are archived to if the tenant doesn't set one
+	DefaultArchiveTier = "Archive"
+
 	// metrics
 	MetricMaxLocalTracesPerUser           = "max_local_traces_per_user"
 	MetricMaxGlobalTracesPerUser          = "max_global_traces_per_user"
@@ -71,6 +75,9 @@ type IngestionOverrides struct {
 	RateStrategy   string `yaml:"rate_strategy,omitempty" json:"rate_strategy,omitempty"`
 	RateLimitBytes int    `yaml:"rate_limit_bytes,omitempty" json:"rate_limit_bytes,omitempty"`
 	BurstSizeBytes int    `yaml:"burst_size_bytes,omitempty" json:"burst_size_bytes,omitempty"`
+	// BurstRateLimitBytes is the rate of the burst class, allowed above RateLimitBytes for at most BurstDuration.
+	BurstRateLimitBytes int            `yaml:"burst_rate_limit_bytes,omitempty" json:"burst_rate_limit_bytes,omitempty"`
+	BurstDuration       model.Duration `yaml:"burst_duration,omitempty" json:"burst_duration,omitempty"`
 
 	// Ingester enforced limits.
 	MaxLocalTracesPerUser  int `yaml:"max_traces_per_user,omitempty" json:"max_traces_per_user,omitempty"`
@@ -79,6 +86,16 @@ type IngestionOverrides struct {
 	TenantShardSize   int            `yaml:"tenant_shard_size,omitempty" json:"tenant_shard_size,omitempty"`
 	MaxAttributeBytes int            `yaml:"max_attribute_bytes,omitempty" json:"max_attribute_bytes,omitempty"`
 	ArtificialDelay   *time.Duration `yaml:"artificial_delay,omitempty" json:"artificial_delay,omitempty"`
+
+	// Trace ID hash scheme used to derive the ring token of a trace. The previous scheme is also read by the
+	// queriers while migrating from one scheme to another.
+	TraceIDHashScheme         string `yaml:"trace_id_hash_scheme,omitempty" json:"trace_id_hash_scheme,omitempty"`
+	PreviousTraceIDHashScheme string `yaml:"previous_trace_id_hash_scheme,omitempty" json:"previous_trace_id_hash_scheme,omitempty"`
+
+	// Keys of the resource, scope, span, event and link attributes that are removed by the distributor before
+	// traces are written. With AttributeDenylistMode "hash" their values are replaced with a hash instead.
+	AttributeDenylist     []string `yaml:"attribute_denylist,omitempty" json:"attribute_denylist,omitempty"`
+	AttributeDenylistMode string   `yaml:"attribute_denylist_mode,omitempty" json:"attribute_denylist_mode,omitempty"`
 }
 
 type ForwarderOverrides struct {
@@ -160,12 +177,22 @@ type ReadOverrides struct {
 	// Querier and Ingester enforced overrides.
 	MaxBytesPerTagValuesQuery  int `yaml:"max_bytes_per_tag_values_query,omitempty" json:"max_bytes_per_tag_values_query,omitempty"`
 	MaxBlocksPerTagValuesQuery int `yaml:"max_blocks_per_tag_values_query,omitempty" json:"max_blocks_per_tag_values_query,omitempty"`
+	// MaxConcurrentTailRequests is the maximum number of live tails of the tenant open at once on each querier and
+	// ingester. 0 disables live tailing.
+	MaxConcurrentTailRequests int `yaml:"max_concurrent_tail_requests,omitempty" json:"max_concurrent_tail_requests,omitempty"`
 
 	// QueryFrontend enforced overrides
-	MaxSearchDuration  model.Duration `yaml:"max_search_duration,omitempty" json:"max_search_duration,omitempty"`
-	MaxMetricsDuration model.Duration `yaml:"max_metrics_duration,omitempty" json:"max_metrics_duration,omitempty"`
+	// MaxTagValuesPerQuery pages tag values queries so that a response holds at most this many values.
+	MaxTagValuesPerQuery int            `yaml:"max_tag_values_per_query,omitempty" json:"max_tag_values_per_query,omitempty"`
+	MaxSearchDuration    model.Duration `yaml:"max_search_duration,omitempty" json:"max_search_duration,omitempty"`
+	MaxMetricsDuration   model.Duration `yaml:"max_metrics_duration,omitempty" json:"max_metrics_duration,omitempty"`
 
 	UnsafeQueryHints bool `yaml:"unsafe_query_hints,omitempty" json:"unsafe_query_hints,omitempty"`
+
+	// QueryAuditEnabled records the queries of the tenant in the query audit log.
+	QueryAuditEnabled bool `yaml:"query_audit_enabled,omitempty" json:"query_audit_enabled,omitempty"`
+	// QueryAuditRetention is how long query audit records are kept. 0 keeps them forever.
+	QueryAuditRetention model.Duration `yaml:"query_audit_retention,omitempty" json:"query_audit_retention,omitempty"`
 }
 
 type CompactionOverrides struct {
@@ -173,6 +200,18 @@ type CompactionOverrides struct {
 	BlockRetention     model.Duration `yaml:"block_retention,omitempty" json:"block_retention,omitempty"`
 	CompactionWindow   model.Duration `yaml:"compaction_window,omitempty" json:"compaction_window,omitempty"`
 	CompactionDisabled bool           `yaml:"compaction_disabled,omitempty" json:"compaction_disabled,omitempty"`
+	// ConvertV2Blocks rewrites v2 blocks in the configured parquet block version during compaction.
+	ConvertV2Blocks bool `yaml:"convert_v2_blocks,omitempty" json:"convert_v2_blocks,omitempty"`
+	// DedicatedColumnsAutoApply writes compacted vParquet4 blocks with the dedicated columns recommended for the tenant.
+	DedicatedColumnsAutoApply bool `yaml:"dedicated_columns_auto_apply,omitempty" json:"dedicated_columns_auto_apply,omitempty"`
+	// RetentionClassAttribute is the resource attribute whose value selects the retention class of a block.
+	RetentionClassAttribute string `yaml:"retention_class_attribute,omitempty" json:"retention_class_attribute,omitempty"`
+	// RetentionClasses is the retention of blocks by the value of RetentionClassAttribute.
+	RetentionClasses map[string]model.Duration `yaml:"retention_classes,omitempty" json:"retention_classes,omitempty"`
+	// ArchiveAfter moves blocks to the ArchiveTier of the backend once they are older than it. 0 disables archiving.
+	ArchiveAfter model.Duration `yaml:"archive_after,omitempty" json:"archive_after,omitempty"`
+	// ArchiveTier is the storage tier that blocks are archived to. Defaults to Archive.
+	ArchiveTier string `yaml:"archive_tier,omitempty" json:"archive_tier,omitempty"`
 }
 
 type GlobalOverrides struct {
@@ -184,6 +223,12 @@ type GlobalOverrides struct {
 type StorageOverrides struct {
 	// tempodb limits
 	DedicatedColumns backend.DedicatedColumns `yaml:"parquet_dedicated_columns" json:"parquet_dedicated_columns"`
+	// AttributePolicy decides which attribute keys are stored at all. Enforced at block creation.
+	AttributePolicy common.AttributePolicy `yaml:"attribute_policy,omitempty" json:"attribute_policy,omitempty"`
+	// AttributeCardinality limits the number of distinct values per attribute key. Enforced by ingesters.
+	AttributeCardinality common.AttributeCardinalityPolicy `yaml:"attribute_cardinality,omitempty" json:"attribute_cardinality,omitempty"`
+	// BlockEncoding overrides the encoding of the blocks created by block builders and compactors.
+	BlockEncoding common.BlockEncoding `yaml:"block_encoding,omitempty" json:"block_encoding,omitempty"`
 }
 
 type CostAttributionOverrides struct {
@@ -279,6 +324,7 @@ func (c *Config) RegisterFlagsAndApplyDefaults(f *flag.FlagSet) {
 	// Querier limits
 	f.IntVar(&c.Defaults.Read.MaxBytesPerTagValuesQuery, "querier.max-bytes-per-tag-values-query", 10e5, "Maximum size of response for a tag-values query. Used mainly to limit large the number of values associated with a particular tag")
 	f.IntVar(&c.Defaults.Read.MaxBlocksPerTagValuesQuery, "querier.max-blocks-per-tag-values-query", 0, "Maximum number of blocks to query for a tag-values query. 0 to disable.")
+	f.IntVar(&c.Defaults.Read.MaxConcurrentTailRequests, "querier.max-concurrent-tail-requests", 10, "Maximum number of live tails of a tenant open at once on each querier and ingester. 0 to disable live tailing.")
 
 	f.StringVar(&c.PerTenantOverrideConfig, "config.per-user-override-config", "", "File name of per-user Overrides.")
 	_ = c.PerTenantOverridePeriod.Set("10s")
@@ -5,6 +5,7 @@ import (
 
 	"github.com/grafana/tempo/pkg/util/listtomap"
 	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 
 	"github.com/prometheus/common/model"
 
@@ -14,14 +15,21 @@ import (
 
 func (c *Overrides) toLegacy() LegacyOverrides {
 	return LegacyOverrides{
-		IngestionRateStrategy:      c.Ingestion.RateStrategy,
-		IngestionRateLimitBytes:    c.Ingestion.RateLimitBytes,
-		IngestionBurstSizeBytes:    c.Ingestion.BurstSizeBytes,
-		IngestionTenantShardSize:   c.Ingestion.TenantShardSize,
-		MaxLocalTracesPerUser:      c.Ingestion.MaxLocalTracesPerUser,
-		MaxGlobalTracesPerUser:     c.Ingestion.MaxGlobalTracesPerUser,
-		IngestionMaxAttributeBytes: c.Ingestion.MaxAttributeBytes,
-		IngestionArtificialDelay:   c.Ingestion.ArtificialDelay,
+		IngestionRateStrategy:        c.Ingestion.RateStrategy,
+		IngestionRateLimitBytes:      c.Ingestion.RateLimitBytes,
+		IngestionBurstSizeBytes:      c.Ingestion.BurstSizeBytes,
+		IngestionBurstRateLimitBytes: c.Ingestion.BurstRateLimitBytes,
+		IngestionBurstDuration:       c.Ingestion.BurstDuration,
+		IngestionTenantShardSize:     c.Ingestion.TenantShardSize,
+		MaxLocalTracesPerUser:        c.Ingestion.MaxLocalTracesPerUser,
+		MaxGlobalTracesPerUser:       c.Ingestion.MaxGlobalTracesPerUser,
+		IngestionMaxAttributeBytes:   c.Ingestion.MaxAttributeBytes,
+		IngestionArtificialDelay:     c.Ingestion.ArtificialDelay,
+
+		IngestionTraceIDHashScheme:         c.Ingestion.TraceIDHashScheme,
+		IngestionPreviousTraceIDHashScheme: c.Ingestion.PreviousTraceIDHashScheme,
+		IngestionAttributeDenylist:         c.Ingestion.AttributeDenylist,
+		IngestionAttributeDenylistMode:     c.Ingestion.AttributeDenylistMode,
 
 		Forwarders: c.Forwarders,
 
@@ -58,19 +66,32 @@ func (c *Overrides) toLegacy() LegacyOverrides {
 		MetricsGeneratorProcessorHostInfoMetricName:                                 c.MetricsGenerator.Processor.HostInfo.MetricName,
 		MetricsGeneratorIngestionSlack:                                              c.MetricsGenerator.IngestionSlack,
 
-		BlockRetention:     c.Compaction.BlockRetention,
-		CompactionWindow:   c.Compaction.CompactionWindow,
-		CompactionDisabled: c.Compaction.CompactionDisabled,
+		BlockRetention:            c.Compaction.BlockRetention,
+		CompactionWindow:          c.Compaction.CompactionWindow,
+		CompactionDisabled:        c.Compaction.CompactionDisabled,
+		ConvertV2Blocks:           c.Compaction.ConvertV2Blocks,
+		DedicatedColumnsAutoApply: c.Compaction.DedicatedColumnsAutoApply,
+		RetentionClassAttribute:   c.Compaction.RetentionClassAttribute,
+		RetentionClasses:          c.Compaction.RetentionClasses,
+		ArchiveAfter:              c.Compaction.ArchiveAfter,
+		ArchiveTier:               c.Compaction.ArchiveTier,
 
 		MaxBytesPerTagValuesQuery:  c.Read.MaxBytesPerTagValuesQuery,
 		MaxBlocksPerTagValuesQuery: c.Read.MaxBlocksPerTagValuesQuery,
+		MaxConcurrentTailRequests:  c.Read.MaxConcurrentTailRequests,
+		MaxTagValuesPerQuery:       c.Read.MaxTagValuesPerQuery,
 		MaxSearchDuration:          c.Read.MaxSearchDuration,
 		MaxMetricsDuration:         c.Read.MaxMetricsDuration,
 		UnsafeQueryHints:           c.Read.UnsafeQueryHints,
+		QueryAuditEnabled:          c.Read.QueryAuditEnabled,
+		QueryAuditRetention:        c.Read.QueryAuditRetention,
 
 		MaxBytesPerTrace: c.Global.MaxBytesPerTrace,
 
-		DedicatedColumns: c.Storage.DedicatedColumns,
+		DedicatedColumns:            c.Storage.DedicatedColumns,
+		StorageAttributePolicy:      c.Storage.AttributePolicy,
+		StorageAttributeCardinality: c.Storage.AttributeCardinality,
+		StorageBlockEncoding:        c.Storage.BlockEncoding,
 		CostAttribution: CostAttributionOverrides{
 			Dimensions:     c.CostAttribution.Dimensions,
 			MaxCardinality: c.CostAttribution.MaxCardinality,
@@ -82,12 +103,20 @@ func (c *Overrides) toLegacy() LegacyOverrides {
 // limits via flags, or per-user limits via yaml config.
 type LegacyOverrides struct {
 	// Distributor enforced limits.
-	IngestionRateStrategy      string         `yaml:"ingestion_rate_strategy" json:"ingestion_rate_strategy"`
-	IngestionRateLimitBytes    int            `yaml:"ingestion_rate_limit_bytes" json:"ingestion_rate_limit_bytes"`
-	IngestionBurstSizeBytes    int            `yaml:"ingestion_burst_size_bytes" json:"ingestion_burst_size_bytes"`
-	IngestionTenantShardSize   int            `yaml:"ingestion_tenant_shard_size" json:"ingestion_tenant_shard_size"`
-	IngestionMaxAttributeBytes int            `yaml:"ingestion_max_attribute_bytes" json:"ingestion_max_attribute_bytes"`
-	IngestionArtificialDelay   *time.Duration `yaml:"ingestion_artificial_delay" json:"ingestion_artificial_delay"`
+	IngestionRateStrategy        string         `yaml:"ingestion_rate_strategy" json:"ingestion_rate_strategy"`
+	IngestionRateLimitBytes      int            `yaml:"ingestion_rate_limit_bytes" json:"ingestion_rate_limit_bytes"`
+	IngestionBurstSizeBytes      int            `yaml:"ingestion_burst_size_bytes" json:"ingestion_burst_size_bytes"`
+	IngestionBurstRateLimitBytes int            `yaml:"ingestion_burst_rate_limit_bytes" json:"ingestion_burst_rate_limit_bytes"`
+	IngestionBurstDuration       model.Duration `yaml:"ingestion_burst_duration" json:"ingestion_burst_duration"`
+	IngestionTenantShardSize     int            `yaml:"ingestion_tenant_shard_size" json:"ingestion_tenant_shard_size"`
+	IngestionMaxAttributeBytes   int            `yaml:"ingestion_max_attribute_bytes" json:"ingestion_max_attribute_bytes"`
+	IngestionArtificialDelay     *time.Duration `yaml:"ingestion_artificial_delay" json:"ingestion_artificial_delay"`
+
+	IngestionTraceIDHashScheme         string `yaml:"ingestion_trace_id_hash_scheme" json:"ingestion_trace_id_hash_scheme"`
+	IngestionPreviousTraceIDHashScheme string `yaml:"ingestion_previous_trace_id_hash_scheme" json:"ingestion_previous_trace_id_hash_scheme"`
+
+	IngestionAttributeDenylist     []string `yaml:"ingestion_attribute_denylist" json:"ingestion_attribute_denylist"`
+	IngestionAttributeDenylistMode string   `yaml:"ingestion_attribute_denylist_mode" json:"ingestion_attribute_denylist_mode"`
 
 	// Ingester enforced limits.
 	MaxLocalTracesPerUser  int `yaml:"max_traces_per_user" json:"max_traces_per_user"`
@@ -131,18 +160,28 @@ type LegacyOverrides struct {
 	MetricsGeneratorIngestionSlack                                              time.Duration                    `yaml:"metrics_generator_ingestion_time_range_slack" json:"metrics_generator_ingestion_time_range_slack"`
 
 	// Compactor enforced limits.
-	BlockRetention     model.Duration `yaml:"block_retention" json:"block_retention"`
-	CompactionDisabled bool           `yaml:"compaction_disabled" json:"compaction_disabled"`
-	CompactionWindow   model.Duration `yaml:"compaction_window" json:"compaction_window"`
+	BlockRetention            model.Duration            `yaml:"block_retention" json:"block_retention"`
+	CompactionDisabled        bool                      `yaml:"compaction_disabled" json:"compaction_disabled"`
+	CompactionWindow          model.Duration            `yaml:"compaction_window" json:"compaction_window"`
+	ConvertV2Blocks           bool                      `yaml:"compaction_convert_v2_blocks" json:"compaction_convert_v2_blocks"`
+	DedicatedColumnsAutoApply bool                      `yaml:"compaction_dedicated_columns_auto_apply" json:"compaction_dedicated_columns_auto_apply"`
+	RetentionClassAttribute   string                    `yaml:"compaction_retention_class_attribute" json:"compaction_retention_class_attribute"`
+	RetentionClasses          map[string]model.Duration `yaml:"compaction_retention_classes" json:"compaction_retention_classes"`
+	ArchiveAfter              model.Duration            `yaml:"compaction_archive_after" json:"compaction_archive_after"`
+	ArchiveTier               string                    `yaml:"compaction_archive_tier" json:"compaction_archive_tier"`
 
 	// Querier and Ingester enforced limits.
 	MaxBytesPerTagValuesQuery  int `yaml:"max_bytes_per_tag_values_query" json:"max_bytes_per_tag_values_query"`
 	MaxBlocksPerTagValuesQuery int `yaml:"max_blocks_per_tag_values_query" json:"max_blocks_per_tag_values_query"`
+	MaxConcurrentTailRequests  int `yaml:"max_concurrent_tail_requests" json:"max_concurrent_tail_requests"`
 
 	// QueryFrontend enforced limits
-	MaxSearchDuration  model.Duration `yaml:"max_search_duration" json:"max_search_duration"`
-	MaxMetricsDuration model.Duration `yaml:"max_metrics_duration" json:"max_metrics_duration"`
-	UnsafeQueryHints   bool           `yaml:"unsafe_query_hints" json:"unsafe_query_hints"`
+	MaxTagValuesPerQuery int            `yaml:"max_tag_values_per_query" json:"max_tag_values_per_query"`
+	MaxSearchDuration    model.Duration `yaml:"max_search_duration" json:"max_search_duration"`
+	MaxMetricsDuration   model.Duration `yaml:"max_metrics_duration" json:"max_metrics_duration"`
+	UnsafeQueryHints     bool           `yaml:"unsafe_query_hints" json:"unsafe_query_hints"`
+	QueryAuditEnabled    bool           `yaml:"query_audit_enabled" json:"query_audit_enabled"`
+	QueryAuditRetention  model.Duration `yaml:"query_audit_retention" json:"query_audit_retention"`
 
 	// MaxBytesPerTrace is enforced in the Ingester, Compactor, Querier (Search). It
 	//  is not used when doing a trace by id lookup.
@@ -151,7 +190,10 @@ type LegacyOverrides struct {
 	CostAttribution CostAttributionOverrides `yaml:"cost_attribution" json:"cost_attribution"`
 
 	// tempodb limits
-	DedicatedColumns backend.DedicatedColumns `yaml:"parquet_dedicated_columns" json:"parquet_dedicated_columns"`
+	DedicatedColumns            backend.DedicatedColumns          `yaml:"parquet_dedicated_columns" json:"parquet_dedicated_columns"`
+	StorageAttributePolicy      common.AttributePolicy            `yaml:"storage_attribute_policy" json:"storage_attribute_policy"`
+	StorageAttributeCardinality common.AttributeCardinalityPolicy `yaml:"storage_attribute_cardinality" json:"storage_attribute_cardinality"`
+	StorageBlockEncoding        common.BlockEncoding              `yaml:"storage_block_encoding" json:"storage_block_encoding"`
 }
 
 func (l *LegacyOverrides) toNewLimits() Overrides {
@@ -160,23 +202,40 @@ func (l *LegacyOverrides) toNewLimits() Overrides {
 			RateStrategy:           l.IngestionRateStrategy,
 			RateLimitBytes:         l.IngestionRateLimitBytes,
 			BurstSizeBytes:         l.IngestionBurstSizeBytes,
+			BurstRateLimitBytes:    l.IngestionBurstRateLimitBytes,
+			BurstDuration:          l.IngestionBurstDuration,
 			MaxLocalTracesPerUser:  l.MaxLocalTracesPerUser,
 			MaxGlobalTracesPerUser: l.MaxGlobalTracesPerUser,
 			TenantShardSize:        l.IngestionTenantShardSize,
 			MaxAttributeBytes:      l.IngestionMaxAttributeBytes,
 			ArtificialDelay:        l.IngestionArtificialDelay,
+
+			TraceIDHashScheme:         l.IngestionTraceIDHashScheme,
+			PreviousTraceIDHashScheme: l.IngestionPreviousTraceIDHashScheme,
+			AttributeDenylist:         l.IngestionAttributeDenylist,
+			AttributeDenylistMode:     l.IngestionAttributeDenylistMode,
 		},
 		Read: ReadOverrides{
 			MaxBytesPerTagValuesQuery:  l.MaxBytesPerTagValuesQuery,
 			MaxBlocksPerTagValuesQuery: l.MaxBlocksPerTagValuesQuery,
+			MaxConcurrentTailRequests:  l.MaxConcurrentTailRequests,
+			MaxTagValuesPerQuery:       l.MaxTagValuesPerQuery,
 			MaxSearchDuration:          l.MaxSearchDuration,
 			MaxMetricsDuration:         l.MaxMetricsDuration,
 			UnsafeQueryHints:           l.UnsafeQueryHints,
+			QueryAuditEnabled:          l.QueryAuditEnabled,
+			QueryAuditRetention:        l.QueryAuditRetention,
 		},
 		Compaction: CompactionOverrides{
-			BlockRetention:     l.BlockRetention,
-			CompactionDisabled: l.CompactionDisabled,
-			CompactionWindow:   l.CompactionWindow,
+			BlockRetention:            l.BlockRetention,
+			CompactionDisabled:        l.CompactionDisabled,
+			CompactionWindow:          l.CompactionWindow,
+			ConvertV2Blocks:           l.ConvertV2Blocks,
+			DedicatedColumnsAutoApply: l.DedicatedColumnsAutoApply,
+			RetentionClassAttribute:   l.RetentionClassAttribute,
+			RetentionClasses:          l.RetentionClasses,
+			ArchiveAfter:              l.ArchiveAfter,
+			ArchiveTier:               l.ArchiveTier,
 		},
 		MetricsGenerator: MetricsGeneratorOverrides{
 			RingSize:                 l.MetricsGeneratorRingSize,
@@ -229,7 +288,10 @@ func (l *LegacyOverrides) toNewLimits() Overrides {
 			MaxBytesPerTrace: l.MaxBytesPerTrace,
 		},
 		Storage: StorageOverrides{
-			DedicatedColumns: l.DedicatedColumns,
+			DedicatedColumns:     l.DedicatedColumns,
+			AttributePolicy:      l.StorageAttributePolicy,
+			AttributeCardinality: l.StorageAttributeCardinality,
+			BlockEncoding:        l.StorageBlockEncoding,
 		},
 		CostAttribution: CostAttributionOverrides{
 			Dimensions:     l.CostAttribution.Dimensions,
@@ -19,6 +19,7 @@ import (
 	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
 	"github.com/grafana/tempo/pkg/util/listtomap"
 	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 // Copied from Cortex
@@ -396,12 +397,19 @@ func isZeroValue(v reflect.Value) bool {
 func generateTestLegacyOverrides() LegacyOverrides {
 	// Create a predefined test fixture with values for all fields
 	return LegacyOverrides{
-		IngestionRateStrategy:      "local",
-		IngestionRateLimitBytes:    100,
-		IngestionBurstSizeBytes:    200,
-		IngestionTenantShardSize:   3,
-		IngestionMaxAttributeBytes: 1000,
-		IngestionArtificialDelay:   durationPtr(5 * time.Minute),
+		IngestionRateStrategy:        "local",
+		IngestionRateLimitBytes:      100,
+		IngestionBurstSizeBytes:      200,
+		IngestionTenantShardSize:     3,
+		IngestionMaxAttributeBytes:   1000,
+		IngestionArtificialDelay:     durationPtr(5 * time.Minute),
+		IngestionBurstRateLimitBytes: 400,
+		IngestionBurstDuration:       model.Duration(time.Minute),
+
+		IngestionTraceIDHashScheme:         "xxhash64",
+		IngestionPreviousTraceIDHashScheme: "fnv32",
+		IngestionAttributeDenylist:         []string{"http.request.header.authorization"},
+		IngestionAttributeDenylistMode:     "hash",
 
 		MaxLocalTracesPerUser:  1000,
 		MaxGlobalTracesPerUser: 2000,
@@ -462,16 +470,26 @@ func generateTestLegacyOverrides() LegacyOverrides {
 		MetricsGeneratorProcessorHostInfoMetricName:                      "host_info",
 		MetricsGeneratorIngestionSlack:                                   1 * time.Minute,
 
-		BlockRetention:     model.Duration(7 * 24 * time.Hour),
-		CompactionDisabled: true,
-		CompactionWindow:   model.Duration(4 * time.Hour),
+		BlockRetention:            model.Duration(7 * 24 * time.Hour),
+		CompactionDisabled:        true,
+		ConvertV2Blocks:           true,
+		DedicatedColumnsAutoApply: true,
+		CompactionWindow:          model.Duration(4 * time.Hour),
+		RetentionClassAttribute:   "deployment.environment",
+		RetentionClasses:          map[string]model.Duration{"prod": model.Duration(30 * 24 * time.Hour), "dev": model.Duration(3 * 24 * time.Hour)},
+		ArchiveAfter:              model.Duration(3 * 24 * time.Hour),
+		ArchiveTier:               "Cold",
 
 		MaxBytesPerTagValuesQuery:  1000,
 		MaxBlocksPerTagValuesQuery: 100,
+		MaxConcurrentTailRequests:  5,
+		MaxTagValuesPerQuery:       5000,
 
-		MaxSearchDuration:  model.Duration(10 * time.Minute),
-		MaxMetricsDuration: model.Duration(30 * time.Minute),
-		UnsafeQueryHints:   true,
+		MaxSearchDuration:   model.Duration(10 * time.Minute),
+		MaxMetricsDuration:  model.Duration(30 * time.Minute),
+		UnsafeQueryHints:    true,
+		QueryAuditEnabled:   true,
+		QueryAuditRetention: model.Duration(365 * 24 * time.Hour),
 
 		MaxBytesPerTrace: 10 * 1024 * 1024,
 
@@ -492,6 +510,21 @@ func generateTestLegacyOverrides() LegacyOverrides {
 				Type:  backend.DedicatedColumnTypeString,
 			},
 		},
+		StorageAttributePolicy: common.AttributePolicy{
+			Allow: []string{"allowed-attribute"},
+			Deny:  []string{"denied-attribute"},
+		},
+		StorageAttributeCardinality: common.AttributeCardinalityPolicy{
+			MaxValues:   1000,
+			Action:      common.AttributeCardinalityActionHash,
+			HashBuckets: 32,
+		},
+		StorageBlockEncoding: common.BlockEncoding{
+			RowGroupSizeBytes: 50_000_000,
+			Compression:       common.ParquetCompressionZstd,
+			ZstdLevel:         9,
+			DisableDictionary: true,
+		},
 	}
 }
 
@@ -16,3 +16,8 @@ var metricDiscardedSpans = promauto.NewCounterVec(prometheus.CounterOpts{
 func RecordDiscardedSpans(spansDiscarded int, reason string, tenant string) {
 	metricDiscardedSpans.WithLabelValues(reason, tenant).Add(float64(spansDiscarded))
 }
+
+// DeleteDiscardedSpans removes the discarded spans series of a tenant for all reasons.
+func DeleteDiscardedSpans(tenant string) {
+	metricDiscardedSpans.DeletePartialMatch(prometheus.Labels{"tenant": tenant})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/grafana/tempo/pkg/sharedconfig"
 	"github.com/grafana/tempo/pkg/spanfilter/config"
 	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 type Service interface {
@@ -38,10 +39,17 @@ type Interface interface {
 	Forwarders(userID string) []string
 	MaxBytesPerTagValuesQuery(userID string) int
 	MaxBlocksPerTagValuesQuery(userID string) int
+	MaxTagValuesPerQuery(userID string) int
 	IngestionRateLimitBytes(userID string) float64
 	IngestionBurstSizeBytes(userID string) int
+	IngestionBurstRateLimitBytes(userID string) float64
+	IngestionBurstDuration(userID string) time.Duration
 	IngestionTenantShardSize(userID string) int
 	IngestionMaxAttributeBytes(userID string) int
+	IngestionTraceIDHashScheme(userID string) string
+	IngestionPreviousTraceIDHashScheme(userID string) string
+	IngestionAttributeDenylist(userID string) []string
+	IngestionAttributeDenylistMode(userID string) string
 	MetricsGeneratorIngestionSlack(userID string) time.Duration
 	MetricsGeneratorRingSize(userID string) int
 	MetricsGeneratorProcessors(userID string) map[string]struct{}
@@ -76,10 +84,20 @@ type Interface interface {
 	MetricsGeneratorProcessorHostInfoMetricName(userID string) string
 	BlockRetention(userID string) time.Duration
 	CompactionDisabled(userID string) bool
+	CompactionConvertV2Blocks(userID string) bool
+	CompactionDedicatedColumnsAutoApply(userID string) bool
+	BlockRetentionClasses(userID string) (string, map[string]time.Duration)
+	BlockArchive(userID string) (time.Duration, string)
 	MaxSearchDuration(userID string) time.Duration
 	MaxMetricsDuration(userID string) time.Duration
 	DedicatedColumns(userID string) backend.DedicatedColumns
+	StorageAttributePolicy(userID string) common.AttributePolicy
+	StorageAttributeCardinality(userID string) common.AttributeCardinalityPolicy
+	StorageBlockEncoding(userID string) common.BlockEncoding
 	UnsafeQueryHints(userID string) bool
+	QueryAuditEnabled(userID string) bool
+	QueryAuditRetention(userID string) time.Duration
+	MaxConcurrentTailRequests(userID string) int
 	CostAttributionMaxCardinality(userID string) uint64
 	CostAttributionDimensions(userID string) map[string]string
 
@@ -22,6 +22,7 @@ import (
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 type Validator interface {
@@ -69,25 +70,8 @@ func (o *perTenantOverrides) forUser(userID string) *Overrides {
 // loadPerTenantOverrides is of type runtimeconfig.Loader
 func loadPerTenantOverrides(validator Validator, typ ConfigType, expandEnv bool) func(r io.Reader) (interface{}, error) {
 	return func(r io.Reader) (interface{}, error) {
-		overrides := &perTenantOverrides{}
-
-		if expandEnv {
-			rr := r.(*bytes.Reader)
-			b, err := io.ReadAll(rr)
-			if err != nil {
-				return nil, err
-			}
-
-			s, err := envsubst.EvalEnv(string(b))
-			if err != nil {
-				return nil, fmt.Errorf("failed to expand env vars: %w", err)
-			}
-			r = bytes.NewReader([]byte(s))
-		}
-
-		decoder := yaml.NewDecoder(r)
-		decoder.SetStrict(true)
-		if err := decoder.Decode(&overrides); err != nil {
+		overrides, err := decodePerTenantOverrides(r, expandEnv)
+		if err != nil {
 			return nil, err
 		}
 
@@ -114,6 +98,51 @@ func loadPerTenantOverrides(validator Validator, typ ConfigType, expandEnv bool)
 	}
 }
 
+func decodePerTenantOverrides(r io.Reader, expandEnv bool) (*perTenantOverrides, error) {
+	overrides := &perTenantOverrides{}
+
+	if expandEnv {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+
+		s, err := envsubst.EvalEnv(string(b))
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand env vars: %w", err)
+		}
+		r = bytes.NewReader([]byte(s))
+	}
+
+	decoder := yaml.NewDecoder(r)
+	decoder.SetStrict(true)
+	if err := decoder.Decode(&overrides); err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+// ValidatePerTenantOverrides parses a per-tenant overrides file like the overrides module does and validates the
+// overrides of each tenant. The validation errors are returned by tenant, an error is returned if the file can't be
+// parsed. The config type of the file is returned to detect the deprecated legacy format.
+func ValidatePerTenantOverrides(r io.Reader, validator Validator, expandEnv bool) (map[string]error, ConfigType, error) {
+	overrides, err := decodePerTenantOverrides(r, expandEnv)
+	if err != nil {
+		return nil, "", err
+	}
+
+	errs := map[string]error{}
+	for tenant, tenantOverrides := range overrides.TenantLimits {
+		if tenantOverrides == nil {
+			continue
+		}
+		if err := validator.Validate(tenantOverrides); err != nil {
+			errs[tenant] = err
+		}
+	}
+	return errs, overrides.ConfigType, nil
+}
+
 // runtimeConfigOverridesManager periodically fetch a set of per-user overrides, and provides convenience
 // functions for fetching the correct value.
 type runtimeConfigOverridesManager struct {
@@ -325,6 +354,16 @@ func (o *runtimeConfigOverridesManager) IngestionBurstSizeBytes(userID string) i
 	return o.getOverridesForUser(userID).Ingestion.BurstSizeBytes
 }
 
+// IngestionBurstRateLimitBytes is the rate of the burst class of this tenant, 0 if it has no burst class.
+func (o *runtimeConfigOverridesManager) IngestionBurstRateLimitBytes(userID string) float64 {
+	return float64(o.getOverridesForUser(userID).Ingestion.BurstRateLimitBytes)
+}
+
+// IngestionBurstDuration is how long this tenant can ingest at the rate of the burst class.
+func (o *runtimeConfigOverridesManager) IngestionBurstDuration(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).Ingestion.BurstDuration)
+}
+
 // IngestionTenantShardSize is the shard size.
 func (o *runtimeConfigOverridesManager) IngestionTenantShardSize(userID string) int {
 	return o.getOverridesForUser(userID).Ingestion.TenantShardSize
@@ -334,6 +373,24 @@ func (o *runtimeConfigOverridesManager) IngestionMaxAttributeBytes(userID string
 	return o.getOverridesForUser(userID).Ingestion.MaxAttributeBytes
 }
 
+func (o *runtimeConfigOverridesManager) IngestionTraceIDHashScheme(userID string) string {
+	return o.getOverridesForUser(userID).Ingestion.TraceIDHashScheme
+}
+
+func (o *runtimeConfigOverridesManager) IngestionPreviousTraceIDHashScheme(userID string) string {
+	return o.getOverridesForUser(userID).Ingestion.PreviousTraceIDHashScheme
+}
+
+// IngestionAttributeDenylist returns the keys of the attributes removed or hashed before traces are written.
+func (o *runtimeConfigOverridesManager) IngestionAttributeDenylist(userID string) []string {
+	return o.getOverridesForUser(userID).Ingestion.AttributeDenylist
+}
+
+// IngestionAttributeDenylistMode returns whether denied attributes are removed, "drop", or hashed, "hash".
+func (o *runtimeConfigOverridesManager) IngestionAttributeDenylistMode(userID string) string {
+	return o.getOverridesForUser(userID).Ingestion.AttributeDenylistMode
+}
+
 func (o *runtimeConfigOverridesManager) IngestionArtificialDelay(userID string) (time.Duration, bool) {
 	artificialDelay := o.getOverridesForUser(userID).Ingestion.ArtificialDelay
 	if artificialDelay != nil {
@@ -362,10 +419,30 @@ func (o *runtimeConfigOverridesManager) MaxBlocksPerTagValuesQuery(userID string
 	return o.getOverridesForUser(userID).Read.MaxBlocksPerTagValuesQuery
 }
 
+// MaxTagValuesPerQuery returns the maximum number of values in a page of a tag-values query allowed for a user.
+func (o *runtimeConfigOverridesManager) MaxTagValuesPerQuery(userID string) int {
+	return o.getOverridesForUser(userID).Read.MaxTagValuesPerQuery
+}
+
 func (o *runtimeConfigOverridesManager) UnsafeQueryHints(userID string) bool {
 	return o.getOverridesForUser(userID).Read.UnsafeQueryHints
 }
 
+func (o *runtimeConfigOverridesManager) QueryAuditEnabled(userID string) bool {
+	return o.getOverridesForUser(userID).Read.QueryAuditEnabled
+}
+
+// QueryAuditRetention is how long the query audit records of this tenant are kept.
+func (o *runtimeConfigOverridesManager) QueryAuditRetention(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).Read.QueryAuditRetention)
+}
+
+// MaxConcurrentTailRequests is the maximum number of live tails of this tenant open at once on each querier and
+// ingester.
+func (o *runtimeConfigOverridesManager) MaxConcurrentTailRequests(userID string) int {
+	return o.getOverridesForUser(userID).Read.MaxConcurrentTailRequests
+}
+
 func (o *runtimeConfigOverridesManager) CostAttributionMaxCardinality(userID string) uint64 {
 	return o.getOverridesForUser(userID).CostAttribution.MaxCardinality
 }
@@ -570,10 +647,58 @@ func (o *runtimeConfigOverridesManager) CompactionDisabled(userID string) bool {
 	return o.getOverridesForUser(userID).Compaction.CompactionDisabled
 }
 
+func (o *runtimeConfigOverridesManager) CompactionConvertV2Blocks(userID string) bool {
+	return o.getOverridesForUser(userID).Compaction.ConvertV2Blocks
+}
+
+func (o *runtimeConfigOverridesManager) CompactionDedicatedColumnsAutoApply(userID string) bool {
+	return o.getOverridesForUser(userID).Compaction.DedicatedColumnsAutoApply
+}
+
+// BlockRetentionClasses returns the resource attribute that selects the retention class of blocks and the retention
+// of each class for this tenant.
+func (o *runtimeConfigOverridesManager) BlockRetentionClasses(userID string) (string, map[string]time.Duration) {
+	compaction := o.getOverridesForUser(userID).Compaction
+	if compaction.RetentionClassAttribute == "" || len(compaction.RetentionClasses) == 0 {
+		return "", nil
+	}
+
+	classes := make(map[string]time.Duration, len(compaction.RetentionClasses))
+	for class, retention := range compaction.RetentionClasses {
+		classes[class] = time.Duration(retention)
+	}
+	return compaction.RetentionClassAttribute, classes
+}
+
+// BlockArchive returns the age after which blocks of this tenant are archived and the storage tier they are
+// archived to.
+func (o *runtimeConfigOverridesManager) BlockArchive(userID string) (time.Duration, string) {
+	compaction := o.getOverridesForUser(userID).Compaction
+	if compaction.ArchiveTier == "" {
+		return time.Duration(compaction.ArchiveAfter), DefaultArchiveTier
+	}
+	return time.Duration(compaction.ArchiveAfter), compaction.ArchiveTier
+}
+
 func (o *runtimeConfigOverridesManager) DedicatedColumns(userID string) backend.DedicatedColumns {
 	return o.getOverridesForUser(userID).Storage.DedicatedColumns
 }
 
+// StorageAttributePolicy returns the policy deciding which attributes are stored in blocks for this tenant.
+func (o *runtimeConfigOverridesManager) StorageAttributePolicy(userID string) common.AttributePolicy {
+	return o.getOverridesForUser(userID).Storage.AttributePolicy
+}
+
+// StorageAttributeCardinality returns the policy limiting the number of distinct values per attribute key for this tenant.
+func (o *runtimeConfigOverridesManager) StorageAttributeCardinality(userID string) common.AttributeCardinalityPolicy {
+	return o.getOverridesForUser(userID).Storage.AttributeCardinality
+}
+
+// StorageBlockEncoding returns the encoding overrides of the blocks created for this tenant.
+func (o *runtimeConfigOverridesManager) StorageBlockEncoding(userID string) common.BlockEncoding {
+	return o.getOverridesForUser(userID).Storage.BlockEncoding
+}
+
 func (o *runtimeConfigOverridesManager) getOverridesForUser(userID string) *Overrides {
 	if tenantOverrides := o.tenantOverrides(); tenantOverrides != nil {
 		l := tenantOverrides.forUser(userID)
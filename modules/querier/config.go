@@ -22,6 +22,7 @@ type Config struct {
 	ShuffleShardingIngestersEnabled        bool          `yaml:"shuffle_sharding_ingesters_enabled"`
 	ShuffleShardingIngestersLookbackPeriod time.Duration `yaml:"shuffle_sharding_ingesters_lookback_period"`
 	QueryRelevantIngesters                 bool          `yaml:"query_relevant_ingesters"`
+	QueryRelevantIngestersLookbackPeriod   time.Duration `yaml:"query_relevant_ingesters_lookback_period"`
 	SecondaryIngesterRing                  string        `yaml:"secondary_ingester_ring,omitempty"`
 }
 
@@ -71,6 +72,7 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 		DNSLookupPeriod: 10 * time.Second,
 	}
 	cfg.ShuffleShardingIngestersLookbackPeriod = 1 * time.Hour
+	cfg.QueryRelevantIngestersLookbackPeriod = 1 * time.Hour
 
 	f.StringVar(&cfg.Worker.FrontendAddress, prefix+".frontend-address", "", "Address of query frontend service, in host:port format.")
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/backend"
 )
 
 const (
@@ -27,6 +28,8 @@ const (
 	QueryModeBlocks    = "blocks"
 	QueryModeAll       = "all"
 	QueryModeRecent    = "recent"
+
+	headerContentTypeNDJSON = "application/x-ndjson"
 )
 
 // TraceByIDHandler is a http.HandlerFunc to retrieve traces
@@ -126,6 +129,51 @@ func (q *Querier) TraceByIDHandlerV2(w http.ResponseWriter, r *http.Request) {
 	writeFormattedContentForRequest(w, r, resp, span)
 }
 
+// TraceByIDsHandler is a http.HandlerFunc to retrieve several traces
+func (q *Querier) TraceByIDsHandler(w http.ResponseWriter, r *http.Request) {
+	// Enforce the query timeout while querying backends
+	ctx, cancel := context.WithDeadline(r.Context(), time.Now().Add(q.cfg.TraceByID.QueryTimeout))
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "Querier.TraceByIDsHandler")
+	defer span.End()
+
+	byteIDs, err := api.ParseTraceIDs(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// validate request
+	blockStart, blockEnd, queryMode, timeStart, timeEnd, rf1After, err := api.ValidateAndSanitizeRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	span.AddEvent("validated request", oteltrace.WithAttributes(
+		attribute.String("blockStart", blockStart),
+		attribute.String("blockEnd", blockEnd),
+		attribute.String("queryMode", queryMode),
+		attribute.String("timeStart", fmt.Sprint(timeStart)),
+		attribute.String("timeEnd", fmt.Sprint(timeEnd)),
+		attribute.Int("traceIDs", len(byteIDs)),
+		attribute.String("rf1After", rf1After.Format(time.RFC3339)),
+	))
+
+	resp, err := q.FindTraceByIDs(ctx, &tempopb.TraceByIDRequest{
+		BlockStart:        blockStart,
+		BlockEnd:          blockEnd,
+		QueryMode:         queryMode,
+		AllowPartialTrace: true,
+		RF1After:          rf1After,
+	}, byteIDs, timeStart, timeEnd)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeFormattedContentForRequest(w, r, resp, span)
+}
+
 func (q *Querier) SearchHandler(w http.ResponseWriter, r *http.Request) {
 	isSearchBlock := api.IsSearchBlock(r)
 
@@ -419,6 +467,55 @@ func (q *Querier) QueryRangeHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// TailHandler streams the spans matching the query as they are received by the ingesters. Each line of the response is
+// a JSON search response, flushed as soon as it's written. Errors before the first line are returned with a status
+// code, the response ends at the first error after it.
+func (q *Querier) TailHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "Querier.TailHandler")
+	defer span.End()
+
+	req, err := api.ParseSearchRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	span.SetAttributes(attribute.String("SearchRequest", req.String()))
+
+	// the tail is open until the client closes it, the write timeout of the server doesn't apply
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	started := false
+	err = q.Tail(ctx, req, func(resp *tempopb.SearchResponse) error {
+		if !started {
+			w.Header().Set(api.HeaderContentType, headerContentTypeNDJSON)
+			started = true
+		}
+		if err := new(jsonpb.Marshaler).Marshal(w, resp); err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte("\n")); err != nil {
+			return err
+		}
+		return rc.Flush()
+	})
+	if err == nil || started {
+		if err != nil {
+			span.RecordError(err)
+		}
+		return
+	}
+
+	switch {
+	case errors.Is(err, errInvalidTailQuery):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, errTailLimitExceeded):
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	default:
+		handleError(w, err)
+	}
+}
+
 func handleError(w http.ResponseWriter, err error) {
 	if err == nil {
 		return
@@ -437,6 +534,12 @@ func handleError(w http.ResponseWriter, err error) {
 		return
 	}
 
+	// archived blocks can't be read until they are rehydrated. fail fast instead of retrying the request.
+	if errors.Is(err, backend.ErrBlockArchived) || strings.Contains(err.Error(), backend.ErrBlockArchived.Error()) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
 	http.Error(w, err.Error(), http.StatusInternalServerError)
 }
 
@@ -54,6 +54,11 @@ var (
 		Name:      "querier_metrics_generator_clients",
 		Help:      "The current number of generator clients.",
 	})
+	metricTraceByIDIngesterFanout = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "querier_trace_by_id_ingester_fanout_total",
+		Help:      "Number of trace by id lookups of relevant ingesters by ring, by whether only the trace owners or all ingesters were queried.",
+	}, []string{"fanout"})
 )
 
 type (
@@ -77,6 +82,7 @@ type Querier struct {
 	engine *traceql.Engine
 	store  storage.Store
 	limits overrides.Interface
+	tails  tailLimiter
 
 	subservices        *services.Manager
 	subservicesWatcher *services.FailureWatcher
@@ -214,13 +220,14 @@ func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDReque
 	maxBytes := q.limits.MaxBytesPerTrace(userID)
 	combiner := trace.NewCombiner(maxBytes, req.AllowPartialTrace)
 	var inspectedBytes uint64
+	var warnings []*tempopb.QueryWarning
 
 	if req.QueryMode == QueryModeIngesters || req.QueryMode == QueryModeAll {
-		var getRSFn replicationSetFn
+		getRSFns := []replicationSetFn{nil}
 		if q.cfg.QueryRelevantIngesters {
-			traceKey := util.TokenFor(userID, req.TraceID)
-			getRSFn = func(r ring.ReadRing) (ring.ReplicationSet, error) {
-				return r.Get(traceKey, ring.Read, nil, nil, nil)
+			getRSFns = getRSFns[:0]
+			for _, traceKey := range q.traceKeys(userID, req.TraceID) {
+				getRSFns = append(getRSFns, q.traceOwnersReplicationSet(traceKey))
 			}
 		}
 
@@ -229,9 +236,13 @@ func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDReque
 		forEach := func(funcCtx context.Context, client tempopb.QuerierClient) (any, error) {
 			return client.FindTraceByID(funcCtx, req)
 		}
-		partialTraces, err := q.forIngesterRings(ctx, userID, getRSFn, forEach)
-		if err != nil {
-			return nil, fmt.Errorf("error querying ingesters in Querier.FindTraceByID: %w", err)
+		var partialTraces []any
+		for _, getRSFn := range getRSFns {
+			results, err := q.forIngesterRings(ctx, userID, getRSFn, forEach)
+			if err != nil {
+				return nil, fmt.Errorf("error querying ingesters in Querier.FindTraceByID: %w", err)
+			}
+			partialTraces = append(partialTraces, results...)
 		}
 
 		var spanCountTotal, traceCountTotal int64
@@ -280,9 +291,14 @@ func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDReque
 			return nil, retErr
 		}
 
+		// skipped blocks are returned as warnings, other errors fail the request
+		warnings, blockErrs = blockErrorsWarnings(blockErrs)
 		if len(blockErrs) > 0 {
 			return nil, multierr.Combine(blockErrs...)
 		}
+		if q.store.BlocklistStale(userID) {
+			warnings = tempopb.AppendWarnings(warnings, warningStaleBlocklist)
+		}
 
 		span.AddEvent("done searching store", oteltrace.WithAttributes(
 			attribute.Int("foundPartialTraces", len(partialTraces))))
@@ -303,18 +319,170 @@ func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDReque
 
 	completeTrace, _ := combiner.Result()
 	resp := &tempopb.TraceByIDResponse{
-		Trace:   completeTrace,
-		Metrics: &tempopb.TraceByIDMetrics{InspectedBytes: inspectedBytes},
+		Trace:    completeTrace,
+		Metrics:  &tempopb.TraceByIDMetrics{InspectedBytes: inspectedBytes},
+		Warnings: warnings,
 	}
 
 	if combiner.IsPartialTrace() {
 		resp.Status = tempopb.PartialStatus_PARTIAL
 		resp.Message = fmt.Sprintf("Trace exceeds maximum size of %d bytes, a partial trace is returned", maxBytes)
+		resp.Warnings = tempopb.AppendWarnings(resp.Warnings, tempopb.NewQueryWarning(tempopb.WarningResultsTruncated, resp.Message))
 	}
 
 	return resp, nil
 }
 
+// FindTraceByIDs finds several traces. The ingesters are queried for each trace, the blocks of the store are
+// searched once for all the traces. req holds the parameters of the request other than the trace id.
+func (q *Querier) FindTraceByIDs(ctx context.Context, req *tempopb.TraceByIDRequest, traceIDs [][]byte, timeStart int64, timeEnd int64) (*tempopb.TraceByIDsResponse, error) {
+	for _, traceID := range traceIDs {
+		if !validation.ValidTraceID(traceID) {
+			return nil, errors.New("invalid trace id")
+		}
+	}
+
+	userID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting org id in Querier.FindTraceByIDs: %w", err)
+	}
+
+	ctx, span := tracer.Start(ctx, "Querier.FindTraceByIDs")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("queryMode", req.QueryMode), attribute.Int("traceIDs", len(traceIDs)))
+
+	maxBytes := q.limits.MaxBytesPerTrace(userID)
+	combiners := make([]*trace.Combiner, len(traceIDs))
+	for i := range combiners {
+		combiners[i] = trace.NewCombiner(maxBytes, req.AllowPartialTrace)
+	}
+	var inspectedBytes uint64
+	var warnings []*tempopb.QueryWarning
+
+	if req.QueryMode == QueryModeIngesters || req.QueryMode == QueryModeAll {
+		for i, traceID := range traceIDs {
+			ingesterReq := *req
+			ingesterReq.TraceID = traceID
+			ingesterReq.QueryMode = QueryModeIngesters
+
+			resp, err := q.FindTraceByID(ctx, &ingesterReq, timeStart, timeEnd)
+			if err != nil {
+				return nil, err
+			}
+			if resp.Trace != nil {
+				if _, err := combiners[i].Consume(resp.Trace); err != nil {
+					return nil, fmt.Errorf("error combining ingester results in Querier.FindTraceByIDs: %w", err)
+				}
+			}
+			if resp.Metrics != nil {
+				inspectedBytes += resp.Metrics.InspectedBytes
+			}
+		}
+	}
+
+	if req.QueryMode == QueryModeBlocks || req.QueryMode == QueryModeAll {
+		opts := common.DefaultSearchOptionsWithMaxBytes(maxBytes)
+		opts.RF1After = req.RF1After
+
+		ids := make([]common.ID, 0, len(traceIDs))
+		for _, traceID := range traceIDs {
+			ids = append(ids, traceID)
+		}
+
+		partialTraces, metrics, blockErrs, err := q.store.FindMany(ctx, userID, ids, req.BlockStart, req.BlockEnd, timeStart, timeEnd, opts)
+		if err != nil {
+			retErr := fmt.Errorf("error querying store in Querier.FindTraceByIDs: %w", err)
+			span.RecordError(retErr)
+			return nil, retErr
+		}
+
+		// skipped blocks are returned as warnings, other errors fail the request
+		warnings, blockErrs = blockErrorsWarnings(blockErrs)
+		if len(blockErrs) > 0 {
+			return nil, multierr.Combine(blockErrs...)
+		}
+		if q.store.BlocklistStale(userID) {
+			warnings = tempopb.AppendWarnings(warnings, warningStaleBlocklist)
+		}
+
+		for i, traces := range partialTraces {
+			for _, partialTrace := range traces {
+				if _, err := combiners[i].Consume(partialTrace); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if metrics != nil {
+			inspectedBytes += metrics.InspectedBytes
+		}
+	}
+
+	resp := &tempopb.TraceByIDsResponse{
+		Traces:   make([]*tempopb.Trace, 0, len(traceIDs)),
+		Metrics:  &tempopb.TraceByIDMetrics{InspectedBytes: inspectedBytes},
+		Warnings: warnings,
+	}
+	for i, c := range combiners {
+		completeTrace, _ := c.Result()
+		if completeTrace == nil {
+			completeTrace = &tempopb.Trace{}
+		}
+		resp.Traces = append(resp.Traces, completeTrace)
+
+		if c.IsPartialTrace() {
+			resp.Warnings = tempopb.AppendWarnings(resp.Warnings, tempopb.NewQueryWarning(tempopb.WarningResultsTruncated,
+				fmt.Sprintf("Trace %s exceeds maximum size of %d bytes, a partial trace is returned", util.TraceIDToHexString(traceIDs[i]), maxBytes)))
+		}
+	}
+
+	return resp, nil
+}
+
+// traceOwnersReplicationSet returns the ingesters that own the trace key. The owners are only used as a hint of
+// where the trace is: if one of them registered in the ring within the lookback period, it took over the key from
+// ingesters that may still hold recent traces, so all ingesters are queried instead.
+func (q *Querier) traceOwnersReplicationSet(traceKey uint32) replicationSetFn {
+	return func(r ring.ReadRing) (ring.ReplicationSet, error) {
+		rs, err := r.Get(traceKey, ring.Read, nil, nil, nil)
+		if err != nil {
+			return ring.ReplicationSet{}, err
+		}
+
+		if ownersRegisteredSince(rs, time.Now().Add(-q.cfg.QueryRelevantIngestersLookbackPeriod)) {
+			metricTraceByIDIngesterFanout.WithLabelValues("all").Inc()
+			return r.GetReplicationSetForOperation(ring.Read)
+		}
+
+		metricTraceByIDIngesterFanout.WithLabelValues("owners").Inc()
+		return rs, nil
+	}
+}
+
+// ownersRegisteredSince returns true if an instance of the replication set registered in the ring after since
+func ownersRegisteredSince(rs ring.ReplicationSet, since time.Time) bool {
+	for _, instance := range rs.Instances {
+		if instance.GetRegisteredAt().After(since) {
+			return true
+		}
+	}
+	return false
+}
+
+// traceKeys returns the ring tokens of the trace ID. While a tenant migrates to another trace ID hash scheme the
+// trace can be in the ingesters of either scheme, so both tokens are returned.
+func (q *Querier) traceKeys(userID string, traceID []byte) []uint32 {
+	scheme := q.limits.IngestionTraceIDHashScheme(userID)
+	keys := []uint32{util.TokenForScheme(scheme, userID, traceID)}
+
+	if previous := q.limits.IngestionPreviousTraceIDHashScheme(userID); previous != "" && previous != scheme {
+		if key := util.TokenForScheme(previous, userID, traceID); key != keys[0] {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
 // forIngesterRings runs f, in parallel, for given ingesters
 func (q *Querier) forIngesterRings(ctx context.Context, userID string, getReplicationSet replicationSetFn, f forEachFn) ([]any, error) {
 	if ctx.Err() != nil {
@@ -341,16 +509,7 @@ func (q *Querier) forIngesterRings(ctx context.Context, userID string, getReplic
 	var overallResults []any
 
 	for i, ingesterRing := range q.ingesterRings {
-		if q.cfg.ShuffleShardingIngestersEnabled {
-			ingesterRing = ingesterRing.ShuffleShardWithLookback(
-				userID,
-				q.limits.IngestionTenantShardSize(userID),
-				q.cfg.ShuffleShardingIngestersLookbackPeriod,
-				time.Now(),
-			)
-		}
-
-		replicationSet, err := getReplicationSet(ingesterRing)
+		replicationSet, err := getReplicationSet(q.tenantIngesterRing(ingesterRing, userID))
 		if err != nil {
 			return nil, fmt.Errorf("forIngesterRings: error getting replication set for ring (%d): %w", i, err)
 		}
@@ -381,6 +540,19 @@ func (q *Querier) forIngesterRings(ctx context.Context, userID string, getReplic
 	return overallResults, nil
 }
 
+// tenantIngesterRing returns the ingesters of the ring that may hold traces of the tenant.
+func (q *Querier) tenantIngesterRing(ingesterRing ring.ReadRing, userID string) ring.ReadRing {
+	if !q.cfg.ShuffleShardingIngestersEnabled {
+		return ingesterRing
+	}
+	return ingesterRing.ShuffleShardWithLookback(
+		userID,
+		q.limits.IngestionTenantShardSize(userID),
+		q.cfg.ShuffleShardingIngestersLookbackPeriod,
+		time.Now(),
+	)
+}
+
 func forOneIngesterRing(ctx context.Context, replicationSet ring.ReplicationSet, f forEachFn, pool *ring_client.Pool, extraQueryDelay time.Duration) ([]any, error) {
 	ctx, span := tracer.Start(ctx, "Querier.forOneIngesterRing")
 	defer span.End()
@@ -489,11 +661,27 @@ func (q *Querier) SearchTagValuesBlocks(ctx context.Context, req *tempopb.Search
 }
 
 func (q *Querier) SearchTagsBlocksV2(ctx context.Context, req *tempopb.SearchTagsBlockRequest) (*tempopb.SearchTagsV2Response, error) {
-	return q.internalTagsSearchBlockV2(ctx, req)
+	resp, err := q.internalTagsSearchBlockV2(ctx, req)
+	if w := blockWarning(err); w != nil {
+		return &tempopb.SearchTagsV2Response{Metrics: &tempopb.MetadataMetrics{}, Warnings: []*tempopb.QueryWarning{w}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp.Warnings = blockVersionWarnings(req.Version, resp.Warnings)
+	return resp, nil
 }
 
 func (q *Querier) SearchTagValuesBlocksV2(ctx context.Context, req *tempopb.SearchTagValuesBlockRequest) (*tempopb.SearchTagValuesV2Response, error) {
-	return q.internalTagValuesSearchBlockV2(ctx, req)
+	resp, err := q.internalTagValuesSearchBlockV2(ctx, req)
+	if w := blockWarning(err); w != nil {
+		return &tempopb.SearchTagValuesV2Response{Metrics: &tempopb.MetadataMetrics{}, Warnings: []*tempopb.QueryWarning{w}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp.Warnings = blockVersionWarnings(req.Version, resp.Warnings)
+	return resp, nil
 }
 
 func (q *Querier) SearchTags(ctx context.Context, req *tempopb.SearchTagsRequest) (*tempopb.SearchTagsResponse, error) {
@@ -646,7 +834,10 @@ func (q *Querier) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTagV
 	}
 
 	maxDataSize := q.limits.MaxBytesPerTagValuesQuery(userID)
-	distinctValues := collector.NewDistinctValue(maxDataSize, req.MaxTagValues, req.StaleValueThreshold, func(v tempopb.TagValue) int { return len(v.Type) + len(v.Value) })
+	distinctValues, err := collector.NewTagValuesV2(maxDataSize, req)
+	if err != nil {
+		return nil, err
+	}
 	var inspectedBytes uint64
 
 	// Virtual tags values. Get these first.
@@ -676,6 +867,9 @@ outer:
 		if resp.Metrics != nil {
 			inspectedBytes += resp.Metrics.InspectedBytes
 		}
+		if resp.NextToken != "" {
+			distinctValues.MarkTruncated()
+		}
 
 		for _, res := range resp.TagValues {
 			distinctValues.Collect(*res)
@@ -768,7 +962,8 @@ func (q *Querier) SpanMetricsSummary(ctx context.Context, req *tempopb.SpanMetri
 
 func valuesToV2Response(distinctValues *collector.DistinctValue[tempopb.TagValue], bytesRead uint64) *tempopb.SearchTagValuesV2Response {
 	resp := &tempopb.SearchTagValuesV2Response{
-		Metrics: &tempopb.MetadataMetrics{InspectedBytes: bytesRead},
+		Metrics:   &tempopb.MetadataMetrics{InspectedBytes: bytesRead},
+		NextToken: collector.NextTagValuesToken(distinctValues),
 	}
 	for _, v := range distinctValues.Values() {
 		v2 := v
@@ -777,8 +972,21 @@ func valuesToV2Response(distinctValues *collector.DistinctValue[tempopb.TagValue
 	return resp
 }
 
-// SearchBlock searches the specified subset of the block for the passed tags.
+// SearchBlock searches a page range of a block. A block that is skipped, because it's archived or its format isn't
+// supported, returns an empty response with a warning.
 func (q *Querier) SearchBlock(ctx context.Context, req *tempopb.SearchBlockRequest) (*tempopb.SearchResponse, error) {
+	resp, err := q.searchBlock(ctx, req)
+	if w := blockWarning(err); w != nil {
+		return &tempopb.SearchResponse{Metrics: &tempopb.SearchMetrics{}, Warnings: []*tempopb.QueryWarning{w}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp.Warnings = blockVersionWarnings(req.Version, resp.Warnings)
+	return resp, nil
+}
+
+func (q *Querier) searchBlock(ctx context.Context, req *tempopb.SearchBlockRequest) (*tempopb.SearchResponse, error) {
 	tenantID, err := user.ExtractOrgID(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error extracting org id in Querier.BackendSearch: %w", err)
@@ -817,15 +1025,52 @@ func (q *Querier) SearchBlock(ctx context.Context, req *tempopb.SearchBlockReque
 	opts.TotalPages = int(req.PagesToSearch)
 	opts.MaxBytes = q.limits.MaxBytesPerTrace(tenantID)
 
+	// the reads of the block are broken down by role in the response metrics
+	ioCtx, ioStats := backend.ContextWithIOStats(ctx)
+
 	if api.IsTraceQLQuery(req.SearchReq) {
+		// sampled queries only search the jobs that are part of the sample
+		var fraction float64
+		var sampled bool
+		if expr, err := traceql.Parse(req.SearchReq.Query); err == nil {
+			fraction, sampled = expr.Hints.GetSample(q.limits.UnsafeQueryHints(tenantID))
+		}
+		if sampled && !inSample(fraction, req.BlockID, req.StartPage) {
+			return &tempopb.SearchResponse{Metrics: &tempopb.SearchMetrics{Estimated: true}}, nil
+		}
+
 		fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
 			return q.store.Fetch(ctx, meta, req, opts)
 		})
 
-		return q.engine.ExecuteSearch(ctx, req.SearchReq, fetcher)
+		resp, err := q.engine.ExecuteSearch(ioCtx, req.SearchReq, fetcher)
+		if err != nil {
+			return nil, err
+		}
+		if sampled && resp.Metrics != nil {
+			resp.Metrics.Estimated = true
+		}
+		setIOStats(resp.Metrics, ioStats)
+		return resp, nil
+	}
+
+	resp, err := q.store.Search(ioCtx, meta, req.SearchReq, opts)
+	if err != nil {
+		return nil, err
+	}
+	setIOStats(resp.Metrics, ioStats)
+	return resp, nil
+}
+
+// setIOStats sets the bytes read from the backend by the role of the objects read in the metrics of a response.
+func setIOStats(metrics *tempopb.SearchMetrics, stats *backend.IOStats) {
+	if metrics == nil {
+		return
 	}
 
-	return q.store.Search(ctx, meta, req.SearchReq, opts)
+	metrics.InspectedBloomBytes = stats.BloomBytes()
+	metrics.InspectedIndexBytes = stats.IndexBytes()
+	metrics.InspectedColumnBytes = stats.ColumnBytes()
 }
 
 func (q *Querier) internalTagsSearchBlockV2(ctx context.Context, req *tempopb.SearchTagsBlockRequest) (*tempopb.SearchTagsV2Response, error) {
@@ -1009,9 +1254,10 @@ func (q *Querier) internalTagValuesSearchBlockV2(ctx context.Context, req *tempo
 		return nil, err
 	}
 
-	valueCollector := collector.NewDistinctValue(q.limits.MaxBytesPerTagValuesQuery(tenantID),
-		req.SearchReq.MaxTagValues, req.SearchReq.StaleValueThreshold,
-		func(v tempopb.TagValue) int { return len(v.Type) + len(v.Value) })
+	valueCollector, err := collector.NewTagValuesV2(q.limits.MaxBytesPerTagValuesQuery(tenantID), req.SearchReq)
+	if err != nil {
+		return nil, err
+	}
 
 	var inspectedBytes uint64
 
@@ -1053,6 +1299,15 @@ func (q *Querier) postProcessIngesterSearchResults(req *tempopb.SearchRequest, r
 		}
 	}
 
+	if order, err := traceql.ParseSearchOrder(req.OrderBy); err == nil && order != nil {
+		combiner := traceql.NewOrderedMetadataCombiner(int(req.Limit), order, false)
+		for _, t := range traces {
+			combiner.AddMetadata(t)
+		}
+		response.Traces = combiner.Metadata()
+		return response
+	}
+
 	for _, t := range traces {
 		response.Traces = append(response.Traces, t)
 	}
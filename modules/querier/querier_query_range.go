@@ -20,7 +20,15 @@ func (q *Querier) QueryRange(ctx context.Context, req *tempopb.QueryRangeRequest
 		return q.queryRangeRecent(ctx, req)
 	}
 
-	return q.queryBlock(ctx, req)
+	resp, err := q.queryBlock(ctx, req)
+	if w := blockWarning(err); w != nil {
+		return &tempopb.QueryRangeResponse{Metrics: &tempopb.SearchMetrics{}, Warnings: []*tempopb.QueryWarning{w}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp.Warnings = blockVersionWarnings(req.Version, resp.Warnings)
+	return resp, nil
 }
 
 func (q *Querier) queryRangeRecent(ctx context.Context, req *tempopb.QueryRangeRequest) (*tempopb.QueryRangeResponse, error) {
@@ -107,15 +115,25 @@ func (q *Querier) queryBlock(ctx context.Context, req *tempopb.QueryRangeRequest
 		timeOverlapCutoff = v
 	}
 
+	// sampled queries only read the jobs that are part of the sample and extrapolate their results
+	fraction, sampled := expr.Hints.GetSample(unsafe)
+	if sampled && !inSample(fraction, req.BlockID, req.StartPage) {
+		return &tempopb.QueryRangeResponse{Metrics: &tempopb.SearchMetrics{Estimated: true}}, nil
+	}
+
 	eval, err := traceql.NewEngine().CompileMetricsQueryRange(req, int(req.Exemplars), timeOverlapCutoff, unsafe)
 	if err != nil {
 		return nil, err
 	}
+	if sampled {
+		eval.Extrapolate(fraction)
+	}
 
+	ioCtx, ioStats := backend.ContextWithIOStats(ctx)
 	f := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
 		return q.store.Fetch(ctx, meta, req, opts)
 	})
-	err = eval.Do(ctx, f, uint64(meta.StartTime.UnixNano()), uint64(meta.EndTime.UnixNano()), int(req.MaxSeries))
+	err = eval.Do(ioCtx, f, uint64(meta.StartTime.UnixNano()), uint64(meta.EndTime.UnixNano()), int(req.MaxSeries))
 	if err != nil {
 		return nil, err
 	}
@@ -142,8 +160,10 @@ func (q *Querier) queryBlock(ctx context.Context, req *tempopb.QueryRangeRequest
 		Metrics: &tempopb.SearchMetrics{
 			InspectedBytes: inspectedBytes,
 			InspectedSpans: spansTotal,
+			Estimated:      sampled,
 		},
 	}
+	setIOStats(response.Metrics, ioStats)
 
 	if req.MaxSeries > 0 && len(res) > int(req.MaxSeries) {
 		response.Status = tempopb.PartialStatus_PARTIAL
@@ -0,0 +1,17 @@
+package querier
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+)
+
+// inSample returns true if the job that starts at the given page of a block is part of a sample of the given
+// fraction of jobs. The sample is deterministic so that repeating a query reads the same jobs.
+func inSample(fraction float64, blockID string, startPage uint32) bool {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(blockID))
+	_ = binary.Write(h, binary.LittleEndian, startPage)
+
+	return float64(h.Sum64())/math.MaxUint64 < fraction
+}
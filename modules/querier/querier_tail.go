@@ -0,0 +1,193 @@
+package querier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/user"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+)
+
+// tailDedupeSize is the number of recent matches remembered to drop the copies sent by the replicas of a trace.
+const tailDedupeSize = 10_000
+
+var (
+	errTailLimitExceeded = errors.New("max concurrent tail requests exceeded")
+	errInvalidTailQuery  = errors.New("invalid tail query")
+)
+
+// tailLimiter limits the live tails of each tenant open at once on the querier. The zero value has no tails.
+type tailLimiter struct {
+	mtx    sync.Mutex
+	active map[string]int
+}
+
+func (l *tailLimiter) acquire(userID string, limit int) (func(), error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.active[userID] >= limit {
+		return nil, fmt.Errorf("%w: the limit of the tenant is %d", errTailLimitExceeded, limit)
+	}
+	if l.active == nil {
+		l.active = map[string]int{}
+	}
+	l.active[userID]++
+
+	return func() {
+		l.mtx.Lock()
+		defer l.mtx.Unlock()
+
+		l.active[userID]--
+		if l.active[userID] == 0 {
+			delete(l.active, userID)
+		}
+	}, nil
+}
+
+// tailDeduper drops the matches already sent, the replicas of a trace are matched by each of their ingesters. The
+// oldest matches are forgotten first.
+type tailDeduper struct {
+	seen  map[string]struct{}
+	order []string
+	next  int
+}
+
+func newTailDeduper(size int) *tailDeduper {
+	return &tailDeduper{
+		seen:  make(map[string]struct{}, size),
+		order: make([]string, size),
+	}
+}
+
+func (d *tailDeduper) filter(traces []*tempopb.TraceSearchMetadata) []*tempopb.TraceSearchMetadata {
+	filtered := traces[:0]
+	for _, md := range traces {
+		key := tailKey(md)
+		if _, ok := d.seen[key]; ok {
+			continue
+		}
+
+		delete(d.seen, d.order[d.next])
+		d.order[d.next] = key
+		d.next = (d.next + 1) % len(d.order)
+		d.seen[key] = struct{}{}
+
+		filtered = append(filtered, md)
+	}
+	return filtered
+}
+
+// tailKey identifies a match by its trace and spans, the spans received later for a trace are new matches.
+func tailKey(md *tempopb.TraceSearchMetadata) string {
+	var sb strings.Builder
+	sb.WriteString(md.TraceID)
+	for _, ss := range md.SpanSets {
+		for _, s := range ss.Spans {
+			sb.WriteByte(':')
+			sb.WriteString(s.SpanID)
+		}
+	}
+	return sb.String()
+}
+
+// Tail streams the spans of the tenant matching the spanset filter of the query as they are written to the head blocks
+// of the ingesters, until the context is done or an ingester closes its stream. Each match is sent once, whatever the
+// replication factor. The number of tails of a tenant open at once on the querier is limited by
+// max_concurrent_tail_requests.
+func (q *Querier) Tail(ctx context.Context, req *tempopb.SearchRequest, send func(*tempopb.SearchResponse) error) error {
+	userID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return fmt.Errorf("error extracting org id in Querier.Tail: %w", err)
+	}
+
+	// the ingesters validate the query too, it's checked here to fail before any stream is open
+	if _, err := traceql.NewSpanFilter(req.Query); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidTailQuery, err)
+	}
+
+	if len(q.ingesterRings) == 0 {
+		return errors.New("no ingester rings configured")
+	}
+
+	release, err := q.tails.acquire(userID, q.limits.MaxConcurrentTailRequests(userID))
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	results := make(chan *tempopb.SearchResponse)
+	errs := make(chan error, 1)
+
+	for i, ingesterRing := range q.ingesterRings {
+		replicationSet, err := q.tenantIngesterRing(ingesterRing, userID).GetReplicationSetForOperation(ring.Read)
+		if err != nil {
+			return fmt.Errorf("error getting replication set for ring (%d) in Querier.Tail: %w", i, err)
+		}
+
+		for _, ingester := range replicationSet.Instances {
+			client, err := q.ingesterPools[i].GetClientFor(ingester.Addr)
+			if err != nil {
+				return fmt.Errorf("failed to get client for %s: %w", ingester.Addr, err)
+			}
+			stream, err := client.(tempopb.QuerierClient).Tail(ctx, req)
+			if err != nil {
+				return fmt.Errorf("failed to tail %s: %w", ingester.Addr, err)
+			}
+
+			wg.Add(1)
+			go func(addr string) {
+				defer wg.Done()
+				for {
+					resp, err := stream.Recv()
+					if err != nil {
+						if ctx.Err() == nil {
+							select {
+							case errs <- fmt.Errorf("tail of %s closed: %w", addr, err):
+							default:
+							}
+						}
+						return
+					}
+
+					select {
+					case results <- resp:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}(ingester.Addr)
+		}
+	}
+
+	dedupe := newTailDeduper(tailDedupeSize)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			return err
+		case resp := <-results:
+			resp.Traces = dedupe.filter(resp.Traces)
+			if len(resp.Traces) == 0 {
+				continue
+			}
+			if err := send(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
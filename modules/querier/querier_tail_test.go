@@ -0,0 +1,53 @@
+package querier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func TestTailLimiter(t *testing.T) {
+	var l tailLimiter
+
+	release, err := l.acquire("tenant", 1)
+	require.NoError(t, err)
+
+	_, err = l.acquire("tenant", 1)
+	require.ErrorIs(t, err, errTailLimitExceeded)
+
+	// tenants are limited separately, a limit of 0 disables tailing
+	other, err := l.acquire("other", 1)
+	require.NoError(t, err)
+	other()
+	_, err = l.acquire("disabled", 0)
+	require.ErrorIs(t, err, errTailLimitExceeded)
+
+	release()
+	release, err = l.acquire("tenant", 1)
+	require.NoError(t, err)
+	release()
+	require.Empty(t, l.active)
+}
+
+func TestTailDeduper(t *testing.T) {
+	match := func(traceID string, spanIDs ...string) *tempopb.TraceSearchMetadata {
+		ss := &tempopb.SpanSet{}
+		for _, id := range spanIDs {
+			ss.Spans = append(ss.Spans, &tempopb.Span{SpanID: id})
+		}
+		return &tempopb.TraceSearchMetadata{TraceID: traceID, SpanSets: []*tempopb.SpanSet{ss}}
+	}
+
+	d := newTailDeduper(2)
+
+	// the copies of the replicas are dropped, the spans received later for a trace aren't
+	filtered := d.filter([]*tempopb.TraceSearchMetadata{match("1", "a"), match("1", "a"), match("2", "b")})
+	require.Equal(t, []*tempopb.TraceSearchMetadata{match("1", "a"), match("2", "b")}, filtered)
+	require.Empty(t, d.filter([]*tempopb.TraceSearchMetadata{match("2", "b")}))
+	require.Equal(t, []*tempopb.TraceSearchMetadata{match("2", "c")}, d.filter([]*tempopb.TraceSearchMetadata{match("2", "c")}))
+
+	// the oldest matches are forgotten
+	require.Equal(t, []*tempopb.TraceSearchMetadata{match("1", "a")}, d.filter([]*tempopb.TraceSearchMetadata{match("1", "a")}))
+}
@@ -2,14 +2,20 @@ package querier
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sort"
 	"testing"
+	"time"
 
+	"github.com/grafana/dskit/ring"
 	"github.com/grafana/dskit/user"
 	generator_client "github.com/grafana/tempo/modules/generator/client"
 	ingester_client "github.com/grafana/tempo/modules/ingester/client"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 )
@@ -104,3 +110,87 @@ func TestVirtualTagsDoesntHitBackend(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, resp)
 }
+
+func TestInSample(t *testing.T) {
+	const jobs = 10000
+
+	sampled := 0
+	for i := 0; i < jobs; i++ {
+		blockID := fmt.Sprintf("block-%d", i/10)
+		in := inSample(0.1, blockID, uint32(i%10))
+		// the sample is deterministic
+		require.Equal(t, in, inSample(0.1, blockID, uint32(i%10)))
+		// and grows with the fraction
+		if in {
+			require.True(t, inSample(0.5, blockID, uint32(i%10)))
+			sampled++
+		}
+	}
+
+	require.InDelta(t, jobs/10, sampled, jobs/50)
+}
+
+func TestBlockErrorsWarnings(t *testing.T) {
+	_, unsupported := encoding.FromVersion("v1")
+	failed := errors.New("failed")
+
+	warnings, errs := blockErrorsWarnings([]error{
+		fmt.Errorf("reading block: %w", backend.ErrBlockArchived),
+		unsupported,
+		// archived errors of other components are only known by their text
+		errors.New("rpc error: " + backend.ErrBlockArchived.Error()),
+		failed,
+	})
+	require.Equal(t, []*tempopb.QueryWarning{warningTierPending, warningBlocksSkipped}, warnings)
+	require.Equal(t, []error{failed}, errs)
+
+	require.Nil(t, blockWarning(nil))
+	require.Nil(t, blockWarning(failed))
+}
+
+func TestBlockVersionWarnings(t *testing.T) {
+	require.Nil(t, blockVersionWarnings(encoding.LatestEncoding().Version(), nil))
+	require.Nil(t, blockVersionWarnings("vParquet9", nil))
+	require.Equal(t, []*tempopb.QueryWarning{warningNewerBlock}, blockVersionWarnings("vParquet5", nil))
+	require.Equal(t, []*tempopb.QueryWarning{warningNewerBlock}, blockVersionWarnings("vParquet5", []*tempopb.QueryWarning{warningNewerBlock}))
+}
+
+type ownersRing struct {
+	ring.ReadRing
+	owners ring.ReplicationSet
+	all    ring.ReplicationSet
+}
+
+func (r *ownersRing) Get(uint32, ring.Operation, []ring.InstanceDesc, []string, []string) (ring.ReplicationSet, error) {
+	return r.owners, nil
+}
+
+func (r *ownersRing) GetReplicationSetForOperation(ring.Operation) (ring.ReplicationSet, error) {
+	return r.all, nil
+}
+
+func TestTraceOwnersReplicationSet(t *testing.T) {
+	now := time.Now()
+	instance := func(addr string, registeredAt time.Time) ring.InstanceDesc {
+		return ring.InstanceDesc{Addr: addr, RegisteredTimestamp: registeredAt.Unix()}
+	}
+
+	all := ring.ReplicationSet{Instances: []ring.InstanceDesc{
+		instance("a", now.Add(-2*time.Hour)),
+		instance("b", now.Add(-2*time.Hour)),
+		instance("c", now.Add(-time.Minute)),
+	}}
+	q := &Querier{cfg: Config{QueryRelevantIngestersLookbackPeriod: time.Hour}}
+
+	// stable owners are queried alone
+	r := &ownersRing{owners: ring.ReplicationSet{Instances: all.Instances[:2]}, all: all}
+	rs, err := q.traceOwnersReplicationSet(1)(r)
+	require.NoError(t, err)
+	require.Equal(t, r.owners, rs)
+
+	// an owner that joined recently took the trace over from other ingesters
+	r = &ownersRing{owners: ring.ReplicationSet{Instances: all.Instances[1:]}, all: all}
+	rs, err = q.traceOwnersReplicationSet(1)(r)
+	require.NoError(t, err)
+	require.Equal(t, all, rs)
+}
@@ -0,0 +1,58 @@
+package querier
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+)
+
+var (
+	warningTierPending    = tempopb.NewQueryWarning(tempopb.WarningTierPending, "some blocks are archived and were skipped, they can be searched once they are rehydrated")
+	warningBlocksSkipped  = tempopb.NewQueryWarning(tempopb.WarningBlocksSkipped, "some blocks have a format that isn't supported and were skipped")
+	warningStaleBlocklist = tempopb.NewQueryWarning(tempopb.WarningStaleBlocklist, "the blocklist wasn't polled recently, recent blocks may be missing")
+	warningNewerBlock     = tempopb.NewQueryWarning(tempopb.WarningNewerBlockVersion, "some blocks have a newer format and were searched with the columns supported by this version, results may be incomplete")
+)
+
+// blockWarning returns the warning of an error reading a block that skips the block instead of failing the query,
+// or nil if the error fails the query.
+func blockWarning(err error) *tempopb.QueryWarning {
+	if err == nil {
+		return nil
+	}
+
+	// NOTE: errors of the backend may be received over GRPC, so check the string content of the error as well.
+	if errors.Is(err, backend.ErrBlockArchived) || strings.Contains(err.Error(), backend.ErrBlockArchived.Error()) {
+		return warningTierPending
+	}
+	if errors.Is(err, encoding.ErrUnsupportedVersion) {
+		return warningBlocksSkipped
+	}
+	return nil
+}
+
+// blockErrorsWarnings splits the errors of the blocks of a query in the warnings of skipped blocks and the errors
+// that fail the query.
+func blockErrorsWarnings(errs []error) ([]*tempopb.QueryWarning, []error) {
+	var warnings []*tempopb.QueryWarning
+	var failed []error
+	for _, err := range errs {
+		if w := blockWarning(err); w != nil {
+			warnings = tempopb.AppendWarnings(warnings, w)
+			continue
+		}
+		failed = append(failed, err)
+	}
+	return warnings, failed
+}
+
+// blockVersionWarnings appends the warning of blocks of a newer version to the warnings of the response of a block of
+// the version.
+func blockVersionWarnings(version string, warnings []*tempopb.QueryWarning) []*tempopb.QueryWarning {
+	if !encoding.IsNewerVersion(version) {
+		return warnings
+	}
+	return tempopb.AppendWarnings(warnings, warningNewerBlock)
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"flag"
+	"time"
 
 	"github.com/grafana/tempo/pkg/cache"
 	"github.com/grafana/tempo/pkg/util"
@@ -11,6 +12,7 @@ import (
 	"github.com/grafana/tempo/tempodb/backend/gcs"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/backend/s3"
+	"github.com/grafana/tempo/tempodb/blocklist"
 	"github.com/grafana/tempo/tempodb/encoding"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	"github.com/grafana/tempo/tempodb/pool"
@@ -29,6 +31,11 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	cfg.Trace.BlocklistPollTenantIndexBuilders = tempodb.DefaultTenantIndexBuilders
 	cfg.Trace.BlocklistPollTolerateConsecutiveErrors = tempodb.DefaultTolerateConsecutiveErrors
 	cfg.Trace.BlocklistPollTolerateTenantFailures = tempodb.DefaultTolerateTenantFailures
+	cfg.Trace.BlocklistPollInventory.Format = blocklist.InventoryFormatS3
+	cfg.Trace.BlocklistPollInventory.MaxAge = 48 * time.Hour
+	cfg.Trace.EmptyTenantDeletionConcurrency = tempodb.DefaultEmptyTenantDeletionConcurrency
+	cfg.Trace.EmptyTenantDeletionMaxObjects = tempodb.DefaultEmptyTenantDeletionMaxObjects
+	cfg.Trace.EmptyTenantDeletionMaxTenantsPerPoll = tempodb.DefaultEmptyTenantDeletionMaxTenantsPerPoll
 
 	f.StringVar(&cfg.Trace.Backend, util.PrefixConfig(prefix, "trace.backend"), "", "Trace backend (s3, azure, gcs, local)")
 	f.DurationVar(&cfg.Trace.BlocklistPoll, util.PrefixConfig(prefix, "trace.blocklist_poll"), tempodb.DefaultBlocklistPoll, "Period at which to run the maintenance cycle.")
@@ -58,6 +65,11 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	cfg.Trace.Local = &local.Config{}
 	cfg.Trace.Local.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "trace"), f)
 
+	cfg.Trace.DualWrite.RegisterFlagsAndApplyDefaults(f)
+	cfg.Trace.Tiered.RegisterFlagsAndApplyDefaults(f)
+	cfg.Trace.TenantPartitions.Period = tempodb.DefaultTenantPartitionsPeriod
+	cfg.Trace.BlocklistPollTenantIndexReplica.RegisterFlagsAndApplyDefaults(f)
+
 	cfg.Trace.BackgroundCache = &cache.BackgroundConfig{}
 	cfg.Trace.BackgroundCache.WriteBackBuffer = 10000
 	cfg.Trace.BackgroundCache.WriteBackGoroutines = 10
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/tempodb"
+)
+
+// TenantOwnershipHandler reports which instances own the index building and compaction jobs of each tenant, as seen
+// by this instance. It responds with a table, or with JSON if the request accepts application/json.
+func TenantOwnershipHandler(c tempodb.Compactor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ownership := c.TenantOwnership()
+
+		if r.Header.Get(api.HeaderAccept) == api.HeaderAcceptJSON {
+			w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+			_ = json.NewEncoder(w).Encode(ownership)
+			return
+		}
+
+		x := table.NewWriter()
+		x.AppendHeader(table.Row{
+			"tenant", "index owners", "builder", "takeover", "last poll", "last built", "index created", "heartbeat builder", "heartbeat", "index conflicts",
+			"last compaction cycle", "last compaction", "owned jobs", "other jobs", "compaction conflicts",
+		})
+
+		for _, o := range ownership {
+			row := table.Row{o.Tenant}
+			if idx := o.Index; idx != nil {
+				hbBuilder, hbTime := "", ""
+				if idx.Heartbeat != nil {
+					hbBuilder, hbTime = idx.Heartbeat.Builder, formatTime(idx.Heartbeat.Time)
+				}
+				row = append(row, strings.Join(idx.Owners, ", "), idx.Builder, idx.Takeover, formatTime(idx.LastPoll), formatTime(idx.LastBuilt),
+					formatTime(idx.IndexCreatedAt), hbBuilder, hbTime, idx.Conflicts)
+			} else {
+				row = append(row, "", "", "", "", "", "", "", "", "")
+			}
+			if cmp := o.Compaction; cmp != nil {
+				row = append(row, formatTime(cmp.LastCycle), formatTime(cmp.LastCompaction), cmp.OwnedJobs, cmp.OtherJobs, cmp.Conflicts)
+			} else {
+				row = append(row, "", "", "", "", "")
+			}
+			x.AppendRow(row)
+		}
+
+		x.AppendSeparator()
+
+		w.Header().Set(api.HeaderContentType, "text/plain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, x.Render())
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -25,7 +25,8 @@ import (
 )
 
 const (
-	urlParamTraceID = "traceID"
+	urlParamTraceID  = "traceID"
+	urlParamTraceIDs = "traceIDs"
 	// search
 	urlParamQuery           = "q"
 	urlParamTags            = "tags"
@@ -39,6 +40,7 @@ const (
 	urlParamSince           = "since"
 	urlParamExemplars       = "exemplars"
 	URLParamRF1After        = "rf1After"
+	urlParamOrderBy         = "order_by"
 	urlMaxSeries            = "maxSeries"
 
 	// backend search querier
@@ -56,6 +58,9 @@ const (
 
 	// search tags
 	urlParamScope = "scope"
+	// tag values paging
+	URLParamAfter    = "after"
+	URLParamPageSize = "pageSize"
 
 	// generator summary
 	urlParamGroupBy = "groupBy"
@@ -73,6 +78,7 @@ const (
 	PathSearch              = "/api/search"
 	PathSearchTags          = "/api/search/tags"
 	PathSearchTagValues     = "/api/search/tag/{" + MuxVarTagName + "}/values"
+	PathTail                = "/api/tail"
 	PathEcho                = "/api/echo"
 	PathBuildInfo           = "/api/status/buildinfo"
 	PathUsageStats          = "/status/usage-stats"
@@ -80,7 +86,11 @@ const (
 	PathSpanMetricsSummary  = "/api/metrics/summary"
 	PathMetricsQueryInstant = "/api/metrics/query"
 	PathMetricsQueryRange   = "/api/metrics/query_range"
+	PathMetricsRemoteRead   = "/api/metrics/read"
 	PathMCP                 = "/api/mcp"
+	PathTraceUI             = "/ui/trace/{traceID}"
+	PathTraceQLParse        = "/api/traceql/parse"
+	PathTraceQLValidate     = "/api/traceql/validate"
 
 	// PathOverrides user configurable overrides
 	PathOverrides = "/api/overrides"
@@ -88,6 +98,7 @@ const (
 	PathSearchTagValuesV2 = "/api/v2/search/tag/{" + MuxVarTagName + "}/values"
 	PathSearchTagsV2      = "/api/v2/search/tags"
 	PathTracesV2          = "/api/v2/traces/{traceID}"
+	PathTracesByIDs       = "/api/v2/traces"
 
 	QueryModeKey       = "mode"
 	QueryModeIngesters = "ingesters"
@@ -96,6 +107,9 @@ const (
 	BlockStartKey      = "blockStart"
 	BlockEndKey        = "blockEnd"
 
+	// MaxTraceIDsPerRequest is the maximum number of trace ids of a trace by ids request
+	MaxTraceIDsPerRequest = 100
+
 	defaultLimit           = 20
 	defaultSpansPerSpanSet = 3
 	defaultSince           = 1 * time.Hour
@@ -116,6 +130,35 @@ func ParseTraceID(r *http.Request) ([]byte, error) {
 	return byteID, nil
 }
 
+// ParseTraceIDs returns the comma separated trace ids of the traceIDs query parameter, in the requested order
+// and without duplicates
+func ParseTraceIDs(r *http.Request) ([][]byte, error) {
+	traceIDs, ok := extractQueryParam(r.URL.Query(), urlParamTraceIDs)
+	if !ok {
+		return nil, fmt.Errorf("please provide traceIDs")
+	}
+
+	seen := map[string]struct{}{}
+	var byteIDs [][]byte
+	for _, traceID := range strings.Split(traceIDs, ",") {
+		byteID, err := util.HexStringToTraceID(strings.TrimSpace(traceID))
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[string(byteID)]; ok {
+			continue
+		}
+		seen[string(byteID)] = struct{}{}
+		byteIDs = append(byteIDs, byteID)
+	}
+
+	if len(byteIDs) > MaxTraceIDsPerRequest {
+		return nil, fmt.Errorf("too many traceIDs: %d, the maximum is %d", len(byteIDs), MaxTraceIDsPerRequest)
+	}
+
+	return byteIDs, nil
+}
+
 // ParseSearchRequest takes an http.Request and decodes query params to create a tempopb.SearchRequest
 func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 	req := &tempopb.SearchRequest{
@@ -183,7 +226,7 @@ func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 		// As Grafana gets updated and/or versions using this get old we can remove this section.
 		for k, v := range vals {
 			// Skip reserved keywords
-			if k == urlParamQuery || k == urlParamTags || k == urlParamMinDuration || k == urlParamMaxDuration || k == urlParamLimit || k == urlParamSpansPerSpanSet || k == urlParamStart || k == urlParamEnd {
+			if k == urlParamQuery || k == urlParamTags || k == urlParamMinDuration || k == urlParamMaxDuration || k == urlParamLimit || k == urlParamSpansPerSpanSet || k == urlParamStart || k == urlParamEnd || k == urlParamOrderBy {
 				continue
 			}
 
@@ -243,6 +286,13 @@ func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 		req.RF1After = t
 	}
 
+	if s, ok := extractQueryParam(vals, urlParamOrderBy); ok {
+		if _, err := traceql.ParseSearchOrder(s); err != nil {
+			return nil, fmt.Errorf("invalid order_by: %w", err)
+		}
+		req.OrderBy = s
+	}
+
 	// start and end == 0 is fine
 	if req.End == 0 && req.Start == 0 {
 		return req, nil
@@ -518,6 +568,21 @@ func BuildQueryRequest(req *http.Request, queryParams map[string]string) *http.R
 	return req
 }
 
+// ParseQueryTextAndRange returns the query and the time range of a request to the query api. Missing or invalid
+// parts of the range are returned as zero times.
+func ParseQueryTextAndRange(r *http.Request) (string, time.Time, time.Time) {
+	vals := r.URL.Query()
+
+	query, ok := extractQueryParam(vals, urlParamQuery)
+	if !ok {
+		query, _ = extractQueryParam(vals, urlParamTags)
+	}
+	start, _ := parseTimestamp(vals.Get(urlParamStart), time.Time{})
+	end, _ := parseTimestamp(vals.Get(urlParamEnd), time.Time{})
+
+	return query, start, end
+}
+
 func bounds(vals url.Values) (time.Time, time.Time, error) {
 	var (
 		now      = time.Now()
@@ -660,6 +725,10 @@ func BuildSearchRequest(req *http.Request, searchReq *tempopb.SearchRequest) (*h
 		qb.addParam(URLParamRF1After, searchReq.RF1After.Format(time.RFC3339))
 	}
 
+	if searchReq.OrderBy != "" {
+		qb.addParam(urlParamOrderBy, searchReq.OrderBy)
+	}
+
 	req.URL.RawQuery = qb.query()
 
 	return req, nil
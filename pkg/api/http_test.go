@@ -24,6 +24,43 @@ func TestEquality(t *testing.T) {
 	assert.Equal(t, HeaderAcceptProtobuf, tempo.ProtobufTypeHeaderValue)
 }
 
+func TestParseTraceIDs(t *testing.T) {
+	tests := []struct {
+		urlQuery string
+		expected [][]byte
+		err      string
+	}{
+		{
+			urlQuery: "",
+			err:      "please provide traceIDs",
+		},
+		{
+			urlQuery: "traceIDs=0a,01,0a",
+			expected: [][]byte{
+				{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x0a},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01},
+			},
+		},
+		{
+			urlQuery: "traceIDs=0a,zz",
+			err:      "trace IDs can only contain hex characters: invalid character 'z' at position 1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.urlQuery, func(t *testing.T) {
+			r := httptest.NewRequest("GET", PathTracesByIDs+"?"+tc.urlQuery, nil)
+			ids, err := ParseTraceIDs(r)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, ids)
+		})
+	}
+}
+
 func TestQuerierParseSearchRequest(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -205,6 +242,21 @@ func TestQuerierParseSearchRequest(t *testing.T) {
 				SpansPerSpanSet: 7,
 			},
 		},
+		{
+			name:     "order by attribute",
+			urlQuery: "q=" + url.QueryEscape("{}") + "&order_by=span.http.status_code",
+			expected: &tempopb.SearchRequest{
+				Tags:            map[string]string{},
+				Query:           "{}",
+				SpansPerSpanSet: defaultSpansPerSpanSet,
+				OrderBy:         "span.http.status_code",
+			},
+		},
+		{
+			name:     "invalid order by",
+			urlQuery: "order_by=name",
+			err:      "invalid order_by: can't order by name, use duration, start_time, root_service, root_name or an attribute",
+		},
 	}
 
 	for _, tt := range tests {
@@ -599,6 +651,15 @@ func TestBuildSearchRequest(t *testing.T) {
 			},
 			query: "?start=10&end=20&limit=50&maxDuration=30ms&tags=foo%3Dbar",
 		},
+		{
+			req: &tempopb.SearchRequest{
+				Query:   "{}",
+				Start:   10,
+				End:     20,
+				OrderBy: "duration",
+			},
+			query: "?start=10&end=20&q=%7B%7D&order_by=duration",
+		},
 		{
 			req: &tempopb.SearchRequest{
 				Tags: map[string]string{
@@ -682,6 +743,48 @@ func Test_parseTimestamp(t *testing.T) {
 	}
 }
 
+func TestParseQueryTextAndRange(t *testing.T) {
+	tcs := []struct {
+		name          string
+		url           string
+		expectedQuery string
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			name: "empty",
+			url:  "/api/traces/1234",
+		},
+		{
+			name:          "search",
+			url:           "/api/search?q=%7B%20.foo%20%3D%20%60bar%60%20%7D&start=1571332130&end=1571335730",
+			expectedQuery: "{ .foo = `bar` }",
+			expectedStart: time.Unix(1571332130, 0),
+			expectedEnd:   time.Unix(1571335730, 0),
+		},
+		{
+			name:          "tags",
+			url:           "/api/search?tags=foo%3Dbar",
+			expectedQuery: "foo=bar",
+		},
+		{
+			name:          "metrics",
+			url:           "/api/metrics/query_range?q=%7B%7D%20%7C%20rate()&start=1571334162051000000&end=invalid",
+			expectedQuery: "{} | rate()",
+			expectedStart: time.Unix(0, 1571334162051000000),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			query, start, end := ParseQueryTextAndRange(httptest.NewRequest("GET", tc.url, nil))
+			assert.Equal(t, tc.expectedQuery, query)
+			assert.Equal(t, tc.expectedStart, start)
+			assert.Equal(t, tc.expectedEnd, end)
+		})
+	}
+}
+
 func TestQueryRangeRoundtrip(t *testing.T) {
 	tcs := []struct {
 		name string
@@ -433,6 +433,21 @@ func parseSearchTagValuesRequest(r *http.Request, enforceTraceQL bool) (*tempopb
 		req.RF1After = t
 	}
 
+	if s, ok := extractQueryParam(vals, URLParamPageSize); ok {
+		pageSize, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pageSize: %w", err)
+		}
+		req.PageSize = uint32(pageSize)
+	}
+
+	if s, ok := extractQueryParam(vals, URLParamAfter); ok {
+		if req.PageSize == 0 {
+			return nil, errors.New("after requires a pageSize")
+		}
+		req.After = s
+	}
+
 	return req, nil
 }
 
@@ -544,6 +559,12 @@ func BuildSearchTagValuesRequest(req *http.Request, searchReq *tempopb.SearchTag
 	qb.addParam(urlParamStart, strconv.FormatUint(uint64(searchReq.Start), 10))
 	qb.addParam(urlParamEnd, strconv.FormatUint(uint64(searchReq.End), 10))
 	qb.addParam(urlParamQuery, searchReq.Query)
+	if searchReq.PageSize > 0 {
+		qb.addParam(URLParamPageSize, strconv.FormatUint(uint64(searchReq.PageSize), 10))
+	}
+	if searchReq.After != "" {
+		qb.addParam(URLParamAfter, searchReq.After)
+	}
 
 	req.URL.RawQuery = qb.query()
 
@@ -112,3 +112,59 @@ func TestParseSearchTagsRequest(t *testing.T) {
 		require.Equal(t, tc.scope, req.Scope)
 	}
 }
+
+func TestParseSearchTagValuesRequestPaging(t *testing.T) {
+	tcs := []struct {
+		query            string
+		expectError      bool
+		expectedPageSize uint32
+		expectedAfter    string
+	}{
+		{
+			query: "",
+		},
+		{
+			query:            "pageSize=10",
+			expectedPageSize: 10,
+		},
+		{
+			query:            "pageSize=10&after=abc",
+			expectedPageSize: 10,
+			expectedAfter:    "abc",
+		},
+		{
+			query:       "after=abc",
+			expectError: true,
+		},
+		{
+			query:       "pageSize=blerg",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			httpReq := httptest.NewRequest("GET", "http://tempo/api/v2/search/tag/span.foo/values?"+tc.query, nil)
+			r := mux.SetURLVars(httpReq, map[string]string{MuxVarTagName: "span.foo"})
+
+			req, err := ParseSearchTagValuesRequestV2(r)
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedPageSize, req.PageSize)
+			require.Equal(t, tc.expectedAfter, req.After)
+
+			// the paging params survive a round trip to the queriers
+			built, err := BuildSearchTagValuesRequest(nil, req)
+			require.NoError(t, err)
+			built = mux.SetURLVars(built, map[string]string{MuxVarTagName: "span.foo"})
+
+			parsed, err := ParseSearchTagValuesRequestV2(built)
+			require.NoError(t, err)
+			require.Equal(t, req.PageSize, parsed.PageSize)
+			require.Equal(t, req.After, parsed.After)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package collector
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 )
 
@@ -22,6 +23,12 @@ type DistinctValue[T comparable] struct {
 	diffEnabled      bool
 	stopReason       string
 	mtx              sync.Mutex
+
+	// paging, set by NewPagedDistinctValue
+	less      func(a, b T) bool
+	after     *T
+	page      []T
+	truncated bool
 }
 
 // NewDistinctValue with the given maximum data size and values limited.
@@ -53,6 +60,23 @@ func NewDistinctValueWithDiff[T comparable](maxDataSize int, maxValues uint32, s
 	}
 }
 
+// NewPagedDistinctValue keeps the pageSize smallest values ordered by less that are greater than after, or all values
+// if after is nil. Values that don't fit in the page or in maxDataSize are dropped and Truncated reports true.
+// Every value has to be seen to know which are the smallest so a paged collector never exceeds its limits and doesn't
+// stop early. Diff is always enabled and returns the values that are in the page and weren't returned before.
+func NewPagedDistinctValue[T comparable](maxDataSize int, pageSize uint32, after *T, less func(a, b T) bool, len func(T) int) *DistinctValue[T] {
+	return &DistinctValue[T]{
+		values:      make(map[T]struct{}),
+		new:         make(map[T]struct{}),
+		maxDataSize: maxDataSize,
+		diffEnabled: true,
+		len:         len,
+		maxValues:   pageSize,
+		less:        less,
+		after:       after,
+	}
+}
+
 // Collect adds a new value to the distinct value collector.
 // return true when it reaches the limits and can't fit more values.
 // callers of return of Collect or call Exceeded to stop early.
@@ -60,6 +84,11 @@ func (d *DistinctValue[T]) Collect(v T) (exceeded bool) {
 	d.mtx.Lock()
 	defer d.mtx.Unlock()
 
+	if d.less != nil {
+		d.collectPaged(v)
+		return false
+	}
+
 	if d.limExceeded {
 		return true
 	}
@@ -100,11 +129,46 @@ func (d *DistinctValue[T]) Collect(v T) (exceeded bool) {
 	return false
 }
 
+func (d *DistinctValue[T]) collectPaged(v T) {
+	if d.after != nil && !d.less(*d.after, v) {
+		return
+	}
+	if _, ok := d.values[v]; ok {
+		return
+	}
+
+	i := sort.Search(len(d.page), func(i int) bool { return d.less(v, d.page[i]) })
+	d.page = append(d.page, v)
+	copy(d.page[i+1:], d.page[i:])
+	d.page[i] = v
+
+	d.values[v] = struct{}{}
+	d.new[v] = struct{}{}
+	d.currDataSize += d.len(v)
+	d.currentValuesLen++
+
+	// drop the greatest values until the page fits. at least one value is kept so paging always makes progress
+	for len(d.page) > 1 && ((d.maxValues > 0 && d.currentValuesLen > d.maxValues) || (d.maxDataSize > 0 && d.currDataSize > d.maxDataSize)) {
+		last := d.page[len(d.page)-1]
+		d.page = d.page[:len(d.page)-1]
+
+		delete(d.values, last)
+		delete(d.new, last)
+		d.currDataSize -= d.len(last)
+		d.currentValuesLen--
+		d.truncated = true
+	}
+}
+
 // Values returns the final list of distinct values collected and sorted.
 func (d *DistinctValue[T]) Values() []T {
 	d.mtx.Lock()
 	defer d.mtx.Unlock()
 
+	if d.less != nil {
+		return append([]T(nil), d.page...)
+	}
+
 	ss := make([]T, 0, len(d.values))
 	for k := range d.values {
 		ss = append(ss, k)
@@ -122,6 +186,24 @@ func (d *DistinctValue[T]) Exceeded() bool {
 	return d.limExceeded
 }
 
+// Truncated indicates that a paged collector dropped values that didn't fit in the page.
+// The values after the last one of Values can be requested as the next page.
+func (d *DistinctValue[T]) Truncated() bool {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	return d.truncated
+}
+
+// MarkTruncated records that values were dropped before they reached the collector, e.g. by a paged collector
+// of another component whose values are combined into this one.
+func (d *DistinctValue[T]) MarkTruncated() {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	d.truncated = true
+}
+
 func (d *DistinctValue[T]) StopReason() string {
 	return d.stopReason
 }
@@ -0,0 +1,80 @@
+package collector
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+var errInvalidTagValuesToken = errors.New("invalid tag values page token")
+
+// NewTagValuesV2 returns the collector of a SearchTagValuesV2 request. Requests with a page size collect one page of
+// values in order, the others collect up to the max tag values of the request in any order.
+func NewTagValuesV2(maxDataSize int, req *tempopb.SearchTagValuesRequest) (*DistinctValue[tempopb.TagValue], error) {
+	if req.PageSize == 0 {
+		return NewDistinctValue(maxDataSize, req.MaxTagValues, req.StaleValueThreshold, tagValueLen), nil
+	}
+	return NewPagedTagValues(maxDataSize, req.PageSize, req.After)
+}
+
+// NewPagedTagValues returns a collector of the page of pageSize tag values that follows the after token.
+// An empty token starts at the first page.
+func NewPagedTagValues(maxDataSize int, pageSize uint32, after string) (*DistinctValue[tempopb.TagValue], error) {
+	var afterValue *tempopb.TagValue
+	if after != "" {
+		v, err := ParseTagValuesToken(after)
+		if err != nil {
+			return nil, err
+		}
+		afterValue = &v
+	}
+
+	return NewPagedDistinctValue(maxDataSize, pageSize, afterValue, lessTagValue, tagValueLen), nil
+}
+
+// NextTagValuesToken returns the token of the page that follows the values of d, or an empty string if d isn't
+// truncated.
+func NextTagValuesToken(d *DistinctValue[tempopb.TagValue]) string {
+	if !d.Truncated() {
+		return ""
+	}
+
+	values := d.Values()
+	if len(values) == 0 {
+		return ""
+	}
+	return TagValuesToken(values[len(values)-1])
+}
+
+// TagValuesToken encodes the last tag value of a page into an opaque token.
+func TagValuesToken(v tempopb.TagValue) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(v.Type + ":" + v.Value))
+}
+
+// ParseTagValuesToken decodes a token returned by TagValuesToken.
+func ParseTagValuesToken(token string) (tempopb.TagValue, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return tempopb.TagValue{}, errInvalidTagValuesToken
+	}
+
+	typ, value, ok := strings.Cut(string(b), ":")
+	if !ok {
+		return tempopb.TagValue{}, errInvalidTagValuesToken
+	}
+	return tempopb.TagValue{Type: typ, Value: value}, nil
+}
+
+// lessTagValue orders tag values by value and then by type
+func lessTagValue(a, b tempopb.TagValue) bool {
+	if a.Value != b.Value {
+		return a.Value < b.Value
+	}
+	return a.Type < b.Type
+}
+
+func tagValueLen(v tempopb.TagValue) int {
+	return len(v.Type) + len(v.Value)
+}
@@ -0,0 +1,121 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func TestPagedDistinctValue(t *testing.T) {
+	d := NewPagedDistinctValue(0, 2, nil, func(a, b string) bool { return a < b }, func(s string) int { return len(s) })
+
+	require.False(t, d.Collect("c"))
+	require.False(t, d.Collect("a"))
+	require.False(t, d.Truncated())
+	require.Equal(t, []string{"a", "c"}, d.Values())
+
+	// smaller values push the greatest out of the page, the collector never stops early
+	require.False(t, d.Collect("b"))
+	require.False(t, d.Collect("d"))
+	require.False(t, d.Collect("a"))
+	require.False(t, d.Exceeded())
+	require.True(t, d.Truncated())
+	require.Equal(t, []string{"a", "b"}, d.Values())
+	require.Equal(t, 2, d.Size())
+
+	// values that left the page aren't part of the diff
+	diff, err := d.Diff()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"a", "b"}, diff)
+
+	// the next page starts after the last value
+	after := "b"
+	d = NewPagedDistinctValue(0, 2, &after, func(a, b string) bool { return a < b }, func(s string) int { return len(s) })
+	for _, v := range []string{"d", "a", "b", "c"} {
+		d.Collect(v)
+	}
+	require.False(t, d.Truncated())
+	require.Equal(t, []string{"c", "d"}, d.Values())
+}
+
+func TestPagedDistinctValueMaxDataSize(t *testing.T) {
+	d := NewPagedDistinctValue(5, 0, nil, func(a, b string) bool { return a < b }, func(s string) int { return len(s) })
+
+	d.Collect("ccc")
+	d.Collect("bb")
+	d.Collect("a")
+	require.True(t, d.Truncated())
+	require.Equal(t, []string{"a", "bb"}, d.Values())
+
+	// a value larger than the max data size is still returned so paging makes progress
+	d = NewPagedDistinctValue(2, 0, nil, func(a, b string) bool { return a < b }, func(s string) int { return len(s) })
+	d.Collect("zzz")
+	require.Equal(t, []string{"zzz"}, d.Values())
+}
+
+func TestTagValuesV2Paging(t *testing.T) {
+	collect := func(req *tempopb.SearchTagValuesRequest, values []tempopb.TagValue) ([]tempopb.TagValue, string) {
+		d, err := NewTagValuesV2(0, req)
+		require.NoError(t, err)
+		for _, v := range values {
+			d.Collect(v)
+		}
+		return d.Values(), NextTagValuesToken(d)
+	}
+
+	values := []tempopb.TagValue{
+		{Type: "string", Value: "c"},
+		{Type: "int", Value: "1"},
+		{Type: "string", Value: "a"},
+		{Type: "string", Value: "b:c"},
+		{Type: "int", Value: "a"},
+	}
+
+	// read all values one page at a time
+	var (
+		all   []tempopb.TagValue
+		token string
+	)
+	for {
+		page, next := collect(&tempopb.SearchTagValuesRequest{PageSize: 2, After: token}, values)
+		all = append(all, page...)
+		if next == "" {
+			break
+		}
+		token = next
+	}
+
+	require.Equal(t, []tempopb.TagValue{
+		{Type: "int", Value: "1"},
+		{Type: "int", Value: "a"},
+		{Type: "string", Value: "a"},
+		{Type: "string", Value: "b:c"},
+		{Type: "string", Value: "c"},
+	}, all)
+
+	// requests without a page size aren't paged
+	page, next := collect(&tempopb.SearchTagValuesRequest{}, values)
+	require.ElementsMatch(t, values, page)
+	require.Empty(t, next)
+
+	_, err := NewTagValuesV2(0, &tempopb.SearchTagValuesRequest{PageSize: 2, After: "not a token"})
+	require.Error(t, err)
+}
+
+func TestTagValuesToken(t *testing.T) {
+	for _, v := range []tempopb.TagValue{
+		{Type: "string", Value: "foo"},
+		{Type: "string", Value: "a:b:c"},
+		{Type: "string", Value: ""},
+		{Type: "int", Value: "123"},
+	} {
+		parsed, err := ParseTagValuesToken(TagValuesToken(v))
+		require.NoError(t, err)
+		require.Equal(t, v, parsed)
+	}
+
+	_, err := ParseTagValuesToken("!!")
+	require.Error(t, err)
+}
@@ -0,0 +1,451 @@
+// Package embedded runs Tempo in process, for integration tests and small tools. Traces are pushed directly to the
+// WAL of their tenant, without distributors or ingesters, flushed to blocks in a local backend and queried by ID or
+// with TraceQL.
+package embedded
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/validation"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/wal"
+)
+
+const defaultSearchLimit = 20
+
+// Config of an embedded Tempo.
+type Config struct {
+	// Path is the folder of the WAL and of the blocks. Required.
+	Path string
+	// MaxBlockDuration is how long traces are appended to the head block of a tenant before it's flushed to a
+	// block. 0 only flushes the head blocks with Flush and Stop.
+	MaxBlockDuration time.Duration
+	// Block configures the flushed blocks. The defaults of Tempo and the latest encoding are used if nil.
+	Block *common.BlockConfig
+}
+
+// Tempo is an embedded Tempo. Pushed traces can be queried right away.
+type Tempo struct {
+	cfg    Config
+	logger log.Logger
+
+	db               tempodb.Reader
+	r                backend.Reader
+	w                tempodb.Writer
+	engine           *traceql.Engine
+	dedicatedColumns backend.DedicatedColumns
+
+	mtx     sync.RWMutex
+	tenants map[string]*tenant
+	started bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type tenant struct {
+	head        common.WALBlock
+	headCreated time.Time
+	blocks      []common.BackendBlock
+}
+
+// New creates an embedded Tempo storing its data in cfg.Path. It must be started before traces are pushed.
+func New(cfg Config, logger log.Logger) (*Tempo, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("path is required")
+	}
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+
+	block := cfg.Block
+	if block == nil {
+		block = &common.BlockConfig{}
+		block.RegisterFlagsAndApplyDefaults("", flag.NewFlagSet("", flag.ContinueOnError))
+		block.Version = encoding.LatestEncoding().Version()
+	}
+
+	localCfg := &local.Config{Path: filepath.Join(cfg.Path, "blocks")}
+	db, w, _, err := tempodb.New(&tempodb.Config{
+		Backend: backend.Local,
+		Local:   localCfg,
+		Block:   block,
+		WAL: &wal.Config{
+			Filepath: filepath.Join(cfg.Path, "wal"),
+			Encoding: backend.EncNone,
+		},
+	}, nil, logger)
+	if err != nil {
+		return nil, fmt.Errorf("error creating tempodb: %w", err)
+	}
+
+	rawR, _, _, err := local.New(localCfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating local backend: %w", err)
+	}
+
+	return &Tempo{
+		cfg:              cfg,
+		logger:           logger,
+		db:               db,
+		r:                backend.NewReader(rawR),
+		w:                w,
+		engine:           traceql.NewEngine(),
+		dedicatedColumns: block.DedicatedColumns,
+		tenants:          map[string]*tenant{},
+	}, nil
+}
+
+// Start opens the blocks written by previous runs in the path, flushes the traces left in the WAL and starts
+// flushing the head blocks every MaxBlockDuration.
+func (t *Tempo) Start(ctx context.Context) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.started {
+		return errors.New("already started")
+	}
+
+	if err := t.openBlocks(ctx); err != nil {
+		return err
+	}
+
+	replayed, err := t.w.WAL().RescanBlocks(0, t.logger)
+	if err != nil {
+		return fmt.Errorf("error replaying wal: %w", err)
+	}
+	for _, b := range replayed {
+		if err := t.completeBlock(ctx, t.tenant(b.BlockMeta().TenantID), b); err != nil {
+			return fmt.Errorf("error flushing replayed wal block %s: %w", b.BlockMeta().BlockID, err)
+		}
+	}
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	t.done = make(chan struct{})
+	t.started = true
+
+	go t.flushLoop(loopCtx)
+	return nil
+}
+
+// Stop flushes the head blocks and stops the embedded Tempo. It can't be started again.
+func (t *Tempo) Stop() error {
+	t.mtx.Lock()
+	if !t.started {
+		t.mtx.Unlock()
+		return nil
+	}
+	t.started = false
+	t.mtx.Unlock()
+
+	t.cancel()
+	<-t.done
+
+	defer t.db.Shutdown()
+	return t.Flush(context.Background())
+}
+
+// Push appends the spans of tr to the head block of the tenant, grouped by trace ID.
+func (t *Tempo) Push(_ context.Context, tenantID string, tr *tempopb.Trace) error {
+	if tenantID == "" {
+		return errors.New("tenant id is required")
+	}
+
+	traces, err := tracesByID(tr)
+	if err != nil {
+		return err
+	}
+	if len(traces) == 0 {
+		return nil
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if !t.started {
+		return errors.New("not started")
+	}
+
+	tn := t.tenant(tenantID)
+	if tn.head == nil {
+		meta := &backend.BlockMeta{
+			BlockID:          backend.NewUUID(),
+			TenantID:         tenantID,
+			DedicatedColumns: t.dedicatedColumns,
+		}
+		head, err := t.w.WAL().NewBlock(meta, model.CurrentEncoding)
+		if err != nil {
+			return fmt.Errorf("error creating head block: %w", err)
+		}
+		tn.head, tn.headCreated = head, time.Now()
+	}
+
+	for _, pushed := range traces {
+		if err := tn.head.AppendTrace(pushed.id, pushed.trace, pushed.start, pushed.end, false); err != nil {
+			return fmt.Errorf("error appending trace: %w", err)
+		}
+	}
+
+	// the pages of the head block are only read once flushed
+	return tn.head.Flush()
+}
+
+// Flush flushes the head blocks of all tenants to blocks.
+func (t *Tempo) Flush(ctx context.Context) error {
+	return t.flush(ctx, func(*tenant) bool { return true })
+}
+
+// FindTraceByID returns the trace of the tenant combined from all blocks, nil if it's not found.
+func (t *Tempo) FindTraceByID(ctx context.Context, tenantID string, id []byte) (*tempopb.Trace, error) {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	combiner := trace.NewCombiner(0, true)
+	for _, b := range t.blocksOf(tenantID) {
+		resp, err := b.FindTraceByID(ctx, id, common.DefaultSearchOptions())
+		if err != nil {
+			return nil, fmt.Errorf("error finding trace in block %s: %w", b.BlockMeta().BlockID, err)
+		}
+		if resp == nil || resp.Trace == nil {
+			continue
+		}
+		if _, err := combiner.Consume(resp.Trace); err != nil {
+			return nil, err
+		}
+	}
+
+	tr, _ := combiner.Result()
+	return tr, nil
+}
+
+// Search runs the TraceQL query of the request on all blocks of the tenant.
+func (t *Tempo) Search(ctx context.Context, tenantID string, req *tempopb.SearchRequest) (*tempopb.SearchResponse, error) {
+	if req.Query == "" {
+		return nil, errors.New("a TraceQL query is required")
+	}
+
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	var (
+		combiner = traceql.NewMetadataCombiner(limit, false)
+		metrics  = &tempopb.SearchMetrics{}
+		opts     = common.DefaultSearchOptions()
+	)
+	for _, b := range t.blocksOf(tenantID) {
+		resp, err := t.engine.ExecuteSearch(ctx, req, traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+			return b.Fetch(ctx, req, opts)
+		}))
+		if err != nil {
+			return nil, fmt.Errorf("error searching block %s: %w", b.BlockMeta().BlockID, err)
+		}
+
+		if resp.Metrics != nil {
+			metrics.InspectedTraces += resp.Metrics.InspectedTraces
+			metrics.InspectedBytes += resp.Metrics.InspectedBytes
+		}
+		for _, tr := range resp.Traces {
+			combiner.AddMetadata(tr)
+		}
+	}
+
+	return &tempopb.SearchResponse{
+		Traces:  combiner.Metadata(),
+		Metrics: metrics,
+	}, nil
+}
+
+func (t *Tempo) flushLoop(ctx context.Context) {
+	defer close(t.done)
+
+	if t.cfg.MaxBlockDuration <= 0 {
+		<-ctx.Done()
+		return
+	}
+
+	ticker := time.NewTicker(t.cfg.MaxBlockDuration / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			cutoff := time.Now().Add(-t.cfg.MaxBlockDuration)
+			err := t.flush(ctx, func(tn *tenant) bool { return tn.headCreated.Before(cutoff) })
+			if err != nil {
+				level.Error(t.logger).Log("msg", "failed to flush head blocks", "err", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (t *Tempo) flush(ctx context.Context, shouldFlush func(*tenant) bool) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	var errs []error
+	for tenantID, tn := range t.tenants {
+		if tn.head == nil || !shouldFlush(tn) {
+			continue
+		}
+		if err := t.completeBlock(ctx, tn, tn.head); err != nil {
+			errs = append(errs, fmt.Errorf("error flushing head block of tenant %s: %w", tenantID, err))
+			continue
+		}
+		tn.head = nil
+	}
+	return errors.Join(errs...)
+}
+
+// completeBlock writes the WAL block to a block of the tenant and clears it. Must be called with the lock held.
+func (t *Tempo) completeBlock(ctx context.Context, tn *tenant, b common.WALBlock) error {
+	complete, err := t.w.CompleteBlock(ctx, b)
+	if err != nil {
+		return err
+	}
+	tn.blocks = append(tn.blocks, complete)
+	return b.Clear()
+}
+
+// openBlocks opens the blocks of all tenants in the backend. Must be called with the lock held.
+func (t *Tempo) openBlocks(ctx context.Context) error {
+	tenantIDs, err := t.r.Tenants(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing tenants: %w", err)
+	}
+
+	for _, tenantID := range tenantIDs {
+		blockIDs, _, err := t.r.Blocks(ctx, tenantID)
+		if err != nil {
+			return fmt.Errorf("error listing blocks of tenant %s: %w", tenantID, err)
+		}
+
+		for _, blockID := range blockIDs {
+			meta, err := t.r.BlockMeta(ctx, blockID, tenantID)
+			if errors.Is(err, backend.ErrDoesNotExist) {
+				// partially written
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("error reading meta of block %s: %w", blockID, err)
+			}
+
+			b, err := encoding.OpenBlock(meta, t.r)
+			if err != nil {
+				return fmt.Errorf("error opening block %s: %w", blockID, err)
+			}
+			tn := t.tenant(tenantID)
+			tn.blocks = append(tn.blocks, b)
+		}
+	}
+	return nil
+}
+
+// tenant returns the tenant, created if it doesn't exist. Must be called with the lock held.
+func (t *Tempo) tenant(tenantID string) *tenant {
+	tn, ok := t.tenants[tenantID]
+	if !ok {
+		tn = &tenant{}
+		t.tenants[tenantID] = tn
+	}
+	return tn
+}
+
+// blocksOf returns the head block and the blocks of the tenant. Must be called with the lock held.
+func (t *Tempo) blocksOf(tenantID string) []common.BackendBlock {
+	tn, ok := t.tenants[tenantID]
+	if !ok {
+		return nil
+	}
+
+	blocks := make([]common.BackendBlock, 0, len(tn.blocks)+1)
+	if tn.head != nil {
+		blocks = append(blocks, tn.head)
+	}
+	return append(blocks, tn.blocks...)
+}
+
+type pushedTrace struct {
+	id         []byte
+	trace      *tempopb.Trace
+	start, end uint32
+}
+
+// tracesByID splits the spans of tr by trace ID, keeping their resources and scopes. The traces are in the order
+// of their first span.
+func tracesByID(tr *tempopb.Trace) ([]*pushedTrace, error) {
+	var (
+		traces []*pushedTrace
+		byID   = map[string]*pushedTrace{}
+	)
+	for _, rs := range tr.GetResourceSpans() {
+		// resource and scope spans of each trace in this resource
+		resources := map[*pushedTrace]*v1.ResourceSpans{}
+
+		for _, ss := range rs.ScopeSpans {
+			scopes := map[*pushedTrace]*v1.ScopeSpans{}
+
+			for _, span := range ss.Spans {
+				if !validation.ValidTraceID(span.TraceId) {
+					return nil, fmt.Errorf("trace ids must be 128 bit, received %d bits", len(span.TraceId)*8)
+				}
+
+				pushed, ok := byID[string(span.TraceId)]
+				if !ok {
+					pushed = &pushedTrace{
+						id:    bytes.Clone(span.TraceId),
+						trace: &tempopb.Trace{},
+						start: ^uint32(0),
+					}
+					byID[string(span.TraceId)] = pushed
+					traces = append(traces, pushed)
+				}
+
+				resource, ok := resources[pushed]
+				if !ok {
+					resource = &v1.ResourceSpans{Resource: rs.Resource, SchemaUrl: rs.SchemaUrl}
+					resources[pushed] = resource
+					pushed.trace.ResourceSpans = append(pushed.trace.ResourceSpans, resource)
+				}
+
+				scope, ok := scopes[pushed]
+				if !ok {
+					scope = &v1.ScopeSpans{Scope: ss.Scope, SchemaUrl: ss.SchemaUrl}
+					scopes[pushed] = scope
+					resource.ScopeSpans = append(resource.ScopeSpans, scope)
+				}
+				scope.Spans = append(scope.Spans, span)
+
+				start, end := uint32(span.StartTimeUnixNano/uint64(time.Second)), uint32(span.EndTimeUnixNano/uint64(time.Second))
+				pushed.start = min(pushed.start, start)
+				pushed.end = max(pushed.end, end)
+			}
+		}
+	}
+	return traces, nil
+}
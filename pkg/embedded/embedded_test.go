@@ -0,0 +1,104 @@
+package embedded
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func spanCount(tr *tempopb.Trace) int {
+	count := 0
+	for _, rs := range tr.GetResourceSpans() {
+		for _, ss := range rs.ScopeSpans {
+			count += len(ss.Spans)
+		}
+	}
+	return count
+}
+
+func TestTempo(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+
+	tempo, err := New(Config{Path: path}, nil)
+	require.NoError(t, err)
+	require.Error(t, tempo.Push(ctx, "test", test.MakeTrace(1, nil)), "not started")
+	require.NoError(t, tempo.Start(ctx))
+
+	// the spans of another trace pushed together are split
+	id, other := test.ValidTraceID(nil), test.ValidTraceID(nil)
+	first, second, third := test.MakeTrace(2, id), test.MakeTrace(1, id), test.MakeTrace(1, other)
+	pushed := &tempopb.Trace{ResourceSpans: append(first.ResourceSpans, third.ResourceSpans...)}
+	require.NoError(t, tempo.Push(ctx, "test", pushed))
+
+	// found in the head block
+	tr, err := tempo.FindTraceByID(ctx, "test", id)
+	require.NoError(t, err)
+	require.Equal(t, spanCount(first), spanCount(tr))
+
+	// and combined with the flushed block
+	require.NoError(t, tempo.Flush(ctx))
+	require.NoError(t, tempo.Push(ctx, "test", second))
+	tr, err = tempo.FindTraceByID(ctx, "test", id)
+	require.NoError(t, err)
+	require.Equal(t, spanCount(first)+spanCount(second), spanCount(tr))
+
+	tr, err = tempo.FindTraceByID(ctx, "other", id)
+	require.NoError(t, err)
+	require.Nil(t, tr)
+
+	resp, err := tempo.Search(ctx, "test", &tempopb.SearchRequest{Query: "{ }", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, resp.Traces, 2)
+	require.ElementsMatch(t, []string{util.TraceIDToHexString(id), util.TraceIDToHexString(other)}, []string{resp.Traces[0].TraceID, resp.Traces[1].TraceID})
+
+	// the traces left in the wal of a crashed instance are flushed on start, tempo isn't stopped
+	replayed, err := New(Config{Path: path}, nil)
+	require.NoError(t, err)
+	require.NoError(t, replayed.Start(ctx))
+	tr, err = replayed.FindTraceByID(ctx, "test", id)
+	require.NoError(t, err)
+	require.Equal(t, spanCount(first)+spanCount(second), spanCount(tr))
+
+	// the pushed traces are flushed on stop and the blocks opened on start
+	require.NoError(t, replayed.Push(ctx, "test", test.MakeTrace(1, other)))
+	require.NoError(t, replayed.Stop())
+
+	restarted, err := New(Config{Path: path}, nil)
+	require.NoError(t, err)
+	require.NoError(t, restarted.Start(ctx))
+	defer func() { require.NoError(t, restarted.Stop()) }()
+
+	tr, err = restarted.FindTraceByID(ctx, "test", id)
+	require.NoError(t, err)
+	require.Equal(t, spanCount(first)+spanCount(second), spanCount(tr))
+	tr, err = restarted.FindTraceByID(ctx, "test", other)
+	require.NoError(t, err)
+	require.Greater(t, spanCount(tr), spanCount(third))
+}
+
+func TestTracesByID(t *testing.T) {
+	id, other := test.ValidTraceID(nil), test.ValidTraceID(nil)
+
+	tr := test.MakeTrace(1, id)
+	tr.ResourceSpans[0].ScopeSpans[0].Spans = append(tr.ResourceSpans[0].ScopeSpans[0].Spans, test.MakeSpan(other))
+
+	traces, err := tracesByID(tr)
+	require.NoError(t, err)
+	require.Len(t, traces, 2)
+	require.Equal(t, id, traces[0].id)
+	require.Equal(t, other, traces[1].id)
+	require.Equal(t, spanCount(tr)-1, spanCount(traces[0].trace))
+	require.Equal(t, 1, spanCount(traces[1].trace))
+	require.Equal(t, tr.ResourceSpans[0].Resource, traces[1].trace.ResourceSpans[0].Resource)
+	require.LessOrEqual(t, traces[1].start, traces[1].end)
+
+	tr.ResourceSpans[0].ScopeSpans[0].Spans[0].TraceId = []byte{0x01}
+	_, err = tracesByID(tr)
+	require.Error(t, err)
+}
@@ -47,6 +47,10 @@ var (
 type Config struct {
 	Enabled bool        `yaml:"enabled"`
 	Kafka   KafkaConfig `yaml:"kafka"`
+
+	// IngestersConsume makes the ingesters consume the traces of their partition from Kafka instead of receiving
+	// them from the distributors, so ingest spikes are buffered in Kafka.
+	IngestersConsume bool `yaml:"ingesters_consume"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
@@ -0,0 +1,228 @@
+package trace
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+// DefaultCollisionMinGap is the default minimum time between distinct traces that share a trace ID.
+const DefaultCollisionMinGap = time.Hour
+
+// CollisionConfig configures the detection of distinct traces that share a trace ID at query time.
+type CollisionConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Split returns the distinct traces as separate results instead of combining them.
+	Split bool `yaml:"split"`
+	// MinGap is the minimum time between the spans of two traces for them to be distinct.
+	MinGap time.Duration `yaml:"min_gap"`
+}
+
+// collisionTree is a tree of spans linked by their parent span IDs, or a set of trees merged in the same trace.
+type collisionTree struct {
+	spans    []*v1.Span
+	services []string
+	hasRoot  bool
+	start    uint64
+	end      uint64
+}
+
+func (t *collisionTree) merge(o *collisionTree) {
+	t.spans = append(t.spans, o.spans...)
+	for _, s := range o.services {
+		if !slices.Contains(t.services, s) {
+			t.services = append(t.services, s)
+		}
+	}
+	t.hasRoot = t.hasRoot || o.hasRoot
+	t.start = min(t.start, o.start)
+	t.end = max(t.end, o.end)
+}
+
+// distinct returns true if the trees are distinct traces: they have no service in common and their spans are at
+// least minGap apart.
+func (t *collisionTree) distinct(o *collisionTree, minGap time.Duration) bool {
+	for _, s := range o.services {
+		if slices.Contains(t.services, s) {
+			return false
+		}
+	}
+
+	gap := uint64(minGap.Nanoseconds())
+	return o.start >= t.end+gap || t.start >= o.end+gap
+}
+
+// SplitCollisions splits a trace in the distinct traces that share its trace ID, ordered by start time. Spans are
+// grouped in trees by their parent span IDs. Trees with root spans are distinct traces if they have no service in
+// common and their spans are at least minGap apart, trees without a root span are added to the closest trace. The
+// trace itself is returned if there's no collision.
+func SplitCollisions(tr *tempopb.Trace, minGap time.Duration) []*tempopb.Trace {
+	if tr == nil {
+		return nil
+	}
+
+	trees := spanTrees(tr)
+
+	// trees of the same trace are merged until all traces are distinct
+	var traces, orphans []*collisionTree
+	for _, t := range trees {
+		if !t.hasRoot {
+			orphans = append(orphans, t)
+			continue
+		}
+		traces = append(traces, t)
+	}
+	for merged := true; merged; {
+		merged = false
+		for i := 0; i < len(traces) && !merged; i++ {
+			for j := i + 1; j < len(traces); j++ {
+				if !traces[i].distinct(traces[j], minGap) {
+					traces[i].merge(traces[j])
+					traces = slices.Delete(traces, j, j+1)
+					merged = true
+					break
+				}
+			}
+		}
+	}
+
+	if len(traces) < 2 {
+		return []*tempopb.Trace{tr}
+	}
+
+	for _, o := range orphans {
+		closest := traces[0]
+		for _, t := range traces[1:] {
+			if distance(t, o) < distance(closest, o) {
+				closest = t
+			}
+		}
+		closest.merge(o)
+	}
+
+	sort.Slice(traces, func(i, j int) bool { return traces[i].start < traces[j].start })
+
+	// the group of each span, spans are unique in the trace
+	groups := make(map[*v1.Span]int)
+	for i, t := range traces {
+		for _, s := range t.spans {
+			groups[s] = i
+		}
+	}
+
+	split := make([]*tempopb.Trace, len(traces))
+	for i := range split {
+		split[i] = &tempopb.Trace{}
+	}
+	for _, rs := range tr.ResourceSpans {
+		byGroup := map[int]*v1.ResourceSpans{}
+		for _, ss := range rs.ScopeSpans {
+			scopeByGroup := map[int]*v1.ScopeSpans{}
+			for _, s := range ss.Spans {
+				g := groups[s]
+				groupSS, ok := scopeByGroup[g]
+				if !ok {
+					groupRS, ok := byGroup[g]
+					if !ok {
+						groupRS = &v1.ResourceSpans{Resource: rs.Resource, SchemaUrl: rs.SchemaUrl}
+						byGroup[g] = groupRS
+						split[g].ResourceSpans = append(split[g].ResourceSpans, groupRS)
+					}
+					groupSS = &v1.ScopeSpans{Scope: ss.Scope, SchemaUrl: ss.SchemaUrl}
+					scopeByGroup[g] = groupSS
+					groupRS.ScopeSpans = append(groupRS.ScopeSpans, groupSS)
+				}
+				groupSS.Spans = append(groupSS.Spans, s)
+			}
+		}
+	}
+
+	return split
+}
+
+// spanTrees returns the trees of the spans of the trace.
+func spanTrees(tr *tempopb.Trace) []*collisionTree {
+	type node struct {
+		span    *v1.Span
+		service string
+		parent  int
+	}
+
+	var nodes []node
+	byID := map[string]int{}
+	for _, rs := range tr.ResourceSpans {
+		service := ""
+		if rs.Resource != nil {
+			for _, attr := range rs.Resource.Attributes {
+				if attr.Key == "service.name" {
+					service = attr.Value.GetStringValue()
+					break
+				}
+			}
+		}
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				byID[string(s.SpanId)] = len(nodes)
+				nodes = append(nodes, node{span: s, service: service, parent: -1})
+			}
+		}
+	}
+
+	// union find of the nodes by their parents
+	for i := range nodes {
+		if p, ok := byID[string(nodes[i].span.ParentSpanId)]; ok && len(nodes[i].span.ParentSpanId) > 0 && p != i {
+			nodes[i].parent = p
+		}
+	}
+	roots := make([]int, len(nodes))
+	var find func(i int) int
+	find = func(i int) int {
+		if roots[i] != i {
+			roots[i] = find(roots[i])
+		}
+		return roots[i]
+	}
+	for i := range roots {
+		roots[i] = i
+	}
+	for i, n := range nodes {
+		if n.parent >= 0 {
+			roots[find(i)] = find(n.parent)
+		}
+	}
+
+	treeOf := map[int]*collisionTree{}
+	var trees []*collisionTree
+	for i, n := range nodes {
+		r := find(i)
+		t, ok := treeOf[r]
+		if !ok {
+			t = &collisionTree{start: n.span.StartTimeUnixNano, end: n.span.EndTimeUnixNano}
+			treeOf[r] = t
+			trees = append(trees, t)
+		}
+		t.merge(&collisionTree{
+			spans:    []*v1.Span{n.span},
+			services: []string{n.service},
+			hasRoot:  len(n.span.ParentSpanId) == 0,
+			start:    n.span.StartTimeUnixNano,
+			end:      n.span.EndTimeUnixNano,
+		})
+	}
+	return trees
+}
+
+// distance returns the time between the spans of two trees, 0 if they overlap.
+func distance(t, o *collisionTree) uint64 {
+	switch {
+	case o.start > t.end:
+		return o.start - t.end
+	case t.start > o.end:
+		return t.start - o.end
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,109 @@
+package trace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func collisionBatch(service string, spans ...*v1.Span) *v1.ResourceSpans {
+	return &v1.ResourceSpans{
+		Resource: &v1_resource.Resource{
+			Attributes: []*v1_common.KeyValue{{Key: "service.name", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: service}}}},
+		},
+		ScopeSpans: []*v1.ScopeSpans{{Spans: spans}},
+	}
+}
+
+func collisionSpan(id, parent byte, start time.Duration) *v1.Span {
+	s := &v1.Span{
+		SpanId:            []byte{0, 0, 0, 0, 0, 0, 0, id},
+		StartTimeUnixNano: uint64(start.Nanoseconds()),
+		EndTimeUnixNano:   uint64((start + time.Second).Nanoseconds()),
+	}
+	if parent != 0 {
+		s.ParentSpanId = []byte{0, 0, 0, 0, 0, 0, 0, parent}
+	}
+	return s
+}
+
+func spanIDs(tr *tempopb.Trace) []byte {
+	var ids []byte
+	for _, rs := range tr.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				ids = append(ids, s.SpanId[7])
+			}
+		}
+	}
+	return ids
+}
+
+func TestSplitCollisions(t *testing.T) {
+	day := 24 * time.Hour
+
+	tcs := []struct {
+		name     string
+		trace    *tempopb.Trace
+		expected [][]byte
+	}{
+		{
+			name: "single trace",
+			trace: &tempopb.Trace{ResourceSpans: []*v1.ResourceSpans{
+				collisionBatch("a", collisionSpan(1, 0, 0), collisionSpan(2, 1, time.Second)),
+				collisionBatch("b", collisionSpan(3, 2, 2*time.Second)),
+			}},
+			expected: [][]byte{{1, 2, 3}},
+		},
+		{
+			name: "distant roots of distinct services",
+			trace: &tempopb.Trace{ResourceSpans: []*v1.ResourceSpans{
+				collisionBatch("b", collisionSpan(3, 0, day), collisionSpan(4, 3, day)),
+				collisionBatch("a", collisionSpan(1, 0, 0), collisionSpan(2, 1, time.Second)),
+			}},
+			expected: [][]byte{{1, 2}, {3, 4}},
+		},
+		{
+			name: "roots of the same service",
+			trace: &tempopb.Trace{ResourceSpans: []*v1.ResourceSpans{
+				collisionBatch("a", collisionSpan(1, 0, 0), collisionSpan(2, 0, day)),
+			}},
+			expected: [][]byte{{1, 2}},
+		},
+		{
+			name: "close roots of distinct services",
+			trace: &tempopb.Trace{ResourceSpans: []*v1.ResourceSpans{
+				collisionBatch("a", collisionSpan(1, 0, 0)),
+				collisionBatch("b", collisionSpan(2, 0, time.Minute)),
+			}},
+			expected: [][]byte{{1, 2}},
+		},
+		{
+			name: "orphans join the closest trace",
+			trace: &tempopb.Trace{ResourceSpans: []*v1.ResourceSpans{
+				collisionBatch("a", collisionSpan(1, 0, 0)),
+				collisionBatch("b", collisionSpan(2, 0, day)),
+				collisionBatch("c", collisionSpan(3, 9, day+time.Minute), collisionSpan(4, 8, time.Minute)),
+			}},
+			expected: [][]byte{{1, 4}, {2, 3}},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			traces := SplitCollisions(tc.trace, time.Hour)
+			require.Len(t, traces, len(tc.expected))
+			for i, tr := range traces {
+				require.ElementsMatch(t, tc.expected[i], spanIDs(tr))
+			}
+		})
+	}
+
+	require.Nil(t, SplitCollisions(nil, time.Hour))
+}
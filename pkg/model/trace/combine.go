@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 )
 
 // token is uint64 to reduce hash collision rates.  Experimentally, it was observed
@@ -49,6 +50,9 @@ type Combiner struct {
 	maxSizeBytes        int
 	allowPartialTrace   bool
 	maxTraceSizeReached bool
+
+	// number of resource spans in result already returned by NewResourceSpans
+	streamed int
 }
 
 // It creates a new Trace combiner. If maxSizeBytes is 0, the final trace size is not checked
@@ -179,6 +183,22 @@ func (c *Combiner) Result() (*tempopb.Trace, int) {
 	return c.result, spanCount
 }
 
+// NewResourceSpans returns the resource spans added to the combined trace since the previous call.
+// It allows a trace to be streamed while it is being combined. Result sorts the trace in place so
+// it must not be called until streaming is complete.
+func (c *Combiner) NewResourceSpans() []*v1.ResourceSpans {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.result == nil {
+		return nil
+	}
+
+	rs := c.result.ResourceSpans[c.streamed:]
+	c.streamed = len(c.result.ResourceSpans)
+	return rs
+}
+
 // Returns true if the combined trace is a partial one if partal trace is enabled
 func (c *Combiner) IsPartialTrace() bool {
 	return c.maxTraceSizeReached && c.allowPartialTrace
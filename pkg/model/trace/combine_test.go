@@ -122,6 +122,32 @@ func TestCombinerParallel(t *testing.T) {
 	wg.Wait()
 }
 
+func TestCombinerNewResourceSpans(t *testing.T) {
+	c := NewCombiner(0, false)
+	require.Nil(t, c.NewResourceSpans())
+
+	traceA := test.MakeTraceWithSpanCount(2, 10, []byte{0x01})
+	traceB := test.MakeTraceWithSpanCount(3, 10, []byte{0x01})
+	dupe := &tempopb.Trace{ResourceSpans: traceA.ResourceSpans[:1]}
+
+	_, err := c.Consume(traceA)
+	require.NoError(t, err)
+	require.Len(t, c.NewResourceSpans(), 2)
+	require.Empty(t, c.NewResourceSpans())
+
+	// already combined spans are not returned again
+	_, err = c.Consume(dupe)
+	require.NoError(t, err)
+	require.Empty(t, c.NewResourceSpans())
+
+	_, err = c.Consume(traceB)
+	require.NoError(t, err)
+	require.Len(t, c.NewResourceSpans(), 3)
+
+	_, spanCount := c.Result()
+	require.Equal(t, 50, spanCount)
+}
+
 func TestTokenForIDCollision(t *testing.T) {
 	// Estimate the hash collision rate of tokenForID.
 
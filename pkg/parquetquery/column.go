@@ -7,6 +7,13 @@ type ColumnChunkHelper struct {
 	pages     parquet.Pages
 	firstPage parquet.Page
 	err       error
+
+	// skipDictionaryPages is set by iterators whose whole predicate keeps the values with KeepDictionaryPages, the
+	// dictionary and its entries kept by the predicate are only remembered then.
+	skipDictionaryPages bool
+	dictionary          parquet.Dictionary
+	dictionaryKept      []bool
+	nullKept            bool
 }
 
 // Dictionary makes it easier to access the dictionary for this column chunk which
@@ -51,6 +58,78 @@ func (h *ColumnChunkHelper) NextPage() (parquet.Page, error) {
 	return h.pages.ReadPage()
 }
 
+// KeepStatistics uses the min, max and null count statistics of the column chunk in the file footer to check if the
+// chunk may have values kept by a predicate, before reading the dictionary or the column index. It returns false if
+// the bounds of the chunk are not kept by keepRange and its nulls, if any, are not kept by keepValue. Column chunks
+// without statistics are kept.
+func (h *ColumnChunkHelper) KeepStatistics(keepRange func(min, max parquet.Value) bool, keepValue func(parquet.Value) bool) bool {
+	fc, ok := h.ColumnChunk.(*parquet.FileColumnChunk)
+	if !ok {
+		return true
+	}
+
+	nullCount := fc.NullCount()
+	if nullCount > 0 && keepValue(parquet.Value{}) {
+		return true
+	}
+	if nullCount == fc.NumValues() {
+		// all nulls
+		return false
+	}
+
+	min, max, ok := fc.Bounds()
+	if !ok {
+		return true
+	}
+	return keepRange(min, max)
+}
+
+// KeepDictionaryPages inspects all values of the dictionary like keepDictionary and returns if any matches were
+// found. The matching entries are remembered so KeepDictionaryPage can skip the pages of the column chunk that
+// don't reference any of them, without reading their values.
+func (h *ColumnChunkHelper) KeepDictionaryPages(dict parquet.Dictionary, keepValue func(parquet.Value) bool) bool {
+	if !h.skipDictionaryPages {
+		return keepDictionary(dict, keepValue)
+	}
+
+	kept := make([]bool, dict.Len())
+	found := false
+	for i := range kept {
+		if keepValue(dict.Index(int32(i))) {
+			kept[i] = true
+			found = true
+		}
+	}
+
+	if found {
+		h.dictionary = dict
+		h.dictionaryKept = kept
+		h.nullKept = keepValue(parquet.Value{})
+	}
+	return found
+}
+
+// KeepDictionaryPage returns false if the page is encoded with the dictionary inspected by KeepDictionaryPages and
+// none of its values are matching entries. The dictionary indexes of the page are checked, its values are never
+// looked up. Pages are kept if KeepDictionaryPages wasn't called for this column chunk.
+func (h *ColumnChunkHelper) KeepDictionaryPage(pg parquet.Page) bool {
+	if h.dictionaryKept == nil || pg.Dictionary() != h.dictionary {
+		return true
+	}
+
+	if h.nullKept && pg.NumNulls() > 0 {
+		return true
+	}
+
+	data := pg.Data()
+	for _, i := range data.Int32() {
+		if h.dictionaryKept[i] {
+			return true
+		}
+	}
+	return false
+}
+
 func (h *ColumnChunkHelper) Close() error {
 	if h.firstPage != nil {
 		parquet.Release(h.firstPage)
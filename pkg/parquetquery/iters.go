@@ -385,6 +385,9 @@ type SyncIterator struct {
 	rgsMax     []RowNumber // Exclusive, row number of next one past the row group
 	readSize   int
 	filter     Predicate
+	// skipDictionaryPages is true if the filter keeps the dictionary entries of the column chunks with
+	// ColumnChunkHelper.KeepDictionaryPages
+	skipDictionaryPages bool
 
 	// Status
 	span            trace.Span
@@ -406,6 +409,10 @@ type SyncIterator struct {
 
 	intern   bool
 	interner *intern.Interner
+
+	// Stats
+	pagesSkipped           int64
+	dictionaryPagesSkipped int64
 }
 
 var _ Iterator = (*SyncIterator)(nil)
@@ -448,6 +455,8 @@ func NewSyncIterator(ctx context.Context, rgs []pq.RowGroup, column int, opts ..
 		opt(i)
 	}
 
+	i.skipDictionaryPages = i.filter != nil && keepsDictionaryPages(i.filter)
+
 	if i.selectAs != "" {
 		// Preallocate 1 entry with the given name.
 		i.at.Entries = []struct {
@@ -554,7 +563,7 @@ func (c *SyncIterator) seekRowGroup(seekTo RowNumber, definitionLevel int) (done
 			continue
 		}
 
-		cc := &ColumnChunkHelper{ColumnChunk: rg.ColumnChunks()[c.column]}
+		cc := &ColumnChunkHelper{ColumnChunk: rg.ColumnChunks()[c.column], skipDictionaryPages: c.skipDictionaryPages}
 		if c.filter != nil && !c.filter.KeepColumnChunk(cc) {
 			cc.Close()
 			continue
@@ -616,7 +625,7 @@ func (c *SyncIterator) seekPages(seekTo RowNumber, definitionLevel int) (done bo
 			}
 
 			// Skip based on filter?
-			if c.filter != nil && !c.filter.KeepPage(pg) {
+			if c.filter != nil && !c.keepPage(pg) {
 				c.curr.Skip(pg.NumRows())
 				pq.Release(pg)
 				continue
@@ -712,7 +721,7 @@ func (c *SyncIterator) next() (RowNumber, *pq.Value, error) {
 				return EmptyRowNumber(), nil, nil
 			}
 
-			cc := &ColumnChunkHelper{ColumnChunk: rg.ColumnChunks()[c.column]}
+			cc := &ColumnChunkHelper{ColumnChunk: rg.ColumnChunks()[c.column], skipDictionaryPages: c.skipDictionaryPages}
 			if c.filter != nil && !c.filter.KeepColumnChunk(cc) {
 				cc.Close()
 				continue
@@ -731,7 +740,7 @@ func (c *SyncIterator) next() (RowNumber, *pq.Value, error) {
 				c.closeCurrRowGroup()
 				continue
 			}
-			if c.filter != nil && !c.filter.KeepPage(pg) {
+			if c.filter != nil && !c.keepPage(pg) {
 				// This page filtered out
 				c.curr.Skip(pg.NumRows())
 				pq.Release(pg)
@@ -778,6 +787,20 @@ func (c *SyncIterator) next() (RowNumber, *pq.Value, error) {
 	}
 }
 
+// keepPage checks the page against the dictionary entries kept for the current column chunk, then against the
+// filter.
+func (c *SyncIterator) keepPage(pg pq.Page) bool {
+	if !c.currChunk.KeepDictionaryPage(pg) {
+		c.dictionaryPagesSkipped++
+		return false
+	}
+	if !c.filter.KeepPage(pg) {
+		c.pagesSkipped++
+		return false
+	}
+	return true
+}
+
 func (c *SyncIterator) setRowGroup(rg pq.RowGroup, min, max RowNumber, cc *ColumnChunkHelper) {
 	c.closeCurrRowGroup()
 	c.curr = min
@@ -854,6 +877,10 @@ func (c *SyncIterator) makeResult(t RowNumber, v *pq.Value) *IteratorResult {
 func (c *SyncIterator) Close() {
 	c.closeCurrRowGroup()
 
+	c.span.SetAttributes(
+		attribute.Int64("pagesSkipped", c.pagesSkipped),
+		attribute.Int64("dictionaryPagesSkipped", c.dictionaryPagesSkipped),
+	)
 	c.span.End()
 
 	if c.intern && c.interner != nil {
@@ -176,6 +176,145 @@ func TestNewRegexNotInPredicate(t *testing.T) {
 // KeepColumnChunk on all of its children. This is important because the
 // Dictionary predicates rely on KeepColumnChunk always being called at the
 // beginning of a row group to reset their page.
+type testOptionalInt struct {
+	I *int64 `parquet:",optional"`
+}
+
+func TestColumnChunkStatistics(t *testing.T) {
+	i := func(v int64) *int64 { return &v }
+
+	// three row groups: [200, 201], [500, null] and [null, null]
+	writeData := func(w *parquet.Writer) { //nolint:all
+		require.NoError(t, w.Write(&testOptionalInt{i(200)}))
+		require.NoError(t, w.Write(&testOptionalInt{i(201)}))
+		require.NoError(t, w.Flush())
+		require.NoError(t, w.Write(&testOptionalInt{i(500)}))
+		require.NoError(t, w.Write(&testOptionalInt{}))
+		require.NoError(t, w.Flush())
+		require.NoError(t, w.Write(&testOptionalInt{}))
+		require.NoError(t, w.Write(&testOptionalInt{}))
+	}
+
+	testCases := []predicateTestCase{
+		{
+			testName:   "chunks out of range and with only nulls are skipped",
+			predicate:  NewIntGreaterEqualPredicate(500),
+			keptChunks: 1,
+			keptPages:  1,
+			keptValues: 1,
+			writeData:  writeData,
+		},
+		{
+			// the column index of [500, null] skips the chunk
+			testName:   "chunks with only nulls are kept if the predicate keeps nulls",
+			predicate:  NewIntLessPredicate(300),
+			keptChunks: 2,
+			keptPages:  2,
+			keptValues: 4,
+			writeData:  writeData,
+		},
+		{
+			testName:   "between",
+			predicate:  NewIntBetweenPredicate(300, 400),
+			keptChunks: 0,
+			keptPages:  0,
+			keptValues: 0,
+			writeData:  writeData,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.testName, func(t *testing.T) {
+			testPredicate(t, tC)
+		})
+	}
+
+	// the statistics in the footer skip the chunks before the column index is read
+	buf := new(bytes.Buffer)
+	w := parquet.NewWriter(buf)
+	writeData(w)
+	require.NoError(t, w.Close())
+
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()), parquet.SkipPageIndex(true))
+	require.NoError(t, err)
+
+	var kept []bool
+	for _, rg := range f.RowGroups() {
+		pred := NewIntGreaterEqualPredicate(500)
+		kept = append(kept, (&ColumnChunkHelper{ColumnChunk: rg.ColumnChunks()[0]}).KeepStatistics(pred.keepRange, pred.KeepValue))
+	}
+	require.Equal(t, []bool{false, true, false}, kept)
+}
+
+func TestDictionaryPages(t *testing.T) {
+	// one row group with a dictionary and pages of a few values: a page of "a", a page of "b" and a page of "a"
+	buf := new(bytes.Buffer)
+	w := parquet.NewGenericWriter[testDictString](buf, parquet.PageBufferSize(8))
+	for _, s := range []string{"a", "a", "b", "b", "a", "a"} {
+		_, err := w.Write([]testDictString{{s}})
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	ci, err := f.RowGroups()[0].ColumnChunks()[0].ColumnIndex()
+	require.NoError(t, err)
+	require.Equal(t, 3, ci.NumPages())
+
+	tcs := []struct {
+		name                   string
+		predicate              Predicate
+		expected               []int32
+		keptPages              int64
+		dictionaryPagesSkipped int64
+	}{
+		{
+			name:                   "equal",
+			predicate:              NewStringEqualPredicate([]byte("b")),
+			expected:               []int32{2, 3},
+			keptPages:              1,
+			dictionaryPagesSkipped: 2,
+		},
+		{
+			name:                   "in",
+			predicate:              NewStringInPredicate([]string{"b", "c"}),
+			expected:               []int32{2, 3},
+			keptPages:              1,
+			dictionaryPagesSkipped: 2,
+		},
+		{
+			// the entries kept by one predicate of an or aren't all the entries kept
+			name:      "or",
+			predicate: NewOrPredicate(NewStringEqualPredicate([]byte("b")), NewStringEqualPredicate([]byte("a"))),
+			expected:  []int32{0, 1, 2, 3, 4, 5},
+			keptPages: 3,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &InstrumentedPredicate{Pred: tc.predicate}
+			iter := NewSyncIterator(context.TODO(), f.RowGroups(), 0, SyncIteratorOptPredicate(p))
+			defer iter.Close()
+
+			var actual []int32
+			for {
+				res, err := iter.Next()
+				require.NoError(t, err)
+				if res == nil {
+					break
+				}
+				actual = append(actual, res.RowNumber[0])
+			}
+
+			require.Equal(t, tc.expected, actual)
+			require.Equal(t, tc.keptPages, p.KeptPages)
+			require.Equal(t, tc.dictionaryPagesSkipped, iter.dictionaryPagesSkipped)
+		})
+	}
+}
+
 func TestOrPredicateCallsKeepColumnChunk(t *testing.T) {
 	tcs := []struct {
 		preds []*mockPredicate
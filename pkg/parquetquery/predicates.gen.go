@@ -25,6 +25,10 @@ func (p IntEqualPredicate) String() string {
 }
 
 func (p IntEqualPredicate) KeepColumnChunk(c *ColumnChunkHelper) bool {
+	if !c.KeepStatistics(p.keepRange, p.KeepValue) {
+		return false
+	}
+
 	if d := c.Dictionary(); d != nil {
 		return keepDictionary(d, p.KeepValue)
 	}
@@ -56,6 +60,13 @@ func (p IntEqualPredicate) KeepPage(page pq.Page) bool {
 	return true
 }
 
+func (p IntEqualPredicate) keepRange(minV, maxV pq.Value) bool {
+	min := minV.Int64()
+	max := maxV.Int64()
+
+	return min <= p.value && p.value <= max
+}
+
 func (p IntEqualPredicate) KeepValue(v pq.Value) bool {
 	vv := v.Int64()
 	return vv == p.value
@@ -76,6 +87,10 @@ func (p IntNotEqualPredicate) String() string {
 }
 
 func (p IntNotEqualPredicate) KeepColumnChunk(c *ColumnChunkHelper) bool {
+	if !c.KeepStatistics(p.keepRange, p.KeepValue) {
+		return false
+	}
+
 	if d := c.Dictionary(); d != nil {
 		return keepDictionary(d, p.KeepValue)
 	}
@@ -107,6 +122,13 @@ func (p IntNotEqualPredicate) KeepPage(page pq.Page) bool {
 	return true
 }
 
+func (p IntNotEqualPredicate) keepRange(minV, maxV pq.Value) bool {
+	min := minV.Int64()
+	max := maxV.Int64()
+
+	return min != p.value || p.value != max
+}
+
 func (p IntNotEqualPredicate) KeepValue(v pq.Value) bool {
 	vv := v.Int64()
 	return vv != p.value
@@ -127,6 +149,10 @@ func (p IntGreaterPredicate) String() string {
 }
 
 func (p IntGreaterPredicate) KeepColumnChunk(c *ColumnChunkHelper) bool {
+	if !c.KeepStatistics(p.keepRange, p.KeepValue) {
+		return false
+	}
+
 	if d := c.Dictionary(); d != nil {
 		return keepDictionary(d, p.KeepValue)
 	}
@@ -158,6 +184,13 @@ func (p IntGreaterPredicate) KeepPage(page pq.Page) bool {
 	return true
 }
 
+func (p IntGreaterPredicate) keepRange(_, maxV pq.Value) bool {
+	
+	max := maxV.Int64()
+
+	return max > p.value
+}
+
 func (p IntGreaterPredicate) KeepValue(v pq.Value) bool {
 	vv := v.Int64()
 	return vv > p.value
@@ -178,6 +211,10 @@ func (p IntGreaterEqualPredicate) String() string {
 }
 
 func (p IntGreaterEqualPredicate) KeepColumnChunk(c *ColumnChunkHelper) bool {
+	if !c.KeepStatistics(p.keepRange, p.KeepValue) {
+		return false
+	}
+
 	if d := c.Dictionary(); d != nil {
 		return keepDictionary(d, p.KeepValue)
 	}
@@ -209,6 +246,13 @@ func (p IntGreaterEqualPredicate) KeepPage(page pq.Page) bool {
 	return true
 }
 
+func (p IntGreaterEqualPredicate) keepRange(_, maxV pq.Value) bool {
+	
+	max := maxV.Int64()
+
+	return max >= p.value
+}
+
 func (p IntGreaterEqualPredicate) KeepValue(v pq.Value) bool {
 	vv := v.Int64()
 	return vv >= p.value
@@ -229,6 +273,10 @@ func (p IntLessPredicate) String() string {
 }
 
 func (p IntLessPredicate) KeepColumnChunk(c *ColumnChunkHelper) bool {
+	if !c.KeepStatistics(p.keepRange, p.KeepValue) {
+		return false
+	}
+
 	if d := c.Dictionary(); d != nil {
 		return keepDictionary(d, p.KeepValue)
 	}
@@ -260,6 +308,13 @@ func (p IntLessPredicate) KeepPage(page pq.Page) bool {
 	return true
 }
 
+func (p IntLessPredicate) keepRange(minV, _ pq.Value) bool {
+	min := minV.Int64()
+	
+
+	return min < p.value
+}
+
 func (p IntLessPredicate) KeepValue(v pq.Value) bool {
 	vv := v.Int64()
 	return vv < p.value
@@ -280,6 +335,10 @@ func (p IntLessEqualPredicate) String() string {
 }
 
 func (p IntLessEqualPredicate) KeepColumnChunk(c *ColumnChunkHelper) bool {
+	if !c.KeepStatistics(p.keepRange, p.KeepValue) {
+		return false
+	}
+
 	if d := c.Dictionary(); d != nil {
 		return keepDictionary(d, p.KeepValue)
 	}
@@ -311,6 +370,13 @@ func (p IntLessEqualPredicate) KeepPage(page pq.Page) bool {
 	return true
 }
 
+func (p IntLessEqualPredicate) keepRange(minV, _ pq.Value) bool {
+	min := minV.Int64()
+	
+
+	return min <= p.value
+}
+
 func (p IntLessEqualPredicate) KeepValue(v pq.Value) bool {
 	vv := v.Int64()
 	return vv <= p.value
@@ -331,6 +397,10 @@ func (p FloatEqualPredicate) String() string {
 }
 
 func (p FloatEqualPredicate) KeepColumnChunk(c *ColumnChunkHelper) bool {
+	if !c.KeepStatistics(p.keepRange, p.KeepValue) {
+		return false
+	}
+
 	if d := c.Dictionary(); d != nil {
 		return keepDictionary(d, p.KeepValue)
 	}
@@ -362,6 +432,13 @@ func (p FloatEqualPredicate) KeepPage(page pq.Page) bool {
 	return true
 }
 
+func (p FloatEqualPredicate) keepRange(minV, maxV pq.Value) bool {
+	min := minV.Double()
+	max := maxV.Double()
+
+	return min <= p.value && p.value <= max
+}
+
 func (p FloatEqualPredicate) KeepValue(v pq.Value) bool {
 	vv := v.Double()
 	return vv == p.value
@@ -382,6 +459,10 @@ func (p FloatNotEqualPredicate) String() string {
 }
 
 func (p FloatNotEqualPredicate) KeepColumnChunk(c *ColumnChunkHelper) bool {
+	if !c.KeepStatistics(p.keepRange, p.KeepValue) {
+		return false
+	}
+
 	if d := c.Dictionary(); d != nil {
 		return keepDictionary(d, p.KeepValue)
 	}
@@ -413,6 +494,13 @@ func (p FloatNotEqualPredicate) KeepPage(page pq.Page) bool {
 	return true
 }
 
+func (p FloatNotEqualPredicate) keepRange(minV, maxV pq.Value) bool {
+	min := minV.Double()
+	max := maxV.Double()
+
+	return min != p.value || p.value != max
+}
+
 func (p FloatNotEqualPredicate) KeepValue(v pq.Value) bool {
 	vv := v.Double()
 	return vv != p.value
@@ -433,6 +521,10 @@ func (p FloatGreaterPredicate) String() string {
 }
 
 func (p FloatGreaterPredicate) KeepColumnChunk(c *ColumnChunkHelper) bool {
+	if !c.KeepStatistics(p.keepRange, p.KeepValue) {
+		return false
+	}
+
 	if d := c.Dictionary(); d != nil {
 		return keepDictionary(d, p.KeepValue)
 	}
@@ -464,6 +556,13 @@ func (p FloatGreaterPredicate) KeepPage(page pq.Page) bool {
 	return true
 }
 
+func (p FloatGreaterPredicate) keepRange(_, maxV pq.Value) bool {
+	
+	max := maxV.Double()
+
+	return max > p.value
+}
+
 func (p FloatGreaterPredicate) KeepValue(v pq.Value) bool {
 	vv := v.Double()
 	return vv > p.value
@@ -484,6 +583,10 @@ func (p FloatGreaterEqualPredicate) String() string {
 }
 
 func (p FloatGreaterEqualPredicate) KeepColumnChunk(c *ColumnChunkHelper) bool {
+	if !c.KeepStatistics(p.keepRange, p.KeepValue) {
+		return false
+	}
+
 	if d := c.Dictionary(); d != nil {
 		return keepDictionary(d, p.KeepValue)
 	}
@@ -515,6 +618,13 @@ func (p FloatGreaterEqualPredicate) KeepPage(page pq.Page) bool {
 	return true
 }
 
+func (p FloatGreaterEqualPredicate) keepRange(_, maxV pq.Value) bool {
+	
+	max := maxV.Double()
+
+	return max >= p.value
+}
+
 func (p FloatGreaterEqualPredicate) KeepValue(v pq.Value) bool {
 	vv := v.Double()
 	return vv >= p.value
@@ -535,6 +645,10 @@ func (p FloatLessPredicate) String() string {
 }
 
 func (p FloatLessPredicate) KeepColumnChunk(c *ColumnChunkHelper) bool {
+	if !c.KeepStatistics(p.keepRange, p.KeepValue) {
+		return false
+	}
+
 	if d := c.Dictionary(); d != nil {
 		return keepDictionary(d, p.KeepValue)
 	}
@@ -566,6 +680,13 @@ func (p FloatLessPredicate) KeepPage(page pq.Page) bool {
 	return true
 }
 
+func (p FloatLessPredicate) keepRange(minV, _ pq.Value) bool {
+	min := minV.Double()
+	
+
+	return min < p.value
+}
+
 func (p FloatLessPredicate) KeepValue(v pq.Value) bool {
 	vv := v.Double()
 	return vv < p.value
@@ -586,6 +707,10 @@ func (p FloatLessEqualPredicate) String() string {
 }
 
 func (p FloatLessEqualPredicate) KeepColumnChunk(c *ColumnChunkHelper) bool {
+	if !c.KeepStatistics(p.keepRange, p.KeepValue) {
+		return false
+	}
+
 	if d := c.Dictionary(); d != nil {
 		return keepDictionary(d, p.KeepValue)
 	}
@@ -617,6 +742,13 @@ func (p FloatLessEqualPredicate) KeepPage(page pq.Page) bool {
 	return true
 }
 
+func (p FloatLessEqualPredicate) keepRange(minV, _ pq.Value) bool {
+	min := minV.Double()
+	
+
+	return min <= p.value
+}
+
 func (p FloatLessEqualPredicate) KeepValue(v pq.Value) bool {
 	vv := v.Double()
 	return vv <= p.value
@@ -702,7 +834,7 @@ func (p StringEqualPredicate) String() string {
 
 func (p StringEqualPredicate) KeepColumnChunk(c *ColumnChunkHelper) bool {
 	if d := c.Dictionary(); d != nil {
-		return keepDictionary(d, p.KeepValue)
+		return c.KeepDictionaryPages(d, p.KeepValue)
 	}
 
 	return true
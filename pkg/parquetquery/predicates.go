@@ -51,7 +51,7 @@ func (p *StringInPredicate) String() string {
 
 func (p *StringInPredicate) KeepColumnChunk(cc *ColumnChunkHelper) bool {
 	if d := cc.Dictionary(); d != nil {
-		return keepDictionary(d, p.KeepValue)
+		return cc.KeepDictionaryPages(d, p.KeepValue)
 	}
 
 	ci, err := cc.ColumnIndex()
@@ -215,6 +215,10 @@ func (p *IntBetweenPredicate) String() string {
 }
 
 func (p *IntBetweenPredicate) KeepColumnChunk(c *ColumnChunkHelper) bool {
+	if !c.KeepStatistics(p.keepRange, p.KeepValue) {
+		return false
+	}
+
 	ci, err := c.ColumnIndex()
 	if err == nil && ci != nil {
 		for i := 0; i < ci.NumPages(); i++ {
@@ -230,6 +234,10 @@ func (p *IntBetweenPredicate) KeepColumnChunk(c *ColumnChunkHelper) bool {
 	return true
 }
 
+func (p *IntBetweenPredicate) keepRange(min, max pq.Value) bool {
+	return p.max >= min.Int64() && p.min <= max.Int64()
+}
+
 func (p *IntBetweenPredicate) KeepValue(v pq.Value) bool {
 	vv := v.Int64()
 	return p.min <= vv && vv <= p.max
@@ -423,6 +431,18 @@ func (p *InstrumentedPredicate) KeepValue(v pq.Value) bool {
 	return false
 }
 
+// keepsDictionaryPages returns true if the predicate only keeps the dictionary entries it inspects with
+// ColumnChunkHelper.KeepDictionaryPages, so the pages that don't reference any of them can be skipped.
+func keepsDictionaryPages(p Predicate) bool {
+	switch p := p.(type) {
+	case StringEqualPredicate, *StringInPredicate:
+		return true
+	case *InstrumentedPredicate:
+		return p.Pred != nil && keepsDictionaryPages(p.Pred)
+	}
+	return false
+}
+
 // keepDictionary inspects all values using the callback and returns if any
 // matches were found.
 func keepDictionary(dict pq.Dictionary, keepValue func(pq.Value) bool) bool {
@@ -48,8 +48,17 @@ func (p {{ $structName }}) String() string {
 }
 
 func (p {{ $structName }}) KeepColumnChunk(c *ColumnChunkHelper) bool {
+	{{- if and $pred.ChunkStatistics (gt (.RangeCond | strlen) 0) }}
+	if !c.KeepStatistics(p.keepRange, p.KeepValue) {
+		return false
+	}
+{{ end }}
 	if d := c.Dictionary(); d != nil {
+		{{- if .DictionaryPages }}
+		return c.KeepDictionaryPages(d, p.KeepValue)
+		{{- else }}
 		return keepDictionary(d, p.KeepValue)
+		{{- end }}
 	}
 
 	{{- if gt (.RangeCond | strlen) 0 }}
@@ -84,6 +93,16 @@ func (p {{ $structName }}) KeepPage(page pq.Page) bool {
 	return true
 }
 
+{{- if and $pred.ChunkStatistics (gt (.RangeCond | strlen) 0) }}
+
+func (p {{ $structName }}) keepRange({{ if $minInRange }}minV{{else}}_{{end}}, {{ if $maxInRange }}maxV{{else}}_{{end}} pq.Value) bool {
+	{{ if $minInRange }}min := minV.{{ $pred.ParquetFunc }}{{end}}
+	{{ if $maxInRange }}max := maxV.{{ $pred.ParquetFunc }}{{end}}
+
+	return {{ .RangeCond }}
+}
+{{- end }}
+
 func (p {{ $structName }}) KeepValue(v pq.Value) bool {
 	vv := v.{{ $pred.ParquetFunc }}
 	return {{ .CompareCond }}
@@ -96,6 +115,9 @@ func (p {{ $structName }}) KeepValue(v pq.Value) bool {
 		Op          string
 		CompareCond string
 		RangeCond   string
+		// DictionaryPages remembers the dictionary entries kept by the predicate to skip the pages that don't
+		// reference any of them
+		DictionaryPages bool
 	}
 
 	preds := []struct {
@@ -103,14 +125,17 @@ func (p {{ $structName }}) KeepValue(v pq.Value) bool {
 		Type           string
 		FormatModifier string
 		ParquetFunc    string
-		Ops            []op
+		// ChunkStatistics checks the range of the column chunk statistics in the file footer before the dictionary
+		ChunkStatistics bool
+		Ops             []op
 	}{
 		// int64!
 		{
-			Name:           "Int",
-			Type:           "int64",
-			ParquetFunc:    "Int64()",
-			FormatModifier: "%d",
+			Name:            "Int",
+			Type:            "int64",
+			ParquetFunc:     "Int64()",
+			FormatModifier:  "%d",
+			ChunkStatistics: true,
 			Ops: []op{
 				{
 					Op:          "Equal",
@@ -146,10 +171,11 @@ func (p {{ $structName }}) KeepValue(v pq.Value) bool {
 		},
 		// float64!
 		{
-			Name:           "Float",
-			Type:           "float64",
-			ParquetFunc:    "Double()",
-			FormatModifier: "%f",
+			Name:            "Float",
+			Type:            "float64",
+			ParquetFunc:     "Double()",
+			FormatModifier:  "%f",
+			ChunkStatistics: true,
 			Ops: []op{
 				{
 					Op:          "Equal",
@@ -212,9 +238,10 @@ func (p {{ $structName }}) KeepValue(v pq.Value) bool {
 			FormatModifier: "%s",
 			Ops: []op{
 				{
-					Op:          "Equal",
-					CompareCond: "bytes.Equal(vv, p.value)",
-					RangeCond:   "", // benchmarks are generally better w/o a range condition? "bytes.Compare(p.value, min) >= 0 && bytes.Compare(p.value, max) <= 0",
+					Op:              "Equal",
+					CompareCond:     "bytes.Equal(vv, p.value)",
+					DictionaryPages: true,
+					RangeCond:       "", // benchmarks are generally better w/o a range condition? "bytes.Compare(p.value, min) >= 0 && bytes.Compare(p.value, max) <= 0",
 				},
 				{
 					Op:          "NotEqual",
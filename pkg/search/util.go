@@ -49,6 +49,11 @@ func GetVirtualTagValuesV2(tagName string) []tempopb.TagValue {
 		return []tempopb.TagValue{}
 	case traceql.IntrinsicTraceDuration.String():
 		return []tempopb.TagValue{}
+	case traceql.IntrinsicTraceSampled.String():
+		return []tempopb.TagValue{
+			{Type: "bool", Value: "true"},
+			{Type: "bool", Value: "false"},
+		}
 	}
 
 	return nil
@@ -76,6 +81,8 @@ func GetVirtualIntrinsicValues() []string {
 		traceql.IntrinsicEventTimeSinceStart.String(),
 		traceql.IntrinsicInstrumentationName.String(),
 		traceql.IntrinsicInstrumentationVersion.String(),
+		traceql.IntrinsicTraceState.String(),
+		traceql.IntrinsicTraceSampled.String(),
 		/* these are technically intrinsics that can be requested, but they are not generally of interest to a user
 		   typing a query. for simplicity and clarity we are leaving them out of autocomplete
 			IntrinsicNestedSetLeft
@@ -228,6 +228,10 @@ type TraceByIDResponse struct {
 	Metrics *TraceByIDMetrics `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
 	Status  PartialStatus     `protobuf:"varint,3,opt,name=status,proto3,enum=tempopb.PartialStatus" json:"status,omitempty"`
 	Message string            `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	// Data quality caveats of the response, if any the results may be incomplete.
+	Warnings []*QueryWarning `protobuf:"bytes,5,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	// The other distinct traces that share the trace ID, ordered by start time. Only set if trace ID collisions are split.
+	CollidingTraces []*Trace `protobuf:"bytes,6,rep,name=collidingTraces,proto3" json:"collidingTraces,omitempty"`
 }
 
 func (m *TraceByIDResponse) Reset()         { *m = TraceByIDResponse{} }
@@ -291,6 +295,20 @@ func (m *TraceByIDResponse) GetMessage() string {
 	return ""
 }
 
+func (m *TraceByIDResponse) GetWarnings() []*QueryWarning {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
+func (m *TraceByIDResponse) GetCollidingTraces() []*Trace {
+	if m != nil {
+		return m.CollidingTraces
+	}
+	return nil
+}
+
 type TraceByIDMetrics struct {
 	InspectedBytes uint64 `protobuf:"varint,1,opt,name=inspectedBytes,proto3" json:"inspectedBytes,omitempty"`
 }
@@ -349,6 +367,9 @@ type SearchRequest struct {
 	SpansPerSpanSet uint32 `protobuf:"varint,9,opt,name=SpansPerSpanSet,proto3" json:"SpansPerSpanSet,omitempty"`
 	// Rhythm fields
 	RF1After time.Time `protobuf:"bytes,10,opt,name=RF1After,proto3,stdtime" json:"RF1After"`
+	// Orders the results by "duration", "start_time" or a numeric attribute,
+	// longest/latest/greatest first. Empty keeps the implicit ordering.
+	OrderBy string `protobuf:"bytes,11,opt,name=OrderBy,proto3" json:"OrderBy,omitempty"`
 }
 
 func (m *SearchRequest) Reset()         { *m = SearchRequest{} }
@@ -447,6 +468,13 @@ func (m *SearchRequest) GetRF1After() time.Time {
 	return time.Time{}
 }
 
+func (m *SearchRequest) GetOrderBy() string {
+	if m != nil {
+		return m.OrderBy
+	}
+	return ""
+}
+
 // SearchBlockRequest takes SearchRequest parameters as well as all information
 // necessary to search a block in the backend.
 type SearchBlockRequest struct {
@@ -645,6 +673,8 @@ func (m *DedicatedColumn) GetType() DedicatedColumn_Type {
 type SearchResponse struct {
 	Traces  []*TraceSearchMetadata `protobuf:"bytes,1,rep,name=traces,proto3" json:"traces,omitempty"`
 	Metrics *SearchMetrics         `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	// Data quality caveats of the response, if any the results may be incomplete.
+	Warnings []*QueryWarning `protobuf:"bytes,3,rep,name=warnings,proto3" json:"warnings,omitempty"`
 }
 
 func (m *SearchResponse) Reset()         { *m = SearchResponse{} }
@@ -694,6 +724,13 @@ func (m *SearchResponse) GetMetrics() *SearchMetrics {
 	return nil
 }
 
+func (m *SearchResponse) GetWarnings() []*QueryWarning {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
 type TraceSearchMetadata struct {
 	TraceID           string                   `protobuf:"bytes,1,opt,name=traceID,proto3" json:"traceID,omitempty"`
 	RootServiceName   string                   `protobuf:"bytes,2,opt,name=rootServiceName,proto3" json:"rootServiceName,omitempty"`
@@ -990,6 +1027,12 @@ type SearchMetrics struct {
 	TotalJobs       uint32 `protobuf:"varint,5,opt,name=totalJobs,proto3" json:"totalJobs,omitempty"`
 	TotalBlockBytes uint64 `protobuf:"varint,6,opt,name=totalBlockBytes,proto3" json:"totalBlockBytes,omitempty"`
 	InspectedSpans  uint64 `protobuf:"varint,7,opt,name=inspectedSpans,proto3" json:"inspectedSpans,omitempty"`
+	// estimated is true when the results were extrapolated from a sample of the data.
+	Estimated bool `protobuf:"varint,8,opt,name=estimated,proto3" json:"estimated,omitempty"`
+	// inspectedBloomBytes, inspectedIndexBytes and inspectedColumnBytes break down the bytes read from the backend by the role of the objects read.
+	InspectedBloomBytes  uint64 `protobuf:"varint,9,opt,name=inspectedBloomBytes,proto3" json:"inspectedBloomBytes,omitempty"`
+	InspectedIndexBytes  uint64 `protobuf:"varint,10,opt,name=inspectedIndexBytes,proto3" json:"inspectedIndexBytes,omitempty"`
+	InspectedColumnBytes uint64 `protobuf:"varint,11,opt,name=inspectedColumnBytes,proto3" json:"inspectedColumnBytes,omitempty"`
 }
 
 func (m *SearchMetrics) Reset()         { *m = SearchMetrics{} }
@@ -1074,6 +1117,34 @@ func (m *SearchMetrics) GetInspectedSpans() uint64 {
 	return 0
 }
 
+func (m *SearchMetrics) GetEstimated() bool {
+	if m != nil {
+		return m.Estimated
+	}
+	return false
+}
+
+func (m *SearchMetrics) GetInspectedBloomBytes() uint64 {
+	if m != nil {
+		return m.InspectedBloomBytes
+	}
+	return 0
+}
+
+func (m *SearchMetrics) GetInspectedIndexBytes() uint64 {
+	if m != nil {
+		return m.InspectedIndexBytes
+	}
+	return 0
+}
+
+func (m *SearchMetrics) GetInspectedColumnBytes() uint64 {
+	if m != nil {
+		return m.InspectedColumnBytes
+	}
+	return 0
+}
+
 type SearchTagsRequest struct {
 	Scope                string `protobuf:"bytes,1,opt,name=scope,proto3" json:"scope,omitempty"`
 	Query                string `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
@@ -1504,6 +1575,8 @@ func (m *SearchTagsResponse) GetMetrics() *MetadataMetrics {
 type SearchTagsV2Response struct {
 	Scopes  []*SearchTagsV2Scope `protobuf:"bytes,1,rep,name=scopes,proto3" json:"scopes,omitempty"`
 	Metrics *MetadataMetrics     `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	// Data quality caveats of the response, if any the results may be incomplete.
+	Warnings []*QueryWarning `protobuf:"bytes,3,rep,name=warnings,proto3" json:"warnings,omitempty"`
 }
 
 func (m *SearchTagsV2Response) Reset()         { *m = SearchTagsV2Response{} }
@@ -1553,6 +1626,13 @@ func (m *SearchTagsV2Response) GetMetrics() *MetadataMetrics {
 	return nil
 }
 
+func (m *SearchTagsV2Response) GetWarnings() []*QueryWarning {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
 type SearchTagsV2Scope struct {
 	Name string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Tags []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
@@ -1614,6 +1694,10 @@ type SearchTagValuesRequest struct {
 	StaleValueThreshold uint32 `protobuf:"varint,7,opt,name=staleValueThreshold,proto3" json:"staleValueThreshold,omitempty"`
 	// Rhythm fields
 	RF1After time.Time `protobuf:"bytes,8,opt,name=RF1After,proto3,stdtime" json:"RF1After"`
+	// Opaque token returned as nextToken by a previous page. Only values after it are returned.
+	After string `protobuf:"bytes,9,opt,name=after,proto3" json:"after,omitempty"`
+	// Enables paging when greater than 0. Values are returned in order, pageSize at a time.
+	PageSize uint32 `protobuf:"varint,10,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
 }
 
 func (m *SearchTagValuesRequest) Reset()         { *m = SearchTagValuesRequest{} }
@@ -1698,6 +1782,20 @@ func (m *SearchTagValuesRequest) GetRF1After() time.Time {
 	return time.Time{}
 }
 
+func (m *SearchTagValuesRequest) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
+}
+
+func (m *SearchTagValuesRequest) GetPageSize() uint32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
 type SearchTagValuesResponse struct {
 	TagValues []string         `protobuf:"bytes,1,rep,name=tagValues,proto3" json:"tagValues,omitempty"`
 	Metrics   *MetadataMetrics `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
@@ -1805,6 +1903,10 @@ func (m *TagValue) GetValue() string {
 type SearchTagValuesV2Response struct {
 	TagValues []*TagValue      `protobuf:"bytes,1,rep,name=tagValues,proto3" json:"tagValues,omitempty"`
 	Metrics   *MetadataMetrics `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	// Set when there are more values than fit in the page. Pass it as after to request the next page.
+	NextToken string `protobuf:"bytes,3,opt,name=nextToken,proto3" json:"nextToken,omitempty"`
+	// Data quality caveats of the response, if any the results may be incomplete.
+	Warnings []*QueryWarning `protobuf:"bytes,5,rep,name=warnings,proto3" json:"warnings,omitempty"`
 }
 
 func (m *SearchTagValuesV2Response) Reset()         { *m = SearchTagValuesV2Response{} }
@@ -1854,6 +1956,20 @@ func (m *SearchTagValuesV2Response) GetMetrics() *MetadataMetrics {
 	return nil
 }
 
+func (m *SearchTagValuesV2Response) GetNextToken() string {
+	if m != nil {
+		return m.NextToken
+	}
+	return ""
+}
+
+func (m *SearchTagValuesV2Response) GetWarnings() []*QueryWarning {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
 type MetadataMetrics struct {
 	InspectedBytes  uint64 `protobuf:"varint,1,opt,name=inspectedBytes,proto3" json:"inspectedBytes,omitempty"`
 	TotalJobs       uint32 `protobuf:"varint,2,opt,name=totalJobs,proto3" json:"totalJobs,omitempty"`
@@ -2021,7 +2137,8 @@ func (m *PushResponse) GetErrorsByTrace() []PushErrorReason {
 
 // PushBytesRequest pushes slices of traces, ids and searchdata. Traces are
 // encoded using the
-//  current BatchDecoder in ./pkg/model
+//
+//	current BatchDecoder in ./pkg/model
 type PushBytesRequest struct {
 	// pre-marshalled Traces. length must match ids
 	Traces []PreallocBytes `protobuf:"bytes,2,rep,name=traces,proto3,customtype=PreallocBytes" json:"traces"`
@@ -3092,6 +3209,8 @@ type QueryInstantResponse struct {
 	Metrics *SearchMetrics   `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
 	Status  PartialStatus    `protobuf:"varint,3,opt,name=status,proto3,enum=tempopb.PartialStatus" json:"status,omitempty"`
 	Message string           `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	// Data quality caveats of the response, if any the results may be incomplete.
+	Warnings []*QueryWarning `protobuf:"bytes,5,rep,name=warnings,proto3" json:"warnings,omitempty"`
 }
 
 func (m *QueryInstantResponse) Reset()         { *m = QueryInstantResponse{} }
@@ -3155,6 +3274,13 @@ func (m *QueryInstantResponse) GetMessage() string {
 	return ""
 }
 
+func (m *QueryInstantResponse) GetWarnings() []*QueryWarning {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
 type InstantSeries struct {
 	// Series labels containing name and value. Data-type aware.
 	Labels []v1.KeyValue `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels"`
@@ -3383,6 +3509,8 @@ type QueryRangeResponse struct {
 	Metrics *SearchMetrics `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
 	Status  PartialStatus  `protobuf:"varint,3,opt,name=status,proto3,enum=tempopb.PartialStatus" json:"status,omitempty"`
 	Message string         `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	// Data quality caveats of the response, if any the results may be incomplete.
+	Warnings []*QueryWarning `protobuf:"bytes,5,rep,name=warnings,proto3" json:"warnings,omitempty"`
 }
 
 func (m *QueryRangeResponse) Reset()         { *m = QueryRangeResponse{} }
@@ -3446,11 +3574,21 @@ func (m *QueryRangeResponse) GetMessage() string {
 	return ""
 }
 
+func (m *QueryRangeResponse) GetWarnings() []*QueryWarning {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
 type Exemplar struct {
 	// Optional, can be empty.
 	Labels      []v1.KeyValue `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels"`
 	Value       float64       `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
 	TimestampMs int64         `protobuf:"varint,3,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
+	// Start time and duration of the span of the exemplar. Optional, zero if unknown.
+	SpanStartTimeUnixNano uint64 `protobuf:"varint,4,opt,name=span_start_time_unix_nano,json=spanStartTimeUnixNano,proto3" json:"span_start_time_unix_nano,omitempty"`
+	SpanDurationNanos     uint64 `protobuf:"varint,5,opt,name=span_duration_nanos,json=spanDurationNanos,proto3" json:"span_duration_nanos,omitempty"`
 }
 
 func (m *Exemplar) Reset()         { *m = Exemplar{} }
@@ -3507,6 +3645,20 @@ func (m *Exemplar) GetTimestampMs() int64 {
 	return 0
 }
 
+func (m *Exemplar) GetSpanStartTimeUnixNano() uint64 {
+	if m != nil {
+		return m.SpanStartTimeUnixNano
+	}
+	return 0
+}
+
+func (m *Exemplar) GetSpanDurationNanos() uint64 {
+	if m != nil {
+		return m.SpanDurationNanos
+	}
+	return 0
+}
+
 type Sample struct {
 	// Fields order MUST match promql.FPoint so that we can cast types between them.
 	TimestampMs int64   `protobuf:"varint,2,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
@@ -3571,6 +3723,8 @@ type TimeSeries struct {
 	// Exemplars are optional and can be empty.
 	// Sorted by time, oldest exemplar first.
 	Exemplars []Exemplar `protobuf:"bytes,4,rep,name=exemplars,proto3" json:"exemplars"`
+	// sampling_weighted is true when the samples were scaled by the sampling weight of one or more spans.
+	SamplingWeighted bool `protobuf:"varint,5,opt,name=sampling_weighted,json=samplingWeighted,proto3" json:"sampling_weighted,omitempty"`
 }
 
 func (m *TimeSeries) Reset()         { *m = TimeSeries{} }
@@ -3634,6 +3788,130 @@ func (m *TimeSeries) GetExemplars() []Exemplar {
 	return nil
 }
 
+func (m *TimeSeries) GetSamplingWeighted() bool {
+	if m != nil {
+		return m.SamplingWeighted
+	}
+	return false
+}
+
+// QueryWarning is a data quality caveat of a query response.
+type QueryWarning struct {
+	// code is the type of the warning: BLOCKS_SKIPPED, RESULTS_TRUNCATED, STALE_BLOCKLIST, TIER_PENDING or TRACE_ID_COLLISION
+	Code    string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (m *QueryWarning) Reset()         { *m = QueryWarning{} }
+func (m *QueryWarning) String() string { return proto.CompactTextString(m) }
+func (*QueryWarning) ProtoMessage()    {}
+func (*QueryWarning) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{49}
+}
+func (m *QueryWarning) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryWarning) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryWarning.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryWarning) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryWarning.Merge(m, src)
+}
+func (m *QueryWarning) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryWarning) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryWarning.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryWarning proto.InternalMessageInfo
+
+func (m *QueryWarning) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *QueryWarning) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+// TraceByIDsResponse holds the traces of several trace IDs found in a single pass over the blocks.
+type TraceByIDsResponse struct {
+	// The traces in the order of the requested trace IDs, empty for the trace IDs that were not found.
+	Traces  []*Trace          `protobuf:"bytes,1,rep,name=traces,proto3" json:"traces,omitempty"`
+	Metrics *TraceByIDMetrics `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	// Data quality caveats of the response, if any the results may be incomplete.
+	Warnings []*QueryWarning `protobuf:"bytes,3,rep,name=warnings,proto3" json:"warnings,omitempty"`
+}
+
+func (m *TraceByIDsResponse) Reset()         { *m = TraceByIDsResponse{} }
+func (m *TraceByIDsResponse) String() string { return proto.CompactTextString(m) }
+func (*TraceByIDsResponse) ProtoMessage()    {}
+func (*TraceByIDsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{50}
+}
+func (m *TraceByIDsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TraceByIDsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TraceByIDsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TraceByIDsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceByIDsResponse.Merge(m, src)
+}
+func (m *TraceByIDsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TraceByIDsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceByIDsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TraceByIDsResponse proto.InternalMessageInfo
+
+func (m *TraceByIDsResponse) GetTraces() []*Trace {
+	if m != nil {
+		return m.Traces
+	}
+	return nil
+}
+
+func (m *TraceByIDsResponse) GetMetrics() *TraceByIDMetrics {
+	if m != nil {
+		return m.Metrics
+	}
+	return nil
+}
+
+func (m *TraceByIDsResponse) GetWarnings() []*QueryWarning {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("tempopb.PushErrorReason", PushErrorReason_name, PushErrorReason_value)
 	proto.RegisterEnum("tempopb.PartialStatus", PartialStatus_name, PartialStatus_value)
@@ -3690,203 +3968,210 @@ func init() {
 	proto.RegisterType((*Exemplar)(nil), "tempopb.Exemplar")
 	proto.RegisterType((*Sample)(nil), "tempopb.Sample")
 	proto.RegisterType((*TimeSeries)(nil), "tempopb.TimeSeries")
+	proto.RegisterType((*QueryWarning)(nil), "tempopb.QueryWarning")
+	proto.RegisterType((*TraceByIDsResponse)(nil), "tempopb.TraceByIDsResponse")
 }
 
 func init() { proto.RegisterFile("pkg/tempopb/tempo.proto", fileDescriptor_f22805646f4f62b6) }
 
 var fileDescriptor_f22805646f4f62b6 = []byte{
-	// 3047 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x3a, 0x4b, 0x6f, 0x23, 0xc7,
-	0xd1, 0x1a, 0xbe, 0x55, 0x24, 0x25, 0xaa, 0xa5, 0x95, 0xb9, 0xdc, 0xb5, 0xa4, 0x6f, 0xbc, 0x30,
-	0xf4, 0xad, 0x6d, 0x4a, 0x4b, 0xaf, 0xf1, 0x79, 0xed, 0x2f, 0x4e, 0xa4, 0x15, 0xbd, 0x96, 0xad,
-	0x97, 0x9b, 0xb4, 0x6c, 0x04, 0x06, 0x84, 0x11, 0xd9, 0xcb, 0x1d, 0x88, 0x9c, 0xa1, 0x67, 0x86,
-	0xb2, 0x94, 0x83, 0x91, 0x07, 0x82, 0x24, 0x40, 0x0e, 0x3e, 0x24, 0x87, 0xfc, 0x82, 0x20, 0xb9,
-	0xe6, 0x92, 0x4b, 0x2e, 0x09, 0x10, 0x38, 0x08, 0x0c, 0x18, 0xc8, 0xc5, 0xc8, 0xc1, 0x09, 0xec,
-	0x43, 0xfe, 0x41, 0xce, 0x41, 0x57, 0x77, 0xcf, 0x8b, 0x23, 0x69, 0x77, 0x2d, 0x23, 0x3e, 0xf8,
-	0xc4, 0xee, 0xea, 0xea, 0xea, 0xea, 0x7a, 0x57, 0x0f, 0xe1, 0x89, 0xe1, 0x51, 0x6f, 0xc5, 0x63,
-	0x83, 0xa1, 0x3d, 0x3c, 0x14, 0xbf, 0xf5, 0xa1, 0x63, 0x7b, 0x36, 0xc9, 0x4b, 0x60, 0x6d, 0xbe,
-	0x63, 0x0f, 0x06, 0xb6, 0xb5, 0x72, 0x7c, 0x6b, 0x45, 0x8c, 0x04, 0x42, 0xed, 0xb9, 0x9e, 0xe9,
-	0x3d, 0x18, 0x1d, 0xd6, 0x3b, 0xf6, 0x60, 0xa5, 0x67, 0xf7, 0xec, 0x15, 0x04, 0x1f, 0x8e, 0xee,
-	0xe3, 0x0c, 0x27, 0x38, 0x92, 0xe8, 0x73, 0x9e, 0x63, 0x74, 0x18, 0xa7, 0x82, 0x03, 0x09, 0x5d,
-	0xec, 0xd9, 0x76, 0xaf, 0xcf, 0x82, 0xbd, 0x9e, 0x39, 0x60, 0xae, 0x67, 0x0c, 0x86, 0x02, 0x41,
-	0xff, 0xb7, 0x06, 0x95, 0x36, 0xdf, 0xb0, 0x7e, 0xba, 0xb9, 0x41, 0xd9, 0x7b, 0x23, 0xe6, 0x7a,
-	0xa4, 0x0a, 0x79, 0x24, 0xb2, 0xb9, 0x51, 0xd5, 0x96, 0xb4, 0xe5, 0x12, 0x55, 0x53, 0xb2, 0x00,
-	0x70, 0xd8, 0xb7, 0x3b, 0x47, 0x2d, 0xcf, 0x70, 0xbc, 0x6a, 0x6a, 0x49, 0x5b, 0x9e, 0xa4, 0x21,
-	0x08, 0xa9, 0x41, 0x01, 0x67, 0x4d, 0xab, 0x5b, 0x4d, 0xe3, 0xaa, 0x3f, 0x27, 0xd7, 0x61, 0xf2,
-	0xbd, 0x11, 0x73, 0x4e, 0xb7, 0xed, 0x2e, 0xab, 0x66, 0x71, 0x31, 0x00, 0x90, 0x67, 0x61, 0xc6,
-	0xe8, 0xf7, 0xed, 0xf7, 0xf7, 0x0c, 0xc7, 0x33, 0x8d, 0x3e, 0xf2, 0x54, 0xcd, 0x2d, 0x69, 0xcb,
-	0x05, 0x3a, 0xbe, 0x40, 0xbe, 0x03, 0x05, 0xfa, 0xea, 0xad, 0xb5, 0xfb, 0x1e, 0x73, 0xaa, 0xf9,
-	0x25, 0x6d, 0xb9, 0xd8, 0xa8, 0xd5, 0xc5, 0x55, 0xeb, 0xea, 0xaa, 0xf5, 0xb6, 0xba, 0xea, 0x7a,
-	0xe1, 0xa3, 0xcf, 0x16, 0x27, 0x3e, 0xfc, 0xc7, 0xa2, 0x46, 0xfd, 0x5d, 0xfa, 0xef, 0x35, 0x98,
-	0x09, 0x5d, 0xdc, 0x1d, 0xda, 0x96, 0xcb, 0xc8, 0x0d, 0xc8, 0xe2, 0x55, 0xf1, 0xde, 0xc5, 0xc6,
-	0x54, 0x5d, 0x6a, 0xa9, 0x8e, 0xa8, 0x54, 0x2c, 0x92, 0xe7, 0x21, 0x3f, 0x60, 0x9e, 0x63, 0x76,
-	0x5c, 0x14, 0x41, 0xb1, 0x71, 0x35, 0x8a, 0xc7, 0x49, 0x6e, 0x0b, 0x04, 0xaa, 0x30, 0x49, 0x1d,
-	0x72, 0xae, 0x67, 0x78, 0x23, 0x17, 0x05, 0x33, 0xd5, 0x98, 0xf7, 0xf7, 0xc8, 0x9b, 0xb5, 0x70,
-	0x95, 0x4a, 0x2c, 0xae, 0x84, 0x01, 0x73, 0x5d, 0xa3, 0xc7, 0xaa, 0x19, 0x14, 0x96, 0x9a, 0xea,
-	0x2f, 0x85, 0x54, 0x26, 0x8f, 0x21, 0x4f, 0xc3, 0x94, 0x69, 0xb9, 0x43, 0xd6, 0xf1, 0x58, 0x77,
-	0xfd, 0xd4, 0x63, 0x2e, 0xde, 0x20, 0x43, 0x63, 0x50, 0xfd, 0xc3, 0x34, 0x94, 0x5b, 0xcc, 0x70,
-	0x3a, 0x0f, 0x94, 0xb2, 0x5f, 0x82, 0x4c, 0xdb, 0xe8, 0x71, 0xfc, 0xf4, 0x72, 0xb1, 0xb1, 0xe4,
-	0x73, 0x15, 0xc1, 0xaa, 0x73, 0x94, 0xa6, 0xe5, 0x39, 0xa7, 0xeb, 0x19, 0x2e, 0x4c, 0x8a, 0x7b,
-	0xc8, 0x0d, 0x28, 0x6f, 0x9b, 0xd6, 0xc6, 0xc8, 0x31, 0x3c, 0xd3, 0xb6, 0xb6, 0x85, 0x38, 0xca,
-	0x34, 0x0a, 0x44, 0x2c, 0xe3, 0x24, 0x84, 0x95, 0x96, 0x58, 0x61, 0x20, 0x99, 0x83, 0xec, 0x96,
-	0x39, 0x30, 0x3d, 0xbc, 0x6d, 0x99, 0x8a, 0x09, 0x87, 0xba, 0x68, 0x6b, 0x59, 0x01, 0xc5, 0x09,
-	0xa9, 0x40, 0x9a, 0x59, 0x5d, 0x34, 0x8f, 0x32, 0xe5, 0x43, 0x8e, 0xf7, 0x26, 0xb7, 0xa5, 0x6a,
-	0x01, 0x65, 0x25, 0x26, 0x64, 0x19, 0xa6, 0x5b, 0x43, 0xc3, 0x72, 0xf7, 0x98, 0xc3, 0x7f, 0x5b,
-	0xcc, 0xab, 0x4e, 0xe2, 0x9e, 0x38, 0x38, 0x62, 0x50, 0xf0, 0x38, 0x06, 0x55, 0xfb, 0x3f, 0x98,
-	0xf4, 0x85, 0xc4, 0x19, 0x3c, 0x62, 0xa7, 0xa8, 0x83, 0x49, 0xca, 0x87, 0x9c, 0xc1, 0x63, 0xa3,
-	0x3f, 0x62, 0xd2, 0x69, 0xc4, 0xe4, 0xa5, 0xd4, 0x8b, 0x9a, 0xfe, 0xe7, 0x34, 0x10, 0x21, 0xec,
-	0x75, 0xee, 0x2a, 0x4a, 0x2f, 0xb7, 0x61, 0xd2, 0x55, 0x2a, 0x90, 0xe6, 0x38, 0x9f, 0xac, 0x1c,
-	0x1a, 0x20, 0x72, 0xab, 0x41, 0x87, 0xdb, 0xdc, 0x90, 0x07, 0xa9, 0x29, 0x77, 0x3f, 0x14, 0xde,
-	0x1e, 0xb7, 0x28, 0xa1, 0x81, 0x00, 0xc0, 0x75, 0x34, 0x34, 0x7a, 0xcc, 0x6d, 0xdb, 0x82, 0xb4,
-	0xd4, 0x42, 0x14, 0xc8, 0xdd, 0x9b, 0x59, 0x1d, 0xbb, 0x6b, 0x5a, 0x3d, 0xe9, 0xc1, 0xfe, 0x9c,
-	0x53, 0x30, 0xad, 0x2e, 0x3b, 0xe1, 0xe4, 0x5a, 0xe6, 0xf7, 0x98, 0xd4, 0x4e, 0x14, 0x48, 0x74,
-	0x28, 0x79, 0xb6, 0x67, 0xf4, 0x29, 0xeb, 0xd8, 0x4e, 0xd7, 0x45, 0xe7, 0x2d, 0xd3, 0x08, 0x8c,
-	0xe3, 0x74, 0x0d, 0xcf, 0x68, 0xaa, 0x93, 0x84, 0x4a, 0x23, 0x30, 0x7e, 0xcf, 0x63, 0xe6, 0xb8,
-	0xa6, 0x6d, 0xa1, 0x46, 0x27, 0xa9, 0x9a, 0x12, 0x02, 0x19, 0x97, 0x1f, 0x0f, 0x68, 0xff, 0x38,
-	0xe6, 0x61, 0xeb, 0xbe, 0x6d, 0x7b, 0xcc, 0x41, 0xc6, 0x8a, 0x78, 0x66, 0x08, 0x42, 0x36, 0xa0,
-	0xd2, 0x65, 0x5d, 0xb3, 0x63, 0x78, 0xac, 0x7b, 0xd7, 0xee, 0x8f, 0x06, 0x96, 0x5b, 0x2d, 0xa1,
-	0x3f, 0x54, 0x7d, 0x91, 0x6f, 0x44, 0x11, 0xe8, 0xd8, 0x0e, 0xfd, 0x4f, 0x1a, 0x4c, 0xc7, 0xb0,
-	0xc8, 0x6d, 0xc8, 0xba, 0x1d, 0x7b, 0xc8, 0xa4, 0xd3, 0x2f, 0x9c, 0x45, 0xae, 0xde, 0xe2, 0x58,
-	0x54, 0x20, 0xf3, 0x3b, 0x58, 0xc6, 0x40, 0xd9, 0x0a, 0x8e, 0xc9, 0x2d, 0xc8, 0x78, 0xa7, 0x43,
-	0x11, 0x99, 0xa6, 0x1a, 0x4f, 0x9e, 0x49, 0xa8, 0x7d, 0x3a, 0x64, 0x14, 0x51, 0xf5, 0x45, 0xc8,
-	0x22, 0x59, 0x52, 0x80, 0x4c, 0x6b, 0x6f, 0x6d, 0xa7, 0x32, 0x41, 0x4a, 0x50, 0xa0, 0xcd, 0xd6,
-	0xee, 0x5b, 0xf4, 0x6e, 0xb3, 0xa2, 0xe9, 0x04, 0x32, 0x1c, 0x9d, 0x00, 0xe4, 0x5a, 0x6d, 0xba,
-	0xb9, 0x73, 0xaf, 0x32, 0xa1, 0x9f, 0xc0, 0x94, 0xb2, 0x2e, 0x19, 0x14, 0x6f, 0x43, 0x0e, 0xe3,
-	0x9e, 0x8a, 0x11, 0xd7, 0xa3, 0xd1, 0x4e, 0x60, 0x6f, 0x33, 0xcf, 0xe0, 0x1a, 0xa2, 0x12, 0x97,
-	0xac, 0xc6, 0x83, 0x64, 0xdc, 0x7a, 0xe3, 0x11, 0x52, 0xff, 0x5b, 0x1a, 0x66, 0x13, 0x28, 0xc6,
-	0xd3, 0xd1, 0x64, 0x90, 0x8e, 0x96, 0x61, 0xda, 0xb1, 0x6d, 0xaf, 0xc5, 0x9c, 0x63, 0xb3, 0xc3,
-	0x76, 0x02, 0x91, 0xc5, 0xc1, 0xdc, 0x3a, 0x39, 0x08, 0xc9, 0x23, 0x9e, 0xc8, 0x4e, 0x51, 0x20,
-	0x4f, 0x42, 0xe8, 0x12, 0xdc, 0xd3, 0xdf, 0xb2, 0xcc, 0x93, 0x1d, 0xc3, 0xb2, 0xd1, 0x13, 0x32,
-	0x74, 0x7c, 0x81, 0x5b, 0x55, 0x37, 0x08, 0x6a, 0x22, 0x40, 0x85, 0x20, 0xe4, 0x26, 0xe4, 0x5d,
-	0x19, 0x75, 0x72, 0x28, 0x81, 0x4a, 0x20, 0x01, 0x01, 0xa7, 0x0a, 0x81, 0x3c, 0x0b, 0x05, 0x39,
-	0xe4, 0x3e, 0x91, 0x4e, 0x44, 0xf6, 0x31, 0x08, 0x85, 0x92, 0x2b, 0x2e, 0xc7, 0x93, 0x86, 0x5b,
-	0x2d, 0xe0, 0x8e, 0xfa, 0x79, 0x7a, 0xa9, 0xb7, 0x42, 0x1b, 0x30, 0x48, 0xd1, 0x08, 0x8d, 0xda,
-	0x3e, 0xcc, 0x8c, 0xa1, 0x24, 0xc4, 0xb1, 0x67, 0xc2, 0x71, 0xac, 0xd8, 0xb8, 0x12, 0x52, 0x6a,
-	0xb0, 0x39, 0x1c, 0xde, 0xb6, 0xa0, 0x14, 0x5e, 0xc2, 0x38, 0x34, 0x34, 0xac, 0xbb, 0xf6, 0xc8,
-	0xf2, 0x90, 0x30, 0x8f, 0x43, 0x0a, 0xc0, 0x65, 0xca, 0x1c, 0xc7, 0x76, 0xc4, 0xb2, 0x48, 0x27,
-	0x21, 0x88, 0xfe, 0x63, 0x0d, 0xf2, 0x2a, 0x66, 0x3f, 0x05, 0x59, 0xbe, 0x51, 0x99, 0x65, 0x39,
-	0x22, 0x30, 0x2a, 0xd6, 0x30, 0x8d, 0x1a, 0x5e, 0xe7, 0x01, 0xeb, 0x4a, 0x6a, 0x6a, 0x4a, 0x5e,
-	0x06, 0x30, 0x3c, 0xcf, 0x31, 0x0f, 0x47, 0x3c, 0x5d, 0xa6, 0x91, 0xc6, 0x35, 0x9f, 0x86, 0xac,
-	0xc5, 0x8e, 0x6f, 0xd5, 0xdf, 0x60, 0xa7, 0xfb, 0xfc, 0x36, 0x34, 0x84, 0xce, 0x7d, 0x3d, 0xc3,
-	0x8f, 0x21, 0xf3, 0x90, 0xe3, 0x07, 0xf9, 0xb6, 0x29, 0x67, 0x89, 0x2e, 0x9c, 0x68, 0x5e, 0xe9,
-	0xb3, 0xcc, 0xeb, 0x06, 0x94, 0x95, 0x31, 0xf1, 0xb9, 0x2b, 0x0d, 0x31, 0x0a, 0x8c, 0xdd, 0x22,
-	0xfb, 0x68, 0xb7, 0xf8, 0x55, 0x4a, 0x55, 0x03, 0xaa, 0x8e, 0x58, 0x86, 0x69, 0xbf, 0x62, 0x68,
-	0x2b, 0xa7, 0xc7, 0x8c, 0x19, 0x03, 0x27, 0x54, 0x1c, 0xa9, 0xa4, 0x8a, 0x83, 0x2c, 0x41, 0x11,
-	0xa3, 0x3b, 0x26, 0x37, 0x95, 0xfb, 0xc3, 0x20, 0x7e, 0xd1, 0x8e, 0x3d, 0x18, 0xf6, 0x99, 0xc7,
-	0xba, 0xaf, 0xdb, 0x87, 0xae, 0xca, 0x3d, 0x11, 0x20, 0xb7, 0x1b, 0xdc, 0x84, 0x18, 0xc2, 0xd9,
-	0x02, 0x00, 0xe7, 0x3b, 0x20, 0x29, 0xd8, 0xc9, 0x21, 0x3b, 0x71, 0x70, 0x84, 0x6f, 0xac, 0x02,
-	0x30, 0x07, 0x85, 0xf9, 0x46, 0xa8, 0xfe, 0x93, 0x14, 0x77, 0x08, 0x2e, 0x1b, 0x9e, 0xd6, 0x55,
-	0x56, 0x9e, 0x53, 0xf1, 0x5c, 0x68, 0x5b, 0xc6, 0xeb, 0x39, 0xc8, 0x62, 0x25, 0xab, 0x92, 0x3b,
-	0x4e, 0x82, 0xda, 0x25, 0x9d, 0x50, 0xbb, 0x64, 0x82, 0xda, 0x65, 0x19, 0xa6, 0x07, 0xc6, 0x09,
-	0x3f, 0x85, 0x17, 0x24, 0x48, 0x5d, 0xdc, 0x2f, 0x0e, 0x26, 0x0d, 0x98, 0x73, 0x3d, 0xa3, 0xcf,
-	0x50, 0x93, 0x6e, 0xfb, 0x81, 0xc3, 0xdc, 0x07, 0x76, 0x5f, 0x15, 0x42, 0x89, 0x6b, 0x97, 0x50,
-	0x2a, 0xff, 0x36, 0x03, 0xf3, 0x81, 0x24, 0x22, 0x45, 0xca, 0x8b, 0xe3, 0x45, 0x4a, 0x2d, 0x16,
-	0xe6, 0x43, 0xd2, 0xfb, 0xa6, 0x50, 0xf9, 0x5a, 0x14, 0x2a, 0x49, 0x06, 0x57, 0x4e, 0x36, 0xb8,
-	0x55, 0x98, 0x0d, 0x8c, 0x2a, 0xb0, 0xb7, 0x29, 0xc4, 0x4e, 0x5a, 0xd2, 0x3f, 0x4d, 0xc3, 0x35,
-	0x5f, 0xf1, 0xc2, 0x16, 0x23, 0x16, 0xf3, 0xad, 0x71, 0x8b, 0x59, 0x1c, 0xb7, 0x18, 0xb1, 0xf1,
-	0x1b, 0xb3, 0xf9, 0x5a, 0xd5, 0xb7, 0x5d, 0xd5, 0xa7, 0x08, 0x97, 0x96, 0xd5, 0x61, 0x0d, 0x0a,
-	0x9e, 0xd1, 0xe3, 0xe5, 0x93, 0x48, 0xc4, 0x93, 0xd4, 0x9f, 0x93, 0x46, 0xbc, 0x06, 0x0c, 0x8e,
-	0x53, 0x75, 0xc9, 0x58, 0x15, 0xf8, 0x01, 0xcc, 0x05, 0xa7, 0xec, 0x37, 0xfc, 0x73, 0x1a, 0x90,
-	0xc3, 0x60, 0xab, 0xd2, 0x7d, 0x52, 0x9c, 0xd9, 0x6f, 0x88, 0x32, 0x5a, 0x62, 0x3e, 0xd6, 0xf9,
-	0x2f, 0x87, 0xc3, 0xbe, 0x24, 0xe8, 0x67, 0x73, 0x2d, 0x94, 0xcd, 0x09, 0x64, 0x3c, 0xde, 0x38,
-	0xa7, 0xf0, 0xd2, 0x38, 0xd6, 0x7f, 0x9a, 0x0a, 0x85, 0xca, 0x88, 0x11, 0x63, 0x15, 0x2b, 0xe4,
-	0xe2, 0x57, 0xb1, 0x62, 0x7a, 0x51, 0xf6, 0xc8, 0x24, 0x64, 0x8f, 0x6c, 0x90, 0x3d, 0x74, 0x28,
-	0x09, 0xaf, 0x15, 0xc7, 0x49, 0xb3, 0x8c, 0xc0, 0xce, 0x72, 0xe3, 0xfc, 0x99, 0x6e, 0x1c, 0xc9,
-	0x1a, 0x85, 0xc7, 0xca, 0x1a, 0x47, 0xf0, 0xc4, 0x98, 0x24, 0xa4, 0x2a, 0x79, 0x2a, 0xf7, 0xf9,
-	0x15, 0x36, 0x13, 0x00, 0x1e, 0x4b, 0x69, 0xb7, 0xa1, 0xa0, 0x8e, 0x41, 0xbd, 0x9c, 0xfa, 0x19,
-	0x1a, 0xc7, 0xc9, 0xdd, 0xb7, 0xfe, 0x7d, 0x0d, 0xae, 0xc6, 0x78, 0x0c, 0x19, 0xdc, 0x4a, 0x9c,
-	0xcb, 0x62, 0x63, 0x26, 0xa8, 0xb0, 0xe5, 0xca, 0x97, 0x65, 0xfc, 0x2f, 0x1a, 0x4c, 0xc7, 0x16,
-	0x1f, 0xf6, 0x2d, 0x27, 0x5a, 0x11, 0xa5, 0xe2, 0x15, 0xd1, 0x58, 0x55, 0x95, 0x4e, 0xaa, 0xaa,
-	0x62, 0xd5, 0x59, 0x66, 0xbc, 0x3a, 0x4b, 0xa8, 0xac, 0xb2, 0x89, 0x95, 0x95, 0xbe, 0x03, 0x59,
-	0xf1, 0x3a, 0xd7, 0x84, 0xb2, 0xc3, 0x5c, 0x7b, 0xe4, 0x74, 0x58, 0x2b, 0x54, 0xa0, 0x07, 0x71,
-	0x5e, 0x3c, 0x51, 0x1e, 0xdf, 0xaa, 0xd3, 0x30, 0x1a, 0x8d, 0xee, 0xd2, 0x77, 0xa0, 0xb4, 0x37,
-	0x72, 0x83, 0x3e, 0xf4, 0x15, 0x28, 0x63, 0x27, 0xe0, 0xae, 0x9f, 0xb6, 0xe5, 0x23, 0x5d, 0x7a,
-	0x79, 0x2a, 0x24, 0x65, 0x8e, 0xdd, 0xe4, 0x18, 0x94, 0x19, 0xae, 0x6d, 0xd1, 0x28, 0xba, 0xfe,
-	0x33, 0x0d, 0x2a, 0x1c, 0x05, 0xb9, 0x55, 0x6e, 0xf9, 0x9c, 0xdf, 0xdc, 0x72, 0x3f, 0x2e, 0xad,
-	0x5f, 0xe1, 0xa6, 0xfc, 0xf7, 0xcf, 0x16, 0xcb, 0x7b, 0x0e, 0x33, 0xfa, 0x7d, 0xbb, 0x23, 0xb0,
-	0x55, 0x57, 0x5b, 0x81, 0xb4, 0xd9, 0x15, 0xdd, 0x42, 0x89, 0xf2, 0x21, 0xb9, 0x0d, 0x57, 0xdc,
-	0x23, 0x73, 0x28, 0x95, 0x77, 0x8f, 0x59, 0x4c, 0x94, 0xe7, 0x28, 0xa5, 0x02, 0x4d, 0x5e, 0xd4,
-	0x7f, 0x24, 0x79, 0x11, 0x17, 0x97, 0xbc, 0xdc, 0x81, 0xfc, 0x21, 0x36, 0x27, 0x0f, 0x2d, 0x31,
-	0x85, 0x7f, 0x36, 0x17, 0xa9, 0xf3, 0xb8, 0xb8, 0x01, 0x20, 0x5f, 0x12, 0xb9, 0x3d, 0xcd, 0x47,
-	0xfa, 0xfc, 0x92, 0xba, 0xb3, 0xfe, 0x0a, 0x4c, 0x6e, 0x99, 0xd6, 0x51, 0xab, 0x6f, 0x76, 0x18,
-	0xb9, 0x05, 0xd9, 0xbe, 0x69, 0x1d, 0x29, 0x0e, 0xaf, 0x8d, 0x73, 0xc8, 0x39, 0xab, 0xf3, 0x0d,
-	0x54, 0x60, 0xea, 0x3f, 0xd4, 0x80, 0x70, 0xa0, 0x32, 0xfe, 0xa0, 0x94, 0x16, 0x61, 0x4f, 0x0b,
-	0x87, 0xbd, 0x2a, 0xe4, 0x7b, 0x8e, 0x3d, 0x1a, 0xae, 0xab, 0x70, 0xa8, 0xa6, 0x1c, 0xbf, 0x8f,
-	0x0f, 0x84, 0xa2, 0x63, 0x12, 0x93, 0x87, 0x0d, 0x93, 0x5c, 0xf9, 0x57, 0x43, 0x4c, 0xb4, 0x46,
-	0x83, 0x81, 0xe1, 0x9c, 0xfe, 0x77, 0x78, 0xf9, 0x8d, 0x06, 0xb3, 0x11, 0x81, 0x04, 0x71, 0x91,
-	0xb9, 0x9e, 0x39, 0xe0, 0x49, 0x17, 0x39, 0x29, 0xd0, 0x00, 0x10, 0x6d, 0x9c, 0x45, 0xaf, 0x15,
-	0x6a, 0x9c, 0x9f, 0x86, 0x29, 0xb4, 0xf6, 0x96, 0x8f, 0x22, 0x58, 0x8b, 0x41, 0x49, 0x3d, 0x08,
-	0x52, 0x19, 0xd4, 0xe0, 0x5c, 0xa4, 0x6d, 0x1e, 0x0b, 0x50, 0xff, 0x0f, 0x25, 0x6a, 0xbc, 0xff,
-	0x9a, 0xe9, 0x7a, 0x76, 0xcf, 0x31, 0x06, 0xdc, 0x48, 0x0e, 0x47, 0x9d, 0x23, 0xe6, 0xc9, 0xa0,
-	0x24, 0x67, 0xfc, 0xee, 0x9d, 0x10, 0x67, 0x62, 0xa2, 0xbf, 0x0e, 0x05, 0xd5, 0x78, 0x26, 0xbc,
-	0x25, 0x3c, 0x1b, 0x7d, 0x4b, 0x98, 0x8f, 0xbe, 0x5f, 0xbc, 0xb9, 0xd5, 0xf2, 0x0c, 0xcf, 0xec,
-	0xa8, 0x68, 0xfd, 0x0b, 0x0d, 0x8a, 0x21, 0x16, 0xc9, 0x3a, 0xcc, 0xf4, 0x0d, 0x8f, 0x59, 0x9d,
-	0xd3, 0x83, 0x07, 0x8a, 0x3d, 0x69, 0x95, 0xc1, 0xab, 0x44, 0x98, 0x77, 0x5a, 0x91, 0xf8, 0xc1,
-	0x6d, 0xfe, 0x17, 0x72, 0x2e, 0x73, 0x4c, 0xe9, 0xfd, 0xe1, 0x00, 0xef, 0xf7, 0xcb, 0x12, 0x81,
-	0x5f, 0x5c, 0x84, 0x13, 0x29, 0x58, 0x39, 0xd3, 0x3f, 0x8e, 0x5a, 0xb7, 0x34, 0xac, 0xf1, 0x67,
-	0x8e, 0x0b, 0xb4, 0x95, 0x4a, 0xd4, 0x56, 0xc0, 0x5f, 0xfa, 0x22, 0xfe, 0x2a, 0x90, 0x1e, 0xde,
-	0xb9, 0x23, 0x1f, 0x09, 0xf8, 0x50, 0x40, 0x5e, 0x90, 0xd1, 0x9a, 0x0f, 0x05, 0x64, 0x55, 0x76,
-	0xc6, 0x7c, 0x88, 0x90, 0x17, 0x56, 0x65, 0x0b, 0xcc, 0x87, 0xfa, 0xdb, 0x50, 0x4b, 0xf2, 0x13,
-	0x69, 0xa2, 0x77, 0x60, 0xd2, 0x45, 0x90, 0xc9, 0xc6, 0x43, 0x40, 0xc2, 0xbe, 0x00, 0x5b, 0xff,
-	0xa5, 0x06, 0xe5, 0x88, 0x62, 0x23, 0x99, 0x3a, 0x2b, 0x33, 0x75, 0x09, 0x34, 0x11, 0xb4, 0xd2,
-	0x54, 0xb3, 0xf8, 0xec, 0x3e, 0xca, 0x5b, 0xa3, 0xda, 0x7d, 0x3e, 0x73, 0xe5, 0xc7, 0x10, 0xcd,
-	0xe5, 0xb3, 0x43, 0x19, 0x64, 0xb5, 0x43, 0x3e, 0xeb, 0xca, 0x8b, 0x69, 0x5d, 0x7c, 0x95, 0x11,
-	0x1f, 0x5b, 0xf2, 0x48, 0x5b, 0x7d, 0x54, 0x21, 0x90, 0x39, 0x32, 0xad, 0x2e, 0x96, 0x34, 0x59,
-	0x8a, 0x63, 0x9d, 0x89, 0x8f, 0x04, 0x92, 0xf1, 0x0d, 0xc3, 0x33, 0x78, 0x3d, 0xed, 0x30, 0x77,
-	0xd4, 0xf7, 0xda, 0x41, 0x21, 0x11, 0x82, 0xf0, 0x5a, 0x54, 0xcc, 0xa4, 0xd9, 0xd4, 0x12, 0x7d,
-	0x08, 0x31, 0xa8, 0xc4, 0xe4, 0x51, 0x70, 0x66, 0x6c, 0x95, 0x9b, 0x49, 0xdf, 0x38, 0x64, 0xfd,
-	0x50, 0x5d, 0x18, 0x00, 0x38, 0x1f, 0x38, 0xd9, 0x0f, 0xd5, 0x2e, 0x21, 0x08, 0x59, 0x81, 0x94,
-	0xa7, 0x4c, 0x63, 0xf1, 0x6c, 0x1e, 0xf6, 0x6c, 0xd3, 0xf2, 0x68, 0xca, 0x73, 0xb9, 0x0f, 0xcd,
-	0x27, 0x2f, 0xa3, 0x32, 0x4c, 0xc9, 0x44, 0x99, 0xe2, 0x98, 0x5b, 0xc7, 0xb1, 0xd1, 0xc7, 0x83,
-	0x35, 0xca, 0x87, 0xbc, 0x1a, 0x60, 0x27, 0x6c, 0x30, 0xec, 0x1b, 0x4e, 0x5b, 0xbe, 0xc9, 0xa6,
-	0xf1, 0x13, 0x61, 0x1c, 0x4c, 0x6e, 0x42, 0x45, 0x81, 0xd4, 0x57, 0x1e, 0x69, 0x9c, 0x63, 0x70,
-	0xbd, 0x05, 0xb3, 0xf8, 0xc1, 0x66, 0xd3, 0x72, 0x3d, 0xc3, 0xf2, 0xce, 0x8f, 0xca, 0x7e, 0x94,
-	0x95, 0x91, 0x26, 0x12, 0x65, 0x85, 0x6f, 0x62, 0x94, 0xfd, 0xa3, 0x06, 0x73, 0x51, 0xaa, 0xd2,
-	0x86, 0xeb, 0xbe, 0x53, 0x09, 0x03, 0x0e, 0xe2, 0x8e, 0xc4, 0x6c, 0xe1, 0xaa, 0xef, 0x59, 0x8f,
-	0xfc, 0x92, 0x7d, 0x89, 0xdf, 0xfa, 0x7e, 0xa0, 0x41, 0x39, 0xc2, 0x15, 0xb9, 0x03, 0x39, 0xb4,
-	0x80, 0x71, 0xf7, 0x1b, 0x7f, 0xec, 0x93, 0x1f, 0xeb, 0xe4, 0x86, 0x68, 0x15, 0xac, 0xc9, 0xb8,
-	0x4a, 0x16, 0xa1, 0x38, 0x74, 0xec, 0xc1, 0x81, 0xa4, 0x2a, 0x1e, 0xc6, 0x81, 0x83, 0xb6, 0x10,
-	0xa2, 0x7f, 0x9c, 0x86, 0x19, 0x14, 0x24, 0x35, 0xac, 0x1e, 0xbb, 0x14, 0xe5, 0x60, 0x17, 0xeb,
-	0xb1, 0xa1, 0xb4, 0x08, 0x1c, 0x47, 0x3f, 0x10, 0xe7, 0xe3, 0x1f, 0x88, 0x43, 0x9d, 0x7f, 0xe1,
-	0x9c, 0xce, 0x7f, 0xf2, 0xc2, 0xce, 0x1f, 0x92, 0x3a, 0xff, 0x50, 0xbf, 0x5d, 0x8c, 0xf6, 0xdb,
-	0xe1, 0x37, 0x81, 0x52, 0xec, 0x4d, 0x40, 0xf5, 0xe2, 0xe5, 0x33, 0x7b, 0xf1, 0xa9, 0x87, 0xea,
-	0xc5, 0xa7, 0x1f, 0xf9, 0x09, 0x87, 0x97, 0x0a, 0xd2, 0x8b, 0xdc, 0x6a, 0x45, 0xdc, 0xd9, 0x07,
-	0xf0, 0xd5, 0x81, 0x71, 0x22, 0x0c, 0xa6, 0x3a, 0x23, 0x56, 0x7d, 0x80, 0xfe, 0x07, 0x0d, 0x48,
-	0x58, 0x9f, 0xd2, 0x2d, 0x9e, 0x89, 0xb9, 0xc5, 0x6c, 0x90, 0x8e, 0xcd, 0x01, 0xfb, 0x1a, 0xf9,
-	0xc4, 0x07, 0x50, 0x68, 0xca, 0xab, 0x5e, 0xbe, 0x37, 0xfc, 0x0f, 0x94, 0xfc, 0xff, 0x48, 0x1c,
-	0x0c, 0x04, 0xb3, 0x69, 0x5a, 0xf4, 0x61, 0xdb, 0xae, 0xbe, 0x06, 0xb9, 0x96, 0xc1, 0x9b, 0xa8,
-	0x31, 0xe4, 0xd4, 0x18, 0x72, 0x70, 0x8a, 0x16, 0x3a, 0x45, 0xff, 0x44, 0x03, 0x08, 0xa4, 0xfa,
-	0x65, 0x6e, 0xb1, 0x02, 0x79, 0x17, 0x99, 0x51, 0x25, 0xcc, 0x74, 0xa0, 0x08, 0x84, 0x4b, 0x7c,
-	0x85, 0x75, 0xa1, 0xbb, 0x93, 0x17, 0xc2, 0xa6, 0x95, 0x89, 0x95, 0x1d, 0x4a, 0xf0, 0x92, 0x6a,
-	0x80, 0x79, 0xf3, 0x5d, 0x98, 0x8e, 0xf5, 0x5f, 0xa4, 0x04, 0x85, 0x9d, 0xdd, 0x83, 0x26, 0xa5,
-	0xbb, 0xb4, 0x32, 0x41, 0x66, 0x61, 0x7a, 0x7b, 0xed, 0x9d, 0x83, 0xad, 0xcd, 0xfd, 0xe6, 0x41,
-	0x9b, 0xae, 0xdd, 0x6d, 0xb6, 0x2a, 0x1a, 0x07, 0xe2, 0xf8, 0xa0, 0xbd, 0xbb, 0x7b, 0xb0, 0xb5,
-	0x46, 0xef, 0x35, 0x2b, 0x29, 0x32, 0x03, 0xe5, 0xb7, 0x76, 0xde, 0xd8, 0xd9, 0x7d, 0x7b, 0x47,
-	0x6e, 0x4e, 0xdf, 0xbc, 0x09, 0xe5, 0x88, 0x99, 0x70, 0xda, 0x77, 0x77, 0xb7, 0xf7, 0xb6, 0x9a,
-	0xed, 0x66, 0x65, 0x82, 0x14, 0x21, 0xbf, 0xb7, 0x46, 0xdb, 0x9b, 0x6b, 0x5b, 0x15, 0xad, 0xf1,
-	0x73, 0x0d, 0x72, 0x9c, 0x15, 0xe6, 0x90, 0x6f, 0xc3, 0xa4, 0xdf, 0xf1, 0x91, 0xab, 0x91, 0x46,
-	0x31, 0xdc, 0x05, 0xd6, 0xae, 0x44, 0x96, 0x94, 0x4b, 0xe8, 0x13, 0x64, 0x0d, 0x8a, 0x3e, 0xf2,
-	0x7e, 0xe3, 0x71, 0x48, 0x34, 0xfe, 0xa5, 0x41, 0x25, 0xda, 0x7a, 0xd9, 0x3e, 0x63, 0xd8, 0xc5,
-	0xc5, 0xa8, 0x86, 0x5b, 0xc2, 0xb3, 0x19, 0xdb, 0x04, 0xb8, 0xc7, 0x3c, 0x55, 0x0b, 0x5f, 0x4b,
-	0x4e, 0xfe, 0x82, 0xc6, 0xf5, 0x33, 0x2a, 0x03, 0x45, 0xea, 0x1e, 0x40, 0x10, 0x0e, 0x48, 0x50,
-	0xcb, 0x8c, 0xc5, 0xfc, 0xda, 0xb5, 0xc4, 0x35, 0xff, 0xa6, 0xbf, 0xce, 0x40, 0x9e, 0x2f, 0x98,
-	0xcc, 0x21, 0xaf, 0x41, 0xf9, 0x55, 0xd3, 0xea, 0xfa, 0x7f, 0x54, 0x21, 0x09, 0xff, 0x91, 0x51,
-	0x64, 0x6b, 0x49, 0x4b, 0x21, 0x15, 0x94, 0xd4, 0x07, 0xe9, 0x0e, 0xb3, 0x3c, 0x72, 0xc6, 0xbf,
-	0x20, 0x6a, 0x4f, 0x8c, 0xc1, 0x7d, 0x12, 0x4d, 0x28, 0x86, 0xfe, 0x61, 0x11, 0x96, 0xd6, 0xd8,
-	0xff, 0x2e, 0xce, 0x23, 0x73, 0x0f, 0x20, 0x78, 0x1a, 0x24, 0xe7, 0x7c, 0xe8, 0xa8, 0x5d, 0x4b,
-	0x5c, 0xf3, 0x09, 0xbd, 0xa1, 0xae, 0x24, 0xde, 0x18, 0xcf, 0x25, 0xf5, 0x64, 0xe2, 0x3b, 0x67,
-	0x88, 0xd8, 0x3e, 0x4c, 0xc7, 0x1e, 0xb1, 0xc8, 0x45, 0x2f, 0xea, 0xb5, 0xa5, 0xb3, 0x11, 0x7c,
-	0xba, 0xdf, 0x0d, 0x3d, 0x84, 0xaa, 0xc7, 0xb1, 0x8b, 0x29, 0xeb, 0x67, 0x21, 0x84, 0x79, 0x6e,
-	0xfc, 0x35, 0x03, 0x95, 0x96, 0xe7, 0x30, 0x63, 0x60, 0x5a, 0x3d, 0x65, 0x32, 0x2f, 0x43, 0x4e,
-	0x66, 0xe3, 0x47, 0x55, 0xf1, 0xaa, 0xc6, 0xfd, 0xe1, 0x52, 0x74, 0xb3, 0xaa, 0x91, 0xed, 0x4b,
-	0xd4, 0xce, 0xaa, 0x46, 0xde, 0xf9, 0x6a, 0xf4, 0xb3, 0xaa, 0x91, 0x77, 0xbf, 0x3a, 0x0d, 0xad,
-	0x6a, 0x64, 0x0f, 0x66, 0x64, 0xac, 0xb8, 0x94, 0xe8, 0xb0, 0xaa, 0x91, 0x7d, 0x98, 0x0d, 0x53,
-	0x94, 0x75, 0x2d, 0xb9, 0x1e, 0xdd, 0x17, 0x6d, 0x02, 0x42, 0x12, 0x4e, 0x2a, 0xe6, 0x39, 0xdd,
-	0xc6, 0xef, 0x34, 0xc8, 0xab, 0x48, 0x78, 0x90, 0xd8, 0x8d, 0xeb, 0xe7, 0xf5, 0xa8, 0xf2, 0xa0,
-	0xa7, 0xce, 0xc5, 0xb9, 0xf4, 0x68, 0xb9, 0x5e, 0xfd, 0xe8, 0xf3, 0x05, 0xed, 0x93, 0xcf, 0x17,
-	0xb4, 0x7f, 0x7e, 0xbe, 0xa0, 0x7d, 0xf8, 0xc5, 0xc2, 0xc4, 0x27, 0x5f, 0x2c, 0x4c, 0x7c, 0xfa,
-	0xc5, 0xc2, 0xc4, 0x61, 0x0e, 0x9f, 0xd8, 0x9f, 0xff, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xf0,
-	0x4e, 0x3e, 0x6c, 0x3d, 0x2a, 0x00, 0x00,
+	// 3123 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x1a, 0x4d, 0x6f, 0x1b, 0xc7,
+	0x55, 0xcb, 0x6f, 0x3e, 0x92, 0x12, 0x39, 0x96, 0x15, 0x9a, 0x76, 0x24, 0x77, 0x63, 0x14, 0xaa,
+	0x93, 0x50, 0x32, 0xe3, 0xa0, 0x71, 0xd2, 0xa6, 0x95, 0x6c, 0xc6, 0x51, 0xa2, 0xaf, 0x0c, 0x19,
+	0x25, 0x28, 0x02, 0x08, 0x2b, 0x72, 0x4c, 0x2f, 0x44, 0xee, 0x32, 0xbb, 0x4b, 0x5b, 0xea, 0x21,
+	0x40, 0x5b, 0x14, 0x45, 0x81, 0x1e, 0x72, 0x68, 0x7e, 0x43, 0xd1, 0x5c, 0x7b, 0x68, 0x51, 0xa0,
+	0x97, 0x16, 0x28, 0xd2, 0x43, 0x80, 0x00, 0xbd, 0x04, 0x3d, 0xa4, 0x45, 0x72, 0xe8, 0x3f, 0xe8,
+	0xad, 0x40, 0xf1, 0xe6, 0x63, 0xbf, 0xb8, 0x92, 0x3f, 0xa2, 0xa0, 0x39, 0xe4, 0xc4, 0x79, 0x6f,
+	0xde, 0xbc, 0x79, 0x33, 0xef, 0x63, 0xde, 0x7b, 0x4b, 0x78, 0x62, 0x7c, 0x38, 0x58, 0xf1, 0xd8,
+	0x68, 0x6c, 0x8f, 0x0f, 0xc4, 0x6f, 0x73, 0xec, 0xd8, 0x9e, 0x4d, 0xf2, 0x12, 0xd9, 0x58, 0xe8,
+	0xd9, 0xa3, 0x91, 0x6d, 0xad, 0xdc, 0xbb, 0xb6, 0x22, 0x46, 0x82, 0xa0, 0xf1, 0xec, 0xc0, 0xf4,
+	0xee, 0x4e, 0x0e, 0x9a, 0x3d, 0x7b, 0xb4, 0x32, 0xb0, 0x07, 0xf6, 0x0a, 0x47, 0x1f, 0x4c, 0xee,
+	0x70, 0x88, 0x03, 0x7c, 0x24, 0xc9, 0xe7, 0x3d, 0xc7, 0xe8, 0x31, 0xe4, 0xc2, 0x07, 0x12, 0xbb,
+	0x34, 0xb0, 0xed, 0xc1, 0x90, 0x05, 0x6b, 0x3d, 0x73, 0xc4, 0x5c, 0xcf, 0x18, 0x8d, 0x05, 0x81,
+	0xfe, 0x1f, 0x0d, 0xaa, 0x5d, 0x5c, 0xb0, 0x7e, 0xbc, 0x71, 0x8b, 0xb2, 0x77, 0x27, 0xcc, 0xf5,
+	0x48, 0x1d, 0xf2, 0x9c, 0xc9, 0xc6, 0xad, 0xba, 0x76, 0x59, 0x5b, 0x2e, 0x53, 0x05, 0x92, 0x45,
+	0x80, 0x83, 0xa1, 0xdd, 0x3b, 0xec, 0x78, 0x86, 0xe3, 0xd5, 0x53, 0x97, 0xb5, 0xe5, 0x22, 0x0d,
+	0x61, 0x48, 0x03, 0x0a, 0x1c, 0x6a, 0x5b, 0xfd, 0x7a, 0x9a, 0xcf, 0xfa, 0x30, 0xb9, 0x04, 0xc5,
+	0x77, 0x27, 0xcc, 0x39, 0xde, 0xb2, 0xfb, 0xac, 0x9e, 0xe5, 0x93, 0x01, 0x82, 0x3c, 0x03, 0x35,
+	0x63, 0x38, 0xb4, 0xef, 0xef, 0x1a, 0x8e, 0x67, 0x1a, 0x43, 0x2e, 0x53, 0x3d, 0x77, 0x59, 0x5b,
+	0x2e, 0xd0, 0xe9, 0x09, 0xf2, 0x43, 0x28, 0xd0, 0x57, 0xae, 0xad, 0xdd, 0xf1, 0x98, 0x53, 0xcf,
+	0x5f, 0xd6, 0x96, 0x4b, 0xad, 0x46, 0x53, 0x1c, 0xb5, 0xa9, 0x8e, 0xda, 0xec, 0xaa, 0xa3, 0xae,
+	0x17, 0x3e, 0xfa, 0x6c, 0x69, 0xe6, 0xfd, 0x7f, 0x2e, 0x69, 0xd4, 0x5f, 0xa5, 0xff, 0x41, 0x83,
+	0x5a, 0xe8, 0xe0, 0xee, 0xd8, 0xb6, 0x5c, 0x46, 0xae, 0x40, 0x96, 0x1f, 0x95, 0x9f, 0xbb, 0xd4,
+	0x9a, 0x6d, 0x4a, 0x2d, 0x35, 0x39, 0x29, 0x15, 0x93, 0xe4, 0x39, 0xc8, 0x8f, 0x98, 0xe7, 0x98,
+	0x3d, 0x97, 0x5f, 0x41, 0xa9, 0x75, 0x21, 0x4a, 0x87, 0x2c, 0xb7, 0x04, 0x01, 0x55, 0x94, 0xa4,
+	0x09, 0x39, 0xd7, 0x33, 0xbc, 0x89, 0xcb, 0x2f, 0x66, 0xb6, 0xb5, 0xe0, 0xaf, 0x91, 0x27, 0xeb,
+	0xf0, 0x59, 0x2a, 0xa9, 0x50, 0x09, 0x23, 0xe6, 0xba, 0xc6, 0x80, 0xd5, 0x33, 0xfc, 0xb2, 0x14,
+	0xa8, 0xbf, 0x08, 0xd5, 0xf8, 0x36, 0xe4, 0xdb, 0x30, 0x6b, 0x5a, 0xee, 0x98, 0xf5, 0x3c, 0xd6,
+	0x5f, 0x3f, 0xf6, 0x98, 0xcb, 0x4f, 0x90, 0xa1, 0x31, 0xac, 0xfe, 0x61, 0x1a, 0x2a, 0x1d, 0x66,
+	0x38, 0xbd, 0xbb, 0x4a, 0xd9, 0x2f, 0x42, 0xa6, 0x6b, 0x0c, 0x90, 0x3e, 0xbd, 0x5c, 0x6a, 0x5d,
+	0xf6, 0xa5, 0x8a, 0x50, 0x35, 0x91, 0xa4, 0x6d, 0x79, 0xce, 0xf1, 0x7a, 0x06, 0x2f, 0x93, 0xf2,
+	0x35, 0xe4, 0x0a, 0x54, 0xb6, 0x4c, 0xeb, 0xd6, 0xc4, 0x31, 0x3c, 0xd3, 0xb6, 0xb6, 0xc4, 0x75,
+	0x54, 0x68, 0x14, 0xc9, 0xa9, 0x8c, 0xa3, 0x10, 0x55, 0x5a, 0x52, 0x85, 0x91, 0x64, 0x1e, 0xb2,
+	0x9b, 0xe6, 0xc8, 0xf4, 0xf8, 0x69, 0x2b, 0x54, 0x00, 0x88, 0x75, 0xb9, 0xad, 0x65, 0x05, 0x96,
+	0x03, 0xa4, 0x0a, 0x69, 0x66, 0xf5, 0xb9, 0x79, 0x54, 0x28, 0x0e, 0x91, 0xee, 0x0d, 0xb4, 0xa5,
+	0x7a, 0x81, 0xdf, 0x95, 0x00, 0xc8, 0x32, 0xcc, 0x75, 0xc6, 0x86, 0xe5, 0xee, 0x32, 0x07, 0x7f,
+	0x3b, 0xcc, 0xab, 0x17, 0xf9, 0x9a, 0x38, 0x3a, 0x62, 0x50, 0xf0, 0x38, 0x06, 0x85, 0xfa, 0xda,
+	0x71, 0xfa, 0xcc, 0x59, 0x3f, 0xae, 0x97, 0x84, 0xbe, 0x24, 0xd8, 0xf8, 0x2e, 0x14, 0xfd, 0xeb,
+	0x43, 0xd1, 0x0f, 0xd9, 0x31, 0xd7, 0x4e, 0x91, 0xe2, 0x10, 0x45, 0xbf, 0x67, 0x0c, 0x27, 0x4c,
+	0xba, 0x93, 0x00, 0x5e, 0x4c, 0xbd, 0xa0, 0xe9, 0x7f, 0x4d, 0x03, 0x11, 0x6a, 0x58, 0x47, 0x27,
+	0x52, 0x1a, 0xbb, 0x0e, 0x45, 0x57, 0x29, 0x47, 0x1a, 0xea, 0x42, 0xb2, 0xda, 0x68, 0x40, 0x88,
+	0xf2, 0x71, 0x57, 0xdc, 0xb8, 0x25, 0x37, 0x52, 0x20, 0x3a, 0x26, 0xbf, 0xd6, 0x5d, 0xb4, 0x35,
+	0xa1, 0x9b, 0x00, 0x81, 0xda, 0x1b, 0x1b, 0x03, 0xe6, 0x76, 0x6d, 0xc1, 0x5a, 0xea, 0x27, 0x8a,
+	0x44, 0xc7, 0x67, 0x56, 0xcf, 0xee, 0x9b, 0xd6, 0x40, 0xfa, 0xb6, 0x0f, 0x23, 0x07, 0xd3, 0xea,
+	0xb3, 0x23, 0x64, 0xd7, 0x31, 0x7f, 0xcc, 0xa4, 0xde, 0xa2, 0x48, 0xa2, 0x43, 0xd9, 0xb3, 0x3d,
+	0x63, 0x48, 0x59, 0xcf, 0x76, 0xfa, 0x2e, 0x77, 0xeb, 0x0a, 0x8d, 0xe0, 0x90, 0xa6, 0x6f, 0x78,
+	0x46, 0x5b, 0xed, 0x24, 0x94, 0x1d, 0xc1, 0xe1, 0x39, 0xef, 0x31, 0xc7, 0x35, 0x6d, 0x8b, 0xeb,
+	0xba, 0x48, 0x15, 0x48, 0x08, 0x64, 0x5c, 0xdc, 0x1e, 0xb8, 0x67, 0xf0, 0x31, 0x06, 0xb4, 0x3b,
+	0xb6, 0xed, 0x31, 0x87, 0x0b, 0x56, 0xe2, 0x7b, 0x86, 0x30, 0xe4, 0x16, 0x54, 0xfb, 0xac, 0x6f,
+	0xf6, 0x0c, 0x8f, 0xf5, 0x6f, 0xda, 0xc3, 0xc9, 0xc8, 0x72, 0xeb, 0x65, 0xee, 0x29, 0x75, 0xff,
+	0xca, 0x6f, 0x45, 0x09, 0xe8, 0xd4, 0x0a, 0xfd, 0x2f, 0x1a, 0xcc, 0xc5, 0xa8, 0xc8, 0x75, 0xc8,
+	0xba, 0x3d, 0x7b, 0xcc, 0x64, 0x38, 0x58, 0x3c, 0x89, 0x5d, 0xb3, 0x83, 0x54, 0x54, 0x10, 0xe3,
+	0x19, 0x2c, 0x63, 0xa4, 0x6c, 0x85, 0x8f, 0xc9, 0x35, 0xc8, 0x78, 0xc7, 0x63, 0x11, 0xb3, 0x66,
+	0x5b, 0x4f, 0x9e, 0xc8, 0xa8, 0x7b, 0x3c, 0x66, 0x94, 0x93, 0xea, 0x4b, 0x90, 0xe5, 0x6c, 0x49,
+	0x01, 0x32, 0x9d, 0xdd, 0xb5, 0xed, 0xea, 0x0c, 0x29, 0x43, 0x81, 0xb6, 0x3b, 0x3b, 0x6f, 0xd2,
+	0x9b, 0xed, 0xaa, 0xa6, 0x13, 0xc8, 0x20, 0x39, 0x01, 0xc8, 0x75, 0xba, 0x74, 0x63, 0xfb, 0x76,
+	0x75, 0x46, 0x3f, 0x82, 0x59, 0x65, 0x5d, 0x32, 0x5c, 0x5e, 0x87, 0x1c, 0x8f, 0x88, 0x2a, 0x7a,
+	0x5c, 0x8a, 0xc6, 0x41, 0x41, 0xbd, 0xc5, 0x3c, 0x03, 0x35, 0x44, 0x25, 0x2d, 0x59, 0x8d, 0x87,
+	0xcf, 0xb8, 0xf5, 0xc6, 0x63, 0xa7, 0xfe, 0xf7, 0x34, 0x9c, 0x4b, 0xe0, 0x18, 0x7f, 0xa8, 0x8a,
+	0xc1, 0x43, 0xb5, 0x0c, 0x73, 0x8e, 0x6d, 0x7b, 0x1d, 0xe6, 0xdc, 0x33, 0x7b, 0x6c, 0x3b, 0xb8,
+	0xb2, 0x38, 0x1a, 0xad, 0x13, 0x51, 0x9c, 0x3d, 0xa7, 0x13, 0xef, 0x56, 0x14, 0x89, 0xcf, 0x13,
+	0x77, 0x09, 0x8c, 0x01, 0x6f, 0x5a, 0xe6, 0xd1, 0xb6, 0x61, 0xd9, 0xdc, 0x13, 0x32, 0x74, 0x7a,
+	0x02, 0xad, 0xaa, 0x1f, 0x84, 0x3b, 0x11, 0xba, 0x42, 0x18, 0x72, 0x15, 0xf2, 0xae, 0x8c, 0x47,
+	0x39, 0x7e, 0x03, 0xd5, 0xe0, 0x06, 0x04, 0x9e, 0x2a, 0x02, 0xf2, 0x0c, 0x14, 0xe4, 0x10, 0x7d,
+	0x22, 0x9d, 0x48, 0xec, 0x53, 0x10, 0x0a, 0x65, 0x57, 0x1c, 0x0e, 0x9f, 0x13, 0xb7, 0x5e, 0xe0,
+	0x2b, 0x9a, 0xa7, 0xe9, 0xa5, 0xd9, 0x09, 0x2d, 0xe0, 0x41, 0x8a, 0x46, 0x78, 0x34, 0xf6, 0xa0,
+	0x36, 0x45, 0x92, 0x10, 0xc7, 0x9e, 0x0e, 0xc7, 0xb1, 0x52, 0xeb, 0x7c, 0x48, 0xa9, 0xc1, 0xe2,
+	0x70, 0x78, 0xdb, 0x84, 0x72, 0x78, 0x8a, 0xc7, 0xa1, 0xb1, 0x61, 0xdd, 0xb4, 0x27, 0x96, 0x57,
+	0xd7, 0x64, 0x1c, 0x52, 0x08, 0xbc, 0x53, 0xe6, 0x38, 0xb6, 0x23, 0xa6, 0xc5, 0x43, 0x13, 0xc2,
+	0xe8, 0x3f, 0xd7, 0x20, 0xaf, 0xa2, 0xf9, 0x53, 0x90, 0xc5, 0x85, 0xca, 0x2c, 0x2b, 0x91, 0x0b,
+	0xa3, 0x62, 0x8e, 0x3f, 0xb0, 0x86, 0xd7, 0xbb, 0xcb, 0xfa, 0x92, 0x9b, 0x02, 0xc9, 0x4b, 0x00,
+	0x86, 0xe7, 0x39, 0xe6, 0xc1, 0x04, 0x1f, 0xd2, 0x34, 0xe7, 0x71, 0xd1, 0xe7, 0x21, 0xb3, 0xb4,
+	0x7b, 0xd7, 0x9a, 0xaf, 0xb3, 0xe3, 0x3d, 0x3c, 0x0d, 0x0d, 0x91, 0xa3, 0xaf, 0x67, 0x70, 0x1b,
+	0xb2, 0x00, 0x39, 0xdc, 0xc8, 0xb7, 0x4d, 0x09, 0x25, 0xba, 0x70, 0xa2, 0x79, 0xa5, 0x4f, 0x32,
+	0xaf, 0x2b, 0x50, 0x51, 0xc6, 0x84, 0xb0, 0x2b, 0x0d, 0x31, 0x8a, 0x8c, 0x9d, 0x22, 0xfb, 0x68,
+	0xa7, 0xf8, 0x7d, 0x0a, 0x2a, 0x11, 0x67, 0x44, 0x8f, 0xf2, 0x73, 0x89, 0xae, 0x72, 0x7a, 0xfe,
+	0x96, 0xc6, 0xd0, 0x09, 0xb9, 0x48, 0x2a, 0x29, 0x17, 0x21, 0x97, 0xa1, 0xc4, 0xa3, 0x3b, 0x7f,
+	0xdc, 0x54, 0x56, 0x10, 0x46, 0xe1, 0x41, 0x7b, 0xf6, 0x68, 0x3c, 0x64, 0x1e, 0xeb, 0xbf, 0x66,
+	0x1f, 0xb8, 0xea, 0xed, 0x89, 0x20, 0xd1, 0x6e, 0xf8, 0x22, 0x4e, 0x21, 0x9c, 0x2d, 0x40, 0xa0,
+	0xdc, 0x01, 0x4b, 0x21, 0x4e, 0x8e, 0x8b, 0x13, 0x47, 0x47, 0xe4, 0xe6, 0xf9, 0x41, 0x3d, 0x1f,
+	0x93, 0x9b, 0x63, 0x71, 0x3f, 0xe6, 0x7a, 0xe6, 0x08, 0x43, 0x2b, 0x7f, 0x82, 0x0a, 0x34, 0x40,
+	0xe8, 0xbf, 0x48, 0x41, 0x4d, 0xdc, 0x1c, 0x3e, 0xfa, 0xea, 0xcd, 0x9e, 0x57, 0xd1, 0x5e, 0xd8,
+	0x82, 0x00, 0x10, 0xcb, 0x33, 0x60, 0xf5, 0xf4, 0x73, 0x20, 0xc8, 0x79, 0xd2, 0x09, 0x39, 0x4f,
+	0x26, 0xc8, 0x79, 0x96, 0x61, 0x6e, 0x64, 0x1c, 0xe1, 0x2e, 0x98, 0xc8, 0x70, 0xee, 0xe2, 0xf4,
+	0x71, 0x34, 0x69, 0xc1, 0xbc, 0xeb, 0x19, 0x43, 0xc6, 0xf5, 0xec, 0x76, 0xef, 0x3a, 0xcc, 0xbd,
+	0x6b, 0x0f, 0x55, 0x02, 0x95, 0x38, 0x77, 0x06, 0x29, 0xf6, 0x87, 0x19, 0x58, 0x08, 0x6e, 0x22,
+	0x92, 0xc2, 0xbc, 0x30, 0x9d, 0xc2, 0x34, 0x62, 0x8f, 0x40, 0xe8, 0xf6, 0xbe, 0x49, 0x63, 0xbe,
+	0x16, 0x69, 0x4c, 0x92, 0xc1, 0x55, 0x92, 0x0d, 0x6e, 0x15, 0xce, 0x05, 0x46, 0x15, 0xd8, 0xdb,
+	0x2c, 0xa7, 0x4e, 0x9a, 0xd2, 0x3f, 0x4d, 0xc3, 0x45, 0x5f, 0xf1, 0x7c, 0x2e, 0x6a, 0x31, 0xdf,
+	0x9f, 0xb6, 0x98, 0xa5, 0x69, 0x8b, 0x11, 0x0b, 0xbf, 0x31, 0x9b, 0xaf, 0x55, 0xf6, 0xdb, 0x57,
+	0x55, 0x8c, 0x70, 0x69, 0x99, 0x3b, 0x36, 0xa0, 0xe0, 0x19, 0x03, 0x4c, 0xae, 0xc4, 0x33, 0x5d,
+	0xa4, 0x3e, 0x4c, 0x5a, 0xf1, 0x0c, 0x31, 0xd8, 0x4e, 0x65, 0x2d, 0x53, 0x39, 0xe2, 0x7b, 0x30,
+	0x1f, 0xec, 0xb2, 0xd7, 0xf2, 0xf7, 0x69, 0x41, 0x8e, 0x07, 0x5b, 0x95, 0x0c, 0x24, 0xc5, 0x99,
+	0xbd, 0x96, 0x48, 0xb2, 0x25, 0xe5, 0x63, 0xed, 0xff, 0x12, 0xd4, 0xa6, 0x18, 0xfa, 0x6f, 0xbd,
+	0x16, 0x7a, 0xeb, 0x09, 0x64, 0x3c, 0x2c, 0xb8, 0x53, 0xfc, 0xd0, 0x7c, 0xac, 0xff, 0x31, 0x05,
+	0x0b, 0xc9, 0x46, 0xcc, 0x73, 0x5c, 0x71, 0x2f, 0x7e, 0x8e, 0x2b, 0xc0, 0x07, 0xbd, 0x1e, 0x99,
+	0x84, 0xd7, 0x23, 0x1b, 0xbc, 0x1e, 0x3a, 0x94, 0x85, 0xd7, 0x8a, 0xed, 0xa4, 0x59, 0x46, 0x70,
+	0x27, 0xb9, 0x71, 0xfe, 0x44, 0x37, 0x8e, 0xbc, 0x1a, 0x85, 0xc7, 0xaa, 0xa3, 0xe7, 0x21, 0x6b,
+	0xf0, 0xe5, 0xc2, 0x7e, 0x05, 0x80, 0xd6, 0x32, 0x56, 0x0e, 0x04, 0x7c, 0x7b, 0x1f, 0xd6, 0x0f,
+	0xe1, 0x89, 0xa9, 0xbb, 0x93, 0xca, 0xc7, 0xd4, 0xc0, 0x3f, 0xa1, 0xb0, 0xb2, 0x00, 0xf1, 0x58,
+	0x6a, 0xbe, 0x0e, 0x05, 0xb5, 0x0d, 0x21, 0xa1, 0xc2, 0xab, 0x28, 0x2a, 0xab, 0xe4, 0x6a, 0x1e,
+	0xbb, 0x4d, 0x17, 0x62, 0x32, 0x86, 0x4c, 0x74, 0x25, 0x2e, 0x65, 0xa9, 0x55, 0x0b, 0x32, 0x76,
+	0x39, 0xf3, 0x25, 0x05, 0xc7, 0xab, 0xb0, 0xd8, 0x91, 0xd7, 0xb5, 0x0f, 0x99, 0x25, 0x6b, 0x9c,
+	0x00, 0x81, 0x56, 0x76, 0xdf, 0x70, 0x2c, 0x0c, 0x2b, 0xb2, 0xdb, 0x24, 0x41, 0xfd, 0x6f, 0x1a,
+	0xcc, 0xc5, 0x98, 0x3e, 0x6c, 0xb7, 0x29, 0x9a, 0x99, 0xa5, 0xe2, 0x99, 0xd9, 0x54, 0x76, 0x97,
+	0x4e, 0xca, 0xee, 0x62, 0x59, 0x62, 0x66, 0x3a, 0x4b, 0x4c, 0xc8, 0xf0, 0xb2, 0x89, 0x19, 0x9e,
+	0xbe, 0x0d, 0x59, 0xd1, 0x3f, 0x6c, 0x43, 0xc5, 0x61, 0xae, 0x3d, 0x71, 0x7a, 0xac, 0x13, 0x2a,
+	0x14, 0x82, 0x17, 0x45, 0x34, 0x51, 0xef, 0x5d, 0x6b, 0xd2, 0x30, 0x19, 0x8d, 0xae, 0xd2, 0xb7,
+	0xa1, 0xbc, 0x3b, 0x71, 0x83, 0x7a, 0xf8, 0x65, 0xa8, 0xf0, 0x8a, 0xc4, 0x5d, 0x3f, 0xee, 0xca,
+	0x36, 0x62, 0x7a, 0x79, 0x36, 0xa4, 0x1d, 0xa4, 0x6e, 0x23, 0x05, 0x65, 0x86, 0x6b, 0x5b, 0x34,
+	0x4a, 0xae, 0xff, 0x52, 0x83, 0x2a, 0x92, 0x70, 0x69, 0x55, 0x00, 0x78, 0xd6, 0x2f, 0xb2, 0x31,
+	0x62, 0x94, 0xd7, 0xcf, 0xa3, 0xd3, 0xfc, 0xe3, 0xb3, 0xa5, 0xca, 0xae, 0xc3, 0xb0, 0x33, 0xda,
+	0x13, 0xd4, 0x92, 0x08, 0x3d, 0xdd, 0xec, 0x8b, 0xaa, 0xa5, 0x4c, 0x71, 0x48, 0xae, 0xc3, 0x79,
+	0xf7, 0xd0, 0x1c, 0x4b, 0xe5, 0xdd, 0x66, 0x16, 0x13, 0x65, 0x02, 0xbf, 0xa5, 0x02, 0x4d, 0x9e,
+	0xd4, 0x7f, 0x26, 0x65, 0x11, 0x07, 0x97, 0xb2, 0xdc, 0x80, 0xfc, 0x01, 0x2f, 0x92, 0x1e, 0xfa,
+	0xc6, 0x14, 0xfd, 0xc9, 0x52, 0xa4, 0x4e, 0x93, 0xe2, 0x0a, 0x80, 0xec, 0x75, 0xa2, 0x3d, 0x2d,
+	0x44, 0xfa, 0x0d, 0x65, 0x75, 0x66, 0xfd, 0x65, 0x28, 0x6e, 0x9a, 0xd6, 0x61, 0x67, 0x68, 0xf6,
+	0xb0, 0x1d, 0x92, 0x1d, 0x9a, 0xd6, 0xa1, 0x92, 0xf0, 0xe2, 0xb4, 0x84, 0x28, 0x59, 0x13, 0x17,
+	0x50, 0x41, 0xa9, 0xff, 0x54, 0x03, 0x82, 0x48, 0xe5, 0x34, 0x41, 0xd2, 0x2e, 0x02, 0xac, 0x16,
+	0x0e, 0xb0, 0x75, 0xc8, 0x0f, 0x1c, 0x7b, 0x32, 0x5e, 0x57, 0x81, 0x57, 0x81, 0x48, 0x3f, 0xe4,
+	0x2d, 0x4c, 0x51, 0xb9, 0x09, 0xe0, 0x61, 0x03, 0x32, 0x2a, 0xff, 0x42, 0x48, 0x88, 0xce, 0x64,
+	0x34, 0x32, 0x9c, 0xe3, 0xff, 0x8f, 0x2c, 0xbf, 0xd5, 0xe0, 0x5c, 0xe4, 0x42, 0x82, 0x78, 0x1a,
+	0x94, 0x3e, 0x5a, 0xac, 0xf4, 0x89, 0x16, 0xf0, 0xa2, 0xe6, 0x0b, 0x10, 0x18, 0x34, 0xb8, 0xb5,
+	0x77, 0x7c, 0x12, 0x21, 0x5a, 0x0c, 0x4b, 0x9a, 0x41, 0x70, 0xcb, 0x70, 0x0d, 0xce, 0x47, 0xca,
+	0xf7, 0xa9, 0x88, 0xfc, 0x3d, 0x28, 0x53, 0xe3, 0xfe, 0xab, 0xa6, 0xeb, 0xd9, 0x03, 0xc7, 0x18,
+	0xa1, 0x91, 0x1c, 0x4c, 0x7a, 0x87, 0xcc, 0x93, 0x41, 0x49, 0x42, 0x78, 0xf6, 0x5e, 0x48, 0x32,
+	0x01, 0xe8, 0xaf, 0x41, 0x41, 0x15, 0xc0, 0x09, 0x3d, 0x8d, 0x67, 0xa2, 0x3d, 0x8d, 0x85, 0x68,
+	0x1f, 0xe5, 0x8d, 0xcd, 0x8e, 0x67, 0x78, 0x66, 0x4f, 0x45, 0xf9, 0x5f, 0x6b, 0x50, 0x0a, 0x89,
+	0x48, 0xd6, 0xa1, 0x36, 0x34, 0x3c, 0x66, 0xf5, 0x8e, 0xf7, 0xef, 0x2a, 0xf1, 0xa4, 0x55, 0x06,
+	0xdd, 0x91, 0xb0, 0xec, 0xb4, 0x2a, 0xe9, 0x83, 0xd3, 0x7c, 0x07, 0x72, 0x2e, 0x73, 0x4c, 0xe9,
+	0xfd, 0xe1, 0x87, 0xc1, 0xaf, 0xdb, 0x25, 0x01, 0x1e, 0x5c, 0x84, 0x13, 0x79, 0xb1, 0x12, 0xd2,
+	0x3f, 0x8e, 0x5a, 0xb7, 0x34, 0xac, 0xe9, 0x76, 0xcb, 0x03, 0xb4, 0x95, 0x4a, 0xd4, 0x56, 0x20,
+	0x5f, 0xfa, 0x41, 0xf2, 0x55, 0x21, 0x3d, 0xbe, 0x71, 0x43, 0x36, 0x2b, 0x70, 0x28, 0x30, 0xcf,
+	0xcb, 0x68, 0x8d, 0x43, 0x81, 0x59, 0x95, 0x15, 0x3a, 0x0e, 0x39, 0xe6, 0xf9, 0x55, 0x59, 0x8a,
+	0xe3, 0x50, 0x7f, 0x0b, 0x1a, 0x49, 0x7e, 0x22, 0x4d, 0xf4, 0x06, 0x14, 0x5d, 0x8e, 0x32, 0xd9,
+	0x74, 0x08, 0x48, 0x58, 0x17, 0x50, 0xeb, 0x1f, 0x68, 0x50, 0x89, 0x28, 0x36, 0xf2, 0xc2, 0x67,
+	0xe5, 0x0b, 0x5f, 0x06, 0x4d, 0x04, 0xad, 0x34, 0xd5, 0x2c, 0x84, 0xee, 0xf0, 0xfb, 0xd6, 0xa8,
+	0x76, 0x07, 0x21, 0x57, 0x3e, 0xa0, 0x9a, 0x8b, 0xd0, 0x81, 0x0c, 0xb2, 0xda, 0x01, 0x42, 0x7d,
+	0x79, 0x30, 0xad, 0x8f, 0xca, 0x92, 0x9f, 0x83, 0xf2, 0x9c, 0xb7, 0x84, 0x70, 0xc7, 0x43, 0xd3,
+	0x12, 0x7d, 0x85, 0x2c, 0xe5, 0x63, 0x9d, 0xc1, 0x5c, 0x48, 0xf0, 0x5b, 0x86, 0x67, 0x60, 0xe6,
+	0xee, 0x30, 0x77, 0x32, 0xf4, 0xba, 0x41, 0x02, 0x12, 0xc2, 0x60, 0xd6, 0x2b, 0xa0, 0x7a, 0x2a,
+	0x9e, 0xf5, 0x46, 0xdc, 0x7a, 0x32, 0xf4, 0xa8, 0xa4, 0xc4, 0x28, 0x58, 0x9b, 0x9a, 0x45, 0x33,
+	0x19, 0x1a, 0x07, 0x6c, 0x18, 0xca, 0x40, 0x03, 0x04, 0xca, 0xc1, 0x81, 0xbd, 0x50, 0xce, 0x13,
+	0xc2, 0x90, 0x15, 0x48, 0x79, 0xca, 0x34, 0x96, 0x4e, 0x96, 0x61, 0xd7, 0x36, 0x2d, 0x8f, 0xa6,
+	0x3c, 0x17, 0x7d, 0x68, 0x21, 0x79, 0x9a, 0x2b, 0xc3, 0x94, 0x42, 0x54, 0x28, 0x1f, 0xa3, 0x75,
+	0xdc, 0x33, 0x86, 0x7c, 0x63, 0x8d, 0xe2, 0x10, 0xb3, 0x01, 0x76, 0xc4, 0x46, 0xe3, 0xa1, 0xe1,
+	0x74, 0x65, 0x6f, 0x38, 0xcd, 0x3f, 0x62, 0xc6, 0xd1, 0xe4, 0x2a, 0x54, 0x15, 0x4a, 0x7d, 0x87,
+	0x92, 0xc6, 0x39, 0x85, 0xd7, 0x3b, 0x70, 0x8e, 0x7f, 0x52, 0xda, 0xb0, 0x5c, 0xcf, 0xb0, 0xbc,
+	0xd3, 0xa3, 0xb2, 0x1f, 0x65, 0x65, 0xa4, 0x89, 0x44, 0x59, 0xe1, 0x9b, 0x38, 0xd4, 0xff, 0xac,
+	0xc1, 0x7c, 0x94, 0xab, 0xb4, 0xe1, 0xa6, 0xef, 0x54, 0xc2, 0x80, 0x83, 0xb8, 0x23, 0x29, 0x3b,
+	0x7c, 0xd6, 0xf7, 0xac, 0x47, 0xee, 0xa8, 0x9f, 0xe1, 0xd7, 0xc8, 0x9f, 0x68, 0x50, 0x89, 0x48,
+	0x45, 0x6e, 0x40, 0x8e, 0x5b, 0xc0, 0xb4, 0xfb, 0x4d, 0x37, 0x1d, 0xe5, 0xe7, 0x44, 0xb9, 0x20,
+	0x9a, 0x3d, 0x6b, 0x32, 0xae, 0x92, 0x25, 0x28, 0x8d, 0x1d, 0x7b, 0xb4, 0x2f, 0xb9, 0x8a, 0xe4,
+	0x15, 0x10, 0xb5, 0xc9, 0x31, 0xfa, 0xc7, 0x69, 0xa8, 0xf1, 0x8b, 0xa4, 0x86, 0x35, 0x60, 0x67,
+	0xa2, 0x1c, 0x5e, 0x2f, 0x7b, 0x6c, 0x2c, 0x2d, 0x82, 0x8f, 0xa3, 0x9f, 0xb0, 0xf3, 0xf1, 0x4f,
+	0xd8, 0xa1, 0x1e, 0x43, 0xe1, 0x94, 0x1e, 0x43, 0xf1, 0x81, 0x3d, 0x06, 0x48, 0xea, 0x31, 0x84,
+	0x2a, 0xfb, 0x52, 0xb4, 0xb2, 0x0f, 0x77, 0x1f, 0xca, 0xb1, 0xee, 0x83, 0xaa, 0xfa, 0x2b, 0x27,
+	0x56, 0xfd, 0xb3, 0x0f, 0x55, 0xf5, 0xcf, 0x3d, 0x72, 0xb3, 0x08, 0x53, 0x05, 0xe9, 0x45, 0x6e,
+	0xbd, 0x2a, 0xce, 0xec, 0x23, 0x70, 0x76, 0x64, 0x1c, 0x09, 0x83, 0xa9, 0xd7, 0xc4, 0xac, 0x8f,
+	0xd0, 0xff, 0xa4, 0x01, 0x09, 0xeb, 0x53, 0xba, 0xc5, 0xd3, 0x31, 0xb7, 0x38, 0x17, 0x3c, 0xc7,
+	0xe6, 0x88, 0x7d, 0x8d, 0x7c, 0xe2, 0x3d, 0x28, 0xb4, 0xe5, 0x51, 0xcf, 0xde, 0x1b, 0xbe, 0x05,
+	0x65, 0xff, 0x5f, 0x1c, 0xfb, 0x23, 0x21, 0x6c, 0x9a, 0x96, 0x7c, 0xdc, 0x96, 0xab, 0xaf, 0x41,
+	0xae, 0x63, 0x60, 0x11, 0x35, 0x45, 0x9c, 0x9a, 0x22, 0x0e, 0x76, 0xd1, 0x42, 0xbb, 0xe8, 0xff,
+	0xd5, 0x00, 0x82, 0x5b, 0xfd, 0x32, 0xa7, 0x58, 0x81, 0xbc, 0xcb, 0x85, 0x51, 0x29, 0xcc, 0x5c,
+	0xa0, 0x08, 0x8e, 0x97, 0xf4, 0x8a, 0xea, 0x81, 0xee, 0x4e, 0x9e, 0x0f, 0x9b, 0x56, 0x26, 0x96,
+	0x76, 0xa8, 0x8b, 0x97, 0x5c, 0x03, 0x4a, 0xf2, 0x34, 0xd4, 0xf8, 0x16, 0xa6, 0x35, 0xd8, 0xbf,
+	0xcf, 0xcc, 0xc1, 0x5d, 0x4c, 0x62, 0xc5, 0xf3, 0x5c, 0x55, 0x13, 0x6f, 0x49, 0xfc, 0xd5, 0x77,
+	0x60, 0x2e, 0x56, 0xac, 0xe1, 0x17, 0xd2, 0xed, 0x9d, 0xfd, 0x36, 0xa5, 0x3b, 0xb4, 0x3a, 0x43,
+	0xce, 0xc1, 0xdc, 0xd6, 0xda, 0xdb, 0xfb, 0x9b, 0x1b, 0x7b, 0xed, 0xfd, 0x2e, 0x5d, 0xbb, 0xd9,
+	0xee, 0x54, 0x35, 0x44, 0xf2, 0xf1, 0x7e, 0x77, 0x67, 0x67, 0x7f, 0x73, 0x8d, 0xde, 0x6e, 0x57,
+	0x53, 0xa4, 0x06, 0x95, 0x37, 0xb7, 0x5f, 0xdf, 0xde, 0x79, 0x6b, 0x5b, 0x2e, 0x4e, 0x5f, 0xbd,
+	0x0a, 0x95, 0x88, 0x4d, 0x21, 0xef, 0x9b, 0x3b, 0x5b, 0xbb, 0x9b, 0xed, 0x6e, 0xbb, 0x3a, 0x43,
+	0x4a, 0x90, 0xdf, 0x5d, 0xa3, 0xdd, 0x8d, 0xb5, 0xcd, 0xaa, 0xd6, 0xfa, 0x95, 0x06, 0x39, 0x14,
+	0x85, 0x39, 0xe4, 0x07, 0x50, 0xf4, 0xcb, 0x43, 0x72, 0x21, 0x52, 0x55, 0x86, 0x4b, 0xc6, 0xc6,
+	0xf9, 0xc8, 0x94, 0xf2, 0x1f, 0x7d, 0x86, 0xac, 0x41, 0xc9, 0x27, 0xde, 0x6b, 0x3d, 0x0e, 0x8b,
+	0xd6, 0xbf, 0x35, 0xa8, 0x46, 0xeb, 0x34, 0xdb, 0x17, 0x4c, 0x7c, 0x1f, 0x89, 0x72, 0x0d, 0xd7,
+	0x8f, 0x27, 0x0b, 0xb6, 0x01, 0x70, 0x9b, 0x79, 0x92, 0x2f, 0xb9, 0x98, 0x9c, 0x29, 0x08, 0x1e,
+	0x97, 0x92, 0x27, 0x7d, 0x56, 0xb7, 0x01, 0x82, 0xd8, 0x41, 0x82, 0xc4, 0x67, 0xea, 0x81, 0x68,
+	0x5c, 0x4c, 0x9c, 0xf3, 0x4f, 0xfa, 0x9b, 0x0c, 0xe4, 0x71, 0xc2, 0x64, 0x0e, 0x79, 0x15, 0x2a,
+	0xaf, 0x98, 0x56, 0xdf, 0xff, 0xdf, 0x0d, 0x49, 0xf8, 0xcb, 0x8f, 0x62, 0xdb, 0x48, 0x9a, 0x0a,
+	0xa9, 0xa0, 0xac, 0xbe, 0xa2, 0xf7, 0x98, 0xe5, 0x91, 0x13, 0xfe, 0xba, 0xd1, 0x78, 0x62, 0x0a,
+	0xef, 0xb3, 0x68, 0x43, 0x29, 0xf4, 0xb7, 0x90, 0xf0, 0x6d, 0x4d, 0xfd, 0x59, 0xe4, 0x34, 0x36,
+	0xb7, 0x01, 0x82, 0x8e, 0x25, 0x39, 0xe5, 0xfb, 0x4b, 0xe3, 0x62, 0xe2, 0x9c, 0xcf, 0xe8, 0x75,
+	0x28, 0x07, 0xf8, 0xbd, 0xd6, 0xa9, 0xac, 0x9e, 0x4c, 0x6c, 0xbf, 0x86, 0x98, 0xed, 0xc1, 0x5c,
+	0xac, 0x53, 0x46, 0x1e, 0xd4, 0xe8, 0x6f, 0x5c, 0x3e, 0x99, 0xc0, 0xe7, 0xfb, 0x23, 0xa8, 0xc5,
+	0x26, 0xf7, 0x5a, 0x0f, 0xe6, 0xac, 0x9f, 0x44, 0x10, 0x96, 0xb9, 0xf5, 0x41, 0x16, 0xaa, 0x1d,
+	0xcf, 0x61, 0xc6, 0xc8, 0xb4, 0x06, 0xca, 0x64, 0x5e, 0x82, 0x9c, 0x58, 0xf3, 0xc8, 0x2a, 0x5e,
+	0xd5, 0xd0, 0x1f, 0xce, 0x44, 0x37, 0xab, 0x1a, 0xd9, 0x3a, 0x43, 0xed, 0xac, 0x6a, 0xe4, 0xed,
+	0xaf, 0x46, 0x3f, 0xab, 0x1a, 0x79, 0xe7, 0xab, 0xd3, 0xd0, 0xaa, 0x46, 0x76, 0xa1, 0x26, 0x63,
+	0xc5, 0x99, 0x44, 0x87, 0x55, 0x8d, 0xec, 0xc1, 0xb9, 0x30, 0x47, 0x99, 0x04, 0x93, 0x4b, 0xd1,
+	0x75, 0xd1, 0x8a, 0xa1, 0xf1, 0xe4, 0x09, 0xb3, 0x21, 0xbe, 0x67, 0x14, 0x6b, 0x56, 0xb5, 0xd6,
+	0xef, 0x34, 0xc8, 0xab, 0x98, 0xba, 0x9f, 0xd8, 0x04, 0xd0, 0x4f, 0x2b, 0x8d, 0xe5, 0x1e, 0x4f,
+	0x9d, 0x4a, 0x73, 0xe6, 0x71, 0x77, 0xbd, 0xfe, 0xd1, 0xe7, 0x8b, 0xda, 0x27, 0x9f, 0x2f, 0x6a,
+	0xff, 0xfa, 0x7c, 0x51, 0x7b, 0xff, 0x8b, 0xc5, 0x99, 0x4f, 0xbe, 0x58, 0x9c, 0xf9, 0xf4, 0x8b,
+	0xc5, 0x99, 0x83, 0x1c, 0xff, 0x86, 0xf0, 0xdc, 0xff, 0x06, 0x00, 0xf1, 0x0e, 0xe5, 0x99, 0x56,
+	0x2b, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -4164,6 +4449,7 @@ type QuerierClient interface {
 	SearchTagsV2(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsV2Response, error)
 	SearchTagValues(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesResponse, error)
 	SearchTagValuesV2(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesV2Response, error)
+	Tail(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (Querier_TailClient, error)
 }
 
 type querierClient struct {
@@ -4237,6 +4523,38 @@ func (c *querierClient) SearchTagValuesV2(ctx context.Context, in *SearchTagValu
 	return out, nil
 }
 
+func (c *querierClient) Tail(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (Querier_TailClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Querier_serviceDesc.Streams[0], "/tempopb.Querier/Tail", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &querierTailClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Querier_TailClient interface {
+	Recv() (*SearchResponse, error)
+	grpc.ClientStream
+}
+
+type querierTailClient struct {
+	grpc.ClientStream
+}
+
+func (x *querierTailClient) Recv() (*SearchResponse, error) {
+	m := new(SearchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // QuerierServer is the server API for Querier service.
 type QuerierServer interface {
 	FindTraceByID(context.Context, *TraceByIDRequest) (*TraceByIDResponse, error)
@@ -4246,6 +4564,7 @@ type QuerierServer interface {
 	SearchTagsV2(context.Context, *SearchTagsRequest) (*SearchTagsV2Response, error)
 	SearchTagValues(context.Context, *SearchTagValuesRequest) (*SearchTagValuesResponse, error)
 	SearchTagValuesV2(context.Context, *SearchTagValuesRequest) (*SearchTagValuesV2Response, error)
+	Tail(*SearchRequest, Querier_TailServer) error
 }
 
 // UnimplementedQuerierServer can be embedded to have forward compatible implementations.
@@ -4273,6 +4592,9 @@ func (*UnimplementedQuerierServer) SearchTagValues(ctx context.Context, req *Sea
 func (*UnimplementedQuerierServer) SearchTagValuesV2(ctx context.Context, req *SearchTagValuesRequest) (*SearchTagValuesV2Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SearchTagValuesV2 not implemented")
 }
+func (*UnimplementedQuerierServer) Tail(req *SearchRequest, srv Querier_TailServer) error {
+	return status.Errorf(codes.Unimplemented, "method Tail not implemented")
+}
 
 func RegisterQuerierServer(s *grpc.Server, srv QuerierServer) {
 	s.RegisterService(&_Querier_serviceDesc, srv)
@@ -4404,6 +4726,27 @@ func _Querier_SearchTagValuesV2_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Querier_Tail_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QuerierServer).Tail(m, &querierTailServer{stream})
+}
+
+type Querier_TailServer interface {
+	Send(*SearchResponse) error
+	grpc.ServerStream
+}
+
+type querierTailServer struct {
+	grpc.ServerStream
+}
+
+func (x *querierTailServer) Send(m *SearchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Querier_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "tempopb.Querier",
 	HandlerType: (*QuerierServer)(nil),
@@ -4437,7 +4780,13 @@ var _Querier_serviceDesc = grpc.ServiceDesc{
 			Handler:    _Querier_SearchTagValuesV2_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Tail",
+			Handler:       _Querier_Tail_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "pkg/tempopb/tempo.proto",
 }
 
@@ -4452,6 +4801,7 @@ type StreamingQuerierClient interface {
 	SearchTagValuesV2(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (StreamingQuerier_SearchTagValuesV2Client, error)
 	MetricsQueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (StreamingQuerier_MetricsQueryRangeClient, error)
 	MetricsQueryInstant(ctx context.Context, in *QueryInstantRequest, opts ...grpc.CallOption) (StreamingQuerier_MetricsQueryInstantClient, error)
+	FindTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (StreamingQuerier_FindTraceByIDClient, error)
 }
 
 type streamingQuerierClient struct {
@@ -4686,6 +5036,38 @@ func (x *streamingQuerierMetricsQueryInstantClient) Recv() (*QueryInstantRespons
 	return m, nil
 }
 
+func (c *streamingQuerierClient) FindTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (StreamingQuerier_FindTraceByIDClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StreamingQuerier_serviceDesc.Streams[7], "/tempopb.StreamingQuerier/FindTraceByID", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamingQuerierFindTraceByIDClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StreamingQuerier_FindTraceByIDClient interface {
+	Recv() (*TraceByIDResponse, error)
+	grpc.ClientStream
+}
+
+type streamingQuerierFindTraceByIDClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamingQuerierFindTraceByIDClient) Recv() (*TraceByIDResponse, error) {
+	m := new(TraceByIDResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // StreamingQuerierServer is the server API for StreamingQuerier service.
 type StreamingQuerierServer interface {
 	Search(*SearchRequest, StreamingQuerier_SearchServer) error
@@ -4695,6 +5077,7 @@ type StreamingQuerierServer interface {
 	SearchTagValuesV2(*SearchTagValuesRequest, StreamingQuerier_SearchTagValuesV2Server) error
 	MetricsQueryRange(*QueryRangeRequest, StreamingQuerier_MetricsQueryRangeServer) error
 	MetricsQueryInstant(*QueryInstantRequest, StreamingQuerier_MetricsQueryInstantServer) error
+	FindTraceByID(*TraceByIDRequest, StreamingQuerier_FindTraceByIDServer) error
 }
 
 // UnimplementedStreamingQuerierServer can be embedded to have forward compatible implementations.
@@ -4722,6 +5105,9 @@ func (*UnimplementedStreamingQuerierServer) MetricsQueryRange(req *QueryRangeReq
 func (*UnimplementedStreamingQuerierServer) MetricsQueryInstant(req *QueryInstantRequest, srv StreamingQuerier_MetricsQueryInstantServer) error {
 	return status.Errorf(codes.Unimplemented, "method MetricsQueryInstant not implemented")
 }
+func (*UnimplementedStreamingQuerierServer) FindTraceByID(req *TraceByIDRequest, srv StreamingQuerier_FindTraceByIDServer) error {
+	return status.Errorf(codes.Unimplemented, "method FindTraceByID not implemented")
+}
 
 func RegisterStreamingQuerierServer(s *grpc.Server, srv StreamingQuerierServer) {
 	s.RegisterService(&_StreamingQuerier_serviceDesc, srv)
@@ -4874,6 +5260,27 @@ func (x *streamingQuerierMetricsQueryInstantServer) Send(m *QueryInstantResponse
 	return x.ServerStream.SendMsg(m)
 }
 
+func _StreamingQuerier_FindTraceByID_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TraceByIDRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StreamingQuerierServer).FindTraceByID(m, &streamingQuerierFindTraceByIDServer{stream})
+}
+
+type StreamingQuerier_FindTraceByIDServer interface {
+	Send(*TraceByIDResponse) error
+	grpc.ServerStream
+}
+
+type streamingQuerierFindTraceByIDServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamingQuerierFindTraceByIDServer) Send(m *TraceByIDResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _StreamingQuerier_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "tempopb.StreamingQuerier",
 	HandlerType: (*StreamingQuerierServer)(nil),
@@ -4914,6 +5321,11 @@ var _StreamingQuerier_serviceDesc = grpc.ServiceDesc{
 			Handler:       _StreamingQuerier_MetricsQueryInstant_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "FindTraceByID",
+			Handler:       _StreamingQuerier_FindTraceByID_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "pkg/tempopb/tempo.proto",
 }
@@ -5115,8 +5527,36 @@ func (m *TraceByIDResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
-	if len(m.Message) > 0 {
-		i -= len(m.Message)
+	if len(m.CollidingTraces) > 0 {
+		for iNdEx := len(m.CollidingTraces) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.CollidingTraces[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Warnings[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.Message) > 0 {
+		i -= len(m.Message)
 		copy(dAtA[i:], m.Message)
 		i = encodeVarintTempo(dAtA, i, uint64(len(m.Message)))
 		i--
@@ -5202,6 +5642,13 @@ func (m *SearchRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.OrderBy) > 0 {
+		i -= len(m.OrderBy)
+		copy(dAtA[i:], m.OrderBy)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.OrderBy)))
+		i--
+		dAtA[i] = 0x5a
+	}
 	n4, err4 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.RF1After, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.RF1After):])
 	if err4 != nil {
 		return 0, err4
@@ -5436,6 +5883,20 @@ func (m *SearchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Warnings[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if m.Metrics != nil {
 		{
 			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
@@ -5741,6 +6202,31 @@ func (m *SearchMetrics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.InspectedColumnBytes != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedColumnBytes))
+		i--
+		dAtA[i] = 0x58
+	}
+	if m.InspectedIndexBytes != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedIndexBytes))
+		i--
+		dAtA[i] = 0x50
+	}
+	if m.InspectedBloomBytes != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedBloomBytes))
+		i--
+		dAtA[i] = 0x48
+	}
+	if m.Estimated {
+		i--
+		if m.Estimated {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x40
+	}
 	if m.InspectedSpans != 0 {
 		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedSpans))
 		i--
@@ -6132,6 +6618,20 @@ func (m *SearchTagsV2Response) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Warnings[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if m.Metrics != nil {
 		{
 			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
@@ -6220,6 +6720,18 @@ func (m *SearchTagValuesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	_ = i
 	var l int
 	_ = l
+	if m.PageSize != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.PageSize))
+		i--
+		dAtA[i] = 0x50
+	}
+	if len(m.After) > 0 {
+		i -= len(m.After)
+		copy(dAtA[i:], m.After)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.After)))
+		i--
+		dAtA[i] = 0x4a
+	}
 	n14, err14 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.RF1After, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.RF1After):])
 	if err14 != nil {
 		return 0, err14
@@ -6366,6 +6878,27 @@ func (m *SearchTagValuesV2Response) MarshalToSizedBuffer(dAtA []byte) (int, erro
 	_ = i
 	var l int
 	_ = l
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Warnings[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.NextToken) > 0 {
+		i -= len(m.NextToken)
+		copy(dAtA[i:], m.NextToken)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.NextToken)))
+		i--
+		dAtA[i] = 0x1a
+	}
 	if m.Metrics != nil {
 		{
 			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
@@ -7361,6 +7894,20 @@ func (m *QueryInstantResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Warnings[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
 	if len(m.Message) > 0 {
 		i -= len(m.Message)
 		copy(dAtA[i:], m.Message)
@@ -7593,6 +8140,20 @@ func (m *QueryRangeResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Warnings[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
 	if len(m.Message) > 0 {
 		i -= len(m.Message)
 		copy(dAtA[i:], m.Message)
@@ -7654,6 +8215,16 @@ func (m *Exemplar) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.SpanDurationNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SpanDurationNanos))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.SpanStartTimeUnixNano != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SpanStartTimeUnixNano))
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.TimestampMs != 0 {
 		i = encodeVarintTempo(dAtA, i, uint64(m.TimestampMs))
 		i--
@@ -7736,6 +8307,16 @@ func (m *TimeSeries) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.SamplingWeighted {
+		i--
+		if m.SamplingWeighted {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
 	if len(m.Exemplars) > 0 {
 		for iNdEx := len(m.Exemplars) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -7788,6 +8369,106 @@ func (m *TimeSeries) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *QueryWarning) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryWarning) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryWarning) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Message) > 0 {
+		i -= len(m.Message)
+		copy(dAtA[i:], m.Message)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Message)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Code) > 0 {
+		i -= len(m.Code)
+		copy(dAtA[i:], m.Code)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Code)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TraceByIDsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TraceByIDsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TraceByIDsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Warnings[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.Metrics != nil {
+		{
+			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Traces) > 0 {
+		for iNdEx := len(m.Traces) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Traces[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintTempo(dAtA []byte, offset int, v uint64) int {
 	offset -= sovTempo(v)
 	base := offset
@@ -7850,6 +8531,18 @@ func (m *TraceByIDResponse) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTempo(uint64(l))
 	}
+	if len(m.Warnings) > 0 {
+		for _, e := range m.Warnings {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if len(m.CollidingTraces) > 0 {
+		for _, e := range m.CollidingTraces {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -7903,6 +8596,10 @@ func (m *SearchRequest) Size() (n int) {
 	}
 	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.RF1After)
 	n += 1 + l + sovTempo(uint64(l))
+	l = len(m.OrderBy)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
 	return n
 }
 
@@ -7994,6 +8691,12 @@ func (m *SearchResponse) Size() (n int) {
 		l = m.Metrics.Size()
 		n += 1 + l + sovTempo(uint64(l))
 	}
+	if len(m.Warnings) > 0 {
+		for _, e := range m.Warnings {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -8142,6 +8845,18 @@ func (m *SearchMetrics) Size() (n int) {
 	if m.InspectedSpans != 0 {
 		n += 1 + sovTempo(uint64(m.InspectedSpans))
 	}
+	if m.Estimated {
+		n += 2
+	}
+	if m.InspectedBloomBytes != 0 {
+		n += 1 + sovTempo(uint64(m.InspectedBloomBytes))
+	}
+	if m.InspectedIndexBytes != 0 {
+		n += 1 + sovTempo(uint64(m.InspectedIndexBytes))
+	}
+	if m.InspectedColumnBytes != 0 {
+		n += 1 + sovTempo(uint64(m.InspectedColumnBytes))
+	}
 	return n
 }
 
@@ -8323,6 +9038,12 @@ func (m *SearchTagsV2Response) Size() (n int) {
 		l = m.Metrics.Size()
 		n += 1 + l + sovTempo(uint64(l))
 	}
+	if len(m.Warnings) > 0 {
+		for _, e := range m.Warnings {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -8373,6 +9094,13 @@ func (m *SearchTagValuesRequest) Size() (n int) {
 	}
 	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.RF1After)
 	n += 1 + l + sovTempo(uint64(l))
+	l = len(m.After)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.PageSize != 0 {
+		n += 1 + sovTempo(uint64(m.PageSize))
+	}
 	return n
 }
 
@@ -8428,6 +9156,16 @@ func (m *SearchTagValuesV2Response) Size() (n int) {
 		l = m.Metrics.Size()
 		n += 1 + l + sovTempo(uint64(l))
 	}
+	l = len(m.NextToken)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if len(m.Warnings) > 0 {
+		for _, e := range m.Warnings {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -8878,6 +9616,12 @@ func (m *QueryInstantResponse) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTempo(uint64(l))
 	}
+	if len(m.Warnings) > 0 {
+		for _, e := range m.Warnings {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -8988,6 +9732,12 @@ func (m *QueryRangeResponse) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTempo(uint64(l))
 	}
+	if len(m.Warnings) > 0 {
+		for _, e := range m.Warnings {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -9009,6 +9759,12 @@ func (m *Exemplar) Size() (n int) {
 	if m.TimestampMs != 0 {
 		n += 1 + sovTempo(uint64(m.TimestampMs))
 	}
+	if m.SpanStartTimeUnixNano != 0 {
+		n += 1 + sovTempo(uint64(m.SpanStartTimeUnixNano))
+	}
+	if m.SpanDurationNanos != 0 {
+		n += 1 + sovTempo(uint64(m.SpanDurationNanos))
+	}
 	return n
 }
 
@@ -9055,6 +9811,51 @@ func (m *TimeSeries) Size() (n int) {
 			n += 1 + l + sovTempo(uint64(l))
 		}
 	}
+	if m.SamplingWeighted {
+		n += 2
+	}
+	return n
+}
+
+func (m *QueryWarning) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Code)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *TraceByIDsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Traces) > 0 {
+		for _, e := range m.Traces {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.Metrics != nil {
+		l = m.Metrics.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if len(m.Warnings) > 0 {
+		for _, e := range m.Warnings {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -9449,6 +10250,74 @@ func (m *TraceByIDResponse) Unmarshal(dAtA []byte) error {
 			}
 			m.Message = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, &QueryWarning{})
+			if err := m.Warnings[len(m.Warnings)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CollidingTraces", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CollidingTraces = append(m.CollidingTraces, &Trace{})
+			if err := m.CollidingTraces[len(m.CollidingTraces)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -9874,6 +10743,38 @@ func (m *SearchRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OrderBy", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OrderBy = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -10476,6 +11377,40 @@ func (m *SearchResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, &QueryWarning{})
+			if err := m.Warnings[len(m.Warnings)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -11453,6 +12388,83 @@ func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Estimated", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Estimated = bool(v != 0)
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InspectedBloomBytes", wireType)
+			}
+			m.InspectedBloomBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.InspectedBloomBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InspectedIndexBytes", wireType)
+			}
+			m.InspectedIndexBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.InspectedIndexBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InspectedColumnBytes", wireType)
+			}
+			m.InspectedColumnBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.InspectedColumnBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -12676,17 +13688,51 @@ func (m *SearchTagsV2Response) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
 			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, &QueryWarning{})
+			if err := m.Warnings[len(m.Warnings)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
 			}
 			iNdEx += skippy
 		}
@@ -13013,6 +14059,57 @@ func (m *SearchTagValuesRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field After", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.After = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PageSize", wireType)
+			}
+			m.PageSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PageSize |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -13365,6 +14462,72 @@ func (m *SearchTagValuesV2Response) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NextToken", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NextToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, &QueryWarning{})
+			if err := m.Warnings[len(m.Warnings)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -16125,6 +17288,40 @@ func (m *QueryInstantResponse) Unmarshal(dAtA []byte) error {
 			}
 			m.Message = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, &QueryWarning{})
+			if err := m.Warnings[len(m.Warnings)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -16838,6 +18035,40 @@ func (m *QueryRangeResponse) Unmarshal(dAtA []byte) error {
 			}
 			m.Message = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, &QueryWarning{})
+			if err := m.Warnings[len(m.Warnings)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -16952,6 +18183,44 @@ func (m *Exemplar) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanStartTimeUnixNano", wireType)
+			}
+			m.SpanStartTimeUnixNano = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SpanStartTimeUnixNano |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanDurationNanos", wireType)
+			}
+			m.SpanDurationNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SpanDurationNanos |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -17216,6 +18485,294 @@ func (m *TimeSeries) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SamplingWeighted", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.SamplingWeighted = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryWarning) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryWarning: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryWarning: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Code", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Code = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TraceByIDsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TraceByIDsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TraceByIDsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Traces = append(m.Traces, &Trace{})
+			if err := m.Traces[len(m.Traces)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metrics == nil {
+				m.Metrics = &TraceByIDMetrics{}
+			}
+			if err := m.Metrics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, &QueryWarning{})
+			if err := m.Warnings[len(m.Warnings)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
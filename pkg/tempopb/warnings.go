@@ -0,0 +1,60 @@
+package tempopb
+
+// Codes of the warnings of query responses
+const (
+	// WarningArchivePending is set on the partial results of a streamed query while blocks in the archive tier are
+	// still being searched. It's dropped once all jobs completed.
+	WarningArchivePending = "ARCHIVE_PENDING"
+	// WarningBlocksSkipped is set when blocks weren't searched, for example because their format isn't supported.
+	WarningBlocksSkipped = "BLOCKS_SKIPPED"
+	// WarningNewerBlockVersion is set when blocks of a newer version were searched with the columns they share with
+	// this version, results may be incomplete.
+	WarningNewerBlockVersion = "NEWER_BLOCK_VERSION"
+	// WarningResultsPaged is set when the results are a page, the rest is requested with the next page token.
+	WarningResultsPaged = "RESULTS_PAGED"
+	// WarningResultsTruncated is set when the results were cut at a limit.
+	WarningResultsTruncated = "RESULTS_TRUNCATED"
+	// WarningStaleBlocklist is set when the blocklist wasn't polled recently, recent blocks may be missing.
+	WarningStaleBlocklist = "STALE_BLOCKLIST"
+	// WarningTierPending is set when blocks are archived and must be restored before they can be searched.
+	WarningTierPending = "TIER_PENDING"
+	// WarningTraceIDCollision is set when distinct traces share the trace ID of a trace by ID response.
+	WarningTraceIDCollision = "TRACE_ID_COLLISION"
+)
+
+// NewQueryWarning returns a warning with the code and message.
+func NewQueryWarning(code, message string) *QueryWarning {
+	return &QueryWarning{Code: code, Message: message}
+}
+
+// AppendWarnings appends the warnings that aren't in dst yet. Warnings are equal if they have the same code and
+// message, so messages should not be specific to a block or a job to keep the list short.
+func AppendWarnings(dst []*QueryWarning, warnings ...*QueryWarning) []*QueryWarning {
+	for _, w := range warnings {
+		if w == nil || hasWarning(dst, w) {
+			continue
+		}
+		dst = append(dst, &QueryWarning{Code: w.Code, Message: w.Message})
+	}
+	return dst
+}
+
+// WithoutWarning returns the warnings without the ones with the code.
+func WithoutWarning(warnings []*QueryWarning, code string) []*QueryWarning {
+	var res []*QueryWarning
+	for _, w := range warnings {
+		if w.Code != code {
+			res = append(res, w)
+		}
+	}
+	return res
+}
+
+func hasWarning(warnings []*QueryWarning, w *QueryWarning) bool {
+	for _, existing := range warnings {
+		if existing.Code == w.Code && existing.Message == w.Message {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package tempopb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendWarnings(t *testing.T) {
+	skipped := NewQueryWarning(WarningBlocksSkipped, "skipped")
+	truncated := NewQueryWarning(WarningResultsTruncated, "truncated")
+
+	warnings := AppendWarnings(nil, skipped, nil, skipped)
+	require.Equal(t, []*QueryWarning{skipped}, warnings)
+
+	// the same code with another message is another warning
+	other := NewQueryWarning(WarningBlocksSkipped, "other")
+	warnings = AppendWarnings(warnings, truncated, other, NewQueryWarning(WarningResultsTruncated, "truncated"))
+	require.Equal(t, []*QueryWarning{skipped, truncated, other}, warnings)
+
+	// appended warnings are copies
+	require.NotSame(t, skipped, warnings[0])
+}
+
+func TestWithoutWarning(t *testing.T) {
+	skipped := NewQueryWarning(WarningBlocksSkipped, "skipped")
+	pending := NewQueryWarning(WarningArchivePending, "pending")
+
+	require.Equal(t, []*QueryWarning{skipped}, WithoutWarning([]*QueryWarning{pending, skipped}, WarningArchivePending))
+	require.Nil(t, WithoutWarning([]*QueryWarning{pending}, WarningArchivePending))
+}
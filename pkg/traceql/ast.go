@@ -119,6 +119,56 @@ func (r *RootExpr) IsNoop() bool {
 	return true
 }
 
+// RequiresErrorStatus detects queries like { status = error && .foo = "bar" } which only
+// match spans with an error status. Like IsNoop this only checks the first pipeline element
+// and returns false for anything it doesn't understand.
+func (r *RootExpr) RequiresErrorStatus() bool {
+	if len(r.Pipeline.Elements) == 0 {
+		return false
+	}
+	return spansetRequiresErrorStatus(r.Pipeline.Elements[0])
+}
+
+func spansetRequiresErrorStatus(e pipelineElement) bool {
+	switch x := e.(type) {
+	case *SpansetFilter:
+		return fieldRequiresErrorStatus(x.Expression)
+	case SpansetOperation:
+		return x.Op == OpSpansetAnd && spansetRequiresErrorStatus(x.LHS) && spansetRequiresErrorStatus(x.RHS)
+	case Pipeline:
+		return len(x.Elements) > 0 && spansetRequiresErrorStatus(x.Elements[0])
+	}
+	return false
+}
+
+func fieldRequiresErrorStatus(e FieldExpression) bool {
+	o, ok := e.(*BinaryOperation)
+	if !ok {
+		return false
+	}
+
+	switch o.Op {
+	case OpAnd:
+		return fieldRequiresErrorStatus(o.LHS) || fieldRequiresErrorStatus(o.RHS)
+	case OpEqual:
+		return isErrorStatusComparison(o.LHS, o.RHS) || isErrorStatusComparison(o.RHS, o.LHS)
+	}
+	return false
+}
+
+func isErrorStatusComparison(a, s FieldExpression) bool {
+	attr, ok := a.(Attribute)
+	if !ok || attr.Intrinsic != IntrinsicStatus {
+		return false
+	}
+	static, ok := s.(Static)
+	if !ok {
+		return false
+	}
+	status, ok := static.Status()
+	return ok && status == StatusError
+}
+
 // **********************
 // Pipeline
 // **********************
@@ -1088,6 +1138,10 @@ func (a Attribute) impliedType() StaticType {
 		return TypeString
 	case IntrinsicParentID:
 		return TypeString
+	case IntrinsicTraceState:
+		return TypeString
+	case IntrinsicTraceSampled:
+		return TypeBoolean
 	}
 
 	return TypeAttribute
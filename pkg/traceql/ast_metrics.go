@@ -36,6 +36,8 @@ type MetricsAggregate struct {
 	exemplarFn getExemplar
 	// Type of operation for simple aggregatation in layers 2 and 3
 	simpleAggregationOp SimpleAggregationOp
+	// Count spans by their sampling weight, see samplingWeight()
+	samplingWeights bool
 }
 
 func newMetricsAggregate(agg MetricsAggregateOp, by []Attribute) *MetricsAggregate {
@@ -103,7 +105,7 @@ func (a *MetricsAggregate) init(q *tempopb.QueryRangeRequest, mode AggregateMode
 
 	switch a.op {
 	case metricsAggregateCountOverTime:
-		innerAgg = func() VectorAggregator { return NewCountOverTimeAggregator() }
+		innerAgg = a.countAggregator(1.0)
 		a.simpleAggregationOp = sumAggregation
 		a.exemplarFn = exemplarNaN
 
@@ -123,19 +125,19 @@ func (a *MetricsAggregate) init(q *tempopb.QueryRangeRequest, mode AggregateMode
 		a.exemplarFn = exemplarFnFor(a.attr)
 
 	case metricsAggregateRate:
-		innerAgg = func() VectorAggregator { return NewRateAggregator(1.0 / time.Duration(q.Step).Seconds()) }
+		innerAgg = a.countAggregator(1.0 / time.Duration(q.Step).Seconds())
 		a.simpleAggregationOp = sumAggregation
 		a.exemplarFn = exemplarNaN
 
 	case metricsAggregateHistogramOverTime:
-		innerAgg = func() VectorAggregator { return NewCountOverTimeAggregator() }
+		innerAgg = a.countAggregator(1.0)
 		byFunc = bucketizeFnFor(a.attr)
 		byFuncLabel = internalLabelBucket
 		a.simpleAggregationOp = sumAggregation
 		a.exemplarFn = exemplarNaN // Histogram final series are counts so exemplars are placeholders
 
 	case metricsAggregateQuantileOverTime:
-		innerAgg = func() VectorAggregator { return NewCountOverTimeAggregator() }
+		innerAgg = a.countAggregator(1.0)
 		byFunc = bucketizeFnFor(a.attr)
 		byFuncLabel = internalLabelBucket
 		a.simpleAggregationOp = sumAggregation
@@ -157,6 +159,36 @@ func (a *MetricsAggregate) init(q *tempopb.QueryRangeRequest, mode AggregateMode
 	}, a.by, byFunc, byFuncLabel)
 }
 
+// countsSpans returns true if the values of the aggregate are span counts.
+func (a *MetricsAggregate) countsSpans() bool {
+	switch a.op {
+	case metricsAggregateCountOverTime, metricsAggregateRate, metricsAggregateHistogramOverTime, metricsAggregateQuantileOverTime:
+		return true
+	}
+	return false
+}
+
+// extrapolates returns true if the values of the aggregate grow with the number of spans, so that they can be
+// extrapolated from a sample of them.
+func (a *MetricsAggregate) extrapolates() bool {
+	return a.countsSpans() || a.op == metricsAggregateSumOverTime
+}
+
+// countAggregator returns the inner aggregator for the operations that count spans. Spans are counted by their
+// sampling weight if enabled.
+func (a *MetricsAggregate) countAggregator(rateMult float64) func() VectorAggregator {
+	if !a.samplingWeights {
+		return func() VectorAggregator { return NewRateAggregator(rateMult) }
+	}
+
+	return func() VectorAggregator {
+		return &CountOverTimeAggregator{
+			rateMult: rateMult,
+			weightFn: samplingWeight,
+		}
+	}
+}
+
 func bucketizeFnFor(attr Attribute) func(Span) (Static, bool) {
 	switch attr {
 	case IntrinsicDurationAttribute:
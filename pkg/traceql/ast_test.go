@@ -42,6 +42,44 @@ func TestRootExprIsNoop(t *testing.T) {
 	}
 }
 
+func TestRootExprRequiresErrorStatus(t *testing.T) {
+	errors := []string{
+		"{status = error}",
+		"{span:status = error}",
+		"{error = status}",
+		`{status = error && .foo = "bar"}`,
+		`{.foo = "bar" && (status = error && duration > 1s)}`,
+		"({status = error})",
+		"{status = error} && {status = error}",
+		"{status = error} | count() > 2",
+		"{status = error} | rate()",
+	}
+
+	for _, q := range errors {
+		expr, err := Parse(q)
+		require.NoError(t, err)
+		require.True(t, expr.RequiresErrorStatus(), "Query should require error status: %v", q)
+	}
+
+	others := []string{
+		"{}",
+		"{status = ok}",
+		"{status != error}",
+		"{!(status = error)}",
+		`{status = error || .foo = "bar"}`,
+		`{status = error} && {.foo = "bar"}`,
+		`{status = error} || {.foo = "bar"}`,
+		`{status = error} >> {.foo = "bar"}`,
+		"{.status = error}",
+	}
+
+	for _, q := range others {
+		expr, err := Parse(q)
+		require.NoError(t, err)
+		require.False(t, expr.RequiresErrorStatus(), "Query should not require error status: %v", q)
+	}
+}
+
 func TestNewStaticNil(t *testing.T) {
 	s := NewStaticNil()
 	assert.Equal(t, TypeNil, s.Type)
@@ -1,6 +1,9 @@
 package traceql
 
 import (
+	"cmp"
+	"container/heap"
+	"fmt"
 	"math"
 	"slices"
 	"sort"
@@ -8,6 +11,7 @@ import (
 	"time"
 
 	"github.com/grafana/tempo/pkg/tempopb"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
 	"github.com/grafana/tempo/pkg/util"
 )
 
@@ -225,6 +229,263 @@ func (c *mostRecentCombiner) OldestTimestampNanos() uint64 {
 	return c.trsSorted[len(c.trsSorted)-1].StartTimeUnixNano
 }
 
+const (
+	SearchOrderDuration    = "duration"
+	SearchOrderStartTime   = "start_time"
+	SearchOrderRootService = "root_service"
+	SearchOrderRootName    = "root_name"
+)
+
+// SearchOrder orders search results by trace duration, trace start time, root service, root span name or the
+// greatest numeric value of an attribute in the returned spans of a trace. Durations, start times and attribute
+// values are ordered greatest first and traces without a numeric value for the attribute are last. Root services
+// and names are ordered alphabetically and traces without a root span are last.
+type SearchOrder struct {
+	by        string
+	attribute Attribute
+}
+
+// ParseSearchOrder parses the order_by parameter of a search. It returns nil if s is empty.
+func ParseSearchOrder(s string) (*SearchOrder, error) {
+	switch s {
+	case "":
+		return nil, nil
+	case SearchOrderDuration, SearchOrderStartTime, SearchOrderRootService, SearchOrderRootName:
+		return &SearchOrder{by: s}, nil
+	}
+
+	a, err := ParseIdentifier(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// the trace-level intrinsics are in the search metadata of every trace
+	switch a.Intrinsic {
+	case IntrinsicTraceDuration:
+		return &SearchOrder{by: SearchOrderDuration}, nil
+	case IntrinsicTraceStartTime:
+		return &SearchOrder{by: SearchOrderStartTime}, nil
+	case IntrinsicTraceRootService:
+		return &SearchOrder{by: SearchOrderRootService}, nil
+	case IntrinsicTraceRootSpan:
+		return &SearchOrder{by: SearchOrderRootName}, nil
+	}
+	if a.Intrinsic != IntrinsicNone || a.Name == "" {
+		return nil, fmt.Errorf("can't order by %s, use %s, %s, %s, %s or an attribute", s,
+			SearchOrderDuration, SearchOrderStartTime, SearchOrderRootService, SearchOrderRootName)
+	}
+
+	return &SearchOrder{attribute: a}, nil
+}
+
+func (o *SearchOrder) String() string {
+	if o.by != "" {
+		return o.by
+	}
+	return o.attribute.String()
+}
+
+// Attribute returns the attribute results are ordered by and false if they are ordered by a trace-level value.
+func (o *SearchOrder) Attribute() (Attribute, bool) {
+	return o.attribute, o.by == ""
+}
+
+// compare returns a positive number if a is ordered before b, a negative number if b is ordered before a and 0 if
+// they are equal.
+func (o *SearchOrder) compare(a, b *tempopb.TraceSearchMetadata) int {
+	switch o.by {
+	case SearchOrderDuration:
+		return cmp.Compare(a.DurationMs, b.DurationMs)
+	case SearchOrderStartTime:
+		return cmp.Compare(a.StartTimeUnixNano, b.StartTimeUnixNano)
+	case SearchOrderRootService:
+		return compareNames(a.RootServiceName, b.RootServiceName)
+	case SearchOrderRootName:
+		return compareNames(a.RootTraceName, b.RootTraceName)
+	}
+
+	return cmp.Compare(o.metadataValue(a), o.metadataValue(b))
+}
+
+// compareNames compares names like compare, alphabetically with empty names of traces whose root span wasn't found
+// last.
+func compareNames(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return -1
+	case b == "":
+		return 1
+	}
+	return strings.Compare(b, a)
+}
+
+// metadataValue returns the greatest value of the attribute in the spans of the metadata, or -Inf.
+func (o *SearchOrder) metadataValue(m *tempopb.TraceSearchMetadata) float64 {
+	v := math.Inf(-1)
+	for _, ss := range m.SpanSets {
+		for _, s := range ss.Spans {
+			for _, kv := range s.Attributes {
+				if kv.Key != o.attribute.Name {
+					continue
+				}
+				switch val := kv.Value.GetValue().(type) {
+				case *common_v1.AnyValue_IntValue:
+					v = math.Max(v, float64(val.IntValue))
+				case *common_v1.AnyValue_DoubleValue:
+					v = math.Max(v, val.DoubleValue)
+				}
+			}
+		}
+	}
+	return v
+}
+
+// spanValue returns the value of the attribute of the span and false if the span has no numeric value for it.
+func (o *SearchOrder) spanValue(s Span) (float64, bool) {
+	v, ok := s.AttributeFor(o.attribute)
+	if !ok || (v.Type != TypeInt && v.Type != TypeFloat) {
+		return 0, false
+	}
+	return v.Float(), true
+}
+
+// orderedCombiner keeps the first limit traces in the search order in a heap with the last trace at the root, so
+// adding a trace is O(log limit) however many results are merged. Every trace has to be seen before the results are
+// known so it is never complete.
+type orderedCombiner struct {
+	trs   map[string]*orderedTrace
+	heap  orderedHeap
+	seq   uint64
+	limit int
+
+	// sorted are the traces of the heap in the search order, nil once the heap changed
+	sorted []*tempopb.TraceSearchMetadata
+}
+
+// orderedTrace is a trace in the heap of an orderedCombiner. seq orders traces that are equal in the search order by
+// the time they were added.
+type orderedTrace struct {
+	tr    *tempopb.TraceSearchMetadata
+	seq   uint64
+	index int
+}
+
+// NewOrderedMetadataCombiner returns a combiner that keeps the first limit traces in the given order. It falls back
+// to NewMetadataCombiner if order is nil.
+func NewOrderedMetadataCombiner(limit int, order *SearchOrder, keepMostRecent bool) MetadataCombiner {
+	if order == nil {
+		return NewMetadataCombiner(limit, keepMostRecent)
+	}
+
+	return &orderedCombiner{
+		trs: make(map[string]*orderedTrace, limit),
+		heap: orderedHeap{
+			trs:   make([]*orderedTrace, 0, limit),
+			order: order,
+		},
+		limit: limit,
+	}
+}
+
+func (c *orderedCombiner) addSpanset(new *Spanset) {
+	c.AddMetadata(asTraceSearchMetadata(new))
+}
+
+// AddMetadata adds the new metadata if it's within the limit once ordered. if it already exists
+// use CombineSearchResults to combine the two and reorder the result
+func (c *orderedCombiner) AddMetadata(new *tempopb.TraceSearchMetadata) bool {
+	if existing, ok := c.trs[new.TraceID]; ok {
+		combineSearchResults(existing.tr, new)
+		heap.Fix(&c.heap, existing.index)
+		c.sorted = nil
+		return true
+	}
+
+	if c.limit > 0 && len(c.trs) >= c.limit {
+		if c.heap.order.compare(new, c.heap.trs[0].tr) <= 0 {
+			return false
+		}
+
+		last := heap.Pop(&c.heap).(*orderedTrace)
+		delete(c.trs, last.tr.TraceID)
+	}
+
+	ot := &orderedTrace{tr: new, seq: c.seq}
+	c.seq++
+	c.trs[new.TraceID] = ot
+	heap.Push(&c.heap, ot)
+	c.sorted = nil
+	return true
+}
+
+func (c *orderedCombiner) IsCompleteFor(_ uint32) bool {
+	return false
+}
+
+// Metadata returns the traces in the search order. They are only sorted again once traces were added since the
+// last call, so streamed diffs don't sort unchanged results.
+func (c *orderedCombiner) Metadata() []*tempopb.TraceSearchMetadata {
+	if c.sorted != nil || len(c.heap.trs) == 0 {
+		return c.sorted
+	}
+
+	sorted := slices.Clone(c.heap.trs)
+	slices.SortFunc(sorted, func(a, b *orderedTrace) int {
+		return -c.heap.compare(a, b)
+	})
+
+	c.sorted = make([]*tempopb.TraceSearchMetadata, 0, len(sorted))
+	for _, ot := range sorted {
+		c.sorted = append(c.sorted, ot.tr)
+	}
+	return c.sorted
+}
+
+// MetadataAfter returns all traces. the order has no relation to the time the traces were searched
+func (c *orderedCombiner) MetadataAfter(_ uint32) []*tempopb.TraceSearchMetadata {
+	return c.Metadata()
+}
+
+// orderedHeap implements heap.Interface with the trace ordered last at the root. Of the traces that are equal in the
+// search order, the last one added is ordered last.
+type orderedHeap struct {
+	trs   []*orderedTrace
+	order *SearchOrder
+}
+
+// compare returns a positive number if a is ordered before b
+func (h *orderedHeap) compare(a, b *orderedTrace) int {
+	if r := h.order.compare(a.tr, b.tr); r != 0 {
+		return r
+	}
+	return cmp.Compare(b.seq, a.seq)
+}
+
+func (h *orderedHeap) Len() int { return len(h.trs) }
+
+func (h *orderedHeap) Less(i, j int) bool { return h.compare(h.trs[i], h.trs[j]) < 0 }
+
+func (h *orderedHeap) Swap(i, j int) {
+	h.trs[i], h.trs[j] = h.trs[j], h.trs[i]
+	h.trs[i].index = i
+	h.trs[j].index = j
+}
+
+func (h *orderedHeap) Push(x any) {
+	ot := x.(*orderedTrace)
+	ot.index = len(h.trs)
+	h.trs = append(h.trs, ot)
+}
+
+func (h *orderedHeap) Pop() any {
+	ot := h.trs[len(h.trs)-1]
+	h.trs[len(h.trs)-1] = nil
+	h.trs = h.trs[:len(h.trs)-1]
+	return ot
+}
+
 // combineSearchResults overlays the incoming search result with the existing result. This is required
 // for the following reason:  a trace may be present in multiple blocks, or in partial segments
 // in live traces.  The results should reflect elements of all segments.
@@ -368,6 +629,7 @@ func (q *QueryRangeCombiner) Combine(resp *tempopb.QueryRangeResponse) {
 		q.metrics.InspectedTraces += resp.Metrics.InspectedTraces
 		q.metrics.InspectedSpans += resp.Metrics.InspectedSpans
 		q.metrics.CompletedJobs += resp.Metrics.CompletedJobs
+		q.metrics.Estimated = q.metrics.Estimated || resp.Metrics.Estimated
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/rand/v2"
 	"slices"
+	"strconv"
 	"testing"
 	"time"
 
@@ -317,3 +318,223 @@ func TestCombinerKeepsMostRecent(t *testing.T) {
 	actualTraces := combiner.MetadataAfter(afterSeconds)
 	require.Equal(t, expectedTracesCount, len(actualTraces))
 }
+
+func TestParseSearchOrder(t *testing.T) {
+	tcs := []struct {
+		in       string
+		expected *SearchOrder
+		err      bool
+	}{
+		{in: "", expected: nil},
+		{in: "duration", expected: &SearchOrder{by: SearchOrderDuration}},
+		{in: "start_time", expected: &SearchOrder{by: SearchOrderStartTime}},
+		{in: "root_service", expected: &SearchOrder{by: SearchOrderRootService}},
+		{in: "root_name", expected: &SearchOrder{by: SearchOrderRootName}},
+		{in: "traceDuration", expected: &SearchOrder{by: SearchOrderDuration}},
+		{in: "trace:duration", expected: &SearchOrder{by: SearchOrderDuration}},
+		{in: "traceStartTime", expected: &SearchOrder{by: SearchOrderStartTime}},
+		{in: "rootServiceName", expected: &SearchOrder{by: SearchOrderRootService}},
+		{in: "trace:rootService", expected: &SearchOrder{by: SearchOrderRootService}},
+		{in: "rootName", expected: &SearchOrder{by: SearchOrderRootName}},
+		{in: "trace:rootName", expected: &SearchOrder{by: SearchOrderRootName}},
+		{in: "span.http.status_code", expected: &SearchOrder{attribute: NewScopedAttribute(AttributeScopeSpan, false, "http.status_code")}},
+		{in: ".foo", expected: &SearchOrder{attribute: NewAttribute("foo")}},
+		{in: "name", err: true},
+		{in: ".", err: true},
+		{in: "foo", err: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.in, func(t *testing.T) {
+			actual, err := ParseSearchOrder(tc.in)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestOrderedCombiner(t *testing.T) {
+	metadataWithValue := func(id string, v *v1.AnyValue) *tempopb.TraceSearchMetadata {
+		m := &tempopb.TraceSearchMetadata{TraceID: id, SpanSets: []*tempopb.SpanSet{{Spans: []*tempopb.Span{{}}}}}
+		if v != nil {
+			m.SpanSets[0].Spans[0].Attributes = []*v1.KeyValue{{Key: "foo", Value: v}}
+		}
+		return m
+	}
+	intValue := func(n int64) *v1.AnyValue { return &v1.AnyValue{Value: &v1.AnyValue_IntValue{IntValue: n}} }
+	doubleValue := func(f float64) *v1.AnyValue { return &v1.AnyValue{Value: &v1.AnyValue_DoubleValue{DoubleValue: f}} }
+
+	tcs := []struct {
+		name     string
+		order    string
+		limit    int
+		in       []*tempopb.TraceSearchMetadata
+		expected []string
+	}{
+		{
+			name:  "duration",
+			order: "duration",
+			limit: 3,
+			in: []*tempopb.TraceSearchMetadata{
+				{TraceID: "1", DurationMs: 10},
+				{TraceID: "2", DurationMs: 50},
+				{TraceID: "3", DurationMs: 20},
+				{TraceID: "4", DurationMs: 5},
+				{TraceID: "5", DurationMs: 40},
+			},
+			expected: []string{"2", "5", "3"},
+		},
+		{
+			name:  "start time",
+			order: "start_time",
+			limit: 2,
+			in: []*tempopb.TraceSearchMetadata{
+				{TraceID: "1", StartTimeUnixNano: 10},
+				{TraceID: "2", StartTimeUnixNano: 30},
+				{TraceID: "3", StartTimeUnixNano: 20},
+			},
+			expected: []string{"2", "3"},
+		},
+		{
+			name:  "root service",
+			order: "trace:rootService",
+			limit: 3,
+			in: []*tempopb.TraceSearchMetadata{
+				{TraceID: "1", RootServiceName: "b"},
+				{TraceID: "2"},
+				{TraceID: "3", RootServiceName: "c"},
+				{TraceID: "4", RootServiceName: "a"},
+			},
+			expected: []string{"4", "1", "3"},
+		},
+		{
+			name:  "root name without root span is last",
+			order: "root_name",
+			limit: 0,
+			in: []*tempopb.TraceSearchMetadata{
+				{TraceID: "1"},
+				{TraceID: "2", RootTraceName: "GET /"},
+			},
+			expected: []string{"2", "1"},
+		},
+		{
+			name:  "attribute",
+			order: ".foo",
+			limit: 3,
+			in: []*tempopb.TraceSearchMetadata{
+				metadataWithValue("1", intValue(3)),
+				metadataWithValue("2", nil),
+				metadataWithValue("3", doubleValue(4.5)),
+				metadataWithValue("4", intValue(-1)),
+			},
+			expected: []string{"3", "1", "4"},
+		},
+		{
+			name:  "attribute missing is last",
+			order: ".foo",
+			limit: 0,
+			in: []*tempopb.TraceSearchMetadata{
+				metadataWithValue("1", nil),
+				metadataWithValue("2", intValue(1)),
+			},
+			expected: []string{"2", "1"},
+		},
+		{
+			name:  "combined trace is reordered",
+			order: "duration",
+			limit: 2,
+			in: []*tempopb.TraceSearchMetadata{
+				{TraceID: "1", DurationMs: 10},
+				{TraceID: "2", DurationMs: 20},
+				{TraceID: "1", DurationMs: 30},
+				{TraceID: "3", DurationMs: 15},
+			},
+			expected: []string{"1", "2"},
+		},
+		{
+			name:  "equal traces are kept in the order they were added",
+			order: "duration",
+			limit: 3,
+			in: []*tempopb.TraceSearchMetadata{
+				{TraceID: "1", DurationMs: 10},
+				{TraceID: "2", DurationMs: 20},
+				{TraceID: "3", DurationMs: 10},
+				{TraceID: "4", DurationMs: 10},
+			},
+			expected: []string{"2", "1", "3"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			order, err := ParseSearchOrder(tc.order)
+			require.NoError(t, err)
+
+			combiner := NewOrderedMetadataCombiner(tc.limit, order, false)
+			for _, m := range tc.in {
+				combiner.AddMetadata(m)
+				require.False(t, combiner.IsCompleteFor(TimestampNever))
+			}
+
+			actual := make([]string, 0, len(tc.expected))
+			for _, m := range combiner.Metadata() {
+				actual = append(actual, m.TraceID)
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestOrderedMetadataCombinerKeepsTheFirstTraces(t *testing.T) {
+	order, err := ParseSearchOrder("duration")
+	require.NoError(t, err)
+
+	const limit = 20
+	combiner := NewOrderedMetadataCombiner(limit, order, false)
+
+	durations := rand.Perm(1000)
+	for i, d := range durations {
+		combiner.AddMetadata(&tempopb.TraceSearchMetadata{TraceID: strconv.Itoa(i), DurationMs: uint32(d)})
+	}
+
+	actual := combiner.Metadata()
+	require.Len(t, actual, limit)
+	for i, m := range actual {
+		require.Equal(t, uint32(999-i), m.DurationMs)
+	}
+}
+
+func TestOrderedMetadataCombinerSortsOnlyOnChange(t *testing.T) {
+	order, err := ParseSearchOrder("duration")
+	require.NoError(t, err)
+
+	traceIDs := func(metadata []*tempopb.TraceSearchMetadata) []string {
+		ids := make([]string, 0, len(metadata))
+		for _, m := range metadata {
+			ids = append(ids, m.TraceID)
+		}
+		return ids
+	}
+
+	combiner := NewOrderedMetadataCombiner(2, order, false)
+	require.Empty(t, combiner.Metadata())
+
+	combiner.AddMetadata(&tempopb.TraceSearchMetadata{TraceID: "1", DurationMs: 10})
+	combiner.AddMetadata(&tempopb.TraceSearchMetadata{TraceID: "2", DurationMs: 20})
+	first := combiner.Metadata()
+	require.Equal(t, []string{"2", "1"}, traceIDs(first))
+
+	// unchanged results aren't sorted again
+	require.Same(t, &first[0], &combiner.Metadata()[0])
+
+	// traces that aren't kept don't change them either
+	require.False(t, combiner.AddMetadata(&tempopb.TraceSearchMetadata{TraceID: "3", DurationMs: 5}))
+	require.Same(t, &first[0], &combiner.Metadata()[0])
+
+	require.True(t, combiner.AddMetadata(&tempopb.TraceSearchMetadata{TraceID: "1", DurationMs: 30}))
+	require.Equal(t, []string{"1", "2"}, traceIDs(combiner.Metadata()))
+}
@@ -60,6 +60,11 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 		mostRecent = false
 	}
 
+	order, err := ParseSearchOrder(searchReq.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+
 	if rootExpr.IsNoop() {
 		return &tempopb.SearchResponse{
 			Traces:  nil,
@@ -77,6 +82,15 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 	meta := SearchMetaConditionsWithout(fetchSpansRequest.Conditions, fetchSpansRequest.AllConditions)
 	fetchSpansRequest.SecondPassConditions = append(fetchSpansRequest.SecondPassConditions, meta...)
 
+	// fetch the attribute the results are ordered by so it's returned with the spans
+	orderAttribute, orderByAttribute := Attribute{}, false
+	if order != nil {
+		orderAttribute, orderByAttribute = order.Attribute()
+	}
+	if orderByAttribute {
+		fetchSpansRequest.SecondPassConditions = append(fetchSpansRequest.SecondPassConditions, Condition{Attribute: orderAttribute})
+	}
+
 	spansetsEvaluated := 0
 	// set up the expression evaluation as a filter to reduce data pulled
 	fetchSpansRequest.SecondPass = func(inSS *Spanset) ([]*Spanset, error) {
@@ -105,6 +119,9 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 				spansPerSpanSet = DefaultSpansPerSpanSet
 			}
 			if l > spansPerSpanSet {
+				if orderByAttribute {
+					keepGreatestSpan(evalSS[i].Spans, order)
+				}
 				evalSS[i].Spans = evalSS[i].Spans[:spansPerSpanSet]
 			}
 		}
@@ -123,7 +140,7 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 		Traces:  nil,
 		Metrics: &tempopb.SearchMetrics{},
 	}
-	combiner := NewMetadataCombiner(int(searchReq.Limit), mostRecent)
+	combiner := NewOrderedMetadataCombiner(int(searchReq.Limit), order, mostRecent)
 	for {
 		spanset, err := iterator.Next(ctx)
 		if err != nil && !errors.Is(err, io.EOF) {
@@ -261,6 +278,20 @@ func (e *Engine) createAutocompleteRequest(tag Attribute, pipeline Pipeline) Fet
 	return autocompleteReq
 }
 
+// keepGreatestSpan moves the span with the greatest value of the order attribute first so it is
+// kept when the spanset is truncated
+func keepGreatestSpan(spans []Span, order *SearchOrder) {
+	greatest, greatestValue := -1, 0.0
+	for i, s := range spans {
+		if v, ok := order.spanValue(s); ok && (greatest == -1 || v > greatestValue) {
+			greatest, greatestValue = i, v
+		}
+	}
+	if greatest > 0 {
+		spans[0], spans[greatest] = spans[greatest], spans[0]
+	}
+}
+
 func asTraceSearchMetadata(spanset *Spanset) *tempopb.TraceSearchMetadata {
 	metadata := &tempopb.TraceSearchMetadata{
 		TraceID:           util.TraceIDToHexString(spanset.TraceID),
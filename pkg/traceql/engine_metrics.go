@@ -260,12 +260,78 @@ type Exemplar struct {
 	Labels      Labels
 	Value       float64
 	TimestampMs uint64
+	// SpanStartTimeUnixNano and SpanDurationNanos locate the span of the exemplar in its trace, zero if unknown.
+	SpanStartTimeUnixNano uint64
+	SpanDurationNanos     uint64
+}
+
+// newExemplar returns the exemplar of a span with the attributes of the span as labels.
+func newExemplar(span Span, value float64, ts uint64) Exemplar {
+	all := span.AllAttributes()
+	lbls := make(Labels, 0, len(all))
+	for k, v := range all {
+		lbls = append(lbls, Label{k.String(), v})
+	}
+	return Exemplar{
+		Labels:                lbls,
+		Value:                 value,
+		TimestampMs:           ts,
+		SpanStartTimeUnixNano: span.StartTimeUnixNanos(),
+		SpanDurationNanos:     span.DurationNanos(),
+	}
+}
+
+// exemplarFromProto returns the exemplar of a job response.
+func exemplarFromProto(e tempopb.Exemplar) Exemplar {
+	lbls := make(Labels, 0, len(e.Labels))
+	for _, l := range e.Labels {
+		lbls = append(lbls, Label{Name: l.Key, Value: StaticFromAnyValue(l.Value)})
+	}
+	return Exemplar{
+		Labels:                lbls,
+		Value:                 e.Value,
+		TimestampMs:           uint64(e.TimestampMs), //nolint: gosec // G115
+		SpanStartTimeUnixNano: e.SpanStartTimeUnixNano,
+		SpanDurationNanos:     e.SpanDurationNanos,
+	}
+}
+
+// sameSpan returns true if both exemplars are of the same span, like the exemplars of a span found by two jobs.
+// Exemplars without their span aren't compared.
+func (e Exemplar) sameSpan(o Exemplar) bool {
+	if e.SpanStartTimeUnixNano == 0 || e.SpanStartTimeUnixNano != o.SpanStartTimeUnixNano || e.SpanDurationNanos != o.SpanDurationNanos {
+		return false
+	}
+	traceID, otherTraceID := e.traceID(), o.traceID()
+	return traceID.Type != TypeNil && traceID.Equals(&otherTraceID)
+}
+
+func (e Exemplar) traceID() Static {
+	name := IntrinsicTraceIDAttribute.String()
+	for _, l := range e.Labels {
+		if l.Name == name {
+			return l.Value
+		}
+	}
+	return NewStaticNil()
+}
+
+// containsSpan returns true if the exemplars have one of the same span as the exemplar.
+func containsSpan(exemplars []Exemplar, e Exemplar) bool {
+	for _, existing := range exemplars {
+		if existing.sameSpan(e) {
+			return true
+		}
+	}
+	return false
 }
 
 type TimeSeries struct {
 	Labels    Labels
 	Values    []float64
 	Exemplars []Exemplar
+	// SamplingWeighted is true when the values were scaled by the sampling weight of one or more spans.
+	SamplingWeighted bool
 }
 
 // SeriesSet is a set of unique timeseries. They are mapped by the "Prometheus"-style
@@ -333,17 +399,20 @@ func (set SeriesSet) ToProto(req *tempopb.QueryRangeRequest) []*tempopb.TimeSeri
 			}
 
 			exemplars = append(exemplars, tempopb.Exemplar{
-				Labels:      labels,
-				Value:       e.Value,
-				TimestampMs: int64(e.TimestampMs),
+				Labels:                labels,
+				Value:                 e.Value,
+				TimestampMs:           int64(e.TimestampMs),
+				SpanStartTimeUnixNano: e.SpanStartTimeUnixNano,
+				SpanDurationNanos:     e.SpanDurationNanos,
 			})
 		}
 
 		ss := &tempopb.TimeSeries{
-			PromLabels: promLabels,
-			Labels:     labels,
-			Samples:    samples,
-			Exemplars:  exemplars,
+			PromLabels:       promLabels,
+			Labels:           labels,
+			Samples:          samples,
+			Exemplars:        exemplars,
+			SamplingWeighted: s.SamplingWeighted,
 		}
 
 		resp = append(resp, ss)
@@ -362,10 +431,11 @@ type VectorAggregator interface {
 // TODO - for efficiency we probably combine this with VectorAggregator (see todo about CountOverTimeAggregator)
 type RangeAggregator interface {
 	Observe(s Span)
-	ObserveExemplar(float64, uint64, Labels)
+	ObserveExemplar(Exemplar)
 	Samples() []float64
 	Exemplars() []Exemplar
 	Length() int
+	SamplingWeighted() bool
 }
 
 // SpanAggregator sorts spans into series
@@ -377,11 +447,14 @@ type SpanAggregator interface {
 }
 
 // CountOverTimeAggregator counts the number of spans. It can also
-// calculate the rate when given a multiplier.
+// calculate the rate when given a multiplier. When given a weight function
+// each span is counted by its weight instead.
 // TODO - Rewrite me to be []float64 which is more efficient
 type CountOverTimeAggregator struct {
 	count    float64
 	rateMult float64
+	weightFn func(Span) float64
+	weighted bool
 }
 
 var _ VectorAggregator = (*CountOverTimeAggregator)(nil)
@@ -398,8 +471,17 @@ func NewRateAggregator(rateMult float64) *CountOverTimeAggregator {
 	}
 }
 
-func (c *CountOverTimeAggregator) Observe(_ Span) {
-	c.count++
+func (c *CountOverTimeAggregator) Observe(s Span) {
+	if c.weightFn == nil {
+		c.count++
+		return
+	}
+
+	w := c.weightFn(s)
+	if w != 1 {
+		c.weighted = true
+	}
+	c.count += w
 }
 
 func (c *CountOverTimeAggregator) Sample() float64 {
@@ -500,19 +582,15 @@ func (s *StepAggregator) Observe(span Span) {
 	s.vectors[interval].Observe(span)
 }
 
-func (s *StepAggregator) ObserveExemplar(value float64, ts uint64, lbls Labels) {
+func (s *StepAggregator) ObserveExemplar(e Exemplar) {
 	if s.exemplarBuckets.testTotal() {
 		return
 	}
-	if s.exemplarBuckets.addAndTest(ts) {
+	if s.exemplarBuckets.addAndTest(e.TimestampMs) {
 		return
 	}
 
-	s.exemplars = append(s.exemplars, Exemplar{
-		Labels:      lbls,
-		Value:       value,
-		TimestampMs: ts,
-	})
+	s.exemplars = append(s.exemplars, e)
 }
 
 func (s *StepAggregator) Samples() []float64 {
@@ -531,6 +609,16 @@ func (s *StepAggregator) Length() int {
 	return 0
 }
 
+// SamplingWeighted returns true if any interval was scaled by sampling weights.
+func (s *StepAggregator) SamplingWeighted() bool {
+	for _, v := range s.vectors {
+		if c, ok := v.(*CountOverTimeAggregator); ok && c.weighted {
+			return true
+		}
+	}
+	return false
+}
+
 const maxGroupBys = 5 // TODO - This isn't ideal but see comment below.
 
 // FastValues is an array of attribute values (static values) that can be used
@@ -709,14 +797,7 @@ func (g *GroupingAggregator[F, S]) ObserveExemplar(span Span, value float64, ts
 	}
 
 	s := g.getSeries()
-
-	// Observe exemplar
-	all := span.AllAttributes()
-	lbls := make(Labels, 0, len(all))
-	for k, v := range span.AllAttributes() {
-		lbls = append(lbls, Label{k.String(), v})
-	}
-	s.agg.ObserveExemplar(value, ts, lbls)
+	s.agg.ObserveExemplar(newExemplar(span, value, ts))
 }
 
 func (g *GroupingAggregator[F, S]) Length() int {
@@ -779,9 +860,10 @@ func (g *GroupingAggregator[F, S]) Series() SeriesSet {
 		labels, promLabels := g.labelsFor(s.vals)
 
 		ss[promLabels] = TimeSeries{
-			Labels:    labels,
-			Values:    s.agg.Samples(),
-			Exemplars: s.agg.Exemplars(),
+			Labels:           labels,
+			Values:           s.agg.Samples(),
+			Exemplars:        s.agg.Exemplars(),
+			SamplingWeighted: s.agg.SamplingWeighted(),
 		}
 	}
 
@@ -801,12 +883,7 @@ func (u *UngroupedAggregator) Observe(span Span) {
 }
 
 func (u *UngroupedAggregator) ObserveExemplar(span Span, value float64, ts uint64) {
-	all := span.AllAttributes()
-	lbls := make(Labels, 0, len(all))
-	for k, v := range all {
-		lbls = append(lbls, Label{k.String(), v})
-	}
-	u.innerAgg.ObserveExemplar(value, ts, lbls)
+	u.innerAgg.ObserveExemplar(newExemplar(span, value, ts))
 }
 
 func (u *UngroupedAggregator) Length() int {
@@ -821,9 +898,10 @@ func (u *UngroupedAggregator) Series() SeriesSet {
 	l := labels.FromStrings(labels.MetricName, u.name)
 	return SeriesSet{
 		l.String(): {
-			Labels:    []Label{{labels.MetricName, NewStaticString(u.name)}},
-			Values:    u.innerAgg.Samples(),
-			Exemplars: u.innerAgg.Exemplars(),
+			Labels:           []Label{{labels.MetricName, NewStaticString(u.name)}},
+			Values:           u.innerAgg.Samples(),
+			Exemplars:        u.innerAgg.Exemplars(),
+			SamplingWeighted: u.innerAgg.SamplingWeighted(),
 		},
 	}
 }
@@ -898,6 +976,14 @@ func (e *Engine) CompileMetricsQueryRange(req *tempopb.QueryRangeRequest, exempl
 		exemplars = v
 	}
 
+	// Sampling weights are only supported by the aggregates that count spans
+	if v, ok := expr.Hints.GetBool(HintSamplingWeights, allowUnsafeQueryHints); ok && v {
+		if agg, ok := metricsPipeline.(*MetricsAggregate); ok && agg.countsSpans() {
+			agg.samplingWeights = true
+			storageReq.SecondPassConditions = append(storageReq.SecondPassConditions, samplingWeightConditions(storageReq)...)
+		}
+	}
+
 	// This initializes all step buffers, counters, etc
 	metricsPipeline.init(req, AggregateModeRaw)
 
@@ -1057,6 +1143,7 @@ type MetricsEvaluator struct {
 	storageReq                      *FetchSpansRequest
 	metricsPipeline                 firstStageElement
 	spansTotal, spansDeduped, bytes uint64
+	extrapolation                   float64
 	mtx                             sync.Mutex
 }
 
@@ -1190,7 +1277,27 @@ func (e *MetricsEvaluator) Results() SeriesSet {
 	// can only be processed on the frontend.
 	// we could do this but it would require knowing if the first stage functions
 	// can be pushed down to second stage or not so we are skipping it for now, and will handle it later.
-	return e.metricsPipeline.result()
+	res := e.metricsPipeline.result()
+
+	if e.extrapolation > 0 {
+		if agg, ok := e.metricsPipeline.(*MetricsAggregate); ok && agg.extrapolates() {
+			for _, ts := range res {
+				for i := range ts.Values {
+					ts.Values[i] *= e.extrapolation
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// Extrapolate scales the results of the aggregates that count or sum spans to estimate the results of the whole
+// data when only the given fraction of it was read. The other aggregates are estimated by the sample as is.
+func (e *MetricsEvaluator) Extrapolate(fraction float64) {
+	if fraction > 0 && fraction < 1 {
+		e.extrapolation = 1 / fraction
+	}
 }
 
 func (e *MetricsEvaluator) sampleExemplar(id []byte) bool {
@@ -1349,6 +1456,7 @@ func (b *SimpleAggregator) Combine(in []*tempopb.TimeSeries) {
 		}
 
 		b.aggregateExemplars(ts, &existing)
+		existing.SamplingWeighted = existing.SamplingWeighted || ts.SamplingWeighted
 
 		b.ss[ts.PromLabels] = existing
 	}
@@ -1359,21 +1467,14 @@ func (b *SimpleAggregator) aggregateExemplars(ts *tempopb.TimeSeries, existing *
 		if b.exemplarBuckets.testTotal() {
 			break
 		}
-		if b.exemplarBuckets.addAndTest(uint64(exemplar.TimestampMs)) { //nolint: gosec // G115
-			continue // Skip this exemplar and continue, next exemplar might fit in a different bucket	}
+		e := exemplarFromProto(exemplar)
+		if containsSpan(existing.Exemplars, e) {
+			continue // the span was already found by another job
 		}
-		labels := make(Labels, 0, len(exemplar.Labels))
-		for _, l := range exemplar.Labels {
-			labels = append(labels, Label{
-				Name:  l.Key,
-				Value: StaticFromAnyValue(l.Value),
-			})
+		if b.exemplarBuckets.addAndTest(e.TimestampMs) {
+			continue // Skip this exemplar and continue, next exemplar might fit in a different bucket	}
 		}
-		existing.Exemplars = append(existing.Exemplars, Exemplar{
-			Labels:      labels,
-			Value:       exemplar.Value,
-			TimestampMs: uint64(exemplar.TimestampMs),
-		})
+		existing.Exemplars = append(existing.Exemplars, e)
 	}
 }
 
@@ -1412,6 +1513,7 @@ type histSeries struct {
 	labels    Labels
 	hist      []Histogram
 	exemplars []Exemplar
+	weighted  bool
 }
 
 type HistogramAggregator struct {
@@ -1481,30 +1583,25 @@ func (h *HistogramAggregator) Combine(in []*tempopb.TimeSeries) {
 			}
 			j := IntervalOfMs(sample.TimestampMs, h.start, h.end, h.step)
 			if j >= 0 && j < len(existing.hist) {
-				existing.hist[j].Record(b, int(sample.Value))
+				// counts are fractional when weighted by sampling
+				existing.hist[j].Record(b, int(math.Round(sample.Value)))
 			}
 		}
+		existing.weighted = existing.weighted || ts.SamplingWeighted
 
 		for _, exemplar := range ts.Exemplars {
 			if h.exemplarBuckets.testTotal() {
 				break
 			}
-			if h.exemplarBuckets.addAndTest(uint64(exemplar.TimestampMs)) { //nolint: gosec // G115
+			e := exemplarFromProto(exemplar)
+			if containsSpan(existing.exemplars, e) {
+				continue // the span was already found by another job
+			}
+			if h.exemplarBuckets.addAndTest(e.TimestampMs) {
 				continue // Skip this exemplar and continue, next exemplar might fit in a different bucket
 			}
 
-			labels := make(Labels, 0, len(exemplar.Labels))
-			for _, l := range exemplar.Labels {
-				labels = append(labels, Label{
-					Name:  l.Key,
-					Value: StaticFromAnyValue(l.Value),
-				})
-			}
-			existing.exemplars = append(existing.exemplars, Exemplar{
-				Labels:      labels,
-				Value:       exemplar.Value,
-				TimestampMs: uint64(exemplar.TimestampMs),
-			})
+			existing.exemplars = append(existing.exemplars, e)
 		}
 		h.ss[withoutBucketStr] = existing
 	}
@@ -1522,9 +1619,10 @@ func (h *HistogramAggregator) Results() SeriesSet {
 			s := labels.String()
 
 			ts := TimeSeries{
-				Labels:    labels,
-				Values:    make([]float64, len(in.hist)),
-				Exemplars: in.exemplars,
+				Labels:           labels,
+				Values:           make([]float64, len(in.hist)),
+				Exemplars:        in.exemplars,
+				SamplingWeighted: in.weighted,
 			}
 			for i := range in.hist {
 
@@ -1759,9 +1857,10 @@ func initSeriesInResult(result SeriesSet, key string, input SeriesSet, valueLeng
 	// series doesn't exist, initialize it
 	// Copy the series labels and exemplars from the input
 	result[key] = TimeSeries{
-		Labels:    input[key].Labels,
-		Values:    make([]float64, valueLength),
-		Exemplars: input[key].Exemplars,
+		Labels:           input[key].Labels,
+		Values:           make([]float64, valueLength),
+		Exemplars:        input[key].Exemplars,
+		SamplingWeighted: input[key].SamplingWeighted,
 	}
 
 	// Initialize all values to NaN because we only want to set values for this timestamp
@@ -301,25 +301,17 @@ func (b *averageOverTimeSeriesAggregator) aggregateExemplars(ts *tempopb.TimeSer
 		if b.exemplarBuckets.testTotal() {
 			break
 		}
-		if b.exemplarBuckets.addAndTest(uint64(exemplar.TimestampMs)) { //nolint: gosec // G115
-			continue // Skip this exemplar and continue, next exemplar might fit in a different bucket	}
+		e := exemplarFromProto(exemplar)
+		if containsSpan(existing.Exemplars, e) {
+			continue // the span was already found by another job
 		}
-		labels := make(Labels, 0, len(exemplar.Labels))
-		for _, l := range exemplar.Labels {
-			labels = append(labels, Label{
-				Name:  l.Key,
-				Value: StaticFromAnyValue(l.Value),
-			})
+		if b.exemplarBuckets.addAndTest(e.TimestampMs) {
+			continue // Skip this exemplar and continue, next exemplar might fit in a different bucket	}
 		}
-		value := exemplar.Value
-		if math.IsNaN(value) {
-			value = 0 // TODO: Use the value of the series at the same timestamp
+		if math.IsNaN(e.Value) {
+			e.Value = 0 // TODO: Use the value of the series at the same timestamp
 		}
-		existing.Exemplars = append(existing.Exemplars, Exemplar{
-			Labels:      labels,
-			Value:       value,
-			TimestampMs: uint64(exemplar.TimestampMs),
-		})
+		existing.Exemplars = append(existing.Exemplars, e)
 	}
 }
 
@@ -463,17 +455,7 @@ func (g *avgOverTimeSpanAggregator[F, S]) ObserveExemplar(span Span, value float
 		return
 	}
 
-	all := span.AllAttributes()
-	lbls := make(Labels, 0, len(all))
-	for k, v := range span.AllAttributes() {
-		lbls = append(lbls, Label{k.String(), v})
-	}
-
-	s.average.Exemplars = append(s.average.Exemplars, Exemplar{
-		Labels:      lbls,
-		Value:       value,
-		TimestampMs: ts,
-	})
+	s.average.Exemplars = append(s.average.Exemplars, newExemplar(span, value, ts))
 	g.series[g.buf.fast] = s
 }
 
@@ -181,16 +181,7 @@ func (m *MetricsCompare) observeExemplar(span Span) {
 		return
 	}
 
-	all := span.AllAttributes()
-	lbls := make(Labels, 0, len(all))
-	for a, v := range all {
-		lbls = append(lbls, Label{Name: a.String(), Value: v})
-	}
-	exemplar := Exemplar{
-		Labels:      lbls,
-		Value:       math.NaN(), // TODO: What value?
-		TimestampMs: span.StartTimeUnixNanos() / uint64(time.Millisecond),
-	}
+	exemplar := newExemplar(span, math.NaN(), span.StartTimeUnixNanos()/uint64(time.Millisecond)) // TODO: What value?
 	if isSelection.Equals(&StaticTrue) {
 		m.selectionExemplars = append(m.selectionExemplars, exemplar)
 	} else {
@@ -442,19 +433,15 @@ func (b *BaselineAggregator) Combine(ss []*tempopb.TimeSeries) {
 			if b.exemplarBuckets.testTotal() {
 				break
 			}
-			if b.exemplarBuckets.addAndTest(uint64(exemplar.TimestampMs)) { //nolint: gosec // G115
+			e := exemplarFromProto(exemplar)
+			if containsSpan(ts.series.Exemplars, e) {
+				continue // the span was already found by another job
+			}
+			if b.exemplarBuckets.addAndTest(e.TimestampMs) {
 				continue
 			}
 
-			lbls := make(Labels, 0, len(exemplar.Labels))
-			for _, l := range exemplar.Labels {
-				lbls = append(lbls, Label{Name: l.Key, Value: StaticFromAnyValue(l.Value)})
-			}
-			ts.series.Exemplars = append(ts.series.Exemplars, Exemplar{
-				Labels:      lbls,
-				Value:       exemplar.Value,
-				TimestampMs: uint64(exemplar.TimestampMs),
-			})
+			ts.series.Exemplars = append(ts.series.Exemplars, e)
 		}
 		attr[vk] = ts
 	}
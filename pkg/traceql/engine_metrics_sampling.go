@@ -0,0 +1,76 @@
+package traceql
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Span attributes that carry the OpenTelemetry sampling decision of a span. See
+// https://opentelemetry.io/docs/specs/otel/trace/tracestate-probability-sampling/
+const (
+	// samplingAdjustedCountName is the number of spans in the population represented by this span.
+	samplingAdjustedCountName = "sampling.adjusted_count"
+	// samplingThresholdName is the rejection threshold as encoded in the th key of the OpenTelemetry tracestate.
+	// Up to 14 hex digits with trailing zeros removed, e.g. "c" is a threshold of 75% or a sampling
+	// probability of 25%.
+	samplingThresholdName = "sampling.threshold"
+
+	// maxSamplingThreshold is the exclusive upper bound of the 56 bit rejection threshold.
+	maxSamplingThreshold    = 1 << 56
+	samplingThresholdDigits = 14
+)
+
+var (
+	samplingAdjustedCountAttribute = NewScopedAttribute(AttributeScopeSpan, false, samplingAdjustedCountName)
+	samplingThresholdAttribute     = NewScopedAttribute(AttributeScopeSpan, false, samplingThresholdName)
+)
+
+// samplingWeightConditions returns the conditions needed to compute the sampling weight of spans that are not
+// already part of the request.
+func samplingWeightConditions(req *FetchSpansRequest) []Condition {
+	var conds []Condition
+	for _, a := range []Attribute{samplingAdjustedCountAttribute, samplingThresholdAttribute} {
+		if !req.HasAttribute(a) {
+			conds = append(conds, Condition{Attribute: a})
+		}
+	}
+	return conds
+}
+
+// samplingWeight returns the number of spans represented by the given span. An explicit adjusted count takes
+// precedence over the threshold. Spans without either attribute, or with an invalid value, count as 1.
+func samplingWeight(s Span) float64 {
+	if v, ok := s.AttributeFor(samplingAdjustedCountAttribute); ok {
+		switch v.Type {
+		case TypeInt, TypeFloat:
+			if f := v.Float(); f > 0 && !math.IsInf(f, 0) {
+				return f
+			}
+		}
+	}
+
+	if v, ok := s.AttributeFor(samplingThresholdAttribute); ok && v.Type == TypeString {
+		if w, ok := weightForThreshold(v.EncodeToString(false)); ok {
+			return w
+		}
+	}
+
+	return 1
+}
+
+// weightForThreshold converts an OpenTelemetry rejection threshold into the adjusted count of the span.
+// A threshold of T means spans were kept with probability (2^56 - T) / 2^56.
+func weightForThreshold(th string) (float64, bool) {
+	if th == "" || len(th) > samplingThresholdDigits {
+		return 0, false
+	}
+
+	// the threshold is encoded with trailing zeros removed
+	t, err := strconv.ParseUint(th+strings.Repeat("0", samplingThresholdDigits-len(th)), 16, 64)
+	if err != nil || t >= maxSamplingThreshold {
+		return 0, false
+	}
+
+	return float64(maxSamplingThreshold) / float64(maxSamplingThreshold-t), true
+}
@@ -2329,3 +2329,223 @@ func generateTestTimeSeries(seriesCount, samplesCount, exemplarCount int, start,
 
 	return result
 }
+
+func TestSamplingWeight(t *testing.T) {
+	tcs := []struct {
+		name     string
+		span     Span
+		expected float64
+	}{
+		{name: "unsampled", span: newMockSpan(nil), expected: 1},
+		{name: "adjusted count", span: newMockSpan(nil).WithSpanInt(samplingAdjustedCountName, 10), expected: 10},
+		{name: "adjusted count float", span: newMockSpan(nil).WithSpanFloat(samplingAdjustedCountName, 2.5), expected: 2.5},
+		{name: "adjusted count invalid", span: newMockSpan(nil).WithSpanInt(samplingAdjustedCountName, 0), expected: 1},
+		{name: "threshold 50%", span: newMockSpan(nil).WithSpanString(samplingThresholdName, "8"), expected: 2},
+		{name: "threshold 25%", span: newMockSpan(nil).WithSpanString(samplingThresholdName, "c"), expected: 4},
+		{name: "threshold always sample", span: newMockSpan(nil).WithSpanString(samplingThresholdName, "0"), expected: 1},
+		{name: "threshold invalid", span: newMockSpan(nil).WithSpanString(samplingThresholdName, "xyz"), expected: 1},
+		{name: "threshold too long", span: newMockSpan(nil).WithSpanString(samplingThresholdName, "fffffffffffffff"), expected: 1},
+		{
+			name:     "adjusted count takes precedence",
+			span:     newMockSpan(nil).WithSpanInt(samplingAdjustedCountName, 3).WithSpanString(samplingThresholdName, "8"),
+			expected: 3,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, samplingWeight(tc.span))
+		})
+	}
+}
+
+func TestCountOverTimeSamplingWeights(t *testing.T) {
+	in := []Span{
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanString("foo", "bar").WithSpanString(samplingThresholdName, "c"),
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanString("foo", "bar").WithSpanInt(samplingAdjustedCountName, 10),
+		newMockSpan(nil).WithStartTime(uint64(2*time.Second)).WithSpanString("foo", "bar"),
+
+		newMockSpan(nil).WithStartTime(uint64(2*time.Second)).WithSpanString("foo", "baz"),
+		newMockSpan(nil).WithStartTime(uint64(3*time.Second)).WithSpanString("foo", "baz"),
+	}
+
+	tcs := []struct {
+		query    string
+		expected SeriesSet
+	}{
+		{
+			query: "{ } | count_over_time() by (span.foo) with(sampling_weights=true)",
+			expected: SeriesSet{
+				`{"span.foo"="bar"}`: TimeSeries{
+					Labels:           []Label{{Name: "span.foo", Value: NewStaticString("bar")}},
+					Values:           []float64{14, 1, 0},
+					Exemplars:        make([]Exemplar, 0),
+					SamplingWeighted: true,
+				},
+				`{"span.foo"="baz"}`: TimeSeries{
+					Labels:    []Label{{Name: "span.foo", Value: NewStaticString("baz")}},
+					Values:    []float64{0, 1, 1},
+					Exemplars: make([]Exemplar, 0),
+				},
+			},
+		},
+		{
+			// weights are ignored without the hint
+			query: "{ } | count_over_time() by (span.foo)",
+			expected: SeriesSet{
+				`{"span.foo"="bar"}`: TimeSeries{
+					Labels:    []Label{{Name: "span.foo", Value: NewStaticString("bar")}},
+					Values:    []float64{2, 1, 0},
+					Exemplars: make([]Exemplar, 0),
+				},
+				`{"span.foo"="baz"}`: TimeSeries{
+					Labels:    []Label{{Name: "span.foo", Value: NewStaticString("baz")}},
+					Values:    []float64{0, 1, 1},
+					Exemplars: make([]Exemplar, 0),
+				},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			req := &tempopb.QueryRangeRequest{
+				Start: uint64(1 * time.Second),
+				End:   uint64(3 * time.Second),
+				Step:  uint64(1 * time.Second),
+				Query: tc.query,
+			}
+
+			result, _, err := runTraceQLMetric(req, in)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestCompileMetricsQueryRangeSamplingWeights(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Start: 1,
+		End:   2,
+		Step:  1,
+		Query: "{ } | rate() with(sampling_weights=true)",
+	}
+
+	eval, err := NewEngine().CompileMetricsQueryRange(req, 0, 0, false)
+	require.NoError(t, err)
+	require.True(t, eval.storageReq.HasAttribute(samplingAdjustedCountAttribute))
+	require.True(t, eval.storageReq.HasAttribute(samplingThresholdAttribute))
+
+	// aggregates of attribute values are not weighted
+	req.Query = "{ } | max_over_time(duration) with(sampling_weights=true)"
+	eval, err = NewEngine().CompileMetricsQueryRange(req, 0, 0, false)
+	require.NoError(t, err)
+	require.False(t, eval.storageReq.HasAttribute(samplingAdjustedCountAttribute))
+}
+
+func TestMetricsEvaluatorExtrapolate(t *testing.T) {
+	in := []Span{
+		newMockSpan(nil).WithStartTime(uint64(1 * time.Second)).WithDuration(uint64(2 * time.Second)),
+		newMockSpan(nil).WithStartTime(uint64(1 * time.Second)).WithDuration(uint64(4 * time.Second)),
+		newMockSpan(nil).WithStartTime(uint64(2 * time.Second)).WithDuration(uint64(1 * time.Second)),
+	}
+
+	tcs := []struct {
+		query    string
+		expected []float64
+	}{
+		{query: "{ } | count_over_time()", expected: []float64{20, 10, 0}},
+		{query: "{ } | sum_over_time(duration)", expected: []float64{60, 10, math.NaN()}},
+		// aggregates that don't grow with the number of spans are estimated by the sample as is
+		{query: "{ } | max_over_time(duration)", expected: []float64{4, 1, math.NaN()}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			req := &tempopb.QueryRangeRequest{
+				Start: uint64(1 * time.Second),
+				End:   uint64(3 * time.Second),
+				Step:  uint64(1 * time.Second),
+				Query: tc.query,
+			}
+
+			eval, err := NewEngine().CompileMetricsQueryRange(req, 0, 0, false)
+			require.NoError(t, err)
+			eval.Extrapolate(0.1)
+			for _, s := range in {
+				eval.metricsPipeline.observe(s)
+			}
+
+			res := eval.Results()
+			require.Len(t, res, 1)
+			for _, ts := range res {
+				require.Len(t, ts.Values, len(tc.expected))
+				for i, v := range tc.expected {
+					if math.IsNaN(v) {
+						require.True(t, math.IsNaN(ts.Values[i]))
+						continue
+					}
+					require.InDelta(t, v, ts.Values[i], 0.0001)
+				}
+			}
+		})
+	}
+}
+
+func TestMetricsExemplarsSpan(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Start:     uint64(1 * time.Second),
+		End:       uint64(3 * time.Second),
+		Step:      uint64(1 * time.Second),
+		Query:     "{ } | quantile_over_time(duration, .5) by (span.foo)",
+		Exemplars: 10,
+	}
+
+	span := newMockSpan(nil).WithStartTime(uint64(1500*time.Millisecond)).WithDuration(uint64(250*time.Millisecond)).WithSpanString("foo", "bar")
+	span.attributes[IntrinsicTraceIDAttribute] = NewStaticString("0102")
+
+	e := NewEngine()
+	jobResult := func() []*tempopb.TimeSeries {
+		layer1, err := e.CompileMetricsQueryRange(req, 10, 0, false)
+		require.NoError(t, err)
+		layer1.metricsPipeline.observe(span)
+		layer1.metricsPipeline.observeExemplar(span)
+		return layer1.Results().ToProto(req)
+	}
+
+	// exemplars locate their span in the trace
+	series := jobResult()
+	var exemplars []tempopb.Exemplar
+	for _, s := range series {
+		exemplars = append(exemplars, s.Exemplars...)
+	}
+	require.Len(t, exemplars, 1)
+	require.Equal(t, uint64(1500*time.Millisecond), exemplars[0].SpanStartTimeUnixNano)
+	require.Equal(t, uint64(250*time.Millisecond), exemplars[0].SpanDurationNanos)
+	require.Equal(t, int64(1500), exemplars[0].TimestampMs)
+
+	// the exemplars of a span found by two jobs are merged
+	for _, mode := range []AggregateMode{AggregateModeSum, AggregateModeFinal} {
+		layer, err := e.CompileMetricsQueryRangeNonRaw(req, mode)
+		require.NoError(t, err)
+		input := jobResult()
+		if mode == AggregateModeFinal {
+			layer2, err := e.CompileMetricsQueryRangeNonRaw(req, AggregateModeSum)
+			require.NoError(t, err)
+			layer2.ObserveSeries(jobResult())
+			input = layer2.Results().ToProto(req)
+		}
+		layer.ObserveSeries(input)
+		layer.ObserveSeries(input)
+
+		count := 0
+		for _, s := range layer.Results() {
+			for _, ex := range s.Exemplars {
+				require.Equal(t, uint64(1500*time.Millisecond), ex.SpanStartTimeUnixNano)
+				require.Equal(t, uint64(250*time.Millisecond), ex.SpanDurationNanos)
+				count++
+			}
+		}
+		require.Equal(t, 1, count, mode)
+	}
+}
@@ -403,6 +403,49 @@ func (m *MockAutocompleteFetcher) Fetch(ctx context.Context, req FetchTagValuesR
 	return nil
 }
 
+func TestEngine_ExecuteSearchOrderBy(t *testing.T) {
+	e := NewEngine()
+
+	req := &tempopb.SearchRequest{
+		Query:           `{ .foo = "value" }`,
+		SpansPerSpanSet: 1,
+		Limit:           2,
+		OrderBy:         ".n",
+	}
+	spanWithN := func(id byte, n int) *mockSpan {
+		return &mockSpan{
+			id: []byte{id},
+			attributes: map[Attribute]Static{
+				NewAttribute("foo"): NewStaticString("value"),
+				NewAttribute("n"):   NewStaticInt(n),
+			},
+		}
+	}
+	spanSetFetcher := MockSpanSetFetcher{
+		iterator: &MockSpanSetIterator{
+			results: []*Spanset{
+				{TraceID: []byte{1}, Spans: []Span{&mockSpan{id: []byte{1}, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("value")}}}},
+				{TraceID: []byte{2}, Spans: []Span{spanWithN(2, 1), spanWithN(3, 5)}},
+				{TraceID: []byte{3}, Spans: []Span{spanWithN(4, 3)}},
+			},
+		},
+	}
+	response, err := e.ExecuteSearch(context.Background(), req, &spanSetFetcher)
+	require.NoError(t, err)
+
+	// the order attribute is fetched with the spans
+	require.Contains(t, spanSetFetcher.capturedRequest.SecondPassConditions, newCondition(NewAttribute("n"), OpNone))
+
+	require.Len(t, response.Traces, 2)
+	require.Equal(t, "2", response.Traces[0].TraceID)
+	require.Equal(t, "3", response.Traces[1].TraceID)
+	// the span with the greatest value is kept when the spanset is truncated
+	require.Equal(t, "0000000000000003", response.Traces[0].SpanSets[0].Spans[0].SpanID)
+
+	_, err = e.ExecuteSearch(context.Background(), &tempopb.SearchRequest{Query: "{}", OrderBy: "name"}, &spanSetFetcher)
+	require.Error(t, err)
+}
+
 type MockSpanSetFetcher struct {
 	iterator        SpansetIterator
 	capturedRequest FetchSpansRequest
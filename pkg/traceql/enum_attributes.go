@@ -83,6 +83,8 @@ const (
 	IntrinsicLinkTraceID
 	IntrinsicInstrumentationName
 	IntrinsicInstrumentationVersion
+	IntrinsicTraceState
+	IntrinsicTraceSampled
 
 	// not yet implemented in traceql but will be
 	IntrinsicParent
@@ -139,6 +141,8 @@ var (
 	IntrinsicEventTimeSinceStartAttribute    = NewIntrinsic(IntrinsicEventTimeSinceStart)
 	IntrinsicInstrumentationNameAttribute    = NewIntrinsic(IntrinsicInstrumentationName)
 	IntrinsicInstrumentationVersionAttribute = NewIntrinsic(IntrinsicInstrumentationVersion)
+	IntrinsicTraceStateAttribute             = NewIntrinsic(IntrinsicTraceState)
+	IntrinsicTraceSampledAttribute           = NewIntrinsic(IntrinsicTraceSampled)
 )
 
 func (i Intrinsic) String() string {
@@ -201,6 +205,10 @@ func (i Intrinsic) String() string {
 		return "instrumentation:name"
 	case IntrinsicInstrumentationVersion:
 		return "instrumentation:version"
+	case IntrinsicTraceState:
+		return "trace:state"
+	case IntrinsicTraceSampled:
+		return "trace:sampled"
 	// below is unimplemented
 	case IntrinsicSpanStartTime:
 		return "spanStartTime"
@@ -274,6 +282,10 @@ func intrinsicFromString(s string) Intrinsic {
 		return IntrinsicInstrumentationName
 	case "instrumentation:version":
 		return IntrinsicInstrumentationVersion
+	case "trace:state":
+		return IntrinsicTraceState
+	case "trace:sampled":
+		return IntrinsicTraceSampled
 	// unimplemented
 	case "spanStartTime":
 		return IntrinsicSpanStartTime
@@ -1,23 +1,26 @@
 package traceql
 
 import (
+	"strconv"
+	"strings"
 	"time"
 )
 
 // The list of all traceql query hints.  Although most of these are implementation-specific
 // and not part of the language or engine, we organize them here in one place.
 const (
-	HintSample            = "sample"
+	HintSample            = "sample" // read a deterministic sample of the data, e.g. with(sample=0.1) or with(sample="10%")
 	HintJobSize           = "job_size"
 	HintTimeOverlapCutoff = "time_overlap_cutoff"
 	HintConcurrentBlocks  = "concurrent_blocks"
 	HintExemplars         = "exemplars"
 	HintMostRecent        = "most_recent" // traceql search hint to return most recent results ordered by time
+	HintSamplingWeights   = "sampling_weights"
 )
 
 func isUnsafe(h string) bool {
 	switch h {
-	case HintSample, HintExemplars, HintMostRecent:
+	case HintSample, HintExemplars, HintMostRecent, HintSamplingWeights:
 		return false
 	default:
 		return true
@@ -82,6 +85,29 @@ func (h *Hints) GetBool(k string, allowUnsafe bool) (v, ok bool) {
 	return
 }
 
+// GetSample returns the fraction of the data to read of the sample hint. The hint is either a fraction like
+// with(sample=0.1) or a percentage like with(sample="10%"). ok is false unless the fraction is in (0, 1).
+func (h *Hints) GetSample(allowUnsafe bool) (v float64, ok bool) {
+	if s, ok := h.Get(HintSample, TypeString, allowUnsafe); ok {
+		pct, found := strings.CutSuffix(strings.TrimSpace(s.EncodeToString(false)), "%")
+		if !found {
+			return 0, false
+		}
+		n, err := strconv.ParseFloat(pct, 64)
+		if err != nil {
+			return 0, false
+		}
+		v = n / 100
+	} else if v, ok = h.GetFloat(HintSample, allowUnsafe); !ok {
+		return 0, false
+	}
+
+	if v <= 0 || v >= 1 {
+		return 0, false
+	}
+	return v, true
+}
+
 func (h *Hints) Get(k string, t StaticType, allowUnsafe bool) (v Static, ok bool) {
 	if h == nil {
 		return
@@ -0,0 +1,37 @@
+package traceql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHintsGetSample(t *testing.T) {
+	tcs := []struct {
+		query    string
+		expected float64
+		ok       bool
+	}{
+		{query: `{ } | rate() with(sample=0.1)`, expected: 0.1, ok: true},
+		{query: `{ } | rate() with(sample="25%")`, expected: 0.25, ok: true},
+		{query: `{ } | rate() with(sample="0.5%")`, expected: 0.005, ok: true},
+		{query: `{ } with(sample="10%")`, expected: 0.1, ok: true},
+		{query: `{ } | rate()`},
+		{query: `{ } | rate() with(sample=1)`},
+		{query: `{ } | rate() with(sample="100%")`},
+		{query: `{ } | rate() with(sample=0)`},
+		{query: `{ } | rate() with(sample="10")`},
+		{query: `{ } | rate() with(sample="abc%")`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			expr, err := Parse(tc.query)
+			require.NoError(t, err)
+
+			v, ok := expr.Hints.GetSample(false)
+			require.Equal(t, tc.ok, ok)
+			require.InDelta(t, tc.expected, v, 0.000001)
+		})
+	}
+}
@@ -96,7 +96,7 @@ import (
                         KIND_UNSPECIFIED KIND_INTERNAL KIND_SERVER KIND_CLIENT KIND_PRODUCER KIND_CONSUMER
                         IDURATION CHILDCOUNT NAME STATUS STATUS_MESSAGE PARENT KIND ROOTNAME ROOTSERVICENAME 
                         ROOTSERVICE TRACEDURATION NESTEDSETLEFT NESTEDSETRIGHT NESTEDSETPARENT ID 
-                        TRACE_ID SPAN_ID PARENT_ID TIMESINCESTART VERSION
+                        TRACE_ID SPAN_ID PARENT_ID TIMESINCESTART VERSION STATE SAMPLED
                         PARENT_DOT RESOURCE_DOT SPAN_DOT TRACE_COLON SPAN_COLON 
                         EVENT_COLON EVENT_DOT LINK_COLON LINK_DOT INSTRUMENTATION_COLON INSTRUMENTATION_DOT
                         COUNT AVG MAX MIN SUM
@@ -428,6 +428,8 @@ scopedIntrinsicField:
   | TRACE_COLON ROOTNAME            { $$ = NewIntrinsic(IntrinsicTraceRootSpan)          }
   | TRACE_COLON ROOTSERVICE         { $$ = NewIntrinsic(IntrinsicTraceRootService)       }
   | TRACE_COLON ID                  { $$ = NewIntrinsic(IntrinsicTraceID)                }
+  | TRACE_COLON STATE               { $$ = NewIntrinsic(IntrinsicTraceState)             }
+  | TRACE_COLON SAMPLED             { $$ = NewIntrinsic(IntrinsicTraceSampled)           }
 //  span:             
   | SPAN_COLON IDURATION            { $$ = NewIntrinsic(IntrinsicDuration)               }
   | SPAN_COLON NAME                 { $$ = NewIntrinsic(IntrinsicName)                   }
@@ -99,68 +99,70 @@ const SPAN_ID = 57385
 const PARENT_ID = 57386
 const TIMESINCESTART = 57387
 const VERSION = 57388
-const PARENT_DOT = 57389
-const RESOURCE_DOT = 57390
-const SPAN_DOT = 57391
-const TRACE_COLON = 57392
-const SPAN_COLON = 57393
-const EVENT_COLON = 57394
-const EVENT_DOT = 57395
-const LINK_COLON = 57396
-const LINK_DOT = 57397
-const INSTRUMENTATION_COLON = 57398
-const INSTRUMENTATION_DOT = 57399
-const COUNT = 57400
-const AVG = 57401
-const MAX = 57402
-const MIN = 57403
-const SUM = 57404
-const BY = 57405
-const COALESCE = 57406
-const SELECT = 57407
-const END_ATTRIBUTE = 57408
-const RATE = 57409
-const COUNT_OVER_TIME = 57410
-const MIN_OVER_TIME = 57411
-const MAX_OVER_TIME = 57412
-const AVG_OVER_TIME = 57413
-const SUM_OVER_TIME = 57414
-const QUANTILE_OVER_TIME = 57415
-const HISTOGRAM_OVER_TIME = 57416
-const COMPARE = 57417
-const TOPK = 57418
-const BOTTOMK = 57419
-const WITH = 57420
-const PIPE = 57421
-const AND = 57422
-const OR = 57423
-const EQ = 57424
-const NEQ = 57425
-const LT = 57426
-const LTE = 57427
-const GT = 57428
-const GTE = 57429
-const NRE = 57430
-const RE = 57431
-const DESC = 57432
-const ANCE = 57433
-const SIBL = 57434
-const NOT_CHILD = 57435
-const NOT_PARENT = 57436
-const NOT_DESC = 57437
-const NOT_ANCE = 57438
-const UNION_CHILD = 57439
-const UNION_PARENT = 57440
-const UNION_DESC = 57441
-const UNION_ANCE = 57442
-const UNION_SIBL = 57443
-const ADD = 57444
-const SUB = 57445
-const NOT = 57446
-const MUL = 57447
-const DIV = 57448
-const MOD = 57449
-const POW = 57450
+const STATE = 57389
+const SAMPLED = 57390
+const PARENT_DOT = 57391
+const RESOURCE_DOT = 57392
+const SPAN_DOT = 57393
+const TRACE_COLON = 57394
+const SPAN_COLON = 57395
+const EVENT_COLON = 57396
+const EVENT_DOT = 57397
+const LINK_COLON = 57398
+const LINK_DOT = 57399
+const INSTRUMENTATION_COLON = 57400
+const INSTRUMENTATION_DOT = 57401
+const COUNT = 57402
+const AVG = 57403
+const MAX = 57404
+const MIN = 57405
+const SUM = 57406
+const BY = 57407
+const COALESCE = 57408
+const SELECT = 57409
+const END_ATTRIBUTE = 57410
+const RATE = 57411
+const COUNT_OVER_TIME = 57412
+const MIN_OVER_TIME = 57413
+const MAX_OVER_TIME = 57414
+const AVG_OVER_TIME = 57415
+const SUM_OVER_TIME = 57416
+const QUANTILE_OVER_TIME = 57417
+const HISTOGRAM_OVER_TIME = 57418
+const COMPARE = 57419
+const TOPK = 57420
+const BOTTOMK = 57421
+const WITH = 57422
+const PIPE = 57423
+const AND = 57424
+const OR = 57425
+const EQ = 57426
+const NEQ = 57427
+const LT = 57428
+const LTE = 57429
+const GT = 57430
+const GTE = 57431
+const NRE = 57432
+const RE = 57433
+const DESC = 57434
+const ANCE = 57435
+const SIBL = 57436
+const NOT_CHILD = 57437
+const NOT_PARENT = 57438
+const NOT_DESC = 57439
+const NOT_ANCE = 57440
+const UNION_CHILD = 57441
+const UNION_PARENT = 57442
+const UNION_DESC = 57443
+const UNION_ANCE = 57444
+const UNION_SIBL = 57445
+const ADD = 57446
+const SUB = 57447
+const NOT = 57448
+const MUL = 57449
+const DIV = 57450
+const MOD = 57451
+const POW = 57452
 
 var yyToknames = [...]string{
 	"$end",
@@ -209,6 +211,8 @@ var yyToknames = [...]string{
 	"PARENT_ID",
 	"TIMESINCESTART",
 	"VERSION",
+	"STATE",
+	"SAMPLED",
 	"PARENT_DOT",
 	"RESOURCE_DOT",
 	"SPAN_DOT",
@@ -283,98 +287,99 @@ var yyExca = [...]int{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 306,
+	-1, 308,
 	13, 87,
 	-2, 95,
 }
 
 const yyPrivate = 57344
 
-const yyLast = 1147
+const yyLast = 1114
 
 var yyAct = [...]int{
 
-	102, 6, 5, 8, 7, 99, 18, 101, 289, 249,
+	102, 6, 5, 8, 7, 99, 18, 101, 291, 249,
 	12, 90, 67, 238, 239, 240, 249, 77, 231, 230,
-	348, 13, 254, 253, 94, 207, 100, 304, 2, 291,
-	30, 70, 154, 153, 157, 155, 29, 66, 211, 236,
-	237, 365, 238, 239, 240, 249, 87, 88, 89, 90,
-	364, 187, 189, 190, 191, 192, 193, 194, 195, 196,
-	197, 198, 199, 200, 201, 202, 203, 204, 350, 351,
-	341, 78, 79, 80, 81, 82, 83, 213, 241, 242,
+	350, 13, 206, 207, 94, 293, 100, 306, 2, 254,
+	253, 70, 154, 153, 157, 155, 30, 66, 236, 237,
+	29, 238, 239, 240, 249, 87, 88, 89, 90, 206,
+	367, 187, 189, 190, 191, 192, 193, 194, 195, 196,
+	197, 198, 199, 200, 201, 202, 203, 204, 352, 353,
+	366, 78, 79, 80, 81, 82, 83, 213, 241, 242,
 	243, 244, 245, 246, 248, 247, 74, 75, 76, 77,
-	340, 85, 86, 234, 87, 88, 89, 90, 236, 237,
-	233, 238, 239, 240, 249, 206, 221, 223, 224, 225,
-	226, 227, 228, 339, 336, 335, 229, 334, 333, 232,
-	252, 206, 255, 256, 414, 391, 345, 85, 86, 387,
-	87, 88, 89, 90, 103, 104, 105, 108, 131, 386,
-	93, 95, 385, 384, 96, 106, 107, 110, 109, 111,
-	112, 113, 114, 115, 116, 117, 118, 119, 120, 121,
-	122, 124, 123, 125, 126, 211, 127, 128, 129, 130,
-	301, 362, 284, 285, 286, 287, 134, 132, 133, 138,
-	139, 140, 135, 141, 136, 142, 137, 207, 371, 302,
-	209, 301, 423, 250, 251, 241, 242, 243, 244, 245,
+	364, 85, 86, 234, 87, 88, 89, 90, 236, 237,
+	233, 238, 239, 240, 249, 343, 221, 223, 224, 225,
+	226, 227, 228, 342, 341, 338, 229, 207, 337, 232,
+	252, 336, 255, 256, 347, 85, 86, 335, 87, 88,
+	89, 90, 103, 104, 105, 108, 131, 416, 93, 95,
+	393, 389, 96, 106, 107, 110, 109, 111, 112, 113,
+	114, 115, 116, 117, 118, 119, 120, 121, 122, 124,
+	123, 125, 126, 211, 127, 128, 129, 130, 388, 387,
+	303, 386, 286, 287, 288, 289, 134, 132, 133, 138,
+	139, 140, 135, 141, 136, 142, 137, 373, 211, 304,
+	372, 303, 334, 250, 251, 241, 242, 243, 244, 245,
 	246, 248, 247, 78, 79, 80, 81, 82, 83, 154,
-	153, 157, 155, 370, 306, 236, 237, 332, 238, 239,
-	240, 249, 376, 72, 73, 277, 74, 75, 76, 77,
-	281, 427, 97, 98, 78, 79, 80, 81, 82, 83,
-	394, 278, 308, 393, 302, 85, 86, 282, 87, 88,
-	89, 90, 279, 280, 85, 86, 377, 87, 88, 89,
-	90, 312, 313, 314, 315, 316, 317, 318, 320, 321,
-	322, 323, 324, 325, 326, 327, 328, 375, 330, 72,
-	73, 374, 74, 75, 76, 77, 373, 260, 19, 20,
-	21, 372, 17, 361, 167, 234, 234, 234, 234, 234,
-	234, 353, 233, 233, 233, 233, 233, 233, 67, 352,
-	67, 360, 234, 354, 355, 356, 357, 358, 359, 233,
-	283, 232, 232, 232, 232, 232, 232, 70, 210, 70,
-	363, 261, 262, 426, 311, 308, 422, 311, 232, 421,
-	23, 26, 24, 25, 27, 14, 168, 15, 420, 311,
-	419, 311, 418, 311, 367, 403, 366, 72, 73, 400,
-	74, 75, 76, 77, 154, 153, 157, 155, 417, 311,
-	407, 311, 270, 266, 271, 273, 274, 17, 272, 188,
-	267, 399, 268, 234, 234, 22, 275, 269, 398, 276,
-	233, 233, 406, 311, 404, 405, 402, 401, 234, 234,
-	234, 234, 395, 396, 234, 233, 233, 233, 233, 232,
-	232, 233, 378, 379, 346, 347, 397, 408, 409, 410,
-	411, 383, 234, 415, 232, 232, 232, 232, 382, 233,
-	232, 310, 311, 369, 368, 303, 300, 299, 298, 297,
-	296, 424, 103, 104, 105, 108, 131, 295, 232, 95,
-	294, 293, 331, 106, 107, 110, 109, 111, 112, 113,
+	153, 157, 155, 283, 308, 236, 237, 425, 238, 239,
+	240, 249, 378, 72, 73, 279, 74, 75, 76, 77,
+	284, 429, 97, 98, 78, 79, 80, 81, 82, 83,
+	260, 280, 310, 396, 304, 72, 73, 395, 74, 75,
+	76, 77, 281, 282, 85, 86, 379, 87, 88, 89,
+	90, 314, 315, 316, 317, 318, 319, 320, 322, 323,
+	324, 325, 326, 327, 328, 329, 330, 377, 332, 85,
+	86, 209, 87, 88, 89, 90, 261, 262, 19, 20,
+	21, 376, 17, 375, 167, 17, 374, 234, 234, 234,
+	234, 234, 234, 363, 233, 233, 233, 233, 233, 233,
+	67, 355, 67, 362, 234, 356, 357, 358, 359, 360,
+	361, 233, 354, 232, 232, 232, 232, 232, 232, 70,
+	285, 70, 365, 428, 313, 424, 313, 310, 422, 313,
+	232, 210, 23, 26, 24, 25, 27, 14, 168, 15,
+	266, 421, 313, 420, 313, 423, 369, 267, 368, 268,
+	419, 313, 409, 313, 269, 405, 154, 153, 157, 155,
+	270, 271, 72, 73, 402, 74, 75, 76, 77, 408,
+	313, 401, 19, 20, 21, 234, 234, 22, 222, 28,
+	406, 407, 233, 233, 404, 403, 380, 381, 348, 349,
+	234, 234, 234, 234, 397, 398, 234, 233, 233, 233,
+	233, 232, 232, 233, 312, 313, 17, 400, 188, 410,
+	411, 412, 413, 399, 234, 417, 232, 232, 232, 232,
+	385, 233, 232, 384, 371, 370, 23, 26, 24, 25,
+	27, 305, 302, 426, 103, 104, 105, 108, 131, 301,
+	232, 95, 300, 299, 333, 106, 107, 110, 109, 111,
+	112, 113, 114, 115, 116, 117, 118, 119, 120, 121,
+	122, 124, 123, 125, 126, 298, 127, 128, 129, 130,
+	297, 22, 296, 295, 294, 214, 170, 151, 134, 132,
+	133, 138, 139, 140, 135, 141, 136, 142, 137, 150,
+	149, 148, 103, 104, 105, 108, 131, 147, 146, 95,
+	92, 91, 96, 106, 107, 110, 109, 111, 112, 113,
 	114, 115, 116, 117, 118, 119, 120, 121, 122, 124,
-	123, 125, 126, 292, 127, 128, 129, 130, 214, 170,
-	151, 150, 149, 148, 134, 132, 133, 138, 139, 140,
-	135, 141, 136, 142, 137, 147, 146, 92, 91, 17,
-	103, 104, 105, 108, 131, 413, 412, 95, 390, 389,
-	96, 106, 107, 110, 109, 111, 112, 113, 114, 115,
+	123, 125, 126, 427, 127, 128, 129, 130, 143, 144,
+	145, 415, 414, 418, 97, 98, 134, 132, 133, 138,
+	139, 140, 135, 141, 136, 142, 137, 346, 392, 391,
+	103, 104, 105, 108, 131, 68, 11, 95, 394, 383,
+	321, 106, 107, 110, 109, 111, 112, 113, 114, 115,
 	116, 117, 118, 119, 120, 121, 122, 124, 123, 125,
-	126, 425, 127, 128, 129, 130, 143, 144, 145, 416,
-	97, 98, 134, 132, 133, 138, 139, 140, 135, 141,
-	136, 142, 137, 344, 84, 392, 381, 380, 103, 104,
-	105, 108, 131, 68, 11, 95, 71, 290, 319, 106,
-	107, 110, 109, 111, 112, 113, 114, 115, 116, 117,
-	118, 119, 120, 121, 122, 124, 123, 125, 126, 338,
-	127, 128, 129, 130, 337, 265, 264, 263, 97, 98,
-	134, 132, 133, 138, 139, 140, 135, 141, 136, 142,
-	137, 19, 20, 21, 259, 17, 258, 167, 235, 257,
-	250, 251, 241, 242, 243, 244, 245, 246, 248, 247,
-	343, 28, 288, 388, 69, 212, 215, 216, 217, 218,
-	219, 220, 236, 237, 16, 238, 239, 240, 249, 4,
-	349, 152, 10, 156, 1, 0, 97, 98, 0, 342,
-	0, 0, 0, 23, 26, 24, 25, 27, 14, 168,
-	15, 329, 158, 159, 160, 161, 163, 162, 164, 165,
-	166, 0, 0, 0, 0, 0, 0, 250, 251, 241,
-	242, 243, 244, 245, 246, 248, 247, 250, 251, 241,
-	242, 243, 244, 245, 246, 248, 247, 309, 22, 236,
-	237, 0, 238, 239, 240, 249, 0, 0, 0, 236,
-	237, 0, 238, 239, 240, 249, 250, 251, 241, 242,
-	243, 244, 245, 246, 248, 247, 208, 0, 250, 251,
-	241, 242, 243, 244, 245, 246, 248, 247, 236, 237,
-	0, 238, 239, 240, 249, 0, 0, 0, 205, 0,
-	236, 237, 0, 238, 239, 240, 249, 0, 0, 0,
-	0, 0, 0, 0, 250, 251, 241, 242, 243, 244,
-	245, 246, 248, 247, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 236, 237, 0, 238,
-	239, 240, 249, 48, 53, 0, 0, 50, 0, 49,
+	126, 382, 127, 128, 129, 130, 292, 340, 339, 265,
+	264, 263, 97, 98, 134, 132, 133, 138, 139, 140,
+	135, 141, 136, 142, 137, 19, 20, 21, 259, 17,
+	258, 167, 257, 290, 345, 390, 250, 251, 241, 242,
+	243, 244, 245, 246, 248, 247, 69, 212, 215, 216,
+	217, 218, 219, 220, 84, 16, 4, 351, 236, 237,
+	152, 238, 239, 240, 249, 344, 71, 10, 156, 1,
+	97, 98, 0, 0, 0, 331, 0, 0, 0, 23,
+	26, 24, 25, 27, 14, 168, 15, 0, 158, 159,
+	160, 161, 163, 162, 164, 165, 166, 0, 0, 0,
+	0, 0, 0, 250, 251, 241, 242, 243, 244, 245,
+	246, 248, 247, 311, 0, 0, 0, 0, 0, 0,
+	0, 235, 0, 0, 22, 236, 237, 0, 238, 239,
+	240, 249, 0, 0, 250, 251, 241, 242, 243, 244,
+	245, 246, 248, 247, 250, 251, 241, 242, 243, 244,
+	245, 246, 248, 247, 208, 0, 236, 237, 0, 238,
+	239, 240, 249, 0, 0, 0, 236, 237, 0, 238,
+	239, 240, 249, 0, 0, 0, 205, 0, 0, 0,
+	0, 0, 250, 251, 241, 242, 243, 244, 245, 246,
+	248, 247, 250, 251, 241, 242, 243, 244, 245, 246,
+	248, 247, 0, 0, 236, 237, 0, 238, 239, 240,
+	249, 0, 0, 0, 236, 237, 0, 238, 239, 240,
+	249, 0, 0, 48, 53, 0, 0, 50, 0, 49,
 	0, 57, 0, 51, 52, 54, 55, 56, 59, 58,
 	60, 61, 64, 63, 62, 31, 36, 0, 0, 33,
 	0, 32, 0, 42, 0, 34, 35, 37, 38, 39,
@@ -383,85 +388,81 @@ var yyAct = [...]int{
 	55, 56, 59, 58, 60, 61, 64, 63, 62, 31,
 	36, 0, 0, 33, 0, 32, 0, 42, 0, 34,
 	35, 37, 38, 39, 40, 41, 43, 44, 45, 46,
-	47, 19, 20, 21, 0, 17, 0, 307, 0, 19,
-	20, 21, 50, 17, 49, 305, 57, 0, 51, 52,
+	47, 19, 20, 21, 0, 17, 0, 309, 0, 19,
+	20, 21, 50, 17, 49, 307, 57, 0, 51, 52,
 	54, 55, 56, 59, 58, 60, 61, 64, 63, 62,
 	33, 0, 32, 0, 42, 0, 34, 35, 37, 38,
-	39, 40, 41, 43, 44, 45, 46, 47, 0, 0,
-	0, 0, 0, 23, 26, 24, 25, 27, 14, 0,
-	15, 23, 26, 24, 25, 27, 14, 0, 15, 19,
-	20, 21, 0, 17, 0, 9, 0, 19, 20, 21,
-	0, 17, 0, 167, 19, 20, 21, 0, 0, 0,
-	222, 0, 0, 0, 0, 0, 0, 0, 22, 0,
-	0, 0, 0, 0, 0, 0, 22, 0, 0, 65,
-	3, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 23, 26, 24, 25, 27, 14, 0, 15, 23,
-	26, 24, 25, 27, 0, 0, 23, 26, 24, 25,
-	27, 169, 171, 172, 173, 174, 175, 176, 177, 178,
-	179, 180, 181, 182, 183, 184, 185, 186, 0, 0,
-	0, 0, 0, 0, 131, 0, 22, 0, 0, 0,
-	0, 0, 0, 0, 22, 0, 0, 0, 0, 0,
-	0, 22, 118, 119, 120, 121, 122, 124, 123, 125,
-	126, 0, 127, 128, 129, 130, 0, 0, 0, 0,
+	39, 40, 41, 43, 44, 45, 46, 47, 19, 20,
+	21, 0, 17, 0, 9, 23, 26, 24, 25, 27,
+	14, 0, 15, 23, 26, 24, 25, 27, 14, 0,
+	15, 19, 20, 21, 0, 17, 272, 167, 273, 275,
+	276, 0, 274, 0, 0, 0, 0, 0, 0, 0,
+	277, 0, 131, 278, 0, 0, 0, 0, 0, 0,
+	22, 0, 23, 26, 24, 25, 27, 14, 22, 15,
+	118, 119, 120, 121, 122, 124, 123, 125, 126, 0,
+	127, 128, 129, 130, 0, 23, 26, 24, 25, 27,
 	0, 0, 134, 132, 133, 138, 139, 140, 135, 141,
-	136, 142, 137, 103, 104, 105, 108, 0, 0, 0,
-	214, 0, 0, 0, 106, 107, 110, 109, 111, 112,
-	113, 114, 115, 116, 117, 103, 104, 105, 108, 0,
-	0, 0, 0, 0, 0, 0, 106, 107, 110, 109,
-	111, 112, 113, 114, 115, 116, 117,
+	136, 142, 137, 65, 3, 0, 0, 22, 103, 104,
+	105, 108, 0, 0, 0, 214, 0, 0, 0, 106,
+	107, 110, 109, 111, 112, 113, 114, 115, 116, 117,
+	22, 0, 0, 0, 0, 169, 171, 172, 173, 174,
+	175, 176, 177, 178, 179, 180, 181, 182, 183, 184,
+	185, 186, 103, 104, 105, 108, 0, 0, 0, 0,
+	0, 0, 0, 106, 107, 110, 109, 111, 112, 113,
+	114, 115, 116, 117,
 }
 var yyPact = [...]int{
 
-	953, -42, -49, 789, -1000, 767, -1000, -1000, -1000, 953,
-	-1000, 121, -1000, -11, 486, 485, -1000, 129, -1000, -1000,
-	-1000, -1000, 530, 484, 483, 471, 470, 469, -1000, 468,
-	605, 467, 467, 467, 467, 467, 467, 467, 467, 467,
-	467, 467, 467, 467, 467, 467, 467, 467, 367, 367,
-	367, 367, 367, 367, 367, 367, 367, 367, 367, 367,
-	367, 367, 367, 367, 367, 745, 108, 723, 177, 315,
-	152, 1098, 466, 466, 466, 466, 466, 466, -1000, -1000,
-	-1000, -1000, -1000, -1000, 968, 968, 968, 968, 968, 968,
-	968, 495, 1045, -1000, 607, 495, -60, 495, 495, -1000,
+	942, -40, -45, 797, -1000, 775, -1000, -1000, -1000, 942,
+	-1000, 119, -1000, -13, 499, 498, -1000, 127, -1000, -1000,
+	-1000, -1000, 532, 496, 495, 489, 488, 487, -1000, 475,
+	609, 474, 474, 474, 474, 474, 474, 474, 474, 474,
+	474, 474, 474, 474, 474, 474, 474, 474, 406, 406,
+	406, 406, 406, 406, 406, 406, 406, 406, 406, 406,
+	406, 406, 406, 406, 406, 753, 36, 731, 268, 328,
+	150, 1043, 473, 473, 473, 473, 473, 473, -1000, -1000,
+	-1000, -1000, -1000, -1000, 376, 376, 376, 376, 376, 376,
+	376, 497, 983, -1000, 700, 497, -55, 497, 497, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, 615, 612, 610, 283, 593, 592, 591, 346, 345,
-	196, 210, 201, -1000, -1000, -1000, 307, 495, 495, 495,
-	495, 563, -50, 767, -1000, -1000, -1000, -1000, 461, 439,
-	438, 435, 428, 427, 426, 425, 424, 961, 423, 836,
-	893, -1000, -1000, -1000, -1000, 836, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 818, 367, -1000,
-	-1000, -1000, -1000, 818, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, 618, 616, 614, 236, 597, 596, 595, 323, 949,
+	196, 210, 184, -1000, -1000, -1000, 317, 497, 497, 497,
+	497, 592, -56, 775, -1000, -1000, -1000, -1000, 472, 471,
+	470, 468, 463, 441, 440, 437, 430, 965, 429, 844,
+	903, -1000, -1000, -1000, -1000, 844, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 826, 406, -1000,
+	-1000, -1000, -1000, 826, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 282, -1000, -1000,
-	-1000, -1000, 255, -1000, 885, -19, -19, -91, -91, -91,
-	-91, 143, 968, -59, -59, -97, -97, -97, -97, 694,
-	418, -1000, -1000, -1000, -1000, -1000, 495, 495, 495, 495,
-	495, 495, 553, 495, 495, 495, 495, 495, 495, 495,
-	495, 495, 658, 437, 202, -92, -92, 52, 51, 49,
-	48, 590, 585, 47, 24, 4, -1000, -1000, -1000, -1000,
+	-1000, -1000, 141, -1000, 895, -21, -21, -93, -93, -93,
+	-93, 21, 376, -62, -62, -99, -99, -99, -99, 690,
+	401, -1000, -1000, -1000, -1000, -1000, 497, 497, 497, 497,
+	497, 497, 555, 497, 497, 497, 497, 497, 497, 497,
+	497, 497, 652, 439, 177, -94, -94, 59, 53, 50,
+	47, 594, 593, 46, 45, 37, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, 646, 617, 540, 113, 401, -1000,
-	-62, -8, 296, 288, 1045, 1045, 1045, 1045, 1045, 1045,
-	489, 723, 25, 280, 92, 893, -1000, 885, -54, -1000,
-	-1000, 1045, -92, -92, -99, -99, -99, -63, -63, -1000,
-	-63, -63, -63, -63, -63, -63, -99, -4, -4, -1000,
-	-63, -1000, -1000, -1000, -1000, -1000, -1000, -16, -25, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 563, 1120, -1000,
-	422, 421, 150, 125, 278, 273, 268, 264, 208, 243,
-	399, -1000, 282, -1000, -1000, -1000, -1000, -1000, 551, 550,
-	416, 409, 80, 79, 76, 66, 502, 62, -1000, 549,
-	230, 227, 1045, 1045, 404, 376, 369, 347, 383, -1000,
-	-1000, 343, 381, -1000, -1000, 379, 357, 1045, 1045, 1045,
-	1045, 499, 61, 1045, -1000, 533, -1000, -1000, 355, 339,
-	337, 335, -1000, -1000, 327, 323, 178, -1000, -1000, -1000,
-	-1000, 1045, -1000, 525, 320, 218, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, 642, 611, 544, 111,
+	385, -1000, -64, -10, 309, 298, 983, 983, 983, 983,
+	983, 983, 285, 731, 175, 290, 9, 903, -1000, 895,
+	-58, -1000, -1000, 983, -94, -94, -101, -101, -101, -66,
+	-66, -1000, -66, -66, -66, -66, -66, -66, -101, -6,
+	-6, -1000, -66, -1000, -1000, -1000, -1000, -1000, -1000, 2,
+	-18, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 592,
+	1087, -1000, 423, 422, 125, 122, 283, 280, 278, 264,
+	208, 243, 383, -1000, 282, -1000, -1000, -1000, -1000, -1000,
+	585, 563, 421, 418, 106, 104, 103, 76, 552, 75,
+	-1000, 562, 234, 230, 983, 983, 411, 405, 369, 362,
+	381, -1000, -1000, 353, 377, -1000, -1000, 366, 349, 983,
+	983, 983, 983, 535, 72, 983, -1000, 537, -1000, -1000,
+	347, 340, 338, 325, -1000, -1000, 343, 322, 203, -1000,
+	-1000, -1000, -1000, 983, -1000, 527, 320, 218, -1000, -1000,
 }
 var yyPgo = [...]int{
 
-	0, 654, 4, 653, 3, 19, 2, 999, 652, 27,
-	10, 1, 554, 651, 650, 649, 563, 21, 644, 634,
-	6, 24, 5, 26, 7, 0, 18, 633, 8, 632,
-	631,
+	0, 659, 4, 658, 3, 19, 2, 1043, 657, 27,
+	10, 1, 644, 650, 647, 646, 565, 21, 645, 636,
+	6, 24, 5, 26, 7, 0, 18, 625, 8, 623,
+	389,
 }
 var yyR1 = [...]int{
 
@@ -485,8 +486,8 @@ var yyR1 = [...]int{
 	22, 22, 22, 22, 23, 23, 23, 23, 23, 23,
 	23, 23, 23, 23, 23, 23, 23, 25, 25, 25,
 	25, 25, 25, 25, 25, 25, 25, 25, 25, 25,
-	25, 25, 25, 25, 24, 24, 24, 24, 24, 24,
-	24, 24, 24,
+	25, 25, 25, 25, 25, 25, 24, 24, 24, 24,
+	24, 24, 24, 24, 24,
 }
 var yyR2 = [...]int{
 
@@ -510,54 +511,54 @@ var yyR2 = [...]int{
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 2, 2, 2,
 	2, 2, 2, 2, 2, 2, 2, 2, 2, 2,
-	2, 2, 2, 2, 3, 3, 3, 3, 4, 4,
-	3, 3, 3,
+	2, 2, 2, 2, 2, 2, 3, 3, 3, 3,
+	4, 4, 3, 3, 3,
 }
 var yyChk = [...]int{
 
 	-1000, -1, -9, -7, -15, -6, -11, -2, -4, 12,
-	-8, -16, -10, -17, 63, 65, -18, 10, -20, 6,
-	7, 8, 103, 58, 60, 61, 59, 62, -30, 78,
-	79, 80, 86, 84, 90, 91, 81, 92, 93, 94,
-	95, 96, 88, 97, 98, 99, 100, 101, 80, 86,
-	84, 90, 91, 81, 92, 93, 94, 88, 96, 95,
-	97, 98, 101, 100, 99, -7, -9, -6, -16, -19,
-	-17, -12, 102, 103, 105, 106, 107, 108, 82, 83,
-	84, 85, 86, 87, -12, 102, 103, 105, 106, 107,
-	108, 12, 12, 11, -21, 12, 15, 103, 104, -22,
+	-8, -16, -10, -17, 65, 67, -18, 10, -20, 6,
+	7, 8, 105, 60, 62, 63, 61, 64, -30, 80,
+	81, 82, 88, 86, 92, 93, 83, 94, 95, 96,
+	97, 98, 90, 99, 100, 101, 102, 103, 82, 88,
+	86, 92, 93, 83, 94, 95, 96, 90, 98, 97,
+	99, 100, 103, 102, 101, -7, -9, -6, -16, -19,
+	-17, -12, 104, 105, 107, 108, 109, 110, 84, 85,
+	86, 87, 88, 89, -12, 104, 105, 107, 108, 109,
+	110, 12, 12, 11, -21, 12, 15, 105, 106, -22,
 	-23, -24, -25, 5, 6, 7, 16, 17, 8, 19,
 	18, 20, 21, 22, 23, 24, 25, 26, 27, 28,
 	29, 30, 31, 33, 32, 34, 35, 37, 38, 39,
-	40, 9, 48, 49, 47, 53, 55, 57, 50, 51,
-	52, 54, 56, 6, 7, 8, 12, 12, 12, 12,
-	12, 12, -13, -6, -11, -2, -3, -4, 67, 68,
-	69, 70, 72, 71, 73, 74, 75, 12, 64, -7,
+	40, 9, 50, 51, 49, 55, 57, 59, 52, 53,
+	54, 56, 58, 6, 7, 8, 12, 12, 12, 12,
+	12, 12, -13, -6, -11, -2, -3, -4, 69, 70,
+	71, 72, 74, 73, 75, 76, 77, 12, 66, -7,
 	12, -7, -7, -7, -7, -7, -7, -7, -7, -7,
 	-7, -7, -7, -7, -7, -7, -7, -6, 12, -6,
 	-6, -6, -6, -6, -6, -6, -6, -6, -6, -6,
-	-6, -6, -6, -6, -6, 13, 13, 79, 13, 13,
+	-6, -6, -6, -6, -6, 13, 13, 81, 13, 13,
 	13, 13, -16, -22, 12, -16, -16, -16, -16, -16,
 	-16, -17, 12, -17, -17, -17, -17, -17, -17, -21,
-	-5, -26, -23, -24, -25, 11, 102, 103, 105, 106,
-	107, 82, 83, 84, 85, 86, 87, 89, 88, 108,
-	80, 81, -21, 83, 82, -21, -21, 4, 4, 4,
-	4, 48, 49, 4, 4, 4, 27, 34, 36, 41,
-	27, 29, 33, 30, 31, 41, 44, 29, 45, 42,
-	43, 29, 46, 13, -21, -21, -21, -21, -29, -28,
-	4, 79, 12, 12, 12, 12, 12, 12, 12, 12,
-	12, -6, -17, 12, -9, 12, -20, 12, -9, 13,
-	13, 14, -21, -21, -21, -21, -21, -21, -21, 15,
-	-21, -21, -21, -21, -21, -21, -21, -21, -21, 13,
-	-21, 15, 15, 66, 66, 66, 66, 4, 4, 66,
-	66, 66, 13, 13, 13, 13, 13, 14, 82, -14,
-	76, 77, 13, 13, -26, -26, -26, -26, -26, -26,
-	-10, 13, 79, -26, 66, 66, -28, -22, 12, 12,
-	63, 63, 13, 13, 13, 13, 14, 13, 13, 14,
-	6, 6, 12, 12, 63, 63, 63, 63, -27, 7,
-	6, 63, 6, 13, 13, -5, -5, 12, 12, 12,
-	12, 14, 13, 12, 13, 14, 13, 13, -5, -5,
-	-5, -5, 7, 6, 63, -5, 6, 13, 13, 13,
-	13, 12, 13, 14, -5, 6, 13, 13,
+	-5, -26, -23, -24, -25, 11, 104, 105, 107, 108,
+	109, 84, 85, 86, 87, 88, 89, 91, 90, 110,
+	82, 83, -21, 85, 84, -21, -21, 4, 4, 4,
+	4, 50, 51, 4, 4, 4, 27, 34, 36, 41,
+	47, 48, 27, 29, 33, 30, 31, 41, 44, 29,
+	45, 42, 43, 29, 46, 13, -21, -21, -21, -21,
+	-29, -28, 4, 81, 12, 12, 12, 12, 12, 12,
+	12, 12, 12, -6, -17, 12, -9, 12, -20, 12,
+	-9, 13, 13, 14, -21, -21, -21, -21, -21, -21,
+	-21, 15, -21, -21, -21, -21, -21, -21, -21, -21,
+	-21, 13, -21, 15, 15, 68, 68, 68, 68, 4,
+	4, 68, 68, 68, 13, 13, 13, 13, 13, 14,
+	84, -14, 78, 79, 13, 13, -26, -26, -26, -26,
+	-26, -26, -10, 13, 81, -26, 68, 68, -28, -22,
+	12, 12, 65, 65, 13, 13, 13, 13, 14, 13,
+	13, 14, 6, 6, 12, 12, 65, 65, 65, 65,
+	-27, 7, 6, 65, 6, 13, 13, -5, -5, 12,
+	12, 12, 12, 14, 13, 12, 13, 14, 13, 13,
+	-5, -5, -5, -5, 7, 6, 65, -5, 6, 13,
+	13, 13, 13, 12, 13, 14, -5, 6, 13, 13,
 }
 var yyDef = [...]int{
 
@@ -589,21 +590,21 @@ var yyDef = [...]int{
 	0, 0, 0, 0, 0, 153, 154, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 187, 188, 189, 190,
 	191, 192, 193, 194, 195, 196, 197, 198, 199, 200,
-	201, 202, 203, 102, 0, 0, 0, 0, 0, 130,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, -2, 0, 0, 36,
-	38, 0, 133, 134, 135, 136, 137, 138, 139, 149,
-	140, 141, 142, 143, 144, 145, 146, 147, 148, 132,
-	150, 151, 152, 204, 205, 206, 207, 0, 0, 210,
-	211, 212, 103, 104, 105, 106, 129, 0, 0, 5,
-	0, 0, 107, 109, 0, 0, 0, 0, 0, 0,
-	0, 37, 0, 43, 208, 209, 131, 128, 0, 0,
-	0, 0, 111, 113, 115, 117, 0, 121, 123, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 44,
-	45, 0, 0, 126, 127, 0, 0, 0, 0, 0,
-	0, 0, 119, 0, 124, 0, 108, 110, 0, 0,
-	0, 0, 46, 47, 0, 0, 0, 112, 114, 116,
-	118, 0, 122, 0, 0, 0, 120, 125,
+	201, 202, 203, 204, 205, 102, 0, 0, 0, 0,
+	0, 130, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, -2, 0,
+	0, 36, 38, 0, 133, 134, 135, 136, 137, 138,
+	139, 149, 140, 141, 142, 143, 144, 145, 146, 147,
+	148, 132, 150, 151, 152, 206, 207, 208, 209, 0,
+	0, 212, 213, 214, 103, 104, 105, 106, 129, 0,
+	0, 5, 0, 0, 107, 109, 0, 0, 0, 0,
+	0, 0, 0, 37, 0, 43, 210, 211, 131, 128,
+	0, 0, 0, 0, 111, 113, 115, 117, 0, 121,
+	123, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 44, 45, 0, 0, 126, 127, 0, 0, 0,
+	0, 0, 0, 0, 119, 0, 124, 0, 108, 110,
+	0, 0, 0, 0, 46, 47, 0, 0, 0, 112,
+	114, 116, 118, 0, 122, 0, 0, 0, 120, 125,
 }
 var yyTok1 = [...]int{
 
@@ -621,7 +622,7 @@ var yyTok2 = [...]int{
 	72, 73, 74, 75, 76, 77, 78, 79, 80, 81,
 	82, 83, 84, 85, 86, 87, 88, 89, 90, 91,
 	92, 93, 94, 95, 96, 97, 98, 99, 100, 101,
-	102, 103, 104, 105, 106, 107, 108,
+	102, 103, 104, 105, 106, 107, 108, 109, 110,
 }
 var yyTok3 = [...]int{
 	0,
@@ -2106,133 +2107,145 @@ yydefault:
 		}
 	case 191:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:432
+//line pkg/traceql/expr.y:431
 		{
-			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicDuration)
+			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicTraceState)
 		}
 	case 192:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:433
+//line pkg/traceql/expr.y:432
 		{
-			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicName)
+			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicTraceSampled)
 		}
 	case 193:
 		yyDollar = yyS[yypt-2 : yypt+1]
 //line pkg/traceql/expr.y:434
 		{
-			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicKind)
+			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicDuration)
 		}
 	case 194:
 		yyDollar = yyS[yypt-2 : yypt+1]
 //line pkg/traceql/expr.y:435
 		{
-			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicStatus)
+			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicName)
 		}
 	case 195:
 		yyDollar = yyS[yypt-2 : yypt+1]
 //line pkg/traceql/expr.y:436
 		{
-			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicStatusMessage)
+			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicKind)
 		}
 	case 196:
 		yyDollar = yyS[yypt-2 : yypt+1]
 //line pkg/traceql/expr.y:437
 		{
-			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicSpanID)
+			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicStatus)
 		}
 	case 197:
 		yyDollar = yyS[yypt-2 : yypt+1]
 //line pkg/traceql/expr.y:438
 		{
-			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicParentID)
+			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicStatusMessage)
 		}
 	case 198:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:440
+//line pkg/traceql/expr.y:439
 		{
-			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicEventName)
+			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicSpanID)
 		}
 	case 199:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:441
+//line pkg/traceql/expr.y:440
 		{
-			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicEventTimeSinceStart)
+			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicParentID)
 		}
 	case 200:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:443
+//line pkg/traceql/expr.y:442
 		{
-			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicLinkTraceID)
+			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicEventName)
 		}
 	case 201:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:444
+//line pkg/traceql/expr.y:443
 		{
-			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicLinkSpanID)
+			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicEventTimeSinceStart)
 		}
 	case 202:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:446
+//line pkg/traceql/expr.y:445
 		{
-			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicInstrumentationName)
+			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicLinkTraceID)
 		}
 	case 203:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:447
+//line pkg/traceql/expr.y:446
 		{
-			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicInstrumentationVersion)
+			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicLinkSpanID)
 		}
 	case 204:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:451
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line pkg/traceql/expr.y:448
 		{
-			yyVAL.attributeField = NewAttribute(yyDollar[2].staticStr)
+			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicInstrumentationName)
 		}
 	case 205:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:452
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line pkg/traceql/expr.y:449
 		{
-			yyVAL.attributeField = NewScopedAttribute(AttributeScopeResource, false, yyDollar[2].staticStr)
+			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicInstrumentationVersion)
 		}
 	case 206:
 		yyDollar = yyS[yypt-3 : yypt+1]
 //line pkg/traceql/expr.y:453
 		{
-			yyVAL.attributeField = NewScopedAttribute(AttributeScopeSpan, false, yyDollar[2].staticStr)
+			yyVAL.attributeField = NewAttribute(yyDollar[2].staticStr)
 		}
 	case 207:
 		yyDollar = yyS[yypt-3 : yypt+1]
 //line pkg/traceql/expr.y:454
 		{
-			yyVAL.attributeField = NewScopedAttribute(AttributeScopeNone, true, yyDollar[2].staticStr)
+			yyVAL.attributeField = NewScopedAttribute(AttributeScopeResource, false, yyDollar[2].staticStr)
 		}
 	case 208:
-		yyDollar = yyS[yypt-4 : yypt+1]
+		yyDollar = yyS[yypt-3 : yypt+1]
 //line pkg/traceql/expr.y:455
 		{
-			yyVAL.attributeField = NewScopedAttribute(AttributeScopeResource, true, yyDollar[3].staticStr)
+			yyVAL.attributeField = NewScopedAttribute(AttributeScopeSpan, false, yyDollar[2].staticStr)
 		}
 	case 209:
-		yyDollar = yyS[yypt-4 : yypt+1]
+		yyDollar = yyS[yypt-3 : yypt+1]
 //line pkg/traceql/expr.y:456
 		{
-			yyVAL.attributeField = NewScopedAttribute(AttributeScopeSpan, true, yyDollar[3].staticStr)
+			yyVAL.attributeField = NewScopedAttribute(AttributeScopeNone, true, yyDollar[2].staticStr)
 		}
 	case 210:
-		yyDollar = yyS[yypt-3 : yypt+1]
+		yyDollar = yyS[yypt-4 : yypt+1]
 //line pkg/traceql/expr.y:457
 		{
-			yyVAL.attributeField = NewScopedAttribute(AttributeScopeEvent, false, yyDollar[2].staticStr)
+			yyVAL.attributeField = NewScopedAttribute(AttributeScopeResource, true, yyDollar[3].staticStr)
 		}
 	case 211:
-		yyDollar = yyS[yypt-3 : yypt+1]
+		yyDollar = yyS[yypt-4 : yypt+1]
 //line pkg/traceql/expr.y:458
 		{
-			yyVAL.attributeField = NewScopedAttribute(AttributeScopeLink, false, yyDollar[2].staticStr)
+			yyVAL.attributeField = NewScopedAttribute(AttributeScopeSpan, true, yyDollar[3].staticStr)
 		}
 	case 212:
 		yyDollar = yyS[yypt-3 : yypt+1]
 //line pkg/traceql/expr.y:459
+		{
+			yyVAL.attributeField = NewScopedAttribute(AttributeScopeEvent, false, yyDollar[2].staticStr)
+		}
+	case 213:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line pkg/traceql/expr.y:460
+		{
+			yyVAL.attributeField = NewScopedAttribute(AttributeScopeLink, false, yyDollar[2].staticStr)
+		}
+	case 214:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line pkg/traceql/expr.y:461
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeInstrumentation, false, yyDollar[2].staticStr)
 		}
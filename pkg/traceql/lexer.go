@@ -81,6 +81,8 @@ var tokens = map[string]int{
 	"parentID":            PARENT_ID,
 	"timeSinceStart":      TIMESINCESTART,
 	"version":             VERSION,
+	"state":               STATE,
+	"sampled":             SAMPLED,
 	"parent":              PARENT,
 	"parent.":             PARENT_DOT,
 	"resource.":           RESOURCE_DOT,
@@ -97,6 +97,8 @@ func TestLexerScopedIntrinsic(t *testing.T) {
 		{`trace:rootName`, []int{TRACE_COLON, ROOTNAME}},
 		{`trace:rootService`, []int{TRACE_COLON, ROOTSERVICE}},
 		{`trace:id`, []int{TRACE_COLON, ID}},
+		{`trace:state`, []int{TRACE_COLON, STATE}},
+		{`trace:sampled`, []int{TRACE_COLON, SAMPLED}},
 		// span scoped intrinsics
 		{`span:duration`, []int{SPAN_COLON, IDURATION}},
 		{`span:name`, []int{SPAN_COLON, NAME}},
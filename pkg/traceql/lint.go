@@ -0,0 +1,139 @@
+package traceql
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	DiagnosticSeverityError   = "error"
+	DiagnosticSeverityWarning = "warning"
+)
+
+// Diagnostic is a problem found in a query. Line and Column are the position of parse errors and are 0 if the
+// position is unknown.
+type Diagnostic struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+}
+
+// LintResult is the result of linting a query.
+type LintResult struct {
+	// Valid is true if the query parses and, unless only parsed, validates. Warnings don't make a query invalid.
+	Valid bool `json:"valid"`
+	// Query is the normalized query, empty if it doesn't parse.
+	Query       string       `json:"query,omitempty"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// Lint parses the query and, unless parseOnly is true, validates it and warns about patterns that are expensive to
+// execute. The query is not executed.
+func Lint(query string, parseOnly bool) *LintResult {
+	res := &LintResult{
+		Diagnostics: []Diagnostic{},
+	}
+
+	expr, err := Parse(query)
+	if err != nil {
+		res.Diagnostics = append(res.Diagnostics, errorDiagnostic(err))
+		return res
+	}
+	res.Query = expr.String()
+
+	if parseOnly {
+		res.Valid = true
+		return res
+	}
+
+	req := &FetchSpansRequest{
+		AllConditions: true,
+	}
+	expr.extractConditions(req)
+
+	if err := expr.validate(); err != nil {
+		res.Diagnostics = append(res.Diagnostics, errorDiagnostic(err))
+		return res
+	}
+
+	res.Valid = true
+	res.Diagnostics = append(res.Diagnostics, lintWarnings(expr, req)...)
+	return res
+}
+
+func errorDiagnostic(err error) Diagnostic {
+	d := Diagnostic{
+		Severity: DiagnosticSeverityError,
+		Message:  err.Error(),
+	}
+
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		d.Message = parseErr.msg
+		d.Line = parseErr.line
+		d.Column = parseErr.col
+	}
+
+	return d
+}
+
+// lintWarnings returns warnings for the conditions of the query that make it expensive to execute.
+func lintWarnings(expr *RootExpr, req *FetchSpansRequest) []Diagnostic {
+	var warnings []Diagnostic
+	warn := func(format string, args ...any) {
+		warnings = append(warnings, Diagnostic{
+			Severity: DiagnosticSeverityWarning,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	if expr.IsNoop() {
+		warn("query never matches any span")
+		return warnings
+	}
+
+	// conditions without an operator only fetch the attribute
+	filtered := false
+	for _, c := range req.Conditions {
+		if c.Op != OpNone {
+			filtered = true
+			break
+		}
+	}
+	if !filtered {
+		warn("query matches all spans and reads every span of the searched blocks")
+		return warnings
+	}
+
+	seen := map[string]struct{}{}
+	once := func(key string) bool {
+		if _, ok := seen[key]; ok {
+			return false
+		}
+		seen[key] = struct{}{}
+		return true
+	}
+
+	for _, c := range req.Conditions {
+		a := c.Attribute
+
+		switch a.Intrinsic {
+		case IntrinsicStructuralDescendant, IntrinsicStructuralChild, IntrinsicStructuralSibling:
+			if once("structural") {
+				warn("structural operators read the span hierarchy of every matching trace")
+			}
+			continue
+		case IntrinsicNone:
+			if a.Scope == AttributeScopeNone && once("unscoped "+a.Name) {
+				warn("unscoped attribute %s is looked up in span and resource attributes, add a span. or resource. scope", a.String())
+			}
+		}
+
+		if (c.Op == OpRegex || c.Op == OpNotRegex) && once("regex "+a.String()) {
+			warn("regular expression on %s is evaluated for every value of the attribute", a.String())
+		}
+	}
+
+	return warnings
+}
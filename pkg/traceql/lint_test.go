@@ -0,0 +1,97 @@
+package traceql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		parseOnly bool
+		expected  *LintResult
+	}{
+		{
+			name:  "valid",
+			query: `{ span.foo = "bar" }`,
+			expected: &LintResult{
+				Valid:       true,
+				Query:       `{ span.foo = ` + "`bar`" + ` }`,
+				Diagnostics: []Diagnostic{},
+			},
+		},
+		{
+			name:  "parse error with position",
+			query: "{ .a } | { .b",
+			expected: &LintResult{
+				Diagnostics: []Diagnostic{
+					{Severity: DiagnosticSeverityError, Message: "syntax error: unexpected $end", Line: 1, Column: 14},
+				},
+			},
+		},
+		{
+			name:  "validation error",
+			query: `{ .foo + 1 }`,
+			expected: &LintResult{
+				Query: "{ .foo + 1 }",
+				Diagnostics: []Diagnostic{
+					{Severity: DiagnosticSeverityError, Message: "span filter field expressions must resolve to a boolean: { .foo + 1 }"},
+				},
+			},
+		},
+		{
+			name:      "validation is skipped when only parsing",
+			query:     `{ .foo + 1 }`,
+			parseOnly: true,
+			expected: &LintResult{
+				Valid:       true,
+				Query:       "{ .foo + 1 }",
+				Diagnostics: []Diagnostic{},
+			},
+		},
+		{
+			name:  "all spans",
+			query: `{}`,
+			expected: &LintResult{
+				Valid: true,
+				Query: "{ true }",
+				Diagnostics: []Diagnostic{
+					{Severity: DiagnosticSeverityWarning, Message: "query matches all spans and reads every span of the searched blocks"},
+				},
+			},
+		},
+		{
+			name:  "never matches",
+			query: `{ false }`,
+			expected: &LintResult{
+				Valid: true,
+				Query: "{ false }",
+				Diagnostics: []Diagnostic{
+					{Severity: DiagnosticSeverityWarning, Message: "query never matches any span"},
+				},
+			},
+		},
+		{
+			name:  "expensive patterns",
+			query: `{ .foo =~ "a.*" && .foo =~ "b.*" } >> { span.bar !~ "c" }`,
+			expected: &LintResult{
+				Valid: true,
+				Query: "({ (.foo =~ `a.*`) && (.foo =~ `b.*`) }) >> ({ span.bar !~ `c` })",
+				Diagnostics: []Diagnostic{
+					{Severity: DiagnosticSeverityWarning, Message: "structural operators read the span hierarchy of every matching trace"},
+					{Severity: DiagnosticSeverityWarning, Message: "unscoped attribute .foo is looked up in span and resource attributes, add a span. or resource. scope"},
+					{Severity: DiagnosticSeverityWarning, Message: "regular expression on .foo is evaluated for every value of the attribute"},
+					{Severity: DiagnosticSeverityWarning, Message: "regular expression on span.bar is evaluated for every value of the attribute"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, Lint(tc.query, tc.parseOnly))
+		})
+	}
+}
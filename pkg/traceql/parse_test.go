@@ -1214,6 +1214,8 @@ func TestScopedIntrinsics(t *testing.T) {
 		{in: "trace:rootName", expected: IntrinsicTraceRootSpan},
 		{in: "trace:rootService", expected: IntrinsicTraceRootService},
 		{in: "trace:id", expected: IntrinsicTraceID},
+		{in: "trace:state", expected: IntrinsicTraceState},
+		{in: "trace:sampled", expected: IntrinsicTraceSampled},
 		{in: "span:duration", expected: IntrinsicDuration},
 		{in: "span:kind", expected: IntrinsicKind},
 		{in: "span:name", expected: IntrinsicName},
@@ -1232,6 +1234,7 @@ func TestScopedIntrinsics(t *testing.T) {
 		{in: "trace:name", shouldError: true},
 		{in: "trace:rootServiceName", shouldError: true},
 		{in: "span:rootServiceName", shouldError: true},
+		{in: "span:state", shouldError: true},
 		{in: "parent:id", shouldError: true},
 	}
 
@@ -0,0 +1,264 @@
+package traceql
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+var errSpanFilterUnsupported = errors.New("only queries of a single spanset filter are supported, e.g. { span.foo = \"bar\" }")
+
+// SpanFilter matches the spans of traces in memory against a query of a single spanset filter. It's compiled once to
+// match the spans of many traces, like the spans received by the ingesters. Trace level intrinsics and the events,
+// links and parents of spans aren't known, they never match.
+type SpanFilter struct {
+	pipeline Pipeline
+	// attributes are returned with the matching spans, like search returns the attributes of the conditions
+	attributes []Attribute
+}
+
+// NewSpanFilter compiles the query of a span filter. Structural queries, pipelines and metrics aren't supported.
+func NewSpanFilter(query string) (*SpanFilter, error) {
+	expr, _, _, _, req, err := Compile(query)
+	if err != nil {
+		return nil, err
+	}
+	if expr.MetricsPipeline != nil || len(expr.Pipeline.Elements) != 1 {
+		return nil, errSpanFilterUnsupported
+	}
+	if _, ok := expr.Pipeline.Elements[0].(*SpansetFilter); !ok {
+		return nil, errSpanFilterUnsupported
+	}
+
+	attributes := []Attribute{NewIntrinsic(IntrinsicName)}
+	for _, c := range req.Conditions {
+		if !slices.Contains(attributes, c.Attribute) {
+			attributes = append(attributes, c.Attribute)
+		}
+	}
+	return &SpanFilter{pipeline: expr.Pipeline, attributes: attributes}, nil
+}
+
+// Match returns the spans of the trace matching the filter as a search result, with up to spansPerSpanSet spans. It
+// returns nil if no span matches.
+func (f *SpanFilter) Match(traceID []byte, tr *tempopb.Trace, spansPerSpanSet int) (*tempopb.TraceSearchMetadata, error) {
+	ss := &Spanset{
+		TraceID:      traceID,
+		ServiceStats: map[string]ServiceStats{},
+	}
+
+	var end uint64
+	for _, rs := range tr.ResourceSpans {
+		serviceName := ""
+		if rs.Resource != nil {
+			serviceName = stringAttribute(rs.Resource.Attributes, "service.name")
+		}
+		for _, scope := range rs.ScopeSpans {
+			for _, s := range scope.Spans {
+				ss.Spans = append(ss.Spans, &protoSpan{rs: rs, scope: scope, span: s, attributes: f.attributes})
+
+				if len(s.ParentSpanId) == 0 {
+					ss.RootServiceName, ss.RootSpanName = serviceName, s.Name
+				}
+				if ss.StartTimeUnixNanos == 0 || s.StartTimeUnixNano < ss.StartTimeUnixNanos {
+					ss.StartTimeUnixNanos = s.StartTimeUnixNano
+				}
+				end = max(end, s.EndTimeUnixNano)
+
+				stats := ss.ServiceStats[serviceName]
+				stats.SpanCount++
+				if s.Status != nil && s.Status.Code == v1.Status_STATUS_CODE_ERROR {
+					stats.ErrorCount++
+				}
+				ss.ServiceStats[serviceName] = stats
+			}
+		}
+	}
+	if len(ss.Spans) == 0 {
+		return nil, nil
+	}
+	if end > ss.StartTimeUnixNanos {
+		ss.DurationNanos = end - ss.StartTimeUnixNanos
+	}
+
+	matches, err := f.pipeline.evaluate([]*Spanset{ss})
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 || len(matches[0].Spans) == 0 {
+		return nil, nil
+	}
+
+	match := matches[0]
+	match.AddAttribute(attributeMatched, NewStaticInt(len(match.Spans)))
+	if spansPerSpanSet == 0 {
+		spansPerSpanSet = DefaultSpansPerSpanSet
+	}
+	if len(match.Spans) > spansPerSpanSet {
+		match.Spans = match.Spans[:spansPerSpanSet]
+	}
+	return asTraceSearchMetadata(match), nil
+}
+
+// protoSpan is a span of a trace in memory.
+type protoSpan struct {
+	rs         *v1.ResourceSpans
+	scope      *v1.ScopeSpans
+	span       *v1.Span
+	attributes []Attribute
+}
+
+var _ Span = (*protoSpan)(nil)
+
+func (s *protoSpan) AttributeFor(a Attribute) (Static, bool) {
+	if a.Intrinsic != IntrinsicNone {
+		return s.intrinsic(a.Intrinsic)
+	}
+
+	switch a.Scope {
+	case AttributeScopeSpan:
+		return findAttribute(s.span.Attributes, a.Name)
+	case AttributeScopeResource:
+		if s.rs.Resource == nil {
+			return StaticNil, false
+		}
+		return findAttribute(s.rs.Resource.Attributes, a.Name)
+	case AttributeScopeInstrumentation:
+		if s.scope.Scope == nil {
+			return StaticNil, false
+		}
+		return findAttribute(s.scope.Scope.Attributes, a.Name)
+	case AttributeScopeNone:
+		// unscoped attributes are the attributes of the span before those of the resource
+		if v, ok := findAttribute(s.span.Attributes, a.Name); ok {
+			return v, true
+		}
+		if s.rs.Resource == nil {
+			return StaticNil, false
+		}
+		return findAttribute(s.rs.Resource.Attributes, a.Name)
+	}
+	return StaticNil, false
+}
+
+func (s *protoSpan) intrinsic(i Intrinsic) (Static, bool) {
+	switch i {
+	case IntrinsicName:
+		return NewStaticString(s.span.Name), true
+	case IntrinsicDuration:
+		return NewStaticDuration(time.Duration(s.DurationNanos())), true
+	case IntrinsicStatus:
+		code := v1.Status_STATUS_CODE_UNSET
+		if s.span.Status != nil {
+			code = s.span.Status.Code
+		}
+		switch code {
+		case v1.Status_STATUS_CODE_OK:
+			return NewStaticStatus(StatusOk), true
+		case v1.Status_STATUS_CODE_ERROR:
+			return NewStaticStatus(StatusError), true
+		}
+		return NewStaticStatus(StatusUnset), true
+	case IntrinsicStatusMessage:
+		if s.span.Status == nil {
+			return NewStaticString(""), true
+		}
+		return NewStaticString(s.span.Status.Message), true
+	case IntrinsicKind:
+		switch s.span.Kind {
+		case v1.Span_SPAN_KIND_INTERNAL:
+			return NewStaticKind(KindInternal), true
+		case v1.Span_SPAN_KIND_SERVER:
+			return NewStaticKind(KindServer), true
+		case v1.Span_SPAN_KIND_CLIENT:
+			return NewStaticKind(KindClient), true
+		case v1.Span_SPAN_KIND_PRODUCER:
+			return NewStaticKind(KindProducer), true
+		case v1.Span_SPAN_KIND_CONSUMER:
+			return NewStaticKind(KindConsumer), true
+		}
+		return NewStaticKind(KindUnspecified), true
+	case IntrinsicSpanID:
+		return NewStaticString(util.SpanIDToHexString(s.span.SpanId)), true
+	case IntrinsicTraceID:
+		return NewStaticString(util.TraceIDToHexString(s.span.TraceId)), true
+	case IntrinsicInstrumentationName:
+		if s.scope.Scope == nil {
+			return StaticNil, false
+		}
+		return NewStaticString(s.scope.Scope.Name), true
+	case IntrinsicInstrumentationVersion:
+		if s.scope.Scope == nil {
+			return StaticNil, false
+		}
+		return NewStaticString(s.scope.Scope.Version), true
+	}
+	return StaticNil, false
+}
+
+// AllAttributes returns the attributes of the filter only.
+func (s *protoSpan) AllAttributes() map[Attribute]Static {
+	atts := make(map[Attribute]Static, len(s.attributes))
+	s.AllAttributesFunc(func(a Attribute, v Static) {
+		atts[a] = v
+	})
+	return atts
+}
+
+func (s *protoSpan) AllAttributesFunc(cb func(Attribute, Static)) {
+	for _, a := range s.attributes {
+		if v, ok := s.AttributeFor(a); ok {
+			cb(a, v)
+		}
+	}
+}
+
+func (s *protoSpan) ID() []byte {
+	return s.span.SpanId
+}
+
+func (s *protoSpan) StartTimeUnixNanos() uint64 {
+	return s.span.StartTimeUnixNano
+}
+
+func (s *protoSpan) DurationNanos() uint64 {
+	if s.span.EndTimeUnixNano < s.span.StartTimeUnixNano {
+		return 0
+	}
+	return s.span.EndTimeUnixNano - s.span.StartTimeUnixNano
+}
+
+func (s *protoSpan) SiblingOf([]Span, []Span, bool, bool, []Span) []Span {
+	return nil
+}
+
+func (s *protoSpan) DescendantOf([]Span, []Span, bool, bool, bool, []Span) []Span {
+	return nil
+}
+
+func (s *protoSpan) ChildOf([]Span, []Span, bool, bool, bool, []Span) []Span {
+	return nil
+}
+
+func findAttribute(atts []*common_v1.KeyValue, name string) (Static, bool) {
+	for _, kv := range atts {
+		if kv.Key == name && kv.Value != nil {
+			return StaticFromAnyValue(kv.Value), true
+		}
+	}
+	return StaticNil, false
+}
+
+func stringAttribute(atts []*common_v1.KeyValue, name string) string {
+	for _, kv := range atts {
+		if kv.Key == name {
+			return kv.Value.GetStringValue()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package traceql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	resource_v1 "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+func TestNewSpanFilterUnsupported(t *testing.T) {
+	for _, q := range []string{
+		`{ } | count() > 1`,
+		`{ span.foo = "bar" } >> { }`,
+		`{ } | rate()`,
+		`{ } | select(span.foo)`,
+	} {
+		t.Run(q, func(t *testing.T) {
+			_, err := NewSpanFilter(q)
+			require.ErrorIs(t, err, errSpanFilterUnsupported)
+		})
+	}
+
+	_, err := NewSpanFilter(`{ span.foo = }`)
+	require.Error(t, err)
+}
+
+func TestSpanFilterMatch(t *testing.T) {
+	traceID := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
+	str := func(k, v string) *common_v1.KeyValue {
+		return &common_v1.KeyValue{Key: k, Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: v}}}
+	}
+	tr := &tempopb.Trace{ResourceSpans: []*v1.ResourceSpans{{
+		Resource: &resource_v1.Resource{Attributes: []*common_v1.KeyValue{str("service.name", "svc")}},
+		ScopeSpans: []*v1.ScopeSpans{{
+			Spans: []*v1.Span{
+				{
+					TraceId:           traceID,
+					SpanId:            []byte{0, 0, 0, 0, 0, 0, 0, 1},
+					Name:              "root",
+					Kind:              v1.Span_SPAN_KIND_SERVER,
+					StartTimeUnixNano: 1000,
+					EndTimeUnixNano:   5000,
+				},
+				{
+					TraceId:           traceID,
+					SpanId:            []byte{0, 0, 0, 0, 0, 0, 0, 2},
+					ParentSpanId:      []byte{0, 0, 0, 0, 0, 0, 0, 1},
+					Name:              "child",
+					Kind:              v1.Span_SPAN_KIND_CLIENT,
+					StartTimeUnixNano: 2000,
+					EndTimeUnixNano:   3000,
+					Status:            &v1.Status{Code: v1.Status_STATUS_CODE_ERROR},
+					Attributes:        []*common_v1.KeyValue{str("foo", "bar")},
+				},
+			},
+		}},
+	}}}
+
+	tcs := []struct {
+		query   string
+		matched []string
+	}{
+		{query: `{ }`, matched: []string{"0000000000000001", "0000000000000002"}},
+		{query: `{ span.foo = "bar" }`, matched: []string{"0000000000000002"}},
+		{query: `{ .foo = "bar" && resource.service.name = "svc" }`, matched: []string{"0000000000000002"}},
+		{query: `{ status = error || duration > 3us }`, matched: []string{"0000000000000001", "0000000000000002"}},
+		{query: `{ kind = server && name = "root" }`, matched: []string{"0000000000000001"}},
+		{query: `{ span.foo = "baz" }`},
+		{query: `{ resource.foo = "bar" }`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			f, err := NewSpanFilter(tc.query)
+			require.NoError(t, err)
+
+			md, err := f.Match(traceID, tr, 0)
+			require.NoError(t, err)
+			if len(tc.matched) == 0 {
+				require.Nil(t, md)
+				return
+			}
+
+			require.Equal(t, util.TraceIDToHexString(traceID), md.TraceID)
+			require.Equal(t, "svc", md.RootServiceName)
+			require.Equal(t, "root", md.RootTraceName)
+			require.Equal(t, uint32(0), md.DurationMs)
+			require.Equal(t, uint32(2), md.ServiceStats["svc"].SpanCount)
+			require.Equal(t, uint32(1), md.ServiceStats["svc"].ErrorCount)
+
+			require.Len(t, md.SpanSets, 1)
+			require.Equal(t, uint32(len(tc.matched)), md.SpanSets[0].Matched)
+			var matched []string
+			for _, s := range md.SpanSets[0].Spans {
+				matched = append(matched, s.SpanID)
+			}
+			require.Equal(t, tc.matched, matched)
+		})
+	}
+}
+
+func TestSpanFilterMatchSpansPerSpanSet(t *testing.T) {
+	traceID := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
+	ss := &v1.ScopeSpans{}
+	for i := range 5 {
+		ss.Spans = append(ss.Spans, &v1.Span{TraceId: traceID, SpanId: []byte{0, 0, 0, 0, 0, 0, 0, byte(i)}, Name: "span"})
+	}
+	tr := &tempopb.Trace{ResourceSpans: []*v1.ResourceSpans{{ScopeSpans: []*v1.ScopeSpans{ss}}}}
+
+	f, err := NewSpanFilter(`{ name = "span" }`)
+	require.NoError(t, err)
+
+	md, err := f.Match(traceID, tr, 2)
+	require.NoError(t, err)
+	require.Len(t, md.SpanSets[0].Spans, 2)
+	require.Equal(t, uint32(5), md.SpanSets[0].Matched)
+
+	md, err = f.Match(traceID, &tempopb.Trace{}, 2)
+	require.NoError(t, err)
+	require.Nil(t, md)
+}
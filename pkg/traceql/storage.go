@@ -34,6 +34,8 @@ func ExemplarMetaConditions(cb func() bool) []Condition {
 	// TODO: Build predicate that quits early if we have enough exemplars.
 	return []Condition{
 		{Attribute: NewIntrinsic(IntrinsicTraceID), Op: OpNone, CallBack: cb},
+		// the duration locates the span of the exemplar with its start time, which metrics always fetch
+		{Attribute: NewIntrinsic(IntrinsicDuration), Op: OpNone},
 		//{NewIntrinsic(IntrinsicSpanID), OpNone, nil},
 		//{NewIntrinsic(IntrinsicTraceDuration), OpNone, nil},
 		//{NewIntrinsic(IntrinsicTraceStartTime), OpNone, nil},
@@ -1,9 +1,33 @@
 package util
 
 import (
+	"encoding/binary"
 	"hash/fnv"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// Trace ID hash schemes used to derive the ring token of a trace. Changing the scheme of a tenant moves its traces
+// to other ingesters, so the previous scheme must also be read until the ingesters have flushed the old traces.
+const (
+	// TraceIDHashSchemeFNV32 is the default scheme. Trace IDs that are not random, e.g. sequential IDs, can be
+	// distributed unevenly.
+	TraceIDHashSchemeFNV32 = "fnv32"
+	// TraceIDHashSchemeXXHash64 folds a 64 bit xxhash into 32 bits.
+	TraceIDHashSchemeXXHash64 = "xxhash64"
+	// TraceIDHashSchemeFNV128Fold folds a 128 bit fnv-1a hash into 32 bits.
+	TraceIDHashSchemeFNV128Fold = "fnv-128-fold"
 )
 
+// ValidTraceIDHashScheme returns true if the scheme is known. An empty scheme is the default.
+func ValidTraceIDHashScheme(scheme string) bool {
+	switch scheme {
+	case "", TraceIDHashSchemeFNV32, TraceIDHashSchemeXXHash64, TraceIDHashSchemeFNV128Fold:
+		return true
+	}
+	return false
+}
+
 // TokenFor generates a token used for finding ingesters from ring.
 // Not suitable for in-memory hashing or deduping because it is only 32-bit.
 // The collision rate is about 1 in 8000.
@@ -14,6 +38,27 @@ func TokenFor(userID string, b []byte) uint32 {
 	return h.Sum32()
 }
 
+// TokenForScheme is TokenFor using the given trace ID hash scheme. Empty and unknown schemes use
+// TraceIDHashSchemeFNV32 which is the same as TokenFor.
+func TokenForScheme(scheme, userID string, b []byte) uint32 {
+	switch scheme {
+	case TraceIDHashSchemeXXHash64:
+		h := xxhash.New()
+		_, _ = h.WriteString(userID)
+		_, _ = h.Write(b)
+		sum := h.Sum64()
+		return uint32(sum>>32) ^ uint32(sum)
+	case TraceIDHashSchemeFNV128Fold:
+		h := fnv.New128a()
+		_, _ = h.Write([]byte(userID))
+		_, _ = h.Write(b)
+		sum := h.Sum(nil)
+		return binary.BigEndian.Uint32(sum[0:4]) ^ binary.BigEndian.Uint32(sum[4:8]) ^ binary.BigEndian.Uint32(sum[8:12]) ^ binary.BigEndian.Uint32(sum[12:16])
+	default:
+		return TokenFor(userID, b)
+	}
+}
+
 // TokenForTraceID generates a hashed value for a trace id.  Used for bloom lookups.
 // Do not change because it will break lookups on existing bloom filters.
 func TokenForTraceID(b []byte) uint32 {
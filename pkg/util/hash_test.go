@@ -3,6 +3,7 @@ package util
 import (
 	"bytes"
 	"crypto/rand"
+	"encoding/binary"
 	"sort"
 	"testing"
 
@@ -60,3 +61,44 @@ func TestHashForCollisionRate(t *testing.T) {
 	missing := n - len(tokens)
 	require.Zerof(t, missing, "missing 1 out of every %.2f trace ids", float32(n)/float32(missing))
 }
+
+func TestTokenForScheme(t *testing.T) {
+	id, err := HexStringToTraceID("fd5980503add11f09f80f77608c1b2da")
+	require.NoError(t, err)
+
+	// the default scheme must not change, it would move all traces to other ingesters
+	require.Equal(t, TokenFor("tenant", id), TokenForScheme("", "tenant", id))
+	require.Equal(t, TokenFor("tenant", id), TokenForScheme(TraceIDHashSchemeFNV32, "tenant", id))
+	require.Equal(t, TokenFor("tenant", id), TokenForScheme("unknown", "tenant", id))
+
+	for _, scheme := range []string{TraceIDHashSchemeXXHash64, TraceIDHashSchemeFNV128Fold} {
+		require.True(t, ValidTraceIDHashScheme(scheme))
+		require.NotEqual(t, TokenFor("tenant", id), TokenForScheme(scheme, "tenant", id), scheme)
+		require.NotEqual(t, TokenForScheme(scheme, "a", id), TokenForScheme(scheme, "b", id), scheme)
+		require.Equal(t, TokenForScheme(scheme, "tenant", id), TokenForScheme(scheme, "tenant", id), scheme)
+	}
+	require.False(t, ValidTraceIDHashScheme("unknown"))
+}
+
+// Sequential trace IDs are spread evenly over the token space by the alternative schemes. fnv32 is not, which is why
+// they exist.
+func TestTokenForSchemeDistribution(t *testing.T) {
+	const (
+		n       = 100_000
+		buckets = 16
+	)
+
+	for _, scheme := range []string{TraceIDHashSchemeXXHash64, TraceIDHashSchemeFNV128Fold} {
+		t.Run(scheme, func(t *testing.T) {
+			counts := make([]int, buckets)
+			id := make([]byte, 16)
+			for i := 0; i < n; i++ {
+				binary.BigEndian.PutUint64(id[8:], uint64(i))
+				counts[TokenForScheme(scheme, "tenant", id)>>28]++
+			}
+			for _, c := range counts {
+				require.InDelta(t, n/buckets, c, n/buckets*0.1)
+			}
+		})
+	}
+}
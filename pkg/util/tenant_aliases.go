@@ -0,0 +1,77 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/grafana/dskit/tenant"
+	"github.com/grafana/dskit/user"
+)
+
+const tenantIDsSeparator = "|"
+
+// TenantAliases maps alias tenant IDs to their canonical tenant ID. Requests of an alias are ingested and queried
+// as the canonical tenant.
+type TenantAliases map[string]string
+
+// Validate returns an error if an alias or canonical tenant ID is invalid, or if a canonical tenant is an alias
+// itself.
+func (a TenantAliases) Validate() error {
+	for alias, canonical := range a {
+		if err := tenant.ValidTenantID(alias); err != nil {
+			return fmt.Errorf("alias %q: %w", alias, err)
+		}
+		if err := tenant.ValidTenantID(canonical); err != nil {
+			return fmt.Errorf("canonical tenant %q of alias %q: %w", canonical, alias, err)
+		}
+		if alias == canonical {
+			return fmt.Errorf("alias %q is its own canonical tenant", alias)
+		}
+		if _, ok := a[canonical]; ok {
+			return fmt.Errorf("canonical tenant %q of alias %q is an alias", canonical, alias)
+		}
+	}
+	return nil
+}
+
+// Resolve returns the org ID with every alias replaced by its canonical tenant. Org IDs of several tenants are
+// resolved tenant by tenant, and a tenant present twice after resolution is kept once.
+func (a TenantAliases) Resolve(orgID string) string {
+	if len(a) == 0 {
+		return orgID
+	}
+
+	if !strings.Contains(orgID, tenantIDsSeparator) {
+		if canonical, ok := a[orgID]; ok {
+			return canonical
+		}
+		return orgID
+	}
+
+	tenants := strings.Split(orgID, tenantIDsSeparator)
+	resolved := make([]string, 0, len(tenants))
+	for _, t := range tenants {
+		if canonical, ok := a[t]; ok {
+			t = canonical
+		}
+		if !slices.Contains(resolved, t) {
+			resolved = append(resolved, t)
+		}
+	}
+	return strings.Join(resolved, tenantIDsSeparator)
+}
+
+// ResolveContext returns ctx with its org ID resolved. A context without an org ID is returned as is.
+func (a TenantAliases) ResolveContext(ctx context.Context) context.Context {
+	orgID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return ctx
+	}
+
+	if resolved := a.Resolve(orgID); resolved != orgID {
+		return user.InjectOrgID(ctx, resolved)
+	}
+	return ctx
+}
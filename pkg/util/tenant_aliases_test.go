@@ -0,0 +1,56 @@
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantAliasesValidate(t *testing.T) {
+	require.NoError(t, TenantAliases(nil).Validate())
+	require.NoError(t, TenantAliases{"old-team": "new-team", "other-team": "new-team"}.Validate())
+
+	require.ErrorContains(t, TenantAliases{"old-team": "old-team"}.Validate(), "is its own canonical tenant")
+	require.ErrorContains(t, TenantAliases{"a": "b", "b": "c"}.Validate(), "is an alias")
+	require.ErrorContains(t, TenantAliases{"a|b": "c"}.Validate(), `alias "a|b"`)
+	require.ErrorContains(t, TenantAliases{"a": ".."}.Validate(), `canonical tenant ".."`)
+}
+
+func TestTenantAliasesResolve(t *testing.T) {
+	aliases := TenantAliases{"old-team": "new-team", "legacy": "other"}
+
+	tcs := []struct {
+		orgID    string
+		expected string
+	}{
+		{orgID: "new-team", expected: "new-team"},
+		{orgID: "old-team", expected: "new-team"},
+		{orgID: "unknown", expected: "unknown"},
+		{orgID: "old-team|legacy", expected: "new-team|other"},
+		{orgID: "old-team|new-team|third", expected: "new-team|third"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.orgID, func(t *testing.T) {
+			require.Equal(t, tc.expected, aliases.Resolve(tc.orgID))
+		})
+	}
+
+	require.Equal(t, "old-team", TenantAliases(nil).Resolve("old-team"))
+}
+
+func TestTenantAliasesResolveContext(t *testing.T) {
+	aliases := TenantAliases{"old-team": "new-team"}
+
+	ctx := aliases.ResolveContext(user.InjectOrgID(context.Background(), "old-team"))
+	orgID, err := user.ExtractOrgID(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "new-team", orgID)
+
+	// contexts without an org ID are left as is
+	ctx = aliases.ResolveContext(context.Background())
+	_, err = user.ExtractOrgID(ctx)
+	require.Error(t, err)
+}
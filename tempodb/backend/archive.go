@@ -0,0 +1,45 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Archiver is implemented by backends that can move blocks to a colder storage tier. Reads of objects in an offline
+// tier fail with ErrBlockArchived.
+type Archiver interface {
+	// ArchiveBlock moves the data objects of the block to the tier. Block metas and flags are kept in the default
+	// tier so the block can still be polled, compacted away and deleted.
+	ArchiveBlock(ctx context.Context, blockID uuid.UUID, tenantID string, tier string) error
+}
+
+// ErrArchivingNotSupported is returned by backends that forward ArchiveBlock to a backend that can't archive blocks.
+var ErrArchivingNotSupported = errors.New("backend does not support archiving")
+
+// IsBlockDataObject returns true if the object is a data object of a block, i.e. <tenant>/<block id>/<name> except
+// for the block metas and flags.
+func IsBlockDataObject(name string, keypath KeyPath) bool {
+	if len(keypath) != 2 {
+		return false
+	}
+
+	if _, err := uuid.Parse(keypath[1]); err != nil {
+		return false
+	}
+
+	switch name {
+	case MetaName, CompactedMetaName, NoCompactFileName:
+		return false
+	}
+
+	return true
+}
+
+// IsBlockArchived returns true if the block is older than archiveAfter, i.e. it's archived or about to be.
+// Archived blocks aren't compacted. An archiveAfter of 0 disables archiving.
+func IsBlockArchived(meta *BlockMeta, archiveAfter time.Duration, now time.Time) bool {
+	return archiveAfter > 0 && meta.EndTime.Before(now.Add(-archiveAfter))
+}
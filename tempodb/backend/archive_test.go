@@ -0,0 +1,17 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsBlockArchived(t *testing.T) {
+	now := time.Now()
+	meta := &BlockMeta{EndTime: now.Add(-2 * time.Hour)}
+
+	require.True(t, IsBlockArchived(meta, time.Hour, now))
+	require.False(t, IsBlockArchived(meta, 3*time.Hour, now))
+	require.False(t, IsBlockArchived(meta, 0, now))
+}
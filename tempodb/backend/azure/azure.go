@@ -10,7 +10,10 @@ import (
 	"fmt"
 	"io"
 	"path"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
@@ -21,8 +24,10 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
 	"github.com/go-kit/log/level"
 	"github.com/google/uuid"
+	"github.com/grafana/tempo/pkg/boundedwaitgroup"
 	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/tempodb/backend"
+	tempo_credentials "github.com/grafana/tempo/tempodb/backend/credentials"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -39,6 +44,9 @@ type Azure struct {
 	cfg                   *Config
 	containerClient       *container.Client
 	hedgedContainerClient *container.Client
+	// sharedKey is the shared key credential with the key read from the credentials provider, nil without one
+	sharedKey *azblob.SharedKeyCredential
+	creds     *tempo_credentials.Credentials
 }
 
 var (
@@ -46,10 +54,13 @@ var (
 	_ backend.RawWriter             = (*Azure)(nil)
 	_ backend.Compactor             = (*Azure)(nil)
 	_ backend.VersionedReaderWriter = (*Azure)(nil)
+	_ backend.ResumableAppender     = (*Azure)(nil)
+	_ backend.Archiver              = (*Azure)(nil)
 )
 
 type appendTracker struct {
-	Name string
+	Name   string
+	Locked bool
 }
 
 var tracer = otel.Tracer("tempodb/backend/azure")
@@ -73,15 +84,35 @@ func NewVersionedReaderWriter(cfg *Config) (backend.VersionedReaderWriter, error
 	return internalNew(cfg, true)
 }
 
-func internalNew(cfg *Config, confirm bool) (*Azure, error) {
+func internalNew(cfg *Config, confirm bool) (rw *Azure, err error) {
 	ctx := context.Background()
 
-	c, err := getContainerClient(ctx, cfg, false)
+	if cfg.Credentials.Enabled() && (cfg.UseManagedIdentity || cfg.UseFederatedToken) {
+		return nil, errors.New("a credentials provider can't be used with managed identities or federated tokens")
+	}
+	creds, err := tempo_credentials.New(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			creds.Stop()
+		}
+	}()
+	var sharedKey *azblob.SharedKeyCredential
+	if creds != nil {
+		sharedKey, err = newRotatingSharedKeyCredential(cfg, creds)
+		if err != nil {
+			return nil, fmt.Errorf("creating shared key credential: %w", err)
+		}
+	}
+
+	c, err := getContainerClient(ctx, cfg, sharedKey, false)
 	if err != nil {
 		return nil, fmt.Errorf("getting storage container: %w", err)
 	}
 
-	hedgedContainer, err := getContainerClient(ctx, cfg, true)
+	hedgedContainer, err := getContainerClient(ctx, cfg, sharedKey, true)
 	if err != nil {
 		return nil, fmt.Errorf("getting hedged storage container: %w", err)
 	}
@@ -94,10 +125,16 @@ func internalNew(cfg *Config, confirm bool) (*Azure, error) {
 		}
 	}
 
-	rw := &Azure{
+	if cfg.ObjectLock.Enabled() && !slices.Contains(blob.PossibleImmutabilityPolicySettingValues(), blob.ImmutabilityPolicySetting(cfg.ObjectLock.Mode)) {
+		return nil, fmt.Errorf("unsupported object lock mode %q, supported modes are %s and %s", cfg.ObjectLock.Mode, blob.ImmutabilityPolicySettingLocked, blob.ImmutabilityPolicySettingUnlocked)
+	}
+
+	rw = &Azure{
 		cfg:                   cfg,
 		containerClient:       c,
 		hedgedContainerClient: hedgedContainer,
+		sharedKey:             sharedKey,
+		creds:                 creds,
 	}
 
 	return rw, nil
@@ -108,6 +145,10 @@ func readError(err error) error {
 		return backend.ErrDoesNotExist
 	}
 
+	if bloberror.HasCode(err, bloberror.BlobArchived) {
+		return backend.ErrBlockArchived
+	}
+
 	if err != nil {
 		return fmt.Errorf("reading Azure blob container: %w", err)
 	}
@@ -116,44 +157,77 @@ func readError(err error) error {
 
 // Write implements backend.Writer
 func (rw *Azure) Write(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, _ int64, _ *backend.CacheInfo) error {
+	locked := rw.cfg.ObjectLock.Locks(name, keypath)
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 
 	derivedCtx, span := tracer.Start(ctx, "azure.Write")
 	defer span.End()
 
-	return rw.writer(derivedCtx, bufio.NewReader(data), backend.ObjectFileName(keypath, name))
+	objName := backend.ObjectFileName(keypath, name)
+	err := rw.writer(derivedCtx, bufio.NewReader(data), objName)
+	if err != nil {
+		return err
+	}
+
+	if locked {
+		return rw.lockObject(derivedCtx, objName)
+	}
+	return nil
 }
 
 // Append implements backend.Writer
 func (rw *Azure) Append(ctx context.Context, name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
+	return rw.ResumableAppend(ctx, name, keypath, tracker, buffer)
+}
+
+// ResumableAppend implements backend.ResumableAppender. Every append stages a block and commits the block list of
+// the blob, so a failed append leaves the blob as it was after the last successful one.
+func (rw *Azure) ResumableAppend(ctx context.Context, name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
+	locked := rw.cfg.ObjectLock.Locks(name, keypath)
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 	var a appendTracker
 	if tracker == nil {
 		a.Name = backend.ObjectFileName(keypath, name)
+		a.Locked = locked
 
 		err := rw.writeAll(ctx, a.Name, buffer)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		a = tracker.(appendTracker)
+		var ok bool
+		if a, ok = tracker.(appendTracker); !ok {
+			return nil, fmt.Errorf("unexpected append tracker %T", tracker)
+		}
 
 		err := rw.append(ctx, buffer, a.Name)
 		if err != nil {
-			return nil, err
+			return a, err
 		}
 	}
 
 	return a, nil
 }
 
-// CloseAppend implements backend.Writer
-func (rw *Azure) CloseAppend(context.Context, backend.AppendTracker) error {
+// CloseAppend implements backend.Writer. Appended blobs are locked when they are closed because the block list of a
+// locked blob can't be committed again.
+func (rw *Azure) CloseAppend(ctx context.Context, tracker backend.AppendTracker) error {
+	if tracker == nil {
+		return nil
+	}
+
+	a, ok := tracker.(appendTracker)
+	if !ok {
+		return fmt.Errorf("unexpected append tracker %T", tracker)
+	}
+	if a.Locked {
+		return rw.lockObject(ctx, a.Name)
+	}
 	return nil
 }
 
 func (rw *Azure) Delete(ctx context.Context, name string, keypath backend.KeyPath, _ *backend.CacheInfo) error {
-	blobClient, err := getBlobClient(ctx, rw.cfg, backend.ObjectFileName(keypath, name))
+	blobClient, err := getBlobClient(ctx, rw.cfg, rw.sharedKey, backend.ObjectFileName(keypath, name))
 	if err != nil {
 		return fmt.Errorf("cannot get Azure blob client, name: %s: %w", backend.ObjectFileName(keypath, name), err)
 	}
@@ -202,22 +276,70 @@ func (rw *Azure) ListBlocks(ctx context.Context, tenant string) ([]uuid.UUID, []
 	ctx, span := tracer.Start(ctx, "V2.ListBlocks")
 	defer span.End()
 
+	keypath := backend.KeyPathWithPrefix(backend.KeyPath{tenant}, rw.cfg.Prefix)
+	prefix := path.Join(keypath...)
+	if len(prefix) > 0 {
+		prefix += dir
+	}
+
+	if rw.cfg.ListBlocksConcurrency <= 1 {
+		return rw.listBlocks(ctx, prefix, prefix)
+	}
+
+	// blob listings can't start after a key, so the IDs are partitioned by their first byte instead
 	var (
+		wg                = boundedwaitgroup.New(uint(rw.cfg.ListBlocksConcurrency))
+		mtx               sync.Mutex
 		blockIDs          = make([]uuid.UUID, 0, 1000)
 		compactedBlockIDs = make([]uuid.UUID, 0, 1000)
-		keypath           = backend.KeyPathWithPrefix(backend.KeyPath{tenant}, rw.cfg.Prefix)
-		parts             []string
-		id                uuid.UUID
+		errs              []error
 	)
 
-	prefix := path.Join(keypath...)
-	if len(prefix) > 0 {
-		prefix += dir
+	for i := 0; i <= 0xff; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(listPrefix string) {
+			defer wg.Done()
+
+			ids, compactedIDs, err := rw.listBlocks(ctx, prefix, listPrefix)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			blockIDs = append(blockIDs, ids...)
+			compactedBlockIDs = append(compactedBlockIDs, compactedIDs...)
+		}(fmt.Sprintf("%s%02x", prefix, i))
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
 	}
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	return blockIDs, compactedBlockIDs, nil
+}
+
+// listBlocks lists the blocks of the tenant at prefix whose objects start with listPrefix.
+func (rw *Azure) listBlocks(ctx context.Context, prefix, listPrefix string) ([]uuid.UUID, []uuid.UUID, error) {
+	var (
+		blockIDs          = make([]uuid.UUID, 0, 1000)
+		compactedBlockIDs = make([]uuid.UUID, 0, 1000)
+		parts             []string
+		id                uuid.UUID
+	)
 
 	pager := rw.containerClient.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
 		Include: container.ListBlobsInclude{},
-		Prefix:  &prefix,
+		Prefix:  &listPrefix,
 	})
 
 	for pager.More() {
@@ -289,6 +411,9 @@ func (rw *Azure) Find(ctx context.Context, keypath backend.KeyPath, f backend.Fi
 				Key:      o,
 				Modified: *b.Properties.LastModified,
 			}
+			if b.Properties.ContentLength != nil {
+				opts.Size = *b.Properties.ContentLength
+			}
 			f(opts)
 		}
 
@@ -298,14 +423,14 @@ func (rw *Azure) Find(ctx context.Context, keypath backend.KeyPath, f backend.Fi
 }
 
 // Read implements backend.Reader
-func (rw *Azure) Read(ctx context.Context, name string, keypath backend.KeyPath, _ *backend.CacheInfo) (io.ReadCloser, int64, error) {
+func (rw *Azure) Read(ctx context.Context, name string, keypath backend.KeyPath, cacheInfo *backend.CacheInfo) (io.ReadCloser, int64, error) {
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 
 	derivedCtx, span := tracer.Start(ctx, "azure.Read")
 	defer span.End()
 
 	object := backend.ObjectFileName(keypath, name)
-	b, _, err := rw.readAll(derivedCtx, object)
+	b, _, err := rw.readAll(derivedCtx, rw.readContainerClient(cacheInfo, false), object)
 	if err != nil {
 		return nil, 0, readError(err)
 	}
@@ -314,7 +439,7 @@ func (rw *Azure) Read(ctx context.Context, name string, keypath backend.KeyPath,
 }
 
 // ReadRange implements backend.Reader
-func (rw *Azure) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, _ *backend.CacheInfo) error {
+func (rw *Azure) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, cacheInfo *backend.CacheInfo) error {
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 
 	derivedCtx, span := tracer.Start(ctx, "azure.ReadRange", trace.WithAttributes(
@@ -324,7 +449,7 @@ func (rw *Azure) ReadRange(ctx context.Context, name string, keypath backend.Key
 	defer span.End()
 
 	object := backend.ObjectFileName(keypath, name)
-	err := rw.readRange(derivedCtx, object, int64(offset), buffer)
+	err := rw.readRange(derivedCtx, rw.readContainerClient(cacheInfo, true), object, int64(offset), buffer)
 	if err != nil {
 		return readError(err)
 	}
@@ -334,6 +459,7 @@ func (rw *Azure) ReadRange(ctx context.Context, name string, keypath backend.Key
 
 // Shutdown implements backend.Reader
 func (rw *Azure) Shutdown() {
+	rw.creds.Stop()
 }
 
 func (rw *Azure) WriteVersioned(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, size int64, version backend.Version) (backend.Version, error) {
@@ -382,7 +508,7 @@ func (rw *Azure) ReadVersioned(ctx context.Context, name string, keypath backend
 	defer span.End()
 
 	object := backend.ObjectFileName(keypath, name)
-	b, etag, err := rw.readAll(derivedCtx, object)
+	b, etag, err := rw.readAll(derivedCtx, rw.hedgedContainerClient, object)
 	if err != nil {
 		return nil, "", readError(err)
 	}
@@ -390,6 +516,33 @@ func (rw *Azure) ReadVersioned(ctx context.Context, name string, keypath backend
 	return io.NopCloser(bytes.NewReader(b)), backend.Version(etag), nil
 }
 
+// ReadIfChanged implements backend.ConditionalReader. The version is the ETag of the blob, which is compared
+// against the blob properties before the contents are downloaded.
+func (rw *Azure) ReadIfChanged(ctx context.Context, name string, keypath backend.KeyPath, version backend.Version) (io.ReadCloser, int64, backend.Version, error) {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+
+	derivedCtx, span := tracer.Start(ctx, "azure.ReadIfChanged")
+	defer span.End()
+
+	object := backend.ObjectFileName(keypath, name)
+	blobClient := rw.hedgedContainerClient.NewBlockBlobClient(object)
+
+	props, err := blobClient.GetProperties(derivedCtx, &blob.GetPropertiesOptions{})
+	if err != nil {
+		return nil, 0, "", readError(err)
+	}
+	if version != "" && props.ETag != nil && backend.Version(*props.ETag) == version {
+		return nil, 0, "", backend.ErrNotModified
+	}
+
+	b, etag, err := rw.download(blobClient, object, props)
+	if err != nil {
+		return nil, 0, "", readError(err)
+	}
+
+	return io.NopCloser(bytes.NewReader(b)), int64(len(b)), backend.Version(etag), nil
+}
+
 func (rw *Azure) writeAll(ctx context.Context, name string, b []byte) error {
 	err := rw.writer(ctx, bytes.NewReader(b), name)
 	if err != nil {
@@ -454,8 +607,32 @@ func (rw *Azure) writer(ctx context.Context, src io.Reader, name string) error {
 	return nil
 }
 
-func (rw *Azure) readRange(ctx context.Context, name string, offset int64, destBuffer []byte) error {
-	blobClient := rw.hedgedContainerClient.NewBlockBlobClient(name)
+// lockObject sets the immutability policy of the blob.
+func (rw *Azure) lockObject(ctx context.Context, name string) error {
+	blobClient := rw.containerClient.NewBlockBlobClient(name)
+
+	mode := blob.ImmutabilityPolicySetting(rw.cfg.ObjectLock.Mode)
+	_, err := blobClient.SetImmutabilityPolicy(ctx, rw.cfg.ObjectLock.RetainUntil(time.Now()), &blob.SetImmutabilityPolicyOptions{
+		Mode: &mode,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot set immutability policy of blob, name: %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// readContainerClient returns the client of a Read or ReadRange, the hedged client unless the role of the read isn't
+// hedged.
+func (rw *Azure) readContainerClient(cacheInfo *backend.CacheInfo, rangeRead bool) *container.Client {
+	if rw.cfg.HedgeRequestsRoles.Hedge(cacheInfo, rangeRead) {
+		return rw.hedgedContainerClient
+	}
+	return rw.containerClient
+}
+
+func (rw *Azure) readRange(ctx context.Context, client *container.Client, name string, offset int64, destBuffer []byte) error {
+	blobClient := client.NewBlockBlobClient(name)
 
 	props, err := blobClient.GetProperties(ctx, &blob.GetPropertiesOptions{})
 	if err != nil {
@@ -497,16 +674,21 @@ func (rw *Azure) readRange(ctx context.Context, name string, offset int64, destB
 	return nil
 }
 
-func (rw *Azure) readAll(ctx context.Context, name string) ([]byte, azcore.ETag, error) {
-	blobClient := rw.hedgedContainerClient.NewBlockBlobClient(name)
+func (rw *Azure) readAll(ctx context.Context, client *container.Client, name string) ([]byte, azcore.ETag, error) {
+	blobClient := client.NewBlockBlobClient(name)
 
 	props, err := blobClient.GetProperties(ctx, &blob.GetPropertiesOptions{})
 	if err != nil {
 		return nil, "", err
 	}
 
+	return rw.download(blobClient, name, props)
+}
+
+// download reads the whole blob described by props.
+func (rw *Azure) download(blobClient *blockblob.Client, name string, props blob.GetPropertiesResponse) ([]byte, azcore.ETag, error) {
 	if props.ContentLength == nil {
-		return nil, "", fmt.Errorf("expected content length but got none for blob %s: %w", name, err)
+		return nil, "", fmt.Errorf("expected content length but got none for blob %s", name)
 	}
 
 	destBuffer := make([]byte, *props.ContentLength)
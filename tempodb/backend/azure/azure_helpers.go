@@ -15,7 +15,10 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
 	"github.com/cristalhq/hedgedhttp"
+	"github.com/go-kit/log/level"
 
+	"github.com/grafana/tempo/pkg/util/log"
+	tempo_credentials "github.com/grafana/tempo/tempodb/backend/credentials"
 	"github.com/grafana/tempo/tempodb/backend/instrumentation"
 )
 
@@ -23,7 +26,7 @@ const (
 	maxRetries = 1
 )
 
-func getContainerClient(ctx context.Context, cfg *Config, hedge bool) (*container.Client, error) {
+func getContainerClient(ctx context.Context, cfg *Config, sharedKey *azblob.SharedKeyCredential, hedge bool) (*container.Client, error) {
 	var err error
 
 	retry := policy.RetryOptions{
@@ -116,9 +119,12 @@ func getContainerClient(ctx context.Context, cfg *Config, hedge bool) (*containe
 		}
 	// If no authentication mechanism has been explicitly specified, assume shared key credential.
 	default:
-		credential, err := azblob.NewSharedKeyCredential(accountName, getStorageAccountKey(cfg))
-		if err != nil {
-			return nil, err
+		credential := sharedKey
+		if credential == nil {
+			credential, err = azblob.NewSharedKeyCredential(accountName, getStorageAccountKey(cfg))
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		client, err = azblob.NewClientWithSharedKeyCredential(u.String(), credential, &opts)
@@ -130,8 +136,8 @@ func getContainerClient(ctx context.Context, cfg *Config, hedge bool) (*containe
 	return client.ServiceClient().NewContainerClient(cfg.ContainerName), nil
 }
 
-func getBlobClient(ctx context.Context, conf *Config, blobName string) (*blob.Client, error) {
-	c, err := getContainerClient(ctx, conf, false)
+func getBlobClient(ctx context.Context, conf *Config, sharedKey *azblob.SharedKeyCredential, blobName string) (*blob.Client, error) {
+	c, err := getContainerClient(ctx, conf, sharedKey, false)
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +146,7 @@ func getBlobClient(ctx context.Context, conf *Config, blobName string) (*blob.Cl
 }
 
 func CreateContainer(ctx context.Context, conf *Config) (*container.Client, error) {
-	c, err := getContainerClient(ctx, conf, false)
+	c, err := getContainerClient(ctx, conf, nil, false)
 	if err != nil {
 		return nil, err
 	}
@@ -165,3 +171,19 @@ func getStorageAccountKey(cfg *Config) string {
 
 	return accountKey
 }
+
+// newRotatingSharedKeyCredential returns the shared key credential of the storage account with the key read from the
+// credentials provider. The key of the credential is replaced when the provider rotates it.
+func newRotatingSharedKeyCredential(cfg *Config, creds *tempo_credentials.Credentials) (*azblob.SharedKeyCredential, error) {
+	credential, err := azblob.NewSharedKeyCredential(getStorageAccountName(cfg), creds.Value(CredentialsKeyStorageAccountKey))
+	if err != nil {
+		return nil, err
+	}
+
+	creds.OnChange(func(values map[string]string) {
+		if err := credential.SetAccountKey(values[CredentialsKeyStorageAccountKey]); err != nil {
+			level.Error(log.Logger).Log("msg", "failed to rotate azure storage account key", "err", err)
+		}
+	})
+	return credential, nil
+}
@@ -103,7 +103,7 @@ func TestGetContainerClient(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			cfg.Endpoint = tc.endpoint
 
-			client, err := getContainerClient(context.Background(), &cfg, false)
+			client, err := getContainerClient(context.Background(), &cfg, nil, false)
 			assert.NoError(t, err)
 			assert.Equal(t, tc.expectedURL, client.URL())
 		})
@@ -9,6 +9,8 @@ import (
 	"net/http/httptest"
 	"net/textproto"
 	"os"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -166,6 +168,11 @@ func TestReadError(t *testing.T) {
 	otherAzureError := blobStorageError(string(bloberror.InternalError))
 	err = readError(otherAzureError)
 	require.NotEqual(t, backend.ErrDoesNotExist, err)
+
+	// blobs in the archive tier are returned as ErrBlockArchived
+	blobArchivedError := blobStorageError(string(bloberror.BlobArchived))
+	err = readError(blobArchivedError)
+	require.ErrorIs(t, err, backend.ErrBlockArchived)
 }
 
 func blobStorageError(serviceCode string) error {
@@ -246,6 +253,16 @@ func blobStorageError(serviceCode string) error {
 // 	}
 // }
 
+func TestAppendUnexpectedTracker(t *testing.T) {
+	rw := &Azure{cfg: &Config{}}
+
+	_, err := rw.Append(context.Background(), "object", backend.KeyPath{"tenant"}, "tracker", []byte("data"))
+	require.ErrorContains(t, err, "unexpected append tracker string")
+
+	err = rw.CloseAppend(context.Background(), "tracker")
+	require.ErrorContains(t, err, "unexpected append tracker string")
+}
+
 func TestListBlocksWithPrefix(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -399,6 +416,76 @@ func TestListBlocksWithPrefix(t *testing.T) {
 	}
 }
 
+func TestListBlocksConcurrent(t *testing.T) {
+	liveBlockIDs := []uuid.UUID{
+		uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+		uuid.MustParse("ab000000-0000-0000-0000-000000000000"),
+		uuid.MustParse("ff000000-0000-0000-0000-000000000000"),
+	}
+	compactedBlockIDs := []uuid.UUID{
+		uuid.MustParse("ab000000-0000-0000-0000-000000000001"),
+	}
+
+	blob := func(name string) string {
+		return fmt.Sprintf(`<Blob><Name>%s</Name><Properties><BlobType>BlockBlob</BlobType></Properties></Blob>`, name)
+	}
+
+	var (
+		mtx      sync.Mutex
+		prefixes = map[string]int{}
+	)
+	server := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			return
+		}
+
+		prefix := r.URL.Query().Get("prefix")
+		mtx.Lock()
+		prefixes[prefix]++
+		mtx.Unlock()
+
+		var blobs []string
+		for _, id := range liveBlockIDs {
+			if name := "a/single-tenant/" + id.String() + "/meta.json"; strings.HasPrefix(name, prefix) {
+				blobs = append(blobs, blob(name))
+			}
+		}
+		for _, id := range compactedBlockIDs {
+			if name := "a/single-tenant/" + id.String() + "/meta.compacted.json"; strings.HasPrefix(name, prefix) {
+				blobs = append(blobs, blob(name))
+			}
+		}
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+			<EnumerationResults ServiceEndpoint="http://myaccount.blob.core.windows.net/" ContainerName="mycontainer">
+			<Prefix>` + prefix + `</Prefix><Blobs>` + strings.Join(blobs, "") + `</Blobs><NextMarker />
+			</EnumerationResults>`))
+	})
+
+	r, _, _, err := NewNoConfirm(&Config{
+		StorageAccountName:    "testing_account",
+		StorageAccountKey:     flagext.SecretWithValue("YQo="),
+		MaxBuffers:            3,
+		BufferSize:            1000,
+		ContainerName:         "blerg",
+		Prefix:                "a",
+		Endpoint:              server.URL[7:], // [7:] -> strip http://,
+		ListBlocksConcurrency: 4,
+	})
+	require.NoError(t, err)
+
+	blockIDs, compacted, err := r.ListBlocks(context.Background(), "single-tenant")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, liveBlockIDs, blockIDs)
+	assert.ElementsMatch(t, compactedBlockIDs, compacted)
+
+	// every partition is listed once
+	require.Len(t, prefixes, 256)
+	for i := 0; i <= 0xff; i++ {
+		require.Equal(t, 1, prefixes[fmt.Sprintf("a/single-tenant/%02x", i)])
+	}
+}
+
 func testServer(t *testing.T, httpHandler http.HandlerFunc) *httptest.Server {
 	t.Helper()
 	assert.NotNil(t, httpHandler)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"path"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
@@ -34,7 +35,7 @@ func (rw *Azure) MarkBlockCompacted(blockID uuid.UUID, tenantID string) error {
 	compactedMetaFilename := backend.CompactedMetaFileName(blockID, tenantID, rw.cfg.Prefix)
 	ctx := context.TODO()
 
-	src, _, err := rw.readAll(ctx, metaFilename)
+	src, _, err := rw.readAll(ctx, rw.hedgedContainerClient, metaFilename)
 	if err != nil {
 		return err
 	}
@@ -89,6 +90,57 @@ func (rw *Azure) ClearBlock(blockID uuid.UUID, tenantID string) error {
 	return warning
 }
 
+// ArchiveBlock implements backend.Archiver. The data objects of the block are moved to the Cool, Cold or Archive
+// access tier.
+func (rw *Azure) ArchiveBlock(ctx context.Context, blockID uuid.UUID, tenantID string, tier string) error {
+	if len(tenantID) == 0 {
+		return backend.ErrEmptyTenantID
+	}
+	if blockID == uuid.Nil {
+		return backend.ErrEmptyBlockID
+	}
+
+	accessTier := blob.AccessTier(tier)
+	switch accessTier {
+	case blob.AccessTierCool, blob.AccessTierCold, blob.AccessTierArchive:
+	default:
+		return fmt.Errorf("unsupported archive tier %q, supported tiers are %s, %s and %s", tier, blob.AccessTierCool, blob.AccessTierCold, blob.AccessTierArchive)
+	}
+
+	keypath := backend.KeyPathForBlock(blockID, tenantID)
+	prefix := backend.RootPath(blockID, tenantID, rw.cfg.Prefix)
+	pager := rw.containerClient.NewListBlobsHierarchyPager("", &container.ListBlobsHierarchyOptions{
+		Include: container.ListBlobsInclude{},
+		Prefix:  &prefix,
+	})
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("error listing blobs of block %s: %w", blockID, err)
+		}
+
+		for _, b := range page.Segment.BlobItems {
+			if b.Name == nil {
+				return fmt.Errorf("unexpected empty blob name when listing %s", prefix)
+			}
+			if !backend.IsBlockDataObject(path.Base(*b.Name), keypath) {
+				continue
+			}
+			if b.Properties != nil && b.Properties.AccessTier != nil && *b.Properties.AccessTier == accessTier {
+				continue
+			}
+
+			_, err = rw.containerClient.NewBlockBlobClient(*b.Name).SetTier(ctx, accessTier, nil)
+			if err != nil {
+				return fmt.Errorf("cannot set access tier of blob, name: %s: %w", *b.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (rw *Azure) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*backend.CompactedBlockMeta, error) {
 	if len(tenantID) == 0 {
 		return nil, backend.ErrEmptyTenantID
@@ -114,7 +166,7 @@ func (rw *Azure) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*backen
 }
 
 func (rw *Azure) readAllWithModTime(ctx context.Context, name string) ([]byte, time.Time, error) {
-	bytes, _, err := rw.readAll(ctx, name)
+	bytes, _, err := rw.readAll(ctx, rw.hedgedContainerClient, name)
 	if err != nil {
 		return nil, time.Time{}, err
 	}
@@ -128,7 +180,7 @@ func (rw *Azure) readAllWithModTime(ctx context.Context, name string) ([]byte, t
 
 // getAttributes returns information about the specified blob using its name.
 func (rw *Azure) getAttributes(ctx context.Context, name string) (BlobAttributes, error) {
-	blobClient, err := getBlobClient(ctx, rw.cfg, name)
+	blobClient, err := getBlobClient(ctx, rw.cfg, rw.sharedKey, name)
 	if err != nil {
 		return BlobAttributes{}, fmt.Errorf("cannot get Azure blob client, name: %s: %w", name, err)
 	}
@@ -7,21 +7,35 @@ import (
 	"github.com/grafana/dskit/flagext"
 
 	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/credentials"
 )
 
+// CredentialsKeyStorageAccountKey is the key of the storage account key read from a credentials provider.
+const CredentialsKeyStorageAccountKey = "storage_account_key"
+
 type Config struct {
-	StorageAccountName string         `yaml:"storage_account_name"`
-	StorageAccountKey  flagext.Secret `yaml:"storage_account_key"`
-	UseManagedIdentity bool           `yaml:"use_managed_identity"`
-	UseFederatedToken  bool           `yaml:"use_federated_token"`
-	UserAssignedID     string         `yaml:"user_assigned_id"`
-	ContainerName      string         `yaml:"container_name"`
-	Prefix             string         `yaml:"prefix"`
-	Endpoint           string         `yaml:"endpoint_suffix"`
-	MaxBuffers         int            `yaml:"max_buffers"`
-	BufferSize         int            `yaml:"buffer_size"`
-	HedgeRequestsAt    time.Duration  `yaml:"hedge_requests_at"`
-	HedgeRequestsUpTo  int            `yaml:"hedge_requests_up_to"`
+	StorageAccountName string                  `yaml:"storage_account_name"`
+	StorageAccountKey  flagext.Secret          `yaml:"storage_account_key"`
+	UseManagedIdentity bool                    `yaml:"use_managed_identity"`
+	UseFederatedToken  bool                    `yaml:"use_federated_token"`
+	UserAssignedID     string                  `yaml:"user_assigned_id"`
+	ContainerName      string                  `yaml:"container_name"`
+	Prefix             string                  `yaml:"prefix"`
+	Endpoint           string                  `yaml:"endpoint_suffix"`
+	MaxBuffers         int                     `yaml:"max_buffers"`
+	BufferSize         int                     `yaml:"buffer_size"`
+	HedgeRequestsAt    time.Duration           `yaml:"hedge_requests_at"`
+	HedgeRequestsUpTo  int                     `yaml:"hedge_requests_up_to"`
+	HedgeRequestsRoles backend.HedgedReadRoles `yaml:"hedge_requests_roles"`
+	// ListBlocksConcurrency is the number of list calls made in parallel when listing the blocks of a tenant. The
+	// blocks are listed by the first two hex characters of their IDs if it's greater than 1.
+	ListBlocksConcurrency int `yaml:"list_blocks_concurrency"`
+
+	// Credentials reads the storage account key from a provider instead of the static key
+	Credentials credentials.Config `yaml:"credentials"`
+
+	ObjectLock backend.ObjectLockConfig `yaml:"object_lock"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
@@ -31,6 +45,8 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	f.StringVar(&cfg.Prefix, util.PrefixConfig(prefix, "azure.prefix"), "", "Azure container prefix to store blocks in.")
 	f.StringVar(&cfg.Endpoint, util.PrefixConfig(prefix, "azure.endpoint"), "blob.core.windows.net", "Azure endpoint to push blocks to.")
 	f.IntVar(&cfg.MaxBuffers, util.PrefixConfig(prefix, "azure.max_buffers"), 4, "Number of simultaneous uploads.")
+	f.IntVar(&cfg.ListBlocksConcurrency, util.PrefixConfig(prefix, "azure.list_blocks_concurrency"), 3, "number of concurrent list calls to make to backend")
+	cfg.Credentials.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "azure.credentials"), f)
 	cfg.BufferSize = 3 * 1024 * 1024
 	cfg.HedgeRequestsUpTo = 2
 }
@@ -23,6 +23,7 @@ var (
 	ErrEmptyTenantID = fmt.Errorf("empty tenant id")
 	ErrEmptyBlockID  = fmt.Errorf("empty block id")
 	ErrBadSeedFile   = fmt.Errorf("bad seed file")
+	ErrBlockArchived = fmt.Errorf("data archived: the block is in an archive storage tier and can't be read until it's rehydrated")
 
 	GlobalMaxBlockID = uuid.MustParse("ffffffff-ffff-ffff-ffff-ffffffffffff")
 
@@ -50,6 +51,9 @@ type Writer interface {
 	Append(ctx context.Context, name string, blockID uuid.UUID, tenantID string, tracker AppendTracker, buffer []byte) (AppendTracker, error)
 	// CloseAppend closes any resources associated with the AppendTracker
 	CloseAppend(ctx context.Context, tracker AppendTracker) error
+	// ResumableStreamWriter returns a writer that streams data to an object with an Append job. Appends that fail are
+	// retried from the last checkpoint of the job if the backend implements ResumableAppender. Close completes the job.
+	ResumableStreamWriter(ctx context.Context, name string, blockID uuid.UUID, tenantID string) io.WriteCloser
 	// WriteTenantIndex writes the two meta slices as a tenant index
 	WriteTenantIndex(ctx context.Context, tenantID string, meta []*BlockMeta, compactedMeta []*CompactedBlockMeta) error
 	// Delete deletes an object.
@@ -1,6 +1,7 @@
 package backend
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -186,6 +187,78 @@ func (b *BlockMeta) ObjectAdded(start, end uint32) {
 	b.TotalObjects++
 }
 
+// TraceIDRangeAdded extends the trace ID range of the block with the trace ID.
+func (b *BlockMeta) TraceIDRangeAdded(id []byte) {
+	if len(b.MinID) == 0 || bytes.Compare(id, b.MinID) < 0 {
+		b.MinID = append([]byte(nil), id...)
+	}
+	if len(b.MaxID) == 0 || bytes.Compare(id, b.MaxID) > 0 {
+		b.MaxID = append([]byte(nil), id...)
+	}
+}
+
+// TraceIDInRange returns false if the block has a trace ID range and the trace ID is outside of it. Blocks written
+// before the range was recorded don't have one.
+func (b *BlockMeta) TraceIDInRange(id []byte) bool {
+	if len(b.MinID) == 0 || len(b.MaxID) == 0 {
+		return true
+	}
+	return bytes.Compare(id, b.MinID) >= 0 && bytes.Compare(id, b.MaxID) <= 0
+}
+
+// IsFederated returns true if the block was listed in another bucket than the primary bucket of a Federation.
+// These blocks are only read and never compacted, retained or rewritten.
+func (b *BlockMeta) IsFederated() bool {
+	return b.Source != "" && b.Source != FederationPrimary
+}
+
+// ErrorSpanAdded extends the error time range of the block by a span with an error status.
+// start/end are unix epoch nanoseconds. The error time range is only used when ErrorTimesTracked is set.
+func (b *BlockMeta) ErrorSpanAdded(start, end uint64) {
+	startTime := time.Unix(0, int64(start))
+	if b.ErrorStartTime == nil || startTime.Before(*b.ErrorStartTime) {
+		b.ErrorStartTime = &startTime
+	}
+
+	endTime := time.Unix(0, int64(end))
+	if b.ErrorEndTime == nil || endTime.After(*b.ErrorEndTime) {
+		b.ErrorEndTime = &endTime
+	}
+}
+
+// CombineErrorTimes sets the error time range of the block to the combined error time ranges of the
+// given blocks. Error times are only tracked if they are tracked by all the blocks.
+func (b *BlockMeta) CombineErrorTimes(metas []*BlockMeta) {
+	b.ErrorStartTime, b.ErrorEndTime = nil, nil
+	b.ErrorTimesTracked = len(metas) > 0
+
+	for _, m := range metas {
+		if !m.ErrorTimesTracked {
+			b.ErrorStartTime, b.ErrorEndTime = nil, nil
+			b.ErrorTimesTracked = false
+			return
+		}
+		if m.ErrorStartTime != nil && (b.ErrorStartTime == nil || m.ErrorStartTime.Before(*b.ErrorStartTime)) {
+			b.ErrorStartTime = m.ErrorStartTime
+		}
+		if m.ErrorEndTime != nil && (b.ErrorEndTime == nil || m.ErrorEndTime.After(*b.ErrorEndTime)) {
+			b.ErrorEndTime = m.ErrorEndTime
+		}
+	}
+}
+
+// MayContainErrorsInRange returns false if the block tracks its error times and none of its spans with an
+// error status overlap the given range.
+func (b *BlockMeta) MayContainErrorsInRange(start, end time.Time) bool {
+	if !b.ErrorTimesTracked {
+		return true
+	}
+	if b.ErrorStartTime == nil || b.ErrorEndTime == nil {
+		return false
+	}
+	return !b.ErrorStartTime.After(end) && !b.ErrorEndTime.Before(start)
+}
+
 func (b *BlockMeta) DedicatedColumnsHash() uint64 {
 	return b.DedicatedColumns.Hash()
 }
@@ -0,0 +1,115 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/golang/groupcache/lru"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	tempo_io "github.com/grafana/tempo/pkg/io"
+)
+
+var metricBlockMetaCacheReads = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempodb",
+	Name:      "backend_block_meta_cache_reads_total",
+	Help:      "Total number of block meta reads through the block meta cache by result. not_modified reads were revalidated without downloading the meta.",
+}, []string{"result"})
+
+const (
+	blockMetaCacheMiss        = "miss"
+	blockMetaCacheModified    = "modified"
+	blockMetaCacheNotModified = "not_modified"
+)
+
+// blockMetaCache keeps the contents of block metas with the version they were read at. Metas are stored as bytes
+// and unmarshalled on every read so callers never share a *BlockMeta.
+type blockMetaCache struct {
+	r ConditionalReader
+
+	mtx   sync.Mutex
+	cache *lru.Cache
+}
+
+type blockMetaCacheEntry struct {
+	version Version
+	b       []byte
+}
+
+func newBlockMetaCache(r ConditionalReader, size int) *blockMetaCache {
+	return &blockMetaCache{
+		r:     r,
+		cache: lru.New(size),
+	}
+}
+
+func (c *blockMetaCache) blockMeta(ctx context.Context, blockID uuid.UUID, tenantID string) (*BlockMeta, error) {
+	keypath := KeyPathForBlock(blockID, tenantID)
+	key := MetaFileName(blockID, tenantID, "")
+
+	c.mtx.Lock()
+	var cached *blockMetaCacheEntry
+	if v, ok := c.cache.Get(key); ok {
+		cached = v.(*blockMetaCacheEntry)
+	}
+	c.mtx.Unlock()
+
+	var version Version
+	if cached != nil {
+		version = cached.version
+	}
+
+	b, version, err := c.read(ctx, keypath, version)
+	switch {
+	case errors.Is(err, ErrNotModified):
+		metricBlockMetaCacheReads.WithLabelValues(blockMetaCacheNotModified).Inc()
+		b = cached.b
+	case err != nil:
+		if errors.Is(err, ErrDoesNotExist) {
+			c.mtx.Lock()
+			c.cache.Remove(key)
+			c.mtx.Unlock()
+		}
+		return nil, err
+	default:
+		if cached != nil {
+			metricBlockMetaCacheReads.WithLabelValues(blockMetaCacheModified).Inc()
+		} else {
+			metricBlockMetaCacheReads.WithLabelValues(blockMetaCacheMiss).Inc()
+		}
+	}
+
+	out := &BlockMeta{}
+	err = json.Unmarshal(b, out)
+	if err != nil {
+		return nil, err
+	}
+
+	// only cache metas that unmarshal and can be revalidated
+	if version != "" && (cached == nil || cached.version != version) {
+		c.mtx.Lock()
+		c.cache.Add(key, &blockMetaCacheEntry{version: version, b: b})
+		c.mtx.Unlock()
+	}
+
+	return out, nil
+}
+
+func (c *blockMetaCache) read(ctx context.Context, keypath KeyPath, version Version) ([]byte, Version, error) {
+	reader, size, current, err := c.r.ReadIfChanged(ctx, MetaName, keypath, version)
+	if err != nil {
+		return nil, version, err
+	}
+	defer reader.Close()
+
+	b, err := tempo_io.ReadAllWithEstimate(reader, size)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return b, current, nil
+}
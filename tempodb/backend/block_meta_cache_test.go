@@ -0,0 +1,77 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type mockConditionalReader struct {
+	MockRawReader
+
+	version Version
+	reads   int
+}
+
+func (m *mockConditionalReader) ReadIfChanged(_ context.Context, _ string, _ KeyPath, version Version) (io.ReadCloser, int64, Version, error) {
+	if m.R == nil {
+		return nil, 0, "", ErrDoesNotExist
+	}
+	if version != "" && version == m.version {
+		return nil, 0, "", ErrNotModified
+	}
+
+	m.reads++
+	return io.NopCloser(bytes.NewReader(m.R)), int64(len(m.R)), m.version, nil
+}
+
+func TestBlockMetaCache(t *testing.T) {
+	ctx := context.Background()
+	blockID := uuid.New()
+
+	meta := NewBlockMeta("test", blockID, "v2", EncGZIP, "")
+	meta.TotalObjects = 1
+	b, err := json.Marshal(meta)
+	require.NoError(t, err)
+
+	raw := &mockConditionalReader{version: "1"}
+	raw.R = b
+	r := NewReaderWithBlockMetaCache(raw, 10)
+
+	m, err := r.BlockMeta(ctx, blockID, "test")
+	require.NoError(t, err)
+	require.Equal(t, 1, raw.reads)
+	require.Equal(t, int64(1), m.TotalObjects)
+
+	// unchanged metas are revalidated without reading them again
+	m.TotalObjects = 100
+	m, err = r.BlockMeta(ctx, blockID, "test")
+	require.NoError(t, err)
+	require.Equal(t, 1, raw.reads)
+	require.Equal(t, int64(1), m.TotalObjects, "callers must not share cached metas")
+
+	// a new version is read
+	meta.TotalObjects = 2
+	raw.R, err = json.Marshal(meta)
+	require.NoError(t, err)
+	raw.version = "2"
+
+	m, err = r.BlockMeta(ctx, blockID, "test")
+	require.NoError(t, err)
+	require.Equal(t, 2, raw.reads)
+	require.Equal(t, int64(2), m.TotalObjects)
+
+	// deleted metas are evicted
+	raw.R = nil
+	_, err = r.BlockMeta(ctx, blockID, "test")
+	require.ErrorIs(t, err, ErrDoesNotExist)
+	require.Equal(t, 0, r.(*reader).metas.cache.Len())
+
+	// without a conditional reader the cache is disabled
+	require.Nil(t, NewReaderWithBlockMetaCache(&MockRawReader{}, 10).(*reader).metas)
+}
@@ -1,6 +1,7 @@
 package backend
 
 import (
+	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"testing"
@@ -90,12 +91,46 @@ func TestBlockMetaObjectAdded(t *testing.T) {
 	}
 }
 
+func TestBlockMetaErrorTimes(t *testing.T) {
+	now := time.Unix(0, time.Now().UnixNano())
+
+	b := &BlockMeta{ErrorTimesTracked: true}
+	require.False(t, b.MayContainErrorsInRange(now.Add(-time.Hour), now))
+
+	b.ErrorSpanAdded(uint64(now.Add(-10*time.Minute).UnixNano()), uint64(now.Add(-9*time.Minute).UnixNano()))
+	b.ErrorSpanAdded(uint64(now.Add(-30*time.Minute).UnixNano()), uint64(now.Add(-29*time.Minute).UnixNano()))
+	require.Equal(t, now.Add(-30*time.Minute), *b.ErrorStartTime)
+	require.Equal(t, now.Add(-9*time.Minute), *b.ErrorEndTime)
+
+	require.True(t, b.MayContainErrorsInRange(now.Add(-time.Hour), now))
+	require.True(t, b.MayContainErrorsInRange(now.Add(-20*time.Minute), now.Add(-19*time.Minute)))
+	require.True(t, b.MayContainErrorsInRange(now.Add(-9*time.Minute), now))
+	require.False(t, b.MayContainErrorsInRange(now.Add(-5*time.Minute), now))
+	require.False(t, b.MayContainErrorsInRange(now.Add(-2*time.Hour), now.Add(-time.Hour)))
+
+	// blocks without tracked error times may always contain errors
+	require.True(t, (&BlockMeta{}).MayContainErrorsInRange(now.Add(-5*time.Minute), now))
+
+	combined := &BlockMeta{}
+	combined.CombineErrorTimes([]*BlockMeta{b, {ErrorTimesTracked: true}})
+	require.True(t, combined.ErrorTimesTracked)
+	require.Equal(t, b.ErrorStartTime, combined.ErrorStartTime)
+	require.Equal(t, b.ErrorEndTime, combined.ErrorEndTime)
+
+	combined.CombineErrorTimes([]*BlockMeta{b, {}})
+	require.False(t, combined.ErrorTimesTracked)
+	require.Nil(t, combined.ErrorStartTime)
+	require.Nil(t, combined.ErrorEndTime)
+}
+
 func TestBlockMetaParsing(t *testing.T) {
 	timeParse := func(s string) time.Time {
 		date, err := time.Parse(time.RFC3339Nano, s)
 		require.NoError(t, err)
 		return date
 	}
+	errorStart := timeParse("2021-01-01T01:00:00.0000000Z")
+	errorEnd := timeParse("2021-01-01T02:00:00.0000000Z")
 
 	meta := BlockMeta{
 		Version:         "vParquet3",
@@ -117,6 +152,10 @@ func TestBlockMetaParsing(t *testing.T) {
 			{Scope: "span", Name: "http.method", Type: "string"},
 			{Scope: "span", Name: "namespace", Type: "string"},
 		},
+		RetentionClass:    "prod",
+		ErrorStartTime:    &errorStart,
+		ErrorEndTime:      &errorEnd,
+		ErrorTimesTracked: true,
 	}
 
 	expectedJSON := `{
@@ -138,7 +177,11 @@ func TestBlockMetaParsing(t *testing.T) {
     		{"s": "resource", "n": "namespace"},
     		{"n": "http.method"},
     		{"n": "namespace"}
-    	]
+    	],
+		"retentionClass": "prod",
+		"errorStartTime": "2021-01-01T01:00:00Z",
+		"errorEndTime": "2021-01-01T02:00:00Z",
+		"errorTimesTracked": true
 	}`
 
 	metaJSON, err := json.Marshal(meta)
@@ -254,3 +297,71 @@ func TestDedicatedColumns_ToTempopb(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockMetaTraceIDRange(t *testing.T) {
+	// blocks without a range may contain any trace
+	m := &BlockMeta{}
+	assert.True(t, m.MayContainTraceID([]byte{0x02}))
+
+	for _, id := range [][]byte{{0x05}, {0x03}, {0x08}, {0x04}} {
+		m.TraceIDRangeAdded(id)
+	}
+	assert.Equal(t, []byte{0x03}, m.MinID)
+	assert.Equal(t, []byte{0x08}, m.MaxID)
+
+	assert.False(t, m.MayContainTraceID([]byte{0x02}))
+	assert.True(t, m.MayContainTraceID([]byte{0x03}))
+	assert.True(t, m.MayContainTraceID([]byte{0x06}))
+	assert.True(t, m.MayContainTraceID([]byte{0x08}))
+	assert.False(t, m.MayContainTraceID([]byte{0x09}))
+
+	// the range goes through the proto and json encodings of the meta
+	b, err := m.Marshal()
+	require.NoError(t, err)
+	var fromProto BlockMeta
+	require.NoError(t, fromProto.Unmarshal(b))
+	assert.Equal(t, m.MinID, fromProto.MinID)
+	assert.Equal(t, m.MaxID, fromProto.MaxID)
+
+	b, err = json.Marshal(m)
+	require.NoError(t, err)
+	var fromJSON BlockMeta
+	require.NoError(t, json.Unmarshal(b, &fromJSON))
+	assert.Equal(t, m.MinID, fromJSON.MinID)
+	assert.Equal(t, m.MaxID, fromJSON.MaxID)
+}
+
+func TestBlockMetaTraceIDSummary(t *testing.T) {
+	// blocks without a summary may contain any trace
+	m := &BlockMeta{}
+	m.TraceIDAdded([]byte{0x01})
+	assert.Nil(t, m.TraceIDSummary)
+	assert.True(t, m.MayContainTraceID([]byte{0x02}))
+
+	randomID := func() []byte {
+		id := make([]byte, 16)
+		_, _ = rand.Read(id)
+		return id
+	}
+
+	m.TraceIDSummary = NewTraceIDSummary(1024)
+	ids := make([][]byte, 100)
+	for i := range ids {
+		ids[i] = randomID()
+		m.TraceIDAdded(ids[i])
+	}
+	for _, id := range ids {
+		assert.True(t, m.MayContainTraceID(id))
+	}
+
+	misses := 0
+	for i := 0; i < 1000; i++ {
+		if !m.MayContainTraceID(randomID()) {
+			misses++
+		}
+	}
+	// ~0.1% false positives for 3 hashes, 100 ids and 8192 bits
+	assert.Greater(t, misses, 950)
+
+	assert.Nil(t, NewTraceIDSummary(0))
+}
@@ -106,6 +106,17 @@ func (r *readerWriter) Read(ctx context.Context, name string, keypath backend.Ke
 	return io.NopCloser(bytes.NewReader(b)), size, err
 }
 
+// ReadIfChanged implements backend.ConditionalReader. Conditional reads are never cached. If the next reader does
+// not support them the object is always read and no version is returned.
+func (r *readerWriter) ReadIfChanged(ctx context.Context, name string, keypath backend.KeyPath, version backend.Version) (io.ReadCloser, int64, backend.Version, error) {
+	if cr, ok := r.nextReader.(backend.ConditionalReader); ok {
+		return cr.ReadIfChanged(ctx, name, keypath, version)
+	}
+
+	object, size, err := r.nextReader.Read(ctx, name, keypath, nil)
+	return object, size, "", err
+}
+
 // ReadRange implements backend.RawReader
 func (r *readerWriter) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, cacheInfo *backend.CacheInfo) error {
 	var k string
@@ -157,6 +168,20 @@ func (r *readerWriter) Append(ctx context.Context, name string, keypath backend.
 	return r.nextWriter.Append(ctx, name, keypath, tracker, buffer)
 }
 
+// ResumableAppend implements backend.ResumableAppender. If the next writer can't resume appends failed appends return
+// a nil checkpoint.
+func (r *readerWriter) ResumableAppend(ctx context.Context, name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
+	if ra, ok := r.nextWriter.(backend.ResumableAppender); ok {
+		return ra.ResumableAppend(ctx, name, keypath, tracker, buffer)
+	}
+
+	tracker, err := r.nextWriter.Append(ctx, name, keypath, tracker, buffer)
+	if err != nil {
+		return nil, err
+	}
+	return tracker, nil
+}
+
 // CloseAppend implements backend.Writer
 func (r *readerWriter) CloseAppend(ctx context.Context, tracker backend.AppendTracker) error {
 	return r.nextWriter.CloseAppend(ctx, tracker)
@@ -0,0 +1,207 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/pkg/util/log"
+)
+
+const (
+	ProviderFile              = "file"
+	ProviderVault             = "vault"
+	ProviderAWSSecretsManager = "aws_secrets_manager"
+
+	fetchTimeout = 30 * time.Second
+)
+
+var (
+	metricRefreshFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "backend_credentials_refresh_failures_total",
+		Help:      "Total number of failures to refresh the backend credentials from their provider.",
+	}, []string{"provider"})
+	metricRotations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "backend_credentials_rotations_total",
+		Help:      "Total number of times the backend credentials changed at their provider.",
+	}, []string{"provider"})
+)
+
+// Config configures the provider the credentials of a backend are fetched from instead of the static keys of its
+// config.
+type Config struct {
+	// Provider is one of file, vault or aws_secrets_manager. Empty uses the static keys of the backend config.
+	Provider string `yaml:"provider"`
+	// RefreshInterval is how often the credentials are fetched from the provider. Rotated credentials are used
+	// without a restart after at most one interval.
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+
+	File              FileConfig              `yaml:"file"`
+	Vault             VaultConfig             `yaml:"vault"`
+	AWSSecretsManager AWSSecretsManagerConfig `yaml:"aws_secrets_manager"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.Provider, util.PrefixConfig(prefix, "provider"), "", fmt.Sprintf("Provider of the backend credentials, one of %s, %s or %s. Empty uses the static credentials.", ProviderFile, ProviderVault, ProviderAWSSecretsManager))
+	f.DurationVar(&cfg.RefreshInterval, util.PrefixConfig(prefix, "refresh-interval"), 5*time.Minute, "How often the backend credentials are refreshed from their provider.")
+}
+
+// Enabled returns true if the credentials are fetched from a provider.
+func (cfg *Config) Enabled() bool {
+	return cfg != nil && cfg.Provider != ""
+}
+
+// Provider fetches the credentials of a backend from a secret store. The credentials are key values, the keys read by
+// a backend are documented with its configuration.
+type Provider interface {
+	Fetch(ctx context.Context) (map[string]string, error)
+}
+
+// NewProvider returns the provider of the config.
+func NewProvider(cfg Config) (Provider, error) {
+	switch cfg.Provider {
+	case ProviderFile:
+		return newFileProvider(cfg.File)
+	case ProviderVault:
+		return newVaultProvider(cfg.Vault)
+	case ProviderAWSSecretsManager:
+		return newAWSSecretsManagerProvider(cfg.AWSSecretsManager)
+	}
+	return nil, fmt.Errorf("unknown credentials provider %q", cfg.Provider)
+}
+
+// Credentials are the credentials of a backend, fetched from a provider and refreshed in the background. Backends
+// read them on each use or subscribe to their changes, so rotated credentials are picked up without a restart.
+type Credentials struct {
+	name     string
+	provider Provider
+
+	mtx      sync.RWMutex
+	values   map[string]string
+	version  uint64
+	onChange []func(map[string]string)
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// New fetches the credentials from the provider of the config and refreshes them every refresh interval. It returns
+// nil if the config has no provider, and an error if the first fetch fails.
+func New(cfg Config) (*Credentials, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	p, err := NewProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newCredentials(cfg.Provider, p, cfg.RefreshInterval)
+}
+
+func newCredentials(name string, p Provider, refreshInterval time.Duration) (*Credentials, error) {
+	c := &Credentials{
+		name:     name,
+		provider: p,
+		stop:     make(chan struct{}),
+	}
+
+	if err := c.refresh(); err != nil {
+		return nil, fmt.Errorf("failed to fetch credentials from %s: %w", name, err)
+	}
+
+	if refreshInterval > 0 {
+		go c.loop(refreshInterval)
+	}
+	return c, nil
+}
+
+func (c *Credentials) loop(refreshInterval time.Duration) {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// on failure the last credentials are kept, they may still be valid
+			if err := c.refresh(); err != nil {
+				metricRefreshFailures.WithLabelValues(c.name).Inc()
+				level.Error(log.Logger).Log("msg", "failed to refresh backend credentials", "provider", c.name, "err", err)
+			}
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+func (c *Credentials) refresh() error {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	values, err := c.provider.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		return errors.New("no credentials")
+	}
+
+	c.mtx.Lock()
+	if c.values != nil && maps.Equal(c.values, values) {
+		c.mtx.Unlock()
+		return nil
+	}
+	rotated := c.values != nil
+	c.values = values
+	c.version++
+	onChange := c.onChange
+	c.mtx.Unlock()
+
+	if rotated {
+		metricRotations.WithLabelValues(c.name).Inc()
+		level.Info(log.Logger).Log("msg", "backend credentials rotated", "provider", c.name)
+	}
+	for _, fn := range onChange {
+		fn(values)
+	}
+	return nil
+}
+
+// Get returns the credentials and their version, which changes each time they are rotated.
+func (c *Credentials) Get() (map[string]string, uint64) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.values, c.version
+}
+
+// Value returns the credential of the key.
+func (c *Credentials) Value(key string) string {
+	values, _ := c.Get()
+	return values[key]
+}
+
+// OnChange calls fn with the new credentials each time they are rotated.
+func (c *Credentials) OnChange(fn func(map[string]string)) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.onChange = append(c.onChange, fn)
+}
+
+// Stop stops refreshing the credentials.
+func (c *Credentials) Stop() {
+	if c == nil {
+		return
+	}
+	c.stopOnce.Do(func() { close(c.stop) })
+}
@@ -0,0 +1,162 @@
+package credentials
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialsRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("access_key: foo\nsecret_key: bar\n"), 0o600))
+
+	c, err := New(Config{Provider: ProviderFile, File: FileConfig{Path: path}})
+	require.NoError(t, err)
+	defer c.Stop()
+
+	values, version := c.Get()
+	require.Equal(t, map[string]string{"access_key": "foo", "secret_key": "bar"}, values)
+
+	var changed []map[string]string
+	c.OnChange(func(values map[string]string) { changed = append(changed, values) })
+	rotations := testutil.ToFloat64(metricRotations.WithLabelValues(ProviderFile))
+
+	// unchanged credentials aren't rotated
+	require.NoError(t, c.refresh())
+	_, v := c.Get()
+	require.Equal(t, version, v)
+	require.Empty(t, changed)
+
+	// the file is reloaded, JSON is YAML too
+	require.NoError(t, os.WriteFile(path, []byte(`{"access_key": "foo", "secret_key": "baz"}`), 0o600))
+	require.NoError(t, c.refresh())
+	_, v = c.Get()
+	require.NotEqual(t, version, v)
+	require.Equal(t, "baz", c.Value("secret_key"))
+	require.Equal(t, []map[string]string{{"access_key": "foo", "secret_key": "baz"}}, changed)
+	require.Equal(t, rotations+1, testutil.ToFloat64(metricRotations.WithLabelValues(ProviderFile)))
+
+	// the last credentials are kept if they can't be refreshed
+	require.NoError(t, os.Remove(path))
+	require.Error(t, c.refresh())
+	require.Equal(t, "baz", c.Value("secret_key"))
+}
+
+func TestNew(t *testing.T) {
+	c, err := New(Config{})
+	require.NoError(t, err)
+	require.Nil(t, c)
+	c.Stop()
+
+	_, err = New(Config{Provider: "unknown"})
+	require.EqualError(t, err, `unknown credentials provider "unknown"`)
+
+	_, err = New(Config{Provider: ProviderFile, File: FileConfig{Path: filepath.Join(t.TempDir(), "missing")}})
+	require.Error(t, err)
+}
+
+func TestVaultProvider(t *testing.T) {
+	secrets := map[string]string{
+		"/v1/secret/data/tempo": `{"data": {"data": {"access_key": "foo", "secret_key": "bar"}, "metadata": {"version": 3}}}`,
+		"/v1/kv/tempo":          `{"data": {"access_key": "foo", "secret_key": "baz"}}`,
+		"/v1/kv/invalid":        `{"data": {"access_key": 1}}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" || r.Header.Get("X-Vault-Namespace") != "ns" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		secret, ok := secrets[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(secret))
+	}))
+	defer srv.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("token\n"), 0o600))
+
+	for _, tc := range []struct {
+		name     string
+		cfg      VaultConfig
+		expected map[string]string
+		err      string
+	}{
+		{
+			name:     "kv v2",
+			cfg:      VaultConfig{Path: "secret/data/tempo", Token: flagext.SecretWithValue("token")},
+			expected: map[string]string{"access_key": "foo", "secret_key": "bar"},
+		},
+		{
+			name:     "kv v1 and token file",
+			cfg:      VaultConfig{Path: "/kv/tempo", TokenFile: tokenFile},
+			expected: map[string]string{"access_key": "foo", "secret_key": "baz"},
+		},
+		{
+			name: "forbidden",
+			cfg:  VaultConfig{Path: "kv/tempo", Token: flagext.SecretWithValue("wrong")},
+			err:  "unexpected status 403",
+		},
+		{
+			name: "not a string",
+			cfg:  VaultConfig{Path: "kv/invalid", Token: flagext.SecretWithValue("token")},
+			err:  "credential access_key isn't a string",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.cfg.Address = srv.URL
+			tc.cfg.Namespace = "ns"
+
+			p, err := newVaultProvider(tc.cfg)
+			require.NoError(t, err)
+
+			values, err := p.Fetch(t.Context())
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, values)
+		})
+	}
+}
+
+func TestAWSSecretsManagerProvider(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "id")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=id/") ||
+			!strings.Contains(r.Header.Get("Authorization"), "/eu-west-1/secretsmanager/") ||
+			string(body) != `{"SecretId":"tempo"}` {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		secret, _ := json.Marshal(map[string]string{"access_key": "foo", "secret_key": "bar"})
+		_ = json.NewEncoder(w).Encode(map[string]string{"SecretString": string(secret)})
+	}))
+	defer srv.Close()
+
+	p, err := newAWSSecretsManagerProvider(AWSSecretsManagerConfig{Region: "eu-west-1", SecretID: "tempo", Endpoint: srv.URL})
+	require.NoError(t, err)
+
+	values, err := p.Fetch(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"access_key": "foo", "secret_key": "bar"}, values)
+}
@@ -0,0 +1,234 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/grafana/dskit/flagext"
+	"gopkg.in/yaml.v2"
+)
+
+// FileConfig configures credentials read from a YAML or JSON file of key values, e.g. a mounted Kubernetes secret.
+type FileConfig struct {
+	Path string `yaml:"path"`
+}
+
+type fileProvider struct {
+	path string
+}
+
+func newFileProvider(cfg FileConfig) (*fileProvider, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("credentials file path is required")
+	}
+	return &fileProvider{path: cfg.Path}, nil
+}
+
+// Fetch implements Provider. The file is read again on each fetch so rewritten files are reloaded.
+func (p *fileProvider) Fetch(context.Context) (map[string]string, error) {
+	b, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	if err := yaml.Unmarshal(b, &values); err != nil {
+		return nil, fmt.Errorf("error parsing credentials file %s: %w", p.path, err)
+	}
+	return values, nil
+}
+
+// VaultConfig configures credentials read from a secret of HashiCorp Vault. Both the KV version 1 and 2 secrets
+// engines are supported, the path of a version 2 secret includes its data, e.g. secret/data/tempo.
+type VaultConfig struct {
+	Address string `yaml:"address"`
+	Path    string `yaml:"path"`
+	// Token authenticates to Vault. TokenFile is read on each fetch instead if set, e.g. the sink of a Vault agent
+	// that renews the token.
+	Token     flagext.Secret `yaml:"token"`
+	TokenFile string         `yaml:"token_file"`
+	Namespace string         `yaml:"namespace"`
+}
+
+type vaultProvider struct {
+	cfg    VaultConfig
+	client *http.Client
+}
+
+func newVaultProvider(cfg VaultConfig) (*vaultProvider, error) {
+	if cfg.Address == "" || cfg.Path == "" {
+		return nil, errors.New("vault address and path are required")
+	}
+	return &vaultProvider{cfg: cfg, client: &http.Client{}}, nil
+}
+
+// Fetch implements Provider
+func (p *vaultProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	token := p.cfg.Token.String()
+	if p.cfg.TokenFile != "" {
+		b, err := os.ReadFile(p.cfg.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading vault token: %w", err)
+		}
+		token = strings.TrimSpace(string(b))
+	}
+
+	url := strings.TrimSuffix(p.cfg.Address, "/") + "/v1/" + strings.TrimPrefix(p.cfg.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if p.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.cfg.Namespace)
+	}
+
+	body, err := do(p.client, req)
+	if err != nil {
+		return nil, fmt.Errorf("error reading vault secret %s: %w", p.cfg.Path, err)
+	}
+
+	var secret struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return nil, fmt.Errorf("error parsing vault secret %s: %w", p.cfg.Path, err)
+	}
+
+	// the data of KV version 2 secrets is nested with their metadata
+	data := secret.Data
+	if nested, ok := data["data"]; ok {
+		if _, ok := data["metadata"]; ok {
+			data = nil
+			if err := json.Unmarshal(nested, &data); err != nil {
+				return nil, fmt.Errorf("error parsing vault secret %s: %w", p.cfg.Path, err)
+			}
+		}
+	}
+	return stringValues(data)
+}
+
+// AWSSecretsManagerConfig configures credentials read from a secret of AWS Secrets Manager whose string is a JSON
+// object of key values. Tempo authenticates to Secrets Manager with the default AWS credential chain.
+type AWSSecretsManagerConfig struct {
+	Region   string `yaml:"region"`
+	SecretID string `yaml:"secret_id"`
+	// Endpoint overrides the endpoint of the region.
+	Endpoint string `yaml:"endpoint"`
+}
+
+type awsSecretsManagerProvider struct {
+	cfg      AWSSecretsManagerConfig
+	endpoint string
+	creds    aws.CredentialsProvider
+	signer   *v4.Signer
+	client   *http.Client
+}
+
+func newAWSSecretsManagerProvider(cfg AWSSecretsManagerConfig) (*awsSecretsManagerProvider, error) {
+	if cfg.Region == "" || cfg.SecretID == "" {
+		return nil, errors.New("aws secrets manager region and secret id are required")
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRegion(cfg.Region))
+	if err != nil {
+		return nil, fmt.Errorf("error loading aws config: %w", err)
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", cfg.Region)
+	}
+	return &awsSecretsManagerProvider{
+		cfg:      cfg,
+		endpoint: endpoint,
+		creds:    aws.NewCredentialsCache(awsCfg.Credentials),
+		signer:   v4.NewSigner(),
+		client:   &http.Client{},
+	}, nil
+}
+
+// Fetch implements Provider
+func (p *awsSecretsManagerProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	payload, err := json.Marshal(map[string]string{"SecretId": p.cfg.SecretID})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	creds, err := p.creds.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving aws credentials: %w", err)
+	}
+	hash := sha256.Sum256(payload)
+	if err := p.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "secretsmanager", p.cfg.Region, time.Now()); err != nil {
+		return nil, fmt.Errorf("error signing aws request: %w", err)
+	}
+
+	body, err := do(p.client, req)
+	if err != nil {
+		return nil, fmt.Errorf("error reading aws secret %s: %w", p.cfg.SecretID, err)
+	}
+
+	var secret struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return nil, fmt.Errorf("error parsing aws secret %s: %w", p.cfg.SecretID, err)
+	}
+
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(secret.SecretString), &data); err != nil {
+		return nil, fmt.Errorf("aws secret %s isn't a JSON object: %w", p.cfg.SecretID, err)
+	}
+	return stringValues(data)
+}
+
+func do(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// stringValues returns the string values of a secret, credentials that aren't strings are an error.
+func stringValues(data map[string]json.RawMessage) (map[string]string, error) {
+	values := make(map[string]string, len(data))
+	for k, raw := range data {
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("credential %s isn't a string", k)
+		}
+		values[k] = v
+	}
+	return values, nil
+}
@@ -0,0 +1,63 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	tempo_io "github.com/grafana/tempo/pkg/io"
+)
+
+// DedicatedColumnsRecommendation are the dedicated columns recommended for a tenant from the attributes of its
+// recent blocks. It is stored in the tenant path.
+type DedicatedColumnsRecommendation struct {
+	TenantID    string           `json:"tenant_id"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	Blocks      []UUID           `json:"blocks"`
+	Columns     DedicatedColumns `json:"columns"`
+	// Attributes are the largest attributes of each scope, recommended or not, largest first.
+	Attributes []AttributeSize `json:"attributes"`
+}
+
+// AttributeSize is the size of the string values of an attribute in the analysed blocks.
+type AttributeSize struct {
+	Scope DedicatedColumnScope `json:"scope"`
+	Name  string               `json:"name"`
+	Bytes uint64               `json:"bytes"`
+	// Share is the share of the attribute in the bytes of all attributes of its scope.
+	Share float64 `json:"share"`
+}
+
+// ReadDedicatedColumnsRecommendation reads the dedicated columns recommended for the tenant. ErrDoesNotExist is
+// returned if none has been generated.
+func ReadDedicatedColumnsRecommendation(ctx context.Context, r RawReader, tenantID string) (*DedicatedColumnsRecommendation, error) {
+	reader, size, err := r.Read(ctx, DedicatedColumnsRecommendationFileName, KeyPath{tenantID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	b, err := tempo_io.ReadAllWithEstimate(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &DedicatedColumnsRecommendation{}
+	err = json.Unmarshal(b, out)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// WriteDedicatedColumnsRecommendation writes the recommendation to the tenant path.
+func WriteDedicatedColumnsRecommendation(ctx context.Context, w RawWriter, rec *DedicatedColumnsRecommendation) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	return w.Write(ctx, DedicatedColumnsRecommendationFileName, KeyPath{rec.TenantID}, bytes.NewReader(b), int64(len(b)), nil)
+}
@@ -0,0 +1,1018 @@
+package backend
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/golang/groupcache/lru"
+	"github.com/google/uuid"
+)
+
+// ErrNoTenantKey is returned by a KMS for tenants without a key. The blocks of these tenants aren't encrypted.
+var ErrNoTenantKey = errors.New("no encryption key for tenant")
+
+// DataKeySize is the size of the data keys blocks are encrypted with, AES-256.
+const DataKeySize = 32
+
+// defaultDataKeyCacheSize is the number of unwrapped data keys kept in memory by the encrypted reader and writer.
+const defaultDataKeyCacheSize = 10000
+
+// KMS wraps the data keys of blocks with the keys of their tenant. Operators plug in their key management service to
+// keep the keys of the tenants out of Tempo. The keys of tenants are versioned, data keys are wrapped with the current
+// version of the key of their tenant and can be re-wrapped with a new version without rewriting the blocks.
+type KMS interface {
+	// GenerateDataKey returns a new data key for a block of the tenant, the data key wrapped with the current key of
+	// the tenant and the version of the key. It returns ErrNoTenantKey if the tenant has no key.
+	GenerateDataKey(ctx context.Context, tenantID string) (key []byte, wrapped []byte, version uint32, err error)
+	// WrapDataKey wraps the data key with the current key of the tenant and returns the version of the key.
+	WrapDataKey(ctx context.Context, tenantID string, key []byte) (wrapped []byte, version uint32, err error)
+	// UnwrapDataKey returns the data key wrapped with the version of the key of the tenant.
+	UnwrapDataKey(ctx context.Context, tenantID string, wrapped []byte, version uint32) ([]byte, error)
+	// KeyVersion returns the version of the current key of the tenant. It returns ErrNoTenantKey if the tenant has no
+	// key.
+	KeyVersion(ctx context.Context, tenantID string) (uint32, error)
+}
+
+// StaticKMS is a KMS wrapping data keys with AES-GCM with static tenant keys. The version of a key is derived from
+// the key, so rotated keys can be removed in any order.
+type StaticKMS struct {
+	tenants map[string][]staticKey
+}
+
+type staticKey struct {
+	aead    cipher.AEAD
+	version uint32
+}
+
+var _ KMS = (*StaticKMS)(nil)
+
+// NewStaticKMS returns a StaticKMS with the 256 bit keys of the tenants. The first key of a tenant is its current
+// key, the others are rotated keys that only unwrap data keys.
+func NewStaticKMS(keys map[string][][]byte) (*StaticKMS, error) {
+	tenants := make(map[string][]staticKey, len(keys))
+	for tenantID, tenantKeys := range keys {
+		if len(tenantKeys) == 0 {
+			return nil, fmt.Errorf("tenant %s has no key", tenantID)
+		}
+		versions := map[uint32]struct{}{}
+		for _, key := range tenantKeys {
+			if len(key) != DataKeySize {
+				return nil, fmt.Errorf("key of tenant %s must be %d bytes, got %d", tenantID, DataKeySize, len(key))
+			}
+			aead, err := newGCM(key)
+			if err != nil {
+				return nil, err
+			}
+			version := StaticKeyVersion(key)
+			if _, ok := versions[version]; ok {
+				return nil, fmt.Errorf("tenant %s has the key version %d more than once", tenantID, version)
+			}
+			versions[version] = struct{}{}
+			tenants[tenantID] = append(tenants[tenantID], staticKey{aead: aead, version: version})
+		}
+	}
+	return &StaticKMS{tenants: tenants}, nil
+}
+
+// StaticKeyVersion returns the version of a key of a StaticKMS, derived from the key. It's never 0, the version of
+// data keys wrapped before keys were versioned.
+func StaticKeyVersion(key []byte) uint32 {
+	h := sha256.Sum256(append([]byte("tempo-key-version:"), key...))
+	return max(binary.BigEndian.Uint32(h[:4]), 1)
+}
+
+// GenerateDataKey implements KMS
+func (k *StaticKMS) GenerateDataKey(ctx context.Context, tenantID string) ([]byte, []byte, uint32, error) {
+	key := make([]byte, DataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, 0, err
+	}
+	wrapped, version, err := k.WrapDataKey(ctx, tenantID, key)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return key, wrapped, version, nil
+}
+
+// WrapDataKey implements KMS
+func (k *StaticKMS) WrapDataKey(_ context.Context, tenantID string, key []byte) ([]byte, uint32, error) {
+	keys, ok := k.tenants[tenantID]
+	if !ok {
+		return nil, 0, ErrNoTenantKey
+	}
+
+	current := keys[0]
+	nonce := make([]byte, current.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, 0, err
+	}
+	return current.aead.Seal(nonce, nonce, key, []byte(tenantID)), current.version, nil
+}
+
+// UnwrapDataKey implements KMS. Data keys without a version are unwrapped with any key of the tenant.
+func (k *StaticKMS) UnwrapDataKey(_ context.Context, tenantID string, wrapped []byte, version uint32) ([]byte, error) {
+	keys, ok := k.tenants[tenantID]
+	if !ok {
+		return nil, ErrNoTenantKey
+	}
+
+	var err error
+	unwrapped := false
+	for _, key := range keys {
+		if version != 0 && key.version != version {
+			continue
+		}
+		unwrapped = true
+
+		aead := key.aead
+		if len(wrapped) < aead.NonceSize() {
+			return nil, errors.New("wrapped data key is too short")
+		}
+		nonce, ciphertext := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+		var dataKey []byte
+		if dataKey, err = aead.Open(nil, nonce, ciphertext, []byte(tenantID)); err == nil {
+			return dataKey, nil
+		}
+	}
+	if !unwrapped {
+		return nil, fmt.Errorf("key version %d of tenant %s is unknown", version, tenantID)
+	}
+	return nil, fmt.Errorf("error unwrapping data key: %w", err)
+}
+
+// KeyVersion implements KMS
+func (k *StaticKMS) KeyVersion(_ context.Context, tenantID string) (uint32, error) {
+	keys, ok := k.tenants[tenantID]
+	if !ok {
+		return 0, ErrNoTenantKey
+	}
+	return keys[0].version, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// dataKey is the key of a block. wrapped and the version of the tenant key it's wrapped with are stored in the meta
+// of the block, a nil aead means the block isn't encrypted.
+type dataKey struct {
+	aead    cipher.AEAD
+	wrapped []byte
+	version uint32
+}
+
+func newDataKey(key, wrapped []byte, version uint32) (*dataKey, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &dataKey{aead: aead, wrapped: wrapped, version: version}, nil
+}
+
+// Objects are encrypted with AES-GCM in chunks, so ranges of objects are decrypted without reading them from the
+// start. An encrypted object is a random nonce prefix followed by the sealed chunks. The nonce of a chunk is the
+// prefix and the index of the chunk, and the block ID and name of the object are authenticated with every chunk, so
+// chunks can't be moved within or between objects. All chunks but the last one have chunkDataSize bytes of
+// plaintext. The last one has less, possibly none, and is padded, so the position of every chunk is known without
+// the size of the object and an object cut at a chunk boundary is detected.
+const (
+	// objectNonceSize is the size of the random nonce prefix at the start of an encrypted object.
+	objectNonceSize = 8
+	// chunkDataSize is the size of the plaintext of the chunks objects are encrypted in.
+	chunkDataSize = 4096
+	// chunkLengthSize is the size of the length of the plaintext of a chunk.
+	chunkLengthSize = 2
+	// sealedChunkSize is the size of an encrypted chunk: the length and the padded plaintext, and the GCM tag.
+	sealedChunkSize = chunkLengthSize + chunkDataSize + 16
+)
+
+// errCorruptEncryptedObject is returned for encrypted objects that can't be decrypted.
+var errCorruptEncryptedObject = errors.New("encrypted object is corrupt")
+
+// sealedSize returns the size of an encrypted object with size bytes of plaintext.
+func sealedSize(size int64) int64 {
+	return objectNonceSize + (size/chunkDataSize+1)*sealedChunkSize
+}
+
+func objectAAD(blockID uuid.UUID, name string) []byte {
+	return append(blockID[:], name...)
+}
+
+func chunkNonce(prefix []byte, index uint32) []byte {
+	nonce := make([]byte, objectNonceSize+4)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[objectNonceSize:], index)
+	return nonce
+}
+
+// chunkSealer encrypts an object in chunks.
+type chunkSealer struct {
+	aead   cipher.AEAD
+	aad    []byte
+	prefix []byte
+	index  uint32
+	// header is the nonce prefix until it's returned with the first chunks
+	header []byte
+	buf    []byte
+}
+
+func (k *dataKey) newSealer(blockID uuid.UUID, name string) (*chunkSealer, error) {
+	prefix := make([]byte, objectNonceSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+	return &chunkSealer{
+		aead:   k.aead,
+		aad:    objectAAD(blockID, name),
+		prefix: prefix,
+		header: prefix,
+		buf:    make([]byte, 0, chunkDataSize),
+	}, nil
+}
+
+// write appends p to the plaintext of the object and the chunks that are complete to dst.
+func (s *chunkSealer) write(dst, p []byte) []byte {
+	dst = append(dst, s.header...)
+	s.header = nil
+
+	for len(p) > 0 {
+		n := min(chunkDataSize-len(s.buf), len(p))
+		s.buf = append(s.buf, p[:n]...)
+		p = p[n:]
+		if len(s.buf) == chunkDataSize {
+			dst = s.seal(dst, s.buf)
+			s.buf = s.buf[:0]
+		}
+	}
+	return dst
+}
+
+// close appends the last chunk of the object to dst.
+func (s *chunkSealer) close(dst []byte) []byte {
+	dst = append(dst, s.header...)
+	s.header = nil
+	return s.seal(dst, s.buf)
+}
+
+func (s *chunkSealer) seal(dst, data []byte) []byte {
+	payload := make([]byte, chunkLengthSize+chunkDataSize)
+	binary.BigEndian.PutUint16(payload, uint16(len(data)))
+	copy(payload[chunkLengthSize:], data)
+
+	dst = s.aead.Seal(dst, chunkNonce(s.prefix, s.index), payload, s.aad)
+	s.index++
+	return dst
+}
+
+// openChunk decrypts the chunk of an object with the index. It returns the plaintext of the chunk and true if it's the
+// last chunk of the object.
+func (k *dataKey) openChunk(aad, prefix []byte, index uint32, sealed []byte) ([]byte, bool, error) {
+	payload, err := k.aead.Open(nil, chunkNonce(prefix, index), sealed, aad)
+	if err != nil {
+		return nil, false, fmt.Errorf("error decrypting chunk %d: %w", index, errCorruptEncryptedObject)
+	}
+	n := int(binary.BigEndian.Uint16(payload))
+	if n > chunkDataSize {
+		return nil, false, fmt.Errorf("chunk %d has an invalid length %d: %w", index, n, errCorruptEncryptedObject)
+	}
+	return payload[chunkLengthSize : chunkLengthSize+n], n < chunkDataSize, nil
+}
+
+func (k *dataKey) sealObject(blockID uuid.UUID, name string, p []byte) ([]byte, error) {
+	s, err := k.newSealer(blockID, name)
+	if err != nil {
+		return nil, err
+	}
+	dst := make([]byte, 0, sealedSize(int64(len(p))))
+	return s.close(s.write(dst, p)), nil
+}
+
+func (k *dataKey) openObject(blockID uuid.UUID, name string, b []byte) ([]byte, error) {
+	if len(b) < objectNonceSize+sealedChunkSize || (len(b)-objectNonceSize)%sealedChunkSize != 0 {
+		return nil, fmt.Errorf("unexpected size %d: %w", len(b), errCorruptEncryptedObject)
+	}
+
+	aad := objectAAD(blockID, name)
+	prefix, chunks := b[:objectNonceSize], b[objectNonceSize:]
+	decrypted := make([]byte, 0, len(chunks)/sealedChunkSize*chunkDataSize)
+	for i := 0; len(chunks) > 0; i++ {
+		data, last, err := k.openChunk(aad, prefix, uint32(i), chunks[:sealedChunkSize])
+		if err != nil {
+			return nil, err
+		}
+		chunks = chunks[sealedChunkSize:]
+		if last != (len(chunks) == 0) {
+			return nil, fmt.Errorf("chunk %d is out of place: %w", i, errCorruptEncryptedObject)
+		}
+		decrypted = append(decrypted, data...)
+	}
+	return decrypted, nil
+}
+
+// sealingReader encrypts the objects of a reader.
+type sealingReader struct {
+	s    *chunkSealer
+	r    io.Reader
+	buf  []byte
+	out  []byte
+	done bool
+}
+
+func (r *sealingReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		n, err := io.ReadFull(r.r, r.buf)
+		switch {
+		case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+			r.out = r.s.close(r.s.write(r.out, r.buf[:n]))
+			r.done = true
+		case err != nil:
+			return 0, err
+		default:
+			r.out = r.s.write(r.out, r.buf[:n])
+		}
+	}
+
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+// sealingWriteCloser encrypts the objects written to a writer. The last chunk is written on Close.
+type sealingWriteCloser struct {
+	s *chunkSealer
+	w io.WriteCloser
+}
+
+func (w *sealingWriteCloser) Write(p []byte) (int, error) {
+	if out := w.s.write(nil, p); len(out) > 0 {
+		if _, err := w.w.Write(out); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *sealingWriteCloser) Close() error {
+	if _, err := w.w.Write(w.s.close(nil)); err != nil {
+		_ = w.w.Close()
+		return err
+	}
+	return w.w.Close()
+}
+
+// openingReadCloser decrypts the encrypted objects of a reader.
+type openingReadCloser struct {
+	k      *dataKey
+	aad    []byte
+	prefix []byte
+	rc     io.ReadCloser
+	index  uint32
+	buf    []byte
+	out    []byte
+	last   bool
+}
+
+func (r *openingReadCloser) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		n, err := io.ReadFull(r.rc, r.buf)
+		switch {
+		case errors.Is(err, io.EOF) && r.last:
+			return 0, io.EOF
+		case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+			return 0, fmt.Errorf("object ends after %d chunks: %w", r.index, errCorruptEncryptedObject)
+		case err != nil:
+			return 0, err
+		case r.last:
+			return 0, fmt.Errorf("chunk %d is out of place: %w", r.index, errCorruptEncryptedObject)
+		}
+
+		r.out, r.last, err = r.k.openChunk(r.aad, r.prefix, r.index, r.buf[:n])
+		if err != nil {
+			return 0, err
+		}
+		r.index++
+	}
+
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+func (r *openingReadCloser) Close() error {
+	return r.rc.Close()
+}
+
+const (
+	// maxPendingDataKeys is the number of blocks written at the same time with a data key and without a meta.
+	maxPendingDataKeys = 10000
+	// pendingDataKeyIdleTimeout is the time after which the key of a block that is written is dropped if none of its
+	// objects are written, because writing the block failed. It's well above the time between the objects of a block.
+	pendingDataKeyIdleTimeout = time.Hour
+)
+
+// encryption resolves the data keys of blocks. Metas aren't encrypted, the data key of a block is unwrapped from its
+// meta when the block is read.
+type encryption struct {
+	kms KMS
+	r   Reader
+
+	mtx sync.Mutex
+	// pending are the keys of blocks that are being written and don't have a meta yet
+	pending map[blockKey]*pendingDataKey
+	keys    *lru.Cache
+	// nonces are the nonce prefixes of the objects read by range
+	nonces *lru.Cache
+
+	maxPending  int
+	idleTimeout time.Duration
+	now         func() time.Time
+}
+
+type blockKey struct {
+	blockID  uuid.UUID
+	tenantID string
+}
+
+type objectKey struct {
+	blockKey
+	name string
+}
+
+type pendingDataKey struct {
+	k    *dataKey
+	used time.Time
+}
+
+func newEncryption(r Reader, kms KMS) *encryption {
+	return &encryption{
+		kms:         kms,
+		r:           r,
+		pending:     map[blockKey]*pendingDataKey{},
+		keys:        lru.New(defaultDataKeyCacheSize),
+		nonces:      lru.New(defaultDataKeyCacheSize),
+		maxPending:  maxPendingDataKeys,
+		idleTimeout: pendingDataKeyIdleTimeout,
+		now:         time.Now,
+	}
+}
+
+func (e *encryption) cached(b blockKey) (*dataKey, bool) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	if p, ok := e.pending[b]; ok {
+		p.used = e.now()
+		return p.k, true
+	}
+	if k, ok := e.keys.Get(b); ok {
+		return k.(*dataKey), true
+	}
+	return nil, false
+}
+
+// key returns the data key of an existing block. It returns false if the block has no meta.
+func (e *encryption) key(ctx context.Context, blockID uuid.UUID, tenantID string, cacheInfo *CacheInfo) (*dataKey, bool, error) {
+	b := blockKey{blockID: blockID, tenantID: tenantID}
+	if k, ok := e.cached(b); ok {
+		return k, true, nil
+	}
+
+	var k *dataKey
+	if cacheInfo != nil && cacheInfo.Meta != nil && len(cacheInfo.Meta.EncryptionKey) > 0 {
+		var err error
+		if k, err = e.unwrap(ctx, cacheInfo.Meta); err != nil {
+			// the data key may have been re-wrapped with a newer key of the tenant since the block was listed, so
+			// the meta is read from the backend
+			k = nil
+		}
+	}
+	if k == nil {
+		meta, err := e.blockMeta(ctx, blockID, tenantID)
+		if errors.Is(err, ErrDoesNotExist) {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("error reading meta of block %s: %w", blockID, err)
+		}
+		if k, err = e.unwrap(ctx, meta); err != nil {
+			return nil, false, err
+		}
+	}
+
+	e.mtx.Lock()
+	e.keys.Add(b, k)
+	e.mtx.Unlock()
+	return k, true, nil
+}
+
+func (e *encryption) unwrap(ctx context.Context, meta *BlockMeta) (*dataKey, error) {
+	if len(meta.EncryptionKey) == 0 {
+		return &dataKey{}, nil
+	}
+
+	key, err := e.kms.UnwrapDataKey(ctx, meta.TenantID, meta.EncryptionKey, meta.EncryptionKeyVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error unwrapping data key of block %s: %w", meta.BlockID, err)
+	}
+	return newDataKey(key, meta.EncryptionKey, meta.EncryptionKeyVersion)
+}
+
+// blockMeta returns the meta of a block from the backend, the meta of compacted blocks is read to decrypt blocks
+// until they're cleared.
+func (e *encryption) blockMeta(ctx context.Context, blockID uuid.UUID, tenantID string) (*BlockMeta, error) {
+	b, err := e.r.Read(ctx, MetaName, blockID, tenantID, nil)
+	if err == nil {
+		meta := &BlockMeta{}
+		if err := json.Unmarshal(b, meta); err != nil {
+			return nil, err
+		}
+		return meta, nil
+	}
+	if !errors.Is(err, ErrDoesNotExist) {
+		return nil, err
+	}
+
+	b, err = e.r.Read(ctx, CompactedMetaName, blockID, tenantID, nil)
+	if err != nil {
+		return nil, err
+	}
+	compacted := &CompactedBlockMeta{}
+	if err := json.Unmarshal(b, compacted); err != nil {
+		return nil, err
+	}
+	return &compacted.BlockMeta, nil
+}
+
+// keyForWrite returns the data key of a block that is written. Blocks that exist keep their key, a new key is
+// generated for new blocks.
+func (e *encryption) keyForWrite(ctx context.Context, blockID uuid.UUID, tenantID string) (*dataKey, error) {
+	k, ok, err := e.key(ctx, blockID, tenantID, nil)
+	if err != nil || ok {
+		return k, err
+	}
+
+	// hold the lock while generating the key, so the objects of a block written concurrently share its key
+	b := blockKey{blockID: blockID, tenantID: tenantID}
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	now := e.now()
+	if p, ok := e.pending[b]; ok {
+		p.used = now
+		return p.k, nil
+	}
+
+	for pb, p := range e.pending {
+		if now.Sub(p.used) > e.idleTimeout {
+			delete(e.pending, pb)
+		}
+	}
+	if len(e.pending) >= e.maxPending {
+		return nil, fmt.Errorf("error generating data key of block %s: %d blocks are written without a meta", blockID, len(e.pending))
+	}
+
+	key, wrapped, version, err := e.kms.GenerateDataKey(ctx, tenantID)
+	switch {
+	case errors.Is(err, ErrNoTenantKey):
+		k = &dataKey{}
+	case err != nil:
+		return nil, fmt.Errorf("error generating data key of block %s: %w", blockID, err)
+	default:
+		if k, err = newDataKey(key, wrapped, version); err != nil {
+			return nil, err
+		}
+	}
+	e.pending[b] = &pendingDataKey{k: k, used: now}
+	return k, nil
+}
+
+// written records that the meta of the block was written with its key.
+func (e *encryption) written(blockID uuid.UUID, tenantID string, k *dataKey) {
+	b := blockKey{blockID: blockID, tenantID: tenantID}
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	delete(e.pending, b)
+	e.keys.Add(b, k)
+}
+
+// nonce returns the nonce prefix of an object read by range.
+func (e *encryption) nonce(o objectKey) ([]byte, bool) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	prefix, ok := e.nonces.Get(o)
+	if !ok {
+		return nil, false
+	}
+	return prefix.([]byte), true
+}
+
+func (e *encryption) setNonce(o objectKey, prefix []byte) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	e.nonces.Add(o, prefix)
+}
+
+// EncryptedWriter is a Writer encrypting the data objects of blocks with a data key per block. The data key is
+// wrapped with the key of the tenant by the KMS and stored in the meta of the block. Metas and flags aren't
+// encrypted. Blocks of tenants without a key are written unencrypted.
+type EncryptedWriter struct {
+	Writer
+	e *encryption
+}
+
+var _ Writer = (*EncryptedWriter)(nil)
+
+// NewEncryptedReaderWriter returns an EncryptedReader reading from r and an EncryptedWriter writing to w. They share
+// the data keys, so blocks are read back with their key while they're being written. The metas of existing blocks
+// are read from r to keep their key when objects are written to them.
+func NewEncryptedReaderWriter(r Reader, w Writer, kms KMS) (*EncryptedReader, *EncryptedWriter) {
+	e := newEncryption(r, kms)
+	return &EncryptedReader{Reader: r, e: e}, &EncryptedWriter{Writer: w, e: e}
+}
+
+func (w *EncryptedWriter) objectKey(ctx context.Context, name string, blockID uuid.UUID, tenantID string) (*dataKey, error) {
+	if !IsBlockDataObject(name, KeyPathForBlock(blockID, tenantID)) {
+		return nil, nil
+	}
+	k, err := w.e.keyForWrite(ctx, blockID, tenantID)
+	if err != nil || k.aead == nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Write implements Writer
+func (w *EncryptedWriter) Write(ctx context.Context, name string, blockID uuid.UUID, tenantID string, buffer []byte, cacheInfo *CacheInfo) error {
+	k, err := w.objectKey(ctx, name, blockID, tenantID)
+	if err != nil {
+		return err
+	}
+	if k != nil {
+		if buffer, err = k.sealObject(blockID, name, buffer); err != nil {
+			return err
+		}
+	}
+	return w.Writer.Write(ctx, name, blockID, tenantID, buffer, cacheInfo)
+}
+
+// StreamWriter implements Writer
+func (w *EncryptedWriter) StreamWriter(ctx context.Context, name string, blockID uuid.UUID, tenantID string, data io.Reader, size int64) error {
+	k, err := w.objectKey(ctx, name, blockID, tenantID)
+	if err != nil {
+		return err
+	}
+	if k != nil {
+		s, err := k.newSealer(blockID, name)
+		if err != nil {
+			return err
+		}
+		data = &sealingReader{s: s, r: data, buf: make([]byte, chunkDataSize)}
+		size = sealedSize(size)
+	}
+	return w.Writer.StreamWriter(ctx, name, blockID, tenantID, data, size)
+}
+
+// WriteBlockMeta implements Writer. The wrapped data key of the block is stored in the meta.
+func (w *EncryptedWriter) WriteBlockMeta(ctx context.Context, meta *BlockMeta) error {
+	blockID := (uuid.UUID)(meta.BlockID)
+	k, err := w.e.keyForWrite(ctx, blockID, meta.TenantID)
+	if err != nil {
+		return err
+	}
+
+	m := *meta
+	m.EncryptionKey = k.wrapped
+	m.EncryptionKeyVersion = k.version
+	if err := w.Writer.WriteBlockMeta(ctx, &m); err != nil {
+		return err
+	}
+	w.e.written(blockID, meta.TenantID, k)
+	return nil
+}
+
+// RewrapDataKey wraps the data key of an encrypted block with the current key of its tenant and writes its meta. The
+// data of the block isn't rewritten. It returns the meta of the block, that is unchanged if the block isn't encrypted
+// or its data key is already wrapped with the current key. Blocks of tenants without a key are unchanged as well, so
+// their keys can still be re-wrapped if the key of the tenant is only removed by mistake.
+func (w *EncryptedWriter) RewrapDataKey(ctx context.Context, meta *BlockMeta) (*BlockMeta, error) {
+	if len(meta.EncryptionKey) == 0 {
+		return meta, nil
+	}
+
+	current, err := w.e.kms.KeyVersion(ctx, meta.TenantID)
+	if errors.Is(err, ErrNoTenantKey) {
+		return meta, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if meta.EncryptionKeyVersion == current {
+		return meta, nil
+	}
+
+	key, err := w.e.kms.UnwrapDataKey(ctx, meta.TenantID, meta.EncryptionKey, meta.EncryptionKeyVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error unwrapping data key of block %s: %w", meta.BlockID, err)
+	}
+	wrapped, version, err := w.e.kms.WrapDataKey(ctx, meta.TenantID, key)
+	if err != nil {
+		return nil, fmt.Errorf("error wrapping data key of block %s: %w", meta.BlockID, err)
+	}
+	k, err := newDataKey(key, wrapped, version)
+	if err != nil {
+		return nil, err
+	}
+
+	m := *meta
+	m.EncryptionKey = wrapped
+	m.EncryptionKeyVersion = version
+	if err := w.Writer.WriteBlockMeta(ctx, &m); err != nil {
+		return nil, err
+	}
+	w.e.written((uuid.UUID)(meta.BlockID), meta.TenantID, k)
+	return &m, nil
+}
+
+// RewrapDataKeyForTenant returns the data key of an encrypted block wrapped with the current key of another tenant
+// and the version of the key, for blocks copied to that tenant. Objects are authenticated with their block ID and name
+// only, so the data of the block is copied as is. It returns ErrNoTenantKey if either tenant has no key.
+func RewrapDataKeyForTenant(ctx context.Context, kms KMS, meta *BlockMeta, tenantID string) ([]byte, uint32, error) {
+	key, err := kms.UnwrapDataKey(ctx, meta.TenantID, meta.EncryptionKey, meta.EncryptionKeyVersion)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error unwrapping data key of block %s: %w", meta.BlockID, err)
+	}
+	wrapped, version, err := kms.WrapDataKey(ctx, tenantID, key)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error wrapping data key of block %s for tenant %s: %w", meta.BlockID, tenantID, err)
+	}
+	return wrapped, version, nil
+}
+
+// encryptedAppendTracker holds the plaintext of an appended object that isn't a complete chunk yet. The last chunk
+// is appended by CloseAppend.
+type encryptedAppendTracker struct {
+	tracker  AppendTracker
+	s        *chunkSealer
+	name     string
+	blockID  uuid.UUID
+	tenantID string
+}
+
+// Append implements Writer
+func (w *EncryptedWriter) Append(ctx context.Context, name string, blockID uuid.UUID, tenantID string, tracker AppendTracker, buffer []byte) (AppendTracker, error) {
+	k, err := w.objectKey(ctx, name, blockID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if k == nil {
+		return w.Writer.Append(ctx, name, blockID, tenantID, tracker, buffer)
+	}
+
+	var et *encryptedAppendTracker
+	if tracker != nil {
+		var ok bool
+		if et, ok = tracker.(*encryptedAppendTracker); !ok {
+			return nil, fmt.Errorf("unexpected append tracker %T", tracker)
+		}
+	} else {
+		s, err := k.newSealer(blockID, name)
+		if err != nil {
+			return nil, err
+		}
+		et = &encryptedAppendTracker{s: s, name: name, blockID: blockID, tenantID: tenantID}
+	}
+
+	if encrypted := et.s.write(nil, buffer); len(encrypted) > 0 {
+		if et.tracker, err = w.Writer.Append(ctx, name, blockID, tenantID, et.tracker, encrypted); err != nil {
+			return nil, err
+		}
+	}
+	return et, nil
+}
+
+// CloseAppend implements Writer
+func (w *EncryptedWriter) CloseAppend(ctx context.Context, tracker AppendTracker) error {
+	et, ok := tracker.(*encryptedAppendTracker)
+	if !ok {
+		return w.Writer.CloseAppend(ctx, tracker)
+	}
+
+	tracker, err := w.Writer.Append(ctx, et.name, et.blockID, et.tenantID, et.tracker, et.s.close(nil))
+	if err != nil {
+		return err
+	}
+	return w.Writer.CloseAppend(ctx, tracker)
+}
+
+// ResumableStreamWriter implements Writer
+func (w *EncryptedWriter) ResumableStreamWriter(ctx context.Context, name string, blockID uuid.UUID, tenantID string) io.WriteCloser {
+	k, err := w.objectKey(ctx, name, blockID, tenantID)
+	if err != nil {
+		return errWriteCloser{err: err}
+	}
+	var s *chunkSealer
+	if k != nil {
+		if s, err = k.newSealer(blockID, name); err != nil {
+			return errWriteCloser{err: err}
+		}
+	}
+
+	wc := w.Writer.ResumableStreamWriter(ctx, name, blockID, tenantID)
+	if s == nil {
+		return wc
+	}
+	return &sealingWriteCloser{s: s, w: wc}
+}
+
+// errWriteCloser fails all writes with the error of the writer it replaces.
+type errWriteCloser struct {
+	err error
+}
+
+func (w errWriteCloser) Write([]byte) (int, error) { return 0, w.err }
+
+func (w errWriteCloser) Close() error { return w.err }
+
+// EncryptedReader is a Reader decrypting the data objects of blocks written by an EncryptedWriter. Data keys are
+// unwrapped from the meta in the cache info of the read, or from the meta of the block in the backend.
+type EncryptedReader struct {
+	Reader
+	e *encryption
+}
+
+var _ Reader = (*EncryptedReader)(nil)
+
+func (r *EncryptedReader) objectKey(ctx context.Context, name string, blockID uuid.UUID, tenantID string, cacheInfo *CacheInfo) (*dataKey, error) {
+	if !IsBlockDataObject(name, KeyPathForBlock(blockID, tenantID)) {
+		return nil, nil
+	}
+	k, _, err := r.e.key(ctx, blockID, tenantID, cacheInfo)
+	if err != nil || k == nil || k.aead == nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Read implements Reader
+func (r *EncryptedReader) Read(ctx context.Context, name string, blockID uuid.UUID, tenantID string, cacheInfo *CacheInfo) ([]byte, error) {
+	k, err := r.objectKey(ctx, name, blockID, tenantID, cacheInfo)
+	if err != nil {
+		return nil, err
+	}
+	b, err := r.Reader.Read(ctx, name, blockID, tenantID, cacheInfo)
+	if err != nil || k == nil {
+		return b, err
+	}
+
+	decrypted, err := k.openObject(blockID, name, b)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting %s of block %s: %w", name, blockID, err)
+	}
+	return decrypted, nil
+}
+
+// StreamReader implements Reader. The size of the plaintext is read from the last chunk of the object.
+func (r *EncryptedReader) StreamReader(ctx context.Context, name string, blockID uuid.UUID, tenantID string) (io.ReadCloser, int64, error) {
+	k, err := r.objectKey(ctx, name, blockID, tenantID, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	rc, size, err := r.Reader.StreamReader(ctx, name, blockID, tenantID)
+	if err != nil || k == nil {
+		return rc, size, err
+	}
+
+	plainSize, prefix, err := r.plaintextSize(ctx, k, name, blockID, tenantID, rc, size)
+	if err != nil {
+		_ = rc.Close()
+		return nil, 0, fmt.Errorf("error decrypting %s of block %s: %w", name, blockID, err)
+	}
+	return &openingReadCloser{
+		k:      k,
+		aad:    objectAAD(blockID, name),
+		prefix: prefix,
+		rc:     rc,
+		buf:    make([]byte, sealedChunkSize),
+	}, plainSize, nil
+}
+
+// plaintextSize reads the nonce prefix from the start of an encrypted object and returns it with the size of the
+// plaintext of the object.
+func (r *EncryptedReader) plaintextSize(ctx context.Context, k *dataKey, name string, blockID uuid.UUID, tenantID string, rc io.Reader, size int64) (int64, []byte, error) {
+	if size < objectNonceSize+sealedChunkSize || (size-objectNonceSize)%sealedChunkSize != 0 {
+		return 0, nil, fmt.Errorf("unexpected size %d: %w", size, errCorruptEncryptedObject)
+	}
+
+	prefix := make([]byte, objectNonceSize)
+	if _, err := io.ReadFull(rc, prefix); err != nil {
+		return 0, nil, err
+	}
+
+	chunks := (size - objectNonceSize) / sealedChunkSize
+	sealed := make([]byte, sealedChunkSize)
+	if err := r.Reader.ReadRange(ctx, name, blockID, tenantID, uint64(objectNonceSize+(chunks-1)*sealedChunkSize), sealed, nil); err != nil {
+		return 0, nil, err
+	}
+	data, last, err := k.openChunk(objectAAD(blockID, name), prefix, uint32(chunks-1), sealed)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !last {
+		return 0, nil, fmt.Errorf("object ends after %d chunks: %w", chunks, errCorruptEncryptedObject)
+	}
+	return (chunks-1)*chunkDataSize + int64(len(data)), prefix, nil
+}
+
+// ReadRange implements Reader. The chunks of the range are read and decrypted, the nonce prefix of the object is
+// read once and kept in memory.
+func (r *EncryptedReader) ReadRange(ctx context.Context, name string, blockID uuid.UUID, tenantID string, offset uint64, buffer []byte, cacheInfo *CacheInfo) error {
+	k, err := r.objectKey(ctx, name, blockID, tenantID, cacheInfo)
+	if err != nil {
+		return err
+	}
+	if k == nil || len(buffer) == 0 {
+		return r.Reader.ReadRange(ctx, name, blockID, tenantID, offset, buffer, cacheInfo)
+	}
+
+	first := offset / chunkDataSize
+	last := (offset + uint64(len(buffer)) - 1) / chunkDataSize
+	sealed := make([]byte, (last-first+1)*sealedChunkSize)
+	if err := r.Reader.ReadRange(ctx, name, blockID, tenantID, objectNonceSize+first*sealedChunkSize, sealed, cacheInfo); err != nil {
+		return err
+	}
+
+	o := objectKey{blockKey: blockKey{blockID: blockID, tenantID: tenantID}, name: name}
+	prefix, cached := r.e.nonce(o)
+	if !cached {
+		if prefix, err = r.readNonce(ctx, o); err != nil {
+			return err
+		}
+	}
+
+	err = r.openRange(k, o, prefix, first, sealed, offset, buffer)
+	if err != nil && cached && errors.Is(err, errCorruptEncryptedObject) {
+		// the object may have been rewritten with another nonce
+		if prefix, err = r.readNonce(ctx, o); err != nil {
+			return err
+		}
+		err = r.openRange(k, o, prefix, first, sealed, offset, buffer)
+	}
+	if err != nil {
+		return fmt.Errorf("error decrypting %s of block %s: %w", name, blockID, err)
+	}
+	return nil
+}
+
+func (r *EncryptedReader) readNonce(ctx context.Context, o objectKey) ([]byte, error) {
+	prefix := make([]byte, objectNonceSize)
+	if err := r.Reader.ReadRange(ctx, o.name, o.blockID, o.tenantID, 0, prefix, nil); err != nil {
+		return nil, err
+	}
+	r.e.setNonce(o, prefix)
+	return prefix, nil
+}
+
+// openRange decrypts the chunks of the object starting with the chunk first and copies the plaintext at the offset
+// to the buffer.
+func (r *EncryptedReader) openRange(k *dataKey, o objectKey, prefix []byte, first uint64, sealed []byte, offset uint64, buffer []byte) error {
+	aad := objectAAD(o.blockID, o.name)
+	skip := offset - first*chunkDataSize
+	n := 0
+	for i := first; n < len(buffer); i++ {
+		data, last, err := k.openChunk(aad, prefix, uint32(i), sealed[:sealedChunkSize])
+		if err != nil {
+			return err
+		}
+		sealed = sealed[sealedChunkSize:]
+
+		if skip >= uint64(len(data)) && last {
+			return fmt.Errorf("range at %d is past the end of the object: %w", offset, io.ErrUnexpectedEOF)
+		}
+		n += copy(buffer[n:], data[skip:])
+		skip = 0
+		if last && n < len(buffer) {
+			return fmt.Errorf("range at %d is past the end of the object: %w", offset, io.ErrUnexpectedEOF)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,392 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticKMS(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewStaticKMS(map[string][][]byte{"a": {make([]byte, 16)}})
+	require.Error(t, err)
+
+	kms, err := NewStaticKMS(map[string][][]byte{"a": {testTenantKey(t)}, "b": {testTenantKey(t)}})
+	require.NoError(t, err)
+
+	key, wrapped, version, err := kms.GenerateDataKey(ctx, "a")
+	require.NoError(t, err)
+	require.Len(t, key, DataKeySize)
+	require.NotContains(t, string(wrapped), string(key))
+	current, err := kms.KeyVersion(ctx, "a")
+	require.NoError(t, err)
+	require.Equal(t, current, version)
+	require.NotZero(t, version)
+
+	unwrapped, err := kms.UnwrapDataKey(ctx, "a", wrapped, version)
+	require.NoError(t, err)
+	require.Equal(t, key, unwrapped)
+
+	// data keys are bound to their tenant
+	_, err = kms.UnwrapDataKey(ctx, "b", wrapped, version)
+	require.Error(t, err)
+
+	_, _, _, err = kms.GenerateDataKey(ctx, "c")
+	require.ErrorIs(t, err, ErrNoTenantKey)
+	_, err = kms.KeyVersion(ctx, "c")
+	require.ErrorIs(t, err, ErrNoTenantKey)
+
+	// data keys are wrapped with the current key of the tenant and unwrapped with the key of their version
+	oldKey, newKey := testTenantKey(t), testTenantKey(t)
+	_, err = NewStaticKMS(map[string][][]byte{"a": {oldKey, oldKey}})
+	require.Error(t, err)
+
+	kms, err = NewStaticKMS(map[string][][]byte{"a": {oldKey}})
+	require.NoError(t, err)
+	_, wrapped, version, err = kms.GenerateDataKey(ctx, "a")
+	require.NoError(t, err)
+	require.Equal(t, StaticKeyVersion(oldKey), version)
+
+	rotated, err := NewStaticKMS(map[string][][]byte{"a": {newKey, oldKey}})
+	require.NoError(t, err)
+	current, err = rotated.KeyVersion(ctx, "a")
+	require.NoError(t, err)
+	require.Equal(t, StaticKeyVersion(newKey), current)
+
+	key, err = rotated.UnwrapDataKey(ctx, "a", wrapped, version)
+	require.NoError(t, err)
+	_, err = rotated.UnwrapDataKey(ctx, "a", wrapped, current)
+	require.Error(t, err)
+	_, err = rotated.UnwrapDataKey(ctx, "a", wrapped, 1)
+	require.Error(t, err)
+	// data keys wrapped before keys were versioned are unwrapped with any key of the tenant
+	unwrapped, err = rotated.UnwrapDataKey(ctx, "a", wrapped, 0)
+	require.NoError(t, err)
+	require.Equal(t, key, unwrapped)
+
+	rewrapped, version, err := rotated.WrapDataKey(ctx, "a", key)
+	require.NoError(t, err)
+	require.Equal(t, current, version)
+	unwrapped, err = rotated.UnwrapDataKey(ctx, "a", rewrapped, version)
+	require.NoError(t, err)
+	require.Equal(t, key, unwrapped)
+}
+
+func TestEncryptedReaderWriter(t *testing.T) {
+	ctx := context.Background()
+	kms, err := NewStaticKMS(map[string][][]byte{"encrypted": {testTenantKey(t)}})
+	require.NoError(t, err)
+
+	raw := &memoryBackend{objects: map[string][]byte{}}
+	plainR, plainW := NewReader(raw), NewWriter(raw)
+	r, w := NewEncryptedReaderWriter(plainR, plainW, kms)
+
+	// the data spans multiple chunks
+	data := bytes.Repeat([]byte("0123456789abcdef-"), 1000)
+	writeBlock := func(tenantID string) *BlockMeta {
+		meta := NewBlockMeta(tenantID, uuid.New(), "v2", EncNone, "")
+		blockID := (uuid.UUID)(meta.BlockID)
+
+		require.NoError(t, w.Write(ctx, "write", blockID, tenantID, data, nil))
+		require.NoError(t, w.StreamWriter(ctx, "stream", blockID, tenantID, bytes.NewReader(data), int64(len(data))))
+
+		var tracker AppendTracker
+		for _, chunk := range [][]byte{data[:7], data[7:500], data[500:9000], data[9000:]} {
+			tracker, err = w.Append(ctx, "append", blockID, tenantID, tracker, chunk)
+			require.NoError(t, err)
+		}
+		require.NoError(t, w.CloseAppend(ctx, tracker))
+
+		wc := w.ResumableStreamWriter(ctx, "resumable", blockID, tenantID)
+		_, err = wc.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+
+		require.NoError(t, w.WriteBlockMeta(ctx, meta))
+		require.NoError(t, w.WriteNoCompactFlag(ctx, blockID, tenantID))
+		return meta
+	}
+
+	readBlock := func(r Reader, meta *BlockMeta) {
+		blockID := (uuid.UUID)(meta.BlockID)
+		for _, name := range []string{"write", "stream", "append", "resumable"} {
+			b, err := r.Read(ctx, name, blockID, meta.TenantID, nil)
+			require.NoError(t, err)
+			require.Equal(t, data, b, name)
+
+			rc, _, err := r.StreamReader(ctx, name, blockID, meta.TenantID)
+			require.NoError(t, err)
+			b, err = io.ReadAll(rc)
+			require.NoError(t, err)
+			require.NoError(t, rc.Close())
+			require.Equal(t, data, b, name)
+
+			for _, offset := range []uint64{0, 5, 16, 33, 1000, 4090, 8192, 16900} {
+				buffer := make([]byte, 100)
+				require.NoError(t, r.ReadRange(ctx, name, blockID, meta.TenantID, offset, buffer, &CacheInfo{Meta: meta}))
+				require.Equal(t, data[offset:offset+100], buffer, name)
+			}
+		}
+	}
+
+	// data objects of tenants with a key are encrypted, the meta stores the wrapped data key
+	encrypted := writeBlock("encrypted")
+	readBlock(r, encrypted)
+	for _, name := range []string{"write", "stream", "append", "resumable"} {
+		require.NotEqual(t, data, raw.object(encrypted, name), name)
+	}
+
+	meta, err := plainR.BlockMeta(ctx, (uuid.UUID)(encrypted.BlockID), encrypted.TenantID)
+	require.NoError(t, err)
+	require.NotEmpty(t, meta.EncryptionKey)
+	require.Empty(t, encrypted.EncryptionKey)
+	hasFlag, err := plainR.HasNoCompactFlag(ctx, (uuid.UUID)(encrypted.BlockID), encrypted.TenantID)
+	require.NoError(t, err)
+	require.True(t, hasFlag)
+
+	// blocks are decrypted by readers that didn't write them with the key of the meta
+	otherR, _ := NewEncryptedReaderWriter(plainR, plainW, kms)
+	readBlock(otherR, encrypted)
+	otherR, _ = NewEncryptedReaderWriter(plainR, plainW, kms)
+	readBlock(otherR, meta)
+
+	// objects written to an existing block keep its key
+	_, otherW := NewEncryptedReaderWriter(plainR, plainW, kms)
+	require.NoError(t, otherW.Write(ctx, "write", (uuid.UUID)(encrypted.BlockID), encrypted.TenantID, data, nil))
+	otherR, _ = NewEncryptedReaderWriter(plainR, plainW, kms)
+	readBlock(otherR, encrypted)
+
+	// blocks of tenants without a key are written unencrypted
+	plain := writeBlock("plain")
+	readBlock(r, plain)
+	readBlock(plainR, plain)
+	meta, err = plainR.BlockMeta(ctx, (uuid.UUID)(plain.BlockID), plain.TenantID)
+	require.NoError(t, err)
+	require.Empty(t, meta.EncryptionKey)
+
+	// and encrypted blocks can't be read without the key of their tenant
+	otherKMS, err := NewStaticKMS(map[string][][]byte{"encrypted": {testTenantKey(t)}})
+	require.NoError(t, err)
+	otherR, _ = NewEncryptedReaderWriter(plainR, plainW, otherKMS)
+	_, err = otherR.Read(ctx, "write", (uuid.UUID)(encrypted.BlockID), encrypted.TenantID, nil)
+	require.Error(t, err)
+}
+
+func TestEncryptedObjects(t *testing.T) {
+	ctx := context.Background()
+	kms, err := NewStaticKMS(map[string][][]byte{"encrypted": {testTenantKey(t)}})
+	require.NoError(t, err)
+
+	raw := &memoryBackend{objects: map[string][]byte{}}
+	plainR, plainW := NewReader(raw), NewWriter(raw)
+	r, w := NewEncryptedReaderWriter(plainR, plainW, kms)
+
+	meta := NewBlockMeta("encrypted", uuid.New(), "v2", EncNone, "")
+	blockID := (uuid.UUID)(meta.BlockID)
+	require.NoError(t, w.WriteBlockMeta(ctx, meta))
+
+	// objects of the size of chunks end with an empty chunk
+	for _, size := range []int{0, 1, chunkDataSize - 1, chunkDataSize, chunkDataSize + 1, 3 * chunkDataSize} {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+
+		require.NoError(t, w.Write(ctx, "data", blockID, meta.TenantID, data, nil))
+		require.Len(t, raw.object(meta, "data"), int(sealedSize(int64(size))))
+
+		b, err := r.Read(ctx, "data", blockID, meta.TenantID, nil)
+		require.NoError(t, err)
+		require.Equal(t, data, b)
+
+		rc, plainSize, err := r.StreamReader(ctx, "data", blockID, meta.TenantID)
+		require.NoError(t, err)
+		require.Equal(t, int64(size), plainSize)
+		b, err = io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		require.Equal(t, data, b)
+
+		// ranges are read after the object was rewritten with another nonce
+		if size > 0 {
+			buffer := make([]byte, 1)
+			require.NoError(t, r.ReadRange(ctx, "data", blockID, meta.TenantID, uint64(size-1), buffer, nil))
+			require.Equal(t, data[size-1:], buffer)
+		}
+	}
+
+	// every object has its own nonce
+	data := bytes.Repeat([]byte("a"), 2*chunkDataSize)
+	require.NoError(t, w.Write(ctx, "a", blockID, meta.TenantID, data, nil))
+	require.NoError(t, w.Write(ctx, "b", blockID, meta.TenantID, data, nil))
+	require.NotEqual(t, raw.object(meta, "a")[:objectNonceSize], raw.object(meta, "b")[:objectNonceSize])
+
+	readErr := func() error {
+		otherR, _ := NewEncryptedReaderWriter(plainR, plainW, kms)
+		_, err := otherR.Read(ctx, "a", blockID, meta.TenantID, nil)
+		return err
+	}
+	require.NoError(t, readErr())
+
+	// tampered objects are rejected
+	a := raw.object(meta, "a")
+	tampered := bytes.Clone(a)
+	tampered[objectNonceSize+10] ^= 1
+	raw.set(meta, "a", tampered)
+	require.ErrorIs(t, readErr(), errCorruptEncryptedObject)
+
+	// as are objects missing their last chunk
+	raw.set(meta, "a", a[:len(a)-sealedChunkSize])
+	require.ErrorIs(t, readErr(), errCorruptEncryptedObject)
+
+	// and objects with their chunks swapped
+	swapped := bytes.Clone(a[:objectNonceSize])
+	swapped = append(swapped, a[objectNonceSize+sealedChunkSize:objectNonceSize+2*sealedChunkSize]...)
+	swapped = append(swapped, a[objectNonceSize:objectNonceSize+sealedChunkSize]...)
+	swapped = append(swapped, a[objectNonceSize+2*sealedChunkSize:]...)
+	raw.set(meta, "a", swapped)
+	require.ErrorIs(t, readErr(), errCorruptEncryptedObject)
+
+	// or copied from another object
+	raw.set(meta, "a", raw.object(meta, "b"))
+	require.ErrorIs(t, readErr(), errCorruptEncryptedObject)
+}
+
+func TestEncryptionPendingDataKeys(t *testing.T) {
+	ctx := context.Background()
+	kms, err := NewStaticKMS(map[string][][]byte{"encrypted": {testTenantKey(t)}})
+	require.NoError(t, err)
+
+	raw := &memoryBackend{objects: map[string][]byte{}}
+	e := newEncryption(NewReader(raw), kms)
+	e.maxPending = 2
+	now := time.Now()
+	e.now = func() time.Time { return now }
+
+	blockIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	k, err := e.keyForWrite(ctx, blockIDs[0], "encrypted")
+	require.NoError(t, err)
+	_, err = e.keyForWrite(ctx, blockIDs[1], "encrypted")
+	require.NoError(t, err)
+
+	// the number of blocks written without a meta is bounded
+	_, err = e.keyForWrite(ctx, blockIDs[2], "encrypted")
+	require.Error(t, err)
+
+	// blocks with a meta don't count
+	e.written(blockIDs[1], "encrypted", k)
+	_, err = e.keyForWrite(ctx, blockIDs[2], "encrypted")
+	require.NoError(t, err)
+	require.Len(t, e.pending, 2)
+
+	// and the keys of blocks that are no longer written are dropped
+	now = now.Add(pendingDataKeyIdleTimeout / 2)
+	same, err := e.keyForWrite(ctx, blockIDs[0], "encrypted")
+	require.NoError(t, err)
+	require.Same(t, k, same)
+
+	now = now.Add(pendingDataKeyIdleTimeout + time.Minute)
+	_, err = e.keyForWrite(ctx, uuid.New(), "encrypted")
+	require.NoError(t, err)
+	require.Len(t, e.pending, 1)
+}
+
+func testTenantKey(t *testing.T) []byte {
+	key := make([]byte, DataKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+// memoryBackend is an in memory RawReader and RawWriter.
+type memoryBackend struct {
+	mtx     sync.Mutex
+	objects map[string][]byte
+}
+
+type memoryAppendTracker struct {
+	name string
+}
+
+func (m *memoryBackend) object(meta *BlockMeta, name string) []byte {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.objects[path.Join(append(KeyPathForBlock((uuid.UUID)(meta.BlockID), meta.TenantID), name)...)]
+}
+
+func (m *memoryBackend) set(meta *BlockMeta, name string, b []byte) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.objects[path.Join(append(KeyPathForBlock((uuid.UUID)(meta.BlockID), meta.TenantID), name)...)] = b
+}
+
+func (m *memoryBackend) Write(_ context.Context, name string, keypath KeyPath, data io.Reader, _ int64, _ *CacheInfo) error {
+	b, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.objects[path.Join(append(keypath, name)...)] = b
+	return nil
+}
+
+func (m *memoryBackend) Append(_ context.Context, name string, keypath KeyPath, tracker AppendTracker, buffer []byte) (AppendTracker, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	key := path.Join(append(keypath, name)...)
+	if tracker == nil {
+		m.objects[key] = nil
+	}
+	m.objects[key] = append(m.objects[key], buffer...)
+	return memoryAppendTracker{name: key}, nil
+}
+
+func (m *memoryBackend) CloseAppend(context.Context, AppendTracker) error { return nil }
+
+func (m *memoryBackend) Delete(_ context.Context, name string, keypath KeyPath, _ *CacheInfo) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	delete(m.objects, path.Join(append(keypath, name)...))
+	return nil
+}
+
+func (m *memoryBackend) List(context.Context, KeyPath) ([]string, error) { return nil, nil }
+
+func (m *memoryBackend) ListBlocks(context.Context, string) ([]uuid.UUID, []uuid.UUID, error) {
+	return nil, nil, nil
+}
+
+func (m *memoryBackend) Find(context.Context, KeyPath, FindFunc) error { return nil }
+
+func (m *memoryBackend) Read(_ context.Context, name string, keypath KeyPath, _ *CacheInfo) (io.ReadCloser, int64, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	b, ok := m.objects[path.Join(append(keypath, name)...)]
+	if !ok {
+		return nil, 0, ErrDoesNotExist
+	}
+	return io.NopCloser(strings.NewReader(string(b))), int64(len(b)), nil
+}
+
+func (m *memoryBackend) ReadRange(_ context.Context, name string, keypath KeyPath, offset uint64, buffer []byte, _ *CacheInfo) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	b, ok := m.objects[path.Join(append(keypath, name)...)]
+	if !ok {
+		return ErrDoesNotExist
+	}
+	copy(buffer, b[offset:])
+	return nil
+}
+
+func (m *memoryBackend) Shutdown() {}
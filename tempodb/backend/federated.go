@@ -0,0 +1,307 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// FederationPrimary is the source of the blocks listed in the primary bucket of a federation.
+const FederationPrimary = "primary"
+
+// FederatedBucket is one of the buckets a Federation reads from.
+type FederatedBucket struct {
+	Name string
+	R    RawReader
+	C    Compactor
+}
+
+// BlockSourcer returns the name of the bucket a block was listed in.
+type BlockSourcer interface {
+	BlockSource(tenantID string, blockID uuid.UUID) string
+}
+
+// Federation is a RawReader and Compactor that federates several buckets, for example the buckets of two accounts
+// after a cloud migration. Tenants and blocks are listed in all buckets and merged. if a block is listed in several
+// buckets the first bucket wins. Reads of a block are routed to the bucket it was listed in and other objects are
+// read from the first bucket they exist in. Only the primary bucket is written to, so blocks are only marked
+// compacted, cleared or archived there and versioned objects only live there.
+type Federation struct {
+	buckets []FederatedBucket // primary first
+
+	mtx     sync.RWMutex
+	sources map[string]map[uuid.UUID]int // tenant -> block -> bucket
+}
+
+var (
+	_ RawReader             = (*Federation)(nil)
+	_ Compactor             = (*Federation)(nil)
+	_ BlockSourcer          = (*Federation)(nil)
+	_ VersionedReaderWriter = (*Federation)(nil)
+	_ ConditionalReader     = (*Federation)(nil)
+	_ Archiver              = (*Federation)(nil)
+)
+
+// errVersioningNotSupported is returned by the versioned writes of a Federation whose primary bucket doesn't support
+// versioning, a Federation can't write to it otherwise.
+var errVersioningNotSupported = errors.New("primary federated bucket does not support versioning")
+
+// NewFederation returns a Federation of the primary bucket and the other buckets in the order they are given.
+func NewFederation(primaryR RawReader, primaryC Compactor, others []FederatedBucket) (*Federation, error) {
+	buckets := []FederatedBucket{{Name: FederationPrimary, R: primaryR, C: primaryC}}
+	names := map[string]struct{}{FederationPrimary: {}}
+	for _, b := range others {
+		if _, ok := names[b.Name]; ok {
+			return nil, fmt.Errorf("federated bucket name %q must be unique and not %q", b.Name, FederationPrimary)
+		}
+		names[b.Name] = struct{}{}
+		buckets = append(buckets, b)
+	}
+
+	return &Federation{
+		buckets: buckets,
+		sources: map[string]map[uuid.UUID]int{},
+	}, nil
+}
+
+// List implements RawReader
+func (f *Federation) List(ctx context.Context, keypath KeyPath) ([]string, error) {
+	var merged []string
+	seen := map[string]struct{}{}
+	for _, b := range f.buckets {
+		list, err := b.R.List(ctx, keypath)
+		if err != nil {
+			return nil, fmt.Errorf("error listing federated bucket %s: %w", b.Name, err)
+		}
+		for _, s := range list {
+			if _, ok := seen[s]; ok {
+				continue
+			}
+			seen[s] = struct{}{}
+			merged = append(merged, s)
+		}
+	}
+	return merged, nil
+}
+
+// ListBlocks implements RawReader. It records the bucket each block is listed in.
+func (f *Federation) ListBlocks(ctx context.Context, tenant string) ([]uuid.UUID, []uuid.UUID, error) {
+	var blockIDs, compactedBlockIDs []uuid.UUID
+	sources := map[uuid.UUID]int{}
+	for i, b := range f.buckets {
+		blocks, compacted, err := b.R.ListBlocks(ctx, tenant)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error listing blocks of federated bucket %s: %w", b.Name, err)
+		}
+		for _, id := range blocks {
+			if _, ok := sources[id]; !ok {
+				sources[id] = i
+				blockIDs = append(blockIDs, id)
+			}
+		}
+		for _, id := range compacted {
+			if _, ok := sources[id]; !ok {
+				sources[id] = i
+				compactedBlockIDs = append(compactedBlockIDs, id)
+			}
+		}
+	}
+
+	f.mtx.Lock()
+	f.sources[tenant] = sources
+	f.mtx.Unlock()
+
+	return blockIDs, compactedBlockIDs, nil
+}
+
+// Find implements RawReader
+func (f *Federation) Find(ctx context.Context, keypath KeyPath, fn FindFunc) error {
+	for _, b := range f.buckets {
+		if err := b.R.Find(ctx, keypath, fn); err != nil {
+			return fmt.Errorf("error finding objects in federated bucket %s: %w", b.Name, err)
+		}
+	}
+	return nil
+}
+
+// Read implements RawReader
+func (f *Federation) Read(ctx context.Context, name string, keypath KeyPath, cacheInfo *CacheInfo) (io.ReadCloser, int64, error) {
+	var (
+		rc   io.ReadCloser
+		size int64
+	)
+	err := f.route(keypath, cacheInfo, func(r RawReader) error {
+		var err error
+		rc, size, err = r.Read(ctx, name, keypath, cacheInfo)
+		return err
+	})
+	return rc, size, err
+}
+
+// ReadRange implements RawReader
+func (f *Federation) ReadRange(ctx context.Context, name string, keypath KeyPath, offset uint64, buffer []byte, cacheInfo *CacheInfo) error {
+	return f.route(keypath, cacheInfo, func(r RawReader) error {
+		return r.ReadRange(ctx, name, keypath, offset, buffer, cacheInfo)
+	})
+}
+
+// ReadIfChanged implements ConditionalReader. The read is routed like Read. If the bucket does not support conditional
+// reads the object is always read and no version is returned.
+func (f *Federation) ReadIfChanged(ctx context.Context, name string, keypath KeyPath, version Version) (io.ReadCloser, int64, Version, error) {
+	var (
+		rc      io.ReadCloser
+		size    int64
+		current Version
+	)
+	err := f.route(keypath, nil, func(r RawReader) error {
+		var err error
+		if cr, ok := r.(ConditionalReader); ok {
+			rc, size, current, err = cr.ReadIfChanged(ctx, name, keypath, version)
+			return err
+		}
+		rc, size, err = r.Read(ctx, name, keypath, nil)
+		return err
+	})
+	return rc, size, current, err
+}
+
+// WriteVersioned implements VersionedReaderWriter. Versioned objects are only written to the primary bucket.
+func (f *Federation) WriteVersioned(ctx context.Context, name string, keypath KeyPath, data io.Reader, size int64, version Version) (Version, error) {
+	v, ok := f.buckets[0].R.(VersionedReaderWriter)
+	if !ok {
+		return "", errVersioningNotSupported
+	}
+	return v.WriteVersioned(ctx, name, keypath, data, size, version)
+}
+
+// ReadVersioned implements VersionedReaderWriter. Versioned objects are only read from the primary bucket. If it does
+// not support versioning the object is read with VersionNew like FakeVersionedReaderWriter does.
+func (f *Federation) ReadVersioned(ctx context.Context, name string, keypath KeyPath) (io.ReadCloser, Version, error) {
+	primary := f.buckets[0].R
+	if v, ok := primary.(VersionedReaderWriter); ok {
+		return v.ReadVersioned(ctx, name, keypath)
+	}
+	rc, _, err := primary.Read(ctx, name, keypath, nil)
+	return rc, VersionNew, err
+}
+
+// DeleteVersioned implements VersionedReaderWriter. Versioned objects are only deleted from the primary bucket.
+func (f *Federation) DeleteVersioned(ctx context.Context, name string, keypath KeyPath, version Version) error {
+	v, ok := f.buckets[0].R.(VersionedReaderWriter)
+	if !ok {
+		return errVersioningNotSupported
+	}
+	return v.DeleteVersioned(ctx, name, keypath, version)
+}
+
+// Shutdown implements RawReader
+func (f *Federation) Shutdown() {
+	for _, b := range f.buckets {
+		b.R.Shutdown()
+	}
+}
+
+// MarkBlockCompacted implements Compactor. Blocks are only marked compacted in the primary bucket.
+func (f *Federation) MarkBlockCompacted(blockID uuid.UUID, tenantID string) error {
+	return f.buckets[0].C.MarkBlockCompacted(blockID, tenantID)
+}
+
+// ClearBlock implements Compactor. Blocks are only cleared from the primary bucket.
+func (f *Federation) ClearBlock(blockID uuid.UUID, tenantID string) error {
+	return f.buckets[0].C.ClearBlock(blockID, tenantID)
+}
+
+// ArchiveBlock implements Archiver. Only blocks of the primary bucket are archived.
+func (f *Federation) ArchiveBlock(ctx context.Context, blockID uuid.UUID, tenantID string, tier string) error {
+	archiver, ok := f.buckets[0].C.(Archiver)
+	if !ok {
+		return ErrArchivingNotSupported
+	}
+	return archiver.ArchiveBlock(ctx, blockID, tenantID, tier)
+}
+
+// CompactedBlockMeta implements Compactor
+func (f *Federation) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*CompactedBlockMeta, error) {
+	if i, ok := f.source(tenantID, blockID); ok {
+		return f.buckets[i].C.CompactedBlockMeta(blockID, tenantID)
+	}
+
+	for i, b := range f.buckets {
+		meta, err := b.C.CompactedBlockMeta(blockID, tenantID)
+		if errors.Is(err, ErrDoesNotExist) && i < len(f.buckets)-1 {
+			continue
+		}
+		return meta, err
+	}
+	return nil, ErrDoesNotExist
+}
+
+// BlockSource implements BlockSourcer. It returns the name of the bucket the block was last listed in or read from,
+// or an empty string if it's unknown.
+func (f *Federation) BlockSource(tenantID string, blockID uuid.UUID) string {
+	i, ok := f.source(tenantID, blockID)
+	if !ok {
+		return ""
+	}
+	return f.buckets[i].Name
+}
+
+// route calls fn with the bucket of the block of the keypath if it's known and with each bucket until the object
+// exists otherwise. The bucket the block of the keypath is found in is recorded.
+func (f *Federation) route(keypath KeyPath, cacheInfo *CacheInfo, fn func(RawReader) error) error {
+	tenantID, blockID, isBlock := blockOfKeyPath(keypath)
+	if cacheInfo != nil && cacheInfo.Meta != nil && cacheInfo.Meta.Source != "" {
+		for _, b := range f.buckets {
+			if b.Name == cacheInfo.Meta.Source {
+				return fn(b.R)
+			}
+		}
+	}
+	if isBlock {
+		if i, ok := f.source(tenantID, blockID); ok {
+			return fn(f.buckets[i].R)
+		}
+	}
+
+	var err error
+	for i, b := range f.buckets {
+		err = fn(b.R)
+		if errors.Is(err, ErrDoesNotExist) {
+			continue
+		}
+		if err == nil && isBlock {
+			f.mtx.Lock()
+			if f.sources[tenantID] == nil {
+				f.sources[tenantID] = map[uuid.UUID]int{}
+			}
+			f.sources[tenantID][blockID] = i
+			f.mtx.Unlock()
+		}
+		return err
+	}
+	return err
+}
+
+func (f *Federation) source(tenantID string, blockID uuid.UUID) (int, bool) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	i, ok := f.sources[tenantID][blockID]
+	return i, ok
+}
+
+// blockOfKeyPath returns the tenant and block of a keypath built with KeyPathForBlock.
+func blockOfKeyPath(keypath KeyPath) (string, uuid.UUID, bool) {
+	if len(keypath) != 2 {
+		return "", uuid.UUID{}, false
+	}
+	blockID, err := uuid.Parse(keypath[1])
+	if err != nil {
+		return "", uuid.UUID{}, false
+	}
+	return keypath[0], blockID, true
+}
@@ -0,0 +1,140 @@
+package backend
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFederation(t *testing.T) {
+	ctx := context.Background()
+	primaryBlock, otherBlock, bothBlock, compactedBlock := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	readFn := func(objects map[uuid.UUID]string) func(context.Context, string, KeyPath, *CacheInfo) (io.ReadCloser, int64, error) {
+		return func(_ context.Context, _ string, keypath KeyPath, _ *CacheInfo) (io.ReadCloser, int64, error) {
+			for id, s := range objects {
+				if keypath[len(keypath)-1] == id.String() {
+					return io.NopCloser(strings.NewReader(s)), int64(len(s)), nil
+				}
+			}
+			return nil, 0, ErrDoesNotExist
+		}
+	}
+
+	primary := &MockRawReader{
+		L:        []string{"a", "b"},
+		BlockIDs: []uuid.UUID{primaryBlock, bothBlock},
+		ReadFn:   readFn(map[uuid.UUID]string{primaryBlock: "primary", bothBlock: "primary"}),
+	}
+	other := &MockRawReader{
+		L:                 []string{"b", "c"},
+		BlockIDs:          []uuid.UUID{otherBlock, bothBlock},
+		CompactedBlockIDs: []uuid.UUID{compactedBlock},
+		ReadFn:            readFn(map[uuid.UUID]string{otherBlock: "other", bothBlock: "other"}),
+	}
+	primaryC, otherC := &MockCompactor{}, &MockCompactor{}
+
+	_, err := NewFederation(primary, primaryC, []FederatedBucket{{Name: FederationPrimary, R: other, C: otherC}})
+	require.Error(t, err)
+
+	f, err := NewFederation(primary, primaryC, []FederatedBucket{{Name: "other", R: other, C: otherC}})
+	require.NoError(t, err)
+
+	// tenants and blocks are merged, the first bucket wins
+	tenants, err := f.List(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, tenants)
+
+	blocks, compacted, err := f.ListBlocks(ctx, "test")
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{primaryBlock, bothBlock, otherBlock}, blocks)
+	require.Equal(t, []uuid.UUID{compactedBlock}, compacted)
+
+	require.Equal(t, FederationPrimary, f.BlockSource("test", bothBlock))
+	require.Equal(t, "other", f.BlockSource("test", otherBlock))
+	require.Equal(t, "other", f.BlockSource("test", compactedBlock))
+	require.Empty(t, f.BlockSource("test", uuid.New()))
+
+	// reads are routed to the bucket of the block
+	read := func(id uuid.UUID, cacheInfo *CacheInfo) string {
+		rc, _, err := f.Read(ctx, MetaName, KeyPathForBlock(id, "test"), cacheInfo)
+		require.NoError(t, err)
+		b, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		return string(b)
+	}
+	require.Equal(t, "primary", read(bothBlock, nil))
+	require.Equal(t, "other", read(otherBlock, nil))
+	require.Equal(t, "other", read(bothBlock, &CacheInfo{Meta: &BlockMeta{Source: "other"}}))
+
+	// or found in the first bucket it exists in when the block isn't known
+	unknown := uuid.New()
+	other.ReadFn = readFn(map[uuid.UUID]string{unknown: "other"})
+	require.Empty(t, f.BlockSource("other-tenant", unknown))
+	rc, _, err := f.Read(ctx, MetaName, KeyPathForBlock(unknown, "other-tenant"), nil)
+	require.NoError(t, err)
+	rc.Close()
+	require.Equal(t, "other", f.BlockSource("other-tenant", unknown))
+
+	_, _, err = f.Read(ctx, MetaName, KeyPathForBlock(uuid.New(), "test"), nil)
+	require.ErrorIs(t, err, ErrDoesNotExist)
+
+	// compacted metas are read from the bucket of the block
+	primaryC.BlockMetaFn = func(uuid.UUID, string) (*CompactedBlockMeta, error) { return nil, ErrDoesNotExist }
+	otherC.BlockMetaFn = func(blockID uuid.UUID, tenantID string) (*CompactedBlockMeta, error) {
+		return &CompactedBlockMeta{BlockMeta: BlockMeta{BlockID: UUID(blockID), TenantID: tenantID}}, nil
+	}
+	meta, err := f.CompactedBlockMeta(compactedBlock, "test")
+	require.NoError(t, err)
+	require.Equal(t, UUID(compactedBlock), meta.BlockID)
+	require.Empty(t, primaryC.CompactedBlockMetaCalls)
+
+	// and from the first bucket they exist in otherwise
+	_, err = f.CompactedBlockMeta(uuid.New(), "test")
+	require.NoError(t, err)
+	require.Len(t, primaryC.CompactedBlockMetaCalls["test"], 1)
+}
+
+func TestFederationForwardsOptionalInterfaces(t *testing.T) {
+	ctx := context.Background()
+	keypath := KeyPath{"test"}
+	other := FederatedBucket{Name: "other", R: &MockRawReader{}, C: &MockCompactor{}}
+
+	primary := &optionalBackend{}
+	f, err := NewFederation(primary, primary, []FederatedBucket{other})
+	require.NoError(t, err)
+
+	_, _, version, err := f.ReadIfChanged(ctx, TenantIndexName, keypath, "")
+	require.NoError(t, err)
+	require.Equal(t, Version("v1"), version)
+	_, err = f.WriteVersioned(ctx, TenantIndexName, keypath, strings.NewReader(""), 0, VersionNew)
+	require.NoError(t, err)
+	_, _, err = f.ReadVersioned(ctx, TenantIndexName, keypath)
+	require.NoError(t, err)
+	require.NoError(t, f.DeleteVersioned(ctx, TenantIndexName, keypath, "v1"))
+	require.NoError(t, f.ArchiveBlock(ctx, uuid.New(), "test", "cold"))
+	require.Equal(t, []string{"ReadIfChanged", "WriteVersioned", "ReadVersioned", "DeleteVersioned", "ArchiveBlock"}, primary.calls)
+
+	// a primary bucket without the optional interfaces gets the fallbacks, versioned writes need a versioned bucket
+	f, err = NewFederation(&MockRawReader{R: []byte("index")}, &MockCompactor{}, []FederatedBucket{other})
+	require.NoError(t, err)
+
+	rc, _, version, err := f.ReadIfChanged(ctx, TenantIndexName, keypath, "v1")
+	require.NoError(t, err)
+	require.Empty(t, version)
+	b, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.Equal(t, "index", string(b))
+
+	_, version, err = f.ReadVersioned(ctx, TenantIndexName, keypath)
+	require.NoError(t, err)
+	require.Equal(t, VersionNew, version)
+	_, err = f.WriteVersioned(ctx, TenantIndexName, keypath, strings.NewReader(""), 0, VersionNew)
+	require.Error(t, err)
+	require.Error(t, f.DeleteVersioned(ctx, TenantIndexName, keypath, "v1"))
+	require.ErrorIs(t, f.ArchiveBlock(ctx, uuid.New(), "test", "cold"), ErrArchivingNotSupported)
+}
@@ -71,7 +71,7 @@ func (rw *readerWriter) ClearBlock(blockID uuid.UUID, tenantID string) error {
 func (rw *readerWriter) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*backend.CompactedBlockMeta, error) {
 	name := backend.CompactedMetaFileName(blockID, tenantID, rw.cfg.Prefix)
 
-	bytes, attrs, err := rw.readAll(context.Background(), name)
+	bytes, attrs, err := rw.readAll(context.Background(), rw.hedgedBucket, name)
 	if err != nil {
 		return nil, readError(err)
 	}
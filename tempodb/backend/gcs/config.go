@@ -5,25 +5,42 @@ import (
 	"time"
 
 	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/credentials"
+)
+
+const (
+	objectRetentionLocked   = "Locked"
+	objectRetentionUnlocked = "Unlocked"
+
+	// CredentialsKeyServiceAccount is the key of the JSON service account key read from a credentials provider.
+	CredentialsKeyServiceAccount = "service_account"
 )
 
 type Config struct {
-	BucketName            string            `yaml:"bucket_name"`
-	Prefix                string            `yaml:"prefix"`
-	ChunkBufferSize       int               `yaml:"chunk_buffer_size"`
-	Endpoint              string            `yaml:"endpoint"`
-	HedgeRequestsAt       time.Duration     `yaml:"hedge_requests_at"`
-	HedgeRequestsUpTo     int               `yaml:"hedge_requests_up_to"`
-	Insecure              bool              `yaml:"insecure"`
-	ObjectCacheControl    string            `yaml:"object_cache_control"`
-	ObjectMetadata        map[string]string `yaml:"object_metadata"`
-	ListBlocksConcurrency int               `yaml:"list_blocks_concurrency"`
+	BucketName            string                  `yaml:"bucket_name"`
+	Prefix                string                  `yaml:"prefix"`
+	ChunkBufferSize       int                     `yaml:"chunk_buffer_size"`
+	Endpoint              string                  `yaml:"endpoint"`
+	HedgeRequestsAt       time.Duration           `yaml:"hedge_requests_at"`
+	HedgeRequestsUpTo     int                     `yaml:"hedge_requests_up_to"`
+	HedgeRequestsRoles    backend.HedgedReadRoles `yaml:"hedge_requests_roles"`
+	Insecure              bool                    `yaml:"insecure"`
+	ObjectCacheControl    string                  `yaml:"object_cache_control"`
+	ObjectMetadata        map[string]string       `yaml:"object_metadata"`
+	ListBlocksConcurrency int                     `yaml:"list_blocks_concurrency"`
+
+	// Credentials reads the service account key from a provider instead of the default credentials of the environment
+	Credentials credentials.Config `yaml:"credentials"`
+
+	ObjectLock backend.ObjectLockConfig `yaml:"object_lock"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	f.StringVar(&cfg.BucketName, util.PrefixConfig(prefix, "gcs.bucket"), "", "gcs bucket to store traces in.")
 	f.StringVar(&cfg.Prefix, util.PrefixConfig(prefix, "gcs.prefix"), "", "gcs bucket prefix to store traces in.")
 	f.IntVar(&cfg.ListBlocksConcurrency, util.PrefixConfig(prefix, "gcs.list_blocks_concurrency"), 3, "number of concurrent list calls to make to backend")
+	cfg.Credentials.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "gcs.credentials"), f)
 	cfg.ChunkBufferSize = 10 * 1024 * 1024
 	cfg.HedgeRequestsUpTo = 2
 }
@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
@@ -25,6 +26,9 @@ import (
 	"github.com/cristalhq/hedgedhttp"
 	gkLog "github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 	google_http "google.golang.org/api/transport/http"
@@ -33,6 +37,7 @@ import (
 	tempo_io "github.com/grafana/tempo/pkg/io"
 	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/tempodb/backend"
+	tempo_credentials "github.com/grafana/tempo/tempodb/backend/credentials"
 )
 
 type readerWriter struct {
@@ -40,6 +45,7 @@ type readerWriter struct {
 	cfg          *Config
 	bucket       *storage.BucketHandle
 	hedgedBucket *storage.BucketHandle
+	creds        *tempo_credentials.Credentials
 }
 
 var tracer = otel.Tracer("tempodb/backend/gcs")
@@ -83,15 +89,35 @@ func NewVersionedReaderWriter(cfg *Config, confirmVersioning bool) (backend.Vers
 	return rw, nil
 }
 
-func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
+func internalNew(cfg *Config, confirm bool) (rw *readerWriter, err error) {
 	ctx := context.Background()
 
-	bucket, err := createBucket(ctx, cfg, false)
+	if cfg.Credentials.Enabled() && cfg.Insecure {
+		return nil, errors.New("a credentials provider can't be used with insecure")
+	}
+	creds, err := tempo_credentials.New(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			creds.Stop()
+		}
+	}()
+	var tokenSource oauth2.TokenSource
+	if creds != nil {
+		tokenSource, err = newRotatingTokenSource(creds)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bucket, err := createBucket(ctx, cfg, tokenSource, false)
 	if err != nil {
 		return nil, fmt.Errorf("creating bucket: %w", err)
 	}
 
-	hedgedBucket, err := createBucket(ctx, cfg, true)
+	hedgedBucket, err := createBucket(ctx, cfg, tokenSource, true)
 	if err != nil {
 		return nil, fmt.Errorf("creating hedged bucket: %w", err)
 	}
@@ -103,11 +129,16 @@ func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
 		}
 	}
 
-	rw := &readerWriter{
+	if cfg.ObjectLock.Enabled() && cfg.ObjectLock.Mode != objectRetentionLocked && cfg.ObjectLock.Mode != objectRetentionUnlocked {
+		return nil, fmt.Errorf("unsupported object lock mode %q, supported modes are %s and %s", cfg.ObjectLock.Mode, objectRetentionLocked, objectRetentionUnlocked)
+	}
+
+	rw = &readerWriter{
 		logger:       log.Logger,
 		cfg:          cfg,
 		bucket:       bucket,
 		hedgedBucket: hedgedBucket,
+		creds:        creds,
 	}
 
 	return rw, nil
@@ -115,6 +146,7 @@ func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
 
 // Write implements backend.Writer
 func (rw *readerWriter) Write(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, _ int64, _ *backend.CacheInfo) error {
+	locked := rw.cfg.ObjectLock.Locks(name, keypath)
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 	derivedCtx, span := tracer.Start(ctx, "gcs.Write")
 	defer span.End()
@@ -122,6 +154,9 @@ func (rw *readerWriter) Write(ctx context.Context, name string, keypath backend.
 	span.SetAttributes(attribute.String("object", name))
 
 	w := rw.writer(derivedCtx, backend.ObjectFileName(keypath, name), nil)
+	if locked {
+		rw.lockObject(w)
+	}
 
 	written, err := io.Copy(w, data)
 	if err != nil {
@@ -142,6 +177,7 @@ func (rw *readerWriter) Write(ctx context.Context, name string, keypath backend.
 
 // Append implements backend.Writer
 func (rw *readerWriter) Append(ctx context.Context, name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
+	locked := rw.cfg.ObjectLock.Locks(name, keypath)
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 	ctx, span := tracer.Start(ctx, "gcs.Append", trace.WithAttributes(
 		attribute.Int("len", len(buffer)),
@@ -151,6 +187,9 @@ func (rw *readerWriter) Append(ctx context.Context, name string, keypath backend
 	var w *storage.Writer
 	if tracker == nil {
 		w = rw.writer(ctx, backend.ObjectFileName(keypath, name), nil)
+		if locked {
+			rw.lockObject(w)
+		}
 	} else {
 		w = tracker.(*storage.Writer)
 	}
@@ -351,6 +390,7 @@ func (rw *readerWriter) Find(ctx context.Context, keypath backend.KeyPath, f bac
 		opts := backend.FindMatch{
 			Key:      attrs.Name,
 			Modified: attrs.Updated,
+			Size:     attrs.Size,
 		}
 		f(opts)
 	}
@@ -359,14 +399,14 @@ func (rw *readerWriter) Find(ctx context.Context, keypath backend.KeyPath, f bac
 }
 
 // Read implements backend.Reader
-func (rw *readerWriter) Read(ctx context.Context, name string, keypath backend.KeyPath, _ *backend.CacheInfo) (io.ReadCloser, int64, error) {
+func (rw *readerWriter) Read(ctx context.Context, name string, keypath backend.KeyPath, cacheInfo *backend.CacheInfo) (io.ReadCloser, int64, error) {
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 	derivedCtx, span := tracer.Start(ctx, "gcs.Read")
 	defer span.End()
 
 	span.SetAttributes(attribute.String("object", name))
 
-	b, _, err := rw.readAll(derivedCtx, backend.ObjectFileName(keypath, name))
+	b, _, err := rw.readAll(derivedCtx, rw.readBucket(cacheInfo, false), backend.ObjectFileName(keypath, name))
 	if err != nil {
 		span.SetStatus(codes.Error, "")
 	}
@@ -374,7 +414,7 @@ func (rw *readerWriter) Read(ctx context.Context, name string, keypath backend.K
 }
 
 // ReadRange implements backend.Reader
-func (rw *readerWriter) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, _ *backend.CacheInfo) error {
+func (rw *readerWriter) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, cacheInfo *backend.CacheInfo) error {
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 	derivedCtx, span := tracer.Start(ctx, "gcs.ReadRange", trace.WithAttributes(
 		attribute.Int("len", len(buffer)),
@@ -382,7 +422,7 @@ func (rw *readerWriter) ReadRange(ctx context.Context, name string, keypath back
 	))
 	defer span.End()
 
-	err := rw.readRange(derivedCtx, backend.ObjectFileName(keypath, name), int64(offset), buffer)
+	err := rw.readRange(derivedCtx, rw.readBucket(cacheInfo, true), backend.ObjectFileName(keypath, name), int64(offset), buffer)
 	if err != nil {
 		span.SetStatus(codes.Error, "")
 	}
@@ -391,6 +431,7 @@ func (rw *readerWriter) ReadRange(ctx context.Context, name string, keypath back
 
 // Shutdown implements backend.Reader
 func (rw *readerWriter) Shutdown() {
+	rw.creds.Stop()
 }
 
 func (rw *readerWriter) WriteVersioned(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, _ int64, version backend.Version) (backend.Version, error) {
@@ -441,7 +482,7 @@ func (rw *readerWriter) ReadVersioned(ctx context.Context, name string, keypath
 	))
 	defer span.End()
 
-	b, attrs, err := rw.readAll(derivedCtx, backend.ObjectFileName(keypath, name))
+	b, attrs, err := rw.readAll(derivedCtx, rw.hedgedBucket, backend.ObjectFileName(keypath, name))
 	if err != nil {
 		span.SetStatus(codes.Error, "")
 		return nil, "", readError(err)
@@ -449,6 +490,43 @@ func (rw *readerWriter) ReadVersioned(ctx context.Context, name string, keypath
 	return io.NopCloser(bytes.NewReader(b)), toVersion(attrs.Generation), nil
 }
 
+// ReadIfChanged implements backend.ConditionalReader. The version is the generation of the object, an unchanged
+// object is answered with a 304 and no body.
+func (rw *readerWriter) ReadIfChanged(ctx context.Context, name string, keypath backend.KeyPath, version backend.Version) (io.ReadCloser, int64, backend.Version, error) {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	derivedCtx, span := tracer.Start(ctx, "gcs.ReadIfChanged", trace.WithAttributes(
+		attribute.String("object", name),
+	))
+	defer span.End()
+
+	o := rw.hedgedBucket.Object(backend.ObjectFileName(keypath, name))
+	if version != "" {
+		generation, err := strconv.ParseInt(string(version), 10, 64)
+		if err != nil {
+			return nil, 0, "", backend.ErrVersionInvalid
+		}
+		o = o.If(storage.Conditions{GenerationNotMatch: generation})
+	}
+
+	r, err := o.NewReader(derivedCtx)
+	if err != nil {
+		var gErr *googleapi.Error
+		if errors.As(err, &gErr) && gErr.Code == http.StatusNotModified {
+			return nil, 0, "", backend.ErrNotModified
+		}
+		span.SetStatus(codes.Error, "")
+		return nil, 0, "", readError(err)
+	}
+	defer r.Close()
+
+	b, err := tempo_io.ReadAllWithEstimate(r, r.Attrs.Size)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	return io.NopCloser(bytes.NewReader(b)), int64(len(b)), toVersion(r.Attrs.Generation), nil
+}
+
 func toVersion(generation int64) backend.Version {
 	return backend.Version(fmt.Sprint(generation))
 }
@@ -472,8 +550,24 @@ func (rw *readerWriter) writer(ctx context.Context, name string, conditions *sto
 	return w
 }
 
-func (rw *readerWriter) readAll(ctx context.Context, name string) ([]byte, *storage.ReaderObjectAttrs, error) {
-	r, err := rw.hedgedBucket.Object(name).NewReader(ctx)
+// lockObject sets the object retention of the object written by w.
+func (rw *readerWriter) lockObject(w *storage.Writer) {
+	w.Retention = &storage.ObjectRetention{
+		Mode:        rw.cfg.ObjectLock.Mode,
+		RetainUntil: rw.cfg.ObjectLock.RetainUntil(time.Now()),
+	}
+}
+
+// readBucket returns the bucket of a Read or ReadRange, the hedged bucket unless the role of the read isn't hedged.
+func (rw *readerWriter) readBucket(cacheInfo *backend.CacheInfo, rangeRead bool) *storage.BucketHandle {
+	if rw.cfg.HedgeRequestsRoles.Hedge(cacheInfo, rangeRead) {
+		return rw.hedgedBucket
+	}
+	return rw.bucket
+}
+
+func (rw *readerWriter) readAll(ctx context.Context, bucket *storage.BucketHandle, name string) ([]byte, *storage.ReaderObjectAttrs, error) {
+	r, err := bucket.Object(name).NewReader(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -487,8 +581,8 @@ func (rw *readerWriter) readAll(ctx context.Context, name string) ([]byte, *stor
 	return buf, &r.Attrs, nil
 }
 
-func (rw *readerWriter) readRange(ctx context.Context, name string, offset int64, buffer []byte) error {
-	r, err := rw.hedgedBucket.Object(name).NewRangeReader(ctx, offset, int64(len(buffer)))
+func (rw *readerWriter) readRange(ctx context.Context, bucket *storage.BucketHandle, name string, offset int64, buffer []byte) error {
+	r, err := bucket.Object(name).NewRangeReader(ctx, offset, int64(len(buffer)))
 	if err != nil {
 		return err
 	}
@@ -507,7 +601,7 @@ func (rw *readerWriter) readRange(ctx context.Context, name string, offset int64
 	return err
 }
 
-func createBucket(ctx context.Context, cfg *Config, hedge bool) (*storage.BucketHandle, error) {
+func createBucket(ctx context.Context, cfg *Config, tokenSource oauth2.TokenSource, hedge bool) (*storage.BucketHandle, error) {
 	// start with default transport
 	customTransport := http.DefaultTransport.(*http.Transport).Clone()
 
@@ -519,6 +613,9 @@ func createBucket(ctx context.Context, cfg *Config, hedge bool) (*storage.Bucket
 		transportOptions = append(transportOptions, option.WithoutAuthentication())
 		customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 	}
+	if tokenSource != nil {
+		transportOptions = append(transportOptions, option.WithTokenSource(tokenSource))
+	}
 	transport, err := google_http.NewTransport(ctx, customTransport, transportOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("creating google http transport: %w", err)
@@ -557,6 +654,54 @@ func createBucket(ctx context.Context, cfg *Config, hedge bool) (*storage.Bucket
 	return client.Bucket(cfg.BucketName), nil
 }
 
+// rotatingTokenSource returns the tokens of the service account key read from a credentials provider. The token
+// source of the key is rebuilt when the provider rotates it.
+type rotatingTokenSource struct {
+	creds *tempo_credentials.Credentials
+
+	mtx     sync.Mutex
+	version uint64
+	source  oauth2.TokenSource
+}
+
+func newRotatingTokenSource(creds *tempo_credentials.Credentials) (*rotatingTokenSource, error) {
+	s := &rotatingTokenSource{creds: creds}
+
+	// error early if the key is invalid
+	values, version := creds.Get()
+	if err := s.load(values, version); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Token implements oauth2.TokenSource
+func (s *rotatingTokenSource) Token() (*oauth2.Token, error) {
+	values, version := s.creds.Get()
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if version != s.version {
+		if err := s.load(values, version); err != nil {
+			return nil, err
+		}
+	}
+	return s.source.Token()
+}
+
+func (s *rotatingTokenSource) load(values map[string]string, version uint64) error {
+	// only service account keys are accepted, other credential configurations aren't validated
+	jwtCfg, err := google.JWTConfigFromJSON([]byte(values[CredentialsKeyServiceAccount]), storage.ScopeReadWrite)
+	if err != nil {
+		return fmt.Errorf("invalid service account key in credential %s: %w", CredentialsKeyServiceAccount, err)
+	}
+
+	s.source = oauth2.ReuseTokenSource(nil, jwtCfg.TokenSource(context.Background()))
+	s.version = version
+	return nil
+}
+
 func readError(err error) error {
 	if errors.Is(err, storage.ErrObjectNotExist) {
 		return backend.ErrDoesNotExist
@@ -0,0 +1,52 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// TenantIndexHeartbeat is written by the builder of a tenant index every time it writes the index. Pollers that
+// don't own the tenant use it to detect a builder that stopped and take over building the index.
+type TenantIndexHeartbeat struct {
+	Builder  string    `json:"builder"`
+	Takeover bool      `json:"takeover,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// ReadTenantIndexHeartbeat reads the tenant index heartbeat of the tenant and the version it was read at.
+// ErrDoesNotExist is returned if no builder has written a heartbeat.
+func ReadTenantIndexHeartbeat(ctx context.Context, r VersionedReaderWriter, tenantID string) (*TenantIndexHeartbeat, Version, error) {
+	reader, version, err := r.ReadVersioned(ctx, TenantIndexHeartbeatName, KeyPath{tenantID})
+	if err != nil {
+		return nil, "", err
+	}
+	defer reader.Close()
+
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, "", err
+	}
+
+	out := &TenantIndexHeartbeat{}
+	err = json.Unmarshal(b, out)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return out, version, nil
+}
+
+// WriteTenantIndexHeartbeat writes the tenant index heartbeat of the tenant if the current heartbeat is at version.
+// Pass VersionNew to write the first heartbeat. ErrVersionDoesNotMatch is returned if another poller wrote a
+// heartbeat in the meantime.
+func WriteTenantIndexHeartbeat(ctx context.Context, w VersionedReaderWriter, tenantID string, hb *TenantIndexHeartbeat, version Version) (Version, error) {
+	b, err := json.Marshal(hb)
+	if err != nil {
+		return "", err
+	}
+
+	return w.WriteVersioned(ctx, TenantIndexHeartbeatName, KeyPath{tenantID}, bytes.NewReader(b), int64(len(b)), version)
+}
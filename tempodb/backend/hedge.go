@@ -0,0 +1,59 @@
+package backend
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/grafana/tempo/pkg/cache"
+)
+
+const (
+	// HedgedReadRoleBloom are the reads of bloom filters
+	HedgedReadRoleBloom = "bloom"
+	// HedgedReadRoleIndex are the reads of trace ID indexes, parquet footers and parquet column and offset indexes
+	HedgedReadRoleIndex = "index"
+	// HedgedReadRoleColumn are the range reads of parquet pages and column chunks
+	HedgedReadRoleColumn = "column"
+)
+
+var hedgedReadRoles = []string{HedgedReadRoleBloom, HedgedReadRoleIndex, HedgedReadRoleColumn}
+
+// HedgedReadRoles are the roles of the block reads that a backend hedges. If empty all reads are hedged.
+type HedgedReadRoles []string
+
+// Validate returns an error if a role is unknown.
+func (r HedgedReadRoles) Validate() error {
+	for _, role := range r {
+		if !slices.Contains(hedgedReadRoles, role) {
+			return fmt.Errorf("unknown hedged read role %q, supported roles are %v", role, hedgedReadRoles)
+		}
+	}
+	return nil
+}
+
+// Hedge returns true if a Read, or a ReadRange if rangeRead is set, with the cache info is hedged. Reads without a
+// role, like the reads of block metas, are only hedged if all reads are.
+func (r HedgedReadRoles) Hedge(cacheInfo *CacheInfo, rangeRead bool) bool {
+	if len(r) == 0 {
+		return true
+	}
+
+	role := cache.RoleNone
+	if cacheInfo != nil {
+		role = cacheInfo.Role
+	}
+
+	switch role {
+	case cache.RoleBloom:
+		return slices.Contains(r, HedgedReadRoleBloom)
+	case cache.RoleTraceIDIdx, cache.RoleParquetFooter, cache.RoleParquetColumnIdx, cache.RoleParquetOffsetIdx:
+		return slices.Contains(r, HedgedReadRoleIndex)
+	case cache.RoleParquetPage:
+		return slices.Contains(r, HedgedReadRoleColumn)
+	case cache.RoleNone, "":
+		// range reads without a role are column chunks larger than a page
+		return rangeRead && slices.Contains(r, HedgedReadRoleColumn)
+	default:
+		return false
+	}
+}
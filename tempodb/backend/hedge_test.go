@@ -0,0 +1,50 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/cache"
+)
+
+func TestHedgedReadRolesValidate(t *testing.T) {
+	require.NoError(t, HedgedReadRoles(nil).Validate())
+	require.NoError(t, HedgedReadRoles{HedgedReadRoleBloom, HedgedReadRoleIndex, HedgedReadRoleColumn}.Validate())
+	require.ErrorContains(t, HedgedReadRoles{"pages"}.Validate(), `unknown hedged read role "pages"`)
+}
+
+func TestHedgedReadRolesHedge(t *testing.T) {
+	tcs := []struct {
+		name      string
+		roles     HedgedReadRoles
+		role      cache.Role
+		noInfo    bool
+		rangeRead bool
+		expected  bool
+	}{
+		{name: "all roles", role: cache.RoleParquetPage, rangeRead: true, expected: true},
+		{name: "all roles without info", noInfo: true, expected: true},
+		{name: "bloom", roles: HedgedReadRoles{HedgedReadRoleBloom}, role: cache.RoleBloom, expected: true},
+		{name: "bloom not hedged", roles: HedgedReadRoles{HedgedReadRoleIndex}, role: cache.RoleBloom, expected: false},
+		{name: "trace id index", roles: HedgedReadRoles{HedgedReadRoleIndex}, role: cache.RoleTraceIDIdx, expected: true},
+		{name: "footer", roles: HedgedReadRoles{HedgedReadRoleIndex}, role: cache.RoleParquetFooter, rangeRead: true, expected: true},
+		{name: "column index", roles: HedgedReadRoles{HedgedReadRoleIndex}, role: cache.RoleParquetColumnIdx, rangeRead: true, expected: true},
+		{name: "offset index", roles: HedgedReadRoles{HedgedReadRoleIndex}, role: cache.RoleParquetOffsetIdx, rangeRead: true, expected: true},
+		{name: "page", roles: HedgedReadRoles{HedgedReadRoleColumn}, role: cache.RoleParquetPage, rangeRead: true, expected: true},
+		{name: "column chunk", roles: HedgedReadRoles{HedgedReadRoleColumn}, role: cache.RoleNone, rangeRead: true, expected: true},
+		{name: "range read without info", roles: HedgedReadRoles{HedgedReadRoleColumn}, noInfo: true, rangeRead: true, expected: true},
+		{name: "read without info", roles: HedgedReadRoles{HedgedReadRoleColumn}, noInfo: true, expected: false},
+		{name: "page not hedged", roles: HedgedReadRoles{HedgedReadRoleBloom, HedgedReadRoleIndex}, role: cache.RoleParquetPage, rangeRead: true, expected: false},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var cacheInfo *CacheInfo
+			if !tc.noInfo {
+				cacheInfo = &CacheInfo{Role: tc.role}
+			}
+			require.Equal(t, tc.expected, tc.roles.Hedge(cacheInfo, tc.rangeRead))
+		})
+	}
+}
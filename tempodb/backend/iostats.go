@@ -0,0 +1,78 @@
+package backend
+
+import (
+	"context"
+
+	"go.uber.org/atomic"
+
+	"github.com/grafana/tempo/pkg/cache"
+)
+
+type ioStatsContextKey struct{}
+
+// IOStats accumulates the bytes read through a Reader for a single request broken down by the role of the
+// objects read. It is carried in the context passed to the Reader and safe for concurrent use.
+type IOStats struct {
+	bloomBytes  atomic.Uint64
+	indexBytes  atomic.Uint64
+	columnBytes atomic.Uint64
+}
+
+// ContextWithIOStats returns a context recording the reads done with it in the returned IOStats.
+// If the context already records its reads they are recorded in the same IOStats.
+func ContextWithIOStats(ctx context.Context) (context.Context, *IOStats) {
+	if stats := IOStatsFromContext(ctx); stats != nil {
+		return ctx, stats
+	}
+
+	stats := &IOStats{}
+	return context.WithValue(ctx, ioStatsContextKey{}, stats), stats
+}
+
+// IOStatsFromContext returns the IOStats recording the reads done with the context or nil.
+func IOStatsFromContext(ctx context.Context) *IOStats {
+	stats, _ := ctx.Value(ioStatsContextKey{}).(*IOStats)
+	return stats
+}
+
+// Add records n bytes read of an object with the given cache info. Bloom filters are recorded as bloom bytes,
+// the trace id index and the parquet footer and indexes as index bytes and everything else as column bytes.
+func (s *IOStats) Add(cacheInfo *CacheInfo, n uint64) {
+	if s == nil {
+		return
+	}
+
+	role := cache.RoleNone
+	if cacheInfo != nil {
+		role = cacheInfo.Role
+	}
+
+	switch role {
+	case cache.RoleBloom:
+		s.bloomBytes.Add(n)
+	case cache.RoleTraceIDIdx, cache.RoleParquetFooter, cache.RoleParquetColumnIdx, cache.RoleParquetOffsetIdx:
+		s.indexBytes.Add(n)
+	default:
+		s.columnBytes.Add(n)
+	}
+}
+
+// BloomBytes returns the bytes of bloom filters read.
+func (s *IOStats) BloomBytes() uint64 {
+	return s.bloomBytes.Load()
+}
+
+// IndexBytes returns the bytes of indexes read.
+func (s *IOStats) IndexBytes() uint64 {
+	return s.indexBytes.Load()
+}
+
+// ColumnBytes returns the bytes of column data read.
+func (s *IOStats) ColumnBytes() uint64 {
+	return s.columnBytes.Load()
+}
+
+// TotalBytes returns the bytes read.
+func (s *IOStats) TotalBytes() uint64 {
+	return s.BloomBytes() + s.IndexBytes() + s.ColumnBytes()
+}
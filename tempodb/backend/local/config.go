@@ -2,16 +2,30 @@ package local
 
 import (
 	"flag"
+	"time"
 
 	"github.com/grafana/tempo/pkg/util"
 )
 
 type Config struct {
 	Path string `yaml:"path"`
+
+	// Watch maintains the list of blocks from filesystem notifications instead of walking the tenant directory
+	// on every poll.
+	Watch bool `yaml:"watch"`
+	// WatchResyncPeriod is how often the list of blocks of a tenant is rebuilt with a full scan when Watch is
+	// enabled. 0 disables periodic scans.
+	WatchResyncPeriod time.Duration `yaml:"watch_resync_period"`
+	// ListBlocksConcurrency is the number of block directories of a tenant checked for metas in parallel when
+	// its blocks are listed. The tenant directory is walked if it's 1 or less.
+	ListBlocksConcurrency int `yaml:"list_blocks_concurrency"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	f.StringVar(&cfg.Path, util.PrefixConfig(prefix, "local.path"), "", "path to store traces at.")
+	f.BoolVar(&cfg.Watch, util.PrefixConfig(prefix, "local.watch"), false, "maintain the list of blocks from filesystem notifications instead of scanning the path on every poll.")
+	f.DurationVar(&cfg.WatchResyncPeriod, util.PrefixConfig(prefix, "local.watch-resync-period"), time.Hour, "how often the list of blocks is rebuilt with a full scan when watching is enabled.")
+	f.IntVar(&cfg.ListBlocksConcurrency, util.PrefixConfig(prefix, "local.list_blocks_concurrency"), 3, "number of block directories checked in parallel when listing blocks")
 }
 
 func (cfg *Config) PathMatches(other *Config) bool {
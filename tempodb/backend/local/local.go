@@ -2,31 +2,39 @@ package local
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/go-kit/log/level"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/grafana/tempo/pkg/boundedwaitgroup"
+	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/tempodb/backend"
 )
 
 type Backend struct {
 	cfg *Config
+
+	// watcher maintains the list of blocks if watching is enabled
+	watcher *blockWatcher
 }
 
 var tracer = otel.Tracer("tempodb/backend/local")
 
 var (
-	_                backend.RawReader = (*Backend)(nil)
-	_                backend.RawWriter = (*Backend)(nil)
-	_                backend.Compactor = (*Backend)(nil)
-	pathSeparatorStr                   = string(os.PathSeparator)
+	_                backend.RawReader         = (*Backend)(nil)
+	_                backend.RawWriter         = (*Backend)(nil)
+	_                backend.Compactor         = (*Backend)(nil)
+	_                backend.ConditionalReader = (*Backend)(nil)
+	pathSeparatorStr                           = string(os.PathSeparator)
 )
 
 func NewBackend(cfg *Config) (*Backend, error) {
@@ -39,6 +47,13 @@ func NewBackend(cfg *Config) (*Backend, error) {
 		cfg: cfg,
 	}
 
+	if cfg.Watch {
+		l.watcher, err = newBlockWatcher(cfg.Path, cfg.WatchResyncPeriod)
+		if err != nil {
+			level.Warn(log.Logger).Log("msg", "failed to watch local backend, falling back to scanning", "path", cfg.Path, "err", err)
+		}
+	}
+
 	return l, nil
 }
 
@@ -47,7 +62,8 @@ func New(cfg *Config) (backend.RawReader, backend.RawWriter, backend.Compactor,
 	return l, l, l, err
 }
 
-// Write implements backend.Writer
+// Write implements backend.Writer. The object is written to a temporary file that is renamed into place once it's
+// complete, so a failed write never leaves a truncated object behind.
 func (rw *Backend) Write(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, _ int64, _ *backend.CacheInfo) error {
 	if err := ctx.Err(); err != nil {
 		return err
@@ -59,21 +75,27 @@ func (rw *Backend) Write(ctx context.Context, name string, keypath backend.KeyPa
 		return err
 	}
 
-	tracesFileName := rw.objectFileName(keypath, name)
-	dst, err := os.Create(tracesFileName)
+	dst, err := os.CreateTemp(blockFolder, name+tempFileSuffix)
 	if err != nil {
 		return err
 	}
-	defer dst.Close()
 
 	_, err = io.Copy(dst, data)
 	if err != nil {
+		abortTempFile(dst)
 		return err
 	}
-	return err
+	return commitTempFile(dst, rw.objectFileName(keypath, name))
 }
 
-// Append implements backend.Writer
+// appendTracker is the AppendTracker of the local backend. The object is appended to a temporary file that is renamed
+// into place by CloseAppend.
+type appendTracker struct {
+	f    *os.File
+	name string
+}
+
+// Append implements backend.Writer. A failed append removes the temporary file of the object.
 func (rw *Backend) Append(ctx context.Context, name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
@@ -84,7 +106,7 @@ func (rw *Backend) Append(ctx context.Context, name string, keypath backend.KeyP
 	))
 	defer span.End()
 
-	var dst *os.File
+	var dst *appendTracker
 	if tracker == nil {
 		blockFolder := rw.rootPath(keypath)
 		err := os.MkdirAll(blockFolder, 0o700)
@@ -92,17 +114,18 @@ func (rw *Backend) Append(ctx context.Context, name string, keypath backend.KeyP
 			return nil, err
 		}
 
-		tracesFileName := rw.objectFileName(keypath, name)
-		dst, err = os.Create(tracesFileName)
+		f, err := os.CreateTemp(blockFolder, name+tempFileSuffix)
 		if err != nil {
 			return nil, err
 		}
+		dst = &appendTracker{f: f, name: rw.objectFileName(keypath, name)}
 	} else {
-		dst = tracker.(*os.File)
+		dst = tracker.(*appendTracker)
 	}
 
-	_, err := dst.Write(buffer)
+	_, err := dst.f.Write(buffer)
 	if err != nil {
+		abortTempFile(dst.f)
 		return nil, err
 	}
 
@@ -111,16 +134,39 @@ func (rw *Backend) Append(ctx context.Context, name string, keypath backend.KeyP
 
 // CloseAppend implements backend.Writer
 func (rw *Backend) CloseAppend(ctx context.Context, tracker backend.AppendTracker) error {
+	if tracker == nil {
+		return nil
+	}
+
+	dst := tracker.(*appendTracker)
 	if err := ctx.Err(); err != nil {
+		abortTempFile(dst.f)
 		return err
 	}
 
-	if tracker == nil {
-		return nil
+	return commitTempFile(dst.f, dst.name)
+}
+
+// tempFileSuffix is the pattern of the suffix of the temporary files objects are written to.
+const tempFileSuffix = ".tmp-*"
+
+// commitTempFile closes the temporary file and renames it to name. The temporary file is removed if it can't be
+// renamed.
+func commitTempFile(f *os.File, name string) error {
+	err := f.Close()
+	if err == nil {
+		err = os.Rename(f.Name(), name)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
 	}
+	return err
+}
 
-	var dst *os.File = tracker.(*os.File)
-	return dst.Close()
+// abortTempFile closes and removes the temporary file.
+func abortTempFile(f *os.File) {
+	_ = f.Close()
+	_ = os.Remove(f.Name())
 }
 
 func (rw *Backend) Delete(ctx context.Context, name string, keypath backend.KeyPath, _ *backend.CacheInfo) error {
@@ -157,6 +203,103 @@ func (rw *Backend) List(ctx context.Context, keypath backend.KeyPath) ([]string,
 
 // ListBlocks implements backend.Reader
 func (rw *Backend) ListBlocks(_ context.Context, tenant string) (metas []uuid.UUID, compactedMetas []uuid.UUID, err error) {
+	if rw.watcher == nil {
+		return rw.scanBlocks(tenant)
+	}
+
+	if metas, compactedMetas, ok := rw.watcher.blocks(tenant); ok {
+		return metas, compactedMetas, nil
+	}
+
+	// the tenant is watched before it's scanned so blocks written during the scan aren't missed. if it can't be
+	// watched it's scanned on every listing.
+	watchErr := rw.watcher.watchTenant(tenant)
+	if watchErr != nil && !os.IsNotExist(watchErr) {
+		level.Warn(log.Logger).Log("msg", "failed to watch tenant in local backend, falling back to scanning", "tenant", tenant, "err", watchErr)
+	}
+
+	metas, compactedMetas, err = rw.scanBlocks(tenant)
+	if err == nil && watchErr == nil {
+		rw.watcher.sync(tenant, metas, compactedMetas)
+	}
+
+	return metas, compactedMetas, err
+}
+
+// scanBlocks lists the blocks of the tenant. The block directories are checked for metas in parallel if
+// ListBlocksConcurrency is greater than 1, otherwise the tenant directory is walked.
+func (rw *Backend) scanBlocks(tenant string) (metas []uuid.UUID, compactedMetas []uuid.UUID, err error) {
+	if rw.cfg.ListBlocksConcurrency <= 1 {
+		return rw.walkBlocks(tenant)
+	}
+
+	rootPath := rw.rootPath(backend.KeyPath{tenant})
+	entries, err := os.ReadDir(rootPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	type scannedBlock struct {
+		meta, compactedMeta bool
+		err                 error
+	}
+
+	var (
+		wg      = boundedwaitgroup.New(uint(rw.cfg.ListBlocksConcurrency))
+		scanned = make([]scannedBlock, len(entries))
+	)
+	for i, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		wg.Add(1)
+		go func(b *scannedBlock, blockPath string) {
+			defer wg.Done()
+
+			b.meta, b.err = fileExists(filepath.Join(blockPath, backend.MetaName))
+			if b.err != nil {
+				return
+			}
+			b.compactedMeta, b.err = fileExists(filepath.Join(blockPath, backend.CompactedMetaName))
+		}(&scanned[i], filepath.Join(rootPath, e.Name()))
+	}
+	wg.Wait()
+
+	// the blocks are returned in the order of the walk
+	for i, b := range scanned {
+		if b.err != nil {
+			return nil, nil, b.err
+		}
+		if !b.meta && !b.compactedMeta {
+			continue
+		}
+
+		id, err := uuid.Parse(entries[i].Name())
+		if err != nil {
+			return nil, nil, err
+		}
+		if b.meta {
+			metas = append(metas, id)
+		}
+		if b.compactedMeta {
+			compactedMetas = append(compactedMetas, id)
+		}
+	}
+
+	return metas, compactedMetas, nil
+}
+
+func fileExists(name string) (bool, error) {
+	_, err := os.Stat(name)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// walkBlocks lists the blocks of the tenant by walking its directory.
+func (rw *Backend) walkBlocks(tenant string) (metas []uuid.UUID, compactedMetas []uuid.UUID, err error) {
 	rootPath := rw.rootPath(backend.KeyPath{tenant})
 	fff := os.DirFS(rootPath)
 	err = fs.WalkDir(fff, ".", func(path string, d fs.DirEntry, err error) error {
@@ -216,6 +359,7 @@ func (rw *Backend) Find(_ context.Context, keypath backend.KeyPath, f backend.Fi
 		opts := backend.FindMatch{
 			Key:      tenantFilePath,
 			Modified: info.ModTime(),
+			Size:     info.Size(),
 		}
 
 		f(opts)
@@ -248,6 +392,33 @@ func (rw *Backend) Read(ctx context.Context, name string, keypath backend.KeyPat
 	return f, stat.Size(), err
 }
 
+// ReadIfChanged implements backend.ConditionalReader. The version of a file is derived from its modification time
+// and size.
+func (rw *Backend) ReadIfChanged(ctx context.Context, name string, keypath backend.KeyPath, version backend.Version) (io.ReadCloser, int64, backend.Version, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, -1, "", err
+	}
+
+	f, err := os.OpenFile(rw.objectFileName(keypath, name), os.O_RDONLY, 0o600)
+	if err != nil {
+		return nil, -1, "", readError(err)
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, -1, "", err
+	}
+
+	current := backend.Version(fmt.Sprintf("%d-%d", stat.ModTime().UnixNano(), stat.Size()))
+	if version != "" && current == version {
+		f.Close()
+		return nil, -1, "", backend.ErrNotModified
+	}
+
+	return f, stat.Size(), current, nil
+}
+
 // ReadRange implements backend.Reader
 func (rw *Backend) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, _ *backend.CacheInfo) error {
 	if err := ctx.Err(); err != nil {
@@ -281,6 +452,10 @@ func (rw *Backend) ReadRange(ctx context.Context, name string, keypath backend.K
 func (rw *Backend) Shutdown() {
 	ctx := context.Background()
 
+	if rw.watcher != nil {
+		rw.watcher.close()
+	}
+
 	// Shutdown() doesn't return error so this is best effort
 	tenants, err := rw.List(ctx, backend.KeyPath{})
 	if err != nil {
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	crand "crypto/rand"
+	"errors"
 	"fmt"
 	"math/rand"
 	"os"
 	"testing"
+	"testing/iotest"
 
 	"github.com/google/uuid"
 	"github.com/grafana/tempo/pkg/io"
@@ -80,6 +82,50 @@ func TestReadWrite(t *testing.T) {
 	assert.Len(t, cm, 1)
 }
 
+func TestListBlocksConcurrent(t *testing.T) {
+	path := t.TempDir()
+	ctx := context.Background()
+	tenant := "tenant"
+
+	_, w, _, err := New(&Config{Path: path})
+	require.NoError(t, err)
+
+	var expectedMetas, expectedCompacted []uuid.UUID
+	for i := 0; i < 20; i++ {
+		id := uuid.New()
+		name := backend.MetaName
+		if i%3 == 0 {
+			name = backend.CompactedMetaName
+		}
+		err = w.Write(ctx, name, backend.KeyPathForBlock(id, tenant), bytes.NewReader([]byte("{}")), 2, nil)
+		require.NoError(t, err)
+
+		if name == backend.MetaName {
+			expectedMetas = append(expectedMetas, id)
+		} else {
+			expectedCompacted = append(expectedCompacted, id)
+		}
+	}
+	// blocks without metas are skipped
+	err = w.Write(ctx, objectName, backend.KeyPathForBlock(uuid.New(), tenant), bytes.NewReader([]byte("{}")), 2, nil)
+	require.NoError(t, err)
+
+	for _, concurrency := range []int{0, 4} {
+		r, _, _, err := New(&Config{Path: path, ListBlocksConcurrency: concurrency})
+		require.NoError(t, err)
+
+		metas, compacted, err := r.ListBlocks(ctx, tenant)
+		require.NoError(t, err)
+		require.ElementsMatch(t, expectedMetas, metas)
+		require.ElementsMatch(t, expectedCompacted, compacted)
+	}
+
+	r, _, _, err := New(&Config{Path: path, ListBlocksConcurrency: 4})
+	require.NoError(t, err)
+	_, _, err = r.ListBlocks(ctx, "missing")
+	require.Error(t, err)
+}
+
 func TestShutdownLeavesTenantsWithBlocks(t *testing.T) {
 	r, w, _, err := New(&Config{
 		Path: t.TempDir(),
@@ -156,3 +202,73 @@ func blockExists(t *testing.T, blockID backend.UUID, tenant string, r backend.Ra
 	require.Len(t, blocks, 1)
 	require.Equal(t, blockID.String(), blocks[0])
 }
+
+func TestReadIfChanged(t *testing.T) {
+	l, err := NewBackend(&Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	keypath := backend.KeyPath{"tenant"}
+
+	_, _, _, err = l.ReadIfChanged(ctx, objectName, keypath, "")
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+
+	require.NoError(t, l.Write(ctx, objectName, keypath, bytes.NewReader([]byte("foo")), 3, nil))
+
+	r, size, version, err := l.ReadIfChanged(ctx, objectName, keypath, "")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), size)
+	require.NotEmpty(t, version)
+	b, err := io.ReadAllWithEstimate(r, size)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, []byte("foo"), b)
+
+	_, _, _, err = l.ReadIfChanged(ctx, objectName, keypath, version)
+	require.ErrorIs(t, err, backend.ErrNotModified)
+
+	require.NoError(t, l.Write(ctx, objectName, keypath, bytes.NewReader([]byte("foobar")), 6, nil))
+
+	r, size, changed, err := l.ReadIfChanged(ctx, objectName, keypath, version)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, int64(6), size)
+	require.NotEqual(t, version, changed)
+}
+
+func TestWriteIsAtomic(t *testing.T) {
+	l, err := NewBackend(&Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	keypath := backend.KeyPath{"tenant"}
+
+	// a failed write leaves nothing behind
+	err = l.Write(ctx, objectName, keypath, iotest.ErrReader(errors.New("broken")), 3, nil)
+	require.Error(t, err)
+	_, _, err = l.Read(ctx, objectName, keypath, nil)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+	entries, err := os.ReadDir(l.rootPath(keypath))
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	// appended objects are in place once the append is closed
+	tracker, err := l.Append(ctx, objectName, keypath, nil, []byte("foo"))
+	require.NoError(t, err)
+	tracker, err = l.Append(ctx, objectName, keypath, tracker, []byte("bar"))
+	require.NoError(t, err)
+	_, _, err = l.Read(ctx, objectName, keypath, nil)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+
+	require.NoError(t, l.CloseAppend(ctx, tracker))
+	r, size, err := l.Read(ctx, objectName, keypath, nil)
+	require.NoError(t, err)
+	b, err := io.ReadAllWithEstimate(r, size)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, []byte("foobar"), b)
+}
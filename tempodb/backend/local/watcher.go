@@ -0,0 +1,321 @@
+package local
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const (
+	scanReasonUnwatched = "unwatched"
+	scanReasonResync    = "resync"
+)
+
+var metricBlocklistScans = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempodb",
+	Name:      "backend_local_blocklist_scans_total",
+	Help:      "Total number of full scans of a tenant directory made to list blocks while watching the local backend.",
+}, []string{"reason"})
+
+type blockState uint8
+
+const (
+	blockStateMeta blockState = 1 << iota
+	blockStateCompacted
+)
+
+type watchedTenant struct {
+	// valid is false until the tenant is scanned, and after notifications may have been lost
+	valid    bool
+	syncedAt time.Time
+	blocks   map[uuid.UUID]blockState
+
+	// scanning is true while the tenant is scanned. the blocks touched during the scan are refreshed once it's
+	// done because the scan may have missed them.
+	scanning bool
+	touched  map[uuid.UUID]struct{}
+}
+
+// blockWatcher maintains the list of blocks of the tenants in the local backend from filesystem notifications.
+// fsnotify doesn't watch recursively, so the root, every tenant and every block directory is watched. A tenant is
+// scanned when it's first listed, when notifications may have been lost and every resync period.
+type blockWatcher struct {
+	root         string
+	resyncPeriod time.Duration
+
+	watcher   *fsnotify.Watcher
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mtx     sync.Mutex
+	tenants map[string]*watchedTenant
+}
+
+func newBlockWatcher(root string, resyncPeriod time.Duration) (*blockWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := watcher.Add(root); err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+
+	w := &blockWatcher{
+		root:         root,
+		resyncPeriod: resyncPeriod,
+		watcher:      watcher,
+		done:         make(chan struct{}),
+		tenants:      map[string]*watchedTenant{},
+	}
+
+	go w.run()
+
+	return w, nil
+}
+
+// blocks returns the blocks of the tenant if they are known from notifications.
+func (w *blockWatcher) blocks(tenant string) (metas []uuid.UUID, compactedMetas []uuid.UUID, ok bool) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	t, ok := w.tenants[tenant]
+	if !ok || !t.valid {
+		metricBlocklistScans.WithLabelValues(scanReasonUnwatched).Inc()
+		return nil, nil, false
+	}
+	if w.resyncPeriod > 0 && time.Since(t.syncedAt) > w.resyncPeriod {
+		metricBlocklistScans.WithLabelValues(scanReasonResync).Inc()
+		return nil, nil, false
+	}
+
+	for id, state := range t.blocks {
+		if state&blockStateMeta != 0 {
+			metas = append(metas, id)
+		}
+		if state&blockStateCompacted != 0 {
+			compactedMetas = append(compactedMetas, id)
+		}
+	}
+
+	return metas, compactedMetas, true
+}
+
+// watchTenant watches the directory of the tenant and of all of its blocks. It's called before the tenant is
+// scanned so that no block written during the scan is missed.
+func (w *blockWatcher) watchTenant(tenant string) error {
+	w.mtx.Lock()
+	t, ok := w.tenants[tenant]
+	if !ok {
+		t = &watchedTenant{blocks: map[uuid.UUID]blockState{}}
+		w.tenants[tenant] = t
+	}
+	t.scanning = true
+	t.touched = map[uuid.UUID]struct{}{}
+	w.mtx.Unlock()
+
+	err := w.addTenantWatches(tenant)
+	if err != nil {
+		w.mtx.Lock()
+		t.valid = false
+		t.scanning = false
+		w.mtx.Unlock()
+	}
+
+	return err
+}
+
+func (w *blockWatcher) addTenantWatches(tenant string) error {
+	tenantPath := filepath.Join(w.root, tenant)
+	if err := w.watcher.Add(tenantPath); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(tenantPath)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if err := w.watcher.Add(filepath.Join(tenantPath, e.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sync replaces the blocks of the tenant with the result of a scan. It does nothing if the scan was aborted because
+// a directory couldn't be watched or the tenant was removed.
+func (w *blockWatcher) sync(tenant string, metas []uuid.UUID, compactedMetas []uuid.UUID) {
+	blocks := make(map[uuid.UUID]blockState, len(metas)+len(compactedMetas))
+	for _, id := range metas {
+		blocks[id] |= blockStateMeta
+	}
+	for _, id := range compactedMetas {
+		blocks[id] |= blockStateCompacted
+	}
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	t, ok := w.tenants[tenant]
+	if !ok || !t.scanning {
+		return
+	}
+
+	t.blocks = blocks
+	for id := range t.touched {
+		w.refreshBlock(t, tenant, id)
+	}
+	t.valid = true
+	t.syncedAt = time.Now()
+	t.scanning = false
+	t.touched = nil
+}
+
+func (w *blockWatcher) run() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case ev, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			w.handle(ev)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			// events may have been lost, scan all tenants on their next listing
+			level.Warn(log.Logger).Log("msg", "local backend watcher error, falling back to scanning", "err", err)
+			w.invalidateAll()
+		}
+	}
+}
+
+func (w *blockWatcher) handle(ev fsnotify.Event) {
+	rel, err := filepath.Rel(w.root, ev.Name)
+	if err != nil {
+		return
+	}
+	parts := strings.Split(rel, pathSeparatorStr)
+	removed := ev.Has(fsnotify.Remove) || ev.Has(fsnotify.Rename)
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	switch len(parts) {
+	case 1: // <tenantID>
+		if removed {
+			_ = w.watcher.Remove(ev.Name)
+			delete(w.tenants, parts[0])
+		}
+		// new tenants are scanned and watched when they are first listed
+
+	case 2: // <tenantID>/<blockID>
+		t, id, ok := w.tenantBlock(parts)
+		if !ok {
+			return
+		}
+		if removed {
+			_ = w.watcher.Remove(ev.Name)
+		} else if ev.Has(fsnotify.Create) {
+			if err := w.watcher.Add(ev.Name); err != nil && !errors.Is(err, os.ErrNotExist) {
+				level.Warn(log.Logger).Log("msg", "failed to watch block, falling back to scanning", "path", ev.Name, "err", err)
+				t.valid = false
+				t.scanning = false
+				return
+			}
+		}
+		w.touch(t, parts[0], id)
+
+	case 3: // <tenantID>/<blockID>/<object>
+		if parts[2] != backend.MetaName && parts[2] != backend.CompactedMetaName {
+			return
+		}
+		if t, id, ok := w.tenantBlock(parts); ok {
+			w.touch(t, parts[0], id)
+		}
+	}
+}
+
+// tenantBlock returns the watched tenant and the block of the path.
+func (w *blockWatcher) tenantBlock(parts []string) (*watchedTenant, uuid.UUID, bool) {
+	t, ok := w.tenants[parts[0]]
+	if !ok {
+		return nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, uuid.Nil, false
+	}
+
+	return t, id, true
+}
+
+// touch refreshes a block after a notification. Blocks of tenants that aren't valid are rebuilt by their next scan.
+func (w *blockWatcher) touch(t *watchedTenant, tenant string, id uuid.UUID) {
+	switch {
+	case t.scanning:
+		t.touched[id] = struct{}{}
+	case t.valid:
+		w.refreshBlock(t, tenant, id)
+	}
+}
+
+// refreshBlock sets the state of the block from its metas on disk. The metas are checked instead of interpreting
+// the notifications, so the state is correct no matter in which order they are received.
+func (w *blockWatcher) refreshBlock(t *watchedTenant, tenant string, id uuid.UUID) {
+	blockPath := filepath.Join(w.root, tenant, id.String())
+
+	var state blockState
+	if _, err := os.Stat(filepath.Join(blockPath, backend.MetaName)); err == nil {
+		state |= blockStateMeta
+	}
+	if _, err := os.Stat(filepath.Join(blockPath, backend.CompactedMetaName)); err == nil {
+		state |= blockStateCompacted
+	}
+
+	if state == 0 {
+		delete(t.blocks, id)
+		return
+	}
+	t.blocks[id] = state
+}
+
+func (w *blockWatcher) invalidateAll() {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	for _, t := range w.tenants {
+		t.valid = false
+		t.scanning = false
+	}
+}
+
+// close stops watching. Tenants are scanned on every listing after the watcher is closed.
+func (w *blockWatcher) close() {
+	w.closeOnce.Do(func() {
+		close(w.done)
+		_ = w.watcher.Close()
+		w.invalidateAll()
+	})
+}
@@ -0,0 +1,118 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestListBlocksWatch(t *testing.T) {
+	ctx := context.Background()
+	tenant := "fake"
+
+	rw, err := NewBackend(&Config{
+		Path:  t.TempDir(),
+		Watch: true,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, rw.watcher)
+	t.Cleanup(rw.Shutdown)
+
+	writeMeta := func(id uuid.UUID) {
+		keypath := backend.KeyPathForBlock(id, tenant)
+		require.NoError(t, rw.Write(ctx, objectName, keypath, bytes.NewReader([]byte{0x01}), 1, nil))
+		require.NoError(t, rw.Write(ctx, backend.MetaName, keypath, bytes.NewReader([]byte("{}")), 2, nil))
+	}
+
+	// the blocks are eventually listed from notifications without scanning
+	requireWatched := func(expectedMetas, expectedCompacted []uuid.UUID) {
+		require.EventuallyWithT(t, func(c *assert.CollectT) {
+			metas, compacted, ok := rw.watcher.blocks(tenant)
+			assert.True(c, ok)
+			assert.ElementsMatch(c, expectedMetas, metas)
+			assert.ElementsMatch(c, expectedCompacted, compacted)
+		}, 5*time.Second, 10*time.Millisecond)
+
+		metas, compacted, err := rw.ListBlocks(ctx, tenant)
+		require.NoError(t, err)
+		require.ElementsMatch(t, expectedMetas, metas)
+		require.ElementsMatch(t, expectedCompacted, compacted)
+	}
+
+	blockA, blockB := uuid.New(), uuid.New()
+
+	// the first listing scans the tenant
+	writeMeta(blockA)
+	_, _, ok := rw.watcher.blocks(tenant)
+	require.False(t, ok)
+	metas, compacted, err := rw.ListBlocks(ctx, tenant)
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{blockA}, metas)
+	require.Empty(t, compacted)
+
+	writeMeta(blockB)
+	requireWatched([]uuid.UUID{blockA, blockB}, nil)
+
+	require.NoError(t, rw.MarkBlockCompacted(blockB, tenant))
+	requireWatched([]uuid.UUID{blockA}, []uuid.UUID{blockB})
+
+	require.NoError(t, rw.ClearBlock(blockA, tenant))
+	requireWatched(nil, []uuid.UUID{blockB})
+
+	// lost notifications fall back to scanning
+	rw.watcher.invalidateAll()
+	_, _, ok = rw.watcher.blocks(tenant)
+	require.False(t, ok)
+	metas, compacted, err = rw.ListBlocks(ctx, tenant)
+	require.NoError(t, err)
+	require.Empty(t, metas)
+	require.Equal(t, []uuid.UUID{blockB}, compacted)
+	requireWatched(nil, []uuid.UUID{blockB})
+
+	// no more watching after shutdown
+	rw.Shutdown()
+	_, _, ok = rw.watcher.blocks(tenant)
+	require.False(t, ok)
+}
+
+func TestListBlocksWatchResync(t *testing.T) {
+	ctx := context.Background()
+	tenant := "fake"
+
+	rw, err := NewBackend(&Config{
+		Path:              t.TempDir(),
+		Watch:             true,
+		WatchResyncPeriod: time.Hour,
+	})
+	require.NoError(t, err)
+	t.Cleanup(rw.Shutdown)
+
+	id := uuid.New()
+	require.NoError(t, rw.Write(ctx, backend.MetaName, backend.KeyPathForBlock(id, tenant), bytes.NewReader([]byte("{}")), 2, nil))
+
+	_, _, err = rw.ListBlocks(ctx, tenant)
+	require.NoError(t, err)
+	_, _, ok := rw.watcher.blocks(tenant)
+	require.True(t, ok)
+
+	// the tenant is scanned again once the resync period has passed
+	rw.watcher.mtx.Lock()
+	rw.watcher.tenants[tenant].syncedAt = time.Now().Add(-2 * time.Hour)
+	rw.watcher.mtx.Unlock()
+
+	_, _, ok = rw.watcher.blocks(tenant)
+	require.False(t, ok)
+
+	metas, _, err := rw.ListBlocks(ctx, tenant)
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{id}, metas)
+	_, _, ok = rw.watcher.blocks(tenant)
+	require.True(t, ok)
+}
@@ -279,6 +279,16 @@ func (m *MockWriter) CloseAppend(context.Context, AppendTracker) error {
 	return nil
 }
 
+func (m *MockWriter) ResumableStreamWriter(context.Context, string, uuid.UUID, string) io.WriteCloser {
+	return nopWriteCloser{io.Discard}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
 func (m *MockWriter) Delete(context.Context, string, KeyPath) error {
 	return nil
 }
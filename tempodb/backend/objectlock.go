@@ -0,0 +1,40 @@
+package backend
+
+import (
+	"time"
+)
+
+// ObjectLockConfig configures a retention lock (WORM) for the objects of blocks. Locked objects can't be deleted or
+// overwritten until the lock expires. Block metas and flags are never locked because compaction and retention
+// rewrite and delete them.
+type ObjectLockConfig struct {
+	// Mode is the retention mode of the backend, e.g. GOVERNANCE or COMPLIANCE on S3.
+	Mode string `yaml:"mode"`
+	// Retention is how long objects are locked after they are written. 0 disables object lock.
+	Retention time.Duration `yaml:"retention"`
+}
+
+// Enabled returns true if objects are locked when written.
+func (c *ObjectLockConfig) Enabled() bool {
+	return c != nil && c.Retention > 0
+}
+
+// RetainUntil returns the time until which an object written at now is locked.
+func (c *ObjectLockConfig) RetainUntil(now time.Time) time.Time {
+	return now.Add(c.Retention)
+}
+
+// Locks returns true if the object is locked when written. Only the data objects of blocks are locked.
+func (c *ObjectLockConfig) Locks(name string, keypath KeyPath) bool {
+	return c.Enabled() && IsBlockDataObject(name, keypath)
+}
+
+// LockedUntil returns the time until which the objects of a block that was compacted at compactedTime may still be
+// locked. All objects of a block are written before the block is compacted, so their locks expire at the latest one
+// retention after it.
+func (c *ObjectLockConfig) LockedUntil(compactedTime time.Time) time.Time {
+	if !c.Enabled() {
+		return time.Time{}
+	}
+	return compactedTime.Add(c.Retention)
+}
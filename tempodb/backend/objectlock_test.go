@@ -0,0 +1,49 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectLockLocks(t *testing.T) {
+	blockPath := KeyPathForBlock(uuid.New(), "tenant")
+	cfg := &ObjectLockConfig{Mode: "COMPLIANCE", Retention: time.Hour}
+
+	tests := []struct {
+		name     string
+		cfg      *ObjectLockConfig
+		object   string
+		keypath  KeyPath
+		expected bool
+	}{
+		{name: "data", cfg: cfg, object: "data.parquet", keypath: blockPath, expected: true},
+		{name: "bloom", cfg: cfg, object: "bloom-0", keypath: blockPath, expected: true},
+		{name: "meta", cfg: cfg, object: MetaName, keypath: blockPath},
+		{name: "compacted meta", cfg: cfg, object: CompactedMetaName, keypath: blockPath},
+		{name: "no compact flag", cfg: cfg, object: NoCompactFileName, keypath: blockPath},
+		{name: "tenant index", cfg: cfg, object: TenantIndexName, keypath: KeyPath{"tenant"}},
+		{name: "trash", cfg: cfg, object: "data.parquet", keypath: KeyPathForTrashedBlock(uuid.New(), "tenant")},
+		{name: "not a block", cfg: cfg, object: "data.parquet", keypath: KeyPath{"tenant", "blerg"}},
+		{name: "disabled", cfg: &ObjectLockConfig{Mode: "COMPLIANCE"}, object: "data.parquet", keypath: blockPath},
+		{name: "nil", object: "data.parquet", keypath: blockPath},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.cfg.Locks(tc.object, tc.keypath))
+		})
+	}
+}
+
+func TestObjectLockLockedUntil(t *testing.T) {
+	compacted := time.Now()
+
+	var cfg *ObjectLockConfig
+	require.True(t, cfg.LockedUntil(compacted).IsZero())
+
+	cfg = &ObjectLockConfig{Mode: "COMPLIANCE", Retention: time.Hour}
+	require.Equal(t, compacted.Add(time.Hour), cfg.LockedUntil(compacted))
+}
@@ -0,0 +1,80 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	tempo_io "github.com/grafana/tempo/pkg/io"
+)
+
+type OffboardingState string
+
+const (
+	// OffboardingStatePending means writes are rejected and data will be deleted once the confirmation window has passed.
+	OffboardingStatePending OffboardingState = "pending"
+	// OffboardingStateDeleting means all blocks have been marked compacted and objects are being deleted.
+	OffboardingStateDeleting OffboardingState = "deleting"
+	// OffboardingStateComplete means all tenant objects other than the offboarding record have been deleted.
+	OffboardingStateComplete OffboardingState = "complete"
+	// OffboardingStateCancelled means the offboarding was cancelled during the confirmation window.
+	OffboardingStateCancelled OffboardingState = "cancelled"
+)
+
+// TenantOffboarding is the record of a tenant offboarding. It is stored in the tenant path and is kept after
+// the tenant data is deleted as the final report.
+type TenantOffboarding struct {
+	TenantID    string            `json:"tenant_id"`
+	State       OffboardingState  `json:"state"`
+	RequestedAt time.Time         `json:"requested_at"`
+	DeleteAfter time.Time         `json:"delete_after"`
+	CompletedAt time.Time         `json:"completed_at,omitempty"`
+	Report      OffboardingReport `json:"report"`
+}
+
+// OffboardingReport accumulates what was removed while offboarding a tenant.
+type OffboardingReport struct {
+	MarkedBlocks   int    `json:"marked_blocks"`
+	DeletedBlocks  int    `json:"deleted_blocks"`
+	DeletedObjects int    `json:"deleted_objects"`
+	DeletedBytes   uint64 `json:"deleted_bytes"`
+}
+
+// AcceptsWrites returns false if the tenant is being, or has been, offboarded.
+func (o *TenantOffboarding) AcceptsWrites() bool {
+	return o == nil || o.State == OffboardingStateCancelled
+}
+
+// ReadTenantOffboarding reads the offboarding record for the tenant. ErrDoesNotExist is returned if the tenant
+// has never been offboarded.
+func ReadTenantOffboarding(ctx context.Context, r RawReader, tenantID string) (*TenantOffboarding, error) {
+	reader, size, err := r.Read(ctx, OffboardingFileName, KeyPath{tenantID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	b, err := tempo_io.ReadAllWithEstimate(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &TenantOffboarding{}
+	err = json.Unmarshal(b, out)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// WriteTenantOffboarding writes the offboarding record to the tenant path.
+func WriteTenantOffboarding(ctx context.Context, w RawWriter, o *TenantOffboarding) error {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+
+	return w.Write(ctx, OffboardingFileName, KeyPath{o.TenantID}, bytes.NewReader(b), int64(len(b)), nil)
+}
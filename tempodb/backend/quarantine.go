@@ -0,0 +1,68 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// QuarantineKeyPath is the path in the tenant that blocks that can't be read are moved to, i.e.
+	// <tenant>/__quarantine__/<blockID>/
+	QuarantineKeyPath = "__quarantine__"
+
+	// QuarantinedMetaName is the marker of a block in quarantine
+	QuarantinedMetaName = "meta.quarantined.json"
+)
+
+// QuarantinedBlockMeta is the marker written to a block when it's moved to quarantine. It holds the meta of the
+// block so it can be inspected or restored by hand.
+type QuarantinedBlockMeta struct {
+	BlockMeta
+	QuarantinedTime time.Time `json:"quarantinedTime"`
+	Reason          string    `json:"reason"`
+}
+
+// KeyPathForQuarantinedBlock returns the keypath of a block in quarantine.
+func KeyPathForQuarantinedBlock(blockID uuid.UUID, tenantID string) KeyPath {
+	return KeyPath{tenantID, QuarantineKeyPath, blockID.String()}
+}
+
+// MoveBlockToQuarantine copies all objects of the block to the quarantine of the tenant and clears the block, like
+// MoveBlockToTrash. Quarantined blocks are never purged.
+func MoveBlockToQuarantine(ctx context.Context, r RawReader, w RawWriter, c Compactor, meta *BlockMeta, reason string, quarantinedTime time.Time) error {
+	blockID, tenantID := (uuid.UUID)(meta.BlockID), meta.TenantID
+
+	m := &QuarantinedBlockMeta{
+		BlockMeta:       *meta,
+		QuarantinedTime: quarantinedTime,
+		Reason:          reason,
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	quarantinePath := KeyPathForQuarantinedBlock(blockID, tenantID)
+	err = w.Write(ctx, QuarantinedMetaName, quarantinePath, bytes.NewReader(b), int64(len(b)), nil)
+	if err != nil {
+		return fmt.Errorf("error writing quarantine marker: %w", err)
+	}
+
+	blockPath := KeyPathForBlock(blockID, tenantID)
+	names, err := objectNames(ctx, r, blockPath)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := copyObject(ctx, r, w, name, blockPath, quarantinePath); err != nil {
+			return fmt.Errorf("error copying %s to quarantine: %w", name, err)
+		}
+	}
+
+	return c.ClearBlock(blockID, tenantID)
+}
@@ -30,8 +30,29 @@ const (
 	// File name for the work cache
 	WorkFileName = "work.json"
 
+	// Directory of the query audit log of the tenants, next to the tenant directories
+	QueryAuditDirName = "tempo_query_audit"
+
 	// File name for the nocompact flag
 	NoCompactFileName = "nocompact.flg"
+
+	// File name for the tenant offboarding record
+	OffboardingFileName = "offboarding.json"
+
+	// File name for the attribute redactions of a tenant
+	RedactionsFileName = "redactions.json"
+
+	// File name for the dedicated columns recommended for a tenant
+	DedicatedColumnsRecommendationFileName = "dedicated_columns.json"
+
+	// File name for the heartbeat of the tenant index builder
+	TenantIndexHeartbeatName = "index.heartbeat.json"
+
+	// File name for the dedupe report of a compacted block
+	DedupeReportName = "dedupe_report.json"
+
+	// File name for the usage report of a tenant
+	UsageReportName = "usage_report.json"
 )
 
 // KeyPath is an ordered set of strings that govern where data is read/written
@@ -46,6 +67,7 @@ type FindFunc func(FindMatch)
 type FindMatch struct {
 	Modified time.Time
 	Key      string
+	Size     int64
 }
 
 // RawWriter is a collection of methods to write data to tempodb backends
@@ -129,6 +151,11 @@ func (w *writer) CloseAppend(ctx context.Context, tracker AppendTracker) error {
 	return w.w.CloseAppend(ctx, tracker)
 }
 
+// ResumableStreamWriter implements backend.Writer
+func (w *writer) ResumableStreamWriter(ctx context.Context, name string, blockID uuid.UUID, tenantID string) io.WriteCloser {
+	return newResumableStreamWriter(ctx, w.w, name, KeyPathForBlock(blockID, tenantID))
+}
+
 // Write implements backend.Writer
 func (w *writer) WriteTenantIndex(ctx context.Context, tenantID string, meta []*BlockMeta, compactedMeta []*CompactedBlockMeta) error {
 	// If meta and compactedMeta are empty, call delete the tenant index.
@@ -185,7 +212,8 @@ func (w *writer) DeleteNoCompactFlag(ctx context.Context, blockID uuid.UUID, ten
 }
 
 type reader struct {
-	r RawReader
+	r     RawReader
+	metas *blockMetaCache
 }
 
 // NewReader returns an object that implements Reader and bridges to a RawReader
@@ -195,6 +223,21 @@ func NewReader(r RawReader) Reader {
 	}
 }
 
+// NewReaderWithBlockMetaCache returns a Reader that keeps up to size block metas along with the version they were
+// read at. Cached metas are revalidated with a conditional read so unchanged metas are not downloaded again. If the
+// RawReader does not implement ConditionalReader or size is 0 this is the same as NewReader.
+func NewReaderWithBlockMetaCache(r RawReader, size int) Reader {
+	cr, ok := r.(ConditionalReader)
+	if !ok || size <= 0 {
+		return NewReader(r)
+	}
+
+	return &reader{
+		r:     r,
+		metas: newBlockMetaCache(cr, size),
+	}
+}
+
 // Read implements backend.Reader
 func (r *reader) Read(ctx context.Context, name string, blockID uuid.UUID, tenantID string, cacheInfo *CacheInfo) ([]byte, error) {
 	objReader, size, err := r.r.Read(ctx, name, KeyPathForBlock(blockID, tenantID), cacheInfo)
@@ -202,7 +245,9 @@ func (r *reader) Read(ctx context.Context, name string, blockID uuid.UUID, tenan
 		return nil, err
 	}
 	defer objReader.Close()
-	return tempo_io.ReadAllWithEstimate(objReader, size)
+	b, err := tempo_io.ReadAllWithEstimate(objReader, size)
+	IOStatsFromContext(ctx).Add(cacheInfo, uint64(len(b)))
+	return b, err
 }
 
 // StreamReader implements backend.Reader
@@ -212,7 +257,11 @@ func (r *reader) StreamReader(ctx context.Context, name string, blockID uuid.UUI
 
 // ReadRange implements backend.Reader
 func (r *reader) ReadRange(ctx context.Context, name string, blockID uuid.UUID, tenantID string, offset uint64, buffer []byte, cacheInfo *CacheInfo) error {
-	return r.r.ReadRange(ctx, name, KeyPathForBlock(blockID, tenantID), offset, buffer, cacheInfo)
+	err := r.r.ReadRange(ctx, name, KeyPathForBlock(blockID, tenantID), offset, buffer, cacheInfo)
+	if err == nil {
+		IOStatsFromContext(ctx).Add(cacheInfo, uint64(len(buffer)))
+	}
+	return err
 }
 
 // Tenants implements backend.Reader
@@ -222,7 +271,7 @@ func (r *reader) Tenants(ctx context.Context) ([]string, error) {
 	// this filter is added to fix a GCS usage stats issue that would result in ""
 	var filteredList []string
 	for _, tenant := range list {
-		if tenant != "" && tenant != ClusterSeedFileName && tenant != WorkFileName {
+		if tenant != "" && tenant != ClusterSeedFileName && tenant != WorkFileName && tenant != QueryAuditDirName {
 			filteredList = append(filteredList, tenant)
 		}
 	}
@@ -237,6 +286,10 @@ func (r *reader) Blocks(ctx context.Context, tenantID string) ([]uuid.UUID, []uu
 
 // BlockMeta implements backend.Reader
 func (r *reader) BlockMeta(ctx context.Context, blockID uuid.UUID, tenantID string) (*BlockMeta, error) {
+	if r.metas != nil {
+		return r.metas.blockMeta(ctx, blockID, tenantID)
+	}
+
 	reader, size, err := r.r.Read(ctx, MetaName, KeyPathForBlock(blockID, tenantID), nil)
 	if err != nil {
 		return nil, err
@@ -14,6 +14,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/cache"
 )
 
 const (
@@ -152,6 +154,35 @@ func TestReader(t *testing.T) {
 	assert.True(t, cmp.Equal(expectedIdx, idx))
 }
 
+func TestReaderIOStats(t *testing.T) {
+	m := &MockRawReader{R: make([]byte, 10), Range: make([]byte, 4)}
+	r := NewReader(m)
+
+	ctx, stats := ContextWithIOStats(context.Background())
+	nested, nestedStats := ContextWithIOStats(ctx)
+	require.Equal(t, ctx, nested)
+	require.Same(t, stats, nestedStats)
+
+	_, err := r.Read(ctx, "test", uuid.New(), "test", &CacheInfo{Role: cache.RoleBloom})
+	require.NoError(t, err)
+	_, err = r.Read(ctx, "test", uuid.New(), "test", &CacheInfo{Role: cache.RoleTraceIDIdx})
+	require.NoError(t, err)
+	require.NoError(t, r.ReadRange(ctx, "test", uuid.New(), "test", 0, make([]byte, 4), &CacheInfo{Role: cache.RoleParquetFooter}))
+	require.NoError(t, r.ReadRange(ctx, "test", uuid.New(), "test", 0, make([]byte, 4), &CacheInfo{Role: cache.RoleParquetPage}))
+	require.NoError(t, r.ReadRange(ctx, "test", uuid.New(), "test", 0, make([]byte, 4), nil))
+
+	require.Equal(t, uint64(10), stats.BloomBytes())
+	require.Equal(t, uint64(14), stats.IndexBytes())
+	require.Equal(t, uint64(8), stats.ColumnBytes())
+	require.Equal(t, uint64(32), stats.TotalBytes())
+
+	// reads without stats in the context aren't recorded
+	_, err = r.Read(context.Background(), "test", uuid.New(), "test", nil)
+	require.NoError(t, err)
+	require.Nil(t, IOStatsFromContext(context.Background()))
+	require.Equal(t, uint64(32), stats.TotalBytes())
+}
+
 func TestKeyPathForBlock(t *testing.T) {
 	b := uuid.New()
 	tid := tenantID
@@ -0,0 +1,82 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	tempo_io "github.com/grafana/tempo/pkg/io"
+)
+
+type RedactionState string
+
+const (
+	// RedactionStateRunning means the blocks of the time range are being rewritten.
+	RedactionStateRunning RedactionState = "running"
+	// RedactionStateComplete means no block of the time range contains the value anymore.
+	RedactionStateComplete RedactionState = "complete"
+	// RedactionStateCancelled means the redaction was cancelled before completing.
+	RedactionStateCancelled RedactionState = "cancelled"
+)
+
+// Redaction is the record of a job replacing the values of an attribute key in the blocks of a tenant. The records
+// of a tenant are stored in its path and kept once complete as the audit trail. The value is only stored while the
+// job is running, the SHA-256 of the value identifies it afterwards.
+type Redaction struct {
+	ID          string            `json:"id"`
+	Key         string            `json:"key"`
+	Value       string            `json:"value,omitempty"`
+	ValueSHA256 string            `json:"value_sha256"`
+	Start       time.Time         `json:"start"`
+	End         time.Time         `json:"end"`
+	Reason      string            `json:"reason,omitempty"`
+	RequestedBy string            `json:"requested_by,omitempty"`
+	State       RedactionState    `json:"state"`
+	RequestedAt time.Time         `json:"requested_at"`
+	CompletedAt time.Time         `json:"completed_at,omitempty"`
+	Progress    RedactionProgress `json:"progress"`
+	// Blocks are the blocks written or skipped by the redaction, they are never rewritten again.
+	Blocks []UUID `json:"blocks,omitempty"`
+}
+
+// RedactionProgress accumulates the blocks processed by a redaction.
+type RedactionProgress struct {
+	PendingBlocks   int `json:"pending_blocks"`
+	RewrittenBlocks int `json:"rewritten_blocks"`
+	SkippedBlocks   int `json:"skipped_blocks"`
+	RedactedValues  int `json:"redacted_values"`
+}
+
+// ReadTenantRedactions reads the redaction records of the tenant. ErrDoesNotExist is returned if the tenant has
+// never been redacted.
+func ReadTenantRedactions(ctx context.Context, r RawReader, tenantID string) ([]*Redaction, error) {
+	reader, size, err := r.Read(ctx, RedactionsFileName, KeyPath{tenantID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	b, err := tempo_io.ReadAllWithEstimate(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*Redaction
+	err = json.Unmarshal(b, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// WriteTenantRedactions writes the redaction records to the tenant path.
+func WriteTenantRedactions(ctx context.Context, w RawWriter, tenantID string, redactions []*Redaction) error {
+	b, err := json.Marshal(redactions)
+	if err != nil {
+		return err
+	}
+
+	return w.Write(ctx, RedactionsFileName, KeyPath{tenantID}, bytes.NewReader(b), int64(len(b)), nil)
+}
@@ -0,0 +1,97 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/grafana/dskit/backoff"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var metricResumedAppends = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "tempodb",
+	Name:      "backend_resumed_appends_total",
+	Help:      "Total number of failed appends that were retried from the last checkpoint of the upload.",
+})
+
+// ResumableAppender is implemented by backends whose Append jobs are checkpointed after every append, like multipart
+// uploads, so a failed append can be retried without restarting the object.
+type ResumableAppender interface {
+	// ResumableAppend is the same as Append except that a failed append returns a checkpoint of the job before the
+	// failed call. Passing it to ResumableAppend again with the same buffer resumes the job. A nil checkpoint means the
+	// job can't be resumed.
+	ResumableAppend(ctx context.Context, name string, keypath KeyPath, tracker AppendTracker, buffer []byte) (AppendTracker, error)
+}
+
+// resumableAppendBackoff is the backoff between attempts to append a buffer to a resumable job.
+var resumableAppendBackoff = backoff.Config{
+	MinBackoff: 500 * time.Millisecond,
+	MaxBackoff: 10 * time.Second,
+	MaxRetries: 5,
+}
+
+// resumableStreamWriter streams data to an object with an Append job. Every write is appended to the job and retried
+// from the last checkpoint if the backend supports resuming it.
+type resumableStreamWriter struct {
+	ctx     context.Context
+	w       RawWriter
+	name    string
+	keypath KeyPath
+	tracker AppendTracker
+}
+
+var _ io.WriteCloser = (*resumableStreamWriter)(nil)
+
+func newResumableStreamWriter(ctx context.Context, w RawWriter, name string, keypath KeyPath) *resumableStreamWriter {
+	return &resumableStreamWriter{
+		ctx:     ctx,
+		w:       w,
+		name:    name,
+		keypath: keypath,
+	}
+}
+
+func (s *resumableStreamWriter) Write(p []byte) (int, error) {
+	ra, ok := s.w.(ResumableAppender)
+	if !ok {
+		var err error
+		s.tracker, err = s.w.Append(s.ctx, s.name, s.keypath, s.tracker, p)
+		if err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	var (
+		b   = backoff.New(s.ctx, resumableAppendBackoff)
+		err error
+	)
+	for b.Ongoing() {
+		if b.NumRetries() > 0 {
+			metricResumedAppends.Inc()
+		}
+		started := s.tracker != nil
+
+		var checkpoint AppendTracker
+		checkpoint, err = ra.ResumableAppend(s.ctx, s.name, s.keypath, s.tracker, p)
+		if err == nil {
+			s.tracker = checkpoint
+			return len(p), nil
+		}
+		if checkpoint == nil && started {
+			return 0, fmt.Errorf("error appending to %s, upload can't be resumed: %w", s.name, err)
+		}
+
+		s.tracker = checkpoint
+		b.Wait()
+	}
+
+	return 0, fmt.Errorf("error appending to %s after %d attempts: %w", s.name, b.NumRetries(), err)
+}
+
+func (s *resumableStreamWriter) Close() error {
+	return s.w.CloseAppend(s.ctx, s.tracker)
+}
@@ -0,0 +1,106 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAppendJob struct {
+	parts [][]byte
+}
+
+// fakeAppender is a RawWriter whose appends fail the configured number of times before succeeding.
+type fakeAppender struct {
+	MockRawWriter
+	resumable bool
+	failures  int
+	calls     int
+	closed    *fakeAppendJob
+}
+
+func (f *fakeAppender) Append(_ context.Context, _ string, _ KeyPath, tracker AppendTracker, buffer []byte) (AppendTracker, error) {
+	f.calls++
+	if f.failures > 0 {
+		f.failures--
+		return nil, errors.New("append failed")
+	}
+	return f.append(tracker, buffer), nil
+}
+
+func (f *fakeAppender) CloseAppend(_ context.Context, tracker AppendTracker) error {
+	f.closed = tracker.(*fakeAppendJob)
+	return nil
+}
+
+func (f *fakeAppender) append(tracker AppendTracker, buffer []byte) *fakeAppendJob {
+	job := &fakeAppendJob{}
+	if tracker != nil {
+		job.parts = append(job.parts, tracker.(*fakeAppendJob).parts...)
+	}
+	job.parts = append(job.parts, append([]byte(nil), buffer...))
+	return job
+}
+
+type fakeResumableAppender struct {
+	fakeAppender
+}
+
+func (f *fakeResumableAppender) ResumableAppend(_ context.Context, _ string, _ KeyPath, tracker AppendTracker, buffer []byte) (AppendTracker, error) {
+	f.calls++
+	if f.failures > 0 {
+		f.failures--
+		return tracker, errors.New("append failed")
+	}
+	return f.append(tracker, buffer), nil
+}
+
+func TestResumableStreamWriter(t *testing.T) {
+	cfg := resumableAppendBackoff
+	t.Cleanup(func() { resumableAppendBackoff = cfg })
+	resumableAppendBackoff.MinBackoff = time.Millisecond
+	resumableAppendBackoff.MaxBackoff = time.Millisecond
+
+	t.Run("resumes failed appends", func(t *testing.T) {
+		w := &fakeResumableAppender{}
+		s := newResumableStreamWriter(context.Background(), w, "data", KeyPath{"tenant", "block"})
+
+		_, err := s.Write([]byte("a"))
+		require.NoError(t, err)
+
+		w.failures = 2
+		n, err := s.Write([]byte("b"))
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+		require.NoError(t, s.Close())
+
+		require.Equal(t, 4, w.calls)
+		require.Equal(t, [][]byte{[]byte("a"), []byte("b")}, w.closed.parts)
+	})
+
+	t.Run("fails after max retries", func(t *testing.T) {
+		w := &fakeResumableAppender{}
+		w.failures = resumableAppendBackoff.MaxRetries + 1
+		s := newResumableStreamWriter(context.Background(), w, "data", KeyPath{"tenant", "block"})
+
+		_, err := s.Write([]byte("a"))
+		require.Error(t, err)
+		require.Equal(t, resumableAppendBackoff.MaxRetries, w.calls)
+	})
+
+	t.Run("fails when the job can't be resumed", func(t *testing.T) {
+		w := &fakeAppender{}
+		s := newResumableStreamWriter(context.Background(), w, "data", KeyPath{"tenant", "block"})
+
+		_, err := s.Write([]byte("a"))
+		require.NoError(t, err)
+
+		w.failures = 1
+		_, err = s.Write([]byte("b"))
+		require.Error(t, err)
+		require.Equal(t, 2, w.calls)
+	})
+}
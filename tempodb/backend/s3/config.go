@@ -11,6 +11,8 @@ import (
 	"github.com/grafana/dskit/flagext"
 
 	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/credentials"
 )
 
 const (
@@ -24,6 +26,11 @@ const (
 	// SSES3 config type constant to configure S3 server side encryption with AES-256
 	// https://docs.aws.amazon.com/AmazonS3/latest/dev/UsingServerSideEncryption.html
 	SSES3 = "SSE-S3"
+
+	// keys of the credentials read from a credentials provider
+	CredentialsKeyAccessKey    = "access_key"
+	CredentialsKeySecretKey    = "secret_key"
+	CredentialsKeySessionToken = "session_token"
 )
 
 var (
@@ -52,6 +59,8 @@ type Config struct {
 	PartSize          uint64         `yaml:"part_size"`
 	HedgeRequestsAt   time.Duration  `yaml:"hedge_requests_at"`
 	HedgeRequestsUpTo int            `yaml:"hedge_requests_up_to"`
+	// HedgeRequestsRoles limits hedging to the reads of these roles
+	HedgeRequestsRoles backend.HedgedReadRoles `yaml:"hedge_requests_roles"`
 	// SignatureV2 configures the object storage to use V2 signing instead of V4
 	SignatureV2      bool              `yaml:"signature_v2"`
 	ForcePathStyle   bool              `yaml:"forcepathstyle"`
@@ -65,6 +74,11 @@ type Config struct {
 	NativeAWSAuthEnabled  bool      `yaml:"native_aws_auth_enabled"`
 	ListBlocksConcurrency int       `yaml:"list_blocks_concurrency"`
 	SSE                   SSEConfig `yaml:"sse"`
+
+	// Credentials reads the access key, secret key and session token from a provider instead of the static keys
+	Credentials credentials.Config `yaml:"credentials"`
+
+	ObjectLock backend.ObjectLockConfig `yaml:"object_lock"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
@@ -76,6 +90,7 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	f.Var(&cfg.SecretKey, util.PrefixConfig(prefix, "s3.secret_key"), "s3 secret key.")
 	f.Var(&cfg.SessionToken, util.PrefixConfig(prefix, "s3.session_token"), "s3 session token.")
 	f.IntVar(&cfg.ListBlocksConcurrency, util.PrefixConfig(prefix, "s3.list_blocks_concurrency"), 3, "number of concurrent list calls to make to backend")
+	cfg.Credentials.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "s3.credentials"), f)
 
 	f.StringVar(&cfg.SSE.Type, util.PrefixConfig(prefix, "s3.sse.type"), "", fmt.Sprintf("Enable AWS Server Side Encryption. Supported values: %s.", strings.Join(supportedSSETypes, ", ")))
 	f.StringVar(&cfg.SSE.KMSKeyID, util.PrefixConfig(prefix, "s3.sse.kms-key-id"), "", "KMS Key ID used to encrypt objects in S3")
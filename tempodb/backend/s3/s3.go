@@ -3,6 +3,8 @@ package s3
 import (
 	"bytes"
 	"context"
+	"crypto/md5" //nolint:gosec // S3 requires a content MD5 for objects with a retention
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,6 +14,8 @@ import (
 	"path"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
@@ -33,6 +37,7 @@ import (
 	tempo_io "github.com/grafana/tempo/pkg/io"
 	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/tempodb/backend"
+	tempo_credentials "github.com/grafana/tempo/tempodb/backend/credentials"
 )
 
 // readerWriter can read/write from an s3 backend
@@ -42,6 +47,7 @@ type readerWriter struct {
 	core       *minio.Core
 	hedgedCore *minio.Core
 	sse        encrypt.ServerSide
+	creds      *tempo_credentials.Credentials
 }
 
 var tracer = otel.Tracer("tempodb/backend/s3")
@@ -51,6 +57,7 @@ var (
 	_ backend.RawWriter             = (*readerWriter)(nil)
 	_ backend.Compactor             = (*readerWriter)(nil)
 	_ backend.VersionedReaderWriter = (*readerWriter)(nil)
+	_ backend.ResumableAppender     = (*readerWriter)(nil)
 )
 
 // appendTracker is a struct used to track multipart uploads
@@ -96,6 +103,37 @@ func (s *overrideSignatureVersion) IsExpired() bool {
 	return s.upstream.IsExpired()
 }
 
+// rotatingCredentials are the keys read from a credentials provider. They expire when the provider rotates them, so
+// minio retrieves the new keys on the next request.
+type rotatingCredentials struct {
+	creds   *tempo_credentials.Credentials
+	version atomic.Uint64
+}
+
+func (r *rotatingCredentials) RetrieveWithCredContext(*credentials.CredContext) (credentials.Value, error) {
+	values, version := r.creds.Get()
+	r.version.Store(version)
+
+	if values[CredentialsKeyAccessKey] == "" || values[CredentialsKeySecretKey] == "" {
+		return credentials.Value{}, fmt.Errorf("credentials %s and %s are required", CredentialsKeyAccessKey, CredentialsKeySecretKey)
+	}
+	return credentials.Value{
+		AccessKeyID:     values[CredentialsKeyAccessKey],
+		SecretAccessKey: values[CredentialsKeySecretKey],
+		SessionToken:    values[CredentialsKeySessionToken],
+		SignerType:      credentials.SignatureV4,
+	}, nil
+}
+
+func (r *rotatingCredentials) Retrieve() (credentials.Value, error) {
+	return r.RetrieveWithCredContext(nil)
+}
+
+func (r *rotatingCredentials) IsExpired() bool {
+	_, version := r.creds.Get()
+	return version != r.version.Load()
+}
+
 // NewNoConfirm gets the S3 backend without testing it
 func NewNoConfirm(cfg *Config) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
 	rw, err := internalNew(cfg, false)
@@ -114,19 +152,29 @@ func NewVersionedReaderWriter(cfg *Config) (backend.VersionedReaderWriter, error
 	return internalNew(cfg, true)
 }
 
-func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
+func internalNew(cfg *Config, confirm bool) (rw *readerWriter, err error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("config is nil")
 	}
 
 	l := log.Logger
 
-	core, err := createCore(cfg, false)
+	creds, err := tempo_credentials.New(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			creds.Stop()
+		}
+	}()
+
+	core, err := createCore(cfg, creds, false)
 	if err != nil {
 		return nil, fmt.Errorf("unexpected error creating core: %w", err)
 	}
 
-	hedgedCore, err := createCore(cfg, true)
+	hedgedCore, err := createCore(cfg, creds, true)
 	if err != nil {
 		return nil, fmt.Errorf("unexpected error creating hedgedCore: %w", err)
 	}
@@ -144,12 +192,17 @@ func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
 		return nil, fmt.Errorf("returned Error when trying to configure Server Side Encryption: %w", err)
 	}
 
-	rw := &readerWriter{
+	if cfg.ObjectLock.Enabled() && !minio.RetentionMode(cfg.ObjectLock.Mode).IsValid() {
+		return nil, fmt.Errorf("unsupported object lock mode %q, supported modes are %s and %s", cfg.ObjectLock.Mode, minio.Governance, minio.Compliance)
+	}
+
+	rw = &readerWriter{
 		logger:     l,
 		cfg:        cfg,
 		core:       core,
 		hedgedCore: hedgedCore,
 		sse:        encryption,
+		creds:      creds,
 	}
 
 	return rw, nil
@@ -165,6 +218,18 @@ func getPutObjectOptions(rw *readerWriter) minio.PutObjectOptions {
 	}
 }
 
+// lockObject sets the object lock retention of the object if it's locked.
+func lockObject(rw *readerWriter, options *minio.PutObjectOptions, name string, keypath backend.KeyPath) {
+	if !rw.cfg.ObjectLock.Locks(name, keypath) {
+		return
+	}
+
+	options.Mode = minio.RetentionMode(rw.cfg.ObjectLock.Mode)
+	options.RetainUntilDate = rw.cfg.ObjectLock.RetainUntil(time.Now())
+	// objects with a retention must be uploaded with a content MD5
+	options.SendContentMd5 = true
+}
+
 func getObjectOptions(rw *readerWriter) minio.GetObjectOptions {
 	return minio.GetObjectOptions{
 		ServerSideEncryption: rw.sse,
@@ -178,11 +243,12 @@ func (rw *readerWriter) Write(ctx context.Context, name string, keypath backend.
 
 	span.SetAttributes(attribute.String("object", name))
 
+	putObjectOptions := getPutObjectOptions(rw)
+	lockObject(rw, &putObjectOptions, name, keypath)
+
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 	objName := backend.ObjectFileName(keypath, name)
 
-	putObjectOptions := getPutObjectOptions(rw)
-
 	info, err := rw.core.Client.PutObject(
 		derivedCtx,
 		rw.cfg.Bucket,
@@ -202,16 +268,23 @@ func (rw *readerWriter) Write(ctx context.Context, name string, keypath backend.
 
 // AppendObject implements backend.Writer
 func (rw *readerWriter) Append(ctx context.Context, name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
+	return rw.ResumableAppend(ctx, name, keypath, tracker, buffer)
+}
+
+// ResumableAppend implements backend.ResumableAppender. Every append uploads a part of a multipart upload. A part that
+// fails to upload doesn't advance the tracker, so retrying the append uploads it again with the same part number.
+func (rw *readerWriter) ResumableAppend(ctx context.Context, name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
 	ctx, span := tracer.Start(ctx, "s3.Append", trace.WithAttributes(
 		attribute.Int("len", len(buffer)),
 	))
 	defer span.End()
 
 	var a appendTracker
+	options := getPutObjectOptions(rw)
+	lockObject(rw, &options, name, keypath)
+
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 	objectName := backend.ObjectFileName(keypath, name)
-
-	options := getPutObjectOptions(rw)
 	if tracker != nil {
 		a = tracker.(appendTracker)
 	} else {
@@ -230,20 +303,26 @@ func (rw *readerWriter) Append(ctx context.Context, name string, keypath backend
 
 	level.Debug(rw.logger).Log("msg", "appending object to s3", "objectName", objectName)
 
-	a.partNum++
+	var partOptions minio.PutObjectPartOptions
+	if options.SendContentMd5 {
+		sum := md5.Sum(buffer) //nolint:gosec
+		partOptions.Md5Base64 = base64.StdEncoding.EncodeToString(sum[:])
+	}
+
 	objPart, err := rw.core.PutObjectPart(
 		ctx,
 		rw.cfg.Bucket,
 		objectName,
 		a.uploadID,
-		a.partNum,
+		a.partNum+1,
 		bytes.NewReader(buffer),
 		int64(len(buffer)),
-		minio.PutObjectPartOptions{},
+		partOptions,
 	)
 	if err != nil {
 		return a, fmt.Errorf("error in multipart upload: %w", err)
 	}
+	a.partNum++
 	a.parts = append(a.parts, objPart)
 
 	return a, nil
@@ -457,6 +536,7 @@ func (rw *readerWriter) Find(ctx context.Context, keypath backend.KeyPath, f bac
 					opts := backend.FindMatch{
 						Key:      c.Key,
 						Modified: c.LastModified,
+						Size:     c.Size,
 					}
 					f(opts)
 				}
@@ -468,12 +548,12 @@ func (rw *readerWriter) Find(ctx context.Context, keypath backend.KeyPath, f bac
 }
 
 // Read implements backend.Reader
-func (rw *readerWriter) Read(ctx context.Context, name string, keypath backend.KeyPath, _ *backend.CacheInfo) (io.ReadCloser, int64, error) {
+func (rw *readerWriter) Read(ctx context.Context, name string, keypath backend.KeyPath, cacheInfo *backend.CacheInfo) (io.ReadCloser, int64, error) {
 	derivedCtx, span := tracer.Start(ctx, "s3.Read")
 	defer span.End()
 
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
-	b, err := rw.readAll(derivedCtx, backend.ObjectFileName(keypath, name))
+	b, err := rw.readAll(derivedCtx, rw.readCore(cacheInfo, false), backend.ObjectFileName(keypath, name))
 	if err != nil {
 		return nil, 0, readError(err)
 	}
@@ -482,7 +562,7 @@ func (rw *readerWriter) Read(ctx context.Context, name string, keypath backend.K
 }
 
 // ReadRange implements backend.Reader
-func (rw *readerWriter) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, _ *backend.CacheInfo) error {
+func (rw *readerWriter) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, cacheInfo *backend.CacheInfo) error {
 	derivedCtx, span := tracer.Start(ctx, "s3.ReadRange", trace.WithAttributes(
 		attribute.Int("len", len(buffer)),
 		attribute.Int64("offset", int64(offset)),
@@ -490,11 +570,12 @@ func (rw *readerWriter) ReadRange(ctx context.Context, name string, keypath back
 	defer span.End()
 
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
-	return readError(rw.readRange(derivedCtx, backend.ObjectFileName(keypath, name), int64(offset), buffer))
+	return readError(rw.readRange(derivedCtx, rw.readCore(cacheInfo, true), backend.ObjectFileName(keypath, name), int64(offset), buffer))
 }
 
 // Shutdown implements backend.Reader
 func (rw *readerWriter) Shutdown() {
+	rw.creds.Stop()
 }
 
 func (rw *readerWriter) WriteVersioned(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, size int64, version backend.Version) (backend.Version, error) {
@@ -556,9 +637,51 @@ func (rw *readerWriter) ReadVersioned(ctx context.Context, name string, keypath
 	return io.NopCloser(bytes.NewReader(b)), backend.Version(objectInfo.ETag), nil
 }
 
-func (rw *readerWriter) readAll(ctx context.Context, name string) ([]byte, error) {
+// ReadIfChanged implements backend.ConditionalReader. The version is the ETag of the object and is sent as
+// If-None-Match so an unchanged object is answered with a 304 and no body.
+func (rw *readerWriter) ReadIfChanged(ctx context.Context, name string, keypath backend.KeyPath, version backend.Version) (io.ReadCloser, int64, backend.Version, error) {
+	derivedCtx, span := tracer.Start(ctx, "s3.ReadIfChanged")
+	defer span.End()
+
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	objName := backend.ObjectFileName(keypath, name)
+
 	options := getObjectOptions(rw)
-	reader, info, _, err := rw.hedgedCore.GetObject(ctx, rw.cfg.Bucket, name, options)
+	if version != "" {
+		err := options.SetMatchETagExcept(string(version))
+		if err != nil {
+			return nil, 0, "", fmt.Errorf("error setting headers for conditional read in s3: %w", err)
+		}
+	}
+
+	reader, info, _, err := rw.hedgedCore.GetObject(derivedCtx, rw.cfg.Bucket, objName, options)
+	if err != nil {
+		if minio.ToErrorResponse(err).StatusCode == http.StatusNotModified {
+			return nil, 0, "", backend.ErrNotModified
+		}
+		return nil, 0, "", readError(err)
+	}
+	defer reader.Close()
+
+	b, err := tempo_io.ReadAllWithEstimate(reader, info.Size)
+	if err != nil {
+		return nil, 0, "", fmt.Errorf("error reading response from s3 backend: %w", err)
+	}
+
+	return io.NopCloser(bytes.NewReader(b)), int64(len(b)), backend.Version(info.ETag), nil
+}
+
+// readCore returns the core of a Read or ReadRange, the hedged core unless the role of the read isn't hedged.
+func (rw *readerWriter) readCore(cacheInfo *backend.CacheInfo, rangeRead bool) *minio.Core {
+	if rw.cfg.HedgeRequestsRoles.Hedge(cacheInfo, rangeRead) {
+		return rw.hedgedCore
+	}
+	return rw.core
+}
+
+func (rw *readerWriter) readAll(ctx context.Context, core *minio.Core, name string) ([]byte, error) {
+	options := getObjectOptions(rw)
+	reader, info, _, err := core.GetObject(ctx, rw.cfg.Bucket, name, options)
 	if err != nil {
 		// do not change or wrap this error
 		// we need to compare the specific err message
@@ -586,13 +709,13 @@ func (rw *readerWriter) readAllWithObjInfo(ctx context.Context, name string) ([]
 	return buf, info, nil
 }
 
-func (rw *readerWriter) readRange(ctx context.Context, objName string, offset int64, buffer []byte) error {
+func (rw *readerWriter) readRange(ctx context.Context, core *minio.Core, objName string, offset int64, buffer []byte) error {
 	options := getObjectOptions(rw)
 	err := options.SetRange(offset, offset+int64(len(buffer)))
 	if err != nil {
 		return fmt.Errorf("error setting headers for range read in s3: %w", err)
 	}
-	reader, _, _, err := rw.hedgedCore.GetObject(ctx, rw.cfg.Bucket, objName, options)
+	reader, _, _, err := core.GetObject(ctx, rw.cfg.Bucket, objName, options)
 	if err != nil {
 		return fmt.Errorf("error in range read from s3 backend, bucket: %s, objName: %s: %w", rw.cfg.Bucket, objName, err)
 	}
@@ -611,7 +734,7 @@ func (rw *readerWriter) readRange(ctx context.Context, objName string, offset in
 	return err
 }
 
-func fetchCreds(cfg *Config) (*credentials.Credentials, error) {
+func fetchCreds(cfg *Config, providerCreds *tempo_credentials.Credentials) (*credentials.Credentials, error) {
 	wrapCredentialsProvider := func(p credentials.Provider) credentials.Provider {
 		if cfg.SignatureV2 {
 			return &overrideSignatureVersion{useV2: cfg.SignatureV2, upstream: p}
@@ -619,6 +742,15 @@ func fetchCreds(cfg *Config) (*credentials.Credentials, error) {
 		return p
 	}
 
+	if providerCreds != nil {
+		// the credentials of the provider replace the static credentials and the default chain
+		creds := credentials.New(wrapCredentialsProvider(&rotatingCredentials{creds: providerCreds}))
+		if _, err := creds.GetWithContext(&credentials.CredContext{Client: http.DefaultClient}); err != nil {
+			return nil, fmt.Errorf("failed to get credentials: %w", err)
+		}
+		return creds, nil
+	}
+
 	chain := []credentials.Provider{
 		wrapCredentialsProvider(&credentials.Static{
 			Value: credentials.Value{
@@ -649,8 +781,8 @@ func fetchCreds(cfg *Config) (*credentials.Credentials, error) {
 	return creds, nil
 }
 
-func createCore(cfg *Config, hedge bool) (*minio.Core, error) {
-	creds, err := fetchCreds(cfg)
+func createCore(cfg *Config, providerCreds *tempo_credentials.Credentials, hedge bool) (*minio.Core, error) {
+	creds, err := fetchCreds(cfg, providerCreds)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch credentials: %w", err)
 	}
@@ -7,11 +7,13 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"sync/atomic"
@@ -27,7 +29,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/tempo/pkg/cache"
 	"github.com/grafana/tempo/tempodb/backend"
+	tempo_credentials "github.com/grafana/tempo/tempodb/backend/credentials"
 )
 
 const (
@@ -197,7 +201,7 @@ func TestCredentials(t *testing.T) {
 				c.SecretKey = flagext.SecretWithValue(tc.secret)
 			}
 
-			creds, err := fetchCreds(c)
+			creds, err := fetchCreds(c, nil)
 			assert.NoError(t, err)
 
 			realCreds, err := creds.Get()
@@ -208,6 +212,41 @@ func TestCredentials(t *testing.T) {
 	}
 }
 
+func TestCredentialsProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("access_key: foo\nsecret_key: bar\n"), 0o600))
+
+	providerCreds, err := tempo_credentials.New(tempo_credentials.Config{
+		Provider:        tempo_credentials.ProviderFile,
+		RefreshInterval: 10 * time.Millisecond,
+		File:            tempo_credentials.FileConfig{Path: path},
+	})
+	require.NoError(t, err)
+	defer providerCreds.Stop()
+
+	// the static credentials are ignored
+	creds, err := fetchCreds(&Config{AccessKey: "static", SecretKey: flagext.SecretWithValue("static")}, providerCreds)
+	require.NoError(t, err)
+
+	v, err := creds.Get()
+	require.NoError(t, err)
+	require.Equal(t, credentials.Value{AccessKeyID: "foo", SecretAccessKey: "bar", SignerType: credentials.SignatureV4}, v)
+
+	// rotated credentials are used without recreating the client
+	require.NoError(t, os.WriteFile(path, []byte("access_key: foo\nsecret_key: baz\nsession_token: token\n"), 0o600))
+	require.Eventually(t, func() bool {
+		v, err := creds.Get()
+		return err == nil && v.SecretAccessKey == "baz" && v.SessionToken == "token"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// credentials without a secret key are an error
+	require.NoError(t, os.WriteFile(path, []byte("access_key: foo\n"), 0o600))
+	providerCreds, err = tempo_credentials.New(tempo_credentials.Config{Provider: tempo_credentials.ProviderFile, File: tempo_credentials.FileConfig{Path: path}})
+	require.NoError(t, err)
+	_, err = fetchCreds(&Config{}, providerCreds)
+	require.ErrorContains(t, err, "credentials access_key and secret_key are required")
+}
+
 func TestHedge(t *testing.T) {
 	tests := []struct {
 		name                   string
@@ -280,6 +319,52 @@ func TestHedge(t *testing.T) {
 	}
 }
 
+func TestHedgeRoles(t *testing.T) {
+	returnIn := 100 * time.Millisecond
+	count := int32(0)
+	server := fakeServer(t, returnIn, &count)
+
+	r, _, _, err := New(&Config{
+		Region:             "blerg",
+		AccessKey:          "test",
+		SecretKey:          flagext.SecretWithValue("test"),
+		Bucket:             "blerg",
+		Insecure:           true,
+		Endpoint:           server.URL[7:], // [7:] -> strip http://
+		HedgeRequestsAt:    time.Millisecond,
+		HedgeRequestsUpTo:  2,
+		HedgeRequestsRoles: backend.HedgedReadRoles{backend.HedgedReadRoleBloom},
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	bloom := &backend.CacheInfo{Role: cache.RoleBloom}
+	page := &backend.CacheInfo{Role: cache.RoleParquetPage}
+
+	// the first call on each client initiates an extra http request
+	// clearing that here
+	_, _, _ = r.Read(ctx, "object", backend.KeyPath{"test"}, bloom)
+	_, _, _ = r.Read(ctx, "object", backend.KeyPath{"test"}, nil)
+	time.Sleep(returnIn)
+	atomic.StoreInt32(&count, 0)
+
+	// calls that should hedge
+	_, _, _ = r.Read(ctx, "object", backend.KeyPath{"test"}, bloom)
+	time.Sleep(returnIn)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&count))
+	atomic.StoreInt32(&count, 0)
+
+	// calls that should not hedge
+	_ = r.ReadRange(ctx, "object", backend.KeyPath{"test"}, 10, []byte{}, page)
+	time.Sleep(returnIn)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&count))
+	atomic.StoreInt32(&count, 0)
+
+	_, _, _ = r.Read(ctx, "object", backend.KeyPath{"test"}, nil)
+	time.Sleep(returnIn)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&count))
+}
+
 func TestNilConfig(t *testing.T) {
 	_, _, _, err := New(nil)
 	require.Error(t, err)
@@ -740,3 +825,116 @@ func metadataMockedHandler(t *testing.T) http.HandlerFunc {
 }
 
 func timeNow() time.Time { return time.Date(2024, 5, 12, 16, 21, 24, 42, time.UTC) }
+
+func TestResumableAppend(t *testing.T) {
+	var (
+		failures    int32 = 1
+		partNumbers []string
+		completed   []byte
+	)
+	server := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case r.Method == getMethod:
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+			<ListBucketResult>
+			</ListBucketResult>`))
+		case r.Method == http.MethodPost && q.Has("uploads"):
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+			<InitiateMultipartUploadResult><Bucket>blerg</Bucket><Key>test/object</Key><UploadId>upload</UploadId></InitiateMultipartUploadResult>`))
+		case r.Method == putMethod && q.Get("uploadId") == "upload":
+			partNumbers = append(partNumbers, q.Get("partNumber"))
+			if q.Get("partNumber") == "2" && atomic.AddInt32(&failures, -1) >= 0 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+				<Error><Code>InvalidArgument</Code><Message>part failed</Message></Error>`))
+				return
+			}
+			w.Header().Set("ETag", `"etag`+q.Get("partNumber")+`"`)
+		case r.Method == http.MethodPost && q.Get("uploadId") == "upload":
+			var err error
+			completed, err = io.ReadAll(r.Body)
+			require.NoError(t, err)
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+			<CompleteMultipartUploadResult><Bucket>blerg</Bucket><Key>test/object</Key><ETag>"etag"</ETag></CompleteMultipartUploadResult>`))
+		}
+	})
+
+	_, w, _, err := New(&Config{
+		Region:    "blerg",
+		AccessKey: "test",
+		SecretKey: flagext.SecretWithValue("test"),
+		Bucket:    "blerg",
+		Insecure:  true,
+		Endpoint:  server.URL[7:],
+	})
+	require.NoError(t, err)
+
+	ra, ok := w.(backend.ResumableAppender)
+	require.True(t, ok)
+
+	ctx := context.Background()
+	tracker, err := ra.ResumableAppend(ctx, "object", backend.KeyPath{"test"}, nil, []byte("a"))
+	require.NoError(t, err)
+
+	// the failed part is uploaded again with the same part number
+	checkpoint, err := ra.ResumableAppend(ctx, "object", backend.KeyPath{"test"}, tracker, []byte("b"))
+	require.Error(t, err)
+	tracker, err = ra.ResumableAppend(ctx, "object", backend.KeyPath{"test"}, checkpoint, []byte("b"))
+	require.NoError(t, err)
+
+	require.NoError(t, w.CloseAppend(ctx, tracker))
+	require.Equal(t, []string{"1", "2", "2"}, partNumbers)
+
+	var complete struct {
+		Parts []minio.CompletePart `xml:"Part"`
+	}
+	require.NoError(t, xml.Unmarshal(completed, &complete))
+	require.Len(t, complete.Parts, 2)
+	require.Equal(t, 1, complete.Parts[0].PartNumber)
+	require.Equal(t, 2, complete.Parts[1].PartNumber)
+}
+
+func TestObjectLock(t *testing.T) {
+	locks := map[string]string{}
+	server := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case putMethod:
+			locks[path.Base(r.URL.Path)] = r.Header.Get("X-Amz-Object-Lock-Mode")
+		case getMethod:
+			// return fake list response b/c it's the only call that has to succeed
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+		<ListBucketResult>
+		</ListBucketResult>`))
+		}
+	})
+
+	cfg := &Config{
+		Region:    "blerg",
+		AccessKey: "test",
+		SecretKey: flagext.SecretWithValue("test"),
+		Bucket:    "blerg",
+		Insecure:  true,
+		Endpoint:  server.URL[7:], // [7:] -> strip http://
+		ObjectLock: backend.ObjectLockConfig{
+			Mode:      "COMPLIANCE",
+			Retention: time.Hour,
+		},
+	}
+	_, w, _, err := New(cfg)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	keypath := backend.KeyPathForBlock(uuid.New(), "tenant")
+	require.NoError(t, w.Write(ctx, "data.parquet", keypath, bytes.NewReader([]byte("data")), 4, nil))
+	require.NoError(t, w.Write(ctx, backend.MetaName, keypath, bytes.NewReader([]byte("{}")), 2, nil))
+
+	require.Equal(t, map[string]string{
+		"data.parquet":   "COMPLIANCE",
+		backend.MetaName: "",
+	}, locks)
+
+	cfg.ObjectLock.Mode = "blerg"
+	_, _, _, err = NewNoConfirm(cfg)
+	require.Error(t, err)
+}
@@ -2,7 +2,9 @@ package backend
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -82,3 +84,58 @@ func (b *TenantIndex) unmarshalPb(buffer []byte) error {
 
 	return b.Unmarshal(bb)
 }
+
+// ReadTenantIndexVersion returns the version of the tenant index of the tenant, VersionNew if the tenant doesn't have
+// an index.
+func ReadTenantIndexVersion(ctx context.Context, r VersionedReaderWriter, tenantID string) (Version, error) {
+	reader, version, err := r.ReadVersioned(ctx, TenantIndexNamePb, KeyPath{tenantID})
+	if errors.Is(err, ErrDoesNotExist) {
+		return VersionNew, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	reader.Close()
+
+	return version, nil
+}
+
+// WriteTenantIndexVersioned writes the tenant index of the tenant if the current index is at version, and deletes it
+// if meta and compactedMeta are empty. ErrVersionDoesNotMatch is returned if the index was written in the meantime.
+// Only the proto index is versioned, the JSON index is written with w once the proto index is written.
+func WriteTenantIndexVersioned(ctx context.Context, rw VersionedReaderWriter, w RawWriter, tenantID string, meta []*BlockMeta, compactedMeta []*CompactedBlockMeta, version Version) (Version, error) {
+	if len(meta) == 0 && len(compactedMeta) == 0 {
+		if version != VersionNew {
+			err := rw.DeleteVersioned(ctx, TenantIndexNamePb, KeyPath{tenantID}, version)
+			if err != nil && !errors.Is(err, ErrDoesNotExist) {
+				return "", err
+			}
+		}
+
+		err := w.Delete(ctx, TenantIndexName, KeyPath{tenantID}, nil)
+		if err != nil && !errors.Is(err, ErrDoesNotExist) {
+			return "", err
+		}
+
+		return VersionNew, nil
+	}
+
+	b := newTenantIndex(meta, compactedMeta)
+
+	indexBytesPb, err := b.marshalPb()
+	if err != nil {
+		return "", err
+	}
+
+	newVersion, err := rw.WriteVersioned(ctx, TenantIndexNamePb, KeyPath{tenantID}, bytes.NewReader(indexBytesPb), int64(len(indexBytesPb)), version)
+	if err != nil {
+		return "", err
+	}
+
+	indexBytesJSON, err := b.marshal()
+	if err != nil {
+		return "", err
+	}
+
+	return newVersion, w.Write(ctx, TenantIndexName, KeyPath{tenantID}, bytes.NewReader(indexBytesJSON), int64(len(indexBytesJSON)), nil)
+}
@@ -11,6 +11,9 @@ import (
 )
 
 func TestIndexMarshalUnmarshal(t *testing.T) {
+	errorStart := time.Now().Add(-time.Hour)
+	errorEnd := time.Now()
+
 	tests := []struct {
 		idx *TenantIndex
 	}{
@@ -69,6 +72,16 @@ func TestIndexMarshalUnmarshal(t *testing.T) {
 				},
 			},
 		},
+		{
+			idx: &TenantIndex{
+				Meta: []*BlockMeta{
+					{Version: "v1", BlockID: NewUUID(), TenantID: "test", Encoding: EncNone, RetentionClass: "prod"},
+					{Version: "v1", BlockID: NewUUID(), TenantID: "test", Encoding: EncNone, ErrorTimesTracked: true},
+					{Version: "v1", BlockID: NewUUID(), TenantID: "test", Encoding: EncNone, ErrorTimesTracked: true, ErrorStartTime: &errorStart, ErrorEndTime: &errorEnd},
+					{Version: "v1", BlockID: NewUUID(), TenantID: "test", Encoding: EncNone, TraceIDSummary: []byte{0x01, 0x80}},
+				},
+			},
+		},
 	}
 
 	for _, tc := range tests {
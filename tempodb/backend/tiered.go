@@ -0,0 +1,401 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/util/log"
+)
+
+var metricTieredMirrorFailures = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "tempodb",
+	Name:      "tiered_mirror_failures_total",
+	Help:      "Total number of objects written to the trace storage that couldn't be mirrored to the local disk.",
+})
+
+// Tiered is a RawReader, RawWriter and Compactor that keeps local copies of recent blocks next to the object storage.
+// Writes of block objects go to the object storage and are mirrored to the local disk, reads are served from the local
+// copy if it exists. Objects outside of a block, like the tenant index, can change at any time and always go to the
+// object storage. Listing and compacted metas are always served by the object storage, which is the source of truth.
+// Local copies are removed when their block is marked compacted or cleared and evicted by Evict once they're old enough.
+//
+// Mirroring is best effort: a write that succeeded in the object storage never fails because of the local disk. The
+// local backend must put objects in place atomically once they're complete, like the local backend does, and a local
+// copy is only completed if it has the size of the remote object. Reads can then trust any local copy they find.
+type Tiered struct {
+	localR RawReader
+	localW RawWriter
+	localC Compactor
+
+	remoteR RawReader
+	remoteW RawWriter
+	remoteC Compactor
+}
+
+var (
+	_ RawReader             = (*Tiered)(nil)
+	_ RawWriter             = (*Tiered)(nil)
+	_ Compactor             = (*Tiered)(nil)
+	_ VersionedReaderWriter = (*Tiered)(nil)
+	_ ConditionalReader     = (*Tiered)(nil)
+	_ ResumableAppender     = (*Tiered)(nil)
+	_ Archiver              = (*Tiered)(nil)
+)
+
+type tieredAppendTracker struct {
+	local  AppendTracker
+	remote AppendTracker
+
+	name    string
+	keypath KeyPath
+	// localFailed is set once appending to the local copy failed, the rest of the object isn't mirrored
+	localFailed bool
+}
+
+// NewTiered returns a Tiered backend mirroring the writes to the remote backend to the local backend.
+func NewTiered(localR RawReader, localW RawWriter, localC Compactor, remoteR RawReader, remoteW RawWriter, remoteC Compactor) *Tiered {
+	return &Tiered{
+		localR:  localR,
+		localW:  localW,
+		localC:  localC,
+		remoteR: remoteR,
+		remoteW: remoteW,
+		remoteC: remoteC,
+	}
+}
+
+// Write implements RawWriter. The object is streamed to the remote backend and teed to the local backend, which only
+// completes the local copy once the remote write succeeded with all the bytes of the object.
+func (t *Tiered) Write(ctx context.Context, name string, keypath KeyPath, data io.Reader, size int64, cacheInfo *CacheInfo) error {
+	if !isBlockKeyPath(keypath) {
+		return t.remoteW.Write(ctx, name, keypath, data, size, cacheInfo)
+	}
+
+	pr, pw := io.Pipe()
+	mirror := &mirrorWriter{w: pw}
+
+	localErr := make(chan error, 1)
+	go func() {
+		err := t.localW.Write(ctx, name, keypath, pr, size, cacheInfo)
+		// unblock the tee if the local write stopped reading early
+		_ = pr.CloseWithError(errLocalCopyAborted)
+		localErr <- err
+	}()
+
+	err := t.remoteW.Write(ctx, name, keypath, io.TeeReader(data, mirror), size, cacheInfo)
+	switch {
+	case err != nil:
+		_ = pw.CloseWithError(err)
+	case size >= 0 && mirror.n != size:
+		_ = pw.CloseWithError(fmt.Errorf("mirrored %d bytes of an object of %d bytes", mirror.n, size))
+	default:
+		_ = pw.Close()
+	}
+
+	if lerr := <-localErr; err == nil && lerr != nil {
+		t.mirrorFailed(ctx, name, keypath, lerr)
+	}
+	return err
+}
+
+var errLocalCopyAborted = errors.New("local copy aborted")
+
+// mirrorWriter passes the bytes read by the remote write to the local copy. Once the local copy failed the bytes are
+// dropped, so the remote write never fails because of it.
+type mirrorWriter struct {
+	w      io.Writer
+	n      int64
+	failed bool
+}
+
+func (m *mirrorWriter) Write(p []byte) (int, error) {
+	m.n += int64(len(p))
+	if !m.failed {
+		if _, err := m.w.Write(p); err != nil {
+			m.failed = true
+		}
+	}
+	return len(p), nil
+}
+
+// Append implements RawWriter. A failed append to the local copy stops mirroring the object, the local backend removes
+// its partial copy.
+func (t *Tiered) Append(ctx context.Context, name string, keypath KeyPath, tracker AppendTracker, buffer []byte) (AppendTracker, error) {
+	tt := tieredAppendTracker{name: name, keypath: keypath}
+	if tracker != nil {
+		tt = tracker.(tieredAppendTracker)
+	}
+
+	var err error
+	tt.remote, err = t.remoteW.Append(ctx, name, keypath, tt.remote, buffer)
+	if err != nil {
+		t.abortLocalAppend(ctx, tt)
+		return nil, err
+	}
+
+	if !tt.localFailed && isBlockKeyPath(keypath) {
+		tt.local, err = t.localW.Append(ctx, name, keypath, tt.local, buffer)
+		if err != nil {
+			tt.local, tt.localFailed = nil, true
+			t.mirrorFailed(ctx, name, keypath, err)
+		}
+	}
+	return tt, nil
+}
+
+// ResumableAppend implements ResumableAppender. The local copy is only appended to once the remote append succeeded,
+// so it matches the checkpoint of a failed append. If the remote backend can't resume appends failed appends return a
+// nil checkpoint.
+func (t *Tiered) ResumableAppend(ctx context.Context, name string, keypath KeyPath, tracker AppendTracker, buffer []byte) (AppendTracker, error) {
+	ra, ok := t.remoteW.(ResumableAppender)
+	if !ok {
+		tracker, err := t.Append(ctx, name, keypath, tracker, buffer)
+		if err != nil {
+			return nil, err
+		}
+		return tracker, nil
+	}
+
+	tt := tieredAppendTracker{name: name, keypath: keypath}
+	if tracker != nil {
+		tt = tracker.(tieredAppendTracker)
+	}
+
+	checkpoint, err := ra.ResumableAppend(ctx, name, keypath, tt.remote, buffer)
+	if err != nil {
+		if checkpoint == nil {
+			t.abortLocalAppend(ctx, tt)
+			return nil, err
+		}
+		tt.remote = checkpoint
+		return tt, err
+	}
+	tt.remote = checkpoint
+
+	if !tt.localFailed && isBlockKeyPath(keypath) {
+		tt.local, err = t.localW.Append(ctx, name, keypath, tt.local, buffer)
+		if err != nil {
+			tt.local, tt.localFailed = nil, true
+			t.mirrorFailed(ctx, name, keypath, err)
+		}
+	}
+	return tt, nil
+}
+
+// CloseAppend implements RawWriter. The local copy is only completed if the remote object is.
+func (t *Tiered) CloseAppend(ctx context.Context, tracker AppendTracker) error {
+	if tracker == nil {
+		return nil
+	}
+	tt := tracker.(tieredAppendTracker)
+
+	if err := t.remoteW.CloseAppend(ctx, tt.remote); err != nil {
+		t.abortLocalAppend(ctx, tt)
+		return err
+	}
+	if tt.localFailed || tt.local == nil {
+		return nil
+	}
+	if err := t.localW.CloseAppend(ctx, tt.local); err != nil {
+		t.mirrorFailed(ctx, tt.name, tt.keypath, err)
+	}
+	return nil
+}
+
+// abortLocalAppend closes the local copy of an object that won't be completed and deletes it. The local backend puts
+// the object in place when it's closed, so it's deleted right after.
+func (t *Tiered) abortLocalAppend(ctx context.Context, tt tieredAppendTracker) {
+	if tt.localFailed || tt.local == nil {
+		return
+	}
+	_ = t.localW.CloseAppend(ctx, tt.local)
+	_ = t.localW.Delete(ctx, tt.name, tt.keypath, nil)
+}
+
+// mirrorFailed records an object that couldn't be mirrored to the local disk. Reads of the object are served by the
+// remote backend.
+func (t *Tiered) mirrorFailed(ctx context.Context, name string, keypath KeyPath, err error) {
+	metricTieredMirrorFailures.Inc()
+	level.Warn(log.WithContext(ctx, log.Logger)).Log("msg", "failed to mirror object to the local disk", "name", name, "keypath", keypath, "err", err)
+}
+
+// Delete implements RawWriter
+func (t *Tiered) Delete(ctx context.Context, name string, keypath KeyPath, cacheInfo *CacheInfo) error {
+	if err := t.remoteW.Delete(ctx, name, keypath, cacheInfo); err != nil {
+		return err
+	}
+	if !isBlockKeyPath(keypath) {
+		return nil
+	}
+	if err := t.localW.Delete(ctx, name, keypath, cacheInfo); err != nil && !errors.Is(err, ErrDoesNotExist) {
+		return fmt.Errorf("error deleting local copy: %w", err)
+	}
+	return nil
+}
+
+// List implements RawReader
+func (t *Tiered) List(ctx context.Context, keypath KeyPath) ([]string, error) {
+	return t.remoteR.List(ctx, keypath)
+}
+
+// ListBlocks implements RawReader
+func (t *Tiered) ListBlocks(ctx context.Context, tenant string) ([]uuid.UUID, []uuid.UUID, error) {
+	return t.remoteR.ListBlocks(ctx, tenant)
+}
+
+// Find implements RawReader
+func (t *Tiered) Find(ctx context.Context, keypath KeyPath, fn FindFunc) error {
+	return t.remoteR.Find(ctx, keypath, fn)
+}
+
+// Read implements RawReader. The object is read from the remote backend if there's no local copy or it can't be read.
+// Local copies are only put in place once they're complete, so a local copy has the size of the remote object.
+func (t *Tiered) Read(ctx context.Context, name string, keypath KeyPath, cacheInfo *CacheInfo) (io.ReadCloser, int64, error) {
+	if isBlockKeyPath(keypath) {
+		rc, size, err := t.localR.Read(ctx, name, keypath, cacheInfo)
+		if err == nil {
+			return rc, size, nil
+		}
+	}
+	return t.remoteR.Read(ctx, name, keypath, cacheInfo)
+}
+
+// ReadRange implements RawReader. The range is read from the remote backend if the local copy can't be read.
+func (t *Tiered) ReadRange(ctx context.Context, name string, keypath KeyPath, offset uint64, buffer []byte, cacheInfo *CacheInfo) error {
+	if isBlockKeyPath(keypath) {
+		if err := t.localR.ReadRange(ctx, name, keypath, offset, buffer, cacheInfo); err == nil {
+			return nil
+		}
+	}
+	return t.remoteR.ReadRange(ctx, name, keypath, offset, buffer, cacheInfo)
+}
+
+// ReadIfChanged implements ConditionalReader. Versions are those of the remote backend, so conditional reads are always
+// served by it. If the remote backend does not support them the object is always read and no version is returned.
+func (t *Tiered) ReadIfChanged(ctx context.Context, name string, keypath KeyPath, version Version) (io.ReadCloser, int64, Version, error) {
+	if cr, ok := t.remoteR.(ConditionalReader); ok {
+		return cr.ReadIfChanged(ctx, name, keypath, version)
+	}
+
+	rc, size, err := t.remoteR.Read(ctx, name, keypath, nil)
+	return rc, size, "", err
+}
+
+// WriteVersioned implements VersionedReaderWriter. Versioned objects are never mirrored.
+func (t *Tiered) WriteVersioned(ctx context.Context, name string, keypath KeyPath, data io.Reader, size int64, version Version) (Version, error) {
+	return t.versioned().WriteVersioned(ctx, name, keypath, data, size, version)
+}
+
+// ReadVersioned implements VersionedReaderWriter
+func (t *Tiered) ReadVersioned(ctx context.Context, name string, keypath KeyPath) (io.ReadCloser, Version, error) {
+	return t.versioned().ReadVersioned(ctx, name, keypath)
+}
+
+// DeleteVersioned implements VersionedReaderWriter
+func (t *Tiered) DeleteVersioned(ctx context.Context, name string, keypath KeyPath, version Version) error {
+	return t.versioned().DeleteVersioned(ctx, name, keypath, version)
+}
+
+// versioned returns the remote backend if it supports versioning and a fake versioned backend on top of it otherwise.
+func (t *Tiered) versioned() VersionedReaderWriter {
+	if v, ok := t.remoteR.(VersionedReaderWriter); ok {
+		return v
+	}
+	return NewFakeVersionedReaderWriter(t.remoteR, t.remoteW)
+}
+
+// isBlockKeyPath returns true if the keypath was built with KeyPathForBlock. Only objects of a block are mirrored.
+func isBlockKeyPath(keypath KeyPath) bool {
+	_, _, ok := blockOfKeyPath(keypath)
+	return ok
+}
+
+// Shutdown implements RawReader
+func (t *Tiered) Shutdown() {
+	t.localR.Shutdown()
+	t.remoteR.Shutdown()
+}
+
+// MarkBlockCompacted implements Compactor. The local copy of a compacted block is removed right away.
+func (t *Tiered) MarkBlockCompacted(blockID uuid.UUID, tenantID string) error {
+	if err := t.remoteC.MarkBlockCompacted(blockID, tenantID); err != nil {
+		return err
+	}
+	if err := t.localC.ClearBlock(blockID, tenantID); err != nil {
+		return fmt.Errorf("error clearing local copy: %w", err)
+	}
+	return nil
+}
+
+// ClearBlock implements Compactor
+func (t *Tiered) ClearBlock(blockID uuid.UUID, tenantID string) error {
+	if err := t.remoteC.ClearBlock(blockID, tenantID); err != nil {
+		return err
+	}
+	if err := t.localC.ClearBlock(blockID, tenantID); err != nil {
+		return fmt.Errorf("error clearing local copy: %w", err)
+	}
+	return nil
+}
+
+// ArchiveBlock implements Archiver. Only the remote objects are archived, local copies are read until they're evicted.
+func (t *Tiered) ArchiveBlock(ctx context.Context, blockID uuid.UUID, tenantID string, tier string) error {
+	archiver, ok := t.remoteC.(Archiver)
+	if !ok {
+		return ErrArchivingNotSupported
+	}
+	return archiver.ArchiveBlock(ctx, blockID, tenantID, tier)
+}
+
+// CompactedBlockMeta implements Compactor
+func (t *Tiered) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*CompactedBlockMeta, error) {
+	return t.remoteC.CompactedBlockMeta(blockID, tenantID)
+}
+
+// Evict removes the local copies of the blocks with an end time before the given time and of compacted blocks.
+// Blocks without a local meta are still being written and are kept. It returns the number of evicted blocks.
+func (t *Tiered) Evict(ctx context.Context, before time.Time) (int, error) {
+	tenants, err := t.localR.List(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error listing local tenants: %w", err)
+	}
+
+	r := NewReader(t.localR)
+	evicted := 0
+	for _, tenantID := range tenants {
+		blockIDs, compactedBlockIDs, err := t.localR.ListBlocks(ctx, tenantID)
+		if err != nil {
+			return evicted, fmt.Errorf("error listing local blocks of tenant %s: %w", tenantID, err)
+		}
+
+		for _, blockID := range blockIDs {
+			meta, err := r.BlockMeta(ctx, blockID, tenantID)
+			if errors.Is(err, ErrDoesNotExist) {
+				continue
+			}
+			if err != nil {
+				return evicted, fmt.Errorf("error reading local meta of block %s: %w", blockID, err)
+			}
+			if !meta.EndTime.Before(before) {
+				continue
+			}
+			compactedBlockIDs = append(compactedBlockIDs, blockID)
+		}
+
+		for _, blockID := range compactedBlockIDs {
+			if err := t.localC.ClearBlock(blockID, tenantID); err != nil {
+				return evicted, fmt.Errorf("error evicting local copy of block %s: %w", blockID, err)
+			}
+			evicted++
+		}
+	}
+	return evicted, nil
+}
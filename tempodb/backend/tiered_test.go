@@ -0,0 +1,102 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// optionalBackend implements the optional backend interfaces and records the calls to them.
+type optionalBackend struct {
+	MockRawReader
+	MockRawWriter
+	MockCompactor
+
+	calls []string
+}
+
+var (
+	_ VersionedReaderWriter = (*optionalBackend)(nil)
+	_ ConditionalReader     = (*optionalBackend)(nil)
+	_ ResumableAppender     = (*optionalBackend)(nil)
+	_ Archiver              = (*optionalBackend)(nil)
+)
+
+func (b *optionalBackend) ReadIfChanged(context.Context, string, KeyPath, Version) (io.ReadCloser, int64, Version, error) {
+	b.calls = append(b.calls, "ReadIfChanged")
+	return io.NopCloser(bytes.NewReader(b.R)), int64(len(b.R)), "v1", nil
+}
+
+func (b *optionalBackend) WriteVersioned(context.Context, string, KeyPath, io.Reader, int64, Version) (Version, error) {
+	b.calls = append(b.calls, "WriteVersioned")
+	return "v1", nil
+}
+
+func (b *optionalBackend) ReadVersioned(context.Context, string, KeyPath) (io.ReadCloser, Version, error) {
+	b.calls = append(b.calls, "ReadVersioned")
+	return io.NopCloser(bytes.NewReader(b.R)), "v1", nil
+}
+
+func (b *optionalBackend) DeleteVersioned(context.Context, string, KeyPath, Version) error {
+	b.calls = append(b.calls, "DeleteVersioned")
+	return nil
+}
+
+func (b *optionalBackend) ResumableAppend(_ context.Context, _ string, _ KeyPath, tracker AppendTracker, _ []byte) (AppendTracker, error) {
+	b.calls = append(b.calls, "ResumableAppend")
+	return tracker, nil
+}
+
+func (b *optionalBackend) ArchiveBlock(context.Context, uuid.UUID, string, string) error {
+	b.calls = append(b.calls, "ArchiveBlock")
+	return nil
+}
+
+func TestTieredForwardsOptionalInterfaces(t *testing.T) {
+	ctx := context.Background()
+	keypath := KeyPath{"tenant"}
+	local := &MockRawReader{}
+
+	remote := &optionalBackend{}
+	tiered := NewTiered(local, &MockRawWriter{}, &MockCompactor{}, remote, remote, remote)
+
+	_, _, version, err := tiered.ReadIfChanged(ctx, TenantIndexName, keypath, "")
+	require.NoError(t, err)
+	require.Equal(t, Version("v1"), version)
+	_, err = tiered.WriteVersioned(ctx, TenantIndexName, keypath, bytes.NewReader(nil), 0, VersionNew)
+	require.NoError(t, err)
+	_, _, err = tiered.ReadVersioned(ctx, TenantIndexName, keypath)
+	require.NoError(t, err)
+	require.NoError(t, tiered.DeleteVersioned(ctx, TenantIndexName, keypath, "v1"))
+	_, err = tiered.ResumableAppend(ctx, "data", keypath, nil, []byte("a"))
+	require.NoError(t, err)
+	require.NoError(t, tiered.ArchiveBlock(ctx, uuid.New(), "tenant", "cold"))
+	require.Equal(t, []string{"ReadIfChanged", "WriteVersioned", "ReadVersioned", "DeleteVersioned", "ResumableAppend", "ArchiveBlock"}, remote.calls)
+
+	// a remote backend without the optional interfaces gets the fallbacks
+	plain := &MockRawReader{R: []byte("index")}
+	plainW := &MockRawWriter{}
+	tiered = NewTiered(local, &MockRawWriter{}, &MockCompactor{}, plain, plainW, &MockCompactor{})
+
+	rc, _, version, err := tiered.ReadIfChanged(ctx, TenantIndexName, keypath, "v1")
+	require.NoError(t, err)
+	require.Empty(t, version)
+	b, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.Equal(t, []byte("index"), b)
+
+	version, err = tiered.WriteVersioned(ctx, TenantIndexName, keypath, bytes.NewReader([]byte("index")), 5, VersionNew)
+	require.NoError(t, err)
+	require.Equal(t, VersionNew, version)
+	require.Equal(t, []byte("index"), plainW.writeBuffer["tenant/"+TenantIndexName])
+
+	_, err = tiered.ResumableAppend(ctx, "data", keypath, nil, []byte("a"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("a"), plainW.appendBuffer)
+
+	require.ErrorIs(t, tiered.ArchiveBlock(ctx, uuid.New(), "tenant", "cold"), ErrArchivingNotSupported)
+}
@@ -0,0 +1,62 @@
+package backend
+
+import (
+	"github.com/cespare/xxhash/v2"
+)
+
+// traceIDSummaryHashes is the number of bits set per trace ID in a trace ID summary.
+const traceIDSummaryHashes = 3
+
+// NewTraceIDSummary returns an empty trace ID summary of the given size in bytes, or nil if the size is not positive.
+// A trace ID summary is a small bloom filter of the trace IDs of a block that is stored in its meta, and so in the
+// tenant index, so the blocks of a trace can be pruned without reading their bloom filters from the backend. Its
+// false positive rate grows with the number of trace IDs of the block, it is meant to be a few KiB per block.
+func NewTraceIDSummary(size int) []byte {
+	if size <= 0 {
+		return nil
+	}
+	return make([]byte, size)
+}
+
+// TraceIDAdded adds the trace ID to the trace ID summary of the block, if it has one.
+func (b *BlockMeta) TraceIDAdded(id []byte) {
+	if len(b.TraceIDSummary) == 0 {
+		return
+	}
+
+	bits := uint64(len(b.TraceIDSummary)) * 8
+	h1, h2 := traceIDSummaryHash(id)
+	for i := uint64(0); i < traceIDSummaryHashes; i++ {
+		bit := (h1 + i*h2) % bits
+		b.TraceIDSummary[bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// MayContainTraceID returns false if the trace ID is outside the trace ID range of the block, or if the block has a
+// trace ID summary and the trace ID isn't in it.
+func (b *BlockMeta) MayContainTraceID(id []byte) bool {
+	if !b.TraceIDInRange(id) {
+		return false
+	}
+
+	if len(b.TraceIDSummary) == 0 {
+		return true
+	}
+
+	bits := uint64(len(b.TraceIDSummary)) * 8
+	h1, h2 := traceIDSummaryHash(id)
+	for i := uint64(0); i < traceIDSummaryHashes; i++ {
+		bit := (h1 + i*h2) % bits
+		if b.TraceIDSummary[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// traceIDSummaryHash returns the two hashes of the trace ID that the bits of the summary are derived from. Trace IDs
+// are hashed instead of used as is because 64 bit trace IDs are padded with zeros.
+func traceIDSummaryHash(id []byte) (uint64, uint64) {
+	h := xxhash.Sum64(id)
+	return h & 0xffffffff, h>>32 | 1
+}
@@ -0,0 +1,234 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+
+	tempo_io "github.com/grafana/tempo/pkg/io"
+)
+
+const (
+	// TrashKeyPath is the path in the tenant that deleted blocks are moved to, i.e. <tenant>/__trash__/<blockID>/
+	TrashKeyPath = "__trash__"
+
+	// TrashedMetaName is the marker of a block in the trash
+	TrashedMetaName = "meta.trashed.json"
+)
+
+// TrashedBlockMeta is the marker written to a block when it's moved to the trash.
+type TrashedBlockMeta struct {
+	BlockID     UUID      `json:"blockID"`
+	TenantID    string    `json:"tenantID"`
+	TrashedTime time.Time `json:"trashedTime"`
+}
+
+// KeyPathForTrashedBlock returns the keypath of a block in the trash.
+func KeyPathForTrashedBlock(blockID uuid.UUID, tenantID string) KeyPath {
+	return KeyPath{tenantID, TrashKeyPath, blockID.String()}
+}
+
+// MoveBlockToTrash copies all objects of the block to the trash of the tenant and clears the block. The marker
+// is written first so a block that was only partially copied is purged with the rest of the trash, and the block is
+// only cleared once all of its objects are copied.
+func MoveBlockToTrash(ctx context.Context, r RawReader, w RawWriter, c Compactor, blockID uuid.UUID, tenantID string, trashedTime time.Time) error {
+	m := &TrashedBlockMeta{
+		BlockID:     UUID(blockID),
+		TenantID:    tenantID,
+		TrashedTime: trashedTime,
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	trashPath := KeyPathForTrashedBlock(blockID, tenantID)
+	err = w.Write(ctx, TrashedMetaName, trashPath, bytes.NewReader(b), int64(len(b)), nil)
+	if err != nil {
+		return fmt.Errorf("error writing trash marker: %w", err)
+	}
+
+	blockPath := KeyPathForBlock(blockID, tenantID)
+	names, err := objectNames(ctx, r, blockPath)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := copyObject(ctx, r, w, name, blockPath, trashPath); err != nil {
+			return fmt.Errorf("error copying %s to trash: %w", name, err)
+		}
+	}
+
+	return c.ClearBlock(blockID, tenantID)
+}
+
+// TrashedBlocks returns the markers of the blocks in the trash of the tenant.
+func TrashedBlocks(ctx context.Context, r RawReader, tenantID string) ([]*TrashedBlockMeta, error) {
+	ids, err := r.List(ctx, KeyPath{tenantID, TrashKeyPath})
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, ErrDoesNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	metas := make([]*TrashedBlockMeta, 0, len(ids))
+	for _, id := range ids {
+		blockID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+
+		m, err := readTrashedBlockMeta(ctx, r, blockID, tenantID)
+		if errors.Is(err, ErrDoesNotExist) {
+			// leftover of a block that was already purged
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		metas = append(metas, m)
+	}
+
+	return metas, nil
+}
+
+// PurgeTrashedBlock deletes a block from the trash. The marker is deleted last so a failed purge is retried.
+func PurgeTrashedBlock(ctx context.Context, r RawReader, w RawWriter, blockID uuid.UUID, tenantID string) error {
+	trashPath := KeyPathForTrashedBlock(blockID, tenantID)
+	names, err := objectNames(ctx, r, trashPath)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if name == TrashedMetaName {
+			continue
+		}
+		if err := w.Delete(ctx, name, trashPath, nil); err != nil && !errors.Is(err, ErrDoesNotExist) {
+			return fmt.Errorf("error deleting %s from trash: %w", name, err)
+		}
+	}
+
+	err = w.Delete(ctx, TrashedMetaName, trashPath, nil)
+	if err != nil && !errors.Is(err, ErrDoesNotExist) {
+		return fmt.Errorf("error deleting trash marker: %w", err)
+	}
+	return nil
+}
+
+// RestoreTrashedBlock copies a block in the trash back to the tenant and removes it from the trash. Compacted blocks
+// are restored as regular blocks so they aren't deleted again.
+func RestoreTrashedBlock(ctx context.Context, r RawReader, w RawWriter, blockID uuid.UUID, tenantID string) error {
+	if _, err := readTrashedBlockMeta(ctx, r, blockID, tenantID); err != nil {
+		return fmt.Errorf("error reading trash marker: %w", err)
+	}
+
+	trashPath := KeyPathForTrashedBlock(blockID, tenantID)
+	names, err := objectNames(ctx, r, trashPath)
+	if err != nil {
+		return err
+	}
+
+	blockPath := KeyPathForBlock(blockID, tenantID)
+	hasMeta := false
+	for _, name := range names {
+		switch name {
+		case TrashedMetaName, CompactedMetaName:
+			continue
+		case MetaName:
+			hasMeta = true
+		}
+		if err := copyObject(ctx, r, w, name, trashPath, blockPath); err != nil {
+			return fmt.Errorf("error restoring %s: %w", name, err)
+		}
+	}
+
+	if !hasMeta {
+		if err := restoreCompactedMeta(ctx, r, w, trashPath, blockPath); err != nil {
+			return err
+		}
+	}
+
+	return PurgeTrashedBlock(ctx, r, w, blockID, tenantID)
+}
+
+func readTrashedBlockMeta(ctx context.Context, r RawReader, blockID uuid.UUID, tenantID string) (*TrashedBlockMeta, error) {
+	reader, size, err := r.Read(ctx, TrashedMetaName, KeyPathForTrashedBlock(blockID, tenantID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	b, err := tempo_io.ReadAllWithEstimate(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &TrashedBlockMeta{}
+	err = json.Unmarshal(b, out)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// restoreCompactedMeta writes the block meta of the compacted meta in from as the meta of the block in to.
+func restoreCompactedMeta(ctx context.Context, r RawReader, w RawWriter, from, to KeyPath) error {
+	reader, size, err := r.Read(ctx, CompactedMetaName, from, nil)
+	if err != nil {
+		return fmt.Errorf("error reading compacted meta: %w", err)
+	}
+	defer reader.Close()
+
+	b, err := tempo_io.ReadAllWithEstimate(reader, size)
+	if err != nil {
+		return err
+	}
+
+	compacted := &CompactedBlockMeta{}
+	if err := json.Unmarshal(b, compacted); err != nil {
+		return err
+	}
+
+	b, err = json.Marshal(&compacted.BlockMeta)
+	if err != nil {
+		return err
+	}
+
+	return w.Write(ctx, MetaName, to, bytes.NewReader(b), int64(len(b)), nil)
+}
+
+// objectNames returns the names of the objects in the keypath. Blocks don't have nested objects so only the base
+// name is kept.
+func objectNames(ctx context.Context, r RawReader, keypath KeyPath) ([]string, error) {
+	var names []string
+	err := r.Find(ctx, keypath, func(m FindMatch) {
+		names = append(names, path.Base(m.Key))
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding objects: %w", err)
+	}
+	return names, nil
+}
+
+func copyObject(ctx context.Context, r RawReader, w RawWriter, name string, from, to KeyPath) error {
+	reader, size, err := r.Read(ctx, name, from, nil)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	return w.Write(ctx, name, to, reader, size, nil)
+}
@@ -45,6 +45,24 @@ type BlockMeta struct {
 	DedicatedColumns DedicatedColumns `protobuf:"bytes,17,opt,name=dedicated_columns,json=dedicatedColumns,proto3,customtype=DedicatedColumns" json:"dedicatedColumns,omitempty"`
 	// repeated bytes dedicated_columns = 17 [(gogoproto.customtype) = "DedicatedColumn", (gogoproto.jsontag) = "dedicatedColumns,omitempty", (gogoproto.nullable) = false];
 	ReplicationFactor uint32 `protobuf:"varint,18,opt,name=replication_factor,json=replicationFactor,proto3" json:"replicationFactor,omitempty"`
+	RetentionClass    string `protobuf:"bytes,19,opt,name=retention_class,json=retentionClass,proto3" json:"retentionClass,omitempty"`
+	// time range of the spans with an error status, only set if error_times_tracked is true
+	ErrorStartTime    *time.Time `protobuf:"bytes,20,opt,name=error_start_time,json=errorStartTime,proto3,stdtime" json:"errorStartTime,omitempty"`
+	ErrorEndTime      *time.Time `protobuf:"bytes,21,opt,name=error_end_time,json=errorEndTime,proto3,stdtime" json:"errorEndTime,omitempty"`
+	ErrorTimesTracked bool       `protobuf:"varint,22,opt,name=error_times_tracked,json=errorTimesTracked,proto3" json:"errorTimesTracked,omitempty"`
+	// bloom filter of the trace IDs of the block, see TraceIDSummary
+	TraceIDSummary []byte `protobuf:"bytes,23,opt,name=trace_id_summary,json=traceIdSummary,proto3" json:"traceIDSummary,omitempty"`
+	// lowest and highest trace IDs of the block
+	MinID []byte `protobuf:"bytes,24,opt,name=min_id,json=minId,proto3" json:"minID,omitempty"`
+	MaxID []byte `protobuf:"bytes,25,opt,name=max_id,json=maxId,proto3" json:"maxID,omitempty"`
+	// name of the bucket the block was listed in when blocks are read from several buckets
+	Source string `protobuf:"bytes,26,opt,name=source,proto3" json:"source,omitempty"`
+	// data key of the block wrapped with the key of the tenant, empty if the block isn't encrypted
+	EncryptionKey []byte `protobuf:"bytes,27,opt,name=encryption_key,json=encryptionKey,proto3" json:"encryptionKey,omitempty"`
+	// true if the service and span names of the block are written to its name dictionary
+	NameDictionary bool `protobuf:"varint,28,opt,name=name_dictionary,json=nameDictionary,proto3" json:"nameDictionary,omitempty"`
+	// version of the key of the tenant the data key of the block is wrapped with
+	EncryptionKeyVersion uint32 `protobuf:"varint,29,opt,name=encryption_key_version,json=encryptionKeyVersion,proto3" json:"encryptionKeyVersion,omitempty"`
 }
 
 func (m *BlockMeta) Reset()         { *m = BlockMeta{} }
@@ -171,6 +189,83 @@ func (m *BlockMeta) GetReplicationFactor() uint32 {
 	return 0
 }
 
+func (m *BlockMeta) GetRetentionClass() string {
+	if m != nil {
+		return m.RetentionClass
+	}
+	return ""
+}
+
+func (m *BlockMeta) GetErrorStartTime() *time.Time {
+	if m != nil {
+		return m.ErrorStartTime
+	}
+	return nil
+}
+
+func (m *BlockMeta) GetErrorEndTime() *time.Time {
+	if m != nil {
+		return m.ErrorEndTime
+	}
+	return nil
+}
+
+func (m *BlockMeta) GetErrorTimesTracked() bool {
+	if m != nil {
+		return m.ErrorTimesTracked
+	}
+	return false
+}
+
+func (m *BlockMeta) GetTraceIDSummary() []byte {
+	if m != nil {
+		return m.TraceIDSummary
+	}
+	return nil
+}
+
+func (m *BlockMeta) GetMinID() []byte {
+	if m != nil {
+		return m.MinID
+	}
+	return nil
+}
+
+func (m *BlockMeta) GetMaxID() []byte {
+	if m != nil {
+		return m.MaxID
+	}
+	return nil
+}
+
+func (m *BlockMeta) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *BlockMeta) GetEncryptionKey() []byte {
+	if m != nil {
+		return m.EncryptionKey
+	}
+	return nil
+}
+
+func (m *BlockMeta) GetNameDictionary() bool {
+	if m != nil {
+		return m.NameDictionary
+	}
+	return false
+}
+
+func (m *BlockMeta) GetEncryptionKeyVersion() uint32 {
+	if m != nil {
+		return m.EncryptionKeyVersion
+	}
+	return 0
+}
+
 type CompactedBlockMeta struct {
 	BlockMeta     `protobuf:"bytes,1,opt,name=block_meta,json=blockMeta,proto3,embedded=block_meta" json:""`
 	CompactedTime time.Time `protobuf:"bytes,2,opt,name=compacted_time,json=compactedTime,proto3,stdtime" json:"compactedTime"`
@@ -285,57 +380,64 @@ func init() {
 func init() { proto.RegisterFile("tempodb/backend/v1/v1.proto", fileDescriptor_6bc10ae735c1a340) }
 
 var fileDescriptor_6bc10ae735c1a340 = []byte{
-	// 793 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x55, 0x5f, 0x8f, 0xdb, 0x44,
-	0x10, 0x8f, 0xef, 0xc2, 0x25, 0xd9, 0x24, 0x97, 0x64, 0xab, 0x22, 0x93, 0x4a, 0xd9, 0x28, 0xe2,
-	0x21, 0x48, 0xc5, 0xd1, 0xb5, 0x2a, 0x12, 0x42, 0x20, 0xe1, 0x3b, 0x90, 0x8a, 0xf8, 0x53, 0xdc,
-	0xeb, 0x0b, 0x42, 0xb2, 0xd6, 0xde, 0x8d, 0x6b, 0x6a, 0x7b, 0x23, 0x7b, 0x13, 0x41, 0x3f, 0x45,
-	0x3f, 0x0d, 0x9f, 0xa1, 0xe2, 0xe9, 0x1e, 0x11, 0x0f, 0x0b, 0xca, 0xbd, 0x99, 0x2f, 0x81, 0x76,
-	0xec, 0xd8, 0x49, 0x2a, 0xda, 0x97, 0x68, 0x66, 0x7e, 0xf3, 0x9b, 0x99, 0xdf, 0xd8, 0xe3, 0xa0,
-	0x7b, 0x92, 0xc7, 0x2b, 0xc1, 0xbc, 0x85, 0x47, 0xfd, 0x17, 0x3c, 0x61, 0x8b, 0xcd, 0xc5, 0x62,
-	0x73, 0x61, 0xad, 0x52, 0x21, 0x05, 0x46, 0x65, 0xd0, 0xda, 0x5c, 0x8c, 0x49, 0x20, 0x44, 0x10,
-	0xf1, 0x05, 0x20, 0xde, 0x7a, 0xb9, 0x90, 0x61, 0xcc, 0x33, 0x49, 0xe3, 0x55, 0x91, 0x3c, 0xfe,
-	0x38, 0x08, 0xe5, 0xf3, 0xb5, 0x67, 0xf9, 0x22, 0x5e, 0x04, 0x22, 0x10, 0x75, 0xa6, 0xf6, 0xc0,
-	0x01, 0xab, 0x48, 0x9f, 0xfd, 0xd1, 0x42, 0x1d, 0x3b, 0x12, 0xfe, 0x8b, 0xef, 0xb8, 0xa4, 0xf8,
-	0x43, 0xd4, 0xda, 0xf0, 0x34, 0x0b, 0x45, 0x62, 0x1a, 0x53, 0x63, 0xde, 0xb1, 0x51, 0xae, 0xc8,
-	0xd9, 0x52, 0xa4, 0x31, 0x95, 0xce, 0x0e, 0xc2, 0x9f, 0xa3, 0xb6, 0xa7, 0x29, 0x6e, 0xc8, 0xcc,
-	0x93, 0xa9, 0x31, 0xef, 0xd9, 0xb3, 0xd7, 0x8a, 0x34, 0xfe, 0x52, 0xa4, 0xf9, 0xec, 0xd9, 0xe3,
-	0xab, 0xad, 0x22, 0x2d, 0x28, 0xf9, 0xf8, 0x2a, 0x57, 0xa4, 0xe5, 0x15, 0xa6, 0x53, 0x1a, 0x0c,
-	0x3f, 0x42, 0x1d, 0xc9, 0x13, 0x9a, 0x48, 0xcd, 0x7f, 0x0f, 0xda, 0x98, 0x5b, 0x45, 0xda, 0xd7,
-	0x10, 0x04, 0x52, 0x5b, 0x96, 0xb6, 0xb3, 0xb3, 0x18, 0x7e, 0x82, 0x50, 0x26, 0x69, 0x2a, 0x5d,
-	0xad, 0xd8, 0x3c, 0x9b, 0x1a, 0xf3, 0xee, 0x83, 0xb1, 0x55, 0xac, 0xc3, 0xda, 0x89, 0xb4, 0xae,
-	0x77, 0xeb, 0xb0, 0xef, 0xea, 0x99, 0x72, 0x45, 0x3a, 0xc0, 0xd2, 0xf1, 0x57, 0x7f, 0x13, 0xc3,
-	0xa9, 0x5d, 0xfc, 0x0d, 0x6a, 0xf3, 0x84, 0x15, 0xf5, 0x5a, 0xef, 0xac, 0x77, 0xa7, 0xac, 0xd7,
-	0xe2, 0x09, 0xab, 0xaa, 0xed, 0x1c, 0xfc, 0x08, 0xf5, 0xa5, 0x90, 0x34, 0x72, 0x85, 0xf7, 0x0b,
-	0xf7, 0x65, 0x66, 0xb6, 0xa7, 0xc6, 0xfc, 0xd4, 0x1e, 0xe6, 0x8a, 0xf4, 0x00, 0xf8, 0xa1, 0x88,
-	0x3b, 0x07, 0x1e, 0xc6, 0xa8, 0x99, 0x85, 0x2f, 0xb9, 0xd9, 0x99, 0x1a, 0xf3, 0xa6, 0x03, 0x36,
-	0xfe, 0x02, 0x0d, 0x7d, 0x11, 0xaf, 0xa8, 0x2f, 0x43, 0x91, 0xb8, 0x11, 0xdf, 0xf0, 0xc8, 0x44,
-	0x53, 0x63, 0xde, 0xb7, 0xef, 0xe4, 0x8a, 0x0c, 0x6a, 0xec, 0x5b, 0x0d, 0x39, 0xc7, 0x01, 0x7c,
-	0x5f, 0xcb, 0xf2, 0x05, 0x0b, 0x93, 0xc0, 0xec, 0xc2, 0xe3, 0x19, 0x96, 0x8f, 0xa7, 0xfd, 0x55,
-	0x19, 0x77, 0xaa, 0x0c, 0xfc, 0x29, 0x1a, 0x84, 0x09, 0xe3, 0xbf, 0xba, 0x2b, 0x1a, 0x70, 0x17,
-	0x86, 0xe9, 0x41, 0xb3, 0x51, 0xae, 0x48, 0x1f, 0xa0, 0x27, 0x34, 0xe0, 0x4f, 0xc3, 0x97, 0xdc,
-	0x39, 0x74, 0x6b, 0xcd, 0x29, 0xf7, 0x45, 0xca, 0x32, 0xb3, 0x0f, 0xc4, 0x5a, 0xb3, 0x53, 0xc4,
-	0x9d, 0x03, 0x4f, 0xd3, 0x18, 0x95, 0xd4, 0xad, 0x86, 0x3c, 0x87, 0x77, 0x00, 0x68, 0x1a, 0xa8,
-	0x86, 0x3c, 0xf0, 0xf0, 0x67, 0x68, 0xe4, 0x45, 0x42, 0xc4, 0x6e, 0xf6, 0x9c, 0xa6, 0xcc, 0xf5,
-	0xc5, 0x3a, 0x91, 0xe6, 0x00, 0x3a, 0x0e, 0x72, 0x45, 0xba, 0x00, 0x3e, 0xd5, 0x58, 0xe6, 0x0c,
-	0x6a, 0xe7, 0x52, 0xe7, 0xe1, 0x05, 0xea, 0x2e, 0x85, 0x90, 0x3c, 0x2d, 0x14, 0x0e, 0x81, 0x76,
-	0x9e, 0x2b, 0x82, 0x8a, 0x30, 0xc8, 0xdb, 0xb3, 0xb1, 0x8f, 0x46, 0x8c, 0xb3, 0xd0, 0xa7, 0x92,
-	0xeb, 0x5e, 0xd1, 0x3a, 0x4e, 0x32, 0x73, 0x04, 0xdb, 0xfc, 0xa4, 0xdc, 0xe6, 0xf0, 0x6a, 0x97,
-	0x70, 0x59, 0xe0, 0xb9, 0x22, 0x63, 0x76, 0x14, 0xbb, 0x2f, 0xe2, 0x50, 0xdf, 0xb6, 0xfc, 0xcd,
-	0x19, 0x1e, 0x63, 0xf8, 0x7b, 0x84, 0x53, 0xbe, 0x8a, 0x74, 0x50, 0x3f, 0xea, 0x25, 0xf5, 0xa5,
-	0x48, 0x4d, 0x0c, 0xc3, 0x91, 0x5c, 0x91, 0x7b, 0x7b, 0xe8, 0xd7, 0x00, 0xee, 0x95, 0x1b, 0xbd,
-	0x01, 0xce, 0x7e, 0x37, 0x10, 0xbe, 0x2c, 0xde, 0x06, 0xce, 0xea, 0xab, 0xb6, 0x11, 0x2a, 0xee,
-	0x35, 0xe6, 0x92, 0xc2, 0x61, 0x77, 0x1f, 0xdc, 0xb5, 0xea, 0x8f, 0x8a, 0x55, 0xa5, 0xda, 0x3d,
-	0xad, 0xed, 0x46, 0x11, 0x23, 0x57, 0xa4, 0xe1, 0x74, 0xbc, 0xaa, 0xc6, 0xcf, 0xe8, 0xdc, 0xdf,
-	0x55, 0x2e, 0x2e, 0xe6, 0xe4, 0x9d, 0x17, 0xf3, 0x41, 0x79, 0x31, 0xfd, 0x8a, 0x59, 0xdd, 0xcd,
-	0x61, 0x68, 0xf6, 0xaf, 0x81, 0xba, 0xe5, 0xf9, 0xeb, 0x37, 0x0c, 0xff, 0x88, 0x90, 0x9f, 0x72,
-	0xd8, 0x3d, 0x95, 0xe5, 0xc4, 0x6f, 0xeb, 0xf4, 0x7e, 0xd9, 0x69, 0x8f, 0x55, 0x1c, 0x7b, 0xe9,
-	0x7f, 0x29, 0xf1, 0x43, 0xd4, 0x04, 0xf9, 0x27, 0xd3, 0xd3, 0xff, 0x97, 0xdf, 0xce, 0x15, 0x81,
-	0x34, 0x07, 0x7e, 0xf1, 0xf5, 0xbe, 0x6a, 0xa0, 0x9f, 0x02, 0x7d, 0xb2, 0x4f, 0x7f, 0x73, 0xe3,
-	0x76, 0x5f, 0x7f, 0x77, 0x2a, 0xe6, 0x9e, 0x5a, 0x40, 0x3f, 0x7a, 0xbd, 0x9d, 0x18, 0x37, 0xdb,
-	0x89, 0xf1, 0xcf, 0x76, 0x62, 0xbc, 0xba, 0x9d, 0x34, 0x6e, 0x6e, 0x27, 0x8d, 0x3f, 0x6f, 0x27,
-	0x8d, 0x9f, 0x06, 0x47, 0x7f, 0x03, 0xde, 0x19, 0x88, 0x7d, 0xf8, 0x5f, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0xaf, 0x58, 0xa6, 0xc0, 0x20, 0x06, 0x00, 0x00,
+	// 899 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x55, 0x5f, 0x6f, 0xe3, 0x44,
+	0x10, 0x8f, 0xdb, 0xd2, 0x24, 0x9b, 0xff, 0x5b, 0x5a, 0x99, 0x1e, 0xca, 0x46, 0x15, 0x0f, 0x41,
+	0x3a, 0x12, 0xf5, 0x4e, 0x87, 0x84, 0x10, 0x48, 0xa4, 0x2d, 0xd2, 0x21, 0xe0, 0x0e, 0xb7, 0xf7,
+	0x82, 0x90, 0xcc, 0xda, 0xbb, 0xc9, 0x99, 0x8b, 0xbd, 0x91, 0xbd, 0x8d, 0xe0, 0x3e, 0xc5, 0x7d,
+	0x10, 0x9e, 0xf9, 0x0c, 0xf7, 0xd8, 0x47, 0xc4, 0xc3, 0x82, 0xd2, 0x37, 0xf3, 0x25, 0xd0, 0xce,
+	0x3a, 0xb6, 0xd3, 0x72, 0xca, 0x4b, 0xb4, 0x33, 0xbf, 0xf9, 0xcd, 0xec, 0x6f, 0xbc, 0x33, 0x41,
+	0x0f, 0x24, 0x0f, 0x17, 0x82, 0x79, 0x63, 0x8f, 0xfa, 0xaf, 0x78, 0xc4, 0xc6, 0xcb, 0xd3, 0xf1,
+	0xf2, 0x74, 0xb4, 0x88, 0x85, 0x14, 0x18, 0x65, 0xce, 0xd1, 0xf2, 0xf4, 0x98, 0xcc, 0x84, 0x98,
+	0xcd, 0xf9, 0x18, 0x10, 0xef, 0x7a, 0x3a, 0x96, 0x41, 0xc8, 0x13, 0x49, 0xc3, 0x85, 0x09, 0x3e,
+	0xfe, 0x64, 0x16, 0xc8, 0x97, 0xd7, 0xde, 0xc8, 0x17, 0xe1, 0x78, 0x26, 0x66, 0xa2, 0x88, 0xd4,
+	0x16, 0x18, 0x70, 0x32, 0xe1, 0x27, 0xbf, 0x23, 0x54, 0x9f, 0xcc, 0x85, 0xff, 0xea, 0x3b, 0x2e,
+	0x29, 0xfe, 0x08, 0x55, 0x97, 0x3c, 0x4e, 0x02, 0x11, 0xd9, 0xd6, 0xc0, 0x1a, 0xd6, 0x27, 0x28,
+	0x55, 0x64, 0x7f, 0x2a, 0xe2, 0x90, 0x4a, 0x67, 0x0d, 0xe1, 0x2f, 0x50, 0xcd, 0xd3, 0x14, 0x37,
+	0x60, 0xf6, 0xce, 0xc0, 0x1a, 0x36, 0x27, 0x27, 0x6f, 0x15, 0xa9, 0xfc, 0xa5, 0xc8, 0xde, 0x8b,
+	0x17, 0x4f, 0xcf, 0x57, 0x8a, 0x54, 0x21, 0xe5, 0xd3, 0xf3, 0x54, 0x91, 0xaa, 0x67, 0x8e, 0x4e,
+	0x76, 0x60, 0xf8, 0x09, 0xaa, 0x4b, 0x1e, 0xd1, 0x48, 0x6a, 0xfe, 0x7b, 0x50, 0xc6, 0x5e, 0x29,
+	0x52, 0xbb, 0x02, 0x27, 0x90, 0x6a, 0x32, 0x3b, 0x3b, 0xeb, 0x13, 0xc3, 0xcf, 0x11, 0x4a, 0x24,
+	0x8d, 0xa5, 0xab, 0x15, 0xdb, 0xfb, 0x03, 0x6b, 0xd8, 0x78, 0x74, 0x3c, 0x32, 0xed, 0x18, 0xad,
+	0x45, 0x8e, 0xae, 0xd6, 0xed, 0x98, 0x1c, 0xea, 0x3b, 0xa5, 0x8a, 0xd4, 0x81, 0xa5, 0xfd, 0x6f,
+	0xfe, 0x26, 0x96, 0x53, 0x98, 0xf8, 0x1b, 0x54, 0xe3, 0x11, 0x33, 0xf9, 0xaa, 0x5b, 0xf3, 0x1d,
+	0x64, 0xf9, 0xaa, 0x3c, 0x62, 0x79, 0xb6, 0xb5, 0x81, 0x9f, 0xa0, 0x96, 0x14, 0x92, 0xce, 0x5d,
+	0xe1, 0xfd, 0xc2, 0x7d, 0x99, 0xd8, 0xb5, 0x81, 0x35, 0xdc, 0x9d, 0x74, 0x53, 0x45, 0x9a, 0x00,
+	0x3c, 0x33, 0x7e, 0x67, 0xc3, 0xc2, 0x18, 0xed, 0x25, 0xc1, 0x6b, 0x6e, 0xd7, 0x07, 0xd6, 0x70,
+	0xcf, 0x81, 0x33, 0xfe, 0x12, 0x75, 0x7d, 0x11, 0x2e, 0xa8, 0x2f, 0x03, 0x11, 0xb9, 0x73, 0xbe,
+	0xe4, 0x73, 0x1b, 0x0d, 0xac, 0x61, 0x6b, 0x72, 0x90, 0x2a, 0xd2, 0x29, 0xb0, 0x6f, 0x35, 0xe4,
+	0xdc, 0x75, 0xe0, 0x87, 0x5a, 0x96, 0x2f, 0x58, 0x10, 0xcd, 0xec, 0x06, 0x7c, 0x9e, 0x6e, 0xf6,
+	0x79, 0x6a, 0x17, 0x99, 0xdf, 0xc9, 0x23, 0xf0, 0x67, 0xa8, 0x13, 0x44, 0x8c, 0xff, 0xea, 0x2e,
+	0xe8, 0x8c, 0xbb, 0x70, 0x99, 0x26, 0x14, 0xeb, 0xa5, 0x8a, 0xb4, 0x00, 0x7a, 0x4e, 0x67, 0xfc,
+	0x32, 0x78, 0xcd, 0x9d, 0x4d, 0xb3, 0xd0, 0x1c, 0x73, 0x5f, 0xc4, 0x2c, 0xb1, 0x5b, 0x40, 0x2c,
+	0x34, 0x3b, 0xc6, 0xef, 0x6c, 0x58, 0x9a, 0xc6, 0xa8, 0xa4, 0x6e, 0x7e, 0xc9, 0x36, 0xbc, 0x01,
+	0xa0, 0x69, 0x20, 0xbf, 0xe4, 0x86, 0x85, 0x3f, 0x47, 0x3d, 0x6f, 0x2e, 0x44, 0xe8, 0x26, 0x2f,
+	0x69, 0xcc, 0x5c, 0x5f, 0x5c, 0x47, 0xd2, 0xee, 0x40, 0xc5, 0x4e, 0xaa, 0x48, 0x03, 0xc0, 0x4b,
+	0x8d, 0x25, 0x4e, 0xa7, 0x30, 0xce, 0x74, 0x1c, 0x1e, 0xa3, 0xc6, 0x54, 0x08, 0xc9, 0x63, 0xa3,
+	0xb0, 0x0b, 0xb4, 0x76, 0xaa, 0x08, 0x32, 0x6e, 0x90, 0x57, 0x3a, 0x63, 0x1f, 0xf5, 0x18, 0x67,
+	0x81, 0x4f, 0x25, 0xd7, 0xb5, 0xe6, 0xd7, 0x61, 0x94, 0xd8, 0x3d, 0xe8, 0xe6, 0xa7, 0x59, 0x37,
+	0xbb, 0xe7, 0xeb, 0x80, 0x33, 0x83, 0xa7, 0x8a, 0x1c, 0xb3, 0x3b, 0xbe, 0x87, 0x22, 0x0c, 0xf4,
+	0x6c, 0xcb, 0xdf, 0x9c, 0xee, 0x5d, 0x0c, 0x7f, 0x8f, 0x70, 0xcc, 0x17, 0x73, 0xed, 0xd4, 0x9f,
+	0x7a, 0x4a, 0x7d, 0x29, 0x62, 0x1b, 0xc3, 0xe5, 0x48, 0xaa, 0xc8, 0x83, 0x12, 0xfa, 0x35, 0x80,
+	0xa5, 0x74, 0xbd, 0x7b, 0x20, 0xbe, 0x40, 0x9d, 0x98, 0x4b, 0x1e, 0x41, 0x36, 0x7f, 0x4e, 0x93,
+	0xc4, 0x3e, 0x80, 0xde, 0x7e, 0x98, 0x2a, 0x62, 0xe7, 0xd0, 0x99, 0x46, 0x4a, 0x99, 0xda, 0x9b,
+	0x08, 0x9e, 0xa2, 0x2e, 0x8f, 0x63, 0x11, 0xbb, 0xa5, 0x79, 0x7b, 0x7f, 0xeb, 0x7c, 0x0c, 0x74,
+	0x0d, 0xe0, 0x5d, 0xae, 0x27, 0xac, 0xa8, 0x01, 0xc3, 0xd2, 0xde, 0x44, 0xf1, 0xcf, 0xc8, 0x78,
+	0xdc, 0x7c, 0x0a, 0x0f, 0xb7, 0x56, 0xe9, 0xa7, 0x8a, 0x1c, 0x01, 0xeb, 0x22, 0x62, 0xff, 0x53,
+	0xa3, 0x59, 0xc6, 0xf0, 0x33, 0x74, 0x60, 0x2a, 0xc0, 0x96, 0x74, 0x65, 0xac, 0x17, 0x29, 0xb3,
+	0x8f, 0x06, 0xd6, 0xb0, 0x66, 0x3a, 0x0c, 0x30, 0xa4, 0xbf, 0x32, 0x60, 0xb9, 0xc3, 0xf7, 0xc0,
+	0x93, 0x3f, 0x2c, 0x84, 0xcf, 0xcc, 0xbc, 0x71, 0x56, 0xec, 0xcd, 0x09, 0x42, 0x66, 0x23, 0x86,
+	0x5c, 0x52, 0x58, 0x9d, 0x8d, 0x47, 0x87, 0xa3, 0x62, 0x6d, 0x8f, 0xf2, 0xd0, 0x49, 0x53, 0xbf,
+	0x9e, 0x1b, 0x45, 0xac, 0x54, 0x91, 0x8a, 0x53, 0xf7, 0xf2, 0x1c, 0x3f, 0xa1, 0xb6, 0xbf, 0xce,
+	0x6c, 0xba, 0xb1, 0xb3, 0xb5, 0x1b, 0x1f, 0x64, 0x3b, 0xa9, 0x95, 0x33, 0xf3, 0xcd, 0xb4, 0xe9,
+	0x3a, 0xf9, 0xd7, 0x42, 0x8d, 0x6c, 0xc1, 0xea, 0x19, 0xc6, 0x3f, 0x20, 0xe4, 0xc7, 0x1c, 0x5e,
+	0x37, 0x95, 0xb6, 0xb5, 0xb5, 0xd2, 0x51, 0x56, 0xa9, 0xc4, 0x32, 0xeb, 0x34, 0xb3, 0xbf, 0x92,
+	0xf8, 0x31, 0xda, 0x03, 0xf9, 0x3b, 0x83, 0xdd, 0x77, 0xcb, 0xaf, 0xa5, 0x8a, 0x40, 0x98, 0x03,
+	0xbf, 0xf8, 0xaa, 0xac, 0x1a, 0xe8, 0xbb, 0x40, 0xef, 0x97, 0xe9, 0xf7, 0x3b, 0x3e, 0x69, 0xe9,
+	0xcd, 0x9e, 0x33, 0x4b, 0x6a, 0x01, 0xfd, 0xf8, 0xed, 0xaa, 0x6f, 0xdd, 0xac, 0xfa, 0xd6, 0x3f,
+	0xab, 0xbe, 0xf5, 0xe6, 0xb6, 0x5f, 0xb9, 0xb9, 0xed, 0x57, 0xfe, 0xbc, 0xed, 0x57, 0x7e, 0xec,
+	0xdc, 0xf9, 0xa3, 0xf5, 0xf6, 0x41, 0xec, 0xe3, 0xff, 0x06, 0x00, 0xd8, 0x0d, 0xc4, 0xfc, 0x82,
+	0x07, 0x00, 0x00,
 }
 
 func (m *BlockMeta) Marshal() (dAtA []byte, err error) {
@@ -358,6 +460,115 @@ func (m *BlockMeta) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.EncryptionKeyVersion != 0 {
+		i = encodeVarintV1(dAtA, i, uint64(m.EncryptionKeyVersion))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xe8
+	}
+	if m.NameDictionary {
+		i--
+		if m.NameDictionary {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xe0
+	}
+	if len(m.EncryptionKey) > 0 {
+		i -= len(m.EncryptionKey)
+		copy(dAtA[i:], m.EncryptionKey)
+		i = encodeVarintV1(dAtA, i, uint64(len(m.EncryptionKey)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xda
+	}
+	if len(m.Source) > 0 {
+		i -= len(m.Source)
+		copy(dAtA[i:], m.Source)
+		i = encodeVarintV1(dAtA, i, uint64(len(m.Source)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xd2
+	}
+	if len(m.MaxID) > 0 {
+		i -= len(m.MaxID)
+		copy(dAtA[i:], m.MaxID)
+		i = encodeVarintV1(dAtA, i, uint64(len(m.MaxID)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xca
+	}
+	if len(m.MinID) > 0 {
+		i -= len(m.MinID)
+		copy(dAtA[i:], m.MinID)
+		i = encodeVarintV1(dAtA, i, uint64(len(m.MinID)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xc2
+	}
+	if len(m.TraceIDSummary) > 0 {
+		i -= len(m.TraceIDSummary)
+		copy(dAtA[i:], m.TraceIDSummary)
+		i = encodeVarintV1(dAtA, i, uint64(len(m.TraceIDSummary)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xba
+	}
+	if m.ErrorTimesTracked {
+		i--
+		if m.ErrorTimesTracked {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xb0
+	}
+	if m.ErrorEndTime != nil {
+		n1, err1 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.ErrorEndTime, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.ErrorEndTime):])
+		if err1 != nil {
+			return 0, err1
+		}
+		i -= n1
+		i = encodeVarintV1(dAtA, i, uint64(n1))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xaa
+	}
+	if m.ErrorStartTime != nil {
+		n2, err2 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.ErrorStartTime, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.ErrorStartTime):])
+		if err2 != nil {
+			return 0, err2
+		}
+		i -= n2
+		i = encodeVarintV1(dAtA, i, uint64(n2))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xa2
+	}
+	if len(m.RetentionClass) > 0 {
+		i -= len(m.RetentionClass)
+		copy(dAtA[i:], m.RetentionClass)
+		i = encodeVarintV1(dAtA, i, uint64(len(m.RetentionClass)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x9a
+	}
 	if m.ReplicationFactor != 0 {
 		i = encodeVarintV1(dAtA, i, uint64(m.ReplicationFactor))
 		i--
@@ -431,20 +642,20 @@ func (m *BlockMeta) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x40
 	}
-	n1, err1 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.EndTime, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.EndTime):])
-	if err1 != nil {
-		return 0, err1
+	n3, err3 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.EndTime, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.EndTime):])
+	if err3 != nil {
+		return 0, err3
 	}
-	i -= n1
-	i = encodeVarintV1(dAtA, i, uint64(n1))
+	i -= n3
+	i = encodeVarintV1(dAtA, i, uint64(n3))
 	i--
 	dAtA[i] = 0x3a
-	n2, err2 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTime, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTime):])
-	if err2 != nil {
-		return 0, err2
+	n4, err4 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTime, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTime):])
+	if err4 != nil {
+		return 0, err4
 	}
-	i -= n2
-	i = encodeVarintV1(dAtA, i, uint64(n2))
+	i -= n4
+	i = encodeVarintV1(dAtA, i, uint64(n4))
 	i--
 	dAtA[i] = 0x32
 	if len(m.TenantID) > 0 {
@@ -494,12 +705,12 @@ func (m *CompactedBlockMeta) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
-	n3, err3 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.CompactedTime, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.CompactedTime):])
-	if err3 != nil {
-		return 0, err3
+	n5, err5 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.CompactedTime, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.CompactedTime):])
+	if err5 != nil {
+		return 0, err5
 	}
-	i -= n3
-	i = encodeVarintV1(dAtA, i, uint64(n3))
+	i -= n5
+	i = encodeVarintV1(dAtA, i, uint64(n5))
 	i--
 	dAtA[i] = 0x12
 	{
@@ -563,12 +774,12 @@ func (m *TenantIndex) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 			dAtA[i] = 0x12
 		}
 	}
-	n5, err5 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.CreatedAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.CreatedAt):])
-	if err5 != nil {
-		return 0, err5
+	n7, err7 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.CreatedAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.CreatedAt):])
+	if err7 != nil {
+		return 0, err7
 	}
-	i -= n5
-	i = encodeVarintV1(dAtA, i, uint64(n5))
+	i -= n7
+	i = encodeVarintV1(dAtA, i, uint64(n7))
 	i--
 	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
@@ -637,6 +848,47 @@ func (m *BlockMeta) Size() (n int) {
 	if m.ReplicationFactor != 0 {
 		n += 2 + sovV1(uint64(m.ReplicationFactor))
 	}
+	l = len(m.RetentionClass)
+	if l > 0 {
+		n += 2 + l + sovV1(uint64(l))
+	}
+	if m.ErrorStartTime != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdTime(*m.ErrorStartTime)
+		n += 2 + l + sovV1(uint64(l))
+	}
+	if m.ErrorEndTime != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdTime(*m.ErrorEndTime)
+		n += 2 + l + sovV1(uint64(l))
+	}
+	if m.ErrorTimesTracked {
+		n += 3
+	}
+	l = len(m.TraceIDSummary)
+	if l > 0 {
+		n += 2 + l + sovV1(uint64(l))
+	}
+	l = len(m.MinID)
+	if l > 0 {
+		n += 2 + l + sovV1(uint64(l))
+	}
+	l = len(m.MaxID)
+	if l > 0 {
+		n += 2 + l + sovV1(uint64(l))
+	}
+	l = len(m.Source)
+	if l > 0 {
+		n += 2 + l + sovV1(uint64(l))
+	}
+	l = len(m.EncryptionKey)
+	if l > 0 {
+		n += 2 + l + sovV1(uint64(l))
+	}
+	if m.NameDictionary {
+		n += 3
+	}
+	if m.EncryptionKeyVersion != 0 {
+		n += 2 + sovV1(uint64(m.EncryptionKeyVersion))
+	}
 	return n
 }
 
@@ -1124,6 +1376,337 @@ func (m *BlockMeta) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RetentionClass", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowV1
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthV1
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthV1
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RetentionClass = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorStartTime", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowV1
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthV1
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthV1
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ErrorStartTime == nil {
+				m.ErrorStartTime = new(time.Time)
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(m.ErrorStartTime, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 21:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorEndTime", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowV1
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthV1
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthV1
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ErrorEndTime == nil {
+				m.ErrorEndTime = new(time.Time)
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(m.ErrorEndTime, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 22:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorTimesTracked", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowV1
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ErrorTimesTracked = bool(v != 0)
+		case 23:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceIDSummary", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowV1
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthV1
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthV1
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TraceIDSummary = append(m.TraceIDSummary[:0], dAtA[iNdEx:postIndex]...)
+			if m.TraceIDSummary == nil {
+				m.TraceIDSummary = []byte{}
+			}
+			iNdEx = postIndex
+		case 24:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinID", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowV1
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthV1
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthV1
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MinID = append(m.MinID[:0], dAtA[iNdEx:postIndex]...)
+			if m.MinID == nil {
+				m.MinID = []byte{}
+			}
+			iNdEx = postIndex
+		case 25:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxID", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowV1
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthV1
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthV1
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MaxID = append(m.MaxID[:0], dAtA[iNdEx:postIndex]...)
+			if m.MaxID == nil {
+				m.MaxID = []byte{}
+			}
+			iNdEx = postIndex
+		case 26:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Source", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowV1
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthV1
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthV1
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Source = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 27:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EncryptionKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowV1
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthV1
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthV1
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EncryptionKey = append(m.EncryptionKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.EncryptionKey == nil {
+				m.EncryptionKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 28:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NameDictionary", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowV1
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.NameDictionary = bool(v != 0)
+		case 29:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EncryptionKeyVersion", wireType)
+			}
+			m.EncryptionKeyVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowV1
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EncryptionKeyVersion |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipV1(dAtA[iNdEx:])
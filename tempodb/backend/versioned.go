@@ -18,6 +18,7 @@ const (
 var (
 	ErrVersionDoesNotMatch = errors.New("version does not match")
 	ErrVersionInvalid      = errors.New("version is not valid")
+	ErrNotModified         = errors.New("not modified")
 )
 
 // VersionedReaderWriter is a collection of methods to read and write data from tempodb backends with
@@ -37,6 +38,15 @@ type VersionedReaderWriter interface {
 	ReadVersioned(ctx context.Context, name string, keypath KeyPath) (io.ReadCloser, Version, error)
 }
 
+// ConditionalReader is implemented by backends that can revalidate an object against a version returned by a
+// previous read without downloading it again.
+type ConditionalReader interface {
+	// ReadIfChanged reads an object and returns its current version if the version differs from the one given.
+	// If the object has not changed the request fails with ErrNotModified. An empty version always reads the
+	// object.
+	ReadIfChanged(ctx context.Context, name string, keypath KeyPath, version Version) (io.ReadCloser, int64, Version, error)
+}
+
 type FakeVersionedReaderWriter struct {
 	RawReader
 	RawWriter
@@ -0,0 +1,148 @@
+package tempodb
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+const (
+	BlockRepairActionRebuilt     = "rebuilt"
+	BlockRepairActionQuarantined = "quarantined"
+)
+
+var (
+	metricBlockRepairChecked = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "block_repair_blocks_checked_total",
+		Help:      "Total number of blocks whose objects were checked by the block repair.",
+	})
+	metricBlockRepairActions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "block_repair_blocks_total",
+		Help:      "Total number of blocks repaired, by action: rebuilt or quarantined.",
+	}, []string{"action"})
+	metricBlockRepairObjectsRebuilt = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "block_repair_objects_rebuilt_total",
+		Help:      "Total number of block objects rebuilt from the data of their block.",
+	})
+	metricBlockRepairErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "block_repair_errors_total",
+		Help:      "Total number of blocks that failed to be checked or repaired.",
+	})
+)
+
+// BlockRepairConfig configures the job rebuilding the objects of blocks, like the blooms, that are missing or corrupt.
+type BlockRepairConfig struct {
+	// Interval between two checks of the blocks of the blocklist. 0 disables the block repair.
+	Interval time.Duration `yaml:"interval"`
+	// Quarantine moves blocks whose data is missing or corrupt to the quarantine of their tenant.
+	Quarantine bool `yaml:"quarantine"`
+}
+
+func (cfg *BlockRepairConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.DurationVar(&cfg.Interval, util.PrefixConfig(prefix, "block-repair.interval"), 0, "Interval between two checks of the blocks owned by the compactor for missing or corrupt objects. 0 disables the block repair.")
+	f.BoolVar(&cfg.Quarantine, util.PrefixConfig(prefix, "block-repair.quarantine"), true, "Move blocks whose data is missing or corrupt to the quarantine of their tenant.")
+}
+
+// Enabled returns true if the block repair is enabled.
+func (cfg *BlockRepairConfig) Enabled() bool {
+	return cfg.Interval > 0
+}
+
+// blockRepairLoop checks the blocks owned by this compactor every interval of the block repair. Blocks are only
+// checked once, the objects of a complete block don't change.
+func (rw *readerWriter) blockRepairLoop(ctx context.Context) {
+	checked := map[backend.UUID]struct{}{}
+
+	ticker := time.NewTicker(rw.compactorCfg.BlockRepair.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			checked = rw.doBlockRepair(ctx, checked)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (rw *readerWriter) doBlockRepair(ctx context.Context, previous map[backend.UUID]struct{}) map[backend.UUID]struct{} {
+	// only the blocks still in the blocklist are kept
+	checked := map[backend.UUID]struct{}{}
+	for _, tenantID := range rw.blocklist.Tenants() {
+		for _, meta := range rw.blocklist.Metas(tenantID) {
+			if ctx.Err() != nil {
+				return previous
+			}
+			if _, ok := previous[meta.BlockID]; ok {
+				checked[meta.BlockID] = struct{}{}
+				continue
+			}
+			// the blocks of federated buckets are never written to
+			if meta.IsFederated() || !rw.compactorSharder.Owns(meta.BlockID.String()) {
+				continue
+			}
+
+			action, err := rw.repairBlock(ctx, meta)
+			if err != nil {
+				level.Error(rw.logger).Log("msg", "failed to repair block", "blockID", meta.BlockID, "tenantID", tenantID, "err", err)
+				metricBlockRepairErrors.Inc()
+				continue
+			}
+			metricBlockRepairChecked.Inc()
+			if action != "" {
+				metricBlockRepairActions.WithLabelValues(action).Inc()
+			}
+			if action != BlockRepairActionQuarantined {
+				checked[meta.BlockID] = struct{}{}
+			}
+		}
+	}
+	return checked
+}
+
+// repairBlock rebuilds the missing or corrupt objects of the block, or quarantines it if its data is missing or
+// corrupt. The action taken is returned, empty if the block is complete or its encoding can't repair blocks.
+func (rw *readerWriter) repairBlock(ctx context.Context, meta *backend.BlockMeta) (string, error) {
+	enc, err := encoding.FromVersion(meta.Version)
+	if err != nil {
+		return "", err
+	}
+	repairer, ok := enc.(encoding.BlockRepairer)
+	if !ok {
+		return "", nil
+	}
+
+	rebuilt, err := repairer.RepairBlock(ctx, rw.cfg.Block, meta, rw.r, rw.w)
+	metricBlockRepairObjectsRebuilt.Add(float64(len(rebuilt)))
+	if errors.Is(err, common.ErrCorruptBlockData) && rw.compactorCfg.BlockRepair.Quarantine {
+		level.Warn(rw.logger).Log("msg", "quarantining block", "blockID", meta.BlockID, "tenantID", meta.TenantID, "reason", err)
+		if err := backend.MoveBlockToQuarantine(ctx, rw.rawR, rw.rawW, rw.c, meta, err.Error(), time.Now()); err != nil {
+			return "", err
+		}
+		rw.blocklist.Update(meta.TenantID, nil, []*backend.BlockMeta{meta}, nil, nil)
+		return BlockRepairActionQuarantined, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if len(rebuilt) == 0 {
+		return "", nil
+	}
+	level.Info(rw.logger).Log("msg", "rebuilt block objects", "blockID", meta.BlockID, "tenantID", meta.TenantID, "objects", len(rebuilt))
+	return BlockRepairActionRebuilt, nil
+}
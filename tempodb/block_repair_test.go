@@ -0,0 +1,86 @@
+package tempodb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
+)
+
+func TestBlockRepair(t *testing.T) {
+	ctx := context.Background()
+
+	r, w, c, _ := testConfig(t, backend.EncNone, 0)
+	require.NoError(t, c.EnableCompaction(ctx, &CompactorConfig{
+		MaxCompactionRange: time.Hour,
+		BlockRepair:        BlockRepairConfig{Quarantine: true},
+	}, &mockSharder{}, &mockOverrides{}))
+	r.EnablePolling(ctx, &mockJobSharder{}, false)
+	rw := r.(*readerWriter)
+
+	dec := model.MustNewSegmentDecoder(model.CurrentEncoding)
+	writeBlock := func() *backend.BlockMeta {
+		block, err := w.WAL().NewBlock(backend.NewBlockMeta(testTenantID, uuid.New(), vparquet4.VersionString, backend.EncNone, ""), model.CurrentEncoding)
+		require.NoError(t, err)
+		for i := 0; i < 10; i++ {
+			id := test.ValidTraceID(nil)
+			writeTraceToWal(t, block, dec, id, test.MakeTrace(2, id), 0, 0)
+		}
+		require.NoError(t, block.Flush())
+
+		complete, err := w.CompleteBlock(ctx, block)
+		require.NoError(t, err)
+		return complete.BlockMeta()
+	}
+	complete, partial, corrupt := writeBlock(), writeBlock(), writeBlock()
+
+	blockPath := func(m *backend.BlockMeta) backend.KeyPath {
+		return backend.KeyPathForBlock((uuid.UUID)(m.BlockID), m.TenantID)
+	}
+	require.NoError(t, rw.rawW.Delete(ctx, common.BloomName(0), blockPath(partial), nil))
+	require.NoError(t, rw.rawW.Delete(ctx, vparquet4.DataFileName, blockPath(corrupt), nil))
+
+	rw.pollBlocklist(ctx)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 3)
+
+	checked := rw.doBlockRepair(ctx, map[backend.UUID]struct{}{})
+	require.Equal(t, map[backend.UUID]struct{}{complete.BlockID: {}, partial.BlockID: {}}, checked)
+
+	// the bloom is rebuilt
+	require.NoError(t, rw.ValidateBlock(ctx, testTenantID, partial.BlockID))
+
+	// the corrupt block is moved to quarantine with its meta
+	metas := rw.blocklist.Metas(testTenantID)
+	require.Len(t, metas, 2)
+	require.NotContains(t, []backend.UUID{metas[0].BlockID, metas[1].BlockID}, corrupt.BlockID)
+
+	_, err := rw.r.BlockMeta(ctx, (uuid.UUID)(corrupt.BlockID), testTenantID)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+
+	rc, _, err := rw.rawR.Read(ctx, backend.QuarantinedMetaName, backend.KeyPathForQuarantinedBlock((uuid.UUID)(corrupt.BlockID), testTenantID), nil)
+	require.NoError(t, err)
+	defer rc.Close()
+	b, err := io.ReadAll(rc)
+	require.NoError(t, err)
+
+	quarantined := &backend.QuarantinedBlockMeta{}
+	require.NoError(t, json.Unmarshal(b, quarantined))
+	require.Equal(t, corrupt.BlockID, quarantined.BlockID)
+	require.Contains(t, quarantined.Reason, common.ErrCorruptBlockData.Error())
+
+	// checked blocks aren't checked again
+	require.NoError(t, rw.rawW.Delete(ctx, common.BloomName(0), blockPath(complete), nil))
+	checked = rw.doBlockRepair(ctx, checked)
+	require.Len(t, checked, 2)
+	require.Error(t, rw.ValidateBlock(ctx, testTenantID, complete.BlockID))
+}
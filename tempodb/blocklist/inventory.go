@@ -0,0 +1,387 @@
+package blocklist
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// Supported inventory formats
+const (
+	// InventoryFormatS3 is the CSV format of S3 Inventory. There is no header, the object key is the second
+	// column and is URL encoded.
+	InventoryFormatS3 = "s3"
+	// InventoryFormatGCS is the CSV format of GCS Storage Insights. The header names the columns and the object
+	// key is in the name column.
+	InventoryFormatGCS = "gcs"
+)
+
+// InventoryManifestName is the name of the manifest of an S3 Inventory, which lists the CSV files of the inventory.
+const InventoryManifestName = "manifest.json"
+
+// InventoryConfig configures a bucket inventory used to bootstrap the blocklist of tenants without listing them.
+type InventoryConfig struct {
+	// Path is the object key of the inventory, relative to the backend prefix. It is either a CSV file, files
+	// ending in .gz are decompressed, the manifest.json of an S3 Inventory, or a directory ending in / in which
+	// the latest manifest.json is used. An empty path disables the inventory.
+	Path string `yaml:"path"`
+	// Format is one of InventoryFormatS3 or InventoryFormatGCS.
+	Format string `yaml:"format"`
+	// MaxAge is the maximum age of the inventory file. Older inventories are ignored.
+	MaxAge time.Duration `yaml:"max_age"`
+}
+
+// Validate returns an error if the config is enabled and invalid.
+func (c *InventoryConfig) Validate() error {
+	if c.Path == "" {
+		return nil
+	}
+
+	// the directory of the inventory is listed to find it, which must not be the whole bucket
+	if dir, _ := path.Split(strings.TrimPrefix(c.Path, "/")); dir == "" {
+		return fmt.Errorf("inventory path %q must be in a directory", c.Path)
+	}
+
+	switch c.Format {
+	case InventoryFormatS3, InventoryFormatGCS:
+	default:
+		return fmt.Errorf("unknown inventory format %q, must be one of %s or %s", c.Format, InventoryFormatS3, InventoryFormatGCS)
+	}
+
+	if c.isManifest() && c.Format != InventoryFormatS3 {
+		return fmt.Errorf("inventory manifests are only supported with the %s format", InventoryFormatS3)
+	}
+
+	if c.MaxAge <= 0 {
+		return errors.New("inventory max age must be greater than 0")
+	}
+
+	return nil
+}
+
+// isManifest returns true if the inventory is read from the manifest of an S3 Inventory.
+func (c *InventoryConfig) isManifest() bool {
+	return strings.HasSuffix(c.Path, "/") || path.Base(c.Path) == InventoryManifestName
+}
+
+// Inventory is a point in time listing of the blocks in the backend.
+type Inventory struct {
+	CreatedAt time.Time
+	tenants   map[string]*inventoryTenant
+}
+
+type inventoryTenant struct {
+	blockIDs          []uuid.UUID
+	compactedBlockIDs []uuid.UUID
+}
+
+// Blocks returns the block IDs and compacted block IDs of the tenant at the time of the inventory. ok is false if
+// the tenant is not in the inventory.
+func (i *Inventory) Blocks(tenantID string) (blockIDs []uuid.UUID, compactedBlockIDs []uuid.UUID, ok bool) {
+	t, ok := i.tenants[tenantID]
+	if !ok {
+		return nil, nil, false
+	}
+	return t.blockIDs, t.compactedBlockIDs, true
+}
+
+// InventorySource returns the latest inventory of the backend.
+type InventorySource interface {
+	Inventory(ctx context.Context) (*Inventory, error)
+}
+
+// InventoryReader reads the inventory from the backend. The parsed inventory is kept until the inventory changes.
+type InventoryReader struct {
+	cfg InventoryConfig
+	r   backend.RawReader
+
+	mtx      sync.Mutex
+	key      string
+	modified time.Time
+	current  *Inventory
+}
+
+var _ InventorySource = (*InventoryReader)(nil)
+
+// NewInventoryReader creates an InventoryReader. It returns nil if the inventory is not configured.
+func NewInventoryReader(cfg InventoryConfig, r backend.RawReader) *InventoryReader {
+	if cfg.Path == "" {
+		return nil
+	}
+
+	return &InventoryReader{
+		cfg: cfg,
+		r:   r,
+	}
+}
+
+// Inventory implements InventorySource. An inventory older than the configured max age is an error.
+func (i *InventoryReader) Inventory(ctx context.Context) (*Inventory, error) {
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+
+	key, modified, err := i.find(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the age of a manifest is checked against its creation time after reading it
+	if !i.cfg.isManifest() {
+		if age := time.Since(modified); age > i.cfg.MaxAge {
+			return nil, fmt.Errorf("inventory %s is %s old which exceeds the max age of %s", key, age, i.cfg.MaxAge)
+		}
+	}
+
+	if i.current != nil && i.key == key && i.modified.Equal(modified) {
+		return i.current, nil
+	}
+
+	var inv *Inventory
+	if i.cfg.isManifest() {
+		inv, err = i.readManifest(ctx, key)
+	} else {
+		inv = newInventory()
+		err = i.readFile(ctx, inv, key, i.cfg.Format, 1)
+		inv.CreatedAt = modified
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if age := time.Since(inv.CreatedAt); age > i.cfg.MaxAge {
+		return nil, fmt.Errorf("inventory %s is %s old which exceeds the max age of %s", key, age, i.cfg.MaxAge)
+	}
+
+	i.current = inv
+	i.key = key
+	i.modified = modified
+
+	return inv, nil
+}
+
+// find returns the key, relative to the backend prefix, and the modification time of the inventory file or of
+// the latest manifest in the inventory directory. Only the directory of the inventory is listed.
+func (i *InventoryReader) find(ctx context.Context) (string, time.Time, error) {
+	dir := strings.Trim(path.Dir(strings.TrimPrefix(i.cfg.Path, "/")), "/")
+	if strings.HasSuffix(i.cfg.Path, "/") {
+		dir = strings.Trim(i.cfg.Path, "/")
+	}
+
+	var (
+		key      string
+		modified time.Time
+	)
+	err := i.r.Find(ctx, backend.KeyPath(strings.Split(dir, "/")), func(m backend.FindMatch) {
+		// the keys of the matches include the backend prefix
+		idx := strings.LastIndex(m.Key, dir+"/")
+		if idx < 0 {
+			return
+		}
+		rel := m.Key[idx:]
+
+		if strings.HasSuffix(i.cfg.Path, "/") {
+			if path.Base(rel) == InventoryManifestName && m.Modified.After(modified) {
+				key, modified = rel, m.Modified
+			}
+			return
+		}
+		if rel == strings.TrimPrefix(i.cfg.Path, "/") {
+			key, modified = rel, m.Modified
+		}
+	})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to find inventory: %w", err)
+	}
+	if modified.IsZero() {
+		return "", time.Time{}, fmt.Errorf("inventory %s: %w", i.cfg.Path, backend.ErrDoesNotExist)
+	}
+
+	return key, modified, nil
+}
+
+// s3InventoryManifest is the manifest.json of an S3 Inventory.
+type s3InventoryManifest struct {
+	// CreationTimestamp is in milliseconds since the epoch
+	CreationTimestamp string `json:"creationTimestamp"`
+	FileFormat        string `json:"fileFormat"`
+	FileSchema        string `json:"fileSchema"`
+	Files             []struct {
+		Key string `json:"key"`
+	} `json:"files"`
+}
+
+// readManifest reads the inventory from the CSV files listed in an S3 Inventory manifest. The manifest is in a
+// dated directory next to the data directory of the files: <root>/<date>/manifest.json and <root>/data/<file>.
+func (i *InventoryReader) readManifest(ctx context.Context, key string) (*Inventory, error) {
+	b, err := i.readAll(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var m s3InventoryManifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse inventory manifest %s: %w", key, err)
+	}
+	if !strings.EqualFold(m.FileFormat, "csv") {
+		return nil, fmt.Errorf("inventory manifest %s has unsupported file format %q, only CSV is supported", key, m.FileFormat)
+	}
+	keyColumn := slices.IndexFunc(strings.Split(m.FileSchema, ","), func(c string) bool { return strings.TrimSpace(c) == "Key" })
+	if keyColumn < 0 {
+		return nil, fmt.Errorf("inventory manifest %s has no Key column in schema %q", key, m.FileSchema)
+	}
+	createdMs, err := strconv.ParseInt(m.CreationTimestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("inventory manifest %s has invalid creation timestamp %q: %w", key, m.CreationTimestamp, err)
+	}
+
+	inv := newInventory()
+	inv.CreatedAt = time.UnixMilli(createdMs)
+
+	root := path.Dir(path.Dir(key))
+	for _, f := range m.Files {
+		idx := strings.LastIndex(f.Key, "data/")
+		if idx < 0 {
+			return nil, fmt.Errorf("inventory manifest %s lists file %s outside of a data directory", key, f.Key)
+		}
+		if err := i.readFile(ctx, inv, path.Join(root, f.Key[idx:]), InventoryFormatS3, keyColumn); err != nil {
+			return nil, err
+		}
+	}
+
+	return inv, nil
+}
+
+// readFile adds the blocks of an inventory CSV file to inv. keyColumn is the column of the object keys of the s3
+// format.
+func (i *InventoryReader) readFile(ctx context.Context, inv *Inventory, key string, format string, keyColumn int) error {
+	rc, err := i.open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	var reader io.Reader = rc
+	if strings.HasSuffix(key, ".gz") {
+		gz, err := gzip.NewReader(rc)
+		if err != nil {
+			return fmt.Errorf("failed to decompress inventory %s: %w", key, err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	if err := parseInventory(inv, reader, format, keyColumn); err != nil {
+		return fmt.Errorf("failed to parse inventory %s: %w", key, err)
+	}
+	return nil
+}
+
+func (i *InventoryReader) readAll(ctx context.Context, key string) ([]byte, error) {
+	rc, err := i.open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
+
+// open opens the object of the key relative to the backend prefix.
+func (i *InventoryReader) open(ctx context.Context, key string) (io.ReadCloser, error) {
+	dir, name := path.Split(key)
+	rc, _, err := i.r.Read(ctx, name, backend.KeyPath(strings.Split(strings.Trim(dir, "/"), "/")), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inventory %s: %w", key, err)
+	}
+	return rc, nil
+}
+
+func newInventory() *Inventory {
+	return &Inventory{
+		tenants: map[string]*inventoryTenant{},
+	}
+}
+
+// parseInventory adds the per tenant block lists of the object keys in the inventory to inv. Keys are matched on
+// their last three segments, <tenant>/<block id>/<meta>, so the backend prefix does not need to be known. The
+// object keys of the s3 format are in keyColumn, the gcs format names its columns in a header.
+func parseInventory(inv *Inventory, r io.Reader, format string, keyColumn int) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	if format == InventoryFormatGCS {
+		header, err := cr.Read()
+		if err != nil {
+			return fmt.Errorf("failed to read header: %w", err)
+		}
+
+		keyColumn = slices.Index(header, "name")
+		if keyColumn < 0 {
+			return errors.New("header has no name column")
+		}
+	}
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if keyColumn >= len(record) {
+			continue
+		}
+
+		key := record[keyColumn]
+		if format == InventoryFormatS3 {
+			key, err = url.QueryUnescape(key)
+			if err != nil {
+				continue
+			}
+		}
+
+		parts := strings.Split(key, "/")
+		if len(parts) < 3 {
+			continue
+		}
+		tenantID, blockID, name := parts[len(parts)-3], parts[len(parts)-2], parts[len(parts)-1]
+		if name != backend.MetaName && name != backend.CompactedMetaName {
+			continue
+		}
+		id, err := uuid.Parse(blockID)
+		if err != nil {
+			continue
+		}
+
+		t, ok := inv.tenants[tenantID]
+		if !ok {
+			t = &inventoryTenant{}
+			inv.tenants[tenantID] = t
+		}
+
+		if name == backend.MetaName {
+			t.blockIDs = append(t.blockIDs, id)
+		} else {
+			t.compactedBlockIDs = append(t.compactedBlockIDs, id)
+		}
+	}
+
+	return nil
+}
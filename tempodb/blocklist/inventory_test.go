@@ -0,0 +1,259 @@
+package blocklist
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+)
+
+var (
+	inventoryBlockID          = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	inventoryCompactedBlockID = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+)
+
+func TestParseInventory(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+	}{
+		{
+			name:   "s3",
+			format: InventoryFormatS3,
+			data: `"bucket","prefix/single-tenant/00000000-0000-0000-0000-000000000001/meta.json","123"
+"bucket","prefix/single-tenant/00000000-0000-0000-0000-000000000001/data.parquet","123"
+"bucket","prefix/single-tenant/00000000-0000-0000-0000-000000000002/meta.compacted.json","123"
+"bucket","prefix/single-tenant/index.json.gz","123"
+"bucket","prefix/single-tenant/not-a-block/meta.json","123"
+"bucket","prefix/tenant%3Aencoded/00000000-0000-0000-0000-000000000001/meta.json","123"
+`,
+		},
+		{
+			name:   "gcs",
+			format: InventoryFormatGCS,
+			data: `bucket,name,size
+bucket,prefix/single-tenant/00000000-0000-0000-0000-000000000001/meta.json,123
+bucket,prefix/single-tenant/00000000-0000-0000-0000-000000000001/data.parquet,123
+bucket,prefix/single-tenant/00000000-0000-0000-0000-000000000002/meta.compacted.json,123
+bucket,prefix/single-tenant/index.json.gz,123
+bucket,prefix/single-tenant/not-a-block/meta.json,123
+bucket,prefix/tenant:encoded/00000000-0000-0000-0000-000000000001/meta.json,123
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			inv := newInventory()
+			require.NoError(t, parseInventory(inv, strings.NewReader(tc.data), tc.format, 1))
+
+			blockIDs, compactedBlockIDs, ok := inv.Blocks("single-tenant")
+			require.True(t, ok)
+			require.Equal(t, []uuid.UUID{inventoryBlockID}, blockIDs)
+			require.Equal(t, []uuid.UUID{inventoryCompactedBlockID}, compactedBlockIDs)
+
+			blockIDs, compactedBlockIDs, ok = inv.Blocks("tenant:encoded")
+			require.True(t, ok)
+			require.Equal(t, []uuid.UUID{inventoryBlockID}, blockIDs)
+			require.Empty(t, compactedBlockIDs)
+
+			_, _, ok = inv.Blocks("unknown")
+			require.False(t, ok)
+		})
+	}
+
+	err := parseInventory(newInventory(), strings.NewReader("bucket,key\n"), InventoryFormatGCS, 1)
+	require.ErrorContains(t, err, "no name column")
+}
+
+func TestInventoryReader(t *testing.T) {
+	dir := t.TempDir()
+	r, w, _, err := local.New(&local.Config{Path: dir})
+	require.NoError(t, err)
+
+	cfg := InventoryConfig{
+		Path:   "inventory/tempo.csv.gz",
+		Format: InventoryFormatS3,
+		MaxAge: time.Hour,
+	}
+	require.NoError(t, cfg.Validate())
+
+	ctx := context.Background()
+	inventory := NewInventoryReader(cfg, r)
+
+	_, err = inventory.Inventory(ctx)
+	require.Error(t, err)
+
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	_, err = gz.Write([]byte(`"bucket","single-tenant/00000000-0000-0000-0000-000000000001/meta.json"` + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	require.NoError(t, w.Write(ctx, "tempo.csv.gz", backend.KeyPath{"inventory"}, bytes.NewReader(buf.Bytes()), int64(buf.Len()), nil))
+
+	inv, err := inventory.Inventory(ctx)
+	require.NoError(t, err)
+	blockIDs, _, ok := inv.Blocks("single-tenant")
+	require.True(t, ok)
+	require.Equal(t, []uuid.UUID{inventoryBlockID}, blockIDs)
+
+	// the parsed inventory is kept until the file changes
+	again, err := inventory.Inventory(ctx)
+	require.NoError(t, err)
+	require.Same(t, inv, again)
+
+	// stale inventories are not used
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "inventory", "tempo.csv.gz"), old, old))
+	_, err = inventory.Inventory(ctx)
+	require.ErrorContains(t, err, "exceeds the max age")
+
+	require.Nil(t, NewInventoryReader(InventoryConfig{}, r))
+	require.Error(t, (&InventoryConfig{Path: "inventory/tempo.csv", Format: "parquet", MaxAge: time.Hour}).Validate())
+	require.Error(t, (&InventoryConfig{Path: "inventory/tempo.csv", Format: InventoryFormatS3}).Validate())
+	// finding an inventory at the root would list the whole bucket
+	require.ErrorContains(t, (&InventoryConfig{Path: "tempo.csv", Format: InventoryFormatS3, MaxAge: time.Hour}).Validate(), "must be in a directory")
+	require.Error(t, (&InventoryConfig{Path: "inventory/", Format: InventoryFormatGCS, MaxAge: time.Hour}).Validate())
+}
+
+func TestInventoryReaderManifest(t *testing.T) {
+	dir := t.TempDir()
+	r, w, _, err := local.New(&local.Config{Path: dir})
+	require.NoError(t, err)
+
+	cfg := InventoryConfig{
+		Path:   "inventory/bucket/tempo/",
+		Format: InventoryFormatS3,
+		MaxAge: time.Hour,
+	}
+	require.NoError(t, cfg.Validate())
+
+	ctx := context.Background()
+	write := func(name string, keypath backend.KeyPath, data []byte) {
+		require.NoError(t, w.Write(ctx, name, keypath, bytes.NewReader(data), int64(len(data)), nil))
+	}
+	manifest := func(created time.Time, file string) []byte {
+		return []byte(`{"sourceBucket":"bucket","fileFormat":"CSV","fileSchema":"Bucket, Key, Size","creationTimestamp":"` +
+			strconv.FormatInt(created.UnixMilli(), 10) + `","files":[{"key":"inventory/bucket/tempo/data/` + file + `"}]}`)
+	}
+
+	// the data files are found relative to the root of the inventory
+	write("old.csv", backend.KeyPath{"inventory", "bucket", "tempo", "data"}, []byte(`"bucket","single-tenant/00000000-0000-0000-0000-000000000002/meta.json","1"`+"\n"))
+	write("new.csv", backend.KeyPath{"inventory", "bucket", "tempo", "data"}, []byte(`"bucket","single-tenant/00000000-0000-0000-0000-000000000001/meta.json","1"`+"\n"))
+	write(InventoryManifestName, backend.KeyPath{"inventory", "bucket", "tempo", "2026-01-01T00-00Z"}, manifest(time.Now(), "old.csv"))
+	old := time.Now().Add(-time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "inventory", "bucket", "tempo", "2026-01-01T00-00Z", InventoryManifestName), old, old))
+	write(InventoryManifestName, backend.KeyPath{"inventory", "bucket", "tempo", "2026-01-02T00-00Z"}, manifest(time.Now(), "new.csv"))
+
+	// the latest manifest is used
+	inventory := NewInventoryReader(cfg, r)
+	inv, err := inventory.Inventory(ctx)
+	require.NoError(t, err)
+	blockIDs, _, ok := inv.Blocks("single-tenant")
+	require.True(t, ok)
+	require.Equal(t, []uuid.UUID{inventoryBlockID}, blockIDs)
+
+	// the age of a manifest is its creation time
+	write(InventoryManifestName, backend.KeyPath{"inventory", "bucket", "tempo", "2026-01-03T00-00Z"}, manifest(time.Now().Add(-2*time.Hour), "new.csv"))
+	_, err = inventory.Inventory(ctx)
+	require.ErrorContains(t, err, "exceeds the max age")
+}
+
+type staticInventory struct {
+	inv *Inventory
+}
+
+func (s *staticInventory) Inventory(context.Context) (*Inventory, error) {
+	return s.inv, nil
+}
+
+func TestPollBootstrapFromInventory(t *testing.T) {
+	listed := 0
+	r := &backend.MockReader{
+		BlocksFn: func(context.Context, string) ([]uuid.UUID, []uuid.UUID, error) {
+			listed++
+			return []uuid.UUID{inventoryBlockID}, nil, nil
+		},
+		BlockMetaFn: func(_ context.Context, blockID uuid.UUID, tenantID string) (*backend.BlockMeta, error) {
+			return &backend.BlockMeta{BlockID: backend.UUID(blockID), TenantID: tenantID}, nil
+		},
+	}
+
+	p := NewPoller(&PollerConfig{PollConcurrency: testPollConcurrency}, &mockJobSharder{}, r, &backend.MockCompactor{}, &backend.MockWriter{}, log.NewNopLogger())
+	p.SetInventory(&staticInventory{inv: &Inventory{
+		CreatedAt: time.Now(),
+		tenants: map[string]*inventoryTenant{
+			"bootstrapped": {blockIDs: []uuid.UUID{inventoryBlockID}},
+		},
+	}})
+	p.loadInventory(context.Background())
+
+	// a tenant in the inventory is bootstrapped without listing
+	metas, _, err := p.pollTenantBlocks(context.Background(), "bootstrapped", New())
+	require.NoError(t, err)
+	require.Len(t, metas, 1)
+	require.Equal(t, 0, listed)
+
+	// but only once
+	_, _, err = p.pollTenantBlocks(context.Background(), "bootstrapped", New())
+	require.NoError(t, err)
+	require.Equal(t, 1, listed)
+
+	// tenants that are not in the inventory are listed
+	_, _, err = p.pollTenantBlocks(context.Background(), "unknown", New())
+	require.NoError(t, err)
+	require.Equal(t, 2, listed)
+}
+
+func TestPollCrossCheckInventory(t *testing.T) {
+	r := &backend.MockReader{
+		BlocksFn: func(context.Context, string) ([]uuid.UUID, []uuid.UUID, error) {
+			return []uuid.UUID{inventoryBlockID, inventoryCompactedBlockID}, nil, nil
+		},
+		BlockMetaFn: func(_ context.Context, blockID uuid.UUID, tenantID string) (*backend.BlockMeta, error) {
+			return &backend.BlockMeta{BlockID: backend.UUID(blockID), TenantID: tenantID}, nil
+		},
+	}
+
+	p := NewPoller(&PollerConfig{PollConcurrency: testPollConcurrency}, &mockJobSharder{}, r, &backend.MockCompactor{}, &backend.MockWriter{}, log.NewNopLogger())
+	p.SetInventory(&staticInventory{inv: &Inventory{
+		CreatedAt: time.Now(),
+		tenants: map[string]*inventoryTenant{
+			"listed":       {blockIDs: []uuid.UUID{inventoryBlockID, uuid.MustParse("00000000-0000-0000-0000-000000000003")}},
+			"bootstrapped": {blockIDs: []uuid.UUID{inventoryBlockID}},
+		},
+	}})
+	p.loadInventory(context.Background())
+
+	// the listing of a known tenant is compared with the inventory
+	previous := New()
+	previous.metas["listed"] = []*backend.BlockMeta{{BlockID: backend.UUID(inventoryBlockID), TenantID: "listed"}}
+	_, _, err := p.pollTenantBlocks(context.Background(), "listed", previous)
+	require.NoError(t, err)
+	require.Equal(t, 1.0, testutil.ToFloat64(metricInventoryDriftBlocks.WithLabelValues("listed", "inventory")))
+	require.Equal(t, 1.0, testutil.ToFloat64(metricInventoryDriftBlocks.WithLabelValues("listed", "backend")))
+	require.NotNil(t, p.inventory)
+
+	// an inventory that contradicts the backend isn't used to bootstrap tenants
+	p.inventory.tenants["listed"].compactedBlockIDs = []uuid.UUID{inventoryCompactedBlockID}
+	_, _, err = p.pollTenantBlocks(context.Background(), "listed", previous)
+	require.NoError(t, err)
+	require.Nil(t, p.inventory)
+
+	_, _, bootstrapped := p.bootstrapFromInventory("bootstrapped", New())
+	require.False(t, bootstrapped)
+}
@@ -1,12 +1,21 @@
 package blocklist
 
 import (
+	"errors"
+	"fmt"
 	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/grafana/tempo/tempodb/backend"
 )
 
+// ErrPageTokenExpired is returned by MetasPaged when the blocklist changed since the page token was issued.
+// Paging has to restart from the first page.
+var ErrPageTokenExpired = errors.New("page token expired")
+
 // PerTenant is a map of tenant ids to backend.BlockMetas
 type PerTenant map[string][]*backend.BlockMeta
 
@@ -24,6 +33,14 @@ type List struct {
 	removed          PerTenant
 	compactedAdded   PerTenantCompacted
 	compactedRemoved PerTenantCompacted
+	replaced         PerTenant
+
+	// time indexes of the metas of the tenants, built on the first MetasInRange after a change
+	timeIndexes map[string]*timeIndex
+
+	// generation is incremented on every change to metas. page tokens are only valid for the generation
+	// they were issued in.
+	generation uint64
 }
 
 func New() *List {
@@ -35,6 +52,9 @@ func New() *List {
 		removed:          make(PerTenant),
 		compactedAdded:   make(PerTenantCompacted),
 		compactedRemoved: make(PerTenantCompacted),
+		replaced:         make(PerTenant),
+
+		timeIndexes: make(map[string]*timeIndex),
 	}
 }
 
@@ -64,6 +84,119 @@ func (l *List) Metas(tenantID string) []*backend.BlockMeta {
 	return copiedBlocklist
 }
 
+// MetasInRange returns the metas of the tenant that overlap the time range, in blocklist order. Only the matching
+// metas are visited and copied using a time index of the tenant, which avoids scanning the entire blocklist of large
+// tenants for queries over a short time range.
+func (l *List) MetasInRange(tenantID string, start, end time.Time) []*backend.BlockMeta {
+	if tenantID == "" {
+		return nil
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	tenantMetas := l.metas[tenantID]
+	if len(tenantMetas) == 0 {
+		return nil
+	}
+
+	idx, ok := l.timeIndexes[tenantID]
+	if !ok {
+		idx = newTimeIndex(tenantMetas)
+		l.timeIndexes[tenantID] = idx
+	}
+
+	var metas []*backend.BlockMeta
+	for _, p := range idx.overlapping(start, end) {
+		metas = append(metas, tenantMetas[p])
+	}
+	return metas
+}
+
+// CompactedMetasInRange returns the compacted metas of the tenant that overlap the time range.
+func (l *List) CompactedMetasInRange(tenantID string, start, end time.Time) []*backend.CompactedBlockMeta {
+	if tenantID == "" {
+		return nil
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	var metas []*backend.CompactedBlockMeta
+	for _, m := range l.compactedMetas[tenantID] {
+		if overlaps(&m.BlockMeta, start, end) {
+			metas = append(metas, m)
+		}
+	}
+	return metas
+}
+
+// MetasPaged returns up to pageSize metas of the tenant starting at the page token and the token of the next
+// page. An empty page token requests the first page and an empty next token means there are no more pages.
+// ErrPageTokenExpired is returned if the blocklist was updated since the token was issued.
+func (l *List) MetasPaged(tenantID string, pageSize int, pageToken string) ([]*backend.BlockMeta, string, error) {
+	if tenantID == "" {
+		return nil, "", nil
+	}
+	if pageSize <= 0 {
+		return nil, "", fmt.Errorf("page size must be greater than 0. received %d", pageSize)
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	offset := 0
+	if pageToken != "" {
+		generation, o, err := parsePageToken(pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		if generation != l.generation {
+			return nil, "", ErrPageTokenExpired
+		}
+		offset = o
+	}
+
+	metas := l.metas[tenantID]
+	if offset >= len(metas) {
+		return nil, "", nil
+	}
+
+	end := min(offset+pageSize, len(metas))
+	page := make([]*backend.BlockMeta, 0, end-offset)
+	page = append(page, metas[offset:end]...)
+
+	var nextToken string
+	if end < len(metas) {
+		nextToken = strconv.FormatUint(l.generation, 10) + ":" + strconv.Itoa(end)
+	}
+
+	return page, nextToken, nil
+}
+
+func parsePageToken(token string) (uint64, int, error) {
+	g, o, ok := strings.Cut(token, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid page token %q", token)
+	}
+
+	generation, err := strconv.ParseUint(g, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid page token %q: %w", token, err)
+	}
+	offset, err := strconv.Atoi(o)
+	if err != nil || offset < 0 {
+		return 0, 0, fmt.Errorf("invalid page token %q", token)
+	}
+
+	return generation, offset, nil
+}
+
+// overlaps returns true if the block start is before or equal to end and the block end is after or equal to start
+func overlaps(m *backend.BlockMeta, start, end time.Time) bool {
+	return !m.StartTime.After(end) && !m.EndTime.Before(start)
+}
+
 func (l *List) CompactedMetas(tenantID string) []*backend.CompactedBlockMeta {
 	if tenantID == "" {
 		return nil
@@ -87,16 +220,57 @@ func (l *List) ApplyPollResults(m PerTenant, c PerTenantCompacted) {
 
 	l.metas = m
 	l.compactedMetas = c
+	l.generation++
+	clear(l.timeIndexes)
 
 	// now reapply all updates and clear
 	for tenantID := range l.added {
 		l.updateInternal(tenantID, l.added[tenantID], l.removed[tenantID], l.compactedAdded[tenantID], l.compactedRemoved[tenantID])
 	}
+	for tenantID, metas := range l.replaced {
+		l.replaceInternal(tenantID, metas)
+	}
 
 	clear(l.added)
 	clear(l.removed)
 	clear(l.compactedAdded)
 	clear(l.compactedRemoved)
+	clear(l.replaced)
+}
+
+// Replace replaces the metas of the tenant with the same block ids as the passed metas. Metas of blocks that aren't
+// in the blocklist are ignored. Like Update, the replacements are kept until the next polling cycle.
+func (l *List) Replace(tenantID string, metas []*backend.BlockMeta) {
+	if tenantID == "" || len(metas) == 0 {
+		return
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.replaceInternal(tenantID, metas)
+	l.generation++
+
+	l.replaced[tenantID] = append(l.replaced[tenantID], metas...)
+}
+
+// replaceInternal must be called under lock
+func (l *List) replaceInternal(tenantID string, metas []*backend.BlockMeta) {
+	existing := l.metas[tenantID]
+	final := make([]*backend.BlockMeta, 0, len(existing))
+	for _, b := range existing {
+		// the last replacement of a block wins
+		for i := len(metas) - 1; i >= 0; i-- {
+			if metas[i].BlockID == b.BlockID {
+				b = metas[i]
+				break
+			}
+		}
+		final = append(final, b)
+	}
+
+	l.metas[tenantID] = final
+	delete(l.timeIndexes, tenantID)
 }
 
 // Update Adds and removes regular or compacted blocks from the in-memory blocklist.
@@ -110,6 +284,7 @@ func (l *List) Update(tenantID string, add []*backend.BlockMeta, remove []*backe
 	defer l.mtx.Unlock()
 
 	l.updateInternal(tenantID, add, remove, compactedAdd, compactedRemove)
+	l.generation++
 
 	// We have updated the current blocklist, but we may be in the middle of a
 	// polling cycle.  When the Apply is called above, we will have lost the
@@ -164,6 +339,7 @@ func (l *List) updateInternal(tenantID string, add []*backend.BlockMeta, remove
 		}
 
 		l.metas[tenantID] = final
+		delete(l.timeIndexes, tenantID)
 	}
 
 	// ******** Compacted blocks ********
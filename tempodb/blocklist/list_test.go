@@ -1,8 +1,10 @@
 package blocklist
 
 import (
+	"fmt"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -493,6 +495,140 @@ func TestUpdatesSaved(t *testing.T) {
 	}
 }
 
+func TestReplace(t *testing.T) {
+	one := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	two := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+
+	l := New()
+	l.ApplyPollResults(PerTenant{
+		"test": {
+			{BlockID: backend.UUID(one)},
+			{BlockID: backend.UUID(two)},
+		},
+	}, PerTenantCompacted{})
+
+	replaced := &backend.BlockMeta{BlockID: backend.UUID(one), EncryptionKeyVersion: 1}
+	l.Replace("test", []*backend.BlockMeta{
+		replaced,
+		{BlockID: backend.UUID(uuid.MustParse("00000000-0000-0000-0000-000000000003"))},
+	})
+	require.Equal(t, []*backend.BlockMeta{replaced, {BlockID: backend.UUID(two)}}, l.Metas("test"))
+
+	// the replacement survives a poll that started before it
+	l.ApplyPollResults(PerTenant{
+		"test": {
+			{BlockID: backend.UUID(one)},
+			{BlockID: backend.UUID(two)},
+		},
+	}, PerTenantCompacted{})
+	require.Equal(t, []*backend.BlockMeta{replaced, {BlockID: backend.UUID(two)}}, l.Metas("test"))
+
+	// and is then dropped
+	l.ApplyPollResults(PerTenant{
+		"test": {
+			{BlockID: backend.UUID(one)},
+		},
+	}, PerTenantCompacted{})
+	require.Equal(t, []*backend.BlockMeta{{BlockID: backend.UUID(one)}}, l.Metas("test"))
+}
+
+func TestMetasInRange(t *testing.T) {
+	block := func(id string, start, end int64) *backend.BlockMeta {
+		m := meta(id)
+		m.StartTime = time.Unix(start, 0)
+		m.EndTime = time.Unix(end, 0)
+		return m
+	}
+
+	var (
+		early   = block("00000000-0000-0000-0000-000000000001", 0, 10)
+		middle  = block("00000000-0000-0000-0000-000000000002", 10, 20)
+		late    = block("00000000-0000-0000-0000-000000000003", 20, 30)
+		spanned = block("00000000-0000-0000-0000-000000000004", 0, 30)
+	)
+
+	l := New()
+	l.ApplyPollResults(PerTenant{testTenantID: {early, middle, late, spanned}}, PerTenantCompacted{
+		testTenantID: {{BlockMeta: *block("00000000-0000-0000-0000-000000000005", 10, 20)}},
+	})
+
+	tests := []struct {
+		name       string
+		start, end int64
+		expected   []*backend.BlockMeta
+	}{
+		{name: "all", start: 0, end: 30, expected: []*backend.BlockMeta{early, middle, late, spanned}},
+		{name: "inclusive bounds", start: 10, end: 10, expected: []*backend.BlockMeta{early, middle, spanned}},
+		{name: "within a block", start: 22, end: 25, expected: []*backend.BlockMeta{late, spanned}},
+		{name: "outside", start: 31, end: 40},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := l.MetasInRange(testTenantID, time.Unix(tc.start, 0), time.Unix(tc.end, 0))
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+
+	require.Len(t, l.CompactedMetasInRange(testTenantID, time.Unix(15, 0), time.Unix(16, 0)), 1)
+	require.Empty(t, l.CompactedMetasInRange(testTenantID, time.Unix(21, 0), time.Unix(30, 0)))
+	require.Empty(t, l.MetasInRange("unknown", time.Unix(0, 0), time.Unix(30, 0)))
+}
+
+func TestMetasPaged(t *testing.T) {
+	metas := make([]*backend.BlockMeta, 0, 5)
+	for i := 1; i <= 5; i++ {
+		metas = append(metas, meta(fmt.Sprintf("00000000-0000-0000-0000-00000000000%d", i)))
+	}
+
+	l := New()
+	l.ApplyPollResults(PerTenant{testTenantID: metas}, PerTenantCompacted{})
+
+	// page through all metas
+	var (
+		all   []*backend.BlockMeta
+		token string
+		pages int
+	)
+	for {
+		page, next, err := l.MetasPaged(testTenantID, 2, token)
+		require.NoError(t, err)
+		all = append(all, page...)
+		pages++
+
+		if next == "" {
+			break
+		}
+		token = next
+	}
+	require.Equal(t, metas, all)
+	require.Equal(t, 3, pages)
+
+	// a page size larger than the blocklist returns everything in one page
+	page, next, err := l.MetasPaged(testTenantID, 10, "")
+	require.NoError(t, err)
+	require.Equal(t, metas, page)
+	require.Empty(t, next)
+
+	// tokens expire when the blocklist changes
+	_, next, err = l.MetasPaged(testTenantID, 2, "")
+	require.NoError(t, err)
+	l.Update(testTenantID, []*backend.BlockMeta{meta("00000000-0000-0000-0000-000000000006")}, nil, nil, nil)
+	_, _, err = l.MetasPaged(testTenantID, 2, next)
+	require.ErrorIs(t, err, ErrPageTokenExpired)
+
+	// invalid requests
+	_, _, err = l.MetasPaged(testTenantID, 0, "")
+	require.Error(t, err)
+	_, _, err = l.MetasPaged(testTenantID, 2, "garbage")
+	require.Error(t, err)
+
+	page, next, err = l.MetasPaged("unknown", 2, "")
+	require.NoError(t, err)
+	require.Empty(t, page)
+	require.Empty(t, next)
+}
+
 func BenchmarkUpdate(b *testing.B) {
 	var (
 		l         = New()
@@ -0,0 +1,103 @@
+package blocklist
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/log/level"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// JobOwner is implemented by job sharders that can tell which instance owns a job.
+type JobOwner interface {
+	// Owner returns the instance that owns a job, identified by a string.
+	Owner(job string) (string, error)
+}
+
+// TenantIndexOwnership is what a poller knows about the builders of the index of a tenant.
+type TenantIndexOwnership struct {
+	Tenant string `json:"tenant"`
+	// Owners are the instances that own the builder jobs of the tenant. It's empty if the sharder can't tell.
+	Owners []string `json:"owners,omitempty"`
+	// Builder is true if this poller built the index at the last poll. Takeover is true if it built it without
+	// owning the tenant because the heartbeat of the owner is too old.
+	Builder  bool `json:"builder"`
+	Takeover bool `json:"takeover"`
+	// LastPoll is the time of the last poll of the tenant, LastBuilt the last time this poller wrote its index.
+	LastPoll       time.Time `json:"last_poll"`
+	LastBuilt      time.Time `json:"last_built,omitempty"`
+	IndexCreatedAt time.Time `json:"index_created_at,omitempty"`
+	// Heartbeat is the last tenant index heartbeat read by this poller.
+	Heartbeat *backend.TenantIndexHeartbeat `json:"heartbeat,omitempty"`
+	// Conflicts counts the heartbeats of other pollers found while this poller owns the tenant, and the takeovers
+	// lost to another poller.
+	Conflicts int `json:"conflicts"`
+}
+
+// TenantIndexOwnership returns the ownership of the index of all tenants seen at the last poll, ordered by tenant.
+func (p *Poller) TenantIndexOwnership() []TenantIndexOwnership {
+	p.ownershipMtx.Lock()
+	out := make([]TenantIndexOwnership, 0, len(p.ownership))
+	for _, o := range p.ownership {
+		out = append(out, *o)
+	}
+	p.ownershipMtx.Unlock()
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Tenant < out[j].Tenant })
+
+	if jo, ok := p.sharder.(JobOwner); ok {
+		for i := range out {
+			out[i].Owners = p.tenantIndexOwners(jo, out[i].Tenant)
+		}
+	}
+
+	return out
+}
+
+// tenantIndexOwners returns the distinct instances that own the builder jobs of a tenant.
+func (p *Poller) tenantIndexOwners(jo JobOwner, tenant string) []string {
+	var owners []string
+	for i := 0; i < p.cfg.TenantIndexBuilders; i++ {
+		owner, err := jo.Owner(jobPrefix + strconv.Itoa(i) + "-" + tenant)
+		if err != nil {
+			level.Error(p.logger).Log("msg", "failed to get tenant index builder owner", "tenant", tenant, "err", err)
+			continue
+		}
+		if owner != "" && !slices.Contains(owners, owner) {
+			owners = append(owners, owner)
+		}
+	}
+	return owners
+}
+
+func (p *Poller) updateOwnership(tenantID string, fn func(o *TenantIndexOwnership)) {
+	p.ownershipMtx.Lock()
+	defer p.ownershipMtx.Unlock()
+
+	o, ok := p.ownership[tenantID]
+	if !ok {
+		o = &TenantIndexOwnership{Tenant: tenantID}
+		p.ownership[tenantID] = o
+	}
+	fn(o)
+}
+
+// pruneOwnership forgets the ownership of the tenants that are gone.
+func (p *Poller) pruneOwnership(tenants []string) {
+	polled := make(map[string]struct{}, len(tenants))
+	for _, tenantID := range tenants {
+		polled[tenantID] = struct{}{}
+	}
+
+	p.ownershipMtx.Lock()
+	defer p.ownershipMtx.Unlock()
+
+	for tenantID := range p.ownership {
+		if _, ok := polled[tenantID]; !ok {
+			delete(p.ownership, tenantID)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 	"path"
+	"slices"
 	"strconv"
 	"sync"
 	"time"
@@ -20,6 +21,7 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
+	"golang.org/x/time/rate"
 
 	"github.com/grafana/tempo/pkg/boundedwaitgroup"
 	"github.com/grafana/tempo/tempodb/backend"
@@ -62,6 +64,36 @@ var (
 		Name:      "blocklist_length",
 		Help:      "Total number of blocks per tenant.",
 	}, []string{"tenant"})
+	metricBlocklistLevelBlocks = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_compaction_level_blocks",
+		Help:      "Total number of blocks per tenant and compaction level.",
+	}, []string{"tenant", "level"})
+	metricBlocklistLevelBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_compaction_level_bytes",
+		Help:      "Total number of bytes in blocks per tenant and compaction level.",
+	}, []string{"tenant", "level"})
+	metricBlocklistVersionBlocks = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_version_blocks",
+		Help:      "Total number of blocks per tenant and block version.",
+	}, []string{"tenant", "version"})
+	metricTenantIndexVerifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_tenant_index_verifications_total",
+		Help:      "Total number of tenant index verifications by result.",
+	}, []string{"result"})
+	metricTenantIndexMissingBlocks = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_tenant_index_missing_blocks",
+		Help:      "Number of blocks found in the backend listing but missing from the tenant index at the last verification.",
+	}, []string{"tenant"})
+	metricTenantIndexExtraBlocks = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_tenant_index_extra_blocks",
+		Help:      "Number of blocks in the tenant index that were not found in the backend listing at the last verification.",
+	}, []string{"tenant"})
 	metricTenantIndexErrors = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "tempodb",
 		Name:      "blocklist_tenant_index_errors_total",
@@ -72,13 +104,118 @@ var (
 		Name:      "blocklist_tenant_index_builder",
 		Help:      "A value of 1 indicates this instance of tempodb is building the tenant index.",
 	}, []string{"tenant"})
+	metricTenantIndexTakeovers = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_tenant_index_builder_takeovers_total",
+		Help:      "Total number of times this instance of tempodb took over building a tenant index from a builder whose heartbeat was stale.",
+	}, []string{"tenant"})
+	metricTenantIndexReplicaErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_tenant_index_replica_errors_total",
+		Help:      "Total number of times an error occurred while writing a tenant index to the replica backend.",
+	}, []string{"tenant"})
 	metricTenantIndexAgeSeconds = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "tempodb",
 		Name:      "blocklist_tenant_index_age_seconds",
 		Help:      "Age in seconds of the last pulled tenant index.",
 	}, []string{"tenant"})
+	metricTenantIndexesOverAgeTarget = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_tenant_indexes_over_age_target",
+		Help:      "Number of tenants whose tenant index is older than the tenant index age target after the last poll.",
+	})
+	metricTenantDeleted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "tenant_deleted_total",
+		Help:      "Total number of empty tenants whose remaining objects were deleted.",
+	})
+	metricTenantDeletedBytes = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "tenant_deleted_bytes_total",
+		Help:      "Total number of bytes reclaimed by deleting the remaining objects of empty tenants.",
+	})
+	metricTenantDeletionsDeferred = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "tenant_deletions_deferred_total",
+		Help:      "Total number of empty tenant deletions deferred to a later poll by the deletion budgets.",
+	}, []string{"reason"})
+	metricBackendCallsSlow = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_poll_backend_calls_slow_total",
+		Help:      "Total number of backend calls made while polling that exceeded the configured slow threshold.",
+	}, []string{"operation"})
+	metricBackendCallsTimedOut = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_poll_backend_calls_timed_out_total",
+		Help:      "Total number of backend calls made while polling that were cancelled by the per-call deadline.",
+	}, []string{"operation"})
+	metricBackendCallsWithoutDeadline = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_poll_backend_calls_without_deadline_total",
+		Help:      "Total number of backend calls made while polling whose context carried no deadline. Only recorded in deadline audit mode.",
+	}, []string{"operation"})
+	metricRateLimitedSeconds = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_poll_rate_limited_seconds_total",
+		Help:      "Total time in seconds backend calls made while polling waited on the poll rate limits.",
+	}, []string{"limit"})
+	metricInventoryAgeSeconds = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_inventory_age_seconds",
+		Help:      "Age in seconds of the last loaded bucket inventory.",
+	})
+	metricInventoryErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_inventory_errors_total",
+		Help:      "Total number of times the bucket inventory could not be loaded.",
+	})
+	metricInventoryBootstrappedTenants = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_inventory_bootstrapped_tenants_total",
+		Help:      "Total number of tenants whose blocklist was bootstrapped from the bucket inventory instead of a listing.",
+	})
+	metricInventoryDriftBlocks = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "blocklist_inventory_drift_blocks",
+		Help:      "Number of blocks of the last listing of a tenant that are missing from the bucket inventory, or the other way around.",
+	}, []string{"tenant", "missing_from"})
+)
+
+const (
+	rateLimitRequests = "requests"
+	rateLimitBytes    = "bytes"
+)
+
+// Names of the backend operations performed in the poll path. Used as metric labels.
+const (
+	opTenants            = "tenants"
+	opTenantIndex        = "tenant_index"
+	opWriteTenantIndex   = "write_tenant_index"
+	opWriteReplicaIndex  = "write_tenant_index_replica"
+	opBlocks             = "blocks"
+	opBlockMeta          = "block_meta"
+	opCompactedBlockMeta = "compacted_block_meta"
+	opHasNoCompactFlag   = "has_nocompact_flag"
+	opFind               = "find"
+	opDelete             = "delete"
+	opInventory          = "inventory"
+	opHeartbeat          = "tenant_index_heartbeat"
+	opWriteHeartbeat     = "write_tenant_index_heartbeat"
 )
 
+// readOps are the operations that list or read from the backend. Only these are subject to the poll rate limits.
+var readOps = map[string]struct{}{
+	opTenants:            {},
+	opTenantIndex:        {},
+	opBlocks:             {},
+	opBlockMeta:          {},
+	opCompactedBlockMeta: {},
+	opHasNoCompactFlag:   {},
+	opFind:               {},
+	opInventory:          {},
+	opHeartbeat:          {},
+}
+
 // Config is used to configure the poller
 type PollerConfig struct {
 	PollConcurrency            uint
@@ -91,7 +228,47 @@ type PollerConfig struct {
 	TolerateTenantFailures     int
 	EmptyTenantDeletionAge     time.Duration
 	EmptyTenantDeletionEnabled bool
-	SkipNoCompactBlocks        bool
+	// EmptyTenantDeletionConcurrency is the number of objects of an empty tenant deleted in parallel. The tempodb
+	// config defaults it to DefaultEmptyTenantDeletionConcurrency, a poller created with 0 deletes them sequentially.
+	EmptyTenantDeletionConcurrency uint
+	// EmptyTenantDeletionMaxObjects is the maximum number of objects of an empty tenant deleted per poll. The
+	// remaining objects are deleted on the following polls. 0 disables it.
+	EmptyTenantDeletionMaxObjects int
+	// EmptyTenantDeletionMaxTenantsPerPoll is the maximum number of empty tenants whose objects are deleted per
+	// poll. Other empty tenants are deleted on the following polls. 0 disables it.
+	EmptyTenantDeletionMaxTenantsPerPoll int
+	SkipNoCompactBlocks                  bool
+
+	// BackendCallTimeout is a hard deadline applied to every individual backend call made
+	// while polling. 0 disables it.
+	BackendCallTimeout time.Duration
+	// BackendCallSlowThreshold is a soft threshold. Backend calls taking longer than this
+	// are counted and logged, but not cancelled. 0 disables it.
+	BackendCallSlowThreshold time.Duration
+	// BackendCallDeadlineAudit verifies that every backend call made while polling carries
+	// a deadline and counts the ones that do not.
+	BackendCallDeadlineAudit bool
+	// IndexVerificationTenants is the number of randomly chosen tenants per poll cycle whose pulled
+	// tenant index is compared against a listing of the backend. 0 disables verification.
+	IndexVerificationTenants int
+	// RequestsPerSecond limits the rate of backend list and read calls made while polling. 0 disables it.
+	RequestsPerSecond float64
+	// BytesPerSecond limits the rate of bytes read from the backend while polling. Bytes are accounted
+	// for after each read so a single large tenant index can briefly exceed it. 0 disables it.
+	BytesPerSecond int
+	// TenantIndexBuilderTimeout is the age of the heartbeat of a tenant index builder after which another
+	// poller takes over building the index of the tenant. 0 disables it.
+	TenantIndexBuilderTimeout time.Duration
+	// TenantAllowList are glob patterns of the tenants that are polled, all tenants are polled if it's empty.
+	// TenantDenyList are glob patterns of the tenants that are never polled, it takes precedence over the allow
+	// list. Tenants that aren't polled are missing from the blocklist, so they are skipped by the components
+	// using it.
+	TenantAllowList []string
+	TenantDenyList  []string
+	// TenantIndexAgeTarget is the target max age of the tenant indexes. Tenants are polled oldest index first, so
+	// with a limited tenant poll concurrency the tenants closest to exceed the target are polled before the others.
+	// 0 polls the tenants in listing order.
+	TenantIndexAgeTarget time.Duration
 }
 
 // JobSharder is used to determine if a particular job is owned by this process
@@ -111,6 +288,26 @@ func (ownsNothingSharder) Owns(_ string) bool {
 
 const jobPrefix = "build-tenant-index-"
 
+const (
+	deletionDeferredMaxObjects = "max_objects"
+	deletionDeferredMaxTenants = "max_tenants_per_poll"
+)
+
+const (
+	verificationResultOK    = "ok"
+	verificationResultDrift = "drift"
+	verificationResultError = "error"
+)
+
+// TenantLifecycleListener is notified when the poller deletes the remaining objects of a tenant that no
+// longer has any blocks. It allows other subsystems to drop the per-tenant state they hold.
+type TenantLifecycleListener interface {
+	// BeforeTenantDeletion is called once the tenant has been confirmed empty, before any object is deleted.
+	BeforeTenantDeletion(ctx context.Context, tenantID string)
+	// AfterTenantDeletion is called after all remaining objects of the tenant have been deleted.
+	AfterTenantDeletion(ctx context.Context, tenantID string, deletedObjects int, deletedBytes int64)
+}
+
 // Poller retrieves the blocklist
 type Poller struct {
 	reader    backend.Reader
@@ -119,13 +316,50 @@ type Poller struct {
 
 	cfg *PollerConfig
 
-	sharder JobSharder
-	logger  log.Logger
+	sharder   JobSharder
+	logger    log.Logger
+	listeners []TenantLifecycleListener
+
+	requestLimiter *rate.Limiter
+	bytesLimiter   *rate.Limiter
+
+	inventorySource InventorySource
+	inventoryMtx    sync.Mutex
+	inventory       *Inventory
+	bootstrapped    map[string]struct{}
+
+	replica backend.Writer
+
+	sources backend.BlockSourcer
+
+	heartbeats   backend.VersionedReaderWriter
+	builderID    string
+	takeoversMtx sync.Mutex
+	takeovers    map[string]backend.Version
+
+	ownershipMtx sync.Mutex
+	ownership    map[string]*TenantIndexOwnership
+
+	// failingSince is the time of the first failed poll of the tenants whose last poll failed. Their previous
+	// blocklist is kept, so it's stale.
+	failingMtx   sync.Mutex
+	failingSince map[string]time.Time
+
+	// deletions is the number of tenant deletions started in the current poll. deletionProgress holds the
+	// objects and bytes already deleted of the tenants whose deletion spans several polls.
+	deletionsMtx     sync.Mutex
+	deletions        int
+	deletionProgress map[string]tenantDeletionProgress
+}
+
+type tenantDeletionProgress struct {
+	objects int
+	bytes   int64
 }
 
 // NewPoller creates the Poller
 func NewPoller(cfg *PollerConfig, sharder JobSharder, reader backend.Reader, compactor backend.Compactor, writer backend.Writer, logger log.Logger) *Poller {
-	return &Poller{
+	p := &Poller{
 		reader:    reader,
 		compactor: compactor,
 		writer:    writer,
@@ -133,7 +367,54 @@ func NewPoller(cfg *PollerConfig, sharder JobSharder, reader backend.Reader, com
 		cfg:     cfg,
 		sharder: sharder,
 		logger:  logger,
+
+		bootstrapped:     map[string]struct{}{},
+		takeovers:        map[string]backend.Version{},
+		ownership:        map[string]*TenantIndexOwnership{},
+		failingSince:     map[string]time.Time{},
+		deletionProgress: map[string]tenantDeletionProgress{},
+	}
+
+	if cfg.RequestsPerSecond > 0 {
+		p.requestLimiter = rate.NewLimiter(rate.Limit(cfg.RequestsPerSecond), max(1, int(cfg.RequestsPerSecond)))
 	}
+	if cfg.BytesPerSecond > 0 {
+		p.bytesLimiter = rate.NewLimiter(rate.Limit(cfg.BytesPerSecond), cfg.BytesPerSecond)
+	}
+
+	return p
+}
+
+// AddTenantLifecycleListener registers a listener for tenant deletions. It must be called before polling starts.
+func (p *Poller) AddTenantLifecycleListener(l TenantLifecycleListener) {
+	p.listeners = append(p.listeners, l)
+}
+
+// SetInventory sets the source of the inventory used to bootstrap the blocklist of tenants that are not in the
+// previous blocklist without listing the backend. It must be called before polling starts.
+func (p *Poller) SetInventory(s InventorySource) {
+	p.inventorySource = s
+}
+
+// SetTenantIndexReplica sets a second backend that tenant indexes are written to after they are written to the
+// backend, for example to let pollers in another region pull them without building them. It must be called before
+// polling starts.
+func (p *Poller) SetTenantIndexReplica(w backend.Writer) {
+	p.replica = w
+}
+
+// SetBlockSources sets the source of the buckets blocks are listed in when the backend federates several buckets.
+// The metas of polled blocks are tagged with the name of their bucket. It must be called before polling starts.
+func (p *Poller) SetBlockSources(s backend.BlockSourcer) {
+	p.sources = s
+}
+
+// SetTenantIndexHeartbeats enables the tenant index builder heartbeats. Builders write a heartbeat identified by
+// builderID with every tenant index, and the tenant indexes of builders whose heartbeat is older than
+// TenantIndexBuilderTimeout are taken over. It must be called before polling starts.
+func (p *Poller) SetTenantIndexHeartbeats(rw backend.VersionedReaderWriter, builderID string) {
+	p.heartbeats = rw
+	p.builderID = builderID
 }
 
 // Do does the doing of getting a blocklist
@@ -149,12 +430,26 @@ func (p *Poller) Do(parentCtx context.Context, previous *List) (PerTenant, PerTe
 	parentCtx, parentSpan := tracer.Start(parentCtx, "Poller.Do")
 	defer parentSpan.End()
 
-	tenants, err := p.reader.Tenants(parentCtx)
+	var tenants []string
+	err := p.backendCall(parentCtx, opTenants, "", func(ctx context.Context) error {
+		var err error
+		tenants, err = p.reader.Tenants(ctx)
+		return err
+	})
 	if err != nil {
 		metricBlocklistErrors.WithLabelValues("").Inc()
 		return nil, nil, err
 	}
 
+	tenants = p.pollableTenants(tenants)
+	tenants = p.prioritizeTenants(tenants)
+	verify := p.tenantsToVerify(tenants)
+	p.loadInventory(parentCtx)
+
+	p.deletionsMtx.Lock()
+	p.deletions = 0
+	p.deletionsMtx.Unlock()
+
 	var (
 		wg  = boundedwaitgroup.New(p.cfg.TenantPollConcurrency)
 		mtx = sync.Mutex{}
@@ -200,7 +495,7 @@ func (p *Poller) Do(parentCtx context.Context, previous *List) (PerTenant, PerTe
 			)
 
 			for consecutiveErrorsRemaining >= 0 {
-				newBlockList, newCompactedBlockList, err = p.pollTenantAndCreateIndex(bgCtx, tenantID, previous)
+				newBlockList, newCompactedBlockList, err = p.pollTenantAndCreateIndex(bgCtx, tenantID, previous, verify[tenantID])
 				if err == nil {
 					break
 				}
@@ -217,15 +512,19 @@ func (p *Poller) Do(parentCtx context.Context, previous *List) (PerTenant, PerTe
 				compactedBlocklist[tenantID] = previous.CompactedMetas(tenantID)
 
 				tenantFailuresRemaining.Dec()
+				p.tenantPolled(tenantID, false)
 
 				return
 			}
+			p.tenantPolled(tenantID, true)
 
 			if len(newBlockList) > 0 || len(newCompactedBlockList) > 0 {
 				blocklist[tenantID] = newBlockList
 				compactedBlocklist[tenantID] = newCompactedBlockList
 
 				metricBlocklistLength.WithLabelValues(tenantID).Set(float64(len(newBlockList)))
+				updateCompactionLevelMetrics(tenantID, newBlockList)
+				updateVersionMetrics(tenantID, newBlockList)
 
 				backendMetaMetrics := sumTotalBackendMetaMetrics(newBlockList, newCompactedBlockList)
 				metricBackendObjects.WithLabelValues(tenantID, blockStatusLiveLabel).Set(float64(backendMetaMetrics.blockMetaTotalObjects))
@@ -235,6 +534,12 @@ func (p *Poller) Do(parentCtx context.Context, previous *List) (PerTenant, PerTe
 				return
 			}
 			metricBlocklistLength.DeleteLabelValues(tenantID)
+			metricTenantIndexMissingBlocks.DeleteLabelValues(tenantID)
+			metricTenantIndexExtraBlocks.DeleteLabelValues(tenantID)
+			metricBlocklistLevelBlocks.DeletePartialMatch(prometheus.Labels{"tenant": tenantID})
+			metricBlocklistLevelBytes.DeletePartialMatch(prometheus.Labels{"tenant": tenantID})
+			metricBlocklistVersionBlocks.DeletePartialMatch(prometheus.Labels{"tenant": tenantID})
+			metricInventoryDriftBlocks.DeletePartialMatch(prometheus.Labels{"tenant": tenantID})
 			metricBackendObjects.DeleteLabelValues(tenantID)
 			metricBackendObjects.DeleteLabelValues(tenantID)
 			metricBackendBytes.DeleteLabelValues(tenantID)
@@ -242,11 +547,15 @@ func (p *Poller) Do(parentCtx context.Context, previous *List) (PerTenant, PerTe
 	}
 
 	wg.Wait()
+	p.updateIndexAgeTargetMetric(tenants)
 
 	if tenantFailuresRemaining.Load() < 0 {
 		return nil, nil, errors.New("too many tenant failures; abandoning polling cycle")
 	}
 
+	p.pruneOwnership(tenants)
+	p.pruneFailingTenants(tenants)
+
 	diff := time.Since(start).Seconds()
 	metricBlocklistPollDuration.Observe(diff)
 	level.Info(p.logger).Log("msg", "blocklist poll complete", "seconds", diff)
@@ -254,29 +563,86 @@ func (p *Poller) Do(parentCtx context.Context, previous *List) (PerTenant, PerTe
 	return blocklist, compactedBlocklist, nil
 }
 
+// TenantFailingSince returns the time of the first failed poll of the tenant if its last poll failed. The blocklist
+// of the tenant is then the one of its last successful poll.
+func (p *Poller) TenantFailingSince(tenantID string) (time.Time, bool) {
+	p.failingMtx.Lock()
+	defer p.failingMtx.Unlock()
+
+	since, ok := p.failingSince[tenantID]
+	return since, ok
+}
+
+func (p *Poller) tenantPolled(tenantID string, success bool) {
+	p.failingMtx.Lock()
+	defer p.failingMtx.Unlock()
+
+	if success {
+		delete(p.failingSince, tenantID)
+		return
+	}
+	if _, ok := p.failingSince[tenantID]; !ok {
+		p.failingSince[tenantID] = time.Now()
+	}
+}
+
+// pruneFailingTenants forgets the failures of the tenants that are gone.
+func (p *Poller) pruneFailingTenants(tenants []string) {
+	p.failingMtx.Lock()
+	defer p.failingMtx.Unlock()
+
+	for tenantID := range p.failingSince {
+		if !slices.Contains(tenants, tenantID) {
+			delete(p.failingSince, tenantID)
+		}
+	}
+}
+
 func (p *Poller) pollTenantAndCreateIndex(
 	ctx context.Context,
 	tenantID string,
 	previous *List,
+	verify bool,
 ) ([]*backend.BlockMeta, []*backend.CompactedBlockMeta, error) {
 	derivedCtx, span := tracer.Start(ctx, "Poller.pollTenantAndCreateIndex", trace.WithAttributes(attribute.String("tenant", tenantID)))
 	defer span.End()
 
-	// are we a tenant index builder?
-	builder := p.tenantIndexBuilder(tenantID)
+	// are we a tenant index builder? if not, has the builder stopped?
+	owner := p.tenantIndexBuilder(tenantID)
+	takeover := !owner && p.takeOverTenantIndex(derivedCtx, tenantID)
+	builder := owner || takeover
 	span.SetAttributes(attribute.Bool("tenant_index_builder", builder))
+	p.updateOwnership(tenantID, func(o *TenantIndexOwnership) {
+		o.Builder = builder
+		o.Takeover = takeover
+		o.LastPoll = time.Now()
+	})
 	if !builder {
 		metricTenantIndexBuilder.WithLabelValues(tenantID).Set(0)
 
-		i, err := p.reader.TenantIndex(derivedCtx, tenantID)
+		var i *backend.TenantIndex
+		err := p.backendCall(derivedCtx, opTenantIndex, tenantID, func(ctx context.Context) error {
+			var err error
+			i, err = p.reader.TenantIndex(ctx, tenantID)
+			return err
+		})
+		if err == nil {
+			err = p.readBytes(derivedCtx, i.Size())
+		}
 		err = p.tenantIndexPollError(i, err)
 		if err == nil {
 			// success! return the retrieved index
 			metricTenantIndexAgeSeconds.WithLabelValues(tenantID).Set(float64(time.Since(i.CreatedAt) / time.Second))
+			p.updateOwnership(tenantID, func(o *TenantIndexOwnership) { o.IndexCreatedAt = i.CreatedAt })
 			level.Info(p.logger).Log("msg", "successfully pulled tenant index", "tenant", tenantID, "createdAt", i.CreatedAt, "metas", len(i.Meta), "compactedMetas", len(i.CompactedMeta))
 
 			span.SetAttributes(attribute.Int("metas", len(i.Meta)))
 			span.SetAttributes(attribute.Int("compactedMetas", len(i.CompactedMeta)))
+
+			if verify {
+				p.verifyTenantIndex(derivedCtx, tenantID, i)
+			}
+
 			return i.Meta, i.CompactedMeta, nil
 		}
 
@@ -303,17 +669,43 @@ func (p *Poller) pollTenantAndCreateIndex(
 
 	// everything is happy, write this tenant index
 	level.Info(p.logger).Log("msg", "writing tenant index", "tenant", tenantID, "metas", len(blocklist), "compactedMetas", len(compactedBlocklist))
-	err = p.writer.WriteTenantIndex(ctx, tenantID, blocklist, compactedBlocklist)
+	err = p.backendCall(ctx, opWriteTenantIndex, tenantID, func(ctx context.Context) error {
+		return p.writer.WriteTenantIndex(ctx, tenantID, blocklist, compactedBlocklist)
+	})
 	if err != nil {
 		metricTenantIndexErrors.WithLabelValues(tenantID).Inc()
 		level.Error(p.logger).Log("msg", "failed to write tenant index", "tenant", tenantID, "err", err)
+	} else {
+		now := time.Now()
+		p.updateOwnership(tenantID, func(o *TenantIndexOwnership) {
+			o.LastBuilt = now
+			o.IndexCreatedAt = now
+		})
+	}
+
+	if p.replica != nil {
+		// an empty index deletes the index of the replica as well
+		err = p.backendCall(ctx, opWriteReplicaIndex, tenantID, func(ctx context.Context) error {
+			return p.replica.WriteTenantIndex(ctx, tenantID, blocklist, compactedBlocklist)
+		})
+		if err != nil {
+			metricTenantIndexReplicaErrors.WithLabelValues(tenantID).Inc()
+			level.Error(p.logger).Log("msg", "failed to write tenant index to replica", "tenant", tenantID, "err", err)
+		}
+	}
+
+	if owner || takeover {
+		p.writeTenantIndexHeartbeat(ctx, tenantID, owner, len(blocklist) == 0 && len(compactedBlocklist) == 0)
 	}
 
 	if len(blocklist) == 0 && len(compactedBlocklist) == 0 {
-		err := p.deleteTenant(ctx, tenantID)
+		deleted, err := p.deleteTenant(ctx, tenantID)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to delete tenant: %w", err)
 		}
+		if deleted {
+			return blocklist, compactedBlocklist, nil
+		}
 	}
 
 	metricTenantIndexAgeSeconds.WithLabelValues(tenantID).Set(0)
@@ -321,6 +713,167 @@ func (p *Poller) pollTenantAndCreateIndex(
 	return blocklist, compactedBlocklist, nil
 }
 
+// prioritizeTenants orders the tenants by the creation time of their index seen at the previous polls, tenants
+// without a known index first. It keeps the listing order if there's no tenant index age target.
+func (p *Poller) prioritizeTenants(tenants []string) []string {
+	if p.cfg.TenantIndexAgeTarget <= 0 {
+		return tenants
+	}
+
+	p.ownershipMtx.Lock()
+	createdAt := make(map[string]time.Time, len(tenants))
+	for _, tenantID := range tenants {
+		if o, ok := p.ownership[tenantID]; ok {
+			createdAt[tenantID] = o.IndexCreatedAt
+		}
+	}
+	p.ownershipMtx.Unlock()
+
+	prioritized := slices.Clone(tenants)
+	slices.SortStableFunc(prioritized, func(a, b string) int {
+		return createdAt[a].Compare(createdAt[b])
+	})
+	return prioritized
+}
+
+// updateIndexAgeTargetMetric counts the tenants whose index is older than the tenant index age target.
+func (p *Poller) updateIndexAgeTargetMetric(tenants []string) {
+	if p.cfg.TenantIndexAgeTarget <= 0 {
+		return
+	}
+
+	now := time.Now()
+	over := 0
+
+	p.ownershipMtx.Lock()
+	for _, tenantID := range tenants {
+		o, ok := p.ownership[tenantID]
+		if !ok || now.Sub(o.IndexCreatedAt) > p.cfg.TenantIndexAgeTarget {
+			over++
+		}
+	}
+	p.ownershipMtx.Unlock()
+
+	metricTenantIndexesOverAgeTarget.Set(float64(over))
+}
+
+// pollableTenants returns the tenants that match the tenant allow and deny lists.
+func (p *Poller) pollableTenants(tenants []string) []string {
+	if len(p.cfg.TenantAllowList) == 0 && len(p.cfg.TenantDenyList) == 0 {
+		return tenants
+	}
+
+	pollable := make([]string, 0, len(tenants))
+	for _, tenantID := range tenants {
+		if len(p.cfg.TenantAllowList) > 0 && !matchesTenant(p.cfg.TenantAllowList, tenantID) {
+			continue
+		}
+		if matchesTenant(p.cfg.TenantDenyList, tenantID) {
+			continue
+		}
+		pollable = append(pollable, tenantID)
+	}
+	return pollable
+}
+
+// matchesTenant returns true if the tenant matches one of the glob patterns. Invalid patterns match nothing, they
+// are rejected when the config is validated.
+func matchesTenant(patterns []string, tenantID string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, tenantID); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// tenantsToVerify returns a random sample of IndexVerificationTenants tenants.
+func (p *Poller) tenantsToVerify(tenants []string) map[string]bool {
+	n := p.cfg.IndexVerificationTenants
+	if n <= 0 || len(tenants) == 0 {
+		return nil
+	}
+	if n > len(tenants) {
+		n = len(tenants)
+	}
+
+	verify := make(map[string]bool, n)
+	for _, i := range rand.Perm(len(tenants))[:n] {
+		verify[tenants[i]] = true
+	}
+
+	return verify
+}
+
+// verifyTenantIndex compares the tenant index against a listing of the backend and reports blocks that
+// are missing from or extra in the index. Blocks written after the index was created are reported as
+// missing, so a steadily increasing number indicates an index that is not being rebuilt. Verification
+// never fails polling.
+func (p *Poller) verifyTenantIndex(ctx context.Context, tenantID string, i *backend.TenantIndex) {
+	derivedCtx, span := tracer.Start(ctx, "Poller.verifyTenantIndex", trace.WithAttributes(attribute.String("tenant", tenantID)))
+	defer span.End()
+
+	var blockIDs, compactedBlockIDs []uuid.UUID
+	err := p.backendCall(derivedCtx, opBlocks, tenantID, func(ctx context.Context) error {
+		var err error
+		blockIDs, compactedBlockIDs, err = p.reader.Blocks(ctx, tenantID)
+		return err
+	})
+	if err != nil {
+		metricTenantIndexVerifications.WithLabelValues(verificationResultError).Inc()
+		level.Error(p.logger).Log("msg", "failed to list blocks to verify tenant index", "tenant", tenantID, "err", err)
+		span.RecordError(err)
+		return
+	}
+
+	missing, extra := tenantIndexDrift(i, blockIDs, compactedBlockIDs)
+	metricTenantIndexMissingBlocks.WithLabelValues(tenantID).Set(float64(missing))
+	metricTenantIndexExtraBlocks.WithLabelValues(tenantID).Set(float64(extra))
+	span.SetAttributes(attribute.Int("missing", missing), attribute.Int("extra", extra))
+
+	if missing == 0 && extra == 0 {
+		metricTenantIndexVerifications.WithLabelValues(verificationResultOK).Inc()
+		return
+	}
+
+	metricTenantIndexVerifications.WithLabelValues(verificationResultDrift).Inc()
+	level.Warn(p.logger).Log("msg", "tenant index does not match backend listing", "tenant", tenantID, "createdAt", i.CreatedAt, "missing", missing, "extra", extra)
+}
+
+// tenantIndexDrift returns the number of listed blocks that are not in the index and the number of
+// blocks in the index that were not listed. Live and compacted blocks are considered together since
+// a block being compacted after the index was built is expected.
+func tenantIndexDrift(i *backend.TenantIndex, blockIDs, compactedBlockIDs []uuid.UUID) (missing, extra int) {
+	listed := make(map[backend.UUID]struct{}, len(blockIDs)+len(compactedBlockIDs))
+	for _, id := range blockIDs {
+		listed[backend.UUID(id)] = struct{}{}
+	}
+	for _, id := range compactedBlockIDs {
+		listed[backend.UUID(id)] = struct{}{}
+	}
+
+	indexed := make(map[backend.UUID]struct{}, len(i.Meta)+len(i.CompactedMeta))
+	for _, m := range i.Meta {
+		indexed[m.BlockID] = struct{}{}
+	}
+	for _, m := range i.CompactedMeta {
+		indexed[m.BlockID] = struct{}{}
+	}
+
+	for id := range listed {
+		if _, ok := indexed[id]; !ok {
+			missing++
+		}
+	}
+	for id := range indexed {
+		if _, ok := listed[id]; !ok {
+			extra++
+		}
+	}
+
+	return missing, extra
+}
+
 func (p *Poller) pollTenantBlocks(
 	ctx context.Context,
 	tenantID string,
@@ -329,9 +882,18 @@ func (p *Poller) pollTenantBlocks(
 	derivedCtx, span := tracer.Start(ctx, "Poller.pollTenantBlocks")
 	defer span.End()
 
-	currentBlockIDs, currentCompactedBlockIDs, err := p.reader.Blocks(derivedCtx, tenantID)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed listing tenant blocks: %w", err)
+	currentBlockIDs, currentCompactedBlockIDs, bootstrapped := p.bootstrapFromInventory(tenantID, previous)
+	span.SetAttributes(attribute.Bool("inventory_bootstrap", bootstrapped))
+	if !bootstrapped {
+		err := p.backendCall(derivedCtx, opBlocks, tenantID, func(ctx context.Context) error {
+			var err error
+			currentBlockIDs, currentCompactedBlockIDs, err = p.reader.Blocks(ctx, tenantID)
+			return err
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed listing tenant blocks: %w", err)
+		}
+		p.crossCheckInventory(tenantID, currentBlockIDs, currentCompactedBlockIDs)
 	}
 
 	var (
@@ -478,7 +1040,12 @@ func (p *Poller) pollBlock(
 	var compactedBlockMeta *backend.CompactedBlockMeta
 
 	if !compacted && p.cfg.SkipNoCompactBlocks {
-		noCompact, flagErr := p.reader.HasNoCompactFlag(derivedCtx, blockID, tenantID)
+		var noCompact bool
+		flagErr := p.backendCall(derivedCtx, opHasNoCompactFlag, tenantID, func(ctx context.Context) error {
+			var err error
+			noCompact, err = p.reader.HasNoCompactFlag(ctx, blockID, tenantID)
+			return err
+		})
 		if flagErr != nil {
 			return nil, nil, fmt.Errorf("failed to check nocompact flag: %w", flagErr)
 		}
@@ -487,12 +1054,31 @@ func (p *Poller) pollBlock(
 		}
 	}
 	if !compacted {
-		blockMeta, err = p.reader.BlockMeta(derivedCtx, blockID, tenantID)
+		err = p.backendCall(derivedCtx, opBlockMeta, tenantID, func(ctx context.Context) error {
+			var err error
+			blockMeta, err = p.reader.BlockMeta(ctx, blockID, tenantID)
+			return err
+		})
 	}
 	// if the normal meta doesn't exist maybe it's compacted.
 	if errors.Is(err, backend.ErrDoesNotExist) || compacted {
 		blockMeta = nil
-		compactedBlockMeta, err = p.compactor.CompactedBlockMeta(blockID, tenantID)
+		// CompactedBlockMeta does not accept a context so the deadline can not be propagated. It
+		// is still measured against the slow threshold.
+		err = p.backendCall(derivedCtx, opCompactedBlockMeta, tenantID, func(context.Context) error {
+			var err error
+			compactedBlockMeta, err = p.compactor.CompactedBlockMeta(blockID, tenantID)
+			return err
+		})
+	}
+
+	if err == nil {
+		switch {
+		case blockMeta != nil:
+			err = p.readBytes(derivedCtx, blockMeta.Size())
+		case compactedBlockMeta != nil:
+			err = p.readBytes(derivedCtx, compactedBlockMeta.Size())
+		}
 	}
 
 	// blocks in intermediate states may not have a compacted or normal block meta.
@@ -505,9 +1091,201 @@ func (p *Poller) pollBlock(
 		return nil, nil, err
 	}
 
+	if p.sources != nil {
+		source := p.sources.BlockSource(tenantID, blockID)
+		switch {
+		case blockMeta != nil:
+			blockMeta.Source = source
+		case compactedBlockMeta != nil:
+			compactedBlockMeta.Source = source
+		}
+	}
+
 	return blockMeta, compactedBlockMeta, nil
 }
 
+// backendCall executes a single backend call on behalf of the poller. It applies the configured
+// hard per-call deadline, audits the presence of a deadline and records calls that exceed the
+// slow threshold.
+func (p *Poller) backendCall(ctx context.Context, op, tenantID string, fn func(context.Context) error) error {
+	// wait on the rate limit before applying the per-call deadline so that time spent waiting is not
+	// counted against the call
+	if _, ok := readOps[op]; ok {
+		if err := p.wait(ctx, p.requestLimiter, rateLimitRequests, 1); err != nil {
+			return fmt.Errorf("backend call %s rate limited: %w", op, err)
+		}
+	}
+
+	if p.cfg.BackendCallTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.cfg.BackendCallTimeout)
+		defer cancel()
+	}
+
+	if p.cfg.BackendCallDeadlineAudit {
+		if _, ok := ctx.Deadline(); !ok {
+			metricBackendCallsWithoutDeadline.WithLabelValues(op).Inc()
+			level.Warn(p.logger).Log("msg", "backend call in poll path has no deadline", "operation", op, "tenant", tenantID)
+		}
+	}
+
+	start := time.Now()
+	err := fn(ctx)
+	elapsed := time.Since(start)
+
+	if p.cfg.BackendCallSlowThreshold > 0 && elapsed > p.cfg.BackendCallSlowThreshold {
+		metricBackendCallsSlow.WithLabelValues(op).Inc()
+		level.Warn(p.logger).Log("msg", "slow backend call in poll path", "operation", op, "tenant", tenantID, "duration", elapsed)
+	}
+
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		metricBackendCallsTimedOut.WithLabelValues(op).Inc()
+		return fmt.Errorf("backend call %s exceeded deadline: %w", op, err)
+	}
+
+	return err
+}
+
+// loadInventory loads the latest inventory for this poll cycle. The poll continues without it if it can't
+// be loaded.
+func (p *Poller) loadInventory(ctx context.Context) {
+	if p.inventorySource == nil {
+		return
+	}
+
+	var inv *Inventory
+	err := p.backendCall(ctx, opInventory, "", func(ctx context.Context) error {
+		var err error
+		inv, err = p.inventorySource.Inventory(ctx)
+		return err
+	})
+	if err != nil {
+		metricInventoryErrors.Inc()
+		level.Warn(p.logger).Log("msg", "failed to load bucket inventory, listing the backend instead", "err", err)
+	} else {
+		metricInventoryAgeSeconds.Set(time.Since(inv.CreatedAt).Seconds())
+	}
+
+	p.inventoryMtx.Lock()
+	defer p.inventoryMtx.Unlock()
+	p.inventory = inv
+}
+
+// bootstrapFromInventory returns the blocks of the tenant from the inventory if the tenant is not in the
+// previous blocklist. Blocks written after the inventory was created are picked up by the listing of the
+// next poll. Each tenant is bootstrapped at most once.
+func (p *Poller) bootstrapFromInventory(tenantID string, previous *List) ([]uuid.UUID, []uuid.UUID, bool) {
+	p.inventoryMtx.Lock()
+	defer p.inventoryMtx.Unlock()
+
+	if p.inventory == nil {
+		return nil, nil, false
+	}
+	if _, ok := p.bootstrapped[tenantID]; ok {
+		return nil, nil, false
+	}
+	if len(previous.Metas(tenantID)) > 0 || len(previous.CompactedMetas(tenantID)) > 0 {
+		return nil, nil, false
+	}
+
+	blockIDs, compactedBlockIDs, ok := p.inventory.Blocks(tenantID)
+	if !ok {
+		return nil, nil, false
+	}
+
+	p.bootstrapped[tenantID] = struct{}{}
+	metricInventoryBootstrappedTenants.Inc()
+	level.Info(p.logger).Log("msg", "bootstrapping tenant blocklist from bucket inventory", "tenant", tenantID, "inventory_created_at", p.inventory.CreatedAt, "blocks", len(blockIDs), "compactedBlocks", len(compactedBlockIDs))
+
+	return blockIDs, compactedBlockIDs, true
+}
+
+// crossCheckInventory compares the listing of the tenant with the inventory. Blocks written since the inventory was
+// created are missing from the inventory and blocks deleted since are missing from the backend, a growing drift
+// means the inventory isn't refreshed. A block that is compacted in the inventory but not in the listing means the
+// inventory doesn't match the backend, it isn't used for the rest of the poll.
+func (p *Poller) crossCheckInventory(tenantID string, blockIDs, compactedBlockIDs []uuid.UUID) {
+	p.inventoryMtx.Lock()
+	inv := p.inventory
+	p.inventoryMtx.Unlock()
+
+	if inv == nil {
+		return
+	}
+	invBlockIDs, invCompactedBlockIDs, ok := inv.Blocks(tenantID)
+	if !ok {
+		return
+	}
+
+	listed := make(map[uuid.UUID]struct{}, len(blockIDs)+len(compactedBlockIDs))
+	for _, id := range blockIDs {
+		listed[id] = struct{}{}
+	}
+	for _, id := range compactedBlockIDs {
+		listed[id] = struct{}{}
+	}
+	inventoried := make(map[uuid.UUID]struct{}, len(invBlockIDs)+len(invCompactedBlockIDs))
+	for _, id := range invBlockIDs {
+		inventoried[id] = struct{}{}
+	}
+	for _, id := range invCompactedBlockIDs {
+		inventoried[id] = struct{}{}
+	}
+
+	missingFromInventory, missingFromBackend := 0, 0
+	for id := range listed {
+		if _, ok := inventoried[id]; !ok {
+			missingFromInventory++
+		}
+	}
+	for id := range inventoried {
+		if _, ok := listed[id]; !ok {
+			missingFromBackend++
+		}
+	}
+	metricInventoryDriftBlocks.WithLabelValues(tenantID, "inventory").Set(float64(missingFromInventory))
+	metricInventoryDriftBlocks.WithLabelValues(tenantID, "backend").Set(float64(missingFromBackend))
+
+	// blocks are never uncompacted
+	compacted := make(map[uuid.UUID]struct{}, len(compactedBlockIDs))
+	for _, id := range compactedBlockIDs {
+		compacted[id] = struct{}{}
+	}
+	for _, id := range invCompactedBlockIDs {
+		if _, ok := compacted[id]; !ok {
+			if _, ok := listed[id]; ok {
+				// stop bootstrapping tenants from an inventory of another backend until the next poll reloads it
+				metricInventoryErrors.Inc()
+				level.Warn(p.logger).Log("msg", "bucket inventory doesn't match the backend, a block compacted in the inventory isn't compacted in the backend", "tenant", tenantID, "block", id)
+				p.inventoryMtx.Lock()
+				if p.inventory == inv {
+					p.inventory = nil
+				}
+				p.inventoryMtx.Unlock()
+				return
+			}
+		}
+	}
+}
+
+// readBytes accounts for n bytes read from the backend against the bytes rate limit. It blocks until the
+// limit allows them.
+func (p *Poller) readBytes(ctx context.Context, n int) error {
+	return p.wait(ctx, p.bytesLimiter, rateLimitBytes, n)
+}
+
+func (p *Poller) wait(ctx context.Context, l *rate.Limiter, limit string, n int) error {
+	if l == nil || n <= 0 {
+		return nil
+	}
+
+	start := time.Now()
+	err := l.WaitN(ctx, min(n, l.Burst()))
+	metricRateLimitedSeconds.WithLabelValues(limit).Add(time.Since(start).Seconds())
+
+	return err
+}
+
 // tenantIndexBuilder returns true if this poller owns this tenant
 func (p *Poller) tenantIndexBuilder(tenant string) bool {
 	for i := 0; i < p.cfg.TenantIndexBuilders; i++ {
@@ -520,6 +1298,132 @@ func (p *Poller) tenantIndexBuilder(tenant string) bool {
 	return false
 }
 
+// takeOverTenantIndex returns true if this poller should build the index of a tenant it doesn't own because the
+// heartbeat of the builder is older than TenantIndexBuilderTimeout. The heartbeat is replaced with a versioned write
+// so only one poller takes over, and it keeps building the index until the owner writes a heartbeat again. Tenants
+// without a heartbeat are never taken over.
+func (p *Poller) takeOverTenantIndex(ctx context.Context, tenantID string) bool {
+	if p.heartbeats == nil || p.cfg.TenantIndexBuilderTimeout <= 0 {
+		return false
+	}
+
+	var (
+		hb      *backend.TenantIndexHeartbeat
+		version backend.Version
+	)
+	err := p.backendCall(ctx, opHeartbeat, tenantID, func(ctx context.Context) error {
+		var err error
+		hb, version, err = backend.ReadTenantIndexHeartbeat(ctx, p.heartbeats, tenantID)
+		return err
+	})
+	if err != nil {
+		if !errors.Is(err, backend.ErrDoesNotExist) {
+			level.Error(p.logger).Log("msg", "failed to read tenant index heartbeat", "tenant", tenantID, "err", err)
+		}
+		p.releaseTenantIndex(tenantID)
+		return false
+	}
+	p.updateOwnership(tenantID, func(o *TenantIndexOwnership) { o.Heartbeat = hb })
+
+	p.takeoversMtx.Lock()
+	held, ok := p.takeovers[tenantID]
+	p.takeoversMtx.Unlock()
+
+	// the owner or another poller is building the index
+	holding := ok && held == version
+	if !holding && time.Since(hb.Time) <= p.cfg.TenantIndexBuilderTimeout {
+		p.releaseTenantIndex(tenantID)
+		return false
+	}
+
+	var newVersion backend.Version
+	err = p.backendCall(ctx, opWriteHeartbeat, tenantID, func(ctx context.Context) error {
+		var err error
+		newVersion, err = backend.WriteTenantIndexHeartbeat(ctx, p.heartbeats, tenantID, &backend.TenantIndexHeartbeat{
+			Builder:  p.builderID,
+			Takeover: true,
+			Time:     time.Now(),
+		}, version)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, backend.ErrVersionDoesNotMatch) {
+			// another poller took over first
+			p.updateOwnership(tenantID, func(o *TenantIndexOwnership) { o.Conflicts++ })
+		} else {
+			level.Error(p.logger).Log("msg", "failed to write tenant index heartbeat", "tenant", tenantID, "err", err)
+		}
+		p.releaseTenantIndex(tenantID)
+		return false
+	}
+
+	if !holding {
+		level.Warn(p.logger).Log("msg", "taking over tenant index builder", "tenant", tenantID, "builder", hb.Builder, "heartbeat", hb.Time)
+		metricTenantIndexTakeovers.WithLabelValues(tenantID).Inc()
+	}
+
+	p.takeoversMtx.Lock()
+	p.takeovers[tenantID] = newVersion
+	p.takeoversMtx.Unlock()
+
+	return true
+}
+
+// releaseTenantIndex stops building the index of a tenant that was taken over.
+func (p *Poller) releaseTenantIndex(tenantID string) {
+	p.takeoversMtx.Lock()
+	defer p.takeoversMtx.Unlock()
+
+	if _, ok := p.takeovers[tenantID]; ok {
+		level.Info(p.logger).Log("msg", "releasing tenant index builder", "tenant", tenantID)
+		delete(p.takeovers, tenantID)
+	}
+}
+
+// writeTenantIndexHeartbeat writes the heartbeat of the owner of the tenant after it wrote the tenant index. The owner
+// always wins over pollers that took over the tenant, which already renewed the heartbeat. The heartbeat of a tenant
+// without blocks is deleted so it doesn't keep the tenant from being deleted, and the tenant isn't taken over.
+func (p *Poller) writeTenantIndexHeartbeat(ctx context.Context, tenantID string, owner, empty bool) {
+	if p.heartbeats == nil || p.cfg.TenantIndexBuilderTimeout <= 0 {
+		return
+	}
+
+	if !owner {
+		if !empty {
+			return
+		}
+		p.releaseTenantIndex(tenantID)
+	}
+
+	err := p.backendCall(ctx, opWriteHeartbeat, tenantID, func(ctx context.Context) error {
+		hb, version, err := backend.ReadTenantIndexHeartbeat(ctx, p.heartbeats, tenantID)
+		if errors.Is(err, backend.ErrDoesNotExist) {
+			if empty {
+				return nil
+			}
+			version = backend.VersionNew
+		} else if err != nil {
+			return err
+		} else if owner && hb.Builder != p.builderID {
+			// another poller took over the tenant while this poller owns it
+			p.updateOwnership(tenantID, func(o *TenantIndexOwnership) { o.Conflicts++ })
+		}
+
+		if empty {
+			return p.heartbeats.DeleteVersioned(ctx, backend.TenantIndexHeartbeatName, backend.KeyPath{tenantID}, version)
+		}
+
+		_, err = backend.WriteTenantIndexHeartbeat(ctx, p.heartbeats, tenantID, &backend.TenantIndexHeartbeat{
+			Builder: p.builderID,
+			Time:    time.Now(),
+		}, version)
+		return err
+	})
+	if err != nil {
+		level.Error(p.logger).Log("msg", "failed to write tenant index heartbeat", "tenant", tenantID, "err", err)
+	}
+}
+
 func (p *Poller) tenantIndexPollError(idx *backend.TenantIndex, err error) error {
 	if err != nil {
 		return err
@@ -532,60 +1436,187 @@ func (p *Poller) tenantIndexPollError(idx *backend.TenantIndex, err error) error
 	return nil
 }
 
-// deleteTenant will delete all of a tenant's objects if there is not a tenant index present.
-func (p *Poller) deleteTenant(ctx context.Context, tenantID string) error {
+// deleteTenant will delete all of a tenant's objects if there is not a tenant index present. It returns true
+// if the tenant was deleted.
+func (p *Poller) deleteTenant(ctx context.Context, tenantID string) (bool, error) {
 	// If we have not enabled empty tenant deletion, do nothing.
 	if !p.cfg.EmptyTenantDeletionEnabled {
-		return nil
+		return false, nil
 	}
 
 	level.Info(p.logger).Log("msg", "deleting tenant", "tenant", tenantID)
 
 	if p.cfg.EmptyTenantDeletionAge == 0 {
-		return fmt.Errorf("empty tenant deletion age must be greater than 0")
+		return false, fmt.Errorf("empty tenant deletion age must be greater than 0")
 	}
 
 	var (
-		foundObjects  []string
+		foundObjects  []backend.FindMatch
 		recentObjects int
 	)
-	err := p.reader.Find(ctx, backend.KeyPath{tenantID}, func(opts backend.FindMatch) {
-		level.Info(p.logger).Log("msg", "checking object for deletion", "object", opts.Key, "modified", opts.Modified)
-
-		if time.Since(opts.Modified) > p.cfg.EmptyTenantDeletionAge {
-			foundObjects = append(foundObjects, opts.Key)
-		} else {
-			recentObjects++
-		}
+	err := p.backendCall(ctx, opFind, tenantID, func(ctx context.Context) error {
+		return p.reader.Find(ctx, backend.KeyPath{tenantID}, func(opts backend.FindMatch) {
+			level.Info(p.logger).Log("msg", "checking object for deletion", "object", opts.Key, "modified", opts.Modified)
+
+			if time.Since(opts.Modified) > p.cfg.EmptyTenantDeletionAge {
+				foundObjects = append(foundObjects, opts)
+			} else {
+				recentObjects++
+			}
+		})
 	})
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// do nothing if there are recent objects for this tenant.
 	if recentObjects > 0 {
-		return nil
+		p.finishTenantDeletion(tenantID)
+		return false, nil
 	}
 
 	// do nothing if the tenant index has appeared.
-	_, err = p.reader.TenantIndex(ctx, tenantID)
+	err = p.backendCall(ctx, opTenantIndex, tenantID, func(ctx context.Context) error {
+		_, err := p.reader.TenantIndex(ctx, tenantID)
+		return err
+	})
 	// If we have any error other than that which indicates that the tenant index
 	// call was made successfully, and that it does not exist, do nothing.  Only
 	// proceed if we know that the index does not exist.
 	if !errors.Is(err, backend.ErrDoesNotExist) {
-		return nil
+		return false, nil
 	}
 
-	for _, object := range foundObjects {
-		dir, name := path.Split(object)
-		level.Info(p.logger).Log("msg", "deleting", "tenant", tenantID, "object", object)
-		err = p.writer.Delete(ctx, name, backend.KeyPath{dir})
-		if err != nil {
-			return err
+	progress, started, ok := p.startTenantDeletion(tenantID)
+	if !ok {
+		level.Info(p.logger).Log("msg", "deferring tenant deletion, too many tenants deleted in this poll", "tenant", tenantID)
+		metricTenantDeletionsDeferred.WithLabelValues(deletionDeferredMaxTenants).Inc()
+		return false, nil
+	}
+
+	if !started {
+		for _, l := range p.listeners {
+			l.BeforeTenantDeletion(ctx, tenantID)
 		}
 	}
 
-	return nil
+	complete := true
+	if limit := p.cfg.EmptyTenantDeletionMaxObjects; limit > 0 && len(foundObjects) > limit {
+		foundObjects = foundObjects[:limit]
+		complete = false
+	}
+
+	deletedObjects, deletedBytes, err := p.deleteObjects(ctx, tenantID, foundObjects)
+	progress.objects += deletedObjects
+	progress.bytes += deletedBytes
+	metricTenantDeletedBytes.Add(float64(deletedBytes))
+	if err != nil {
+		p.updateTenantDeletion(tenantID, progress)
+		return false, err
+	}
+
+	if !complete {
+		p.updateTenantDeletion(tenantID, progress)
+		level.Info(p.logger).Log("msg", "deferring deletion of remaining tenant objects to the next poll", "tenant", tenantID, "objects", deletedObjects, "bytes", deletedBytes)
+		metricTenantDeletionsDeferred.WithLabelValues(deletionDeferredMaxObjects).Inc()
+		return false, nil
+	}
+
+	p.finishTenantDeletion(tenantID)
+	metricTenantDeleted.Inc()
+	clearTenantMetrics(tenantID)
+	level.Info(p.logger).Log("msg", "deleted tenant", "tenant", tenantID, "objects", progress.objects, "bytes", progress.bytes)
+
+	for _, l := range p.listeners {
+		l.AfterTenantDeletion(ctx, tenantID, progress.objects, progress.bytes)
+	}
+
+	return true, nil
+}
+
+// deleteObjects deletes the objects with up to EmptyTenantDeletionConcurrency deletes in parallel. It returns the
+// number of objects and bytes deleted and the first error.
+func (p *Poller) deleteObjects(ctx context.Context, tenantID string, objects []backend.FindMatch) (int, int64, error) {
+	var (
+		wg       = boundedwaitgroup.New(max(1, p.cfg.EmptyTenantDeletionConcurrency))
+		mtx      sync.Mutex
+		objs     int
+		bytes    int64
+		firstErr error
+	)
+
+	for _, object := range objects {
+		mtx.Lock()
+		failed := firstErr != nil
+		mtx.Unlock()
+		if failed {
+			break
+		}
+
+		wg.Add(1)
+		go func(object backend.FindMatch) {
+			defer wg.Done()
+
+			dir, name := path.Split(object.Key)
+			level.Info(p.logger).Log("msg", "deleting", "tenant", tenantID, "object", object.Key)
+			err := p.backendCall(ctx, opDelete, tenantID, func(ctx context.Context) error {
+				return p.writer.Delete(ctx, name, backend.KeyPath{dir})
+			})
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			objs++
+			bytes += object.Size
+		}(object)
+	}
+	wg.Wait()
+
+	return objs, bytes, firstErr
+}
+
+// startTenantDeletion returns the progress of the deletion of the tenant and whether it was started in an earlier
+// poll. It returns false if the deletion of a new tenant would exceed EmptyTenantDeletionMaxTenantsPerPoll.
+func (p *Poller) startTenantDeletion(tenantID string) (tenantDeletionProgress, bool, bool) {
+	p.deletionsMtx.Lock()
+	defer p.deletionsMtx.Unlock()
+
+	if limit := p.cfg.EmptyTenantDeletionMaxTenantsPerPoll; limit > 0 && p.deletions >= limit {
+		return tenantDeletionProgress{}, false, false
+	}
+	p.deletions++
+
+	progress, started := p.deletionProgress[tenantID]
+	return progress, started, true
+}
+
+func (p *Poller) updateTenantDeletion(tenantID string, progress tenantDeletionProgress) {
+	p.deletionsMtx.Lock()
+	defer p.deletionsMtx.Unlock()
+
+	p.deletionProgress[tenantID] = progress
+}
+
+func (p *Poller) finishTenantDeletion(tenantID string) {
+	p.deletionsMtx.Lock()
+	defer p.deletionsMtx.Unlock()
+
+	delete(p.deletionProgress, tenantID)
+}
+
+// clearTenantMetrics removes the per-tenant series of a deleted tenant.
+func clearTenantMetrics(tenantID string) {
+	metricBlocklistErrors.DeleteLabelValues(tenantID)
+	metricTenantIndexErrors.DeleteLabelValues(tenantID)
+	metricTenantIndexBuilder.DeleteLabelValues(tenantID)
+	metricTenantIndexAgeSeconds.DeleteLabelValues(tenantID)
+	metricTenantIndexTakeovers.DeleteLabelValues(tenantID)
+	metricTenantIndexReplicaErrors.DeleteLabelValues(tenantID)
 }
 
 type backendMetaMetrics struct {
@@ -621,3 +1652,47 @@ func sumTotalBackendMetaMetrics(
 		compactedBlockMetaTotalBytes:   sumTotalBytesCBM,
 	}
 }
+
+type compactionLevelMetrics struct {
+	blocks int
+	bytes  uint64
+}
+
+// sumByCompactionLevel returns the number of blocks and bytes in the blocklist by compaction level.
+func sumByCompactionLevel(blockMeta []*backend.BlockMeta) map[uint32]compactionLevelMetrics {
+	levels := map[uint32]compactionLevelMetrics{}
+	for _, bm := range blockMeta {
+		l := levels[bm.CompactionLevel]
+		l.blocks++
+		l.bytes += bm.Size_
+		levels[bm.CompactionLevel] = l
+	}
+
+	return levels
+}
+
+// updateCompactionLevelMetrics replaces the per level metrics of the tenant. Levels that no longer
+// have any blocks are removed.
+func updateCompactionLevelMetrics(tenantID string, blockMeta []*backend.BlockMeta) {
+	metricBlocklistLevelBlocks.DeletePartialMatch(prometheus.Labels{"tenant": tenantID})
+	metricBlocklistLevelBytes.DeletePartialMatch(prometheus.Labels{"tenant": tenantID})
+
+	for lvl, m := range sumByCompactionLevel(blockMeta) {
+		l := strconv.FormatUint(uint64(lvl), 10)
+		metricBlocklistLevelBlocks.WithLabelValues(tenantID, l).Set(float64(m.blocks))
+		metricBlocklistLevelBytes.WithLabelValues(tenantID, l).Set(float64(m.bytes))
+	}
+}
+
+// updateVersionMetrics replaces the per version metrics of the tenant.
+func updateVersionMetrics(tenantID string, blockMeta []*backend.BlockMeta) {
+	metricBlocklistVersionBlocks.DeletePartialMatch(prometheus.Labels{"tenant": tenantID})
+
+	versions := map[string]int{}
+	for _, b := range blockMeta {
+		versions[b.Version]++
+	}
+	for v, n := range versions {
+		metricBlocklistVersionBlocks.WithLabelValues(tenantID, v).Set(float64(n))
+	}
+}
@@ -5,16 +5,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"maps"
 	"math/rand"
 	"os"
+	"slices"
 	"sort"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/go-kit/log"
 	uuid "github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -294,6 +298,454 @@ func TestTenantIndexFallback(t *testing.T) {
 	}
 }
 
+// versionedStore is an in memory backend.VersionedReaderWriter with a version per write.
+type versionedStore struct {
+	backend.RawReader
+	objects  map[string][]byte
+	versions map[string]int
+}
+
+func newVersionedStore() *versionedStore {
+	return &versionedStore{objects: map[string][]byte{}, versions: map[string]int{}}
+}
+
+func (s *versionedStore) WriteVersioned(_ context.Context, name string, keypath backend.KeyPath, data io.Reader, _ int64, version backend.Version) (backend.Version, error) {
+	key := backend.ObjectFileName(keypath, name)
+	current := backend.VersionNew
+	if _, ok := s.objects[key]; ok {
+		current = backend.Version(strconv.Itoa(s.versions[key]))
+	}
+	if current != version {
+		return "", backend.ErrVersionDoesNotMatch
+	}
+
+	b, err := io.ReadAll(data)
+	if err != nil {
+		return "", err
+	}
+	s.objects[key] = b
+	s.versions[key]++
+	return backend.Version(strconv.Itoa(s.versions[key])), nil
+}
+
+func (s *versionedStore) DeleteVersioned(_ context.Context, name string, keypath backend.KeyPath, _ backend.Version) error {
+	delete(s.objects, backend.ObjectFileName(keypath, name))
+	return nil
+}
+
+func (s *versionedStore) ReadVersioned(_ context.Context, name string, keypath backend.KeyPath) (io.ReadCloser, backend.Version, error) {
+	key := backend.ObjectFileName(keypath, name)
+	b, ok := s.objects[key]
+	if !ok {
+		return nil, "", backend.ErrDoesNotExist
+	}
+	return io.NopCloser(bytes.NewReader(b)), backend.Version(strconv.Itoa(s.versions[key])), nil
+}
+
+func TestTenantIndexBuilderTakeover(t *testing.T) {
+	ctx := context.Background()
+	tenant := "test"
+	store := newVersionedStore()
+
+	newTestPoller := func(owner bool, builderID string) (*Poller, *backend.MockWriter) {
+		w := &backend.MockWriter{}
+		p := NewPoller(&PollerConfig{
+			PollConcurrency:           testPollConcurrency,
+			TenantPollConcurrency:     testTenantPollConcurrency,
+			TenantIndexBuilders:       testBuilders,
+			EmptyTenantDeletionAge:    testEmptyTenantIndexAge,
+			TenantIndexBuilderTimeout: time.Minute,
+		}, &mockJobSharder{owns: owner}, newMockReader(PerTenant{tenant: newBlockMetas(1, tenant)}, nil, false), &backend.MockCompactor{}, w, log.NewNopLogger())
+		p.SetTenantIndexHeartbeats(store, builderID)
+		return p, w
+	}
+
+	poll := func(p *Poller, w *backend.MockWriter) bool {
+		w.IndexMeta = nil
+		_, _, err := p.Do(ctx, newBlocklist(PerTenant{}, PerTenantCompacted{}))
+		require.NoError(t, err)
+		return w.IndexMeta != nil
+	}
+
+	heartbeat := func() *backend.TenantIndexHeartbeat {
+		hb, _, err := backend.ReadTenantIndexHeartbeat(ctx, store, tenant)
+		require.NoError(t, err)
+		return hb
+	}
+
+	expireHeartbeat := func() {
+		hb, version, err := backend.ReadTenantIndexHeartbeat(ctx, store, tenant)
+		require.NoError(t, err)
+		hb.Time = time.Now().Add(-time.Hour)
+		_, err = backend.WriteTenantIndexHeartbeat(ctx, store, tenant, hb, version)
+		require.NoError(t, err)
+	}
+
+	owner, ownerWriter := newTestPoller(true, "owner")
+	a, aWriter := newTestPoller(false, "a")
+	b, bWriter := newTestPoller(false, "b")
+
+	// tenants without a heartbeat are never taken over
+	require.False(t, poll(a, aWriter))
+
+	// the owner builds the index and writes a heartbeat
+	require.True(t, poll(owner, ownerWriter))
+	require.Equal(t, "owner", heartbeat().Builder)
+	require.False(t, poll(a, aWriter))
+
+	// a stale heartbeat is taken over by the first poller to see it
+	expireHeartbeat()
+	require.True(t, poll(a, aWriter))
+	require.Equal(t, "a", heartbeat().Builder)
+	require.True(t, heartbeat().Takeover)
+	require.False(t, poll(b, bWriter))
+	require.True(t, poll(a, aWriter))
+
+	// and released once the owner is back
+	require.True(t, poll(owner, ownerWriter))
+	require.Equal(t, "owner", heartbeat().Builder)
+	require.False(t, poll(a, aWriter))
+	require.Empty(t, a.takeovers)
+}
+
+type ownerJobSharder struct {
+	mockJobSharder
+	owner string
+}
+
+func (m *ownerJobSharder) Owner(_ string) (string, error) { return m.owner, nil }
+
+func TestTenantIndexOwnership(t *testing.T) {
+	ctx := context.Background()
+	tenant := "test"
+	store := newVersionedStore()
+
+	newTestPoller := func(owner bool, builderID string) (*Poller, *backend.MockReader) {
+		r := newMockReader(PerTenant{tenant: newBlockMetas(1, tenant)}, nil, false)
+		p := NewPoller(&PollerConfig{
+			PollConcurrency:           testPollConcurrency,
+			TenantPollConcurrency:     testTenantPollConcurrency,
+			TenantIndexBuilders:       testBuilders,
+			EmptyTenantDeletionAge:    testEmptyTenantIndexAge,
+			TenantIndexBuilderTimeout: time.Minute,
+		}, &ownerJobSharder{mockJobSharder: mockJobSharder{owns: owner}, owner: "owner"}, r, &backend.MockCompactor{}, &backend.MockWriter{}, log.NewNopLogger())
+		p.SetTenantIndexHeartbeats(store, builderID)
+		return p, r.(*backend.MockReader)
+	}
+
+	poll := func(p *Poller) {
+		_, _, err := p.Do(ctx, newBlocklist(PerTenant{}, PerTenantCompacted{}))
+		require.NoError(t, err)
+	}
+
+	owner, _ := newTestPoller(true, "owner")
+	a, aReader := newTestPoller(false, "a")
+
+	poll(owner)
+	o := owner.TenantIndexOwnership()
+	require.Len(t, o, 1)
+	require.Equal(t, tenant, o[0].Tenant)
+	require.Equal(t, []string{"owner"}, o[0].Owners)
+	require.True(t, o[0].Builder)
+	require.False(t, o[0].Takeover)
+	require.False(t, o[0].LastPoll.IsZero())
+	require.False(t, o[0].LastBuilt.IsZero())
+	require.Zero(t, o[0].Conflicts)
+
+	// a reads the heartbeat of the owner and the index
+	aReader.TenantIndexFn = func(context.Context, string) (*backend.TenantIndex, error) {
+		return &backend.TenantIndex{CreatedAt: time.Unix(1000, 0)}, nil
+	}
+	poll(a)
+	o = a.TenantIndexOwnership()
+	require.Len(t, o, 1)
+	require.False(t, o[0].Builder)
+	require.True(t, o[0].LastBuilt.IsZero())
+	require.Equal(t, time.Unix(1000, 0), o[0].IndexCreatedAt)
+	require.Equal(t, "owner", o[0].Heartbeat.Builder)
+
+	// a takes over a stale heartbeat, and the owner sees the heartbeat of a when it's back
+	hb, version, err := backend.ReadTenantIndexHeartbeat(ctx, store, tenant)
+	require.NoError(t, err)
+	hb.Time = time.Now().Add(-time.Hour)
+	_, err = backend.WriteTenantIndexHeartbeat(ctx, store, tenant, hb, version)
+	require.NoError(t, err)
+
+	poll(a)
+	o = a.TenantIndexOwnership()
+	require.True(t, o[0].Builder)
+	require.True(t, o[0].Takeover)
+
+	poll(owner)
+	require.Equal(t, 1, owner.TenantIndexOwnership()[0].Conflicts)
+
+	// tenants that are gone are forgotten
+	aReader.T = nil
+	poll(a)
+	require.Empty(t, a.TenantIndexOwnership())
+}
+
+func TestTenantIndexVerification(t *testing.T) {
+	tenant := "verify"
+	listed := newBlockMetas(3, tenant)
+	compacted := newCompactedMetas(1)
+	deleted := newBlockMetas(2, tenant)
+
+	tests := []struct {
+		name            string
+		index           *backend.TenantIndex
+		tenants         int
+		expectedResult  string
+		expectedMissing float64
+		expectedExtra   float64
+	}{
+		{
+			name:           "disabled",
+			index:          &backend.TenantIndex{Meta: listed[:1]},
+			tenants:        0,
+			expectedResult: "",
+		},
+		{
+			name: "index matches listing",
+			index: &backend.TenantIndex{
+				Meta:          listed[:2],
+				CompactedMeta: []*backend.CompactedBlockMeta{{BlockMeta: *listed[2]}, compacted[0]}, // compacted after being listed is fine
+			},
+			tenants:        1,
+			expectedResult: verificationResultOK,
+		},
+		{
+			name: "index drifted",
+			index: &backend.TenantIndex{
+				Meta:          append([]*backend.BlockMeta{listed[0]}, deleted...),
+				CompactedMeta: compacted,
+			},
+			tenants:         1,
+			expectedResult:  verificationResultDrift,
+			expectedMissing: 2,
+			expectedExtra:   2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newMockReader(PerTenant{tenant: listed}, PerTenantCompacted{tenant: compacted}, false)
+			r.(*backend.MockReader).TenantIndexFn = func(context.Context, string) (*backend.TenantIndex, error) {
+				tc.index.CreatedAt = time.Now()
+				return tc.index, nil
+			}
+
+			poller := NewPoller(&PollerConfig{
+				PollConcurrency:          testPollConcurrency,
+				TenantPollConcurrency:    testTenantPollConcurrency,
+				TenantIndexBuilders:      testBuilders,
+				IndexVerificationTenants: tc.tenants,
+			}, &mockJobSharder{}, r, &backend.MockCompactor{}, &backend.MockWriter{}, log.NewNopLogger())
+
+			before := map[string]float64{}
+			for _, result := range []string{verificationResultOK, verificationResultDrift, verificationResultError} {
+				before[result] = testutil.ToFloat64(metricTenantIndexVerifications.WithLabelValues(result))
+			}
+
+			list, _, err := poller.Do(context.Background(), newBlocklist(PerTenant{}, PerTenantCompacted{}))
+			require.NoError(t, err)
+			// verification never changes the polled blocklist
+			assert.Equal(t, tc.index.Meta, list[tenant])
+
+			for result, v := range before {
+				expected := v
+				if result == tc.expectedResult {
+					expected++
+				}
+				assert.Equal(t, expected, testutil.ToFloat64(metricTenantIndexVerifications.WithLabelValues(result)), result)
+			}
+			if tc.expectedResult != "" {
+				assert.Equal(t, tc.expectedMissing, testutil.ToFloat64(metricTenantIndexMissingBlocks.WithLabelValues(tenant)))
+				assert.Equal(t, tc.expectedExtra, testutil.ToFloat64(metricTenantIndexExtraBlocks.WithLabelValues(tenant)))
+			}
+		})
+	}
+}
+
+type recordingTenantListener struct {
+	before         []string
+	after          []string
+	deletedObjects int
+	deletedBytes   int64
+}
+
+func (l *recordingTenantListener) BeforeTenantDeletion(_ context.Context, tenantID string) {
+	l.before = append(l.before, tenantID)
+}
+
+func (l *recordingTenantListener) AfterTenantDeletion(_ context.Context, tenantID string, deletedObjects int, deletedBytes int64) {
+	l.after = append(l.after, tenantID)
+	l.deletedObjects += deletedObjects
+	l.deletedBytes += deletedBytes
+}
+
+func TestDeleteTenantListeners(t *testing.T) {
+	d := t.TempDir()
+	rr, ww, cc, err := local.New(&local.Config{Path: d})
+	require.NoError(t, err)
+
+	var (
+		ctx    = context.Background()
+		tenant = "empty"
+		old    = time.Now().Add(-2 * testEmptyTenantIndexAge)
+		data   = []byte("leftover")
+	)
+
+	for _, name := range []string{"a", "b"} {
+		require.NoError(t, ww.Write(ctx, name, backend.KeyPath{tenant, "dir"}, bytes.NewReader(data), int64(len(data)), nil))
+		require.NoError(t, os.Chtimes(d+"/"+tenant+"/dir/"+name, old, old))
+	}
+
+	listener := &recordingTenantListener{}
+	poller := NewPoller(&PollerConfig{
+		EmptyTenantDeletionAge:     testEmptyTenantIndexAge,
+		EmptyTenantDeletionEnabled: true,
+	}, &mockJobSharder{owns: true}, backend.NewReader(rr), cc, backend.NewWriter(ww), log.NewNopLogger())
+	poller.AddTenantLifecycleListener(listener)
+
+	metricTenantIndexAgeSeconds.WithLabelValues(tenant).Set(10)
+	deletedBefore := testutil.ToFloat64(metricTenantDeleted)
+	bytesBefore := testutil.ToFloat64(metricTenantDeletedBytes)
+
+	deleted, err := poller.deleteTenant(ctx, tenant)
+	require.NoError(t, err)
+	require.True(t, deleted)
+
+	assert.Equal(t, []string{tenant}, listener.before)
+	assert.Equal(t, []string{tenant}, listener.after)
+	assert.Equal(t, 2, listener.deletedObjects)
+	assert.Equal(t, int64(2*len(data)), listener.deletedBytes)
+
+	assert.Equal(t, deletedBefore+1, testutil.ToFloat64(metricTenantDeleted))
+	assert.Equal(t, bytesBefore+float64(2*len(data)), testutil.ToFloat64(metricTenantDeletedBytes))
+	assert.False(t, metricTenantIndexAgeSeconds.DeleteLabelValues(tenant), "tenant metrics should be removed")
+
+	// recent objects keep the tenant and don't notify listeners
+	require.NoError(t, ww.Write(ctx, "c", backend.KeyPath{tenant}, bytes.NewReader(data), int64(len(data)), nil))
+	deleted, err = poller.deleteTenant(ctx, tenant)
+	require.NoError(t, err)
+	require.False(t, deleted)
+	assert.Len(t, listener.before, 1)
+}
+
+func TestDeleteTenantBudgets(t *testing.T) {
+	d := t.TempDir()
+	rr, ww, cc, err := local.New(&local.Config{Path: d})
+	require.NoError(t, err)
+
+	var (
+		ctx  = context.Background()
+		old  = time.Now().Add(-2 * testEmptyTenantIndexAge)
+		data = []byte("leftover")
+	)
+
+	writeOld := func(tenant string, objects int) {
+		for i := 0; i < objects; i++ {
+			name := strconv.Itoa(i)
+			require.NoError(t, ww.Write(ctx, name, backend.KeyPath{tenant, "dir"}, bytes.NewReader(data), int64(len(data)), nil))
+			require.NoError(t, os.Chtimes(d+"/"+tenant+"/dir/"+name, old, old))
+		}
+	}
+	writeOld("a", 5)
+	writeOld("b", 1)
+
+	listener := &recordingTenantListener{}
+	poller := NewPoller(&PollerConfig{
+		EmptyTenantDeletionAge:               testEmptyTenantIndexAge,
+		EmptyTenantDeletionEnabled:           true,
+		EmptyTenantDeletionConcurrency:       3,
+		EmptyTenantDeletionMaxObjects:        2,
+		EmptyTenantDeletionMaxTenantsPerPoll: 1,
+	}, &mockJobSharder{owns: true}, backend.NewReader(rr), cc, backend.NewWriter(ww), log.NewNopLogger())
+	poller.AddTenantLifecycleListener(listener)
+
+	objectsDeferredBefore := testutil.ToFloat64(metricTenantDeletionsDeferred.WithLabelValues(deletionDeferredMaxObjects))
+	tenantsDeferredBefore := testutil.ToFloat64(metricTenantDeletionsDeferred.WithLabelValues(deletionDeferredMaxTenants))
+
+	newPoll := func() {
+		poller.deletionsMtx.Lock()
+		poller.deletions = 0
+		poller.deletionsMtx.Unlock()
+	}
+
+	// the objects of tenant a are deleted over three polls
+	for i, expected := range []bool{false, false, true} {
+		newPoll()
+
+		deleted, err := poller.deleteTenant(ctx, "a")
+		require.NoError(t, err)
+		require.Equal(t, expected, deleted, "poll %d", i)
+
+		// tenant b is deferred, only one tenant is deleted per poll
+		deleted, err = poller.deleteTenant(ctx, "b")
+		require.NoError(t, err)
+		require.False(t, deleted)
+	}
+
+	assert.Equal(t, []string{"a"}, listener.before)
+	assert.Equal(t, []string{"a"}, listener.after)
+	assert.Equal(t, 5, listener.deletedObjects)
+	assert.Equal(t, int64(5*len(data)), listener.deletedBytes)
+	assert.Equal(t, objectsDeferredBefore+2, testutil.ToFloat64(metricTenantDeletionsDeferred.WithLabelValues(deletionDeferredMaxObjects)))
+	assert.Equal(t, tenantsDeferredBefore+3, testutil.ToFloat64(metricTenantDeletionsDeferred.WithLabelValues(deletionDeferredMaxTenants)))
+	assert.Empty(t, poller.deletionProgress)
+
+	newPoll()
+	deleted, err := poller.deleteTenant(ctx, "b")
+	require.NoError(t, err)
+	require.True(t, deleted)
+	assert.Equal(t, []string{"a", "b"}, listener.after)
+}
+
+func TestTenantIndexReplica(t *testing.T) {
+	rr, ww, cc, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+	replicaR, replicaW, _, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+
+	var (
+		ctx     = context.Background()
+		w       = backend.NewWriter(ww)
+		replica = backend.NewReader(replicaR)
+		tenant  = "test"
+	)
+
+	for _, m := range newBlockMetas(3, tenant) {
+		require.NoError(t, w.WriteBlockMeta(ctx, m))
+	}
+
+	poller := NewPoller(&PollerConfig{
+		PollConcurrency:       testPollConcurrency,
+		TenantPollConcurrency: testTenantPollConcurrency,
+		PollFallback:          testPollFallback,
+		TenantIndexBuilders:   testBuilders,
+	}, &mockJobSharder{owns: true}, backend.NewReader(rr), cc, w, log.NewNopLogger())
+	poller.SetTenantIndexReplica(backend.NewWriter(replicaW))
+
+	metas, _, err := poller.Do(ctx, New())
+	require.NoError(t, err)
+	require.Len(t, metas[tenant], 3)
+
+	idx, err := replica.TenantIndex(ctx, tenant)
+	require.NoError(t, err)
+	require.ElementsMatch(t, metas[tenant], idx.Meta)
+
+	// a tenant without blocks removes the index of the replica
+	for _, m := range metas[tenant] {
+		require.NoError(t, cc.ClearBlock((uuid.UUID)(m.BlockID), tenant))
+	}
+	_, _, err = poller.Do(ctx, New())
+	require.NoError(t, err)
+
+	_, err = replica.TenantIndex(ctx, tenant)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+}
+
 func TestPollBlock(t *testing.T) {
 	one := backend.MustParse("00000000-0000-0000-0000-000000000001")
 
@@ -520,6 +972,106 @@ func TestTenantIndexPollError(t *testing.T) {
 	}, nil))
 }
 
+func TestPollBackendCallDeadline(t *testing.T) {
+	hungBlocksFn := func(ctx context.Context, _ string) ([]uuid.UUID, []uuid.UUID, error) {
+		<-ctx.Done()
+		return nil, nil, ctx.Err()
+	}
+
+	tests := []struct {
+		name             string
+		cfg              PollerConfig
+		expectsError     bool
+		expectedTimedOut float64
+		expectedSlow     float64
+		expectedNoDl     float64
+	}{
+		{
+			name:             "hard deadline cancels hung list",
+			cfg:              PollerConfig{BackendCallTimeout: 10 * time.Millisecond},
+			expectsError:     true,
+			expectedTimedOut: 1,
+		},
+		{
+			name:             "slow threshold is counted",
+			cfg:              PollerConfig{BackendCallTimeout: 20 * time.Millisecond, BackendCallSlowThreshold: time.Millisecond},
+			expectsError:     true,
+			expectedTimedOut: 1,
+			expectedSlow:     1,
+		},
+		{
+			name:         "audit counts calls without a deadline",
+			cfg:          PollerConfig{BackendCallDeadlineAudit: true, BackendCallSlowThreshold: time.Hour},
+			expectedNoDl: 1,
+		},
+		{
+			name: "audit passes when a deadline is applied",
+			cfg:  PollerConfig{BackendCallDeadlineAudit: true, BackendCallTimeout: time.Hour},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			metricBackendCallsTimedOut.Reset()
+			metricBackendCallsSlow.Reset()
+			metricBackendCallsWithoutDeadline.Reset()
+
+			r := &backend.MockReader{}
+			if tc.expectsError {
+				r.BlocksFn = hungBlocksFn
+			}
+
+			cfg := tc.cfg
+			cfg.PollConcurrency = testPollConcurrency
+			p := NewPoller(&cfg, &mockJobSharder{}, r, &backend.MockCompactor{}, &backend.MockWriter{}, log.NewNopLogger())
+
+			_, _, err := p.pollTenantBlocks(context.Background(), "test", New())
+			if tc.expectsError {
+				require.ErrorIs(t, err, context.DeadlineExceeded)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.expectedTimedOut, testutil.ToFloat64(metricBackendCallsTimedOut.WithLabelValues(opBlocks)))
+			assert.Equal(t, tc.expectedSlow, testutil.ToFloat64(metricBackendCallsSlow.WithLabelValues(opBlocks)))
+			assert.Equal(t, tc.expectedNoDl, testutil.ToFloat64(metricBackendCallsWithoutDeadline.WithLabelValues(opBlocks)))
+		})
+	}
+}
+
+func TestPollRateLimit(t *testing.T) {
+	p := NewPoller(&PollerConfig{
+		RequestsPerSecond: 1,
+		BytesPerSecond:    100,
+	}, &mockJobSharder{}, &backend.MockReader{}, &backend.MockCompactor{}, &backend.MockWriter{}, log.NewNopLogger())
+
+	noop := func(context.Context) error { return nil }
+	shortCtx := func() context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		t.Cleanup(cancel)
+		return ctx
+	}
+
+	// the first read fits in the burst, the next has to wait longer than the deadline allows
+	require.NoError(t, p.backendCall(shortCtx(), opBlocks, "test", noop))
+	require.Error(t, p.backendCall(shortCtx(), opBlockMeta, "test", noop))
+
+	// writes are not limited
+	require.NoError(t, p.backendCall(shortCtx(), opDelete, "test", noop))
+	require.NoError(t, p.backendCall(shortCtx(), opWriteTenantIndex, "test", noop))
+
+	// reads larger than the burst are capped to it
+	require.NoError(t, p.readBytes(shortCtx(), 1000))
+	require.Error(t, p.readBytes(shortCtx(), 50))
+
+	// no limits configured
+	p = NewPoller(&PollerConfig{}, &mockJobSharder{}, &backend.MockReader{}, &backend.MockCompactor{}, &backend.MockWriter{}, log.NewNopLogger())
+	for i := 0; i < 10; i++ {
+		require.NoError(t, p.backendCall(shortCtx(), opBlocks, "test", noop))
+		require.NoError(t, p.readBytes(shortCtx(), 1000))
+	}
+}
+
 func TestBlockListBackendMetrics(t *testing.T) {
 	tests := []struct {
 		name                                 string
@@ -635,6 +1187,169 @@ func TestBlockListBackendMetrics(t *testing.T) {
 	}
 }
 
+func TestCompactionLevelMetrics(t *testing.T) {
+	tenant := "level-metrics"
+
+	updateCompactionLevelMetrics(tenant, []*backend.BlockMeta{
+		{CompactionLevel: 0, Size_: 100},
+		{CompactionLevel: 0, Size_: 150},
+		{CompactionLevel: 2, Size_: 1000},
+	})
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(metricBlocklistLevelBlocks.WithLabelValues(tenant, "0")))
+	assert.Equal(t, 250.0, testutil.ToFloat64(metricBlocklistLevelBytes.WithLabelValues(tenant, "0")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metricBlocklistLevelBlocks.WithLabelValues(tenant, "2")))
+	assert.Equal(t, 1000.0, testutil.ToFloat64(metricBlocklistLevelBytes.WithLabelValues(tenant, "2")))
+
+	// levels without blocks are removed
+	updateCompactionLevelMetrics(tenant, []*backend.BlockMeta{
+		{CompactionLevel: 3, Size_: 1250},
+	})
+
+	assert.False(t, metricBlocklistLevelBlocks.DeleteLabelValues(tenant, "0"))
+	assert.False(t, metricBlocklistLevelBytes.DeleteLabelValues(tenant, "2"))
+	assert.Equal(t, 1250.0, testutil.ToFloat64(metricBlocklistLevelBytes.WithLabelValues(tenant, "3")))
+}
+
+func TestPollTenantAllowAndDenyLists(t *testing.T) {
+	tests := []struct {
+		name     string
+		allow    []string
+		deny     []string
+		expected []string
+	}{
+		{name: "no lists", expected: []string{"dev-1", "dev-2", "prod-1", "prod-2"}},
+		{name: "allow list", allow: []string{"prod-*"}, expected: []string{"prod-1", "prod-2"}},
+		{name: "deny list", deny: []string{"dev-*", "prod-2"}, expected: []string{"prod-1"}},
+		{name: "deny list takes precedence", allow: []string{"prod-*", "dev-1"}, deny: []string{"prod-1"}, expected: []string{"dev-1", "prod-2"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			list := PerTenant{}
+			for _, tenantID := range []string{"dev-1", "dev-2", "prod-1", "prod-2"} {
+				list[tenantID] = []*backend.BlockMeta{{BlockID: backend.NewUUID(), TenantID: tenantID}}
+			}
+
+			r := newMockReader(list, nil, false)
+			poller := NewPoller(&PollerConfig{
+				PollConcurrency:       testPollConcurrency,
+				PollFallback:          testPollFallback,
+				TenantIndexBuilders:   testBuilders,
+				TenantPollConcurrency: testTenantPollConcurrency,
+				TenantAllowList:       tc.allow,
+				TenantDenyList:        tc.deny,
+			}, &mockJobSharder{owns: true}, r, newMockCompactor(nil, false), &backend.MockWriter{}, log.NewNopLogger())
+
+			metas, _, err := poller.Do(context.Background(), New())
+			require.NoError(t, err)
+
+			var polled []string
+			for tenantID := range metas {
+				polled = append(polled, tenantID)
+			}
+			sort.Strings(polled)
+			require.Equal(t, tc.expected, polled)
+
+			// skipped tenants aren't read at all
+			for tenantID := range list {
+				_, read := r.(*backend.MockReader).BlockMetaCalls[tenantID]
+				require.Equal(t, slices.Contains(tc.expected, tenantID), read, tenantID)
+			}
+		})
+	}
+}
+
+func TestPollTenantIndexAgeTarget(t *testing.T) {
+	p := NewPoller(&PollerConfig{TenantIndexAgeTarget: time.Minute}, nil, nil, nil, nil, log.NewNopLogger())
+
+	now := time.Now()
+	p.updateOwnership("fresh", func(o *TenantIndexOwnership) { o.IndexCreatedAt = now })
+	p.updateOwnership("old", func(o *TenantIndexOwnership) { o.IndexCreatedAt = now.Add(-time.Hour) })
+	p.updateOwnership("older", func(o *TenantIndexOwnership) { o.IndexCreatedAt = now.Add(-2 * time.Hour) })
+
+	// tenants without a known index first, then oldest index first
+	tenants := []string{"fresh", "new", "old", "older"}
+	require.Equal(t, []string{"new", "older", "old", "fresh"}, p.prioritizeTenants(tenants))
+
+	p.updateIndexAgeTargetMetric(tenants)
+	require.Equal(t, float64(3), testutil.ToFloat64(metricTenantIndexesOverAgeTarget))
+
+	// listing order without a target
+	p.cfg.TenantIndexAgeTarget = 0
+	require.Equal(t, tenants, p.prioritizeTenants(tenants))
+
+	// all indexes are fresh once they're built
+	list := PerTenant{}
+	for _, tenantID := range tenants {
+		list[tenantID] = []*backend.BlockMeta{{BlockID: backend.NewUUID(), TenantID: tenantID}}
+	}
+	poller := NewPoller(&PollerConfig{
+		PollConcurrency:       testPollConcurrency,
+		PollFallback:          testPollFallback,
+		TenantIndexBuilders:   testBuilders,
+		TenantPollConcurrency: 1,
+		TenantIndexAgeTarget:  time.Minute,
+	}, &mockJobSharder{owns: true}, newMockReader(list, nil, false), newMockCompactor(nil, false), &backend.MockWriter{}, log.NewNopLogger())
+
+	_, _, err := poller.Do(context.Background(), New())
+	require.NoError(t, err)
+	require.Equal(t, float64(0), testutil.ToFloat64(metricTenantIndexesOverAgeTarget))
+}
+
+func TestPollerTenantFailingSince(t *testing.T) {
+	failing := true
+	r := &backend.MockReader{
+		T: []string{"ok", "failing"},
+		BlocksFn: func(_ context.Context, tenantID string) ([]uuid.UUID, []uuid.UUID, error) {
+			if tenantID == "failing" && failing {
+				return nil, nil, errors.New("err")
+			}
+			return nil, nil, nil
+		},
+		BlockMetaCalls: make(map[string]map[uuid.UUID]int),
+	}
+	poller := NewPoller(&PollerConfig{
+		PollConcurrency:        testPollConcurrency,
+		PollFallback:           testPollFallback,
+		TenantIndexBuilders:    testBuilders,
+		TenantPollConcurrency:  testTenantPollConcurrency,
+		TolerateTenantFailures: 1,
+	}, &mockJobSharder{owns: true}, r, newMockCompactor(PerTenantCompacted{}, false), &backend.MockWriter{}, log.NewNopLogger())
+
+	ctx := context.Background()
+	_, _, err := poller.Do(ctx, New())
+	require.NoError(t, err)
+
+	_, ok := poller.TenantFailingSince("ok")
+	require.False(t, ok)
+	since, ok := poller.TenantFailingSince("failing")
+	require.True(t, ok)
+
+	// the time of the first failure is kept
+	_, _, err = poller.Do(ctx, New())
+	require.NoError(t, err)
+	again, ok := poller.TenantFailingSince("failing")
+	require.True(t, ok)
+	require.Equal(t, since, again)
+
+	failing = false
+	_, _, err = poller.Do(ctx, New())
+	require.NoError(t, err)
+	_, ok = poller.TenantFailingSince("failing")
+	require.False(t, ok)
+
+	// the failures of the tenants that are gone are forgotten
+	failing = true
+	_, _, err = poller.Do(ctx, New())
+	require.NoError(t, err)
+	r.T = []string{"ok"}
+	_, _, err = poller.Do(ctx, New())
+	require.NoError(t, err)
+	_, ok = poller.TenantFailingSince("failing")
+	require.False(t, ok)
+}
+
 func TestPollTolerateConsecutiveErrors(t *testing.T) {
 	var (
 		c = newMockCompactor(PerTenantCompacted{}, false)
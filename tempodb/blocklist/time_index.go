@@ -0,0 +1,88 @@
+package blocklist
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// timeIndex is an interval tree of the metas of a tenant by their time range. It's an implicit binary search tree
+// over the metas sorted by start time: the node of the range [lo, hi) is the meta at (lo+hi)/2 and maxEnd holds the
+// max end time of the range, so ranges that end before the searched range are skipped.
+type timeIndex struct {
+	// positions of the metas in the blocklist, sorted by start time
+	positions []int
+	starts    []time.Time
+	ends      []time.Time
+	maxEnd    []time.Time
+}
+
+func newTimeIndex(metas []*backend.BlockMeta) *timeIndex {
+	idx := &timeIndex{
+		positions: make([]int, len(metas)),
+		starts:    make([]time.Time, len(metas)),
+		ends:      make([]time.Time, len(metas)),
+		maxEnd:    make([]time.Time, len(metas)),
+	}
+
+	for i := range metas {
+		idx.positions[i] = i
+	}
+	sort.SliceStable(idx.positions, func(i, j int) bool {
+		return metas[idx.positions[i]].StartTime.Before(metas[idx.positions[j]].StartTime)
+	})
+	for i, p := range idx.positions {
+		idx.starts[i] = metas[p].StartTime
+		idx.ends[i] = metas[p].EndTime
+	}
+
+	idx.build(0, len(metas))
+	return idx
+}
+
+// build sets the max end time of the range [lo, hi) and returns it.
+func (idx *timeIndex) build(lo, hi int) time.Time {
+	if lo >= hi {
+		return time.Time{}
+	}
+	mid := (lo + hi) / 2
+	end := idx.ends[mid]
+	if e := idx.build(lo, mid); e.After(end) {
+		end = e
+	}
+	if e := idx.build(mid+1, hi); e.After(end) {
+		end = e
+	}
+	idx.maxEnd[mid] = end
+	return end
+}
+
+// overlapping returns the positions in the blocklist of the metas that overlap the time range, in blocklist order.
+// Like overlaps the bounds are inclusive.
+func (idx *timeIndex) overlapping(start, end time.Time) []int {
+	// metas at or after n start after the end of the range
+	n := sort.Search(len(idx.starts), func(i int) bool { return idx.starts[i].After(end) })
+
+	var positions []int
+	var visit func(lo, hi int)
+	visit = func(lo, hi int) {
+		if lo >= hi || lo >= n {
+			return
+		}
+		mid := (lo + hi) / 2
+		if idx.maxEnd[mid].Before(start) {
+			return
+		}
+		visit(lo, mid)
+		if mid < n && !idx.ends[mid].Before(start) {
+			positions = append(positions, idx.positions[mid])
+		}
+		visit(mid+1, hi)
+	}
+	visit(0, len(idx.positions))
+
+	slices.Sort(positions)
+	return positions
+}
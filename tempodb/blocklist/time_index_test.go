@@ -0,0 +1,83 @@
+package blocklist
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func randomMetas(n int, r *rand.Rand) []*backend.BlockMeta {
+	metas := make([]*backend.BlockMeta, n)
+	for i := range metas {
+		start := r.Int63n(10_000)
+		metas[i] = &backend.BlockMeta{
+			BlockID:   backend.UUID(uuid.New()),
+			StartTime: time.Unix(start, 0),
+			EndTime:   time.Unix(start+r.Int63n(1_000), 0),
+		}
+	}
+	return metas
+}
+
+func TestMetasInRangeMatchesScan(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	metas := randomMetas(1000, r)
+
+	l := New()
+	l.ApplyPollResults(PerTenant{testTenantID: metas}, PerTenantCompacted{})
+
+	for i := 0; i < 200; i++ {
+		start := time.Unix(r.Int63n(11_000), 0)
+		end := start.Add(time.Duration(r.Int63n(500)) * time.Second)
+
+		var expected []*backend.BlockMeta
+		for _, m := range metas {
+			if overlaps(m, start, end) {
+				expected = append(expected, m)
+			}
+		}
+		require.Equal(t, expected, l.MetasInRange(testTenantID, start, end))
+	}
+}
+
+func TestMetasInRangeAfterUpdate(t *testing.T) {
+	block := func(start, end int64) *backend.BlockMeta {
+		return &backend.BlockMeta{BlockID: backend.UUID(uuid.New()), StartTime: time.Unix(start, 0), EndTime: time.Unix(end, 0)}
+	}
+	first, second, third := block(0, 10), block(20, 30), block(40, 50)
+
+	l := New()
+	l.ApplyPollResults(PerTenant{testTenantID: {first}}, PerTenantCompacted{})
+	require.Equal(t, []*backend.BlockMeta{first}, l.MetasInRange(testTenantID, time.Unix(0, 0), time.Unix(50, 0)))
+
+	// the index is rebuilt after updates
+	l.Update(testTenantID, []*backend.BlockMeta{second}, nil, nil, nil)
+	require.Equal(t, []*backend.BlockMeta{first, second}, l.MetasInRange(testTenantID, time.Unix(0, 0), time.Unix(50, 0)))
+
+	l.Update(testTenantID, nil, []*backend.BlockMeta{first}, nil, nil)
+	require.Equal(t, []*backend.BlockMeta{second}, l.MetasInRange(testTenantID, time.Unix(0, 0), time.Unix(50, 0)))
+
+	// and polls, which reapply the updates
+	l.ApplyPollResults(PerTenant{testTenantID: {third, first}}, PerTenantCompacted{})
+	require.Equal(t, []*backend.BlockMeta{third, second}, l.MetasInRange(testTenantID, time.Unix(0, 0), time.Unix(50, 0)))
+}
+
+func BenchmarkMetasInRange(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	metas := randomMetas(100_000, r)
+
+	l := New()
+	l.ApplyPollResults(PerTenant{testTenantID: metas}, PerTenantCompacted{})
+	start := time.Unix(5_000, 0)
+	end := start.Add(time.Minute)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = l.MetasInRange(testTenantID, start, end)
+	}
+}
@@ -32,11 +32,12 @@ type timeWindowBlockSelector struct {
 	MaxCompactionRange   time.Duration // Size of the time window - say 6 hours
 	MaxCompactionObjects int           // maximum size of compacted objects
 	MaxBlockBytes        uint64        // maximum block size, estimate
+	MaxCompactionLevel   uint32        // blocks at this level are not compacted again inside the active window. 0 is unlimited
 
-	entries []timeWindowBlockEntry
+	entries []blockEntry
 }
 
-type timeWindowBlockEntry struct {
+type blockEntry struct {
 	meta  *backend.BlockMeta
 	group string // Blocks in the same group will be compacted together. Sort order also determines group priority.
 	order string // Individual block priority within the group.
@@ -45,13 +46,14 @@ type timeWindowBlockEntry struct {
 
 var _ (CompactionBlockSelector) = (*timeWindowBlockSelector)(nil)
 
-func NewTimeWindowBlockSelector(blocklist []*backend.BlockMeta, maxCompactionRange time.Duration, maxCompactionObjects int, maxBlockBytes uint64, minInputBlocks, maxInputBlocks int) CompactionBlockSelector {
+func NewTimeWindowBlockSelector(blocklist []*backend.BlockMeta, maxCompactionRange time.Duration, maxCompactionObjects int, maxBlockBytes uint64, minInputBlocks, maxInputBlocks int, maxCompactionLevel uint32) CompactionBlockSelector {
 	twbs := &timeWindowBlockSelector{
 		MinInputBlocks:       minInputBlocks,
 		MaxInputBlocks:       maxInputBlocks,
 		MaxCompactionRange:   maxCompactionRange,
 		MaxCompactionObjects: maxCompactionObjects,
 		MaxBlockBytes:        maxBlockBytes,
+		MaxCompactionLevel:   maxCompactionLevel,
 	}
 
 	now := time.Now()
@@ -69,16 +71,21 @@ func NewTimeWindowBlockSelector(blocklist []*backend.BlockMeta, maxCompactionRan
 			continue
 		}
 
-		entry := timeWindowBlockEntry{
+		entry := blockEntry{
 			meta: b,
 		}
 
 		age := currWindow - w
 		if activeWindow <= w {
+			// blocks that reached the max compaction level are left alone until they exit the active window
+			if twbs.MaxCompactionLevel > 0 && b.CompactionLevel >= twbs.MaxCompactionLevel {
+				continue
+			}
+
 			// inside active window.
 			// Group by compaction level and window.
 			// Choose lowest compaction level and most recent windows first.
-			entry.group = fmt.Sprintf("A-%v-%016X-%v", b.CompactionLevel, age, b.ReplicationFactor)
+			entry.group = fmt.Sprintf("A-%v-%016X-%v", b.CompactionLevel, age, b.ReplicationFactor) + retentionClassGroup(b)
 
 			// Within group choose smallest blocks first.
 			// update after parquet: we want to make sure blocks of the same version end up together
@@ -89,7 +96,7 @@ func NewTimeWindowBlockSelector(blocklist []*backend.BlockMeta, maxCompactionRan
 		} else {
 			// outside active window.
 			// Group by window only.  Choose most recent windows first.
-			entry.group = fmt.Sprintf("B-%016X-%v", age, b.ReplicationFactor)
+			entry.group = fmt.Sprintf("B-%016X-%v", age, b.ReplicationFactor) + retentionClassGroup(b)
 
 			// Within group chose lowest compaction lvl and smallest blocks first.
 			// update after parquet: we want to make sure blocks of the same version end up together
@@ -102,38 +109,50 @@ func NewTimeWindowBlockSelector(blocklist []*backend.BlockMeta, maxCompactionRan
 		twbs.entries = append(twbs.entries, entry)
 	}
 
-	// sort by group then order
-	sort.SliceStable(twbs.entries, func(i, j int) bool {
-		ei := twbs.entries[i]
-		ej := twbs.entries[j]
+	sortEntries(twbs.entries)
+
+	return twbs
+}
+
+func (twbs *timeWindowBlockSelector) BlocksToCompact() ([]*backend.BlockMeta, string) {
+	return blocksToCompact(&twbs.entries, twbs.MinInputBlocks, twbs.MaxInputBlocks, twbs.MaxCompactionObjects, twbs.MaxBlockBytes)
+}
+
+// sortEntries sorts by group then order
+func sortEntries(entries []blockEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		ei := entries[i]
+		ej := entries[j]
 
 		if ei.group == ej.group {
 			return ei.order < ej.order
 		}
 		return ei.group < ej.group
 	})
-
-	return twbs
 }
 
-func (twbs *timeWindowBlockSelector) BlocksToCompact() ([]*backend.BlockMeta, string) {
-	for len(twbs.entries) > 0 {
-		var chosen []timeWindowBlockEntry
+// blocksToCompact returns the next stripe of sorted entries that share a group and stay within the limits. Entries
+// that were considered are removed.
+func blocksToCompact(entries *[]blockEntry, minInputBlocks, maxInputBlocks, maxCompactionObjects int, maxBlockBytes uint64) ([]*backend.BlockMeta, string) {
+	for len(*entries) > 0 {
+		var chosen []blockEntry
+		e := *entries
 
 		// find everything from cursor forward that belongs to this group
 		// Gather contiguous blocks while staying within limits
 		i := 0
-		for ; i < len(twbs.entries); i++ {
-			for j := i + 1; j < len(twbs.entries); j++ {
-				stripe := twbs.entries[i : j+1]
-
-				if twbs.entries[i].group == twbs.entries[j].group &&
-					twbs.entries[i].meta.DataEncoding == twbs.entries[j].meta.DataEncoding &&
-					twbs.entries[i].meta.Version == twbs.entries[j].meta.Version && // update after parquet: only compact blocks of the same version
-					twbs.entries[i].meta.DedicatedColumnsHash() == twbs.entries[j].meta.DedicatedColumnsHash() && // update after vParquet3: only compact blocks of the same dedicated columns
-					len(stripe) <= twbs.MaxInputBlocks &&
-					totalObjects(stripe) <= twbs.MaxCompactionObjects &&
-					totalSize(stripe) <= twbs.MaxBlockBytes {
+		for ; i < len(e); i++ {
+			for j := i + 1; j < len(e); j++ {
+				stripe := e[i : j+1]
+
+				if e[i].group == e[j].group &&
+					e[i].meta.DataEncoding == e[j].meta.DataEncoding &&
+					e[i].meta.Version == e[j].meta.Version && // update after parquet: only compact blocks of the same version
+					e[i].meta.DedicatedColumnsHash() == e[j].meta.DedicatedColumnsHash() && // update after vParquet3: only compact blocks of the same dedicated columns
+					e[i].meta.RetentionClass == e[j].meta.RetentionClass && // only compact blocks of the same retention class
+					len(stripe) <= maxInputBlocks &&
+					totalObjects(stripe) <= maxCompactionObjects &&
+					totalSize(stripe) <= maxBlockBytes {
 					chosen = stripe
 				} else {
 					break
@@ -146,10 +165,10 @@ func (twbs *timeWindowBlockSelector) BlocksToCompact() ([]*backend.BlockMeta, st
 		}
 
 		// Remove entries that were checked so they are not considered again.
-		twbs.entries = twbs.entries[i+len(chosen):]
+		*entries = e[i+len(chosen):]
 
 		// did we find enough blocks?
-		if len(chosen) >= twbs.MinInputBlocks {
+		if len(chosen) >= minInputBlocks {
 
 			compactBlocks := make([]*backend.BlockMeta, 0)
 			for _, e := range chosen {
@@ -162,7 +181,16 @@ func (twbs *timeWindowBlockSelector) BlocksToCompact() ([]*backend.BlockMeta, st
 	return nil, ""
 }
 
-func totalObjects(entries []timeWindowBlockEntry) int {
+// retentionClassGroup keeps blocks of different retention classes in separate groups, so compaction doesn't extend the
+// retention of traces by merging them into a block of a longer class. Blocks without a class keep their group.
+func retentionClassGroup(b *backend.BlockMeta) string {
+	if b.RetentionClass == "" {
+		return ""
+	}
+	return "-" + b.RetentionClass
+}
+
+func totalObjects(entries []blockEntry) int {
 	totalObjects := 0
 	for _, b := range entries {
 		totalObjects += int(b.meta.TotalObjects)
@@ -170,7 +198,7 @@ func totalObjects(entries []timeWindowBlockEntry) int {
 	return totalObjects
 }
 
-func totalSize(entries []timeWindowBlockEntry) uint64 {
+func totalSize(entries []blockEntry) uint64 {
 	sz := uint64(0)
 	for _, b := range entries {
 		sz += b.meta.Size_
@@ -185,3 +213,47 @@ func (twbs *timeWindowBlockSelector) windowForBlock(meta *backend.BlockMeta) int
 func (twbs *timeWindowBlockSelector) windowForTime(t time.Time) int64 {
 	return t.Unix() / int64(twbs.MaxCompactionRange/time.Second)
 }
+
+/*************************** Conversion Block Selector **************************/
+
+// The conversionBlockSelector returns the blocks of a version one at a time so they are rewritten in another
+// version, most recent blocks first because old blocks are removed by retention soonest. The remaining blocks are
+// selected by the next selector once all blocks have been returned.
+type conversionBlockSelector struct {
+	blocks []*backend.BlockMeta
+	next   CompactionBlockSelector
+}
+
+var _ (CompactionBlockSelector) = (*conversionBlockSelector)(nil)
+
+// NewConversionBlockSelector returns the blocks of the given version first. next is called with the other blocks.
+func NewConversionBlockSelector(blocklist []*backend.BlockMeta, version string, next func(blocklist []*backend.BlockMeta) CompactionBlockSelector) CompactionBlockSelector {
+	cbs := &conversionBlockSelector{}
+
+	rest := make([]*backend.BlockMeta, 0, len(blocklist))
+	for _, b := range blocklist {
+		if b.Version == version {
+			cbs.blocks = append(cbs.blocks, b)
+		} else {
+			rest = append(rest, b)
+		}
+	}
+	sort.SliceStable(cbs.blocks, func(i, j int) bool {
+		return cbs.blocks[i].EndTime.After(cbs.blocks[j].EndTime)
+	})
+
+	cbs.next = next(rest)
+
+	return cbs
+}
+
+func (cbs *conversionBlockSelector) BlocksToCompact() ([]*backend.BlockMeta, string) {
+	if len(cbs.blocks) == 0 {
+		return cbs.next.BlocksToCompact()
+	}
+
+	b := cbs.blocks[0]
+	cbs.blocks = cbs.blocks[1:]
+
+	return []*backend.BlockMeta{b}, fmt.Sprintf("%v-convert-%v", b.TenantID, b.BlockID)
+}
@@ -20,10 +20,12 @@ func TestTimeWindowBlockSelectorBlocksToCompact(t *testing.T) {
 		minInputBlocks int    // optional, defaults to global const
 		maxInputBlocks int    // optional, defaults to global const
 		maxBlockBytes  uint64 // optional, defaults to ???
-		expected       []*backend.BlockMeta
-		expectedHash   string
-		expectedSecond []*backend.BlockMeta
-		expectedHash2  string
+		// optional, defaults to unlimited
+		maxCompactionLevel uint32
+		expected           []*backend.BlockMeta
+		expectedHash       string
+		expectedSecond     []*backend.BlockMeta
+		expectedHash2      string
 	}{
 		{
 			name:      "nil - nil",
@@ -697,6 +699,45 @@ func TestTimeWindowBlockSelectorBlocksToCompact(t *testing.T) {
 			},
 			expectedHash2: fmt.Sprintf("%v-%v-%v-%v", tenantID, 0, now.Unix(), 0),
 		},
+		{
+			name: "blocks of different retention classes are not compacted together",
+			blocklist: []*backend.BlockMeta{
+				{
+					BlockID:        backend.MustParse("00000000-0000-0000-0000-000000000001"),
+					EndTime:        now,
+					RetentionClass: "dev",
+				},
+				{
+					BlockID:        backend.MustParse("00000000-0000-0000-0000-000000000002"),
+					EndTime:        now,
+					RetentionClass: "prod",
+				},
+				{
+					BlockID:        backend.MustParse("00000000-0000-0000-0000-000000000003"),
+					EndTime:        now,
+					RetentionClass: "dev",
+				},
+				{
+					BlockID: backend.MustParse("00000000-0000-0000-0000-000000000004"),
+					EndTime: now,
+				},
+			},
+			expected: []*backend.BlockMeta{
+				{
+					BlockID:        backend.MustParse("00000000-0000-0000-0000-000000000001"),
+					EndTime:        now,
+					RetentionClass: "dev",
+				},
+				{
+					BlockID:        backend.MustParse("00000000-0000-0000-0000-000000000003"),
+					EndTime:        now,
+					RetentionClass: "dev",
+				},
+			},
+			expectedHash:   fmt.Sprintf("%v-%v-%v-%v", tenantID, 0, now.Unix(), 0),
+			expectedSecond: nil,
+			expectedHash2:  "",
+		},
 		{
 			name: "blocks with different dedicated columns are not selected together",
 			blocklist: []*backend.BlockMeta{
@@ -815,6 +856,74 @@ func TestTimeWindowBlockSelectorBlocksToCompact(t *testing.T) {
 			},
 			expectedHash2: fmt.Sprintf("%v-%v-%v-%v", tenantID, 0, now.Unix(), 3),
 		},
+		{
+			name:               "blocks at max compaction level are skipped",
+			maxCompactionLevel: 2,
+			blocklist: []*backend.BlockMeta{
+				{
+					BlockID:         backend.MustParse("00000000-0000-0000-0000-000000000000"),
+					EndTime:         now,
+					CompactionLevel: 2,
+				},
+				{
+					BlockID:         backend.MustParse("00000000-0000-0000-0000-000000000001"),
+					EndTime:         now,
+					CompactionLevel: 2,
+				},
+				{
+					BlockID:         backend.MustParse("00000000-0000-0000-0000-000000000002"),
+					EndTime:         now,
+					CompactionLevel: 1,
+				},
+				{
+					BlockID:         backend.MustParse("00000000-0000-0000-0000-000000000003"),
+					EndTime:         now,
+					CompactionLevel: 1,
+				},
+			},
+			expected: []*backend.BlockMeta{
+				{
+					BlockID:         backend.MustParse("00000000-0000-0000-0000-000000000002"),
+					EndTime:         now,
+					CompactionLevel: 1,
+				},
+				{
+					BlockID:         backend.MustParse("00000000-0000-0000-0000-000000000003"),
+					EndTime:         now,
+					CompactionLevel: 1,
+				},
+			},
+			expectedHash: fmt.Sprintf("%v-%v-%v-%v", tenantID, 1, now.Unix(), 0),
+		},
+		{
+			name:               "max compaction level is ignored outside the active window",
+			maxCompactionLevel: 2,
+			blocklist: []*backend.BlockMeta{
+				{
+					BlockID:         backend.MustParse("00000000-0000-0000-0000-000000000000"),
+					EndTime:         now.Add(-activeWindowDuration - time.Minute),
+					CompactionLevel: 2,
+				},
+				{
+					BlockID:         backend.MustParse("00000000-0000-0000-0000-000000000001"),
+					EndTime:         now.Add(-activeWindowDuration - time.Minute),
+					CompactionLevel: 3,
+				},
+			},
+			expected: []*backend.BlockMeta{
+				{
+					BlockID:         backend.MustParse("00000000-0000-0000-0000-000000000000"),
+					EndTime:         now.Add(-activeWindowDuration - time.Minute),
+					CompactionLevel: 2,
+				},
+				{
+					BlockID:         backend.MustParse("00000000-0000-0000-0000-000000000001"),
+					EndTime:         now.Add(-activeWindowDuration - time.Minute),
+					CompactionLevel: 3,
+				},
+			},
+			expectedHash: fmt.Sprintf("%v-%v-%v", tenantID, now.Add(-activeWindowDuration-time.Minute).Unix(), 0),
+		},
 	}
 
 	for _, tt := range tests {
@@ -834,7 +943,7 @@ func TestTimeWindowBlockSelectorBlocksToCompact(t *testing.T) {
 				maxSize = tt.maxBlockBytes
 			}
 
-			selector := NewTimeWindowBlockSelector(tt.blocklist, time.Second, 100, maxSize, minBlocks, maxBlocks)
+			selector := NewTimeWindowBlockSelector(tt.blocklist, time.Second, 100, maxSize, minBlocks, maxBlocks, tt.maxCompactionLevel)
 
 			actual, hash := selector.BlocksToCompact()
 			assert.Equal(t, tt.expected, actual)
@@ -846,3 +955,34 @@ func TestTimeWindowBlockSelectorBlocksToCompact(t *testing.T) {
 		})
 	}
 }
+
+func TestConversionBlockSelectorBlocksToCompact(t *testing.T) {
+	now := time.Now()
+	tenantID := "tenant"
+
+	older := &backend.BlockMeta{TenantID: tenantID, BlockID: backend.MustParse("00000000-0000-0000-0000-000000000001"), Version: "v2", EndTime: now.Add(-time.Hour)}
+	newer := &backend.BlockMeta{TenantID: tenantID, BlockID: backend.MustParse("00000000-0000-0000-0000-000000000002"), Version: "v2", EndTime: now}
+	parquet1 := &backend.BlockMeta{TenantID: tenantID, BlockID: backend.MustParse("00000000-0000-0000-0000-000000000003"), Version: "vParquet4", EndTime: now}
+	parquet2 := &backend.BlockMeta{TenantID: tenantID, BlockID: backend.MustParse("00000000-0000-0000-0000-000000000004"), Version: "vParquet4", EndTime: now}
+
+	var rest []*backend.BlockMeta
+	selector := NewConversionBlockSelector([]*backend.BlockMeta{older, parquet1, newer, parquet2}, "v2", func(blocklist []*backend.BlockMeta) CompactionBlockSelector {
+		rest = blocklist
+		return NewTimeWindowBlockSelector(blocklist, time.Hour, 1000, 1024*1024*1024, DefaultMinInputBlocks, DefaultMaxInputBlocks, 0)
+	})
+	assert.Equal(t, []*backend.BlockMeta{parquet1, parquet2}, rest)
+
+	actual, hash := selector.BlocksToCompact()
+	assert.Equal(t, []*backend.BlockMeta{newer}, actual)
+	assert.Equal(t, fmt.Sprintf("%v-convert-%v", tenantID, newer.BlockID), hash)
+
+	actual, hash = selector.BlocksToCompact()
+	assert.Equal(t, []*backend.BlockMeta{older}, actual)
+	assert.Equal(t, fmt.Sprintf("%v-convert-%v", tenantID, older.BlockID), hash)
+
+	actual, _ = selector.BlocksToCompact()
+	assert.ElementsMatch(t, []*backend.BlockMeta{parquet1, parquet2}, actual)
+
+	actual, _ = selector.BlocksToCompact()
+	assert.Empty(t, actual)
+}
@@ -0,0 +1,86 @@
+package blockselector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// Built in compaction planners
+const (
+	// PlannerTimeWindow compacts blocks within the same time window. It is the default.
+	PlannerTimeWindow = "time_window"
+	// PlannerSizeTiered compacts blocks of similar size regardless of their time range.
+	PlannerSizeTiered = "size_tiered"
+)
+
+// PlannerOptions are the compactor limits passed to a CompactionPlanner.
+type PlannerOptions struct {
+	MaxCompactionRange   time.Duration
+	MaxCompactionObjects int
+	MaxBlockBytes        uint64
+	MinInputBlocks       int
+	MaxInputBlocks       int
+	MaxCompactionLevel   uint32
+}
+
+// CompactionPlanner creates the CompactionBlockSelector used for one compaction cycle of a tenant.
+type CompactionPlanner interface {
+	NewSelector(blocklist []*backend.BlockMeta, opts PlannerOptions) CompactionBlockSelector
+}
+
+// CompactionPlannerFunc adapts a function to a CompactionPlanner.
+type CompactionPlannerFunc func(blocklist []*backend.BlockMeta, opts PlannerOptions) CompactionBlockSelector
+
+func (f CompactionPlannerFunc) NewSelector(blocklist []*backend.BlockMeta, opts PlannerOptions) CompactionBlockSelector {
+	return f(blocklist, opts)
+}
+
+var (
+	plannersMtx sync.RWMutex
+	planners    = map[string]CompactionPlanner{
+		PlannerTimeWindow: CompactionPlannerFunc(func(blocklist []*backend.BlockMeta, opts PlannerOptions) CompactionBlockSelector {
+			return NewTimeWindowBlockSelector(blocklist, opts.MaxCompactionRange, opts.MaxCompactionObjects, opts.MaxBlockBytes, opts.MinInputBlocks, opts.MaxInputBlocks, opts.MaxCompactionLevel)
+		}),
+		PlannerSizeTiered: CompactionPlannerFunc(func(blocklist []*backend.BlockMeta, opts PlannerOptions) CompactionBlockSelector {
+			return NewSizeTieredBlockSelector(blocklist, opts.MaxCompactionObjects, opts.MaxBlockBytes, opts.MinInputBlocks, opts.MaxInputBlocks)
+		}),
+	}
+)
+
+// RegisterPlanner makes a CompactionPlanner available by name to the compaction_planner setting. It is meant to
+// be called from an init function and panics if the name is already registered.
+func RegisterPlanner(name string, p CompactionPlanner) {
+	plannersMtx.Lock()
+	defer plannersMtx.Unlock()
+
+	if _, ok := planners[name]; ok {
+		panic(fmt.Sprintf("compaction planner %q is already registered", name))
+	}
+	planners[name] = p
+}
+
+// Planner returns the CompactionPlanner registered under name. An empty name returns the time window planner.
+func Planner(name string) (CompactionPlanner, error) {
+	if name == "" {
+		name = PlannerTimeWindow
+	}
+
+	plannersMtx.RLock()
+	defer plannersMtx.RUnlock()
+
+	p, ok := planners[name]
+	if !ok {
+		names := make([]string, 0, len(planners))
+		for n := range planners {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown compaction planner %q, must be one of %s", name, strings.Join(names, ", "))
+	}
+	return p, nil
+}
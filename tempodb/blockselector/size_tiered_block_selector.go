@@ -0,0 +1,71 @@
+package blockselector
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+/*************************** Size Tiered Block Selector **************************/
+
+// sizeTierMinBytes is the upper bound of the smallest tier. Each following tier is sizeTierFactor times larger.
+const (
+	sizeTierMinBytes = 16 * 1024 * 1024
+	sizeTierFactor   = 4
+)
+
+// The sizeTieredBlockSelector groups blocks of similar size and ignores the time window and compaction level. It
+// is meant for historical backfill where many small blocks are written for time ranges that are long past the
+// active window and would otherwise only be compacted one window at a time.
+// Like the timeWindowBlockSelector it can be used ONLY ONCE and needs to be reinitialized with updated blocklist.
+type sizeTieredBlockSelector struct {
+	MinInputBlocks       int
+	MaxInputBlocks       int
+	MaxCompactionObjects int    // maximum size of compacted objects
+	MaxBlockBytes        uint64 // maximum block size, estimate
+
+	entries []blockEntry
+}
+
+var _ (CompactionBlockSelector) = (*sizeTieredBlockSelector)(nil)
+
+func NewSizeTieredBlockSelector(blocklist []*backend.BlockMeta, maxCompactionObjects int, maxBlockBytes uint64, minInputBlocks, maxInputBlocks int) CompactionBlockSelector {
+	stbs := &sizeTieredBlockSelector{
+		MinInputBlocks:       minInputBlocks,
+		MaxInputBlocks:       maxInputBlocks,
+		MaxCompactionObjects: maxCompactionObjects,
+		MaxBlockBytes:        maxBlockBytes,
+	}
+
+	for _, b := range blocklist {
+		tier := sizeTier(b.Size_)
+
+		stbs.entries = append(stbs.entries, blockEntry{
+			meta: b,
+			// Group by tier. Choose smallest tiers first.
+			group: fmt.Sprintf("%04X-%v", tier, b.ReplicationFactor) + retentionClassGroup(b),
+			// Within group keep blocks of the same version and dedicated columns together, then choose the
+			// oldest blocks first so that compacted blocks cover a narrow time range.
+			order: fmt.Sprintf("%v-%016X-%016X", b.Version, b.DedicatedColumnsHash(), b.StartTime.Unix()),
+			hash:  fmt.Sprintf("%v-%v-%v", b.TenantID, tier, b.ReplicationFactor),
+		})
+	}
+
+	sortEntries(stbs.entries)
+
+	return stbs
+}
+
+func (stbs *sizeTieredBlockSelector) BlocksToCompact() ([]*backend.BlockMeta, string) {
+	return blocksToCompact(&stbs.entries, stbs.MinInputBlocks, stbs.MaxInputBlocks, stbs.MaxCompactionObjects, stbs.MaxBlockBytes)
+}
+
+// sizeTier returns 0 for blocks up to sizeTierMinBytes and one more for every sizeTierFactor above that.
+func sizeTier(size uint64) int {
+	if size <= sizeTierMinBytes {
+		return 0
+	}
+	// log base 4 of the ratio, rounded up
+	return (bits.Len64((size-1)/sizeTierMinBytes) + 1) / 2
+}
@@ -0,0 +1,146 @@
+package blockselector
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestSizeTier(t *testing.T) {
+	tests := []struct {
+		size     uint64
+		expected int
+	}{
+		{size: 0, expected: 0},
+		{size: sizeTierMinBytes, expected: 0},
+		{size: sizeTierMinBytes + 1, expected: 1},
+		{size: 4 * sizeTierMinBytes, expected: 1},
+		{size: 4*sizeTierMinBytes + 1, expected: 2},
+		{size: 16 * sizeTierMinBytes, expected: 2},
+		{size: 64*sizeTierMinBytes + 1, expected: 4},
+	}
+
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%d", tc.size), func(t *testing.T) {
+			require.Equal(t, tc.expected, sizeTier(tc.size))
+		})
+	}
+}
+
+func TestSizeTieredBlockSelectorBlocksToCompact(t *testing.T) {
+	now := time.Now()
+	tenantID := "test"
+
+	block := func(id string, size uint64, start time.Time) *backend.BlockMeta {
+		return &backend.BlockMeta{
+			BlockID:   backend.MustParse(id),
+			TenantID:  tenantID,
+			Size_:     size,
+			StartTime: start,
+			EndTime:   start.Add(time.Minute),
+		}
+	}
+
+	var (
+		// small blocks spread over a month, as written by a backfill
+		small1 = block("00000000-0000-0000-0000-000000000001", 1024, now.Add(-30*24*time.Hour))
+		small2 = block("00000000-0000-0000-0000-000000000002", 2048, now.Add(-20*24*time.Hour))
+		small3 = block("00000000-0000-0000-0000-000000000003", 4096, now.Add(-10*24*time.Hour))
+		// blocks in a larger tier
+		large1 = block("00000000-0000-0000-0000-000000000004", 2*sizeTierMinBytes, now.Add(-2*time.Hour))
+		large2 = block("00000000-0000-0000-0000-000000000005", 3*sizeTierMinBytes, now.Add(-time.Hour))
+		// alone in its tier
+		huge = block("00000000-0000-0000-0000-000000000006", 100*sizeTierMinBytes, now)
+	)
+
+	tests := []struct {
+		name           string
+		blocklist      []*backend.BlockMeta
+		maxBlockBytes  uint64 // optional, defaults to unlimited
+		expected       []*backend.BlockMeta
+		expectedHash   string
+		expectedSecond []*backend.BlockMeta
+		expectedHash2  string
+	}{
+		{
+			name:      "nil - nil",
+			blocklist: nil,
+			expected:  nil,
+		},
+		{
+			name:      "only one",
+			blocklist: []*backend.BlockMeta{huge},
+			expected:  nil,
+		},
+		{
+			name:         "ignores time windows",
+			blocklist:    []*backend.BlockMeta{small3, small1, small2},
+			expected:     []*backend.BlockMeta{small1, small2, small3},
+			expectedHash: fmt.Sprintf("%v-%v-%v", tenantID, 0, 0),
+		},
+		{
+			name:           "smallest tier first",
+			blocklist:      []*backend.BlockMeta{huge, large2, small2, large1, small1},
+			expected:       []*backend.BlockMeta{small1, small2},
+			expectedHash:   fmt.Sprintf("%v-%v-%v", tenantID, 0, 0),
+			expectedSecond: []*backend.BlockMeta{large1, large2},
+			expectedHash2:  fmt.Sprintf("%v-%v-%v", tenantID, 1, 0),
+		},
+		{
+			name:          "honors max block bytes",
+			blocklist:     []*backend.BlockMeta{large1, large2, small1, small2},
+			maxBlockBytes: 4 * sizeTierMinBytes,
+			expected:      []*backend.BlockMeta{small1, small2},
+			expectedHash:  fmt.Sprintf("%v-%v-%v", tenantID, 0, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxBlockBytes := tt.maxBlockBytes
+			if maxBlockBytes == 0 {
+				maxBlockBytes = uint64(1024 * 1024 * 1024 * 1024)
+			}
+
+			selector := NewSizeTieredBlockSelector(tt.blocklist, 1000, maxBlockBytes, DefaultMinInputBlocks, DefaultMaxInputBlocks)
+
+			actual, hash := selector.BlocksToCompact()
+			require.Equal(t, tt.expected, actual)
+			require.Equal(t, tt.expectedHash, hash)
+
+			actual, hash = selector.BlocksToCompact()
+			require.Equal(t, tt.expectedSecond, actual)
+			require.Equal(t, tt.expectedHash2, hash)
+		})
+	}
+}
+
+func TestPlanner(t *testing.T) {
+	p, err := Planner("")
+	require.NoError(t, err)
+	require.IsType(t, &timeWindowBlockSelector{}, p.NewSelector(nil, PlannerOptions{MaxCompactionRange: time.Hour}))
+
+	p, err = Planner(PlannerSizeTiered)
+	require.NoError(t, err)
+	require.IsType(t, &sizeTieredBlockSelector{}, p.NewSelector(nil, PlannerOptions{MaxCompactionRange: time.Hour}))
+
+	_, err = Planner("level")
+	require.EqualError(t, err, `unknown compaction planner "level", must be one of size_tiered, time_window`)
+
+	t.Cleanup(func() {
+		plannersMtx.Lock()
+		delete(planners, "level")
+		plannersMtx.Unlock()
+	})
+	RegisterPlanner("level", CompactionPlannerFunc(func(blocklist []*backend.BlockMeta, opts PlannerOptions) CompactionBlockSelector {
+		return NewSizeTieredBlockSelector(blocklist, opts.MaxCompactionObjects, opts.MaxBlockBytes, opts.MinInputBlocks, opts.MaxInputBlocks)
+	}))
+	_, err = Planner("level")
+	require.NoError(t, err)
+
+	require.Panics(t, func() { RegisterPlanner(PlannerTimeWindow, nil) })
+}
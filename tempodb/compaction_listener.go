@@ -0,0 +1,252 @@
+package tempodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	gkLog "github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const (
+	// RetentionActionMarked is reported when retention marks a block compacted because it is past its retention.
+	RetentionActionMarked = "marked"
+	// RetentionActionDeleted is reported when retention deletes a compacted block.
+	RetentionActionDeleted = "deleted"
+	// RetentionActionTrashed is reported when retention moves a compacted block to the trash.
+	RetentionActionTrashed = "trashed"
+	// RetentionActionPurged is reported when retention deletes a block from the trash.
+	RetentionActionPurged = "purged"
+
+	webhookResultSent    = "sent"
+	webhookResultFailed  = "failed"
+	webhookResultDropped = "dropped"
+
+	webhookEventCompaction = "compaction"
+	webhookEventRetention  = "retention"
+)
+
+var metricCompactionWebhookNotifications = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempodb",
+	Name:      "compaction_webhook_notifications_total",
+	Help:      "Total number of compaction and retention notifications by webhook result.",
+}, []string{"result"})
+
+// CompactionEvent describes a finished compaction job.
+type CompactionEvent struct {
+	TenantID    string               `json:"tenantID"`
+	Inputs      []*backend.BlockMeta `json:"inputs"`
+	Outputs     []*backend.BlockMeta `json:"outputs"`
+	InputBytes  uint64               `json:"inputBytes"`
+	OutputBytes uint64               `json:"outputBytes"`
+	// Duration is encoded in nanoseconds.
+	Duration time.Duration `json:"duration"`
+}
+
+// RetentionEvent describes a block removed by retention.
+type RetentionEvent struct {
+	TenantID string       `json:"tenantID"`
+	BlockID  backend.UUID `json:"blockID"`
+	// Action is one of RetentionActionMarked, RetentionActionDeleted, RetentionActionTrashed or RetentionActionPurged.
+	Action string `json:"action"`
+	// Bytes is the size of the block, 0 for blocks purged from the trash.
+	Bytes          uint64 `json:"bytes"`
+	RetentionClass string `json:"retentionClass,omitempty"`
+}
+
+// CompactionListener is notified of finished compactions and of the blocks removed by retention. Listeners are
+// called synchronously from the compaction and retention loops and must not block.
+type CompactionListener interface {
+	AfterCompaction(ctx context.Context, e CompactionEvent)
+	AfterRetention(ctx context.Context, e RetentionEvent)
+}
+
+// CompactionWebhookConfig configures the webhook that compaction and retention events are posted to.
+type CompactionWebhookConfig struct {
+	// Endpoint is the URL events are posted to as JSON. Empty disables the webhook.
+	Endpoint string        `yaml:"endpoint"`
+	Timeout  time.Duration `yaml:"timeout"`
+	// QueueSize is the number of events waiting to be sent. Events are dropped when the queue is full.
+	QueueSize int `yaml:"queue_size"`
+}
+
+func (cfg *CompactionWebhookConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.Endpoint, util.PrefixConfig(prefix, "webhook.endpoint"), "", "URL compaction and retention events are posted to. Empty disables the webhook.")
+	cfg.Timeout = 5 * time.Second
+	cfg.QueueSize = 1000
+}
+
+// Enabled returns true if events are posted to an endpoint.
+func (cfg *CompactionWebhookConfig) Enabled() bool {
+	return cfg.Endpoint != ""
+}
+
+func (cfg *CompactionWebhookConfig) validate() error {
+	if !cfg.Enabled() {
+		return nil
+	}
+	if cfg.QueueSize <= 0 {
+		return errors.New("compaction webhook queue size must be greater than 0")
+	}
+	return nil
+}
+
+type webhookPayload struct {
+	Type       string           `json:"type"`
+	Compaction *CompactionEvent `json:"compaction,omitempty"`
+	Retention  *RetentionEvent  `json:"retention,omitempty"`
+}
+
+// CompactionWebhook is a CompactionListener that posts events to an HTTP endpoint. Events are queued and sent in the
+// background, one at a time, in the order they happened. Failed events are not retried.
+type CompactionWebhook struct {
+	endpoint string
+	client   *http.Client
+	logger   gkLog.Logger
+
+	queue    chan webhookPayload
+	done     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+var _ CompactionListener = (*CompactionWebhook)(nil)
+
+// NewCompactionWebhook starts sending events to the endpoint of the config. Stop must be called to stop sending.
+func NewCompactionWebhook(cfg CompactionWebhookConfig, logger gkLog.Logger) *CompactionWebhook {
+	w := &CompactionWebhook{
+		endpoint: cfg.Endpoint,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		logger:   logger,
+		queue:    make(chan webhookPayload, cfg.QueueSize),
+		done:     make(chan struct{}),
+	}
+
+	w.wg.Add(1)
+	go w.run()
+
+	return w
+}
+
+// AfterCompaction implements CompactionListener
+func (w *CompactionWebhook) AfterCompaction(_ context.Context, e CompactionEvent) {
+	w.enqueue(webhookPayload{Type: webhookEventCompaction, Compaction: &e})
+}
+
+// AfterRetention implements CompactionListener
+func (w *CompactionWebhook) AfterRetention(_ context.Context, e RetentionEvent) {
+	w.enqueue(webhookPayload{Type: webhookEventRetention, Retention: &e})
+}
+
+func (w *CompactionWebhook) enqueue(p webhookPayload) {
+	select {
+	case w.queue <- p:
+	default:
+		metricCompactionWebhookNotifications.WithLabelValues(webhookResultDropped).Inc()
+	}
+}
+
+func (w *CompactionWebhook) run() {
+	defer w.wg.Done()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case p := <-w.queue:
+			if err := w.send(p); err != nil {
+				level.Warn(w.logger).Log("msg", "failed to send compaction webhook", "type", p.Type, "err", err)
+				metricCompactionWebhookNotifications.WithLabelValues(webhookResultFailed).Inc()
+				continue
+			}
+			metricCompactionWebhookNotifications.WithLabelValues(webhookResultSent).Inc()
+		}
+	}
+}
+
+func (w *CompactionWebhook) send(p webhookPayload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Stop stops sending events. Events still in the queue are dropped.
+func (w *CompactionWebhook) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.done)
+		w.wg.Wait()
+	})
+}
+
+func (rw *readerWriter) AddCompactionListener(l CompactionListener) {
+	rw.compactionListeners = append(rw.compactionListeners, l)
+}
+
+func (rw *readerWriter) notifyCompaction(ctx context.Context, tenantID string, inputs, outputs []*backend.BlockMeta, start time.Time) {
+	if len(rw.compactionListeners) == 0 {
+		return
+	}
+
+	e := CompactionEvent{
+		TenantID: tenantID,
+		Inputs:   inputs,
+		Outputs:  outputs,
+		Duration: time.Since(start),
+	}
+	for _, m := range inputs {
+		e.InputBytes += m.Size_
+	}
+	for _, m := range outputs {
+		e.OutputBytes += m.Size_
+	}
+
+	for _, l := range rw.compactionListeners {
+		l.AfterCompaction(ctx, e)
+	}
+}
+
+func (rw *readerWriter) notifyRetention(ctx context.Context, tenantID string, blockID backend.UUID, action string, meta *backend.BlockMeta) {
+	e := RetentionEvent{
+		TenantID: tenantID,
+		BlockID:  blockID,
+		Action:   action,
+	}
+	if meta != nil {
+		e.Bytes = meta.Size_
+		e.RetentionClass = meta.RetentionClass
+	}
+
+	for _, l := range rw.compactionListeners {
+		l.AfterRetention(ctx, e)
+	}
+}
@@ -0,0 +1,199 @@
+package tempodb
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/pool"
+	"github.com/grafana/tempo/tempodb/wal"
+)
+
+type testCompactionListener struct {
+	mtx         sync.Mutex
+	compactions []CompactionEvent
+	retentions  []RetentionEvent
+}
+
+func (l *testCompactionListener) AfterCompaction(_ context.Context, e CompactionEvent) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.compactions = append(l.compactions, e)
+}
+
+func (l *testCompactionListener) AfterRetention(_ context.Context, e RetentionEvent) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.retentions = append(l.retentions, e)
+}
+
+func TestCompactionListener(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Pool: &pool.Config{
+			MaxWorkers: 10,
+			QueueDepth: 100,
+		},
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 11,
+			BloomFP:              .01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncNone,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	listener := &testCompactionListener{}
+	c.AddCompactionListener(listener)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      24 * time.Hour,
+		BlockRetention:          time.Hour,
+		CompactedBlockRetention: time.Hour,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{}, true)
+
+	blockCount := 3
+	cutTestBlocks(t, w, testTenantID, blockCount, 1)
+
+	rw := r.(*readerWriter)
+	rw.pollBlocklist(ctx)
+
+	inputs := rw.blocklist.Metas(testTenantID)
+	err = rw.compactOneJob(ctx, inputs, testTenantID)
+	require.NoError(t, err)
+
+	outputs := rw.blocklist.Metas(testTenantID)
+	require.Len(t, outputs, 1)
+
+	require.Len(t, listener.compactions, 1)
+	e := listener.compactions[0]
+	require.Equal(t, testTenantID, e.TenantID)
+	require.ElementsMatch(t, inputs, e.Inputs)
+	require.Equal(t, outputs, e.Outputs)
+	var inputBytes uint64
+	for _, m := range inputs {
+		inputBytes += m.Size_
+	}
+	require.Equal(t, inputBytes, e.InputBytes)
+	require.Equal(t, outputs[0].Size_, e.OutputBytes)
+	require.Greater(t, e.Duration, time.Duration(0))
+
+	// retention marks the output and then deletes it with the compacted inputs
+	rw.compactorCfg.BlockRetention = 0
+	rw.compactorCfg.CompactedBlockRetention = 0
+	rw.doRetention(ctx)
+
+	actions := map[backend.UUID][]string{}
+	for _, e := range listener.retentions {
+		require.Equal(t, testTenantID, e.TenantID)
+		actions[e.BlockID] = append(actions[e.BlockID], e.Action)
+	}
+	expected := map[backend.UUID][]string{outputs[0].BlockID: {RetentionActionMarked, RetentionActionDeleted}}
+	for _, m := range inputs {
+		expected[m.BlockID] = []string{RetentionActionDeleted}
+	}
+	require.Equal(t, expected, actions)
+}
+
+func TestCompactionWebhook(t *testing.T) {
+	payloads := make(chan webhookPayload, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var p webhookPayload
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&p))
+		payloads <- p
+
+		if p.Type == webhookEventRetention {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	cfg := CompactionWebhookConfig{}
+	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+	cfg.Endpoint = server.URL
+
+	sent := testutil.ToFloat64(metricCompactionWebhookNotifications.WithLabelValues(webhookResultSent))
+	failed := testutil.ToFloat64(metricCompactionWebhookNotifications.WithLabelValues(webhookResultFailed))
+
+	webhook := NewCompactionWebhook(cfg, log.NewNopLogger())
+	defer webhook.Stop()
+
+	blockID := backend.NewUUID()
+	webhook.AfterCompaction(context.Background(), CompactionEvent{
+		TenantID:    testTenantID,
+		Inputs:      []*backend.BlockMeta{{BlockID: blockID, TenantID: testTenantID}},
+		InputBytes:  10,
+		OutputBytes: 5,
+		Duration:    time.Second,
+	})
+	webhook.AfterRetention(context.Background(), RetentionEvent{
+		TenantID: testTenantID,
+		BlockID:  blockID,
+		Action:   RetentionActionDeleted,
+		Bytes:    10,
+	})
+
+	p := <-payloads
+	require.Equal(t, webhookEventCompaction, p.Type)
+	require.Nil(t, p.Retention)
+	require.Equal(t, testTenantID, p.Compaction.TenantID)
+	require.Equal(t, blockID, p.Compaction.Inputs[0].BlockID)
+	require.Equal(t, uint64(10), p.Compaction.InputBytes)
+	require.Equal(t, uint64(5), p.Compaction.OutputBytes)
+	require.Equal(t, time.Second, p.Compaction.Duration)
+
+	p = <-payloads
+	require.Equal(t, webhookEventRetention, p.Type)
+	require.Nil(t, p.Compaction)
+	require.Equal(t, RetentionEvent{TenantID: testTenantID, BlockID: blockID, Action: RetentionActionDeleted, Bytes: 10}, *p.Retention)
+
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(metricCompactionWebhookNotifications.WithLabelValues(webhookResultSent)) == sent+1 &&
+			testutil.ToFloat64(metricCompactionWebhookNotifications.WithLabelValues(webhookResultFailed)) == failed+1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestCompactionWebhookDropsWhenFull(t *testing.T) {
+	cfg := CompactionWebhookConfig{Endpoint: "http://localhost", QueueSize: 1}
+
+	// the webhook isn't started so the queue isn't consumed
+	webhook := &CompactionWebhook{queue: make(chan webhookPayload, cfg.QueueSize)}
+
+	dropped := testutil.ToFloat64(metricCompactionWebhookNotifications.WithLabelValues(webhookResultDropped))
+	webhook.AfterRetention(context.Background(), RetentionEvent{})
+	webhook.AfterRetention(context.Background(), RetentionEvent{})
+	require.Equal(t, dropped+1, testutil.ToFloat64(metricCompactionWebhookNotifications.WithLabelValues(webhookResultDropped)))
+}
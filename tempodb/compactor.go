@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/tempo/tempodb/blockselector"
 	"github.com/grafana/tempo/tempodb/encoding"
 	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
 )
 
 const (
@@ -72,6 +73,21 @@ var (
 		Name:      "compaction_spans_combined_total",
 		Help:      "Number of spans that are deduped per replication factor.",
 	}, []string{"replication_factor"})
+	metricCompactionDuplicateTraces = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_duplicate_traces_total",
+		Help:      "Total number of traces found in several blocks whose duplicates were merged during compaction.",
+	}, []string{"tenant"})
+	metricCompactionDedupedBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_deduped_bytes_total",
+		Help:      "Estimated number of bytes saved by merging duplicate traces during compaction.",
+	}, []string{"tenant"})
+	metricAttributePolicyDroppedBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_attribute_policy_dropped_bytes_total",
+		Help:      "Total number of attribute bytes dropped during compaction by the tenant's storage attribute policy.",
+	}, []string{"tenant"})
 
 	errCompactionJobNoLongerOwned = fmt.Errorf("compaction job no longer owned")
 )
@@ -116,8 +132,16 @@ func (rw *readerWriter) compactOneTenant(ctx context.Context) {
 		return
 	}
 
-	// Get the meta file of all non-compacted blocks for the given tenant
-	blocklist := rw.blocklist.Metas(tenantID)
+	// Get the meta file of all non-compacted blocks for the given tenant. Archived blocks can't be read so they
+	// aren't compacted and the blocks of federated buckets are never written to.
+	archiveAfter, _ := rw.compactorOverrides.BlockArchiveForTenant(tenantID)
+	now := time.Now()
+	var blocklist []*backend.BlockMeta
+	for _, b := range rw.blocklist.Metas(tenantID) {
+		if !backend.IsBlockArchived(b, archiveAfter, now) && !b.IsFederated() {
+			blocklist = append(blocklist, b)
+		}
+	}
 
 	window := rw.compactorOverrides.MaxCompactionRangeForTenant(tenantID)
 	if window == 0 {
@@ -131,14 +155,36 @@ func (rw *readerWriter) compactOneTenant(ctx context.Context) {
 	//   Favoring lower compaction levels, and compacting blocks only from the same tenant.
 	//  2. If blocks are outside the active window, they're grouped only by windows, ignoring compaction level.
 	//   It picks more recent windows first, and compacting blocks only from the same tenant.
-	blockSelector := blockselector.NewTimeWindowBlockSelector(blocklist,
-		window,
-		rw.compactorCfg.MaxCompactionObjects,
-		rw.compactorCfg.MaxBlockBytes,
-		blockselector.DefaultMinInputBlocks,
-		blockselector.DefaultMaxInputBlocks)
+	//  Inside the active window blocks that reached max_compaction_level are not compacted again.
+	//
+	// This is the default time_window planner. Other planners can be chosen with compaction_planner.
+	planner, err := blockselector.Planner(rw.compactorCfg.CompactionPlanner)
+	if err != nil {
+		level.Error(rw.logger).Log("msg", "failed to get compaction planner", "err", err)
+		return
+	}
+	opts := blockselector.PlannerOptions{
+		MaxCompactionRange:   window,
+		MaxCompactionObjects: rw.compactorCfg.MaxCompactionObjects,
+		MaxBlockBytes:        rw.compactorCfg.MaxBlockBytes,
+		MinInputBlocks:       blockselector.DefaultMinInputBlocks,
+		MaxInputBlocks:       blockselector.DefaultMaxInputBlocks,
+		MaxCompactionLevel:   rw.compactorCfg.MaxCompactionLevel,
+	}
+	// v2 blocks of tenants with convert_v2_blocks are converted one at a time before any other compaction
+	blockSelector := rw.newConversionBlockSelector(tenantID, blocklist, func(rest []*backend.BlockMeta) blockselector.CompactionBlockSelector {
+		return planner.NewSelector(rest, opts)
+	})
 
 	start := time.Now()
+	ownedJobs, otherJobs := 0, 0
+	defer func() {
+		rw.updateCompactionOwnership(tenantID, func(c *TenantCompactionOwnership) {
+			c.LastCycle = start
+			c.OwnedJobs = ownedJobs
+			c.OtherJobs = otherJobs
+		})
+	}()
 
 	level.Info(rw.logger).Log("msg", "starting compaction cycle", "tenantID", tenantID, "offset", rw.compactorTenantOffset)
 	for {
@@ -162,8 +208,10 @@ func (rw *readerWriter) compactOneTenant(ctx context.Context) {
 		}
 		if !owns() {
 			// continue on this tenant until we find something we own
+			otherJobs++
 			continue
 		}
+		ownedJobs++
 
 		level.Info(rw.logger).Log("msg", "Compacting hash", "hashString", hashString)
 		err := rw.compactWhileOwns(ctx, toBeCompacted, tenantID, owns)
@@ -173,6 +221,8 @@ func (rw *readerWriter) compactOneTenant(ctx context.Context) {
 		} else if err != nil {
 			level.Error(rw.logger).Log("msg", "error during compaction cycle", "err", err)
 			metricCompactionErrors.Inc()
+		} else {
+			rw.updateCompactionOwnership(tenantID, func(c *TenantCompactionOwnership) { c.LastCompaction = time.Now() })
 		}
 
 		// after a maintenance cycle bail out
@@ -216,6 +266,7 @@ func (rw *readerWriter) compactWhileOwns(ctx context.Context, blockMetas []*back
 	err := rw.compactOneJob(ownsCtx, blockMetas, tenantID)
 	if errors.Is(err, context.Canceled) && errors.Is(context.Cause(ownsCtx), errCompactionJobNoLongerOwned) {
 		level.Warn(rw.logger).Log("msg", "lost ownership of this job. abandoning job and trying again on this block list", "err", err)
+		rw.updateCompactionOwnership(tenantID, func(c *TenantCompactionOwnership) { c.Conflicts++ })
 		return nil
 	}
 
@@ -230,6 +281,7 @@ func (rw *readerWriter) compactWhileOwns(ctx context.Context, blockMetas []*back
 		}
 
 		level.Error(rw.logger).Log("msg", "lost ownership of this job after compaction. possible data duplication", "tenant", tenantID, "input_blocks", sb.String())
+		rw.updateCompactionOwnership(tenantID, func(c *TenantCompactionOwnership) { c.Conflicts++ })
 	}
 
 	return err
@@ -242,6 +294,7 @@ func (rw *readerWriter) compactOneJob(ctx context.Context, blockMetas []*backend
 
 func (rw *readerWriter) CompactWithConfig(ctx context.Context, blockMetas []*backend.BlockMeta, tenantID string, compactorCfg *CompactorConfig, compactorSharder CompactorSharder, compactorOverrides CompactorOverrides) ([]*backend.BlockMeta, error) {
 	level.Debug(rw.logger).Log("msg", "beginning compaction", "num blocks compacting", len(blockMetas))
+	compactorOverrides = withPartitionOverrides(compactorOverrides)
 
 	// todo - add timeout?
 	ctx, span := tracer.Start(ctx, "rw.compact")
@@ -286,7 +339,22 @@ func (rw *readerWriter) CompactWithConfig(ctx context.Context, blockMetas []*bac
 		}
 	}
 
-	enc, err := encoding.FromVersion(blockMetas[0].Version)
+	// compacted blocks are written in the block version of the tenant, blocks of other versions are converted first
+	version := blockVersionForTenant(rw.cfg.Block, tenantID, compactorOverrides)
+	if blockMetas[0].Version != version {
+		if rw.convertsBlocks(blockMetas[0].Version, tenantID, compactorOverrides) {
+			newBlocks, err := rw.convertBlocks(ctx, blockMetas, tenantID, compactorCfg, compactorOverrides)
+			if err != nil {
+				return nil, err
+			}
+			recordBlocksWritten(blocksWrittenSourceCompaction, newBlocks...)
+			rw.notifyCompaction(ctx, tenantID, blockMetas, newBlocks, startTime)
+			return newBlocks, nil
+		}
+		version = blockMetas[0].Version
+	}
+
+	enc, err := encoding.FromVersion(version)
 	if err != nil {
 		return nil, err
 	}
@@ -294,17 +362,29 @@ func (rw *readerWriter) CompactWithConfig(ctx context.Context, blockMetas []*bac
 	compactionLevel := CompactionLevelForBlocks(blockMetas)
 	compactionLevelLabel := strconv.Itoa(int(compactionLevel))
 
+	var reports *dedupeReports
+	if compactorCfg.DedupeReport {
+		reports = newDedupeReports(tenantID, blockMetas)
+	}
+
 	combiner := instrumentedObjectCombiner{
 		tenant:               tenantID,
 		inner:                compactorSharder,
 		compactionLevelLabel: compactionLevelLabel,
 	}
 
+	// the recommended dedicated columns are applied to the blocks written by the compaction
+	var dedicatedColumns backend.DedicatedColumns
+	if version == vparquet4.VersionString && compactorOverrides.DedicatedColumnsAutoApplyForTenant(tenantID) {
+		dedicatedColumns = rw.recommendedDedicatedColumns(ctx, tenantID)
+	}
+
 	opts := common.CompactionOptions{
-		BlockConfig:        *rw.cfg.Block,
+		BlockConfig:        compactorOverrides.BlockEncodingForTenant(tenantID).ApplyTo(*rw.cfg.Block),
 		ChunkSizeBytes:     compactorCfg.ChunkSizeBytes,
 		FlushSizeBytes:     compactorCfg.FlushSizeBytes,
 		IteratorBufferSize: compactorCfg.IteratorBufferSize,
+		ReadAheadChunks:    compactorCfg.ReadAheadChunks,
 		OutputBlocks:       outputBlocks,
 		Combiner:           combiner,
 		MaxBytesPerTrace:   compactorOverrides.MaxBytesPerTraceForTenant(tenantID),
@@ -329,6 +409,19 @@ func (rw *readerWriter) CompactWithConfig(ctx context.Context, blockMetas []*bac
 		DedupedSpans: func(replFactor, dedupedSpans int) {
 			metricDedupedSpans.WithLabelValues(strconv.Itoa(replFactor)).Add(float64(dedupedSpans))
 		},
+		AttributeFilter:  common.NewAttributeFilter(compactorOverrides.StorageAttributePolicyForTenant(tenantID)),
+		DedicatedColumns: dedicatedColumns,
+		RetentionClass:   retentionClassForBlocks(blockMetas, retentionClassesForTenant(tenantID, compactorCfg, compactorOverrides)),
+		AttributesDropped: func(bytes int) {
+			metricAttributePolicyDroppedBytes.WithLabelValues(tenantID).Add(float64(bytes))
+		},
+		TraceDeduped: func(block backend.UUID, traceID common.ID, duplicates, dedupedBytes int) {
+			metricCompactionDuplicateTraces.WithLabelValues(tenantID).Inc()
+			metricCompactionDedupedBytes.WithLabelValues(tenantID).Add(float64(dedupedBytes))
+			if reports != nil {
+				reports.traceDeduped(block, traceID, duplicates, dedupedBytes)
+			}
+		},
 	}
 
 	compactor := enc.NewCompactor(opts)
@@ -339,12 +432,21 @@ func (rw *readerWriter) CompactWithConfig(ctx context.Context, blockMetas []*bac
 		return nil, err
 	}
 
+	if reports != nil {
+		// the report is informational, failing to write it doesn't fail the compaction
+		if err := reports.write(ctx, rw.w, newCompactedBlocks); err != nil {
+			level.Warn(rw.logger).Log("msg", "failed to write dedupe report", "tenant", tenantID, "err", err)
+		}
+	}
+
 	// mark old blocks compacted, so they don't show up in polling
 	if err := markCompacted(rw, tenantID, blockMetas, newCompactedBlocks); err != nil {
 		return nil, err
 	}
 
 	metricCompactionBlocks.WithLabelValues(compactionLevelLabel).Add(float64(len(blockMetas)))
+	recordBlocksWritten(blocksWrittenSourceCompaction, newCompactedBlocks...)
+	rw.notifyCompaction(ctx, tenantID, blockMetas, newCompactedBlocks, startTime)
 
 	logArgs := []interface{}{
 		"msg",
@@ -424,6 +526,16 @@ func MeasureOutstandingBlocks(tenantID string, blockSelector blockselector.Compa
 	metricCompactionOutstandingBlocks.WithLabelValues(tenantID).Set(float64(totalOutstandingBlocks))
 }
 
+// retentionClassForBlocks returns the retention class of the block compacted from the given blocks, the class of
+// the input with the longest retention.
+func retentionClassForBlocks(blockMetas []*backend.BlockMeta, classes *RetentionClasses) string {
+	inputs := make([]string, 0, len(blockMetas))
+	for _, m := range blockMetas {
+		inputs = append(inputs, m.RetentionClass)
+	}
+	return classes.BlockClass(inputs...)
+}
+
 func CompactionLevelForBlocks(blockMetas []*backend.BlockMeta) uint8 {
 	level := uint8(0)
 
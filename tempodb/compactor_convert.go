@@ -0,0 +1,155 @@
+package tempodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/blockselector"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	v2 "github.com/grafana/tempo/tempodb/encoding/v2"
+)
+
+var (
+	metricConversionBlocks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_converted_blocks_total",
+		Help:      "Total number of blocks converted to the block version of their tenant.",
+	}, []string{"tenant"})
+	metricConversionBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_converted_bytes_total",
+		Help:      "Total number of bytes of blocks converted to the block version of their tenant.",
+	}, []string{"tenant"})
+	metricConversionErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_conversion_errors_total",
+		Help:      "Total number of errors converting blocks to the block version of their tenant.",
+	}, []string{"tenant"})
+)
+
+// convertsV2Blocks returns true if v2 blocks of the tenant are converted instead of compacted.
+func (rw *readerWriter) convertsV2Blocks(tenantID string, compactorOverrides CompactorOverrides) bool {
+	return blockVersionForTenant(rw.cfg.Block, tenantID, compactorOverrides) != v2.VersionString && compactorOverrides.ConvertV2BlocksForTenant(tenantID)
+}
+
+// convertsBlocks returns true if blocks of the version are converted to the block version of the tenant instead of
+// compacted. v2 blocks are only converted if enabled for the tenant, and blocks are never converted to v2.
+func (rw *readerWriter) convertsBlocks(version, tenantID string, compactorOverrides CompactorOverrides) bool {
+	if version == v2.VersionString {
+		return rw.convertsV2Blocks(tenantID, compactorOverrides)
+	}
+	tenantVersion := blockVersionForTenant(rw.cfg.Block, tenantID, compactorOverrides)
+	return version != tenantVersion && tenantVersion != v2.VersionString
+}
+
+// blockVersionForTenant returns the version of the blocks created for the tenant, the configured block version
+// unless the tenant is pinned to another one.
+func blockVersionForTenant(cfg *common.BlockConfig, tenantID string, compactorOverrides CompactorOverrides) string {
+	return compactorOverrides.BlockEncodingForTenant(tenantID).ApplyTo(*cfg).Version
+}
+
+// newConversionBlockSelector puts the v2 blocks of the tenant ahead of the blocks selected by next when they are
+// converted.
+func (rw *readerWriter) newConversionBlockSelector(tenantID string, blocklist []*backend.BlockMeta, next func([]*backend.BlockMeta) blockselector.CompactionBlockSelector) blockselector.CompactionBlockSelector {
+	if !rw.convertsV2Blocks(tenantID, rw.compactorOverrides) {
+		return next(blocklist)
+	}
+	return blockselector.NewConversionBlockSelector(blocklist, v2.VersionString, next)
+}
+
+// convertBlocks rewrites each block in the block version of the tenant. Blocks are converted one to one and keep
+// their compaction level, they are compacted with other blocks of the new version in later cycles.
+func (rw *readerWriter) convertBlocks(ctx context.Context, blockMetas []*backend.BlockMeta, tenantID string, compactorCfg *CompactorConfig, compactorOverrides CompactorOverrides) ([]*backend.BlockMeta, error) {
+	blockCfg := compactorOverrides.BlockEncodingForTenant(tenantID).ApplyTo(*rw.cfg.Block)
+
+	to, err := encoding.FromVersion(blockCfg.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	converted := make([]*backend.BlockMeta, 0, len(blockMetas))
+	for _, meta := range blockMetas {
+		start := time.Now()
+
+		newMeta, err := rw.convertBlock(ctx, meta, to, &blockCfg, compactorCfg, compactorOverrides.DedicatedColumnsForTenant(tenantID))
+		if err != nil {
+			metricConversionErrors.WithLabelValues(tenantID).Inc()
+			return nil, fmt.Errorf("error converting block %s: %w", meta.BlockID, err)
+		}
+		converted = append(converted, newMeta)
+
+		metricConversionBlocks.WithLabelValues(tenantID).Inc()
+		metricConversionBytes.WithLabelValues(tenantID).Add(float64(meta.Size_))
+
+		level.Info(rw.logger).Log(
+			"msg", "converted block",
+			"tenantID", tenantID,
+			"blockID", meta.BlockID.String(),
+			"newBlockID", newMeta.BlockID.String(),
+			"version", newMeta.Version,
+			"elapsed", time.Since(start),
+		)
+	}
+
+	if err := markCompacted(rw, tenantID, blockMetas, converted); err != nil {
+		return nil, err
+	}
+
+	return converted, nil
+}
+
+func (rw *readerWriter) convertBlock(ctx context.Context, meta *backend.BlockMeta, to encoding.VersionedEncoding, blockCfg *common.BlockConfig, compactorCfg *CompactorConfig, dedicatedColumns backend.DedicatedColumns) (*backend.BlockMeta, error) {
+	iter, err := rw.blockTraceIterator(ctx, meta, compactorCfg)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	newMeta := backend.NewBlockMetaWithDedicatedColumns(meta.TenantID, uuid.New(), to.Version(), blockCfg.Encoding, meta.DataEncoding, dedicatedColumns)
+	newMeta.StartTime = meta.StartTime
+	newMeta.EndTime = meta.EndTime
+	newMeta.TotalObjects = meta.TotalObjects
+	newMeta.CompactionLevel = meta.CompactionLevel
+	newMeta.ReplicationFactor = meta.ReplicationFactor
+	newMeta.RetentionClass = meta.RetentionClass
+
+	return to.CreateBlock(ctx, blockCfg, newMeta, iter, rw.r, rw.w)
+}
+
+// traceIterableBlock is a block whose traces can be iterated to convert it to another version.
+type traceIterableBlock interface {
+	TraceIterator(ctx context.Context) (common.Iterator, error)
+}
+
+// blockTraceIterator returns the traces of the block ordered by ID.
+func (rw *readerWriter) blockTraceIterator(ctx context.Context, meta *backend.BlockMeta, compactorCfg *CompactorConfig) (common.Iterator, error) {
+	if meta.Version == v2.VersionString {
+		block, err := v2.NewBackendBlock(meta, rw.r)
+		if err != nil {
+			return nil, err
+		}
+		return block.TraceIterator(ctx, compactorCfg.ChunkSizeBytes, compactorCfg.ReadAheadChunks)
+	}
+
+	enc, err := encoding.FromVersion(meta.Version)
+	if err != nil {
+		return nil, err
+	}
+	block, err := enc.OpenBlock(meta, rw.r)
+	if err != nil {
+		return nil, err
+	}
+	iterable, ok := block.(traceIterableBlock)
+	if !ok {
+		return nil, fmt.Errorf("blocks of version %s can't be converted", meta.Version)
+	}
+	return iterable.TraceIterator(ctx)
+}
@@ -0,0 +1,118 @@
+package tempodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// dedupeReportTopTraces is the number of traces with the most deduped bytes listed in a dedupe report
+const dedupeReportTopTraces = 10
+
+// DedupeReport holds the duplicate traces merged by the compaction in a block. It's written next to the block if
+// the compactor dedupe report is enabled, for capacity planning.
+type DedupeReport struct {
+	TenantID        string         `json:"tenantID"`
+	BlockID         backend.UUID   `json:"blockID"`
+	CompactedBlocks []backend.UUID `json:"compactedBlocks"`
+	CreatedAt       time.Time      `json:"createdAt"`
+	// DuplicateTraces is the number of traces of the block found in several compacted blocks, DuplicateObjects
+	// the number of their copies merged and DedupedBytes the estimated bytes saved by merging them.
+	DuplicateTraces  int   `json:"duplicateTraces"`
+	DuplicateObjects int   `json:"duplicateObjects"`
+	DedupedBytes     int64 `json:"dedupedBytes"`
+	// TopTraces are the traces with the most deduped bytes, in descending order.
+	TopTraces []DedupeReportTrace `json:"topTraces,omitempty"`
+}
+
+type DedupeReportTrace struct {
+	TraceID          string `json:"traceID"`
+	DuplicateObjects int    `json:"duplicateObjects"`
+	DedupedBytes     int    `json:"dedupedBytes"`
+}
+
+// dedupeReports collects the dedupe reports of the blocks written by a compaction.
+type dedupeReports struct {
+	tenantID        string
+	compactedBlocks []backend.UUID
+	reports         map[backend.UUID]*DedupeReport
+}
+
+func newDedupeReports(tenantID string, inputs []*backend.BlockMeta) *dedupeReports {
+	r := &dedupeReports{
+		tenantID: tenantID,
+		reports:  map[backend.UUID]*DedupeReport{},
+	}
+	for _, m := range inputs {
+		r.compactedBlocks = append(r.compactedBlocks, m.BlockID)
+	}
+	return r
+}
+
+func (r *dedupeReports) traceDeduped(block backend.UUID, traceID common.ID, duplicates, dedupedBytes int) {
+	report, ok := r.reports[block]
+	if !ok {
+		report = &DedupeReport{
+			TenantID:        r.tenantID,
+			BlockID:         block,
+			CompactedBlocks: r.compactedBlocks,
+		}
+		r.reports[block] = report
+	}
+
+	report.DuplicateTraces++
+	report.DuplicateObjects += duplicates
+	report.DedupedBytes += int64(dedupedBytes)
+
+	// keep a few more traces than listed so they are sorted only once in a while
+	report.TopTraces = append(report.TopTraces, DedupeReportTrace{
+		TraceID:          util.TraceIDToHexString(traceID),
+		DuplicateObjects: duplicates,
+		DedupedBytes:     dedupedBytes,
+	})
+	if len(report.TopTraces) >= 2*dedupeReportTopTraces {
+		sortTopTraces(report.TopTraces)
+		report.TopTraces = report.TopTraces[:dedupeReportTopTraces]
+	}
+}
+
+// write writes the reports of the blocks next to them. Blocks without duplicate traces get an empty report.
+func (r *dedupeReports) write(ctx context.Context, w backend.Writer, blocks []*backend.BlockMeta) error {
+	now := time.Now()
+	for _, m := range blocks {
+		report, ok := r.reports[m.BlockID]
+		if !ok {
+			report = &DedupeReport{
+				TenantID:        r.tenantID,
+				BlockID:         m.BlockID,
+				CompactedBlocks: r.compactedBlocks,
+			}
+		}
+		report.CreatedAt = now
+		sortTopTraces(report.TopTraces)
+		if len(report.TopTraces) > dedupeReportTopTraces {
+			report.TopTraces = report.TopTraces[:dedupeReportTopTraces]
+		}
+
+		b, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		if err := w.Write(ctx, backend.DedupeReportName, (uuid.UUID)(m.BlockID), r.tenantID, b, nil); err != nil {
+			return fmt.Errorf("error writing dedupe report of block %s: %w", m.BlockID.String(), err)
+		}
+	}
+	return nil
+}
+
+func sortTopTraces(traces []DedupeReportTrace) {
+	sort.SliceStable(traces, func(i, j int) bool { return traces[i].DedupedBytes > traces[j].DedupedBytes })
+}
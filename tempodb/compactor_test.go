@@ -20,6 +20,7 @@ import (
 	"github.com/grafana/tempo/pkg/model/trace"
 	v1 "github.com/grafana/tempo/pkg/model/v1"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/backend/local"
@@ -27,6 +28,9 @@ import (
 	"github.com/grafana/tempo/tempodb/blockselector"
 	"github.com/grafana/tempo/tempodb/encoding"
 	"github.com/grafana/tempo/tempodb/encoding/common"
+	v2 "github.com/grafana/tempo/tempodb/encoding/v2"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet3"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
 	"github.com/grafana/tempo/tempodb/pool"
 	"github.com/grafana/tempo/tempodb/wal"
 )
@@ -48,10 +52,19 @@ type mockJobSharder struct{}
 func (m *mockJobSharder) Owns(string) bool { return true }
 
 type mockOverrides struct {
-	blockRetention      time.Duration
-	disabled            bool
-	maxBytesPerTrace    int
-	maxCompactionWindow time.Duration
+	blockRetention            time.Duration
+	disabled                  bool
+	maxBytesPerTrace          int
+	maxCompactionWindow       time.Duration
+	attributePolicy           common.AttributePolicy
+	convertV2Blocks           bool
+	dedicatedColumns          backend.DedicatedColumns
+	retentionClassAttribute   string
+	retentionClasses          map[string]time.Duration
+	archiveAfter              time.Duration
+	archiveTier               string
+	blockEncoding             common.BlockEncoding
+	dedicatedColumnsAutoApply bool
 }
 
 func (m *mockOverrides) BlockRetentionForTenant(_ string) time.Duration {
@@ -70,6 +83,34 @@ func (m *mockOverrides) MaxCompactionRangeForTenant(_ string) time.Duration {
 	return m.maxCompactionWindow
 }
 
+func (m *mockOverrides) StorageAttributePolicyForTenant(_ string) common.AttributePolicy {
+	return m.attributePolicy
+}
+
+func (m *mockOverrides) ConvertV2BlocksForTenant(_ string) bool {
+	return m.convertV2Blocks
+}
+
+func (m *mockOverrides) DedicatedColumnsForTenant(_ string) backend.DedicatedColumns {
+	return m.dedicatedColumns
+}
+
+func (m *mockOverrides) BlockRetentionClassesForTenant(_ string) (string, map[string]time.Duration) {
+	return m.retentionClassAttribute, m.retentionClasses
+}
+
+func (m *mockOverrides) BlockArchiveForTenant(_ string) (time.Duration, string) {
+	return m.archiveAfter, m.archiveTier
+}
+
+func (m *mockOverrides) BlockEncodingForTenant(_ string) common.BlockEncoding {
+	return m.blockEncoding
+}
+
+func (m *mockOverrides) DedicatedColumnsAutoApplyForTenant(_ string) bool {
+	return m.dedicatedColumnsAutoApply
+}
+
 func TestCompactionRoundtrip(t *testing.T) {
 	for _, enc := range encoding.AllEncodings() {
 		version := enc.Version()
@@ -163,7 +204,7 @@ func testCompactionRoundtrip(t *testing.T, targetBlockVersion string) {
 	rw.pollBlocklist(ctx)
 
 	blocklist := rw.blocklist.Metas(testTenantID)
-	blockSelector := blockselector.NewTimeWindowBlockSelector(blocklist, rw.compactorCfg.MaxCompactionRange, 10000, 1024*1024*1024, blockselector.DefaultMinInputBlocks, 2)
+	blockSelector := blockselector.NewTimeWindowBlockSelector(blocklist, rw.compactorCfg.MaxCompactionRange, 10000, 1024*1024*1024, blockselector.DefaultMinInputBlocks, 2, 0)
 
 	expectedCompactions := len(blocklist) / inputBlocks
 	compactions := 0
@@ -333,7 +374,7 @@ func testSameIDCompaction(t *testing.T, targetBlockVersion string) {
 
 	var blocks []*backend.BlockMeta
 	list := rw.blocklist.Metas(testTenantID)
-	blockSelector := blockselector.NewTimeWindowBlockSelector(list, rw.compactorCfg.MaxCompactionRange, 10000, 1024*1024*1024, blockselector.DefaultMinInputBlocks, blockCount)
+	blockSelector := blockselector.NewTimeWindowBlockSelector(list, rw.compactorCfg.MaxCompactionRange, 10000, 1024*1024*1024, blockselector.DefaultMinInputBlocks, blockCount, 0)
 	blocks, _ = blockSelector.BlocksToCompact()
 	require.Len(t, blocks, blockCount)
 
@@ -867,6 +908,204 @@ func testCompactWithConfig(t *testing.T, targetBlockVersion string) {
 	require.NoError(t, err)
 }
 
+func TestCompactionConvertsV2Blocks(t *testing.T) {
+	tempDir := t.TempDir()
+
+	newRW := func(version string) (Reader, Writer, Compactor) {
+		r, w, c, err := New(&Config{
+			Backend: backend.Local,
+			Pool: &pool.Config{
+				MaxWorkers: 10,
+				QueueDepth: 100,
+			},
+			Local: &local.Config{
+				Path: path.Join(tempDir, "traces"),
+			},
+			Block: &common.BlockConfig{
+				IndexDownsampleBytes: 11,
+				BloomFP:              .01,
+				BloomShardSizeBytes:  100_000,
+				Version:              version,
+				Encoding:             backend.EncNone,
+				IndexPageSizeBytes:   1000,
+				RowGroupSizeBytes:    30_000_000,
+			},
+			WAL: &wal.Config{
+				Filepath: path.Join(tempDir, "wal"),
+			},
+			BlocklistPoll: 0,
+		}, nil, log.NewNopLogger())
+		require.NoError(t, err)
+		return r, w, c
+	}
+
+	blockCount := 3
+	recordCount := 10
+
+	// write v2 blocks and a block of the new version that is left alone
+	_, w, _ := newRW(v2.VersionString)
+	v2Blocks := cutTestBlocks(t, w, testTenantID, blockCount, recordCount)
+
+	r, w, c := newRW(vparquet4.VersionString)
+	parquetBlocks := cutTestBlocks(t, w, testTenantID, 1, recordCount)
+
+	ctx := context.Background()
+	dedicatedColumns := backend.DedicatedColumns{{Scope: "span", Name: "key", Type: "string"}}
+	err := c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10_000_000,
+		FlushSizeBytes:          10_000_000,
+		MaxCompactionRange:      24 * time.Hour,
+		MaxCompactionObjects:    1000,
+		MaxBlockBytes:           100_000_000,
+		BlockRetention:          0,
+		CompactedBlockRetention: 0,
+	}, &mockSharder{}, &mockOverrides{convertV2Blocks: true, dedicatedColumns: dedicatedColumns})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{}, true)
+	rw := r.(*readerWriter)
+	rw.pollBlocklist(ctx)
+	require.Len(t, rw.blocklist.Metas(testTenantID), blockCount+1)
+
+	blockSelector := rw.newConversionBlockSelector(testTenantID, rw.blocklist.Metas(testTenantID), func(rest []*backend.BlockMeta) blockselector.CompactionBlockSelector {
+		return blockselector.NewTimeWindowBlockSelector(rest, time.Hour, 1000, 100_000_000, blockselector.DefaultMinInputBlocks, blockselector.DefaultMaxInputBlocks, 0)
+	})
+
+	conversions := 0
+	for {
+		blocks, _ := blockSelector.BlocksToCompact()
+		if len(blocks) == 0 {
+			break
+		}
+		require.Len(t, blocks, 1)
+		require.Equal(t, v2.VersionString, blocks[0].Version)
+
+		require.NoError(t, rw.compactOneJob(ctx, blocks, testTenantID))
+		conversions++
+	}
+	require.Equal(t, blockCount, conversions)
+
+	// all v2 blocks were replaced by blocks of the new version with the same objects
+	metas := rw.blocklist.Metas(testTenantID)
+	require.Len(t, metas, blockCount+1)
+	require.Len(t, rw.blocklist.CompactedMetas(testTenantID), blockCount)
+	for _, meta := range metas {
+		require.Equal(t, vparquet4.VersionString, meta.Version)
+		require.Equal(t, int64(recordCount), meta.TotalObjects)
+		if meta.BlockID != parquetBlocks[0].BlockMeta().BlockID {
+			require.Equal(t, dedicatedColumns, meta.DedicatedColumns)
+		}
+	}
+
+	for i, b := range v2Blocks {
+		require.Equal(t, v2.VersionString, b.BlockMeta().Version)
+
+		for j := 0; j < recordCount; j++ {
+			trs, failedBlocks, err := rw.Find(ctx, testTenantID, makeTraceID(i, j), BlockIDMin, BlockIDMax, 0, 0, common.DefaultSearchOptions())
+			require.NoError(t, err)
+			require.Nil(t, failedBlocks)
+			require.NotEmpty(t, trs)
+		}
+	}
+}
+
+func TestCompactionDedupeReport(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Pool: &pool.Config{
+			MaxWorkers: 10,
+			QueueDepth: 100,
+		},
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 11,
+			BloomFP:              .01,
+			BloomShardSizeBytes:  100_000,
+			Version:              vparquet4.VersionString,
+			Encoding:             backend.EncNone,
+			IndexPageSizeBytes:   1000,
+			RowGroupSizeBytes:    30_000_000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10_000_000,
+		FlushSizeBytes:          10_000_000,
+		MaxCompactionRange:      24 * time.Hour,
+		MaxCompactionObjects:    1000,
+		MaxBlockBytes:           100_000_000,
+		BlockRetention:          0,
+		CompactedBlockRetention: 0,
+		DedupeReport:            true,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{}, true)
+	rw := r.(*readerWriter)
+
+	// the same trace is in all blocks, another one is split across two blocks
+	copied, split := makeTraceID(1, 0), makeTraceID(2, 0)
+	copiedTrace := test.MakeTrace(10, copied)
+	cutTestBlockWithTraces(t, w, []testData{
+		{copied, copiedTrace, 0, 0},
+		{split, test.MakeTrace(1, split), 0, 0},
+		{makeTraceID(3, 0), test.MakeTrace(1, makeTraceID(3, 0)), 0, 0},
+	})
+	cutTestBlockWithTraces(t, w, []testData{
+		{copied, copiedTrace, 0, 0},
+		{split, test.MakeTrace(1, split), 0, 0},
+	})
+	cutTestBlockWithTraces(t, w, []testData{
+		{copied, copiedTrace, 0, 0},
+	})
+
+	rw.pollBlocklist(ctx)
+	blocks := rw.blocklist.Metas(testTenantID)
+	require.Len(t, blocks, 3)
+
+	duplicatesBefore, err := test.GetCounterVecValue(metricCompactionDuplicateTraces, testTenantID)
+	require.NoError(t, err)
+
+	require.NoError(t, rw.compactOneJob(ctx, blocks, testTenantID))
+
+	duplicates, err := test.GetCounterVecValue(metricCompactionDuplicateTraces, testTenantID)
+	require.NoError(t, err)
+	require.Equal(t, float64(2), duplicates-duplicatesBefore)
+
+	metas := rw.blocklist.Metas(testTenantID)
+	require.Len(t, metas, 1)
+	require.Equal(t, int64(3), metas[0].TotalObjects)
+
+	b, err := rw.r.Read(ctx, backend.DedupeReportName, uuid.UUID(metas[0].BlockID), testTenantID, nil)
+	require.NoError(t, err)
+
+	report := DedupeReport{}
+	require.NoError(t, json.Unmarshal(b, &report))
+	require.Equal(t, testTenantID, report.TenantID)
+	require.Equal(t, metas[0].BlockID, report.BlockID)
+	require.ElementsMatch(t, []backend.UUID{blocks[0].BlockID, blocks[1].BlockID, blocks[2].BlockID}, report.CompactedBlocks)
+	require.Equal(t, 2, report.DuplicateTraces)
+	require.Equal(t, 3, report.DuplicateObjects)
+	require.Greater(t, report.DedupedBytes, int64(0))
+
+	// the identical copies saved the most bytes
+	require.Len(t, report.TopTraces, 2)
+	require.Equal(t, util.TraceIDToHexString(copied), report.TopTraces[0].TraceID)
+	require.Equal(t, 2, report.TopTraces[0].DuplicateObjects)
+	require.Equal(t, util.TraceIDToHexString(split), report.TopTraces[1].TraceID)
+	require.Equal(t, 1, report.TopTraces[1].DuplicateObjects)
+}
+
 type testData struct {
 	id         common.ID
 	t          *tempopb.Trace
@@ -892,6 +1131,84 @@ func cutTestBlockWithTraces(t testing.TB, w Writer, data []testData) common.Back
 	return b
 }
 
+func TestCompactionWritesTenantBlockVersion(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Pool: &pool.Config{
+			MaxWorkers: 10,
+			QueueDepth: 100,
+		},
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 11,
+			BloomFP:              .01,
+			BloomShardSizeBytes:  100_000,
+			Version:              vparquet3.VersionString,
+			Encoding:             backend.EncNone,
+			IndexPageSizeBytes:   1000,
+			RowGroupSizeBytes:    30_000_000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	blockCount := 2
+	recordCount := 10
+	cutTestBlocks(t, w, testTenantID, blockCount, recordCount)
+
+	// the tenant is pinned to another version than the blocks
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10_000_000,
+		FlushSizeBytes:          10_000_000,
+		MaxCompactionRange:      24 * time.Hour,
+		MaxCompactionObjects:    1000,
+		MaxBlockBytes:           100_000_000,
+		BlockRetention:          0,
+		CompactedBlockRetention: 0,
+	}, &mockSharder{}, &mockOverrides{blockEncoding: common.BlockEncoding{Version: vparquet4.VersionString}})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{}, true)
+	rw := r.(*readerWriter)
+	rw.pollBlocklist(ctx)
+
+	// the blocks are converted to the version of the tenant
+	metas := rw.blocklist.Metas(testTenantID)
+	require.Len(t, metas, blockCount)
+	require.NoError(t, rw.compactOneJob(ctx, metas, testTenantID))
+
+	metas = rw.blocklist.Metas(testTenantID)
+	require.Len(t, metas, blockCount)
+	for _, meta := range metas {
+		require.Equal(t, vparquet4.VersionString, meta.Version)
+		require.Equal(t, int64(recordCount), meta.TotalObjects)
+	}
+
+	// and then compacted in it
+	require.NoError(t, rw.compactOneJob(ctx, metas, testTenantID))
+	metas = rw.blocklist.Metas(testTenantID)
+	require.Len(t, metas, 1)
+	require.Equal(t, vparquet4.VersionString, metas[0].Version)
+	require.Equal(t, int64(blockCount*recordCount), metas[0].TotalObjects)
+
+	for i := 0; i < blockCount; i++ {
+		for j := 0; j < recordCount; j++ {
+			trs, failedBlocks, err := rw.Find(ctx, testTenantID, makeTraceID(i, j), BlockIDMin, BlockIDMax, 0, 0, common.DefaultSearchOptions())
+			require.NoError(t, err)
+			require.Nil(t, failedBlocks)
+			require.NotEmpty(t, trs)
+		}
+	}
+}
+
 func cutTestBlocks(t testing.TB, w Writer, tenantID string, blockCount int, recordCount int) []common.BackendBlock {
 	blocks := make([]common.BackendBlock, 0)
 	dec := model.MustNewSegmentDecoder(model.CurrentEncoding)
@@ -4,18 +4,24 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"path"
 	"time"
 
+	"github.com/grafana/dskit/flagext"
+
 	"github.com/grafana/tempo/modules/cache/memcached"
 	"github.com/grafana/tempo/modules/cache/redis"
 
 	"github.com/grafana/tempo/pkg/cache"
 	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/backend/azure"
 	backend_cache "github.com/grafana/tempo/tempodb/backend/cache"
 	"github.com/grafana/tempo/tempodb/backend/gcs"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/backend/s3"
+	"github.com/grafana/tempo/tempodb/blocklist"
+	"github.com/grafana/tempo/tempodb/blockselector"
 	"github.com/grafana/tempo/tempodb/encoding"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	"github.com/grafana/tempo/tempodb/pool"
@@ -32,12 +38,22 @@ const (
 	DefaultTolerateConsecutiveErrors      = 1
 	DefaultTolerateTenantFailures         = 1
 
-	DefaultEmptyTenantDeletionAge = 12 * time.Hour
+	DefaultEmptyTenantDeletionAge               = 12 * time.Hour
+	DefaultEmptyTenantDeletionConcurrency       = uint(10)
+	DefaultEmptyTenantDeletionMaxObjects        = 10_000
+	DefaultEmptyTenantDeletionMaxTenantsPerPoll = 10
 
 	DefaultPrefetchTraceCount   = 1000
 	DefaultSearchChunkSizeBytes = 1_000_000
 	DefaultReadBufferCount      = 32
 	DefaultReadBufferSize       = 1 * 1024 * 1024
+
+	DefaultTraceByIDRowGroupConcurrency = 4
+
+	DefaultTieredMaxAge           = 24 * time.Hour
+	DefaultTieredEvictionInterval = 5 * time.Minute
+
+	DefaultTenantPartitionsPeriod = time.Hour
 )
 
 // Config holds the entirety of tempodb configuration
@@ -54,12 +70,31 @@ type Config struct {
 	BlocklistPollFallback                  bool          `yaml:"blocklist_poll_fallback"`
 	BlocklistPollTenantIndexBuilders       int           `yaml:"blocklist_poll_tenant_index_builders"`
 	BlocklistPollStaleTenantIndex          time.Duration `yaml:"blocklist_poll_stale_tenant_index"`
+	BlocklistPollStaleThreshold            time.Duration `yaml:"blocklist_poll_stale_threshold"`
+	BlocklistPollTenantAllowList           []string      `yaml:"blocklist_poll_tenant_allow_list"`
+	BlocklistPollTenantDenyList            []string      `yaml:"blocklist_poll_tenant_deny_list"`
 	BlocklistPollJitterMs                  int           `yaml:"blocklist_poll_jitter_ms"`
 	BlocklistPollTolerateConsecutiveErrors int           `yaml:"blocklist_poll_tolerate_consecutive_errors"`
 	BlocklistPollTolerateTenantFailures    int           `yaml:"blocklist_poll_tolerate_tenant_failures"`
+	BlocklistPollBackendCallTimeout        time.Duration `yaml:"blocklist_poll_backend_call_timeout"`
+	BlocklistPollBackendCallSlowThreshold  time.Duration `yaml:"blocklist_poll_backend_call_slow_threshold"`
+	BlocklistPollDeadlineAudit             bool          `yaml:"blocklist_poll_deadline_audit"`
+	BlocklistPollIndexVerificationTenants  int           `yaml:"blocklist_poll_index_verification_tenants"`
+	BlocklistPollRequestsPerSecond         float64       `yaml:"blocklist_poll_requests_per_second"`
+	BlocklistPollBytesPerSecond            int           `yaml:"blocklist_poll_bytes_per_second"`
+	BlocklistPollBlockMetaCacheSize        int           `yaml:"blocklist_poll_block_meta_cache_size"`
+	BlocklistPollTenantIndexBuilderTimeout time.Duration `yaml:"blocklist_poll_tenant_index_builder_timeout"`
+	BlocklistPollTenantIndexAgeTarget      time.Duration `yaml:"blocklist_poll_tenant_index_age_target"`
+
+	BlocklistPollInventory blocklist.InventoryConfig `yaml:"blocklist_poll_inventory"`
+	// BlocklistPollTenantIndexReplica is a second backend that tenant index builders write the tenant indexes to
+	BlocklistPollTenantIndexReplica TenantIndexReplicaConfig `yaml:"blocklist_poll_tenant_index_replica"`
 
-	EmptyTenantDeletionEnabled bool          `yaml:"empty_tenant_deletion_enabled"`
-	EmptyTenantDeletionAge     time.Duration `yaml:"empty_tenant_deletion_age"`
+	EmptyTenantDeletionEnabled           bool          `yaml:"empty_tenant_deletion_enabled"`
+	EmptyTenantDeletionAge               time.Duration `yaml:"empty_tenant_deletion_age"`
+	EmptyTenantDeletionConcurrency       uint          `yaml:"empty_tenant_deletion_concurrency"`
+	EmptyTenantDeletionMaxObjects        int           `yaml:"empty_tenant_deletion_max_objects"`
+	EmptyTenantDeletionMaxTenantsPerPoll int           `yaml:"empty_tenant_deletion_max_tenants_per_poll"`
 
 	// backends
 	Backend string        `yaml:"backend"`
@@ -68,6 +103,21 @@ type Config struct {
 	S3      *s3.Config    `yaml:"s3"`
 	Azure   *azure.Config `yaml:"azure"`
 
+	// DualWrite writes flushed blocks to a second backend as well
+	DualWrite DualWriteConfig `yaml:"dual_write"`
+
+	// Tiered keeps local copies of recent blocks next to the trace storage
+	Tiered TieredConfig `yaml:"tiered"`
+
+	// TenantPartitions spreads the blocks of the largest tenants over several storage tenants
+	TenantPartitions TenantPartitionsConfig `yaml:"tenant_partitions"`
+
+	// FederatedBuckets are other buckets blocks are read from as well. Writes only go to the trace storage.
+	FederatedBuckets []FederatedBucketConfig `yaml:"federated_buckets"`
+
+	// Encryption encrypts the blocks of tenants at rest with the keys of the tenants
+	Encryption EncryptionConfig `yaml:"encryption"`
+
 	// legacy cache config. this is loaded by tempodb and added to the cache
 	// provider on construction
 	Cache           string                  `yaml:"cache"`
@@ -78,6 +128,161 @@ type Config struct {
 	BloomCacheCfg backend_cache.BloomConfig `yaml:",inline"`
 }
 
+// DualWriteConfig configures a second backend that flushed blocks are also written to during a migration to a new
+// bucket or block format. Blocks are only read from and compacted in the trace storage.
+type DualWriteConfig struct {
+	// Backend of the second storage. Dual writes are disabled if empty.
+	Backend string        `yaml:"backend"`
+	Local   *local.Config `yaml:"local"`
+	GCS     *gcs.Config   `yaml:"gcs"`
+	S3      *s3.Config    `yaml:"s3"`
+	Azure   *azure.Config `yaml:"azure"`
+
+	// Version of the blocks written to the second storage. Defaults to the block version of the trace storage.
+	Version string `yaml:"version"`
+}
+
+func (c *DualWriteConfig) RegisterFlagsAndApplyDefaults(*flag.FlagSet) {
+	// pass in a dummy flagset because we don't want to set any flags for the second storage
+	dummyFlagSet := &flag.FlagSet{}
+
+	c.Local = &local.Config{}
+	c.Local.RegisterFlagsAndApplyDefaults("", dummyFlagSet)
+	c.GCS = &gcs.Config{}
+	c.GCS.RegisterFlagsAndApplyDefaults("", dummyFlagSet)
+	c.S3 = &s3.Config{}
+	c.S3.RegisterFlagsAndApplyDefaults("", dummyFlagSet)
+	c.Azure = &azure.Config{}
+	c.Azure.RegisterFlagsAndApplyDefaults("", dummyFlagSet)
+}
+
+// Enabled returns true if blocks are written to a second storage.
+func (c *DualWriteConfig) Enabled() bool {
+	return c.Backend != ""
+}
+
+// TieredConfig configures a local disk, for example an SSD, that blocks written to the trace storage are mirrored to.
+// Blocks are read from their local copy while it exists and local copies are evicted once the newest trace of their
+// block is older than the max age. Blocks are listed in and compacted in the trace storage only.
+type TieredConfig struct {
+	// Local disk of the recent blocks. Tiering is disabled if its path is empty.
+	Local *local.Config `yaml:"local"`
+
+	// MaxAge after the end time of a block its local copy is evicted.
+	MaxAge time.Duration `yaml:"max_age"`
+	// EvictionInterval is how often local copies are checked for eviction.
+	EvictionInterval time.Duration `yaml:"eviction_interval"`
+}
+
+func (c *TieredConfig) RegisterFlagsAndApplyDefaults(*flag.FlagSet) {
+	// pass in a dummy flagset because we don't want to set any flags for the local disk
+	dummyFlagSet := &flag.FlagSet{}
+
+	c.Local = &local.Config{}
+	c.Local.RegisterFlagsAndApplyDefaults("", dummyFlagSet)
+	c.MaxAge = DefaultTieredMaxAge
+	c.EvictionInterval = DefaultTieredEvictionInterval
+}
+
+// Enabled returns true if recent blocks are kept on a local disk.
+func (c *TieredConfig) Enabled() bool {
+	return c.Local != nil && c.Local.Path != ""
+}
+
+// TenantPartitionsConfig configures the tenants whose blocks are spread over several partitions. Each partition is
+// stored as its own storage tenant `<tenant>~<partition>`, with its own prefix and tenant index, and is polled,
+// compacted and retained on its own. The read path fans out over the partitions of the tenant.
+type TenantPartitionsConfig struct {
+	// Tenants maps the partitioned tenants to their number of partitions.
+	Tenants map[string]int `yaml:"tenants"`
+	// Period of the time windows that are assigned to the partitions of a tenant round robin by the start time of
+	// the flushed blocks.
+	Period time.Duration `yaml:"period"`
+}
+
+// EncryptionConfig configures the envelope encryption of the blocks of tenants at rest. Each block is encrypted with
+// its own data key, that is wrapped with the key of its tenant and stored in the meta of the block. Metas, flags and
+// tenant indexes aren't encrypted.
+type EncryptionConfig struct {
+	// TenantKeys maps the tenants to their base64 encoded 256 bit key. Blocks of other tenants aren't encrypted.
+	TenantKeys map[string]flagext.Secret `yaml:"tenant_keys"`
+	// RotatedTenantKeys maps the tenants to their previous keys. They only unwrap the data keys of blocks that weren't
+	// re-wrapped with the current key yet, and can be removed once no block uses them.
+	RotatedTenantKeys map[string][]flagext.Secret `yaml:"rotated_tenant_keys"`
+
+	// KMS wraps the data keys instead of the tenant keys, for example with the key management service of a cloud
+	// provider. It can only be set by programs embedding Tempo.
+	KMS backend.KMS `yaml:"-"`
+}
+
+// Enabled returns true if blocks are encrypted.
+func (c *EncryptionConfig) Enabled() bool {
+	return c.KMS != nil || len(c.TenantKeys) > 0
+}
+
+// FederatedBucketConfig configures a bucket, for example the bucket of another account after a cloud migration,
+// that tenants and blocks are listed in and read from next to the trace storage. Its blocks are tagged with the
+// name of the bucket and are never compacted, retained or rewritten.
+type FederatedBucketConfig struct {
+	// Name of the bucket the metas of its blocks are tagged with.
+	Name    string        `yaml:"name"`
+	Backend string        `yaml:"backend"`
+	Local   *local.Config `yaml:"local"`
+	GCS     *gcs.Config   `yaml:"gcs"`
+	S3      *s3.Config    `yaml:"s3"`
+	Azure   *azure.Config `yaml:"azure"`
+}
+
+// UnmarshalYAML applies the defaults of the backends before unmarshalling a bucket as buckets are configured in a
+// list and don't have flags.
+func (c *FederatedBucketConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	// pass in a dummy flagset because we don't want to set any flags for the bucket
+	dummyFlagSet := &flag.FlagSet{}
+
+	c.Local = &local.Config{}
+	c.Local.RegisterFlagsAndApplyDefaults("", dummyFlagSet)
+	c.GCS = &gcs.Config{}
+	c.GCS.RegisterFlagsAndApplyDefaults("", dummyFlagSet)
+	c.S3 = &s3.Config{}
+	c.S3.RegisterFlagsAndApplyDefaults("", dummyFlagSet)
+	c.Azure = &azure.Config{}
+	c.Azure.RegisterFlagsAndApplyDefaults("", dummyFlagSet)
+
+	type rawConfig FederatedBucketConfig
+	return unmarshal((*rawConfig)(c))
+}
+
+// TenantIndexReplicaConfig configures a second backend, for example a bucket in another region, that tenant index
+// builders write the tenant indexes to as well. Pollers of a read path with the replica as their backend can pull
+// the tenant indexes without running their own builders.
+type TenantIndexReplicaConfig struct {
+	// Backend of the replica. Replication is disabled if empty.
+	Backend string        `yaml:"backend"`
+	Local   *local.Config `yaml:"local"`
+	GCS     *gcs.Config   `yaml:"gcs"`
+	S3      *s3.Config    `yaml:"s3"`
+	Azure   *azure.Config `yaml:"azure"`
+}
+
+func (c *TenantIndexReplicaConfig) RegisterFlagsAndApplyDefaults(*flag.FlagSet) {
+	// pass in a dummy flagset because we don't want to set any flags for the replica
+	dummyFlagSet := &flag.FlagSet{}
+
+	c.Local = &local.Config{}
+	c.Local.RegisterFlagsAndApplyDefaults("", dummyFlagSet)
+	c.GCS = &gcs.Config{}
+	c.GCS.RegisterFlagsAndApplyDefaults("", dummyFlagSet)
+	c.S3 = &s3.Config{}
+	c.S3.RegisterFlagsAndApplyDefaults("", dummyFlagSet)
+	c.Azure = &azure.Config{}
+	c.Azure.RegisterFlagsAndApplyDefaults("", dummyFlagSet)
+}
+
+// Enabled returns true if tenant indexes are written to a replica.
+func (c *TenantIndexReplicaConfig) Enabled() bool {
+	return c.Backend != ""
+}
+
 type CacheControlConfig struct {
 	Footer      bool `yaml:"footer"`
 	ColumnIndex bool `yaml:"column_index"`
@@ -92,6 +297,10 @@ type SearchConfig struct {
 	// vParquet blocks
 	ReadBufferCount     int `yaml:"read_buffer_count"`
 	ReadBufferSizeBytes int `yaml:"read_buffer_size_bytes"`
+	// number of row groups of a block read in parallel when a trace id is found in several of them
+	TraceByIDRowGroupConcurrency int `yaml:"trace_by_id_row_group_concurrency"`
+	// prefetch the column chunks that are typically read together by the queries of a tenant and query class
+	AdaptiveReadAhead bool `yaml:"adaptive_read_ahead"`
 	// todo: consolidate caching config in one spot
 	CacheControl CacheControlConfig `yaml:"cache_control"`
 }
@@ -101,6 +310,7 @@ func (c *SearchConfig) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 	c.PrefetchTraceCount = DefaultPrefetchTraceCount
 	c.ReadBufferCount = DefaultReadBufferCount
 	c.ReadBufferSizeBytes = DefaultReadBufferSize
+	c.TraceByIDRowGroupConcurrency = DefaultTraceByIDRowGroupConcurrency
 }
 
 func (c SearchConfig) ApplyToOptions(o *common.SearchOptions) {
@@ -108,6 +318,8 @@ func (c SearchConfig) ApplyToOptions(o *common.SearchOptions) {
 	o.PrefetchTraceCount = c.PrefetchTraceCount
 	o.ReadBufferCount = c.ReadBufferCount
 	o.ReadBufferSize = c.ReadBufferSizeBytes
+	o.RowGroupConcurrency = c.TraceByIDRowGroupConcurrency
+	o.AdaptiveReadAhead = c.AdaptiveReadAhead
 
 	if o.ChunkSizeBytes == 0 {
 		o.ChunkSizeBytes = DefaultSearchChunkSizeBytes
@@ -121,6 +333,9 @@ func (c SearchConfig) ApplyToOptions(o *common.SearchOptions) {
 	if o.ReadBufferCount <= 0 {
 		o.ReadBufferCount = DefaultReadBufferCount
 	}
+	if o.RowGroupConcurrency <= 0 {
+		o.RowGroupConcurrency = DefaultTraceByIDRowGroupConcurrency
+	}
 }
 
 // CompactorConfig contains compaction configuration options
@@ -128,14 +343,27 @@ type CompactorConfig struct {
 	ChunkSizeBytes          uint32        `yaml:"v2_in_buffer_bytes"`
 	FlushSizeBytes          uint32        `yaml:"v2_out_buffer_bytes"`
 	IteratorBufferSize      int           `yaml:"v2_prefetch_traces_count"`
+	ReadAheadChunks         int           `yaml:"v2_read_ahead_chunks"`
 	MaxCompactionRange      time.Duration `yaml:"compaction_window"`
 	MaxCompactionObjects    int           `yaml:"max_compaction_objects"`
 	MaxBlockBytes           uint64        `yaml:"max_block_bytes"`
 	BlockRetention          time.Duration `yaml:"block_retention"`
 	CompactedBlockRetention time.Duration `yaml:"compacted_block_retention"`
+	TrashRetention          time.Duration `yaml:"trash_retention"`
 	RetentionConcurrency    uint          `yaml:"retention_concurrency"`
 	MaxTimePerTenant        time.Duration `yaml:"max_time_per_tenant"`
 	CompactionCycle         time.Duration `yaml:"compaction_cycle"`
+	MaxCompactionLevel      uint32        `yaml:"max_compaction_level"`
+	CompactionPlanner       string        `yaml:"compaction_planner"`
+	// DedupeReport writes a report of the duplicate traces merged in each compacted block next to it.
+	DedupeReport bool `yaml:"dedupe_report"`
+
+	// Webhook posts compaction and retention events to an HTTP endpoint.
+	Webhook CompactionWebhookConfig `yaml:"webhook"`
+	// UsageReport periodically compares the objects of each tenant in the backend with its blocklist.
+	UsageReport UsageReportConfig `yaml:"usage_report"`
+	// BlockRepair periodically rebuilds the missing or corrupt objects of the blocks in the blocklist.
+	BlockRepair BlockRepairConfig `yaml:"block_repair"`
 }
 
 func (cfg *CompactorConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
@@ -152,6 +380,10 @@ func (cfg *CompactorConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag
 	f.IntVar(&cfg.MaxCompactionObjects, util.PrefixConfig(prefix, "compaction.max-objects-per-block"), 6000000, "Maximum number of traces in a compacted block.")
 	f.Uint64Var(&cfg.MaxBlockBytes, util.PrefixConfig(prefix, "compaction.max-block-bytes"), 100*1024*1024*1024 /* 100GB */, "Maximum size of a compacted block.")
 	f.DurationVar(&cfg.MaxCompactionRange, util.PrefixConfig(prefix, "compaction.compaction-window"), time.Hour, "Maximum time window across which to compact blocks.")
+	f.StringVar(&cfg.CompactionPlanner, util.PrefixConfig(prefix, "compaction.planner"), blockselector.PlannerTimeWindow, "Strategy used to select blocks to compact. Built in planners are time_window and size_tiered.")
+	cfg.Webhook.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "compaction"), f)
+	cfg.UsageReport.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "compaction"), f)
+	cfg.BlockRepair.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "compaction"), f)
 }
 
 func (cfg *CompactorConfig) validate() error {
@@ -159,7 +391,15 @@ func (cfg *CompactorConfig) validate() error {
 		return errors.New("Compaction window can't be 0")
 	}
 
-	return nil
+	if _, err := blockselector.Planner(cfg.CompactionPlanner); err != nil {
+		return err
+	}
+
+	if err := cfg.UsageReport.validate(); err != nil {
+		return err
+	}
+
+	return cfg.Webhook.validate()
 }
 
 func validateConfig(cfg *Config) error {
@@ -195,5 +435,92 @@ func validateConfig(cfg *Config) error {
 		return fmt.Errorf("block version validation failed: %w", err)
 	}
 
+	if cfg.BlocklistPollStaleThreshold > 0 && cfg.BlocklistPollStaleThreshold < cfg.BlocklistPoll {
+		return errors.New("blocklist poll stale threshold must be at least the blocklist poll interval")
+	}
+
+	for _, patterns := range [][]string{cfg.BlocklistPollTenantAllowList, cfg.BlocklistPollTenantDenyList} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid blocklist poll tenant pattern %q: %w", pattern, err)
+			}
+		}
+	}
+
+	err = cfg.BlocklistPollInventory.Validate()
+	if err != nil {
+		return fmt.Errorf("blocklist poll inventory config validation failed: %w", err)
+	}
+
+	err = validateHedgedReadRoles(cfg)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Tiered.Enabled() {
+		if cfg.Tiered.MaxAge <= 0 || cfg.Tiered.EvictionInterval <= 0 {
+			return errors.New("tiered max age and eviction interval must be greater than 0")
+		}
+		if cfg.Backend == backend.Local && cfg.Local != nil && path.Clean(cfg.Local.Path) == path.Clean(cfg.Tiered.Local.Path) {
+			return errors.New("tiered local path must not be the path of the local trace storage")
+		}
+	}
+
+	for tenantID, partitions := range cfg.TenantPartitions.Tenants {
+		if partitions < 1 {
+			return fmt.Errorf("tenant %s must have at least one partition", tenantID)
+		}
+		if cfg.TenantPartitions.Period <= 0 {
+			return errors.New("tenant partitions period must be greater than 0")
+		}
+	}
+
+	for tenantID, key := range cfg.Encryption.TenantKeys {
+		if _, err := decodeTenantKey(key); err != nil {
+			return fmt.Errorf("encryption key of tenant %s is invalid: %w", tenantID, err)
+		}
+	}
+	for tenantID, keys := range cfg.Encryption.RotatedTenantKeys {
+		if _, ok := cfg.Encryption.TenantKeys[tenantID]; !ok {
+			return fmt.Errorf("tenant %s has rotated encryption keys but no current key", tenantID)
+		}
+		for i, key := range keys {
+			if _, err := decodeTenantKey(key); err != nil {
+				return fmt.Errorf("rotated encryption key %d of tenant %s is invalid: %w", i, tenantID, err)
+			}
+		}
+	}
+
+	for _, b := range cfg.FederatedBuckets {
+		if b.Name == "" || b.Backend == "" {
+			return errors.New("federated buckets must have a name and a backend")
+		}
+	}
+
+	if cfg.DualWrite.Enabled() && cfg.DualWrite.Version != "" {
+		_, err = encoding.FromVersion(cfg.DualWrite.Version)
+		if err != nil {
+			return fmt.Errorf("dual write block version validation failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func validateHedgedReadRoles(cfg *Config) error {
+	var roles backend.HedgedReadRoles
+	switch {
+	case cfg.Backend == backend.S3 && cfg.S3 != nil:
+		roles = cfg.S3.HedgeRequestsRoles
+	case cfg.Backend == backend.GCS && cfg.GCS != nil:
+		roles = cfg.GCS.HedgeRequestsRoles
+	case cfg.Backend == backend.Azure && cfg.Azure != nil:
+		roles = cfg.Azure.HedgeRequestsRoles
+	}
+
+	err := roles.Validate()
+	if err != nil {
+		return fmt.Errorf("%s hedge_requests_roles validation failed: %w", cfg.Backend, err)
+	}
 	return nil
 }
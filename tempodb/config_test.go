@@ -3,11 +3,18 @@ package tempodb
 import (
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/backend/s3"
+	"github.com/grafana/tempo/tempodb/blockselector"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	"github.com/grafana/tempo/tempodb/wal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 )
 
 func TestApplyToOptions(t *testing.T) {
@@ -20,6 +27,7 @@ func TestApplyToOptions(t *testing.T) {
 	require.Equal(t, opts.ChunkSizeBytes, uint32(DefaultSearchChunkSizeBytes))
 	require.Equal(t, opts.ReadBufferCount, DefaultReadBufferCount)
 	require.Equal(t, opts.ReadBufferSize, DefaultReadBufferSize)
+	require.Equal(t, opts.RowGroupConcurrency, DefaultTraceByIDRowGroupConcurrency)
 
 	// test parameter fields are left alone
 	opts.StartPage = 1
@@ -35,11 +43,13 @@ func TestApplyToOptions(t *testing.T) {
 	cfg.PrefetchTraceCount = 5
 	cfg.ReadBufferCount = 6
 	cfg.ReadBufferSizeBytes = 7
+	cfg.TraceByIDRowGroupConcurrency = 8
 	cfg.ApplyToOptions(&opts)
 	require.Equal(t, cfg.ChunkSizeBytes, uint32(4))
 	require.Equal(t, cfg.PrefetchTraceCount, 5)
 	require.Equal(t, cfg.ReadBufferCount, 6)
 	require.Equal(t, cfg.ReadBufferSizeBytes, 7)
+	require.Equal(t, opts.RowGroupConcurrency, 8)
 }
 
 func TestValidateConfig(t *testing.T) {
@@ -128,6 +138,30 @@ func TestValidateConfig(t *testing.T) {
 	}
 }
 
+func TestValidateConfigHedgedReadRoles(t *testing.T) {
+	cfg := &Config{
+		WAL: &wal.Config{},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 1,
+			IndexPageSizeBytes:   1,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  1,
+			Version:              "v2",
+		},
+		Backend: backend.S3,
+		S3:      &s3.Config{HedgeRequestsRoles: backend.HedgedReadRoles{backend.HedgedReadRoleBloom, "pages"}},
+	}
+	require.ErrorContains(t, validateConfig(cfg), `s3 hedge_requests_roles validation failed: unknown hedged read role "pages"`)
+
+	cfg.S3.HedgeRequestsRoles = backend.HedgedReadRoles{backend.HedgedReadRoleBloom}
+	require.NoError(t, validateConfig(cfg))
+
+	// only the roles of the configured backend are validated
+	cfg.Backend = backend.GCS
+	cfg.S3.HedgeRequestsRoles = backend.HedgedReadRoles{"pages"}
+	require.NoError(t, validateConfig(cfg))
+}
+
 func TestDeprecatedVersions(t *testing.T) {
 	tests := []struct {
 		cfg            *Config
@@ -187,3 +221,143 @@ func TestValidateCompactorConfig(t *testing.T) {
 
 	require.Equal(t, expected, actual)
 }
+
+func TestValidateCompactorConfigPlanner(t *testing.T) {
+	compactorConfig := CompactorConfig{
+		MaxCompactionRange: time.Hour,
+		CompactionPlanner:  blockselector.PlannerSizeTiered,
+	}
+	require.NoError(t, compactorConfig.validate())
+
+	compactorConfig.CompactionPlanner = "unknown"
+	require.ErrorContains(t, compactorConfig.validate(), `unknown compaction planner "unknown"`)
+}
+
+func TestValidateConfigBlocklistPollTenantLists(t *testing.T) {
+	cfg := &Config{
+		WAL: &wal.Config{},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 1,
+			IndexPageSizeBytes:   1,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  1,
+			Version:              "v2",
+		},
+		BlocklistPollTenantAllowList: []string{"prod-*"},
+		BlocklistPollTenantDenyList:  []string{"prod-[a-c]"},
+	}
+	require.NoError(t, validateConfig(cfg))
+
+	cfg.BlocklistPollTenantDenyList = []string{"prod-["}
+	require.ErrorContains(t, validateConfig(cfg), `invalid blocklist poll tenant pattern "prod-["`)
+}
+
+func TestFederatedBucketConfig(t *testing.T) {
+	cfg := &Config{
+		WAL: &wal.Config{},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 1,
+			IndexPageSizeBytes:   1,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  1,
+			Version:              "v2",
+		},
+	}
+	require.NoError(t, yaml.UnmarshalStrict([]byte(`
+federated_buckets:
+  - name: old
+    backend: s3
+    s3:
+      bucket: old-bucket
+`), cfg))
+	require.Len(t, cfg.FederatedBuckets, 1)
+	require.Equal(t, "old-bucket", cfg.FederatedBuckets[0].S3.Bucket)
+	// the defaults of the backends are applied
+	require.Equal(t, 3, cfg.FederatedBuckets[0].S3.ListBlocksConcurrency)
+	require.NotNil(t, cfg.FederatedBuckets[0].GCS)
+	require.NoError(t, validateConfig(cfg))
+
+	cfg.FederatedBuckets[0].Name = ""
+	require.ErrorContains(t, validateConfig(cfg), "federated buckets must have a name and a backend")
+}
+
+func TestValidateConfigTiered(t *testing.T) {
+	cfg := &Config{
+		WAL: &wal.Config{},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 1,
+			IndexPageSizeBytes:   1,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  1,
+			Version:              "v2",
+		},
+		Backend: backend.Local,
+		Local:   &local.Config{Path: "/var/tempo/traces"},
+	}
+	cfg.Tiered.RegisterFlagsAndApplyDefaults(nil)
+	require.False(t, cfg.Tiered.Enabled())
+	require.NoError(t, validateConfig(cfg))
+
+	cfg.Tiered.Local.Path = "/var/tempo/traces/"
+	require.EqualError(t, validateConfig(cfg), "tiered local path must not be the path of the local trace storage")
+
+	cfg.Tiered.Local.Path = "/mnt/ssd/traces"
+	require.NoError(t, validateConfig(cfg))
+
+	cfg.Tiered.MaxAge = 0
+	require.EqualError(t, validateConfig(cfg), "tiered max age and eviction interval must be greater than 0")
+}
+
+func TestValidateConfigTenantPartitions(t *testing.T) {
+	cfg := &Config{
+		WAL: &wal.Config{},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 1,
+			IndexPageSizeBytes:   1,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  1,
+			Version:              "v2",
+		},
+		TenantPartitions: TenantPartitionsConfig{Tenants: map[string]int{"big": 4}, Period: DefaultTenantPartitionsPeriod},
+	}
+	require.NoError(t, validateConfig(cfg))
+
+	cfg.TenantPartitions.Tenants["big"] = 0
+	require.EqualError(t, validateConfig(cfg), "tenant big must have at least one partition")
+
+	cfg.TenantPartitions.Tenants["big"] = 4
+	cfg.TenantPartitions.Period = 0
+	require.EqualError(t, validateConfig(cfg), "tenant partitions period must be greater than 0")
+}
+
+func TestValidateConfigEncryption(t *testing.T) {
+	cfg := &Config{
+		WAL: &wal.Config{},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 1,
+			IndexPageSizeBytes:   1,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  1,
+			Version:              "v2",
+		},
+		Encryption: EncryptionConfig{TenantKeys: map[string]flagext.Secret{
+			"a": flagext.SecretWithValue("AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE="),
+		}},
+	}
+	require.NoError(t, validateConfig(cfg))
+
+	cfg.Encryption.RotatedTenantKeys = map[string][]flagext.Secret{
+		"a": {flagext.SecretWithValue("AgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgI=")},
+	}
+	require.NoError(t, validateConfig(cfg))
+
+	cfg.Encryption.RotatedTenantKeys["a"] = append(cfg.Encryption.RotatedTenantKeys["a"], flagext.SecretWithValue("AgI="))
+	require.EqualError(t, validateConfig(cfg), "rotated encryption key 1 of tenant a is invalid: key must be 32 bytes, got 2")
+
+	cfg.Encryption.RotatedTenantKeys = map[string][]flagext.Secret{"b": {}}
+	require.EqualError(t, validateConfig(cfg), "tenant b has rotated encryption keys but no current key")
+	cfg.Encryption.RotatedTenantKeys = nil
+
+	cfg.Encryption.TenantKeys["a"] = flagext.SecretWithValue("AQEBAQ==")
+	require.EqualError(t, validateConfig(cfg), "encryption key of tenant a is invalid: key must be 32 bytes, got 4")
+}
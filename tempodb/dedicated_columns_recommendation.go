@@ -0,0 +1,117 @@
+package tempodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log/level"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
+)
+
+// minDedicatedColumnShare is the share of the bytes of its scope under which an attribute isn't worth a dedicated
+// column.
+const minDedicatedColumnShare = 0.01
+
+// RecommendDedicatedColumns measures the attributes of the most recent vParquet4 blocks of the tenant and recommends
+// the attributes with the largest string values of each scope as dedicated columns. backend.ErrDoesNotExist is
+// returned if the tenant has no vParquet4 blocks.
+func (rw *readerWriter) RecommendDedicatedColumns(ctx context.Context, tenantID string, blocks int) (*backend.DedicatedColumnsRecommendation, error) {
+	var metas []*backend.BlockMeta
+	for _, m := range rw.tenantMetas(tenantID) {
+		if m.Version == vparquet4.VersionString {
+			metas = append(metas, m)
+		}
+	}
+	if len(metas) == 0 {
+		return nil, fmt.Errorf("tenant %s has no %s blocks to analyse: %w", tenantID, vparquet4.VersionString, backend.ErrDoesNotExist)
+	}
+
+	// blocks with the same end time are ordered by ID so the same blocks are analysed every time
+	sort.Slice(metas, func(i, j int) bool {
+		if !metas[i].EndTime.Equal(metas[j].EndTime) {
+			return metas[i].EndTime.After(metas[j].EndTime)
+		}
+		return metas[i].BlockID.String() < metas[j].BlockID.String()
+	})
+	if blocks > 0 && len(metas) > blocks {
+		metas = metas[:blocks]
+	}
+
+	rec := &backend.DedicatedColumnsRecommendation{
+		TenantID:    tenantID,
+		GeneratedAt: time.Now(),
+		Columns:     backend.DedicatedColumns{},
+	}
+	total := vparquet4.AttributeSizes{}
+	for _, m := range metas {
+		sizes, err := vparquet4.ReadAttributeSizes(ctx, rw.r, m)
+		if err != nil {
+			return nil, err
+		}
+		for scope, attrs := range sizes {
+			if total[scope] == nil {
+				total[scope] = map[string]uint64{}
+			}
+			for name, size := range attrs {
+				total[scope][name] += size
+			}
+		}
+		rec.Blocks = append(rec.Blocks, m.BlockID)
+	}
+
+	for _, scope := range []backend.DedicatedColumnScope{backend.DedicatedColumnScopeResource, backend.DedicatedColumnScopeSpan} {
+		columns := len(vparquet4.DedicatedResourceColumnPaths[scope][backend.DedicatedColumnTypeString])
+
+		var scopeBytes uint64
+		attrs := make([]backend.AttributeSize, 0, len(total[scope]))
+		for name, size := range total[scope] {
+			if size == 0 {
+				continue
+			}
+			attrs = append(attrs, backend.AttributeSize{Scope: scope, Name: name, Bytes: size})
+			scopeBytes += size
+		}
+		sort.Slice(attrs, func(i, j int) bool {
+			if attrs[i].Bytes != attrs[j].Bytes {
+				return attrs[i].Bytes > attrs[j].Bytes
+			}
+			return attrs[i].Name < attrs[j].Name
+		})
+
+		// the attributes just below the recommended ones are reported as well
+		if len(attrs) > 2*columns {
+			attrs = attrs[:2*columns]
+		}
+		for i := range attrs {
+			attrs[i].Share = float64(attrs[i].Bytes) / float64(scopeBytes)
+			if i < columns && attrs[i].Share >= minDedicatedColumnShare {
+				rec.Columns = append(rec.Columns, backend.DedicatedColumn{Scope: scope, Name: attrs[i].Name, Type: backend.DedicatedColumnTypeString})
+			}
+		}
+		rec.Attributes = append(rec.Attributes, attrs...)
+	}
+
+	return rec, nil
+}
+
+// recommendedDedicatedColumns returns the dedicated columns recommended for the tenant of the storage tenant, nil if
+// there is no recommendation or it's empty.
+func (rw *readerWriter) recommendedDedicatedColumns(ctx context.Context, tenantID string) backend.DedicatedColumns {
+	rec, err := backend.ReadDedicatedColumnsRecommendation(ctx, rw.rawR, logicalTenant(tenantID))
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return nil
+	}
+	if err != nil {
+		level.Warn(rw.logger).Log("msg", "failed to read dedicated columns recommendation", "tenantID", tenantID, "err", err)
+		return nil
+	}
+	if len(rec.Columns) == 0 {
+		return nil
+	}
+	return rec.Columns
+}
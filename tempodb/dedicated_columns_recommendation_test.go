@@ -0,0 +1,86 @@
+package tempodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
+)
+
+func TestRecommendDedicatedColumns(t *testing.T) {
+	ctx := context.Background()
+
+	// keep the end times of the blocks so they are distinct
+	r, w, c, _ := testConfig(t, backend.EncNone, 0, func(cfg *Config) {
+		cfg.WAL.IngestionSlack = time.Since(time.Time{})
+	})
+	require.NoError(t, c.EnableCompaction(ctx, &CompactorConfig{MaxCompactionRange: time.Hour}, &mockSharder{}, &mockOverrides{}))
+	r.EnablePolling(ctx, &mockJobSharder{}, false)
+	rw := r.(*readerWriter)
+
+	_, err := c.RecommendDedicatedColumns(ctx, testTenantID, 1)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+
+	dec := model.MustNewSegmentDecoder(model.CurrentEncoding)
+	writeBlock := func(end time.Time) *backend.BlockMeta {
+		block, err := w.WAL().NewBlock(backend.NewBlockMeta(testTenantID, uuid.New(), vparquet4.VersionString, backend.EncNone, ""), model.CurrentEncoding)
+		require.NoError(t, err)
+		for range 10 {
+			id := test.ValidTraceID(nil)
+			writeTraceToWal(t, block, dec, id, test.AddDedicatedAttributes(test.MakeTrace(10, id)), uint32(end.Unix()), uint32(end.Unix()))
+		}
+		require.NoError(t, block.Flush())
+		complete, err := w.CompleteBlock(ctx, block)
+		require.NoError(t, err)
+		return complete.BlockMeta()
+	}
+	older := writeBlock(time.Now().Add(-time.Hour))
+	newer := writeBlock(time.Now())
+	rw.pollBlocklist(ctx)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 2)
+
+	// only the most recent block is analysed
+	rec, err := c.RecommendDedicatedColumns(ctx, testTenantID, 1)
+	require.NoError(t, err)
+	require.Equal(t, []backend.UUID{newer.BlockID}, rec.Blocks)
+
+	rec, err = c.RecommendDedicatedColumns(ctx, testTenantID, 0)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []backend.UUID{older.BlockID, newer.BlockID}, rec.Blocks)
+
+	// the attributes of the same size are ordered by name, the random attributes of the spans are too small
+	columns := map[backend.DedicatedColumnScope][]string{}
+	for _, col := range rec.Columns {
+		require.Equal(t, backend.DedicatedColumnTypeString, col.Type)
+		columns[col.Scope] = append(columns[col.Scope], col.Name)
+	}
+	require.Equal(t, []string{"dedicated.resource.1", "dedicated.resource.2", "dedicated.resource.3", "dedicated.resource.4", "dedicated.resource.5", "random.res.attr"}, columns[backend.DedicatedColumnScopeResource])
+	require.Equal(t, []string{"dedicated.span.1", "dedicated.span.2", "dedicated.span.3", "dedicated.span.4", "dedicated.span.5"}, columns[backend.DedicatedColumnScopeSpan][:5])
+	require.LessOrEqual(t, len(columns[backend.DedicatedColumnScopeSpan]), 6)
+	require.Len(t, rec.Attributes, 6+20)
+
+	// the recommended columns are applied by compaction
+	require.NoError(t, backend.WriteDedicatedColumnsRecommendation(ctx, rw.rawW, rec))
+	for _, autoApply := range []bool{false, true} {
+		metas := []*backend.BlockMeta{writeBlock(time.Now()), writeBlock(time.Now())}
+		out, err := c.CompactWithConfig(ctx, metas, testTenantID, &CompactorConfig{
+			MaxCompactionRange: 24 * time.Hour,
+			MaxBlockBytes:      100_000_000,
+		}, &mockSharder{}, &mockOverrides{dedicatedColumnsAutoApply: autoApply})
+		require.NoError(t, err)
+		require.Len(t, out, 1)
+
+		if autoApply {
+			require.Equal(t, rec.Columns, out[0].DedicatedColumns)
+		} else {
+			require.Empty(t, out[0].DedicatedColumns)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package tempodb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	gkLog "github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/azure"
+	"github.com/grafana/tempo/tempodb/backend/gcs"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/backend/s3"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+const (
+	dualWriteTargetPrimary   = "primary"
+	dualWriteTargetSecondary = "secondary"
+
+	dualWriteResultSuccess = "success"
+	dualWriteResultFailed  = "failed"
+	dualWriteResultSkipped = "skipped"
+)
+
+var (
+	metricDualWriteBlocks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "dual_write_blocks_total",
+		Help:      "Total number of blocks written to the dual write storage by result. skipped blocks have a different version and weren't converted when they were completed.",
+	}, []string{"tenant", "result"})
+	metricDualWriteObjects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "dual_write_objects_total",
+		Help:      "Total number of traces in the blocks written to the trace storage (primary) and the dual write storage (secondary). The targets match once both storages have received the same blocks.",
+	}, []string{"tenant", "target"})
+)
+
+// dualWriter writes the blocks flushed to the trace storage to a second storage as well. Blocks with the version
+// of the second storage are copied when they are written. Blocks with another version are converted from the wal
+// when they are completed. Failed writes to the second storage are counted but never fail the write to the trace
+// storage.
+type dualWriter struct {
+	r       backend.Reader
+	w       backend.Writer
+	version string
+	cfg     *common.BlockConfig
+	logger  gkLog.Logger
+
+	// blocks converted when they were completed that don't have to be copied when they are written
+	convertedMtx sync.Mutex
+	converted    map[uuid.UUID]struct{}
+}
+
+func newDualWriter(cfg *Config, logger gkLog.Logger) (*dualWriter, error) {
+	rawR, rawW, err := newSecondaryBackend(cfg.DualWrite.Backend, cfg.DualWrite.Local, cfg.DualWrite.GCS, cfg.DualWrite.S3, cfg.DualWrite.Azure)
+	if err != nil {
+		return nil, fmt.Errorf("error creating dual write backend: %w", err)
+	}
+
+	// the blocks in the second storage use the block config of the trace storage with their own version
+	blockCfg := *cfg.Block
+	if cfg.DualWrite.Version != "" {
+		blockCfg.Version = cfg.DualWrite.Version
+	}
+
+	return &dualWriter{
+		r:         backend.NewReader(rawR),
+		w:         backend.NewWriter(rawW),
+		version:   blockCfg.Version,
+		cfg:       &blockCfg,
+		logger:    logger,
+		converted: map[uuid.UUID]struct{}{},
+	}, nil
+}
+
+// write copies a block that was written to the trace storage to the second storage.
+func (d *dualWriter) write(ctx context.Context, block WriteableBlock) {
+	meta := block.BlockMeta()
+	metricDualWriteObjects.WithLabelValues(meta.TenantID, dualWriteTargetPrimary).Add(float64(meta.TotalObjects))
+
+	if d.takeConverted(meta) {
+		return
+	}
+
+	if meta.Version != d.version {
+		level.Warn(d.logger).Log("msg", "skipping dual write of block with a different version", "tenant", meta.TenantID, "block", meta.BlockID.String(), "version", meta.Version, "dual_write_version", d.version)
+		metricDualWriteBlocks.WithLabelValues(meta.TenantID, dualWriteResultSkipped).Inc()
+		return
+	}
+
+	d.record(meta, block.Write(ctx, d.w))
+}
+
+// convert creates the block in the second storage from the wal block. If track is true the block is skipped
+// when it's written to the trace storage later.
+func (d *dualWriter) convert(ctx context.Context, block common.WALBlock, inMeta *backend.BlockMeta, track bool) {
+	vers, err := encoding.FromVersion(d.version)
+	if err == nil {
+		var iter common.Iterator
+		iter, err = block.Iterator()
+		if err == nil {
+			defer iter.Close()
+
+			meta := *inMeta
+			_, err = vers.CreateBlock(ctx, d.cfg, &meta, iter, d.r, d.w)
+		}
+	}
+
+	if err == nil && track {
+		d.convertedMtx.Lock()
+		d.converted[(uuid.UUID)(inMeta.BlockID)] = struct{}{}
+		d.convertedMtx.Unlock()
+	}
+
+	d.record(inMeta, err)
+}
+
+func (d *dualWriter) takeConverted(meta *backend.BlockMeta) bool {
+	d.convertedMtx.Lock()
+	defer d.convertedMtx.Unlock()
+
+	id := (uuid.UUID)(meta.BlockID)
+	if _, ok := d.converted[id]; !ok {
+		return false
+	}
+	delete(d.converted, id)
+	return true
+}
+
+func (d *dualWriter) record(meta *backend.BlockMeta, err error) {
+	if err != nil {
+		level.Error(d.logger).Log("msg", "failed to write block to dual write storage", "tenant", meta.TenantID, "block", meta.BlockID.String(), "err", err)
+		metricDualWriteBlocks.WithLabelValues(meta.TenantID, dualWriteResultFailed).Inc()
+		return
+	}
+
+	metricDualWriteBlocks.WithLabelValues(meta.TenantID, dualWriteResultSuccess).Inc()
+	metricDualWriteObjects.WithLabelValues(meta.TenantID, dualWriteTargetSecondary).Add(float64(meta.TotalObjects))
+}
+
+func (d *dualWriter) shutdown() {
+	d.r.Shutdown()
+}
+
+// newSecondaryBackend creates a backend used next to the trace storage. It isn't cached and doesn't compact.
+func newSecondaryBackend(name string, localCfg *local.Config, gcsCfg *gcs.Config, s3Cfg *s3.Config, azureCfg *azure.Config) (backend.RawReader, backend.RawWriter, error) {
+	rawR, rawW, _, err := newSecondaryBackendWithCompactor(name, localCfg, gcsCfg, s3Cfg, azureCfg)
+	return rawR, rawW, err
+}
+
+// newSecondaryBackendWithCompactor creates a backend used next to the trace storage and its compactor.
+func newSecondaryBackendWithCompactor(name string, localCfg *local.Config, gcsCfg *gcs.Config, s3Cfg *s3.Config, azureCfg *azure.Config) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
+	var (
+		rawR backend.RawReader
+		rawW backend.RawWriter
+		c    backend.Compactor
+		err  error
+	)
+
+	switch name {
+	case backend.Local:
+		rawR, rawW, c, err = local.New(localCfg)
+	case backend.GCS:
+		rawR, rawW, c, err = gcs.New(gcsCfg)
+	case backend.S3:
+		rawR, rawW, c, err = s3.New(s3Cfg)
+	case backend.Azure:
+		rawR, rawW, c, err = azure.New(azureCfg)
+	default:
+		err = fmt.Errorf("unknown backend %s", name)
+	}
+
+	return rawR, rawW, c, err
+}
@@ -0,0 +1,162 @@
+package tempodb
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto" //nolint:all
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet3"
+)
+
+type testWriteableBlock struct {
+	common.BackendBlock
+	r backend.Reader
+}
+
+func (b *testWriteableBlock) Write(ctx context.Context, w backend.Writer) error {
+	return encoding.CopyBlock(ctx, b.BlockMeta(), b.r, w)
+}
+
+func TestDualWrite(t *testing.T) {
+	tcs := []struct {
+		name    string
+		version string
+		// direct completes the block into the trace storage instead of a local backend that is then written
+		direct bool
+	}{
+		{name: "same version", version: ""},
+		{name: "other version", version: vparquet3.VersionString},
+		{name: "same version direct", version: "", direct: true},
+		{name: "other version direct", version: vparquet3.VersionString, direct: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			tenant := uuid.NewString()
+			secondaryPath := path.Join(t.TempDir(), "secondary")
+
+			_, w, _, tempDir := testConfig(t, backend.EncNone, time.Minute, func(c *Config) {
+				c.DualWrite = DualWriteConfig{
+					Backend: backend.Local,
+					Local:   &local.Config{Path: secondaryPath},
+					Version: tc.version,
+				}
+			})
+			rw := w.(*readerWriter)
+
+			block, err := w.WAL().NewBlock(&backend.BlockMeta{BlockID: backend.NewUUID(), TenantID: tenant}, model.CurrentEncoding)
+			require.NoError(t, err)
+
+			dec := model.MustNewSegmentDecoder(model.CurrentEncoding)
+			ids := make([][]byte, 0, 10)
+			reqs := make([]*tempopb.Trace, 0, 10)
+			for i := 0; i < 10; i++ {
+				id := test.ValidTraceID(nil)
+				req := test.MakeTrace(3, id)
+				trace.SortTrace(req)
+				writeTraceToWal(t, block, dec, id, req, 0, 0)
+				ids = append(ids, id)
+				reqs = append(reqs, req)
+			}
+
+			ctx := context.Background()
+			if tc.direct {
+				_, err = w.CompleteBlock(ctx, block)
+				require.NoError(t, err)
+			} else {
+				rawR, rawW, _, err := local.New(&local.Config{Path: path.Join(tempDir, "ingester")})
+				require.NoError(t, err)
+				r := backend.NewReader(rawR)
+
+				complete, err := w.CompleteBlockWithBackend(ctx, block, common.BlockEncoding{}, r, backend.NewWriter(rawW))
+				require.NoError(t, err)
+
+				err = w.WriteBlock(ctx, &testWriteableBlock{BackendBlock: complete, r: r})
+				require.NoError(t, err)
+			}
+
+			expectedVersion := tc.version
+			if expectedVersion == "" {
+				expectedVersion = rw.cfg.Block.Version
+			}
+
+			meta, err := rw.dualWriter.r.BlockMeta(ctx, (uuid.UUID)(block.BlockMeta().BlockID), tenant)
+			require.NoError(t, err)
+			require.Equal(t, expectedVersion, meta.Version)
+			require.Equal(t, int64(10), meta.TotalObjects)
+
+			secondary, err := encoding.OpenBlock(meta, rw.dualWriter.r)
+			require.NoError(t, err)
+			for i, id := range ids {
+				found, err := secondary.FindTraceByID(ctx, id, common.DefaultSearchOptions())
+				require.NoError(t, err)
+				require.NotNil(t, found)
+				trace.SortTrace(found.Trace)
+				require.True(t, proto.Equal(found.Trace, reqs[i]))
+			}
+
+			require.Equal(t, 1.0, testutil.ToFloat64(metricDualWriteBlocks.WithLabelValues(tenant, dualWriteResultSuccess)))
+			require.Equal(t, 0.0, testutil.ToFloat64(metricDualWriteBlocks.WithLabelValues(tenant, dualWriteResultFailed)))
+			require.Equal(t, 0.0, testutil.ToFloat64(metricDualWriteBlocks.WithLabelValues(tenant, dualWriteResultSkipped)))
+			require.Equal(t, 10.0, testutil.ToFloat64(metricDualWriteObjects.WithLabelValues(tenant, dualWriteTargetPrimary)))
+			require.Equal(t, 10.0, testutil.ToFloat64(metricDualWriteObjects.WithLabelValues(tenant, dualWriteTargetSecondary)))
+			require.Empty(t, rw.dualWriter.converted)
+		})
+	}
+}
+
+func TestDualWriteSkipsUnconvertedBlocks(t *testing.T) {
+	tenant := uuid.NewString()
+
+	_, w, _, tempDir := testConfig(t, backend.EncNone, time.Minute, func(c *Config) {
+		c.DualWrite = DualWriteConfig{
+			Backend: backend.Local,
+			Local:   &local.Config{Path: path.Join(t.TempDir(), "secondary")},
+			Version: vparquet3.VersionString,
+		}
+	})
+	rw := w.(*readerWriter)
+
+	block, err := w.WAL().NewBlock(&backend.BlockMeta{BlockID: backend.NewUUID(), TenantID: tenant}, model.CurrentEncoding)
+	require.NoError(t, err)
+	id := test.ValidTraceID(nil)
+	writeTraceToWal(t, block, model.MustNewSegmentDecoder(model.CurrentEncoding), id, test.MakeTrace(3, id), 0, 0)
+
+	// complete the block without dual writing, e.g. before a restart
+	dualWriter := rw.dualWriter
+	rw.dualWriter = nil
+
+	ctx := context.Background()
+	rawR, rawW, _, err := local.New(&local.Config{Path: path.Join(tempDir, "ingester")})
+	require.NoError(t, err)
+	r := backend.NewReader(rawR)
+
+	complete, err := w.CompleteBlockWithBackend(ctx, block, common.BlockEncoding{}, r, backend.NewWriter(rawW))
+	require.NoError(t, err)
+
+	rw.dualWriter = dualWriter
+	err = w.WriteBlock(ctx, &testWriteableBlock{BackendBlock: complete, r: r})
+	require.NoError(t, err)
+
+	_, err = dualWriter.r.BlockMeta(ctx, (uuid.UUID)(block.BlockMeta().BlockID), tenant)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(metricDualWriteBlocks.WithLabelValues(tenant, dualWriteResultSkipped)))
+	require.Equal(t, 0.0, testutil.ToFloat64(metricDualWriteBlocks.WithLabelValues(tenant, dualWriteResultSuccess)))
+	require.Equal(t, 1.0, testutil.ToFloat64(metricDualWriteObjects.WithLabelValues(tenant, dualWriteTargetPrimary)))
+	require.Equal(t, 0.0, testutil.ToFloat64(metricDualWriteObjects.WithLabelValues(tenant, dualWriteTargetSecondary)))
+}
@@ -0,0 +1,279 @@
+package common
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+)
+
+const (
+	// AttributeCardinalityActionHash replaces the values of a key above the limit with a hashed bucket.
+	AttributeCardinalityActionHash = "hash"
+	// AttributeCardinalityActionDrop drops a key from the attributes once it is above the limit.
+	AttributeCardinalityActionDrop = "drop"
+
+	defaultAttributeCardinalityHashBuckets = 16
+
+	attributeScopeResource = "resource"
+	attributeScopeSpan     = "span"
+)
+
+// AttributeCardinalityPolicy limits the number of distinct values of resource and span attribute keys. Once a key
+// has MaxValues distinct values, other values are replaced with one of HashBuckets hashed buckets or the key is
+// dropped, depending on Action. Values are only counted approximately, by their hash.
+type AttributeCardinalityPolicy struct {
+	MaxValues   int    `yaml:"max_values,omitempty" json:"max_values,omitempty"`
+	Action      string `yaml:"action,omitempty" json:"action,omitempty"`
+	HashBuckets int    `yaml:"hash_buckets,omitempty" json:"hash_buckets,omitempty"`
+}
+
+// IsEmpty returns true if the policy does not limit any attributes.
+func (p AttributeCardinalityPolicy) IsEmpty() bool {
+	return p.MaxValues <= 0
+}
+
+// Validate returns an error if the policy is invalid.
+func (p AttributeCardinalityPolicy) Validate() error {
+	switch p.Action {
+	case "", AttributeCardinalityActionHash, AttributeCardinalityActionDrop:
+	default:
+		return fmt.Errorf("unknown attribute cardinality action %q, supported actions are %s and %s", p.Action, AttributeCardinalityActionHash, AttributeCardinalityActionDrop)
+	}
+	if p.HashBuckets < 0 {
+		return fmt.Errorf("attribute cardinality hash_buckets must not be negative")
+	}
+	return nil
+}
+
+// LimitedAttribute is an attribute key whose values exceeded the cardinality limit.
+type LimitedAttribute struct {
+	Scope string `json:"scope"`
+	Key   string `json:"key"`
+	// LimitedValues is the number of values that were replaced or dropped.
+	LimitedValues int `json:"limitedValues"`
+}
+
+type attributeKey struct {
+	scope string
+	key   string
+}
+
+// AttributeCardinalityLimiter enforces an AttributeCardinalityPolicy on traces. Values are counted in windows, e.g.
+// per block, that are started with Reset. A nil *AttributeCardinalityLimiter keeps all attributes.
+type AttributeCardinalityLimiter struct {
+	policy  AttributeCardinalityPolicy
+	buckets uint64
+
+	mtx      sync.Mutex
+	values   map[attributeKey]map[uint64]struct{}
+	limited  map[attributeKey]*LimitedAttribute
+	previous map[attributeKey]*LimitedAttribute
+}
+
+// NewAttributeCardinalityLimiter returns an AttributeCardinalityLimiter for the policy, or nil if the policy is
+// empty.
+func NewAttributeCardinalityLimiter(p AttributeCardinalityPolicy) *AttributeCardinalityLimiter {
+	if p.IsEmpty() {
+		return nil
+	}
+
+	buckets := p.HashBuckets
+	if buckets == 0 {
+		buckets = defaultAttributeCardinalityHashBuckets
+	}
+
+	return &AttributeCardinalityLimiter{
+		policy:  p,
+		buckets: uint64(buckets),
+		values:  map[attributeKey]map[uint64]struct{}{},
+		limited: map[attributeKey]*LimitedAttribute{},
+	}
+}
+
+// Policy returns the policy enforced by the limiter.
+func (l *AttributeCardinalityLimiter) Policy() AttributeCardinalityPolicy {
+	return l.policy
+}
+
+// Reset starts a new window. The values of the previous window are forgotten but its limited keys are still
+// reported by Limited until the end of the new window.
+func (l *AttributeCardinalityLimiter) Reset() {
+	if l == nil {
+		return
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.previous = l.limited
+	l.values = map[attributeKey]map[uint64]struct{}{}
+	l.limited = map[attributeKey]*LimitedAttribute{}
+}
+
+// LimitTrace replaces or drops the values of the attributes of the trace that exceed the cardinality limit. The
+// trace is modified in place. It returns the number of limited values.
+func (l *AttributeCardinalityLimiter) LimitTrace(tr *tempopb.Trace) int {
+	if l == nil || tr == nil {
+		return 0
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	limited := 0
+	for _, rs := range tr.ResourceSpans {
+		if rs.Resource != nil {
+			rs.Resource.Attributes, limited = l.limit(attributeScopeResource, rs.Resource.Attributes, limited)
+		}
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				s.Attributes, limited = l.limit(attributeScopeSpan, s.Attributes, limited)
+			}
+		}
+	}
+
+	return limited
+}
+
+// Limited returns the keys that exceeded the limit in the current or the previous window, sorted by scope and key.
+func (l *AttributeCardinalityLimiter) Limited() []LimitedAttribute {
+	if l == nil {
+		return nil
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	merged := make(map[attributeKey]LimitedAttribute, len(l.limited)+len(l.previous))
+	for k, a := range l.previous {
+		merged[k] = *a
+	}
+	for k, a := range l.limited {
+		// copy before adding the previous window, the stored attribute keeps counting the current one
+		la := *a
+		if p, ok := merged[k]; ok {
+			la.LimitedValues += p.LimitedValues
+		}
+		merged[k] = la
+	}
+
+	out := make([]LimitedAttribute, 0, len(merged))
+	for _, a := range merged {
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Scope != out[j].Scope {
+			return out[i].Scope < out[j].Scope
+		}
+		return out[i].Key < out[j].Key
+	})
+
+	return out
+}
+
+// NumLimited returns the number of keys that exceeded the limit in the current or the previous window.
+func (l *AttributeCardinalityLimiter) NumLimited() int {
+	if l == nil {
+		return 0
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	n := len(l.limited)
+	for k := range l.previous {
+		if _, ok := l.limited[k]; !ok {
+			n++
+		}
+	}
+	return n
+}
+
+func (l *AttributeCardinalityLimiter) limit(scope string, attrs []*v1_common.KeyValue, limited int) ([]*v1_common.KeyValue, int) {
+	kept := attrs[:0]
+	for _, a := range attrs {
+		if l.allow(scope, a) {
+			kept = append(kept, a)
+			continue
+		}
+
+		limited++
+		if l.policy.Action == AttributeCardinalityActionDrop {
+			continue
+		}
+
+		h, _ := hashAnyValue(a.Value)
+		a.Value = &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{
+			StringValue: fmt.Sprintf("__overflow_%d__", h%l.buckets),
+		}}
+		kept = append(kept, a)
+	}
+
+	// clear the tail so dropped attributes can be collected
+	for i := len(kept); i < len(attrs); i++ {
+		attrs[i] = nil
+	}
+
+	return kept, limited
+}
+
+// allow returns true if the value of the attribute is kept. Values of keys below the limit are counted and values
+// that were already counted are always kept.
+func (l *AttributeCardinalityLimiter) allow(scope string, a *v1_common.KeyValue) bool {
+	if a == nil || (scope == attributeScopeResource && a.Key == serviceNameKey) {
+		return true
+	}
+
+	h, ok := hashAnyValue(a.Value)
+	if !ok {
+		return true
+	}
+
+	k := attributeKey{scope: scope, key: a.Key}
+	values, ok := l.values[k]
+	if !ok {
+		values = map[uint64]struct{}{}
+		l.values[k] = values
+	}
+	if _, ok := values[h]; ok {
+		return true
+	}
+	if len(values) < l.policy.MaxValues {
+		values[h] = struct{}{}
+		return true
+	}
+
+	la, ok := l.limited[k]
+	if !ok {
+		la = &LimitedAttribute{Scope: scope, Key: a.Key}
+		l.limited[k] = la
+	}
+	la.LimitedValues++
+
+	return false
+}
+
+// hashAnyValue returns the hash of a scalar value. Arrays, maps and empty values are not counted.
+func hashAnyValue(v *v1_common.AnyValue) (uint64, bool) {
+	if v == nil {
+		return 0, false
+	}
+
+	switch v := v.Value.(type) {
+	case *v1_common.AnyValue_StringValue:
+		return xxhash.Sum64String(v.StringValue), true
+	case *v1_common.AnyValue_IntValue:
+		return uint64(v.IntValue), true
+	case *v1_common.AnyValue_DoubleValue:
+		return math.Float64bits(v.DoubleValue), true
+	case *v1_common.AnyValue_BytesValue:
+		return xxhash.Sum64(v.BytesValue), true
+	}
+	return 0, false
+}
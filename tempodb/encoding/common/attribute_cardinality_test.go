@@ -0,0 +1,127 @@
+package common
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestAttributeCardinalityPolicyValidate(t *testing.T) {
+	require.NoError(t, AttributeCardinalityPolicy{}.Validate())
+	require.NoError(t, AttributeCardinalityPolicy{MaxValues: 10, Action: AttributeCardinalityActionDrop}.Validate())
+	require.Error(t, AttributeCardinalityPolicy{MaxValues: 10, Action: "foo"}.Validate())
+	require.Error(t, AttributeCardinalityPolicy{MaxValues: 10, HashBuckets: -1}.Validate())
+
+	assert.True(t, AttributeCardinalityPolicy{}.IsEmpty())
+	assert.Nil(t, NewAttributeCardinalityLimiter(AttributeCardinalityPolicy{}))
+}
+
+func TestAttributeCardinalityLimiterHash(t *testing.T) {
+	l := NewAttributeCardinalityLimiter(AttributeCardinalityPolicy{MaxValues: 2, HashBuckets: 4})
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, 0, l.LimitTrace(testCardinalityTrace(strconv.Itoa(i))))
+	}
+
+	// values that were already seen are kept
+	tr := testCardinalityTrace("0")
+	assert.Equal(t, 0, l.LimitTrace(tr))
+	assert.Equal(t, "0", tr.ResourceSpans[0].ScopeSpans[0].Spans[0].Attributes[0].Value.GetStringValue())
+
+	// new values are replaced with a bucket
+	tr = testCardinalityTrace("2")
+	assert.Equal(t, 2, l.LimitTrace(tr))
+	rs := tr.ResourceSpans[0]
+	require.Len(t, rs.Resource.Attributes, 2)
+	assert.Equal(t, "2", rs.Resource.Attributes[0].Value.GetStringValue(), "service.name is never limited")
+	assert.Regexp(t, `^__overflow_[0-3]__$`, rs.Resource.Attributes[1].Value.GetStringValue())
+	assert.Regexp(t, `^__overflow_[0-3]__$`, rs.ScopeSpans[0].Spans[0].Attributes[0].Value.GetStringValue())
+
+	expected := []LimitedAttribute{
+		{Scope: attributeScopeResource, Key: "pod", LimitedValues: 1},
+		{Scope: attributeScopeSpan, Key: "user.id", LimitedValues: 1},
+	}
+	assert.Equal(t, expected, l.Limited())
+	assert.Equal(t, 2, l.NumLimited())
+
+	// limited keys are reported for one more window
+	l.Reset()
+	assert.Equal(t, 0, l.LimitTrace(testCardinalityTrace("3")))
+	assert.Equal(t, expected, l.Limited())
+
+	// keys limited in both windows are summed, repeatedly
+	assert.Equal(t, 0, l.LimitTrace(testCardinalityTrace("4")))
+	assert.Equal(t, 2, l.LimitTrace(testCardinalityTrace("5")))
+	expected = []LimitedAttribute{
+		{Scope: attributeScopeResource, Key: "pod", LimitedValues: 2},
+		{Scope: attributeScopeSpan, Key: "user.id", LimitedValues: 2},
+	}
+	assert.Equal(t, expected, l.Limited())
+	assert.Equal(t, expected, l.Limited())
+
+	l.Reset()
+	assert.Equal(t, []LimitedAttribute{
+		{Scope: attributeScopeResource, Key: "pod", LimitedValues: 1},
+		{Scope: attributeScopeSpan, Key: "user.id", LimitedValues: 1},
+	}, l.Limited())
+
+	l.Reset()
+	assert.Empty(t, l.Limited())
+}
+
+func TestAttributeCardinalityLimiterDrop(t *testing.T) {
+	l := NewAttributeCardinalityLimiter(AttributeCardinalityPolicy{MaxValues: 1, Action: AttributeCardinalityActionDrop})
+
+	assert.Equal(t, 0, l.LimitTrace(testCardinalityTrace("0")))
+
+	tr := testCardinalityTrace("1")
+	assert.Equal(t, 2, l.LimitTrace(tr))
+	rs := tr.ResourceSpans[0]
+	assert.Equal(t, []string{serviceNameKey}, attributeKeys(rs.Resource.Attributes))
+	assert.Empty(t, rs.ScopeSpans[0].Spans[0].Attributes)
+
+	// nil limiter does nothing
+	var nilLimiter *AttributeCardinalityLimiter
+	tr = testCardinalityTrace("2")
+	assert.Equal(t, 0, nilLimiter.LimitTrace(tr))
+	assert.Equal(t, testCardinalityTrace("2"), tr)
+	assert.Nil(t, nilLimiter.Limited())
+}
+
+func testCardinalityTrace(value string) *tempopb.Trace {
+	str := func(key string) *v1_common.KeyValue {
+		return &v1_common.KeyValue{Key: key, Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: value}}}
+	}
+
+	return &tempopb.Trace{
+		ResourceSpans: []*v1_trace.ResourceSpans{
+			{
+				Resource: &v1_resource.Resource{
+					Attributes: []*v1_common.KeyValue{str(serviceNameKey), str("pod")},
+				},
+				ScopeSpans: []*v1_trace.ScopeSpans{
+					{
+						Spans: []*v1_trace.Span{
+							{Attributes: []*v1_common.KeyValue{str("user.id")}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func attributeKeys(attrs []*v1_common.KeyValue) []string {
+	keys := make([]string, 0, len(attrs))
+	for _, a := range attrs {
+		keys = append(keys, a.Key)
+	}
+	return keys
+}
@@ -0,0 +1,152 @@
+package common
+
+import (
+	"context"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+)
+
+// serviceNameKey is never dropped by an attribute policy. Too much of Tempo relies on it.
+const serviceNameKey = "service.name"
+
+// AttributePolicy decides which attribute keys are stored in blocks. If Allow is non-empty
+// only the listed keys are stored. Keys listed in Deny are never stored. It applies to resource,
+// scope, span, event and link attributes.
+type AttributePolicy struct {
+	Allow []string `yaml:"allow,omitempty" json:"allow,omitempty"`
+	Deny  []string `yaml:"deny,omitempty" json:"deny,omitempty"`
+}
+
+// IsEmpty returns true if the policy does not drop any attributes.
+func (p AttributePolicy) IsEmpty() bool {
+	return len(p.Allow) == 0 && len(p.Deny) == 0
+}
+
+// AttributeFilter enforces an AttributePolicy on traces. A nil *AttributeFilter keeps all attributes.
+type AttributeFilter struct {
+	allow map[string]struct{}
+	deny  map[string]struct{}
+}
+
+// NewAttributeFilter returns an AttributeFilter for the policy, or nil if the policy is empty.
+func NewAttributeFilter(p AttributePolicy) *AttributeFilter {
+	if p.IsEmpty() {
+		return nil
+	}
+
+	f := &AttributeFilter{}
+	if len(p.Allow) > 0 {
+		f.allow = make(map[string]struct{}, len(p.Allow))
+		for _, k := range p.Allow {
+			f.allow[k] = struct{}{}
+		}
+	}
+	if len(p.Deny) > 0 {
+		f.deny = make(map[string]struct{}, len(p.Deny))
+		for _, k := range p.Deny {
+			f.deny[k] = struct{}{}
+		}
+	}
+
+	return f
+}
+
+// Keep returns true if an attribute with the given key should be stored.
+func (f *AttributeFilter) Keep(key string) bool {
+	if f == nil || key == serviceNameKey {
+		return true
+	}
+	if _, ok := f.deny[key]; ok {
+		return false
+	}
+	if f.allow != nil {
+		_, ok := f.allow[key]
+		return ok
+	}
+	return true
+}
+
+// FilterTrace removes all attributes from the trace that should not be stored. The trace is
+// modified in place. It returns the size in bytes of the removed attributes.
+func (f *AttributeFilter) FilterTrace(tr *tempopb.Trace) int {
+	if f == nil || tr == nil {
+		return 0
+	}
+
+	dropped := 0
+	for _, rs := range tr.ResourceSpans {
+		if rs.Resource != nil {
+			rs.Resource.Attributes, dropped = f.filter(rs.Resource.Attributes, dropped)
+		}
+		for _, ss := range rs.ScopeSpans {
+			if ss.Scope != nil {
+				ss.Scope.Attributes, dropped = f.filter(ss.Scope.Attributes, dropped)
+			}
+			for _, s := range ss.Spans {
+				s.Attributes, dropped = f.filter(s.Attributes, dropped)
+				for _, e := range s.Events {
+					e.Attributes, dropped = f.filter(e.Attributes, dropped)
+				}
+				for _, l := range s.Links {
+					l.Attributes, dropped = f.filter(l.Attributes, dropped)
+				}
+			}
+		}
+	}
+
+	return dropped
+}
+
+func (f *AttributeFilter) filter(attrs []*v1_common.KeyValue, dropped int) ([]*v1_common.KeyValue, int) {
+	kept := attrs[:0]
+	for _, a := range attrs {
+		if f.Keep(a.Key) {
+			kept = append(kept, a)
+			continue
+		}
+		dropped += a.Size()
+	}
+
+	// clear the tail so dropped attributes can be collected
+	for i := len(kept); i < len(attrs); i++ {
+		attrs[i] = nil
+	}
+
+	return kept, dropped
+}
+
+// attributeFilterIterator applies an AttributeFilter to every trace returned by the wrapped Iterator.
+type attributeFilterIterator struct {
+	Iterator
+	filter    *AttributeFilter
+	onDropped func(bytes int)
+}
+
+// NewAttributeFilterIterator wraps iter so that the filter is applied to every trace. onDropped is
+// called with the number of dropped bytes for every trace that had attributes removed and may be nil.
+// If the filter is nil iter is returned unchanged.
+func NewAttributeFilterIterator(iter Iterator, filter *AttributeFilter, onDropped func(bytes int)) Iterator {
+	if filter == nil {
+		return iter
+	}
+
+	return &attributeFilterIterator{
+		Iterator:  iter,
+		filter:    filter,
+		onDropped: onDropped,
+	}
+}
+
+func (i *attributeFilterIterator) Next(ctx context.Context) (ID, *tempopb.Trace, error) {
+	id, tr, err := i.Iterator.Next(ctx)
+	if err != nil || tr == nil {
+		return id, tr, err
+	}
+
+	if dropped := i.filter.FilterTrace(tr); dropped > 0 && i.onDropped != nil {
+		i.onDropped(dropped)
+	}
+
+	return id, tr, nil
+}
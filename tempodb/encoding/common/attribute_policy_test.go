@@ -0,0 +1,167 @@
+package common
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestAttributeFilterKeep(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   AttributePolicy
+		kept     []string
+		filtered []string
+	}{
+		{
+			name: "empty policy keeps everything",
+			kept: []string{"foo", "bar"},
+		},
+		{
+			name:     "deny",
+			policy:   AttributePolicy{Deny: []string{"debug.payload"}},
+			kept:     []string{"foo", "bar"},
+			filtered: []string{"debug.payload"},
+		},
+		{
+			name:     "allow",
+			policy:   AttributePolicy{Allow: []string{"foo"}},
+			kept:     []string{"foo", serviceNameKey},
+			filtered: []string{"bar"},
+		},
+		{
+			name:     "deny wins over allow",
+			policy:   AttributePolicy{Allow: []string{"foo", "bar"}, Deny: []string{"bar"}},
+			kept:     []string{"foo"},
+			filtered: []string{"bar", "baz"},
+		},
+		{
+			name:   "service name is never dropped",
+			policy: AttributePolicy{Deny: []string{serviceNameKey}},
+			kept:   []string{serviceNameKey},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewAttributeFilter(tc.policy)
+			for _, k := range tc.kept {
+				assert.True(t, f.Keep(k), k)
+			}
+			for _, k := range tc.filtered {
+				assert.False(t, f.Keep(k), k)
+			}
+		})
+	}
+}
+
+func TestAttributeFilterTrace(t *testing.T) {
+	f := NewAttributeFilter(AttributePolicy{Deny: []string{"drop"}})
+
+	tr := testAttributePolicyTrace()
+	dropped := f.FilterTrace(tr)
+
+	expectedDropped := 5 * kv("drop").Size()
+	assert.Equal(t, expectedDropped, dropped)
+
+	rs := tr.ResourceSpans[0]
+	assert.Equal(t, []*v1_common.KeyValue{kv(serviceNameKey)}, rs.Resource.Attributes)
+	assert.Equal(t, []*v1_common.KeyValue{kv("keep")}, rs.ScopeSpans[0].Scope.Attributes)
+	span := rs.ScopeSpans[0].Spans[0]
+	assert.Equal(t, []*v1_common.KeyValue{kv("keep")}, span.Attributes)
+	assert.Empty(t, span.Events[0].Attributes)
+	assert.Empty(t, span.Links[0].Attributes)
+
+	// nil filter does nothing
+	var nilFilter *AttributeFilter
+	tr = testAttributePolicyTrace()
+	assert.Equal(t, 0, nilFilter.FilterTrace(tr))
+	assert.Equal(t, testAttributePolicyTrace(), tr)
+}
+
+func TestAttributeFilterIterator(t *testing.T) {
+	iter := &sliceIterator{traces: []*tempopb.Trace{testAttributePolicyTrace(), testAttributePolicyTrace()}}
+
+	// no filter returns the iterator unchanged
+	assert.Same(t, Iterator(iter), NewAttributeFilterIterator(iter, nil, nil))
+
+	totalDropped := 0
+	filtered := NewAttributeFilterIterator(iter, NewAttributeFilter(AttributePolicy{Deny: []string{"drop"}}), func(bytes int) {
+		totalDropped += bytes
+	})
+
+	count := 0
+	for {
+		_, tr, err := filtered.Next(context.Background())
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, []*v1_common.KeyValue{kv("keep")}, tr.ResourceSpans[0].ScopeSpans[0].Spans[0].Attributes)
+		count++
+	}
+
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 2*5*kv("drop").Size(), totalDropped)
+}
+
+func kv(key string) *v1_common.KeyValue {
+	return &v1_common.KeyValue{
+		Key:   key,
+		Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "value"}},
+	}
+}
+
+func testAttributePolicyTrace() *tempopb.Trace {
+	return &tempopb.Trace{
+		ResourceSpans: []*v1_trace.ResourceSpans{
+			{
+				Resource: &v1_resource.Resource{
+					Attributes: []*v1_common.KeyValue{kv(serviceNameKey), kv("drop")},
+				},
+				ScopeSpans: []*v1_trace.ScopeSpans{
+					{
+						Scope: &v1_common.InstrumentationScope{
+							Attributes: []*v1_common.KeyValue{kv("drop"), kv("keep")},
+						},
+						Spans: []*v1_trace.Span{
+							{
+								Attributes: []*v1_common.KeyValue{kv("keep"), kv("drop")},
+								Events: []*v1_trace.Span_Event{
+									{Attributes: []*v1_common.KeyValue{kv("drop")}},
+								},
+								Links: []*v1_trace.Span_Link{
+									{Attributes: []*v1_common.KeyValue{kv("drop")}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+type sliceIterator struct {
+	traces []*tempopb.Trace
+}
+
+func (i *sliceIterator) Next(context.Context) (ID, *tempopb.Trace, error) {
+	if len(i.traces) == 0 {
+		return nil, nil, io.EOF
+	}
+
+	tr := i.traces[0]
+	i.traces = i.traces[1:]
+	return ID{0x01}, tr, nil
+}
+
+func (i *sliceIterator) Close() {}
@@ -0,0 +1,76 @@
+package common
+
+import (
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+)
+
+// RedactedValue replaces the attribute values removed by an AttributeRedaction.
+const RedactedValue = "[REDACTED]"
+
+// AttributeRedaction replaces the string values of an attribute key equal to Value with RedactedValue, e.g. to
+// forget a credential logged by accident. It applies to resource, scope, span, event and link attributes, and to
+// the string values of array attributes. A nil *AttributeRedaction keeps all values.
+type AttributeRedaction struct {
+	Key   string
+	Value string
+}
+
+// RedactTrace replaces the redacted values of the trace. The trace is modified in place. It returns the number of
+// values replaced.
+func (r *AttributeRedaction) RedactTrace(tr *tempopb.Trace) int {
+	if r == nil || tr == nil {
+		return 0
+	}
+
+	redacted := 0
+	for _, rs := range tr.ResourceSpans {
+		if rs.Resource != nil {
+			redacted += r.redact(rs.Resource.Attributes)
+		}
+		for _, ss := range rs.ScopeSpans {
+			if ss.Scope != nil {
+				redacted += r.redact(ss.Scope.Attributes)
+			}
+			for _, s := range ss.Spans {
+				redacted += r.redact(s.Attributes)
+				for _, e := range s.Events {
+					redacted += r.redact(e.Attributes)
+				}
+				for _, l := range s.Links {
+					redacted += r.redact(l.Attributes)
+				}
+			}
+		}
+	}
+
+	return redacted
+}
+
+func (r *AttributeRedaction) redact(attrs []*v1_common.KeyValue) int {
+	redacted := 0
+	for _, a := range attrs {
+		if a.Key != r.Key || a.Value == nil {
+			continue
+		}
+
+		switch v := a.Value.Value.(type) {
+		case *v1_common.AnyValue_StringValue:
+			if v.StringValue == r.Value {
+				v.StringValue = RedactedValue
+				redacted++
+			}
+		case *v1_common.AnyValue_ArrayValue:
+			if v.ArrayValue == nil {
+				continue
+			}
+			for _, av := range v.ArrayValue.Values {
+				if sv, ok := av.GetValue().(*v1_common.AnyValue_StringValue); ok && sv.StringValue == r.Value {
+					sv.StringValue = RedactedValue
+					redacted++
+				}
+			}
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,53 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+)
+
+func TestAttributeRedactionTrace(t *testing.T) {
+	r := &AttributeRedaction{Key: "drop", Value: "value"}
+
+	tr := testAttributePolicyTrace()
+	assert.Equal(t, 5, r.RedactTrace(tr))
+
+	redacted := &v1_common.KeyValue{
+		Key:   "drop",
+		Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: RedactedValue}},
+	}
+	rs := tr.ResourceSpans[0]
+	assert.Equal(t, []*v1_common.KeyValue{kv(serviceNameKey), redacted}, rs.Resource.Attributes)
+	assert.Equal(t, []*v1_common.KeyValue{redacted, kv("keep")}, rs.ScopeSpans[0].Scope.Attributes)
+	span := rs.ScopeSpans[0].Spans[0]
+	assert.Equal(t, []*v1_common.KeyValue{kv("keep"), redacted}, span.Attributes)
+	assert.Equal(t, []*v1_common.KeyValue{redacted}, span.Events[0].Attributes)
+	assert.Equal(t, []*v1_common.KeyValue{redacted}, span.Links[0].Attributes)
+
+	// only the string values of arrays equal to the value are replaced
+	span.Attributes = []*v1_common.KeyValue{{
+		Key: "drop",
+		Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_ArrayValue{ArrayValue: &v1_common.ArrayValue{Values: []*v1_common.AnyValue{
+			{Value: &v1_common.AnyValue_StringValue{StringValue: "value"}},
+			{Value: &v1_common.AnyValue_StringValue{StringValue: "other"}},
+			{Value: &v1_common.AnyValue_IntValue{IntValue: 1}},
+		}}}},
+	}}
+	assert.Equal(t, 1, r.RedactTrace(tr))
+	values := span.Attributes[0].Value.GetArrayValue().Values
+	assert.Equal(t, RedactedValue, values[0].GetStringValue())
+	assert.Equal(t, "other", values[1].GetStringValue())
+
+	// other values and keys are kept
+	tr = testAttributePolicyTrace()
+	assert.Equal(t, 0, (&AttributeRedaction{Key: "drop", Value: "other"}).RedactTrace(tr))
+	assert.Equal(t, 0, (&AttributeRedaction{Key: "other", Value: "value"}).RedactTrace(tr))
+	assert.Equal(t, testAttributePolicyTrace(), tr)
+
+	// nil redaction does nothing
+	var nilRedaction *AttributeRedaction
+	assert.Equal(t, 0, nilRedaction.RedactTrace(tr))
+	assert.Equal(t, testAttributePolicyTrace(), tr)
+}
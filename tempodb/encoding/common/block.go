@@ -7,6 +7,10 @@ import (
 
 var ErrUnsupported = errors.New("unsupported")
 
+// ErrCorruptBlockData is returned when the data object of a block is missing or can't be read, so nothing can be
+// rebuilt from it.
+var ErrCorruptBlockData = errors.New("block data is missing or corrupt")
+
 const (
 	// NameObjects names the backend data object
 	NameObjects = "data"
@@ -19,10 +19,43 @@ const (
 
 type ShardedBloomFilter struct {
 	blooms []*bloom.BloomFilter
+
+	// used by auto sized filters. trace ids are buffered until the filter is first read so the
+	// shard count can be derived from the number of ids actually added.
+	fp          float64
+	shardSize   uint
+	pendingIDs  []byte
+	pendingEnds []uint32
+}
+
+// NewBloomForConfig creates a ShardedBloomFilter using the bloom settings in cfg. If
+// cfg.BloomShardAutoSize is set the estimate is ignored and the filter is sized using the
+// number of ids that are added.
+func NewBloomForConfig(cfg *BlockConfig, estimatedObjects uint) *ShardedBloomFilter {
+	if cfg.BloomShardAutoSize {
+		return NewAutoSizedBloom(cfg.BloomFP, uint(cfg.BloomShardSizeBytes))
+	}
+	return NewBloom(cfg.BloomFP, uint(cfg.BloomShardSizeBytes), estimatedObjects)
+}
+
+// NewAutoSizedBloom creates a ShardedBloomFilter whose shard count is chosen using the number
+// of trace ids added instead of an estimate. ids are held in memory until the filter is
+// first tested, marshalled or its shard count is requested. No ids may be added after that.
+func NewAutoSizedBloom(fp float64, shardSize uint) *ShardedBloomFilter {
+	return &ShardedBloomFilter{
+		fp:        fp,
+		shardSize: shardSize,
+	}
 }
 
 // NewBloom creates a ShardedBloomFilter
 func NewBloom(fp float64, shardSize, estimatedObjects uint) *ShardedBloomFilter {
+	return &ShardedBloomFilter{
+		blooms: newBlooms(fp, shardSize, estimatedObjects),
+	}
+}
+
+func newBlooms(fp float64, shardSize, estimatedObjects uint) []*bloom.BloomFilter {
 	// estimate the number of shards needed
 	// m: number of bits in the filter
 	// k: number of hash functions
@@ -39,25 +72,31 @@ func NewBloom(fp float64, shardSize, estimatedObjects uint) *ShardedBloomFilter
 		level.Warn(log.Logger).Log("msg", "required bloom filter shard count exceeded max. consider increasing bloom_filter_shard_size_bytes")
 	}
 
-	b := &ShardedBloomFilter{
-		blooms: make([]*bloom.BloomFilter, shardCount),
-	}
-
+	blooms := make([]*bloom.BloomFilter, shardCount)
 	for i := 0; i < int(shardCount); i++ {
 		// New(m uint, k uint) creates a new Bloom filter with _m_ bits and _k_ hashing functions
-		b.blooms[i] = bloom.New(shardSize*8, k)
+		blooms[i] = bloom.New(shardSize*8, k)
 	}
 
-	return b
+	return blooms
 }
 
 func (b *ShardedBloomFilter) Add(traceID []byte) {
+	if b.blooms == nil {
+		// auto sized. copy the id, callers are free to reuse the slice
+		b.pendingIDs = append(b.pendingIDs, traceID...)
+		b.pendingEnds = append(b.pendingEnds, uint32(len(b.pendingIDs)))
+		return
+	}
+
 	shardKey := ShardKeyForTraceID(traceID, len(b.blooms))
 	b.blooms[shardKey].Add(traceID)
 }
 
 // Marshal is a wrapper around bloom.WriteTo
 func (b *ShardedBloomFilter) Marshal() ([][]byte, error) {
+	b.build()
+
 	bloomBytes := make([][]byte, len(b.blooms))
 	for i, f := range b.blooms {
 		bloomBuffer := &bytes.Buffer{}
@@ -71,15 +110,37 @@ func (b *ShardedBloomFilter) Marshal() ([][]byte, error) {
 }
 
 func (b *ShardedBloomFilter) GetShardCount() int {
+	b.build()
 	return len(b.blooms)
 }
 
 // Test implements bloom.Test -> required only for testing
 func (b *ShardedBloomFilter) Test(traceID []byte) bool {
+	b.build()
 	shardKey := ShardKeyForTraceID(traceID, len(b.blooms))
 	return b.blooms[shardKey].Test(traceID)
 }
 
+// build sizes and fills an auto sized filter from the buffered ids. It is a no-op once the
+// filter has been built.
+func (b *ShardedBloomFilter) build() {
+	if b.blooms != nil {
+		return
+	}
+
+	b.blooms = newBlooms(b.fp, b.shardSize, uint(len(b.pendingEnds)))
+
+	start := uint32(0)
+	for _, end := range b.pendingEnds {
+		traceID := b.pendingIDs[start:end]
+		b.blooms[ShardKeyForTraceID(traceID, len(b.blooms))].Add(traceID)
+		start = end
+	}
+
+	b.pendingIDs = nil
+	b.pendingEnds = nil
+}
+
 func ShardKeyForTraceID(traceID []byte, shardCount int) int {
 	return int(util.TokenForTraceID(traceID)) % ValidateShardCount(shardCount)
 }
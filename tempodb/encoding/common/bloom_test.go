@@ -151,3 +151,60 @@ func TestBloomShardCount(t *testing.T) {
 		})
 	}
 }
+
+func TestAutoSizedBloom(t *testing.T) {
+	const (
+		bloomFP   = .01
+		shardSize = uint(100)
+		numTraces = 10000
+	)
+
+	auto := NewAutoSizedBloom(bloomFP, shardSize)
+	// a badly underestimated static bloom for comparison
+	static := NewBloom(bloomFP, shardSize, 1)
+
+	traceIDs := make([][]byte, 0, numTraces)
+	id := make([]byte, 16)
+	for i := 0; i < numTraces; i++ {
+		_, err := crand.Read(id)
+		assert.NoError(t, err)
+		traceIDs = append(traceIDs, append([]byte(nil), id...))
+
+		// reuse the same slice to ensure the auto sized bloom copies ids
+		auto.Add(id)
+		static.Add(id)
+	}
+
+	assert.Equal(t, NewBloom(bloomFP, shardSize, numTraces).GetShardCount(), auto.GetShardCount())
+	assert.Greater(t, auto.GetShardCount(), static.GetShardCount())
+
+	for _, traceID := range traceIDs {
+		assert.True(t, auto.Test(traceID))
+	}
+
+	bloomBytes, err := auto.Marshal()
+	assert.NoError(t, err)
+	assert.Len(t, bloomBytes, auto.GetShardCount())
+
+	for i, singleBloom := range bloomBytes {
+		filter := &willf_bloom.BloomFilter{}
+		_, err = filter.ReadFrom(bytes.NewReader(singleBloom))
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, filter.EstimateFalsePositiveRate(numTraces/uint(auto.GetShardCount())), bloomFP, "shard %d", i)
+	}
+}
+
+func TestNewBloomForConfig(t *testing.T) {
+	cfg := &BlockConfig{
+		BloomFP:             .01,
+		BloomShardSizeBytes: 100,
+	}
+
+	b := NewBloomForConfig(cfg, 10000)
+	assert.Equal(t, NewBloom(cfg.BloomFP, uint(cfg.BloomShardSizeBytes), 10000).GetShardCount(), b.GetShardCount())
+
+	cfg.BloomShardAutoSize = true
+	b = NewBloomForConfig(cfg, 10000)
+	b.Add([]byte{0x01})
+	assert.Equal(t, minShardCount, b.GetShardCount())
+}
@@ -15,14 +15,28 @@ const (
 	DefaultIndexPageSizeBytes   = 250 * 1024
 )
 
+const (
+	ParquetCompressionSnappy = "snappy"
+	ParquetCompressionZstd   = "zstd"
+	ParquetCompressionNone   = "none"
+)
+
 // BlockConfig holds configuration options for newly created blocks
 type BlockConfig struct {
 	BloomFP             float64          `yaml:"bloom_filter_false_positive"`
 	BloomShardSizeBytes int              `yaml:"bloom_filter_shard_size_bytes"`
+	BloomShardAutoSize  bool             `yaml:"bloom_filter_shard_auto_size"`
 	Version             string           `yaml:"version"`
 	SearchEncoding      backend.Encoding `yaml:"search_encoding"`
 	SearchPageSizeBytes int              `yaml:"search_page_size_bytes"`
 
+	// size of the trace ID summary stored in the block meta, 0 disables it. Only supported by vParquet4.
+	TraceIDSummarySizeBytes int `yaml:"trace_id_summary_size_bytes"`
+
+	// max number of distinct service and span names of the name dictionary written next to the block, 0 disables
+	// it. Only supported by vParquet4.
+	NameDictionaryMaxValues int `yaml:"name_dictionary_max_values"`
+
 	// v2 fields
 	IndexDownsampleBytes int              `yaml:"v2_index_downsample_bytes"`
 	IndexPageSizeBytes   int              `yaml:"v2_index_page_size_bytes"`
@@ -34,6 +48,11 @@ type BlockConfig struct {
 	// vParquet3 fields
 	DedicatedColumns backend.DedicatedColumns `yaml:"parquet_dedicated_columns"`
 
+	// vParquet4 fields, set by the BlockEncoding of a tenant. See BlockEncoding.
+	ParquetCompression       string `yaml:"-"`
+	ParquetZstdLevel         int    `yaml:"-"`
+	ParquetDisableDictionary bool   `yaml:"-"`
+
 	// used internally. If true, the block will be created by default with the nocompact flag set.
 	CreateWithNoCompactFlag bool `yaml:"-"`
 }
@@ -41,6 +60,9 @@ type BlockConfig struct {
 func (cfg *BlockConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	f.Float64Var(&cfg.BloomFP, util.PrefixConfig(prefix, "trace.block.v2-bloom-filter-false-positive"), DefaultBloomFP, "Bloom Filter False Positive.")
 	f.IntVar(&cfg.BloomShardSizeBytes, util.PrefixConfig(prefix, "trace.block.v2-bloom-filter-shard-size-bytes"), DefaultBloomShardSizeBytes, "Bloom Filter Shard Size in bytes.")
+	f.BoolVar(&cfg.BloomShardAutoSize, util.PrefixConfig(prefix, "trace.block.v2-bloom-filter-shard-auto-size"), false, "Size the bloom filter shard count from the number of trace IDs written to the block instead of an estimate.")
+	f.IntVar(&cfg.TraceIDSummarySizeBytes, util.PrefixConfig(prefix, "trace.block.trace-id-summary-size-bytes"), 0, "Size in bytes of the bloom filter of trace IDs stored in the block meta and the tenant index. 0 disables it.")
+	f.IntVar(&cfg.NameDictionaryMaxValues, util.PrefixConfig(prefix, "trace.block.name-dictionary-max-values"), 0, "Max number of distinct service and span names of the name dictionary written next to the block. 0 disables it.")
 	f.IntVar(&cfg.IndexDownsampleBytes, util.PrefixConfig(prefix, "trace.block.v2-index-downsample-bytes"), DefaultIndexDownSampleBytes, "Number of bytes (before compression) per index record.")
 	f.IntVar(&cfg.IndexPageSizeBytes, util.PrefixConfig(prefix, "trace.block.v2-index-page-size-bytes"), DefaultIndexPageSizeBytes, "Number of bytes per index page.")
 	// cfg.Version = encoding.DefaultEncoding().Version() // Cyclic dependency - ugh
@@ -68,5 +90,69 @@ func ValidateConfig(b *BlockConfig) error {
 		return fmt.Errorf("positive value required for bloom-filter shard size")
 	}
 
+	if b.TraceIDSummarySizeBytes < 0 {
+		return fmt.Errorf("trace id summary size must not be negative")
+	}
+
+	if b.NameDictionaryMaxValues < 0 {
+		return fmt.Errorf("name dictionary max values must not be negative")
+	}
+
 	return b.DedicatedColumns.Validate()
 }
+
+// BlockEncoding overrides how the blocks of a tenant are encoded. The zero value keeps the BlockConfig as is.
+type BlockEncoding struct {
+	// Version pins the version of the blocks created for the tenant, compacted blocks included. Blocks of other versions
+	// are converted to it before they are compacted, v2 blocks only if converting them is enabled for the tenant.
+	// Defaults to the configured block version.
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+	// RowGroupSizeBytes is the size of the parquet row groups.
+	RowGroupSizeBytes int `yaml:"parquet_row_group_size_bytes,omitempty" json:"parquet_row_group_size_bytes,omitempty"`
+	// Compression is the codec of all parquet columns: snappy, zstd or none. Defaults to the codec of each column.
+	// Only supported by vParquet4.
+	Compression string `yaml:"parquet_compression,omitempty" json:"parquet_compression,omitempty"`
+	// ZstdLevel is the zstd compression level, from 1 to 22. Defaults to 3. Only supported by vParquet4.
+	ZstdLevel int `yaml:"parquet_zstd_level,omitempty" json:"parquet_zstd_level,omitempty"`
+	// DisableDictionary writes all parquet columns with plain encoding. Only supported by vParquet4.
+	DisableDictionary bool `yaml:"parquet_disable_dictionary,omitempty" json:"parquet_disable_dictionary,omitempty"`
+}
+
+// Validate returns an error if the encoding is invalid
+func (e BlockEncoding) Validate() error {
+	if e.RowGroupSizeBytes < 0 {
+		return fmt.Errorf("parquet_row_group_size_bytes must not be negative")
+	}
+
+	switch e.Compression {
+	case "", ParquetCompressionSnappy, ParquetCompressionZstd, ParquetCompressionNone:
+	default:
+		return fmt.Errorf("parquet_compression %q is not a valid value, valid values: %s, %s, %s", e.Compression, ParquetCompressionSnappy, ParquetCompressionZstd, ParquetCompressionNone)
+	}
+
+	if e.ZstdLevel < 0 || e.ZstdLevel > 22 {
+		return fmt.Errorf("parquet_zstd_level must be between 1 and 22")
+	}
+
+	return nil
+}
+
+// ApplyTo returns a copy of cfg with the encoding overrides applied.
+func (e BlockEncoding) ApplyTo(cfg BlockConfig) BlockConfig {
+	if e.Version != "" {
+		cfg.Version = e.Version
+	}
+	if e.RowGroupSizeBytes > 0 {
+		cfg.RowGroupSizeBytes = e.RowGroupSizeBytes
+	}
+	if e.Compression != "" {
+		cfg.ParquetCompression = e.Compression
+	}
+	if e.ZstdLevel > 0 {
+		cfg.ParquetZstdLevel = e.ZstdLevel
+	}
+	if e.DisableDictionary {
+		cfg.ParquetDisableDictionary = true
+	}
+	return cfg
+}
@@ -0,0 +1,64 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+// SortedUniqueIDs returns a copy of ids sorted ascending with duplicates removed.
+func SortedUniqueIDs(ids []ID) []ID {
+	sorted := make([]ID, len(ids))
+	copy(sorted, ids)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) == -1
+	})
+
+	unique := sorted[:0]
+	for _, id := range sorted {
+		if len(unique) > 0 && bytes.Equal(unique[len(unique)-1], id) {
+			continue
+		}
+		unique = append(unique, id)
+	}
+
+	return unique
+}
+
+// TracesForIDs returns the traces in found in the order of ids. Ids that were not found map to nil.
+func TracesForIDs(ids []ID, found *IDMap[*tempopb.Trace]) []*tempopb.Trace {
+	traces := make([]*tempopb.Trace, len(ids))
+	for i, id := range ids {
+		traces[i], _ = found.Get(id)
+	}
+
+	return traces
+}
+
+// FindTraceByIDsOneByOne implements Finder.FindTraceByIDs by calling find for every id. It is used by blocks
+// that can't do better than individual lookups, like WAL blocks that are entirely local.
+func FindTraceByIDsOneByOne(ctx context.Context, ids []ID, opts SearchOptions, find func(context.Context, ID, SearchOptions) (*tempopb.TraceByIDResponse, error)) ([]*tempopb.Trace, *tempopb.TraceByIDMetrics, error) {
+	metrics := &tempopb.TraceByIDMetrics{}
+	found := NewIDMap[*tempopb.Trace](len(ids))
+
+	for _, id := range SortedUniqueIDs(ids) {
+		resp, err := find(ctx, id, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp == nil {
+			continue
+		}
+
+		if resp.Metrics != nil {
+			metrics.InspectedBytes += resp.Metrics.InspectedBytes
+		}
+		if resp.Trace != nil {
+			found.Set(id, resp.Trace)
+		}
+	}
+
+	return TracesForIDs(ids, found), metrics, nil
+}
@@ -0,0 +1,51 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func TestSortedUniqueIDs(t *testing.T) {
+	ids := []ID{{0x03}, {0x01}, {0x02}, {0x01}, {0x03}}
+
+	assert.Equal(t, []ID{{0x01}, {0x02}, {0x03}}, SortedUniqueIDs(ids))
+	assert.Equal(t, []ID{{0x03}, {0x01}, {0x02}, {0x01}, {0x03}}, ids) // input is not modified
+	assert.Empty(t, SortedUniqueIDs(nil))
+}
+
+func TestFindTraceByIDsOneByOne(t *testing.T) {
+	tr1 := &tempopb.Trace{}
+	tr2 := &tempopb.Trace{}
+	block := map[string]*tempopb.Trace{
+		string([]byte{0x01}): tr1,
+		string([]byte{0x02}): tr2,
+	}
+
+	var calls []ID
+	find := func(_ context.Context, id ID, _ SearchOptions) (*tempopb.TraceByIDResponse, error) {
+		calls = append(calls, id)
+		tr, ok := block[string(id)]
+		if !ok {
+			return nil, nil
+		}
+		return &tempopb.TraceByIDResponse{Trace: tr, Metrics: &tempopb.TraceByIDMetrics{InspectedBytes: 10}}, nil
+	}
+
+	traces, metrics, err := FindTraceByIDsOneByOne(context.Background(), []ID{{0x02}, {0x03}, {0x01}, {0x02}}, SearchOptions{}, find)
+	require.NoError(t, err)
+
+	require.Len(t, traces, 4)
+	assert.Same(t, tr2, traces[0])
+	assert.Nil(t, traces[1])
+	assert.Same(t, tr1, traces[2])
+	assert.Same(t, tr2, traces[3])
+	assert.Equal(t, uint64(20), metrics.InspectedBytes)
+
+	// every id is only looked up once and in order
+	assert.Equal(t, []ID{{0x01}, {0x02}, {0x03}}, calls)
+}
@@ -14,6 +14,9 @@ import (
 
 type Finder interface {
 	FindTraceByID(ctx context.Context, id ID, opts SearchOptions) (*tempopb.TraceByIDResponse, error)
+	// FindTraceByIDs finds multiple traces in a single pass over the block. The returned traces are in the
+	// same order as ids and nil for every id that is not in the block.
+	FindTraceByIDs(ctx context.Context, ids []ID, opts SearchOptions) ([]*tempopb.Trace, *tempopb.TraceByIDMetrics, error)
 }
 
 type (
@@ -44,6 +47,11 @@ type SearchOptions struct {
 	ReadBufferCount    int
 	ReadBufferSize     int
 	RF1After           time.Time // Only blocks with RF1 are selected after this timestamp. RF3 is selected otherwise.
+	// How many row groups that may contain the trace are read in parallel when finding a trace by id. vParquet4 only.
+	RowGroupConcurrency int
+	// Prefetch together the column chunks that are learned to be read by the queries of a tenant and query class.
+	// TraceQL queries of vParquet4 only.
+	AdaptiveReadAhead bool
 }
 
 // DefaultSearchOptions is used in a lot of places such as local ingester searches. It is important
@@ -73,11 +81,28 @@ type CompactionOptions struct {
 	ChunkSizeBytes     uint32
 	FlushSizeBytes     uint32
 	IteratorBufferSize int // How many traces to prefetch async.
+	ReadAheadChunks    int // How many chunks of ChunkSizeBytes to read concurrently ahead of iteration. v2 only.
 	MaxBytesPerTrace   int
 	OutputBlocks       uint8
 	BlockConfig        BlockConfig
 	Combiner           model.ObjectCombiner
 
+	// AttributeFilter removes attributes that should not be stored from compacted traces. It is
+	// currently enforced by vParquet4 only. Nil keeps all attributes.
+	AttributeFilter *AttributeFilter
+
+	// AttributeRedaction replaces attribute values of compacted traces, e.g. a credential logged by accident. It is
+	// currently enforced by vParquet4 only. Nil keeps all values.
+	AttributeRedaction *AttributeRedaction
+
+	// DedicatedColumns are the dedicated columns of the output blocks. The attributes of the compacted traces are
+	// moved between the generic and the dedicated columns accordingly. It is currently supported by vParquet4 only.
+	// Nil keeps the dedicated columns of the input blocks.
+	DedicatedColumns backend.DedicatedColumns
+
+	// RetentionClass is the retention class of the output blocks.
+	RetentionClass string
+
 	// DropObject can be used to drop a trace from the compaction process. Currently it only receives the ID
 	// of the trace to be compacted. If the function returns true, the trace will be dropped.
 	DropObject func(ID) bool
@@ -89,6 +114,12 @@ type CompactionOptions struct {
 	DisconnectedTrace func()
 	RootlessTrace     func()
 	DedupedSpans      func(replFactor, dedupedSpans int)
+	AttributesDropped func(bytes int)
+	// AttributesRedacted is called with the number of values replaced by the AttributeRedaction of each trace.
+	AttributesRedacted func(values int)
+	// TraceDeduped is called for each trace found in several input blocks with the output block it's written to,
+	// the number of duplicate objects merged into it and the estimated bytes saved by merging them. vParquet4 only.
+	TraceDeduped func(block backend.UUID, traceID ID, duplicates, dedupedBytes int)
 }
 
 type Iterator interface {
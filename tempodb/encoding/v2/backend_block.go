@@ -3,9 +3,14 @@ package v2
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"sync/atomic"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	willf_bloom "github.com/willf/bloom"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -18,6 +23,22 @@ import (
 	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
+const (
+	bloomResultNegative      = "negative"
+	bloomResultTruePositive  = "true_positive"
+	bloomResultFalsePositive = "false_positive"
+)
+
+// validateChunkSizeBytes is the amount of object data read at once by Validate
+const validateChunkSizeBytes = 5 * 1024 * 1024
+
+// the per tenant false positive rate is false_positive / (false_positive + negative)
+var metricBloomFilterTests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempodb",
+	Name:      "bloom_filter_tests_total",
+	Help:      "Total number of bloom filter tests performed when finding a trace by ID, by outcome.",
+}, []string{"tenant", "result"})
+
 // BackendBlock represents a block already in the backend.
 type BackendBlock struct {
 	meta   *backend.BlockMeta
@@ -51,26 +72,15 @@ func (b *BackendBlock) find(ctx context.Context, id common.ID) ([]byte, error) {
 
 	span.SetAttributes(attribute.String("block", b.meta.BlockID.String()))
 
-	shardKey := common.ShardKeyForTraceID(id, int(b.meta.BloomShardCount))
-	blockID := b.meta.BlockID
 	tenantID := b.meta.TenantID
 
-	nameBloom := common.BloomName(shardKey)
-	bloomBytes, err := b.reader.Read(ctx, nameBloom, (uuid.UUID)(blockID), tenantID, &backend.CacheInfo{
-		Meta: b.meta,
-		Role: cache.RoleBloom,
-	})
+	filter, err := b.bloomFilter(ctx, common.ShardKeyForTraceID(id, int(b.meta.BloomShardCount)))
 	if err != nil {
-		return nil, fmt.Errorf("error retrieving bloom %s (%s, %s): %w", nameBloom, b.meta.TenantID, b.meta.BlockID, err)
-	}
-
-	filter := &willf_bloom.BloomFilter{}
-	_, err = filter.ReadFrom(bytes.NewReader(bloomBytes))
-	if err != nil {
-		return nil, fmt.Errorf("error parsing bloom (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
+		return nil, err
 	}
 
 	if !filter.Test(id) {
+		metricBloomFilterTests.WithLabelValues(tenantID, bloomResultNegative).Inc()
 		return nil, nil
 	}
 
@@ -94,9 +104,111 @@ func (b *BackendBlock) find(ctx context.Context, id common.ID) ([]byte, error) {
 		return nil, fmt.Errorf("error using pageFinder (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
 	}
 
+	if objectBytes == nil {
+		metricBloomFilterTests.WithLabelValues(tenantID, bloomResultFalsePositive).Inc()
+	} else {
+		metricBloomFilterTests.WithLabelValues(tenantID, bloomResultTruePositive).Inc()
+	}
+
 	return objectBytes, nil
 }
 
+// findMany finds all ids with a single pass over the block. Every bloom shard is read at most once and ids
+// ruled out by the bloom are not searched. ids must be sorted ascending and unique. The returned objects are
+// in the same order as ids.
+func (b *BackendBlock) findMany(ctx context.Context, ids []common.ID) ([][]byte, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "BackendBlock.findMany")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "")
+		}
+		span.End()
+	}()
+
+	span.SetAttributes(attribute.String("block", b.meta.BlockID.String()), attribute.Int("ids", len(ids)))
+
+	tenantID := b.meta.TenantID
+	shardCount := int(b.meta.BloomShardCount)
+	filters := map[int]*willf_bloom.BloomFilter{}
+
+	candidates := make([]common.ID, 0, len(ids))
+	candidateIdxs := make([]int, 0, len(ids))
+	for i, id := range ids {
+		shardKey := common.ShardKeyForTraceID(id, shardCount)
+		filter, ok := filters[shardKey]
+		if !ok {
+			filter, err = b.bloomFilter(ctx, shardKey)
+			if err != nil {
+				return nil, err
+			}
+			filters[shardKey] = filter
+		}
+
+		if !filter.Test(id) {
+			metricBloomFilterTests.WithLabelValues(tenantID, bloomResultNegative).Inc()
+			continue
+		}
+		candidates = append(candidates, id)
+		candidateIdxs = append(candidateIdxs, i)
+	}
+
+	found := make([][]byte, len(ids))
+	if len(candidates) == 0 {
+		return found, nil
+	}
+
+	indexReader, err := b.NewIndexReader()
+	if err != nil {
+		return nil, err
+	}
+
+	ra := backend.NewContextReader(b.meta, common.NameObjects, b.reader)
+	dataReader, err := NewDataReader(ra, b.meta.Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("error building page reader (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
+	}
+	defer dataReader.Close()
+
+	// passing nil for objectCombiner here.  this is fine b/c a backend block should never have dupes
+	finder := newPagedFinder(indexReader, dataReader, nil, NewObjectReaderWriter(), b.meta.DataEncoding)
+	objects, err := finder.FindMany(ctx, candidates)
+	if err != nil {
+		return nil, fmt.Errorf("error using pageFinder (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
+	}
+
+	for i, obj := range objects {
+		if obj == nil {
+			metricBloomFilterTests.WithLabelValues(tenantID, bloomResultFalsePositive).Inc()
+			continue
+		}
+		metricBloomFilterTests.WithLabelValues(tenantID, bloomResultTruePositive).Inc()
+		found[candidateIdxs[i]] = obj
+	}
+
+	return found, nil
+}
+
+func (b *BackendBlock) bloomFilter(ctx context.Context, shardKey int) (*willf_bloom.BloomFilter, error) {
+	nameBloom := common.BloomName(shardKey)
+	bloomBytes, err := b.reader.Read(ctx, nameBloom, (uuid.UUID)(b.meta.BlockID), b.meta.TenantID, &backend.CacheInfo{
+		Meta: b.meta,
+		Role: cache.RoleBloom,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving bloom %s (%s, %s): %w", nameBloom, b.meta.TenantID, b.meta.BlockID, err)
+	}
+
+	filter := &willf_bloom.BloomFilter{}
+	_, err = filter.ReadFrom(bytes.NewReader(bloomBytes))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing bloom (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
+	}
+
+	return filter, nil
+}
+
 // Iterator returns an Iterator that iterates over the objects in the block from the backend
 func (b *BackendBlock) Iterator(chunkSizeBytes uint32) (BytesIterator, error) {
 	// read index
@@ -114,6 +226,54 @@ func (b *BackendBlock) Iterator(chunkSizeBytes uint32) (BytesIterator, error) {
 	return newPagedIterator(chunkSizeBytes, reader, dataReader, NewObjectReaderWriter()), nil
 }
 
+// IteratorWithReadAhead returns an Iterator that iterates over the objects in the block from the backend
+// while concurrently prefetching up to readAheadChunks chunks of chunkSizeBytes. This hides the latency of
+// object storage during compaction. If readAheadChunks is <= 0 it behaves like Iterator.
+func (b *BackendBlock) IteratorWithReadAhead(ctx context.Context, chunkSizeBytes uint32, readAheadChunks int) (BytesIterator, error) {
+	if readAheadChunks <= 0 {
+		return b.Iterator(chunkSizeBytes)
+	}
+
+	dataReaders := make([]DataReader, 0, readAheadChunks)
+	for range readAheadChunks {
+		ra := backend.NewContextReader(b.meta, common.NameObjects, b.reader)
+		dataReader, err := NewDataReader(ra, b.meta.Encoding)
+		if err != nil {
+			for _, dr := range dataReaders {
+				dr.Close()
+			}
+			return nil, fmt.Errorf("failed to create dataReader (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
+		}
+		dataReaders = append(dataReaders, dataReader)
+	}
+
+	reader, err := b.NewIndexReader()
+	if err != nil {
+		for _, dr := range dataReaders {
+			dr.Close()
+		}
+		return nil, err
+	}
+
+	return newReadAheadIterator(ctx, chunkSizeBytes, reader, dataReaders, NewObjectReaderWriter()), nil
+}
+
+// TraceIterator returns a common.Iterator over the decoded traces of the block. It is used to convert the block
+// to other encodings.
+func (b *BackendBlock) TraceIterator(ctx context.Context, chunkSizeBytes uint32, readAheadChunks int) (common.Iterator, error) {
+	iter, err := b.IteratorWithReadAhead(ctx, chunkSizeBytes, readAheadChunks)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err = NewDedupingIterator(iter, model.StaticCombiner, b.meta.DataEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	return iter.(*dedupingIterator), nil
+}
+
 func (b *BackendBlock) NewIndexReader() (IndexReader, error) {
 	indexReaderAt := backend.NewContextReader(b.meta, common.NameIndex, b.reader)
 	reader, err := NewIndexReader(indexReaderAt, int(b.meta.IndexPageSize), int(b.meta.TotalRecords))
@@ -154,6 +314,60 @@ func (b *BackendBlock) FindTraceByID(ctx context.Context, id common.ID, _ common
 	}, err
 }
 
+// FindTraceByIDs implements common.Finder
+func (b *BackendBlock) FindTraceByIDs(ctx context.Context, ids []common.ID, _ common.SearchOptions) ([]*tempopb.Trace, *tempopb.TraceByIDMetrics, error) {
+	ctx, span := tracer.Start(ctx, "BackendBlock.FindTraceByIDs")
+	defer span.End()
+
+	// count the bytes read from the backend on a copy of the block
+	counting := &bytesCountingReader{Reader: b.reader}
+	unique := common.SortedUniqueIDs(ids)
+	objs, err := (&BackendBlock{meta: b.meta, reader: counting}).findMany(ctx, unique)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dec, err := model.NewObjectDecoder(b.meta.DataEncoding)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	found := common.NewIDMap[*tempopb.Trace](len(unique))
+	for i, obj := range objs {
+		if obj == nil {
+			continue
+		}
+
+		trace, err := dec.PrepareForRead(obj)
+		if err != nil {
+			return nil, nil, err
+		}
+		found.Set(unique[i], trace)
+	}
+
+	return common.TracesForIDs(ids, found), &tempopb.TraceByIDMetrics{InspectedBytes: counting.bytesRead.Load()}, nil
+}
+
+// bytesCountingReader is a backend.Reader counting the bytes of the objects read through it.
+type bytesCountingReader struct {
+	backend.Reader
+	bytesRead atomic.Uint64
+}
+
+func (r *bytesCountingReader) Read(ctx context.Context, name string, blockID uuid.UUID, tenantID string, cacheInfo *backend.CacheInfo) ([]byte, error) {
+	b, err := r.Reader.Read(ctx, name, blockID, tenantID, cacheInfo)
+	r.bytesRead.Add(uint64(len(b)))
+	return b, err
+}
+
+func (r *bytesCountingReader) ReadRange(ctx context.Context, name string, blockID uuid.UUID, tenantID string, offset uint64, buffer []byte, cacheInfo *backend.CacheInfo) error {
+	err := r.Reader.ReadRange(ctx, name, blockID, tenantID, offset, buffer, cacheInfo)
+	if err == nil {
+		r.bytesRead.Add(uint64(len(buffer)))
+	}
+	return err
+}
+
 func (b *BackendBlock) Search(context.Context, *tempopb.SearchRequest, common.SearchOptions) (resp *tempopb.SearchResponse, err error) {
 	return nil, common.ErrUnsupported
 }
@@ -182,6 +396,107 @@ func (b *BackendBlock) FetchTagNames(context.Context, traceql.FetchTagsRequest,
 	return common.ErrUnsupported
 }
 
-func (b *BackendBlock) Validate(_ context.Context) error {
-	return common.ErrUnsupported
+// Validate reads the entire block and confirms it is intact. Index page checksums are verified,
+// index records must be ordered and cover the objects file exactly, every bloom shard must parse
+// and contain all stored ids, and the number of objects must match meta.TotalObjects.
+func (b *BackendBlock) Validate(ctx context.Context) error {
+	if b.meta == nil {
+		return errors.New("block meta is nil")
+	}
+
+	err := b.validateIndex(ctx)
+	if err != nil {
+		return err
+	}
+
+	shardCount := common.ValidateShardCount(int(b.meta.BloomShardCount))
+	filters := make([]*willf_bloom.BloomFilter, shardCount)
+	for i := range filters {
+		nameBloom := common.BloomName(i)
+		bloomBytes, err := b.reader.Read(ctx, nameBloom, (uuid.UUID)(b.meta.BlockID), b.meta.TenantID, nil)
+		if err != nil {
+			return fmt.Errorf("error retrieving bloom %s: %w", nameBloom, err)
+		}
+
+		filters[i] = &willf_bloom.BloomFilter{}
+		_, err = filters[i].ReadFrom(bytes.NewReader(bloomBytes))
+		if err != nil {
+			return fmt.Errorf("error parsing bloom %s: %w", nameBloom, err)
+		}
+	}
+
+	iter, err := b.Iterator(validateChunkSizeBytes)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	var (
+		prevID  common.ID
+		objects int64
+	)
+	for {
+		id, _, err := iter.NextBytes(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading object %d: %w", objects, err)
+		}
+
+		if prevID != nil && bytes.Compare(prevID, id) >= 0 {
+			return fmt.Errorf("object %d is out of order", objects)
+		}
+		if !filters[common.ShardKeyForTraceID(id, shardCount)].Test(id) {
+			return fmt.Errorf("object %d is missing from the bloom filter", objects)
+		}
+
+		prevID = append(prevID[:0], id...)
+		objects++
+	}
+
+	if objects != b.meta.TotalObjects {
+		return fmt.Errorf("found %d objects, expected %d", objects, b.meta.TotalObjects)
+	}
+
+	return nil
+}
+
+// validateIndex reads every index record. The index reader verifies page checksums.
+func (b *BackendBlock) validateIndex(ctx context.Context) error {
+	indexReader, err := b.NewIndexReader()
+	if err != nil {
+		return err
+	}
+
+	var prev *Record
+	for i := 0; i < int(b.meta.TotalRecords); i++ {
+		record, err := indexReader.At(ctx, i)
+		if err != nil {
+			return fmt.Errorf("error reading index record %d: %w", i, err)
+		}
+		if record == nil {
+			return fmt.Errorf("missing index record %d", i)
+		}
+
+		if prev == nil {
+			if record.Start != 0 {
+				return fmt.Errorf("first index record starts at %d", record.Start)
+			}
+		} else {
+			if bytes.Compare(prev.ID, record.ID) >= 0 {
+				return fmt.Errorf("index record %d is out of order", i)
+			}
+			if prev.Start+uint64(prev.Length) != record.Start {
+				return fmt.Errorf("index record %d is not contiguous with the previous record", i)
+			}
+		}
+		prev = record
+	}
+
+	if prev != nil && prev.Start+uint64(prev.Length) != b.meta.Size_ {
+		return fmt.Errorf("index covers %d bytes, expected %d", prev.Start+uint64(prev.Length), b.meta.Size_)
+	}
+
+	return nil
 }
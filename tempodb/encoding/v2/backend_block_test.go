@@ -1,12 +1,16 @@
 package v2
 
 import (
+	"bytes"
 	"context"
 	"testing"
 
 	"github.com/google/uuid"
+	v1 "github.com/grafana/tempo/pkg/model/v1"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -59,16 +63,51 @@ func testLegacyBlock(t *testing.T, ids [][]byte, objs [][]byte, meta *backend.Bl
 	require.NoError(t, err, "error creating backendblock")
 
 	// test Find
+	truePositives := testutil.ToFloat64(metricBloomFilterTests.WithLabelValues(meta.TenantID, bloomResultTruePositive))
 	for i, id := range ids {
 		foundBytes, err := backendBlock.find(context.Background(), id)
 		assert.NoError(t, err)
 
 		assert.Equal(t, objs[i], foundBytes)
 	}
+	assert.Equal(t, truePositives+float64(len(ids)), testutil.ToFloat64(metricBloomFilterTests.WithLabelValues(meta.TenantID, bloomResultTruePositive)))
+
+	// test FindTraceByIDs, the fixture objects are v1 encoded
+	v1Meta := *meta
+	v1Meta.DataEncoding = v1.Encoding
+	v1Block, err := NewBackendBlock(&v1Meta, reader)
+	require.NoError(t, err, "error creating backendblock")
+	traceIDs := make([]common.ID, 0, len(ids))
+	for _, id := range ids {
+		traceIDs = append(traceIDs, id)
+	}
+	traces, metrics, err := v1Block.FindTraceByIDs(context.Background(), traceIDs, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Len(t, traces, len(ids))
+	for _, tr := range traces {
+		assert.NotNil(t, tr)
+	}
+	assert.Greater(t, metrics.InspectedBytes, uint64(0))
+
+	// test Validate
+	require.NoError(t, backendBlock.Validate(context.Background()))
 
 	// test Iterator
 	iterator, err := backendBlock.Iterator(10)
 	require.NoError(t, err, "error getting iterator")
+	testLegacyIterator(t, iterator, ids, objs)
+
+	// test Iterator with read ahead
+	for _, readAhead := range []int{1, 3, 20} {
+		iterator, err = backendBlock.IteratorWithReadAhead(context.Background(), 10, readAhead)
+		require.NoError(t, err, "error getting read ahead iterator")
+		testLegacyIterator(t, iterator, ids, objs)
+	}
+}
+
+func testLegacyIterator(t *testing.T, iterator BytesIterator, ids [][]byte, objs [][]byte) {
+	defer iterator.Close()
+
 	i := 0
 	for {
 		id, obj, err := iterator.NextBytes(context.Background())
@@ -83,3 +122,88 @@ func testLegacyBlock(t *testing.T, ids [][]byte, objs [][]byte, meta *backend.Bl
 	}
 	assert.Equal(t, len(ids), i)
 }
+
+func TestBackendBlockValidate(t *testing.T) {
+	ctx := context.Background()
+
+	srcR, _, _, err := local.New(&local.Config{
+		Path: "./v2test",
+	})
+	require.NoError(t, err)
+	src := backend.NewReader(srcR)
+
+	srcMeta, err := src.BlockMeta(ctx, uuid.MustParse("4cd3c468-6398-481b-b5ec-de56d1048427"), "fake")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		corrupt     func(t *testing.T, meta *backend.BlockMeta, w backend.RawWriter)
+		expectedErr string
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name: "total objects mismatch",
+			corrupt: func(_ *testing.T, meta *backend.BlockMeta, _ backend.RawWriter) {
+				meta.TotalObjects++
+			},
+			expectedErr: "found 10 objects, expected 11",
+		},
+		{
+			name: "size mismatch",
+			corrupt: func(_ *testing.T, meta *backend.BlockMeta, _ backend.RawWriter) {
+				meta.Size_++
+			},
+			expectedErr: "index covers",
+		},
+		{
+			name: "bad index checksum",
+			corrupt: func(t *testing.T, meta *backend.BlockMeta, w backend.RawWriter) {
+				index, err := src.Read(ctx, common.NameIndex, (uuid.UUID)(srcMeta.BlockID), srcMeta.TenantID, nil)
+				require.NoError(t, err)
+				index[20] ^= 0xff // flip a byte in the first record
+
+				err = w.Write(ctx, common.NameIndex, backend.KeyPathForBlock((uuid.UUID)(meta.BlockID), meta.TenantID), bytes.NewReader(index), int64(len(index)), nil)
+				require.NoError(t, err)
+			},
+			expectedErr: "mismatched checksum",
+		},
+		{
+			name: "missing bloom",
+			corrupt: func(t *testing.T, meta *backend.BlockMeta, w backend.RawWriter) {
+				err := w.Delete(ctx, common.BloomName(0), backend.KeyPathForBlock((uuid.UUID)(meta.BlockID), meta.TenantID), nil)
+				require.NoError(t, err)
+			},
+			expectedErr: "error retrieving bloom",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rawR, rawW, _, err := local.New(&local.Config{
+				Path: t.TempDir(),
+			})
+			require.NoError(t, err)
+			r := backend.NewReader(rawR)
+			w := backend.NewWriter(rawW)
+
+			meta := *srcMeta
+			require.NoError(t, CopyBlock(ctx, srcMeta, &meta, src, w))
+
+			if tc.corrupt != nil {
+				tc.corrupt(t, &meta, rawW)
+			}
+
+			block, err := NewBackendBlock(&meta, r)
+			require.NoError(t, err)
+
+			err = block.Validate(ctx)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
@@ -53,7 +53,7 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 			return nil, err
 		}
 
-		iter, err := block.Iterator(c.opts.ChunkSizeBytes)
+		iter, err := block.IteratorWithReadAhead(ctx, c.opts.ChunkSizeBytes, c.opts.ReadAheadChunks)
 		if err != nil {
 			return nil, err
 		}
@@ -97,6 +97,7 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 				return nil, fmt.Errorf("error making new compacted block: %w", err)
 			}
 			currentBlock.BlockMeta().CompactionLevel = nextCompactionLevel
+			currentBlock.BlockMeta().RetentionClass = c.opts.RetentionClass
 			newCompactedBlocks = append(newCompactedBlocks, currentBlock.BlockMeta())
 		}
 
@@ -24,6 +24,7 @@ func CreateBlock(ctx context.Context, cfg *common.BlockConfig, meta *backend.Blo
 	if err != nil {
 		return nil, fmt.Errorf("error creating streaming block: %w", err)
 	}
+	newBlock.meta.RetentionClass = meta.RetentionClass
 
 	bytesIterator, isBytesIterator := i.(BytesIterator)
 
@@ -76,6 +76,83 @@ func (f *PagedFinder) Find(ctx context.Context, id common.ID) ([]byte, error) {
 	return bytesFound, nil
 }
 
+// FindMany finds all ids with a single pass over the index and data. ids must be sorted ascending and
+// unique. The returned objects are in the same order as ids and nil for ids that are not found. Every data
+// page is read at most once.
+func (f *PagedFinder) FindMany(ctx context.Context, ids []common.ID) ([][]byte, error) {
+	found := make([][]byte, len(ids))
+
+	// consecutive records with matching ids need to be combined. fall back to individual lookups
+	if f.combiner != nil {
+		for i, id := range ids {
+			b, err := f.Find(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			found[i] = b
+		}
+		return found, nil
+	}
+
+	for i := 0; i < len(ids); {
+		record, _, err := f.index.Find(ctx, ids[i])
+		if err != nil {
+			return nil, err
+		}
+		if record == nil {
+			// all remaining ids are past the final record
+			break
+		}
+
+		// the record id is the largest id in the page. find all ids that may be within it
+		end := i + 1
+		for end < len(ids) && bytes.Compare(ids[end], record.ID) <= 0 {
+			end++
+		}
+
+		err = f.findInPage(ctx, ids[i:end], found[i:end], *record)
+		if err != nil {
+			return nil, err
+		}
+		i = end
+	}
+
+	return found, nil
+}
+
+func (f *PagedFinder) findInPage(ctx context.Context, ids []common.ID, found [][]byte, record Record) error {
+	pages, _, err := f.r.Read(ctx, []Record{record}, nil, nil)
+	if err != nil {
+		return err
+	}
+	if len(pages) == 0 {
+		return errors.New("unexpected 0 length pages in findInPage")
+	}
+
+	// both the page and ids are sorted so they can be merged
+	iter := NewIterator(bytes.NewReader(pages[0]), f.objectRW)
+	i := 0
+	for i < len(ids) {
+		foundID, b, err := iter.NextBytes(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		for i < len(ids) && bytes.Compare(ids[i], foundID) < 0 {
+			i++
+		}
+		if i < len(ids) && bytes.Equal(ids[i], foundID) {
+			found[i] = b
+			i++
+		}
+	}
+
+	return nil
+}
+
 func (f *PagedFinder) findOne(ctx context.Context, id common.ID, record Record) ([]byte, error) {
 	pages, _, err := f.r.Read(ctx, []Record{record}, nil, nil)
 	if err != nil {
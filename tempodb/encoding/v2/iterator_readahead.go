@@ -0,0 +1,209 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// chunkResult holds the decompressed pages of a single chunk of records. done is closed
+// once the chunk has been read.
+type chunkResult struct {
+	done  chan struct{}
+	pages [][]byte
+	err   error
+}
+
+type readAheadIterator struct {
+	indexReader    IndexReader
+	objectRW       ObjectReaderWriter
+	chunkSizeBytes uint32
+
+	chunks   chan *chunkResult
+	quitCh   chan struct{}
+	quitOnce sync.Once
+
+	pages      [][]byte
+	activePage []byte
+}
+
+var _ BytesIterator = (*readAheadIterator)(nil)
+
+// newReadAheadIterator returns an iterator that fetches up to depth chunks of chunkSizeBytes
+// concurrently ahead of the consumer. Each concurrent read requires its own DataReader so
+// dataReaders must contain depth readers. Objects are returned in the same order as the
+// pagedIterator.
+func newReadAheadIterator(ctx context.Context, chunkSizeBytes uint32, indexReader IndexReader, dataReaders []DataReader, objectRW ObjectReaderWriter) BytesIterator {
+	i := &readAheadIterator{
+		indexReader:    indexReader,
+		objectRW:       objectRW,
+		chunkSizeBytes: chunkSizeBytes,
+		chunks:         make(chan *chunkResult, len(dataReaders)),
+		quitCh:         make(chan struct{}),
+	}
+
+	go i.readAhead(ctx, dataReaders)
+
+	return i
+}
+
+// For performance reasons the ID and object slices returned from this method are owned by
+// the iterator.  If you have need to keep these values for longer than a single iteration
+// you need to make a copy of them.
+func (i *readAheadIterator) NextBytes(ctx context.Context) (common.ID, []byte, error) {
+	for {
+		if len(i.activePage) == 0 && len(i.pages) > 0 {
+			i.activePage = i.pages[0]
+			i.pages = i.pages[1:]
+		}
+
+		var (
+			id     common.ID
+			object []byte
+			err    error
+		)
+		i.activePage, id, object, err = i.objectRW.UnmarshalAndAdvanceBuffer(i.activePage)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, nil, fmt.Errorf("error unmarshalling active page, err: %w", err)
+		} else if !errors.Is(err, io.EOF) {
+			return id, object, nil
+		}
+
+		if len(i.pages) > 0 {
+			continue
+		}
+
+		var chunk *chunkResult
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case chunk, ok = <-i.chunks:
+		}
+		if !ok {
+			return nil, nil, io.EOF
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-chunk.done:
+		}
+		if chunk.err != nil {
+			return nil, nil, chunk.err
+		}
+		if len(chunk.pages) == 0 {
+			return nil, nil, errors.New("unexpected 0 length pages in readAheadIterator")
+		}
+
+		i.pages = chunk.pages
+		i.activePage = nil
+	}
+}
+
+// Close signals the read ahead goroutine to exit. Data readers are closed once all in
+// flight reads have completed.
+func (i *readAheadIterator) Close() {
+	i.quitOnce.Do(func() {
+		close(i.quitCh)
+	})
+}
+
+// readAhead walks the index building chunks of records and hands each chunk to an idle data
+// reader. Chunks are queued in index order so the consumer receives objects in order.
+func (i *readAheadIterator) readAhead(ctx context.Context, dataReaders []DataReader) {
+	var (
+		wg   sync.WaitGroup
+		idle = make(chan DataReader, len(dataReaders))
+	)
+	for _, dr := range dataReaders {
+		idle <- dr
+	}
+
+	defer func() {
+		wg.Wait()
+		for _, dr := range dataReaders {
+			dr.Close()
+		}
+		close(i.chunks)
+	}()
+
+	currentIndex := 0
+	for {
+		records, err := i.nextRecords(ctx, &currentIndex)
+		if len(records) == 0 && err == nil {
+			return
+		}
+
+		chunk := &chunkResult{done: make(chan struct{})}
+		if err != nil {
+			chunk.err = err
+			close(chunk.done)
+		}
+
+		// queue the chunk. this blocks once depth chunks are waiting on the consumer
+		select {
+		case <-ctx.Done():
+			return
+		case <-i.quitCh:
+			return
+		case i.chunks <- chunk:
+		}
+
+		if err != nil {
+			return
+		}
+
+		var dr DataReader
+		select {
+		case <-ctx.Done():
+			return
+		case <-i.quitCh:
+			return
+		case dr = <-idle:
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { idle <- dr }()
+			defer close(chunk.done)
+
+			// buffers are not reused because pages escape to the consumer
+			chunk.pages, _, chunk.err = dr.Read(ctx, records, nil, nil)
+			if chunk.err != nil {
+				chunk.err = fmt.Errorf("error reading objects for records, err: %w", chunk.err)
+			}
+		}()
+	}
+}
+
+// nextRecords returns the next contiguous set of records that fit in chunkSizeBytes. At least
+// one record is always returned unless the index is exhausted.
+func (i *readAheadIterator) nextRecords(ctx context.Context, currentIndex *int) ([]Record, error) {
+	var length uint32
+	records := make([]Record, 0, 5)
+
+	for {
+		record, err := i.indexReader.At(ctx, *currentIndex)
+		if err != nil {
+			return nil, fmt.Errorf("error getting next record, err: %w", err)
+		}
+		if record == nil {
+			return records, nil
+		}
+
+		// see if we can fit this record in.  we have to get at least one record in
+		if length+record.Length > i.chunkSizeBytes && len(records) != 0 {
+			return records, nil
+		}
+
+		records = append(records, *record)
+		length += record.Length
+		*currentIndex++
+	}
+}
@@ -56,7 +56,7 @@ func NewStreamingBlock(cfg *common.BlockConfig, id uuid.UUID, tenantID string, m
 
 	c := &StreamingBlock{
 		meta:  newMeta,
-		bloom: common.NewBloom(cfg.BloomFP, uint(cfg.BloomShardSizeBytes), uint(estimatedObjects)),
+		bloom: common.NewBloomForConfig(cfg, uint(estimatedObjects)),
 		cfg:   cfg,
 
 		withNoCompactFlag: cfg.CreateWithNoCompactFlag,
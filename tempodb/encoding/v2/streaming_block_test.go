@@ -174,6 +174,7 @@ func testStreamingBlockToBackendBlock(t *testing.T, cfg *common.BlockConfig) {
 
 	backendBlock, err := NewBackendBlock(meta, r)
 	require.NoError(t, err, "error creating block")
+	require.NoError(t, backendBlock.Validate(context.Background()))
 
 	// test Find
 	for i, id := range ids {
@@ -205,6 +206,20 @@ func testStreamingBlockToBackendBlock(t *testing.T, cfg *common.BlockConfig) {
 		i++
 	}
 	require.Equal(t, len(ids), i)
+
+	// test findMany with every id and one that is not in the block
+	findIDs := make([]common.ID, 0, len(ids)+1)
+	for _, id := range ids {
+		findIDs = append(findIDs, id)
+	}
+	findIDs = append(findIDs, bytes.Repeat([]byte{0xff}, 16))
+	found, err := backendBlock.findMany(context.Background(), findIDs)
+	require.NoError(t, err)
+	require.Len(t, found, len(ids)+1)
+	for i, id := range ids {
+		require.Equal(t, idsToObjs[util.TokenForTraceID(id)], found[i])
+	}
+	require.Nil(t, found[len(ids)])
 }
 
 func streamingBlock(t *testing.T, cfg *common.BlockConfig, w backend.Writer) (*StreamingBlock, [][]byte, [][]byte) {
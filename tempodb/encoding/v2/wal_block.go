@@ -27,8 +27,9 @@ const maxDataEncodingLength = 32
 
 var _ common.WALBlock = (*walBlock)(nil)
 
-// walBlock is a block that is actively used to append new objects to.  It stores all data in the appendFile
-// in the order it was received and an in memory sorted index.
+// walBlock is a block that is actively used to append new objects to.  It stores all data in the segments of the
+// wal block folder in the order it was received and an in memory sorted index. Wal blocks written before the segment
+// format store their data in a single file, the appendFile.
 type walBlock struct {
 	meta           *backend.BlockMeta
 	ingestionSlack time.Duration
@@ -37,6 +38,10 @@ type walBlock struct {
 	appender   Appender
 	encoder    model.SegmentDecoder
 
+	// segmentWriter and segmentReader are set for wal blocks in the segment format
+	segmentWriter *walSegmentWriter
+	segmentReader *walSegmentReader
+
 	filepath string
 	readFile *os.File
 	once     sync.Once
@@ -62,19 +67,19 @@ func createWALBlock(meta *backend.BlockMeta, filepath, dataEncoding string, inge
 
 	name := h.fullFilename()
 
-	f, err := os.OpenFile(name, os.O_APPEND|os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	// the epoch tells apart the segments of this block from leftovers of a previous block in the same folder
+	h.segmentWriter, err = newWALSegmentWriter(name, uint64(time.Now().UnixNano()))
 	if err != nil {
 		return nil, err
 	}
-	h.appendFile = f
+	h.segmentReader = newWALSegmentReader(name)
 
-	dataWriter, err := NewDataWriter(f, meta.Encoding)
+	h.appender, err = newSegmentAppender(h.segmentWriter, meta.Encoding, dataEncoding)
 	if err != nil {
+		_ = h.segmentWriter.close()
 		return nil, err
 	}
 
-	h.appender = NewAppender(dataWriter)
-
 	return h, nil
 }
 
@@ -93,38 +98,57 @@ func openWALBlock(filename, path string, ingestionSlack, additionalStartSlack ti
 		ingestionSlack: ingestionSlack,
 	}
 
+	blockStart := uint32(math.MaxUint32)
+	blockEnd := uint32(0)
+	handleRange := func(start, end uint32) {
+		start, end = b.adjustTimeRangeForSlack(start, end, additionalStartSlack)
+		if start < blockStart {
+			blockStart = start
+		}
+		if end > blockEnd {
+			blockEnd = end
+		}
+	}
+
+	info, err := os.Stat(filepath.Join(path, filename))
+	if err != nil {
+		return nil, nil, fmt.Errorf("accessing file: %w", err)
+	}
+
+	if info.IsDir() {
+		// replay segments to extract records
+		replay, warning, err := replayWALSegments(filepath.Join(path, filename), e, dataEncoding, handleRange)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		b.segmentReader = newWALSegmentReader(filepath.Join(path, filename))
+		b.appender = &replayedSegmentsAppender{Appender: NewRecordAppender(replay.records), dataLength: replay.dataLength}
+		b.meta.TotalObjects = int64(b.appender.Length())
+		b.meta.StartTime = time.Unix(int64(blockStart), 0)
+		b.meta.EndTime = time.Unix(int64(blockEnd), 0)
+
+		return b, warning, nil
+	}
+
 	// replay file to extract records
 	f, err := b.file()
 	if err != nil {
 		return nil, nil, fmt.Errorf("accessing file: %w", err)
 	}
 
-	blockStart := uint32(math.MaxUint32)
-	blockEnd := uint32(0)
-
 	dec, err := model.NewObjectDecoder(dataEncoding)
 	if err != nil {
 		return nil, nil, fmt.Errorf("creating object decoder: %w", err)
 	}
 
 	records, warning, err := ReplayWALAndGetRecords(f, e, func(bytes []byte) error {
-		start, end, err := dec.FastRange(bytes)
-		if errors.Is(err, decoder.ErrUnsupported) {
-			now := uint32(time.Now().Unix())
-			start = now
-			end = now
-		}
+		start, end, err := objectRange(dec, bytes)
 		if err != nil {
 			return err
 		}
 
-		start, end = b.adjustTimeRangeForSlack(start, end, additionalStartSlack)
-		if start < blockStart {
-			blockStart = start
-		}
-		if end > blockEnd {
-			blockEnd = end
-		}
+		handleRange(start, end)
 		return nil
 	})
 	if err != nil {
@@ -140,11 +164,7 @@ func openWALBlock(filename, path string, ingestionSlack, additionalStartSlack ti
 }
 
 func ownsWALBlock(entry fs.DirEntry) bool {
-	// all v2 wal blocks are files
-	if entry.IsDir() {
-		return false
-	}
-
+	// v2 wal blocks are folders of segments or files written before the segment format
 	_, _, version, _, _, err := ParseFilename(entry.Name())
 	if err != nil {
 		return false
@@ -183,7 +203,11 @@ func (a *walBlock) AppendTrace(id common.ID, trace *tempopb.Trace, start, end ui
 	return a.Append(id, buff2, start, end, adjustIngestionSlack)
 }
 
+// Flush syncs the current segment to disk.
 func (a *walBlock) Flush() error {
+	if a.segmentWriter != nil {
+		return a.segmentWriter.sync()
+	}
 	return nil
 }
 
@@ -210,14 +234,20 @@ func (a *walBlock) Iterator() (common.Iterator, error) {
 		}
 		a.appendFile = nil
 	}
+	if a.segmentWriter != nil {
+		err := a.segmentWriter.close()
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	records := a.appender.Records()
-	readFile, err := a.file()
+	r, err := a.contextReader()
 	if err != nil {
 		return nil, err
 	}
 
-	dataReader, err := NewDataReader(backend.NewContextReaderWithAllReader(readFile), a.meta.Encoding)
+	dataReader, err := NewDataReader(r, a.meta.Encoding)
 	if err != nil {
 		return nil, err
 	}
@@ -242,11 +272,23 @@ func (a *walBlock) Clear() error {
 		a.appendFile = nil
 	}
 
+	if a.segmentWriter != nil {
+		_ = a.segmentWriter.close()
+	}
+	if a.segmentReader != nil {
+		a.segmentReader.close()
+	}
+
 	// ignore error, it's important to remove the file above all else
 	_ = a.appender.Complete()
 
 	name := a.fullFilename()
-	return os.Remove(name)
+	return os.RemoveAll(name)
+}
+
+// FindTraceByIDs implements common.Finder
+func (a *walBlock) FindTraceByIDs(ctx context.Context, ids []common.ID, opts common.SearchOptions) ([]*tempopb.Trace, *tempopb.TraceByIDMetrics, error) {
+	return common.FindTraceByIDsOneByOne(ctx, ids, opts, a.FindTraceByID)
 }
 
 // FindTraceByID Find implements common.Finder
@@ -257,7 +299,7 @@ func (a *walBlock) FindTraceByID(ctx context.Context, id common.ID, _ common.Sea
 	combiner := model.StaticCombiner
 
 	records := a.appender.RecordsForID(id)
-	file, err := a.file()
+	r, err := a.contextReader()
 	if err != nil {
 		return nil, err
 	}
@@ -265,7 +307,7 @@ func (a *walBlock) FindTraceByID(ctx context.Context, id common.ID, _ common.Sea
 		return nil, nil
 	}
 
-	dataReader, err := NewDataReader(backend.NewContextReaderWithAllReader(file), a.meta.Encoding)
+	dataReader, err := NewDataReader(r, a.meta.Encoding)
 	if err != nil {
 		return nil, err
 	}
@@ -373,6 +415,29 @@ func (a *walBlock) file() (*os.File, error) {
 	return a.readFile, err
 }
 
+// contextReader returns the reader of the records of the block.
+func (a *walBlock) contextReader() (backend.ContextReader, error) {
+	if a.segmentReader != nil {
+		return a.segmentReader, nil
+	}
+
+	f, err := a.file()
+	if err != nil {
+		return nil, err
+	}
+	return backend.NewContextReaderWithAllReader(f), nil
+}
+
+// objectRange returns the time range of the object. It's the current time if the data encoding doesn't support it.
+func objectRange(dec model.ObjectDecoder, obj []byte) (uint32, uint32, error) {
+	start, end, err := dec.FastRange(obj)
+	if errors.Is(err, decoder.ErrUnsupported) {
+		now := uint32(time.Now().Unix())
+		return now, now, nil
+	}
+	return start, end, err
+}
+
 func (a *walBlock) adjustTimeRangeForSlack(start, end uint32, additionalStartSlack time.Duration) (uint32, uint32) {
 	now := time.Now()
 	startOfRange := uint32(now.Add(-a.ingestionSlack).Add(-additionalStartSlack).Unix())
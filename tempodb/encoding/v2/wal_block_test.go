@@ -211,7 +211,7 @@ func TestPartialBlock(t *testing.T) {
 	_, err = crand.Read(garbo)
 	require.NoError(t, err)
 
-	appendFile, err := os.OpenFile(v2Block.fullFilename(), os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
+	appendFile, err := os.OpenFile(walSegmentFilename(v2Block.fullFilename(), v2Block.segmentWriter.seq), os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
 	require.NoError(t, err)
 	_, err = appendFile.Write(garbo)
 	require.NoError(t, err)
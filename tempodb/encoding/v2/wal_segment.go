@@ -0,0 +1,639 @@
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+/*
+WAL format v3. A wal block is a folder of segment files named by their sequence number. Segments are sealed and a new
+one is started once they reach walSegmentSizeBytes.
+
+segment:
+|    24 bytes    |  8 bytes  |          |     |          |            |
+| segment header | record hd | page     | ... | index    | trailer    |
+
+segment header: | magic u32 | format u8 | reserved 3 bytes | epoch u64 | seq u32 | crc32 of the previous fields u32 |
+record header:  | page length u32 | crc32 of the page u32 |
+index:          | id len u16 | id | offset u32 | length u32 | start u32 | end u32 | ... (one per record)
+trailer:        | index length u32 | crc32 of the index u32 | magic u32 |
+
+The index and trailer are only written when a segment is sealed. Sealed segments are replayed from their index without
+reading their records. The records of the other segments are replayed until the first torn or corrupted one, the torn
+tail of the last segment is truncated.
+*/
+
+const (
+	walFormatV3 = 3
+
+	walSegmentMagic        uint32 = 0x33574154 // "TAW3"
+	walSegmentTrailerMagic uint32 = 0x58444954 // "TIDX"
+
+	walSegmentHeaderSize  = 24
+	walRecordHeaderSize   = 8
+	walSegmentTrailerSize = 12
+	walIndexEntrySize     = 18 // without the id
+
+	// walSegmentOffsetBits is the number of bits of the offset in a segment in the address of a record
+	walSegmentOffsetBits = 32
+)
+
+// walSegmentSizeBytes is the size after which a segment is sealed. Records are never split across segments so a
+// segment with a single record can be bigger.
+var walSegmentSizeBytes int64 = 32 * 1024 * 1024
+
+var walCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+var errTornRecord = errors.New("torn or corrupted record")
+
+type walIndexEntry struct {
+	id         common.ID
+	offset     uint32
+	length     uint32
+	start, end uint32
+}
+
+// walRecordAddress returns the start of a record in the segments. It's stored in Record.Start.
+func walRecordAddress(seq uint32, offset int64) uint64 {
+	return uint64(seq)<<walSegmentOffsetBits | uint64(offset)
+}
+
+func splitWALRecordAddress(address int64) (uint32, int64) {
+	return uint32(address >> walSegmentOffsetBits), address & (1<<walSegmentOffsetBits - 1)
+}
+
+func walSegmentFilename(dir string, seq uint32) string {
+	return filepath.Join(dir, fmt.Sprintf("%08d", seq))
+}
+
+func marshalWALSegmentHeader(epoch uint64, seq uint32) []byte {
+	b := make([]byte, walSegmentHeaderSize)
+	binary.LittleEndian.PutUint32(b[0:], walSegmentMagic)
+	b[4] = walFormatV3
+	binary.LittleEndian.PutUint64(b[8:], epoch)
+	binary.LittleEndian.PutUint32(b[16:], seq)
+	binary.LittleEndian.PutUint32(b[20:], crc32.Checksum(b[:20], walCRCTable))
+	return b
+}
+
+func unmarshalWALSegmentHeader(b []byte) (epoch uint64, seq uint32, err error) {
+	if len(b) < walSegmentHeaderSize {
+		return 0, 0, fmt.Errorf("segment header of size %d too small", len(b))
+	}
+	if magic := binary.LittleEndian.Uint32(b[0:]); magic != walSegmentMagic {
+		return 0, 0, fmt.Errorf("unexpected segment magic %x", magic)
+	}
+	if crc := binary.LittleEndian.Uint32(b[20:]); crc != crc32.Checksum(b[:20], walCRCTable) {
+		return 0, 0, errors.New("segment header crc mismatch")
+	}
+	if b[4] != walFormatV3 {
+		return 0, 0, fmt.Errorf("unsupported wal format %d", b[4])
+	}
+	return binary.LittleEndian.Uint64(b[8:]), binary.LittleEndian.Uint32(b[16:]), nil
+}
+
+// walSegmentWriter appends records to the segments of a wal block.
+type walSegmentWriter struct {
+	dir   string
+	epoch uint64
+
+	seq   uint32
+	file  *os.File
+	size  int64
+	index []walIndexEntry
+	buf   []byte
+}
+
+func newWALSegmentWriter(dir string, epoch uint64) (*walSegmentWriter, error) {
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &walSegmentWriter{
+		dir:   dir,
+		epoch: epoch,
+	}
+	return w, w.openSegment(1)
+}
+
+func (w *walSegmentWriter) openSegment(seq uint32) error {
+	f, err := os.OpenFile(walSegmentFilename(w.dir, seq), os.O_APPEND|os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(marshalWALSegmentHeader(w.epoch, seq))
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	w.seq = seq
+	w.file = f
+	w.size = walSegmentHeaderSize
+	w.index = w.index[:0]
+	return nil
+}
+
+// write appends the page as a record and returns its address. The record header and page are written at once so a
+// crash leaves at most one torn record at the end of the segment.
+func (w *walSegmentWriter) write(page []byte, id common.ID, start, end uint32) (uint64, error) {
+	if w.file == nil {
+		return 0, errors.New("wal segment writer is closed")
+	}
+
+	recordSize := int64(walRecordHeaderSize + len(page))
+	if len(w.index) > 0 && w.size+recordSize > walSegmentSizeBytes {
+		if err := w.seal(); err != nil {
+			return 0, err
+		}
+		if err := w.openSegment(w.seq + 1); err != nil {
+			return 0, err
+		}
+	}
+
+	w.buf = append(w.buf[:0], make([]byte, walRecordHeaderSize)...)
+	binary.LittleEndian.PutUint32(w.buf[0:], uint32(len(page)))
+	binary.LittleEndian.PutUint32(w.buf[4:], crc32.Checksum(page, walCRCTable))
+	w.buf = append(w.buf, page...)
+
+	_, err := w.file.Write(w.buf)
+	if err != nil {
+		return 0, err
+	}
+
+	offset := w.size + walRecordHeaderSize
+	w.index = append(w.index, walIndexEntry{
+		id:     id,
+		offset: uint32(offset),
+		length: uint32(len(page)),
+		start:  start,
+		end:    end,
+	})
+	w.size += recordSize
+
+	return walRecordAddress(w.seq, offset), nil
+}
+
+// seal writes the index of the current segment, syncs and closes it.
+func (w *walSegmentWriter) seal() error {
+	index := marshalWALIndex(w.index)
+	trailer := make([]byte, walSegmentTrailerSize)
+	binary.LittleEndian.PutUint32(trailer[0:], uint32(len(index)))
+	binary.LittleEndian.PutUint32(trailer[4:], crc32.Checksum(index, walCRCTable))
+	binary.LittleEndian.PutUint32(trailer[8:], walSegmentTrailerMagic)
+
+	_, err := w.file.Write(append(index, trailer...))
+	if err != nil {
+		return err
+	}
+	if err := w.file.Sync(); err != nil {
+		return err
+	}
+
+	err = w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *walSegmentWriter) sync() error {
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// close closes the current segment without sealing it.
+func (w *walSegmentWriter) close() error {
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func marshalWALIndex(entries []walIndexEntry) []byte {
+	size := 0
+	for _, e := range entries {
+		size += walIndexEntrySize + len(e.id)
+	}
+
+	b := make([]byte, 0, size)
+	for _, e := range entries {
+		b = binary.LittleEndian.AppendUint16(b, uint16(len(e.id)))
+		b = append(b, e.id...)
+		b = binary.LittleEndian.AppendUint32(b, e.offset)
+		b = binary.LittleEndian.AppendUint32(b, e.length)
+		b = binary.LittleEndian.AppendUint32(b, e.start)
+		b = binary.LittleEndian.AppendUint32(b, e.end)
+	}
+	return b
+}
+
+func unmarshalWALIndex(b []byte) ([]walIndexEntry, error) {
+	var entries []walIndexEntry
+	for len(b) > 0 {
+		if len(b) < uint16Size {
+			return nil, errors.New("unexpected end of index")
+		}
+		idLen := int(binary.LittleEndian.Uint16(b))
+		if len(b) < walIndexEntrySize+idLen {
+			return nil, errors.New("unexpected end of index")
+		}
+		b = b[uint16Size:]
+
+		e := walIndexEntry{id: append([]byte(nil), b[:idLen]...)}
+		b = b[idLen:]
+		e.offset = binary.LittleEndian.Uint32(b[0:])
+		e.length = binary.LittleEndian.Uint32(b[4:])
+		e.start = binary.LittleEndian.Uint32(b[8:])
+		e.end = binary.LittleEndian.Uint32(b[12:])
+		b = b[16:]
+
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// walSegmentReader reads records from the segments of a wal block by their address.
+type walSegmentReader struct {
+	dir string
+
+	mtx   sync.Mutex
+	files map[uint32]*os.File
+}
+
+var _ backend.ContextReader = (*walSegmentReader)(nil)
+
+func newWALSegmentReader(dir string) *walSegmentReader {
+	return &walSegmentReader{
+		dir:   dir,
+		files: map[uint32]*os.File{},
+	}
+}
+
+// ReadAt implements backend.ContextReader
+func (r *walSegmentReader) ReadAt(_ context.Context, p []byte, off int64) (int, error) {
+	seq, offset := splitWALRecordAddress(off)
+
+	r.mtx.Lock()
+	f, ok := r.files[seq]
+	if !ok {
+		var err error
+		f, err = os.OpenFile(walSegmentFilename(r.dir, seq), os.O_RDONLY, 0o600)
+		if err != nil {
+			r.mtx.Unlock()
+			return 0, err
+		}
+		r.files[seq] = f
+	}
+	r.mtx.Unlock()
+
+	return f.ReadAt(p, offset)
+}
+
+// ReadAll implements backend.ContextReader
+func (r *walSegmentReader) ReadAll(context.Context) ([]byte, error) {
+	return nil, util.ErrUnsupported
+}
+
+// Reader implements backend.ContextReader
+func (r *walSegmentReader) Reader() (io.Reader, error) {
+	return nil, util.ErrUnsupported
+}
+
+func (r *walSegmentReader) close() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	for seq, f := range r.files {
+		_ = f.Close()
+		delete(r.files, seq)
+	}
+}
+
+// segmentAppender is the Appender of wal blocks in the segment format. It writes each object in a page of its own,
+// like appender.
+type segmentAppender struct {
+	writer     *walSegmentWriter
+	dataWriter DataWriter
+	pageBuffer *bytes.Buffer
+	decoder    model.ObjectDecoder
+
+	records    map[uint64][]Record
+	recordsMtx sync.RWMutex
+	hash       hash.Hash64
+	dataLength uint64
+}
+
+func newSegmentAppender(writer *walSegmentWriter, enc backend.Encoding, dataEncoding string) (*segmentAppender, error) {
+	pageBuffer := &bytes.Buffer{}
+	dataWriter, err := NewDataWriter(pageBuffer, enc)
+	if err != nil {
+		return nil, err
+	}
+
+	dec, err := model.NewObjectDecoder(dataEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	return &segmentAppender{
+		writer:     writer,
+		dataWriter: dataWriter,
+		pageBuffer: pageBuffer,
+		decoder:    dec,
+		records:    map[uint64][]Record{},
+		hash:       xxhash.New(),
+	}, nil
+}
+
+// Append implements Appender. The time range of the object is recorded in the index of the segment.
+func (a *segmentAppender) Append(id common.ID, b []byte) error {
+	start, end, err := objectRange(a.decoder, b)
+	if err != nil {
+		return err
+	}
+
+	a.pageBuffer.Reset()
+	_, err = a.dataWriter.Write(id, b)
+	if err != nil {
+		return err
+	}
+	bytesWritten, err := a.dataWriter.CutPage()
+	if err != nil {
+		return err
+	}
+
+	address, err := a.writer.write(a.pageBuffer.Bytes(), id, start, end)
+	if err != nil {
+		return err
+	}
+
+	a.hash.Reset()
+	_, _ = a.hash.Write(id)
+	hash := a.hash.Sum64()
+
+	a.recordsMtx.Lock()
+	a.records[hash] = append(a.records[hash], Record{
+		ID:     id,
+		Start:  address,
+		Length: uint32(bytesWritten),
+	})
+	a.recordsMtx.Unlock()
+	a.dataLength += uint64(bytesWritten)
+
+	return nil
+}
+
+func (a *segmentAppender) Records() []Record {
+	a.recordsMtx.RLock()
+	sliceRecords := make([]Record, 0, len(a.records))
+	for _, r := range a.records {
+		sliceRecords = append(sliceRecords, r...)
+	}
+	a.recordsMtx.RUnlock()
+
+	SortRecords(sliceRecords)
+	return sliceRecords
+}
+
+func (a *segmentAppender) RecordsForID(id common.ID) []Record {
+	hasher := xxhash.New()
+	_, _ = hasher.Write(id)
+	hash := hasher.Sum64()
+
+	a.recordsMtx.RLock()
+	defer a.recordsMtx.RUnlock()
+
+	return a.records[hash]
+}
+
+func (a *segmentAppender) Length() int {
+	a.recordsMtx.RLock()
+	defer a.recordsMtx.RUnlock()
+
+	return len(a.records)
+}
+
+func (a *segmentAppender) DataLength() uint64 {
+	return a.dataLength
+}
+
+func (a *segmentAppender) Complete() error {
+	return a.dataWriter.Complete()
+}
+
+// replayedSegmentsAppender is the Appender of replayed wal blocks in the segment format. Record addresses aren't
+// offsets so the data length is tracked separately.
+type replayedSegmentsAppender struct {
+	Appender
+	dataLength uint64
+}
+
+func (a *replayedSegmentsAppender) DataLength() uint64 {
+	return a.dataLength
+}
+
+// walSegmentsReplay holds the result of the replay of the segments of a wal block.
+type walSegmentsReplay struct {
+	records    []Record
+	dataLength uint64
+	// sealed is the number of segments replayed from their index
+	sealed int
+}
+
+// replayWALSegments replays the segments of the wal block in the folder. handleRange is called with the time range of
+// each object. Segments of another epoch than the first one are ignored.
+func replayWALSegments(dir string, enc backend.Encoding, dataEncoding string, handleRange func(start, end uint32)) (*walSegmentsReplay, error, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var seqs []uint32
+	for _, e := range entries {
+		seq, err := strconv.ParseUint(e.Name(), 10, 32)
+		if err != nil || e.IsDir() {
+			continue
+		}
+		seqs = append(seqs, uint32(seq))
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+
+	dec, err := model.NewObjectDecoder(dataEncoding)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating object decoder: %w", err)
+	}
+
+	var (
+		replay   = &walSegmentsReplay{}
+		warnings []error
+		epoch    uint64
+		hasEpoch bool
+	)
+	for i, seq := range seqs {
+		segment, err := os.ReadFile(walSegmentFilename(dir, seq))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		segmentEpoch, headerSeq, err := unmarshalWALSegmentHeader(segment)
+		if err == nil && headerSeq != seq {
+			err = fmt.Errorf("unexpected sequence number %d", headerSeq)
+		}
+		if err == nil && hasEpoch && segmentEpoch != epoch {
+			err = fmt.Errorf("unexpected epoch %d, expected %d", segmentEpoch, epoch)
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("ignoring segment %d: %w", seq, err))
+			continue
+		}
+		epoch, hasEpoch = segmentEpoch, true
+
+		if index, ok := sealedWALSegmentIndex(segment); ok {
+			for _, e := range index {
+				handleRange(e.start, e.end)
+				replay.records = append(replay.records, Record{
+					ID:     e.id,
+					Start:  walRecordAddress(seq, int64(e.offset)),
+					Length: e.length,
+				})
+				replay.dataLength += uint64(e.length)
+			}
+			replay.sealed++
+			continue
+		}
+
+		dataReader, err := NewDataReader(backend.NewContextReaderWithAllReader(bytes.NewReader(segment)), enc)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		offset := int64(walSegmentHeaderSize)
+		for offset < int64(len(segment)) {
+			page, err := walSegmentRecord(segment, offset)
+			if err != nil {
+				break
+			}
+
+			id, obj, err := readWALRecordObject(dataReader, Record{Start: uint64(offset + walRecordHeaderSize), Length: uint32(len(page))})
+			if err != nil {
+				break
+			}
+			start, end, err := objectRange(dec, obj)
+			if err != nil {
+				break
+			}
+			handleRange(start, end)
+
+			replay.records = append(replay.records, Record{
+				ID:     id,
+				Start:  walRecordAddress(seq, offset+walRecordHeaderSize),
+				Length: uint32(len(page)),
+			})
+			replay.dataLength += uint64(len(page))
+			offset += walRecordHeaderSize + int64(len(page))
+		}
+		dataReader.Close()
+
+		if offset == int64(len(segment)) {
+			continue
+		}
+		if i < len(seqs)-1 {
+			// keep the records before the corrupted one and replay the next segments
+			warnings = append(warnings, fmt.Errorf("partially replayed segment %d: %w at offset %d", seq, errTornRecord, offset))
+			continue
+		}
+		if err := os.Truncate(walSegmentFilename(dir, seq), offset); err != nil {
+			return nil, nil, fmt.Errorf("truncating torn tail of segment %d: %w", seq, err)
+		}
+		warnings = append(warnings, fmt.Errorf("truncated segment %d: %w at offset %d", seq, errTornRecord, offset))
+	}
+
+	SortRecords(replay.records)
+
+	return replay, errors.Join(warnings...), nil
+}
+
+// sealedWALSegmentIndex returns the index of the segment if it's sealed and the index is valid.
+func sealedWALSegmentIndex(segment []byte) ([]walIndexEntry, bool) {
+	if len(segment) < walSegmentHeaderSize+walSegmentTrailerSize {
+		return nil, false
+	}
+
+	trailer := segment[len(segment)-walSegmentTrailerSize:]
+	if binary.LittleEndian.Uint32(trailer[8:]) != walSegmentTrailerMagic {
+		return nil, false
+	}
+	indexLen := int(binary.LittleEndian.Uint32(trailer[0:]))
+	if indexLen > len(segment)-walSegmentHeaderSize-walSegmentTrailerSize {
+		return nil, false
+	}
+	index := segment[len(segment)-walSegmentTrailerSize-indexLen : len(segment)-walSegmentTrailerSize]
+	if binary.LittleEndian.Uint32(trailer[4:]) != crc32.Checksum(index, walCRCTable) {
+		return nil, false
+	}
+
+	entries, err := unmarshalWALIndex(index)
+	if err != nil {
+		return nil, false
+	}
+	return entries, true
+}
+
+// walSegmentRecord returns the page of the record at the offset of the segment or errTornRecord if it's incomplete or
+// its crc doesn't match.
+func walSegmentRecord(segment []byte, offset int64) ([]byte, error) {
+	if int64(len(segment))-offset < walRecordHeaderSize {
+		return nil, errTornRecord
+	}
+	length := int64(binary.LittleEndian.Uint32(segment[offset:]))
+	crc := binary.LittleEndian.Uint32(segment[offset+4:])
+
+	start := offset + walRecordHeaderSize
+	if int64(len(segment))-start < length {
+		return nil, errTornRecord
+	}
+	page := segment[start : start+length]
+	if crc32.Checksum(page, walCRCTable) != crc {
+		return nil, errTornRecord
+	}
+	return page, nil
+}
+
+func readWALRecordObject(dataReader DataReader, record Record) (common.ID, []byte, error) {
+	pages, _, err := dataReader.Read(context.Background(), []Record{record}, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(pages) != 1 {
+		return nil, nil, fmt.Errorf("expected 1 page, got %d", len(pages))
+	}
+
+	id, obj, err := NewObjectReaderWriter().UnmarshalObjectFromReader(bytes.NewReader(pages[0]))
+	if err != nil {
+		return nil, nil, err
+	}
+	return append([]byte(nil), id...), obj, nil
+}
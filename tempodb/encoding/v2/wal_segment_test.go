@@ -0,0 +1,195 @@
+package v2
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func writeTestSegmentedWALBlock(t *testing.T, segmentSize int64, numMsgs int) (*walBlock, []common.ID, []*tempopb.Trace) {
+	defer func(size int64) { walSegmentSizeBytes = size }(walSegmentSizeBytes)
+	walSegmentSizeBytes = segmentSize
+
+	meta := backend.NewBlockMeta(testTenantID, uuid.New(), VersionString, backend.EncSnappy, model.CurrentEncoding)
+	block, err := createWALBlock(meta, t.TempDir(), model.CurrentEncoding, 0)
+	require.NoError(t, err)
+
+	enc := model.MustNewSegmentDecoder(model.CurrentEncoding)
+
+	ids := make([]common.ID, 0, numMsgs)
+	reqs := make([]*tempopb.Trace, 0, numMsgs)
+	for i := 0; i < numMsgs; i++ {
+		id := make([]byte, 4)
+		binary.LittleEndian.PutUint32(id, uint32(i))
+		id = test.ValidTraceID(id)
+		req := test.MakeTrace(5, id)
+
+		b1, err := enc.PrepareForWrite(req, 0, 0)
+		require.NoError(t, err)
+		b2, err := enc.ToObject([][]byte{b1})
+		require.NoError(t, err)
+		require.NoError(t, block.Append(id, b2, 0, 0, false))
+
+		ids = append(ids, id)
+		reqs = append(reqs, req)
+	}
+	require.NoError(t, block.Flush())
+
+	return block.(*walBlock), ids, reqs
+}
+
+func reopenTestWALBlock(t *testing.T, b *walBlock) (*walBlock, error) {
+	require.NoError(t, b.segmentWriter.close())
+
+	name := b.fullFilename()
+	replayed, warning, err := openWALBlock(filepath.Base(name), filepath.Dir(name), 0, 0)
+	require.NoError(t, err)
+	return replayed.(*walBlock), warning
+}
+
+func requireTraces(t *testing.T, b *walBlock, ids []common.ID, reqs []*tempopb.Trace) {
+	require.Equal(t, int64(len(ids)), b.BlockMeta().TotalObjects)
+	for i, id := range ids {
+		resp, err := b.FindTraceByID(context.Background(), id, common.DefaultSearchOptions())
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		require.True(t, proto.Equal(reqs[i], resp.Trace))
+	}
+}
+
+func segmentCount(t *testing.T, b *walBlock) int {
+	entries, err := os.ReadDir(b.fullFilename())
+	require.NoError(t, err)
+	return len(entries)
+}
+
+func TestSegmentedWALBlockReplay(t *testing.T) {
+	block, ids, reqs := writeTestSegmentedWALBlock(t, 4096, 100)
+	require.Greater(t, segmentCount(t, block), 2)
+	requireTraces(t, block, ids, reqs)
+
+	replayed, warning := reopenTestWALBlock(t, block)
+	require.NoError(t, warning)
+	require.Equal(t, block.DataLength(), replayed.DataLength())
+	requireTraces(t, replayed, ids, reqs)
+
+	// all segments but the last one are sealed and replayed from their index
+	replay, warning, err := replayWALSegments(block.fullFilename(), backend.EncSnappy, model.CurrentEncoding, func(uint32, uint32) {})
+	require.NoError(t, err)
+	require.NoError(t, warning)
+	require.Equal(t, segmentCount(t, block)-1, replay.sealed)
+}
+
+func TestSegmentedWALBlockTornTail(t *testing.T) {
+	block, ids, reqs := writeTestSegmentedWALBlock(t, 4096, 100)
+
+	// a torn record at the end of the last segment
+	last := walSegmentFilename(block.fullFilename(), block.segmentWriter.seq)
+	info, err := os.Stat(last)
+	require.NoError(t, err)
+
+	f, err := os.OpenFile(last, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{100, 0, 0, 0, 1, 2, 3, 4, 5})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	replayed, warning := reopenTestWALBlock(t, block)
+	require.ErrorIs(t, warning, errTornRecord)
+	requireTraces(t, replayed, ids, reqs)
+
+	// the torn tail is truncated
+	truncated, err := os.Stat(last)
+	require.NoError(t, err)
+	require.Equal(t, info.Size(), truncated.Size())
+
+	_, warning = reopenTestWALBlock(t, block)
+	require.NoError(t, warning)
+}
+
+func TestSegmentedWALBlockPartialSegment(t *testing.T) {
+	block, ids, reqs := writeTestSegmentedWALBlock(t, 4096, 100)
+	require.Greater(t, segmentCount(t, block), 2)
+
+	// corrupt the index and the last record of the first segment, so it's replayed up to the record
+	first := walSegmentFilename(block.fullFilename(), 1)
+	segment, err := os.ReadFile(first)
+	require.NoError(t, err)
+	index, ok := sealedWALSegmentIndex(segment)
+	require.True(t, ok)
+	lost := index[len(index)-1]
+
+	segment[len(segment)-1] ^= 0xff
+	segment[lost.offset] ^= 0xff
+	require.NoError(t, os.WriteFile(first, segment, 0o600))
+
+	replayed, warning := reopenTestWALBlock(t, block)
+	require.ErrorIs(t, warning, errTornRecord)
+
+	// the objects of the corrupted record are lost, all others are replayed
+	for i, id := range ids {
+		if string(id) == string(lost.id) {
+			ids = append(ids[:i], ids[i+1:]...)
+			reqs = append(reqs[:i], reqs[i+1:]...)
+			break
+		}
+	}
+	requireTraces(t, replayed, ids, reqs)
+}
+
+func TestSegmentedWALBlockIgnoresOtherEpochs(t *testing.T) {
+	block, ids, reqs := writeTestSegmentedWALBlock(t, 1024*1024, 10)
+	require.Equal(t, 1, segmentCount(t, block))
+
+	// a segment left by a previous block in the same folder
+	require.NoError(t, os.WriteFile(walSegmentFilename(block.fullFilename(), 2), marshalWALSegmentHeader(block.segmentWriter.epoch+1, 2), 0o600))
+
+	replayed, warning := reopenTestWALBlock(t, block)
+	require.ErrorContains(t, warning, "unexpected epoch")
+	requireTraces(t, replayed, ids, reqs)
+}
+
+func TestLegacyWALBlockReplay(t *testing.T) {
+	meta := backend.NewBlockMeta(testTenantID, uuid.New(), VersionString, backend.EncSnappy, model.CurrentEncoding)
+	b := &walBlock{meta: meta, filepath: t.TempDir()}
+
+	f, err := os.OpenFile(b.fullFilename(), os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
+	require.NoError(t, err)
+	dataWriter, err := NewDataWriter(f, meta.Encoding)
+	require.NoError(t, err)
+	appender := NewAppender(dataWriter)
+
+	enc := model.MustNewSegmentDecoder(model.CurrentEncoding)
+	ids := make([]common.ID, 0, 10)
+	reqs := make([]*tempopb.Trace, 0, 10)
+	for i := 0; i < 10; i++ {
+		id := test.ValidTraceID(nil)
+		req := test.MakeTrace(5, id)
+		b1, err := enc.PrepareForWrite(req, 0, 0)
+		require.NoError(t, err)
+		b2, err := enc.ToObject([][]byte{b1})
+		require.NoError(t, err)
+		require.NoError(t, appender.Append(id, b2))
+
+		ids = append(ids, id)
+		reqs = append(reqs, req)
+	}
+	require.NoError(t, f.Close())
+
+	replayed, warning, err := openWALBlock(filepath.Base(b.fullFilename()), b.filepath, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, warning)
+	requireTraces(t, replayed.(*walBlock), ids, reqs)
+}
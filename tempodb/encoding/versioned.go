@@ -2,10 +2,19 @@ package encoding
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	v2 "github.com/grafana/tempo/tempodb/encoding/v2"
@@ -14,6 +23,31 @@ import (
 	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
 )
 
+var metricFallbackBlocksOpened = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempodb",
+	Name:      "blocks_opened_with_fallback_total",
+	Help:      "Total number of blocks of a newer version opened with the latest encoding.",
+}, []string{"version"})
+
+// ErrUnsupportedVersion is returned for blocks of a version this build can't read.
+var ErrUnsupportedVersion = errors.New("unsupported block version")
+
+// fallbackWarned holds the newer versions that have already been logged
+var fallbackWarned sync.Map
+
+// NewerBlockOpener is implemented by encodings that can read the columns they share with blocks of a newer version.
+type NewerBlockOpener interface {
+	OpenNewerBlock(meta *backend.BlockMeta, r backend.Reader) (common.BackendBlock, error)
+}
+
+// BlockRepairer is implemented by encodings that can rebuild the objects of a block derived from its data.
+type BlockRepairer interface {
+	// RepairBlock rebuilds the objects of the block that are missing or can't be parsed, like the blooms, from its
+	// data. It returns the names of the rebuilt objects, none if the block is complete, or common.ErrCorruptBlockData
+	// if the data itself is missing or corrupt.
+	RepairBlock(ctx context.Context, cfg *common.BlockConfig, meta *backend.BlockMeta, r backend.Reader, w backend.Writer) ([]string, error)
+}
+
 // VersionedEncoding represents a backend block version, and the methods to
 // read/write them.
 type VersionedEncoding interface {
@@ -73,7 +107,7 @@ func FromVersion(v string) (VersionedEncoding, error) {
 	case vparquet4.VersionString:
 		return vparquet4.Encoding{}, nil
 	default:
-		return nil, fmt.Errorf("%s is not a valid block version", v)
+		return nil, fmt.Errorf("%s is not a valid block version: %w", v, ErrUnsupportedVersion)
 	}
 }
 
@@ -98,12 +132,50 @@ func AllEncodings() []VersionedEncoding {
 }
 
 // OpenBlock for reading in the backend. It automatically chooses the encoding for the given block.
+// Blocks of the version that follows the latest encoding, written by newer compactors during a rollout, are opened
+// with the latest encoding and only return the data of the columns they share with it.
 func OpenBlock(meta *backend.BlockMeta, r backend.Reader) (common.BackendBlock, error) {
 	v, err := FromVersion(meta.Version)
-	if err != nil {
+	if err == nil {
+		return v.OpenBlock(meta, r)
+	}
+
+	if !IsNewerVersion(meta.Version) {
 		return nil, err
 	}
-	return v.OpenBlock(meta, r)
+	latest := LatestEncoding()
+	opener := latest.(NewerBlockOpener)
+
+	if _, warned := fallbackWarned.LoadOrStore(meta.Version, struct{}{}); !warned {
+		level.Warn(log.Logger).Log("msg", "opening blocks of a newer version with the latest encoding, results may be incomplete",
+			"version", meta.Version, "encoding", latest.Version())
+	}
+	metricFallbackBlocksOpened.WithLabelValues(meta.Version).Inc()
+
+	return opener.OpenNewerBlock(meta, r)
+}
+
+// IsNewerVersion returns true if blocks of the version are opened with the latest encoding by OpenBlock, only
+// returning the data of the columns they share with it.
+func IsNewerVersion(version string) bool {
+	if _, err := FromVersion(version); err == nil {
+		return false
+	}
+
+	latest := LatestEncoding()
+	_, ok := latest.(NewerBlockOpener)
+	return ok && version == nextVersion(latest.Version())
+}
+
+// nextVersion returns the version that follows v by incrementing its trailing number, e.g. vParquet5 for vParquet4.
+// It returns an empty string if v doesn't end with a number.
+func nextVersion(v string) string {
+	prefix := strings.TrimRight(v, "0123456789")
+	n, err := strconv.Atoi(v[len(prefix):])
+	if err != nil {
+		return ""
+	}
+	return prefix + strconv.Itoa(n+1)
 }
 
 // CopyBlock from one backend to another. It automatically chooses the encoding for the given block.
@@ -3,8 +3,12 @@ package encoding
 import (
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	v2 "github.com/grafana/tempo/tempodb/encoding/v2"
 )
 
 func TestFromVersionErrors(t *testing.T) {
@@ -21,3 +25,34 @@ func TestAllVersions(t *testing.T) {
 		require.NoError(t, err)
 	}
 }
+
+func TestNextVersion(t *testing.T) {
+	require.Equal(t, "vParquet5", nextVersion("vParquet4"))
+	require.Equal(t, "v3", nextVersion("v2"))
+	require.Equal(t, "vParquet10", nextVersion("vParquet9"))
+	require.Equal(t, "", nextVersion("vParquet"))
+}
+
+func TestIsNewerVersion(t *testing.T) {
+	require.True(t, IsNewerVersion(nextVersion(LatestEncoding().Version())))
+	require.False(t, IsNewerVersion(LatestEncoding().Version()))
+	require.False(t, IsNewerVersion(v2.VersionString))
+	require.False(t, IsNewerVersion(nextVersion(nextVersion(LatestEncoding().Version()))))
+	require.False(t, IsNewerVersion("definitely-not-a-real-version"))
+}
+
+func TestOpenBlockNewerVersion(t *testing.T) {
+	meta := backend.NewBlockMeta("fake", uuid.New(), nextVersion(LatestEncoding().Version()), backend.EncNone, "")
+	block, err := OpenBlock(meta, nil)
+	require.NoError(t, err)
+	require.Equal(t, meta, block.BlockMeta())
+
+	// only the version that follows the latest encoding is opened
+	meta.Version = nextVersion(meta.Version)
+	_, err = OpenBlock(meta, nil)
+	require.Error(t, err)
+
+	meta.Version = "definitely-not-a-real-version"
+	_, err = OpenBlock(meta, nil)
+	require.Error(t, err)
+}
@@ -43,24 +43,33 @@ func (b *backendBlock) checkBloom(ctx context.Context, id common.ID) (found bool
 	defer span.End()
 
 	shardKey := common.ShardKeyForTraceID(id, int(b.meta.BloomShardCount))
-	nameBloom := common.BloomName(shardKey)
-	span.SetAttributes(attribute.String("bloom", nameBloom))
+	span.SetAttributes(attribute.String("bloom", common.BloomName(shardKey)))
 
-	bloomBytes, err := b.r.Read(derivedCtx, nameBloom, (uuid.UUID)(b.meta.BlockID), b.meta.TenantID, &backend.CacheInfo{
+	filter, err := b.readBloom(derivedCtx, shardKey)
+	if err != nil {
+		return false, err
+	}
+
+	return filter.Test(id), nil
+}
+
+func (b *backendBlock) readBloom(ctx context.Context, shardKey int) (*bloom.BloomFilter, error) {
+	nameBloom := common.BloomName(shardKey)
+	bloomBytes, err := b.r.Read(ctx, nameBloom, (uuid.UUID)(b.meta.BlockID), b.meta.TenantID, &backend.CacheInfo{
 		Meta: b.meta,
 		Role: cache.RoleBloom,
 	})
 	if err != nil {
-		return false, fmt.Errorf("error retrieving bloom %s (%s, %s): %w", nameBloom, b.meta.TenantID, b.meta.BlockID, err)
+		return nil, fmt.Errorf("error retrieving bloom %s (%s, %s): %w", nameBloom, b.meta.TenantID, b.meta.BlockID, err)
 	}
 
 	filter := &bloom.BloomFilter{}
 	_, err = filter.ReadFrom(bytes.NewReader(bloomBytes))
 	if err != nil {
-		return false, fmt.Errorf("error parsing bloom (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
+		return nil, fmt.Errorf("error parsing bloom (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
 	}
 
-	return filter.Test(id), nil
+	return filter, nil
 }
 
 func (b *backendBlock) checkIndex(ctx context.Context, id common.ID) (bool, int, error) {
@@ -137,6 +146,129 @@ func (b *backendBlock) FindTraceByID(ctx context.Context, traceID common.ID, opt
 	return findTraceByID(derivedCtx, traceID, b.meta, pf, rowGroup)
 }
 
+// FindTraceByIDs finds multiple traces with a single pass over the trace id column. Every bloom shard is
+// read at most once and ids ruled out by the bloom are not searched.
+func (b *backendBlock) FindTraceByIDs(ctx context.Context, ids []common.ID, opts common.SearchOptions) (_ []*tempopb.Trace, _ *tempopb.TraceByIDMetrics, err error) {
+	derivedCtx, span := tracer.Start(ctx, "parquet.backendBlock.FindTraceByIDs",
+		trace.WithAttributes(
+			attribute.String("blockID", b.meta.BlockID.String()),
+			attribute.String("tenantID", b.meta.TenantID),
+			attribute.Int64("blockSize", int64(b.meta.Size_)),
+			attribute.Int("ids", len(ids)),
+		))
+	defer span.End()
+
+	metrics := &tempopb.TraceByIDMetrics{}
+
+	candidates, err := b.checkBlooms(derivedCtx, common.SortedUniqueIDs(ids))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(candidates) == 0 {
+		return make([]*tempopb.Trace, len(ids)), metrics, nil
+	}
+
+	pf, rr, err := b.openForSearch(derivedCtx, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unexpected error opening parquet file: %w", err)
+	}
+
+	found, err := findTraceByIDs(derivedCtx, candidates, b.meta, pf)
+
+	bytesRead := rr.BytesRead()
+	metrics.InspectedBytes += bytesRead
+	span.SetAttributes(attribute.Int64("inspectedBytes", int64(bytesRead)))
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return common.TracesForIDs(ids, found), metrics, nil
+}
+
+// checkBlooms returns the ids that may be in the block. Every bloom shard is read at most once.
+func (b *backendBlock) checkBlooms(ctx context.Context, ids []common.ID) ([]common.ID, error) {
+	filters := map[int]*bloom.BloomFilter{}
+
+	candidates := make([]common.ID, 0, len(ids))
+	for _, id := range ids {
+		shardKey := common.ShardKeyForTraceID(id, int(b.meta.BloomShardCount))
+		filter, ok := filters[shardKey]
+		if !ok {
+			var err error
+			filter, err = b.readBloom(ctx, shardKey)
+			if err != nil {
+				return nil, err
+			}
+			filters[shardKey] = filter
+		}
+
+		if filter.Test(id) {
+			candidates = append(candidates, id)
+		}
+	}
+
+	return candidates, nil
+}
+
+// findTraceByIDs scans the trace id column once for all ids and reads the matching rows in order.
+// ids must be sorted ascending.
+func findTraceByIDs(ctx context.Context, ids []common.ID, meta *backend.BlockMeta, pf *parquet.File) (*common.IDMap[*tempopb.Trace], error) {
+	colIndex, _, maxDef := pq.GetColumnIndexByPath(pf, TraceIDColumnName)
+	if colIndex == -1 {
+		return nil, fmt.Errorf("unable to get index for column: %s", TraceIDColumnName)
+	}
+
+	traceIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		traceIDs = append(traceIDs, string(id))
+	}
+
+	iter := parquetquery.NewSyncIterator(ctx, pf.RowGroups(), colIndex,
+		parquetquery.SyncIteratorOptPredicate(parquetquery.NewStringInPredicate(traceIDs)),
+		parquetquery.SyncIteratorOptMaxDefinitionLevel(maxDef),
+	)
+	defer iter.Close()
+
+	// all row groups are iterated so row numbers are absolute
+	var rows []int64
+	for {
+		res, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			break
+		}
+		rows = append(rows, int64(res.RowNumber[0]))
+	}
+
+	found := common.NewIDMap[*tempopb.Trace](len(rows))
+	if len(rows) == 0 {
+		return found, nil
+	}
+
+	r := parquet.NewReader(pf)
+	defer r.Close()
+
+	for _, row := range rows {
+		err := r.SeekToRow(row)
+		if err != nil {
+			return nil, fmt.Errorf("seek to row: %w", err)
+		}
+
+		tr := new(Trace)
+		err = r.Read(tr)
+		if err != nil {
+			return nil, fmt.Errorf("error reading row from backend: %w", err)
+		}
+
+		found.Set(tr.TraceID, ParquetTraceToTempopbTrace(tr))
+	}
+
+	return found, nil
+}
+
 func findTraceByID(ctx context.Context, traceID common.ID, meta *backend.BlockMeta, pf *parquet.File, rowGroup int) (*tempopb.TraceByIDResponse, error) {
 	// traceID column index
 	colIndex, _, maxDef := pq.GetColumnIndexByPath(pf, TraceIDColumnName)
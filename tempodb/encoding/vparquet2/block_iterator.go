@@ -28,6 +28,19 @@ func (b *backendBlock) open(ctx context.Context) (*parquet.File, *parquet.Reader
 	return pf, r, nil
 }
 
+// TraceIterator returns the traces of the block ordered by ID. It's used to convert the block to another version.
+func (b *backendBlock) TraceIterator(ctx context.Context) (common.Iterator, error) {
+	iter, err := b.rawIter(ctx, newRowPool(1_000_000))
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarks := []*bookmark[parquet.Row]{newBookmark[parquet.Row](iter)}
+	return newCommonIterator(newMultiblockIterator(bookmarks, func(rows []parquet.Row) (parquet.Row, error) {
+		return rows[0], nil
+	}), parquet.SchemaOf(new(Trace))), nil
+}
+
 func (b *backendBlock) rawIter(ctx context.Context, pool *rowPool) (*rawIterator, error) {
 	pf, r, err := b.open(ctx)
 	if err != nil {
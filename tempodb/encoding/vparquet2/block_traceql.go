@@ -718,6 +718,8 @@ var intrinsicColumnLookups = map[traceql.Intrinsic]struct {
 	traceql.IntrinsicServiceStats:           {intrinsicScopeTrace, traceql.TypeNil, ""},
 	traceql.IntrinsicInstrumentationName:    {intrinsicScopeInstrumentation, traceql.TypeNil, ""},
 	traceql.IntrinsicInstrumentationVersion: {intrinsicScopeInstrumentation, traceql.TypeNil, ""},
+	traceql.IntrinsicTraceState:             {intrinsicScopeSpan, traceql.TypeNil, ""},
+	traceql.IntrinsicTraceSampled:           {intrinsicScopeSpan, traceql.TypeNil, ""},
 }
 
 // Lookup table of all well-known attributes with dedicated columns
@@ -802,7 +804,9 @@ func checkConditions(conditions []traceql.Condition) error {
 			cond.Attribute.Intrinsic == traceql.IntrinsicLinkTraceID ||
 			cond.Attribute.Intrinsic == traceql.IntrinsicLinkSpanID ||
 			cond.Attribute.Intrinsic == traceql.IntrinsicInstrumentationName ||
-			cond.Attribute.Intrinsic == traceql.IntrinsicInstrumentationVersion {
+			cond.Attribute.Intrinsic == traceql.IntrinsicInstrumentationVersion ||
+			cond.Attribute.Intrinsic == traceql.IntrinsicTraceState ||
+			cond.Attribute.Intrinsic == traceql.IntrinsicTraceSampled {
 
 			return fmt.Errorf("intrinsic '%s' not supported in vParquet2: %w", cond.Attribute.Intrinsic, common.ErrUnsupported)
 		}
@@ -153,6 +153,7 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 				TenantID:        inputs[0].TenantID,
 				CompactionLevel: nextCompactionLevel,
 				TotalObjects:    recordsPerBlock, // Just an estimate
+				RetentionClass:  c.opts.RetentionClass,
 			}
 
 			currentBlock = newStreamingBlock(ctx, &c.opts.BlockConfig, newMeta, r, w, tempo_io.NewBufferedWriter)
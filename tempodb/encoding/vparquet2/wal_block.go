@@ -484,6 +484,11 @@ func (b *walBlock) Clear() error {
 	return errs.Err()
 }
 
+// FindTraceByIDs implements common.Finder. The WAL is local so traces are looked up one by one.
+func (b *walBlock) FindTraceByIDs(ctx context.Context, ids []common.ID, opts common.SearchOptions) ([]*tempopb.Trace, *tempopb.TraceByIDMetrics, error) {
+	return common.FindTraceByIDsOneByOne(ctx, ids, opts, b.FindTraceByID)
+}
+
 func (b *walBlock) FindTraceByID(ctx context.Context, id common.ID, opts common.SearchOptions) (*tempopb.TraceByIDResponse, error) {
 	trs := make([]*tempopb.Trace, 0)
 
@@ -161,6 +161,7 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 				TotalObjects:      recordsPerBlock, // Just an estimate
 				ReplicationFactor: inputs[0].ReplicationFactor,
 				DedicatedColumns:  inputs[0].DedicatedColumns,
+				RetentionClass:    c.opts.RetentionClass,
 			}
 
 			currentBlock = newStreamingBlock(ctx, &c.opts.BlockConfig, newMeta, r, w, tempo_io.NewBufferedWriter)
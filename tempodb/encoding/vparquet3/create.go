@@ -14,26 +14,6 @@ import (
 	"github.com/parquet-go/parquet-go"
 )
 
-type backendWriter struct {
-	ctx      context.Context
-	w        backend.Writer
-	name     string
-	blockID  uuid.UUID
-	tenantID string
-	tracker  backend.AppendTracker
-}
-
-var _ io.WriteCloser = (*backendWriter)(nil)
-
-func (b *backendWriter) Write(p []byte) (n int, err error) {
-	b.tracker, err = b.w.Append(b.ctx, b.name, b.blockID, b.tenantID, b.tracker, p)
-	return len(p), err
-}
-
-func (b *backendWriter) Close() error {
-	return b.w.CloseAppend(b.ctx, b.tracker)
-}
-
 func CreateBlock(ctx context.Context, cfg *common.BlockConfig, meta *backend.BlockMeta, i common.Iterator, r backend.Reader, to backend.Writer) (*backend.BlockMeta, error) {
 	s := newStreamingBlock(ctx, cfg, meta, r, to, tempo_io.NewBufferedWriter)
 
@@ -97,7 +77,7 @@ type streamingBlock struct {
 	meta  *backend.BlockMeta
 	bw    tempo_io.BufferedWriteFlusher
 	pw    *parquet.GenericWriter[*Trace]
-	w     *backendWriter
+	w     io.WriteCloser
 	r     backend.Reader
 	to    backend.Writer
 	index *index
@@ -113,12 +93,13 @@ func newStreamingBlock(ctx context.Context, cfg *common.BlockConfig, meta *backe
 	newMeta.StartTime = meta.StartTime
 	newMeta.EndTime = meta.EndTime
 	newMeta.ReplicationFactor = meta.ReplicationFactor
+	newMeta.RetentionClass = meta.RetentionClass
 
 	// TotalObjects is used here an an estimated count for the bloom filter.
 	// The real number of objects is tracked below.
-	bloom := common.NewBloom(cfg.BloomFP, uint(cfg.BloomShardSizeBytes), uint(meta.TotalObjects))
+	bloom := common.NewBloomForConfig(cfg, uint(meta.TotalObjects))
 
-	w := &backendWriter{ctx, to, DataFileName, (uuid.UUID)(meta.BlockID), meta.TenantID, nil}
+	w := to.ResumableStreamWriter(ctx, DataFileName, (uuid.UUID)(meta.BlockID), meta.TenantID)
 	bw := createBufferedWriter(w)
 	pw := parquet.NewGenericWriter[*Trace](bw)
 
@@ -147,6 +128,7 @@ func (b *streamingBlock) Add(tr *Trace, start, end uint32) error {
 	b.index.Add(id)
 	b.bloom.Add(id)
 	b.meta.ObjectAdded(start, end)
+	b.meta.TraceIDRangeAdded(id)
 	b.currentBufferedTraces++
 	b.currentBufferedBytes += estimateMarshalledSizeFromTrace(tr)
 
@@ -162,6 +144,7 @@ func (b *streamingBlock) AddRaw(id []byte, row parquet.Row, start, end uint32) e
 	b.index.Add(id)
 	b.bloom.Add(id)
 	b.meta.ObjectAdded(start, end)
+	b.meta.TraceIDRangeAdded(id)
 	b.currentBufferedTraces++
 	b.currentBufferedBytes += estimateMarshalledSizeFromParquetRow(row)
 
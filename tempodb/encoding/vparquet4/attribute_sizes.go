@@ -0,0 +1,136 @@
+package vparquet4
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/parquet-go/parquet-go"
+
+	pq "github.com/grafana/tempo/pkg/parquetquery"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// AttributeSizes are the bytes of the string values of the attributes of a block by scope and attribute name.
+type AttributeSizes map[backend.DedicatedColumnScope]map[string]uint64
+
+// genericAttributeColumns are the columns of the generic attributes of each scope that can be stored in dedicated
+// columns.
+var genericAttributeColumns = map[backend.DedicatedColumnScope]struct {
+	definitionLevel     int
+	key, value, isArray string
+}{
+	backend.DedicatedColumnScopeResource: {DefinitionLevelResourceAttrs, FieldResourceAttrKey, FieldResourceAttrVal, FieldResourceAttrIsArray},
+	backend.DedicatedColumnScopeSpan:     {DefinitionLevelResourceSpansILSSpanAttrs, FieldSpanAttrKey, FieldSpanAttrVal, FieldSpanAttrIsArray},
+}
+
+// ReadAttributeSizes measures the string values of the generic resource and span attributes of the block and of its
+// string dedicated columns. Array attributes aren't measured, they can't be stored in dedicated columns.
+func ReadAttributeSizes(ctx context.Context, r backend.Reader, meta *backend.BlockMeta) (AttributeSizes, error) {
+	pf, _, err := newBackendBlock(meta, r).openForSearch(ctx, common.DefaultSearchOptions())
+	if err != nil {
+		return nil, fmt.Errorf("error opening block %s: %w", meta.BlockID, err)
+	}
+
+	makeIter := makeIterFunc(ctx, pf.RowGroups(), pf)
+	sizes := AttributeSizes{}
+	for _, scope := range allScopes {
+		sizes[scope] = map[string]uint64{}
+
+		cols := genericAttributeColumns[scope]
+		err := measureGenericAttributes(pq.NewJoinIterator(cols.definitionLevel, []pq.Iterator{
+			makeIter(cols.key, nil, "key"),
+			makeIter(cols.value, nil, "value"),
+			makeIter(cols.isArray, nil, "isArray"),
+		}, &attributeSizeCollector{sizes: sizes[scope]}))
+		if err != nil {
+			return nil, err
+		}
+
+		dedicated := dedicatedColumnsToColumnMapping(meta.DedicatedColumns, scope)
+		dedicated.forEach(func(attr string, col dedicatedColumn) {
+			if err != nil || col.Type != backend.DedicatedColumnTypeString {
+				return
+			}
+			sizes[scope][attr], err = measureColumn(makeIter(col.ColumnPath, nil, "value"))
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sizes, nil
+}
+
+// measureGenericAttributes drains the iterator, the sizes are accumulated by its attributeSizeCollector.
+func measureGenericAttributes(iter pq.Iterator) error {
+	defer iter.Close()
+
+	for {
+		res, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		if res == nil {
+			return nil
+		}
+	}
+}
+
+func measureColumn(iter pq.Iterator) (uint64, error) {
+	defer iter.Close()
+
+	var size uint64
+	for {
+		res, err := iter.Next()
+		if err != nil {
+			return 0, err
+		}
+		if res == nil {
+			return size, nil
+		}
+		for _, e := range res.Entries {
+			if !e.Value.IsNull() {
+				size += uint64(len(e.Value.ByteArray()))
+			}
+		}
+	}
+}
+
+var _ pq.GroupPredicate = (*attributeSizeCollector)(nil)
+
+// attributeSizeCollector adds the bytes of the string values of each attribute to sizes. All groups are dropped.
+type attributeSizeCollector struct {
+	sizes map[string]uint64
+}
+
+func (c *attributeSizeCollector) String() string {
+	return "attributeSizeCollector{}"
+}
+
+func (c *attributeSizeCollector) KeepGroup(res *pq.IteratorResult) bool {
+	var (
+		name    string
+		size    uint64
+		isArray bool
+	)
+	for _, e := range res.Entries {
+		switch e.Key {
+		case "key":
+			name = e.Value.String()
+		case "value":
+			if e.Value.Kind() == parquet.ByteArray {
+				size += uint64(len(e.Value.ByteArray()))
+			}
+		case "isArray":
+			isArray = isArray || e.Value.Boolean()
+		}
+	}
+
+	if name != "" && size > 0 && !isArray {
+		c.sizes[name] += size
+	}
+
+	res.Reset()
+	return false
+}
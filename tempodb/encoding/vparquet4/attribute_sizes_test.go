@@ -0,0 +1,54 @@
+package vparquet4
+
+import (
+	"context"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestReadAttributeSizes(t *testing.T) {
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+
+	blockConfig := common.BlockConfig{Version: VersionString}
+	blockConfig.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+
+	dc := backend.DedicatedColumns{
+		{Scope: backend.DedicatedColumnScopeResource, Name: "dedicated.resource.1", Type: backend.DedicatedColumnTypeString},
+		{Scope: backend.DedicatedColumnScopeSpan, Name: "dedicated.span.1", Type: backend.DedicatedColumnTypeString},
+		{Scope: backend.DedicatedColumnScopeSpan, Name: "dedicated.span.2", Type: backend.DedicatedColumnTypeString},
+	}
+	meta := createTestBlock(t, context.Background(), &blockConfig, r, w, 10, 10, 10, 1, dc)
+
+	sizes, err := ReadAttributeSizes(context.Background(), r, meta)
+	require.NoError(t, err)
+
+	// 100 batches of 10 spans, the dedicated and the generic columns are measured alike
+	const (
+		batches       = 100
+		spans         = 1000
+		resourceValue = uint64(len("dedicated-resource-attr-value-1"))
+		spanValue     = uint64(len("dedicated-span-attr-value-1"))
+	)
+	for _, attr := range []string{"dedicated.resource.1", "dedicated.resource.2", "dedicated.resource.5"} {
+		require.Equal(t, batches*resourceValue, sizes[backend.DedicatedColumnScopeResource][attr], attr)
+	}
+	for _, attr := range []string{"dedicated.span.1", "dedicated.span.2", "dedicated.span.5"} {
+		require.Equal(t, spans*spanValue, sizes[backend.DedicatedColumnScopeSpan][attr], attr)
+	}
+	require.Greater(t, sizes[backend.DedicatedColumnScopeResource]["random.res.attr"], uint64(0))
+	require.Greater(t, sizes[backend.DedicatedColumnScopeSpan]["key"], uint64(0))
+	require.Equal(t, uint64(0), sizes[backend.DedicatedColumnScopeSpan]["key"]%uint64(len("value")))
+	require.NotContains(t, sizes[backend.DedicatedColumnScopeResource], "service.name", "well-known attributes have their own columns")
+}
@@ -5,9 +5,13 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"sync"
 
 	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+
+	pq "github.com/grafana/tempo/pkg/parquetquery"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	"go.opentelemetry.io/otel"
@@ -23,6 +27,10 @@ type backendBlock struct {
 	meta *backend.BlockMeta
 	r    backend.Reader
 
+	// fallback is set for blocks written by a newer encoding and for blocks written before the span flags column was
+	// added. They are read with the schema of the file, and the columns that are missing from it match nothing.
+	fallback bool
+
 	openMtx sync.Mutex
 }
 
@@ -35,6 +43,24 @@ func newBackendBlock(meta *backend.BlockMeta, r backend.Reader) *backendBlock {
 	}
 }
 
+// openParquetFile opens the parquet file with the schema of the encoding, unless fallback is set or the file was
+// written before the span flags column was added. These files are opened with their own schema and fallback is
+// returned set.
+func openParquetFile(r io.ReaderAt, size int64, fallback bool, o ...parquet.FileOption) (*parquet.File, bool, error) {
+	if !fallback {
+		pf, err := parquet.OpenFile(r, size, append(o[:len(o):len(o)], parquet.FileSchema(parquetSchema))...)
+		if err != nil {
+			return nil, false, err
+		}
+		if index, _, _ := pq.GetColumnIndexByPath(pf, columnPathSpanFlags); index != -1 {
+			return pf, false, nil
+		}
+	}
+
+	pf, err := parquet.OpenFile(r, size, o...)
+	return pf, true, err
+}
+
 func (b *backendBlock) BlockMeta() *backend.BlockMeta {
 	return b.meta
 }
@@ -46,6 +72,27 @@ func (b *backendBlock) Validate(ctx context.Context) error {
 		return errors.New("block meta is nil")
 	}
 
+	err := b.validateDataFile(ctx)
+	if err != nil {
+		return err
+	}
+
+	// read the first byte from all blooms to confirm they exist
+	buff := make([]byte, 1)
+	for i := 0; i < int(b.meta.BloomShardCount); i++ {
+		bloomName := common.BloomName(i)
+		err = b.r.ReadRange(ctx, bloomName, uuid.UUID(b.meta.BlockID), b.meta.TenantID, 0, buff, nil)
+		if err != nil {
+			return fmt.Errorf("failed to read first byte of bloom(%d): %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// validateDataFile checks the parquet footer of the data file. Errors of a footer that doesn't match the meta wrap
+// common.ErrCorruptBlockData.
+func (b *backendBlock) validateDataFile(ctx context.Context) error {
 	// read last 8 bytes of the file to confirm its at least complete. the last 4 should be ascii "PAR1"
 	// and the 4 bytes before that should be the length of the footer
 	buff := make([]byte, 8)
@@ -55,22 +102,12 @@ func (b *backendBlock) Validate(ctx context.Context) error {
 	}
 
 	if string(buff[4:]) != "PAR1" {
-		return fmt.Errorf("invalid parquet magic footer: %x", buff[4:])
+		return fmt.Errorf("invalid parquet magic footer: %x: %w", buff[4:], common.ErrCorruptBlockData)
 	}
 
 	footerSize := int64(binary.LittleEndian.Uint32(buff[:4]))
 	if footerSize != int64(b.meta.FooterSize) {
-		return fmt.Errorf("unexpected parquet footer size: %d", footerSize)
-	}
-
-	// read the first byte from all blooms to confirm they exist
-	buff = make([]byte, 1)
-	for i := 0; i < int(b.meta.BloomShardCount); i++ {
-		bloomName := common.BloomName(i)
-		err = b.r.ReadRange(ctx, bloomName, uuid.UUID(b.meta.BlockID), b.meta.TenantID, 0, buff, nil)
-		if err != nil {
-			return fmt.Errorf("failed to read first byte of bloom(%d): %w", i, err)
-		}
+		return fmt.Errorf("unexpected parquet footer size: %d: %w", footerSize, common.ErrCorruptBlockData)
 	}
 
 	return nil
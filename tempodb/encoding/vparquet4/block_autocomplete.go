@@ -458,6 +458,24 @@ func createDistinctSpanIterator(
 			addSelectAs(cond.Attribute, columnPathSpanStatusMessage, columnPathSpanStatusMessage)
 			continue
 
+		case traceql.IntrinsicTraceState:
+			pred, err := createStringPredicate(cond.Op, cond.Operands)
+			if err != nil {
+				return nil, err
+			}
+			addPredicate(columnPathSpanTraceState, pred)
+			addSelectAs(cond.Attribute, columnPathSpanTraceState, columnPathSpanTraceState)
+			continue
+
+		case traceql.IntrinsicTraceSampled:
+			pred, err := createSampledPredicate(cond.Op, cond.Operands)
+			if err != nil {
+				return nil, err
+			}
+			addPredicate(columnPathSpanFlags, pred)
+			addSelectAs(cond.Attribute, columnPathSpanFlags, columnPathSpanFlags)
+			continue
+
 		case traceql.IntrinsicNestedSetLeft:
 			pred, err := createIntPredicate(cond.Op, cond.Operands)
 			if err != nil {
@@ -1102,6 +1120,10 @@ func mapSpanAttr(e entry) traceql.Static {
 		return traceql.NewStaticStatus(status)
 	case columnPathSpanStatusMessage:
 		return traceql.NewStaticString(unsafeToString(e.Value.ByteArray()))
+	case columnPathSpanFlags:
+		if flags := e.Value.Uint32(); flags != 0 {
+			return traceql.NewStaticBool(flags&spanFlagSampled != 0)
+		}
 	case columnPathSpanKind:
 		var kind traceql.Kind
 		switch e.Value.Uint64() {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync"
 
 	"github.com/google/uuid"
 	"github.com/parquet-go/parquet-go"
@@ -14,6 +15,7 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/grafana/tempo/pkg/boundedwaitgroup"
 	"github.com/grafana/tempo/pkg/cache"
 	"github.com/grafana/tempo/pkg/parquetquery"
 	pq "github.com/grafana/tempo/pkg/parquetquery"
@@ -43,24 +45,33 @@ func (b *backendBlock) checkBloom(ctx context.Context, id common.ID) (found bool
 	defer span.End()
 
 	shardKey := common.ShardKeyForTraceID(id, int(b.meta.BloomShardCount))
-	nameBloom := common.BloomName(shardKey)
-	span.SetAttributes(attribute.String("bloom", nameBloom))
+	span.SetAttributes(attribute.String("bloom", common.BloomName(shardKey)))
+
+	filter, err := b.readBloom(derivedCtx, shardKey)
+	if err != nil {
+		return false, err
+	}
 
-	bloomBytes, err := b.r.Read(derivedCtx, nameBloom, (uuid.UUID)(b.meta.BlockID), b.meta.TenantID, &backend.CacheInfo{
+	return filter.Test(id), nil
+}
+
+func (b *backendBlock) readBloom(ctx context.Context, shardKey int) (*bloom.BloomFilter, error) {
+	nameBloom := common.BloomName(shardKey)
+	bloomBytes, err := b.r.Read(ctx, nameBloom, (uuid.UUID)(b.meta.BlockID), b.meta.TenantID, &backend.CacheInfo{
 		Meta: b.meta,
 		Role: cache.RoleBloom,
 	})
 	if err != nil {
-		return false, fmt.Errorf("error retrieving bloom %s (%s, %s): %w", nameBloom, b.meta.TenantID, b.meta.BlockID, err)
+		return nil, fmt.Errorf("error retrieving bloom %s (%s, %s): %w", nameBloom, b.meta.TenantID, b.meta.BlockID, err)
 	}
 
 	filter := &bloom.BloomFilter{}
 	_, err = filter.ReadFrom(bytes.NewReader(bloomBytes))
 	if err != nil {
-		return false, fmt.Errorf("error parsing bloom (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
+		return nil, fmt.Errorf("error parsing bloom (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
 	}
 
-	return filter.Test(id), nil
+	return filter, nil
 }
 
 func (b *backendBlock) checkIndex(ctx context.Context, id common.ID) (bool, int, error) {
@@ -131,7 +142,7 @@ func (b *backendBlock) FindTraceByID(ctx context.Context, traceID common.ID, opt
 		return nil, fmt.Errorf("unexpected error opening parquet file: %w", err)
 	}
 
-	foundTrace, err := findTraceByID(derivedCtx, traceID, b.meta, pf, rowGroup)
+	foundTrace, err := findTraceByID(derivedCtx, traceID, b.meta, pf, rowGroup, opts.RowGroupConcurrency)
 
 	result := &tempopb.TraceByIDResponse{
 		Trace:   foundTrace,
@@ -144,13 +155,141 @@ func (b *backendBlock) FindTraceByID(ctx context.Context, traceID common.ID, opt
 	return result, err
 }
 
-func findTraceByID(ctx context.Context, traceID common.ID, meta *backend.BlockMeta, pf *parquet.File, rowGroup int) (*tempopb.Trace, error) {
+// FindTraceByIDs finds multiple traces with a single pass over the trace id column. Every bloom shard is
+// read at most once and ids ruled out by the bloom are not searched.
+func (b *backendBlock) FindTraceByIDs(ctx context.Context, ids []common.ID, opts common.SearchOptions) (_ []*tempopb.Trace, _ *tempopb.TraceByIDMetrics, err error) {
+	derivedCtx, span := tracer.Start(ctx, "parquet.backendBlock.FindTraceByIDs",
+		trace.WithAttributes(
+			attribute.String("blockID", b.meta.BlockID.String()),
+			attribute.String("tenantID", b.meta.TenantID),
+			attribute.Int64("blockSize", int64(b.meta.Size_)),
+			attribute.Int("ids", len(ids)),
+		))
+	defer span.End()
+
+	metrics := &tempopb.TraceByIDMetrics{}
+
+	candidates, err := b.checkBlooms(derivedCtx, common.SortedUniqueIDs(ids))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(candidates) == 0 {
+		return make([]*tempopb.Trace, len(ids)), metrics, nil
+	}
+
+	pf, rr, err := b.openForSearch(derivedCtx, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unexpected error opening parquet file: %w", err)
+	}
+
+	found, err := findTraceByIDs(derivedCtx, candidates, b.meta, pf)
+
+	bytesRead := rr.BytesRead()
+	metrics.InspectedBytes += bytesRead
+	span.SetAttributes(attribute.Int64("inspectedBytes", int64(bytesRead)))
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return common.TracesForIDs(ids, found), metrics, nil
+}
+
+// checkBlooms returns the ids that may be in the block. Every bloom shard is read at most once.
+func (b *backendBlock) checkBlooms(ctx context.Context, ids []common.ID) ([]common.ID, error) {
+	filters := map[int]*bloom.BloomFilter{}
+
+	candidates := make([]common.ID, 0, len(ids))
+	for _, id := range ids {
+		shardKey := common.ShardKeyForTraceID(id, int(b.meta.BloomShardCount))
+		filter, ok := filters[shardKey]
+		if !ok {
+			var err error
+			filter, err = b.readBloom(ctx, shardKey)
+			if err != nil {
+				return nil, err
+			}
+			filters[shardKey] = filter
+		}
+
+		if filter.Test(id) {
+			candidates = append(candidates, id)
+		}
+	}
+
+	return candidates, nil
+}
+
+// findTraceByIDs scans the trace id column once for all ids and reads the matching rows in order.
+// ids must be sorted ascending.
+func findTraceByIDs(ctx context.Context, ids []common.ID, meta *backend.BlockMeta, pf *parquet.File) (*common.IDMap[*tempopb.Trace], error) {
+	colIndex, _, maxDef := pq.GetColumnIndexByPath(pf, TraceIDColumnName)
+	if colIndex == -1 {
+		return nil, fmt.Errorf("unable to get index for column: %s", TraceIDColumnName)
+	}
+
+	traceIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		traceIDs = append(traceIDs, string(id))
+	}
+
+	iter := parquetquery.NewSyncIterator(ctx, pf.RowGroups(), colIndex,
+		parquetquery.SyncIteratorOptPredicate(parquetquery.NewStringInPredicate(traceIDs)),
+		parquetquery.SyncIteratorOptMaxDefinitionLevel(maxDef),
+	)
+	defer iter.Close()
+
+	// all row groups are iterated so row numbers are absolute
+	var rows []int64
+	for {
+		res, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			break
+		}
+		rows = append(rows, int64(res.RowNumber[0]))
+	}
+
+	found := common.NewIDMap[*tempopb.Trace](len(rows))
+	if len(rows) == 0 {
+		return found, nil
+	}
+
+	r := parquet.NewGenericReader[*Trace](pf)
+	defer r.Close()
+
+	for _, row := range rows {
+		err := r.SeekToRow(row)
+		if err != nil {
+			return nil, fmt.Errorf("seek to row: %w", err)
+		}
+
+		tr := new(Trace)
+		_, err = r.Read([]*Trace{tr})
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("error reading row from backend: %w", err)
+		}
+
+		found.Set(tr.TraceID, parquetTraceToTempopbTrace(meta, tr))
+	}
+
+	return found, nil
+}
+
+// findTraceByID finds the trace in the row group or, if it's -1, in the row groups whose bounds include the trace
+// id. Blocks with replicated traces can contain the trace id in several consecutive row groups, which are read with
+// up to concurrency row groups in parallel and combined.
+func findTraceByID(ctx context.Context, traceID common.ID, meta *backend.BlockMeta, pf *parquet.File, rowGroup int, concurrency int) (*tempopb.Trace, error) {
 	// traceID column index
 	colIndex, _, maxDef := pq.GetColumnIndexByPath(pf, TraceIDColumnName)
 	if colIndex == -1 {
 		return nil, fmt.Errorf("unable to get index for column: %s", TraceIDColumnName)
 	}
 
+	rowGroups := []int{rowGroup}
+
 	// If no index then fallback to binary searching the rowgroups.
 	if rowGroup == -1 {
 		var (
@@ -229,54 +368,144 @@ func findTraceByID(ctx context.Context, traceID common.ID, meta *backend.BlockMe
 		if err != nil {
 			return nil, fmt.Errorf("error binary searching row groups: %w", err)
 		}
+		if rowGroup == -1 {
+			// Not within the bounds of any row group
+			return nil, nil
+		}
+
+		// Duplicates of the trace id can continue into the following row groups, which then start with the trace
+		// id, and the row group before the first one starting with it can end with it.
+		first, last := rowGroup, rowGroup
+		for first > 0 {
+			min, err := getRowGroupMin(first)
+			if err != nil {
+				return nil, fmt.Errorf("error reading row group bounds: %w", err)
+			}
+			if !bytes.Equal(min, traceID) {
+				break
+			}
+			first--
+		}
+		for last < numRowGroups-1 {
+			min, err := getRowGroupMin(last + 1)
+			if err != nil {
+				return nil, fmt.Errorf("error reading row group bounds: %w", err)
+			}
+			if !bytes.Equal(min, traceID) {
+				break
+			}
+			last++
+		}
+
+		rowGroups = rowGroups[:0]
+		for rg := first; rg <= last; rg++ {
+			rowGroups = append(rowGroups, rg)
+		}
 	}
 
-	if rowGroup == -1 {
-		// Not within the bounds of any row group
+	if len(rowGroups) == 1 {
+		tr, err := findTraceInRowGroup(ctx, traceID, pf, colIndex, maxDef, rowGroups[0])
+		if err != nil || tr == nil {
+			return nil, err
+		}
+		// convert to proto trace and return
+		return parquetTraceToTempopbTrace(meta, tr), nil
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("rowGroups", len(rowGroups)))
+
+	// Read the candidate row groups in parallel, sharing the concurrency of the lookup
+	var (
+		wg       = boundedwaitgroup.New(uint(max(1, concurrency)))
+		mtx      sync.Mutex
+		found    = make([]*Trace, len(rowGroups))
+		firstErr error
+	)
+	for i, rg := range rowGroups {
+		wg.Add(1)
+		go func(i, rg int) {
+			defer wg.Done()
+
+			tr, err := findTraceInRowGroup(ctx, traceID, pf, colIndex, maxDef, rg)
+			mtx.Lock()
+			defer mtx.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			found[i] = tr
+		}(i, rg)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	partials := make([]*Trace, 0, len(found))
+	for _, tr := range found {
+		if tr != nil {
+			partials = append(partials, tr)
+		}
+	}
+	if len(partials) == 0 {
 		return nil, nil
 	}
 
-	// Now iterate the matching row group
+	return parquetTraceToTempopbTrace(meta, combineTraces(partials...)), nil
+}
+
+// findTraceInRowGroup reads the trace from the row group or returns nil if it isn't in it. Duplicates of the trace
+// in the row group are combined.
+func findTraceInRowGroup(ctx context.Context, traceID common.ID, pf *parquet.File, colIndex, maxDef, rowGroup int) (*Trace, error) {
 	iter := parquetquery.NewSyncIterator(ctx, pf.RowGroups()[rowGroup:rowGroup+1], colIndex,
 		parquetquery.SyncIteratorOptPredicate(parquetquery.NewStringInPredicate([]string{string(traceID)})),
 		parquetquery.SyncIteratorOptMaxDefinitionLevel(maxDef),
 	)
 	defer iter.Close()
 
-	res, err := iter.Next()
-	if err != nil {
-		return nil, err
-	}
-	if res == nil {
-		// TraceID not found in this block
-		return nil, nil
-	}
-
 	// The row number coming out of the iterator is relative,
 	// so offset it using the num rows in all previous groups
-	rowMatch := int64(0)
+	rowOffset := int64(0)
 	for _, rg := range pf.RowGroups()[0:rowGroup] {
-		rowMatch += rg.NumRows()
+		rowOffset += rg.NumRows()
 	}
-	rowMatch += int64(res.RowNumber[0])
 
-	// seek to row and read
+	var rows []int64
+	for {
+		res, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			break
+		}
+		rows = append(rows, rowOffset+int64(res.RowNumber[0]))
+	}
+	if len(rows) == 0 {
+		// TraceID not found in this row group
+		return nil, nil
+	}
+
+	// seek to rows and read
 	r := parquet.NewGenericReader[*Trace](pf)
 	defer r.Close()
 
-	err = r.SeekToRow(rowMatch)
-	if err != nil {
-		return nil, fmt.Errorf("seek to row: %w", err)
-	}
+	traces := make([]*Trace, 0, len(rows))
+	for _, row := range rows {
+		err := r.SeekToRow(row)
+		if err != nil {
+			return nil, fmt.Errorf("seek to row: %w", err)
+		}
 
-	tr := new(Trace)
-	_, err = r.Read([]*Trace{tr})
-	if err != nil && !errors.Is(err, io.EOF) {
-		return nil, fmt.Errorf("error reading row from backend: %w", err)
+		tr := new(Trace)
+		_, err = r.Read([]*Trace{tr})
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("error reading row from backend: %w", err)
+		}
+		traces = append(traces, tr)
 	}
 
-	// convert to proto trace and return
-	return parquetTraceToTempopbTrace(meta, tr), nil
+	return combineTraces(traces...), nil
 }
 
 // binarySearch that finds exact matching entry. Returns non-zero index when found, or -1 when not found
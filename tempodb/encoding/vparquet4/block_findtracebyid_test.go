@@ -104,6 +104,106 @@ func TestBackendBlockFindTraceByID(t *testing.T) {
 	}
 }
 
+func TestBackendBlockFindTraceByIDDuplicates(t *testing.T) {
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+	ctx := context.Background()
+
+	cfg := &common.BlockConfig{
+		BloomFP:             0.01,
+		BloomShardSizeBytes: 100 * 1024,
+	}
+
+	ids := make([][]byte, 0, 3)
+	for i := 0; i < 3; i++ {
+		ids = append(ids, test.ValidTraceID(nil))
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i], ids[j]) == -1
+	})
+	dupID := ids[1]
+
+	makeTrace := func(id []byte, spanID byte) *Trace {
+		return &Trace{
+			TraceID: id,
+			ResourceSpans: []ResourceSpans{
+				{
+					Resource: Resource{ServiceName: "s"},
+					ScopeSpans: []ScopeSpans{
+						{
+							Spans: []Span{
+								{
+									Name:         "span",
+									SpanID:       []byte{0, 0, 0, 0, 0, 0, 0, spanID},
+									ParentSpanID: []byte{},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	meta := backend.NewBlockMeta("fake", uuid.New(), VersionString, backend.EncNone, "")
+	meta.TotalObjects = 6
+	s := newStreamingBlock(ctx, cfg, meta, r, w, tempo_io.NewBufferedWriter)
+
+	// the duplicates of the trace span three row groups. the first row group ends with the trace, the others start
+	// with it.
+	rowGroups := [][]*Trace{
+		{makeTrace(ids[0], 1), makeTrace(dupID, 2)},
+		{makeTrace(dupID, 3), makeTrace(dupID, 4)},
+		{makeTrace(dupID, 5), makeTrace(ids[2], 6)},
+	}
+	for _, rg := range rowGroups {
+		for _, tr := range rg {
+			require.NoError(t, s.Add(tr, 0, 0))
+		}
+		_, err = s.Flush()
+		require.NoError(t, err)
+	}
+	_, err = s.Complete()
+	require.NoError(t, err)
+
+	b := newBackendBlock(s.meta, r)
+	pf, _, err := b.openForSearch(ctx, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Len(t, pf.RowGroups(), 3)
+
+	for _, concurrency := range []int{0, 1, 2, 8} {
+		opts := common.DefaultSearchOptions()
+		opts.RowGroupConcurrency = concurrency
+
+		got, err := b.FindTraceByID(ctx, dupID, opts)
+		require.NoError(t, err)
+		require.NotNil(t, got.Trace)
+
+		var spanIDs []byte
+		for _, rs := range got.Trace.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, sp := range ss.Spans {
+					spanIDs = append(spanIDs, sp.SpanId[7])
+				}
+			}
+		}
+		sort.Slice(spanIDs, func(i, j int) bool { return spanIDs[i] < spanIDs[j] })
+		require.Equal(t, []byte{2, 3, 4, 5}, spanIDs, "concurrency %d", concurrency)
+
+		// traces next to the duplicates are found in a single row group
+		for _, tr := range []*Trace{rowGroups[0][0], rowGroups[2][1]} {
+			got, err := b.FindTraceByID(ctx, tr.TraceID, opts)
+			require.NoError(t, err)
+			require.Equal(t, parquetTraceToTempopbTrace(meta, tr), got.Trace)
+		}
+	}
+}
+
 func TestBackendBlockFindTraceByID_TestData(t *testing.T) {
 	rawR, _, _, err := local.New(&local.Config{
 		Path: "./test-data",
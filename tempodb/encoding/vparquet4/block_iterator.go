@@ -24,11 +24,10 @@ func (b *backendBlock) openForIteration(ctx context.Context) (*parquet.File, *pa
 	o := []parquet.FileOption{
 		parquet.SkipBloomFilters(true),
 		parquet.SkipPageIndex(true),
-		parquet.FileSchema(parquetSchema),
 		parquet.FileReadMode(parquet.ReadModeAsync),
 	}
 
-	pf, err := parquet.OpenFile(br, int64(b.meta.Size_), o...)
+	pf, _, err := openParquetFile(br, int64(b.meta.Size_), b.fallback, o...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -37,6 +36,19 @@ func (b *backendBlock) openForIteration(ctx context.Context) (*parquet.File, *pa
 	return pf, r, nil
 }
 
+// TraceIterator returns the traces of the block ordered by ID. It's used to convert the block to another version.
+func (b *backendBlock) TraceIterator(ctx context.Context) (common.Iterator, error) {
+	iter, err := b.rawIter(ctx, newRowPool(1_000_000))
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarks := []*bookmark[parquet.Row]{newBookmark[parquet.Row](iter)}
+	return newCommonIterator(b.meta, newMultiblockIterator(bookmarks, func(rows []parquet.Row) (parquet.Row, error) {
+		return rows[0], nil
+	}), parquet.SchemaOf(new(Trace))), nil
+}
+
 func (b *backendBlock) rawIter(ctx context.Context, pool *rowPool) (*rawIterator, error) {
 	pf, r, err := b.openForIteration(ctx)
 	if err != nil {
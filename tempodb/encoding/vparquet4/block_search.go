@@ -55,6 +55,12 @@ var KindMapping = map[string]int{
 
 // openForSearch consolidates all the logic for opening a parquet file
 func (b *backendBlock) openForSearch(ctx context.Context, opts common.SearchOptions) (*parquet.File, *BackendReaderAt, error) {
+	return b.openForSearchWithReadAhead(ctx, opts, "")
+}
+
+// openForSearchWithReadAhead opens the parquet file like openForSearch. If the adaptive read ahead is enabled and the
+// query class isn't empty, the column chunks learned for the tenant and query class are prefetched together.
+func (b *backendBlock) openForSearchWithReadAhead(ctx context.Context, opts common.SearchOptions, queryClass string) (*parquet.File, *BackendReaderAt, error) {
 	b.openMtx.Lock()
 	defer b.openMtx.Unlock()
 
@@ -65,7 +71,6 @@ func (b *backendBlock) openForSearch(ctx context.Context, opts common.SearchOpti
 		parquet.SkipBloomFilters(true),
 		parquet.SkipPageIndex(true),
 		parquet.FileReadMode(parquet.ReadModeAsync),
-		parquet.FileSchema(parquetSchema),
 	}
 
 	// if the read buffer size provided is <= 0 then we'll use the parquet default
@@ -76,12 +81,26 @@ func (b *backendBlock) openForSearch(ctx context.Context, opts common.SearchOpti
 
 	o = append(o, parquet.ReadBufferSize(readBufferSize))
 
+	var r cacheReaderAt = backendReaderAt
+	var readAhead *readAheadReaderAt
+	if opts.AdaptiveReadAhead && queryClass != "" {
+		// prefetch at most the bytes of the read buffers per row group
+		readAhead = newReadAheadReaderAt(backendReaderAt, defaultReadAheadController, b.meta.TenantID+"/"+queryClass, readBufferSize*max(opts.ReadBufferCount, 1))
+		r = readAhead
+	}
+
 	// cached reader
-	cachedReaderAt := newCachedReaderAt(backendReaderAt, readBufferSize, int64(b.meta.Size_), b.meta.FooterSize) // most reads to the backend are going to be readbuffersize so use it as our "page cache" size
+	cachedReaderAt := newCachedReaderAt(r, readBufferSize, int64(b.meta.Size_), b.meta.FooterSize) // most reads to the backend are going to be readbuffersize so use it as our "page cache" size
 
 	_, span := tracer.Start(ctx, "parquet.OpenFile")
 	defer span.End()
-	pf, err := parquet.OpenFile(cachedReaderAt, int64(b.meta.Size_), o...)
+	pf, fallback, err := openParquetFile(cachedReaderAt, int64(b.meta.Size_), b.fallback, o...)
+	if err == nil {
+		b.fallback = fallback
+	}
+	if err == nil && readAhead != nil {
+		readAhead.setFile(pf)
+	}
 
 	return pf, backendReaderAt, err
 }
@@ -353,6 +372,11 @@ func makeIterFunc(ctx context.Context, rgs []parquet.RowGroup, pf *parquet.File)
 	return func(name string, predicate pq.Predicate, selectAs string) pq.Iterator {
 		index, _, maxDef := pq.GetColumnIndexByPath(pf, name)
 		if index == -1 {
+			if pf.Schema() != parquetSchema {
+				// files written by a newer encoding or before the span flags column are opened with their own schema
+				// and may not have the column
+				return &rowNumberIterator{}
+			}
 			// TODO - don't panic, error instead
 			panic("column not found in parquet file:" + name)
 		}
@@ -1,17 +1,22 @@
 package vparquet4
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"math/rand"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
 	"github.com/stretchr/testify/require"
 
 	tempo_io "github.com/grafana/tempo/pkg/io"
 	"github.com/grafana/tempo/pkg/tempopb"
 	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/grafana/tempo/tempodb/backend"
@@ -248,7 +253,254 @@ func TestBackendBlockSearch(t *testing.T) {
 	}
 }
 
+func TestBackendBlockOpenNewerBlock(t *testing.T) {
+	ctx := context.Background()
+	wantTr := fullyPopulatedTestTrace(nil)
+	b := makeBackendBlockWithTraces(t, []*Trace{wantTr})
+
+	meta := *b.meta
+	meta.Version = "vParquet5"
+	bb, err := Encoding{}.OpenNewerBlock(&meta, b.r)
+	require.NoError(t, err)
+	newer := bb.(*backendBlock)
+
+	want, err := b.FindTraceByID(ctx, wantTr.TraceID, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	got, err := newer.FindTraceByID(ctx, wantTr.TraceID, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Equal(t, want.Trace, got.Trace)
+
+	pf, _, err := newer.openForSearch(ctx, common.DefaultSearchOptions())
+	require.NoError(t, err)
+
+	// columns missing from the file of a newer block match nothing
+	iter := makeIterFunc(ctx, pf.RowGroups(), pf)("not.a.column", nil, "")
+	defer iter.Close()
+	res, err := iter.Next()
+	require.NoError(t, err)
+	require.Nil(t, res)
+
+	// and are still not expected from blocks of this version
+	pf, _, err = b.openForSearch(ctx, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Panics(t, func() {
+		makeIterFunc(ctx, pf.RowGroups(), pf)("not.a.column", nil, "")
+	})
+}
+
+func TestBackendBlockTraceLevelColumnStatistics(t *testing.T) {
+	ctx := context.Background()
+
+	var trs []*Trace
+	for i, root := range []string{"b", "c", "a"} {
+		tr := fullyPopulatedTestTrace(common.ID{byte(i + 1)})
+		tr.StartTimeUnixNano = uint64(i+1) * uint64(time.Second)
+		tr.DurationNano = uint64(3-i) * uint64(time.Millisecond)
+		tr.RootServiceName = root + "-service"
+		tr.RootSpanName = root + "-span"
+		trs = append(trs, tr)
+	}
+	b := makeBackendBlockWithTraces(t, trs)
+
+	pf, _, err := b.openForSearch(ctx, common.DefaultSearchOptions())
+	require.NoError(t, err)
+
+	// the trace-level intrinsics are filtered and ordered by the min/max statistics of their column chunks and pages
+	for _, tc := range []struct {
+		column   string
+		min, max parquet.Value
+	}{
+		{columnPathStartTimeUnixNano, parquet.Int64Value(int64(time.Second)), parquet.Int64Value(int64(3 * time.Second))},
+		{columnPathDurationNanos, parquet.Int64Value(int64(time.Millisecond)), parquet.Int64Value(int64(3 * time.Millisecond))},
+		{columnPathRootServiceName, parquet.ByteArrayValue([]byte("a-service")), parquet.ByteArrayValue([]byte("c-service"))},
+		{columnPathRootSpanName, parquet.ByteArrayValue([]byte("a-span")), parquet.ByteArrayValue([]byte("c-span"))},
+	} {
+		t.Run(tc.column, func(t *testing.T) {
+			leaf, ok := pf.Schema().Lookup(tc.column)
+			require.True(t, ok)
+			typ := leaf.Node.Type()
+
+			var chunkMin, chunkMax, pageMin, pageMax parquet.Value
+			extend := func(minV, maxV *parquet.Value, v parquet.Value) {
+				if minV.IsNull() || typ.Compare(v, *minV) < 0 {
+					*minV = v
+				}
+				if maxV.IsNull() || typ.Compare(v, *maxV) > 0 {
+					*maxV = v
+				}
+			}
+			for _, rg := range pf.RowGroups() {
+				chunk := rg.ColumnChunks()[leaf.ColumnIndex].(*parquet.FileColumnChunk)
+
+				minV, maxV, ok := chunk.Bounds()
+				require.True(t, ok)
+				extend(&chunkMin, &chunkMax, minV)
+				extend(&chunkMin, &chunkMax, maxV)
+
+				index, err := chunk.ColumnIndex()
+				require.NoError(t, err)
+				require.Positive(t, index.NumPages())
+				for i := 0; i < index.NumPages(); i++ {
+					extend(&pageMin, &pageMax, index.MinValue(i))
+					extend(&pageMin, &pageMax, index.MaxValue(i))
+				}
+			}
+			for _, v := range [][2]parquet.Value{{tc.min, chunkMin}, {tc.max, chunkMax}, {tc.min, pageMin}, {tc.max, pageMax}} {
+				require.True(t, parquet.Equal(v[0], v[1]), "expected %v, got %v", v[0], v[1])
+			}
+		})
+	}
+}
+
+// withoutFieldNode removes the field at path from a node, like the schema of the files written before the field was
+// added to it.
+type withoutFieldNode struct {
+	parquet.Node
+	path []string
+}
+
+func (n *withoutFieldNode) Fields() []parquet.Field {
+	return withoutField(n.Node.Fields(), n.path)
+}
+
+type withoutFieldField struct {
+	parquet.Field
+	path []string
+}
+
+func (f *withoutFieldField) Fields() []parquet.Field {
+	return withoutField(f.Field.Fields(), f.path)
+}
+
+func withoutField(fields []parquet.Field, path []string) []parquet.Field {
+	wrapped := make([]parquet.Field, 0, len(fields))
+	for _, f := range fields {
+		switch {
+		case f.Name() != path[0]:
+			wrapped = append(wrapped, f)
+		case len(path) > 1:
+			wrapped = append(wrapped, &withoutFieldField{Field: f, path: path[1:]})
+		}
+	}
+	return wrapped
+}
+
+func TestBackendBlockWithoutSpanFlags(t *testing.T) {
+	ctx := context.Background()
+	wantTr := fullyPopulatedTestTrace(nil)
+	b, w := makeBackendBlockWithTracesWriter(t, []*Trace{wantTr})
+	want, err := b.FindTraceByID(ctx, wantTr.TraceID, common.DefaultSearchOptions())
+	require.NoError(t, err)
+
+	// rewrite the data of the block without the span flags column, like the blocks written before it was added
+	schema := parquet.NewSchema(parquetSchema.Name(), &withoutFieldNode{Node: parquetSchema, path: strings.Split(columnPathSpanFlags, ".")})
+	require.Len(t, schema.Columns(), len(parquetSchema.Columns())-1)
+
+	buf := &bytes.Buffer{}
+	pw := parquet.NewWriter(buf, schema)
+	require.NoError(t, pw.Write(wantTr))
+	require.NoError(t, pw.Close())
+
+	data := buf.Bytes()
+	meta := *b.meta
+	meta.Size_ = uint64(len(data))
+	meta.FooterSize = binary.LittleEndian.Uint32(data[len(data)-8 : len(data)-4])
+
+	err = w.Write(ctx, DataFileName, (uuid.UUID)(meta.BlockID), meta.TenantID, data, nil)
+	require.NoError(t, err)
+
+	bb := newBackendBlock(&meta, b.r)
+	got, err := bb.FindTraceByID(ctx, wantTr.TraceID, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.True(t, bb.fallback)
+
+	// the spans have no flags, the trace is unchanged otherwise
+	for _, rs := range want.Trace.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				s.Flags = 0
+			}
+		}
+	}
+	require.Equal(t, want.Trace, got.Trace)
+
+	for q, matches := range map[string]bool{
+		`{ trace:sampled = true }`: false,
+		`{ span.foo = "def" }`:     true,
+	} {
+		req := traceql.MustExtractFetchSpansRequestWithMetadata(q)
+		req.SecondPass = func(s *traceql.Spanset) ([]*traceql.Spanset, error) { return []*traceql.Spanset{s}, nil }
+		resp, err := bb.Fetch(ctx, req, common.DefaultSearchOptions())
+		require.NoError(t, err, q)
+
+		ss, err := resp.Results.Next(ctx)
+		require.NoError(t, err, q)
+		require.Equal(t, matches, ss != nil, q)
+		resp.Results.Close()
+	}
+
+	iter, err := bb.TraceIterator(ctx)
+	require.NoError(t, err)
+	defer iter.Close()
+	_, tr, err := iter.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, want.Trace, tr)
+}
+
+// newerTrace has a column that traces of this version don't have, like the schema of a newer version would.
+type newerTrace struct {
+	NewColumn string `parquet:",snappy,dict"`
+	Trace
+}
+
+func TestBackendBlockOpenNewerBlockSchemaMismatch(t *testing.T) {
+	ctx := context.Background()
+	wantTr := fullyPopulatedTestTrace(nil)
+	b, w := makeBackendBlockWithTracesWriter(t, []*Trace{wantTr})
+	want, err := b.FindTraceByID(ctx, wantTr.TraceID, common.DefaultSearchOptions())
+	require.NoError(t, err)
+
+	// rewrite the data of the block with the schema of a newer version, the index and blooms are still valid as the
+	// block has a single trace.
+	buf := &bytes.Buffer{}
+	pw := parquet.NewGenericWriter[*newerTrace](buf)
+	_, err = pw.Write([]*newerTrace{{NewColumn: "new", Trace: *wantTr}})
+	require.NoError(t, err)
+	require.NoError(t, pw.Close())
+
+	data := buf.Bytes()
+	meta := *b.meta
+	meta.Version = "vParquet5"
+	meta.Size_ = uint64(len(data))
+	meta.FooterSize = binary.LittleEndian.Uint32(data[len(data)-8 : len(data)-4])
+
+	err = w.Write(ctx, DataFileName, (uuid.UUID)(meta.BlockID), meta.TenantID, data, nil)
+	require.NoError(t, err)
+
+	// the schema of the file doesn't match the schema of this version
+	require.Panics(t, func() {
+		_, _ = newBackendBlock(&meta, b.r).FindTraceByID(ctx, wantTr.TraceID, common.DefaultSearchOptions())
+	})
+
+	bb, err := Encoding{}.OpenNewerBlock(&meta, b.r)
+	require.NoError(t, err)
+
+	got, err := bb.FindTraceByID(ctx, wantTr.TraceID, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Equal(t, want.Trace, got.Trace)
+
+	resp, err := bb.Search(ctx, &tempopb.SearchRequest{Tags: map[string]string{"http.method": "get"}}, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Len(t, resp.Traces, 1)
+	require.Equal(t, util.TraceIDToHexString(wantTr.TraceID), resp.Traces[0].TraceID)
+}
+
 func makeBackendBlockWithTraces(t *testing.T, trs []*Trace) *backendBlock {
+	b, _ := makeBackendBlockWithTracesWriter(t, trs)
+	return b
+}
+
+func makeBackendBlockWithTracesWriter(t *testing.T, trs []*Trace) (*backendBlock, backend.Writer) {
 	rawR, rawW, _, err := local.New(&local.Config{
 		Path: t.TempDir(),
 	})
@@ -283,7 +535,7 @@ func makeBackendBlockWithTraces(t *testing.T, trs []*Trace) *backendBlock {
 
 	b := newBackendBlock(s.meta, r)
 
-	return b
+	return b, w
 }
 
 func makeTraces() ([]*Trace, map[string]string, map[string]string, map[string]string) {
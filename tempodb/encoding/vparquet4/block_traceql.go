@@ -32,6 +32,10 @@ var (
 	pqInstrumentationPool = parquetquery.NewResultPool(1)
 )
 
+// spanFlagSampled is the W3C sampled trace flag in the OTLP span flags. The spans ingested without flags have no
+// sampled flag, neither set nor unset.
+const spanFlagSampled = 0x01
+
 type attrVal struct {
 	a traceql.Attribute
 	s traceql.Static
@@ -922,6 +926,8 @@ const (
 	columnPathSpanKind            = "rs.list.element.ss.list.element.Spans.list.element.Kind"
 	columnPathSpanStatusCode      = "rs.list.element.ss.list.element.Spans.list.element.StatusCode"
 	columnPathSpanStatusMessage   = "rs.list.element.ss.list.element.Spans.list.element.StatusMessage"
+	columnPathSpanTraceState      = "rs.list.element.ss.list.element.Spans.list.element.TraceState"
+	columnPathSpanFlags           = "rs.list.element.ss.list.element.Spans.list.element.Flags"
 	columnPathSpanAttrKey         = "rs.list.element.ss.list.element.Spans.list.element.Attrs.list.element.Key"
 	columnPathSpanAttrString      = "rs.list.element.ss.list.element.Spans.list.element.Attrs.list.element.Value.list.element"
 	columnPathSpanAttrInt         = "rs.list.element.ss.list.element.Spans.list.element.Attrs.list.element.ValueInt.list.element"
@@ -984,6 +990,9 @@ var intrinsicColumnLookups = map[traceql.Intrinsic]struct {
 	traceql.IntrinsicNestedSetRight:       {intrinsicScopeSpan, traceql.TypeInt, columnPathSpanNestedSetRight},
 	traceql.IntrinsicNestedSetParent:      {intrinsicScopeSpan, traceql.TypeInt, columnPathSpanParentID},
 
+	traceql.IntrinsicTraceState:   {intrinsicScopeSpan, traceql.TypeString, columnPathSpanTraceState},
+	traceql.IntrinsicTraceSampled: {intrinsicScopeSpan, traceql.TypeBoolean, columnPathSpanFlags},
+
 	traceql.IntrinsicTraceRootService: {intrinsicScopeTrace, traceql.TypeString, columnPathRootServiceName},
 	traceql.IntrinsicTraceRootSpan:    {intrinsicScopeTrace, traceql.TypeString, columnPathRootSpanName},
 	traceql.IntrinsicTraceDuration:    {intrinsicScopeTrace, traceql.TypeString, columnPathDurationNanos},
@@ -1035,12 +1044,31 @@ func (b *backendBlock) Fetch(ctx context.Context, req traceql.FetchSpansRequest,
 
 	coalesceConditions(&req)
 
-	pf, rr, err := b.openForSearch(ctx, opts)
+	// searches for a service or span name are resolved to the row groups with the name by the name dictionary of the
+	// block, before the block is opened
+	nameRowGroups, dictionaryBytes, found, err := b.nameDictionaryRowGroups(ctx, req)
+	if err != nil {
+		return traceql.FetchSpansResponse{}, err
+	}
+	if found {
+		nameRowGroups = rowGroupsInPages(nameRowGroups, opts)
+	}
+	if found && len(nameRowGroups) == 0 {
+		return traceql.FetchSpansResponse{
+			Results: &mergeSpansetIterator{},
+			Bytes:   func() uint64 { return dictionaryBytes },
+		}, nil
+	}
+
+	pf, rr, err := b.openForSearchWithReadAhead(ctx, opts, fetchQueryClass(req))
 	if err != nil {
 		return traceql.FetchSpansResponse{}, err
 	}
 
 	rgs := rowGroupsFromFile(pf, opts)
+	if found {
+		rgs = filterRowGroups(rgs, firstRowGroup(opts), nameRowGroups)
+	}
 
 	iter, err := fetch(ctx, req, pf, rgs, b.meta.DedicatedColumns)
 	if err != nil {
@@ -1049,7 +1077,7 @@ func (b *backendBlock) Fetch(ctx context.Context, req traceql.FetchSpansRequest,
 
 	return traceql.FetchSpansResponse{
 		Results: iter,
-		Bytes:   func() uint64 { return rr.BytesRead() },
+		Bytes:   func() uint64 { return dictionaryBytes + rr.BytesRead() },
 	}, nil
 }
 
@@ -1948,6 +1976,24 @@ func createSpanIterator(makeIter makeIterFn, innerIterators []parquetquery.Itera
 			columnSelectAs[columnPathSpanStatusMessage] = columnPathSpanStatusMessage
 			continue
 
+		case traceql.IntrinsicTraceState:
+			pred, err := createStringPredicate(cond.Op, cond.Operands)
+			if err != nil {
+				return nil, err
+			}
+			addPredicate(columnPathSpanTraceState, pred)
+			columnSelectAs[columnPathSpanTraceState] = columnPathSpanTraceState
+			continue
+
+		case traceql.IntrinsicTraceSampled:
+			pred, err := createSampledPredicate(cond.Op, cond.Operands)
+			if err != nil {
+				return nil, err
+			}
+			addPredicate(columnPathSpanFlags, pred)
+			columnSelectAs[columnPathSpanFlags] = columnPathSpanFlags
+			continue
+
 		case traceql.IntrinsicStructuralDescendant:
 			addNilPredicateIfNotAlready(columnPathSpanNestedSetLeft)
 			addNilPredicateIfNotAlready(columnPathSpanNestedSetRight)
@@ -2700,6 +2746,51 @@ func createBoolPredicate(op traceql.Operator, operands traceql.Operands) (parque
 	}
 }
 
+// createSampledPredicate returns the predicate of the sampled flag in the span flags column.
+func createSampledPredicate(op traceql.Operator, operands traceql.Operands) (parquetquery.Predicate, error) {
+	if op == traceql.OpNone {
+		return nil, nil
+	}
+
+	b, ok := operands[0].Bool()
+	if !ok {
+		return nil, fmt.Errorf("operand is not bool: %+v", operands[0].EncodeToString(false))
+	}
+
+	switch op {
+	case traceql.OpEqual:
+		return &sampledPredicate{sampled: b}, nil
+	case traceql.OpNotEqual:
+		return &sampledPredicate{sampled: !b}, nil
+	default:
+		return nil, fmt.Errorf("operator not supported for booleans: %+v", op)
+	}
+}
+
+// sampledPredicate keeps the spans of which the sampled flag is set or unset, the spans without flags are dropped.
+type sampledPredicate struct {
+	sampled bool
+}
+
+var _ parquetquery.Predicate = (*sampledPredicate)(nil)
+
+func (p *sampledPredicate) String() string {
+	return fmt.Sprintf("sampledPredicate{%t}", p.sampled)
+}
+
+func (p *sampledPredicate) KeepColumnChunk(*parquetquery.ColumnChunkHelper) bool {
+	return true
+}
+
+func (p *sampledPredicate) KeepPage(parquet.Page) bool {
+	return true
+}
+
+func (p *sampledPredicate) KeepValue(v parquet.Value) bool {
+	flags := v.Uint32()
+	return flags != 0 && (flags&spanFlagSampled != 0) == p.sampled
+}
+
 func createAttributeIterator(makeIter makeIterFn, conditions []traceql.Condition,
 	definitionLevel int,
 	keyPath, strPath, intPath, floatPath, boolPath string,
@@ -2885,6 +2976,12 @@ func (c *spanCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
 			sp.addSpanAttr(traceql.IntrinsicStatusAttribute, traceql.NewStaticStatus(otlpStatusToTraceqlStatus(kv.Value.Uint64())))
 		case columnPathSpanStatusMessage:
 			sp.addSpanAttr(traceql.IntrinsicStatusMessageAttribute, traceql.NewStaticString(unsafeToString(kv.Value.Bytes())))
+		case columnPathSpanTraceState:
+			sp.addSpanAttr(traceql.IntrinsicTraceStateAttribute, traceql.NewStaticString(unsafeToString(kv.Value.Bytes())))
+		case columnPathSpanFlags:
+			if flags := kv.Value.Uint32(); flags != 0 {
+				sp.addSpanAttr(traceql.IntrinsicTraceSampledAttribute, traceql.NewStaticBool(flags&spanFlagSampled != 0))
+			}
 		case columnPathSpanKind:
 			sp.addSpanAttr(traceql.IntrinsicKindAttribute, traceql.NewStaticKind(otlpKindToTraceqlKind(kv.Value.Uint64())))
 		case columnPathSpanParentID:
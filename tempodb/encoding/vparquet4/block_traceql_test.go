@@ -235,6 +235,9 @@ func TestBackendBlockSearchTraceQL(t *testing.T) {
 		{"Intrinsic: statusMessage = STATUS_CODE_ERROR", traceql.MustExtractFetchSpansRequestWithMetadata(`{` + "statusMessage" + ` = "STATUS_CODE_ERROR"}`)},
 		{"Intrinsic: kind = client", traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelKind + ` = client }`)},
 		{"Intrinsic: trace:id", traceql.MustExtractFetchSpansRequestWithMetadata(`{ trace:id = "` + traceIDText + `" }`)},
+		{"Intrinsic: trace:state", traceql.MustExtractFetchSpansRequestWithMetadata(`{ trace:state = "tracestate" }`)},
+		{"Intrinsic: trace:state regex", traceql.MustExtractFetchSpansRequestWithMetadata(`{ trace:state =~ "trace.*" }`)},
+		{"Intrinsic: trace:sampled", traceql.MustExtractFetchSpansRequestWithMetadata(`{ trace:sampled = true }`)},
 		// Resource well-known attributes
 		{".service.name", traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelServiceName + ` = "spanservicename"}`)}, // Overridden at span},
 		{".cluster", traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelCluster + ` = "cluster"}`)},
@@ -418,6 +421,8 @@ func TestBackendBlockSearchTraceQL(t *testing.T) {
 		{"Intrinsic: statusMessage", traceql.MustExtractFetchSpansRequestWithMetadata(`{` + "statusMessage" + ` = "abc"}`)},
 		{"Intrinsic: name", traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelName + ` = "nothello"}`)},
 		{"Intrinsic: kind", traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelKind + ` = producer }`)},
+		{"Intrinsic: trace:state", traceql.MustExtractFetchSpansRequestWithMetadata(`{ trace:state = "nope" }`)},
+		{"Intrinsic: trace:sampled", traceql.MustExtractFetchSpansRequestWithMetadata(`{ trace:sampled = false }`)},
 		{"Intrinsic: event:name", traceql.MustExtractFetchSpansRequestWithMetadata(`{event:name = "x2"}`)},
 		{"Intrinsic: link:spanID", traceql.MustExtractFetchSpansRequestWithMetadata(`{link:spanID = "ffffffffffffffff"}`)},
 		{"Intrinsic: link:traceID", traceql.MustExtractFetchSpansRequestWithMetadata(`{link:traceID = "ffffffffffffffffffffffffffffffff"}`)},
@@ -705,6 +710,7 @@ func fullyPopulatedTestTraceWithOption(id common.ID, parentIDTest bool) *Trace {
 								StatusCode:             int(v1.Status_STATUS_CODE_ERROR),
 								StatusMessage:          v1.Status_STATUS_CODE_ERROR.String(),
 								TraceState:             "tracestate",
+								Flags:                  0x101, // sampled, not remote
 								Kind:                   int(v1.Span_SPAN_KIND_CLIENT),
 								DroppedAttributesCount: 42,
 								DroppedEventsCount:     43,
@@ -1011,6 +1017,10 @@ func flattenForSelectAll(tr *Trace, dcm dedicatedColumnMapping) *traceql.Spanset
 				newS.addSpanAttr(traceql.IntrinsicNameAttribute, traceql.NewStaticString(s.Name))
 				newS.addSpanAttr(traceql.IntrinsicStatusAttribute, traceql.NewStaticStatus(otlpStatusToTraceqlStatus(uint64(s.StatusCode))))
 				newS.addSpanAttr(traceql.IntrinsicStatusMessageAttribute, traceql.NewStaticString(s.StatusMessage))
+				newS.addSpanAttr(traceql.IntrinsicTraceStateAttribute, traceql.NewStaticString(s.TraceState))
+				if s.Flags != 0 {
+					newS.addSpanAttr(traceql.IntrinsicTraceSampledAttribute, traceql.NewStaticBool(s.Flags&spanFlagSampled != 0))
+				}
 
 				if s.HttpStatusCode != nil {
 					newS.addSpanAttr(traceql.NewScopedAttribute(traceql.AttributeScopeSpan, false, LabelHTTPStatusCode), traceql.NewStaticInt(int(*s.HttpStatusCode)))
@@ -1119,6 +1129,77 @@ func BenchmarkBackendBlockTraceQL(b *testing.B) {
 	}
 }
 
+// BenchmarkBackendBlockFetchPredicates measures the fetch layer for a single predicate per operator and
+// column type. It runs against a generated block so it doesn't need a block on local disk and can be
+// compared between commits to catch regressions in the read path.
+func BenchmarkBackendBlockFetchPredicates(b *testing.B) {
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{"intrinsicEqual", "{ name = `test` }"},
+		{"intrinsicNotEqual", "{ name != `test` }"},
+		{"intrinsicRegex", "{ name =~ `te.*` }"},
+		{"intrinsicNotRegex", "{ name !~ `te.*` }"},
+		{"intrinsicGreater", "{ duration > 500ms }"},
+		{"intrinsicEnum", "{ kind = client }"},
+		{"resourceWellKnown", "{ resource.service.name = `test-service` }"},
+		{"resourceDedicated", "{ resource.dedicated.resource.1 = `dedicated-resource-attr-value-1` }"},
+		{"resourceGeneric", "{ resource.random.res.attr != `foo` }"},
+		{"spanDedicated", "{ span.dedicated.span.1 = `dedicated-span-attr-value-1` }"},
+		{"spanGeneric", "{ span.foo = `bar` }"},
+		{"spanGenericExists", "{ span.foo != nil }"},
+		{"unscoped", "{ .foo = `bar` }"},
+	}
+
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: b.TempDir(),
+	})
+	require.NoError(b, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+	ctx := context.Background()
+
+	cfg := &common.BlockConfig{
+		BloomFP:             0.01,
+		BloomShardSizeBytes: 100 * 1024,
+		RowGroupSizeBytes:   20_000_000,
+	}
+	meta := createTestBlock(b, ctx, cfg, r, w, 1000, 5, 20, 0, test.MakeDedicatedColumns())
+	block := newBackendBlock(meta, r)
+	opts := common.DefaultSearchOptions()
+
+	for _, tc := range testCases {
+		b.Run(tc.name, func(b *testing.B) {
+			_, _, _, _, req, err := traceql.Compile(tc.query)
+			require.NoError(b, err)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			bytesRead := 0
+
+			for i := 0; i < b.N; i++ {
+				resp, err := block.Fetch(ctx, *req, opts)
+				require.NoError(b, err)
+
+				for {
+					ss, err := resp.Results.Next(ctx)
+					require.NoError(b, err)
+					if ss == nil {
+						break
+					}
+				}
+				resp.Results.Close()
+
+				bytesRead += int(resp.Bytes())
+			}
+			b.SetBytes(int64(bytesRead) / int64(b.N))
+			b.ReportMetric(float64(bytesRead)/float64(b.N)/1000.0/1000.0, "MB_io/op")
+		})
+	}
+}
+
 // BenchmarkBackendBlockGetMetrics This doesn't really belong here but I can't think of
 // a better place that has access to all of the packages, especially the backend.
 func BenchmarkBackendBlockGetMetrics(b *testing.B) {
@@ -81,10 +81,15 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 		replicationFactor   = inputs[0].ReplicationFactor
 		nextCompactionLevel = compactionLevel + 1
 		sch                 = parquet.SchemaOf(new(Trace))
+
+		// duplicates and dedupedBytes of the last combined trace, for the TraceDeduped callback
+		duplicates, dedupedBytes int
 	)
 
 	// Dedupe rows and also call the metrics callback.
 	combine := func(rows []parquet.Row) (parquet.Row, error) {
+		duplicates, dedupedBytes = 0, 0
+
 		if len(rows) == 0 {
 			return nil, nil
 		}
@@ -98,7 +103,11 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 			isEqual = rows[0].Equal(rows[i])
 		}
 		if isEqual {
+			duplicates = len(rows) - 1
 			for i := 1; i < len(rows); i++ {
+				if c.opts.TraceDeduped != nil {
+					dedupedBytes += estimateMarshalledSizeFromParquetRow(rows[i])
+				}
 				pool.Put(rows[i])
 			}
 			return rows[0], nil
@@ -124,6 +133,9 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 		cmb := NewCombiner()
 		dedupedSpans := 0
 		for i, row := range rows {
+			if c.opts.TraceDeduped != nil {
+				dedupedBytes += estimateMarshalledSizeFromParquetRow(row)
+			}
 			tr := new(Trace)
 			err := sch.Reconstruct(tr, row)
 			if err != nil {
@@ -142,14 +154,26 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 		}
 
 		c.opts.ObjectsCombined(int(compactionLevel), 1)
-		return sch.Deconstruct(pool.Get(), tr), nil
+		row := sch.Deconstruct(pool.Get(), tr)
+		duplicates = len(rows) - 1
+		if c.opts.TraceDeduped != nil {
+			dedupedBytes -= estimateMarshalledSizeFromParquetRow(row)
+		}
+		return row, nil
 	}
 
 	var (
 		m               = newMultiblockIterator(bookmarks, combine)
 		recordsPerBlock = (totalRecords / int64(c.opts.OutputBlocks))
 		currentBlock    *streamingBlock
+
+		// the rows are converted if the output blocks have other dedicated columns than the inputs
+		dedicatedColumns = inputs[0].DedicatedColumns
+		convertColumns   = c.opts.DedicatedColumns != nil && c.opts.DedicatedColumns.Hash() != dedicatedColumns.Hash()
 	)
+	if convertColumns {
+		dedicatedColumns = c.opts.DedicatedColumns
+	}
 	defer m.Close()
 
 	for {
@@ -166,6 +190,13 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 			continue
 		}
 
+		if c.opts.AttributeFilter != nil || c.opts.AttributeRedaction != nil || convertColumns {
+			lowestObject, err = c.filterAttributes(sch, inputs[0], dedicatedColumns, convertColumns, lowestID, lowestObject)
+			if err != nil {
+				return nil, fmt.Errorf("error applying attribute policy: %w", err)
+			}
+		}
+
 		// make a new block if necessary
 		if currentBlock == nil {
 			// Start with a copy and then customize
@@ -175,14 +206,20 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 				CompactionLevel:   nextCompactionLevel,
 				TotalObjects:      recordsPerBlock, // Just an estimate
 				ReplicationFactor: replicationFactor,
-				DedicatedColumns:  inputs[0].DedicatedColumns,
+				DedicatedColumns:  dedicatedColumns,
+				RetentionClass:    c.opts.RetentionClass,
 			}
+			newMeta.CombineErrorTimes(inputs)
 
 			currentBlock = newStreamingBlock(ctx, &c.opts.BlockConfig, newMeta, r, w, tempo_io.NewBufferedWriter)
 			currentBlock.meta.CompactionLevel = nextCompactionLevel
 			newCompactedBlocks = append(newCompactedBlocks, currentBlock.meta)
 		}
 
+		if duplicates > 0 && c.opts.TraceDeduped != nil {
+			c.opts.TraceDeduped(currentBlock.meta.BlockID, lowestID, duplicates, max(dedupedBytes, 0))
+		}
+
 		// Flush existing block data if the next trace can't fit
 		if currentBlock.EstimatedBufferedBytes() > 0 && currentBlock.EstimatedBufferedBytes()+estimateMarshalledSizeFromParquetRow(lowestObject) > c.opts.BlockConfig.RowGroupSizeBytes {
 			runtime.GC()
@@ -237,6 +274,35 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 	return newCompactedBlocks, nil
 }
 
+// filterAttributes enforces the storage attribute policy and the attribute redaction on a single row and moves its
+// attributes to the dedicated columns of the output blocks if convert is set. The row is only rewritten if
+// attributes were dropped, redacted or converted.
+func (c *Compactor) filterAttributes(sch *parquet.Schema, meta *backend.BlockMeta, dedicatedColumns backend.DedicatedColumns, convert bool, id common.ID, row parquet.Row) (parquet.Row, error) {
+	tr := new(Trace)
+	err := sch.Reconstruct(tr, row)
+	if err != nil {
+		return nil, err
+	}
+
+	pbTrace := parquetTraceToTempopbTrace(meta, tr)
+	dropped := c.opts.AttributeFilter.FilterTrace(pbTrace)
+	redacted := c.opts.AttributeRedaction.RedactTrace(pbTrace)
+	if dropped == 0 && redacted == 0 && !convert {
+		return row, nil
+	}
+
+	if dropped > 0 && c.opts.AttributesDropped != nil {
+		c.opts.AttributesDropped(dropped)
+	}
+	if redacted > 0 && c.opts.AttributesRedacted != nil {
+		c.opts.AttributesRedacted(redacted)
+	}
+
+	tr, _ = traceToParquet(&backend.BlockMeta{DedicatedColumns: dedicatedColumns}, id, pbTrace, tr)
+	pool.Put(row)
+	return sch.Deconstruct(pool.Get(), tr), nil
+}
+
 func (c *Compactor) appendBlock(ctx context.Context, block *streamingBlock, l log.Logger) error {
 	_, span := tracer.Start(ctx, "vparquet.compactor.appendBlock")
 	defer span.End()
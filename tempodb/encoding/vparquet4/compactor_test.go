@@ -217,6 +217,179 @@ func TestCompact(t *testing.T) {
 	require.Equal(t, dedicatedColumns, newMeta[0].DedicatedColumns)
 }
 
+func TestCompactAttributePolicy(t *testing.T) {
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+
+	blockConfig := common.BlockConfig{Version: VersionString}
+	blockConfig.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+
+	droppedBytes := 0
+	c := NewCompactor(common.CompactionOptions{
+		BlockConfig:       blockConfig,
+		OutputBlocks:      1,
+		FlushSizeBytes:    30_000_000,
+		ObjectsCombined:   func(compactionLevel, objects int) {},
+		AttributeFilter:   common.NewAttributeFilter(common.AttributePolicy{Deny: []string{"random.res.attr"}}),
+		AttributesDropped: func(bytes int) { droppedBytes += bytes },
+	})
+
+	meta1 := createTestBlock(t, context.Background(), &blockConfig, r, w, 10, 10, 10, 1, nil)
+	meta2 := createTestBlock(t, context.Background(), &blockConfig, r, w, 10, 10, 10, 1, nil)
+
+	newMeta, err := c.Compact(context.Background(), log.NewNopLogger(), r, w, []*backend.BlockMeta{meta1, meta2})
+	require.NoError(t, err)
+	require.Len(t, newMeta, 1)
+	require.Equal(t, int64(20), newMeta[0].TotalObjects)
+	require.Greater(t, droppedBytes, 0)
+
+	iter, err := newBackendBlock(newMeta[0], r).rawIter(context.Background(), newRowPool(10))
+	require.NoError(t, err)
+	defer iter.Close()
+
+	sch := parquet.SchemaOf(new(Trace))
+	count := 0
+	for {
+		_, row, err := iter.Next(context.Background())
+		require.NoError(t, err)
+		if row == nil {
+			break
+		}
+
+		tr := new(Trace)
+		require.NoError(t, sch.Reconstruct(tr, row))
+		for _, rs := range tr.ResourceSpans {
+			require.NotEmpty(t, rs.Resource.ServiceName)
+			for _, a := range rs.Resource.Attrs {
+				require.NotEqual(t, "random.res.attr", a.Key)
+			}
+		}
+		count++
+	}
+	require.Equal(t, 20, count)
+}
+
+func TestCompactAttributeRedaction(t *testing.T) {
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+
+	blockConfig := common.BlockConfig{Version: VersionString}
+	blockConfig.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+
+	redactedValues := 0
+	c := NewCompactor(common.CompactionOptions{
+		BlockConfig:        blockConfig,
+		OutputBlocks:       1,
+		FlushSizeBytes:     30_000_000,
+		ObjectsCombined:    func(compactionLevel, objects int) {},
+		AttributeRedaction: &common.AttributeRedaction{Key: "key", Value: "value"},
+		AttributesRedacted: func(values int) { redactedValues += values },
+	})
+
+	meta := createTestBlock(t, context.Background(), &blockConfig, r, w, 10, 10, 10, 1, nil)
+
+	newMeta, err := c.Compact(context.Background(), log.NewNopLogger(), r, w, []*backend.BlockMeta{meta})
+	require.NoError(t, err)
+	require.Len(t, newMeta, 1)
+	require.Equal(t, int64(10), newMeta[0].TotalObjects)
+
+	iter, err := newBackendBlock(newMeta[0], r).rawIter(context.Background(), newRowPool(10))
+	require.NoError(t, err)
+	defer iter.Close()
+
+	sch := parquet.SchemaOf(new(Trace))
+	count := 0
+	for {
+		_, row, err := iter.Next(context.Background())
+		require.NoError(t, err)
+		if row == nil {
+			break
+		}
+
+		tr := new(Trace)
+		require.NoError(t, sch.Reconstruct(tr, row))
+		for _, rs := range tr.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					for _, a := range s.Attrs {
+						if a.Key == "key" {
+							require.Equal(t, []string{common.RedactedValue}, a.Value)
+							count++
+						}
+					}
+				}
+			}
+		}
+	}
+	require.Greater(t, count, 0)
+	require.Equal(t, count, redactedValues)
+}
+
+func TestCompactDedicatedColumns(t *testing.T) {
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+
+	blockConfig := common.BlockConfig{Version: VersionString}
+	blockConfig.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+
+	columns := backend.DedicatedColumns{
+		{Scope: backend.DedicatedColumnScopeSpan, Name: "dedicated.span.2", Type: backend.DedicatedColumnTypeString},
+		{Scope: backend.DedicatedColumnScopeSpan, Name: "key", Type: backend.DedicatedColumnTypeString},
+	}
+	c := NewCompactor(common.CompactionOptions{
+		BlockConfig:      blockConfig,
+		OutputBlocks:     1,
+		FlushSizeBytes:   30_000_000,
+		ObjectsCombined:  func(compactionLevel, objects int) {},
+		DedicatedColumns: columns,
+	})
+
+	meta := createTestBlock(t, context.Background(), &blockConfig, r, w, 10, 10, 10, 1, test.MakeDedicatedColumns())
+
+	newMeta, err := c.Compact(context.Background(), log.NewNopLogger(), r, w, []*backend.BlockMeta{meta})
+	require.NoError(t, err)
+	require.Len(t, newMeta, 1)
+	require.Equal(t, columns, newMeta[0].DedicatedColumns)
+
+	// the attributes are moved between the columns without changing the traces
+	before, err := ReadAttributeSizes(context.Background(), r, meta)
+	require.NoError(t, err)
+	after, err := ReadAttributeSizes(context.Background(), r, newMeta[0])
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+
+	iter, err := newBackendBlock(newMeta[0], r).rawIter(context.Background(), newRowPool(10))
+	require.NoError(t, err)
+	defer iter.Close()
+
+	sch := parquet.SchemaOf(new(Trace))
+	_, row, err := iter.Next(context.Background())
+	require.NoError(t, err)
+	tr := new(Trace)
+	require.NoError(t, sch.Reconstruct(tr, row))
+	span := tr.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	require.Equal(t, "dedicated-span-attr-value-2", *span.DedicatedAttributes.String01)
+	for _, a := range span.Attrs {
+		require.NotEqual(t, "dedicated.span.2", a.Key)
+		require.NotEqual(t, "key", a.Key)
+	}
+}
+
 type slowWriter struct {
 	backend.Writer
 	wait chan struct{}
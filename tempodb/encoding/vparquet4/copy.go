@@ -56,6 +56,14 @@ func CopyBlock(ctx context.Context, fromMeta, toMeta *backend.BlockMeta, from ba
 		return err
 	}
 
+	// Name dictionary
+	if fromMeta.NameDictionary {
+		err = cpy(NameDictionaryFileName, &backend.CacheInfo{Role: cache.RoleParquetColumnIdx})
+		if err != nil {
+			return err
+		}
+	}
+
 	// no-compact flag
 	if hasNoCompactFlag, err := from.HasNoCompactFlag(ctx, (uuid.UUID)(toMeta.BlockID), toMeta.TenantID); err != nil {
 		return err
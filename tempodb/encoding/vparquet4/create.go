@@ -11,31 +11,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/grafana/tempo/pkg/dataquality"
 	tempo_io "github.com/grafana/tempo/pkg/io"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	"github.com/parquet-go/parquet-go"
 )
 
-type backendWriter struct {
-	ctx      context.Context
-	w        backend.Writer
-	name     string
-	blockID  uuid.UUID
-	tenantID string
-	tracker  backend.AppendTracker
-}
-
-var _ io.WriteCloser = (*backendWriter)(nil)
-
-func (b *backendWriter) Write(p []byte) (n int, err error) {
-	b.tracker, err = b.w.Append(b.ctx, b.name, b.blockID, b.tenantID, b.tracker, p)
-	return len(p), err
-}
-
-func (b *backendWriter) Close() error {
-	return b.w.CloseAppend(b.ctx, b.tracker)
-}
-
 func CreateBlock(ctx context.Context, cfg *common.BlockConfig, meta *backend.BlockMeta, i common.Iterator, r backend.Reader, to backend.Writer) (*backend.BlockMeta, error) {
 	s := newStreamingBlock(ctx, cfg, meta, r, to, tempo_io.NewBufferedWriter)
 
@@ -67,6 +48,10 @@ func CreateBlock(ctx context.Context, cfg *common.BlockConfig, meta *backend.Blo
 			resMapping  = dedicatedColumnsToColumnMapping(meta.DedicatedColumns, backend.DedicatedColumnScopeResource)
 			spanMapping = dedicatedColumnsToColumnMapping(meta.DedicatedColumns, backend.DedicatedColumnScopeSpan)
 		)
+
+		// Error times are tracked from the traces added below instead of taken from the meta.
+		s.meta.ErrorStartTime, s.meta.ErrorEndTime, s.meta.ErrorTimesTracked = nil, nil, true
+
 		next = func(context.Context) error {
 			id, tr, err := i.Next(ctx)
 			if err != nil {
@@ -128,10 +113,12 @@ type streamingBlock struct {
 	meta  *backend.BlockMeta
 	bw    tempo_io.BufferedWriteFlusher
 	pw    *parquet.GenericWriter[*Trace]
-	w     *backendWriter
+	w     io.WriteCloser
 	r     backend.Reader
 	to    backend.Writer
 	index *index
+	// names is the name dictionary of the block, nil if it isn't written
+	names *nameDictionary
 
 	withNoCompactFlag bool
 
@@ -144,14 +131,25 @@ func newStreamingBlock(ctx context.Context, cfg *common.BlockConfig, meta *backe
 	newMeta.StartTime = meta.StartTime
 	newMeta.EndTime = meta.EndTime
 	newMeta.ReplicationFactor = meta.ReplicationFactor
+	newMeta.RetentionClass = meta.RetentionClass
+	newMeta.ErrorStartTime = meta.ErrorStartTime
+	newMeta.ErrorEndTime = meta.ErrorEndTime
+	newMeta.ErrorTimesTracked = meta.ErrorTimesTracked
+	// the summary is built from the trace IDs added to the block
+	newMeta.TraceIDSummary = backend.NewTraceIDSummary(cfg.TraceIDSummarySizeBytes)
 
 	// TotalObjects is used here an an estimated count for the bloom filter.
 	// The real number of objects is tracked below.
-	bloom := common.NewBloom(cfg.BloomFP, uint(cfg.BloomShardSizeBytes), uint(meta.TotalObjects))
+	bloom := common.NewBloomForConfig(cfg, uint(meta.TotalObjects))
 
-	w := &backendWriter{ctx, to, DataFileName, (uuid.UUID)(meta.BlockID), meta.TenantID, nil}
+	w := to.ResumableStreamWriter(ctx, DataFileName, (uuid.UUID)(meta.BlockID), meta.TenantID)
 	bw := createBufferedWriter(w)
-	pw := parquet.NewGenericWriter[*Trace](bw)
+	pw := parquet.NewGenericWriter[*Trace](bw, writerOptions(cfg)...)
+
+	var names *nameDictionary
+	if cfg.NameDictionaryMaxValues > 0 {
+		names = newNameDictionary(cfg.NameDictionaryMaxValues)
+	}
 
 	return &streamingBlock{
 		ctx:   ctx,
@@ -163,6 +161,7 @@ func newStreamingBlock(ctx context.Context, cfg *common.BlockConfig, meta *backe
 		r:     r,
 		to:    to,
 		index: &index{},
+		names: names,
 
 		withNoCompactFlag: cfg.CreateWithNoCompactFlag,
 	}
@@ -177,7 +176,13 @@ func (b *streamingBlock) Add(tr *Trace, start, end uint32) error {
 
 	b.index.Add(id)
 	b.bloom.Add(id)
+	if b.names != nil {
+		b.names.AddTrace(tr)
+	}
 	b.meta.ObjectAdded(start, end)
+	b.meta.TraceIDAdded(id)
+	b.meta.TraceIDRangeAdded(id)
+	addErrorTimes(b.meta, tr)
 	b.currentBufferedTraces++
 	b.currentBufferedBytes += estimateMarshalledSizeFromTrace(tr)
 
@@ -192,13 +197,31 @@ func (b *streamingBlock) AddRaw(id []byte, row parquet.Row, start, end uint32) e
 
 	b.index.Add(id)
 	b.bloom.Add(id)
+	if b.names != nil {
+		b.names.AddRow(row)
+	}
 	b.meta.ObjectAdded(start, end)
+	b.meta.TraceIDAdded(id)
+	b.meta.TraceIDRangeAdded(id)
 	b.currentBufferedTraces++
 	b.currentBufferedBytes += estimateMarshalledSizeFromParquetRow(row)
 
 	return nil
 }
 
+// addErrorTimes extends the error time range of the block by the spans of the trace with an error status.
+func addErrorTimes(meta *backend.BlockMeta, tr *Trace) {
+	for _, rs := range tr.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				if s.StatusCode == int(v1.Status_STATUS_CODE_ERROR) {
+					meta.ErrorSpanAdded(s.StartTimeUnixNano, s.StartTimeUnixNano+s.DurationNano)
+				}
+			}
+		}
+	}
+}
+
 func (b *streamingBlock) EstimatedBufferedBytes() int {
 	return b.currentBufferedBytes
 }
@@ -210,6 +233,9 @@ func (b *streamingBlock) CurrentBufferedObjects() int {
 func (b *streamingBlock) Flush() (int, error) {
 	// Flush row group
 	b.index.Flush()
+	if b.names != nil {
+		b.names.Flush()
+	}
 	err := b.pw.Flush()
 	if err != nil {
 		return 0, err
@@ -228,6 +254,9 @@ func (b *streamingBlock) Flush() (int, error) {
 func (b *streamingBlock) Complete() (int, error) {
 	// Flush final row group
 	b.index.Flush()
+	if b.names != nil {
+		b.names.Flush()
+	}
 	b.meta.TotalRecords++
 	err := b.pw.Flush()
 	if err != nil {
@@ -279,6 +308,11 @@ func (b *streamingBlock) Complete() (int, error) {
 		}
 	}
 
+	err = writeNameDictionary(b.ctx, b.to, b.meta, b.names)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected error writing name dictionary: %w", err)
+	}
+
 	return n, writeBlockMeta(b.ctx, b.to, b.meta, b.bloom, b.index)
 }
 
@@ -1,13 +1,18 @@
 package vparquet4
 
 import (
+	"bytes"
 	"context"
 	"io"
+	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/grafana/tempo/pkg/model"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/backend/local"
@@ -48,6 +53,164 @@ func TestCreateBlockHonorsTraceStartEndTimesFromWalMeta(t *testing.T) {
 	require.Equal(t, 305, int(outMeta.EndTime.Unix()))
 }
 
+func TestCreateBlockTraceIDSummary(t *testing.T) {
+	ctx := context.Background()
+
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+
+	cfg := &common.BlockConfig{
+		BloomFP:                 0.01,
+		BloomShardSizeBytes:     100 * 1024,
+		TraceIDSummarySizeBytes: 1024,
+	}
+
+	iter := newTestIterator()
+	ids := make([][]byte, 10)
+	for i := range ids {
+		ids[i] = test.ValidTraceID(nil)
+		iter.AddWithID(ids[i], test.MakeTrace(1, ids[i]))
+	}
+
+	meta := backend.NewBlockMeta("fake", uuid.New(), VersionString, backend.EncNone, "")
+	meta.TotalObjects = int64(len(ids))
+	// the summary of the input block is ignored, it's built from the traces
+	meta.TraceIDSummary = []byte{0xff}
+
+	outMeta, err := CreateBlock(ctx, cfg, meta, iter, r, w)
+	require.NoError(t, err)
+	require.Len(t, outMeta.TraceIDSummary, 1024)
+	for _, id := range ids {
+		require.True(t, outMeta.MayContainTraceID(id))
+	}
+	require.False(t, outMeta.MayContainTraceID(test.ValidTraceID(nil)))
+
+	// the trace ID range is recorded too
+	require.Equal(t, slices.MinFunc(ids, bytes.Compare), outMeta.MinID)
+	require.Equal(t, slices.MaxFunc(ids, bytes.Compare), outMeta.MaxID)
+
+	// no summary by default
+	cfg.TraceIDSummarySizeBytes = 0
+	iter = newTestIterator()
+	iter.AddWithID(ids[0], test.MakeTrace(1, ids[0]))
+
+	outMeta, err = CreateBlock(ctx, cfg, meta, iter, r, w)
+	require.NoError(t, err)
+	require.Nil(t, outMeta.TraceIDSummary)
+}
+
+func TestCreateBlockTracksErrorTimes(t *testing.T) {
+	ctx := context.Background()
+
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+
+	cfg := &common.BlockConfig{
+		BloomFP:             0.01,
+		BloomShardSizeBytes: 100 * 1024,
+	}
+
+	errorStart := time.Unix(1000, 0)
+	errorEnd := time.Unix(2005, 0)
+
+	iter := newTestIterator()
+	iter.Add(makeErrorTrace(nil, time.Time{}, time.Time{}), 0, 0)
+	iter.Add(makeErrorTrace(nil, errorStart, errorStart.Add(time.Second)), 0, 0)
+	iter.Add(makeErrorTrace(nil, time.Unix(2000, 0), errorEnd), 0, 0)
+
+	// meta of the input block is ignored, the error times are taken from the traces
+	meta := backend.NewBlockMeta("fake", uuid.New(), VersionString, backend.EncNone, "")
+	meta.TotalObjects = 3
+	meta.ErrorStartTime = &time.Time{}
+
+	outMeta, err := CreateBlock(ctx, cfg, meta, iter, r, w)
+	require.NoError(t, err)
+	require.True(t, outMeta.ErrorTimesTracked)
+	require.Equal(t, errorStart, *outMeta.ErrorStartTime)
+	require.Equal(t, errorEnd, *outMeta.ErrorEndTime)
+
+	iter = newTestIterator()
+	iter.Add(makeErrorTrace(nil, time.Time{}, time.Time{}), 0, 0)
+
+	meta = backend.NewBlockMeta("fake", uuid.New(), VersionString, backend.EncNone, "")
+	meta.TotalObjects = 1
+
+	outMeta, err = CreateBlock(ctx, cfg, meta, iter, r, w)
+	require.NoError(t, err)
+	require.True(t, outMeta.ErrorTimesTracked)
+	require.Nil(t, outMeta.ErrorStartTime)
+	require.Nil(t, outMeta.ErrorEndTime)
+}
+
+func TestCreateBlockTracksErrorTimesFromWal(t *testing.T) {
+	ctx := context.Background()
+
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+
+	errorStart := time.Unix(1000, 0)
+
+	wal, err := createWALBlock(backend.NewBlockMeta("fake", uuid.New(), VersionString, backend.EncNone, ""), t.TempDir(), model.CurrentEncoding, 0)
+	require.NoError(t, err)
+	for _, start := range []time.Time{{}, errorStart, {}} {
+		id := test.ValidTraceID(nil)
+		require.NoError(t, wal.AppendTrace(id, makeErrorTrace(id, start, start.Add(time.Second)), 0, 0, false))
+	}
+	require.NoError(t, wal.Flush())
+
+	// error times are kept when the wal is replayed
+	replayed, _, err := openWALBlock(filepath.Base(wal.walPath()), filepath.Dir(wal.walPath()), 0, 0)
+	require.NoError(t, err)
+	require.True(t, replayed.BlockMeta().ErrorTimesTracked)
+	require.Equal(t, errorStart.UnixNano(), replayed.BlockMeta().ErrorStartTime.UnixNano())
+	require.Equal(t, errorStart.Add(time.Second).UnixNano(), replayed.BlockMeta().ErrorEndTime.UnixNano())
+
+	iter, err := replayed.Iterator()
+	require.NoError(t, err)
+	defer iter.Close()
+
+	outMeta, err := CreateBlock(ctx, &common.BlockConfig{BloomFP: 0.01, BloomShardSizeBytes: 100 * 1024}, replayed.BlockMeta(), iter, r, w)
+	require.NoError(t, err)
+	require.True(t, outMeta.ErrorTimesTracked)
+	require.Equal(t, errorStart.UnixNano(), outMeta.ErrorStartTime.UnixNano())
+	require.Equal(t, errorStart.Add(time.Second).UnixNano(), outMeta.ErrorEndTime.UnixNano())
+}
+
+// makeErrorTrace makes a trace with a single span with an error status from errorStart to errorEnd. If errorStart
+// is zero all spans have an ok status.
+func makeErrorTrace(id []byte, errorStart, errorEnd time.Time) *tempopb.Trace {
+	tr := test.MakeTrace(3, id)
+	for _, rs := range tr.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				s.Status = &v1.Status{Code: v1.Status_STATUS_CODE_OK}
+			}
+		}
+	}
+	if !errorStart.IsZero() {
+		s := tr.ResourceSpans[0].ScopeSpans[0].Spans[0]
+		s.Status = &v1.Status{Code: v1.Status_STATUS_CODE_ERROR}
+		s.StartTimeUnixNano = uint64(errorStart.UnixNano())
+		s.EndTimeUnixNano = uint64(errorEnd.UnixNano())
+	}
+	return tr
+}
+
 // func TestEstimateTraceSize(t *testing.T) {
 // 	f := "<put data.parquet file here>"
 // 	file, err := os.OpenFile(f, os.O_RDONLY, 0644)
@@ -91,6 +254,7 @@ func TestCreateBlockHonorsTraceStartEndTimesFromWalMeta(t *testing.T) {
 // }
 
 type testIterator struct {
+	ids    []common.ID
 	traces []*tempopb.Trace
 }
 
@@ -101,6 +265,11 @@ func newTestIterator() *testIterator {
 }
 
 func (i *testIterator) Add(tr *tempopb.Trace, _, _ uint32) {
+	i.AddWithID(nil, tr)
+}
+
+func (i *testIterator) AddWithID(id common.ID, tr *tempopb.Trace) {
+	i.ids = append(i.ids, id)
 	i.traces = append(i.traces, tr)
 }
 
@@ -108,9 +277,9 @@ func (i *testIterator) Next(context.Context) (common.ID, *tempopb.Trace, error)
 	if len(i.traces) == 0 {
 		return nil, nil, io.EOF
 	}
-	tr := i.traces[0]
-	i.traces = i.traces[1:]
-	return nil, tr, nil
+	id, tr := i.ids[0], i.traces[0]
+	i.ids, i.traces = i.ids[1:], i.traces[1:]
+	return id, tr, nil
 }
 
 func (i *testIterator) Close() {
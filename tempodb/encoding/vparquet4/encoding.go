@@ -25,6 +25,14 @@ func (v Encoding) OpenBlock(meta *backend.BlockMeta, r backend.Reader) (common.B
 	return newBackendBlock(meta, r), nil
 }
 
+// OpenNewerBlock opens a block written by a newer encoding for reading. Only the columns shared with this encoding
+// are read, the others are ignored.
+func (v Encoding) OpenNewerBlock(meta *backend.BlockMeta, r backend.Reader) (common.BackendBlock, error) {
+	b := newBackendBlock(meta, r)
+	b.fallback = true
+	return b, nil
+}
+
 func (v Encoding) CopyBlock(ctx context.Context, meta *backend.BlockMeta, from backend.Reader, to backend.Writer) error {
 	return CopyBlock(ctx, meta, meta, from, to)
 }
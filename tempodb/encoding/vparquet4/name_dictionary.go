@@ -0,0 +1,250 @@
+package vparquet4
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// NameDictionaryFileName is the name dictionary of a block, written if the block config enables it.
+const NameDictionaryFileName = "names.json"
+
+var (
+	serviceNameColumnIndex = columnIndex(columnPathResourceServiceName)
+	spanNameColumnIndex    = columnIndex(columnPathSpanName)
+)
+
+func columnIndex(path string) int {
+	leaf, found := parquetSchema.Lookup(strings.Split(path, ".")...)
+	if !found {
+		panic("column not found in schema: " + path)
+	}
+	return leaf.ColumnIndex
+}
+
+// nameDictionary maps the service and span names of a block to the row groups they're in. It is a tiny object next
+// to the block that resolves the row groups of searches for a service or span name without opening the block. The
+// names of a kind are dropped once there are more distinct names than the max values and are looked up in the block
+// then.
+type nameDictionary struct {
+	maxValues int
+	rowGroup  int
+	dirty     bool
+
+	ServiceNames map[string][]int `json:"serviceNames"`
+	SpanNames    map[string][]int `json:"spanNames"`
+}
+
+func newNameDictionary(maxValues int) *nameDictionary {
+	return &nameDictionary{
+		maxValues:    maxValues,
+		ServiceNames: map[string][]int{},
+		SpanNames:    map[string][]int{},
+	}
+}
+
+// AddTrace adds the names of the trace to the current row group.
+func (d *nameDictionary) AddTrace(tr *Trace) {
+	d.dirty = true
+	for _, rs := range tr.ResourceSpans {
+		d.ServiceNames = d.add(d.ServiceNames, rs.Resource.ServiceName)
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				d.SpanNames = d.add(d.SpanNames, s.Name)
+			}
+		}
+	}
+}
+
+// AddRow adds the names of the trace of the row to the current row group.
+func (d *nameDictionary) AddRow(row parquet.Row) {
+	d.dirty = true
+	for _, v := range row {
+		if v.IsNull() {
+			continue
+		}
+		switch v.Column() {
+		case serviceNameColumnIndex:
+			d.ServiceNames = d.add(d.ServiceNames, v.String())
+		case spanNameColumnIndex:
+			d.SpanNames = d.add(d.SpanNames, v.String())
+		}
+	}
+}
+
+func (d *nameDictionary) add(names map[string][]int, name string) map[string][]int {
+	if names == nil {
+		return nil
+	}
+
+	rgs := names[name]
+	if len(rgs) > 0 && rgs[len(rgs)-1] == d.rowGroup {
+		return names
+	}
+	if len(rgs) == 0 && len(names) >= d.maxValues {
+		// too many distinct names, they're looked up in the block
+		return nil
+	}
+	names[name] = append(rgs, d.rowGroup)
+	return names
+}
+
+// Flush starts the next row group if names were added to the current one.
+func (d *nameDictionary) Flush() {
+	if d.dirty {
+		d.rowGroup++
+		d.dirty = false
+	}
+}
+
+// Useful returns true if the service or the span names are complete.
+func (d *nameDictionary) Useful() bool {
+	return d.ServiceNames != nil || d.SpanNames != nil
+}
+
+func (d *nameDictionary) Marshal() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+func unmarshalNameDictionary(b []byte) (*nameDictionary, error) {
+	d := &nameDictionary{}
+	return d, json.Unmarshal(b, d)
+}
+
+// writeNameDictionary writes the name dictionary of the block and records it in the meta.
+func writeNameDictionary(ctx context.Context, w backend.Writer, meta *backend.BlockMeta, d *nameDictionary) error {
+	if d == nil || !d.Useful() {
+		return nil
+	}
+
+	b, err := d.Marshal()
+	if err != nil {
+		return err
+	}
+	err = w.Write(ctx, NameDictionaryFileName, (uuid.UUID)(meta.BlockID), meta.TenantID, b, &backend.CacheInfo{
+		Meta: meta,
+		Role: cache.RoleParquetColumnIdx,
+	})
+	if err != nil {
+		return err
+	}
+
+	meta.NameDictionary = true
+	return nil
+}
+
+// nameCondition returns the service or span name searched for by a request with a single condition
+// `{ resource.service.name = "x" }` or `{ name = "y" }`, the names of the dictionary searched and true. Other
+// conditions of the request only fetch values.
+func nameCondition(req traceql.FetchSpansRequest) (string, func(*nameDictionary) map[string][]int, bool) {
+	var filter *traceql.Condition
+	for i := range req.Conditions {
+		if req.Conditions[i].Op == traceql.OpNone {
+			continue
+		}
+		if filter != nil {
+			return "", nil, false
+		}
+		filter = &req.Conditions[i]
+	}
+	if filter == nil || filter.Op != traceql.OpEqual || len(filter.Operands) != 1 || filter.Operands[0].Type != traceql.TypeString {
+		return "", nil, false
+	}
+	if !req.AllConditions && len(req.Conditions) > 1 {
+		return "", nil, false
+	}
+
+	name := filter.Operands[0].EncodeToString(false)
+	switch {
+	case filter.Attribute.Intrinsic == traceql.IntrinsicName:
+		return name, func(d *nameDictionary) map[string][]int { return d.SpanNames }, true
+	case filter.Attribute.Intrinsic == traceql.IntrinsicNone && filter.Attribute.Scope == traceql.AttributeScopeResource && filter.Attribute.Name == LabelServiceName:
+		return name, func(d *nameDictionary) map[string][]int { return d.ServiceNames }, true
+	}
+	return "", nil, false
+}
+
+// nameDictionaryRowGroups returns the row groups of the block with the service or span name searched for by the
+// request, resolved from the name dictionary of the block, and the bytes read. It returns false if the request
+// doesn't only search for a name or the block has no dictionary of the names.
+func (b *backendBlock) nameDictionaryRowGroups(ctx context.Context, req traceql.FetchSpansRequest) ([]int, uint64, bool, error) {
+	if !b.meta.NameDictionary {
+		return nil, 0, false, nil
+	}
+	name, names, ok := nameCondition(req)
+	if !ok {
+		return nil, 0, false, nil
+	}
+
+	buf, err := b.r.Read(ctx, NameDictionaryFileName, (uuid.UUID)(b.meta.BlockID), b.meta.TenantID, &backend.CacheInfo{
+		Meta: b.meta,
+		Role: cache.RoleParquetColumnIdx,
+	})
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		// the block is searched without its dictionary
+		return nil, 0, false, nil
+	}
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("error reading name dictionary: %w", err)
+	}
+
+	d, err := unmarshalNameDictionary(buf)
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("error unmarshalling name dictionary: %w", err)
+	}
+	m := names(d)
+	if m == nil {
+		return nil, uint64(len(buf)), false, nil
+	}
+	return m[name], uint64(len(buf)), true, nil
+}
+
+// rowGroupsInPages returns the row groups that are in the pages of the search options.
+func rowGroupsInPages(rowGroups []int, opts common.SearchOptions) []int {
+	if opts.TotalPages <= 0 {
+		return rowGroups
+	}
+
+	var in []int
+	for _, rg := range rowGroups {
+		if rg >= opts.StartPage && rg < opts.StartPage+opts.TotalPages {
+			in = append(in, rg)
+		}
+	}
+	return in
+}
+
+// firstRowGroup returns the index of the first row group returned by rowGroupsFromFile.
+func firstRowGroup(opts common.SearchOptions) int {
+	if opts.TotalPages > 0 {
+		return opts.StartPage
+	}
+	return 0
+}
+
+// filterRowGroups returns the row groups of rgs, the row groups of the file starting at the first one, that are in
+// rowGroups.
+func filterRowGroups(rgs []parquet.RowGroup, first int, rowGroups []int) []parquet.RowGroup {
+	keep := make(map[int]struct{}, len(rowGroups))
+	for _, rg := range rowGroups {
+		keep[rg] = struct{}{}
+	}
+
+	filtered := make([]parquet.RowGroup, 0, len(rowGroups))
+	for i, rg := range rgs {
+		if _, ok := keep[first+i]; ok {
+			filtered = append(filtered, rg)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,216 @@
+package vparquet4
+
+import (
+	"context"
+	crand "crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	tempo_io "github.com/grafana/tempo/pkg/io"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestNameDictionary(t *testing.T) {
+	traces := nameDictionaryTestTraces(t, 4, 10)
+
+	// the dictionary built from rows is the same as from traces
+	fromTraces, fromRows := newNameDictionary(100), newNameDictionary(100)
+	for i, tr := range traces {
+		fromTraces.AddTrace(tr)
+		fromRows.AddRow(parquetSchema.Deconstruct(nil, tr))
+		if i%10 == 9 {
+			fromTraces.Flush()
+			fromRows.Flush()
+		}
+	}
+	require.Equal(t, fromTraces, fromRows)
+	require.Equal(t, map[string][]int{"svc-0": {0}, "svc-1": {1}, "svc-2": {2}, "svc-3": {3}}, fromTraces.ServiceNames)
+	require.Equal(t, []int{0, 1, 2, 3}, fromTraces.SpanNames["shared"])
+
+	// names are dropped once there are too many distinct ones
+	small := newNameDictionary(5)
+	for _, tr := range traces {
+		small.AddTrace(tr)
+	}
+	require.NotNil(t, small.ServiceNames)
+	require.Nil(t, small.SpanNames)
+	require.True(t, small.Useful())
+}
+
+func TestBackendBlockFetchNameDictionary(t *testing.T) {
+	ctx := context.Background()
+	opts := common.DefaultSearchOptions()
+	traces := nameDictionaryTestTraces(t, 4, 10)
+
+	withDictionary := makeNameDictionaryTestBlock(t, traces, 10, 100)
+	require.True(t, withDictionary.meta.NameDictionary)
+	withoutDictionary := makeNameDictionaryTestBlock(t, traces, 10, 0)
+	require.False(t, withoutDictionary.meta.NameDictionary)
+
+	fetch := func(block *backendBlock, query string, opts common.SearchOptions) (int, uint64) {
+		_, _, _, _, req, err := traceql.Compile(query)
+		require.NoError(t, err)
+
+		resp, err := block.Fetch(ctx, *req, opts)
+		require.NoError(t, err)
+		defer resp.Results.Close()
+
+		spans := 0
+		for {
+			ss, err := resp.Results.Next(ctx)
+			require.NoError(t, err)
+			if ss == nil {
+				break
+			}
+			spans += len(ss.Spans)
+		}
+		return spans, resp.Bytes()
+	}
+
+	for _, tc := range []struct {
+		query     string
+		fewerRead bool
+	}{
+		{query: "{ resource.service.name = `svc-2` }", fewerRead: true},
+		{query: "{ name = `span-1-0` }", fewerRead: true},
+		{query: "{ resource.service.name = `missing` }", fewerRead: true},
+		{query: "{ name = `shared` }"},
+		{query: "{ resource.service.name = `svc-2` && name = `shared` }"},
+		{query: "{ duration > 0 }"},
+	} {
+		spans, bytes := fetch(withDictionary, tc.query, opts)
+		expectedSpans, expectedBytes := fetch(withoutDictionary, tc.query, opts)
+		require.Equal(t, expectedSpans, spans, tc.query)
+		if tc.fewerRead {
+			require.Less(t, bytes, expectedBytes, tc.query)
+		}
+	}
+
+	// names of a single row group are read from it only, and names that aren't in the block from the dictionary only
+	spans, bytes := fetch(withDictionary, "{ resource.service.name = `svc-2` }", opts)
+	require.Equal(t, 10*3, spans)
+
+	spans, bytes = fetch(withDictionary, "{ resource.service.name = `missing` }", opts)
+	require.Zero(t, spans)
+	require.Less(t, bytes, uint64(1000))
+
+	// and the row groups are limited to the pages of the search options
+	pages := opts
+	pages.StartPage, pages.TotalPages = 0, 2
+	spans, _ = fetch(withDictionary, "{ resource.service.name = `svc-2` }", pages)
+	require.Zero(t, spans)
+	pages.StartPage = 2
+	spans, _ = fetch(withDictionary, "{ resource.service.name = `svc-2` }", pages)
+	require.Equal(t, 10*3, spans)
+}
+
+func BenchmarkBackendBlockFetchNameDictionary(b *testing.B) {
+	ctx := context.Background()
+	opts := common.DefaultSearchOptions()
+	traces := nameDictionaryTestTraces(b, 20, 100)
+
+	blocks := map[string]*backendBlock{
+		"dictionary":   makeNameDictionaryTestBlock(b, traces, 100, 1000),
+		"noDictionary": makeNameDictionaryTestBlock(b, traces, 100, 0),
+	}
+
+	for _, query := range []string{
+		"{ resource.service.name = `svc-5` }",
+		"{ resource.service.name = `missing` }",
+		"{ name = `span-5-0` }",
+	} {
+		_, _, _, _, req, err := traceql.Compile(query)
+		require.NoError(b, err)
+
+		for _, name := range []string{"dictionary", "noDictionary"} {
+			block := blocks[name]
+			b.Run(query+"/"+name, func(b *testing.B) {
+				b.ReportAllocs()
+				bytesRead := 0
+
+				for i := 0; i < b.N; i++ {
+					resp, err := block.Fetch(ctx, *req, opts)
+					require.NoError(b, err)
+					for {
+						ss, err := resp.Results.Next(ctx)
+						require.NoError(b, err)
+						if ss == nil {
+							break
+						}
+					}
+					resp.Results.Close()
+					bytesRead += int(resp.Bytes())
+				}
+				b.ReportMetric(float64(bytesRead)/float64(b.N)/1000.0/1000.0, "MB_io/op")
+			})
+		}
+	}
+}
+
+// nameDictionaryTestTraces returns traces of a service and span names per group, with a span name shared by all
+// groups.
+func nameDictionaryTestTraces(t testing.TB, groups, tracesPerGroup int) []*Trace {
+	meta := &backend.BlockMeta{}
+	traces := make([]*Trace, 0, groups*tracesPerGroup)
+	for g := 0; g < groups; g++ {
+		for i := 0; i < tracesPerGroup; i++ {
+			id := make([]byte, 16)
+			_, err := crand.Read(id)
+			require.NoError(t, err)
+
+			tr, _ := traceToParquet(meta, id, test.MakeTraceWithSpanCount(1, 3, id), nil)
+			for r := range tr.ResourceSpans {
+				tr.ResourceSpans[r].Resource.ServiceName = fmt.Sprintf("svc-%d", g)
+				for s := range tr.ResourceSpans[r].ScopeSpans {
+					spans := tr.ResourceSpans[r].ScopeSpans[s].Spans
+					for k := range spans {
+						spans[k].Name = fmt.Sprintf("span-%d-%d", g, k)
+					}
+					spans[len(spans)-1].Name = "shared"
+				}
+			}
+			traces = append(traces, tr)
+		}
+	}
+	return traces
+}
+
+// makeNameDictionaryTestBlock writes the traces to a block with a row group per tracesPerRowGroup traces.
+func makeNameDictionaryTestBlock(t testing.TB, traces []*Trace, tracesPerRowGroup, maxValues int) *backendBlock {
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+	ctx := context.Background()
+
+	cfg := &common.BlockConfig{
+		BloomFP:                 0.01,
+		BloomShardSizeBytes:     100 * 1024,
+		NameDictionaryMaxValues: maxValues,
+	}
+	meta := backend.NewBlockMeta(tenantID, uuid.New(), VersionString, backend.EncNone, "")
+	meta.TotalObjects = int64(len(traces))
+
+	s := newStreamingBlock(ctx, cfg, meta, r, w, tempo_io.NewBufferedWriter)
+	for i, tr := range traces {
+		require.NoError(t, s.AddRaw(tr.TraceID, parquetSchema.Deconstruct(nil, tr), 0, 0))
+		if i%tracesPerRowGroup == tracesPerRowGroup-1 && i < len(traces)-1 {
+			_, err := s.Flush()
+			require.NoError(t, err)
+		}
+	}
+	_, err = s.Complete()
+	require.NoError(t, err)
+
+	return newBackendBlock(s.meta, r)
+}
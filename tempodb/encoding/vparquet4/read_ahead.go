@@ -0,0 +1,301 @@
+package vparquet4
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/traceql"
+)
+
+const (
+	// readAheadMaxPolicies is the max number of tenant and query class pairs the read ahead is learned for.
+	readAheadMaxPolicies = 1000
+	// readAheadMinQueries is the number of queries of a class that are observed before its columns are prefetched.
+	readAheadMinQueries = 2
+	// readAheadWindow is the number of queries after which the observations of a class are halved, so the learned
+	// columns follow the changes of the queries.
+	readAheadWindow = 64
+	// readAheadMaxGap is the max number of bytes between two column chunks that are fetched in one request.
+	readAheadMaxGap = 64 * 1024
+	// readAheadMaxGroups is the number of row groups whose prefetched column chunks are kept by a reader.
+	readAheadMaxGroups = 2
+)
+
+// defaultReadAheadController is shared by the blocks so the read ahead is learned across blocks and queries.
+var defaultReadAheadController = newReadAheadController(readAheadMaxPolicies)
+
+// readAheadController learns per tenant and query class which column chunks are read by the queries. The column
+// chunks of a row group that are read by at least half of the queries of a class are prefetched together on the
+// first read of one of them, instead of being read by read buffer sized requests.
+type readAheadController struct {
+	mtx         sync.Mutex
+	policies    map[string]*readAheadPolicy
+	maxPolicies int
+}
+
+type readAheadPolicy struct {
+	queries int
+	columns map[string]int // number of queries that read each column, by path
+}
+
+func newReadAheadController(maxPolicies int) *readAheadController {
+	return &readAheadController{
+		policies:    map[string]*readAheadPolicy{},
+		maxPolicies: maxPolicies,
+	}
+}
+
+// queryStarted records a query of the key and returns the columns that are learned for it.
+func (c *readAheadController) queryStarted(key string) map[string]struct{} {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	p, ok := c.policies[key]
+	if !ok {
+		if len(c.policies) >= c.maxPolicies {
+			// evict any policy, the popular ones are learned again quickly
+			for k := range c.policies {
+				delete(c.policies, k)
+				break
+			}
+		}
+		p = &readAheadPolicy{columns: map[string]int{}}
+		c.policies[key] = p
+	}
+
+	var learned map[string]struct{}
+	if p.queries >= readAheadMinQueries {
+		learned = make(map[string]struct{}, len(p.columns))
+		for path, n := range p.columns {
+			if 2*n >= p.queries {
+				learned[path] = struct{}{}
+			}
+		}
+	}
+
+	p.queries++
+	if p.queries >= readAheadWindow {
+		p.queries /= 2
+		for path, n := range p.columns {
+			if n /= 2; n == 0 {
+				delete(p.columns, path)
+			} else {
+				p.columns[path] = n
+			}
+		}
+	}
+
+	return learned
+}
+
+// columnRead records that a query of the key read the column. It must be called once per column and query.
+func (c *readAheadController) columnRead(key, path string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if p, ok := c.policies[key]; ok {
+		p.columns[path]++
+	}
+}
+
+// fetchQueryClass returns the class of the request for the read ahead. Requests of a class read the same columns: it
+// is made of the attributes, operators and operand types of the conditions, but not of the operands.
+func fetchQueryClass(req traceql.FetchSpansRequest) string {
+	conds := make([]string, 0, len(req.Conditions)+len(req.SecondPassConditions)+2)
+	condition := func(prefix string, c traceql.Condition) string {
+		s := prefix + c.Attribute.String() + " " + c.Op.String()
+		if len(c.Operands) > 0 {
+			s += " " + c.Operands[0].Type.String()
+		}
+		return s
+	}
+	for _, c := range req.Conditions {
+		conds = append(conds, condition("", c))
+	}
+	for _, c := range req.SecondPassConditions {
+		conds = append(conds, condition("second pass ", c))
+	}
+	sort.Strings(conds)
+
+	if req.AllConditions {
+		conds = append(conds, "all")
+	}
+	if req.SecondPassSelectAll {
+		conds = append(conds, "second pass select all")
+	}
+	return strings.Join(conds, ", ")
+}
+
+type columnChunk struct {
+	offset   int64
+	length   int64
+	rowGroup int
+	path     string
+	prefetch bool
+}
+
+type readAheadRange struct {
+	offset int64
+	buf    []byte
+}
+
+// readAheadGroup holds the prefetched column chunks of a row group.
+type readAheadGroup struct {
+	once   sync.Once
+	chunks []columnChunk
+	ranges []readAheadRange
+	err    error
+}
+
+// readAheadReaderAt prefetches the learned column chunks of a row group together and records the columns that are
+// read for the controller. Reads that aren't in a prefetched column chunk are passed to the underlying reader.
+type readAheadReaderAt struct {
+	r          cacheReaderAt
+	controller *readAheadController
+	key        string
+	maxBytes   int // max bytes prefetched per row group
+
+	mtx    sync.Mutex
+	chunks []columnChunk // sorted by offset, nil until the file is opened
+	read   map[string]struct{}
+	groups map[int]*readAheadGroup
+	order  []int // row groups of the groups, in creation order
+}
+
+var _ cacheReaderAt = (*readAheadReaderAt)(nil)
+
+func newReadAheadReaderAt(r cacheReaderAt, controller *readAheadController, key string, maxBytes int) *readAheadReaderAt {
+	return &readAheadReaderAt{
+		r:          r,
+		controller: controller,
+		key:        key,
+		maxBytes:   maxBytes,
+		read:       map[string]struct{}{},
+		groups:     map[int]*readAheadGroup{},
+	}
+}
+
+// setFile starts the read ahead for the opened file. Reads before it are only passed to the underlying reader.
+func (r *readAheadReaderAt) setFile(pf *parquet.File) {
+	learned := r.controller.queryStarted(r.key)
+
+	var chunks []columnChunk
+	for i, rg := range pf.Metadata().RowGroups {
+		for _, col := range rg.Columns {
+			md := col.MetaData
+			offset := md.DataPageOffset
+			if md.DictionaryPageOffset > 0 && md.DictionaryPageOffset < offset {
+				offset = md.DictionaryPageOffset
+			}
+			path := strings.Join(md.PathInSchema, ".")
+			_, prefetch := learned[path]
+			chunks = append(chunks, columnChunk{
+				offset:   offset,
+				length:   md.TotalCompressedSize,
+				rowGroup: i,
+				path:     path,
+				prefetch: prefetch,
+			})
+		}
+	}
+	sort.Slice(chunks, func(i, j int) bool { return chunks[i].offset < chunks[j].offset })
+
+	r.mtx.Lock()
+	r.chunks = chunks
+	r.mtx.Unlock()
+}
+
+func (r *readAheadReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return r.ReadAtWithCache(p, off, cache.RoleNone)
+}
+
+func (r *readAheadReaderAt) ReadAtWithCache(p []byte, off int64, role cache.Role) (int, error) {
+	g := r.group(off)
+	if g == nil {
+		return r.r.ReadAtWithCache(p, off, role)
+	}
+
+	g.once.Do(func() { g.fetch(r.r) })
+	if g.err == nil {
+		for _, rr := range g.ranges {
+			if off >= rr.offset && off+int64(len(p)) <= rr.offset+int64(len(rr.buf)) {
+				return copy(p, rr.buf[off-rr.offset:]), nil
+			}
+		}
+	}
+
+	return r.r.ReadAtWithCache(p, off, role)
+}
+
+// group records the read of the column chunk at the offset and returns the group of its row group if it's prefetched.
+func (r *readAheadReaderAt) group(off int64) *readAheadGroup {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	i := sort.Search(len(r.chunks), func(i int) bool { return r.chunks[i].offset > off }) - 1
+	if i < 0 || off >= r.chunks[i].offset+r.chunks[i].length {
+		return nil
+	}
+	chunk := r.chunks[i]
+
+	if _, ok := r.read[chunk.path]; !ok {
+		r.read[chunk.path] = struct{}{}
+		r.controller.columnRead(r.key, chunk.path)
+	}
+	if !chunk.prefetch {
+		return nil
+	}
+
+	if g, ok := r.groups[chunk.rowGroup]; ok {
+		return g
+	}
+
+	g := &readAheadGroup{}
+	size := 0
+	for _, c := range r.chunks {
+		if c.rowGroup == chunk.rowGroup && c.prefetch && size+int(c.length) <= r.maxBytes {
+			g.chunks = append(g.chunks, c)
+			size += int(c.length)
+		}
+	}
+
+	if len(r.order) >= readAheadMaxGroups {
+		delete(r.groups, r.order[0])
+		r.order = r.order[1:]
+	}
+	r.groups[chunk.rowGroup] = g
+	r.order = append(r.order, chunk.rowGroup)
+	return g
+}
+
+// fetch reads the column chunks of the group, chunks that are close to each other are read in one request.
+func (g *readAheadGroup) fetch(r cacheReaderAt) {
+	var start, end int64
+	flush := func() {
+		if end <= start {
+			return
+		}
+		buf := make([]byte, end-start)
+		if _, err := r.ReadAtWithCache(buf, start, cache.RoleNone); err != nil {
+			g.err = err
+			return
+		}
+		g.ranges = append(g.ranges, readAheadRange{offset: start, buf: buf})
+	}
+
+	for _, c := range g.chunks {
+		if end > start && c.offset-end > readAheadMaxGap {
+			flush()
+			start, end = c.offset, c.offset
+		}
+		if end <= start {
+			start = c.offset
+		}
+		end = max(end, c.offset+c.length)
+	}
+	flush()
+}
@@ -0,0 +1,133 @@
+package vparquet4
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestReadAheadControllerLearnsColumns(t *testing.T) {
+	c := newReadAheadController(10)
+
+	// nothing is learned before the min number of queries
+	for i := 0; i < 4; i++ {
+		learned := c.queryStarted("key")
+		if i < readAheadMinQueries {
+			require.Empty(t, learned)
+		}
+		c.columnRead("key", "always")
+		if i%2 == 0 {
+			c.columnRead("key", "half")
+		}
+		if i == 0 {
+			c.columnRead("key", "once")
+		}
+	}
+
+	require.Equal(t, map[string]struct{}{"always": {}, "half": {}}, c.queryStarted("key"))
+	require.Empty(t, c.queryStarted("other"))
+
+	// the columns that aren't read anymore are forgotten
+	for i := 0; i < 2*readAheadWindow; i++ {
+		c.queryStarted("key")
+		c.columnRead("key", "new")
+	}
+	require.Equal(t, map[string]struct{}{"new": {}}, c.queryStarted("key"))
+}
+
+func TestReadAheadControllerMaxPolicies(t *testing.T) {
+	c := newReadAheadController(2)
+	c.queryStarted("a")
+	c.queryStarted("b")
+	c.queryStarted("c")
+	require.Len(t, c.policies, 2)
+	require.Contains(t, c.policies, "c")
+}
+
+func TestFetchQueryClass(t *testing.T) {
+	class := func(q string) string {
+		req, err := traceql.ExtractFetchSpansRequest(q)
+		require.NoError(t, err)
+		return fetchQueryClass(req)
+	}
+
+	require.Equal(t, class(`{span.foo = "bar" && resource.service.name = "svc"}`), class(`{resource.service.name = "other" && span.foo = "baz"}`))
+	require.NotEqual(t, class(`{span.foo = "bar"}`), class(`{span.foo = 1}`))
+	require.NotEqual(t, class(`{span.foo = "bar"}`), class(`{span.foo != "bar"}`))
+	require.NotEqual(t, class(`{span.foo = "bar"}`), class(`{span.bar = "bar"}`))
+	require.NotEqual(t, class(`{span.foo = "bar" && span.bar = "bar"}`), class(`{span.foo = "bar" || span.bar = "bar"}`))
+}
+
+type countingReader struct {
+	backend.Reader
+	ranges atomic.Int64
+}
+
+func (r *countingReader) ReadRange(ctx context.Context, name string, blockID uuid.UUID, tenantID string, offset uint64, buffer []byte, cacheInfo *backend.CacheInfo) error {
+	r.ranges.Inc()
+	return r.Reader.ReadRange(ctx, name, blockID, tenantID, offset, buffer, cacheInfo)
+}
+
+func TestBackendBlockFetchAdaptiveReadAhead(t *testing.T) {
+	defer func(c *readAheadController) { defaultReadAheadController = c }(defaultReadAheadController)
+	defaultReadAheadController = newReadAheadController(readAheadMaxPolicies)
+
+	traces := make([]*Trace, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		id := test.ValidTraceID(nil)
+		tr, _ := traceToParquet(&backend.BlockMeta{}, id, test.MakeTrace(1, id), nil)
+		traces = append(traces, tr)
+	}
+	b := makeBackendBlockWithTraces(t, traces)
+	r := &countingReader{Reader: b.r}
+	b.r = r
+
+	req, err := traceql.ExtractFetchSpansRequest(`{span.foo = "bar" || resource.service.name != "svc"}`)
+	require.NoError(t, err)
+	req.SecondPass = func(s *traceql.Spanset) ([]*traceql.Spanset, error) { return []*traceql.Spanset{s}, nil }
+	req.SecondPassConditions = traceql.SearchMetaConditions()
+
+	ctx := context.Background()
+	fetch := func(opts common.SearchOptions) ([]string, int64) {
+		before := r.ranges.Load()
+
+		resp, err := b.Fetch(ctx, req, opts)
+		require.NoError(t, err)
+		defer resp.Results.Close()
+
+		var ids []string
+		for {
+			ss, err := resp.Results.Next(ctx)
+			require.NoError(t, err)
+			if ss == nil {
+				break
+			}
+			ids = append(ids, string(ss.TraceID))
+		}
+		return ids, r.ranges.Load() - before
+	}
+
+	opts := common.DefaultSearchOptions()
+	expected, requests := fetch(opts)
+	require.NotEmpty(t, expected)
+
+	opts.AdaptiveReadAhead = true
+	for i := 0; i < readAheadMinQueries; i++ {
+		ids, learningRequests := fetch(opts)
+		require.Equal(t, expected, ids)
+		require.Equal(t, requests, learningRequests)
+	}
+
+	// the learned column chunks of each row group are fetched together
+	ids, readAheadRequests := fetch(opts)
+	require.Equal(t, expected, ids)
+	require.Less(t, readAheadRequests, requests)
+}
@@ -58,6 +58,39 @@ func TestParquetGoSetsMetadataSections(t *testing.T) {
 	require.True(t, dr.offsetIndex)
 }
 
+func TestBackendReaderAtIOStats(t *testing.T) {
+	rawR, _, _, err := local.New(&local.Config{
+		Path: "./test-data",
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	ctx, stats := backend.ContextWithIOStats(context.Background())
+
+	blocks, _, err := r.Blocks(ctx, tenantID)
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+
+	meta, err := r.BlockMeta(ctx, blocks[0], tenantID)
+	require.NoError(t, err)
+
+	// the footer and indexes are recorded as index bytes
+	br := NewBackendReaderAt(ctx, r, DataFileName, meta)
+	pf, err := parquet.OpenFile(newCachedReaderAt(br, 1_000_000, int64(meta.Size_), meta.FooterSize), int64(meta.Size_))
+	require.NoError(t, err)
+	require.Zero(t, stats.ColumnBytes())
+	require.Positive(t, stats.IndexBytes())
+
+	// and the pages as column bytes
+	rows := parquet.NewReader(pf)
+	_, err = rows.ReadRows(make([]parquet.Row, 1))
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+	require.Positive(t, stats.ColumnBytes())
+	require.Zero(t, stats.BloomBytes())
+	require.Equal(t, br.BytesRead(), stats.TotalBytes())
+}
+
 func TestCachingReaderShortcircuitsFooterHeader(t *testing.T) {
 	rr := &recordingReaderAt{}
 	pr := newCachedReaderAt(rr, 1000, 1000, 100)
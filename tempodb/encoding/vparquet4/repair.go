@@ -0,0 +1,190 @@
+package vparquet4
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+	"github.com/willf/bloom"
+
+	"github.com/grafana/tempo/pkg/cache"
+	pq "github.com/grafana/tempo/pkg/parquetquery"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// RepairBlock rebuilds the bloom shards and the index of the block that are missing or can't be parsed from the
+// trace IDs of its data file. Rebuilt shards have the size and hash functions of the readable shards of the block,
+// or the ones of cfg if none are readable.
+func (v Encoding) RepairBlock(ctx context.Context, cfg *common.BlockConfig, meta *backend.BlockMeta, r backend.Reader, w backend.Writer) ([]string, error) {
+	b := newBackendBlock(meta, r)
+
+	err := b.validateDataFile(ctx)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return nil, fmt.Errorf("%w: %w", err, common.ErrCorruptBlockData)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	shardCount := common.ValidateShardCount(int(meta.BloomShardCount))
+	var (
+		missingShards []int
+		template      *bloom.BloomFilter
+	)
+	for i := 0; i < shardCount; i++ {
+		filter, err := b.readBloomForRepair(ctx, i)
+		if err != nil {
+			return nil, err
+		}
+		if filter == nil {
+			missingShards = append(missingShards, i)
+		} else if template == nil {
+			template = filter
+		}
+	}
+
+	missingIndex, err := b.indexMissing(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missingShards) == 0 && !missingIndex {
+		return nil, nil
+	}
+
+	m, k := uint(cfg.BloomShardSizeBytes)*8, uint(0)
+	if template != nil {
+		m, k = template.Cap(), template.K()
+	} else {
+		_, k = bloom.EstimateParameters(uint(meta.TotalObjects), cfg.BloomFP)
+	}
+	blooms := make(map[int]*bloom.BloomFilter, len(missingShards))
+	for _, i := range missingShards {
+		blooms[i] = bloom.New(m, k)
+	}
+
+	idx := &index{}
+	err = b.iterateTraceIDs(ctx, func(id common.ID) {
+		idx.Add(id)
+		if filter, ok := blooms[common.ShardKeyForTraceID(id, shardCount)]; ok {
+			filter.Add(id)
+		}
+	}, idx.Flush)
+	if err != nil {
+		return nil, err
+	}
+
+	var rebuilt []string
+	for _, i := range missingShards {
+		buf := &bytes.Buffer{}
+		if _, err := blooms[i].WriteTo(buf); err != nil {
+			return nil, err
+		}
+		err = w.Write(ctx, common.BloomName(i), (uuid.UUID)(meta.BlockID), meta.TenantID, buf.Bytes(), &backend.CacheInfo{
+			Meta: meta,
+			Role: cache.RoleBloom,
+		})
+		if err != nil {
+			return rebuilt, fmt.Errorf("error writing %s: %w", common.BloomName(i), err)
+		}
+		rebuilt = append(rebuilt, common.BloomName(i))
+	}
+
+	if missingIndex {
+		i, err := idx.Marshal()
+		if err != nil {
+			return rebuilt, err
+		}
+		err = w.Write(ctx, common.NameIndex, (uuid.UUID)(meta.BlockID), meta.TenantID, i, &backend.CacheInfo{
+			Meta: meta,
+			Role: cache.RoleTraceIDIdx,
+		})
+		if err != nil {
+			return rebuilt, fmt.Errorf("error writing %s: %w", common.NameIndex, err)
+		}
+		rebuilt = append(rebuilt, common.NameIndex)
+	}
+
+	return rebuilt, nil
+}
+
+// readBloomForRepair reads the bloom shard from the backend, bypassing the cache. It returns nil if the shard is
+// missing or can't be parsed.
+func (b *backendBlock) readBloomForRepair(ctx context.Context, shard int) (*bloom.BloomFilter, error) {
+	bloomBytes, err := b.r.Read(ctx, common.BloomName(shard), (uuid.UUID)(b.meta.BlockID), b.meta.TenantID, nil)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving %s: %w", common.BloomName(shard), err)
+	}
+
+	filter := &bloom.BloomFilter{}
+	if _, err := filter.ReadFrom(bytes.NewReader(bloomBytes)); err != nil || filter.Cap() == 0 {
+		return nil, nil
+	}
+	return filter, nil
+}
+
+// indexMissing returns true if the index of the block is missing or can't be parsed.
+func (b *backendBlock) indexMissing(ctx context.Context) (bool, error) {
+	indexBytes, err := b.r.Read(ctx, common.NameIndex, (uuid.UUID)(b.meta.BlockID), b.meta.TenantID, nil)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error retrieving %s: %w", common.NameIndex, err)
+	}
+
+	_, err = unmarshalIndex(indexBytes)
+	return err != nil, nil
+}
+
+// iterateTraceIDs calls fn with the trace IDs of the data file in order, and rowGroupDone after the last trace ID of
+// each row group. Errors opening the file wrap common.ErrCorruptBlockData.
+func (b *backendBlock) iterateTraceIDs(ctx context.Context, fn func(common.ID), rowGroupDone func()) error {
+	pf, _, err := b.openForSearch(ctx, common.DefaultSearchOptions())
+	if err != nil {
+		return fmt.Errorf("error opening parquet file: %w: %w", err, common.ErrCorruptBlockData)
+	}
+
+	colIndex, _, maxDef := pq.GetColumnIndexByPath(pf, TraceIDColumnName)
+	if colIndex == -1 {
+		return fmt.Errorf("unable to get index for column: %s: %w", TraceIDColumnName, common.ErrCorruptBlockData)
+	}
+
+	for _, rg := range pf.RowGroups() {
+		err := iterateRowGroupTraceIDs(ctx, rg, colIndex, maxDef, fn)
+		if err != nil {
+			return err
+		}
+		rowGroupDone()
+	}
+	return nil
+}
+
+func iterateRowGroupTraceIDs(ctx context.Context, rg parquet.RowGroup, colIndex, maxDef int, fn func(common.ID)) error {
+	iter := pq.NewSyncIterator(ctx, []parquet.RowGroup{rg}, colIndex,
+		pq.SyncIteratorOptSelectAs(TraceIDColumnName),
+		pq.SyncIteratorOptMaxDefinitionLevel(maxDef),
+	)
+	defer iter.Close()
+
+	for {
+		res, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		if res == nil {
+			return nil
+		}
+		for _, e := range res.Entries {
+			// copy, the values are only valid until the next call
+			fn(bytes.Clone(e.Value.ByteArray()))
+		}
+	}
+}
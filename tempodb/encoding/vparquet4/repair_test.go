@@ -0,0 +1,59 @@
+package vparquet4
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestRepairBlock(t *testing.T) {
+	ctx := context.Background()
+
+	rawR, rawW, _, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+	r, w := backend.NewReader(rawR), backend.NewWriter(rawW)
+
+	cfg := &common.BlockConfig{
+		BloomFP:             0.01,
+		BloomShardSizeBytes: 10,
+		Version:             VersionString,
+		RowGroupSizeBytes:   10_000,
+	}
+	meta := createTestBlock(t, ctx, cfg, r, w, 50, 2, 2, 1, nil)
+	require.Greater(t, meta.BloomShardCount, uint32(1))
+
+	// nothing to repair
+	rebuilt, err := Encoding{}.RepairBlock(ctx, cfg, meta, r, w)
+	require.NoError(t, err)
+	require.Empty(t, rebuilt)
+
+	blockPath := backend.KeyPathForBlock((uuid.UUID)(meta.BlockID), meta.TenantID)
+	read := func(name string) []byte {
+		b, err := r.Read(ctx, name, (uuid.UUID)(meta.BlockID), meta.TenantID, nil)
+		require.NoError(t, err)
+		return b
+	}
+	bloom0, idx := read(common.BloomName(0)), read(common.NameIndex)
+
+	// a missing bloom shard and a corrupt index are rebuilt like they were written
+	require.NoError(t, rawW.Delete(ctx, common.BloomName(0), blockPath, nil))
+	require.NoError(t, rawW.Write(ctx, common.NameIndex, blockPath, bytes.NewReader([]byte("{")), 1, nil))
+
+	rebuilt, err = Encoding{}.RepairBlock(ctx, cfg, meta, r, w)
+	require.NoError(t, err)
+	require.Equal(t, []string{common.BloomName(0), common.NameIndex}, rebuilt)
+	require.Equal(t, bloom0, read(common.BloomName(0)))
+	require.Equal(t, idx, read(common.NameIndex))
+
+	// the data can't be rebuilt
+	require.NoError(t, rawW.Delete(ctx, DataFileName, blockPath, nil))
+	_, err = Encoding{}.RepairBlock(ctx, cfg, meta, r, w)
+	require.ErrorIs(t, err, common.ErrCorruptBlockData)
+}
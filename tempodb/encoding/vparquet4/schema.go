@@ -190,6 +190,10 @@ type Span struct {
 
 	// Dynamically assignable dedicated attribute columns
 	DedicatedAttributes DedicatedAttributes `parquet:""`
+
+	// Flags is the last column of the schema so the indexes of the other columns are the same in the blocks written
+	// before it was added. These blocks are read with their own schema.
+	Flags uint32 `parquet:",delta"`
 }
 
 func (s *Span) IsRoot() bool {
@@ -465,6 +469,7 @@ func traceToParquetWithMapping(id common.ID, tr *tempopb.Trace, ot *Trace, dedic
 				ss.Name = s.Name
 				ss.Kind = int(s.Kind)
 				ss.TraceState = s.TraceState
+				ss.Flags = s.Flags
 				if s.Status != nil {
 					ss.StatusCode = int(s.Status.Code)
 					ss.StatusMessage = s.Status.Message
@@ -832,6 +837,7 @@ func parquetTraceToTempopbTrace(meta *backend.BlockMeta, parquetTrace *Trace) *t
 					TraceId:           parquetTrace.TraceID,
 					SpanId:            span.SpanID,
 					TraceState:        span.TraceState,
+					Flags:             span.Flags,
 					Name:              span.Name,
 					Kind:              v1_trace.Span_SpanKind(span.Kind),
 					ParentSpanId:      span.ParentSpanID,
@@ -0,0 +1,92 @@
+package vparquet4
+
+import (
+	kzstd "github.com/klauspost/compress/zstd"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+	"github.com/parquet-go/parquet-go/compress/snappy"
+	"github.com/parquet-go/parquet-go/compress/uncompressed"
+	"github.com/parquet-go/parquet-go/compress/zstd"
+	"github.com/parquet-go/parquet-go/encoding"
+	"github.com/parquet-go/parquet-go/format"
+
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// writerOptions returns the options of the writer of new blocks. Blocks are written with parquetSchema, or a copy of
+// it with the compression and dictionary overrides of the block config applied to all columns.
+func writerOptions(cfg *common.BlockConfig) []parquet.WriterOption {
+	codec := compressionCodec(cfg)
+	if codec == nil && !cfg.ParquetDisableDictionary {
+		return nil
+	}
+
+	return []parquet.WriterOption{parquet.NewSchema(parquetSchema.Name(), &encodingNode{
+		Node:  parquetSchema,
+		codec: codec,
+		plain: cfg.ParquetDisableDictionary,
+	})}
+}
+
+func compressionCodec(cfg *common.BlockConfig) compress.Codec {
+	switch cfg.ParquetCompression {
+	case common.ParquetCompressionSnappy:
+		return &snappy.Codec{}
+	case common.ParquetCompressionZstd:
+		level := zstd.DefaultLevel
+		if cfg.ParquetZstdLevel > 0 {
+			level = kzstd.EncoderLevelFromZstd(cfg.ParquetZstdLevel)
+		}
+		return &zstd.Codec{Level: level}
+	case common.ParquetCompressionNone:
+		return &uncompressed.Codec{}
+	}
+	return nil
+}
+
+// encodingNode overrides the compression and dictionary encoding of the leaves of a node
+type encodingNode struct {
+	parquet.Node
+	codec compress.Codec
+	plain bool
+}
+
+func (n *encodingNode) Fields() []parquet.Field {
+	return encodingFields(n.Node.Fields(), n.codec, n.plain)
+}
+
+type encodingField struct {
+	parquet.Field
+	codec compress.Codec
+	plain bool
+}
+
+func (f *encodingField) Fields() []parquet.Field {
+	return encodingFields(f.Field.Fields(), f.codec, f.plain)
+}
+
+func (f *encodingField) Compression() compress.Codec {
+	if f.codec != nil && f.Field.Leaf() {
+		return f.codec
+	}
+	return f.Field.Compression()
+}
+
+func (f *encodingField) Encoding() encoding.Encoding {
+	enc := f.Field.Encoding()
+	if f.plain && enc != nil {
+		switch enc.Encoding() {
+		case format.RLEDictionary, format.PlainDictionary:
+			return nil
+		}
+	}
+	return enc
+}
+
+func encodingFields(fields []parquet.Field, codec compress.Codec, plain bool) []parquet.Field {
+	wrapped := make([]parquet.Field, len(fields))
+	for i, f := range fields {
+		wrapped[i] = &encodingField{Field: f, codec: codec, plain: plain}
+	}
+	return wrapped
+}
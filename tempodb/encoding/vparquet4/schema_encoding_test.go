@@ -0,0 +1,90 @@
+package vparquet4
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go/format"
+	"github.com/stretchr/testify/require"
+
+	tempo_io "github.com/grafana/tempo/pkg/io"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestWriterOptions(t *testing.T) {
+	require.Nil(t, writerOptions(&common.BlockConfig{}))
+
+	tcs := []struct {
+		name      string
+		cfg       common.BlockConfig
+		codec     format.CompressionCodec
+		plainOnly bool
+	}{
+		{
+			name:  "zstd",
+			cfg:   common.BlockConfig{ParquetCompression: common.ParquetCompressionZstd, ParquetZstdLevel: 9},
+			codec: format.Zstd,
+		},
+		{
+			name:  "none",
+			cfg:   common.BlockConfig{ParquetCompression: common.ParquetCompressionNone},
+			codec: format.Uncompressed,
+		},
+		{
+			name:      "no dictionary",
+			cfg:       common.BlockConfig{ParquetDisableDictionary: true},
+			plainOnly: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			rawR, rawW, _, err := local.New(&local.Config{
+				Path: t.TempDir(),
+			})
+			require.NoError(t, err)
+
+			r := backend.NewReader(rawR)
+			w := backend.NewWriter(rawW)
+
+			cfg := tc.cfg
+			cfg.BloomFP = 0.01
+			cfg.BloomShardSizeBytes = 100 * 1024
+
+			meta := backend.NewBlockMeta("fake", uuid.New(), VersionString, backend.EncNone, "")
+			meta.TotalObjects = 1
+
+			wantTr := fullyPopulatedTestTrace(test.ValidTraceID(nil))
+			s := newStreamingBlock(ctx, &cfg, meta, r, w, tempo_io.NewBufferedWriter)
+			require.NoError(t, s.Add(wantTr, 0, 0))
+			_, err = s.Complete()
+			require.NoError(t, err)
+
+			b := newBackendBlock(s.meta, r)
+			pf, _, err := b.openForSearch(ctx, common.DefaultSearchOptions())
+			require.NoError(t, err)
+
+			for _, rg := range pf.Metadata().RowGroups {
+				for _, c := range rg.Columns {
+					if tc.cfg.ParquetCompression != "" {
+						require.Equal(t, tc.codec, c.MetaData.Codec, c.MetaData.PathInSchema)
+					}
+					if tc.plainOnly {
+						require.NotContains(t, c.MetaData.Encoding, format.RLEDictionary, c.MetaData.PathInSchema)
+					}
+				}
+			}
+
+			// the block is read as usual
+			got, err := b.FindTraceByID(ctx, wantTr.TraceID, common.DefaultSearchOptions())
+			require.NoError(t, err)
+			require.NotNil(t, got.Trace)
+			require.Equal(t, parquetTraceToTempopbTrace(meta, wantTr).Size(), got.Trace.Size())
+		})
+	}
+}
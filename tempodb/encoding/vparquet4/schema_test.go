@@ -44,6 +44,21 @@ func TestProtoParquetRoundTrip(t *testing.T) {
 	tempopbTraceEqual(t, expectedTrace, actualTrace)
 }
 
+func TestProtoParquetSpanFlags(t *testing.T) {
+	id := test.ValidTraceID(nil)
+	expectedTrace := test.MakeTrace(1, id)
+	spans := expectedTrace.ResourceSpans[0].ScopeSpans[0].Spans
+	spans[0].Flags = 0x301 // sampled, has and is remote parent
+
+	parquetTrace, _ := traceToParquet(&backend.BlockMeta{}, id, expectedTrace, nil)
+	parquetSpans := parquetTrace.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Equal(t, uint32(0x301), parquetSpans[0].Flags)
+	require.Len(t, parquetSpans[0].Attrs, len(spans[0].Attributes))
+
+	actualTrace := parquetTraceToTempopbTrace(&backend.BlockMeta{}, parquetTrace)
+	require.Equal(t, expectedTrace, actualTrace)
+}
+
 func TestProtoToParquetEmptyTrace(t *testing.T) {
 	want := &Trace{
 		TraceID:       make([]byte, 16),
@@ -162,6 +162,7 @@ func createWALBlock(meta *backend.BlockMeta, filepath, dataEncoding string, inge
 			TenantID:          meta.TenantID,
 			DedicatedColumns:  meta.DedicatedColumns,
 			ReplicationFactor: meta.ReplicationFactor,
+			ErrorTimesTracked: true,
 		},
 		path:           filepath,
 		ids:            common.NewIDMap[int64](0),
@@ -235,10 +236,10 @@ func (w *walBlockFlush) file(ctx context.Context) (*pageFile, error) {
 	o := []parquet.FileOption{
 		parquet.SkipBloomFilters(true),
 		parquet.SkipPageIndex(true),
-		parquet.FileSchema(parquetSchema),
 	}
 
-	pf, err := parquet.OpenFile(wr, size, o...)
+	// wal files written before the span flags column was added are replayed with their own schema
+	pf, _, err := openParquetFile(wr, size, false, o...)
 	if err != nil {
 		return nil, fmt.Errorf("error opening parquet file: %w", err)
 	}
@@ -257,7 +258,7 @@ func (w *walBlockFlush) rowIterator() (*rowIterator, error) {
 	pf := file.parquetFile
 
 	idx, _, _ := parquetquery.GetColumnIndexByPath(pf, TraceIDColumnName)
-	r := parquet.NewReader(pf)
+	r := parquet.NewReader(pf, parquetSchema)
 	return newRowIterator(r, file, w.ids.EntriesSortedByID(), idx), nil
 }
 
@@ -363,6 +364,7 @@ func (b *walBlock) AppendTrace(id common.ID, trace *tempopb.Trace, start, end ui
 	}
 
 	b.meta.ObjectAdded(start, end)
+	addErrorTimes(b.meta, b.buffer)
 	b.ids.Set(id, int64(b.ids.Len())) // Next row number
 
 	b.unflushedSize += int64(estimateMarshalledSizeFromTrace(b.buffer))
@@ -510,6 +512,11 @@ func (b *walBlock) Clear() error {
 	return errs.Err()
 }
 
+// FindTraceByIDs implements common.Finder. The WAL is local so traces are looked up one by one.
+func (b *walBlock) FindTraceByIDs(ctx context.Context, ids []common.ID, opts common.SearchOptions) ([]*tempopb.Trace, *tempopb.TraceByIDMetrics, error) {
+	return common.FindTraceByIDsOneByOne(ctx, ids, opts, b.FindTraceByID)
+}
+
 func (b *walBlock) FindTraceByID(ctx context.Context, id common.ID, opts common.SearchOptions) (*tempopb.TraceByIDResponse, error) {
 	ctx, span := tracer.Start(ctx, "walBlock.FindTraceByID")
 	defer span.End()
@@ -527,7 +534,7 @@ func (b *walBlock) FindTraceByID(ctx context.Context, id common.ID, opts common.
 			defer file.Close()
 			pf := file.parquetFile
 
-			r := parquet.NewReader(pf)
+			r := parquet.NewReader(pf, parquetSchema)
 			defer r.Close()
 
 			err = r.SeekToRow(rowNumber)
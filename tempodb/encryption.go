@@ -0,0 +1,47 @@
+package tempodb
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/grafana/dskit/flagext"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// NewKMS returns the KMS of the encryption config, the KMS set by the program embedding Tempo or a static KMS with
+// the rotated and current tenant keys.
+func NewKMS(cfg *EncryptionConfig) (backend.KMS, error) {
+	if cfg.KMS != nil {
+		return cfg.KMS, nil
+	}
+
+	keys := make(map[string][][]byte, len(cfg.TenantKeys))
+	for tenantID, secret := range cfg.TenantKeys {
+		key, err := decodeTenantKey(secret)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key of tenant %s is invalid: %w", tenantID, err)
+		}
+		keys[tenantID] = [][]byte{key}
+
+		for i, rotated := range cfg.RotatedTenantKeys[tenantID] {
+			key, err := decodeTenantKey(rotated)
+			if err != nil {
+				return nil, fmt.Errorf("rotated encryption key %d of tenant %s is invalid: %w", i, tenantID, err)
+			}
+			keys[tenantID] = append(keys[tenantID], key)
+		}
+	}
+	return backend.NewStaticKMS(keys)
+}
+
+func decodeTenantKey(secret flagext.Secret) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(secret.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != backend.DataKeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", backend.DataKeySize, len(key))
+	}
+	return key, nil
+}
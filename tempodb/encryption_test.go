@@ -0,0 +1,125 @@
+package tempodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/golang/protobuf/proto" //nolint:all
+	"github.com/grafana/dskit/flagext"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
+)
+
+func TestEncryption(t *testing.T) {
+	ctx := context.Background()
+	dec := model.MustNewSegmentDecoder(model.CurrentEncoding)
+	key := bytes.Repeat([]byte{1}, backend.DataKeySize)
+
+	r, w, _, tempDir := testConfig(t, backend.EncNone, 0, func(cfg *Config) {
+		cfg.Block.Version = vparquet4.VersionString
+		cfg.Encryption.TenantKeys = map[string]flagext.Secret{
+			testTenantID: flagext.SecretWithValue(base64.StdEncoding.EncodeToString(key)),
+		}
+	})
+	r.EnablePolling(ctx, &mockJobSharder{}, false)
+	rw := r.(*readerWriter)
+
+	head, err := w.WAL().NewBlock(&backend.BlockMeta{BlockID: backend.NewUUID(), TenantID: testTenantID}, model.CurrentEncoding)
+	require.NoError(t, err)
+	id := test.ValidTraceID(nil)
+	req := test.MakeTrace(5, id)
+	writeTraceToWal(t, head, dec, id, req, 0, 0)
+
+	complete, err := w.CompleteBlock(ctx, head)
+	require.NoError(t, err)
+	blockID := complete.BlockMeta().BlockID
+
+	// the data of the block is encrypted and the meta holds the wrapped data key
+	data, err := os.ReadFile(path.Join(tempDir, "traces", testTenantID, blockID.String(), vparquet4.DataFileName))
+	require.NoError(t, err)
+	require.NotEqual(t, "PAR1", string(data[:4]))
+
+	rw.pollBlocklist(ctx)
+	metas := rw.BlockMetas(testTenantID)
+	require.Len(t, metas, 1)
+	require.NotEmpty(t, metas[0].EncryptionKey)
+
+	// and traces are found in the decrypted block
+	found, failedBlocks, err := r.Find(ctx, testTenantID, id, BlockIDMin, BlockIDMax, 0, 0, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Nil(t, failedBlocks)
+	require.Len(t, found, 1)
+	require.True(t, proto.Equal(req, found[0].Trace))
+}
+
+func TestEncryptionKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	dec := model.MustNewSegmentDecoder(model.CurrentEncoding)
+	oldKey := bytes.Repeat([]byte{1}, backend.DataKeySize)
+	newKey := bytes.Repeat([]byte{2}, backend.DataKeySize)
+	secret := func(key []byte) flagext.Secret {
+		return flagext.SecretWithValue(base64.StdEncoding.EncodeToString(key))
+	}
+
+	_, w, _, tempDir := testConfig(t, backend.EncNone, 0, func(cfg *Config) {
+		cfg.Block.Version = vparquet4.VersionString
+		cfg.Encryption.TenantKeys = map[string]flagext.Secret{testTenantID: secret(oldKey)}
+	})
+
+	head, err := w.WAL().NewBlock(&backend.BlockMeta{BlockID: backend.NewUUID(), TenantID: testTenantID}, model.CurrentEncoding)
+	require.NoError(t, err)
+	id := test.ValidTraceID(nil)
+	req := test.MakeTrace(5, id)
+	writeTraceToWal(t, head, dec, id, req, 0, 0)
+	_, err = w.CompleteBlock(ctx, head)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path.Join(tempDir, "traces", testTenantID, head.BlockMeta().BlockID.String(), vparquet4.DataFileName))
+	require.NoError(t, err)
+
+	// open the same storage with a rotated key
+	reopen := func(keys map[string]flagext.Secret, rotated map[string][]flagext.Secret) *readerWriter {
+		r, _, _, _ := testConfig(t, backend.EncNone, 0, func(cfg *Config) {
+			cfg.Block.Version = vparquet4.VersionString
+			cfg.Local.Path = path.Join(tempDir, "traces")
+			cfg.Encryption.TenantKeys = keys
+			cfg.Encryption.RotatedTenantKeys = rotated
+		})
+		r.EnablePolling(ctx, &mockJobSharder{}, false)
+		rw := r.(*readerWriter)
+		rw.pollBlocklist(ctx)
+		return rw
+	}
+
+	rw := reopen(map[string]flagext.Secret{testTenantID: secret(newKey)}, map[string][]flagext.Secret{testTenantID: {secret(oldKey)}})
+	metas := rw.BlockMetas(testTenantID)
+	require.Len(t, metas, 1)
+	require.Equal(t, backend.StaticKeyVersion(oldKey), metas[0].EncryptionKeyVersion)
+
+	// the data key is re-wrapped with the new key without rewriting the data
+	rw.rewrapTenant(ctx, testTenantID, &mockSharder{})
+	metas = rw.BlockMetas(testTenantID)
+	require.Len(t, metas, 1)
+	require.Equal(t, backend.StaticKeyVersion(newKey), metas[0].EncryptionKeyVersion)
+
+	rewritten, err := os.ReadFile(path.Join(tempDir, "traces", testTenantID, head.BlockMeta().BlockID.String(), vparquet4.DataFileName))
+	require.NoError(t, err)
+	require.Equal(t, data, rewritten)
+
+	// so the rotated key can be removed
+	rw = reopen(map[string]flagext.Secret{testTenantID: secret(newKey)}, nil)
+	found, failedBlocks, err := rw.Find(ctx, testTenantID, id, BlockIDMin, BlockIDMax, 0, 0, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Nil(t, failedBlocks)
+	require.Len(t, found, 1)
+	require.True(t, proto.Equal(req, found[0].Trace))
+}
@@ -0,0 +1,21 @@
+package tempodb
+
+import (
+	"fmt"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// newFederation federates the trace storage with the federated buckets.
+func newFederation(cfg *Config, primaryR backend.RawReader, primaryC backend.Compactor) (*backend.Federation, error) {
+	buckets := make([]backend.FederatedBucket, 0, len(cfg.FederatedBuckets))
+	for _, b := range cfg.FederatedBuckets {
+		rawR, _, c, err := newSecondaryBackendWithCompactor(b.Backend, b.Local, b.GCS, b.S3, b.Azure)
+		if err != nil {
+			return nil, fmt.Errorf("error creating federated bucket %s: %w", b.Name, err)
+		}
+		buckets = append(buckets, backend.FederatedBucket{Name: b.Name, R: rawR, C: c})
+	}
+
+	return backend.NewFederation(primaryR, primaryC, buckets)
+}
@@ -0,0 +1,124 @@
+package tempodb
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto" //nolint:all
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestFederatedBuckets(t *testing.T) {
+	ctx := context.Background()
+	dec := model.MustNewSegmentDecoder(model.CurrentEncoding)
+
+	writeBlock := func(w Writer) (common.ID, *tempopb.Trace, backend.UUID) {
+		head, err := w.WAL().NewBlock(&backend.BlockMeta{BlockID: backend.NewUUID(), TenantID: testTenantID}, model.CurrentEncoding)
+		require.NoError(t, err)
+
+		id := test.ValidTraceID(nil)
+		req := test.MakeTrace(5, id)
+		writeTraceToWal(t, head, dec, id, req, 0, 0)
+
+		complete, err := w.CompleteBlock(ctx, head)
+		require.NoError(t, err)
+		return id, req, complete.BlockMeta().BlockID
+	}
+
+	// a block in the bucket of the old account
+	_, oldW, _, oldDir := testConfig(t, backend.EncNone, 0)
+	oldID, oldReq, oldBlockID := writeBlock(oldW)
+
+	r, w, c, _ := testConfig(t, backend.EncNone, 0, func(cfg *Config) {
+		cfg.FederatedBuckets = []FederatedBucketConfig{{
+			Name:    "old",
+			Backend: backend.Local,
+			Local:   &local.Config{Path: path.Join(oldDir, "traces")},
+		}}
+	})
+	require.NoError(t, c.EnableCompaction(ctx, &CompactorConfig{
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          time.Hour,
+		CompactedBlockRetention: time.Hour,
+	}, &mockSharder{}, &mockOverrides{}))
+	r.EnablePolling(ctx, &mockJobSharder{}, false)
+	rw := r.(*readerWriter)
+
+	id, req, blockID := writeBlock(w)
+	rw.pollBlocklist(ctx)
+
+	// the blocks of both buckets are polled and tagged with their bucket
+	sources := map[backend.UUID]string{}
+	for _, m := range rw.blocklist.Metas(testTenantID) {
+		sources[m.BlockID] = m.Source
+	}
+	require.Equal(t, map[backend.UUID]string{blockID: backend.FederationPrimary, oldBlockID: "old"}, sources)
+
+	// and read from their bucket
+	for _, tc := range []struct {
+		id  common.ID
+		req *tempopb.Trace
+	}{{id, req}, {oldID, oldReq}} {
+		found, failedBlocks, err := r.Find(ctx, testTenantID, tc.id, BlockIDMin, BlockIDMax, 0, 0, common.DefaultSearchOptions())
+		require.NoError(t, err)
+		require.Nil(t, failedBlocks)
+		require.Len(t, found, 1)
+		require.True(t, proto.Equal(tc.req, found[0].Trace))
+	}
+
+	// only the blocks of the primary bucket are retained
+	rw.compactorCfg.BlockRetention = 0
+	rw.doRetention(ctx)
+	metas := rw.blocklist.Metas(testTenantID)
+	require.Len(t, metas, 1)
+	require.Equal(t, oldBlockID, metas[0].BlockID)
+
+	_, err := rw.r.BlockMeta(ctx, (uuid.UUID)(oldBlockID), testTenantID)
+	require.NoError(t, err)
+}
+
+func TestFederationKeepsOptionalInterfaces(t *testing.T) {
+	ctx := context.Background()
+	keypath := backend.KeyPath{testTenantID}
+
+	r, _, _, _ := testConfig(t, backend.EncNone, 0, func(cfg *Config) {
+		cfg.Tiered = TieredConfig{
+			Local:            &local.Config{Path: t.TempDir()},
+			MaxAge:           DefaultTieredMaxAge,
+			EvictionInterval: DefaultTieredEvictionInterval,
+		}
+		cfg.FederatedBuckets = []FederatedBucketConfig{{
+			Name:    "old",
+			Backend: backend.Local,
+			Local:   &local.Config{Path: t.TempDir()},
+		}}
+	})
+	rw := r.(*readerWriter)
+	require.NotNil(t, rw.federation)
+
+	// conditional reads of the local trace storage survive the tiered and federated backends
+	require.NoError(t, rw.rawW.Write(ctx, "object", keypath, bytes.NewReader([]byte("object")), 6, nil))
+	cr, ok := rw.rawR.(backend.ConditionalReader)
+	require.True(t, ok)
+	rc, _, version, err := cr.ReadIfChanged(ctx, "object", keypath, "")
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	require.NotEmpty(t, version)
+	_, _, _, err = cr.ReadIfChanged(ctx, "object", keypath, version)
+	require.ErrorIs(t, err, backend.ErrNotModified)
+
+	archiver, ok := rw.c.(backend.Archiver)
+	require.True(t, ok)
+	require.ErrorIs(t, archiver.ArchiveBlock(ctx, uuid.New(), testTenantID, "cold"), backend.ErrArchivingNotSupported)
+}
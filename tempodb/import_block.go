@@ -0,0 +1,130 @@
+package tempodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+)
+
+// ImportBlock validates an externally produced block, for example written by a batch pipeline, and uploads it to the
+// tenant under a new block ID. srcPath is the folder of the block files, meta.json included. The block is visible to
+// the next poll cycle. The meta of the imported block is returned.
+func ImportBlock(ctx context.Context, tenantID, srcPath string, w backend.Writer) (*backend.BlockMeta, error) {
+	if tenantID == "" {
+		return nil, errors.New("tenant id is required")
+	}
+
+	from := backend.NewReader(&blockFolderReader{path: srcPath})
+
+	// the folder reader ignores the block id and tenant
+	meta, err := from.BlockMeta(ctx, uuid.Nil, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading block meta in %s: %w", srcPath, err)
+	}
+
+	if err := validateImportedBlockMeta(meta); err != nil {
+		return nil, fmt.Errorf("invalid block meta in %s: %w", srcPath, err)
+	}
+
+	enc, err := encoding.FromVersion(meta.Version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block meta in %s: %w", srcPath, err)
+	}
+
+	block, err := enc.OpenBlock(meta, from)
+	if err != nil {
+		return nil, fmt.Errorf("error opening block in %s: %w", srcPath, err)
+	}
+	if err := block.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid block in %s: %w", srcPath, err)
+	}
+
+	// a new id so the block can't collide with blocks of the tenant, or be imported twice under the same id
+	importedMeta := *meta
+	importedMeta.BlockID = backend.NewUUID()
+	importedMeta.TenantID = tenantID
+
+	if err := enc.MigrateBlock(ctx, meta, &importedMeta, from, w); err != nil {
+		return nil, fmt.Errorf("error uploading block %s: %w", importedMeta.BlockID.String(), err)
+	}
+
+	return &importedMeta, nil
+}
+
+func validateImportedBlockMeta(meta *backend.BlockMeta) error {
+	if meta.Version == "" {
+		return errors.New("missing version")
+	}
+	if meta.TotalObjects <= 0 {
+		return errors.New("block has no objects")
+	}
+	if meta.Size_ == 0 {
+		return errors.New("block has no size")
+	}
+	if meta.StartTime.IsZero() || meta.EndTime.Before(meta.StartTime) {
+		return fmt.Errorf("invalid time range %s - %s", meta.StartTime, meta.EndTime)
+	}
+	return nil
+}
+
+// blockFolderReader is a backend.RawReader of the files of a single block in a local folder. Keypaths are ignored.
+type blockFolderReader struct {
+	path string
+}
+
+var _ backend.RawReader = (*blockFolderReader)(nil)
+
+func (r *blockFolderReader) List(context.Context, backend.KeyPath) ([]string, error) {
+	return nil, util.ErrUnsupported
+}
+
+func (r *blockFolderReader) ListBlocks(context.Context, string) ([]uuid.UUID, []uuid.UUID, error) {
+	return nil, nil, util.ErrUnsupported
+}
+
+func (r *blockFolderReader) Find(context.Context, backend.KeyPath, backend.FindFunc) error {
+	return util.ErrUnsupported
+}
+
+func (r *blockFolderReader) Read(_ context.Context, name string, _ backend.KeyPath, _ *backend.CacheInfo) (io.ReadCloser, int64, error) {
+	f, err := os.Open(filepath.Join(r.path, name))
+	if err != nil {
+		return nil, -1, readFolderError(err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, -1, err
+	}
+	return f, info.Size(), nil
+}
+
+func (r *blockFolderReader) ReadRange(_ context.Context, name string, _ backend.KeyPath, offset uint64, buffer []byte, _ *backend.CacheInfo) error {
+	f, err := os.Open(filepath.Join(r.path, name))
+	if err != nil {
+		return readFolderError(err)
+	}
+	defer f.Close()
+
+	_, err = f.ReadAt(buffer, int64(offset))
+	return err
+}
+
+func (r *blockFolderReader) Shutdown() {}
+
+func readFolderError(err error) error {
+	if errors.Is(err, os.ErrNotExist) {
+		return backend.ErrDoesNotExist
+	}
+	return err
+}
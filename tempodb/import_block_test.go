@@ -0,0 +1,83 @@
+package tempodb
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
+	"github.com/grafana/tempo/tempodb/wal"
+)
+
+func TestImportBlock(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, _, err := New(&Config{
+		Backend: backend.Local,
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 17,
+			BloomFP:              .01,
+			BloomShardSizeBytes:  100_000,
+			Version:              vparquet4.VersionString,
+			Encoding:             backend.EncLZ4_256k,
+			IndexPageSizeBytes:   1000,
+			RowGroupSizeBytes:    30_000_000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	r.EnablePolling(ctx, &mockJobSharder{}, false)
+	rw := r.(*readerWriter)
+
+	// an externally produced block, written to a folder outside of the backend
+	id := test.ValidTraceID(nil)
+	block := cutTestBlockWithTraces(t, w, []testData{{id, test.MakeTrace(10, id), 0, 0}})
+	src := filepath.Join(tempDir, "external")
+	require.NoError(t, os.Rename(filepath.Join(tempDir, "traces", testTenantID, block.BlockMeta().BlockID.String()), src))
+
+	meta, err := ImportBlock(ctx, "imported", src, rw.w)
+	require.NoError(t, err)
+	require.NotEqual(t, block.BlockMeta().BlockID, meta.BlockID)
+	require.Equal(t, "imported", meta.TenantID)
+	require.Equal(t, block.BlockMeta().TotalObjects, meta.TotalObjects)
+
+	// the block is visible to the next poll
+	rw.pollBlocklist(ctx)
+	require.Len(t, rw.blocklist.Metas("imported"), 1)
+
+	trs, failedBlocks, err := rw.Find(ctx, "imported", id, BlockIDMin, BlockIDMax, 0, 0, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Nil(t, failedBlocks)
+	require.Len(t, trs, 1)
+
+	// invalid blocks are rejected
+	_, err = ImportBlock(ctx, "imported", filepath.Join(tempDir, "missing"), rw.w)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+
+	data := filepath.Join(src, vparquet4.DataFileName)
+	info, err := os.Stat(data)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(data, info.Size()-1))
+	_, err = ImportBlock(ctx, "imported", src, rw.w)
+	require.ErrorContains(t, err, "invalid block")
+
+	rw.pollBlocklist(ctx)
+	require.Len(t, rw.blocklist.Metas("imported"), 1)
+}
@@ -0,0 +1,78 @@
+package tempodb
+
+import (
+	"sort"
+	"time"
+
+	"github.com/grafana/tempo/tempodb/blocklist"
+)
+
+// TenantOwnership is what this instance knows about the ownership of the index building and compaction of a tenant.
+type TenantOwnership struct {
+	Tenant     string                          `json:"tenant"`
+	Index      *blocklist.TenantIndexOwnership `json:"index,omitempty"`
+	Compaction *TenantCompactionOwnership      `json:"compaction,omitempty"`
+}
+
+// TenantCompactionOwnership reports the compaction jobs of a tenant claimed by this compactor.
+type TenantCompactionOwnership struct {
+	// LastCycle is the start of the last compaction cycle of the tenant, LastCompaction the end of the last job this
+	// compactor compacted.
+	LastCycle      time.Time `json:"last_cycle"`
+	LastCompaction time.Time `json:"last_compaction,omitempty"`
+	// OwnedJobs and OtherJobs are the jobs of the last cycle owned by this compactor and by other compactors.
+	OwnedJobs int `json:"owned_jobs"`
+	OtherJobs int `json:"other_jobs"`
+	// Conflicts counts the jobs this compactor lost the ownership of while compacting them.
+	Conflicts int `json:"conflicts"`
+}
+
+// TenantOwnership returns the ownership of the index building and compaction of all tenants known to this instance,
+// ordered by tenant.
+func (rw *readerWriter) TenantOwnership() []TenantOwnership {
+	byTenant := map[string]*TenantOwnership{}
+	get := func(tenantID string) *TenantOwnership {
+		o, ok := byTenant[tenantID]
+		if !ok {
+			o = &TenantOwnership{Tenant: tenantID}
+			byTenant[tenantID] = o
+		}
+		return o
+	}
+
+	if rw.blocklistPoller != nil {
+		for _, idx := range rw.blocklistPoller.TenantIndexOwnership() {
+			get(idx.Tenant).Index = &idx
+		}
+	}
+
+	rw.compactionOwnershipMtx.Lock()
+	for tenantID, c := range rw.compactionOwnership {
+		compaction := *c
+		get(tenantID).Compaction = &compaction
+	}
+	rw.compactionOwnershipMtx.Unlock()
+
+	out := make([]TenantOwnership, 0, len(byTenant))
+	for _, o := range byTenant {
+		out = append(out, *o)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Tenant < out[j].Tenant })
+
+	return out
+}
+
+func (rw *readerWriter) updateCompactionOwnership(tenantID string, fn func(c *TenantCompactionOwnership)) {
+	rw.compactionOwnershipMtx.Lock()
+	defer rw.compactionOwnershipMtx.Unlock()
+
+	if rw.compactionOwnership == nil {
+		rw.compactionOwnership = map[string]*TenantCompactionOwnership{}
+	}
+	c, ok := rw.compactionOwnership[tenantID]
+	if !ok {
+		c = &TenantCompactionOwnership{}
+		rw.compactionOwnership[tenantID] = c
+	}
+	fn(c)
+}
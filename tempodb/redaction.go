@@ -0,0 +1,84 @@
+package tempodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log/level"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
+)
+
+const blocksWrittenSourceRedaction = "redaction"
+
+// RedactBlock rewrites the block replacing the values of the redaction with common.RedactedValue. The rewritten
+// block replaces the original one, which is marked compacted. The new block meta and the number of values replaced
+// are returned. common.ErrUnsupported is returned for blocks of versions that can't redact attributes.
+func (rw *readerWriter) RedactBlock(ctx context.Context, meta *backend.BlockMeta, redaction *common.AttributeRedaction) (*backend.BlockMeta, int, error) {
+	// only vParquet4 enforces the attribute redaction while compacting
+	if meta.Version != vparquet4.VersionString {
+		return nil, 0, fmt.Errorf("redacting %s blocks: %w", meta.Version, common.ErrUnsupported)
+	}
+
+	enc, err := encoding.FromVersion(meta.Version)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	start := time.Now()
+	blockCfg := *rw.cfg.Block
+	blockCfg.Version = meta.Version
+	blockCfg.DedicatedColumns = meta.DedicatedColumns
+
+	redacted := 0
+	compactor := enc.NewCompactor(common.CompactionOptions{
+		BlockConfig:        blockCfg,
+		ChunkSizeBytes:     DefaultChunkSizeBytes,
+		FlushSizeBytes:     DefaultFlushSizeBytes,
+		IteratorBufferSize: DefaultIteratorBufferSize,
+		OutputBlocks:       1,
+		Combiner:           model.StaticCombiner, // a single block is rewritten, objects are never combined
+		AttributeRedaction: redaction,
+		RetentionClass:     meta.RetentionClass,
+		AttributesRedacted: func(values int) { redacted += values },
+
+		BytesWritten:      func(_, _ int) {},
+		ObjectsCombined:   func(_, _ int) {},
+		ObjectsWritten:    func(_, _ int) {},
+		SpansDiscarded:    func(_, _, _ string, _ int) {},
+		DisconnectedTrace: func() {},
+		RootlessTrace:     func() {},
+		DedupedSpans:      func(_, _ int) {},
+	})
+
+	inputs := []*backend.BlockMeta{meta}
+	outputs, err := compactor.Compact(ctx, rw.logger, rw.r, rw.w, inputs)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(outputs) != 1 {
+		return nil, 0, fmt.Errorf("redacting block %s produced %d blocks", meta.BlockID, len(outputs))
+	}
+
+	if err := markCompacted(rw, meta.TenantID, inputs, outputs); err != nil {
+		return nil, 0, err
+	}
+	recordBlocksWritten(blocksWrittenSourceRedaction, outputs...)
+	rw.notifyCompaction(ctx, meta.TenantID, inputs, outputs, start)
+
+	level.Info(rw.logger).Log(
+		"msg", "redacted block",
+		"tenantID", meta.TenantID,
+		"blockID", meta.BlockID.String(),
+		"newBlockID", outputs[0].BlockID.String(),
+		"redactedValues", redacted,
+		"elapsed", time.Since(start),
+	)
+
+	return outputs[0], redacted, nil
+}
@@ -0,0 +1,63 @@
+package tempodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	v2 "github.com/grafana/tempo/tempodb/encoding/v2"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
+)
+
+func TestRedactBlock(t *testing.T) {
+	ctx := context.Background()
+
+	r, w, c, _ := testConfig(t, backend.EncNone, 0)
+	require.NoError(t, c.EnableCompaction(ctx, &CompactorConfig{MaxCompactionRange: time.Hour}, &mockSharder{}, &mockOverrides{}))
+	r.EnablePolling(ctx, &mockJobSharder{}, false)
+	rw := r.(*readerWriter)
+
+	dec := model.MustNewSegmentDecoder(model.CurrentEncoding)
+	block, err := w.WAL().NewBlock(backend.NewBlockMeta(testTenantID, uuid.New(), vparquet4.VersionString, backend.EncNone, ""), model.CurrentEncoding)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		id := test.ValidTraceID(nil)
+		writeTraceToWal(t, block, dec, id, test.MakeTrace(10, id), 0, 0)
+	}
+	require.NoError(t, block.Flush())
+	complete, err := w.CompleteBlock(ctx, block)
+	require.NoError(t, err)
+	meta := complete.BlockMeta()
+
+	rw.pollBlocklist(ctx)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 1)
+
+	redaction := &common.AttributeRedaction{Key: "key", Value: "value"}
+	newMeta, redacted, err := rw.RedactBlock(ctx, meta, redaction)
+	require.NoError(t, err)
+	require.Greater(t, redacted, 0)
+	require.Equal(t, meta.TotalObjects, newMeta.TotalObjects)
+
+	// the rewritten block replaces the original one
+	metas := rw.blocklist.Metas(testTenantID)
+	require.Len(t, metas, 1)
+	require.Equal(t, newMeta.BlockID, metas[0].BlockID)
+	compacted := rw.blocklist.CompactedMetas(testTenantID)
+	require.Len(t, compacted, 1)
+	require.Equal(t, meta.BlockID, compacted[0].BlockID)
+
+	// nothing is left to redact
+	_, redacted, err = rw.RedactBlock(ctx, newMeta, redaction)
+	require.NoError(t, err)
+	require.Equal(t, 0, redacted)
+
+	_, _, err = rw.RedactBlock(ctx, &backend.BlockMeta{Version: v2.VersionString}, redaction)
+	require.ErrorIs(t, err, common.ErrUnsupported)
+}
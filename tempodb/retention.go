@@ -2,6 +2,7 @@ package tempodb
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/go-kit/log/level"
@@ -37,6 +38,7 @@ func (rw *readerWriter) doRetention(ctx context.Context) {
 }
 
 func (rw *readerWriter) RetainWithConfig(ctx context.Context, compactorCfg *CompactorConfig, compactorSharder CompactorSharder, compactorOverrides CompactorOverrides) {
+	compactorOverrides = withPartitionOverrides(compactorOverrides)
 	tenants := rw.blocklist.Tenants()
 
 	bg := boundedwaitgroup.New(compactorCfg.RetentionConcurrency)
@@ -61,29 +63,34 @@ func (rw *readerWriter) retainTenant(ctx context.Context, tenantID string, compa
 	start := time.Now()
 	defer func() { metricRetentionDuration.Observe(time.Since(start).Seconds()) }()
 
-	// Check for overrides
-	retention := compactorCfg.BlockRetention // Default
-	if r := compactorOverrides.BlockRetentionForTenant(tenantID); r != 0 {
-		retention = r
-	}
-	level.Debug(rw.logger).Log("msg", "Performing block retention", "tenantID", tenantID, "retention", retention)
+	// Check for overrides. Blocks with a retention class use the retention of their class.
+	classes := retentionClassesForTenant(tenantID, compactorCfg, compactorOverrides)
+	retention := classes.Default
+	level.Debug(rw.logger).Log("msg", "Performing block retention", "tenantID", tenantID, "retention", retention, "retentionClasses", len(classes.Retention))
 
 	// iterate through block list.  make compacted anything that is past retention.
-	cutoff := time.Now().Add(-retention)
+	now := time.Now()
 	blocklist := rw.blocklist.Metas(tenantID)
 	for _, b := range blocklist {
+		cutoff := now.Add(-retention)
+		if classes.Enabled() {
+			cutoff = now.Add(-classes.RetentionFor(b.RetentionClass))
+		}
+
 		select {
 		case <-ctx.Done():
 			return
 		default:
-			if b.EndTime.Before(cutoff) && compactorSharder.Owns(b.BlockID.String()) {
-				level.Info(rw.logger).Log("msg", "marking block for deletion", "blockID", b.BlockID, "tenantID", tenantID)
+			// the blocks of federated buckets are retained by the owners of the buckets
+			if b.EndTime.Before(cutoff) && !b.IsFederated() && compactorSharder.Owns(b.BlockID.String()) {
+				level.Info(rw.logger).Log("msg", "marking block for deletion", "blockID", b.BlockID, "tenantID", tenantID, "retentionClass", b.RetentionClass)
 				err := rw.c.MarkBlockCompacted((uuid.UUID)(b.BlockID), tenantID)
 				if err != nil {
 					level.Error(rw.logger).Log("msg", "failed to mark block compacted during retention", "blockID", b.BlockID, "tenantID", tenantID, "err", err)
 					metricRetentionErrors.Inc()
 				} else {
 					metricMarkedForDeletion.Inc()
+					rw.notifyRetention(ctx, tenantID, b.BlockID, RetentionActionMarked, b)
 
 					rw.blocklist.Update(tenantID, nil, []*backend.BlockMeta{b}, []*backend.CompactedBlockMeta{
 						{
@@ -96,8 +103,11 @@ func (rw *readerWriter) retainTenant(ctx context.Context, tenantID string, compa
 		}
 	}
 
+	rw.archiveTenant(ctx, tenantID, compactorSharder, compactorOverrides)
+	rw.rewrapTenant(ctx, tenantID, compactorSharder)
+
 	// iterate through compacted list looking for blocks ready to be cleared
-	cutoff = time.Now().Add(-compactorCfg.CompactedBlockRetention)
+	cutoff := time.Now().Add(-compactorCfg.CompactedBlockRetention)
 	compactedBlocklist := rw.blocklist.CompactedMetas(tenantID)
 	for _, b := range compactedBlocklist {
 		select {
@@ -105,18 +115,185 @@ func (rw *readerWriter) retainTenant(ctx context.Context, tenantID string, compa
 			return
 		default:
 			level.Debug(rw.logger).Log("owns", compactorSharder.Owns(b.BlockID.String()), "blockID", b.BlockID, "tenantID", tenantID)
-			if b.CompactedTime.Before(cutoff) && compactorSharder.Owns(b.BlockID.String()) {
-				level.Info(rw.logger).Log("msg", "deleting block", "blockID", b.BlockID, "tenantID", tenantID)
-				err := rw.c.ClearBlock((uuid.UUID)(b.BlockID), tenantID)
+			if b.CompactedTime.Before(cutoff) && !b.IsFederated() && compactorSharder.Owns(b.BlockID.String()) {
+				if lockedUntil := rw.objectLock.LockedUntil(b.CompactedTime); now.Before(lockedUntil) {
+					level.Debug(rw.logger).Log("msg", "skipping deletion of locked block", "blockID", b.BlockID, "tenantID", tenantID, "lockedUntil", lockedUntil)
+					continue
+				}
+
+				var err error
+				if compactorCfg.TrashRetention > 0 {
+					level.Info(rw.logger).Log("msg", "moving block to trash", "blockID", b.BlockID, "tenantID", tenantID)
+					err = backend.MoveBlockToTrash(ctx, rw.rawR, rw.rawW, rw.c, (uuid.UUID)(b.BlockID), tenantID, time.Now())
+				} else {
+					level.Info(rw.logger).Log("msg", "deleting block", "blockID", b.BlockID, "tenantID", tenantID)
+					err = rw.c.ClearBlock((uuid.UUID)(b.BlockID), tenantID)
+				}
 				if err != nil {
 					level.Error(rw.logger).Log("msg", "failed to clear compacted block during retention", "blockID", b.BlockID, "tenantID", tenantID, "err", err)
 					metricRetentionErrors.Inc()
 				} else {
-					metricDeleted.Inc()
+					if compactorCfg.TrashRetention > 0 {
+						metricTrashed.Inc()
+						rw.notifyRetention(ctx, tenantID, b.BlockID, RetentionActionTrashed, &b.BlockMeta)
+					} else {
+						metricDeleted.Inc()
+						rw.notifyRetention(ctx, tenantID, b.BlockID, RetentionActionDeleted, &b.BlockMeta)
+					}
 
 					rw.blocklist.Update(tenantID, nil, nil, nil, []*backend.CompactedBlockMeta{b})
 				}
 			}
 		}
 	}
+
+	if compactorCfg.TrashRetention > 0 {
+		rw.purgeTrash(ctx, tenantID, compactorCfg.TrashRetention, compactorSharder)
+	}
+}
+
+// purgeTrash deletes the blocks of the tenant that have been in the trash for longer than the trash retention.
+func (rw *readerWriter) purgeTrash(ctx context.Context, tenantID string, trashRetention time.Duration, compactorSharder CompactorSharder) {
+	trashed, err := backend.TrashedBlocks(ctx, rw.rawR, tenantID)
+	if err != nil {
+		level.Error(rw.logger).Log("msg", "failed to list trashed blocks during retention", "tenantID", tenantID, "err", err)
+		metricRetentionErrors.Inc()
+		return
+	}
+
+	cutoff := time.Now().Add(-trashRetention)
+	for _, b := range trashed {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			if b.TrashedTime.Before(cutoff) && compactorSharder.Owns(b.BlockID.String()) {
+				level.Info(rw.logger).Log("msg", "purging block from trash", "blockID", b.BlockID, "tenantID", tenantID)
+				err := backend.PurgeTrashedBlock(ctx, rw.rawR, rw.rawW, (uuid.UUID)(b.BlockID), tenantID)
+				if err != nil {
+					level.Error(rw.logger).Log("msg", "failed to purge trashed block during retention", "blockID", b.BlockID, "tenantID", tenantID, "err", err)
+					metricRetentionErrors.Inc()
+				} else {
+					metricTrashPurged.Inc()
+					rw.notifyRetention(ctx, tenantID, b.BlockID, RetentionActionPurged, nil)
+				}
+			}
+		}
+	}
+}
+
+// archiveTenant moves the blocks of the tenant that are older than its archive age to the archive tier of the
+// backend. Blocks are only archived once by every instance, or again when the tier changes.
+func (rw *readerWriter) archiveTenant(ctx context.Context, tenantID string, compactorSharder CompactorSharder, compactorOverrides CompactorOverrides) {
+	archiveAfter, tier := compactorOverrides.BlockArchiveForTenant(tenantID)
+	if archiveAfter <= 0 {
+		return
+	}
+
+	archiver, ok := rw.c.(backend.Archiver)
+	if !ok {
+		level.Warn(rw.logger).Log("msg", "block archiving is enabled for the tenant but not supported by the backend", "tenantID", tenantID, "backend", rw.cfg.Backend)
+		return
+	}
+
+	rw.archivedBlocksMtx.Lock()
+	previous := rw.archivedBlocks[tenantID]
+	rw.archivedBlocksMtx.Unlock()
+
+	// only the blocks still in the blocklist are kept
+	archived := make(map[backend.UUID]string, len(previous))
+	defer func() {
+		rw.archivedBlocksMtx.Lock()
+		defer rw.archivedBlocksMtx.Unlock()
+		if rw.archivedBlocks == nil {
+			rw.archivedBlocks = map[string]map[backend.UUID]string{}
+		}
+		rw.archivedBlocks[tenantID] = archived
+	}()
+
+	now := time.Now()
+	for _, b := range rw.blocklist.Metas(tenantID) {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if !backend.IsBlockArchived(b, archiveAfter, now) || b.IsFederated() || !compactorSharder.Owns(b.BlockID.String()) {
+			continue
+		}
+		if previous[b.BlockID] == tier {
+			archived[b.BlockID] = tier
+			continue
+		}
+
+		level.Info(rw.logger).Log("msg", "archiving block", "blockID", b.BlockID, "tenantID", tenantID, "tier", tier)
+		err := archiver.ArchiveBlock(ctx, (uuid.UUID)(b.BlockID), tenantID, tier)
+		if errors.Is(err, backend.ErrArchivingNotSupported) {
+			level.Warn(rw.logger).Log("msg", "block archiving is enabled for the tenant but not supported by the backend", "tenantID", tenantID, "backend", rw.cfg.Backend)
+			return
+		}
+		if err != nil {
+			level.Error(rw.logger).Log("msg", "failed to archive block during retention", "blockID", b.BlockID, "tenantID", tenantID, "err", err)
+			metricRetentionErrors.Inc()
+			continue
+		}
+
+		metricArchived.Inc()
+		archived[b.BlockID] = tier
+	}
+}
+
+// rewrapTenant re-wraps the data keys of the encrypted blocks of the tenant that aren't wrapped with its current key,
+// so rotated keys can be removed once no block uses them. The data of the blocks isn't rewritten.
+func (rw *readerWriter) rewrapTenant(ctx context.Context, tenantID string, compactorSharder CompactorSharder) {
+	if rw.encryptedW == nil {
+		return
+	}
+
+	var rewrapped []*backend.BlockMeta
+	defer func() { rw.blocklist.Replace(tenantID, rewrapped) }()
+
+	for _, b := range rw.blocklist.Metas(tenantID) {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if len(b.EncryptionKey) == 0 || b.IsFederated() || !compactorSharder.Owns(b.BlockID.String()) {
+			continue
+		}
+
+		m, err := rw.encryptedW.RewrapDataKey(ctx, b)
+		if err != nil {
+			level.Error(rw.logger).Log("msg", "failed to re-wrap data key of block during retention", "blockID", b.BlockID, "tenantID", tenantID, "err", err)
+			metricRetentionErrors.Inc()
+			continue
+		}
+		if m == b {
+			continue
+		}
+
+		level.Info(rw.logger).Log("msg", "re-wrapped data key of block", "blockID", b.BlockID, "tenantID", tenantID, "keyVersion", m.EncryptionKeyVersion)
+		metricDataKeysRewrapped.Inc()
+		rewrapped = append(rewrapped, m)
+	}
+}
+
+// retentionClassesForTenant returns the retention classes of the tenant. Blocks without a class are retained for the
+// block retention of the tenant, or of the compactor if it has no override.
+func retentionClassesForTenant(tenantID string, compactorCfg *CompactorConfig, compactorOverrides CompactorOverrides) *RetentionClasses {
+	attribute, classes := compactorOverrides.BlockRetentionClassesForTenant(tenantID)
+
+	retention := compactorCfg.BlockRetention
+	if r := compactorOverrides.BlockRetentionForTenant(tenantID); r != 0 {
+		retention = r
+	}
+
+	return &RetentionClasses{
+		Attribute: attribute,
+		Retention: classes,
+		Default:   retention,
+	}
 }
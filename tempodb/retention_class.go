@@ -0,0 +1,147 @@
+package tempodb
+
+import (
+	"math"
+	"time"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+// RetentionClasses select the retention of a block by the value of a resource attribute of the traces it holds.
+// The class of a block is the class with the longest retention of its traces, so no trace is removed before its
+// own retention.
+type RetentionClasses struct {
+	// Attribute is the resource attribute whose value is the class of a trace.
+	Attribute string
+	// Retention is the retention of each class. Traces with other values or without the attribute have no class.
+	Retention map[string]time.Duration
+	// Default is the retention of traces without a class. If it is 0 the retention is unknown and is ranked above
+	// all classes.
+	Default time.Duration
+}
+
+// Enabled returns true if traces are classified.
+func (c *RetentionClasses) Enabled() bool {
+	return c != nil && c.Attribute != "" && len(c.Retention) > 0
+}
+
+// RetentionFor returns the retention of blocks of the class. Unknown classes use the default retention.
+func (c *RetentionClasses) RetentionFor(class string) time.Duration {
+	if r, ok := c.Retention[class]; ok && class != "" {
+		return r
+	}
+	return c.Default
+}
+
+// Longest returns the class of a and b with the longer retention. Unknown classes are returned as no class.
+func (c *RetentionClasses) Longest(a, b string) string {
+	a, b = c.known(a), c.known(b)
+	if a == b {
+		return a
+	}
+
+	ra, rb := c.rank(a), c.rank(b)
+	switch {
+	case ra > rb:
+		return a
+	case rb > ra:
+		return b
+	case a == "" || b == "":
+		return ""
+	case a < b:
+		return a
+	default:
+		return b
+	}
+}
+
+func (c *RetentionClasses) known(class string) string {
+	if _, ok := c.Retention[class]; ok {
+		return class
+	}
+	return ""
+}
+
+func (c *RetentionClasses) rank(class string) time.Duration {
+	if class != "" {
+		return c.Retention[class]
+	}
+	if c.Default == 0 {
+		return math.MaxInt64
+	}
+	return c.Default
+}
+
+// TraceClass returns the class of the trace. Traces with several resources take the class with the longest
+// retention.
+func (c *RetentionClasses) TraceClass(tr *tempopb.Trace) string {
+	class := ""
+	for i, rs := range tr.ResourceSpans {
+		resourceClass := ""
+		if rs.Resource != nil {
+			for _, kv := range rs.Resource.Attributes {
+				if kv.Key == c.Attribute {
+					if _, ok := c.Retention[kv.Value.GetStringValue()]; ok {
+						resourceClass = kv.Value.GetStringValue()
+					}
+					break
+				}
+			}
+		}
+
+		if i == 0 {
+			class = resourceClass
+		} else {
+			class = c.Longest(class, resourceClass)
+		}
+	}
+	return class
+}
+
+// BlockClass returns the class of a block with the given classes, e.g. from the traces of a new block or the input
+// blocks of a compaction.
+func (c *RetentionClasses) BlockClass(classes ...string) string {
+	if !c.Enabled() || len(classes) == 0 {
+		return ""
+	}
+
+	class := c.known(classes[0])
+	for _, other := range classes[1:] {
+		class = c.Longest(class, other)
+	}
+	return class
+}
+
+// RetentionClassifier accumulates the class of a block from the traces added to it.
+type RetentionClassifier struct {
+	classes *RetentionClasses
+	class   string
+	traces  int
+}
+
+// NewRetentionClassifier returns a classifier for a new block. It returns nil if the classes are not enabled.
+func NewRetentionClassifier(classes *RetentionClasses) *RetentionClassifier {
+	if !classes.Enabled() {
+		return nil
+	}
+	return &RetentionClassifier{classes: classes}
+}
+
+// Observe adds the class of the trace to the class of the block.
+func (c *RetentionClassifier) Observe(tr *tempopb.Trace) {
+	class := c.classes.TraceClass(tr)
+	if c.traces == 0 {
+		c.class = class
+	} else {
+		c.class = c.classes.Longest(c.class, class)
+	}
+	c.traces++
+}
+
+// Class returns the class of the block.
+func (c *RetentionClassifier) Class() string {
+	if c == nil {
+		return ""
+	}
+	return c.class
+}
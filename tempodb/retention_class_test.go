@@ -0,0 +1,124 @@
+package tempodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestRetentionClassesTraceClass(t *testing.T) {
+	classes := &RetentionClasses{
+		Attribute: "deployment.environment",
+		Retention: map[string]time.Duration{"prod": 30 * 24 * time.Hour, "dev": 3 * 24 * time.Hour},
+		Default:   14 * 24 * time.Hour,
+	}
+
+	tests := []struct {
+		name         string
+		environments []string
+		expected     string
+	}{
+		{name: "no resources", expected: ""},
+		{name: "one class", environments: []string{"dev"}, expected: "dev"},
+		{name: "unknown value", environments: []string{"staging"}, expected: ""},
+		{name: "longest class", environments: []string{"dev", "prod"}, expected: "prod"},
+		{name: "default longer than class", environments: []string{"dev", ""}, expected: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := &tempopb.Trace{}
+			for _, env := range tc.environments {
+				rs := &v1_trace.ResourceSpans{Resource: &v1_resource.Resource{}}
+				if env != "" {
+					rs.Resource.Attributes = []*v1_common.KeyValue{
+						{Key: "deployment.environment", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: env}}},
+					}
+				}
+				tr.ResourceSpans = append(tr.ResourceSpans, rs)
+			}
+
+			require.Equal(t, tc.expected, classes.TraceClass(tr))
+		})
+	}
+}
+
+func TestRetentionClassesLongest(t *testing.T) {
+	classes := &RetentionClasses{
+		Attribute: "deployment.environment",
+		Retention: map[string]time.Duration{"prod": 30 * 24 * time.Hour, "dev": 3 * 24 * time.Hour, "qa": 3 * 24 * time.Hour},
+		Default:   14 * 24 * time.Hour,
+	}
+
+	require.Equal(t, "prod", classes.Longest("dev", "prod"))
+	require.Equal(t, "prod", classes.Longest("", "prod"))
+	require.Equal(t, "", classes.Longest("", "dev"))
+	require.Equal(t, "dev", classes.Longest("qa", "dev"))
+	require.Equal(t, "", classes.Longest("removed", "dev"))
+
+	// an unknown default retention is longer than all classes
+	classes.Default = 0
+	require.Equal(t, "", classes.Longest("", "prod"))
+	require.Equal(t, 30*24*time.Hour, classes.RetentionFor("prod"))
+	require.Equal(t, time.Duration(0), classes.RetentionFor(""))
+}
+
+func TestRetentionClassForBlocks(t *testing.T) {
+	classes := &RetentionClasses{
+		Attribute: "deployment.environment",
+		Retention: map[string]time.Duration{"prod": 30 * 24 * time.Hour, "dev": 3 * 24 * time.Hour},
+		Default:   14 * 24 * time.Hour,
+	}
+
+	metas := func(classes ...string) []*backend.BlockMeta {
+		var metas []*backend.BlockMeta
+		for _, c := range classes {
+			metas = append(metas, &backend.BlockMeta{RetentionClass: c})
+		}
+		return metas
+	}
+
+	require.Equal(t, "dev", retentionClassForBlocks(metas("dev", "dev"), classes))
+	require.Equal(t, "prod", retentionClassForBlocks(metas("dev", "prod", ""), classes))
+	require.Equal(t, "", retentionClassForBlocks(metas("dev", ""), classes))
+
+	// classes are dropped when they are disabled
+	require.Equal(t, "", retentionClassForBlocks(metas("prod", "prod"), &RetentionClasses{}))
+}
+
+func TestRetentionClassifier(t *testing.T) {
+	require.Nil(t, NewRetentionClassifier(&RetentionClasses{}))
+	require.Equal(t, "", NewRetentionClassifier(nil).Class())
+
+	c := NewRetentionClassifier(&RetentionClasses{
+		Attribute: "deployment.environment",
+		Retention: map[string]time.Duration{"prod": 30 * 24 * time.Hour, "dev": 3 * 24 * time.Hour},
+	})
+	require.NotNil(t, c)
+
+	trace := func(env string) *tempopb.Trace {
+		return &tempopb.Trace{ResourceSpans: []*v1_trace.ResourceSpans{{
+			Resource: &v1_resource.Resource{Attributes: []*v1_common.KeyValue{
+				{Key: "deployment.environment", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: env}}},
+			}},
+		}}}
+	}
+
+	c.Observe(trace("dev"))
+	require.Equal(t, "dev", c.Class())
+	c.Observe(trace("prod"))
+	require.Equal(t, "prod", c.Class())
+	c.Observe(trace("dev"))
+	require.Equal(t, "prod", c.Class())
+
+	// the default retention is unknown so traces without a class keep the default
+	c.Observe(trace("staging"))
+	require.Equal(t, "", c.Class())
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/encoding"
@@ -83,6 +84,239 @@ func TestRetention(t *testing.T) {
 	checkBlocklists(ctx, t, (uuid.UUID)(blockID), 0, 0, rw)
 }
 
+func TestRetentionObjectLock(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 17,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncLZ4_256k,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          0,
+		CompactedBlockRetention: 0,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{}, false)
+
+	wal := w.WAL()
+	head, err := wal.NewBlock(&backend.BlockMeta{BlockID: backend.NewUUID(), TenantID: testTenantID}, model.CurrentEncoding)
+	require.NoError(t, err)
+
+	complete, err := w.CompleteBlock(ctx, head)
+	require.NoError(t, err)
+	blockID := (uuid.UUID)(complete.BlockMeta().BlockID)
+
+	rw := r.(*readerWriter)
+	rw.objectLock = &backend.ObjectLockConfig{Mode: "COMPLIANCE", Retention: time.Hour}
+	checkBlocklists(ctx, t, blockID, 1, 0, rw)
+
+	// locked blocks are marked compacted but not cleared
+	rw.doRetention(ctx)
+	rw.doRetention(ctx)
+	checkBlocklists(ctx, t, blockID, 0, 1, rw)
+
+	// and cleared once the lock expires
+	rw.objectLock.Retention = time.Nanosecond
+	rw.doRetention(ctx)
+	checkBlocklists(ctx, t, blockID, 0, 0, rw)
+}
+
+type archivingCompactor struct {
+	backend.Compactor
+	archived map[uuid.UUID][]string
+}
+
+func (c *archivingCompactor) ArchiveBlock(_ context.Context, blockID uuid.UUID, _ string, tier string) error {
+	if c.archived == nil {
+		c.archived = map[uuid.UUID][]string{}
+	}
+	c.archived[blockID] = append(c.archived[blockID], tier)
+	return nil
+}
+
+func TestRetentionArchive(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 17,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncLZ4_256k,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	overrides := &mockOverrides{archiveAfter: time.Hour, archiveTier: "Archive"}
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          365 * 24 * time.Hour,
+		CompactedBlockRetention: time.Hour,
+	}, &mockSharder{}, overrides)
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{}, false)
+
+	rw := r.(*readerWriter)
+	archiver := &archivingCompactor{Compactor: rw.c}
+	rw.c = archiver
+
+	wal := w.WAL()
+	now := time.Now()
+	for _, endTime := range []time.Time{now.Add(-2 * time.Hour), now} {
+		head, err := wal.NewBlock(&backend.BlockMeta{BlockID: backend.NewUUID(), TenantID: testTenantID}, model.CurrentEncoding)
+		require.NoError(t, err)
+
+		complete, err := w.CompleteBlock(ctx, head)
+		require.NoError(t, err)
+
+		meta := complete.BlockMeta()
+		meta.StartTime, meta.EndTime = endTime, endTime
+		require.NoError(t, rw.w.WriteBlockMeta(ctx, meta))
+	}
+	rw.pollBlocklist(ctx)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 2)
+
+	var old uuid.UUID
+	for _, m := range rw.blocklist.Metas(testTenantID) {
+		if m.EndTime.Before(now) {
+			old = (uuid.UUID)(m.BlockID)
+		}
+	}
+
+	// only the old block is archived, and only once
+	rw.doRetention(ctx)
+	rw.doRetention(ctx)
+	require.Equal(t, map[uuid.UUID][]string{old: {"Archive"}}, archiver.archived)
+
+	// it's archived again when the tier changes
+	overrides.archiveTier = "Cold"
+	rw.doRetention(ctx)
+	require.Equal(t, map[uuid.UUID][]string{old: {"Archive", "Cold"}}, archiver.archived)
+}
+
+func TestRetentionTrash(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 17,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncLZ4_256k,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          0,
+		CompactedBlockRetention: 0,
+		TrashRetention:          time.Hour,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{}, false)
+
+	wal := w.WAL()
+	head, err := wal.NewBlock(&backend.BlockMeta{BlockID: backend.NewUUID(), TenantID: testTenantID}, model.CurrentEncoding)
+	require.NoError(t, err)
+
+	complete, err := w.CompleteBlock(ctx, head)
+	require.NoError(t, err)
+	blockID := (uuid.UUID)(complete.BlockMeta().BlockID)
+
+	rw := r.(*readerWriter)
+	checkBlocklists(ctx, t, blockID, 1, 0, rw)
+
+	// first pass marks the block compacted, the second moves it to the trash
+	rw.doRetention(ctx)
+	rw.doRetention(ctx)
+	checkBlocklists(ctx, t, blockID, 0, 0, rw)
+
+	trashed, err := backend.TrashedBlocks(ctx, rw.rawR, testTenantID)
+	require.NoError(t, err)
+	require.Len(t, trashed, 1)
+	require.Equal(t, blockID, (uuid.UUID)(trashed[0].BlockID))
+
+	// a restored block is a regular block again
+	err = backend.RestoreTrashedBlock(ctx, rw.rawR, rw.rawW, blockID, testTenantID)
+	require.NoError(t, err)
+	checkBlocklists(ctx, t, blockID, 1, 0, rw)
+
+	trashed, err = backend.TrashedBlocks(ctx, rw.rawR, testTenantID)
+	require.NoError(t, err)
+	require.Empty(t, trashed)
+
+	// blocks in the trash are kept for the trash retention
+	rw.doRetention(ctx)
+	rw.doRetention(ctx)
+	checkBlocklists(ctx, t, blockID, 0, 0, rw)
+
+	trashed, err = backend.TrashedBlocks(ctx, rw.rawR, testTenantID)
+	require.NoError(t, err)
+	require.Len(t, trashed, 1)
+
+	// and purged after it
+	rw.compactorCfg.TrashRetention = time.Nanosecond
+	rw.doRetention(ctx)
+
+	trashed, err = backend.TrashedBlocks(ctx, rw.rawR, testTenantID)
+	require.NoError(t, err)
+	require.Empty(t, trashed)
+
+	var objects []string
+	err = rw.rawR.Find(ctx, backend.KeyPathForTrashedBlock(blockID, testTenantID), func(m backend.FindMatch) {
+		objects = append(objects, m.Key)
+	})
+	require.NoError(t, err)
+	require.Empty(t, objects)
+}
+
 func TestRetentionUpdatesBlocklistImmediately(t *testing.T) {
 	// Test that retention updates the in-memory blocklist
 	// immediately to reflect affected blocks and doesn't
@@ -222,6 +456,93 @@ func TestBlockRetentionOverride(t *testing.T) {
 	require.Equal(t, 0, len(rw.blocklist.Metas(testTenantID)))
 }
 
+func TestBlockRetentionClasses(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 17,
+			BloomFP:              0.01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncLZ4_256k,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	overrides := &mockOverrides{
+		blockRetention:          time.Hour,
+		retentionClassAttribute: "deployment.environment",
+		retentionClasses:        map[string]time.Duration{"prod": time.Hour, "dev": time.Nanosecond},
+	}
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10,
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          time.Hour,
+		CompactedBlockRetention: time.Hour,
+	}, &mockSharder{}, overrides)
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{}, false)
+
+	// the class of the wal block is kept when it is completed
+	classes := map[backend.UUID]string{}
+	for _, class := range []string{"prod", "dev", ""} {
+		head, err := w.WAL().NewBlock(&backend.BlockMeta{BlockID: backend.NewUUID(), TenantID: testTenantID}, model.CurrentEncoding)
+		require.NoError(t, err)
+		head.BlockMeta().RetentionClass = class
+
+		id := test.ValidTraceID(nil)
+		now := uint32(time.Now().Unix())
+		writeTraceToWal(t, head, model.MustNewSegmentDecoder(model.CurrentEncoding), id, test.MakeTrace(1, id), now, now)
+
+		complete, err := w.CompleteBlock(ctx, head)
+		require.NoError(t, err)
+		require.Equal(t, class, complete.BlockMeta().RetentionClass)
+		classes[complete.BlockMeta().BlockID] = class
+	}
+
+	time.Sleep(time.Second)
+
+	rw := r.(*readerWriter)
+	rw.pollBlocklist(ctx)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 3)
+
+	remaining := func() []string {
+		var remaining []string
+		for _, m := range rw.blocklist.Metas(testTenantID) {
+			require.Equal(t, classes[m.BlockID], m.RetentionClass)
+			remaining = append(remaining, m.RetentionClass)
+		}
+		return remaining
+	}
+
+	// the dev class is past its retention
+	rw.doRetention(ctx)
+	require.ElementsMatch(t, []string{"prod", ""}, remaining())
+
+	// blocks without a class use the tenant retention
+	overrides.blockRetention = time.Nanosecond
+	rw.doRetention(ctx)
+	require.ElementsMatch(t, []string{"prod"}, remaining())
+
+	// without classes all blocks use the tenant retention
+	overrides.retentionClassAttribute = ""
+	rw.doRetention(ctx)
+	require.Empty(t, remaining())
+}
+
 func TestBlockRetentionOverrideDisabled(t *testing.T) {
 	tempDir := t.TempDir()
 
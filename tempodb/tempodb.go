@@ -6,6 +6,9 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/grafana/tempo/pkg/collector"
@@ -42,6 +45,10 @@ const (
 	BlockIDMin = "00000000-0000-0000-0000-000000000000"
 	// BlockIDMax is the maximum possible value for a block id as a string
 	BlockIDMax = "FFFFFFFF-FFFF-FFFF-FFFF-FFFFFFFFFFFF"
+
+	// staleBlocklistPolls is the number of poll cycles without a successful poll after which the blocklist is stale if
+	// no stale threshold is configured
+	staleBlocklistPolls = 3
 )
 
 var (
@@ -69,12 +76,48 @@ var (
 		Name:      "retention_deleted_total",
 		Help:      "Total number of blocks deleted.",
 	})
+	metricTrashed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "retention_trashed_total",
+		Help:      "Total number of blocks moved to the trash.",
+	})
+	metricTrashPurged = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "retention_trash_purged_total",
+		Help:      "Total number of blocks purged from the trash.",
+	})
+	metricArchived = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "retention_archived_total",
+		Help:      "Total number of blocks moved to an archive storage tier.",
+	})
+	metricDataKeysRewrapped = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "encryption_data_keys_rewrapped_total",
+		Help:      "Total number of data keys of blocks re-wrapped with the current key of their tenant.",
+	})
+	metricBlocksWritten = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "blocks_written_total",
+		Help:      "Total number of blocks written to the backend by version, flushed or created by compaction.",
+	}, []string{"tenant", "version", "source"})
+)
+
+const (
+	blocksWrittenSourceFlush      = "flush"
+	blocksWrittenSourceCompaction = "compaction"
 )
 
+func recordBlocksWritten(source string, metas ...*backend.BlockMeta) {
+	for _, meta := range metas {
+		metricBlocksWritten.WithLabelValues(meta.TenantID, meta.Version, source).Inc()
+	}
+}
+
 type Writer interface {
 	WriteBlock(ctx context.Context, block WriteableBlock) error
 	CompleteBlock(ctx context.Context, block common.WALBlock) (common.BackendBlock, error)
-	CompleteBlockWithBackend(ctx context.Context, block common.WALBlock, r backend.Reader, w backend.Writer) (common.BackendBlock, error)
+	CompleteBlockWithBackend(ctx context.Context, block common.WALBlock, enc common.BlockEncoding, r backend.Reader, w backend.Writer) (common.BackendBlock, error)
 	DeleteNoCompactFlag(ctx context.Context, tenantID string, blockID backend.UUID) error
 	WAL() *wal.WAL
 }
@@ -83,6 +126,9 @@ type IterateObjectCallback func(id common.ID, obj []byte) bool
 
 type Reader interface {
 	Find(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64, opts common.SearchOptions) ([]*tempopb.TraceByIDResponse, []error, error)
+	// FindMany finds several traces with a single pass over each block. The partial traces of each id are returned in
+	// the order of ids.
+	FindMany(ctx context.Context, tenantID string, ids []common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64, opts common.SearchOptions) ([][]*tempopb.Trace, *tempopb.TraceByIDMetrics, []error, error)
 	Search(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchRequest, opts common.SearchOptions) (*tempopb.SearchResponse, error)
 	SearchTags(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchTagsBlockRequest, opts common.SearchOptions) (*tempopb.SearchTagsV2Response, error)
 	SearchTagValues(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchTagValuesBlockRequest, opts common.SearchOptions) (*tempopb.SearchTagValuesResponse, error)
@@ -95,12 +141,22 @@ type Reader interface {
 
 	BlockMeta(ctx context.Context, tenantID string, blockID backend.UUID) (*backend.BlockMeta, *backend.CompactedBlockMeta, error)
 	BlockMetas(tenantID string) []*backend.BlockMeta
+	// BlockMetasInRange returns only the block metas of the tenant that overlap the time range.
+	BlockMetasInRange(tenantID string, start, end time.Time) []*backend.BlockMeta
+
+	// ValidateBlock reads the entire block and returns an error if it is damaged or incomplete.
+	ValidateBlock(ctx context.Context, tenantID string, blockID backend.UUID) error
 
 	Tenants() []string
 
 	// EnablePolling in the background of the blocklists, with the given ownership of tenants.
 	EnablePolling(ctx context.Context, sharder blocklist.JobSharder, skipNoCompactBlocks bool)
 
+	// BlocklistStale returns true if polling is enabled and the blocklist, or the blocklist of the tenant, wasn't
+	// polled successfully within the stale threshold, recent blocks may then be missing from it. Queries still use
+	// it, so they miss at most the blocks written since its last successful poll.
+	BlocklistStale(tenantID string) bool
+
 	// PollNow does an immediate poll of the blocklist and is for testing purposes. Must have already called EnablePolling.
 	PollNow(ctx context.Context)
 
@@ -113,6 +169,17 @@ type Compactor interface {
 	CompactWithConfig(ctx context.Context, metas []*backend.BlockMeta, tenantID string, cfg *CompactorConfig, sharder CompactorSharder, overrides CompactorOverrides) ([]*backend.BlockMeta, error)
 	MarkBlocklistCompacted(tenantID string, outputIDs, inputIDs []*backend.BlockMeta) error
 	RetainWithConfig(ctx context.Context, cfg *CompactorConfig, sharder CompactorSharder, overrides CompactorOverrides)
+	// AddTenantLifecycleListener registers a listener for empty tenant deletion. Must be called before EnablePolling.
+	AddTenantLifecycleListener(l blocklist.TenantLifecycleListener)
+	// AddCompactionListener registers a listener for finished compactions and retention. Must be called before
+	// compaction and retention start.
+	AddCompactionListener(l CompactionListener)
+	// TenantOwnership returns which instances own the index building and compaction jobs of each tenant.
+	TenantOwnership() []TenantOwnership
+	// RedactBlock rewrites the block replacing the values of the redaction and marks the original block compacted.
+	RedactBlock(ctx context.Context, meta *backend.BlockMeta, redaction *common.AttributeRedaction) (*backend.BlockMeta, int, error)
+	// RecommendDedicatedColumns recommends dedicated columns for the tenant from the attributes of its most recent blocks.
+	RecommendDedicatedColumns(ctx context.Context, tenantID string, blocks int) (*backend.DedicatedColumnsRecommendation, error)
 }
 
 type CompactorSharder interface {
@@ -126,6 +193,17 @@ type CompactorOverrides interface {
 	CompactionDisabledForTenant(tenantID string) bool
 	MaxBytesPerTraceForTenant(tenantID string) int
 	MaxCompactionRangeForTenant(tenantID string) time.Duration
+	StorageAttributePolicyForTenant(tenantID string) common.AttributePolicy
+	ConvertV2BlocksForTenant(tenantID string) bool
+	DedicatedColumnsForTenant(tenantID string) backend.DedicatedColumns
+	BlockRetentionClassesForTenant(tenantID string) (string, map[string]time.Duration)
+	// BlockArchiveForTenant returns the age after which blocks are archived and the storage tier they are archived to.
+	BlockArchiveForTenant(tenantID string) (time.Duration, string)
+	// BlockEncodingForTenant returns the encoding overrides of the blocks created by compaction.
+	BlockEncodingForTenant(tenantID string) common.BlockEncoding
+	// DedicatedColumnsAutoApplyForTenant returns true if the blocks created by compaction use the dedicated columns
+	// recommended for the tenant.
+	DedicatedColumnsAutoApplyForTenant(tenantID string) bool
 }
 
 type WriteableBlock interface {
@@ -136,9 +214,20 @@ type WriteableBlock interface {
 var _ Reader = (*readerWriter)(nil)
 
 type readerWriter struct {
-	r backend.Reader
-	w backend.Writer
-	c backend.Compactor
+	r    backend.Reader
+	w    backend.Writer
+	c    backend.Compactor
+	rawR backend.RawReader
+	rawW backend.RawWriter
+
+	// encryptedW re-wraps the data keys of encrypted blocks, nil if encryption is disabled
+	encryptedW *backend.EncryptedWriter
+
+	// versioned bypasses the cache and is used for conditional writes
+	versioned backend.VersionedReaderWriter
+
+	// objectLock is the object lock of the backend, nil if it doesn't support one
+	objectLock *backend.ObjectLockConfig
 
 	wal  *wal.WAL
 	pool *pool.Pool
@@ -148,13 +237,44 @@ type readerWriter struct {
 
 	blocklistPoller *blocklist.Poller
 	blocklist       *blocklist.List
+	// lastPoll is the time of the last successful poll of the blocklist, in unix nanoseconds
+	lastPoll atomic.Int64
 
 	compactorCfg          *CompactorConfig
 	compactorSharder      CompactorSharder
 	compactorOverrides    CompactorOverrides
 	compactorTenantOffset uint
 
+	// archivedBlocks are the tiers of the blocks already archived by this instance, by tenant
+	archivedBlocksMtx sync.Mutex
+	archivedBlocks    map[string]map[backend.UUID]string
+
+	// compactionOwnership is the compaction jobs of the last cycle of each tenant, see TenantOwnership
+	compactionOwnershipMtx sync.Mutex
+	compactionOwnership    map[string]*TenantCompactionOwnership
+
+	// dualWriter writes flushed blocks to a second storage, nil if dual write is disabled
+	dualWriter *dualWriter
+
+	// tenantIndexReplica is the replica tenant indexes are written to, nil if replication is disabled
+	tenantIndexReplica backend.Writer
+
+	// federation reads from the federated buckets next to the trace storage, nil if there are none
+	federation *backend.Federation
+
+	// tiered mirrors the trace storage to a local disk, nil if tiering is disabled. stopTiered stops its eviction.
+	tiered     *backend.Tiered
+	stopTiered context.CancelFunc
+
+	// partitionedBlocks are the storage tenants of the blocks of tenant partitions in the blocklist
+	partitionedBlocksMtx sync.RWMutex
+	partitionedBlocks    map[backend.UUID]string
+
 	pollerShutdownCh chan struct{}
+	tenantListeners  []blocklist.TenantLifecycleListener
+	// compactionListeners are notified of finished compactions and retention
+	compactionListeners []CompactionListener
+	inventory           *blocklist.InventoryReader
 }
 
 // New creates a new tempodb
@@ -162,6 +282,7 @@ func New(cfg *Config, cacheProvider cache.Provider, logger gkLog.Logger) (Reader
 	var rawR backend.RawReader
 	var rawW backend.RawWriter
 	var c backend.Compactor
+	var objectLock *backend.ObjectLockConfig
 
 	err := validateConfig(cfg)
 	if err != nil {
@@ -173,10 +294,13 @@ func New(cfg *Config, cacheProvider cache.Provider, logger gkLog.Logger) (Reader
 		rawR, rawW, c, err = local.New(cfg.Local)
 	case backend.GCS:
 		rawR, rawW, c, err = gcs.New(cfg.GCS)
+		objectLock = &cfg.GCS.ObjectLock
 	case backend.S3:
 		rawR, rawW, c, err = s3.New(cfg.S3)
+		objectLock = &cfg.S3.ObjectLock
 	case backend.Azure:
 		rawR, rawW, c, err = azure.New(cfg.Azure)
+		objectLock = &cfg.Azure.ObjectLock
 	default:
 		err = fmt.Errorf("unknown backend %s", cfg.Backend)
 	}
@@ -185,6 +309,32 @@ func New(cfg *Config, cacheProvider cache.Provider, logger gkLog.Logger) (Reader
 		return nil, nil, nil, err
 	}
 
+	// versioned objects are written to the trace storage, the federated backend doesn't write
+	versioned, ok := rawR.(backend.VersionedReaderWriter)
+	if !ok {
+		versioned = backend.NewFakeVersionedReaderWriter(rawR, rawW)
+	}
+
+	// recent blocks are mirrored to and read from a local disk
+	var tiered *backend.Tiered
+	if cfg.Tiered.Enabled() {
+		tiered, err = newTiered(cfg, rawR, rawW, c)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		rawR, rawW, c = tiered, tiered, tiered
+	}
+
+	// blocks are listed in and read from the federated buckets as well, they are tagged with their bucket when polled
+	var federation *backend.Federation
+	if len(cfg.FederatedBuckets) > 0 {
+		federation, err = newFederation(cfg, rawR, c)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		rawR, c = federation, federation
+	}
+
 	// build a caching layer if we have a provider
 	if cacheProvider != nil {
 		legacyCache, roles, err := createLegacyCache(cfg, logger)
@@ -206,16 +356,48 @@ func New(cfg *Config, cacheProvider cache.Provider, logger gkLog.Logger) (Reader
 		}
 	}
 
-	r := backend.NewReader(rawR)
-	w := backend.NewWriter(rawW)
+	var r backend.Reader = backend.NewReaderWithBlockMetaCache(rawR, cfg.BlocklistPollBlockMetaCacheSize)
+	var w backend.Writer = backend.NewWriter(rawW)
+	var encryptedW *backend.EncryptedWriter
+	if cfg.Encryption.Enabled() {
+		kms, err := NewKMS(&cfg.Encryption)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("error creating encryption kms: %w", err)
+		}
+		r, encryptedW = backend.NewEncryptedReaderWriter(r, w, kms)
+		w = encryptedW
+	}
 	rw := &readerWriter{
-		c:         c,
-		r:         r,
-		w:         w,
-		cfg:       cfg,
-		logger:    logger,
-		pool:      pool.NewPool(cfg.Pool),
-		blocklist: blocklist.New(),
+		c:          c,
+		r:          r,
+		w:          w,
+		encryptedW: encryptedW,
+		rawR:       rawR,
+		rawW:       rawW,
+		versioned:  versioned,
+		objectLock: objectLock,
+		cfg:        cfg,
+		logger:     logger,
+		pool:       pool.NewPool(cfg.Pool),
+		blocklist:  blocklist.New(),
+		inventory:  blocklist.NewInventoryReader(cfg.BlocklistPollInventory, rawR),
+		federation: federation,
+		tiered:     tiered,
+	}
+
+	if cfg.DualWrite.Enabled() {
+		rw.dualWriter, err = newDualWriter(cfg, logger)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	if replica := cfg.BlocklistPollTenantIndexReplica; replica.Enabled() {
+		_, replicaW, err := newSecondaryBackend(replica.Backend, replica.Local, replica.GCS, replica.S3, replica.Azure)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("error creating tenant index replica backend: %w", err)
+		}
+		rw.tenantIndexReplica = backend.NewWriter(replicaW)
 	}
 
 	rw.wal, err = wal.New(rw.cfg.WAL)
@@ -223,23 +405,41 @@ func New(cfg *Config, cacheProvider cache.Provider, logger gkLog.Logger) (Reader
 		return nil, nil, nil, err
 	}
 
+	if tiered != nil {
+		var ctx context.Context
+		ctx, rw.stopTiered = context.WithCancel(context.Background())
+		go rw.tieredEvictionLoop(ctx)
+	}
+
 	return rw, rw, rw, nil
 }
 
 func (rw *readerWriter) WriteBlock(ctx context.Context, c WriteableBlock) error {
-	return c.Write(ctx, rw.w)
+	err := c.Write(ctx, rw.partitionWriter(c.BlockMeta()))
+	if err != nil {
+		return err
+	}
+	recordBlocksWritten(blocksWrittenSourceFlush, c.BlockMeta())
+
+	if rw.dualWriter != nil {
+		rw.dualWriter.write(ctx, c)
+	}
+
+	return nil
 }
 
 // CompleteBlock iterates the given WAL block and flushes it to the TempoDB backend.
 func (rw *readerWriter) CompleteBlock(ctx context.Context, block common.WALBlock) (common.BackendBlock, error) {
-	return rw.CompleteBlockWithBackend(ctx, block, rw.r, rw.w)
+	return rw.CompleteBlockWithBackend(ctx, block, common.BlockEncoding{}, rw.r, rw.w)
 }
 
 // CompleteBlockWithBackend iterates the given WAL block but flushes it to the given backend instead of the default TempoDB backend. The
-// new block will have the same ID as the input block.
-func (rw *readerWriter) CompleteBlockWithBackend(ctx context.Context, block common.WALBlock, r backend.Reader, w backend.Writer) (common.BackendBlock, error) {
+// new block will have the same ID as the input block and is created with the encoding overrides of the tenant applied.
+func (rw *readerWriter) CompleteBlockWithBackend(ctx context.Context, block common.WALBlock, enc common.BlockEncoding, r backend.Reader, w backend.Writer) (common.BackendBlock, error) {
+	blockCfg := enc.ApplyTo(*rw.cfg.Block)
+
 	// The destination block format:
-	vers, err := encoding.FromVersion(rw.cfg.Block.Version)
+	vers, err := encoding.FromVersion(blockCfg.Version)
 	if err != nil {
 		return nil, err
 	}
@@ -267,16 +467,29 @@ func (rw *readerWriter) CompleteBlockWithBackend(ctx context.Context, block comm
 		EndTime:          walMeta.EndTime,
 		DataEncoding:     walMeta.DataEncoding,
 		DedicatedColumns: walMeta.DedicatedColumns,
+		RetentionClass:   walMeta.RetentionClass,
 
 		// Other
-		Encoding: rw.cfg.Block.Encoding,
+		Encoding: blockCfg.Encoding,
 	}
 
-	newMeta, err := vers.CreateBlock(ctx, rw.cfg.Block, inMeta, iter, r, w)
+	newMeta, err := vers.CreateBlock(ctx, &blockCfg, inMeta, iter, r, w)
 	if err != nil {
 		return nil, fmt.Errorf("error creating block: %w", err)
 	}
 
+	// blocks completed directly into the trace storage are never written with WriteBlock, and blocks with
+	// another version than the dual write storage can only be converted from the wal
+	if rw.dualWriter != nil {
+		toTrace := w == rw.w
+		if toTrace || rw.dualWriter.version != blockCfg.Version {
+			rw.dualWriter.convert(ctx, block, inMeta, !toTrace)
+		}
+		if toTrace {
+			metricDualWriteObjects.WithLabelValues(newMeta.TenantID, dualWriteTargetPrimary).Add(float64(newMeta.TotalObjects))
+		}
+	}
+
 	backendBlock, err := encoding.OpenBlock(newMeta, r)
 	if err != nil {
 		return nil, fmt.Errorf("error opening new block: %w", err)
@@ -294,6 +507,7 @@ func (rw *readerWriter) WAL() *wal.WAL {
 }
 
 func (rw *readerWriter) BlockMeta(ctx context.Context, tenantID string, blockID backend.UUID) (*backend.BlockMeta, *backend.CompactedBlockMeta, error) {
+	tenantID = rw.blockStorageTenant(tenantID, blockID)
 	meta, err := rw.r.BlockMeta(ctx, (uuid.UUID)(blockID), tenantID)
 	if err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
 		return nil, nil, err
@@ -312,7 +526,25 @@ func (rw *readerWriter) BlockMeta(ctx context.Context, tenantID string, blockID
 }
 
 func (rw *readerWriter) BlockMetas(tenantID string) []*backend.BlockMeta {
-	return rw.blocklist.Metas(tenantID)
+	return rw.tenantMetas(tenantID)
+}
+
+func (rw *readerWriter) BlockMetasInRange(tenantID string, start, end time.Time) []*backend.BlockMeta {
+	return rw.tenantMetasInRange(tenantID, start, end)
+}
+
+func (rw *readerWriter) ValidateBlock(ctx context.Context, tenantID string, blockID backend.UUID) error {
+	meta, err := rw.r.BlockMeta(ctx, (uuid.UUID)(blockID), rw.blockStorageTenant(tenantID, blockID))
+	if err != nil {
+		return fmt.Errorf("error reading block meta (%s, %s): %w", tenantID, blockID, err)
+	}
+
+	block, err := encoding.OpenBlock(meta, rw.r)
+	if err != nil {
+		return err
+	}
+
+	return block.Validate(ctx)
 }
 
 func (rw *readerWriter) Tenants() []string {
@@ -342,9 +574,16 @@ func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID,
 		return nil, nil, err
 	}
 
-	// gather appropriate blocks
-	blocklist := rw.blocklist.Metas(tenantID)
-	compactedBlocklist := rw.blocklist.CompactedMetas(tenantID)
+	// gather appropriate blocks, includeBlock checks the exact time range
+	var blocklist []*backend.BlockMeta
+	var compactedBlocklist []*backend.CompactedBlockMeta
+	if timeStart != 0 && timeEnd != 0 {
+		blocklist = rw.tenantMetasInRange(tenantID, time.Unix(timeStart, 0), time.Unix(timeEnd, 0))
+		compactedBlocklist = rw.tenantCompactedMetasInRange(tenantID, time.Unix(timeStart, 0), time.Unix(timeEnd, 0))
+	} else {
+		blocklist = rw.tenantMetas(tenantID)
+		compactedBlocklist = rw.tenantCompactedMetas(tenantID)
+	}
 	copiedBlocklist := make([]interface{}, 0, len(blocklist))
 	blocksSearched := 0
 	compactedBlocksSearched := 0
@@ -404,10 +643,128 @@ func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID,
 	return partialTraceObjs, funcErrs, err
 }
 
+// findManyJob are the ids a block may contain, and their index in the ids of the request.
+type findManyJob struct {
+	meta *backend.BlockMeta
+	ids  []common.ID
+	idxs []int
+}
+
+// findManyResult are the traces found in a block, in the order of the ids of its job.
+type findManyResult struct {
+	job     *findManyJob
+	traces  []*tempopb.Trace
+	metrics *tempopb.TraceByIDMetrics
+}
+
+func (rw *readerWriter) FindMany(ctx context.Context, tenantID string, ids []common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64, opts common.SearchOptions) ([][]*tempopb.Trace, *tempopb.TraceByIDMetrics, []error, error) {
+	ctx, span := tracer.Start(ctx, "store.FindMany")
+	defer span.End()
+
+	blockStartUUID, err := uuid.Parse(blockStart)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	blockStartBytes, err := blockStartUUID.MarshalBinary()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	blockEndUUID, err := uuid.Parse(blockEnd)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	blockEndBytes, err := blockEndUUID.MarshalBinary()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var blocklist []*backend.BlockMeta
+	var compactedBlocklist []*backend.CompactedBlockMeta
+	if timeStart != 0 && timeEnd != 0 {
+		blocklist = rw.tenantMetasInRange(tenantID, time.Unix(timeStart, 0), time.Unix(timeEnd, 0))
+		compactedBlocklist = rw.tenantCompactedMetasInRange(tenantID, time.Unix(timeStart, 0), time.Unix(timeEnd, 0))
+	} else {
+		blocklist = rw.tenantMetas(tenantID)
+		compactedBlocklist = rw.tenantCompactedMetas(tenantID)
+	}
+
+	// every block is searched once for all the ids it may contain
+	var jobs []interface{}
+	addJob := func(meta *backend.BlockMeta, include func(id common.ID) bool) {
+		job := &findManyJob{meta: meta}
+		for i, id := range ids {
+			if include(id) {
+				job.ids = append(job.ids, id)
+				job.idxs = append(job.idxs, i)
+			}
+		}
+		if len(job.ids) > 0 {
+			jobs = append(jobs, job)
+		}
+	}
+	for _, b := range blocklist {
+		addJob(b, func(id common.ID) bool {
+			return includeBlock(b, id, blockStartBytes, blockEndBytes, timeStart, timeEnd, opts.RF1After)
+		})
+	}
+	for _, c := range compactedBlocklist {
+		addJob(&c.BlockMeta, func(id common.ID) bool {
+			return includeCompactedBlock(c, id, blockStartBytes, blockEndBytes, rw.cfg.BlocklistPoll, timeStart, timeEnd, opts.RF1After)
+		})
+	}
+
+	span.SetAttributes(attribute.Int("ids", len(ids)), attribute.Int("blocksSearched", len(jobs)))
+
+	traces := make([][]*tempopb.Trace, len(ids))
+	metrics := &tempopb.TraceByIDMetrics{}
+	if len(jobs) == 0 {
+		return traces, metrics, nil, nil
+	}
+
+	if rw.cfg != nil && rw.cfg.Search != nil {
+		rw.cfg.Search.ApplyToOptions(&opts)
+	}
+
+	results, funcErrs, err := rw.pool.RunJobs(ctx, jobs, func(ctx context.Context, payload interface{}) (interface{}, error) {
+		job := payload.(*findManyJob)
+		block, err := encoding.OpenBlock(job.meta, rw.r)
+		if err != nil {
+			return nil, fmt.Errorf("error opening block for reading, blockID: %s: %w", job.meta.BlockID.String(), err)
+		}
+
+		found, blockMetrics, err := block.FindTraceByIDs(ctx, job.ids, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error finding traces by ids, blockID: %s: %w", job.meta.BlockID.String(), err)
+		}
+
+		return &findManyResult{job: job, traces: found, metrics: blockMetrics}, nil
+	})
+
+	for _, r := range results {
+		res, ok := r.(*findManyResult)
+		if !ok || res == nil {
+			continue
+		}
+		if res.metrics != nil {
+			metrics.InspectedBytes += res.metrics.InspectedBytes
+		}
+		for i, tr := range res.traces {
+			if tr != nil {
+				idx := res.job.idxs[i]
+				traces[idx] = append(traces[idx], tr)
+			}
+		}
+	}
+
+	span.SetAttributes(attribute.Int("blockErrs", len(funcErrs)))
+
+	return traces, metrics, funcErrs, err
+}
+
 // Search the given block.  This method takes the pre-loaded block meta instead of a block ID, which
 // eliminates a read per search request.
 func (rw *readerWriter) Search(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchRequest, opts common.SearchOptions) (*tempopb.SearchResponse, error) {
-	block, err := encoding.OpenBlock(meta, rw.r)
+	block, err := encoding.OpenBlock(rw.storageMeta(meta), rw.r)
 	if err != nil {
 		return nil, err
 	}
@@ -424,7 +781,7 @@ func (rw *readerWriter) SearchTags(ctx context.Context, meta *backend.BlockMeta,
 		return nil, fmt.Errorf("unknown scope: %s", scope)
 	}
 
-	block, err := encoding.OpenBlock(meta, rw.r)
+	block, err := encoding.OpenBlock(rw.storageMeta(meta), rw.r)
 	if err != nil {
 		return nil, err
 	}
@@ -462,7 +819,7 @@ func (rw *readerWriter) SearchTags(ctx context.Context, meta *backend.BlockMeta,
 }
 
 func (rw *readerWriter) SearchTagValues(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchTagValuesBlockRequest, opts common.SearchOptions) (response *tempopb.SearchTagValuesResponse, err error) {
-	block, err := encoding.OpenBlock(meta, rw.r)
+	block, err := encoding.OpenBlock(rw.storageMeta(meta), rw.r)
 	if err != nil {
 		return &tempopb.SearchTagValuesResponse{}, err
 	}
@@ -484,7 +841,7 @@ func (rw *readerWriter) SearchTagValues(ctx context.Context, meta *backend.Block
 }
 
 func (rw *readerWriter) SearchTagValuesV2(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchTagValuesRequest, opts common.SearchOptions) (*tempopb.SearchTagValuesV2Response, error) {
-	block, err := encoding.OpenBlock(meta, rw.r)
+	block, err := encoding.OpenBlock(rw.storageMeta(meta), rw.r)
 	if err != nil {
 		return nil, err
 	}
@@ -494,7 +851,10 @@ func (rw *readerWriter) SearchTagValuesV2(ctx context.Context, meta *backend.Blo
 		return nil, err
 	}
 
-	dv := collector.NewDistinctValue(0, req.MaxTagValues, req.StaleValueThreshold, func(v tempopb.TagValue) int { return len(v.Type) + len(v.Value) })
+	dv, err := collector.NewTagValuesV2(0, req)
+	if err != nil {
+		return nil, err
+	}
 	mc := collector.NewMetricsCollector()
 	rw.cfg.Search.ApplyToOptions(&opts)
 	err = block.SearchTagValuesV2(ctx, tag, traceql.MakeCollectTagValueFunc(dv.Collect), mc.Add, opts)
@@ -508,7 +868,8 @@ func (rw *readerWriter) SearchTagValuesV2(ctx context.Context, meta *backend.Blo
 	}
 
 	resp := &tempopb.SearchTagValuesV2Response{
-		Metrics: &tempopb.MetadataMetrics{InspectedBytes: mc.TotalValue()},
+		Metrics:   &tempopb.MetadataMetrics{InspectedBytes: mc.TotalValue()},
+		NextToken: collector.NextTagValuesToken(dv),
 	}
 	for _, v := range dv.Values() {
 		v2 := v
@@ -520,7 +881,7 @@ func (rw *readerWriter) SearchTagValuesV2(ctx context.Context, meta *backend.Blo
 
 // Fetch only uses rw.r which has caching enabled
 func (rw *readerWriter) Fetch(ctx context.Context, meta *backend.BlockMeta, req traceql.FetchSpansRequest, opts common.SearchOptions) (traceql.FetchSpansResponse, error) {
-	block, err := encoding.OpenBlock(meta, rw.r)
+	block, err := encoding.OpenBlock(rw.storageMeta(meta), rw.r)
 	if err != nil {
 		return traceql.FetchSpansResponse{}, err
 	}
@@ -530,7 +891,7 @@ func (rw *readerWriter) Fetch(ctx context.Context, meta *backend.BlockMeta, req
 }
 
 func (rw *readerWriter) FetchTagValues(ctx context.Context, meta *backend.BlockMeta, req traceql.FetchTagValuesRequest, cb traceql.FetchTagValuesCallback, mcb common.MetricsCallback, opts common.SearchOptions) error {
-	block, err := encoding.OpenBlock(meta, rw.r)
+	block, err := encoding.OpenBlock(rw.storageMeta(meta), rw.r)
 	if err != nil {
 		return err
 	}
@@ -540,7 +901,7 @@ func (rw *readerWriter) FetchTagValues(ctx context.Context, meta *backend.BlockM
 }
 
 func (rw *readerWriter) FetchTagNames(ctx context.Context, meta *backend.BlockMeta, req traceql.FetchTagsRequest, cb traceql.FetchTagsCallback, mcb common.MetricsCallback, opts common.SearchOptions) error {
-	block, err := encoding.OpenBlock(meta, rw.r)
+	block, err := encoding.OpenBlock(rw.storageMeta(meta), rw.r)
 	if err != nil {
 		return err
 	}
@@ -554,8 +915,14 @@ func (rw *readerWriter) Shutdown() {
 	if rw.pollerShutdownCh != nil {
 		<-rw.pollerShutdownCh
 	}
+	if rw.stopTiered != nil {
+		rw.stopTiered()
+	}
 	rw.pool.Shutdown()
 	rw.r.Shutdown()
+	if rw.dualWriter != nil {
+		rw.dualWriter.shutdown()
+	}
 }
 
 // EnableCompaction activates the compaction/retention loops
@@ -573,7 +940,7 @@ func (rw *readerWriter) EnableCompaction(ctx context.Context, cfg *CompactorConf
 
 	rw.compactorCfg = cfg
 	rw.compactorSharder = c
-	rw.compactorOverrides = overrides
+	rw.compactorOverrides = withPartitionOverrides(overrides)
 
 	if rw.cfg.BlocklistPoll == 0 {
 		level.Info(rw.logger).Log("msg", "polling cycle unset. compaction and retention disabled")
@@ -584,6 +951,12 @@ func (rw *readerWriter) EnableCompaction(ctx context.Context, cfg *CompactorConf
 		level.Info(rw.logger).Log("msg", "compaction and retention enabled.")
 		go rw.compactionLoop(ctx)
 		go rw.retentionLoop(ctx)
+		if cfg.UsageReport.Enabled() {
+			go rw.usageReportLoop(ctx)
+		}
+		if cfg.BlockRepair.Enabled() {
+			go rw.blockRepairLoop(ctx)
+		}
 	}
 
 	return nil
@@ -621,22 +994,62 @@ func (rw *readerWriter) EnablePolling(ctx context.Context, sharder blocklist.Job
 		rw.cfg.EmptyTenantDeletionAge = DefaultEmptyTenantDeletionAge
 	}
 
+	if rw.cfg.EmptyTenantDeletionConcurrency == 0 {
+		rw.cfg.EmptyTenantDeletionConcurrency = DefaultEmptyTenantDeletionConcurrency
+	}
+
 	level.Info(rw.logger).Log("msg", "polling enabled", "interval", rw.cfg.BlocklistPoll, "blocklist_concurrency", rw.cfg.BlocklistPollConcurrency)
 
 	blocklistPoller := blocklist.NewPoller(&blocklist.PollerConfig{
-		PollConcurrency:            rw.cfg.BlocklistPollConcurrency,
-		PollFallback:               rw.cfg.BlocklistPollFallback,
-		TenantIndexBuilders:        rw.cfg.BlocklistPollTenantIndexBuilders,
-		StaleTenantIndex:           rw.cfg.BlocklistPollStaleTenantIndex,
-		PollJitterMs:               rw.cfg.BlocklistPollJitterMs,
-		TolerateConsecutiveErrors:  rw.cfg.BlocklistPollTolerateConsecutiveErrors,
-		TolerateTenantFailures:     rw.cfg.BlocklistPollTolerateTenantFailures,
-		TenantPollConcurrency:      rw.cfg.BlocklistPollTenantConcurrency,
-		EmptyTenantDeletionAge:     rw.cfg.EmptyTenantDeletionAge,
-		EmptyTenantDeletionEnabled: rw.cfg.EmptyTenantDeletionEnabled,
-		SkipNoCompactBlocks:        skipNoCompactBlocks,
+		PollConcurrency:                      rw.cfg.BlocklistPollConcurrency,
+		PollFallback:                         rw.cfg.BlocklistPollFallback,
+		TenantIndexBuilders:                  rw.cfg.BlocklistPollTenantIndexBuilders,
+		StaleTenantIndex:                     rw.cfg.BlocklistPollStaleTenantIndex,
+		PollJitterMs:                         rw.cfg.BlocklistPollJitterMs,
+		TolerateConsecutiveErrors:            rw.cfg.BlocklistPollTolerateConsecutiveErrors,
+		TolerateTenantFailures:               rw.cfg.BlocklistPollTolerateTenantFailures,
+		TenantPollConcurrency:                rw.cfg.BlocklistPollTenantConcurrency,
+		EmptyTenantDeletionAge:               rw.cfg.EmptyTenantDeletionAge,
+		EmptyTenantDeletionEnabled:           rw.cfg.EmptyTenantDeletionEnabled,
+		EmptyTenantDeletionConcurrency:       rw.cfg.EmptyTenantDeletionConcurrency,
+		EmptyTenantDeletionMaxObjects:        rw.cfg.EmptyTenantDeletionMaxObjects,
+		EmptyTenantDeletionMaxTenantsPerPoll: rw.cfg.EmptyTenantDeletionMaxTenantsPerPoll,
+		SkipNoCompactBlocks:                  skipNoCompactBlocks,
+		BackendCallTimeout:                   rw.cfg.BlocklistPollBackendCallTimeout,
+		BackendCallSlowThreshold:             rw.cfg.BlocklistPollBackendCallSlowThreshold,
+		BackendCallDeadlineAudit:             rw.cfg.BlocklistPollDeadlineAudit,
+		IndexVerificationTenants:             rw.cfg.BlocklistPollIndexVerificationTenants,
+		RequestsPerSecond:                    rw.cfg.BlocklistPollRequestsPerSecond,
+		BytesPerSecond:                       rw.cfg.BlocklistPollBytesPerSecond,
+		TenantIndexBuilderTimeout:            rw.cfg.BlocklistPollTenantIndexBuilderTimeout,
+		TenantAllowList:                      rw.cfg.BlocklistPollTenantAllowList,
+		TenantDenyList:                       rw.cfg.BlocklistPollTenantDenyList,
+		TenantIndexAgeTarget:                 rw.cfg.BlocklistPollTenantIndexAgeTarget,
 	}, sharder, rw.r, rw.c, rw.w, rw.logger)
 
+	// only components that can build tenant indexes take them over
+	if rw.cfg.BlocklistPollTenantIndexBuilderTimeout > 0 && sharder != blocklist.OwnsNothingSharder {
+		builderID, err := os.Hostname()
+		if err != nil {
+			builderID = uuid.NewString()
+		}
+		blocklistPoller.SetTenantIndexHeartbeats(rw.versioned, builderID)
+	}
+
+	if rw.inventory != nil {
+		blocklistPoller.SetInventory(rw.inventory)
+	}
+	if rw.tenantIndexReplica != nil {
+		blocklistPoller.SetTenantIndexReplica(rw.tenantIndexReplica)
+	}
+	if rw.federation != nil {
+		blocklistPoller.SetBlockSources(rw.federation)
+	}
+	blocklistPoller.AddTenantLifecycleListener(rw)
+	for _, l := range rw.tenantListeners {
+		blocklistPoller.AddTenantLifecycleListener(l)
+	}
+
 	rw.blocklistPoller = blocklistPoller
 	rw.pollerShutdownCh = make(chan struct{})
 
@@ -647,6 +1060,23 @@ func (rw *readerWriter) EnablePolling(ctx context.Context, sharder blocklist.Job
 	go rw.pollingLoop(ctx)
 }
 
+func (rw *readerWriter) AddTenantLifecycleListener(l blocklist.TenantLifecycleListener) {
+	rw.tenantListeners = append(rw.tenantListeners, l)
+}
+
+// BeforeTenantDeletion implements blocklist.TenantLifecycleListener
+func (rw *readerWriter) BeforeTenantDeletion(context.Context, string) {}
+
+// AfterTenantDeletion implements blocklist.TenantLifecycleListener and removes the per-tenant compaction series.
+func (rw *readerWriter) AfterTenantDeletion(_ context.Context, tenantID string, _ int, _ int64) {
+	metricCompactionOutstandingBlocks.DeleteLabelValues(tenantID)
+	metricAttributePolicyDroppedBytes.DeleteLabelValues(tenantID)
+
+	rw.compactionOwnershipMtx.Lock()
+	delete(rw.compactionOwnership, tenantID)
+	rw.compactionOwnershipMtx.Unlock()
+}
+
 func (rw *readerWriter) PollNow(ctx context.Context) {
 	rw.pollBlocklist(ctx)
 }
@@ -676,17 +1106,30 @@ func (rw *readerWriter) pollBlocklist(ctx context.Context) {
 	}
 
 	rw.blocklist.ApplyPollResults(blocklist, compactedBlocklist)
+	rw.indexPartitionedBlocks()
+	rw.lastPoll.Store(time.Now().UnixNano())
 }
 
-// includeBlock indicates whether a given block should be included in a backend search
-func includeBlock(b *backend.BlockMeta, _ common.ID, blockStart, blockEnd []byte, timeStart, timeEnd int64, rf1After time.Time) bool {
-	// todo: restore this functionality once it works. min/max ids are currently not recorded
-	//    https://github.com/grafana/tempo/issues/1903
-	//  correctly in a block
-	// if bytes.Compare(id, b.MinID) == -1 || bytes.Compare(id, b.MaxID) == 1 {
-	// 	return false
-	// }
+func (rw *readerWriter) BlocklistStale(tenantID string) bool {
+	if rw.blocklistPoller == nil {
+		return false
+	}
+
+	threshold := rw.cfg.BlocklistPollStaleThreshold
+	if threshold <= 0 {
+		threshold = staleBlocklistPolls * rw.cfg.BlocklistPoll
+	}
+	if time.Since(time.Unix(0, rw.lastPoll.Load())) > threshold {
+		return true
+	}
 
+	// the poll of a tenant can fail while the poll of the blocklist succeeds, its previous blocklist is then kept
+	since, failing := rw.blocklistPoller.TenantFailingSince(tenantID)
+	return failing && time.Since(since) > threshold
+}
+
+// includeBlock indicates whether a given block should be included in a backend search
+func includeBlock(b *backend.BlockMeta, id common.ID, blockStart, blockEnd []byte, timeStart, timeEnd int64, rf1After time.Time) bool {
 	if timeStart != 0 && timeEnd != 0 {
 		if b.StartTime.Unix() >= timeEnd || b.EndTime.Unix() <= timeStart {
 			return false
@@ -700,6 +1143,11 @@ func includeBlock(b *backend.BlockMeta, _ common.ID, blockStart, blockEnd []byte
 		return false
 	}
 
+	// the trace ID range and summary are in the meta, they are cheaper to check than the bloom filter of the block
+	if !b.MayContainTraceID(id) {
+		return false
+	}
+
 	if rf1After.IsZero() {
 		return b.ReplicationFactor == backend.DefaultReplicationFactor
 	}
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/tempo/tempodb/encoding/vparquet3"
 	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
 
 	"github.com/go-kit/log"
@@ -17,6 +18,7 @@ import (
 	"github.com/google/uuid"
 	v2 "github.com/grafana/tempo/tempodb/encoding/v2"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -126,6 +128,19 @@ func TestDB(t *testing.T) {
 		assert.True(t, proto.Equal(bFound[0].Trace, reqs[i]))
 		require.Greater(t, bFound[0].Metrics.InspectedBytes, uint64(100000))
 	}
+
+	// read all the traces and an unknown one in a single pass
+	missing := test.ValidTraceID(nil)
+	traces, metrics, failedBlocks, err := r.FindMany(ctx, testTenantID, append(ids, missing), BlockIDMin, BlockIDMax, 0, 0, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	assert.Nil(t, failedBlocks)
+	require.Len(t, traces, numMsgs+1)
+	for i := range ids {
+		require.Len(t, traces[i], 1)
+		assert.True(t, proto.Equal(traces[i][0], reqs[i]))
+	}
+	assert.Empty(t, traces[numMsgs])
+	require.Greater(t, metrics.InspectedBytes, uint64(0))
 }
 
 func TestNoCompactionWhenCompactionRange0(t *testing.T) {
@@ -260,6 +275,17 @@ func checkBlocklists(ctx context.Context, t *testing.T, expectedID uuid.UUID, ex
 	}
 }
 
+func metaWithTraceIDSummary(blockID string, ids ...[]byte) *backend.BlockMeta {
+	m := &backend.BlockMeta{
+		BlockID:        backend.MustParse(blockID),
+		TraceIDSummary: backend.NewTraceIDSummary(64),
+	}
+	for _, id := range ids {
+		m.TraceIDAdded(id)
+	}
+	return m
+}
+
 func TestIncludeBlock(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -360,6 +386,14 @@ func TestIncludeBlock(t *testing.T) {
 			end:      0,
 			expected: true,
 		},
+		{
+			name:       "include - trace id summary hit",
+			searchID:   []byte{0x05},
+			blockStart: uuid.MustParse(BlockIDMin),
+			blockEnd:   uuid.MustParse(BlockIDMax),
+			meta:       metaWithTraceIDSummary("50000000-0000-0000-0000-000000000000", []byte{0x05}),
+			expected:   true,
+		},
 		// excludes
 		{
 			name:       "exclude - duh",
@@ -370,25 +404,35 @@ func TestIncludeBlock(t *testing.T) {
 				BlockID: backend.MustParse("52000000-0000-0000-0000-000000000000"),
 			},
 		},
-		// todo: restore when this is fixed: https://github.com/grafana/tempo/issues/1903
-		// {
-		// 	name:       "exclude - min id range",
-		// 	searchID:   []byte{0x00},
-		// 	blockStart: uuid.MustParse(BlockIDMin),
-		// 	blockEnd:   uuid.MustParse(BlockIDMax),
-		// 	meta: &backend.BlockMeta{
-		// 		BlockID: uuid.MustParse("50000000-0000-0000-0000-000000000000"),
-		// 	},
-		// },
-		// {
-		// 	name:       "exclude - max id range",
-		// 	searchID:   []byte{0x11},
-		// 	blockStart: uuid.MustParse(BlockIDMin),
-		// 	blockEnd:   uuid.MustParse(BlockIDMax),
-		// 	meta: &backend.BlockMeta{
-		// 		BlockID: uuid.MustParse("50000000-0000-0000-0000-000000000000"),
-		// 	},
-		// },
+		{
+			name:       "exclude - min id range",
+			searchID:   []byte{0x00},
+			blockStart: uuid.MustParse(BlockIDMin),
+			blockEnd:   uuid.MustParse(BlockIDMax),
+			meta: &backend.BlockMeta{
+				BlockID: backend.MustParse("50000000-0000-0000-0000-000000000000"),
+				MinID:   []byte{0x01},
+				MaxID:   []byte{0x10},
+			},
+		},
+		{
+			name:       "exclude - max id range",
+			searchID:   []byte{0x11},
+			blockStart: uuid.MustParse(BlockIDMin),
+			blockEnd:   uuid.MustParse(BlockIDMax),
+			meta: &backend.BlockMeta{
+				BlockID: backend.MustParse("50000000-0000-0000-0000-000000000000"),
+				MinID:   []byte{0x01},
+				MaxID:   []byte{0x10},
+			},
+		},
+		{
+			name:       "exclude - trace id summary miss",
+			searchID:   []byte{0x05},
+			blockStart: uuid.MustParse(BlockIDMin),
+			blockEnd:   uuid.MustParse(BlockIDMax),
+			meta:       metaWithTraceIDSummary("50000000-0000-0000-0000-000000000000", []byte{0x06}),
+		},
 		{
 			name:       "exclude - min block range",
 			searchID:   []byte{0x05},
@@ -621,6 +665,10 @@ func testCompleteBlock(t *testing.T, from, to string) {
 	complete, err := w.CompleteBlock(context.Background(), block)
 	require.NoError(t, err, "unexpected error completing block")
 	require.Equal(t, complete.BlockMeta().Version, to)
+	err = rw.ValidateBlock(context.Background(), testTenantID, complete.BlockMeta().BlockID)
+	if !errors.Is(err, common.ErrUnsupported) {
+		require.NoError(t, err)
+	}
 
 	for i, id := range ids {
 		found, err := complete.FindTraceByID(context.TODO(), id, common.DefaultSearchOptions())
@@ -633,6 +681,65 @@ func testCompleteBlock(t *testing.T, from, to string) {
 			require.Greater(t, found.Metrics.InspectedBytes, uint64(100000))
 		}
 	}
+
+	// batch lookup in reverse order with a duplicate and a missing id
+	batchIDs := make([]common.ID, 0, len(ids)+2)
+	for i := len(ids) - 1; i >= 0; i-- {
+		batchIDs = append(batchIDs, ids[i])
+	}
+	batchIDs = append(batchIDs, ids[0], test.ValidTraceID(nil))
+
+	for _, f := range []common.Finder{block, complete} {
+		traces, metrics, err := f.FindTraceByIDs(context.TODO(), batchIDs, common.DefaultSearchOptions())
+		require.NoError(t, err)
+		require.NotNil(t, metrics)
+		require.Len(t, traces, len(batchIDs))
+		for i := range ids {
+			tr := traces[len(ids)-1-i]
+			require.NotNil(t, tr)
+			trace.SortTrace(tr)
+			require.True(t, proto.Equal(tr, reqs[i]))
+		}
+		require.True(t, proto.Equal(traces[len(ids)], reqs[0]))
+		require.Nil(t, traces[len(ids)+1])
+	}
+}
+
+func TestCompleteBlockPinnedVersion(t *testing.T) {
+	_, w, _, _ := testConfig(t, backend.EncLZ4_256k, time.Minute)
+	rw := w.(*readerWriter)
+	pinned := vparquet3.VersionString
+	require.NotEqual(t, pinned, rw.cfg.Block.Version)
+
+	block, err := w.WAL().NewBlock(backend.NewBlockMeta(testTenantID, uuid.New(), rw.cfg.Block.Version, backend.EncNone, ""), model.CurrentEncoding)
+	require.NoError(t, err)
+
+	dec := model.MustNewSegmentDecoder(model.CurrentEncoding)
+	id := test.ValidTraceID(nil)
+	req := test.MakeTrace(5, id)
+	trace.SortTrace(req)
+	writeTraceToWal(t, block, dec, id, req, 0, 0)
+	require.NoError(t, block.Flush())
+
+	// the block of the tenant is completed in the pinned version
+	l, err := local.NewBackend(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+	r := backend.NewReader(l)
+	complete, err := w.CompleteBlockWithBackend(context.Background(), block, common.BlockEncoding{Version: pinned}, r, backend.NewWriter(l))
+	require.NoError(t, err)
+	require.Equal(t, pinned, complete.BlockMeta().Version)
+
+	found, err := complete.FindTraceByID(context.Background(), id, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	trace.SortTrace(found.Trace)
+	require.True(t, proto.Equal(req, found.Trace))
+
+	// flushed blocks are counted by version
+	written := metricBlocksWritten.WithLabelValues(testTenantID, pinned, blocksWrittenSourceFlush)
+	before := testutil.ToFloat64(written)
+	require.NoError(t, w.WriteBlock(context.Background(), &testWriteableBlock{BackendBlock: complete, r: r}))
+	require.Equal(t, before+1, testutil.ToFloat64(written))
 }
 
 func TestCompleteBlockHonorsStartStopTimes(t *testing.T) {
@@ -953,3 +1060,27 @@ func TestNoCompactFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestBlocklistStale(t *testing.T) {
+	r, _, _, _ := testConfig(t, backend.EncNone, time.Minute)
+
+	// the blocklist isn't polled, so it can't be stale
+	require.False(t, r.BlocklistStale(testTenantID))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.EnablePolling(ctx, &mockJobSharder{}, false)
+	require.False(t, r.BlocklistStale(testTenantID))
+
+	rw := r.(*readerWriter)
+	rw.lastPoll.Store(time.Now().Add(-staleBlocklistPolls * time.Minute).Add(-time.Second).UnixNano())
+	require.True(t, r.BlocklistStale(testTenantID))
+
+	// the stale threshold replaces the default number of polls
+	rw.cfg.BlocklistPollStaleThreshold = 2 * staleBlocklistPolls * time.Minute
+	require.False(t, r.BlocklistStale(testTenantID))
+	rw.cfg.BlocklistPollStaleThreshold = 0
+
+	r.PollNow(ctx)
+	require.False(t, r.BlocklistStale(testTenantID))
+}
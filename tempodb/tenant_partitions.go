@@ -0,0 +1,260 @@
+package tempodb
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// tenantPartitionSeparator separates a tenant and the index of its partition in the storage tenant of a partition.
+// It isn't allowed in tenant IDs, so a storage tenant never collides with a tenant.
+const tenantPartitionSeparator = "~"
+
+// storageTenant returns the storage tenant of the blocks of the tenant starting at the given time. The time windows
+// of the period are assigned to the partitions of the tenant round robin, so the blocks of a window, that are
+// compacted together, share a partition. Tenants that aren't partitioned are their own storage tenant.
+func (c *TenantPartitionsConfig) storageTenant(tenantID string, start time.Time) string {
+	partitions := c.Tenants[tenantID]
+	if partitions <= 1 || c.Period <= 0 {
+		return tenantID
+	}
+
+	window := start.UnixNano() / int64(c.Period)
+	partition := window % int64(partitions)
+	if partition < 0 {
+		partition += int64(partitions)
+	}
+	return tenantID + tenantPartitionSeparator + strconv.FormatInt(partition, 10)
+}
+
+// logicalTenant returns the tenant of a storage tenant.
+func logicalTenant(storageTenantID string) string {
+	tenantID, partition, ok := strings.Cut(storageTenantID, tenantPartitionSeparator)
+	if !ok {
+		return storageTenantID
+	}
+	if _, err := strconv.Atoi(partition); err != nil {
+		return storageTenantID
+	}
+	return tenantID
+}
+
+// storageTenants returns the tenant and the storage tenants of its partitions in the blocklist. Partitions are found
+// in the blocklist, so blocks of partitions are still read after the tenant, or one of its partitions, is removed
+// from the config.
+func (rw *readerWriter) storageTenants(tenantID string) []string {
+	tenants := []string{tenantID}
+	for _, t := range rw.blocklist.Tenants() {
+		if t != tenantID && logicalTenant(t) == tenantID {
+			tenants = append(tenants, t)
+		}
+	}
+	return tenants
+}
+
+// tenantMetas returns the metas of the tenant and its partitions.
+func (rw *readerWriter) tenantMetas(tenantID string) []*backend.BlockMeta {
+	tenants := rw.storageTenants(tenantID)
+	if len(tenants) == 1 {
+		return rw.blocklist.Metas(tenantID)
+	}
+
+	var metas []*backend.BlockMeta
+	for _, t := range tenants {
+		metas = append(metas, rw.blocklist.Metas(t)...)
+	}
+	return metas
+}
+
+// tenantMetasInRange returns the metas of the tenant and its partitions that overlap the time range.
+func (rw *readerWriter) tenantMetasInRange(tenantID string, start, end time.Time) []*backend.BlockMeta {
+	var metas []*backend.BlockMeta
+	for _, t := range rw.storageTenants(tenantID) {
+		metas = append(metas, rw.blocklist.MetasInRange(t, start, end)...)
+	}
+	return metas
+}
+
+// tenantCompactedMetas returns the compacted metas of the tenant and its partitions.
+func (rw *readerWriter) tenantCompactedMetas(tenantID string) []*backend.CompactedBlockMeta {
+	var metas []*backend.CompactedBlockMeta
+	for _, t := range rw.storageTenants(tenantID) {
+		metas = append(metas, rw.blocklist.CompactedMetas(t)...)
+	}
+	return metas
+}
+
+// tenantCompactedMetasInRange returns the compacted metas of the tenant and its partitions that overlap the time
+// range.
+func (rw *readerWriter) tenantCompactedMetasInRange(tenantID string, start, end time.Time) []*backend.CompactedBlockMeta {
+	var metas []*backend.CompactedBlockMeta
+	for _, t := range rw.storageTenants(tenantID) {
+		metas = append(metas, rw.blocklist.CompactedMetasInRange(t, start, end)...)
+	}
+	return metas
+}
+
+// indexPartitionedBlocks records the storage tenant of the blocks of the partitions in the blocklist.
+func (rw *readerWriter) indexPartitionedBlocks() {
+	blocks := map[backend.UUID]string{}
+	for _, t := range rw.blocklist.Tenants() {
+		if logicalTenant(t) == t {
+			continue
+		}
+		for _, m := range rw.blocklist.Metas(t) {
+			blocks[m.BlockID] = t
+		}
+		for _, m := range rw.blocklist.CompactedMetas(t) {
+			blocks[m.BlockID] = t
+		}
+	}
+
+	rw.partitionedBlocksMtx.Lock()
+	rw.partitionedBlocks = blocks
+	rw.partitionedBlocksMtx.Unlock()
+}
+
+// blockStorageTenant returns the storage tenant of a block of the tenant. Blocks that aren't in a partition of the
+// tenant are stored in the tenant.
+func (rw *readerWriter) blockStorageTenant(tenantID string, blockID backend.UUID) string {
+	rw.partitionedBlocksMtx.RLock()
+	storageTenantID, ok := rw.partitionedBlocks[blockID]
+	rw.partitionedBlocksMtx.RUnlock()
+
+	if !ok || logicalTenant(storageTenantID) != tenantID {
+		return tenantID
+	}
+	return storageTenantID
+}
+
+// storageMeta returns the meta with the storage tenant of its block. Metas of the read path are built from requests of
+// the tenant and don't know the partition of their block.
+func (rw *readerWriter) storageMeta(meta *backend.BlockMeta) *backend.BlockMeta {
+	storageTenantID := rw.blockStorageTenant(meta.TenantID, meta.BlockID)
+	if storageTenantID == meta.TenantID {
+		return meta
+	}
+
+	m := *meta
+	m.TenantID = storageTenantID
+	return &m
+}
+
+// partitionWriter writes the objects of a block to the storage tenant of its partition.
+type partitionWriter struct {
+	backend.Writer
+	blockID         uuid.UUID
+	tenantID        string
+	storageTenantID string
+}
+
+func (rw *readerWriter) partitionWriter(meta *backend.BlockMeta) backend.Writer {
+	storageTenantID := rw.cfg.TenantPartitions.storageTenant(meta.TenantID, meta.StartTime)
+	if storageTenantID == meta.TenantID {
+		return rw.w
+	}
+
+	return &partitionWriter{
+		Writer:          rw.w,
+		blockID:         (uuid.UUID)(meta.BlockID),
+		tenantID:        meta.TenantID,
+		storageTenantID: storageTenantID,
+	}
+}
+
+func (w *partitionWriter) tenant(blockID uuid.UUID, tenantID string) string {
+	if blockID == w.blockID && tenantID == w.tenantID {
+		return w.storageTenantID
+	}
+	return tenantID
+}
+
+func (w *partitionWriter) Write(ctx context.Context, name string, blockID uuid.UUID, tenantID string, buffer []byte, cacheInfo *backend.CacheInfo) error {
+	return w.Writer.Write(ctx, name, blockID, w.tenant(blockID, tenantID), buffer, cacheInfo)
+}
+
+func (w *partitionWriter) StreamWriter(ctx context.Context, name string, blockID uuid.UUID, tenantID string, data io.Reader, size int64) error {
+	return w.Writer.StreamWriter(ctx, name, blockID, w.tenant(blockID, tenantID), data, size)
+}
+
+func (w *partitionWriter) WriteBlockMeta(ctx context.Context, meta *backend.BlockMeta) error {
+	m := *meta
+	m.TenantID = w.tenant((uuid.UUID)(meta.BlockID), meta.TenantID)
+	return w.Writer.WriteBlockMeta(ctx, &m)
+}
+
+func (w *partitionWriter) Append(ctx context.Context, name string, blockID uuid.UUID, tenantID string, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
+	return w.Writer.Append(ctx, name, blockID, w.tenant(blockID, tenantID), tracker, buffer)
+}
+
+func (w *partitionWriter) ResumableStreamWriter(ctx context.Context, name string, blockID uuid.UUID, tenantID string) io.WriteCloser {
+	return w.Writer.ResumableStreamWriter(ctx, name, blockID, w.tenant(blockID, tenantID))
+}
+
+func (w *partitionWriter) WriteNoCompactFlag(ctx context.Context, blockID uuid.UUID, tenantID string) error {
+	return w.Writer.WriteNoCompactFlag(ctx, blockID, w.tenant(blockID, tenantID))
+}
+
+// partitionOverrides returns the overrides of the tenant of a storage tenant, so the blocks of the partitions of a
+// tenant are compacted and retained with the overrides of the tenant.
+type partitionOverrides struct {
+	o CompactorOverrides
+}
+
+func withPartitionOverrides(o CompactorOverrides) CompactorOverrides {
+	if _, ok := o.(partitionOverrides); ok || o == nil {
+		return o
+	}
+	return partitionOverrides{o: o}
+}
+
+func (p partitionOverrides) BlockRetentionForTenant(tenantID string) time.Duration {
+	return p.o.BlockRetentionForTenant(logicalTenant(tenantID))
+}
+
+func (p partitionOverrides) CompactionDisabledForTenant(tenantID string) bool {
+	return p.o.CompactionDisabledForTenant(logicalTenant(tenantID))
+}
+
+func (p partitionOverrides) MaxBytesPerTraceForTenant(tenantID string) int {
+	return p.o.MaxBytesPerTraceForTenant(logicalTenant(tenantID))
+}
+
+func (p partitionOverrides) MaxCompactionRangeForTenant(tenantID string) time.Duration {
+	return p.o.MaxCompactionRangeForTenant(logicalTenant(tenantID))
+}
+
+func (p partitionOverrides) StorageAttributePolicyForTenant(tenantID string) common.AttributePolicy {
+	return p.o.StorageAttributePolicyForTenant(logicalTenant(tenantID))
+}
+
+func (p partitionOverrides) ConvertV2BlocksForTenant(tenantID string) bool {
+	return p.o.ConvertV2BlocksForTenant(logicalTenant(tenantID))
+}
+
+func (p partitionOverrides) DedicatedColumnsForTenant(tenantID string) backend.DedicatedColumns {
+	return p.o.DedicatedColumnsForTenant(logicalTenant(tenantID))
+}
+
+func (p partitionOverrides) BlockRetentionClassesForTenant(tenantID string) (string, map[string]time.Duration) {
+	return p.o.BlockRetentionClassesForTenant(logicalTenant(tenantID))
+}
+
+func (p partitionOverrides) BlockArchiveForTenant(tenantID string) (time.Duration, string) {
+	return p.o.BlockArchiveForTenant(logicalTenant(tenantID))
+}
+
+func (p partitionOverrides) BlockEncodingForTenant(tenantID string) common.BlockEncoding {
+	return p.o.BlockEncodingForTenant(logicalTenant(tenantID))
+}
+
+func (p partitionOverrides) DedicatedColumnsAutoApplyForTenant(tenantID string) bool {
+	return p.o.DedicatedColumnsAutoApplyForTenant(logicalTenant(tenantID))
+}
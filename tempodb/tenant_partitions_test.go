@@ -0,0 +1,157 @@
+package tempodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto" //nolint:all
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestTenantPartitionsStorageTenant(t *testing.T) {
+	cfg := TenantPartitionsConfig{Tenants: map[string]int{"big": 3}, Period: time.Hour}
+
+	require.Equal(t, "big~0", cfg.storageTenant("big", time.Unix(0, 0)))
+	require.Equal(t, "big~0", cfg.storageTenant("big", time.Unix(3599, 0)))
+	require.Equal(t, "big~1", cfg.storageTenant("big", time.Unix(3600, 0)))
+	require.Equal(t, "big~2", cfg.storageTenant("big", time.Unix(2*3600, 0)))
+	require.Equal(t, "big~0", cfg.storageTenant("big", time.Unix(3*3600, 0)))
+	require.Equal(t, "small", cfg.storageTenant("small", time.Unix(3600, 0)))
+
+	require.Equal(t, "big", logicalTenant("big~2"))
+	require.Equal(t, "big", logicalTenant("big"))
+	require.Equal(t, "big~x", logicalTenant("big~x"))
+}
+
+func TestTenantPartitions(t *testing.T) {
+	ctx := context.Background()
+	dec := model.MustNewSegmentDecoder(model.CurrentEncoding)
+
+	r, w, c, _ := testConfig(t, backend.EncNone, 0, func(cfg *Config) {
+		cfg.TenantPartitions = TenantPartitionsConfig{Tenants: map[string]int{testTenantID: 2}, Period: time.Hour}
+	})
+	require.NoError(t, c.EnableCompaction(ctx, &CompactorConfig{
+		MaxCompactionRange:      time.Hour,
+		BlockRetention:          time.Hour,
+		CompactedBlockRetention: time.Hour,
+	}, &mockSharder{}, &mockOverrides{}))
+	r.EnablePolling(ctx, &mockJobSharder{}, false)
+	rw := r.(*readerWriter)
+
+	// the blocks of the first two hours are flushed to both partitions
+	l, err := local.NewBackend(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+	localR, localW := backend.NewReader(l), backend.NewWriter(l)
+
+	ids := map[backend.UUID]common.ID{}
+	reqs := map[backend.UUID]*tempopb.Trace{}
+	for hour := uint32(0); hour < 2; hour++ {
+		head, err := w.WAL().NewBlock(&backend.BlockMeta{BlockID: backend.NewUUID(), TenantID: testTenantID}, model.CurrentEncoding)
+		require.NoError(t, err)
+
+		id := test.ValidTraceID(nil)
+		req := test.MakeTrace(5, id)
+		writeTraceToWal(t, head, dec, id, req, 0, 0)
+
+		complete, err := w.CompleteBlockWithBackend(ctx, head, common.BlockEncoding{}, localR, localW)
+		require.NoError(t, err)
+		complete.BlockMeta().StartTime = time.Unix(int64(hour)*3600, 0)
+		complete.BlockMeta().EndTime = time.Unix(int64(hour)*3600+1, 0)
+		require.NoError(t, w.WriteBlock(ctx, &testWriteableBlock{BackendBlock: complete, r: localR}))
+
+		ids[complete.BlockMeta().BlockID] = id
+		reqs[complete.BlockMeta().BlockID] = req
+	}
+	rw.pollBlocklist(ctx)
+
+	require.ElementsMatch(t, []string{testTenantID + "~0", testTenantID + "~1"}, rw.Tenants())
+	metas := rw.BlockMetas(testTenantID)
+	require.Len(t, metas, 2)
+	for _, m := range metas {
+		require.Equal(t, testTenantID, logicalTenant(m.TenantID))
+		require.NotEqual(t, testTenantID, m.TenantID)
+	}
+
+	// traces are found in all partitions of the tenant
+	for blockID, id := range ids {
+		found, failedBlocks, err := r.Find(ctx, testTenantID, id, BlockIDMin, BlockIDMax, 0, 0, common.DefaultSearchOptions())
+		require.NoError(t, err)
+		require.Nil(t, failedBlocks)
+		require.Len(t, found, 1)
+		require.True(t, proto.Equal(reqs[blockID], found[0].Trace))
+
+		// and blocks of requests of the tenant are read from their partition
+		meta, _, err := r.BlockMeta(ctx, testTenantID, blockID)
+		require.NoError(t, err)
+		require.Equal(t, rw.blockStorageTenant(testTenantID, blockID), meta.TenantID)
+
+		resp, err := r.Search(ctx, &backend.BlockMeta{
+			BlockID:      blockID,
+			TenantID:     testTenantID,
+			Version:      meta.Version,
+			Encoding:     meta.Encoding,
+			Size_:        meta.Size_,
+			TotalRecords: meta.TotalRecords,
+			FooterSize:   meta.FooterSize,
+			DataEncoding: meta.DataEncoding,
+		}, &tempopb.SearchRequest{Query: "{}", Limit: 10}, common.DefaultSearchOptions())
+		require.NoError(t, err)
+		require.Len(t, resp.Traces, 1)
+	}
+
+	// blocks of another tenant aren't resolved to the partitions
+	for blockID := range ids {
+		require.Equal(t, "other", rw.blockStorageTenant("other", blockID))
+	}
+	require.Equal(t, testTenantID, rw.blockStorageTenant(testTenantID, backend.UUID(uuid.New())))
+
+	// partitions are retained with the overrides of the tenant, the blocks are past the retention of the config
+	rw.RetainWithConfig(ctx, rw.compactorCfg, &mockSharder{}, tenantRetentionOverrides{testTenantID: 1_000_000 * time.Hour})
+	require.Len(t, rw.BlockMetas(testTenantID), 2)
+
+	rw.RetainWithConfig(ctx, rw.compactorCfg, &mockSharder{}, tenantRetentionOverrides{})
+	require.Empty(t, rw.BlockMetas(testTenantID))
+}
+
+type tenantRetentionOverrides map[string]time.Duration
+
+func (o tenantRetentionOverrides) BlockRetentionForTenant(tenantID string) time.Duration {
+	return o[tenantID]
+}
+
+func (o tenantRetentionOverrides) CompactionDisabledForTenant(string) bool { return false }
+
+func (o tenantRetentionOverrides) MaxBytesPerTraceForTenant(string) int { return 0 }
+
+func (o tenantRetentionOverrides) MaxCompactionRangeForTenant(string) time.Duration { return 0 }
+
+func (o tenantRetentionOverrides) StorageAttributePolicyForTenant(string) common.AttributePolicy {
+	return common.AttributePolicy{}
+}
+
+func (o tenantRetentionOverrides) ConvertV2BlocksForTenant(string) bool { return false }
+
+func (o tenantRetentionOverrides) DedicatedColumnsForTenant(string) backend.DedicatedColumns {
+	return nil
+}
+
+func (o tenantRetentionOverrides) BlockRetentionClassesForTenant(string) (string, map[string]time.Duration) {
+	return "", nil
+}
+
+func (o tenantRetentionOverrides) BlockArchiveForTenant(string) (time.Duration, string) { return 0, "" }
+
+func (o tenantRetentionOverrides) BlockEncodingForTenant(string) common.BlockEncoding {
+	return common.BlockEncoding{}
+}
+
+func (o tenantRetentionOverrides) DedicatedColumnsAutoApplyForTenant(string) bool { return false }
@@ -0,0 +1,66 @@
+package tempodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+)
+
+var (
+	metricTieredEvictedBlocks = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "tiered_evicted_blocks_total",
+		Help:      "Total number of local copies of blocks evicted from the local disk.",
+	})
+	metricTieredEvictionErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "tiered_eviction_errors_total",
+		Help:      "Total number of times an error occurred while evicting local copies of blocks.",
+	})
+)
+
+// newTiered mirrors the trace storage to the local disk of the tiered config.
+func newTiered(cfg *Config, remoteR backend.RawReader, remoteW backend.RawWriter, remoteC backend.Compactor) (*backend.Tiered, error) {
+	localR, localW, localC, err := local.New(cfg.Tiered.Local)
+	if err != nil {
+		return nil, fmt.Errorf("error creating tiered local backend: %w", err)
+	}
+
+	return backend.NewTiered(localR, localW, localC, remoteR, remoteW, remoteC), nil
+}
+
+// tieredEvictionLoop evicts the local copies of the blocks past the max age until ctx is done.
+func (rw *readerWriter) tieredEvictionLoop(ctx context.Context) {
+	ticker := time.NewTicker(rw.cfg.Tiered.EvictionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			rw.doTieredEviction(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (rw *readerWriter) doTieredEviction(ctx context.Context) {
+	evicted, err := rw.tiered.Evict(ctx, time.Now().Add(-rw.cfg.Tiered.MaxAge))
+	metricTieredEvictedBlocks.Add(float64(evicted))
+	if err != nil {
+		metricTieredEvictionErrors.Inc()
+		level.Error(rw.logger).Log("msg", "failed to evict local copies of blocks", "evicted", evicted, "err", err)
+		return
+	}
+
+	if evicted > 0 {
+		level.Info(rw.logger).Log("msg", "evicted local copies of blocks", "evicted", evicted)
+	}
+}
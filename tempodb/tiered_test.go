@@ -0,0 +1,220 @@
+package tempodb
+
+import (
+	"bytes"
+	"context"
+	crand "crypto/rand"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto" //nolint:all
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/blocklist"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestTieredBackend(t *testing.T) {
+	ctx := context.Background()
+	dec := model.MustNewSegmentDecoder(model.CurrentEncoding)
+	localDir := t.TempDir()
+
+	r, w, _, tempDir := testConfig(t, backend.EncNone, 0, func(cfg *Config) {
+		cfg.Tiered = TieredConfig{
+			Local:            &local.Config{Path: localDir},
+			MaxAge:           DefaultTieredMaxAge,
+			EvictionInterval: DefaultTieredEvictionInterval,
+		}
+	})
+	rw := r.(*readerWriter)
+	require.NotNil(t, rw.tiered)
+
+	head, err := w.WAL().NewBlock(&backend.BlockMeta{BlockID: backend.NewUUID(), TenantID: testTenantID}, model.CurrentEncoding)
+	require.NoError(t, err)
+	id := test.ValidTraceID(nil)
+	req := test.MakeTrace(5, id)
+	writeTraceToWal(t, head, dec, id, req, 0, 0)
+
+	complete, err := w.CompleteBlock(ctx, head)
+	require.NoError(t, err)
+	meta := complete.BlockMeta()
+
+	// the block is written to the trace storage and mirrored to the local disk
+	blockPath := path.Join(testTenantID, meta.BlockID.String())
+	localBlock, remoteBlock := path.Join(localDir, blockPath), path.Join(tempDir, "traces", blockPath)
+	require.FileExists(t, path.Join(localBlock, backend.MetaName))
+	require.FileExists(t, path.Join(remoteBlock, backend.MetaName))
+
+	find := func() error {
+		found, _, err := r.Find(ctx, testTenantID, id, BlockIDMin, BlockIDMax, 0, 0, common.DefaultSearchOptions())
+		if err != nil {
+			return err
+		}
+		require.Len(t, found, 1)
+		require.True(t, proto.Equal(req, found[0].Trace))
+		return nil
+	}
+	rw.blocklist.ApplyPollResults(blocklist.PerTenant{testTenantID: {meta}}, blocklist.PerTenantCompacted{})
+
+	// reads are served by the local copy
+	require.NoError(t, os.Rename(remoteBlock, remoteBlock+".moved"))
+	require.NoError(t, find())
+	require.NoError(t, os.Rename(remoteBlock+".moved", remoteBlock))
+
+	// local copies are kept until their block is past the max age
+	evicted, err := rw.tiered.Evict(ctx, meta.EndTime)
+	require.NoError(t, err)
+	require.Zero(t, evicted)
+	require.DirExists(t, localBlock)
+
+	evicted, err = rw.tiered.Evict(ctx, meta.EndTime.Add(time.Second))
+	require.NoError(t, err)
+	require.Equal(t, 1, evicted)
+	require.NoDirExists(t, localBlock)
+	require.DirExists(t, remoteBlock)
+
+	// and evicted blocks are read from the trace storage
+	require.NoError(t, find())
+}
+
+// failingWriter fails the writes and appends after reading a byte of the object.
+type failingWriter struct {
+	backend.RawWriter
+}
+
+func (w *failingWriter) Write(_ context.Context, _ string, _ backend.KeyPath, data io.Reader, _ int64, _ *backend.CacheInfo) error {
+	_, _ = io.CopyN(io.Discard, data, 1)
+	return errors.New("disk full")
+}
+
+func (w *failingWriter) Append(context.Context, string, backend.KeyPath, backend.AppendTracker, []byte) (backend.AppendTracker, error) {
+	return nil, errors.New("disk full")
+}
+
+func TestTieredMirroringIsBestEffort(t *testing.T) {
+	ctx := context.Background()
+	keypath := backend.KeyPathForBlock(uuid.New(), testTenantID)
+	object := make([]byte, 1<<20)
+	_, err := crand.Read(object)
+	require.NoError(t, err)
+
+	localR, localW, localC, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+	remoteR, remoteW, remoteC, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+
+	read := func(r backend.RawReader, name string) []byte {
+		rc, _, err := r.Read(ctx, name, keypath, nil)
+		require.NoError(t, err)
+		defer rc.Close()
+		b, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		return b
+	}
+
+	// the writes succeed if the local disk fails, the objects are read from the remote backend
+	tiered := backend.NewTiered(localR, &failingWriter{localW}, localC, remoteR, remoteW, remoteC)
+	require.NoError(t, tiered.Write(ctx, "written", keypath, bytes.NewReader(object), int64(len(object)), nil))
+
+	tracker, err := tiered.Append(ctx, "appended", keypath, nil, object[:10])
+	require.NoError(t, err)
+	tracker, err = tiered.Append(ctx, "appended", keypath, tracker, object[10:])
+	require.NoError(t, err)
+	require.NoError(t, tiered.CloseAppend(ctx, tracker))
+
+	for _, name := range []string{"written", "appended"} {
+		_, _, err = localR.Read(ctx, name, keypath, nil)
+		require.ErrorIs(t, err, backend.ErrDoesNotExist)
+		require.Equal(t, object, read(remoteR, name))
+		require.Equal(t, object, read(tiered, name))
+	}
+
+	// failed remote writes leave no local copy
+	tiered = backend.NewTiered(localR, localW, localC, remoteR, &failingWriter{remoteW}, remoteC)
+	require.Error(t, tiered.Write(ctx, "failed", keypath, bytes.NewReader(object), int64(len(object)), nil))
+	_, _, err = localR.Read(ctx, "failed", keypath, nil)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+
+	// and complete writes are mirrored
+	tiered = backend.NewTiered(localR, localW, localC, remoteR, remoteW, remoteC)
+	require.NoError(t, tiered.Write(ctx, "mirrored", keypath, bytes.NewReader(object), int64(len(object)), nil))
+	require.Equal(t, object, read(localR, "mirrored"))
+}
+
+func TestTieredOnlyMirrorsBlocks(t *testing.T) {
+	ctx := context.Background()
+	keypath := backend.KeyPath{testTenantID}
+
+	localR, localW, localC, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+	remoteR, remoteW, remoteC, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+	tiered := backend.NewTiered(localR, localW, localC, remoteR, remoteW, remoteC)
+
+	// objects outside of a block, like the tenant index, are only written to the remote backend
+	require.NoError(t, tiered.Write(ctx, backend.TenantIndexName, keypath, bytes.NewReader([]byte("v1")), 2, nil))
+	tracker, err := tiered.Append(ctx, "appended", keypath, nil, []byte("v1"))
+	require.NoError(t, err)
+	require.NoError(t, tiered.CloseAppend(ctx, tracker))
+
+	for _, name := range []string{backend.TenantIndexName, "appended"} {
+		_, _, err = localR.Read(ctx, name, keypath, nil)
+		require.ErrorIs(t, err, backend.ErrDoesNotExist)
+	}
+
+	// and always read from it, a stale local copy is ignored
+	require.NoError(t, localW.Write(ctx, backend.TenantIndexName, keypath, bytes.NewReader([]byte("v0")), 2, nil))
+
+	rc, _, err := tiered.Read(ctx, backend.TenantIndexName, keypath, nil)
+	require.NoError(t, err)
+	b, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	require.Equal(t, []byte("v1"), b)
+
+	buffer := make([]byte, 2)
+	require.NoError(t, tiered.ReadRange(ctx, backend.TenantIndexName, keypath, 0, buffer, nil))
+	require.Equal(t, []byte("v1"), buffer)
+}
+
+func TestTieredKeepsOptionalInterfaces(t *testing.T) {
+	ctx := context.Background()
+	keypath := backend.KeyPath{testTenantID}
+
+	r, _, _, _ := testConfig(t, backend.EncNone, 0, func(cfg *Config) {
+		cfg.Tiered = TieredConfig{
+			Local:            &local.Config{Path: t.TempDir()},
+			MaxAge:           DefaultTieredMaxAge,
+			EvictionInterval: DefaultTieredEvictionInterval,
+		}
+	})
+	rw := r.(*readerWriter)
+	require.NotNil(t, rw.tiered)
+
+	// the local trace storage supports conditional reads
+	require.NoError(t, rw.rawW.Write(ctx, "object", keypath, bytes.NewReader([]byte("object")), 6, nil))
+	cr, ok := rw.rawR.(backend.ConditionalReader)
+	require.True(t, ok)
+	rc, _, version, err := cr.ReadIfChanged(ctx, "object", keypath, "")
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	require.NotEmpty(t, version)
+	_, _, _, err = cr.ReadIfChanged(ctx, "object", keypath, version)
+	require.ErrorIs(t, err, backend.ErrNotModified)
+
+	// and resumable appends and archiving fall back to what it supports
+	_, ok = rw.rawW.(backend.ResumableAppender)
+	require.True(t, ok)
+	archiver, ok := rw.c.(backend.Archiver)
+	require.True(t, ok)
+	require.ErrorIs(t, archiver.ArchiveBlock(ctx, uuid.New(), testTenantID, "cold"), backend.ErrArchivingNotSupported)
+}
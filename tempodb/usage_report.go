@@ -0,0 +1,306 @@
+package tempodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+var (
+	metricUsageReportBackendBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "usage_report_backend_bytes",
+		Help:      "Bytes of the objects of the tenant listed in the backend by the last usage report.",
+	}, []string{"tenant"})
+	metricUsageReportBlocklistBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "usage_report_blocklist_bytes",
+		Help:      "Bytes of the blocks and compacted blocks in the blocklist of the tenant at the last usage report.",
+	}, []string{"tenant"})
+	metricUsageReportOrphanedBlocks = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "usage_report_orphaned_blocks",
+		Help:      "Blocks of the tenant in the backend that are not in the blocklist at the last usage report.",
+	}, []string{"tenant"})
+	metricUsageReportOrphanedBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "usage_report_orphaned_bytes",
+		Help:      "Bytes of the blocks of the tenant in the backend that are not in the blocklist at the last usage report.",
+	}, []string{"tenant"})
+	metricUsageReportMissingBlocks = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "usage_report_missing_blocks",
+		Help:      "Blocks in the blocklist of the tenant without objects in the backend at the last usage report.",
+	}, []string{"tenant"})
+	metricUsageReportErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "usage_report_errors_total",
+		Help:      "Total number of usage reports that failed.",
+	})
+)
+
+// UsageReportConfig configures the job comparing the objects of each tenant in the backend with its blocklist.
+type UsageReportConfig struct {
+	// Interval between two reports of a tenant. 0 disables the usage report.
+	Interval time.Duration `yaml:"interval"`
+	// OrphanMinAge is how long the objects of a block must be unchanged before the block is orphaned if it's not in
+	// the blocklist, so blocks written since the last poll aren't reported.
+	OrphanMinAge time.Duration `yaml:"orphan_min_age"`
+}
+
+func (cfg *UsageReportConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.DurationVar(&cfg.Interval, util.PrefixConfig(prefix, "usage-report.interval"), 0, "Interval between two usage reports of a tenant. 0 disables the usage report.")
+	cfg.OrphanMinAge = 24 * time.Hour
+}
+
+// Enabled returns true if the usage report is enabled.
+func (cfg *UsageReportConfig) Enabled() bool {
+	return cfg.Interval > 0
+}
+
+func (cfg *UsageReportConfig) validate() error {
+	if cfg.Enabled() && cfg.OrphanMinAge <= 0 {
+		return errors.New("usage report orphan min age must be greater than 0")
+	}
+	return nil
+}
+
+// UsageReport compares the objects of a tenant listed in the backend with its blocklist. It's written to the
+// tenant as backend.UsageReportName by the compactor owning the tenant.
+type UsageReport struct {
+	TenantID  string    `json:"tenantID"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// BackendObjects and BackendBytes are all objects of the tenant in the backend, TrashBytes the ones in the
+	// trash and OtherBytes the ones that don't belong to a block, like the tenant index.
+	BackendObjects int64 `json:"backendObjects"`
+	BackendBytes   int64 `json:"backendBytes"`
+	TrashBytes     int64 `json:"trashBytes"`
+	OtherBytes     int64 `json:"otherBytes"`
+
+	// BlocklistBlocks and BlocklistCompactedBlocks are the blocks in the blocklist and BlocklistBytes their size
+	// from their metas. BlockBytes are the bytes of their objects in the backend.
+	BlocklistBlocks          int   `json:"blocklistBlocks"`
+	BlocklistCompactedBlocks int   `json:"blocklistCompactedBlocks"`
+	BlocklistBytes           int64 `json:"blocklistBytes"`
+	BlockBytes               int64 `json:"blockBytes"`
+
+	// OrphanedBlocks are the blocks in the backend that aren't in the blocklist, largest first, and OrphanedBytes
+	// their bytes. Retention and compaction never clean them up.
+	OrphanedBlocks []UsageReportBlock `json:"orphanedBlocks,omitempty"`
+	OrphanedBytes  int64              `json:"orphanedBytes"`
+	// MissingBlocks are the blocks in the blocklist without objects in the backend.
+	MissingBlocks []backend.UUID `json:"missingBlocks,omitempty"`
+}
+
+type UsageReportBlock struct {
+	BlockID      backend.UUID `json:"blockID"`
+	Objects      int64        `json:"objects"`
+	Bytes        int64        `json:"bytes"`
+	LastModified time.Time    `json:"lastModified"`
+}
+
+// usageReportLoop reports the usage of the tenants owned by this compactor every interval of the usage report.
+func (rw *readerWriter) usageReportLoop(ctx context.Context) {
+	// the tenants with metrics, their metrics are deleted when they are not owned anymore
+	reported := map[string]struct{}{}
+
+	ticker := time.NewTicker(rw.compactorCfg.UsageReport.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			reported = rw.doUsageReport(ctx, reported)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (rw *readerWriter) doUsageReport(ctx context.Context, previous map[string]struct{}) map[string]struct{} {
+	reported := map[string]struct{}{}
+	for _, tenantID := range rw.blocklist.Tenants() {
+		if ctx.Err() != nil {
+			return previous
+		}
+		if !rw.compactorSharder.Owns(tenantID) {
+			continue
+		}
+		// the report would keep empty tenants from being deleted
+		if len(rw.blocklist.Metas(tenantID)) == 0 && len(rw.blocklist.CompactedMetas(tenantID)) == 0 {
+			continue
+		}
+
+		report, err := rw.reportTenantUsage(ctx, tenantID, time.Now())
+		if err != nil {
+			level.Error(rw.logger).Log("msg", "failed to report tenant usage", "tenantID", tenantID, "err", err)
+			metricUsageReportErrors.Inc()
+			continue
+		}
+
+		metricUsageReportBackendBytes.WithLabelValues(tenantID).Set(float64(report.BackendBytes))
+		metricUsageReportBlocklistBytes.WithLabelValues(tenantID).Set(float64(report.BlocklistBytes))
+		metricUsageReportOrphanedBlocks.WithLabelValues(tenantID).Set(float64(len(report.OrphanedBlocks)))
+		metricUsageReportOrphanedBytes.WithLabelValues(tenantID).Set(float64(report.OrphanedBytes))
+		metricUsageReportMissingBlocks.WithLabelValues(tenantID).Set(float64(len(report.MissingBlocks)))
+		reported[tenantID] = struct{}{}
+
+		if len(report.OrphanedBlocks) > 0 || len(report.MissingBlocks) > 0 {
+			level.Warn(rw.logger).Log("msg", "tenant blocklist drifted from the backend", "tenantID", tenantID,
+				"orphanedBlocks", len(report.OrphanedBlocks), "orphanedBytes", report.OrphanedBytes, "missingBlocks", len(report.MissingBlocks))
+		}
+	}
+
+	for tenantID := range previous {
+		if _, ok := reported[tenantID]; ok {
+			continue
+		}
+		metricUsageReportBackendBytes.DeleteLabelValues(tenantID)
+		metricUsageReportBlocklistBytes.DeleteLabelValues(tenantID)
+		metricUsageReportOrphanedBlocks.DeleteLabelValues(tenantID)
+		metricUsageReportOrphanedBytes.DeleteLabelValues(tenantID)
+		metricUsageReportMissingBlocks.DeleteLabelValues(tenantID)
+	}
+
+	return reported
+}
+
+// reportTenantUsage lists the objects of the tenant, compares them with its blocklist and writes the report.
+func (rw *readerWriter) reportTenantUsage(ctx context.Context, tenantID string, now time.Time) (*UsageReport, error) {
+	var objects []backend.FindMatch
+	err := rw.rawR.Find(ctx, backend.KeyPath{tenantID}, func(m backend.FindMatch) {
+		objects = append(objects, m)
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("error listing objects: %w", err)
+	}
+
+	report := newUsageReport(tenantID, objects, rw.blocklist.Metas(tenantID), rw.blocklist.CompactedMetas(tenantID), now, rw.compactorCfg.UsageReport.OrphanMinAge)
+
+	b, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	err = rw.rawW.Write(ctx, backend.UsageReportName, backend.KeyPath{tenantID}, bytes.NewReader(b), int64(len(b)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error writing usage report: %w", err)
+	}
+
+	return report, nil
+}
+
+// newUsageReport builds the report from the objects listed in the tenant. Objects are attributed to blocks by the
+// segment following the tenant in their keys, <tenant>/<block id>/<name>, so the backend prefix doesn't matter.
+// Blocks that aren't in the blocklist are only orphaned once their objects are older than orphanMinAge.
+func newUsageReport(tenantID string, objects []backend.FindMatch, metas []*backend.BlockMeta, compactedMetas []*backend.CompactedBlockMeta, now time.Time, orphanMinAge time.Duration) *UsageReport {
+	report := &UsageReport{
+		TenantID:                 tenantID,
+		CreatedAt:                now,
+		BlocklistBlocks:          len(metas),
+		BlocklistCompactedBlocks: len(compactedMetas),
+	}
+
+	known := make(map[uuid.UUID]bool, len(metas)+len(compactedMetas))
+	for _, m := range metas {
+		known[(uuid.UUID)(m.BlockID)] = false
+		report.BlocklistBytes += int64(m.Size_)
+	}
+	for _, m := range compactedMetas {
+		known[(uuid.UUID)(m.BlockID)] = false
+		report.BlocklistBytes += int64(m.Size_)
+	}
+
+	unknown := map[uuid.UUID]*UsageReportBlock{}
+	for _, o := range objects {
+		report.BackendObjects++
+		report.BackendBytes += o.Size
+
+		segments := tenantKeySegments(o.Key, tenantID)
+		if len(segments) > 1 && segments[0] == backend.TrashKeyPath {
+			report.TrashBytes += o.Size
+			continue
+		}
+
+		var (
+			blockID uuid.UUID
+			err     error
+		)
+		if len(segments) > 1 {
+			blockID, err = uuid.Parse(segments[0])
+		}
+		if len(segments) <= 1 || err != nil {
+			report.OtherBytes += o.Size
+			continue
+		}
+
+		if _, ok := known[blockID]; ok {
+			known[blockID] = true
+			report.BlockBytes += o.Size
+			continue
+		}
+
+		b, ok := unknown[blockID]
+		if !ok {
+			b = &UsageReportBlock{BlockID: backend.UUID(blockID)}
+			unknown[blockID] = b
+		}
+		b.Objects++
+		b.Bytes += o.Size
+		if o.Modified.After(b.LastModified) {
+			b.LastModified = o.Modified
+		}
+	}
+
+	for _, b := range unknown {
+		if now.Sub(b.LastModified) < orphanMinAge {
+			// maybe written since the blocklist was polled
+			report.OtherBytes += b.Bytes
+			continue
+		}
+		report.OrphanedBlocks = append(report.OrphanedBlocks, *b)
+		report.OrphanedBytes += b.Bytes
+	}
+	sort.Slice(report.OrphanedBlocks, func(i, j int) bool {
+		if report.OrphanedBlocks[i].Bytes != report.OrphanedBlocks[j].Bytes {
+			return report.OrphanedBlocks[i].Bytes > report.OrphanedBlocks[j].Bytes
+		}
+		return report.OrphanedBlocks[i].BlockID.String() < report.OrphanedBlocks[j].BlockID.String()
+	})
+
+	for id, found := range known {
+		if !found {
+			report.MissingBlocks = append(report.MissingBlocks, backend.UUID(id))
+		}
+	}
+	sort.Slice(report.MissingBlocks, func(i, j int) bool {
+		return report.MissingBlocks[i].String() < report.MissingBlocks[j].String()
+	})
+
+	return report
+}
+
+// tenantKeySegments returns the segments of the key after the tenant, nil if the key isn't in the tenant.
+func tenantKeySegments(key, tenantID string) []string {
+	segments := strings.Split(strings.Trim(key, "/"), "/")
+	for i, s := range segments {
+		if s == tenantID {
+			return segments[i+1:]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package tempodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/blocklist"
+)
+
+func TestNewUsageReport(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-2 * time.Hour)
+
+	live := backend.NewBlockMeta("test", uuid.New(), "v1", backend.EncNone, "")
+	live.Size_ = 100
+	compacted := &backend.CompactedBlockMeta{BlockMeta: *backend.NewBlockMeta("test", uuid.New(), "v1", backend.EncNone, "")}
+	compacted.Size_ = 50
+	missing := backend.NewBlockMeta("test", uuid.New(), "v1", backend.EncNone, "")
+
+	orphaned, recent := uuid.New(), uuid.New()
+
+	// keys have the backend prefix like in object storage
+	key := func(segments ...string) string {
+		k := "prefix/test"
+		for _, s := range segments {
+			k += "/" + s
+		}
+		return k
+	}
+	objects := []backend.FindMatch{
+		{Key: key(live.BlockID.String(), backend.MetaName), Size: 10, Modified: old},
+		{Key: key(live.BlockID.String(), "data.parquet"), Size: 100, Modified: old},
+		{Key: key(compacted.BlockID.String(), backend.CompactedMetaName), Size: 60, Modified: old},
+		{Key: key(orphaned.String(), "data.parquet"), Size: 1000, Modified: old.Add(-time.Hour)},
+		{Key: key(orphaned.String(), "bloom-0"), Size: 20, Modified: old},
+		{Key: key(recent.String(), "data.parquet"), Size: 30, Modified: now},
+		{Key: key(backend.TrashKeyPath, uuid.NewString(), "data.parquet"), Size: 7, Modified: old},
+		{Key: key(backend.TenantIndexName), Size: 3, Modified: now},
+	}
+
+	report := newUsageReport("test", objects, []*backend.BlockMeta{live, missing}, []*backend.CompactedBlockMeta{compacted}, now, time.Hour)
+
+	require.Equal(t, &UsageReport{
+		TenantID:                 "test",
+		CreatedAt:                now,
+		BackendObjects:           8,
+		BackendBytes:             1230,
+		TrashBytes:               7,
+		OtherBytes:               33,
+		BlocklistBlocks:          2,
+		BlocklistCompactedBlocks: 1,
+		BlocklistBytes:           150,
+		BlockBytes:               170,
+		OrphanedBlocks: []UsageReportBlock{
+			{BlockID: backend.UUID(orphaned), Objects: 2, Bytes: 1020, LastModified: old},
+		},
+		OrphanedBytes: 1020,
+		MissingBlocks: []backend.UUID{missing.BlockID},
+	}, report)
+}
+
+func TestReportTenantUsage(t *testing.T) {
+	ctx := context.Background()
+
+	rawR, rawW, _, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+
+	meta := backend.NewBlockMeta("test", uuid.New(), "v1", backend.EncNone, "")
+	require.NoError(t, backend.NewWriter(rawW).WriteBlockMeta(ctx, meta))
+
+	orphaned := uuid.New()
+	require.NoError(t, rawW.Write(ctx, "data.parquet", backend.KeyPathForBlock(orphaned, "test"), bytes.NewReader([]byte("orphaned")), 8, nil))
+
+	rw := &readerWriter{
+		rawR:         rawR,
+		rawW:         rawW,
+		blocklist:    blocklist.New(),
+		compactorCfg: &CompactorConfig{UsageReport: UsageReportConfig{Interval: time.Hour, OrphanMinAge: time.Nanosecond}},
+	}
+	rw.blocklist.ApplyPollResults(blocklist.PerTenant{"test": {meta}}, blocklist.PerTenantCompacted{})
+
+	report, err := rw.reportTenantUsage(ctx, "test", time.Now())
+	require.NoError(t, err)
+	require.Len(t, report.OrphanedBlocks, 1)
+	require.Equal(t, backend.UUID(orphaned), report.OrphanedBlocks[0].BlockID)
+	require.Equal(t, int64(8), report.OrphanedBytes)
+	require.Empty(t, report.MissingBlocks)
+
+	rc, _, err := rawR.Read(ctx, backend.UsageReportName, backend.KeyPath{"test"}, nil)
+	require.NoError(t, err)
+	defer rc.Close()
+	b, err := io.ReadAll(rc)
+	require.NoError(t, err)
+
+	var written UsageReport
+	require.NoError(t, json.Unmarshal(b, &written))
+	require.Equal(t, report.OrphanedBytes, written.OrphanedBytes)
+	require.Equal(t, report.BackendObjects, written.BackendObjects)
+}